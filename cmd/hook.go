@@ -340,6 +340,9 @@ Gitea or set your environment appropriately.`, "")
 	pusherID, _ := strconv.ParseInt(os.Getenv(repo_module.EnvPusherID), 10, 64)
 	prID, _ := strconv.ParseInt(os.Getenv(repo_module.EnvPRID), 10, 64)
 	pusherName := os.Getenv(repo_module.EnvPusherName)
+	// only set by the pushes of Gitea itself, e.g. for the commits of the contents API
+	skipWebhooks, _ := strconv.ParseBool(os.Getenv(repo_module.EnvSkipWebhooks))
+	skipCI, _ := strconv.ParseBool(os.Getenv(repo_module.EnvSkipCI))
 
 	hookOptions := private.HookOptions{
 		UserName:                        pusherName,
@@ -350,6 +353,8 @@ Gitea or set your environment appropriately.`, "")
 		GitPushOptions:                  pushOptions(),
 		PullRequestID:                   prID,
 		PushTrigger:                     repo_module.PushTrigger(os.Getenv(repo_module.EnvPushTrigger)),
+		SkipWebhooks:                    skipWebhooks,
+		SkipCI:                          skipCI,
 	}
 	oldCommitIDs := make([]string, hookBatchSize)
 	newCommitIDs := make([]string, hookBatchSize)
@@ -468,15 +473,11 @@ func hookPrintResult(output, isCreate bool, branch, url string) {
 	_ = os.Stderr.Sync()
 }
 
-func pushOptions() map[string]string {
-	opts := make(map[string]string)
+func pushOptions() private.GitPushOptions {
+	opts := make(private.GitPushOptions)
 	if pushCount, err := strconv.Atoi(os.Getenv(private.GitPushOptionCount)); err == nil {
 		for idx := 0; idx < pushCount; idx++ {
-			opt := os.Getenv(fmt.Sprintf("GIT_PUSH_OPTION_%d", idx))
-			kv := strings.SplitN(opt, "=", 2)
-			if len(kv) == 2 {
-				opts[kv[0]] = kv[1]
-			}
+			opts.AddFromKeyValue(os.Getenv(fmt.Sprintf("GIT_PUSH_OPTION_%d", idx)))
 		}
 	}
 	return opts
@@ -616,10 +617,7 @@ Gitea or set your environment appropriately.`, "")
 				break
 			}
 
-			kv := strings.SplitN(string(rs.Data), "=", 2)
-			if len(kv) == 2 {
-				hookOptions.GitPushOptions[kv[0]] = kv[1]
-			}
+			hookOptions.GitPushOptions.AddFromKeyValue(string(rs.Data))
 		}
 	}
 
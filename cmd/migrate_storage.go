@@ -36,7 +36,7 @@ var CmdMigrateStorage = &cli.Command{
 			Name:    "type",
 			Aliases: []string{"t"},
 			Value:   "",
-			Usage:   "Type of stored files to copy.  Allowed types: 'attachments', 'lfs', 'avatars', 'repo-avatars', 'repo-archivers', 'packages', 'actions-log', 'actions-artifacts",
+			Usage:   "Type of stored files to copy.  Allowed types: 'attachments', 'lfs', 'avatars', 'repo-avatars', 'repo-archivers', 'packages', 'actions-log', 'actions-artifacts', 'actions-cache'",
 		},
 		&cli.StringFlag{
 			Name:    "storage",
@@ -213,6 +213,25 @@ func migrateActionsArtifacts(ctx context.Context, dstStorage storage.ObjectStora
 	})
 }
 
+func migrateActionsCache(ctx context.Context, dstStorage storage.ObjectStorage) error {
+	return db.Iterate(ctx, nil, func(ctx context.Context, cache *actions_model.ActionCache) error {
+		if !cache.Complete {
+			return nil
+		}
+
+		_, err := storage.Copy(dstStorage, cache.StoragePath(), storage.ActionsCache, cache.StoragePath())
+		if err != nil {
+			// ignore files that do not exist
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		return nil
+	})
+}
+
 func runMigrateStorage(ctx *cli.Context) error {
 	stdCtx, cancel := installSignals()
 	defer cancel()
@@ -297,6 +316,7 @@ func runMigrateStorage(ctx *cli.Context) error {
 		"packages":          migratePackages,
 		"actions-log":       migrateActionsLog,
 		"actions-artifacts": migrateActionsArtifacts,
+		"actions-cache":     migrateActionsCache,
 	}
 
 	tp := strings.ToLower(ctx.String("type"))
@@ -22,6 +22,7 @@ import (
 	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/lfstransfer"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/pprof"
 	"code.gitea.io/gitea/modules/private"
@@ -37,6 +38,7 @@ import (
 
 const (
 	lfsAuthenticateVerb = "git-lfs-authenticate"
+	lfsTransferVerb     = "git-lfs-transfer"
 )
 
 // CmdServ represents the available serv sub-command.
@@ -78,6 +80,7 @@ var (
 		"git-upload-archive": perm.AccessModeRead,
 		"git-receive-pack":   perm.AccessModeWrite,
 		lfsAuthenticateVerb:  perm.AccessModeNone,
+		lfsTransferVerb:      perm.AccessModeNone,
 	}
 	alphaDashDotPattern = regexp.MustCompile(`[^\w-\.]`)
 )
@@ -195,10 +198,14 @@ func runServ(c *cli.Context) error {
 	}
 
 	var lfsVerb string
-	if verb == lfsAuthenticateVerb {
+	if verb == lfsAuthenticateVerb || verb == lfsTransferVerb {
 		if !setting.LFS.StartServer {
 			return fail(ctx, "Unknown git command", "LFS authentication request over SSH denied, LFS support is disabled")
 		}
+		// the client falls back to git-lfs-authenticate if the transfer over SSH fails
+		if verb == lfsTransferVerb && !setting.LFS.AllowPureSSH {
+			return fail(ctx, "Unknown git command", "LFS transfer over SSH denied, LFS_ALLOW_PURE_SSH is disabled")
+		}
 
 		if len(words) > 2 {
 			lfsVerb = words[2]
@@ -245,7 +252,7 @@ func runServ(c *cli.Context) error {
 		return fail(ctx, "Unknown git command", "Unknown git command %s", verb)
 	}
 
-	if verb == lfsAuthenticateVerb {
+	if verb == lfsAuthenticateVerb || verb == lfsTransferVerb {
 		if lfsVerb == "upload" {
 			requestedMode = perm.AccessModeWrite
 		} else if lfsVerb == "download" {
@@ -264,20 +271,7 @@ func runServ(c *cli.Context) error {
 	if verb == lfsAuthenticateVerb {
 		url := fmt.Sprintf("%s%s/%s.git/info/lfs", setting.AppURL, url.PathEscape(results.OwnerName), url.PathEscape(results.RepoName))
 
-		now := time.Now()
-		claims := lfs.Claims{
-			RegisteredClaims: jwt.RegisteredClaims{
-				ExpiresAt: jwt.NewNumericDate(now.Add(setting.LFS.HTTPAuthExpiry)),
-				NotBefore: jwt.NewNumericDate(now),
-			},
-			RepoID: results.RepoID,
-			Op:     lfsVerb,
-			UserID: results.UserID,
-		}
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-		// Sign and get the complete encoded token as a string using the secret
-		tokenString, err := token.SignedString(setting.LFS.JWTSecretBytes)
+		tokenString, err := getLFSAuthToken(lfsVerb, results)
 		if err != nil {
 			return fail(ctx, "Failed to sign JWT Token", "Failed to sign JWT token: %v", err)
 		}
@@ -296,6 +290,21 @@ func runServ(c *cli.Context) error {
 		return nil
 	}
 
+	// LFS transfer over SSH, the objects and locks are served by the LFS server with the token of the user
+	if verb == lfsTransferVerb {
+		tokenString, err := getLFSAuthToken(lfsVerb, results)
+		if err != nil {
+			return fail(ctx, "Failed to sign JWT Token", "Failed to sign JWT token: %v", err)
+		}
+
+		url := fmt.Sprintf("%s%s/%s.git/info/lfs", setting.LocalURL, url.PathEscape(results.OwnerName), url.PathEscape(results.RepoName))
+		backend := lfstransfer.NewHTTPBackend(ctx, url, "Bearer "+tokenString)
+		if err := lfstransfer.Serve(os.Stdin, os.Stdout, lfsVerb, results.UserName, backend); err != nil {
+			return fail(ctx, "Failed to transfer LFS objects", "LFS transfer failed: %v", err)
+		}
+		return nil
+	}
+
 	var gitcmd *exec.Cmd
 	gitBinPath := filepath.Dir(git.GitExecutable) // e.g. /usr/bin
 	gitBinVerb := filepath.Join(gitBinPath, verb) // e.g. /usr/bin/git-upload-pack
@@ -349,3 +358,21 @@ func runServ(c *cli.Context) error {
 
 	return nil
 }
+
+// getLFSAuthToken returns a JWT token allowing the LFS operation in the repository
+func getLFSAuthToken(lfsVerb string, results *private.ServCommandResults) (string, error) {
+	now := time.Now()
+	claims := lfs.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(setting.LFS.HTTPAuthExpiry)),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		RepoID: results.RepoID,
+		Op:     lfsVerb,
+		UserID: results.UserID,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	// Sign and get the complete encoded token as a string using the secret
+	return token.SignedString(setting.LFS.JWTSecretBytes)
+}
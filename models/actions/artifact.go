@@ -9,6 +9,7 @@ package actions
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
@@ -130,6 +131,8 @@ type ActionArtifactMeta struct {
 	ArtifactName string
 	FileSize     int64
 	Status       ArtifactStatus
+	CreatedUnix  timeutil.TimeStamp
+	ExpiredUnix  timeutil.TimeStamp
 }
 
 // ListUploadedArtifactsMeta returns all uploaded artifacts meta of a run
@@ -138,7 +141,7 @@ func ListUploadedArtifactsMeta(ctx context.Context, runID int64) ([]*ActionArtif
 	return arts, db.GetEngine(ctx).Table("action_artifact").
 		Where("run_id=? AND (status=? OR status=?)", runID, ArtifactStatusUploadConfirmed, ArtifactStatusExpired).
 		GroupBy("artifact_name").
-		Select("artifact_name, sum(file_size) as file_size, max(status) as status").
+		Select("artifact_name, sum(file_size) as file_size, max(status) as status, min(created_unix) as created_unix, max(expired_unix) as expired_unix").
 		Find(&arts)
 }
 
@@ -169,8 +172,37 @@ func SetArtifactNeedDelete(ctx context.Context, runID int64, name string) error
 	return err
 }
 
+// SetArtifactFileNeedDelete sets a single file of an artifact uploaded by the old backend to need-delete
+func SetArtifactFileNeedDelete(ctx context.Context, artifactID int64) error {
+	_, err := db.GetEngine(ctx).Where("id=? AND status = ?", artifactID, ArtifactStatusUploadConfirmed).Cols("status").Update(&ActionArtifact{Status: int64(ArtifactStatusPendingDeletion)})
+	return err
+}
+
+// LimitArtifactsRetention brings forward the expiration of the artifacts of a repository, or of all the repositories of an owner,
+// which are kept more than retentionDays after they have been created, cron job will expire them
+func LimitArtifactsRetention(ctx context.Context, repoID, ownerID, retentionDays int64) error {
+	cond := builder.In("status", ArtifactStatusUploadPending, ArtifactStatusUploadConfirmed)
+	if repoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": repoID})
+	} else {
+		cond = cond.And(builder.Eq{"owner_id": ownerID})
+	}
+	retention := 3600 * 24 * retentionDays
+	_, err := db.GetEngine(ctx).Where(cond.And(builder.Expr("expired_unix > created_unix + ?", retention))).
+		SetExpr("expired_unix", fmt.Sprintf("created_unix + %d", retention)).
+		NoAutoTime().Update(new(ActionArtifact))
+	return err
+}
+
 // SetArtifactDeleted sets an artifact to deleted
 func SetArtifactDeleted(ctx context.Context, artifactID int64) error {
 	_, err := db.GetEngine(ctx).ID(artifactID).Cols("status").Update(&ActionArtifact{Status: int64(ArtifactStatusDeleted)})
 	return err
 }
+
+// GetRepoArtifactsSize returns the total size of the artifacts stored for a repository
+func GetRepoArtifactsSize(ctx context.Context, repoID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("repo_id = ?", repoID).
+		In("status", ArtifactStatusUploadPending, ArtifactStatusUploadConfirmed, ArtifactStatusUploadError).
+		SumInt(new(ActionArtifact), "file_compressed_size")
+}
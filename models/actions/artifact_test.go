@@ -0,0 +1,48 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitArtifactsRetention(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	const day = 24 * 3600
+	created := timeutil.TimeStampNow()
+	artifacts := []*ActionArtifact{
+		{RunID: 1, RepoID: 4, OwnerID: 5, ArtifactName: "long", ArtifactPath: "long.zip", Status: int64(ArtifactStatusUploadConfirmed), ExpiredUnix: created + 90*day},
+		{RunID: 1, RepoID: 4, OwnerID: 5, ArtifactName: "short", ArtifactPath: "short.zip", Status: int64(ArtifactStatusUploadConfirmed), ExpiredUnix: created + 5*day},
+		{RunID: 2, RepoID: 1, OwnerID: 5, ArtifactName: "other", ArtifactPath: "other.zip", Status: int64(ArtifactStatusUploadConfirmed), ExpiredUnix: created + 90*day},
+	}
+	for _, artifact := range artifacts {
+		_, err := db.GetEngine(db.DefaultContext).NoAutoTime().Insert(&ActionArtifact{
+			RunID: artifact.RunID, RepoID: artifact.RepoID, OwnerID: artifact.OwnerID,
+			ArtifactName: artifact.ArtifactName, ArtifactPath: artifact.ArtifactPath,
+			Status: artifact.Status, CreatedUnix: created, UpdatedUnix: created, ExpiredUnix: artifact.ExpiredUnix,
+		})
+		require.NoError(t, err)
+	}
+	expiredUnix := func(name string) timeutil.TimeStamp {
+		return unittest.AssertExistsAndLoadBean(t, &ActionArtifact{ArtifactName: name}).ExpiredUnix
+	}
+
+	require.NoError(t, LimitArtifactsRetention(db.DefaultContext, 4, 0, 10))
+	assert.Equal(t, created+10*day, expiredUnix("long"))
+	assert.Equal(t, created+5*day, expiredUnix("short"))
+	assert.Equal(t, created+90*day, expiredUnix("other"))
+
+	require.NoError(t, LimitArtifactsRetention(db.DefaultContext, 0, 5, 7))
+	assert.Equal(t, created+7*day, expiredUnix("long"))
+	assert.Equal(t, created+5*day, expiredUnix("short"))
+	assert.Equal(t, created+7*day, expiredUnix("other"))
+}
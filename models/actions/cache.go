@@ -0,0 +1,215 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(ActionCache))
+}
+
+// ActionCache is an entry of actions/cache, stored in the cache storage.
+// The caches are scoped by repository and by ref, a run can restore the caches of its ref and of the refs it is based on.
+type ActionCache struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"index UNIQUE(s)"`
+	Scope       string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"` // the ref the cache has been saved from
+	RunID       int64              `xorm:"index NOT NULL DEFAULT 0"`        // the run which has reserved the cache, only it can upload the content
+	Key         string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	Version     string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"` // the hash of the paths and the compression of the cache
+	Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+	Complete    bool               `xorm:"index NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UsedUnix    timeutil.TimeStamp `xorm:"index"` // the last time the cache has been saved or restored, used to evict the least recently used caches
+}
+
+// StoragePath returns the path of the content of the cache in the cache storage
+func (c *ActionCache) StoragePath() string {
+	return fmt.Sprintf("%d/%d", c.RepoID, c.ID)
+}
+
+// ChunkStoragePath returns the path of a chunk of the content of the cache uploaded but not committed yet
+func (c *ActionCache) ChunkStoragePath(start int64) string {
+	return fmt.Sprintf("tmp/%d/%d.chunk", c.ID, start)
+}
+
+// ChunkStorageDir returns the directory of the chunks of the cache
+func (c *ActionCache) ChunkStorageDir() string {
+	return fmt.Sprintf("tmp/%d", c.ID)
+}
+
+// ReserveCache creates an incomplete cache which content is going to be uploaded by the run.
+// It returns util.ErrAlreadyExist if a cache with the same key and version has already been reserved in the scope.
+func ReserveCache(ctx context.Context, repoID, runID int64, scope, key, version string) (*ActionCache, error) {
+	cache := &ActionCache{
+		RepoID:   repoID,
+		RunID:    runID,
+		Scope:    scope,
+		Key:      key,
+		Version:  version,
+		UsedUnix: timeutil.TimeStampNow(),
+	}
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Exist(&ActionCache{RepoID: repoID, Scope: scope, Key: key, Version: version})
+		if err != nil {
+			return err
+		}
+		if has {
+			return util.ErrAlreadyExist
+		}
+		return db.Insert(ctx, cache)
+	}); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// GetCacheByID returns the cache by its id
+func GetCacheByID(ctx context.Context, id int64) (*ActionCache, error) {
+	cache := &ActionCache{}
+	has, err := db.GetEngine(ctx).ID(id).Get(cache)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.ErrNotExist
+	}
+	return cache, nil
+}
+
+// CommitCache marks the cache as complete once its content has been uploaded
+func CommitCache(ctx context.Context, cache *ActionCache, size int64) error {
+	cache.Size = size
+	cache.Complete = true
+	cache.UsedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(cache.ID).Cols("size", "complete", "used_unix").Update(cache)
+	return err
+}
+
+// MatchCache returns the complete cache of the repository matching one of the keys, in the first scope having one.
+// For each scope the keys are tried in order, an exact match of a key first and then the latest cache prefixed by it.
+// The cache is marked as used.
+func MatchCache(ctx context.Context, repoID int64, scopes, keys []string, version string) (*ActionCache, error) {
+	for _, scope := range scopes {
+		for _, key := range keys {
+			cond := builder.Eq{
+				"repo_id":  repoID,
+				"scope":    scope,
+				"version":  version,
+				"complete": true,
+			}
+			cache := &ActionCache{}
+			has, err := db.GetEngine(ctx).Where(cond.And(builder.Eq{"`key`": key})).Get(cache)
+			if err != nil {
+				return nil, err
+			}
+			if !has {
+				has, err = db.GetEngine(ctx).Where(cond.And(builder.Like{"`key`", key + "%"})).Desc("created_unix", "id").Get(cache)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if has {
+				cache.UsedUnix = timeutil.TimeStampNow()
+				if _, err := db.GetEngine(ctx).ID(cache.ID).Cols("used_unix").Update(cache); err != nil {
+					return nil, err
+				}
+				return cache, nil
+			}
+		}
+	}
+	return nil, util.ErrNotExist
+}
+
+// FindCacheOptions are the options to find caches
+type FindCacheOptions struct {
+	db.ListOptions
+	RepoID        int64
+	Complete      optional.Option[bool]
+	UsedBefore    timeutil.TimeStamp
+	CreatedBefore timeutil.TimeStamp
+}
+
+func (opts FindCacheOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Complete.Has() {
+		cond = cond.And(builder.Eq{"complete": opts.Complete.Value()})
+	}
+	if opts.UsedBefore > 0 {
+		cond = cond.And(builder.Lt{"used_unix": opts.UsedBefore})
+	}
+	if opts.CreatedBefore > 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.CreatedBefore})
+	}
+	return cond
+}
+
+func (opts FindCacheOptions) ToOrders() string {
+	// the least recently used caches first
+	return "used_unix ASC, id ASC"
+}
+
+// DeleteCacheByID deletes the record of a cache, its content has to be removed from the storage by the caller
+func DeleteCacheByID(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(&ActionCache{})
+	return err
+}
+
+// RepoCacheUsage is the size and the number of the caches of a repository
+type RepoCacheUsage struct {
+	RepoID int64
+	Count  int64
+	Size   int64
+}
+
+// GetRepoCacheUsages returns the usages of the repositories having caches, the largest ones first
+func GetRepoCacheUsages(ctx context.Context, opts db.ListOptions) ([]*RepoCacheUsage, int64, error) {
+	total, err := db.GetEngine(ctx).Table("action_cache").Distinct("repo_id").Count()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	usages := make([]*RepoCacheUsage, 0, opts.PageSize)
+	sess := db.GetEngine(ctx).Table("action_cache").
+		Select("repo_id, COUNT(*) AS count, SUM(size) AS size").
+		GroupBy("repo_id").
+		OrderBy("size DESC, repo_id ASC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	if err := sess.Find(&usages); err != nil {
+		return nil, 0, err
+	}
+	return usages, total, nil
+}
+
+// GetRepoCacheSize returns the total size of the caches of a repository
+func GetRepoCacheSize(ctx context.Context, repoID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("repo_id=?", repoID).SumInt(new(ActionCache), "size")
+}
+
+// GetRepoIDsOverCacheQuota returns the repositories which caches are larger than the quota
+func GetRepoIDsOverCacheQuota(ctx context.Context, quota int64) ([]int64, error) {
+	repoIDs := make([]int64, 0, 10)
+	if err := db.GetEngine(ctx).Table("action_cache").
+		Select("repo_id").
+		GroupBy("repo_id").
+		Having(fmt.Sprintf("SUM(size) > %d", quota)).
+		Find(&repoIDs); err != nil {
+		return nil, err
+	}
+	return repoIDs, nil
+}
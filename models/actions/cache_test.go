@@ -0,0 +1,73 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionCache(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	reserve := func(t *testing.T, repoID int64, scope, key string, size int64) *ActionCache {
+		cache, err := ReserveCache(db.DefaultContext, repoID, 1, scope, key, "v1")
+		require.NoError(t, err)
+		if size > 0 {
+			require.NoError(t, CommitCache(db.DefaultContext, cache, size))
+		}
+		return cache
+	}
+
+	main := reserve(t, 1, "refs/heads/main", "npm-linux-abc", 100)
+	feature := reserve(t, 1, "refs/heads/feature", "npm-linux-def", 200)
+	_ = reserve(t, 1, "refs/heads/feature", "npm-linux-incomplete", 0)
+	_ = reserve(t, 2, "refs/heads/main", "npm-linux-other", 50)
+
+	_, err := ReserveCache(db.DefaultContext, 1, 1, "refs/heads/main", "npm-linux-abc", "v1")
+	assert.ErrorIs(t, err, util.ErrAlreadyExist)
+
+	t.Run("Match", func(t *testing.T) {
+		// exact match in the scope of the ref
+		cache, err := MatchCache(db.DefaultContext, 1, []string{"refs/heads/feature", "refs/heads/main"}, []string{"npm-linux-abc"}, "v1")
+		require.NoError(t, err)
+		assert.Equal(t, main.ID, cache.ID)
+
+		// prefix match, the first scope wins and incomplete caches are ignored
+		cache, err = MatchCache(db.DefaultContext, 1, []string{"refs/heads/feature", "refs/heads/main"}, []string{"npm-linux-"}, "v1")
+		require.NoError(t, err)
+		assert.Equal(t, feature.ID, cache.ID)
+
+		// another branch can't restore the caches of the feature branch
+		cache, err = MatchCache(db.DefaultContext, 1, []string{"refs/heads/other", "refs/heads/main"}, []string{"npm-linux-def", "npm-"}, "v1")
+		require.NoError(t, err)
+		assert.Equal(t, main.ID, cache.ID)
+
+		_, err = MatchCache(db.DefaultContext, 1, []string{"refs/heads/main"}, []string{"npm-linux-abc"}, "v2")
+		assert.ErrorIs(t, err, util.ErrNotExist)
+		_, err = MatchCache(db.DefaultContext, 1, []string{"refs/heads/main"}, []string{"npm-linux-other"}, "v1")
+		assert.ErrorIs(t, err, util.ErrNotExist)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		usages, total, err := GetRepoCacheUsages(db.DefaultContext, db.ListOptions{})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.Equal(t, []*RepoCacheUsage{{RepoID: 1, Count: 3, Size: 300}, {RepoID: 2, Count: 1, Size: 50}}, usages)
+
+		size, err := GetRepoCacheSize(db.DefaultContext, 1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 300, size)
+
+		repoIDs, err := GetRepoIDsOverCacheQuota(db.DefaultContext, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, repoIDs)
+	})
+}
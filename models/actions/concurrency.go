@@ -0,0 +1,129 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// Concurrency is the evaluated `concurrency` of a workflow or a job.
+// The runs or the jobs of a repository sharing a group don't run at the same time, the later ones are queued.
+type Concurrency struct {
+	Group string
+	// CancelInProgress cancels the runs or the jobs of the group in progress, otherwise only the pending ones are cancelled
+	CancelInProgress bool
+}
+
+var notDoneStatuses = []Status{StatusWaiting, StatusRunning, StatusBlocked}
+
+// cancelConcurrentRuns cancels the other runs of the concurrency group of the run which are in progress,
+// or only the pending ones if the run doesn't cancel in progress runs.
+func cancelConcurrentRuns(ctx context.Context, run *ActionRun) error {
+	cond := builder.Eq{
+		"repo_id":           run.RepoID,
+		"concurrency_group": run.ConcurrencyGroup,
+	}.And(builder.In("status", notDoneStatuses), builder.Neq{"id": run.ID})
+	if !run.ConcurrencyCancel {
+		// a run is pending as long as none of its jobs has been picked by a runner
+		cond = cond.And(builder.NotIn("id", builder.Select("run_id").From("action_run_job").Where(builder.Gt{"task_id": 0})))
+	}
+
+	runs := make([]*ActionRun, 0, 2)
+	if err := db.GetEngine(ctx).Where(cond).Find(&runs); err != nil {
+		return err
+	}
+	for _, r := range runs {
+		jobs, err := GetRunJobsByRunID(ctx, r.ID)
+		if err != nil {
+			return err
+		}
+		if err := cancelJobs(ctx, jobs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancelConcurrentJobs cancels the jobs of other runs sharing the concurrency group of the job which are in progress,
+// or only the pending ones if the job doesn't cancel in progress jobs.
+func cancelConcurrentJobs(ctx context.Context, job *ActionRunJob) error {
+	cond := builder.Eq{
+		"repo_id":           job.RepoID,
+		"concurrency_group": job.ConcurrencyGroup,
+	}.And(builder.In("status", notDoneStatuses), builder.Neq{"run_id": job.RunID})
+	if !job.ConcurrencyCancel {
+		cond = cond.And(builder.Eq{"task_id": 0})
+	}
+
+	jobs := make([]*ActionRunJob, 0, 2)
+	if err := db.GetEngine(ctx).Where(cond).Find(&jobs); err != nil {
+		return err
+	}
+	return cancelJobs(ctx, jobs)
+}
+
+// IsConcurrencyGroupBusy returns true if the job has to wait for earlier runs or jobs sharing its concurrency groups,
+// the group of its run or its own one, to be done.
+func IsConcurrencyGroupBusy(ctx context.Context, run *ActionRun, job *ActionRunJob) (bool, error) {
+	busy, err := isRunConcurrencyGroupBusy(ctx, run)
+	if err != nil || busy {
+		return busy, err
+	}
+	return isJobConcurrencyGroupBusy(ctx, job)
+}
+
+func isRunConcurrencyGroupBusy(ctx context.Context, run *ActionRun) (bool, error) {
+	if run.ConcurrencyGroup == "" {
+		return false, nil
+	}
+	return db.GetEngine(ctx).Where(builder.Eq{
+		"repo_id":           run.RepoID,
+		"concurrency_group": run.ConcurrencyGroup,
+	}.And(builder.In("status", notDoneStatuses), builder.Lt{"id": run.ID})).Exist(new(ActionRun))
+}
+
+// isJobConcurrencyGroupBusy checks the jobs inserted before the job, all the jobs if it isn't inserted yet
+func isJobConcurrencyGroupBusy(ctx context.Context, job *ActionRunJob) (bool, error) {
+	if job.ConcurrencyGroup == "" {
+		return false, nil
+	}
+	cond := builder.Eq{
+		"repo_id":           job.RepoID,
+		"concurrency_group": job.ConcurrencyGroup,
+	}.And(builder.In("status", notDoneStatuses))
+	if job.ID > 0 {
+		cond = cond.And(builder.Lt{"id": job.ID})
+	}
+	return db.GetEngine(ctx).Where(cond).Exist(new(ActionRunJob))
+}
+
+// FindQueuedRunIDsOfConcurrencyGroups returns the runs with blocked jobs queued in the concurrency groups,
+// at the level of the runs or of the jobs, in the order they have been queued.
+func FindQueuedRunIDsOfConcurrencyGroups(ctx context.Context, repoID int64, runGroups, jobGroups []string) ([]int64, error) {
+	if len(runGroups) == 0 && len(jobGroups) == 0 {
+		return nil, nil
+	}
+
+	cond := builder.NewCond()
+	if len(runGroups) > 0 {
+		cond = cond.Or(builder.In("run_id", builder.Select("id").From("action_run").Where(builder.Eq{"repo_id": repoID}.And(builder.In("concurrency_group", runGroups)))))
+	}
+	if len(jobGroups) > 0 {
+		cond = cond.Or(builder.In("concurrency_group", jobGroups))
+	}
+
+	runIDs := make([]int64, 0, 2)
+	if err := db.GetEngine(ctx).Table("action_run_job").
+		Where(builder.Eq{"repo_id": repoID, "status": StatusBlocked}.And(cond)).
+		Distinct("run_id").
+		OrderBy("run_id ASC").
+		Find(&runIDs); err != nil {
+		return nil, err
+	}
+	return runIDs, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertRunConcurrency(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	insertRun := func(t *testing.T, group string, cancelInProgress bool, jobConcurrencies ...*Concurrency) (*ActionRun, []*ActionRunJob) {
+		jobs, err := jobparser.Parse([]byte(`
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`))
+		require.NoError(t, err)
+		run := &ActionRun{
+			RepoID:            1,
+			OwnerID:           2,
+			WorkflowID:        "test.yaml",
+			TriggerUserID:     2,
+			Ref:               "refs/heads/master",
+			Status:            StatusWaiting,
+			ConcurrencyGroup:  group,
+			ConcurrencyCancel: cancelInProgress,
+		}
+		require.NoError(t, InsertRun(db.DefaultContext, run, jobs, jobConcurrencies))
+		runJobs, err := GetRunJobsByRunID(db.DefaultContext, run.ID)
+		require.NoError(t, err)
+		require.Len(t, runJobs, 1)
+		return run, runJobs
+	}
+	reloadJob := func(t *testing.T, job *ActionRunJob) *ActionRunJob {
+		return unittest.AssertExistsAndLoadBean(t, &ActionRunJob{ID: job.ID})
+	}
+	startJob := func(t *testing.T, job *ActionRunJob) {
+		task := &ActionTask{JobID: job.ID, RepoID: job.RepoID, OwnerID: job.OwnerID, Status: StatusRunning}
+		require.NoError(t, task.GenerateToken())
+		require.NoError(t, db.Insert(db.DefaultContext, task))
+		job.TaskID = task.ID
+		job.Status = StatusRunning
+		_, err := UpdateRunJob(db.DefaultContext, job, nil, "task_id", "status")
+		require.NoError(t, err)
+	}
+
+	t.Run("Workflow", func(t *testing.T) {
+		_, jobs1 := insertRun(t, "deploy", false)
+		assert.Equal(t, StatusWaiting, jobs1[0].Status)
+
+		// a pending run is replaced by the later one
+		run2, jobs2 := insertRun(t, "deploy", false)
+		assert.Equal(t, StatusCancelled, reloadJob(t, jobs1[0]).Status)
+		assert.Equal(t, StatusWaiting, jobs2[0].Status)
+
+		// a run in progress isn't cancelled, the later one is queued
+		startJob(t, jobs2[0])
+		run3, jobs3 := insertRun(t, "deploy", false)
+		assert.Equal(t, StatusRunning, reloadJob(t, jobs2[0]).Status)
+		assert.Equal(t, StatusBlocked, jobs3[0].Status)
+		busy, err := IsConcurrencyGroupBusy(db.DefaultContext, run3, jobs3[0])
+		assert.NoError(t, err)
+		assert.True(t, busy)
+
+		queued, err := FindQueuedRunIDsOfConcurrencyGroups(db.DefaultContext, 1, []string{"deploy"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{run3.ID}, queued)
+
+		// cancel-in-progress cancels the run in progress and the queued one
+		_, jobs4 := insertRun(t, "deploy", true)
+		assert.Equal(t, StatusCancelled, reloadJob(t, jobs2[0]).Status)
+		assert.Equal(t, StatusCancelled, reloadJob(t, jobs3[0]).Status)
+		assert.Equal(t, StatusWaiting, jobs4[0].Status)
+		assert.True(t, unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run2.ID}).Status.IsDone())
+
+		// another group isn't affected
+		_, jobs5 := insertRun(t, "other", false)
+		assert.Equal(t, StatusWaiting, jobs5[0].Status)
+		assert.Equal(t, StatusWaiting, reloadJob(t, jobs4[0]).Status)
+	})
+
+	t.Run("Job", func(t *testing.T) {
+		_, jobs1 := insertRun(t, "", false, &Concurrency{Group: "job-deploy"})
+		assert.Equal(t, "job-deploy", jobs1[0].ConcurrencyGroup)
+		assert.Equal(t, StatusWaiting, jobs1[0].Status)
+		startJob(t, jobs1[0])
+
+		run2, jobs2 := insertRun(t, "", false, &Concurrency{Group: "job-deploy"})
+		assert.Equal(t, StatusRunning, reloadJob(t, jobs1[0]).Status)
+		assert.Equal(t, StatusBlocked, jobs2[0].Status)
+
+		queued, err := FindQueuedRunIDsOfConcurrencyGroups(db.DefaultContext, 1, nil, []string{"job-deploy"})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{run2.ID}, queued)
+
+		_, jobs3 := insertRun(t, "", false, &Concurrency{Group: "job-deploy", CancelInProgress: true})
+		assert.Equal(t, StatusCancelled, reloadJob(t, jobs1[0]).Status)
+		assert.Equal(t, StatusCancelled, reloadJob(t, jobs2[0]).Status)
+		assert.Equal(t, StatusWaiting, jobs3[0].Status)
+	})
+}
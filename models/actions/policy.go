@@ -0,0 +1,186 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+)
+
+func init() {
+	db.RegisterModel(new(ActionPolicy))
+}
+
+// ActionsPolicyMode defines which actions the workflows can use
+type ActionsPolicyMode int
+
+const (
+	// ActionsPolicyAll allows all the actions but the blocked ones
+	ActionsPolicyAll ActionsPolicyMode = iota
+	// ActionsPolicyLocalOnly allows only the actions of the repositories of the instance
+	ActionsPolicyLocalOnly
+	// ActionsPolicySelected allows the actions of the repositories of the instance and the ones matching an allowed pattern
+	ActionsPolicySelected
+)
+
+// String returns the name of the mode, as used by the API
+func (m ActionsPolicyMode) String() string {
+	switch m {
+	case ActionsPolicyLocalOnly:
+		return "local_only"
+	case ActionsPolicySelected:
+		return "selected"
+	}
+	return "all"
+}
+
+// ParseActionsPolicyMode returns the mode by its name
+func ParseActionsPolicyMode(name string) (ActionsPolicyMode, bool) {
+	switch name {
+	case "all":
+		return ActionsPolicyAll, true
+	case "local_only":
+		return ActionsPolicyLocalOnly, true
+	case "selected":
+		return ActionsPolicySelected, true
+	}
+	return ActionsPolicyAll, false
+}
+
+// ActionPolicy defines the actions the workflows of the repositories of an organization, or of the instance, can use.
+// The workflows are checked against the policy of the instance and the one of the owner of their repository,
+// the actions in the repository of a workflow are always allowed.
+type ActionPolicy struct {
+	ID      int64
+	OwnerID int64             `xorm:"UNIQUE NOT NULL DEFAULT 0"` // the organization of the policy, 0 for the policy of the instance
+	Mode    ActionsPolicyMode `xorm:"NOT NULL DEFAULT 0"`
+	// AllowedPatterns are the actions allowed by the selected mode, like `owner/*`, `owner/repo` or `owner/repo@ref`
+	AllowedPatterns []string `xorm:"JSON TEXT"`
+	// BlockedPatterns are the actions never allowed, whatever the mode
+	BlockedPatterns []string `xorm:"JSON TEXT"`
+	// RequireSHAPinning requires the actions not on the instance to be pinned to a full commit SHA
+	RequireSHAPinning bool               `xorm:"NOT NULL DEFAULT false"`
+	Created           timeutil.TimeStamp `xorm:"created"`
+	Updated           timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ActionReference is an action or a reusable workflow used by a workflow
+type ActionReference struct {
+	// Name is the action without its version, with its host if it isn't on the default actions URL
+	Name string
+	// Ref is the version the action is pinned to, a tag, a branch or a commit SHA, or the digest of a docker image
+	Ref string
+	// Local is whether the action is in a repository of the instance
+	Local bool
+}
+
+// String returns the action as it's matched by the patterns of the policies
+func (r *ActionReference) String() string {
+	if r.Ref == "" {
+		return r.Name
+	}
+	return r.Name + "@" + r.Ref
+}
+
+// IsPinned returns whether the action is pinned to a full commit SHA, or to the digest of a docker image
+func (r *ActionReference) IsPinned() bool {
+	if strings.HasPrefix(r.Name, "docker://") {
+		return strings.HasPrefix(r.Ref, "sha256:")
+	}
+	for _, format := range []git.ObjectFormat{git.Sha1ObjectFormat, git.Sha256ObjectFormat} {
+		if len(r.Ref) == format.FullLength() && format.IsValid(r.Ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetActionPolicy returns the policy of the owner, or of the instance for 0, the policy allows all the actions if it hasn't been set
+func GetActionPolicy(ctx context.Context, ownerID int64) (*ActionPolicy, error) {
+	policy := &ActionPolicy{}
+	has, err := db.GetEngine(ctx).Where("owner_id=?", ownerID).Get(policy)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return &ActionPolicy{OwnerID: ownerID}, nil
+	}
+	return policy, nil
+}
+
+// SetActionPolicy creates or replaces the policy of its owner, it returns util.ErrInvalidArgument if a pattern isn't valid
+func SetActionPolicy(ctx context.Context, policy *ActionPolicy) error {
+	for _, pattern := range append(append([]string{}, policy.AllowedPatterns...), policy.BlockedPatterns...) {
+		if _, err := compileActionPattern(pattern); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid action pattern %q: %v", pattern, err)
+		}
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetActionPolicy(ctx, policy.OwnerID)
+		if err != nil {
+			return err
+		}
+		if existing.ID == 0 {
+			return db.Insert(ctx, policy)
+		}
+		policy.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(policy.ID).AllCols().Update(policy)
+		return err
+	})
+}
+
+func compileActionPattern(pattern string) (glob.Glob, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	return glob.Compile(pattern)
+}
+
+// matchActionPatterns returns the first pattern matching the action, a pattern without a version matches all its versions
+func matchActionPatterns(patterns []string, action *ActionReference) (string, bool) {
+	for _, pattern := range patterns {
+		g, err := compileActionPattern(pattern)
+		if err != nil {
+			continue
+		}
+		target := action.Name
+		if strings.Contains(pattern, "@") {
+			target = action.String()
+		}
+		if g.Match(target) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// Check returns why the policy doesn't allow the action, or an empty string if it does
+func (p *ActionPolicy) Check(action *ActionReference) string {
+	if pattern, ok := matchActionPatterns(p.BlockedPatterns, action); ok {
+		return fmt.Sprintf("it's blocked by the pattern %q", pattern)
+	}
+	if action.Local {
+		return ""
+	}
+	switch p.Mode {
+	case ActionsPolicyLocalOnly:
+		return "only the actions of the repositories of the instance are allowed"
+	case ActionsPolicySelected:
+		if _, ok := matchActionPatterns(p.AllowedPatterns, action); !ok {
+			return "it doesn't match an allowed pattern"
+		}
+	}
+	if p.RequireSHAPinning && !action.IsPinned() {
+		return "it must be pinned to a full commit SHA"
+	}
+	return ""
+}
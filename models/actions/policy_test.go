@@ -0,0 +1,109 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionPolicyCheck(t *testing.T) {
+	checkout := &ActionReference{Name: "actions/checkout", Ref: "v4"}
+	pinned := &ActionReference{Name: "actions/checkout", Ref: "11bd71901bbe5b1630ceea73d27597364c9af683"}
+	local := &ActionReference{Name: "org/action", Ref: "main", Local: true}
+	other := &ActionReference{Name: "gitea.com/actions/go-hashfiles", Ref: "v0.0.1"}
+
+	policy := &ActionPolicy{}
+	assert.Empty(t, policy.Check(checkout))
+	assert.Empty(t, policy.Check(other))
+
+	policy = &ActionPolicy{BlockedPatterns: []string{"gitea.com/*", "org/action@main"}}
+	assert.Empty(t, policy.Check(checkout))
+	assert.NotEmpty(t, policy.Check(other))
+	assert.NotEmpty(t, policy.Check(local))
+
+	policy = &ActionPolicy{Mode: ActionsPolicyLocalOnly}
+	assert.NotEmpty(t, policy.Check(checkout))
+	assert.Empty(t, policy.Check(local))
+
+	policy = &ActionPolicy{Mode: ActionsPolicySelected, AllowedPatterns: []string{"actions/*", "gitea.com/actions/go-hashfiles@v1"}}
+	assert.Empty(t, policy.Check(checkout))
+	assert.Empty(t, policy.Check(local))
+	assert.NotEmpty(t, policy.Check(other))
+
+	policy = &ActionPolicy{RequireSHAPinning: true}
+	assert.NotEmpty(t, policy.Check(checkout))
+	assert.Empty(t, policy.Check(pinned))
+	assert.Empty(t, policy.Check(local))
+	assert.NotEmpty(t, policy.Check(&ActionReference{Name: "docker://alpine:3"}))
+	assert.Empty(t, policy.Check(&ActionReference{Name: "docker://alpine:3", Ref: "sha256:c5b1261d6d3e43071626931fc004f70149baeba2c8ec672bd4f27761f8e1ad6b"}))
+}
+
+func TestSetActionPolicy(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	policy, err := GetActionPolicy(db.DefaultContext, 3)
+	require.NoError(t, err)
+	assert.Zero(t, policy.ID)
+	assert.Equal(t, ActionsPolicyAll, policy.Mode)
+
+	require.NoError(t, SetActionPolicy(db.DefaultContext, &ActionPolicy{OwnerID: 3, Mode: ActionsPolicySelected, AllowedPatterns: []string{"actions/*"}}))
+	require.NoError(t, SetActionPolicy(db.DefaultContext, &ActionPolicy{OwnerID: 3, Mode: ActionsPolicyLocalOnly, RequireSHAPinning: true}))
+	policy, err = GetActionPolicy(db.DefaultContext, 3)
+	require.NoError(t, err)
+	assert.Equal(t, ActionsPolicyLocalOnly, policy.Mode)
+	assert.Empty(t, policy.AllowedPatterns)
+	assert.True(t, policy.RequireSHAPinning)
+	assert.Equal(t, 1, unittest.GetCount(t, &ActionPolicy{OwnerID: 3}))
+
+	err = SetActionPolicy(db.DefaultContext, &ActionPolicy{OwnerID: 3, BlockedPatterns: []string{"actions/[checkout"}})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestInsertRunWithFailureAnnotations(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	jobs, err := jobparser.Parse([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+  test:
+    runs-on: ubuntu-latest
+    needs: build
+    steps:
+      - run: echo test
+`))
+	require.NoError(t, err)
+	run := &ActionRun{
+		RepoID:             1,
+		OwnerID:            2,
+		WorkflowID:         "test.yaml",
+		TriggerUserID:      2,
+		Ref:                "refs/heads/master",
+		Status:             StatusWaiting,
+		FailureAnnotations: []string{`the step "actions/checkout@v4" of the job "build" uses "actions/checkout@v4", which isn't allowed`},
+	}
+	require.NoError(t, InsertRun(db.DefaultContext, run, jobs, nil))
+
+	run = unittest.AssertExistsAndLoadBean(t, &ActionRun{ID: run.ID})
+	assert.Equal(t, StatusFailure, run.Status)
+	assert.NotZero(t, run.Stopped)
+	assert.Len(t, run.FailureAnnotations, 1)
+	runJobs, err := GetRunJobsByRunID(db.DefaultContext, run.ID)
+	require.NoError(t, err)
+	require.Len(t, runJobs, 2)
+	for _, job := range runJobs {
+		assert.Equal(t, StatusFailure, job.Status)
+	}
+}
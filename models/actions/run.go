@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	api "code.gitea.io/gitea/modules/structs"
@@ -44,8 +45,16 @@ type ActionRun struct {
 	Event             webhook_module.HookEventType // the webhook event that causes the workflow to run
 	EventPayload      string                       `xorm:"LONGTEXT"`
 	TriggerEvent      string                       // the trigger event defined in the `on` configuration of the triggered workflow
+	DispatchInputs    map[string]string            `xorm:"JSON TEXT"` // the inputs of a run triggered manually by workflow_dispatch
 	Status            Status                       `xorm:"index"`
 	Version           int                          `xorm:"version default 0"` // Status could be updated concomitantly, so an optimistic lock is needed
+	// Attempt is the number of the attempt of the run, it's increased when the run is rerun after it's done
+	Attempt int64 `xorm:"NOT NULL DEFAULT 1"`
+	// ConcurrencyGroup is the evaluated `concurrency` group of the workflow, the runs of a repository sharing it don't run at the same time
+	ConcurrencyGroup  string `xorm:"index NOT NULL DEFAULT ''"`
+	ConcurrencyCancel bool   `xorm:"NOT NULL DEFAULT false"` // cancel-in-progress of the concurrency of the workflow
+	// FailureAnnotations are the reasons the run has failed before being executed, like the actions the policies don't allow
+	FailureAnnotations []string `xorm:"JSON TEXT"`
 	// Started and Stopped is used for recording last run time, if rerun happened, they will be reset to 0
 	Started timeutil.TimeStamp
 	Stopped timeutil.TimeStamp
@@ -221,47 +230,56 @@ func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID strin
 			return err
 		}
 
-		// Iterate over each job and attempt to cancel it.
-		for _, job := range jobs {
-			// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
-			status := job.Status
-			if status.IsDone() {
-				continue
-			}
+		if err := cancelJobs(ctx, jobs); err != nil {
+			return err
+		}
+	}
 
-			// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
-			if job.TaskID == 0 {
-				job.Status = StatusCancelled
-				job.Stopped = timeutil.TimeStampNow()
+	// Return nil to indicate successful cancellation of all running and waiting jobs.
+	return nil
+}
 
-				// Update the job's status and stopped time in the database.
-				n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
-				if err != nil {
-					return err
-				}
+// cancelJobs cancels the jobs which aren't done yet
+func cancelJobs(ctx context.Context, jobs []*ActionRunJob) error {
+	// Iterate over each job and attempt to cancel it.
+	for _, job := range jobs {
+		// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
+		status := job.Status
+		if status.IsDone() {
+			continue
+		}
 
-				// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
-				if n == 0 {
-					return fmt.Errorf("job has changed, try again")
-				}
+		// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
+		if job.TaskID == 0 {
+			job.Status = StatusCancelled
+			job.Stopped = timeutil.TimeStampNow()
 
-				// Continue with the next job.
-				continue
+			// Update the job's status and stopped time in the database.
+			n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
+			if err != nil {
+				return err
 			}
 
-			// If the job has an associated task, try to stop the task, effectively cancelling the job.
-			if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
-				return err
+			// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
+			if n == 0 {
+				return fmt.Errorf("job has changed, try again")
 			}
+
+			// Continue with the next job.
+			continue
 		}
-	}
 
-	// Return nil to indicate successful cancellation of all running and waiting jobs.
+		// If the job has an associated task, try to stop the task, effectively cancelling the job.
+		if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// InsertRun inserts a run
-func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWorkflow) error {
+// InsertRun inserts a run.
+// The evaluated concurrency of each of the jobs, if any, is at the same index in jobConcurrencies.
+func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWorkflow, jobConcurrencies []*Concurrency) error {
 	ctx, committer, err := db.TxContext(ctx)
 	if err != nil {
 		return err
@@ -273,6 +291,15 @@ func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWork
 		return err
 	}
 	run.Index = index
+	run.Attempt = 1
+
+	// a run with failure annotations fails at once, its jobs are never executed
+	failed := len(run.FailureAnnotations) > 0
+	if failed {
+		run.Status = StatusFailure
+		run.Started = timeutil.TimeStampNow()
+		run.Stopped = run.Started
+	}
 
 	if err := db.Insert(ctx, run); err != nil {
 		return err
@@ -290,23 +317,30 @@ func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWork
 		return err
 	}
 
+	// the run is queued behind the runs of its concurrency group it doesn't cancel
+	var runQueued bool
+	if run.ConcurrencyGroup != "" && !failed {
+		if err := cancelConcurrentRuns(ctx, run); err != nil {
+			return err
+		}
+		if runQueued, err = isRunConcurrencyGroupBusy(ctx, run); err != nil {
+			return err
+		}
+	}
+
 	runJobs := make([]*ActionRunJob, 0, len(jobs))
+	// the concurrency groups taken by the jobs of the run
+	takenGroups := make(container.Set[string])
 	var hasWaiting bool
-	for _, v := range jobs {
+	for i, v := range jobs {
 		id, job := v.Job()
 		needs := job.Needs()
 		if err := v.SetJob(id, job.EraseNeeds()); err != nil {
 			return err
 		}
 		payload, _ := v.Marshal()
-		status := StatusWaiting
-		if len(needs) > 0 || run.NeedApproval {
-			status = StatusBlocked
-		} else {
-			hasWaiting = true
-		}
 		job.Name, _ = util.SplitStringAtByteN(job.Name, 255)
-		runJobs = append(runJobs, &ActionRunJob{
+		runJob := &ActionRunJob{
 			RunID:             run.ID,
 			RepoID:            run.RepoID,
 			OwnerID:           run.OwnerID,
@@ -317,8 +351,35 @@ func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWork
 			JobID:             id,
 			Needs:             needs,
 			RunsOn:            job.RunsOn(),
-			Status:            status,
-		})
+		}
+
+		if failed {
+			runJob.Status = StatusFailure
+			runJob.Stopped = run.Stopped
+			runJobs = append(runJobs, runJob)
+			continue
+		}
+
+		jobQueued := false
+		if i < len(jobConcurrencies) && jobConcurrencies[i] != nil && jobConcurrencies[i].Group != "" {
+			runJob.ConcurrencyGroup = jobConcurrencies[i].Group
+			runJob.ConcurrencyCancel = jobConcurrencies[i].CancelInProgress
+			if err := cancelConcurrentJobs(ctx, runJob); err != nil {
+				return err
+			}
+			if jobQueued, err = isJobConcurrencyGroupBusy(ctx, runJob); err != nil {
+				return err
+			}
+			jobQueued = jobQueued || !takenGroups.Add(runJob.ConcurrencyGroup)
+		}
+
+		runJob.Status = StatusWaiting
+		if len(needs) > 0 || run.NeedApproval || runQueued || jobQueued {
+			runJob.Status = StatusBlocked
+		} else {
+			hasWaiting = true
+		}
+		runJobs = append(runJobs, runJob)
 	}
 	if err := db.Insert(ctx, runJobs); err != nil {
 		return err
@@ -378,6 +439,21 @@ func GetWorkflowLatestRun(ctx context.Context, repoID int64, workflowFile, branc
 	return &run, nil
 }
 
+// GetLatestScheduleRun returns the latest run created by the schedule trigger of the workflow
+func GetLatestScheduleRun(ctx context.Context, repoID int64, workflowID string) (*ActionRun, error) {
+	var run ActionRun
+	has, err := db.GetEngine(ctx).Where("repo_id=?", repoID).
+		And("workflow_id = ?", workflowID).
+		And("trigger_event = ?", webhook_module.HookEventSchedule).
+		Desc("id").Get(&run)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("schedule run with repo_id %d, workflow_id %s", repoID, workflowID)
+	}
+	return &run, nil
+}
+
 // UpdateRun updates a run.
 // It requires the inputted run has Version set.
 // It will return error if the version is not matched (it means the run has been changed after loaded).
@@ -7,12 +7,14 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
+	"gopkg.in/yaml.v3"
 	"xorm.io/builder"
 )
 
@@ -33,6 +35,9 @@ type ActionRunJob struct {
 	RunsOn            []string `xorm:"JSON TEXT"`
 	TaskID            int64    // the latest task of the job
 	Status            Status   `xorm:"index"`
+	// ConcurrencyGroup is the evaluated `concurrency` group of the job, the jobs of a repository sharing it don't run at the same time
+	ConcurrencyGroup  string `xorm:"index NOT NULL DEFAULT ''"`
+	ConcurrencyCancel bool   `xorm:"NOT NULL DEFAULT false"` // cancel-in-progress of the concurrency of the job
 	Started           timeutil.TimeStamp
 	Stopped           timeutil.TimeStamp
 	Created           timeutil.TimeStamp `xorm:"created"`
@@ -47,6 +52,39 @@ func (job *ActionRunJob) Duration() time.Duration {
 	return calculateDuration(job.Started, job.Stopped, job.Status)
 }
 
+// Environment returns the name of the `environment` of the job, which is either a name or a mapping of a name and a url.
+// The names with expressions aren't supported, the job has no environment then.
+func (job *ActionRunJob) Environment() string {
+	var workflow struct {
+		Jobs map[string]struct {
+			Environment yaml.Node `yaml:"environment"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(job.WorkflowPayload, &workflow); err != nil {
+		return ""
+	}
+
+	var name string
+	for _, j := range workflow.Jobs {
+		switch j.Environment.Kind {
+		case yaml.ScalarNode:
+			name = j.Environment.Value
+		case yaml.MappingNode:
+			var raw struct {
+				Name string `yaml:"name"`
+			}
+			if err := j.Environment.Decode(&raw); err == nil {
+				name = raw.Name
+			}
+		}
+	}
+	name = strings.TrimSpace(name)
+	if strings.Contains(name, "${{") {
+		return ""
+	}
+	return name
+}
+
 func (job *ActionRunJob) LoadRun(ctx context.Context) error {
 	if job.Run == nil {
 		run, err := GetRunByID(ctx, job.RunID)
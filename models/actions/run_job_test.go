@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRunJob_Environment(t *testing.T) {
+	for payload, expected := range map[string]string{
+		"jobs:\n  deploy:\n    runs-on: ubuntu-latest\n":                                                    "",
+		"jobs:\n  deploy:\n    environment: production\n":                                                   "production",
+		"jobs:\n  deploy:\n    environment:\n      name: staging\n      url: https://staging.example.com\n": "staging",
+		"jobs:\n  deploy:\n    environment: ${{ inputs.target }}\n":                                         "",
+		"invalid: [": "",
+	} {
+		job := &ActionRunJob{WorkflowPayload: []byte(payload)}
+		assert.Equal(t, expected, job.Environment(), payload)
+	}
+}
@@ -6,6 +6,7 @@ package actions
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -46,6 +47,12 @@ type ActionRunner struct {
 
 	// Store labels defined in state file (default: .runner file) of `act_runner`
 	AgentLabels []string `xorm:"TEXT"`
+	// Store labels added with the API, in addition to the labels of `act_runner`
+	CustomLabels []string `xorm:"TEXT"`
+
+	GroupID int64 `xorm:"index NOT NULL DEFAULT 0"` // the runner group of a global or an org level runner, 0 if it isn't in a group
+	// Ephemeral runners are registered with an ephemeral token, they run a single job and are removed once it's done
+	Ephemeral bool `xorm:"NOT NULL DEFAULT false"`
 
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated"`
@@ -82,6 +89,18 @@ func (r *ActionRunner) BelongsToOwnerType() types.OwnerType {
 	return types.OwnerTypeSystemGlobal
 }
 
+// Labels returns the labels the jobs of the runner are matched with, the labels of `act_runner` and the custom ones
+func (r *ActionRunner) Labels() []string {
+	labels := make([]string, 0, len(r.AgentLabels)+len(r.CustomLabels))
+	labels = append(labels, r.AgentLabels...)
+	for _, label := range r.CustomLabels {
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
 // if the logic here changed, you should also modify FindRunnerOptions.ToCond
 func (r *ActionRunner) Status() runnerv1.RunnerStatus {
 	if time.Since(r.LastOnline.AsTime()) > RunnerOfflineTime {
@@ -162,6 +181,7 @@ type FindRunnerOptions struct {
 	Filter        string
 	IsOnline      optional.Option[bool]
 	WithAvailable bool // not only runners belong to, but also runners can be used
+	GroupID       int64
 }
 
 func (opts FindRunnerOptions) ToConds() builder.Cond {
@@ -172,6 +192,8 @@ func (opts FindRunnerOptions) ToConds() builder.Cond {
 		if opts.WithAvailable {
 			c = c.Or(builder.Eq{"owner_id": builder.Select("owner_id").From("repository").Where(builder.Eq{"id": opts.RepoID})})
 			c = c.Or(builder.Eq{"repo_id": 0, "owner_id": 0})
+			// the runners of the groups which aren't granted to the repository can't be used
+			c = c.And(runnerGroupsOfRepoCond(opts.RepoID))
 		}
 		cond = cond.And(c)
 	}
@@ -183,6 +205,10 @@ func (opts FindRunnerOptions) ToConds() builder.Cond {
 		cond = cond.And(c)
 	}
 
+	if opts.GroupID > 0 {
+		cond = cond.And(builder.Eq{"group_id": opts.GroupID})
+	}
+
 	if opts.Filter != "" {
 		cond = cond.And(builder.Like{"name", opts.Filter})
 	}
@@ -0,0 +1,253 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(ActionRunnerGroup))
+	db.RegisterModel(new(ActionRunnerGroupAccess))
+}
+
+// RunnerGroupVisibility defines which repositories can use the runners of a group
+type RunnerGroupVisibility int
+
+const (
+	// RunnerGroupVisibilityAll shares the runners with all the repositories of the owner of the group, or of the instance
+	RunnerGroupVisibilityAll RunnerGroupVisibility = iota
+	// RunnerGroupVisibilitySelected shares the runners with the repositories and the organizations granted to the group only
+	RunnerGroupVisibilitySelected
+)
+
+// String returns the name of the visibility, as used by the API
+func (v RunnerGroupVisibility) String() string {
+	if v == RunnerGroupVisibilitySelected {
+		return "selected"
+	}
+	return "all"
+}
+
+// ParseRunnerGroupVisibility returns the visibility by its name
+func ParseRunnerGroupVisibility(name string) (RunnerGroupVisibility, bool) {
+	switch name {
+	case "all":
+		return RunnerGroupVisibilityAll, true
+	case "selected":
+		return RunnerGroupVisibilitySelected, true
+	}
+	return RunnerGroupVisibilityAll, false
+}
+
+// ActionRunnerGroup is a pool of runners of an organization or of the instance.
+// The runners which aren't in a group can be used by all the repositories of their owner, like a group visible to all,
+// the runners of a group can only be used by the repositories the group is granted to.
+type ActionRunnerGroup struct {
+	ID          int64
+	OwnerID     int64                 `xorm:"UNIQUE(owner_name)"` // the organization of the group, 0 for a group of global runners
+	Name        string                `xorm:"VARCHAR(255) UNIQUE(owner_name) NOT NULL"`
+	Description string                `xorm:"TEXT"`
+	Visibility  RunnerGroupVisibility `xorm:"NOT NULL DEFAULT 0"`
+	Created     timeutil.TimeStamp    `xorm:"created"`
+	Updated     timeutil.TimeStamp    `xorm:"updated"`
+}
+
+// ActionRunnerGroupAccess grants a runner group whose visibility is selected to a repository,
+// or to all the repositories of an organization for a group of global runners
+type ActionRunnerGroupAccess struct {
+	ID      int64
+	GroupID int64 `xorm:"UNIQUE(s) NOT NULL"`
+	OrgID   int64 `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+	RepoID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+}
+
+// CreateRunnerGroup creates a runner group, it returns util.ErrAlreadyExist if its owner has a group with the same name
+func CreateRunnerGroup(ctx context.Context, group *ActionRunnerGroup) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkRunnerGroupName(ctx, group); err != nil {
+			return err
+		}
+		return db.Insert(ctx, group)
+	})
+}
+
+func checkRunnerGroupName(ctx context.Context, group *ActionRunnerGroup) error {
+	has, err := db.GetEngine(ctx).Where(builder.Eq{"owner_id": group.OwnerID, "name": group.Name}.And(builder.Neq{"id": group.ID})).Exist(new(ActionRunnerGroup))
+	if err != nil {
+		return err
+	} else if has {
+		return util.NewAlreadyExistErrorf("runner group %q already exists", group.Name)
+	}
+	return nil
+}
+
+// GetRunnerGroupByID returns the runner group of the owner by its id
+func GetRunnerGroupByID(ctx context.Context, ownerID, id int64) (*ActionRunnerGroup, error) {
+	group := &ActionRunnerGroup{}
+	has, err := db.GetEngine(ctx).Where("id=? AND owner_id=?", id, ownerID).Get(group)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("runner group %d doesn't exist", id)
+	}
+	return group, nil
+}
+
+// UpdateRunnerGroup updates the columns of a runner group, it returns util.ErrAlreadyExist if the new name is already used
+func UpdateRunnerGroup(ctx context.Context, group *ActionRunnerGroup, cols ...string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkRunnerGroupName(ctx, group); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(group.ID).Cols(cols...).Update(group)
+		return err
+	})
+}
+
+// DeleteRunnerGroup deletes a runner group and its accesses, its runners aren't in a group anymore
+func DeleteRunnerGroup(ctx context.Context, group *ActionRunnerGroup) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Table("action_runner").Where("group_id=?", group.ID).Update(map[string]any{"group_id": 0}); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).Delete(&ActionRunnerGroupAccess{GroupID: group.ID}); err != nil {
+			return err
+		}
+		_, err := db.DeleteByID[ActionRunnerGroup](ctx, group.ID)
+		return err
+	})
+}
+
+// DeleteRunnerGroupsByOwnerID deletes the runner groups of an organization and their accesses
+func DeleteRunnerGroupsByOwnerID(ctx context.Context, ownerID int64) error {
+	groupIDs := builder.Select("id").From("action_runner_group").Where(builder.Eq{"owner_id": ownerID})
+	if _, err := db.GetEngine(ctx).Where(builder.In("group_id", groupIDs)).Delete(new(ActionRunnerGroupAccess)); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where("owner_id=?", ownerID).Delete(new(ActionRunnerGroup))
+	return err
+}
+
+// FindRunnerGroupOptions are the options to find the runner groups of an organization or of the instance
+type FindRunnerGroupOptions struct {
+	db.ListOptions
+	OwnerID int64
+}
+
+func (opts FindRunnerGroupOptions) ToConds() builder.Cond {
+	return builder.Eq{"owner_id": opts.OwnerID}
+}
+
+func (opts FindRunnerGroupOptions) ToOrders() string {
+	return "name ASC"
+}
+
+// AddRunnerToGroup moves a runner to the group, the runner has to belong to the owner of the group
+func AddRunnerToGroup(ctx context.Context, group *ActionRunnerGroup, runner *ActionRunner) error {
+	if runner.RepoID != 0 || runner.OwnerID != group.OwnerID {
+		return util.NewInvalidArgumentErrorf("runner %d doesn't belong to the owner of the runner group", runner.ID)
+	}
+	runner.GroupID = group.ID
+	return UpdateRunner(ctx, runner, "group_id")
+}
+
+// RemoveRunnerFromGroup removes a runner from its group
+func RemoveRunnerFromGroup(ctx context.Context, group *ActionRunnerGroup, runner *ActionRunner) error {
+	if runner.GroupID != group.ID {
+		return util.NewNotExistErrorf("runner %d isn't in the runner group", runner.ID)
+	}
+	runner.GroupID = 0
+	return UpdateRunner(ctx, runner, "group_id")
+}
+
+// AddRepoToRunnerGroup grants the runner group to a repository, which has to belong to the owner of an organization group
+func AddRepoToRunnerGroup(ctx context.Context, group *ActionRunnerGroup, repo *repo_model.Repository) error {
+	if group.OwnerID != 0 && repo.OwnerID != group.OwnerID {
+		return util.NewInvalidArgumentErrorf("repository %d doesn't belong to the owner of the runner group", repo.ID)
+	}
+	return addRunnerGroupAccess(ctx, &ActionRunnerGroupAccess{GroupID: group.ID, RepoID: repo.ID})
+}
+
+// RemoveRepoFromRunnerGroup revokes the access of a repository to the runner group
+func RemoveRepoFromRunnerGroup(ctx context.Context, group *ActionRunnerGroup, repoID int64) error {
+	return removeRunnerGroupAccess(ctx, &ActionRunnerGroupAccess{GroupID: group.ID, RepoID: repoID})
+}
+
+// AddOrgToRunnerGroup grants a group of global runners to all the repositories of an organization
+func AddOrgToRunnerGroup(ctx context.Context, group *ActionRunnerGroup, orgID int64) error {
+	if group.OwnerID != 0 {
+		return util.NewInvalidArgumentErrorf("only a group of global runners can be granted to an organization")
+	}
+	return addRunnerGroupAccess(ctx, &ActionRunnerGroupAccess{GroupID: group.ID, OrgID: orgID})
+}
+
+// RemoveOrgFromRunnerGroup revokes the access of an organization to the runner group
+func RemoveOrgFromRunnerGroup(ctx context.Context, group *ActionRunnerGroup, orgID int64) error {
+	return removeRunnerGroupAccess(ctx, &ActionRunnerGroupAccess{GroupID: group.ID, OrgID: orgID})
+}
+
+func addRunnerGroupAccess(ctx context.Context, access *ActionRunnerGroupAccess) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Exist(&ActionRunnerGroupAccess{GroupID: access.GroupID, OrgID: access.OrgID, RepoID: access.RepoID})
+		if err != nil || has {
+			return err
+		}
+		return db.Insert(ctx, access)
+	})
+}
+
+func removeRunnerGroupAccess(ctx context.Context, access *ActionRunnerGroupAccess) error {
+	n, err := db.GetEngine(ctx).Where(builder.Eq{"group_id": access.GroupID, "org_id": access.OrgID, "repo_id": access.RepoID}).Delete(new(ActionRunnerGroupAccess))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return util.NewNotExistErrorf("the runner group isn't granted to it")
+	}
+	return nil
+}
+
+// GetRunnerGroupRepoIDs returns the ids of the repositories the runner group is granted to
+func GetRunnerGroupRepoIDs(ctx context.Context, groupID int64) ([]int64, error) {
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, db.GetEngine(ctx).Table("action_runner_group_access").
+		Where("group_id=? AND repo_id>0", groupID).Asc("repo_id").Cols("repo_id").Find(&repoIDs)
+}
+
+// GetRunnerGroupOrgIDs returns the ids of the organizations a group of global runners is granted to
+func GetRunnerGroupOrgIDs(ctx context.Context, groupID int64) ([]int64, error) {
+	orgIDs := make([]int64, 0, 10)
+	return orgIDs, db.GetEngine(ctx).Table("action_runner_group_access").
+		Where("group_id=? AND org_id>0", groupID).Asc("org_id").Cols("org_id").Find(&orgIDs)
+}
+
+// runnerGroupsOfRepoCond returns the condition on the runner groups the repository can use,
+// the groups visible to all and the ones granted to the repository or to its owner
+func runnerGroupsOfRepoCond(repoID int64) builder.Cond {
+	repoOwnerID := builder.Select("owner_id").From("repository").Where(builder.Eq{"id": repoID})
+	return builder.Eq{"group_id": 0}.
+		Or(builder.In("group_id", builder.Select("id").From("action_runner_group").
+			Where(builder.Eq{"visibility": RunnerGroupVisibilityAll}))).
+		Or(builder.In("group_id", builder.Select("group_id").From("action_runner_group_access").
+			Where(builder.Eq{"repo_id": repoID}.Or(builder.In("org_id", repoOwnerID)))))
+}
+
+// reposOfRunnerGroupCond returns the condition on the repositories which can use the runners of the group
+func reposOfRunnerGroupCond(group *ActionRunnerGroup) builder.Cond {
+	if group.Visibility == RunnerGroupVisibilityAll {
+		return builder.NewCond()
+	}
+	return builder.In("repo_id", builder.Select("repo_id").From("action_runner_group_access").
+		Where(builder.Eq{"group_id": group.ID}.And(builder.Gt{"repo_id": 0}))).
+		Or(builder.In("repo_id", builder.Select("id").From("repository").
+			Where(builder.In("owner_id", builder.Select("org_id").From("action_runner_group_access").
+				Where(builder.Eq{"group_id": group.ID}.And(builder.Gt{"org_id": 0}))))))
+}
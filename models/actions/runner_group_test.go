@@ -0,0 +1,106 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionRunnerGroup(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	runner := &ActionRunner{UUID: "b5f5c5e2-4e1b-4c2e-9b1a-4f3c1c1a2b3c", Name: "global", TokenHash: "runner-group-global"}
+	require.NoError(t, CreateRunner(db.DefaultContext, runner))
+	orgRunner := &ActionRunner{UUID: "c6a6d6f3-5f2c-4d3f-8c2b-5a4d2d2b3c4d", Name: "org3", OwnerID: 3, TokenHash: "runner-group-org3"}
+	require.NoError(t, CreateRunner(db.DefaultContext, orgRunner))
+
+	group := &ActionRunnerGroup{Name: "pool", Visibility: RunnerGroupVisibilitySelected}
+	require.NoError(t, CreateRunnerGroup(db.DefaultContext, group))
+	assert.ErrorIs(t, CreateRunnerGroup(db.DefaultContext, &ActionRunnerGroup{Name: "pool"}), util.ErrAlreadyExist)
+	// the names are unique per owner
+	orgGroup := &ActionRunnerGroup{OwnerID: 3, Name: "pool", Visibility: RunnerGroupVisibilitySelected}
+	require.NoError(t, CreateRunnerGroup(db.DefaultContext, orgGroup))
+
+	_, err := GetRunnerGroupByID(db.DefaultContext, 3, group.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	// the runners and the repositories have to belong to the owner of the group
+	assert.ErrorIs(t, AddRunnerToGroup(db.DefaultContext, orgGroup, runner), util.ErrInvalidArgument)
+	repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	assert.ErrorIs(t, AddRepoToRunnerGroup(db.DefaultContext, orgGroup, repo1), util.ErrInvalidArgument)
+	assert.ErrorIs(t, AddOrgToRunnerGroup(db.DefaultContext, orgGroup, 3), util.ErrInvalidArgument)
+
+	require.NoError(t, AddRunnerToGroup(db.DefaultContext, group, runner))
+	require.NoError(t, AddRepoToRunnerGroup(db.DefaultContext, group, repo1))
+	require.NoError(t, AddRepoToRunnerGroup(db.DefaultContext, group, repo1))
+	repoIDs, err := GetRunnerGroupRepoIDs(db.DefaultContext, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, repoIDs)
+
+	availableRunnerIDs := func(t *testing.T, repoID int64) []int64 {
+		runners, err := db.Find[ActionRunner](db.DefaultContext, FindRunnerOptions{RepoID: repoID, WithAvailable: true})
+		require.NoError(t, err)
+		ids := make([]int64, 0, len(runners))
+		for _, r := range runners {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+	assert.Contains(t, availableRunnerIDs(t, 1), runner.ID)
+	assert.NotContains(t, availableRunnerIDs(t, 2), runner.ID)
+	assert.NotContains(t, availableRunnerIDs(t, 3), runner.ID)
+	assert.Contains(t, availableRunnerIDs(t, 3), orgRunner.ID)
+
+	// granted to all the repositories of an organization
+	require.NoError(t, AddOrgToRunnerGroup(db.DefaultContext, group, 3))
+	orgIDs, err := GetRunnerGroupOrgIDs(db.DefaultContext, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, orgIDs)
+	assert.Contains(t, availableRunnerIDs(t, 3), runner.ID)
+	assert.Contains(t, availableRunnerIDs(t, 5), runner.ID)
+	require.NoError(t, RemoveOrgFromRunnerGroup(db.DefaultContext, group, 3))
+	assert.ErrorIs(t, RemoveOrgFromRunnerGroup(db.DefaultContext, group, 3), util.ErrNotExist)
+	assert.NotContains(t, availableRunnerIDs(t, 3), runner.ID)
+
+	// visible to all the repositories
+	group.Visibility = RunnerGroupVisibilityAll
+	require.NoError(t, UpdateRunnerGroup(db.DefaultContext, group, "visibility"))
+	assert.Contains(t, availableRunnerIDs(t, 2), runner.ID)
+
+	group.Name = "pool"
+	require.NoError(t, UpdateRunnerGroup(db.DefaultContext, group, "name"))
+	other := &ActionRunnerGroup{Name: "other"}
+	require.NoError(t, CreateRunnerGroup(db.DefaultContext, other))
+	other.Name = "pool"
+	assert.ErrorIs(t, UpdateRunnerGroup(db.DefaultContext, other, "name"), util.ErrAlreadyExist)
+
+	runners, err := db.Find[ActionRunner](db.DefaultContext, FindRunnerOptions{GroupID: group.ID})
+	require.NoError(t, err)
+	assert.Len(t, runners, 1)
+
+	require.NoError(t, DeleteRunnerGroup(db.DefaultContext, group))
+	runner, err = GetRunnerByID(db.DefaultContext, runner.ID)
+	require.NoError(t, err)
+	assert.Zero(t, runner.GroupID)
+	unittest.AssertNotExistsBean(t, &ActionRunnerGroupAccess{GroupID: group.ID})
+
+	require.NoError(t, DeleteRunnerGroupsByOwnerID(db.DefaultContext, 3))
+	unittest.AssertNotExistsBean(t, &ActionRunnerGroup{ID: orgGroup.ID})
+}
+
+func TestActionRunnerLabels(t *testing.T) {
+	runner := &ActionRunner{
+		AgentLabels:  []string{"ubuntu-latest", "linux"},
+		CustomLabels: []string{"gpu", "linux"},
+	}
+	assert.Equal(t, []string{"ubuntu-latest", "linux", "gpu"}, runner.Labels())
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// QueuedJobs is the number of the jobs waiting for a runner with a set of labels
+type QueuedJobs struct {
+	Labels []string // sorted
+	Count  int64
+	// OldestQueued is when the job waiting for the longest time has been queued
+	OldestQueued timeutil.TimeStamp
+}
+
+// GetQueuedJobs returns the jobs waiting for a runner grouped by the labels they run on,
+// for the repositories of the owner, or for a repository, or for the instance if both are 0
+func GetQueuedJobs(ctx context.Context, ownerID, repoID int64) ([]*QueuedJobs, error) {
+	cond := builder.Eq{"task_id": 0, "status": StatusWaiting}
+	if ownerID != 0 {
+		cond["owner_id"] = ownerID
+	}
+	if repoID != 0 {
+		cond["repo_id"] = repoID
+	}
+
+	var jobs []*ActionRunJob
+	if err := db.GetEngine(ctx).Where(cond).Cols("runs_on", "updated").Find(&jobs); err != nil {
+		return nil, err
+	}
+
+	byLabels := make(map[string]*QueuedJobs)
+	for _, job := range jobs {
+		labels := append([]string{}, job.RunsOn...)
+		sort.Strings(labels)
+		labels = slices.Compact(labels)
+		key := strings.Join(labels, "\n")
+		queued, ok := byLabels[key]
+		if !ok {
+			queued = &QueuedJobs{Labels: labels, OldestQueued: job.Updated}
+			byLabels[key] = queued
+		}
+		queued.Count++
+		queued.OldestQueued = min(queued.OldestQueued, job.Updated)
+	}
+
+	keys := util.KeysOfMap(byLabels)
+	sort.Strings(keys)
+	result := make([]*QueuedJobs, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, byLabels[key])
+	}
+	return result, nil
+}
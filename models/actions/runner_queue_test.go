@@ -0,0 +1,75 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"slices"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQueuedJobs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	insertJob := func(t *testing.T, ownerID, repoID int64, status Status, runsOn ...string) *ActionRunJob {
+		job := &ActionRunJob{
+			RunID:   791,
+			OwnerID: ownerID,
+			RepoID:  repoID,
+			Name:    "queued",
+			JobID:   "queued",
+			Attempt: 1,
+			RunsOn:  runsOn,
+			Status:  status,
+		}
+		require.NoError(t, db.Insert(db.DefaultContext, job))
+		return job
+	}
+	oldest := insertJob(t, 1, 4, StatusWaiting, "queue-linux", "queue-gpu")
+	_, err := db.GetEngine(db.DefaultContext).ID(oldest.ID).NoAutoTime().Cols("updated").Update(&ActionRunJob{Updated: timeutil.TimeStamp(1683636528)})
+	require.NoError(t, err)
+	insertJob(t, 1, 4, StatusWaiting, "queue-gpu", "queue-linux", "queue-gpu")
+	insertJob(t, 1, 4, StatusWaiting, "queue-linux")
+	insertJob(t, 2, 1, StatusWaiting, "queue-linux")
+	insertJob(t, 1, 4, StatusBlocked, "queue-linux")
+
+	// the jobs of the other tests may be queued too
+	find := func(queued []*QueuedJobs, labels ...string) *QueuedJobs {
+		for _, q := range queued {
+			if slices.Equal(q.Labels, labels) {
+				return q
+			}
+		}
+		return nil
+	}
+
+	queued, err := GetQueuedJobs(db.DefaultContext, 0, 0)
+	require.NoError(t, err)
+	both := find(queued, "queue-gpu", "queue-linux")
+	require.NotNil(t, both)
+	assert.EqualValues(t, 2, both.Count)
+	assert.EqualValues(t, 1683636528, both.OldestQueued)
+	linux := find(queued, "queue-linux")
+	require.NotNil(t, linux)
+	assert.EqualValues(t, 2, linux.Count)
+
+	queued, err = GetQueuedJobs(db.DefaultContext, 2, 0)
+	require.NoError(t, err)
+	assert.Nil(t, find(queued, "queue-gpu", "queue-linux"))
+	linux = find(queued, "queue-linux")
+	require.NotNil(t, linux)
+	assert.EqualValues(t, 1, linux.Count)
+
+	queued, err = GetQueuedJobs(db.DefaultContext, 0, 4)
+	require.NoError(t, err)
+	linux = find(queued, "queue-linux")
+	require.NotNil(t, linux)
+	assert.EqualValues(t, 1, linux.Count)
+}
@@ -6,6 +6,7 @@ package actions
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -23,6 +24,9 @@ type ActionRunnerToken struct {
 	RepoID   int64                  `xorm:"index"` // repo level runner, if orgid also is zero, then it's a global
 	Repo     *repo_model.Repository `xorm:"-"`
 	IsActive bool                   // true means it can be used
+	// Ephemeral tokens register a single ephemeral runner before they expire, they don't invalidate the other tokens
+	Ephemeral bool               `xorm:"NOT NULL DEFAULT false"`
+	Expires   timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"` // 0 means the token never expires
 
 	Created timeutil.TimeStamp `xorm:"created"`
 	Updated timeutil.TimeStamp `xorm:"updated"`
@@ -33,6 +37,11 @@ func init() {
 	db.RegisterModel(new(ActionRunnerToken))
 }
 
+// IsExpired returns whether the token has expired
+func (t *ActionRunnerToken) IsExpired() bool {
+	return t.Expires > 0 && t.Expires <= timeutil.TimeStampNow()
+}
+
 // GetRunnerToken returns a action runner via token
 func GetRunnerToken(ctx context.Context, token string) (*ActionRunnerToken, error) {
 	var runnerToken ActionRunnerToken
@@ -71,7 +80,7 @@ func NewRunnerToken(ctx context.Context, ownerID, repoID int64) (*ActionRunnerTo
 	}
 
 	return runnerToken, db.WithTx(ctx, func(ctx context.Context) error {
-		if _, err := db.GetEngine(ctx).Where("owner_id =? AND repo_id = ?", ownerID, repoID).Cols("is_active").Update(&ActionRunnerToken{
+		if _, err := db.GetEngine(ctx).Where("owner_id =? AND repo_id = ? AND ephemeral = ?", ownerID, repoID, false).Cols("is_active").Update(&ActionRunnerToken{
 			IsActive: false,
 		}); err != nil {
 			return err
@@ -82,10 +91,27 @@ func NewRunnerToken(ctx context.Context, ownerID, repoID int64) (*ActionRunnerTo
 	})
 }
 
+// NewEphemeralRunnerToken creates a token registering a single ephemeral runner until it expires after the ttl
+func NewEphemeralRunnerToken(ctx context.Context, ownerID, repoID int64, ttl time.Duration) (*ActionRunnerToken, error) {
+	token, err := util.CryptoRandomString(40)
+	if err != nil {
+		return nil, err
+	}
+	runnerToken := &ActionRunnerToken{
+		OwnerID:   ownerID,
+		RepoID:    repoID,
+		IsActive:  true,
+		Token:     token,
+		Ephemeral: true,
+		Expires:   timeutil.TimeStamp(time.Now().Add(ttl).Unix()),
+	}
+	return runnerToken, db.Insert(ctx, runnerToken)
+}
+
 // GetLatestRunnerToken returns the latest runner token
 func GetLatestRunnerToken(ctx context.Context, ownerID, repoID int64) (*ActionRunnerToken, error) {
 	var runnerToken ActionRunnerToken
-	has, err := db.GetEngine(ctx).Where("owner_id=? AND repo_id=?", ownerID, repoID).
+	has, err := db.GetEngine(ctx).Where("owner_id=? AND repo_id=? AND ephemeral=?", ownerID, repoID, false).
 		OrderBy("id DESC").Get(&runnerToken)
 	if err != nil {
 		return nil, err
@@ -5,9 +5,11 @@ package actions
 
 import (
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,3 +40,29 @@ func TestUpdateRunnerToken(t *testing.T) {
 	assert.NoError(t, err)
 	assert.EqualValues(t, token, expectedToken)
 }
+
+func TestNewEphemeralRunnerToken(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	latest, err := GetLatestRunnerToken(db.DefaultContext, 1, 0)
+	assert.NoError(t, err)
+
+	token, err := NewEphemeralRunnerToken(db.DefaultContext, 1, 0, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, token.Ephemeral)
+	assert.True(t, token.IsActive)
+	assert.False(t, token.IsExpired())
+
+	// an ephemeral token is never the latest token of the owner
+	expectedToken, err := GetLatestRunnerToken(db.DefaultContext, 1, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, latest.ID, expectedToken.ID)
+
+	// a new token doesn't invalidate the ephemeral ones
+	_, err = NewRunnerToken(db.DefaultContext, 1, 0)
+	assert.NoError(t, err)
+	token = unittest.AssertExistsAndLoadBean(t, &ActionRunnerToken{ID: token.ID})
+	assert.True(t, token.IsActive)
+
+	token.Expires = timeutil.TimeStampNow() - 1
+	assert.True(t, token.IsExpired())
+}
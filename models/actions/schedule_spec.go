@@ -9,6 +9,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/robfig/cron/v3"
 )
@@ -48,3 +49,21 @@ func UpdateScheduleSpec(ctx context.Context, spec *ActionScheduleSpec, cols ...s
 	_, err := sess.Update(spec)
 	return err
 }
+
+// GetScheduleSpecByRepoID returns the spec of the repository with its schedule and its repository loaded
+func GetScheduleSpecByRepoID(ctx context.Context, repoID, id int64) (*ActionScheduleSpec, error) {
+	spec := &ActionScheduleSpec{}
+	has, err := db.GetEngine(ctx).Where("id=? AND repo_id=?", id, repoID).Get(spec)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("schedule spec with id %d", id)
+	}
+	if err := (SpecList{spec}).LoadSchedules(ctx); err != nil {
+		return nil, err
+	}
+	if spec.Schedule == nil {
+		return nil, util.NewNotExistErrorf("schedule of the spec %d", id)
+	}
+	return spec, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetScheduleSpecByRepoID(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	schedule := &ActionSchedule{
+		Title:      "nightly",
+		Specs:      []string{"0 2 * * *", "@hourly"},
+		RepoID:     4,
+		OwnerID:    5,
+		WorkflowID: "nightly.yaml",
+		Event:      webhook_module.HookEventPush,
+	}
+	require.NoError(t, CreateScheduleTask(db.DefaultContext, []*ActionSchedule{schedule}))
+
+	specs, _, err := FindSpecs(db.DefaultContext, FindSpecOptions{ListOptions: db.ListOptionsAll, RepoID: 4})
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+
+	spec, err := GetScheduleSpecByRepoID(db.DefaultContext, 4, specs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, specs[0].Spec, spec.Spec)
+	assert.NotZero(t, spec.Next)
+	if assert.NotNil(t, spec.Schedule) {
+		assert.Equal(t, "nightly.yaml", spec.Schedule.WorkflowID)
+	}
+
+	_, err = GetScheduleSpecByRepoID(db.DefaultContext, 1, specs[0].ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
+
+func TestGetLatestScheduleRun(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := GetLatestScheduleRun(db.DefaultContext, 4, "scheduled.yaml")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	for i := 0; i < 2; i++ {
+		run := &ActionRun{
+			Title:         "scheduled",
+			Index:         int64(100 + i),
+			RepoID:        4,
+			OwnerID:       5,
+			WorkflowID:    "scheduled.yaml",
+			TriggerUserID: 5,
+			Ref:           "refs/heads/master",
+			Event:         webhook_module.HookEventPush,
+			TriggerEvent:  string(webhook_module.HookEventSchedule),
+			Status:        StatusWaiting,
+		}
+		require.NoError(t, db.Insert(db.DefaultContext, run))
+	}
+	dispatched := &ActionRun{
+		Title:         "dispatched",
+		Index:         102,
+		RepoID:        4,
+		OwnerID:       5,
+		WorkflowID:    "scheduled.yaml",
+		TriggerUserID: 5,
+		Ref:           "refs/heads/master",
+		Event:         webhook_module.HookEventWorkflowDispatch,
+		TriggerEvent:  string(webhook_module.HookEventWorkflowDispatch),
+		Status:        StatusWaiting,
+	}
+	require.NoError(t, db.Insert(db.DefaultContext, dispatched))
+
+	run, err := GetLatestScheduleRun(db.DefaultContext, 4, "scheduled.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "scheduled", run.Title)
+	assert.Less(t, run.ID, dispatched.ID)
+}
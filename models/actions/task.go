@@ -232,6 +232,14 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 			Join("INNER", "repo_unit", "`repository`.id = `repo_unit`.repo_id").
 			Where(builder.Eq{"`repository`.owner_id": runner.OwnerID, "`repo_unit`.type": unit.TypeActions}))
 	}
+	if runner.GroupID != 0 {
+		group := &ActionRunnerGroup{}
+		if has, err := e.ID(runner.GroupID).Get(group); err != nil {
+			return nil, false, err
+		} else if has {
+			jobCond = jobCond.And(reposOfRunnerGroupCond(group))
+		}
+	}
 	if jobCond.IsValid() {
 		jobCond = builder.In("run_id", builder.Select("id").From("action_run").Where(jobCond))
 	}
@@ -243,9 +251,10 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 
 	// TODO: a more efficient way to filter labels
 	var job *ActionRunJob
-	log.Trace("runner labels: %v", runner.AgentLabels)
+	labels := runner.Labels()
+	log.Trace("runner labels: %v", labels)
 	for _, v := range jobs {
-		if isSubset(runner.AgentLabels, v.RunsOn) {
+		if isSubset(labels, v.RunsOn) {
 			job = v
 			break
 		}
@@ -509,3 +518,9 @@ func getTaskIDFromCache(token string) int64 {
 	}
 	return t
 }
+
+// GetRepoTaskLogsSize returns the total size of the task logs stored for a repository
+func GetRepoTaskLogsSize(ctx context.Context, repoID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("repo_id = ? AND log_expired = ?", repoID, false).
+		SumInt(new(ActionTask), "log_size")
+}
@@ -20,6 +20,7 @@ type ActionVariable struct {
 	OwnerID     int64              `xorm:"UNIQUE(owner_repo_name)"`
 	RepoID      int64              `xorm:"INDEX UNIQUE(owner_repo_name)"`
 	Name        string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+	Environment string             `xorm:"UNIQUE(owner_repo_name) NOT NULL DEFAULT ''"` // the environment of the repository the variable is bound to, if any
 	Data        string             `xorm:"LONGTEXT NOT NULL"`
 	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
@@ -33,15 +34,29 @@ func (v *ActionVariable) Validate() error {
 	if v.OwnerID != 0 && v.RepoID != 0 {
 		return errors.New("a variable should not be bound to an owner and a repository at the same time")
 	}
+	if v.Environment != "" && v.RepoID == 0 {
+		return errors.New("a variable bound to an environment should be bound to a repository")
+	}
 	return nil
 }
 
+// Scope returns the level the variable is defined at
+func (v *ActionVariable) Scope() VariableScope {
+	return ScopeOf(v.OwnerID, v.RepoID, v.Environment)
+}
+
 func InsertVariable(ctx context.Context, ownerID, repoID int64, name, data string) (*ActionVariable, error) {
+	return InsertEnvironmentVariable(ctx, ownerID, repoID, "", name, data)
+}
+
+// InsertEnvironmentVariable inserts a variable bound to an environment of a repository
+func InsertEnvironmentVariable(ctx context.Context, ownerID, repoID int64, environment, name, data string) (*ActionVariable, error) {
 	variable := &ActionVariable{
-		OwnerID: ownerID,
-		RepoID:  repoID,
-		Name:    strings.ToUpper(name),
-		Data:    data,
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Name:        strings.ToUpper(name),
+		Environment: environment,
+		Data:        data,
 	}
 	if err := variable.Validate(); err != nil {
 		return variable, err
@@ -51,9 +66,10 @@ func InsertVariable(ctx context.Context, ownerID, repoID int64, name, data strin
 
 type FindVariablesOpts struct {
 	db.ListOptions
-	OwnerID int64
-	RepoID  int64
-	Name    string
+	OwnerID     int64
+	RepoID      int64
+	Name        string
+	Environment string
 }
 
 func (opts FindVariablesOpts) ToConds() builder.Cond {
@@ -62,6 +78,7 @@ func (opts FindVariablesOpts) ToConds() builder.Cond {
 	// there is no need to check for null values for `owner_id` and `repo_id`
 	cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
 	cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	cond = cond.And(builder.Eq{"environment": opts.Environment})
 
 	if opts.Name != "" {
 		cond = cond.And(builder.Eq{"name": strings.ToUpper(opts.Name)})
@@ -89,37 +106,53 @@ func DeleteVariable(ctx context.Context, id int64) error {
 	return nil
 }
 
-func GetVariablesOfRun(ctx context.Context, run *ActionRun) (map[string]string, error) {
-	variables := map[string]string{}
+// FindVariablesOfScopes returns the variables of the instance, of the owner, of the repository and of the environment
+// of the repository if it isn't empty, in their order of precedence: a variable overrides the ones of the same name before it
+func FindVariablesOfScopes(ctx context.Context, ownerID, repoID int64, environment string) ([]*ActionVariable, error) {
+	scopes := []FindVariablesOpts{{}, {OwnerID: ownerID}, {RepoID: repoID}}
+	if environment != "" {
+		scopes = append(scopes, FindVariablesOpts{RepoID: repoID, Environment: environment})
+	}
 
-	if err := run.LoadRepo(ctx); err != nil {
-		log.Error("LoadRepo: %v", err)
-		return nil, err
+	var variables []*ActionVariable
+	for _, opts := range scopes {
+		vars, err := db.Find[ActionVariable](ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		variables = append(variables, vars...)
 	}
+	return variables, nil
+}
 
-	// Global
-	globalVariables, err := db.Find[ActionVariable](ctx, FindVariablesOpts{})
-	if err != nil {
-		log.Error("find global variables: %v", err)
+func GetVariablesOfRun(ctx context.Context, run *ActionRun) (map[string]string, error) {
+	return getVariablesOfRun(ctx, run, "")
+}
+
+// GetVariablesOfJob returns the variables of the run of the job, overridden by the ones of the environment of the job
+func GetVariablesOfJob(ctx context.Context, job *ActionRunJob) (map[string]string, error) {
+	if err := job.LoadRun(ctx); err != nil {
 		return nil, err
 	}
+	return getVariablesOfRun(ctx, job.Run, job.Environment())
+}
 
-	// Org / User level
-	ownerVariables, err := db.Find[ActionVariable](ctx, FindVariablesOpts{OwnerID: run.Repo.OwnerID})
-	if err != nil {
-		log.Error("find variables of org: %d, error: %v", run.Repo.OwnerID, err)
+func getVariablesOfRun(ctx context.Context, run *ActionRun, environment string) (map[string]string, error) {
+	variables := map[string]string{}
+
+	if err := run.LoadRepo(ctx); err != nil {
+		log.Error("LoadRepo: %v", err)
 		return nil, err
 	}
 
-	// Repo level
-	repoVariables, err := db.Find[ActionVariable](ctx, FindVariablesOpts{RepoID: run.RepoID})
+	vars, err := FindVariablesOfScopes(ctx, run.Repo.OwnerID, run.RepoID, environment)
 	if err != nil {
 		log.Error("find variables of repo: %d, error: %v", run.RepoID, err)
 		return nil, err
 	}
 
-	// Level precedence: Repo > Org / User > Global
-	for _, v := range append(globalVariables, append(ownerVariables, repoVariables...)...) {
+	// Level precedence: Environment > Repo > Org / User > Global
+	for _, v := range vars {
 		variables[v.Name] = v.Data
 	}
 
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// VariableScope is the level a variable or a secret is defined at,
+// a variable or a secret overrides the ones of the same name defined at the levels before it
+type VariableScope int
+
+const (
+	VariableScopeInstance VariableScope = iota
+	VariableScopeOwner
+	VariableScopeRepository
+	VariableScopeEnvironment
+)
+
+// String returns the name of the scope used by the API
+func (s VariableScope) String() string {
+	switch s {
+	case VariableScopeInstance:
+		return "instance"
+	case VariableScopeOwner:
+		return "owner"
+	case VariableScopeRepository:
+		return "repository"
+	case VariableScopeEnvironment:
+		return "environment"
+	}
+	return "unknown"
+}
+
+// ScopeOf returns the scope of a variable or a secret bound to the owner, the repository and the environment
+func ScopeOf(ownerID, repoID int64, environment string) VariableScope {
+	switch {
+	case environment != "":
+		return VariableScopeEnvironment
+	case repoID != 0:
+		return VariableScopeRepository
+	case ownerID != 0:
+		return VariableScopeOwner
+	}
+	return VariableScopeInstance
+}
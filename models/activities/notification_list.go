@@ -138,6 +138,16 @@ func createOrUpdateIssueNotifications(ctx context.Context, issueID, commentID, n
 		return err
 	}
 
+	// the internal comments are only notified to the users who can see them
+	isInternal := false
+	if commentID > 0 {
+		comment, err := issues_model.GetCommentByID(ctx, commentID)
+		if err != nil && !issues_model.IsErrCommentNotExist(err) {
+			return err
+		}
+		isInternal = comment != nil && comment.IsInternal
+	}
+
 	// notify
 	for userID := range toNotify {
 		issue.Repo.Units = nil
@@ -155,6 +165,15 @@ func createOrUpdateIssueNotifications(ctx context.Context, issueID, commentID, n
 		if !issue.IsPull && !access_model.CheckRepoUnitUser(ctx, issue.Repo, user, unit.TypeIssues) {
 			continue
 		}
+		if isInternal {
+			perm, err := access_model.GetUserRepoPermission(ctx, issue.Repo, user)
+			if err != nil {
+				return err
+			}
+			if !perm.CanWriteIssuesOrPulls(issue.IsPull) {
+				continue
+			}
+		}
 
 		if notificationExists(notifications, issue.ID, userID) {
 			if err = updateIssueNotification(ctx, userID, issue.ID, commentID, notificationAuthorID); err != nil {
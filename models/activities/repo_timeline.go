@@ -0,0 +1,120 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"xorm.io/builder"
+)
+
+// TimelineEventType is the kind of object the actions of the timeline of a repository are about
+type TimelineEventType string
+
+// The kinds of the events of a repository timeline
+const (
+	TimelineEventIssue       TimelineEventType = "issue"
+	TimelineEventPullRequest TimelineEventType = "pull_request"
+	TimelineEventCommit      TimelineEventType = "commit"
+)
+
+// TimelineEventTypes are all the kinds of the events of a repository timeline
+var TimelineEventTypes = []TimelineEventType{TimelineEventIssue, TimelineEventPullRequest, TimelineEventCommit}
+
+var timelineActionTypes = map[TimelineEventType][]ActionType{
+	TimelineEventIssue: {
+		ActionCreateIssue,
+		ActionCommentIssue,
+		ActionCloseIssue,
+		ActionReopenIssue,
+	},
+	TimelineEventPullRequest: {
+		ActionCreatePullRequest,
+		ActionMergePullRequest,
+		ActionClosePullRequest,
+		ActionReopenPullRequest,
+		ActionApprovePullRequest,
+		ActionRejectPullRequest,
+		ActionCommentPull,
+		ActionPullReviewDismissed,
+		ActionPullRequestReadyForReview,
+		ActionAutoMergePullRequest,
+	},
+	TimelineEventCommit: {
+		ActionCommitRepo,
+		ActionPushTag,
+		ActionDeleteTag,
+		ActionDeleteBranch,
+		ActionMirrorSyncPush,
+		ActionMirrorSyncCreate,
+		ActionMirrorSyncDelete,
+	},
+}
+
+// IsValid returns true if the kind of event is known
+func (t TimelineEventType) IsValid() bool {
+	_, ok := timelineActionTypes[t]
+	return ok
+}
+
+// TimelineEventType returns the kind of object the action is about, it's empty if the action isn't part of the timeline
+func (a *Action) TimelineEventType() TimelineEventType {
+	for t, opTypes := range timelineActionTypes {
+		for _, opType := range opTypes {
+			if a.OpType == opType {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+// RepoTimelineOptions options to get the events of the timeline of a repository
+type RepoTimelineOptions struct {
+	Repo  *repo_model.Repository
+	Actor *user_model.User
+	// Since is the cursor of the last received event, only the following events are returned
+	Since int64
+	Types []TimelineEventType
+	Limit int
+}
+
+// GetRepoTimeline returns the issue, pull request and commit actions of a repository in the order they happened.
+// The IDs of the actions are the cursors of the timeline.
+func GetRepoTimeline(ctx context.Context, opts RepoTimelineOptions) (ActionList, error) {
+	cond, err := activityQueryCondition(ctx, GetFeedsOptions{
+		RequestedRepo:  opts.Repo,
+		Actor:          opts.Actor,
+		IncludePrivate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opTypes := make([]ActionType, 0, 16)
+	for _, t := range opts.Types {
+		opTypes = append(opTypes, timelineActionTypes[t]...)
+	}
+	if len(opTypes) == 0 {
+		return ActionList{}, nil
+	}
+	cond = cond.And(builder.Gt{"`action`.id": opts.Since}, builder.In("`action`.op_type", opTypes))
+
+	actions := make([]*Action, 0, opts.Limit)
+	if err := db.GetEngine(ctx).Where(cond).
+		OrderBy("`action`.id ASC").
+		Limit(opts.Limit).
+		Find(&actions); err != nil {
+		return nil, err
+	}
+
+	if err := ActionList(actions).LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities_test
+
+import (
+	"testing"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRepoTimeline(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	createIssue := &activities_model.Action{UserID: 2, ActUserID: 2, OpType: activities_model.ActionCreateIssue, RepoID: repo.ID, Content: "6|new issue"}
+	// the copy of the action for a watcher isn't part of the timeline
+	watcherCopy := &activities_model.Action{UserID: 4, ActUserID: 2, OpType: activities_model.ActionCreateIssue, RepoID: repo.ID, Content: "6|new issue"}
+	star := &activities_model.Action{UserID: 2, ActUserID: 2, OpType: activities_model.ActionStarRepo, RepoID: repo.ID}
+	push := &activities_model.Action{UserID: 2, ActUserID: 2, OpType: activities_model.ActionCommitRepo, RepoID: repo.ID, RefName: "refs/heads/master"}
+	assert.NoError(t, db.Insert(db.DefaultContext, createIssue, watcherCopy, star, push))
+
+	actions, err := activities_model.GetRepoTimeline(db.DefaultContext, activities_model.RepoTimelineOptions{
+		Repo:  repo,
+		Actor: user2,
+		Types: activities_model.TimelineEventTypes,
+		Limit: 10,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, actions, 3) {
+		// the fixture closing an issue
+		assert.EqualValues(t, 9, actions[0].ID)
+		assert.Equal(t, activities_model.TimelineEventIssue, actions[0].TimelineEventType())
+		assert.Equal(t, createIssue.ID, actions[1].ID)
+		assert.Equal(t, push.ID, actions[2].ID)
+		assert.Equal(t, activities_model.TimelineEventCommit, actions[2].TimelineEventType())
+	}
+
+	actions, err = activities_model.GetRepoTimeline(db.DefaultContext, activities_model.RepoTimelineOptions{
+		Repo:  repo,
+		Actor: user2,
+		Since: createIssue.ID,
+		Types: activities_model.TimelineEventTypes,
+		Limit: 10,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, actions, 1) {
+		assert.Equal(t, push.ID, actions[0].ID)
+	}
+
+	actions, err = activities_model.GetRepoTimeline(db.DefaultContext, activities_model.RepoTimelineOptions{
+		Repo:  repo,
+		Actor: user2,
+		Types: []activities_model.TimelineEventType{activities_model.TimelineEventIssue},
+		Limit: 1,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, actions, 1) {
+		assert.EqualValues(t, 9, actions[0].ID)
+	}
+}
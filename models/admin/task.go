@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -47,6 +48,18 @@ type TranslatableMessage struct {
 	Args   []any `json:"omitempty"`
 }
 
+// LocaleMessage returns the message of the task translated if it is a TranslatableMessage
+func (task *Task) LocaleMessage(lang translation.Locale) string {
+	if task.Message == "" || task.Message[0] != '{' {
+		return task.Message
+	}
+	var translatableMessage TranslatableMessage
+	if err := json.Unmarshal([]byte(task.Message), &translatableMessage); err != nil {
+		return task.Message
+	}
+	return lang.TrString(translatableMessage.Format, translatableMessage.Args...)
+}
+
 // LoadRepo loads repository of the task
 func (task *Task) LoadRepo(ctx context.Context) error {
 	if task.Repo != nil {
@@ -200,6 +213,21 @@ func GetMigratingTaskByID(ctx context.Context, id, doerID int64) (*Task, *migrat
 	return &task, &opts, nil
 }
 
+// GetLatestTask returns the latest task of the type of the repository
+func GetLatestTask(ctx context.Context, repoID int64, taskType structs.TaskType) (*Task, error) {
+	task := Task{
+		RepoID: repoID,
+		Type:   taskType,
+	}
+	has, err := db.GetEngine(ctx).Desc("id").Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{0, repoID, task.Type}
+	}
+	return &task, nil
+}
+
 // CreateTask creates a task on database
 func CreateTask(ctx context.Context, task *Task) error {
 	return db.Insert(ctx, task)
@@ -512,11 +512,12 @@ func (err ErrMergeDivergingFastForwardOnly) Error() string {
 
 // ErrRebaseConflicts represents an error if rebase fails with a conflict
 type ErrRebaseConflicts struct {
-	Style     repo_model.MergeStyle
-	CommitSHA string
-	StdOut    string
-	StdErr    string
-	Err       error
+	Style           repo_model.MergeStyle
+	CommitSHA       string
+	ConflictedFiles []string
+	StdOut          string
+	StdErr          string
+	Err             error
 }
 
 // IsErrRebaseConflicts checks if an error is a ErrRebaseConflicts.
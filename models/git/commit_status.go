@@ -409,6 +409,46 @@ func FindRepoRecentCommitStatusContexts(ctx context.Context, repoID int64, befor
 	return contexts, nil
 }
 
+// DeleteOldCommitStatuses deletes the commit statuses created before the given duration,
+// the latest status of every context of a commit is always kept so the combined status doesn't change.
+// It returns the number of deleted statuses.
+func DeleteOldCommitStatuses(ctx context.Context, olderThan time.Duration) (int64, error) {
+	if olderThan <= 0 {
+		return 0, nil
+	}
+
+	const batchSize = 1000
+	before := time.Now().Add(-olderThan).Unix()
+	var deleted int64
+	for {
+		select {
+		case <-ctx.Done():
+			return deleted, db.ErrCancelledf("when deleting old commit statuses")
+		default:
+		}
+
+		ids := make([]int64, 0, batchSize)
+		if err := db.GetEngine(ctx).Table("commit_status").Alias("cs").Cols("cs.id").
+			Where("cs.created_unix < ?", before).
+			And("EXISTS (SELECT 1 FROM commit_status newer WHERE newer.repo_id = cs.repo_id AND newer.sha = cs.sha AND newer.context_hash = cs.context_hash AND newer.id > cs.id)").
+			Limit(batchSize).Find(&ids); err != nil {
+			return deleted, err
+		}
+		if len(ids) == 0 {
+			return deleted, nil
+		}
+
+		n, err := db.GetEngine(ctx).In("id", ids).NoAutoCondition().Delete(new(CommitStatus))
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+		if len(ids) < batchSize {
+			return deleted, nil
+		}
+	}
+}
+
 // NewCommitStatusOptions holds options for creating a CommitStatus
 type NewCommitStatusOptions struct {
 	Repo         *repo_model.Repository
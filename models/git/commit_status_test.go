@@ -231,3 +231,56 @@ func TestFindRepoRecentCommitStatusContexts(t *testing.T) {
 		assert.Equal(t, "compliance/lint-backend", contexts[0])
 	}
 }
+
+func TestDeleteOldCommitStatuses(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo2 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo2)
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo2.DefaultBranch)
+	assert.NoError(t, err)
+
+	for _, status := range []struct {
+		state   structs.CommitStatusState
+		context string
+	}{
+		{structs.CommitStatusPending, "ci/build"},
+		{structs.CommitStatusSuccess, "ci/build"},
+		{structs.CommitStatusPending, "ci/test"},
+		{structs.CommitStatusFailure, "ci/test"},
+		{structs.CommitStatusPending, "ci/lint"},
+	} {
+		assert.NoError(t, git_model.NewCommitStatus(db.DefaultContext, git_model.NewCommitStatusOptions{
+			Repo:    repo2,
+			Creator: user2,
+			SHA:     commit.ID,
+			CommitStatus: &git_model.CommitStatus{
+				State:   status.state,
+				Context: status.context,
+			},
+		}))
+	}
+
+	deleted, err := git_model.DeleteOldCommitStatuses(db.DefaultContext, time.Hour)
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	// make the statuses of ci/build and ci/lint old, ci/test is still recent
+	_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE commit_status SET created_unix = ? WHERE repo_id = ? AND context <> ?",
+		time.Now().Add(-48*time.Hour).Unix(), repo2.ID, "ci/test")
+	assert.NoError(t, err)
+
+	deleted, err = git_model.DeleteOldCommitStatuses(db.DefaultContext, 24*time.Hour)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+
+	statuses, _, err := git_model.GetLatestCommitStatus(db.DefaultContext, repo2.ID, commit.ID.String(), db.ListOptionsAll)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 3)
+	assert.EqualValues(t, 4, unittest.GetCount(t, &git_model.CommitStatus{RepoID: repo2.ID}))
+	unittest.AssertNotExistsBean(t, &git_model.CommitStatus{RepoID: repo2.ID, Context: "ci/build", State: structs.CommitStatusPending})
+}
@@ -86,6 +86,27 @@ func (err ErrLFSLockAlreadyExist) Unwrap() error {
 	return util.ErrAlreadyExist
 }
 
+// ErrLFSLockNotOwner represents a "LFSLockNotOwner" kind of error.
+type ErrLFSLockNotOwner struct {
+	RepoID   int64
+	Path     string
+	UserName string
+}
+
+// IsErrLFSLockNotOwner checks if an error is a ErrLFSLockNotOwner.
+func IsErrLFSLockNotOwner(err error) bool {
+	_, ok := err.(ErrLFSLockNotOwner)
+	return ok
+}
+
+func (err ErrLFSLockNotOwner) Error() string {
+	return fmt.Sprintf("User %s doesn't own lfs lock and force flag is not set [rid: %d, path: %s]", err.UserName, err.RepoID, err.Path)
+}
+
+func (err ErrLFSLockNotOwner) Unwrap() error {
+	return util.ErrPermissionDenied
+}
+
 // ErrLFSFileLocked represents a "LFSFileLocked" kind of error.
 type ErrLFSFileLocked struct {
 	RepoID   int64
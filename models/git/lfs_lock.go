@@ -5,7 +5,6 @@ package git
 
 import (
 	"context"
-	"errors"
 	"strings"
 	"time"
 
@@ -104,7 +103,7 @@ func GetLFSLockByRepoID(ctx context.Context, repoID int64, page, pageSize int) (
 		e.Limit(pageSize, start)
 	}
 	lfsLocks := make([]*LFSLock, 0, pageSize)
-	return lfsLocks, e.Find(&lfsLocks, &LFSLock{RepoID: repoID})
+	return lfsLocks, e.OrderBy("id").Find(&lfsLocks, &LFSLock{RepoID: repoID})
 }
 
 // GetTreePathLock returns LSF lock for the treePath
@@ -142,13 +141,16 @@ func DeleteLFSLockByID(ctx context.Context, id int64, repo *repo_model.Repositor
 	if err != nil {
 		return nil, err
 	}
+	if lock.RepoID != repo.ID {
+		return nil, ErrLFSLockNotExist{id, repo.ID, ""}
+	}
 
 	if err := CheckLFSAccessForRepo(dbCtx, u.ID, repo, perm.AccessModeWrite); err != nil {
 		return nil, err
 	}
 
 	if !force && u.ID != lock.OwnerID {
-		return nil, errors.New("user doesn't own lock and force flag is not set")
+		return nil, ErrLFSLockNotOwner{lock.RepoID, lock.Path, u.Name}
 	}
 
 	if _, err := db.GetEngine(dbCtx).ID(id).Delete(new(LFSLock)); err != nil {
@@ -0,0 +1,211 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+// ManagedGitHook holds the git hook scripts and the built-in push checks defined by the instance administrators
+// for all the repositories of an organization
+type ManagedGitHook struct {
+	ID    int64 `xorm:"pk autoincr"`
+	OrgID int64 `xorm:"UNIQUE NOT NULL"`
+	// MaxFileSize is the maximum size in bytes of a pushed file, 0 means unlimited
+	MaxFileSize          int64    `xorm:"NOT NULL DEFAULT 0"`
+	ForbiddenPaths       []string `xorm:"JSON TEXT"`
+	CommitMessagePattern string   `xorm:"TEXT"`
+	PreReceiveScript     string   `xorm:"LONGTEXT"`
+	UpdateScript         string   `xorm:"LONGTEXT"`
+	// AllowRepoOptOut allows the repository administrators to opt out, instance administrators always can
+	AllowRepoOptOut bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+
+	forbiddenPathGlobs   []glob.Glob    `xorm:"-"`
+	commitMessageRegexp  *regexp.Regexp `xorm:"-"`
+	loadedCompiledChecks bool           `xorm:"-"`
+}
+
+// ManagedGitHookOptOut represents a repository which doesn't apply the managed git hook of its owner
+type ManagedGitHookOptOut struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE NOT NULL"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	ByAdmin     bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ManagedGitHook))
+	db.RegisterModel(new(ManagedGitHookOptOut))
+}
+
+// HasScripts returns whether the managed git hook has hook scripts to run
+func (hook *ManagedGitHook) HasScripts() bool {
+	return strings.TrimSpace(hook.PreReceiveScript) != "" || strings.TrimSpace(hook.UpdateScript) != ""
+}
+
+// HasChecks returns whether the managed git hook has built-in checks of the pushed commits
+func (hook *ManagedGitHook) HasChecks() bool {
+	return hook.MaxFileSize > 0 || len(hook.ForbiddenPaths) > 0 || hook.CommitMessagePattern != ""
+}
+
+func (hook *ManagedGitHook) compileChecks() error {
+	if hook.loadedCompiledChecks {
+		return nil
+	}
+
+	hook.forbiddenPathGlobs = make([]glob.Glob, 0, len(hook.ForbiddenPaths))
+	for _, pattern := range hook.ForbiddenPaths {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return util.NewInvalidArgumentErrorf("invalid forbidden path pattern %q: %v", pattern, err)
+		}
+		hook.forbiddenPathGlobs = append(hook.forbiddenPathGlobs, g)
+	}
+
+	hook.commitMessageRegexp = nil
+	if hook.CommitMessagePattern != "" {
+		re, err := regexp.Compile(hook.CommitMessagePattern)
+		if err != nil {
+			return util.NewInvalidArgumentErrorf("invalid commit message pattern: %v", err)
+		}
+		hook.commitMessageRegexp = re
+	}
+
+	hook.loadedCompiledChecks = true
+	return nil
+}
+
+// IsForbiddenPath returns whether the path matches one of the forbidden path patterns
+func (hook *ManagedGitHook) IsForbiddenPath(path string) bool {
+	if err := hook.compileChecks(); err != nil {
+		return false
+	}
+	for _, g := range hook.forbiddenPathGlobs {
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidCommitMessage returns whether the commit message matches the commit message pattern
+func (hook *ManagedGitHook) IsValidCommitMessage(message string) bool {
+	if err := hook.compileChecks(); err != nil || hook.commitMessageRegexp == nil {
+		return true
+	}
+	return hook.commitMessageRegexp.MatchString(message)
+}
+
+// Validate checks the settings of the managed git hook
+func (hook *ManagedGitHook) Validate() error {
+	if hook.MaxFileSize < 0 {
+		return util.NewInvalidArgumentErrorf("max file size can't be negative")
+	}
+	cleaned := make([]string, 0, len(hook.ForbiddenPaths))
+	for _, pattern := range hook.ForbiddenPaths {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			cleaned = append(cleaned, pattern)
+		}
+	}
+	hook.ForbiddenPaths = cleaned
+	hook.loadedCompiledChecks = false
+	return hook.compileChecks()
+}
+
+// GetManagedGitHook returns the managed git hook of an organization, nil if there is none
+func GetManagedGitHook(ctx context.Context, orgID int64) (*ManagedGitHook, error) {
+	hook := new(ManagedGitHook)
+	has, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Get(hook)
+	if err != nil || !has {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// SaveManagedGitHook creates or updates the managed git hook of an organization
+func SaveManagedGitHook(ctx context.Context, hook *ManagedGitHook) error {
+	if err := hook.Validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetManagedGitHook(ctx, hook.OrgID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			hook.ID = 0
+			return db.Insert(ctx, hook)
+		}
+		hook.ID = existing.ID
+		hook.CreatedUnix = existing.CreatedUnix
+		_, err = db.GetEngine(ctx).ID(hook.ID).AllCols().Update(hook)
+		return err
+	})
+}
+
+// DeleteManagedGitHook deletes the managed git hook of an organization and the opt-outs of its repositories
+func DeleteManagedGitHook(ctx context.Context, orgID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(new(ManagedGitHook)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).
+			Where(builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": orgID}))).
+			Delete(new(ManagedGitHookOptOut))
+		return err
+	})
+}
+
+// GetManagedGitHookOptOut returns the opt-out of a repository, nil if the repository doesn't opt out
+func GetManagedGitHookOptOut(ctx context.Context, repoID int64) (*ManagedGitHookOptOut, error) {
+	optOut := new(ManagedGitHookOptOut)
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(optOut)
+	if err != nil || !has {
+		return nil, err
+	}
+	return optOut, nil
+}
+
+// SetManagedGitHookOptOut makes the repository opt out of the managed git hook of its owner or opt in again
+func SetManagedGitHookOptOut(ctx context.Context, repoID, doerID int64, byAdmin, optOut bool) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(ManagedGitHookOptOut)); err != nil {
+			return err
+		}
+		if !optOut {
+			return nil
+		}
+		return db.Insert(ctx, &ManagedGitHookOptOut{RepoID: repoID, DoerID: doerID, ByAdmin: byAdmin})
+	})
+}
+
+// GetEffectiveManagedGitHook returns the managed git hook which applies to the repository, nil if there is none.
+// The opt-out of a repository administrator only applies while the managed git hook allows it.
+func GetEffectiveManagedGitHook(ctx context.Context, repo *repo_model.Repository) (*ManagedGitHook, error) {
+	hook, err := GetManagedGitHook(ctx, repo.OwnerID)
+	if err != nil || hook == nil {
+		return nil, err
+	}
+	optOut, err := GetManagedGitHookOptOut(ctx, repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if optOut != nil && (optOut.ByAdmin || hook.AllowRepoOptOut) {
+		return nil, nil
+	}
+	return hook, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagedGitHookChecks(t *testing.T) {
+	hook := &git_model.ManagedGitHook{
+		ForbiddenPaths:       []string{" *.exe ", "", "secrets/**"},
+		CommitMessagePattern: `^[A-Z]+-[0-9]+ `,
+	}
+	assert.NoError(t, hook.Validate())
+	assert.Equal(t, []string{"*.exe", "secrets/**"}, hook.ForbiddenPaths)
+
+	assert.True(t, hook.IsForbiddenPath("setup.exe"))
+	assert.False(t, hook.IsForbiddenPath("bin/setup.exe"))
+	assert.True(t, hook.IsForbiddenPath("secrets/prod/key.pem"))
+	assert.False(t, hook.IsForbiddenPath("docs/secrets.md"))
+
+	assert.True(t, hook.IsValidCommitMessage("GITEA-42 fix the build"))
+	assert.False(t, hook.IsValidCommitMessage("fix the build"))
+
+	assert.ErrorIs(t, (&git_model.ManagedGitHook{MaxFileSize: -1}).Validate(), util.ErrInvalidArgument)
+	assert.ErrorIs(t, (&git_model.ManagedGitHook{ForbiddenPaths: []string{"[a-"}}).Validate(), util.ErrInvalidArgument)
+	assert.ErrorIs(t, (&git_model.ManagedGitHook{CommitMessagePattern: "("}).Validate(), util.ErrInvalidArgument)
+}
+
+func TestGetEffectiveManagedGitHook(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+
+	hook, err := git_model.GetEffectiveManagedGitHook(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.Nil(t, hook)
+
+	assert.NoError(t, git_model.SaveManagedGitHook(db.DefaultContext, &git_model.ManagedGitHook{OrgID: repo.OwnerID, MaxFileSize: 1024}))
+	hook, err = git_model.GetEffectiveManagedGitHook(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	if assert.NotNil(t, hook) {
+		assert.EqualValues(t, 1024, hook.MaxFileSize)
+	}
+
+	// the opt-out of a repository administrator doesn't apply while the hook doesn't allow it
+	assert.NoError(t, git_model.SetManagedGitHookOptOut(db.DefaultContext, repo.ID, 2, false, true))
+	hook, err = git_model.GetEffectiveManagedGitHook(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.NotNil(t, hook)
+
+	assert.NoError(t, git_model.SaveManagedGitHook(db.DefaultContext, &git_model.ManagedGitHook{OrgID: repo.OwnerID, MaxFileSize: 1024, AllowRepoOptOut: true}))
+	hook, err = git_model.GetEffectiveManagedGitHook(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.Nil(t, hook)
+
+	// the opt-out of an instance administrator always applies
+	assert.NoError(t, git_model.SaveManagedGitHook(db.DefaultContext, &git_model.ManagedGitHook{OrgID: repo.OwnerID, MaxFileSize: 1024}))
+	assert.NoError(t, git_model.SetManagedGitHookOptOut(db.DefaultContext, repo.ID, 1, true, true))
+	hook, err = git_model.GetEffectiveManagedGitHook(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.Nil(t, hook)
+
+	assert.NoError(t, git_model.DeleteManagedGitHook(db.DefaultContext, repo.OwnerID))
+	unittest.AssertNotExistsBean(t, &git_model.ManagedGitHook{OrgID: repo.OwnerID})
+	unittest.AssertNotExistsBean(t, &git_model.ManagedGitHookOptOut{RepoID: repo.ID})
+}
@@ -53,9 +53,11 @@ type ProtectedBranch struct {
 	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireCodeOwnerApproval      bool     `xorm:"NOT NULL DEFAULT false"`
 	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
 	IgnoreStaleApprovals          bool     `xorm:"NOT NULL DEFAULT false"`
 	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireSignoff                bool     `xorm:"NOT NULL DEFAULT false"`
 	ProtectedFilePatterns         string   `xorm:"TEXT"`
 	UnprotectedFilePatterns       string   `xorm:"TEXT"`
 
@@ -447,6 +449,27 @@ func updateTeamWhitelist(ctx context.Context, repo *repo_model.Repository, curre
 	return whitelist, err
 }
 
+// UpdateProtectBranchStatusChecks saves the required status check contexts of a branch protection,
+// status checks are enabled when there is at least one context.
+func UpdateProtectBranchStatusChecks(ctx context.Context, repo *repo_model.Repository, protectBranch *ProtectedBranch, contexts []string) error {
+	if err := repo.MustNotBeArchived(); err != nil {
+		return err
+	}
+
+	validContexts, err := ValidateStatusCheckContexts(contexts)
+	if err != nil {
+		return err
+	}
+
+	protectBranch.EnableStatusCheck = len(validContexts) > 0
+	protectBranch.StatusCheckContexts = validContexts
+	if !protectBranch.EnableStatusCheck {
+		protectBranch.StatusCheckContexts = nil
+	}
+	_, err = db.GetEngine(ctx).ID(protectBranch.ID).Cols("enable_status_check", "status_check_contexts").Update(protectBranch)
+	return err
+}
+
 // DeleteProtectedBranch removes ProtectedBranch relation between the user and repository.
 func DeleteProtectedBranch(ctx context.Context, repo *repo_model.Repository, id int64) (err error) {
 	err = repo.MustNotBeArchived()
@@ -0,0 +1,96 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+)
+
+// StatusCheckAlternativeSeparator separates the patterns of an any-of group of required status checks
+const StatusCheckAlternativeSeparator = "|"
+
+// RequiredStatusCheck is a required status check context of a protected branch.
+// It's a glob pattern like `ci/build-*` which all the matching statuses of a commit must satisfy,
+// or an any-of group like `ci/build | ci/build-*` which is satisfied when one of its patterns is.
+type RequiredStatusCheck struct {
+	Rule         string
+	Alternatives []string
+	globs        []glob.Glob
+}
+
+// ParseRequiredStatusCheck parses a required status check context
+func ParseRequiredStatusCheck(rule string) (*RequiredStatusCheck, error) {
+	check := &RequiredStatusCheck{Rule: strings.TrimSpace(rule)}
+	for _, pattern := range strings.Split(check.Rule, StatusCheckAlternativeSeparator) {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			return nil, util.NewInvalidArgumentErrorf("status check %q has an empty pattern", rule)
+		}
+		gp, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid status check pattern %q", pattern)
+		}
+		check.Alternatives = append(check.Alternatives, pattern)
+		check.globs = append(check.globs, gp)
+	}
+	return check, nil
+}
+
+// ParseRequiredStatusChecks parses the required status check contexts of a protected branch,
+// the contexts which aren't valid rules are matched literally.
+func ParseRequiredStatusChecks(contexts []string) []*RequiredStatusCheck {
+	checks := make([]*RequiredStatusCheck, 0, len(contexts))
+	for _, context := range contexts {
+		check, err := ParseRequiredStatusCheck(context)
+		if err != nil {
+			// the contexts are validated before they are stored, but the ones stored before glob patterns were introduced may be invalid
+			log.Warn("Invalid required status check %q: %v", context, err)
+			check = &RequiredStatusCheck{
+				Rule:         context,
+				Alternatives: []string{context},
+				globs:        []glob.Glob{glob.MustCompile(glob.QuoteMeta(context))},
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// ValidateStatusCheckContexts trims the required status check contexts, removes the empty and duplicated ones
+// and returns an invalid argument error if any of them isn't a valid rule
+func ValidateStatusCheckContexts(contexts []string) ([]string, error) {
+	validContexts := make([]string, 0, len(contexts))
+	for _, context := range contexts {
+		context = strings.TrimSpace(context)
+		if context == "" || slices.Contains(validContexts, context) {
+			continue
+		}
+		if _, err := ParseRequiredStatusCheck(context); err != nil {
+			return nil, err
+		}
+		validContexts = append(validContexts, context)
+	}
+	return validContexts, nil
+}
+
+// MatchAlternative returns the index of the first pattern of the check matching the status context, or -1
+func (check *RequiredStatusCheck) MatchAlternative(context string) int {
+	for i, gp := range check.globs {
+		if gp.Match(context) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Match returns whether any pattern of the check matches the status context
+func (check *RequiredStatusCheck) Match(context string) bool {
+	return check.MatchAlternative(context) >= 0
+}
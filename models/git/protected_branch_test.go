@@ -76,3 +76,27 @@ func TestBranchRuleMatch(t *testing.T) {
 		)
 	}
 }
+
+func TestParseRequiredStatusCheck(t *testing.T) {
+	check, err := ParseRequiredStatusCheck(" ci/build | ci/build-* ")
+	assert.NoError(t, err)
+	assert.Equal(t, "ci/build | ci/build-*", check.Rule)
+	assert.Equal(t, []string{"ci/build", "ci/build-*"}, check.Alternatives)
+	assert.Equal(t, 0, check.MatchAlternative("ci/build"))
+	assert.Equal(t, 1, check.MatchAlternative("ci/build-linux"))
+	assert.Equal(t, -1, check.MatchAlternative("ci/test"))
+
+	_, err = ParseRequiredStatusCheck("ci/build |")
+	assert.Error(t, err)
+	_, err = ParseRequiredStatusCheck("ci/build-[")
+	assert.Error(t, err)
+
+	contexts, err := ValidateStatusCheckContexts([]string{" ci/test ", "", "ci/build-*|ci/build", "ci/test"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ci/test", "ci/build-*|ci/build"}, contexts)
+
+	// invalid contexts stored before glob patterns were introduced are matched literally
+	checks := ParseRequiredStatusChecks([]string{"ci/build-["})
+	assert.Len(t, checks, 1)
+	assert.True(t, checks[0].Match("ci/build-["))
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// RefUpdateLog is an entry of the server-side journal of the updates of the branches of a repository,
+// it allows to restore a branch to a previous position, e.g. after a bad force-push
+type RefUpdateLog struct {
+	ID      int64  `xorm:"pk autoincr"`
+	RepoID  int64  `xorm:"INDEX(repo_ref) NOT NULL"`
+	RefName string `xorm:"INDEX(repo_ref) VARCHAR(255) NOT NULL"`
+	// OldCommitID is empty for a created branch and NewCommitID is empty for a deleted branch
+	OldCommitID string             `xorm:"VARCHAR(64)"`
+	NewCommitID string             `xorm:"VARCHAR(64)"`
+	PusherID    int64              `xorm:"NOT NULL"`
+	Pusher      *user_model.User   `xorm:"-"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+}
+
+func init() {
+	db.RegisterModel(new(RefUpdateLog))
+}
+
+// AddRefUpdateLogs adds entries to the journal of the updates of the branches
+func AddRefUpdateLogs(ctx context.Context, logs []*RefUpdateLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return db.Insert(ctx, logs)
+}
+
+type RefUpdateLogList []*RefUpdateLog
+
+func (logs RefUpdateLogList) LoadPusher(ctx context.Context) error {
+	ids := container.FilterSlice(logs, func(log *RefUpdateLog) (int64, bool) {
+		return log.PusherID, log.PusherID > 0
+	})
+
+	usersMap := make(map[int64]*user_model.User, len(ids))
+	if err := db.GetEngine(ctx).In("id", ids).Find(&usersMap); err != nil {
+		return err
+	}
+	for _, log := range logs {
+		log.Pusher = usersMap[log.PusherID]
+		if log.Pusher == nil {
+			log.Pusher = user_model.NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// FindRefUpdateLogOptions represents the options to find the journal entries of a branch
+type FindRefUpdateLogOptions struct {
+	db.ListOptions
+	RepoID  int64
+	RefName git.RefName
+}
+
+func (opts FindRefUpdateLogOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.RefName != "" {
+		cond = cond.And(builder.Eq{"ref_name": opts.RefName.String()})
+	}
+	return cond
+}
+
+func (opts FindRefUpdateLogOptions) ToOrders() string {
+	return "created_unix DESC, id DESC"
+}
+
+// IsRefUpdateLogCommit returns whether the commit was a position of the ref according to its journal
+func IsRefUpdateLogCommit(ctx context.Context, repoID int64, refName git.RefName, commitID string) (bool, error) {
+	if commitID == "" {
+		return false, util.NewInvalidArgumentErrorf("empty commit id")
+	}
+	return db.GetEngine(ctx).Where(builder.Eq{"repo_id": repoID, "ref_name": refName.String()}.
+		And(builder.Or(builder.Eq{"old_commit_id": commitID}, builder.Eq{"new_commit_id": commitID}))).
+		Exist(new(RefUpdateLog))
+}
@@ -114,6 +114,11 @@ const (
 
 	CommentTypePin   // 36 pin Issue
 	CommentTypeUnpin // 37 unpin Issue
+
+	CommentTypePRAddedToMergeQueue     // 38 pr was added to the merge queue of its base branch
+	CommentTypePRRemovedFromMergeQueue // 39 pr was removed from the merge queue, the content is the reason if it wasn't removed by a user
+
+	CommentTypeDependencyClosed // 40 Dependency closed, the issue may be unblocked
 )
 
 var commentStrings = []string{
@@ -155,6 +160,9 @@ var commentStrings = []string{
 	"pull_cancel_scheduled_merge",
 	"pin",
 	"unpin",
+	"added_to_merge_queue",
+	"removed_from_merge_queue",
+	"dependency_closed",
 }
 
 func (t CommentType) String() string {
@@ -266,6 +274,8 @@ type Comment struct {
 	Content         string        `xorm:"LONGTEXT"`
 	ContentVersion  int           `xorm:"NOT NULL DEFAULT 0"`
 	RenderedContent template.HTML `xorm:"-"`
+	// IsInternal comments are only visible to the users who can write the issues or pull requests of the repository
+	IsInternal bool `xorm:"NOT NULL DEFAULT false"`
 
 	// Path represents the 4 lines of code cemented by this comment
 	Patch       string `xorm:"-"`
@@ -279,6 +289,8 @@ type Comment struct {
 
 	Attachments []*repo_model.Attachment `xorm:"-"`
 	Reactions   ReactionList             `xorm:"-"`
+	// ResolutionReactions are the reactions on the resolution of the conversation started by a code comment
+	ResolutionReactions ReactionList `xorm:"-"`
 
 	// For view issue page.
 	ShowRole RoleDescriptor `xorm:"-"`
@@ -695,6 +707,21 @@ func (c *Comment) LoadReactions(ctx context.Context, repo *repo_model.Repository
 	return nil
 }
 
+// LoadResolutionReactions loads the reactions on the resolution of a resolved conversation
+func (c *Comment) LoadResolutionReactions(ctx context.Context, repo *repo_model.Repository) (err error) {
+	if c.ResolutionReactions != nil || !c.IsResolved() {
+		return nil
+	}
+	c.ResolutionReactions, _, err = FindResolutionReactions(ctx, c.IssueID, c.ID, db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if _, err := c.ResolutionReactions.LoadUsers(ctx, repo); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *Comment) loadReview(ctx context.Context) (err error) {
 	if c.ReviewID == 0 {
 		return nil
@@ -830,6 +857,7 @@ func CreateComment(ctx context.Context, opts *CreateCommentOptions) (_ *Comment,
 		RefIsPull:        opts.RefIsPull,
 		IsForcePush:      opts.IsForcePush,
 		Invalidated:      opts.Invalidated,
+		IsInternal:       opts.IsInternal,
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -974,6 +1002,30 @@ func createIssueDependencyComment(ctx context.Context, doer *user_model.User, is
 	return err
 }
 
+// createDependencyClosedComments adds a comment to the open issues blocked by the closed issue,
+// in whichever repository they are
+func createDependencyClosedComments(ctx context.Context, doer *user_model.User, issue *Issue) error {
+	blockedIssues, err := GetOpenIssuesBlockedBy(ctx, issue.ID)
+	if err != nil {
+		return err
+	}
+	for _, blocked := range blockedIssues {
+		if !blocked.Repo.IsDependenciesEnabled(ctx) {
+			continue
+		}
+		if _, err := CreateComment(ctx, &CreateCommentOptions{
+			Type:             CommentTypeDependencyClosed,
+			Doer:             doer,
+			Repo:             blocked.Repo,
+			Issue:            blocked,
+			DependentIssueID: issue.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateCommentOptions defines options for creating comment
 type CreateCommentOptions struct {
 	Type  CommentType
@@ -1010,6 +1062,7 @@ type CreateCommentOptions struct {
 	RefIsPull        bool
 	IsForcePush      bool
 	Invalidated      bool
+	IsInternal       bool
 }
 
 // GetCommentByID returns the comment by given ID.
@@ -1038,6 +1091,7 @@ type FindCommentsOptions struct {
 	IssueIDs    []int64
 	Invalidated optional.Option[bool]
 	IsPull      optional.Option[bool]
+	IsInternal  optional.Option[bool]
 }
 
 // ToConds implements FindOptions interface
@@ -1075,6 +1129,9 @@ func (opts FindCommentsOptions) ToConds() builder.Cond {
 	if opts.IsPull.Has() {
 		cond = cond.And(builder.Eq{"issue.is_pull": opts.IsPull.Value()})
 	}
+	if opts.IsInternal.Has() {
+		cond = cond.And(builder.Eq{"comment.is_internal": opts.IsInternal.Value()})
+	}
 	return cond
 }
 
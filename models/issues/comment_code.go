@@ -111,6 +111,10 @@ func findCodeComments(ctx context.Context, opts FindCommentsOptions, issue *Issu
 			return nil, err
 		}
 
+		if err := comment.LoadResolutionReactions(ctx, issue.Repo); err != nil {
+			return nil, err
+		}
+
 		var err error
 		if comment.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
 			Ctx:  ctx,
@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"strings"
+)
+
+// SuggestionLanguage is the language of the fenced code blocks suggesting a change in a code comment
+const SuggestionLanguage = "suggestion"
+
+// CodeSuggestion represents the change suggested by a code comment, it replaces the commented line
+type CodeSuggestion struct {
+	// OldLine is the commented line, it is empty if it isn't known
+	OldLine string
+	// NewLines replace the commented line, the line is deleted if there are none
+	NewLines []string
+}
+
+// ParseSuggestion returns the content of the first ```suggestion block of a comment
+func ParseSuggestion(content string) (string, bool) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		fence, info, ok := parseCodeFence(lines[i])
+		if !ok {
+			continue
+		}
+		// find the closing fence of the block
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if closing, closingInfo, ok := parseCodeFence(lines[j]); ok && closingInfo == "" && strings.HasPrefix(closing, fence) {
+				end = j
+				break
+			}
+		}
+		if info == SuggestionLanguage {
+			// an unclosed block lasts until the end of the comment
+			return strings.Join(lines[i+1:end], "\n"), true
+		}
+		i = end
+	}
+	return "", false
+}
+
+// parseCodeFence parses the opening or closing fence of a fenced code block, the info string is lowercased
+func parseCodeFence(line string) (fence, info string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return "", "", false
+	}
+	char := trimmed[0]
+	if char != '`' && char != '~' {
+		return "", "", false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == char {
+		n++
+	}
+	if n < 3 {
+		return "", "", false
+	}
+	info = strings.TrimSpace(trimmed[n:])
+	if char == '`' && strings.Contains(info, "`") {
+		return "", "", false
+	}
+	if fields := strings.Fields(info); len(fields) > 0 {
+		info = strings.ToLower(fields[0])
+	}
+	return trimmed[:n], info, true
+}
+
+// Suggestion returns the change suggested by the code comment, nil is returned if it doesn't suggest one.
+// Only the lines of the proposed changes can be changed.
+func (c *Comment) Suggestion() *CodeSuggestion {
+	if c.Type != CommentTypeCode || c.Line <= 0 {
+		return nil
+	}
+	suggested, ok := ParseSuggestion(c.Content)
+	if !ok {
+		return nil
+	}
+
+	suggestion := &CodeSuggestion{}
+	if suggested != "" {
+		suggestion.NewLines = strings.Split(suggested, "\n")
+	}
+	// the commented line is the last line of the diff hunk of the comment
+	patchLines := strings.Split(strings.TrimRight(c.Patch, "\n"), "\n")
+	for i := len(patchLines) - 1; i >= 0; i-- {
+		line := patchLines[i]
+		if strings.HasPrefix(line, "\\") {
+			continue
+		}
+		if strings.HasPrefix(line, "+") || strings.HasPrefix(line, " ") {
+			suggestion.OldLine = line[1:]
+		}
+		break
+	}
+	return suggestion
+}
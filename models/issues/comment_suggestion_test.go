@@ -0,0 +1,54 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSuggestion(t *testing.T) {
+	cases := []struct {
+		content   string
+		suggested string
+		ok        bool
+	}{
+		{"no suggestion", "", false},
+		{"```go\nfoo()\n```", "", false},
+		{"Use this:\n```suggestion\nfoo()\n```\nThanks", "foo()", true},
+		{"```suggestion\nfoo()\nbar()\n```", "foo()\nbar()", true},
+		{"```Suggestion\r\nfoo()\r\n```", "foo()", true},
+		{"~~~suggestion\n```\n~~~", "```", true},
+		{"````suggestion\n```\nfoo()\n````", "```\nfoo()", true},
+		{"```suggestion\n```", "", true},
+		{"```suggestion\nfoo()", "foo()", true},
+		{"    ```suggestion\nfoo()\n```", "", false},
+		{"```go\n```suggestion\n```", "", false},
+	}
+	for _, c := range cases {
+		suggested, ok := issues_model.ParseSuggestion(c.content)
+		assert.Equal(t, c.ok, ok, c.content)
+		assert.Equal(t, c.suggested, suggested, c.content)
+	}
+}
+
+func TestCommentSuggestion(t *testing.T) {
+	patch := "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1,2 +1,2 @@\n # repo1\n-old\n+new line\n\\ No newline at end of file\n"
+
+	comment := &issues_model.Comment{Type: issues_model.CommentTypeCode, Line: 2, Patch: patch, Content: "```suggestion\nfirst\nsecond\n```"}
+	assert.Equal(t, &issues_model.CodeSuggestion{OldLine: "new line", NewLines: []string{"first", "second"}}, comment.Suggestion())
+
+	comment.Content = "```suggestion\n```"
+	assert.Equal(t, &issues_model.CodeSuggestion{OldLine: "new line"}, comment.Suggestion())
+
+	comment.Line = -2
+	assert.Nil(t, comment.Suggestion())
+
+	comment.Line = 2
+	comment.Content = "no suggestion"
+	assert.Nil(t, comment.Suggestion())
+}
@@ -208,6 +208,23 @@ func issueDepExists(ctx context.Context, issueID, depID int64) (bool, error) {
 	return db.GetEngine(ctx).Where("(issue_id = ? AND dependency_id = ?)", issueID, depID).Exist(&IssueDependency{})
 }
 
+// GetOpenIssuesBlockedBy returns the open issues blocked by the issue with their repositories loaded,
+// the blocked issues aren't necessarily in the same repository as the issue
+func GetOpenIssuesBlockedBy(ctx context.Context, issueID int64) (IssueList, error) {
+	issues := make(IssueList, 0, 5)
+	if err := db.GetEngine(ctx).
+		Join("INNER", "issue_dependency", "issue_dependency.issue_id = issue.id").
+		Where("issue_dependency.dependency_id = ?", issueID).
+		And("issue.is_closed = ?", false).
+		Find(&issues); err != nil {
+		return nil, err
+	}
+	if _, err := issues.LoadRepositories(ctx); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
 // IssueNoDependenciesLeft checks if issue can be closed
 func IssueNoDependenciesLeft(ctx context.Context, issue *Issue) (bool, error) {
 	exists, err := db.GetEngine(ctx).
@@ -48,10 +48,21 @@ func TestCreateIssueDependency(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, left)
 
+	blocked, err := issues_model.GetOpenIssuesBlockedBy(db.DefaultContext, issue2.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, blocked, 1) {
+		assert.EqualValues(t, issue1.ID, blocked[0].ID)
+		assert.NotNil(t, blocked[0].Repo)
+	}
+
 	// Close #2 and check again
 	_, err = issues_model.ChangeIssueStatus(db.DefaultContext, issue2, user1, true)
 	assert.NoError(t, err)
 
+	blocked, err = issues_model.GetOpenIssuesBlockedBy(db.DefaultContext, issue2.ID)
+	assert.NoError(t, err)
+	assert.Len(t, blocked, 1)
+
 	left, err = issues_model.IssueNoDependenciesLeft(db.DefaultContext, issue1)
 	assert.NoError(t, err)
 	assert.True(t, left)
@@ -17,6 +17,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -117,14 +118,19 @@ type Issue struct {
 	isMilestoneLoaded bool                   `xorm:"-"`
 	Project           *project_model.Project `xorm:"-"`
 	Priority          int
-	AssigneeID        int64            `xorm:"-"`
-	Assignee          *user_model.User `xorm:"-"`
-	isAssigneeLoaded  bool             `xorm:"-"`
-	IsClosed          bool             `xorm:"INDEX"`
-	IsRead            bool             `xorm:"-"`
-	IsPull            bool             `xorm:"INDEX"` // Indicates whether is a pull request or not.
-	PullRequest       *PullRequest     `xorm:"-"`
+	TypeID            int64               `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Type              *IssueType          `xorm:"-"`
+	FieldValues       []*IssueFieldValues `xorm:"-"`
+	SubIssueProgress  *SubIssueProgress   `xorm:"-"`
+	AssigneeID        int64               `xorm:"-"`
+	Assignee          *user_model.User    `xorm:"-"`
+	isAssigneeLoaded  bool                `xorm:"-"`
+	IsClosed          bool                `xorm:"INDEX"`
+	IsRead            bool                `xorm:"-"`
+	IsPull            bool                `xorm:"INDEX"` // Indicates whether is a pull request or not.
+	PullRequest       *PullRequest        `xorm:"-"`
 	NumComments       int
+	NumVotes          int `xorm:"NOT NULL DEFAULT 0"`
 	Ref               string
 	PinOrder          int `xorm:"DEFAULT 0"`
 
@@ -257,6 +263,18 @@ func (issue *Issue) LoadDiscussComments(ctx context.Context) error {
 	return issue.loadCommentsByType(ctx, CommentTypeComment)
 }
 
+// LoadPublicComments loads the comments without the internal comments, for the users who can't see them
+func (issue *Issue) LoadPublicComments(ctx context.Context) (err error) {
+	if issue.Comments != nil {
+		return nil
+	}
+	issue.Comments, err = FindComments(ctx, &FindCommentsOptions{
+		IssueID:    issue.ID,
+		IsInternal: optional.Some(false),
+	})
+	return err
+}
+
 func (issue *Issue) loadCommentsByType(ctx context.Context, tp CommentType) (err error) {
 	if issue.Comments != nil {
 		return nil
@@ -305,9 +323,9 @@ func (issue *Issue) loadReactions(ctx context.Context) (err error) {
 // LoadMilestone load milestone of this issue.
 func (issue *Issue) LoadMilestone(ctx context.Context) (err error) {
 	if !issue.isMilestoneLoaded && (issue.Milestone == nil || issue.Milestone.ID != issue.MilestoneID) && issue.MilestoneID > 0 {
-		issue.Milestone, err = GetMilestoneByRepoID(ctx, issue.RepoID, issue.MilestoneID)
+		issue.Milestone, err = GetMilestoneForRepo(ctx, issue.RepoID, issue.MilestoneID)
 		if err != nil && !IsErrMilestoneNotExist(err) {
-			return fmt.Errorf("getMilestoneByRepoID [repo_id: %d, milestone_id: %d]: %w", issue.RepoID, issue.MilestoneID, err)
+			return fmt.Errorf("getMilestoneForRepo [repo_id: %d, milestone_id: %d]: %w", issue.RepoID, issue.MilestoneID, err)
 		}
 		issue.isMilestoneLoaded = true
 	}
@@ -336,6 +354,18 @@ func (issue *Issue) LoadAttributes(ctx context.Context) (err error) {
 		return err
 	}
 
+	if err = issue.LoadType(ctx); err != nil {
+		return err
+	}
+
+	if err = issue.LoadFieldValues(ctx); err != nil {
+		return err
+	}
+
+	if err = issue.LoadSubIssueProgress(ctx); err != nil {
+		return err
+	}
+
 	if err = issue.LoadAssignees(ctx); err != nil {
 		return err
 	}
@@ -0,0 +1,267 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// IssueAutomationTargets are the kinds of issues which a rule applies to
+var IssueAutomationTargets = []string{"issues", "pulls", "all"}
+
+// IssueAutomation actions of the rules
+const (
+	IssueAutomationActionLabel   = "label"
+	IssueAutomationActionComment = "comment"
+	IssueAutomationActionClose   = "close"
+)
+
+// IssueAutomationActions are the actions which a rule can take on the issues it matches
+var IssueAutomationActions = []string{IssueAutomationActionLabel, IssueAutomationActionComment, IssueAutomationActionClose}
+
+// IssueAutomationRule is a rule of a repository periodically applied to its open issues and pull requests
+// without activity for some days, or with a label added some days ago
+type IssueAutomationRule struct {
+	ID     int64  `xorm:"pk autoincr"`
+	RepoID int64  `xorm:"INDEX NOT NULL"`
+	Name   string `xorm:"VARCHAR(50) NOT NULL"`
+	Target string `xorm:"VARCHAR(10) NOT NULL"`
+	// LabelID selects the issues with the label, the days are then counted since it was added
+	LabelID int64
+	// Days is the number of days without activity, or since the label was added
+	Days int `xorm:"NOT NULL"`
+	// ExemptLabelIDs are the labels of the issues which the rule never applies to
+	ExemptLabelIDs []int64 `xorm:"TEXT JSON"`
+	Action         string  `xorm:"VARCHAR(10) NOT NULL"`
+	// ActionLabelID is the label added by the label action
+	ActionLabelID int64
+	// Comment is posted by the comment action, and with the other actions if it isn't empty
+	Comment string `xorm:"TEXT"`
+	// IsDryRun rules only record the actions they would take
+	IsDryRun    bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IssueAutomationLog records an action of a rule on an issue, the audit trail of the automation of a repository
+type IssueAutomationLog struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"INDEX NOT NULL"`
+	RuleID  int64 `xorm:"INDEX NOT NULL"`
+	IssueID int64 `xorm:"INDEX NOT NULL"`
+	// RuleName is kept for the logs of the deleted rules
+	RuleName    string             `xorm:"VARCHAR(50)"`
+	Action      string             `xorm:"VARCHAR(10)"`
+	IsDryRun    bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+
+	Issue *Issue `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueAutomationRule))
+	db.RegisterModel(new(IssueAutomationLog))
+}
+
+// Validate checks if the rule is consistent and normalizes it, the labels must be available to the issues of its repository
+func (r *IssueAutomationRule) Validate(ctx context.Context) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || len(r.Name) > 50 {
+		return util.NewInvalidArgumentErrorf("invalid rule name %q", r.Name)
+	}
+	if r.Target == "" {
+		r.Target = "issues"
+	}
+	if !slices.Contains(IssueAutomationTargets, r.Target) {
+		return util.NewInvalidArgumentErrorf("invalid target %q", r.Target)
+	}
+	if r.Days < 1 || r.Days > 3650 {
+		return util.NewInvalidArgumentErrorf("invalid number of days %d", r.Days)
+	}
+	if !slices.Contains(IssueAutomationActions, r.Action) {
+		return util.NewInvalidArgumentErrorf("invalid action %q", r.Action)
+	}
+	r.Comment = strings.TrimSpace(r.Comment)
+	switch r.Action {
+	case IssueAutomationActionLabel:
+		if r.ActionLabelID <= 0 {
+			return util.NewInvalidArgumentErrorf("the label action needs a label")
+		}
+	case IssueAutomationActionComment:
+		if r.Comment == "" {
+			return util.NewInvalidArgumentErrorf("the comment action needs a comment")
+		}
+	}
+	if r.Action != IssueAutomationActionLabel {
+		r.ActionLabelID = 0
+	}
+
+	ids := make([]int64, 0, len(r.ExemptLabelIDs)+2)
+	for _, id := range append([]int64{r.LabelID, r.ActionLabelID}, r.ExemptLabelIDs...) {
+		if id < 0 {
+			return util.NewInvalidArgumentErrorf("invalid label %d", id)
+		}
+		if id > 0 && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, r.RepoID)
+	if err != nil {
+		return err
+	}
+	count, err := db.GetEngine(ctx).In("id", ids).
+		And(builder.Eq{"repo_id": r.RepoID}.Or(builder.Eq{"org_id": repo.OwnerID})).
+		Count(new(Label))
+	if err != nil {
+		return err
+	} else if count != int64(len(ids)) {
+		return util.NewInvalidArgumentErrorf("labels %v don't all exist", ids)
+	}
+	return nil
+}
+
+// NewIssueAutomationRule saves a rule of a repository
+func NewIssueAutomationRule(ctx context.Context, r *IssueAutomationRule) error {
+	if err := r.Validate(ctx); err != nil {
+		return err
+	}
+	return db.Insert(ctx, r)
+}
+
+// UpdateIssueAutomationRule updates a rule
+func UpdateIssueAutomationRule(ctx context.Context, r *IssueAutomationRule) error {
+	if err := r.Validate(ctx); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).ID(r.ID).
+		Cols("name", "target", "label_id", "days", "exempt_label_ids", "action", "action_label_id", "comment", "is_dry_run").
+		Update(r)
+	return err
+}
+
+// DeleteIssueAutomationRule deletes a rule, its logs are kept
+func DeleteIssueAutomationRule(ctx context.Context, r *IssueAutomationRule) error {
+	_, err := db.DeleteByID[IssueAutomationRule](ctx, r.ID)
+	return err
+}
+
+// GetIssueAutomationRules returns the rules of a repository
+func GetIssueAutomationRules(ctx context.Context, repoID int64) ([]*IssueAutomationRule, error) {
+	rules := make([]*IssueAutomationRule, 0, 5)
+	return rules, db.GetEngine(ctx).Where("repo_id = ?", repoID).Asc("id").Find(&rules)
+}
+
+// GetIssueAutomationRuleByID returns a rule of a repository
+func GetIssueAutomationRuleByID(ctx context.Context, repoID, id int64) (*IssueAutomationRule, error) {
+	r := new(IssueAutomationRule)
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND id = ?", repoID, id).Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("rule %d does not exist", id)
+	}
+	return r, nil
+}
+
+// FindMatchingIssues returns the open issues of the repository which the rule applies to at the given time, the least
+// recently updated first. An issue isn't matched again by a rule which took an action on it since it was last updated,
+// or since the label of the rule was added.
+func (r *IssueAutomationRule) FindMatchingIssues(ctx context.Context, now timeutil.TimeStamp, limit int) (IssueList, error) {
+	cond := builder.Eq{"issue.repo_id": r.RepoID, "issue.is_closed": false}
+	switch r.Target {
+	case "issues":
+		cond["issue.is_pull"] = false
+	case "pulls":
+		cond["issue.is_pull"] = true
+	}
+	threshold := now.Add(-int64(r.Days) * timeutil.Day)
+
+	// since is the time the days are counted from
+	since := "issue.updated_unix"
+	var conds builder.Cond = cond
+	if r.LabelID > 0 {
+		conds = conds.And(builder.In("issue.id", builder.Select("issue_id").From("issue_label").Where(builder.Eq{"label_id": r.LabelID})))
+		since = "COALESCE((SELECT MAX(comment.created_unix) FROM comment WHERE comment.issue_id = issue.id AND comment.type = ? AND comment.label_id = ? AND comment.content = '1'), issue.created_unix)"
+	}
+	sinceArgs := func() []any {
+		if r.LabelID > 0 {
+			return []any{CommentTypeLabel, r.LabelID}
+		}
+		return nil
+	}
+	conds = conds.And(builder.Expr(since+" < ?", append(sinceArgs(), threshold)...))
+	conds = conds.And(builder.Expr("NOT EXISTS (SELECT 1 FROM issue_automation_log WHERE issue_automation_log.rule_id = ? AND issue_automation_log.issue_id = issue.id AND issue_automation_log.created_unix >= "+since+")",
+		append([]any{r.ID}, sinceArgs()...)...))
+
+	exempt := slices.Clone(r.ExemptLabelIDs)
+	if r.ActionLabelID > 0 {
+		// the issues which already have the label of the action are done
+		exempt = append(exempt, r.ActionLabelID)
+	}
+	if len(exempt) > 0 {
+		conds = conds.And(builder.NotIn("issue.id", builder.Select("issue_id").From("issue_label").Where(builder.In("label_id", exempt))))
+	}
+
+	issues := make(IssueList, 0, 10)
+	return issues, db.GetEngine(ctx).Where(conds).Asc("issue.updated_unix").Asc("issue.id").Limit(limit).Find(&issues)
+}
+
+// IssueAutomationLogOptions are the options to find the logs of the automation of a repository
+type IssueAutomationLogOptions struct {
+	db.ListOptions
+	RepoID int64
+	RuleID int64
+}
+
+func (opts IssueAutomationLogOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.RuleID > 0 {
+		cond = cond.And(builder.Eq{"rule_id": opts.RuleID})
+	}
+	return cond
+}
+
+func (opts IssueAutomationLogOptions) ToOrders() string {
+	return "id DESC"
+}
+
+// IssueAutomationLogList is a list of logs of the automation of a repository
+type IssueAutomationLogList []*IssueAutomationLog
+
+// LoadIssues loads the issues of the logs
+func (logs IssueAutomationLogList) LoadIssues(ctx context.Context) error {
+	ids := make([]int64, 0, len(logs))
+	for _, l := range logs {
+		if !slices.Contains(ids, l.IssueID) {
+			ids = append(ids, l.IssueID)
+		}
+	}
+	issues, err := GetIssuesByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]*Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	for _, l := range logs {
+		l.Issue = byID[l.IssueID]
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIssueAutomationRule(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	test := func(r *issues_model.IssueAutomationRule, expectedErr error) {
+		t.Helper()
+		err := issues_model.NewIssueAutomationRule(db.DefaultContext, r)
+		if expectedErr == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, expectedErr)
+		}
+	}
+
+	stale := &issues_model.IssueAutomationRule{RepoID: 1, Name: " Stale ", Days: 30, Action: "close", ActionLabelID: 2, ExemptLabelIDs: []int64{1}}
+	test(stale, nil)
+	assert.Equal(t, "Stale", stale.Name)
+	assert.Equal(t, "issues", stale.Target)
+	assert.EqualValues(t, 0, stale.ActionLabelID)
+
+	test(&issues_model.IssueAutomationRule{RepoID: 1, Name: "", Days: 30, Action: "close"}, util.ErrInvalidArgument)
+	test(&issues_model.IssueAutomationRule{RepoID: 1, Name: "No days", Action: "close"}, util.ErrInvalidArgument)
+	test(&issues_model.IssueAutomationRule{RepoID: 1, Name: "Bad target", Target: "commits", Days: 30, Action: "close"}, util.ErrInvalidArgument)
+	test(&issues_model.IssueAutomationRule{RepoID: 1, Name: "Bad action", Days: 30, Action: "lock"}, util.ErrInvalidArgument)
+	test(&issues_model.IssueAutomationRule{RepoID: 1, Name: "No label", Days: 30, Action: "label"}, util.ErrInvalidArgument)
+	test(&issues_model.IssueAutomationRule{RepoID: 1, Name: "No comment", Days: 30, Action: "comment"}, util.ErrInvalidArgument)
+	// the label 3 belongs to another owner
+	test(&issues_model.IssueAutomationRule{RepoID: 1, Name: "Other label", Days: 30, Action: "label", ActionLabelID: 3}, util.ErrInvalidArgument)
+
+	rules, err := issues_model.GetIssueAutomationRules(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+}
+
+func TestIssueAutomationRule_FindMatchingIssues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	test := func(r *issues_model.IssueAutomationRule, now timeutil.TimeStamp, expected ...int64) {
+		t.Helper()
+		issues, err := r.FindMatchingIssues(db.DefaultContext, now, 10)
+		assert.NoError(t, err)
+		var ids []int64
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+		assert.Equal(t, expected, ids)
+	}
+
+	// the open issues of the repository 1 were last updated at about 978307200, except the pull request 11
+	now := timeutil.TimeStamp(978307200).Add(31 * timeutil.Day)
+	rule := &issues_model.IssueAutomationRule{ID: 1, RepoID: 1, Target: "all", Days: 30}
+	test(rule, now, 3, 2, 1)
+	test(rule, now.Add(-2*timeutil.Day))
+
+	rule.Target = "issues"
+	test(rule, now, 1)
+	rule.Target = "pulls"
+	test(rule, now, 3, 2)
+
+	rule.Target = "all"
+	rule.ExemptLabelIDs = []int64{4}
+	test(rule, now, 3, 1)
+	rule.ExemptLabelIDs = nil
+	rule.ActionLabelID = 1
+	test(rule, now, 3)
+
+	// the label 1 was added to the issue 1 at 946684810, and to the pull request 2 when it was created
+	labelRule := &issues_model.IssueAutomationRule{ID: 2, RepoID: 1, Target: "all", Days: 30, LabelID: 1}
+	test(labelRule, timeutil.TimeStamp(946684810).Add(31*timeutil.Day), 2, 1)
+	test(labelRule, timeutil.TimeStamp(946684810).Add(29*timeutil.Day))
+
+	// an issue isn't matched again by the rule which took an action on it
+	assert.NoError(t, db.Insert(db.DefaultContext, &issues_model.IssueAutomationLog{RepoID: 1, RuleID: 2, IssueID: 1, Action: "comment"}))
+	test(labelRule, timeutil.TimeStamp(946684810).Add(31*timeutil.Day), 2)
+	rule.ActionLabelID = 0
+	test(rule, now, 3, 2, 1)
+}
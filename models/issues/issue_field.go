@@ -0,0 +1,401 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// IssueFieldType is the type of the values of a custom issue field
+type IssueFieldType string
+
+// The types of custom issue fields
+const (
+	IssueFieldTypeText        IssueFieldType = "text"
+	IssueFieldTypeNumber      IssueFieldType = "number"
+	IssueFieldTypeDate        IssueFieldType = "date"
+	IssueFieldTypeSelect      IssueFieldType = "select"
+	IssueFieldTypeMultiSelect IssueFieldType = "multi_select"
+	IssueFieldTypeUser        IssueFieldType = "user"
+)
+
+// IssueFieldTypes are the types of custom issue fields
+var IssueFieldTypes = []IssueFieldType{IssueFieldTypeText, IssueFieldTypeNumber, IssueFieldTypeDate, IssueFieldTypeSelect, IssueFieldTypeMultiSelect, IssueFieldTypeUser}
+
+// IssueFieldValueMaxLength is the max length of a value of a custom issue field
+const IssueFieldValueMaxLength = 255
+
+// IssueField is a custom field of the issues and pull requests. It's defined by a repository for its issues,
+// or by an organization for the issues of all its repositories, like labels.
+type IssueField struct {
+	ID          int64          `xorm:"pk autoincr"`
+	RepoID      int64          `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	OrgID       int64          `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	Name        string         `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+	Description string         `xorm:"TEXT"`
+	Type        IssueFieldType `xorm:"VARCHAR(20) NOT NULL"`
+	// Options are the values which a select or multi select field can have
+	Options     []string           `xorm:"TEXT JSON"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IssueFieldValue is a value of a custom field of an issue, a multi select field has a value for each selected option
+type IssueFieldValue struct {
+	ID      int64  `xorm:"pk autoincr"`
+	IssueID int64  `xorm:"INDEX NOT NULL"`
+	FieldID int64  `xorm:"INDEX NOT NULL"`
+	Value   string `xorm:"VARCHAR(255) NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueField))
+	db.RegisterModel(new(IssueFieldValue))
+}
+
+// BelongsToOrg returns true if the field is defined by an organization
+func (f *IssueField) BelongsToOrg() bool {
+	return f.OrgID > 0
+}
+
+// IsMultiple returns whether an issue can have several values of the field
+func (f *IssueField) IsMultiple() bool {
+	return f.Type == IssueFieldTypeMultiSelect
+}
+
+// Validate checks if the definition of the field is consistent and normalizes it
+func (f *IssueField) Validate() error {
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" || len(f.Name) > 50 {
+		return util.NewInvalidArgumentErrorf("invalid field name %q", f.Name)
+	}
+	if !slices.Contains(IssueFieldTypes, f.Type) {
+		return util.NewInvalidArgumentErrorf("unknown field type %q", f.Type)
+	}
+
+	if f.Type != IssueFieldTypeSelect && f.Type != IssueFieldTypeMultiSelect {
+		f.Options = nil
+		return nil
+	}
+	options := make([]string, 0, len(f.Options))
+	for _, option := range f.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || slices.Contains(options, option) {
+			continue
+		}
+		if len(option) > IssueFieldValueMaxLength {
+			return util.NewInvalidArgumentErrorf("option %q is too long", option)
+		}
+		options = append(options, option)
+	}
+	if len(options) == 0 {
+		return util.NewInvalidArgumentErrorf("a %s field must have options", f.Type)
+	}
+	f.Options = options
+	return nil
+}
+
+// NormalizeValues checks the values of the field for an issue and returns them in their stored form.
+// Numbers are formatted canonically, dates as YYYY-MM-DD and users are referenced by their ids.
+func (f *IssueField) NormalizeValues(ctx context.Context, values []string) ([]string, error) {
+	values = container.FilterSlice(values, func(value string) (string, bool) {
+		value = strings.TrimSpace(value)
+		return value, value != ""
+	})
+	if len(values) > 1 && !f.IsMultiple() {
+		return nil, util.NewInvalidArgumentErrorf("field %q can only have one value", f.Name)
+	}
+
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		switch f.Type {
+		case IssueFieldTypeText:
+			if len(value) > IssueFieldValueMaxLength {
+				return nil, util.NewInvalidArgumentErrorf("the value of field %q is too long", f.Name)
+			}
+		case IssueFieldTypeNumber:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, util.NewInvalidArgumentErrorf("the value of field %q must be a number", f.Name)
+			}
+			value = strconv.FormatFloat(number, 'f', -1, 64)
+		case IssueFieldTypeDate:
+			date, err := time.Parse(time.DateOnly, value)
+			if err != nil {
+				return nil, util.NewInvalidArgumentErrorf("the value of field %q must be a date like 2006-01-02", f.Name)
+			}
+			value = date.Format(time.DateOnly)
+		case IssueFieldTypeSelect, IssueFieldTypeMultiSelect:
+			if !slices.Contains(f.Options, value) {
+				return nil, util.NewInvalidArgumentErrorf("%q is not an option of field %q", value, f.Name)
+			}
+		case IssueFieldTypeUser:
+			var user *user_model.User
+			var err error
+			if id, parseErr := strconv.ParseInt(value, 10, 64); parseErr == nil {
+				user, err = user_model.GetUserByID(ctx, id)
+			} else {
+				user, err = user_model.GetUserByName(ctx, value)
+			}
+			if err != nil {
+				if user_model.IsErrUserNotExist(err) {
+					return nil, util.NewInvalidArgumentErrorf("user %q does not exist", value)
+				}
+				return nil, err
+			}
+			value = strconv.FormatInt(user.ID, 10)
+		}
+		if !slices.Contains(normalized, value) {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized, nil
+}
+
+// NewIssueField creates a custom field of a repository or an organization
+func NewIssueField(ctx context.Context, f *IssueField) error {
+	if (f.RepoID > 0) == (f.OrgID > 0) {
+		return util.NewInvalidArgumentErrorf("a field belongs to either a repository or an organization")
+	}
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkIssueFieldNameAvailable(ctx, f); err != nil {
+			return err
+		}
+		return db.Insert(ctx, f)
+	})
+}
+
+// UpdateIssueField updates the definition of a custom field, the type of a field can't be changed.
+// The values of the issues which are not an option of the field anymore are removed.
+func UpdateIssueField(ctx context.Context, f *IssueField) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkIssueFieldNameAvailable(ctx, f); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).ID(f.ID).Cols("name", "description", "options").Update(f); err != nil {
+			return err
+		}
+		if len(f.Options) == 0 {
+			return nil
+		}
+		_, err := db.GetEngine(ctx).Where(builder.Eq{"field_id": f.ID}.And(builder.NotIn("value", f.Options))).Delete(new(IssueFieldValue))
+		return err
+	})
+}
+
+func checkIssueFieldNameAvailable(ctx context.Context, f *IssueField) error {
+	exist, err := db.GetEngine(ctx).Where("repo_id = ? AND org_id = ? AND id <> ?", f.RepoID, f.OrgID, f.ID).
+		And(builder.Eq{"LOWER(name)": strings.ToLower(f.Name)}).Exist(new(IssueField))
+	if err != nil {
+		return err
+	} else if exist {
+		return util.NewAlreadyExistErrorf("field %q already exists", f.Name)
+	}
+	return nil
+}
+
+// DeleteIssueField deletes a custom field and its values
+func DeleteIssueField(ctx context.Context, f *IssueField) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("field_id = ?", f.ID).Delete(new(IssueFieldValue)); err != nil {
+			return err
+		}
+		_, err := db.DeleteByID[IssueField](ctx, f.ID)
+		return err
+	})
+}
+
+// GetIssueFieldByID returns the custom field with the given id
+func GetIssueFieldByID(ctx context.Context, id int64) (*IssueField, error) {
+	f, has, err := db.GetByID[IssueField](ctx, id)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("field %d does not exist", id)
+	}
+	return f, nil
+}
+
+// GetIssueFieldsByRepoID returns the custom fields defined by a repository
+func GetIssueFieldsByRepoID(ctx context.Context, repoID int64) ([]*IssueField, error) {
+	fields := make([]*IssueField, 0, 5)
+	return fields, db.GetEngine(ctx).Where("repo_id = ?", repoID).Asc("name").Find(&fields)
+}
+
+// GetIssueFieldsByOrgID returns the custom fields defined by an organization
+func GetIssueFieldsByOrgID(ctx context.Context, orgID int64) ([]*IssueField, error) {
+	fields := make([]*IssueField, 0, 5)
+	return fields, db.GetEngine(ctx).Where("org_id = ?", orgID).Asc("name").Find(&fields)
+}
+
+// GetIssueFieldsForRepo returns the custom fields which the issues of a repository can have,
+// the ones of the repository and the ones of its owner if it's an organization
+func GetIssueFieldsForRepo(ctx context.Context, repoID, ownerID int64) ([]*IssueField, error) {
+	fields := make([]*IssueField, 0, 5)
+	return fields, db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": repoID}.Or(builder.Eq{"org_id": ownerID})).
+		Asc("name").Asc("id").
+		Find(&fields)
+}
+
+// GetIssueFieldForRepoByID returns the custom field with the given id if the issues of the repository can have it
+func GetIssueFieldForRepoByID(ctx context.Context, repoID, ownerID, id int64) (*IssueField, error) {
+	f := new(IssueField)
+	has, err := db.GetEngine(ctx).ID(id).Where(builder.Eq{"repo_id": repoID}.Or(builder.Eq{"org_id": ownerID})).Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("field %d does not exist", id)
+	}
+	return f, nil
+}
+
+// GetIssueFieldForRepoByName returns the custom field with the given name if the issues of the repository can have it,
+// the field of the repository is preferred over the field of its owner with the same name
+func GetIssueFieldForRepoByName(ctx context.Context, repoID, ownerID int64, name string) (*IssueField, error) {
+	f := new(IssueField)
+	has, err := db.GetEngine(ctx).Where(builder.Eq{"repo_id": repoID}.Or(builder.Eq{"org_id": ownerID})).
+		And(builder.Eq{"LOWER(name)": strings.ToLower(strings.TrimSpace(name))}).
+		Desc("repo_id").Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("field %q does not exist", name)
+	}
+	return f, nil
+}
+
+// IssueFieldValues are the values of a custom field of an issue
+type IssueFieldValues struct {
+	Field  *IssueField
+	Values []string
+}
+
+// DisplayValues returns the values in a readable form, the users are referenced by their names
+func (v *IssueFieldValues) DisplayValues(ctx context.Context) []string {
+	if v.Field.Type != IssueFieldTypeUser {
+		return v.Values
+	}
+	names := make([]string, 0, len(v.Values))
+	for _, value := range v.Values {
+		id, _ := strconv.ParseInt(value, 10, 64)
+		user, err := user_model.GetPossibleUserByID(ctx, id)
+		if err != nil {
+			user = user_model.NewGhostUser()
+		}
+		names = append(names, user.Name)
+	}
+	return names
+}
+
+// LoadFieldValues loads the values of the custom fields of the issue
+func (issue *Issue) LoadFieldValues(ctx context.Context) error {
+	if issue.FieldValues != nil {
+		return nil
+	}
+	return IssueList{issue}.LoadFieldValues(ctx)
+}
+
+// LoadFieldValues loads the values of the custom fields of the issues
+func (issues IssueList) LoadFieldValues(ctx context.Context) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	values := make([]*IssueFieldValue, 0, len(issues))
+	if err := db.GetEngine(ctx).In("issue_id", issues.getIssueIDs()).Asc("id").Find(&values); err != nil {
+		return err
+	}
+
+	fieldIDs := container.FilterSlice(values, func(v *IssueFieldValue) (int64, bool) {
+		return v.FieldID, true
+	})
+	fields := make(map[int64]*IssueField, len(fieldIDs))
+	if len(fieldIDs) > 0 {
+		if err := db.GetEngine(ctx).In("id", fieldIDs).Find(&fields); err != nil {
+			return err
+		}
+	}
+
+	valuesByIssue := make(map[int64][]*IssueFieldValues, len(issues))
+	for _, v := range values {
+		field, ok := fields[v.FieldID]
+		if !ok {
+			continue
+		}
+		issueValues := valuesByIssue[v.IssueID]
+		idx := slices.IndexFunc(issueValues, func(fv *IssueFieldValues) bool { return fv.Field.ID == field.ID })
+		if idx < 0 {
+			issueValues = append(issueValues, &IssueFieldValues{Field: field})
+			idx = len(issueValues) - 1
+		}
+		issueValues[idx].Values = append(issueValues[idx].Values, v.Value)
+		valuesByIssue[v.IssueID] = issueValues
+	}
+
+	for _, issue := range issues {
+		issue.FieldValues = valuesByIssue[issue.ID]
+		if issue.FieldValues == nil {
+			issue.FieldValues = []*IssueFieldValues{}
+		}
+		slices.SortFunc(issue.FieldValues, func(a, b *IssueFieldValues) int {
+			return strings.Compare(a.Field.Name, b.Field.Name)
+		})
+	}
+	return nil
+}
+
+// SetIssueFieldValues replaces the values of a custom field of an issue by normalized values,
+// the issue doesn't have a value of the field anymore if there are no values
+func SetIssueFieldValues(ctx context.Context, issue *Issue, f *IssueField, values []string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("issue_id = ? AND field_id = ?", issue.ID, f.ID).Delete(new(IssueFieldValue)); err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			rows := make([]*IssueFieldValue, 0, len(values))
+			for _, value := range values {
+				rows = append(rows, &IssueFieldValue{IssueID: issue.ID, FieldID: f.ID, Value: value})
+			}
+			if err := db.Insert(ctx, rows); err != nil {
+				return err
+			}
+		}
+		issue.FieldValues = nil
+		return nil
+	})
+}
+
+// GetIssueFieldValuesByIssueID returns the values of the custom fields of an issue
+func GetIssueFieldValuesByIssueID(ctx context.Context, issueID int64) ([]*IssueFieldValue, error) {
+	values := make([]*IssueFieldValue, 0, 5)
+	return values, db.GetEngine(ctx).Where("issue_id = ?", issueID).Asc("id").Find(&values)
+}
+
+func applyFieldValuesCondition(sess *xorm.Session, opts *IssuesOptions) {
+	for fieldID, value := range opts.FieldValues {
+		sess.And(builder.In("issue.id",
+			builder.Select("issue_id").From("issue_field_value").Where(builder.Eq{"field_id": fieldID, "value": value}),
+		))
+	}
+}
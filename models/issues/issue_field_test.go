@@ -0,0 +1,141 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueField_Validate(t *testing.T) {
+	field := &issues_model.IssueField{Name: " Priority ", Type: issues_model.IssueFieldTypeSelect, Options: []string{" High", "Low", "", "High"}}
+	assert.NoError(t, field.Validate())
+	assert.Equal(t, "Priority", field.Name)
+	assert.Equal(t, []string{"High", "Low"}, field.Options)
+
+	assert.ErrorIs(t, (&issues_model.IssueField{Name: " ", Type: issues_model.IssueFieldTypeText}).Validate(), util.ErrInvalidArgument)
+	assert.ErrorIs(t, (&issues_model.IssueField{Name: "Estimate", Type: "duration"}).Validate(), util.ErrInvalidArgument)
+	assert.ErrorIs(t, (&issues_model.IssueField{Name: "Priority", Type: issues_model.IssueFieldTypeMultiSelect}).Validate(), util.ErrInvalidArgument)
+}
+
+func TestIssueField_NormalizeValues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	test := func(f *issues_model.IssueField, values []string, expected ...string) {
+		t.Helper()
+		normalized, err := f.NormalizeValues(db.DefaultContext, values)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, normalized)
+	}
+	testInvalid := func(f *issues_model.IssueField, values ...string) {
+		t.Helper()
+		_, err := f.NormalizeValues(db.DefaultContext, values)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	}
+
+	number := &issues_model.IssueField{Name: "Estimate", Type: issues_model.IssueFieldTypeNumber}
+	test(number, []string{" 1.50 "}, "1.5")
+	normalized, err := number.NormalizeValues(db.DefaultContext, []string{""})
+	assert.NoError(t, err)
+	assert.Empty(t, normalized)
+	testInvalid(number, "one")
+	testInvalid(number, "1", "2")
+
+	date := &issues_model.IssueField{Name: "Due", Type: issues_model.IssueFieldTypeDate}
+	test(date, []string{"2024-02-29"}, "2024-02-29")
+	testInvalid(date, "29.02.2024")
+
+	multiSelect := &issues_model.IssueField{Name: "Platforms", Type: issues_model.IssueFieldTypeMultiSelect, Options: []string{"Linux", "Windows"}}
+	test(multiSelect, []string{"Windows", "Linux", "Windows"}, "Windows", "Linux")
+	testInvalid(multiSelect, "macOS")
+
+	user := &issues_model.IssueField{Name: "Reviewer", Type: issues_model.IssueFieldTypeUser}
+	test(user, []string{"user2"}, "2")
+	test(user, []string{"4"}, "4")
+	testInvalid(user, "nobody")
+}
+
+func TestNewIssueField(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repoField := &issues_model.IssueField{RepoID: 3, Name: "Estimate", Type: issues_model.IssueFieldTypeNumber}
+	assert.NoError(t, issues_model.NewIssueField(db.DefaultContext, repoField))
+	orgField := &issues_model.IssueField{OrgID: 3, Name: "Priority", Type: issues_model.IssueFieldTypeSelect, Options: []string{"High", "Low"}}
+	assert.NoError(t, issues_model.NewIssueField(db.DefaultContext, orgField))
+	assert.NoError(t, issues_model.NewIssueField(db.DefaultContext, &issues_model.IssueField{RepoID: 1, Name: "Due", Type: issues_model.IssueFieldTypeDate}))
+
+	assert.ErrorIs(t, issues_model.NewIssueField(db.DefaultContext, &issues_model.IssueField{RepoID: 3, Name: "estimate", Type: issues_model.IssueFieldTypeText}), util.ErrAlreadyExist)
+	assert.ErrorIs(t, issues_model.NewIssueField(db.DefaultContext, &issues_model.IssueField{Name: "Notes", Type: issues_model.IssueFieldTypeText}), util.ErrInvalidArgument)
+
+	fields, err := issues_model.GetIssueFieldsForRepo(db.DefaultContext, 3, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, fields, 2) {
+		assert.Equal(t, "Estimate", fields[0].Name)
+		assert.Equal(t, "Priority", fields[1].Name)
+	}
+
+	field, err := issues_model.GetIssueFieldForRepoByName(db.DefaultContext, 3, 3, "priority")
+	assert.NoError(t, err)
+	assert.Equal(t, orgField.ID, field.ID)
+
+	_, err = issues_model.GetIssueFieldForRepoByID(db.DefaultContext, 1, 1, repoField.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
+
+func TestSetIssueFieldValues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	platforms := &issues_model.IssueField{RepoID: 1, Name: "Platforms", Type: issues_model.IssueFieldTypeMultiSelect, Options: []string{"Linux", "macOS", "Windows"}}
+	assert.NoError(t, issues_model.NewIssueField(db.DefaultContext, platforms))
+	estimate := &issues_model.IssueField{RepoID: 1, Name: "Estimate", Type: issues_model.IssueFieldTypeNumber}
+	assert.NoError(t, issues_model.NewIssueField(db.DefaultContext, estimate))
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, issues_model.SetIssueFieldValues(db.DefaultContext, issue, platforms, []string{"Linux", "Windows"}))
+	assert.NoError(t, issues_model.SetIssueFieldValues(db.DefaultContext, issue, estimate, []string{"3"}))
+	other := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, issues_model.SetIssueFieldValues(db.DefaultContext, other, platforms, []string{"Linux"}))
+
+	assert.NoError(t, issue.LoadFieldValues(db.DefaultContext))
+	if assert.Len(t, issue.FieldValues, 2) {
+		assert.Equal(t, "Estimate", issue.FieldValues[0].Field.Name)
+		assert.Equal(t, []string{"3"}, issue.FieldValues[0].Values)
+		assert.Equal(t, "Platforms", issue.FieldValues[1].Field.Name)
+		assert.Equal(t, []string{"Linux", "Windows"}, issue.FieldValues[1].Values)
+	}
+
+	issues, err := issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{
+		RepoIDs:     []int64{1},
+		FieldValues: map[int64]string{platforms.ID: "Linux"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 2)
+
+	issues, err = issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{
+		RepoIDs:     []int64{1},
+		FieldValues: map[int64]string{platforms.ID: "Linux", estimate.ID: "3"},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].ID)
+	}
+
+	// the values which aren't an option anymore are removed
+	platforms.Options = []string{"Linux", "macOS"}
+	assert.NoError(t, issues_model.UpdateIssueField(db.DefaultContext, platforms))
+	issue.FieldValues = nil
+	assert.NoError(t, issue.LoadFieldValues(db.DefaultContext))
+	if assert.Len(t, issue.FieldValues, 2) {
+		assert.Equal(t, []string{"Linux"}, issue.FieldValues[1].Values)
+	}
+
+	assert.NoError(t, issues_model.DeleteIssueField(db.DefaultContext, platforms))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueFieldValue{FieldID: platforms.ID})
+}
@@ -0,0 +1,349 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// IssueFilterStates are the states of the issues which a filter can show
+var IssueFilterStates = []string{"open", "closed", "all"}
+
+// IssueFilterSortTypes are the orders in which a filter can show the issues
+var IssueFilterSortTypes = []string{"newest", "oldest", "recentupdate", "leastupdate", "mostcomment", "leastcomment", "mostvotes", "leastvotes", "nearduedate", "farduedate", "priority"}
+
+// IssueFilter is a named combination of filters of an issue list, opened by URL with its slug. It's saved by a user
+// for the issue list of a repository and only visible to them, or shared: saved by a repository for its issue list,
+// or by an organization for the issue lists of all its repositories.
+type IssueFilter struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	OrgID  int64 `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	// UserID is the owner of a personal filter, it's 0 for the shared filters
+	UserID int64  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	Slug   string `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+	Name   string `xorm:"VARCHAR(50) NOT NULL"`
+	// LabelIDs are the labels the issues must have, a negative id excludes the issues with the label
+	// and 0 selects the issues without labels
+	LabelIDs []int64 `xorm:"TEXT JSON"`
+	// AssigneeID, MilestoneID and TypeID select the issues without assignee, milestone or type if they're -1
+	AssigneeID  int64
+	MilestoneID int64
+	TypeID      int64
+	State       string `xorm:"VARCHAR(10)"`
+	Keyword     string `xorm:"VARCHAR(255)"`
+	SortType    string `xorm:"VARCHAR(20)"`
+	// IsDefault marks the filter applied to the issue list of a repository which is opened without any filter
+	IsDefault   bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueFilter))
+}
+
+var (
+	issueFilterSlugPattern     = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	issueFilterSlugInvalidChar = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// IssueFilterSlug returns the slug of a filter with the given name
+func IssueFilterSlug(name string) string {
+	return strings.Trim(issueFilterSlugInvalidChar.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// BelongsToOrg returns true if the filter is saved by an organization
+func (f *IssueFilter) BelongsToOrg() bool {
+	return f.OrgID > 0
+}
+
+// BelongsToRepo returns true if the filter is saved by a repository
+func (f *IssueFilter) BelongsToRepo() bool {
+	return f.RepoID > 0 && f.UserID == 0
+}
+
+// IsPersonal returns true if the filter is saved by a user for themselves
+func (f *IssueFilter) IsPersonal() bool {
+	return f.UserID > 0
+}
+
+// QueryString returns the query string of the issue list with the filters
+func (f *IssueFilter) QueryString() string {
+	query := url.Values{}
+	if len(f.LabelIDs) > 0 {
+		query.Set("labels", strings.Join(base.Int64sToStrings(f.LabelIDs), ","))
+	}
+	if f.AssigneeID != 0 {
+		query.Set("assignee", strconv.FormatInt(f.AssigneeID, 10))
+	}
+	if f.MilestoneID != 0 {
+		query.Set("milestone", strconv.FormatInt(f.MilestoneID, 10))
+	}
+	if f.TypeID != 0 {
+		query.Set("issue_type", strconv.FormatInt(f.TypeID, 10))
+	}
+	if f.State != "" {
+		query.Set("state", f.State)
+	}
+	if f.Keyword != "" {
+		query.Set("q", f.Keyword)
+	}
+	if f.SortType != "" {
+		query.Set("sort", f.SortType)
+	}
+	return query.Encode()
+}
+
+// Validate checks if the filters are consistent and normalizes them, the labels, the milestone and the type
+// must be available to the issues of the repository or of all the repositories of the organization
+func (f *IssueFilter) Validate(ctx context.Context) error {
+	if (f.RepoID > 0) == (f.OrgID > 0) {
+		return util.NewInvalidArgumentErrorf("a filter belongs to either a repository or an organization")
+	}
+	if f.UserID > 0 && f.OrgID > 0 {
+		return util.NewInvalidArgumentErrorf("a personal filter belongs to a repository")
+	}
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" || len(f.Name) > 50 {
+		return util.NewInvalidArgumentErrorf("invalid filter name %q", f.Name)
+	}
+	if f.Slug == "" {
+		f.Slug = IssueFilterSlug(f.Name)
+	}
+	if len(f.Slug) > 50 || !issueFilterSlugPattern.MatchString(f.Slug) {
+		return util.NewInvalidArgumentErrorf("invalid slug %q, it must only contain lowercase letters, digits and dashes", f.Slug)
+	}
+	if f.State != "" && !slices.Contains(IssueFilterStates, f.State) {
+		return util.NewInvalidArgumentErrorf("invalid state %q", f.State)
+	}
+	if f.SortType != "" && !slices.Contains(IssueFilterSortTypes, f.SortType) {
+		return util.NewInvalidArgumentErrorf("invalid sort type %q", f.SortType)
+	}
+	f.Keyword = strings.TrimSpace(f.Keyword)
+	if f.QueryString() == "" {
+		return util.NewInvalidArgumentErrorf("the filter %q doesn't filter or sort the issues", f.Name)
+	}
+	if f.IsDefault && !f.BelongsToRepo() {
+		return util.NewInvalidArgumentErrorf("only the shared filters of a repository can be its default")
+	}
+
+	ownerCond, err := f.ownerCond(ctx)
+	if err != nil {
+		return err
+	}
+	if err := f.validateLabels(ctx, ownerCond); err != nil {
+		return err
+	}
+	if f.AssigneeID > 0 {
+		if _, err := user_model.GetUserByID(ctx, f.AssigneeID); err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				return util.NewInvalidArgumentErrorf("assignee %d does not exist", f.AssigneeID)
+			}
+			return err
+		}
+	}
+	if f.MilestoneID > 0 {
+		var err error
+		if f.OrgID > 0 {
+			_, err = GetMilestoneByOrgID(ctx, f.OrgID, f.MilestoneID)
+		} else {
+			_, err = GetMilestoneForRepo(ctx, f.RepoID, f.MilestoneID)
+		}
+		if err != nil {
+			if IsErrMilestoneNotExist(err) {
+				return util.NewInvalidArgumentErrorf("milestone %d does not exist", f.MilestoneID)
+			}
+			return err
+		}
+	}
+	if f.TypeID > 0 {
+		exist, err := db.GetEngine(ctx).Where(builder.Eq{"id": f.TypeID}.And(ownerCond)).Exist(new(IssueType))
+		if err != nil {
+			return err
+		} else if !exist {
+			return util.NewInvalidArgumentErrorf("issue type %d does not exist", f.TypeID)
+		}
+	}
+	return nil
+}
+
+func (f *IssueFilter) validateLabels(ctx context.Context, ownerCond builder.Cond) error {
+	ids := make([]int64, 0, len(f.LabelIDs))
+	for _, id := range f.LabelIDs {
+		if id < 0 {
+			id = -id
+		}
+		if id > 0 && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	count, err := db.GetEngine(ctx).In("id", ids).And(ownerCond).Count(new(Label))
+	if err != nil {
+		return err
+	} else if count != int64(len(ids)) {
+		return util.NewInvalidArgumentErrorf("labels %v don't all exist", f.LabelIDs)
+	}
+	return nil
+}
+
+// ownerCond returns the condition of the labels and the issue types which the filter can select,
+// the ones of the organization or the ones of the repository and of its owner
+func (f *IssueFilter) ownerCond(ctx context.Context) (builder.Cond, error) {
+	if f.OrgID > 0 {
+		return builder.Eq{"org_id": f.OrgID}, nil
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, f.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	return builder.Eq{"repo_id": f.RepoID}.Or(builder.Eq{"org_id": repo.OwnerID}), nil
+}
+
+// NewIssueFilter saves a personal filter of a user or a shared filter of a repository or an organization
+func NewIssueFilter(ctx context.Context, f *IssueFilter) error {
+	if err := f.Validate(ctx); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkIssueFilterSlugAvailable(ctx, f); err != nil {
+			return err
+		}
+		if err := db.Insert(ctx, f); err != nil {
+			return err
+		}
+		return unsetOtherDefaultIssueFilters(ctx, f)
+	})
+}
+
+// UpdateIssueFilter updates a saved filter
+func UpdateIssueFilter(ctx context.Context, f *IssueFilter) error {
+	if err := f.Validate(ctx); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkIssueFilterSlugAvailable(ctx, f); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).ID(f.ID).
+			Cols("slug", "name", "label_ids", "assignee_id", "milestone_id", "type_id", "state", "keyword", "sort_type", "is_default").
+			Update(f); err != nil {
+			return err
+		}
+		return unsetOtherDefaultIssueFilters(ctx, f)
+	})
+}
+
+func checkIssueFilterSlugAvailable(ctx context.Context, f *IssueFilter) error {
+	exist, err := db.GetEngine(ctx).Where("repo_id = ? AND org_id = ? AND user_id = ? AND slug = ? AND id <> ?", f.RepoID, f.OrgID, f.UserID, f.Slug, f.ID).Exist(new(IssueFilter))
+	if err != nil {
+		return err
+	} else if exist {
+		return util.NewAlreadyExistErrorf("filter %q already exists", f.Slug)
+	}
+	return nil
+}
+
+// unsetOtherDefaultIssueFilters makes sure a repository has at most one default filter
+func unsetOtherDefaultIssueFilters(ctx context.Context, f *IssueFilter) error {
+	if !f.IsDefault {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).Where("repo_id = ? AND id <> ? AND is_default = ?", f.RepoID, f.ID, true).
+		Cols("is_default").Update(&IssueFilter{IsDefault: false})
+	return err
+}
+
+// DeleteIssueFilter deletes a saved filter
+func DeleteIssueFilter(ctx context.Context, f *IssueFilter) error {
+	_, err := db.DeleteByID[IssueFilter](ctx, f.ID)
+	return err
+}
+
+// GetIssueFiltersByRepoID returns the shared filters saved by a repository
+func GetIssueFiltersByRepoID(ctx context.Context, repoID int64) ([]*IssueFilter, error) {
+	filters := make([]*IssueFilter, 0, 5)
+	return filters, db.GetEngine(ctx).Where("repo_id = ? AND user_id = 0", repoID).Asc("name").Find(&filters)
+}
+
+// GetIssueFiltersByOrgID returns the filters saved by an organization
+func GetIssueFiltersByOrgID(ctx context.Context, orgID int64) ([]*IssueFilter, error) {
+	filters := make([]*IssueFilter, 0, 5)
+	return filters, db.GetEngine(ctx).Where("org_id = ?", orgID).Asc("name").Find(&filters)
+}
+
+// issueFiltersForRepoCond returns the condition of the filters of the issue list of a repository which a user can see,
+// the personal ones of the user, the shared ones of the repository and the ones of its owner if it's an organization
+func issueFiltersForRepoCond(repoID, ownerID, userID int64) builder.Cond {
+	cond := builder.Eq{"repo_id": repoID, "user_id": 0}.Or(builder.Eq{"org_id": ownerID})
+	if userID > 0 {
+		cond = cond.Or(builder.Eq{"repo_id": repoID, "user_id": userID})
+	}
+	return cond
+}
+
+// GetIssueFiltersForRepo returns the filters of the issue list of a repository which the user can see,
+// userID is 0 for an anonymous user
+func GetIssueFiltersForRepo(ctx context.Context, repoID, ownerID, userID int64) ([]*IssueFilter, error) {
+	filters := make([]*IssueFilter, 0, 5)
+	return filters, db.GetEngine(ctx).
+		Where(issueFiltersForRepoCond(repoID, ownerID, userID)).
+		Asc("name").Asc("id").
+		Find(&filters)
+}
+
+// GetIssueFilterBySlug returns the filter with the given slug saved by a repository or an organization,
+// or the personal filter of a user for a repository if userID isn't 0
+func GetIssueFilterBySlug(ctx context.Context, repoID, orgID, userID int64, slug string) (*IssueFilter, error) {
+	f := new(IssueFilter)
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND org_id = ? AND user_id = ? AND slug = ?", repoID, orgID, userID, slug).Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("filter %q does not exist", slug)
+	}
+	return f, nil
+}
+
+// GetIssueFilterForRepoBySlug returns the filter with the given slug of the issue list of a repository which the user can see,
+// the personal filter of the user is preferred over the filter of the repository, which is preferred over the filter of its owner
+func GetIssueFilterForRepoBySlug(ctx context.Context, repoID, ownerID, userID int64, slug string) (*IssueFilter, error) {
+	f := new(IssueFilter)
+	has, err := db.GetEngine(ctx).Where(issueFiltersForRepoCond(repoID, ownerID, userID)).
+		And("slug = ?", slug).
+		Desc("user_id").Desc("repo_id").Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("filter %q does not exist", slug)
+	}
+	return f, nil
+}
+
+// GetDefaultIssueFilter returns the default filter of the issue list of a repository, or nil if it doesn't have one
+func GetDefaultIssueFilter(ctx context.Context, repoID int64) (*IssueFilter, error) {
+	f := new(IssueFilter)
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND user_id = 0 AND is_default = ?", repoID, true).Get(f)
+	if err != nil || !has {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueFilterSlug(t *testing.T) {
+	assert.Equal(t, "my-open-bugs", issues_model.IssueFilterSlug(" My open bugs!"))
+	assert.Equal(t, "v1-2-release", issues_model.IssueFilterSlug("v1.2 -- Release"))
+}
+
+func TestIssueFilter_QueryString(t *testing.T) {
+	f := &issues_model.IssueFilter{
+		LabelIDs:    []int64{1, -2},
+		AssigneeID:  -1,
+		MilestoneID: 1,
+		State:       "all",
+		Keyword:     "crash on start",
+		SortType:    "oldest",
+	}
+	assert.Equal(t, "assignee=-1&labels=1%2C-2&milestone=1&q=crash+on+start&sort=oldest&state=all", f.QueryString())
+}
+
+func TestNewIssueFilter(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	test := func(f *issues_model.IssueFilter, expectedErr error) {
+		t.Helper()
+		err := issues_model.NewIssueFilter(db.DefaultContext, f)
+		if expectedErr == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, expectedErr)
+		}
+	}
+
+	bugs := &issues_model.IssueFilter{RepoID: 1, Name: "Open bugs", LabelIDs: []int64{1}, MilestoneID: 1, State: "open", IsDefault: true}
+	test(bugs, nil)
+	assert.Equal(t, "open-bugs", bugs.Slug)
+	test(&issues_model.IssueFilter{RepoID: 1, Name: "Open bugs", LabelIDs: []int64{2}}, util.ErrAlreadyExist)
+	test(&issues_model.IssueFilter{RepoID: 1, Name: "Nothing"}, util.ErrInvalidArgument)
+	test(&issues_model.IssueFilter{RepoID: 1, Name: "Other labels", LabelIDs: []int64{3}}, util.ErrInvalidArgument)
+	test(&issues_model.IssueFilter{RepoID: 1, Name: "Bad sort", SortType: "random"}, util.ErrInvalidArgument)
+	test(&issues_model.IssueFilter{RepoID: 1, Name: "Bad slug", Slug: "Bad Slug", State: "all"}, util.ErrInvalidArgument)
+
+	// the filters of an organization can use its labels, but no milestone, and can't be a default
+	orgFilter := &issues_model.IssueFilter{OrgID: 3, Name: "Org bugs", LabelIDs: []int64{3, -4}, AssigneeID: 2}
+	test(orgFilter, nil)
+	test(&issues_model.IssueFilter{OrgID: 3, Name: "Milestone", MilestoneID: 1}, util.ErrInvalidArgument)
+	test(&issues_model.IssueFilter{OrgID: 3, Name: "Default", State: "all", IsDefault: true}, util.ErrInvalidArgument)
+	test(&issues_model.IssueFilter{RepoID: 3, Name: "Repo bugs", LabelIDs: []int64{3}}, nil)
+
+	// a repository has at most one default filter
+	oldest := &issues_model.IssueFilter{RepoID: 1, Name: "Oldest", SortType: "oldest", IsDefault: true}
+	test(oldest, nil)
+	f, err := issues_model.GetDefaultIssueFilter(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, oldest.ID, f.ID)
+
+	oldest.IsDefault = false
+	assert.NoError(t, issues_model.UpdateIssueFilter(db.DefaultContext, oldest))
+	f, err = issues_model.GetDefaultIssueFilter(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, f)
+
+	// the filter of the repository is preferred over the one of its organization
+	test(&issues_model.IssueFilter{RepoID: 3, Name: "Org bugs", State: "closed"}, nil)
+	f, err = issues_model.GetIssueFilterForRepoBySlug(db.DefaultContext, 3, 3, 0, "org-bugs")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, f.RepoID)
+	f, err = issues_model.GetIssueFilterForRepoBySlug(db.DefaultContext, 5, 3, 0, "org-bugs")
+	assert.NoError(t, err)
+	assert.Equal(t, orgFilter.ID, f.ID)
+
+	filters, err := issues_model.GetIssueFiltersForRepo(db.DefaultContext, 3, 3, 0)
+	assert.NoError(t, err)
+	assert.Len(t, filters, 3)
+
+	// a personal filter is only visible to its owner and is preferred over the shared ones, it can't be a default
+	personal := &issues_model.IssueFilter{RepoID: 3, UserID: 4, Name: "Org bugs", State: "open"}
+	test(personal, nil)
+	test(&issues_model.IssueFilter{RepoID: 3, UserID: 4, Name: "Org bugs", State: "all"}, util.ErrAlreadyExist)
+	test(&issues_model.IssueFilter{RepoID: 3, UserID: 4, Name: "Default", State: "all", IsDefault: true}, util.ErrInvalidArgument)
+	test(&issues_model.IssueFilter{OrgID: 3, UserID: 4, Name: "Personal org", State: "all"}, util.ErrInvalidArgument)
+	f, err = issues_model.GetIssueFilterForRepoBySlug(db.DefaultContext, 3, 3, 4, "org-bugs")
+	assert.NoError(t, err)
+	assert.Equal(t, personal.ID, f.ID)
+	f, err = issues_model.GetIssueFilterForRepoBySlug(db.DefaultContext, 3, 3, 2, "org-bugs")
+	assert.NoError(t, err)
+	assert.False(t, f.IsPersonal())
+	filters, err = issues_model.GetIssueFiltersForRepo(db.DefaultContext, 3, 3, 4)
+	assert.NoError(t, err)
+	assert.Len(t, filters, 4)
+	filters, err = issues_model.GetIssueFiltersByRepoID(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Len(t, filters, 2)
+
+	assert.NoError(t, issues_model.DeleteIssueFilter(db.DefaultContext, orgFilter))
+	_, err = issues_model.GetIssueFilterForRepoBySlug(db.DefaultContext, 5, 3, 0, "org-bugs")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
@@ -561,6 +561,18 @@ func (issues IssueList) LoadAttributes(ctx context.Context) error {
 		return fmt.Errorf("issue.loadAttributes: loadProjects: %w", err)
 	}
 
+	if err := issues.LoadTypes(ctx); err != nil {
+		return fmt.Errorf("issue.loadAttributes: LoadTypes: %w", err)
+	}
+
+	if err := issues.LoadFieldValues(ctx); err != nil {
+		return fmt.Errorf("issue.loadAttributes: LoadFieldValues: %w", err)
+	}
+
+	if err := issues.LoadSubIssueProgress(ctx); err != nil {
+		return fmt.Errorf("issue.loadAttributes: LoadSubIssueProgress: %w", err)
+	}
+
 	if err := issues.LoadAssignees(ctx); err != nil {
 		return fmt.Errorf("issue.loadAttributes: loadAssignees: %w", err)
 	}
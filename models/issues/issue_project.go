@@ -5,6 +5,7 @@ package issues
 
 import (
 	"context"
+	"slices"
 
 	"code.gitea.io/gitea/models/db"
 	project_model "code.gitea.io/gitea/models/project"
@@ -47,12 +48,17 @@ func (issue *Issue) ProjectColumnID(ctx context.Context) int64 {
 	return ip.ProjectColumnID
 }
 
-// LoadIssuesFromColumn load issues assigned to this column
-func LoadIssuesFromColumn(ctx context.Context, b *project_model.Column) (IssueList, error) {
+// LoadIssuesFromColumn load issues assigned to this column, in the order of the column
+// or by the number of votes if sortType is "mostvotes"
+func LoadIssuesFromColumn(ctx context.Context, b *project_model.Column, sortType string) (IssueList, error) {
+	if sortType != "mostvotes" {
+		sortType = "project-column-sorting"
+	}
+
 	issueList, err := Issues(ctx, &IssuesOptions{
 		ProjectColumnID: b.ID,
 		ProjectID:       b.ProjectID,
-		SortType:        "project-column-sorting",
+		SortType:        sortType,
 	})
 	if err != nil {
 		return nil, err
@@ -62,12 +68,18 @@ func LoadIssuesFromColumn(ctx context.Context, b *project_model.Column) (IssueLi
 		issues, err := Issues(ctx, &IssuesOptions{
 			ProjectColumnID: db.NoConditionID,
 			ProjectID:       b.ProjectID,
-			SortType:        "project-column-sorting",
+			SortType:        sortType,
 		})
 		if err != nil {
 			return nil, err
 		}
 		issueList = append(issueList, issues...)
+
+		if sortType == "mostvotes" {
+			slices.SortStableFunc(issueList, func(a, b *Issue) int {
+				return b.NumVotes - a.NumVotes
+			})
+		}
 	}
 
 	if err := issueList.LoadComments(ctx); err != nil {
@@ -78,10 +90,10 @@ func LoadIssuesFromColumn(ctx context.Context, b *project_model.Column) (IssueLi
 }
 
 // LoadIssuesFromColumnList load issues assigned to the columns
-func LoadIssuesFromColumnList(ctx context.Context, bs project_model.ColumnList) (map[int64]IssueList, error) {
+func LoadIssuesFromColumnList(ctx context.Context, bs project_model.ColumnList, sortType string) (map[int64]IssueList, error) {
 	issuesMap := make(map[int64]IssueList, len(bs))
 	for i := range bs {
-		il, err := LoadIssuesFromColumn(ctx, bs[i])
+		il, err := LoadIssuesFromColumn(ctx, bs[i], sortType)
 		if err != nil {
 			return nil, err
 		}
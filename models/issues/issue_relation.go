@@ -0,0 +1,257 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// MaxSubIssueDepth is the maximum number of levels of a hierarchy of issues
+const MaxSubIssueDepth = 8
+
+// IssueRelation makes an issue a sub-issue of another one, an issue has at most one parent
+type IssueRelation struct {
+	ID          int64              `xorm:"pk autoincr"`
+	ParentID    int64              `xorm:"INDEX NOT NULL"`
+	ChildID     int64              `xorm:"UNIQUE NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueRelation))
+}
+
+// SubIssueProgress is the progress of the sub-issues of an issue
+type SubIssueProgress struct {
+	Total  int64
+	Closed int64
+}
+
+// Percent returns the percentage of the closed sub-issues
+func (p *SubIssueProgress) Percent() int {
+	if p.Total == 0 {
+		return 0
+	}
+	return int(100 * p.Closed / p.Total)
+}
+
+// AddSubIssue makes child a sub-issue of parent, the child must not have a parent already
+// and mustn't be an ancestor of the parent.
+func AddSubIssue(ctx context.Context, parent, child *Issue) error {
+	if parent.ID == child.ID {
+		return util.NewInvalidArgumentErrorf("an issue can't be a sub-issue of itself")
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		exist, err := db.GetEngine(ctx).Where("child_id = ?", child.ID).Exist(new(IssueRelation))
+		if err != nil {
+			return err
+		} else if exist {
+			return util.NewAlreadyExistErrorf("issue %d is already a sub-issue", child.ID)
+		}
+
+		depth, err := subIssueTreeDepth(ctx, child.ID)
+		if err != nil {
+			return err
+		}
+		for ancestorID := parent.ID; ancestorID != 0; {
+			if ancestorID == child.ID {
+				return util.NewInvalidArgumentErrorf("issue %d is an ancestor of issue %d", child.ID, parent.ID)
+			}
+			if depth++; depth >= MaxSubIssueDepth {
+				return util.NewInvalidArgumentErrorf("a hierarchy of issues can't have more than %d levels", MaxSubIssueDepth)
+			}
+			if ancestorID, err = getParentIssueID(ctx, ancestorID); err != nil {
+				return err
+			}
+		}
+
+		if err := db.Insert(ctx, &IssueRelation{ParentID: parent.ID, ChildID: child.ID}); err != nil {
+			return err
+		}
+		return updateMilestoneCountersOfIssue(ctx, parent.ID)
+	})
+}
+
+// RemoveSubIssue makes child not a sub-issue of parent anymore
+func RemoveSubIssue(ctx context.Context, parent, child *Issue) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		affected, err := db.GetEngine(ctx).Where("parent_id = ? AND child_id = ?", parent.ID, child.ID).Delete(new(IssueRelation))
+		if err != nil {
+			return err
+		} else if affected == 0 {
+			return util.NewNotExistErrorf("issue %d is not a sub-issue of issue %d", child.ID, parent.ID)
+		}
+		return updateMilestoneCountersOfIssue(ctx, parent.ID)
+	})
+}
+
+// DeleteIssueRelations deletes the relations of an issue to its parent and its sub-issues
+func DeleteIssueRelations(ctx context.Context, issueID int64) error {
+	parentID, err := getParentIssueID(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if _, err := db.GetEngine(ctx).Where("parent_id = ? OR child_id = ?", issueID, issueID).Delete(new(IssueRelation)); err != nil {
+		return err
+	}
+	if parentID == 0 {
+		return nil
+	}
+	return updateMilestoneCountersOfIssue(ctx, parentID)
+}
+
+// getParentIssueID returns the id of the parent of an issue, or 0 if it doesn't have one
+func getParentIssueID(ctx context.Context, issueID int64) (int64, error) {
+	var parentID int64
+	_, err := db.GetEngine(ctx).Table("issue_relation").Where("child_id = ?", issueID).Cols("parent_id").Get(&parentID)
+	return parentID, err
+}
+
+// subIssueTreeDepth returns the number of levels of the sub-issues below an issue
+func subIssueTreeDepth(ctx context.Context, issueID int64) (int, error) {
+	depth := 0
+	ids := []int64{issueID}
+	for len(ids) > 0 && depth < MaxSubIssueDepth {
+		children := make([]int64, 0, len(ids))
+		if err := db.GetEngine(ctx).Table("issue_relation").In("parent_id", ids).Cols("child_id").Find(&children); err != nil {
+			return 0, err
+		}
+		if len(children) > 0 {
+			depth++
+		}
+		ids = children
+	}
+	return depth, nil
+}
+
+// GetParentIssue returns the parent of an issue
+func GetParentIssue(ctx context.Context, issueID int64) (*Issue, error) {
+	parentID, err := getParentIssueID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	} else if parentID == 0 {
+		return nil, util.NewNotExistErrorf("issue %d doesn't have a parent", issueID)
+	}
+	return GetIssueByID(ctx, parentID)
+}
+
+// GetSubIssues returns the sub-issues of an issue with their repositories loaded, in the order they were added,
+// the sub-issues aren't necessarily in the same repository as the issue
+func GetSubIssues(ctx context.Context, issueID int64) (IssueList, error) {
+	issues := make(IssueList, 0, 5)
+	if err := db.GetEngine(ctx).
+		Join("INNER", "issue_relation", "issue_relation.child_id = issue.id").
+		Where("issue_relation.parent_id = ?", issueID).
+		OrderBy("issue_relation.id").
+		Find(&issues); err != nil {
+		return nil, err
+	}
+	if _, err := issues.LoadRepositories(ctx); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// LoadSubIssueProgress loads the progress of the sub-issues of the issue
+func (issue *Issue) LoadSubIssueProgress(ctx context.Context) error {
+	if issue.SubIssueProgress != nil {
+		return nil
+	}
+	return IssueList{issue}.LoadSubIssueProgress(ctx)
+}
+
+// LoadSubIssueProgress loads the progress of the sub-issues of the issues
+func (issues IssueList) LoadSubIssueProgress(ctx context.Context) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	progresses, err := getSubIssueProgresses(ctx, builder.In("issue_relation.parent_id", issues.getIssueIDs()))
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		issue.SubIssueProgress = progresses[issue.ID]
+		if issue.SubIssueProgress == nil {
+			issue.SubIssueProgress = &SubIssueProgress{}
+		}
+	}
+	return nil
+}
+
+// getSubIssueProgresses returns the progress of the sub-issues of the parent issues matching the condition,
+// keyed by the parent ids
+func getSubIssueProgresses(ctx context.Context, cond builder.Cond) (map[int64]*SubIssueProgress, error) {
+	type progressCount struct {
+		ParentID int64
+		IsClosed bool
+		Count    int64
+	}
+	counts := make([]*progressCount, 0, 10)
+	if err := db.GetEngine(ctx).Table("issue_relation").
+		Join("INNER", "issue", "issue.id = issue_relation.child_id").
+		Where(cond).
+		Select("issue_relation.parent_id AS parent_id, issue.is_closed AS is_closed, COUNT(*) AS count").
+		GroupBy("issue_relation.parent_id, issue.is_closed").
+		Find(&counts); err != nil {
+		return nil, err
+	}
+
+	progresses := make(map[int64]*SubIssueProgress, len(counts))
+	for _, c := range counts {
+		p, ok := progresses[c.ParentID]
+		if !ok {
+			p = &SubIssueProgress{}
+			progresses[c.ParentID] = p
+		}
+		p.Total += c.Count
+		if c.IsClosed {
+			p.Closed += c.Count
+		}
+	}
+	return progresses, nil
+}
+
+// getMilestoneSubIssueRollup returns the sum of the progress fractions of the open issues of a milestone which
+// have sub-issues, so the progress of an epic counts towards the completeness of its milestone before it's closed
+func getMilestoneSubIssueRollup(ctx context.Context, milestoneID int64) (float64, error) {
+	progresses, err := getSubIssueProgresses(ctx, builder.In("issue_relation.parent_id",
+		builder.Select("id").From("issue").Where(builder.Eq{"milestone_id": milestoneID, "is_closed": false}),
+	))
+	if err != nil {
+		return 0, err
+	}
+	var rollup float64
+	for _, p := range progresses {
+		rollup += float64(p.Closed) / float64(p.Total)
+	}
+	return rollup, nil
+}
+
+// updateMilestoneCountersOfIssue updates the counters of the milestone of an issue if it has one
+func updateMilestoneCountersOfIssue(ctx context.Context, issueID int64) error {
+	var milestoneID int64
+	if _, err := db.GetEngine(ctx).Table("issue").Where("id = ?", issueID).Cols("milestone_id").Get(&milestoneID); err != nil {
+		return err
+	}
+	if milestoneID == 0 {
+		return nil
+	}
+	return UpdateMilestoneCounters(ctx, milestoneID)
+}
+
+// updateMilestoneCountersOfParentIssue updates the counters of the milestone of the parent of an issue,
+// since the progress of the sub-issues of the parent changes with the status of the issue
+func updateMilestoneCountersOfParentIssue(ctx context.Context, issueID int64) error {
+	parentID, err := getParentIssueID(ctx, issueID)
+	if err != nil || parentID == 0 {
+		return err
+	}
+	return updateMilestoneCountersOfIssue(ctx, parentID)
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddSubIssue(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	parent := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	openChild := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	closedChild := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 5})
+
+	assert.NoError(t, issues_model.AddSubIssue(db.DefaultContext, parent, openChild))
+	assert.NoError(t, issues_model.AddSubIssue(db.DefaultContext, parent, closedChild))
+
+	assert.ErrorIs(t, issues_model.AddSubIssue(db.DefaultContext, parent, parent), util.ErrInvalidArgument)
+	assert.ErrorIs(t, issues_model.AddSubIssue(db.DefaultContext, openChild, parent), util.ErrInvalidArgument)
+	assert.ErrorIs(t, issues_model.AddSubIssue(db.DefaultContext, openChild, closedChild), util.ErrAlreadyExist)
+
+	subIssues, err := issues_model.GetSubIssues(db.DefaultContext, parent.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, subIssues, 2) {
+		assert.EqualValues(t, 1, subIssues[0].ID)
+		assert.EqualValues(t, 5, subIssues[1].ID)
+	}
+
+	p, err := issues_model.GetParentIssue(db.DefaultContext, openChild.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, parent.ID, p.ID)
+	_, err = issues_model.GetParentIssue(db.DefaultContext, parent.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	assert.NoError(t, parent.LoadSubIssueProgress(db.DefaultContext))
+	assert.EqualValues(t, 2, parent.SubIssueProgress.Total)
+	assert.EqualValues(t, 1, parent.SubIssueProgress.Closed)
+	assert.Equal(t, 50, parent.SubIssueProgress.Percent())
+
+	// the progress of the sub-issues counts towards the completeness of the milestone of the parent
+	milestone := unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: parent.MilestoneID})
+	assert.Equal(t, 50, milestone.Completeness)
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	assert.NoError(t, openChild.LoadRepo(db.DefaultContext))
+	_, err = issues_model.ChangeIssueStatus(db.DefaultContext, openChild, doer, true)
+	assert.NoError(t, err)
+	milestone = unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: parent.MilestoneID})
+	assert.Equal(t, 100, milestone.Completeness)
+
+	assert.NoError(t, issues_model.RemoveSubIssue(db.DefaultContext, parent, closedChild))
+	assert.ErrorIs(t, issues_model.RemoveSubIssue(db.DefaultContext, parent, closedChild), util.ErrNotExist)
+	unittest.AssertNotExistsBean(t, &issues_model.IssueRelation{ChildID: closedChild.ID})
+}
+
+func TestAddSubIssue_MaxDepth(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issues := make([]*issues_model.Issue, 0, issues_model.MaxSubIssueDepth+1)
+	for id := int64(1); id <= issues_model.MaxSubIssueDepth+1; id++ {
+		issues = append(issues, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: id}))
+	}
+	for i := 1; i < issues_model.MaxSubIssueDepth; i++ {
+		assert.NoError(t, issues_model.AddSubIssue(db.DefaultContext, issues[i-1], issues[i]))
+	}
+	assert.ErrorIs(t, issues_model.AddSubIssue(db.DefaultContext, issues[issues_model.MaxSubIssueDepth-1], issues[issues_model.MaxSubIssueDepth]), util.ErrInvalidArgument)
+
+	// deleting the relations of an issue in the middle splits the hierarchy
+	assert.NoError(t, issues_model.DeleteIssueRelations(db.DefaultContext, issues[3].ID))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueRelation{ChildID: issues[3].ID})
+	unittest.AssertNotExistsBean(t, &issues_model.IssueRelation{ParentID: issues[3].ID})
+	assert.NoError(t, issues_model.AddSubIssue(db.DefaultContext, issues[issues_model.MaxSubIssueDepth-1], issues[issues_model.MaxSubIssueDepth]))
+}
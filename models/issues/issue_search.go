@@ -34,6 +34,8 @@ type IssuesOptions struct { //nolint
 	ReviewedID         int64
 	SubscriberID       int64
 	MilestoneIDs       []int64
+	TypeIDs            []int64
+	FieldValues        map[int64]string // issues must have each value of the custom fields
 	ProjectID          int64
 	ProjectColumnID    int64
 	IsClosed           optional.Option[bool]
@@ -68,6 +70,10 @@ func applySorts(sess *xorm.Session, sortType string, priorityRepoID int64) {
 		sess.Desc("issue.num_comments").Desc("issue.created_unix").Desc("issue.id")
 	case "leastcomment":
 		sess.Asc("issue.num_comments").Desc("issue.created_unix").Desc("issue.id")
+	case "mostvotes":
+		sess.Desc("issue.num_votes").Desc("issue.created_unix").Desc("issue.id")
+	case "leastvotes":
+		sess.Asc("issue.num_votes").Desc("issue.created_unix").Desc("issue.id")
 	case "priority":
 		sess.Desc("issue.priority").Desc("issue.created_unix").Desc("issue.id")
 	case "nearduedate":
@@ -161,6 +167,12 @@ func applyMilestoneCondition(sess *xorm.Session, opts *IssuesOptions) {
 		sess.In("issue.milestone_id", opts.MilestoneIDs)
 	}
 
+	if len(opts.TypeIDs) == 1 && opts.TypeIDs[0] == db.NoConditionID {
+		sess.And("issue.type_id = 0")
+	} else if len(opts.TypeIDs) > 0 {
+		sess.In("issue.type_id", opts.TypeIDs)
+	}
+
 	if len(opts.IncludeMilestones) > 0 {
 		sess.In("issue.milestone_id",
 			builder.Select("id").
@@ -246,6 +258,8 @@ func applyConditions(sess *xorm.Session, opts *IssuesOptions) {
 
 	applyMilestoneCondition(sess, opts)
 
+	applyFieldValuesCondition(sess, opts)
+
 	if opts.UpdatedAfterUnix != 0 {
 		sess.And(builder.Gte{"issue.updated_unix": opts.UpdatedAfterUnix})
 	}
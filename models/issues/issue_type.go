@@ -0,0 +1,304 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// The fields of an issue which an issue type can require
+const (
+	IssueTypeFieldContent   = "content"
+	IssueTypeFieldLabels    = "labels"
+	IssueTypeFieldMilestone = "milestone"
+	IssueTypeFieldAssignees = "assignees"
+	IssueTypeFieldDeadline  = "deadline"
+)
+
+// IssueTypeFields are the fields of an issue which an issue type can require
+var IssueTypeFields = []string{IssueTypeFieldContent, IssueTypeFieldLabels, IssueTypeFieldMilestone, IssueTypeFieldAssignees, IssueTypeFieldDeadline}
+
+// IssueType is a type of issues, like bug, feature, task or epic. It's defined by a repository for its issues,
+// or by an organization for the issues of all its repositories, like labels.
+type IssueType struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	OrgID       int64  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	Name        string `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+	Description string `xorm:"TEXT"`
+	// Icon is the name of the octicon shown for the issues of the type
+	Icon  string `xorm:"VARCHAR(50)"`
+	Color string `xorm:"VARCHAR(7)"`
+	// Template is the default content of the new issues of the type
+	Template string `xorm:"LONGTEXT"`
+	// RequiredFields are the fields the issues of the type must have when they are created, see IssueTypeFields
+	RequiredFields []string           `xorm:"TEXT JSON"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueType))
+}
+
+// DefaultIssueTypeIcon is the icon of the issue types without one
+const DefaultIssueTypeIcon = "octicon-issue-opened"
+
+var (
+	issueTypeIconPattern  = regexp.MustCompile(`^octicon-[a-z0-9-]+$`)
+	issueTypeColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// BelongsToOrg returns true if the issue type is defined by an organization
+func (t *IssueType) BelongsToOrg() bool {
+	return t.OrgID > 0
+}
+
+// BelongsToRepo returns true if the issue type is defined by a repository
+func (t *IssueType) BelongsToRepo() bool {
+	return t.RepoID > 0
+}
+
+// IconName returns the octicon shown for the issues of the type
+func (t *IssueType) IconName() string {
+	if t.Icon == "" {
+		return DefaultIssueTypeIcon
+	}
+	return t.Icon
+}
+
+// IsRequired returns whether the issues of the type must have the field
+func (t *IssueType) IsRequired(field string) bool {
+	return slices.Contains(t.RequiredFields, field)
+}
+
+// Validate checks if the definition of the issue type is consistent and normalizes it
+func (t *IssueType) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" || len(t.Name) > 50 {
+		return util.NewInvalidArgumentErrorf("invalid issue type name %q", t.Name)
+	}
+	if t.Icon != "" && !issueTypeIconPattern.MatchString(t.Icon) {
+		return util.NewInvalidArgumentErrorf("invalid icon %q, it must be the name of an octicon like octicon-bug", t.Icon)
+	}
+	if t.Color != "" && !issueTypeColorPattern.MatchString(t.Color) {
+		return util.NewInvalidArgumentErrorf("invalid color %q", t.Color)
+	}
+	t.RequiredFields = container.FilterSlice(t.RequiredFields, func(field string) (string, bool) {
+		return field, field != ""
+	})
+	for _, field := range t.RequiredFields {
+		if !slices.Contains(IssueTypeFields, field) {
+			return util.NewInvalidArgumentErrorf("unknown field %q, the required fields can be %s", field, strings.Join(IssueTypeFields, ", "))
+		}
+	}
+	return nil
+}
+
+// IssueTypeFieldValues are the values of the fields of a new issue which an issue type can require
+type IssueTypeFieldValues struct {
+	Content      string
+	LabelIDs     []int64
+	MilestoneID  int64
+	AssigneeIDs  []int64
+	DeadlineUnix timeutil.TimeStamp
+}
+
+// MissingFields returns the required fields of the issue type which a new issue doesn't have
+func (t *IssueType) MissingFields(values *IssueTypeFieldValues) []string {
+	var missing []string
+	for _, field := range t.RequiredFields {
+		var ok bool
+		switch field {
+		case IssueTypeFieldContent:
+			ok = strings.TrimSpace(values.Content) != "" && strings.TrimSpace(values.Content) != strings.TrimSpace(t.Template)
+		case IssueTypeFieldLabels:
+			ok = len(values.LabelIDs) > 0
+		case IssueTypeFieldMilestone:
+			ok = values.MilestoneID > 0
+		case IssueTypeFieldAssignees:
+			ok = len(values.AssigneeIDs) > 0
+		case IssueTypeFieldDeadline:
+			ok = values.DeadlineUnix > 0
+		default:
+			ok = true
+		}
+		if !ok {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// ValidateIssue returns an invalid argument error if a new issue doesn't have the required fields of the issue type
+func (t *IssueType) ValidateIssue(values *IssueTypeFieldValues) error {
+	if missing := t.MissingFields(values); len(missing) > 0 {
+		return util.NewInvalidArgumentErrorf("issues of type %q require %s", t.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// NewIssueType creates an issue type of a repository or an organization
+func NewIssueType(ctx context.Context, t *IssueType) error {
+	if (t.RepoID > 0) == (t.OrgID > 0) {
+		return util.NewInvalidArgumentErrorf("an issue type belongs to either a repository or an organization")
+	}
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkIssueTypeNameAvailable(ctx, t); err != nil {
+			return err
+		}
+		return db.Insert(ctx, t)
+	})
+}
+
+// UpdateIssueType updates the definition of an issue type
+func UpdateIssueType(ctx context.Context, t *IssueType) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := checkIssueTypeNameAvailable(ctx, t); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(t.ID).Cols("name", "description", "icon", "color", "template", "required_fields").Update(t)
+		return err
+	})
+}
+
+func checkIssueTypeNameAvailable(ctx context.Context, t *IssueType) error {
+	exist, err := db.GetEngine(ctx).Where("repo_id = ? AND org_id = ? AND id <> ?", t.RepoID, t.OrgID, t.ID).
+		And(builder.Eq{"LOWER(name)": strings.ToLower(t.Name)}).Exist(new(IssueType))
+	if err != nil {
+		return err
+	} else if exist {
+		return util.NewAlreadyExistErrorf("issue type %q already exists", t.Name)
+	}
+	return nil
+}
+
+// DeleteIssueType deletes an issue type, the issues of the type don't have a type anymore
+func DeleteIssueType(ctx context.Context, t *IssueType) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("type_id = ?", t.ID).Cols("type_id").NoAutoTime().Update(&Issue{TypeID: 0}); err != nil {
+			return err
+		}
+		_, err := db.DeleteByID[IssueType](ctx, t.ID)
+		return err
+	})
+}
+
+// GetIssueTypeByID returns the issue type with the given id
+func GetIssueTypeByID(ctx context.Context, id int64) (*IssueType, error) {
+	t, has, err := db.GetByID[IssueType](ctx, id)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("issue type %d does not exist", id)
+	}
+	return t, nil
+}
+
+// GetIssueTypesByRepoID returns the issue types defined by a repository
+func GetIssueTypesByRepoID(ctx context.Context, repoID int64) ([]*IssueType, error) {
+	types := make([]*IssueType, 0, 5)
+	return types, db.GetEngine(ctx).Where("repo_id = ?", repoID).Asc("name").Find(&types)
+}
+
+// GetIssueTypesByOrgID returns the issue types defined by an organization
+func GetIssueTypesByOrgID(ctx context.Context, orgID int64) ([]*IssueType, error) {
+	types := make([]*IssueType, 0, 5)
+	return types, db.GetEngine(ctx).Where("org_id = ?", orgID).Asc("name").Find(&types)
+}
+
+// GetIssueTypesForRepo returns the issue types which the issues of a repository can have,
+// the ones of the repository and the ones of its owner if it's an organization
+func GetIssueTypesForRepo(ctx context.Context, repoID, ownerID int64) ([]*IssueType, error) {
+	types := make([]*IssueType, 0, 5)
+	return types, db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": repoID}.Or(builder.Eq{"org_id": ownerID})).
+		Asc("name").Asc("id").
+		Find(&types)
+}
+
+// GetIssueTypeForRepoByID returns the issue type with the given id if the issues of the repository can have it
+func GetIssueTypeForRepoByID(ctx context.Context, repoID, ownerID, id int64) (*IssueType, error) {
+	t := new(IssueType)
+	has, err := db.GetEngine(ctx).ID(id).Where(builder.Eq{"repo_id": repoID}.Or(builder.Eq{"org_id": ownerID})).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("issue type %d does not exist", id)
+	}
+	return t, nil
+}
+
+// GetIssueTypeForRepoByName returns the issue type with the given name if the issues of the repository can have it,
+// the type of the repository is preferred over the type of its owner with the same name
+func GetIssueTypeForRepoByName(ctx context.Context, repoID, ownerID int64, name string) (*IssueType, error) {
+	t := new(IssueType)
+	has, err := db.GetEngine(ctx).Where(builder.Eq{"repo_id": repoID}.Or(builder.Eq{"org_id": ownerID})).
+		And(builder.Eq{"LOWER(name)": strings.ToLower(strings.TrimSpace(name))}).
+		Desc("repo_id").Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("issue type %q does not exist", name)
+	}
+	return t, nil
+}
+
+// LoadType loads the type of the issue
+func (issue *Issue) LoadType(ctx context.Context) (err error) {
+	if issue.TypeID == 0 || (issue.Type != nil && issue.Type.ID == issue.TypeID) {
+		return nil
+	}
+	issue.Type, err = GetIssueTypeByID(ctx, issue.TypeID)
+	if err != nil && errors.Is(err, util.ErrNotExist) {
+		issue.Type, err = nil, nil
+	}
+	return err
+}
+
+// ChangeIssueType changes the type of an issue, the issue doesn't have a type if the type is nil
+func ChangeIssueType(ctx context.Context, issue *Issue, t *IssueType) error {
+	issue.TypeID, issue.Type = 0, nil
+	if t != nil {
+		issue.TypeID, issue.Type = t.ID, t
+	}
+	_, err := db.GetEngine(ctx).ID(issue.ID).Cols("type_id").Update(issue)
+	return err
+}
+
+// LoadTypes loads the types of the issues
+func (issues IssueList) LoadTypes(ctx context.Context) error {
+	typeIDs := container.FilterSlice(issues, func(issue *Issue) (int64, bool) {
+		return issue.TypeID, issue.TypeID > 0
+	})
+	if len(typeIDs) == 0 {
+		return nil
+	}
+
+	types := make(map[int64]*IssueType, len(typeIDs))
+	if err := db.GetEngine(ctx).In("id", typeIDs).Find(&types); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		issue.Type = types[issue.TypeID]
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueType_Validate(t *testing.T) {
+	issueType := &issues_model.IssueType{Name: " Bug ", Icon: "octicon-bug", Color: "#ee0701", RequiredFields: []string{"labels", ""}}
+	assert.NoError(t, issueType.Validate())
+	assert.Equal(t, "Bug", issueType.Name)
+	assert.Equal(t, []string{"labels"}, issueType.RequiredFields)
+
+	assert.ErrorIs(t, (&issues_model.IssueType{Name: " "}).Validate(), util.ErrInvalidArgument)
+	assert.ErrorIs(t, (&issues_model.IssueType{Name: "Bug", Icon: "bug"}).Validate(), util.ErrInvalidArgument)
+	assert.ErrorIs(t, (&issues_model.IssueType{Name: "Bug", Color: "red"}).Validate(), util.ErrInvalidArgument)
+	assert.ErrorIs(t, (&issues_model.IssueType{Name: "Bug", RequiredFields: []string{"title"}}).Validate(), util.ErrInvalidArgument)
+}
+
+func TestIssueType_MissingFields(t *testing.T) {
+	issueType := &issues_model.IssueType{
+		Name:           "Bug",
+		Template:       "## Steps to reproduce",
+		RequiredFields: []string{issues_model.IssueTypeFieldContent, issues_model.IssueTypeFieldLabels, issues_model.IssueTypeFieldMilestone},
+	}
+	assert.Equal(t, []string{"content", "labels", "milestone"}, issueType.MissingFields(&issues_model.IssueTypeFieldValues{}))
+	assert.Equal(t, []string{"content", "milestone"}, issueType.MissingFields(&issues_model.IssueTypeFieldValues{
+		Content:  "## Steps to reproduce\n",
+		LabelIDs: []int64{1},
+	}))
+	assert.Empty(t, issueType.MissingFields(&issues_model.IssueTypeFieldValues{
+		Content:     "## Steps to reproduce\n1. open the page",
+		LabelIDs:    []int64{1},
+		MilestoneID: 1,
+	}))
+	assert.ErrorIs(t, issueType.ValidateIssue(&issues_model.IssueTypeFieldValues{}), util.ErrInvalidArgument)
+}
+
+func TestNewIssueType(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repoType := &issues_model.IssueType{RepoID: 3, Name: "Bug"}
+	assert.NoError(t, issues_model.NewIssueType(db.DefaultContext, repoType))
+	orgType := &issues_model.IssueType{OrgID: 3, Name: "Epic"}
+	assert.NoError(t, issues_model.NewIssueType(db.DefaultContext, orgType))
+	assert.NoError(t, issues_model.NewIssueType(db.DefaultContext, &issues_model.IssueType{RepoID: 1, Name: "Task"}))
+
+	assert.ErrorIs(t, issues_model.NewIssueType(db.DefaultContext, &issues_model.IssueType{RepoID: 3, Name: "bug"}), util.ErrAlreadyExist)
+	assert.ErrorIs(t, issues_model.NewIssueType(db.DefaultContext, &issues_model.IssueType{Name: "Feature"}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, issues_model.NewIssueType(db.DefaultContext, &issues_model.IssueType{RepoID: 3, OrgID: 3, Name: "Feature"}), util.ErrInvalidArgument)
+
+	types, err := issues_model.GetIssueTypesForRepo(db.DefaultContext, 3, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, types, 2) {
+		assert.Equal(t, "Bug", types[0].Name)
+		assert.Equal(t, "Epic", types[1].Name)
+	}
+
+	issueType, err := issues_model.GetIssueTypeForRepoByName(db.DefaultContext, 3, 3, "epic")
+	assert.NoError(t, err)
+	assert.Equal(t, orgType.ID, issueType.ID)
+
+	_, err = issues_model.GetIssueTypeForRepoByID(db.DefaultContext, 1, 1, repoType.ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+}
+
+func TestChangeAndDeleteIssueType(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issueType := &issues_model.IssueType{RepoID: 3, Name: "Bug"}
+	assert.NoError(t, issues_model.NewIssueType(db.DefaultContext, issueType))
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+	assert.NoError(t, issues_model.ChangeIssueType(db.DefaultContext, issue, issueType))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6, TypeID: issueType.ID})
+
+	issues, err := issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{RepoIDs: []int64{3}, TypeIDs: []int64{issueType.ID}})
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 6, issues[0].ID)
+	}
+
+	assert.NoError(t, issues_model.DeleteIssueType(db.DefaultContext, issueType))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueType{ID: issueType.ID})
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+	assert.EqualValues(t, 0, issue.TypeID)
+	assert.NoError(t, issue.LoadType(db.DefaultContext))
+	assert.Nil(t, issue.Type)
+}
@@ -97,11 +97,23 @@ func doChangeIssueStatus(ctx context.Context, issue *Issue, doer *user_model.Use
 		}
 	}
 
+	// Update the progress of the parent's milestone
+	if err := updateMilestoneCountersOfParentIssue(ctx, issue.ID); err != nil {
+		return nil, err
+	}
+
 	// update repository's issue closed number
 	if err := repo_model.UpdateRepoIssueNumbers(ctx, issue.RepoID, issue.IsPull, true); err != nil {
 		return nil, err
 	}
 
+	// Let the issues blocked by this one know that it's closed
+	if issue.IsClosed {
+		if err := createDependencyClosedComments(ctx, doer, issue); err != nil {
+			return nil, err
+		}
+	}
+
 	// New action comment
 	cmtType := CommentTypeClose
 	if !issue.IsClosed {
@@ -291,7 +303,7 @@ func NewIssueWithIndex(ctx context.Context, doer *user_model.User, opts NewIssue
 	opts.Issue.Title = strings.TrimSpace(opts.Issue.Title)
 
 	if opts.Issue.MilestoneID > 0 {
-		milestone, err := GetMilestoneByRepoID(ctx, opts.Issue.RepoID, opts.Issue.MilestoneID)
+		milestone, err := GetMilestoneForRepo(ctx, opts.Issue.RepoID, opts.Issue.MilestoneID)
 		if err != nil && !IsErrMilestoneNotExist(err) {
 			return fmt.Errorf("getMilestoneByID: %w", err)
 		}
@@ -683,6 +695,22 @@ func DeleteIssuesByRepoID(ctx context.Context, repoID int64) (attachmentPaths []
 			return nil, err
 		}
 
+		_, err = sess.In("issue_id", issueIDs).Delete(&IssueFieldValue{})
+		if err != nil {
+			return nil, err
+		}
+
+		// Delete the relations to sub-issues and parents, which may be in other repositories
+		_, err = sess.In("parent_id", issueIDs).Delete(&IssueRelation{})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = sess.In("child_id", issueIDs).Delete(&IssueRelation{})
+		if err != nil {
+			return nil, err
+		}
+
 		_, err = sess.In("issue_id", issueIDs).Delete(&IssueUser{})
 		if err != nil {
 			return nil, err
@@ -698,6 +726,11 @@ func DeleteIssuesByRepoID(ctx context.Context, repoID int64) (attachmentPaths []
 			return nil, err
 		}
 
+		_, err = sess.In("issue_id", issueIDs).Delete(&IssueVote{})
+		if err != nil {
+			return nil, err
+		}
+
 		_, err = sess.In("issue_id", issueIDs).Delete(&Stopwatch{})
 		if err != nil {
 			return nil, err
@@ -0,0 +1,74 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueVote represents the vote of a user for an issue.
+// Votes are counted separately from the reactions and are used to rank the backlog.
+type IssueVote struct {
+	ID          int64              `xorm:"pk autoincr"`
+	IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	UserID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueVote))
+}
+
+// HasVotedForIssue returns true if the user has voted for the issue
+func HasVotedForIssue(ctx context.Context, userID, issueID int64) (bool, error) {
+	return db.GetEngine(ctx).Exist(&IssueVote{IssueID: issueID, UserID: userID})
+}
+
+// CreateIssueVote adds the vote of a user for an issue, voting twice is a no-op
+func CreateIssueVote(ctx context.Context, userID, issueID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		voted, err := HasVotedForIssue(ctx, userID, issueID)
+		if err != nil || voted {
+			return err
+		}
+		if err := db.Insert(ctx, &IssueVote{IssueID: issueID, UserID: userID}); err != nil {
+			return err
+		}
+		return updateIssueNumVotes(ctx, issueID)
+	})
+}
+
+// DeleteIssueVote removes the vote of a user for an issue
+func DeleteIssueVote(ctx context.Context, userID, issueID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		deleted, err := db.GetEngine(ctx).Delete(&IssueVote{IssueID: issueID, UserID: userID})
+		if err != nil || deleted == 0 {
+			return err
+		}
+		return updateIssueNumVotes(ctx, issueID)
+	})
+}
+
+// GetIssueVoters returns the users who voted for the issue, the most recent votes first
+func GetIssueVoters(ctx context.Context, issueID int64, listOptions db.ListOptions) ([]*user_model.User, int64, error) {
+	sess := db.GetEngine(ctx).
+		Join("INNER", "issue_vote", "`user`.id = issue_vote.user_id").
+		Where("issue_vote.issue_id = ?", issueID).
+		OrderBy("issue_vote.created_unix DESC, issue_vote.id DESC")
+	if listOptions.Page > 0 {
+		sess = db.SetSessionPagination(sess, &listOptions)
+	}
+	users := make([]*user_model.User, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&users)
+	return users, count, err
+}
+
+func updateIssueNumVotes(ctx context.Context, issueID int64) error {
+	_, err := db.Exec(ctx, "UPDATE `issue` SET num_votes = (SELECT COUNT(*) FROM issue_vote WHERE issue_id = ?) WHERE id = ?", issueID, issueID)
+	return err
+}
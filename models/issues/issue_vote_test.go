@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueVotes(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, issues_model.CreateIssueVote(db.DefaultContext, 2, 1))
+	assert.NoError(t, issues_model.CreateIssueVote(db.DefaultContext, 4, 1))
+	// voting twice doesn't count
+	assert.NoError(t, issues_model.CreateIssueVote(db.DefaultContext, 4, 1))
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.EqualValues(t, 2, issue.NumVotes)
+
+	voted, err := issues_model.HasVotedForIssue(db.DefaultContext, 4, 1)
+	assert.NoError(t, err)
+	assert.True(t, voted)
+
+	voters, count, err := issues_model.GetIssueVoters(db.DefaultContext, 1, db.ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, voters, 2) {
+		assert.EqualValues(t, 4, voters[0].ID)
+		assert.EqualValues(t, 2, voters[1].ID)
+	}
+
+	assert.NoError(t, issues_model.DeleteIssueVote(db.DefaultContext, 4, 1))
+	// removing a vote which doesn't exist is a no-op
+	assert.NoError(t, issues_model.DeleteIssueVote(db.DefaultContext, 4, 1))
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.EqualValues(t, 1, issue.NumVotes)
+	unittest.AssertNotExistsBean(t, &issues_model.IssueVote{UserID: 4, IssueID: 1})
+}
+
+func TestIssuesSortedByVotes(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, issues_model.CreateIssueVote(db.DefaultContext, 2, 3))
+	assert.NoError(t, issues_model.CreateIssueVote(db.DefaultContext, 4, 3))
+	assert.NoError(t, issues_model.CreateIssueVote(db.DefaultContext, 2, 2))
+
+	issues, err := issues_model.Issues(db.DefaultContext, &issues_model.IssuesOptions{
+		RepoIDs:  []int64{1},
+		SortType: "mostvotes",
+	})
+	assert.NoError(t, err)
+	if assert.GreaterOrEqual(t, len(issues), 2) {
+		assert.EqualValues(t, 3, issues[0].ID)
+		assert.EqualValues(t, 2, issues[1].ID)
+	}
+}
@@ -138,13 +138,15 @@ func (issue *Issue) getCrossReferences(stdCtx context.Context, ctx *crossReferen
 		err       error
 	)
 
-	allrefs := append(references.FindAllIssueReferences(plaincontent), references.FindAllIssueReferencesMarkdown(mdcontent)...)
+	if err := ctx.OrigIssue.LoadRepo(stdCtx); err != nil {
+		return nil, err
+	}
+	// the keywords are the ones of the repository the references are written in
+	keywords := ctx.OrigIssue.Repo.IssueKeywords(stdCtx)
+	allrefs := append(keywords.FindAllIssueReferences(plaincontent), keywords.FindAllIssueReferencesMarkdown(mdcontent)...)
 	for _, ref := range allrefs {
 		if ref.Owner == "" && ref.Name == "" {
 			// Issues in the same repository
-			if err := ctx.OrigIssue.LoadRepo(stdCtx); err != nil {
-				return nil, err
-			}
 			refRepo = ctx.OrigIssue.Repo
 		} else {
 			// Issues in other repositories
@@ -235,7 +237,8 @@ func (issue *Issue) verifyReferencedIssue(stdCtx context.Context, ctx *crossRefe
 
 // AddCrossReferences add cross references
 func (c *Comment) AddCrossReferences(stdCtx context.Context, doer *user_model.User, removeOld bool) error {
-	if c.Type != CommentTypeCode && c.Type != CommentTypeComment {
+	// the internal comments don't reference other issues, whose timelines would reveal them
+	if (c.Type != CommentTypeCode && c.Type != CommentTypeComment) || c.IsInternal {
 		return nil
 	}
 	if err := c.LoadIssue(stdCtx); err != nil {
@@ -356,5 +359,33 @@ func (pr *PullRequest) ResolveCrossReferences(ctx context.Context) ([]*Comment,
 		}
 	}
 
-	return refs, nil
+	return pr.filterCrossReferences(ctx, refs)
+}
+
+// filterCrossReferences removes the references which the issues config of the base repository doesn't allow to be resolved
+func (pr *PullRequest) filterCrossReferences(ctx context.Context, refs []*Comment) ([]*Comment, error) {
+	if len(refs) == 0 {
+		return refs, nil
+	}
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+	cfg := pr.BaseRepo.GetIssuesConfig(ctx)
+	if cfg.CloseOnlyOnDefaultBranch && pr.BaseBranch != pr.BaseRepo.DefaultBranch {
+		return []*Comment{}, nil
+	}
+	if !cfg.CloseOnlyInSameMilestone {
+		return refs, nil
+	}
+
+	filtered := make([]*Comment, 0, len(refs))
+	for _, ref := range refs {
+		if err := ref.LoadIssue(ctx); err != nil {
+			return nil, err
+		}
+		if ref.Issue.MilestoneID == pr.Issue.MilestoneID {
+			filtered = append(filtered, ref)
+		}
+	}
+	return filtered, nil
 }
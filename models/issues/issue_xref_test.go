@@ -10,6 +10,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/references"
@@ -126,6 +127,50 @@ func TestXRef_ResolveCrossReferences(t *testing.T) {
 	assert.Equal(t, r4.ID, refs[2].ID, "bad ref r4: %+v", refs[2])
 }
 
+func TestXRef_IssuesConfig(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	issuesUnit, err := repo.GetUnit(db.DefaultContext, unit.TypeIssues)
+	assert.NoError(t, err)
+	cfg := issuesUnit.IssuesConfig()
+	cfg.CloseKeywords = []string{"done"}
+	cfg.KeywordLanguages = []string{"de"}
+	assert.NoError(t, repo_model.UpdateRepoUnit(db.DefaultContext, issuesUnit))
+
+	i1 := testCreateIssue(t, 1, 2, "title1", "content1", false)
+	i2 := testCreateIssue(t, 1, 2, "title2", "content2", false)
+	i3 := testCreateIssue(t, 1, 2, "title3", "content3", false)
+	pr := testCreatePR(t, 1, 2, "titlepr", fmt.Sprintf("done #%d, behebt #%d, fixes #%d", i1.Index, i2.Index, i3.Index))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: i1.ID, RefIssueID: pr.Issue.ID, RefAction: references.XRefActionCloses})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: i2.ID, RefIssueID: pr.Issue.ID, RefAction: references.XRefActionCloses})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: i3.ID, RefIssueID: pr.Issue.ID, RefAction: references.XRefActionNone})
+
+	refs, err := pr.ResolveCrossReferences(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Len(t, refs, 2)
+
+	// only the issues without milestone are in the same milestone as the pull request
+	_, err = db.GetEngine(db.DefaultContext).ID(i1.ID).Cols("milestone_id").Update(&issues_model.Issue{MilestoneID: 1})
+	assert.NoError(t, err)
+	cfg.CloseOnlyInSameMilestone = true
+	assert.NoError(t, repo_model.UpdateRepoUnit(db.DefaultContext, issuesUnit))
+	pr.BaseRepo, pr.Issue.Repo = nil, nil
+	refs, err = pr.ResolveCrossReferences(db.DefaultContext)
+	assert.NoError(t, err)
+	if assert.Len(t, refs, 1) {
+		assert.Equal(t, i2.ID, refs[0].IssueID)
+	}
+
+	// the pull request isn't merged into the default branch
+	cfg.CloseOnlyOnDefaultBranch = true
+	assert.NoError(t, repo_model.UpdateRepoUnit(db.DefaultContext, issuesUnit))
+	pr.BaseRepo, pr.Issue.Repo = nil, nil
+	refs, err = pr.ResolveCrossReferences(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+}
+
 func testCreateIssue(t *testing.T, repo, doer int64, title, content string, ispull bool) *issues_model.Issue {
 	r := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repo})
 	d := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: doer})
@@ -23,6 +23,7 @@ import (
 type ErrMilestoneNotExist struct {
 	ID     int64
 	RepoID int64
+	OrgID  int64
 	Name   string
 }
 
@@ -33,6 +34,12 @@ func IsErrMilestoneNotExist(err error) bool {
 }
 
 func (err ErrMilestoneNotExist) Error() string {
+	if err.OrgID > 0 {
+		if len(err.Name) > 0 {
+			return fmt.Sprintf("milestone does not exist [name: %s, org_id: %d]", err.Name, err.OrgID)
+		}
+		return fmt.Sprintf("milestone does not exist [id: %d, org_id: %d]", err.ID, err.OrgID)
+	}
 	if len(err.Name) > 0 {
 		return fmt.Sprintf("milestone does not exist [name: %s, repo_id: %d]", err.Name, err.RepoID)
 	}
@@ -43,11 +50,12 @@ func (err ErrMilestoneNotExist) Unwrap() error {
 	return util.ErrNotExist
 }
 
-// Milestone represents a milestone of repository.
+// Milestone represents a milestone of a repository, or of an organization for the issues of all its repositories.
 type Milestone struct {
 	ID              int64                  `xorm:"pk autoincr"`
 	RepoID          int64                  `xorm:"INDEX"`
 	Repo            *repo_model.Repository `xorm:"-"`
+	OrgID           int64                  `xorm:"INDEX NOT NULL DEFAULT 0"`
 	Name            string
 	Content         string        `xorm:"TEXT"`
 	RenderedContent template.HTML `xorm:"-"`
@@ -96,6 +104,11 @@ func (m *Milestone) AfterLoad() {
 	}
 }
 
+// BelongsToOrg returns true if the milestone is defined by an organization
+func (m *Milestone) BelongsToOrg() bool {
+	return m.OrgID > 0
+}
+
 // State returns string representation of milestone status.
 func (m *Milestone) State() api.StateType {
 	if m.IsClosed {
@@ -118,8 +131,10 @@ func NewMilestone(ctx context.Context, m *Milestone) (err error) {
 		return err
 	}
 
-	if _, err = db.Exec(ctx, "UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
-		return err
+	if m.RepoID > 0 {
+		if _, err = db.Exec(ctx, "UPDATE `repository` SET num_milestones = num_milestones + 1 WHERE id = ?", m.RepoID); err != nil {
+			return err
+		}
 	}
 	return committer.Commit()
 }
@@ -141,6 +156,52 @@ func GetMilestoneByRepoID(ctx context.Context, repoID, id int64) (*Milestone, er
 	return m, nil
 }
 
+// GetMilestoneByOrgID returns the milestone of an organization.
+func GetMilestoneByOrgID(ctx context.Context, orgID, id int64) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := db.GetEngine(ctx).ID(id).Where("org_id=?", orgID).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{ID: id, OrgID: orgID}
+	}
+	return m, nil
+}
+
+// milestoneOfRepoCond selects the milestones of a repository and of the organization owning it
+func milestoneOfRepoCond(repoID int64) builder.Cond {
+	return builder.Eq{"repo_id": repoID}.Or(
+		builder.Gt{"org_id": 0}.And(builder.In("org_id", builder.Select("owner_id").From("repository").Where(builder.Eq{"id": repoID}))),
+	)
+}
+
+// GetMilestoneForRepo returns a milestone which the issues of a repository can have,
+// it belongs to the repository or to the organization owning it.
+func GetMilestoneForRepo(ctx context.Context, repoID, id int64) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := db.GetEngine(ctx).ID(id).Where(milestoneOfRepoCond(repoID)).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{ID: id, RepoID: repoID}
+	}
+	return m, nil
+}
+
+// GetMilestoneForRepoByName returns a milestone by name which the issues of a repository can have,
+// the milestones of the repository take precedence over the ones of its organization.
+func GetMilestoneForRepoByName(ctx context.Context, repoID int64, name string) (*Milestone, error) {
+	var mile Milestone
+	has, err := db.GetEngine(ctx).Where(milestoneOfRepoCond(repoID)).And("name=?", name).OrderBy("org_id ASC").Get(&mile)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrMilestoneNotExist{Name: name, RepoID: repoID}
+	}
+	return &mile, nil
+}
+
 // GetMilestoneByRepoIDANDName return a milestone if one exist by name and repo
 func GetMilestoneByRepoIDANDName(ctx context.Context, repoID int64, name string) (*Milestone, error) {
 	var mile Milestone
@@ -171,7 +232,7 @@ func UpdateMilestone(ctx context.Context, m *Milestone, oldIsClosed bool) error
 	}
 
 	// if IsClosed changed, update milestone numbers of repository
-	if oldIsClosed != m.IsClosed {
+	if oldIsClosed != m.IsClosed && m.RepoID > 0 {
 		if err := updateRepoMilestoneNum(ctx, m.RepoID); err != nil {
 			return err
 		}
@@ -206,9 +267,19 @@ func UpdateMilestoneCounters(ctx context.Context, id int64) error {
 	if err != nil {
 		return err
 	}
-	_, err = e.Exec("UPDATE `milestone` SET completeness=100*num_closed_issues/(CASE WHEN num_issues > 0 THEN num_issues ELSE 1 END) WHERE id=?",
-		id,
-	)
+
+	// the open issues with sub-issues count as partially completed
+	m := new(Milestone)
+	if _, err := e.ID(id).Cols("num_issues", "num_closed_issues").Get(m); err != nil {
+		return err
+	}
+	rollup, err := getMilestoneSubIssueRollup(ctx, id)
+	if err != nil {
+		return err
+	}
+	// update with a map since BeforeUpdate would overwrite the completeness
+	completeness := int(100 * (float64(m.NumClosedIssues) + rollup) / float64(max(m.NumIssues, 1)))
+	_, err = e.Table("milestone").Where("id = ?", id).NoAutoTime().Update(map[string]any{"completeness": completeness})
 	return err
 }
 
@@ -264,7 +335,7 @@ func changeMilestoneStatus(ctx context.Context, m *Milestone, isClosed bool) err
 	if err != nil {
 		return err
 	}
-	if count < 1 {
+	if count < 1 || m.RepoID == 0 {
 		return nil
 	}
 	return updateRepoMilestoneNum(ctx, m.RepoID)
@@ -321,6 +392,25 @@ func DeleteMilestoneByRepoID(ctx context.Context, repoID, id int64) error {
 	return committer.Commit()
 }
 
+// DeleteMilestoneByOrgID deletes a milestone of an organization and removes it from the issues.
+func DeleteMilestoneByOrgID(ctx context.Context, orgID, id int64) error {
+	m, err := GetMilestoneByOrgID(ctx, orgID, id)
+	if err != nil {
+		if IsErrMilestoneNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.DeleteByID[Milestone](ctx, m.ID); err != nil {
+			return err
+		}
+		_, err := db.Exec(ctx, "UPDATE `issue` SET milestone_id = 0 WHERE milestone_id = ?", m.ID)
+		return err
+	})
+}
+
 func updateRepoMilestoneNum(ctx context.Context, repoID int64) error {
 	_, err := db.GetEngine(ctx).Exec("UPDATE `repository` SET num_milestones=(SELECT count(*) FROM milestone WHERE repo_id=?),num_closed_milestones=(SELECT count(*) FROM milestone WHERE repo_id=? AND is_closed=?) WHERE id=?",
 		repoID,
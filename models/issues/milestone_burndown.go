@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// maxMilestoneBurndownDays limits the length of a burndown, the most recent days are kept
+const maxMilestoneBurndownDays = 366
+
+// MilestoneBurndownDay represents the state of the issues of a milestone at the end of a day
+type MilestoneBurndownDay struct {
+	Day          time.Time
+	OpenIssues   int
+	ClosedIssues int
+}
+
+// MilestoneRepoProgress represents the issues of a milestone in one repository
+type MilestoneRepoProgress struct {
+	RepoID       int64
+	OpenIssues   int
+	ClosedIssues int
+}
+
+// MilestoneBurndown represents the progress of a milestone over time and per repository,
+// it aggregates the issues of all the repositories for the milestones of an organization
+type MilestoneBurndown struct {
+	Days  []*MilestoneBurndownDay
+	Repos []*MilestoneRepoProgress
+}
+
+// GetMilestoneBurndown computes the daily burndown of a milestone from its creation until the given time,
+// or until it was closed. The days start at midnight in the location of until.
+// Only the issues of the repositories matching repoCond are counted, all of them if it's nil.
+func GetMilestoneBurndown(ctx context.Context, m *Milestone, repoCond builder.Cond, until time.Time) (*MilestoneBurndown, error) {
+	type milestoneIssue struct {
+		RepoID      int64
+		IsClosed    bool
+		CreatedUnix timeutil.TimeStamp
+		ClosedUnix  timeutil.TimeStamp
+	}
+	issues := make([]*milestoneIssue, 0, m.NumIssues)
+	sess := db.GetEngine(ctx).Table("issue").Where("milestone_id = ?", m.ID)
+	if repoCond != nil {
+		sess.And(builder.In("repo_id", builder.Select("id").From("repository").Where(repoCond)))
+	}
+	if err := sess.
+		Cols("repo_id", "is_closed", "created_unix", "closed_unix").
+		Find(&issues); err != nil {
+		return nil, err
+	}
+
+	burndown := &MilestoneBurndown{}
+
+	repos := make(map[int64]*MilestoneRepoProgress)
+	start := m.CreatedUnix
+	for _, issue := range issues {
+		progress, ok := repos[issue.RepoID]
+		if !ok {
+			progress = &MilestoneRepoProgress{RepoID: issue.RepoID}
+			repos[issue.RepoID] = progress
+			burndown.Repos = append(burndown.Repos, progress)
+		}
+		if issue.IsClosed {
+			progress.ClosedIssues++
+		} else {
+			progress.OpenIssues++
+		}
+		if issue.CreatedUnix < start {
+			start = issue.CreatedUnix
+		}
+	}
+	sort.Slice(burndown.Repos, func(i, j int) bool {
+		return burndown.Repos[i].RepoID < burndown.Repos[j].RepoID
+	})
+
+	end := until
+	if m.IsClosed && m.ClosedDateUnix > 0 && m.ClosedDateUnix.AsTime().Before(end) {
+		end = m.ClosedDateUnix.AsTime()
+	}
+	loc := until.Location()
+	startTime := start.AsTimeInLocation(loc)
+	day := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, loc)
+	if first := end.AddDate(0, 0, 1-maxMilestoneBurndownDays); day.Before(first) {
+		day = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	}
+
+	for ; !day.After(end); day = day.AddDate(0, 0, 1) {
+		endOfDay := timeutil.TimeStamp(day.AddDate(0, 0, 1).Unix())
+		point := &MilestoneBurndownDay{Day: day}
+		for _, issue := range issues {
+			if issue.CreatedUnix >= endOfDay {
+				continue
+			}
+			if issue.IsClosed && issue.ClosedUnix < endOfDay {
+				point.ClosedIssues++
+			} else {
+				point.OpenIssues++
+			}
+		}
+		burndown.Days = append(burndown.Days, point)
+	}
+	return burndown, nil
+}
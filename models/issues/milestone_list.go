@@ -27,7 +27,10 @@ func (milestones MilestoneList) getMilestoneIDs() []int64 {
 // FindMilestoneOptions contain options to get milestones
 type FindMilestoneOptions struct {
 	db.ListOptions
-	RepoID   int64
+	RepoID int64
+	// OrgID selects the milestones of the organization, combined with RepoID
+	// it selects the milestones usable by the issues of a repository of the organization
+	OrgID    int64
 	IsClosed optional.Option[bool]
 	Name     string
 	SortType string
@@ -37,8 +40,12 @@ type FindMilestoneOptions struct {
 
 func (opts FindMilestoneOptions) ToConds() builder.Cond {
 	cond := builder.NewCond()
-	if opts.RepoID != 0 {
+	if opts.RepoID != 0 && opts.OrgID != 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID}.Or(builder.Eq{"org_id": opts.OrgID}))
+	} else if opts.RepoID != 0 {
 		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	} else if opts.OrgID != 0 {
+		cond = cond.And(builder.Eq{"org_id": opts.OrgID})
 	}
 	if opts.IsClosed.Has() {
 		cond = cond.And(builder.Eq{"is_closed": opts.IsClosed.Value()})
@@ -6,6 +6,7 @@ package issues_test
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -17,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
 )
 
 func TestMilestone_State(t *testing.T) {
@@ -319,6 +321,97 @@ func TestDeleteMilestoneByRepoID(t *testing.T) {
 	assert.NoError(t, issues_model.DeleteMilestoneByRepoID(db.DefaultContext, unittest.NonexistentID, unittest.NonexistentID))
 }
 
+func TestOrgMilestone(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// repository 3 is owned by the organization 3
+	milestone := &issues_model.Milestone{
+		OrgID: 3,
+		Name:  "orgMilestone",
+	}
+	assert.NoError(t, issues_model.NewMilestone(db.DefaultContext, milestone))
+	assert.True(t, milestone.BelongsToOrg())
+	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: 3})
+
+	m, err := issues_model.GetMilestoneByOrgID(db.DefaultContext, 3, milestone.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "orgMilestone", m.Name)
+	_, err = issues_model.GetMilestoneByRepoID(db.DefaultContext, 3, milestone.ID)
+	assert.True(t, issues_model.IsErrMilestoneNotExist(err))
+
+	m, err = issues_model.GetMilestoneForRepo(db.DefaultContext, 3, milestone.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, milestone.ID, m.ID)
+	m, err = issues_model.GetMilestoneForRepoByName(db.DefaultContext, 3, "orgMilestone")
+	assert.NoError(t, err)
+	assert.EqualValues(t, milestone.ID, m.ID)
+	_, err = issues_model.GetMilestoneForRepo(db.DefaultContext, 1, milestone.ID)
+	assert.True(t, issues_model.IsErrMilestoneNotExist(err))
+
+	milestones, err := db.Find[issues_model.Milestone](db.DefaultContext, issues_model.FindMilestoneOptions{RepoID: 3, OrgID: 3})
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 1)
+	milestones, err = db.Find[issues_model.Milestone](db.DefaultContext, issues_model.FindMilestoneOptions{RepoID: 1, OrgID: 3})
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 4)
+
+	assert.NoError(t, issues_model.ChangeMilestoneStatus(db.DefaultContext, milestone, true))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: milestone.ID}, "is_closed=1")
+	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: 3})
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+	issue.MilestoneID = milestone.ID
+	assert.NoError(t, issues_model.UpdateIssueCols(db.DefaultContext, issue, "milestone_id"))
+	assert.NoError(t, issues_model.UpdateMilestoneCounters(db.DefaultContext, milestone.ID))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: milestone.ID, NumIssues: 1})
+
+	assert.NoError(t, issues_model.DeleteMilestoneByOrgID(db.DefaultContext, 3, milestone.ID))
+	unittest.AssertNotExistsBean(t, &issues_model.Milestone{ID: milestone.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6, MilestoneID: 0})
+}
+
+func TestGetMilestoneBurndown(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	milestone := &issues_model.Milestone{OrgID: 3, Name: "burndown"}
+	assert.NoError(t, issues_model.NewMilestone(db.DefaultContext, milestone))
+
+	// issue 6 of repository 3 was created on 2000-01-01
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6})
+	issue.MilestoneID = milestone.ID
+	assert.NoError(t, issues_model.UpdateIssueCols(db.DefaultContext, issue, "milestone_id"))
+	milestone.CreatedUnix = issue.CreatedUnix
+	issue.IsClosed = true
+	issue.ClosedUnix = issue.CreatedUnix + 2*24*3600
+	assert.NoError(t, issues_model.UpdateIssueCols(db.DefaultContext, issue, "is_closed", "closed_unix"))
+
+	until := time.Date(2000, 1, 5, 12, 0, 0, 0, time.UTC)
+	burndown, err := issues_model.GetMilestoneBurndown(db.DefaultContext, milestone, nil, until)
+	assert.NoError(t, err)
+	if assert.Len(t, burndown.Days, 5) {
+		assert.Equal(t, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), burndown.Days[0].Day)
+		assert.Equal(t, 1, burndown.Days[0].OpenIssues)
+		assert.Equal(t, 1, burndown.Days[1].OpenIssues)
+		assert.Equal(t, 0, burndown.Days[2].OpenIssues)
+		assert.Equal(t, 1, burndown.Days[2].ClosedIssues)
+		assert.Equal(t, 1, burndown.Days[4].ClosedIssues)
+	}
+	if assert.Len(t, burndown.Repos, 1) {
+		assert.EqualValues(t, 3, burndown.Repos[0].RepoID)
+		assert.Equal(t, 0, burndown.Repos[0].OpenIssues)
+		assert.Equal(t, 1, burndown.Repos[0].ClosedIssues)
+	}
+
+	// the issues of the other repositories aren't counted
+	burndown, err = issues_model.GetMilestoneBurndown(db.DefaultContext, milestone, builder.Eq{"id": 32}, until)
+	assert.NoError(t, err)
+	assert.Empty(t, burndown.Repos)
+	if assert.Len(t, burndown.Days, 5) {
+		assert.Equal(t, 0, burndown.Days[0].OpenIssues)
+		assert.Equal(t, 0, burndown.Days[4].ClosedIssues)
+	}
+}
+
 func TestUpdateMilestone(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 
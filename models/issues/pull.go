@@ -427,6 +427,11 @@ func (pr *PullRequest) GetGitRefName() string {
 	return fmt.Sprintf("%s%d/head", git.PullPrefix, pr.Index)
 }
 
+// GetGitPushRefName returns git ref keeping the head commit of the pull request from before the force push of the comment
+func (pr *PullRequest) GetGitPushRefName(commentID int64) string {
+	return fmt.Sprintf("%s%d/pushes/%d", git.PullPrefix, pr.Index, commentID)
+}
+
 func (pr *PullRequest) GetGitHeadBranchRefName() string {
 	return fmt.Sprintf("%s%s", git.BranchPrefix, pr.HeadBranch)
 }
@@ -935,7 +940,13 @@ func GetCodeOwnersFromContent(ctx context.Context, data string) ([]*CodeOwnerRul
 	return rules, warnings
 }
 
+// MatchFile returns whether the rule applies to the file
+func (rule *CodeOwnerRule) MatchFile(treePath string) bool {
+	return rule.Rule.MatchString(treePath) != rule.Negative
+}
+
 type CodeOwnerRule struct {
+	Pattern  string // the pattern as written in the CODEOWNERS file
 	Rule     *regexp.Regexp
 	Negative bool
 	Users    []*user_model.User
@@ -945,6 +956,7 @@ type CodeOwnerRule struct {
 func ParseCodeOwnersLine(ctx context.Context, tokens []string) (*CodeOwnerRule, []string) {
 	var err error
 	rule := &CodeOwnerRule{
+		Pattern:  tokens[0],
 		Users:    make([]*user_model.User, 0),
 		Teams:    make([]*org_model.Team, 0),
 		Negative: strings.HasPrefix(tokens[0], "!"),
@@ -57,12 +57,17 @@ func (err ErrReactionAlreadyExist) Unwrap() error {
 	return util.ErrAlreadyExist
 }
 
-// Reaction represents a reactions on issues and comments.
+// Reaction represents a reactions on issues, comments, resolved conversations and commits.
 type Reaction struct {
-	ID               int64              `xorm:"pk autoincr"`
-	Type             string             `xorm:"INDEX UNIQUE(s) NOT NULL"`
-	IssueID          int64              `xorm:"INDEX UNIQUE(s) NOT NULL"`
-	CommentID        int64              `xorm:"INDEX UNIQUE(s)"`
+	ID        int64  `xorm:"pk autoincr"`
+	Type      string `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	IssueID   int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	CommentID int64  `xorm:"INDEX UNIQUE(s)"`
+	// IsResolution is set for the reactions on the resolution of the conversation started by the code comment CommentID
+	IsResolution bool `xorm:"UNIQUE(s) NOT NULL DEFAULT false"`
+	// RepoID and CommitSHA are only set for the reactions on a commit, whose IssueID is 0
+	RepoID           int64              `xorm:"INDEX UNIQUE(s) NOT NULL DEFAULT(0)"`
+	CommitSHA        string             `xorm:"VARCHAR(64) UNIQUE(s) NOT NULL DEFAULT('')"`
 	UserID           int64              `xorm:"INDEX UNIQUE(s) NOT NULL"`
 	OriginalAuthorID int64              `xorm:"INDEX UNIQUE(s) NOT NULL DEFAULT(0)"`
 	OriginalAuthor   string             `xorm:"INDEX UNIQUE(s)"`
@@ -107,10 +112,13 @@ func init() {
 // FindReactionsOptions describes the conditions to Find reactions
 type FindReactionsOptions struct {
 	db.ListOptions
-	IssueID   int64
-	CommentID int64
-	UserID    int64
-	Reaction  string
+	IssueID      int64
+	CommentID    int64
+	IsResolution bool
+	RepoID       int64
+	CommitSHA    string
+	UserID       int64
+	Reaction     string
 }
 
 func (opts *FindReactionsOptions) toConds() builder.Cond {
@@ -127,6 +135,14 @@ func (opts *FindReactionsOptions) toConds() builder.Cond {
 	} else if opts.CommentID == -1 {
 		cond = cond.And(builder.Eq{"reaction.comment_id": 0})
 	}
+	// the reactions on a resolution are never mixed with the ones on its comment
+	cond = cond.And(builder.Eq{"reaction.is_resolution": opts.IsResolution})
+	if opts.CommitSHA != "" {
+		cond = cond.And(builder.Eq{
+			"reaction.repo_id":    opts.RepoID,
+			"reaction.commit_sha": opts.CommitSHA,
+		})
+	}
 	if opts.UserID > 0 {
 		cond = cond.And(builder.Eq{
 			"reaction.user_id":            opts.UserID,
@@ -148,6 +164,16 @@ func FindCommentReactions(ctx context.Context, issueID, commentID int64) (Reacti
 	})
 }
 
+// FindResolutionReactions returns a ReactionList of all reactions from the resolution of a conversation
+func FindResolutionReactions(ctx context.Context, issueID, commentID int64, listOptions db.ListOptions) (ReactionList, int64, error) {
+	return FindReactions(ctx, FindReactionsOptions{
+		ListOptions:  listOptions,
+		IssueID:      issueID,
+		CommentID:    commentID,
+		IsResolution: true,
+	})
+}
+
 // FindIssueReactions returns a ReactionList of all reactions from an issue
 func FindIssueReactions(ctx context.Context, issueID int64, listOptions db.ListOptions) (ReactionList, int64, error) {
 	return FindReactions(ctx, FindReactionsOptions{
@@ -157,7 +183,17 @@ func FindIssueReactions(ctx context.Context, issueID int64, listOptions db.ListO
 	})
 }
 
-// FindReactions returns a ReactionList of all reactions from an issue or a comment
+// FindCommitReactions returns a ReactionList of all reactions from a commit
+func FindCommitReactions(ctx context.Context, repoID int64, commitSHA string, listOptions db.ListOptions) (ReactionList, int64, error) {
+	return FindReactions(ctx, FindReactionsOptions{
+		ListOptions: listOptions,
+		RepoID:      repoID,
+		CommitSHA:   commitSHA,
+		CommentID:   -1,
+	})
+}
+
+// FindReactions returns a ReactionList of all reactions from an issue, a comment or a commit
 func FindReactions(ctx context.Context, opts FindReactionsOptions) (ReactionList, int64, error) {
 	sess := db.GetEngine(ctx).
 		Where(opts.toConds()).
@@ -180,14 +216,20 @@ func createReaction(ctx context.Context, opts *ReactionOptions) (*Reaction, erro
 	reaction := &Reaction{
 		Type:      opts.Type,
 		UserID:    opts.DoerID,
-		IssueID:   opts.IssueID,
-		CommentID: opts.CommentID,
+		IssueID:      opts.IssueID,
+		CommentID:    opts.CommentID,
+		IsResolution: opts.IsResolution,
+		RepoID:       opts.RepoID,
+		CommitSHA:    opts.CommitSHA,
 	}
 	findOpts := FindReactionsOptions{
-		IssueID:   opts.IssueID,
-		CommentID: opts.CommentID,
-		Reaction:  opts.Type,
-		UserID:    opts.DoerID,
+		IssueID:      opts.IssueID,
+		CommentID:    opts.CommentID,
+		IsResolution: opts.IsResolution,
+		RepoID:       opts.RepoID,
+		CommitSHA:    opts.CommitSHA,
+		Reaction:     opts.Type,
+		UserID:       opts.DoerID,
 	}
 	if findOpts.CommentID == 0 {
 		// explicit search of Issue Reactions where CommentID = 0
@@ -209,15 +251,19 @@ func createReaction(ctx context.Context, opts *ReactionOptions) (*Reaction, erro
 	return reaction, nil
 }
 
-// ReactionOptions defines options for creating or deleting reactions
+// ReactionOptions defines options for creating or deleting reactions,
+// the reactions on a commit are defined by RepoID and CommitSHA
 type ReactionOptions struct {
-	Type      string
-	DoerID    int64
-	IssueID   int64
-	CommentID int64
+	Type         string
+	DoerID       int64
+	IssueID      int64
+	CommentID    int64
+	IsResolution bool
+	RepoID       int64
+	CommitSHA    string
 }
 
-// CreateReaction creates reaction for issue or comment.
+// CreateReaction creates reaction for issue, comment, resolution or commit.
 func CreateReaction(ctx context.Context, opts *ReactionOptions) (*Reaction, error) {
 	if !setting.UI.ReactionsLookup.Contains(opts.Type) {
 		return nil, ErrForbiddenIssueReaction{opts.Type}
@@ -240,16 +286,19 @@ func CreateReaction(ctx context.Context, opts *ReactionOptions) (*Reaction, erro
 	return reaction, nil
 }
 
-// DeleteReaction deletes reaction for issue or comment.
+// DeleteReaction deletes reaction for issue, comment, resolution or commit.
 func DeleteReaction(ctx context.Context, opts *ReactionOptions) error {
 	reaction := &Reaction{
 		Type:      opts.Type,
 		UserID:    opts.DoerID,
-		IssueID:   opts.IssueID,
-		CommentID: opts.CommentID,
+		IssueID:      opts.IssueID,
+		CommentID:    opts.CommentID,
+		IsResolution: opts.IsResolution,
+		RepoID:       opts.RepoID,
+		CommitSHA:    opts.CommitSHA,
 	}
 
-	sess := db.GetEngine(ctx).Where("original_author_id = 0")
+	sess := db.GetEngine(ctx).Where("original_author_id = 0").MustCols("is_resolution")
 	if opts.CommentID == -1 {
 		reaction.CommentID = 0
 		sess.MustCols("comment_id")
@@ -279,6 +328,34 @@ func DeleteCommentReaction(ctx context.Context, doerID, issueID, commentID int64
 	})
 }
 
+// DeleteResolutionReaction deletes a reaction on the resolution of a conversation.
+func DeleteResolutionReaction(ctx context.Context, doerID, issueID, commentID int64, content string) error {
+	return DeleteReaction(ctx, &ReactionOptions{
+		Type:         content,
+		DoerID:       doerID,
+		IssueID:      issueID,
+		CommentID:    commentID,
+		IsResolution: true,
+	})
+}
+
+// DeleteResolutionReactions deletes all reactions on the resolution of a conversation.
+func DeleteResolutionReactions(ctx context.Context, commentID int64) error {
+	_, err := db.GetEngine(ctx).Where(builder.Eq{"comment_id": commentID, "is_resolution": true}).Delete(new(Reaction))
+	return err
+}
+
+// DeleteCommitReaction deletes a reaction on commit.
+func DeleteCommitReaction(ctx context.Context, doerID, repoID int64, commitSHA, content string) error {
+	return DeleteReaction(ctx, &ReactionOptions{
+		Type:      content,
+		DoerID:    doerID,
+		RepoID:    repoID,
+		CommitSHA: commitSHA,
+		CommentID: -1,
+	})
+}
+
 // ReactionList represents list of reactions
 type ReactionList []*Reaction
 
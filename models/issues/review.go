@@ -895,6 +895,11 @@ func MarkConversation(ctx context.Context, comment *Comment, doer *user_model.Us
 		if _, err = db.GetEngine(ctx).Exec("UPDATE `comment` SET resolve_doer_id=? WHERE id=?", 0, comment.ID); err != nil {
 			return err
 		}
+
+		// the reactions belong to the resolution which has been withdrawn
+		if err = DeleteResolutionReactions(ctx, comment.ID); err != nil {
+			return err
+		}
 	}
 
 	return nil
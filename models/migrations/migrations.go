@@ -591,6 +591,94 @@ var migrations = []Migration{
 
 	// v299 -> v300
 	NewMigration("Add content version to issue and comment table", v1_23.AddContentVersionToIssueAndComment),
+	// v300 -> v301
+	NewMigration("Add repo_manifest and repo_dependency tables", v1_23.AddRepoDependencyTables),
+	// v301 -> v302
+	NewMigration("Add external asset columns to attachment table", v1_23.AddExternalAssetColumnsToAttachment),
+	// v302 -> v303
+	NewMigration("Add release_signature table", v1_23.AddReleaseSignatureTable),
+	// v303 -> v304
+	NewMigration("Add custom_property and repo_custom_property_value tables", v1_23.AddCustomPropertyTables),
+	// v304 -> v305
+	NewMigration("Add managed_git_hook and managed_git_hook_opt_out tables", v1_23.AddManagedGitHookTables),
+	// v305 -> v306
+	NewMigration("Add include column to repo_archiver table", v1_23.AddIncludeToRepoArchiver),
+	// v306 -> v307
+	NewMigration("Add repo_size_stat table", v1_23.AddRepoSizeStatTable),
+	// v307 -> v308
+	NewMigration("Add repo_contributor_week and repo_punch_card tables", v1_23.AddRepoCommitStatsTables),
+	// v308 -> v309
+	NewMigration("Add option_template table", v1_23.AddOptionTemplateTable),
+	// v309 -> v310
+	NewMigration("Add repo_bisect_session table", v1_23.AddRepoBisectSessionTable),
+	// v310 -> v311
+	NewMigration("Add repo_staging_session table", v1_23.AddRepoStagingSessionTable),
+	// v311 -> v312
+	NewMigration("Add deferred_commit table", v1_23.AddDeferredCommitTable),
+	// v312 -> v313
+	NewMigration("Add ref_update_log table", v1_23.AddRefUpdateLogTable),
+	// v313 -> v314
+	NewMigration("Add repo_maintenance table", v1_23.AddRepoMaintenanceTable),
+	// v314 -> v315
+	NewMigration("Add path column to repo_archiver table", v1_23.AddPathToRepoArchiver),
+	// v315 -> v316
+	NewMigration("Add merge_queue and merge_queue_entry tables", v1_23.AddMergeQueueTables),
+	// v316 -> v317
+	NewMigration("Add require_code_owner_approval column to protected_branch table", v1_23.AddRequireCodeOwnerApprovalToProtectedBranch),
+	// v317 -> v318
+	NewMigration("Add condition columns to pull_auto_merge table", v1_23.AddConditionsToPullAutoMerge),
+	// v318 -> v319
+	NewMigration("Add issue_type table and type_id column to issue table", v1_23.AddIssueTypeTable),
+	// v319 -> v320
+	NewMigration("Add issue_field and issue_field_value tables", v1_23.AddIssueFieldTables),
+	// v320 -> v321
+	NewMigration("Add issue_relation table", v1_23.AddIssueRelationTable),
+	// v321 -> v322
+	NewMigration("Add issue_filter table", v1_23.AddIssueFilterTable),
+	// v322 -> v323
+	NewMigration("Add issue_automation_rule and issue_automation_log tables", v1_23.AddIssueAutomationTables),
+	// v323 -> v324
+	NewMigration("Add repo_id and commit_sha columns to reaction table", v1_23.AddCommitToReaction),
+	// v324 -> v325
+	NewMigration("Add is_internal column to comment table", v1_23.AddIsInternalToComment),
+	// v325 -> v326
+	NewMigration("Add issue_vote table and vote settings", v1_23.AddIssueVotes),
+	// v326 -> v327
+	NewMigration("Add org_id to milestone", v1_23.AddOrgIDToMilestone),
+	// v327 -> v328
+	NewMigration("Add concurrency columns to action run and job", v1_23.AddConcurrencyToActionRunAndJob),
+	// v328 -> v329
+	NewMigration("Add action_cache table", v1_23.AddActionCacheTable),
+	// v329 -> v330
+	NewMigration("Add dispatch_inputs to action run", v1_23.AddDispatchInputsToActionRun),
+	// v330 -> v331
+	NewMigration("Add action runner groups", v1_23.AddActionRunnerGroups),
+	// v331 -> v332
+	NewMigration("Add attempt to action run", v1_23.AddAttemptToActionRun),
+	// v332 -> v333
+	NewMigration("Add environment to actions secret and variable", v1_23.AddEnvironmentToActionsSecretAndVariable),
+	// v333 -> v334
+	NewMigration("Add ephemeral to action runner and runner token", v1_23.AddEphemeralToActionRunnerAndToken),
+	// v334 -> v335
+	NewMigration("Add action policies and failure annotations to action runs", v1_23.AddActionPolicyAndRunFailureAnnotations),
+	// v335 -> v336
+	NewMigration("Add container retention to package cleanup rules", v1_23.AddContainerRetentionToPackageCleanupRule),
+	// v336 -> v337
+	NewMigration("Add package upstream table", v1_23.AddPackageUpstreamTable),
+	// v337 -> v338
+	NewMigration("Add immutable to package", v1_23.AddImmutableToPackage),
+	// v338 -> v339
+	NewMigration("Add package attestation table", v1_23.AddPackageAttestationTable),
+	// v339 -> v340
+	NewMigration("Add package audit table", v1_23.AddPackageAuditTable),
+	// v340 -> v341
+	NewMigration("Add run_id to action cache", v1_23.AddRunIDToActionCache),
+	// v341 -> v342
+	NewMigration("Add require_signoff to protected branch", v1_23.AddRequireSignoffToProtectedBranch),
+	// v342 -> v343
+	NewMigration("Add user_id to issue filter", v1_23.AddUserIDToIssueFilter),
+	// v343 -> v344
+	NewMigration("Add is_resolution column to reaction table", v1_23.AddIsResolutionToReaction),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRepoDependencyTables(x *xorm.Engine) error {
+	type RepoManifest struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		CommitID    string             `xorm:"VARCHAR(64)"`
+		Path        string             `xorm:"VARCHAR(500) NOT NULL"`
+		Ecosystem   string             `xorm:"VARCHAR(20) INDEX(s) NOT NULL"`
+		Name        string             `xorm:"VARCHAR(255) INDEX(s)"`
+		CreatedUnix timeutil.TimeStamp `xorm:"CREATED"`
+	}
+
+	type RepoDependency struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		Manifest    string             `xorm:"VARCHAR(500) NOT NULL"`
+		Ecosystem   string             `xorm:"VARCHAR(20) INDEX(s) NOT NULL"`
+		Name        string             `xorm:"VARCHAR(255) INDEX(s) NOT NULL"`
+		Version     string             `xorm:"VARCHAR(255)"`
+		IsDev       bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"CREATED"`
+	}
+
+	return x.Sync(new(RepoManifest), new(RepoDependency))
+}
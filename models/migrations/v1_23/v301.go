@@ -0,0 +1,21 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddExternalAssetColumnsToAttachment(x *xorm.Engine) error {
+	type Attachment struct {
+		ExternalURL         string `xorm:"TEXT"`
+		ExternalSHA256      string `xorm:"VARCHAR(64)"`
+		ExternalStatus      int    `xorm:"NOT NULL DEFAULT 0"`
+		ExternalCheckedUnix timeutil.TimeStamp
+	}
+
+	return x.Sync(new(Attachment))
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddReleaseSignatureTable(x *xorm.Engine) error {
+	type ReleaseSignature struct {
+		ID          int64              `xorm:"pk autoincr"`
+		ReleaseID   int64              `xorm:"UNIQUE NOT NULL"`
+		Fingerprint string             `xorm:"VARCHAR(64) NOT NULL"`
+		Checksums   string             `xorm:"LONGTEXT"`
+		Signature   string             `xorm:"LONGTEXT"`
+		KeyID       string             `xorm:"VARCHAR(255)"`
+		Provenance  string             `xorm:"LONGTEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(ReleaseSignature))
+}
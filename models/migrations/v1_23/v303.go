@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddCustomPropertyTables(x *xorm.Engine) error {
+	type CustomProperty struct {
+		ID            int64              `xorm:"pk autoincr"`
+		OwnerID       int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Name          string             `xorm:"UNIQUE(s) VARCHAR(75) NOT NULL"`
+		Type          string             `xorm:"VARCHAR(20) NOT NULL"`
+		Description   string             `xorm:"TEXT"`
+		AllowedValues []string           `xorm:"TEXT JSON"`
+		DefaultValue  string             `xorm:"VARCHAR(255)"`
+		Required      bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type RepoCustomPropertyValue struct {
+		ID     int64  `xorm:"pk autoincr"`
+		RepoID int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Name   string `xorm:"UNIQUE(s) INDEX(name_value) VARCHAR(75) NOT NULL"`
+		Value  string `xorm:"INDEX(name_value) VARCHAR(255) NOT NULL"`
+	}
+
+	return x.Sync(new(CustomProperty), new(RepoCustomPropertyValue))
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddManagedGitHookTables(x *xorm.Engine) error {
+	type ManagedGitHook struct {
+		ID                   int64              `xorm:"pk autoincr"`
+		OrgID                int64              `xorm:"UNIQUE NOT NULL"`
+		MaxFileSize          int64              `xorm:"NOT NULL DEFAULT 0"`
+		ForbiddenPaths       []string           `xorm:"JSON TEXT"`
+		CommitMessagePattern string             `xorm:"TEXT"`
+		PreReceiveScript     string             `xorm:"LONGTEXT"`
+		UpdateScript         string             `xorm:"LONGTEXT"`
+		AllowRepoOptOut      bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix          timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix          timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ManagedGitHookOptOut struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE NOT NULL"`
+		DoerID      int64              `xorm:"NOT NULL"`
+		ByAdmin     bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(ManagedGitHook), new(ManagedGitHookOptOut))
+}
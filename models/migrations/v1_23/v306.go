@@ -0,0 +1,24 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRepoSizeStatTable(x *xorm.Engine) error {
+	type RepoSizeStat struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"UNIQUE NOT NULL"`
+		AttachmentSize int64              `xorm:"NOT NULL DEFAULT 0"`
+		ArtifactSize   int64              `xorm:"NOT NULL DEFAULT 0"`
+		ActionLogSize  int64              `xorm:"NOT NULL DEFAULT 0"`
+		PackageSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync(new(RepoSizeStat))
+}
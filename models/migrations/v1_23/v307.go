@@ -0,0 +1,31 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddRepoCommitStatsTables(x *xorm.Engine) error {
+	type RepoContributorWeek struct {
+		ID        int64  `xorm:"pk autoincr"`
+		RepoID    int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Email     string `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		Week      int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Name      string `xorm:"VARCHAR(255)"`
+		Commits   int64  `xorm:"NOT NULL DEFAULT 0"`
+		Additions int64  `xorm:"NOT NULL DEFAULT 0"`
+		Deletions int64  `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type RepoPunchCard struct {
+		ID      int64 `xorm:"pk autoincr"`
+		RepoID  int64 `xorm:"UNIQUE(s) NOT NULL"`
+		Weekday int   `xorm:"UNIQUE(s) NOT NULL"`
+		Hour    int   `xorm:"UNIQUE(s) NOT NULL"`
+		Commits int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(RepoContributorWeek), new(RepoPunchCard))
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddOptionTemplateTable(x *xorm.Engine) error {
+	type OptionTemplate struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Type        string             `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+		Name        string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		Content     string             `xorm:"LONGTEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(OptionTemplate))
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRepoBisectSessionTable(x *xorm.Engine) error {
+	type RepoBisectSession struct {
+		ID          int64    `xorm:"pk autoincr"`
+		RepoID      int64    `xorm:"UNIQUE(s) NOT NULL"`
+		UserID      int64    `xorm:"UNIQUE(s) NOT NULL"`
+		Bad         string   `xorm:"VARCHAR(64) NOT NULL"`
+		Good        []string `xorm:"JSON TEXT"`
+		Skipped     []string `xorm:"JSON TEXT"`
+		Path        string   `xorm:"TEXT"`
+		Candidate   string   `xorm:"VARCHAR(64)"`
+		Remaining   int      `xorm:"NOT NULL DEFAULT 0"`
+		Steps       int      `xorm:"NOT NULL DEFAULT 0"`
+		FirstBad    string   `xorm:"VARCHAR(64)"`
+		OnlySkipped []string `xorm:"JSON TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(RepoBisectSession))
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRepoStagingSessionTable(x *xorm.Engine) error {
+	type RepoStagingSession struct {
+		ID           int64  `xorm:"pk autoincr"`
+		UUID         string `xorm:"uuid UNIQUE"`
+		RepoID       int64  `xorm:"INDEX NOT NULL"`
+		UserID       int64  `xorm:"NOT NULL"`
+		Branch       string `xorm:"NOT NULL"`
+		BaseCommitID string `xorm:"VARCHAR(64)"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated INDEX"`
+	}
+
+	return x.Sync(new(RepoStagingSession))
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddDeferredCommitTable(x *xorm.Engine) error {
+	type DeferredCommit struct {
+		ID          int64 `xorm:"pk autoincr"`
+		RepoID      int64 `xorm:"INDEX NOT NULL"`
+		DoerID      int64 `xorm:"NOT NULL"`
+		Branch      string
+		Options     string             `xorm:"LONGTEXT NOT NULL"`
+		ExecuteUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		Status      int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		CommitID    string             `xorm:"VARCHAR(64)"`
+		Error       string             `xorm:"TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(DeferredCommit))
+}
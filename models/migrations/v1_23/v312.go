@@ -0,0 +1,24 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRefUpdateLogTable(x *xorm.Engine) error {
+	type RefUpdateLog struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX(repo_ref) NOT NULL"`
+		RefName     string             `xorm:"INDEX(repo_ref) VARCHAR(255) NOT NULL"`
+		OldCommitID string             `xorm:"VARCHAR(64)"`
+		NewCommitID string             `xorm:"VARCHAR(64)"`
+		PusherID    int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+	}
+
+	return x.Sync(new(RefUpdateLog))
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRepoMaintenanceTable(x *xorm.Engine) error {
+	type RepoMaintenance struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"UNIQUE NOT NULL"`
+		Trigger        string             `xorm:"VARCHAR(20) NOT NULL"`
+		DurationMillis int64              `xorm:"NOT NULL DEFAULT 0"`
+		Error          string             `xorm:"TEXT"`
+		LastRunUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	return x.Sync(new(RepoMaintenance))
+}
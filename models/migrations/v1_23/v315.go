@@ -0,0 +1,37 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddMergeQueueTables(x *xorm.Engine) error {
+	type MergeQueue struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		BaseBranch  string             `xorm:"UNIQUE(s) NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type MergeQueueEntry struct {
+		ID            int64              `xorm:"pk autoincr"`
+		QueueID       int64              `xorm:"INDEX NOT NULL"`
+		RepoID        int64              `xorm:"INDEX NOT NULL"`
+		PullID        int64              `xorm:"UNIQUE NOT NULL"`
+		DoerID        int64              `xorm:"INDEX NOT NULL"`
+		MergeStyle    string             `xorm:"varchar(30)"`
+		Message       string             `xorm:"LONGTEXT"`
+		HeadCommitID  string             `xorm:"VARCHAR(64)"`
+		BaseCommitID  string             `xorm:"VARCHAR(64)"`
+		MergeCommitID string             `xorm:"VARCHAR(64) INDEX"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(MergeQueue), new(MergeQueueEntry))
+}
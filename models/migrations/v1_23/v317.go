@@ -0,0 +1,27 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type pullAutoMergeConditions struct {
+	ID                 int64              `xorm:"pk autoincr"`
+	RequiredApprovals  int64              `xorm:"NOT NULL DEFAULT 0"`
+	NoChangesRequested bool               `xorm:"NOT NULL DEFAULT false"`
+	MergeAfterUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	MergeAfterQueued   bool               `xorm:"NOT NULL DEFAULT false"`
+	AfterPullID        int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+func (pullAutoMergeConditions) TableName() string {
+	return "pull_auto_merge"
+}
+
+func AddConditionsToPullAutoMerge(x *xorm.Engine) error {
+	return x.Sync(new(pullAutoMergeConditions))
+}
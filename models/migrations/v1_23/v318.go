@@ -0,0 +1,32 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueTypeTable(x *xorm.Engine) error {
+	type IssueType struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		OrgID          int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Name           string             `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+		Description    string             `xorm:"TEXT"`
+		Icon           string             `xorm:"VARCHAR(50)"`
+		Color          string             `xorm:"VARCHAR(7)"`
+		Template       string             `xorm:"LONGTEXT"`
+		RequiredFields []string           `xorm:"TEXT JSON"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type Issue struct {
+		TypeID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(IssueType), new(Issue))
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueFieldTables(x *xorm.Engine) error {
+	type IssueField struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		OrgID       int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Name        string             `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		Type        string             `xorm:"VARCHAR(20) NOT NULL"`
+		Options     []string           `xorm:"TEXT JSON"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type IssueFieldValue struct {
+		ID      int64  `xorm:"pk autoincr"`
+		IssueID int64  `xorm:"INDEX NOT NULL"`
+		FieldID int64  `xorm:"INDEX NOT NULL"`
+		Value   string `xorm:"VARCHAR(255) NOT NULL"`
+	}
+
+	return x.Sync(new(IssueField), new(IssueFieldValue))
+}
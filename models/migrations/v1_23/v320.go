@@ -0,0 +1,21 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueRelationTable(x *xorm.Engine) error {
+	type IssueRelation struct {
+		ID          int64              `xorm:"pk autoincr"`
+		ParentID    int64              `xorm:"INDEX NOT NULL"`
+		ChildID     int64              `xorm:"UNIQUE NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(IssueRelation))
+}
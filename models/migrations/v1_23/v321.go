@@ -0,0 +1,32 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueFilterTable(x *xorm.Engine) error {
+	type IssueFilter struct {
+		ID          int64   `xorm:"pk autoincr"`
+		RepoID      int64   `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		OrgID       int64   `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Slug        string  `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+		Name        string  `xorm:"VARCHAR(50) NOT NULL"`
+		LabelIDs    []int64 `xorm:"TEXT JSON"`
+		AssigneeID  int64
+		MilestoneID int64
+		TypeID      int64
+		State       string             `xorm:"VARCHAR(10)"`
+		Keyword     string             `xorm:"VARCHAR(255)"`
+		SortType    string             `xorm:"VARCHAR(20)"`
+		IsDefault   bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(IssueFilter))
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueAutomationTables(x *xorm.Engine) error {
+	type IssueAutomationRule struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"INDEX NOT NULL"`
+		Name           string `xorm:"VARCHAR(50) NOT NULL"`
+		Target         string `xorm:"VARCHAR(10) NOT NULL"`
+		LabelID        int64
+		Days           int     `xorm:"NOT NULL"`
+		ExemptLabelIDs []int64 `xorm:"TEXT JSON"`
+		Action         string  `xorm:"VARCHAR(10) NOT NULL"`
+		ActionLabelID  int64
+		Comment        string             `xorm:"TEXT"`
+		IsDryRun       bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type IssueAutomationLog struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		RuleID      int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL"`
+		RuleName    string             `xorm:"VARCHAR(50)"`
+		Action      string             `xorm:"VARCHAR(10)"`
+		IsDryRun    bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+	}
+
+	return x.Sync(new(IssueAutomationRule), new(IssueAutomationLog))
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/models/migrations/base"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddCommitToReaction(x *xorm.Engine) error {
+	// Reaction represents a reactions on issues, comments and commits.
+	type Reaction struct {
+		ID               int64              `xorm:"pk autoincr"`
+		Type             string             `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		IssueID          int64              `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		CommentID        int64              `xorm:"INDEX UNIQUE(s)"`
+		RepoID           int64              `xorm:"INDEX UNIQUE(s) NOT NULL DEFAULT(0)"`
+		CommitSHA        string             `xorm:"VARCHAR(64) UNIQUE(s) NOT NULL DEFAULT('')"`
+		UserID           int64              `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		OriginalAuthorID int64              `xorm:"INDEX UNIQUE(s) NOT NULL DEFAULT(0)"`
+		OriginalAuthor   string             `xorm:"INDEX UNIQUE(s)"`
+		CreatedUnix      timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	// add the columns first, the table is then recreated to include them in the unique constraint
+	if err := x.Sync(new(Reaction)); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := base.RecreateTable(sess, &Reaction{}); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddCommitToReaction(t *testing.T) {
+	type Reaction struct { // old struct
+		ID               int64  `xorm:"pk autoincr"`
+		Type             string `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		IssueID          int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		CommentID        int64  `xorm:"INDEX UNIQUE(s)"`
+		UserID           int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		OriginalAuthorID int64  `xorm:"INDEX UNIQUE(s) NOT NULL DEFAULT(0)"`
+		OriginalAuthor   string `xorm:"INDEX UNIQUE(s)"`
+		CreatedUnix      int64  `xorm:"INDEX created"`
+	}
+
+	// Prepare and load the testing database
+	x, deferable := base.PrepareTestEnv(t, 0, new(Reaction))
+	defer deferable()
+	if x == nil || t.Failed() {
+		return
+	}
+
+	assert.NoError(t, AddCommitToReaction(x))
+
+	type CommitReaction struct {
+		ID               int64 `xorm:"pk autoincr"`
+		Type             string
+		IssueID          int64
+		CommentID        int64
+		RepoID           int64
+		CommitSHA        string
+		UserID           int64
+		OriginalAuthorID int64
+		OriginalAuthor   string
+	}
+	reactions := make([]*CommitReaction, 0, 3)
+	assert.NoError(t, x.Table("reaction").Find(&reactions))
+	if assert.Len(t, reactions, 1) {
+		assert.EqualValues(t, 1, reactions[0].IssueID)
+		assert.Empty(t, reactions[0].CommitSHA)
+	}
+
+	// a user can give the same reaction to several commits
+	for _, sha := range []string{"65f1bf27bc3bf70f64657658635e66094edbcb4d", "4a357436d925b5c974181ff12a994538ddc5a269"} {
+		_, err := x.Table("reaction").Insert(&CommitReaction{Type: "heart", RepoID: 1, CommitSHA: sha, UserID: 1})
+		assert.NoError(t, err)
+	}
+	_, err := x.Table("reaction").Insert(&CommitReaction{Type: "heart", RepoID: 1, CommitSHA: "4a357436d925b5c974181ff12a994538ddc5a269", UserID: 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddIsInternalToComment(x *xorm.Engine) error {
+	type Comment struct {
+		IsInternal bool `xorm:"NOT NULL DEFAULT false"`
+	}
+	return x.Sync(new(Comment))
+}
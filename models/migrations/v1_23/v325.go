@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueVotes(x *xorm.Engine) error {
+	type IssueVote struct {
+		ID          int64              `xorm:"pk autoincr"`
+		IssueID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		UserID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
+	}
+
+	type Issue struct {
+		NumVotes int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type User struct {
+		IssueVotingMembersOnly bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync(new(IssueVote), new(Issue), new(User))
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionCacheTable(x *xorm.Engine) error {
+	type ActionCache struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"index UNIQUE(s)"`
+		Scope       string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		Key         string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		Version     string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+		Complete    bool               `xorm:"index NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UsedUnix    timeutil.TimeStamp `xorm:"index"`
+	}
+
+	return x.Sync(new(ActionCache))
+}
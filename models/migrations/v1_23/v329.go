@@ -0,0 +1,16 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddDispatchInputsToActionRun(x *xorm.Engine) error {
+	type ActionRun struct {
+		DispatchInputs map[string]string `xorm:"JSON TEXT"`
+	}
+
+	return x.Sync(new(ActionRun))
+}
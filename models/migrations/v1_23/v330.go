@@ -0,0 +1,36 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionRunnerGroups(x *xorm.Engine) error {
+	type ActionRunnerGroup struct {
+		ID          int64
+		OwnerID     int64              `xorm:"UNIQUE(owner_name)"`
+		Name        string             `xorm:"VARCHAR(255) UNIQUE(owner_name) NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		Visibility  int                `xorm:"NOT NULL DEFAULT 0"`
+		Created     timeutil.TimeStamp `xorm:"created"`
+		Updated     timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ActionRunnerGroupAccess struct {
+		ID      int64
+		GroupID int64 `xorm:"UNIQUE(s) NOT NULL"`
+		OrgID   int64 `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+		RepoID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type ActionRunner struct {
+		CustomLabels []string `xorm:"TEXT"`
+		GroupID      int64    `xorm:"index NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(ActionRunnerGroup), new(ActionRunnerGroupAccess), new(ActionRunner))
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddAttemptToActionRun(x *xorm.Engine) error {
+	type ActionRun struct {
+		Attempt int64 `xorm:"NOT NULL DEFAULT 1"`
+	}
+
+	return x.Sync(new(ActionRun))
+}
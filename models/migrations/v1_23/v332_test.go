@@ -0,0 +1,78 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddEnvironmentToActionsSecretAndVariable(t *testing.T) {
+	type Secret struct { // old struct
+		ID      int64
+		OwnerID int64  `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
+		RepoID  int64  `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		Name    string `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+		Data    string `xorm:"LONGTEXT"`
+		Created int64  `xorm:"'created_unix' created NOT NULL"`
+	}
+	type ActionVariable struct { // old struct
+		ID      int64  `xorm:"pk autoincr"`
+		OwnerID int64  `xorm:"UNIQUE(owner_repo_name)"`
+		RepoID  int64  `xorm:"INDEX UNIQUE(owner_repo_name)"`
+		Name    string `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+		Data    string `xorm:"LONGTEXT NOT NULL"`
+		Created int64  `xorm:"'created_unix' created NOT NULL"`
+		Updated int64  `xorm:"'updated_unix' updated"`
+	}
+
+	// Prepare and load the testing database
+	x, deferable := base.PrepareTestEnv(t, 0, new(Secret), new(ActionVariable))
+	defer deferable()
+	if x == nil || t.Failed() {
+		return
+	}
+
+	_, err := x.Insert(&Secret{RepoID: 1, Name: "TOKEN", Data: "encrypted"}, &ActionVariable{RepoID: 1, Name: "TARGET", Data: "staging"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, AddEnvironmentToActionsSecretAndVariable(x))
+
+	type EnvironmentSecret struct {
+		ID          int64
+		OwnerID     int64
+		RepoID      int64
+		Name        string
+		Environment string
+		Data        string
+		CreatedUnix int64 `xorm:"created"`
+	}
+	type EnvironmentVariable struct {
+		ID          int64
+		OwnerID     int64
+		RepoID      int64
+		Name        string
+		Environment string
+		Data        string
+		CreatedUnix int64 `xorm:"created"`
+	}
+
+	// the same name can be used by a repository and its environments
+	_, err = x.Table("secret").Insert(&EnvironmentSecret{RepoID: 1, Name: "TOKEN", Environment: "production"})
+	assert.NoError(t, err)
+	_, err = x.Table("action_variable").Insert(&EnvironmentVariable{RepoID: 1, Name: "TARGET", Environment: "production"})
+	assert.NoError(t, err)
+	_, err = x.Table("secret").Insert(&EnvironmentSecret{RepoID: 1, Name: "TOKEN", Environment: "production"})
+	assert.Error(t, err)
+
+	secrets := make([]*EnvironmentSecret, 0, 2)
+	assert.NoError(t, x.Table("secret").OrderBy("id").Find(&secrets))
+	if assert.Len(t, secrets, 2) {
+		assert.Empty(t, secrets[0].Environment)
+		assert.Equal(t, "production", secrets[1].Environment)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddEphemeralToActionRunnerAndToken(x *xorm.Engine) error {
+	type ActionRunner struct {
+		Ephemeral bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type ActionRunnerToken struct {
+		Ephemeral bool               `xorm:"NOT NULL DEFAULT false"`
+		Expires   timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(ActionRunner), new(ActionRunnerToken))
+}
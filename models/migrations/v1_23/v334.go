@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionPolicyAndRunFailureAnnotations(x *xorm.Engine) error {
+	type ActionPolicy struct {
+		ID                int64
+		OwnerID           int64              `xorm:"UNIQUE NOT NULL DEFAULT 0"`
+		Mode              int                `xorm:"NOT NULL DEFAULT 0"`
+		AllowedPatterns   []string           `xorm:"JSON TEXT"`
+		BlockedPatterns   []string           `xorm:"JSON TEXT"`
+		RequireSHAPinning bool               `xorm:"NOT NULL DEFAULT false"`
+		Created           timeutil.TimeStamp `xorm:"created"`
+		Updated           timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ActionRun struct {
+		FailureAnnotations []string `xorm:"JSON TEXT"`
+	}
+
+	return x.Sync(new(ActionPolicy), new(ActionRun))
+}
@@ -0,0 +1,17 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddContainerRetentionToPackageCleanupRule(x *xorm.Engine) error {
+	type PackageCleanupRule struct {
+		RemoveUntaggedDays int      `xorm:"NOT NULL DEFAULT 0"`
+		ProtectedTags      []string `xorm:"JSON TEXT"`
+	}
+
+	return x.Sync(new(PackageCleanupRule))
+}
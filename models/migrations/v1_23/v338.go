@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPackageAttestationTable(x *xorm.Engine) error {
+	type PackageAttestation struct {
+		ID                 int64              `xorm:"pk autoincr"`
+		VersionID          int64              `xorm:"INDEX NOT NULL"`
+		ReferenceVersionID int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		PredicateType      string             `xorm:"NOT NULL"`
+		MediaType          string             `xorm:"NOT NULL DEFAULT ''"`
+		Content            string             `xorm:"LONGTEXT NOT NULL"`
+		CreatorID          int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix        timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+	}
+
+	return x.Sync(new(PackageAttestation))
+}
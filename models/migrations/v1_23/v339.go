@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPackageAuditTable(x *xorm.Engine) error {
+	type PackageAudit struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL"`
+		DoerID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		Action      string             `xorm:"NOT NULL"`
+		Type        string             `xorm:"NOT NULL"`
+		Name        string             `xorm:"NOT NULL"`
+		Version     string             `xorm:"NOT NULL DEFAULT ''"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+	}
+
+	return x.Sync(new(PackageAudit))
+}
@@ -0,0 +1,16 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddRunIDToActionCache(x *xorm.Engine) error {
+	type ActionCache struct {
+		RunID int64 `xorm:"index NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(ActionCache))
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddUserIDToIssueFilter(x *xorm.Engine) error {
+	// the slug of a personal filter is unique for its owner, the unique index is recreated with the new column
+	type IssueFilter struct {
+		RepoID int64  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		OrgID  int64  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		UserID int64  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Slug   string `xorm:"UNIQUE(s) VARCHAR(50) NOT NULL"`
+	}
+
+	return x.Sync(new(IssueFilter))
+}
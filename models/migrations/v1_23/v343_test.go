@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddIsResolutionToReaction(t *testing.T) {
+	type Reaction struct { // old struct
+		ID               int64  `xorm:"pk autoincr"`
+		Type             string `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		IssueID          int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		CommentID        int64  `xorm:"INDEX UNIQUE(s)"`
+		RepoID           int64  `xorm:"INDEX UNIQUE(s) NOT NULL DEFAULT(0)"`
+		CommitSHA        string `xorm:"VARCHAR(64) UNIQUE(s) NOT NULL DEFAULT('')"`
+		UserID           int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+		OriginalAuthorID int64  `xorm:"INDEX UNIQUE(s) NOT NULL DEFAULT(0)"`
+		OriginalAuthor   string `xorm:"INDEX UNIQUE(s)"`
+		CreatedUnix      int64  `xorm:"INDEX created"`
+	}
+
+	// Prepare and load the testing database
+	x, deferable := base.PrepareTestEnv(t, 0, new(Reaction))
+	defer deferable()
+	if x == nil || t.Failed() {
+		return
+	}
+
+	assert.NoError(t, AddIsResolutionToReaction(x))
+
+	type ResolutionReaction struct {
+		ID               int64 `xorm:"pk autoincr"`
+		Type             string
+		IssueID          int64
+		CommentID        int64
+		IsResolution     bool
+		RepoID           int64
+		CommitSHA        string
+		UserID           int64
+		OriginalAuthorID int64
+		OriginalAuthor   string
+	}
+	reactions := make([]*ResolutionReaction, 0, 3)
+	assert.NoError(t, x.Table("reaction").Find(&reactions))
+	if assert.Len(t, reactions, 1) {
+		assert.False(t, reactions[0].IsResolution)
+	}
+
+	// a user can give the same reaction to a code comment and to the resolution of its conversation
+	_, err := x.Table("reaction").Insert(&ResolutionReaction{Type: "heart", IssueID: 2, CommentID: 4, IsResolution: true, UserID: 1})
+	assert.NoError(t, err)
+	_, err = x.Table("reaction").Insert(&ResolutionReaction{Type: "heart", IssueID: 2, CommentID: 4, IsResolution: true, UserID: 1})
+	assert.Error(t, err)
+}
@@ -405,10 +405,16 @@ func DeleteOrganization(ctx context.Context, org *Organization) error {
 		&user_model.Blocking{BlockerID: org.ID},
 		&actions_model.ActionRunner{OwnerID: org.ID},
 		&actions_model.ActionRunnerToken{OwnerID: org.ID},
+		&actions_model.ActionRunnerGroupAccess{OrgID: org.ID},
+		&repo_model.CustomProperty{OwnerID: org.ID},
 	); err != nil {
 		return fmt.Errorf("DeleteBeans: %w", err)
 	}
 
+	if err := actions_model.DeleteRunnerGroupsByOwnerID(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteRunnerGroupsByOwnerID: %w", err)
+	}
+
 	if _, err := db.GetEngine(ctx).ID(org.ID).Delete(new(user_model.User)); err != nil {
 		return fmt.Errorf("Delete: %w", err)
 	}
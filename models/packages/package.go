@@ -42,6 +42,7 @@ const (
 	TypeGeneric   Type = "generic"
 	TypeGo        Type = "go"
 	TypeHelm      Type = "helm"
+	TypeJulia     Type = "julia"
 	TypeMaven     Type = "maven"
 	TypeNpm       Type = "npm"
 	TypeNuGet     Type = "nuget"
@@ -50,6 +51,7 @@ const (
 	TypeRpm       Type = "rpm"
 	TypeRubyGems  Type = "rubygems"
 	TypeSwift     Type = "swift"
+	TypeTerraform Type = "terraform"
 	TypeVagrant   Type = "vagrant"
 )
 
@@ -66,6 +68,7 @@ var TypeList = []Type{
 	TypeGeneric,
 	TypeGo,
 	TypeHelm,
+	TypeJulia,
 	TypeMaven,
 	TypeNpm,
 	TypeNuGet,
@@ -74,6 +77,7 @@ var TypeList = []Type{
 	TypeRpm,
 	TypeRubyGems,
 	TypeSwift,
+	TypeTerraform,
 	TypeVagrant,
 }
 
@@ -104,6 +108,8 @@ func (pt Type) Name() string {
 		return "Go"
 	case TypeHelm:
 		return "Helm"
+	case TypeJulia:
+		return "Julia"
 	case TypeMaven:
 		return "Maven"
 	case TypeNpm:
@@ -120,6 +126,8 @@ func (pt Type) Name() string {
 		return "RubyGems"
 	case TypeSwift:
 		return "Swift"
+	case TypeTerraform:
+		return "Terraform"
 	case TypeVagrant:
 		return "Vagrant"
 	}
@@ -153,6 +161,8 @@ func (pt Type) SVGName() string {
 		return "gitea-go"
 	case TypeHelm:
 		return "gitea-helm"
+	case TypeJulia:
+		return "gitea-julia"
 	case TypeMaven:
 		return "gitea-maven"
 	case TypeNpm:
@@ -169,6 +179,8 @@ func (pt Type) SVGName() string {
 		return "gitea-rubygems"
 	case TypeSwift:
 		return "gitea-swift"
+	case TypeTerraform:
+		return "gitea-terraform"
 	case TypeVagrant:
 		return "gitea-vagrant"
 	}
@@ -185,6 +197,7 @@ type Package struct {
 	LowerName        string `xorm:"UNIQUE(s) INDEX NOT NULL"`
 	SemverCompatible bool   `xorm:"NOT NULL DEFAULT false"`
 	IsInternal       bool   `xorm:"NOT NULL DEFAULT false"`
+	IsImmutable      bool   `xorm:"NOT NULL DEFAULT false"`
 }
 
 // TryInsertPackage inserts a package. If a package exists already, ErrDuplicatePackage is returned
@@ -222,6 +235,12 @@ func SetRepositoryLink(ctx context.Context, packageID, repoID int64) error {
 	return err
 }
 
+// SetImmutable sets if the versions of the package are immutable
+func SetImmutable(ctx context.Context, packageID int64, immutable bool) error {
+	_, err := db.GetEngine(ctx).ID(packageID).Cols("is_immutable").Update(&Package{IsImmutable: immutable})
+	return err
+}
+
 // UnlinkRepositoryFromAllPackages unlinks every package from the repository
 func UnlinkRepositoryFromAllPackages(ctx context.Context, repoID int64) error {
 	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Cols("repo_id").Update(&Package{})
@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+var ErrPackageAttestationNotExist = util.NewNotExistErrorf("package attestation does not exist")
+
+func init() {
+	db.RegisterModel(new(PackageAttestation))
+}
+
+// PackageAttestation represents a signature or an attestation of a package version
+type PackageAttestation struct {
+	ID        int64 `xorm:"pk autoincr"`
+	VersionID int64 `xorm:"INDEX NOT NULL"`
+	// ReferenceVersionID is the version which holds the attestation if it is stored as a package version itself (cosign signature tags)
+	ReferenceVersionID int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	PredicateType      string             `xorm:"NOT NULL"`
+	MediaType          string             `xorm:"NOT NULL DEFAULT ''"`
+	Content            string             `xorm:"LONGTEXT NOT NULL"`
+	CreatorID          int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix        timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+}
+
+// InsertAttestation inserts an attestation
+func InsertAttestation(ctx context.Context, pa *PackageAttestation) error {
+	return db.Insert(ctx, pa)
+}
+
+// GetAttestationsByVersionID gets all attestations of a package version
+func GetAttestationsByVersionID(ctx context.Context, versionID int64) ([]*PackageAttestation, error) {
+	pas := make([]*PackageAttestation, 0, 5)
+	return pas, db.GetEngine(ctx).Where("version_id = ?", versionID).OrderBy("id").Find(&pas)
+}
+
+// GetAttestationsByVersionIDs gets all attestations of the package versions
+func GetAttestationsByVersionIDs(ctx context.Context, versionIDs []int64) ([]*PackageAttestation, error) {
+	pas := make([]*PackageAttestation, 0, len(versionIDs))
+	if len(versionIDs) == 0 {
+		return pas, nil
+	}
+	return pas, db.GetEngine(ctx).Where(builder.In("version_id", versionIDs)).OrderBy("id").Find(&pas)
+}
+
+// GetAttestationByID gets an attestation of a package version
+func GetAttestationByID(ctx context.Context, versionID, attestationID int64) (*PackageAttestation, error) {
+	pa := &PackageAttestation{}
+
+	has, err := db.GetEngine(ctx).Where("id = ? AND version_id = ?", attestationID, versionID).Get(pa)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageAttestationNotExist
+	}
+	return pa, nil
+}
+
+// DeleteAttestationByID deletes an attestation
+func DeleteAttestationByID(ctx context.Context, attestationID int64) error {
+	_, err := db.GetEngine(ctx).ID(attestationID).Delete(&PackageAttestation{})
+	return err
+}
+
+// DeleteAttestationsByReferenceVersionID deletes all attestations stored in the package version
+func DeleteAttestationsByReferenceVersionID(ctx context.Context, versionID int64) error {
+	_, err := db.GetEngine(ctx).Where("reference_version_id = ?", versionID).Delete(&PackageAttestation{})
+	return err
+}
+
+// DeleteAttestationsByVersionID deletes all attestations of the package version and the attestations stored in it
+func DeleteAttestationsByVersionID(ctx context.Context, versionID int64) error {
+	_, err := db.GetEngine(ctx).Where("version_id = ? OR reference_version_id = ?", versionID, versionID).Delete(&PackageAttestation{})
+	return err
+}
+
+// HasAttestations checks if the package version has attestations
+func HasAttestations(ctx context.Context, versionID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("version_id = ?", versionID).Exist(&PackageAttestation{})
+}
+
+// GetAttestedVersionIDs returns the ids of the package versions which have attestations
+func GetAttestedVersionIDs(ctx context.Context, pvs []*PackageVersion) (container.Set[int64], error) {
+	versionIDs := make([]int64, 0, len(pvs))
+	for _, pv := range pvs {
+		versionIDs = append(versionIDs, pv.ID)
+	}
+
+	ids := make([]int64, 0, len(versionIDs))
+	if len(versionIDs) > 0 {
+		if err := db.GetEngine(ctx).
+			Table("package_attestation").
+			Distinct("version_id").
+			Where(builder.In("version_id", versionIDs)).
+			Find(&ids); err != nil {
+			return nil, err
+		}
+	}
+	return container.SetOf(ids...), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PackageAudit))
+}
+
+// AuditAction is an action on a package which is recorded in the audit trail
+type AuditAction string
+
+const (
+	AuditActionPublish        AuditAction = "publish"
+	AuditActionDeleteVersion  AuditAction = "delete_version"
+	AuditActionDeletePackage  AuditAction = "delete_package"
+	AuditActionSetImmutable   AuditAction = "set_immutable"
+	AuditActionUnsetImmutable AuditAction = "unset_immutable"
+)
+
+// AuditActions are all the recorded actions
+var AuditActions = []AuditAction{
+	AuditActionPublish,
+	AuditActionDeleteVersion,
+	AuditActionDeletePackage,
+	AuditActionSetImmutable,
+	AuditActionUnsetImmutable,
+}
+
+// PackageAudit represents an action on a package of an owner.
+// The names are stored as they were because the package may not exist anymore.
+type PackageAudit struct {
+	ID      int64       `xorm:"pk autoincr"`
+	OwnerID int64       `xorm:"INDEX NOT NULL"`
+	DoerID  int64       `xorm:"NOT NULL DEFAULT 0"` // 0 if the action was done by the system, like a cleanup rule
+	Action  AuditAction `xorm:"NOT NULL"`
+	Type    Type        `xorm:"NOT NULL"`
+	Name    string      `xorm:"NOT NULL"`
+	// Version is empty for the actions on the whole package
+	Version     string             `xorm:"NOT NULL DEFAULT ''"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+}
+
+// InsertAudit records an action in the audit trail
+func InsertAudit(ctx context.Context, pa *PackageAudit) error {
+	return db.Insert(ctx, pa)
+}
+
+// FindAuditOptions are the options to search the audit trail of an owner
+type FindAuditOptions struct {
+	db.ListOptions
+	OwnerID int64
+	Type    Type
+	Name    string
+	Action  AuditAction
+}
+
+func (opts FindAuditOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OwnerID != 0 {
+		cond = cond.And(builder.Eq{"package_audit.owner_id": opts.OwnerID})
+	}
+	if opts.Type != "" {
+		cond = cond.And(builder.Eq{"package_audit.type": opts.Type})
+	}
+	if opts.Name != "" {
+		cond = cond.And(builder.Expr("LOWER(package_audit.name) = ?", strings.ToLower(opts.Name)))
+	}
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"package_audit.action": opts.Action})
+	}
+	return cond
+}
+
+func (opts FindAuditOptions) ToOrders() string {
+	return "package_audit.created_unix DESC, package_audit.id DESC"
+}
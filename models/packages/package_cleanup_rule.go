@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/gobwas/glob"
 	"xorm.io/builder"
 )
 
@@ -34,12 +35,15 @@ type PackageCleanupRule struct {
 	RemovePattern        string             `xorm:"NOT NULL DEFAULT ''"`
 	RemovePatternMatcher *regexp.Regexp     `xorm:"-"`
 	MatchFullName        bool               `xorm:"NOT NULL DEFAULT false"`
+	RemoveUntaggedDays   int                `xorm:"NOT NULL DEFAULT 0"`
+	ProtectedTags        []string           `xorm:"JSON TEXT"`
+	ProtectedTagMatchers []glob.Glob        `xorm:"-"`
 	CreatedUnix          timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
 	UpdatedUnix          timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
 }
 
 func (pcr *PackageCleanupRule) CompiledPattern() error {
-	if pcr.KeepPatternMatcher != nil || pcr.RemovePatternMatcher != nil {
+	if pcr.KeepPatternMatcher != nil || pcr.RemovePatternMatcher != nil || pcr.ProtectedTagMatchers != nil {
 		return nil
 	}
 
@@ -59,9 +63,27 @@ func (pcr *PackageCleanupRule) CompiledPattern() error {
 		}
 	}
 
+	for _, pattern := range pcr.ProtectedTags {
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid protected tag pattern %q: %w", pattern, err)
+		}
+		pcr.ProtectedTagMatchers = append(pcr.ProtectedTagMatchers, g)
+	}
+
 	return nil
 }
 
+// IsProtectedTag returns whether the tag matches a protected tag pattern, the patterns must be compiled
+func (pcr *PackageCleanupRule) IsProtectedTag(tag string) bool {
+	for _, g := range pcr.ProtectedTagMatchers {
+		if g.Match(tag) {
+			return true
+		}
+	}
+	return false
+}
+
 func InsertCleanupRule(ctx context.Context, pcr *PackageCleanupRule) (*PackageCleanupRule, error) {
 	return pcr, db.Insert(ctx, pcr)
 }
@@ -118,6 +118,7 @@ func DeleteFileByID(ctx context.Context, fileID int64) error {
 // PackageFileSearchOptions are options for SearchXXX methods
 type PackageFileSearchOptions struct {
 	OwnerID       int64
+	RepoID        int64
 	PackageType   Type
 	VersionID     int64
 	Query         string
@@ -134,13 +135,16 @@ func (opts *PackageFileSearchOptions) toConds() builder.Cond {
 
 	if opts.VersionID != 0 {
 		cond = cond.And(builder.Eq{"package_file.version_id": opts.VersionID})
-	} else if opts.OwnerID != 0 || (opts.PackageType != "" && opts.PackageType != "all") {
+	} else if opts.OwnerID != 0 || opts.RepoID != 0 || (opts.PackageType != "" && opts.PackageType != "all") {
 		var versionCond builder.Cond = builder.Eq{
 			"package_version.is_internal": false,
 		}
 		if opts.OwnerID != 0 {
 			versionCond = versionCond.And(builder.Eq{"package.owner_id": opts.OwnerID})
 		}
+		if opts.RepoID != 0 {
+			versionCond = versionCond.And(builder.Eq{"package.repo_id": opts.RepoID})
+		}
 		if opts.PackageType != "" && opts.PackageType != "all" {
 			versionCond = versionCond.And(builder.Eq{"package.type": opts.PackageType})
 		}
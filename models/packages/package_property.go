@@ -26,6 +26,9 @@ const (
 	PropertyTypePackage // 2
 )
 
+// PropertyPromotedFrom is the version property holding the name of the owner a package version was promoted from
+const PropertyPromotedFrom = "promoted.from"
+
 // PackageProperty represents a property of a package, version or file
 type PackageProperty struct {
 	ID      int64        `xorm:"pk autoincr"`
@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// PropertyUpstreamURL is the version property holding the url a cached package file was fetched from
+const PropertyUpstreamURL = "upstream.url"
+
+var ErrPackageUpstreamNotExist = util.NewNotExistErrorf("package upstream does not exist")
+
+func init() {
+	db.RegisterModel(new(PackageUpstream))
+}
+
+// PackageUpstream represents an upstream registry, missing package versions of the owner are fetched from it and cached
+type PackageUpstream struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Type        Type               `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	URL         string             `xorm:"TEXT NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+}
+
+// GetUpstreamByOwnerAndType gets the upstream of a type of packages of an owner
+func GetUpstreamByOwnerAndType(ctx context.Context, ownerID int64, packageType Type) (*PackageUpstream, error) {
+	pu := &PackageUpstream{}
+
+	has, err := db.GetEngine(ctx).Where("owner_id = ? AND type = ?", ownerID, packageType).Get(pu)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageUpstreamNotExist
+	}
+	return pu, nil
+}
+
+// GetUpstreamsByOwner gets the upstreams of all types of packages of an owner
+func GetUpstreamsByOwner(ctx context.Context, ownerID int64) ([]*PackageUpstream, error) {
+	pus := make([]*PackageUpstream, 0, 5)
+	return pus, db.GetEngine(ctx).Where("owner_id = ?", ownerID).OrderBy("type").Find(&pus)
+}
+
+// SetUpstream inserts the upstream or updates the url of the existing upstream of the owner and type
+func SetUpstream(ctx context.Context, ownerID int64, packageType Type, upstreamURL string) (*PackageUpstream, error) {
+	var pu *PackageUpstream
+	err := db.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		pu, err = GetUpstreamByOwnerAndType(ctx, ownerID, packageType)
+		if err == ErrPackageUpstreamNotExist {
+			pu = &PackageUpstream{
+				OwnerID: ownerID,
+				Type:    packageType,
+				URL:     upstreamURL,
+			}
+			return db.Insert(ctx, pu)
+		} else if err != nil {
+			return err
+		}
+
+		pu.URL = upstreamURL
+		_, err = db.GetEngine(ctx).ID(pu.ID).Cols("url").Update(pu)
+		return err
+	})
+	return pu, err
+}
+
+// DeleteUpstreamByOwnerAndType deletes the upstream of a type of packages of an owner
+func DeleteUpstreamByOwnerAndType(ctx context.Context, ownerID int64, packageType Type) error {
+	n, err := db.GetEngine(ctx).Where("owner_id = ? AND type = ?", ownerID, packageType).Delete(&PackageUpstream{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrPackageUpstreamNotExist
+	}
+	return nil
+}
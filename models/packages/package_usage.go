@@ -0,0 +1,68 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TypeUsage is the storage used by the packages of a type of an owner
+type TypeUsage struct {
+	Type         Type  `xorm:"type"`
+	PackageCount int64 `xorm:"package_count"`
+	VersionCount int64 `xorm:"version_count"`
+	Size         int64 `xorm:"size"`
+}
+
+// PackageSize is the storage used by all versions of a package
+type PackageSize struct {
+	PackageID    int64  `xorm:"package_id"`
+	OwnerID      int64  `xorm:"owner_id"`
+	Type         Type   `xorm:"type"`
+	Name         string `xorm:"name"`
+	VersionCount int64  `xorm:"version_count"`
+	Size         int64  `xorm:"size"`
+}
+
+// usageSession selects the files of the non-internal package versions matching the condition.
+// Like CalculateFileSize it does NOT respect the deduplication of blobs.
+func usageSession(ctx context.Context, cond builder.Cond) *xorm.Session {
+	return db.GetEngine(ctx).
+		Table("package_file").
+		Join("INNER", "package_blob", "package_blob.id = package_file.blob_id").
+		Join("INNER", "package_version", "package_version.id = package_file.version_id").
+		Join("INNER", "package", "package.id = package_version.package_id").
+		Where(builder.Eq{"package_version.is_internal": false}.And(cond))
+}
+
+// GetUsageByType returns the storage used by the packages of the owner for each package type, the largest first
+func GetUsageByType(ctx context.Context, ownerID int64) ([]*TypeUsage, error) {
+	usages := make([]*TypeUsage, 0, 5)
+	return usages, usageSession(ctx, builder.Eq{"package.owner_id": ownerID}).
+		Select("package.type AS type, COUNT(DISTINCT package.id) AS package_count, COUNT(DISTINCT package_version.id) AS version_count, SUM(package_blob.size) AS size").
+		GroupBy("package.type").
+		OrderBy("size DESC, package.type").
+		Find(&usages)
+}
+
+// GetLargestPackages returns the packages using the most storage of all owners, optionally of a type
+func GetLargestPackages(ctx context.Context, packageType Type, limit int) ([]*PackageSize, error) {
+	cond := builder.NewCond()
+	if packageType != "" {
+		cond = builder.Eq{"package.type": packageType}
+	}
+
+	sizes := make([]*PackageSize, 0, limit)
+	return sizes, usageSession(ctx, cond).
+		Select("package.id AS package_id, package.owner_id AS owner_id, package.type AS type, package.name AS name, COUNT(DISTINCT package_version.id) AS version_count, SUM(package_blob.size) AS size").
+		GroupBy("package.id, package.owner_id, package.type, package.name").
+		OrderBy("size DESC, package.id").
+		Limit(limit).
+		Find(&sizes)
+}
@@ -11,17 +11,39 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
+// AutoMergeConditions are the conditions a pull request scheduled for merging has to meet in addition to succeeding checks
+type AutoMergeConditions struct {
+	// RequiredApprovals is the minimum number of official approvals
+	RequiredApprovals int64 `xorm:"NOT NULL DEFAULT 0"`
+	// NoChangesRequested requires that no official review requests changes
+	NoChangesRequested bool `xorm:"NOT NULL DEFAULT false"`
+	// MergeAfterUnix is the time before which the pull request isn't merged
+	MergeAfterUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// AfterPullID is the ID of a pull request which has to be merged first
+	AfterPullID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+// IsEmpty returns whether there are no conditions
+func (conditions *AutoMergeConditions) IsEmpty() bool {
+	return conditions.RequiredApprovals == 0 && !conditions.NoChangesRequested && conditions.MergeAfterUnix == 0 && conditions.AfterPullID == 0
+}
+
 // AutoMerge represents a pull request scheduled for merging when checks succeed
 type AutoMerge struct {
-	ID          int64                 `xorm:"pk autoincr"`
-	PullID      int64                 `xorm:"UNIQUE"`
-	DoerID      int64                 `xorm:"INDEX NOT NULL"`
-	Doer        *user_model.User      `xorm:"-"`
-	MergeStyle  repo_model.MergeStyle `xorm:"varchar(30)"`
-	Message     string                `xorm:"LONGTEXT"`
-	CreatedUnix timeutil.TimeStamp    `xorm:"created"`
+	ID                  int64                 `xorm:"pk autoincr"`
+	PullID              int64                 `xorm:"UNIQUE"`
+	DoerID              int64                 `xorm:"INDEX NOT NULL"`
+	Doer                *user_model.User      `xorm:"-"`
+	MergeStyle          repo_model.MergeStyle `xorm:"varchar(30)"`
+	Message             string                `xorm:"LONGTEXT"`
+	AutoMergeConditions `xorm:"extends"`
+	// MergeAfterQueued is set once the pull request was queued for merging because MergeAfterUnix passed
+	MergeAfterQueued bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix      timeutil.TimeStamp `xorm:"created"`
 }
 
 // TableName return database table name for xorm
@@ -48,8 +70,8 @@ func IsErrAlreadyScheduledToAutoMerge(err error) bool {
 	return ok
 }
 
-// ScheduleAutoMerge schedules a pull request to be merged when all checks succeed
-func ScheduleAutoMerge(ctx context.Context, doer *user_model.User, pullID int64, style repo_model.MergeStyle, message string) error {
+// ScheduleAutoMerge schedules a pull request to be merged when all checks succeed and the conditions are met
+func ScheduleAutoMerge(ctx context.Context, doer *user_model.User, pullID int64, style repo_model.MergeStyle, message string, conditions AutoMergeConditions) error {
 	// Check if we already have a merge scheduled for that pull request
 	if exists, _, err := GetScheduledMergeByPullID(ctx, pullID); err != nil {
 		return err
@@ -58,10 +80,11 @@ func ScheduleAutoMerge(ctx context.Context, doer *user_model.User, pullID int64,
 	}
 
 	_, err := db.GetEngine(ctx).Insert(&AutoMerge{
-		DoerID:     doer.ID,
-		PullID:     pullID,
-		MergeStyle: style,
-		Message:    message,
+		DoerID:              doer.ID,
+		PullID:              pullID,
+		MergeStyle:          style,
+		Message:             message,
+		AutoMergeConditions: conditions,
 	})
 	return err
 }
@@ -95,3 +118,27 @@ func DeleteScheduledAutoMerge(ctx context.Context, pullID int64) error {
 	_, err = db.GetEngine(ctx).ID(scheduledPRM.ID).Delete(&AutoMerge{})
 	return err
 }
+
+// GetDueAutoMergePullIDs returns the IDs of the pull requests scheduled for merging after a time which has passed
+// and which haven't been queued for merging yet, they are marked as queued
+func GetDueAutoMergePullIDs(ctx context.Context, now timeutil.TimeStamp) ([]int64, error) {
+	var pullIDs []int64
+	err := db.WithTx(ctx, func(ctx context.Context) error {
+		cond := builder.Eq{"merge_after_queued": false}.And(builder.Gt{"merge_after_unix": 0}, builder.Lte{"merge_after_unix": now})
+		if err := db.GetEngine(ctx).Table("pull_auto_merge").Where(cond).Cols("pull_id").Find(&pullIDs); err != nil {
+			return err
+		}
+		if len(pullIDs) == 0 {
+			return nil
+		}
+		_, err := db.GetEngine(ctx).In("pull_id", pullIDs).Cols("merge_after_queued").Update(&AutoMerge{MergeAfterQueued: true})
+		return err
+	})
+	return pullIDs, err
+}
+
+// GetAutoMergePullIDsAfterPull returns the IDs of the pull requests scheduled for merging after the given pull request
+func GetAutoMergePullIDsAfterPull(ctx context.Context, pullID int64) ([]int64, error) {
+	var pullIDs []int64
+	return pullIDs, db.GetEngine(ctx).Table("pull_auto_merge").Where("after_pull_id = ?", pullID).Cols("pull_id").Find(&pullIDs)
+}
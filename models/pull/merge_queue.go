@@ -0,0 +1,170 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MergeQueue represents the queue of the pull requests waiting to be merged into a branch
+type MergeQueue struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	BaseBranch  string             `xorm:"UNIQUE(s) NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// MergeQueueEntry represents a pull request in a merge queue, the entries of a queue are ordered by their ID
+type MergeQueueEntry struct {
+	ID           int64                 `xorm:"pk autoincr"`
+	QueueID      int64                 `xorm:"INDEX NOT NULL"`
+	RepoID       int64                 `xorm:"INDEX NOT NULL"`
+	PullID       int64                 `xorm:"UNIQUE NOT NULL"`
+	DoerID       int64                 `xorm:"INDEX NOT NULL"`
+	Doer         *user_model.User      `xorm:"-"`
+	MergeStyle   repo_model.MergeStyle `xorm:"varchar(30)"`
+	Message      string                `xorm:"LONGTEXT"`
+	HeadCommitID string                `xorm:"VARCHAR(64)"` // the head commit of the pull request when it was added to the queue
+	// BaseCommitID is the commit the speculative merge commit has been created on,
+	// it is the speculative merge commit of the previous entry or the head of the base branch for the first entry
+	BaseCommitID  string             `xorm:"VARCHAR(64)"`
+	MergeCommitID string             `xorm:"VARCHAR(64) INDEX"` // the speculative merge commit, empty while it hasn't been created
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(MergeQueue))
+	db.RegisterModel(new(MergeQueueEntry))
+}
+
+// ErrAlreadyInMergeQueue represents a "PullRequestAlreadyInMergeQueue"-error
+type ErrAlreadyInMergeQueue struct {
+	PullID int64
+}
+
+func (err ErrAlreadyInMergeQueue) Error() string {
+	return fmt.Sprintf("pull request is already in the merge queue [pull_id: %d]", err.PullID)
+}
+
+// IsErrAlreadyInMergeQueue checks if an error is a ErrAlreadyInMergeQueue.
+func IsErrAlreadyInMergeQueue(err error) bool {
+	_, ok := err.(ErrAlreadyInMergeQueue)
+	return ok
+}
+
+// GetMergeQueueByID returns the merge queue with the given id
+func GetMergeQueueByID(ctx context.Context, id int64) (*MergeQueue, error) {
+	queue, exist, err := db.GetByID[MergeQueue](ctx, id)
+	if err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, db.ErrNotExist{Resource: "merge_queue", ID: id}
+	}
+	return queue, nil
+}
+
+// GetMergeQueue returns the merge queue of a branch, nil is returned if the branch has no queue
+func GetMergeQueue(ctx context.Context, repoID int64, baseBranch string) (*MergeQueue, error) {
+	queue := &MergeQueue{}
+	exist, err := db.GetEngine(ctx).Where("repo_id = ? AND base_branch = ?", repoID, baseBranch).Get(queue)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// AddToMergeQueue appends a pull request to the merge queue of its base branch, the queue is created if needed
+func AddToMergeQueue(ctx context.Context, entry *MergeQueueEntry, baseBranch string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if exist, _, err := GetMergeQueueEntryByPullID(ctx, entry.PullID); err != nil {
+			return err
+		} else if exist {
+			return ErrAlreadyInMergeQueue{PullID: entry.PullID}
+		}
+
+		queue, err := GetMergeQueue(ctx, entry.RepoID, baseBranch)
+		if err != nil {
+			return err
+		}
+		if queue == nil {
+			queue = &MergeQueue{RepoID: entry.RepoID, BaseBranch: baseBranch}
+			if err := db.Insert(ctx, queue); err != nil {
+				return err
+			}
+		}
+
+		entry.QueueID = queue.ID
+		return db.Insert(ctx, entry)
+	})
+}
+
+// GetMergeQueueEntries returns the entries of a merge queue in their merge order
+func GetMergeQueueEntries(ctx context.Context, queueID int64) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 10)
+	return entries, db.GetEngine(ctx).Where("queue_id = ?", queueID).OrderBy("id").Find(&entries)
+}
+
+// GetMergeQueueEntriesByMergeCommitID returns the entries of the repository whose speculative merge commit is the given one
+func GetMergeQueueEntriesByMergeCommitID(ctx context.Context, repoID int64, commitID string) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 1)
+	return entries, db.GetEngine(ctx).Where("repo_id = ? AND merge_commit_id = ?", repoID, commitID).Find(&entries)
+}
+
+// GetMergeQueueEntryByPullID returns the merge queue entry of a pull request
+func GetMergeQueueEntryByPullID(ctx context.Context, pullID int64) (bool, *MergeQueueEntry, error) {
+	entry := &MergeQueueEntry{}
+	exist, err := db.GetEngine(ctx).Where("pull_id = ?", pullID).Get(entry)
+	if err != nil || !exist {
+		return false, nil, err
+	}
+	return true, entry, nil
+}
+
+// LoadDoer loads the user who added the pull request to the merge queue
+func (entry *MergeQueueEntry) LoadDoer(ctx context.Context) (err error) {
+	if entry.Doer != nil {
+		return nil
+	}
+	entry.Doer, err = user_model.GetPossibleUserByID(ctx, entry.DoerID)
+	return err
+}
+
+// IsTesting returns whether the speculative merge commit of the entry has been created and is waiting for its checks
+func (entry *MergeQueueEntry) IsTesting() bool {
+	return entry.MergeCommitID != ""
+}
+
+// UpdateMergeQueueEntrySpeculativeMerge records the speculative merge commit of an entry
+func UpdateMergeQueueEntrySpeculativeMerge(ctx context.Context, entry *MergeQueueEntry) error {
+	_, err := db.GetEngine(ctx).ID(entry.ID).Cols("base_commit_id", "merge_commit_id").Update(entry)
+	return err
+}
+
+// DeleteMergeQueueEntry removes the pull request from its merge queue
+func DeleteMergeQueueEntry(ctx context.Context, pullID int64) error {
+	n, err := db.GetEngine(ctx).Where("pull_id = ?", pullID).Delete(&MergeQueueEntry{})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotExist{Resource: "merge_queue_entry", ID: pullID}
+	}
+	return nil
+}
+
+// MergeQueuePosition returns the 1-based position of the pull request in its merge queue
+func MergeQueuePosition(ctx context.Context, entry *MergeQueueEntry) (int64, error) {
+	n, err := db.GetEngine(ctx).Where("queue_id = ? AND id < ?", entry.QueueID, entry.ID).Count(&MergeQueueEntry{})
+	if err != nil {
+		return 0, err
+	}
+	return n + 1, nil
+}
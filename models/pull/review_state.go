@@ -137,3 +137,20 @@ func getNewestReviewStateApartFrom(ctx context.Context, userID, pullID int64, co
 	// As we have no error cases left, the result must be the first element in the list
 	return &reviews[0], nil
 }
+
+// GetNewestReviewStatesByPullIDs gets the newest review of the user for each of the given PRs.
+// PRs the user has not yet reviewed are missing from the returned map.
+func GetNewestReviewStatesByPullIDs(ctx context.Context, userID int64, pullIDs []int64) (map[int64]*ReviewState, error) {
+	reviews := make(map[int64]*ReviewState, len(pullIDs))
+	if len(pullIDs) == 0 {
+		return reviews, nil
+	}
+	var states []*ReviewState
+	if err := db.GetEngine(ctx).Where("user_id = ?", userID).In("pull_id", pullIDs).OrderBy("updated_unix ASC").Find(&states); err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		reviews[state.PullID] = state
+	}
+	return reviews, nil
+}
@@ -112,6 +112,10 @@ func repoStatsCorrectIssueNumComments(ctx context.Context, id int64) error {
 	return StatsCorrectSQL(ctx, "UPDATE `issue` SET num_comments=(SELECT COUNT(*) FROM `comment` WHERE issue_id=? AND type=0) WHERE id=?", id)
 }
 
+func repoStatsCorrectIssueNumVotes(ctx context.Context, id int64) error {
+	return StatsCorrectSQL(ctx, "UPDATE `issue` SET num_votes=(SELECT COUNT(*) FROM `issue_vote` WHERE issue_id=?) WHERE id=?", id)
+}
+
 func repoStatsCorrectNumIssues(ctx context.Context, id int64) error {
 	return repo_model.UpdateRepoIssueNumbers(ctx, id, false, false)
 }
@@ -205,6 +209,12 @@ func CheckRepoStats(ctx context.Context) error {
 			repoStatsCorrectIssueNumComments,
 			"issue count 'num_comments'",
 		},
+		// Issue.NumVotes
+		{
+			statsQuery("SELECT `issue`.id FROM `issue` WHERE `issue`.num_votes!=(SELECT COUNT(*) FROM `issue_vote` WHERE issue_id=`issue`.id)"),
+			repoStatsCorrectIssueNumVotes,
+			"issue count 'num_votes'",
+		},
 	}
 	for _, checker := range checkers {
 		select {
@@ -5,7 +5,9 @@ package repo
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -34,16 +36,40 @@ type RepoArchiver struct { //revive:disable-line:exported
 	Type        git.ArchiveType `xorm:"unique(s)"`
 	Status      ArchiverStatus
 	CommitID    string             `xorm:"VARCHAR(64) unique(s)"`
+	Include     string             `xorm:"VARCHAR(50) NOT NULL DEFAULT '' unique(s)"`  // sorted comma separated list of the extra contents, see ArchiveInclude
+	Path        string             `xorm:"VARCHAR(255) NOT NULL DEFAULT '' unique(s)"` // the path of the tree the archive is limited to, empty for the whole tree
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL created"`
 }
 
+// ArchiveInclude represents extra contents an archive can include in addition to the git tree
+type ArchiveInclude string
+
+// enumerate all the extra contents of an archive
+const (
+	ArchiveIncludeLFS        ArchiveInclude = "lfs"        // the LFS objects instead of their pointers
+	ArchiveIncludeSubmodules ArchiveInclude = "submodules" // the trees of the submodules hosted on this instance
+)
+
 func init() {
 	db.RegisterModel(new(RepoArchiver))
 }
 
 // RelativePath returns the archive path relative to the archive storage root.
 func (archiver *RepoArchiver) RelativePath() string {
-	return fmt.Sprintf("%d/%s/%s.%s", archiver.RepoID, archiver.CommitID[:2], archiver.CommitID, archiver.Type.String())
+	name := archiver.CommitID
+	if archiver.Include != "" {
+		name += "+" + strings.ReplaceAll(archiver.Include, ",", "+")
+	}
+	if archiver.Path != "" {
+		// the path is encoded to be a valid file name which can be decoded by repoArchiverForRelativePath
+		name += "@" + base64.RawURLEncoding.EncodeToString([]byte(archiver.Path))
+	}
+	return fmt.Sprintf("%d/%s/%s.%s", archiver.RepoID, archiver.CommitID[:2], name, archiver.Type.String())
+}
+
+// HasInclude returns whether the archive includes the given extra content
+func (archiver *RepoArchiver) HasInclude(include ArchiveInclude) bool {
+	return slices.Contains(strings.Split(archiver.Include, ","), string(include))
 }
 
 // repoArchiverForRelativePath takes a relativePath created from (archiver *RepoArchiver) RelativePath() and creates a shell repoArchiver struct representing it
@@ -61,17 +87,26 @@ func repoArchiverForRelativePath(relativePath string) (*RepoArchiver, error) {
 		return nil, util.SilentWrap{Message: fmt.Sprintf("invalid storage path: %s", relativePath), Err: util.ErrInvalidArgument}
 	}
 
+	name, encodedPath, _ := strings.Cut(nameExts[0], "@")
+	treePath, err := base64.RawURLEncoding.DecodeString(encodedPath)
+	if err != nil {
+		return nil, util.SilentWrap{Message: fmt.Sprintf("invalid storage path: %s", relativePath), Err: util.ErrInvalidArgument}
+	}
+	name, include, _ := strings.Cut(name, "+")
+
 	return &RepoArchiver{
 		RepoID:   repoID,
-		CommitID: parts[1] + nameExts[0],
+		CommitID: parts[1] + name,
 		Type:     git.ToArchiveType(nameExts[1]),
+		Include:  strings.ReplaceAll(include, "+", ","),
+		Path:     string(treePath),
 	}, nil
 }
 
 // GetRepoArchiver get an archiver
-func GetRepoArchiver(ctx context.Context, repoID int64, tp git.ArchiveType, commitID string) (*RepoArchiver, error) {
+func GetRepoArchiver(ctx context.Context, repoID int64, tp git.ArchiveType, commitID, include, treePath string) (*RepoArchiver, error) {
 	var archiver RepoArchiver
-	has, err := db.GetEngine(ctx).Where("repo_id=?", repoID).And("`type`=?", tp).And("commit_id=?", commitID).Get(&archiver)
+	has, err := db.GetEngine(ctx).Where("repo_id=?", repoID).And("`type`=?", tp).And("commit_id=?", commitID).And("include=?", include).And("path=?", treePath).Get(&archiver)
 	if err != nil {
 		return nil, err
 	}
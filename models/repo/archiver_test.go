@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoArchiverRelativePath(t *testing.T) {
+	for _, archiver := range []*RepoArchiver{
+		{RepoID: 1, Type: git.ZIP, CommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d"},
+		{RepoID: 1, Type: git.TARGZ, CommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Include: "lfs,submodules"},
+		{RepoID: 1, Type: git.TARGZ, CommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Include: "lfs", Path: "docs/api+v1.d"},
+	} {
+		parsed, err := repoArchiverForRelativePath(archiver.RelativePath())
+		assert.NoError(t, err)
+		assert.Equal(t, archiver.Type, parsed.Type)
+		assert.Equal(t, archiver.Include, parsed.Include)
+		assert.Equal(t, archiver.Path, parsed.Path)
+	}
+
+	archiver := &RepoArchiver{RepoID: 1, Type: git.TARGZ, CommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Path: "docs"}
+	assert.Equal(t, "1/65/65f1bf27bc3bf70f64657658635e66094edbcb4d@ZG9jcw.tar.gz", archiver.RelativePath())
+}
@@ -33,6 +33,36 @@ type Attachment struct {
 	Size              int64              `xorm:"DEFAULT 0"`
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	CustomDownloadURL string             `xorm:"-"`
+
+	// External attachments are not stored by Gitea, downloads are redirected to ExternalURL.
+	// The file is expected to match ExternalSHA256 and Size, this is checked periodically.
+	ExternalURL         string              `xorm:"TEXT"`
+	ExternalSHA256      string              `xorm:"VARCHAR(64)"`
+	ExternalStatus      ExternalAssetStatus `xorm:"NOT NULL DEFAULT 0"`
+	ExternalCheckedUnix timeutil.TimeStamp
+}
+
+// ExternalAssetStatus represents the result of the last verification of an external attachment
+type ExternalAssetStatus int
+
+const (
+	ExternalAssetUnverified  ExternalAssetStatus = iota // not checked yet
+	ExternalAssetVerified                               // the file matches the expected checksum and size
+	ExternalAssetMismatch                               // the file doesn't match the expected checksum or size
+	ExternalAssetUnreachable                            // the file could not be downloaded
+)
+
+// String returns the name of the status
+func (s ExternalAssetStatus) String() string {
+	switch s {
+	case ExternalAssetVerified:
+		return "verified"
+	case ExternalAssetMismatch:
+		return "mismatch"
+	case ExternalAssetUnreachable:
+		return "unreachable"
+	}
+	return "unverified"
 }
 
 func init() {
@@ -49,6 +79,11 @@ func (a *Attachment) IncreaseDownloadCount(ctx context.Context) error {
 	return nil
 }
 
+// IsExternal returns whether the attachment points to a file hosted outside of Gitea
+func (a *Attachment) IsExternal() bool {
+	return a.ExternalURL != ""
+}
+
 // AttachmentRelativePath returns the relative path
 func AttachmentRelativePath(uuid string) string {
 	return path.Join(uuid[0:1], uuid[1:2], uuid)
@@ -190,6 +225,9 @@ func DeleteAttachments(ctx context.Context, attachments []*Attachment, remove bo
 
 	if remove {
 		for i, a := range attachments {
+			if a.IsExternal() {
+				continue
+			}
 			if err := storage.Attachments.Delete(a.RelativePath()); err != nil {
 				if !errors.Is(err, os.ErrNotExist) {
 					return i, err
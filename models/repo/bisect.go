@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoBisectSession represents the bisection of the history of a repository by a user to find the commit introducing a bug,
+// a user has at most one session per repository.
+type RepoBisectSession struct { //revive:disable-line:exported
+	ID      int64    `xorm:"pk autoincr"`
+	RepoID  int64    `xorm:"UNIQUE(s) NOT NULL"`
+	UserID  int64    `xorm:"UNIQUE(s) NOT NULL"`
+	Bad     string   `xorm:"VARCHAR(64) NOT NULL"`
+	Good    []string `xorm:"JSON TEXT"`
+	Skipped []string `xorm:"JSON TEXT"`
+	Path    string   `xorm:"TEXT"`
+
+	// the next step computed from the commits marked so far
+	Candidate   string   `xorm:"VARCHAR(64)"`
+	Remaining   int      `xorm:"NOT NULL DEFAULT 0"`
+	Steps       int      `xorm:"NOT NULL DEFAULT 0"`
+	FirstBad    string   `xorm:"VARCHAR(64)"`
+	OnlySkipped []string `xorm:"JSON TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoBisectSession))
+}
+
+// GetRepoBisectSession returns the bisect session of the user in the repository, nil if there is none
+func GetRepoBisectSession(ctx context.Context, repoID, userID int64) (*RepoBisectSession, error) {
+	session := new(RepoBisectSession)
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND user_id = ?", repoID, userID).Get(session)
+	if err != nil || !has {
+		return nil, err
+	}
+	return session, nil
+}
+
+// SaveRepoBisectSession creates the bisect session of its user in its repository, or updates it if it exists
+func SaveRepoBisectSession(ctx context.Context, session *RepoBisectSession) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetRepoBisectSession(ctx, session.RepoID, session.UserID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			session.ID = 0
+			return db.Insert(ctx, session)
+		}
+		session.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(session.ID).AllCols().Update(session)
+		return err
+	})
+}
+
+// DeleteRepoBisectSession deletes the bisect session of the user in the repository
+func DeleteRepoBisectSession(ctx context.Context, repoID, userID int64) (bool, error) {
+	deleted, err := db.GetEngine(ctx).Where("repo_id = ? AND user_id = ?", repoID, userID).Delete(new(RepoBisectSession))
+	return deleted > 0, err
+}
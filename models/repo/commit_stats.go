@@ -0,0 +1,142 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// RepoContributorWeek holds the statistics of the commits of a contributor to the default branch of a repository for a week
+type RepoContributorWeek struct { //revive:disable-line:exported
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE(s) NOT NULL"`
+	// Email is the lowercased email of the author of the commits
+	Email string `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	// Week is the unix timestamp of the start of the week, on Sunday at 00:00 UTC
+	Week      int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Name      string `xorm:"VARCHAR(255)"`
+	Commits   int64  `xorm:"NOT NULL DEFAULT 0"`
+	Additions int64  `xorm:"NOT NULL DEFAULT 0"`
+	Deletions int64  `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// RepoPunchCard holds the number of commits to the default branch of a repository for an hour of a weekday,
+// in the time zones of their authors
+type RepoPunchCard struct { //revive:disable-line:exported
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE(s) NOT NULL"`
+	Weekday int   `xorm:"UNIQUE(s) NOT NULL"`
+	Hour    int   `xorm:"UNIQUE(s) NOT NULL"`
+	Commits int64 `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoContributorWeek))
+	db.RegisterModel(new(RepoPunchCard))
+}
+
+// AddRepoCommitStats adds the statistics of new commits to the ones of a repository,
+// the previous statistics are deleted first if reset is true.
+func AddRepoCommitStats(ctx context.Context, repoID int64, weeks []*RepoContributorWeek, punchCard []*RepoPunchCard, reset bool) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if reset {
+			if err := DeleteRepoCommitStats(ctx, repoID); err != nil {
+				return err
+			}
+		}
+
+		e := db.GetEngine(ctx)
+		for _, week := range weeks {
+			affected, err := e.Where("repo_id = ? AND email = ? AND week = ?", repoID, week.Email, week.Week).
+				Incr("commits", week.Commits).Incr("additions", week.Additions).Incr("deletions", week.Deletions).
+				Update(new(RepoContributorWeek))
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				week.ID = 0
+				week.RepoID = repoID
+				if err := db.Insert(ctx, week); err != nil {
+					return err
+				}
+			}
+		}
+		for _, card := range punchCard {
+			affected, err := e.Where("repo_id = ? AND weekday = ? AND hour = ?", repoID, card.Weekday, card.Hour).
+				Incr("commits", card.Commits).
+				Update(new(RepoPunchCard))
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				card.ID = 0
+				card.RepoID = repoID
+				if err := db.Insert(ctx, card); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteRepoCommitStats deletes the commit statistics of a repository
+func DeleteRepoCommitStats(ctx context.Context, repoID int64) error {
+	if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(RepoContributorWeek)); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(RepoPunchCard))
+	return err
+}
+
+// RepoCodeFrequency represents the lines added and deleted in a repository during a week
+type RepoCodeFrequency struct { //revive:disable-line:exported
+	Week      int64
+	Additions int64
+	Deletions int64
+}
+
+// GetRepoCodeFrequency returns the lines added and deleted in a repository for each week with commits, in chronological order
+func GetRepoCodeFrequency(ctx context.Context, repoID int64) ([]*RepoCodeFrequency, error) {
+	weeks := make([]*RepoCodeFrequency, 0, 50)
+	return weeks, db.GetEngine(ctx).Table("repo_contributor_week").
+		Select("week, SUM(additions) AS additions, SUM(deletions) AS deletions").
+		Where("repo_id = ?", repoID).
+		GroupBy("week").
+		OrderBy("week").
+		Find(&weeks)
+}
+
+// GetRepoPunchCard returns the number of commits of a repository for each hour of the weekdays with commits
+func GetRepoPunchCard(ctx context.Context, repoID int64) ([]*RepoPunchCard, error) {
+	cards := make([]*RepoPunchCard, 0, 7*24)
+	return cards, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("weekday, hour").Find(&cards)
+}
+
+// RepoContributorTotal represents the statistics of the commits of a contributor to a repository
+type RepoContributorTotal struct { //revive:disable-line:exported
+	Email     string
+	Name      string
+	Commits   int64
+	Additions int64
+	Deletions int64
+}
+
+// GetRepoContributorTotals returns the statistics of the contributors of a repository for the weeks starting in [since, until),
+// a zero bound is ignored. The contributors with the most commits come first.
+func GetRepoContributorTotals(ctx context.Context, repoID, since, until int64) ([]*RepoContributorTotal, error) {
+	sess := db.GetEngine(ctx).Table("repo_contributor_week").
+		Select("email, MAX(name) AS name, SUM(commits) AS commits, SUM(additions) AS additions, SUM(deletions) AS deletions").
+		Where("repo_id = ?", repoID)
+	if since > 0 {
+		sess.And("week >= ?", since)
+	}
+	if until > 0 {
+		sess.And("week < ?", until)
+	}
+	totals := make([]*RepoContributorTotal, 0, 10)
+	return totals, sess.GroupBy("email").OrderBy("commits DESC, email").Find(&totals)
+}
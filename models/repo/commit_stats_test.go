@@ -0,0 +1,65 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoCommitStats(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	weeks := func() []*repo_model.RepoContributorWeek {
+		return []*repo_model.RepoContributorWeek{
+			{Email: "user2@example.com", Name: "User Two", Week: 1000, Commits: 2, Additions: 10, Deletions: 1},
+			{Email: "user4@example.com", Name: "User Four", Week: 1000, Commits: 1, Additions: 5},
+			{Email: "user4@example.com", Name: "User Four", Week: 2000, Commits: 1, Deletions: 3},
+		}
+	}
+	punchCard := []*repo_model.RepoPunchCard{{Weekday: 1, Hour: 9, Commits: 4}}
+	assert.NoError(t, repo_model.AddRepoCommitStats(db.DefaultContext, 1, weeks(), punchCard, false))
+	// the statistics of new commits are added to the existing ones
+	assert.NoError(t, repo_model.AddRepoCommitStats(db.DefaultContext, 1, weeks()[:1], punchCard, false))
+
+	frequency, err := repo_model.GetRepoCodeFrequency(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*repo_model.RepoCodeFrequency{
+		{Week: 1000, Additions: 25, Deletions: 2},
+		{Week: 2000, Deletions: 3},
+	}, frequency)
+
+	cards, err := repo_model.GetRepoPunchCard(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, cards, 1) {
+		assert.EqualValues(t, 8, cards[0].Commits)
+	}
+
+	totals, err := repo_model.GetRepoContributorTotals(db.DefaultContext, 1, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*repo_model.RepoContributorTotal{
+		{Email: "user2@example.com", Name: "User Two", Commits: 4, Additions: 20, Deletions: 2},
+		{Email: "user4@example.com", Name: "User Four", Commits: 2, Additions: 5, Deletions: 3},
+	}, totals)
+	totals, err = repo_model.GetRepoContributorTotals(db.DefaultContext, 1, 1500, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*repo_model.RepoContributorTotal{
+		{Email: "user4@example.com", Name: "User Four", Commits: 1, Deletions: 3},
+	}, totals)
+	totals, err = repo_model.GetRepoContributorTotals(db.DefaultContext, 1, 0, 1000)
+	assert.NoError(t, err)
+	assert.Empty(t, totals)
+
+	// the statistics are replaced on reset
+	assert.NoError(t, repo_model.AddRepoCommitStats(db.DefaultContext, 1, weeks()[2:], nil, true))
+	frequency, err = repo_model.GetRepoCodeFrequency(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*repo_model.RepoCodeFrequency{{Week: 2000, Deletions: 3}}, frequency)
+	unittest.AssertCount(t, &repo_model.RepoPunchCard{RepoID: 1}, 0)
+}
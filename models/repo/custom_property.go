@@ -0,0 +1,244 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// CustomPropertyType is the type of the values of a custom property
+type CustomPropertyType string
+
+const (
+	CustomPropertyTypeString       CustomPropertyType = "string"
+	CustomPropertyTypeSingleSelect CustomPropertyType = "single_select"
+	CustomPropertyTypeTrueFalse    CustomPropertyType = "true_false"
+)
+
+// IsValid checks if the custom property type is known
+func (t CustomPropertyType) IsValid() bool {
+	return t == CustomPropertyTypeString || t == CustomPropertyTypeSingleSelect || t == CustomPropertyTypeTrueFalse
+}
+
+// CustomProperty is a property defined by an organization which can be set on each of its repositories.
+// A repository without a value for the property has its default value, if any.
+type CustomProperty struct {
+	ID            int64              `xorm:"pk autoincr"`
+	OwnerID       int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Name          string             `xorm:"UNIQUE(s) VARCHAR(75) NOT NULL"`
+	Type          CustomPropertyType `xorm:"VARCHAR(20) NOT NULL"`
+	Description   string             `xorm:"TEXT"`
+	AllowedValues []string           `xorm:"TEXT JSON"`
+	DefaultValue  string             `xorm:"VARCHAR(255)"`
+	Required      bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+}
+
+// RepoCustomPropertyValue is the value of a custom property set on a repository
+type RepoCustomPropertyValue struct {
+	ID     int64  `xorm:"pk autoincr"`
+	RepoID int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Name   string `xorm:"UNIQUE(s) INDEX(name_value) VARCHAR(75) NOT NULL"`
+	Value  string `xorm:"INDEX(name_value) VARCHAR(255) NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(CustomProperty))
+	db.RegisterModel(new(RepoCustomPropertyValue))
+}
+
+var customPropertyNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,75}$`)
+
+// ValidateValue checks if the value can be set for the custom property
+func (p *CustomProperty) ValidateValue(value string) error {
+	if len(value) > 255 {
+		return util.NewInvalidArgumentErrorf("value of custom property %q is too long", p.Name)
+	}
+	switch p.Type {
+	case CustomPropertyTypeSingleSelect:
+		if !slices.Contains(p.AllowedValues, value) {
+			return util.NewInvalidArgumentErrorf("value %q is not allowed for custom property %q", value, p.Name)
+		}
+	case CustomPropertyTypeTrueFalse:
+		if value != "true" && value != "false" {
+			return util.NewInvalidArgumentErrorf("value of custom property %q must be true or false", p.Name)
+		}
+	}
+	return nil
+}
+
+// Validate checks if the definition of the custom property is consistent
+func (p *CustomProperty) Validate() error {
+	if !customPropertyNamePattern.MatchString(p.Name) {
+		return util.NewInvalidArgumentErrorf("invalid custom property name %q", p.Name)
+	}
+	if !p.Type.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid custom property type %q", p.Type)
+	}
+	if p.Type == CustomPropertyTypeSingleSelect && len(p.AllowedValues) == 0 {
+		return util.NewInvalidArgumentErrorf("custom property %q of type %s needs allowed values", p.Name, p.Type)
+	} else if p.Type != CustomPropertyTypeSingleSelect && len(p.AllowedValues) > 0 {
+		return util.NewInvalidArgumentErrorf("custom property %q of type %s can't have allowed values", p.Name, p.Type)
+	}
+	if p.Required && p.DefaultValue == "" {
+		return util.NewInvalidArgumentErrorf("required custom property %q needs a default value", p.Name)
+	}
+	if p.DefaultValue != "" {
+		return p.ValidateValue(p.DefaultValue)
+	}
+	return nil
+}
+
+// GetCustomProperties returns the custom properties defined by an owner
+func GetCustomProperties(ctx context.Context, ownerID int64) ([]*CustomProperty, error) {
+	props := make([]*CustomProperty, 0, 10)
+	return props, db.GetEngine(ctx).Where("owner_id = ?", ownerID).Asc("name").Find(&props)
+}
+
+// GetCustomPropertyByName returns the custom property of an owner with the given name
+func GetCustomPropertyByName(ctx context.Context, ownerID int64, name string) (*CustomProperty, error) {
+	prop := new(CustomProperty)
+	has, err := db.GetEngine(ctx).Where("owner_id = ? AND name = ?", ownerID, name).Get(prop)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("custom property %q does not exist", name)
+	}
+	return prop, nil
+}
+
+// SaveCustomProperty creates the custom property or updates the existing one of the owner with the same name,
+// it returns whether the custom property has been created.
+func SaveCustomProperty(ctx context.Context, prop *CustomProperty) (created bool, err error) {
+	if err := prop.Validate(); err != nil {
+		return false, err
+	}
+	err = db.WithTx(ctx, func(ctx context.Context) error {
+		existing := new(CustomProperty)
+		has, err := db.GetEngine(ctx).Where("owner_id = ? AND name = ?", prop.OwnerID, prop.Name).Get(existing)
+		if err != nil {
+			return err
+		} else if !has {
+			created = true
+			prop.ID = 0
+			return db.Insert(ctx, prop)
+		}
+		prop.ID = existing.ID
+		prop.CreatedUnix = existing.CreatedUnix
+		_, err = db.GetEngine(ctx).ID(prop.ID).Cols("type", "description", "allowed_values", "default_value", "required").Update(prop)
+		return err
+	})
+	return created, err
+}
+
+// DeleteCustomProperty deletes the custom property of an owner and its values on the repositories of the owner
+func DeleteCustomProperty(ctx context.Context, ownerID int64, name string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := GetCustomPropertyByName(ctx, ownerID, name); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).Where("owner_id = ? AND name = ?", ownerID, name).Delete(new(CustomProperty)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).Where(builder.Eq{"name": name}.
+			And(builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": ownerID})))).
+			Delete(new(RepoCustomPropertyValue))
+		return err
+	})
+}
+
+// GetRepoCustomPropertyValues returns the values of the custom properties of the repository owner,
+// the default value is used for the properties without value. Properties without any value are omitted.
+func GetRepoCustomPropertyValues(ctx context.Context, repo *Repository) ([]*RepoCustomPropertyValue, error) {
+	props, err := GetCustomProperties(ctx, repo.OwnerID)
+	if err != nil || len(props) == 0 {
+		return nil, err
+	}
+
+	values := make([]*RepoCustomPropertyValue, 0, len(props))
+	if err := db.GetEngine(ctx).Where("repo_id = ?", repo.ID).Find(&values); err != nil {
+		return nil, err
+	}
+	valueMap := make(map[string]*RepoCustomPropertyValue, len(values))
+	for _, value := range values {
+		valueMap[value.Name] = value
+	}
+
+	results := make([]*RepoCustomPropertyValue, 0, len(props))
+	for _, prop := range props {
+		if value, ok := valueMap[prop.Name]; ok {
+			results = append(results, value)
+		} else if prop.DefaultValue != "" {
+			results = append(results, &RepoCustomPropertyValue{RepoID: repo.ID, Name: prop.Name, Value: prop.DefaultValue})
+		}
+	}
+	return results, nil
+}
+
+// SetRepoCustomPropertyValues sets the values of custom properties on a repository,
+// an empty value removes the value of the property so its default value applies again.
+func SetRepoCustomPropertyValues(ctx context.Context, repo *Repository, values map[string]string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		for name, value := range values {
+			prop, err := GetCustomPropertyByName(ctx, repo.OwnerID, name)
+			if err != nil {
+				return err
+			}
+			if _, err := db.GetEngine(ctx).Where("repo_id = ? AND name = ?", repo.ID, name).Delete(new(RepoCustomPropertyValue)); err != nil {
+				return err
+			}
+			if value == "" {
+				continue
+			}
+			if err := prop.ValidateValue(value); err != nil {
+				return err
+			}
+			if err := db.Insert(ctx, &RepoCustomPropertyValue{RepoID: repo.ID, Name: name, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// customPropertiesCond returns the condition of the repositories whose custom properties have all the given values
+func customPropertiesCond(properties map[string]string) builder.Cond {
+	cond := builder.NewCond()
+	for name, value := range properties {
+		explicitValue := builder.In("repository.id", builder.Select("repo_id").From("repo_custom_property_value").
+			Where(builder.Eq{"name": name, "value": value}))
+		defaultValue := builder.In("repository.owner_id", builder.Select("owner_id").From("custom_property").
+			Where(builder.Eq{"name": name, "default_value": value})).
+			And(builder.NotIn("repository.id", builder.Select("repo_id").From("repo_custom_property_value").
+				Where(builder.Eq{"name": name})))
+		cond = cond.And(builder.Or(explicitValue, defaultValue))
+	}
+	return cond
+}
+
+// ParseCustomPropertyFilters parses the "name:value" filters of custom properties
+func ParseCustomPropertyFilters(filters []string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	properties := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		name, value, ok := strings.Cut(filter, ":")
+		if !ok || name == "" {
+			return nil, util.NewInvalidArgumentErrorf("invalid custom property filter %q", filter)
+		}
+		properties[name] = value
+	}
+	return properties, nil
+}
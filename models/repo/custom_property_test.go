@@ -0,0 +1,127 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomPropertyValidate(t *testing.T) {
+	for _, prop := range []*repo_model.CustomProperty{
+		{Name: "bad name", Type: repo_model.CustomPropertyTypeString},
+		{Name: "team", Type: "number"},
+		{Name: "tier", Type: repo_model.CustomPropertyTypeSingleSelect},
+		{Name: "team", Type: repo_model.CustomPropertyTypeString, AllowedValues: []string{"a"}},
+		{Name: "team", Type: repo_model.CustomPropertyTypeString, Required: true},
+		{Name: "tier", Type: repo_model.CustomPropertyTypeSingleSelect, AllowedValues: []string{"low"}, DefaultValue: "high"},
+		{Name: "public", Type: repo_model.CustomPropertyTypeTrueFalse, DefaultValue: "yes"},
+	} {
+		assert.ErrorIs(t, prop.Validate(), util.ErrInvalidArgument, "%+v", prop)
+	}
+
+	prop := &repo_model.CustomProperty{Name: "tier", Type: repo_model.CustomPropertyTypeSingleSelect, AllowedValues: []string{"low", "critical"}, DefaultValue: "low", Required: true}
+	assert.NoError(t, prop.Validate())
+}
+
+func TestParseCustomPropertyFilters(t *testing.T) {
+	filters, err := repo_model.ParseCustomPropertyFilters([]string{"team:payments", "tier:critical:1", "empty:"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "tier": "critical:1", "empty": ""}, filters)
+
+	filters, err = repo_model.ParseCustomPropertyFilters(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, filters)
+
+	_, err = repo_model.ParseCustomPropertyFilters([]string{"team"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = repo_model.ParseCustomPropertyFilters([]string{":payments"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestRepoCustomPropertyValues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	repo3 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	repo5 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 5})
+
+	created, err := repo_model.SaveCustomProperty(db.DefaultContext, &repo_model.CustomProperty{
+		OwnerID: org.ID,
+		Name:    "team",
+		Type:    repo_model.CustomPropertyTypeString,
+	})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	created, err = repo_model.SaveCustomProperty(db.DefaultContext, &repo_model.CustomProperty{
+		OwnerID:       org.ID,
+		Name:          "tier",
+		Type:          repo_model.CustomPropertyTypeSingleSelect,
+		AllowedValues: []string{"low", "critical"},
+		DefaultValue:  "low",
+	})
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	assert.NoError(t, repo_model.SetRepoCustomPropertyValues(db.DefaultContext, repo3, map[string]string{"team": "payments", "tier": "critical"}))
+	assert.ErrorIs(t, repo_model.SetRepoCustomPropertyValues(db.DefaultContext, repo5, map[string]string{"tier": "medium"}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, repo_model.SetRepoCustomPropertyValues(db.DefaultContext, repo5, map[string]string{"unknown": "value"}), util.ErrNotExist)
+
+	values, err := repo_model.GetRepoCustomPropertyValues(db.DefaultContext, repo3)
+	assert.NoError(t, err)
+	if assert.Len(t, values, 2) {
+		assert.Equal(t, "payments", values[0].Value)
+		assert.Equal(t, "critical", values[1].Value)
+	}
+	values, err = repo_model.GetRepoCustomPropertyValues(db.DefaultContext, repo5)
+	assert.NoError(t, err)
+	if assert.Len(t, values, 1) {
+		assert.Equal(t, "tier", values[0].Name)
+		assert.Equal(t, "low", values[0].Value)
+	}
+
+	searchRepoIDs := func(properties map[string]string) []int64 {
+		repos, _, err := repo_model.GetUserRepositories(db.DefaultContext, &repo_model.SearchRepoOptions{
+			Actor:            org,
+			Private:          true,
+			OrderBy:          "id ASC",
+			CustomProperties: properties,
+		})
+		assert.NoError(t, err)
+		ids := make([]int64, 0, len(repos))
+		for _, repo := range repos {
+			ids = append(ids, repo.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []int64{3}, searchRepoIDs(map[string]string{"team": "payments"}))
+	assert.Equal(t, []int64{3}, searchRepoIDs(map[string]string{"team": "payments", "tier": "critical"}))
+	assert.Empty(t, searchRepoIDs(map[string]string{"team": "payments", "tier": "low"}))
+	assert.NotContains(t, searchRepoIDs(map[string]string{"tier": "low"}), int64(3))
+	assert.Contains(t, searchRepoIDs(map[string]string{"tier": "low"}), int64(5))
+
+	repos, _, err := repo_model.SearchRepository(db.DefaultContext, &repo_model.SearchRepoOptions{
+		Private:          true,
+		CustomProperties: map[string]string{"team": "payments"},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 3, repos[0].ID)
+	}
+
+	// removing the value restores the default value
+	assert.NoError(t, repo_model.SetRepoCustomPropertyValues(db.DefaultContext, repo3, map[string]string{"tier": ""}))
+	assert.Contains(t, searchRepoIDs(map[string]string{"tier": "low"}), int64(3))
+
+	assert.NoError(t, repo_model.DeleteCustomProperty(db.DefaultContext, org.ID, "team"))
+	unittest.AssertNotExistsBean(t, &repo_model.RepoCustomPropertyValue{RepoID: repo3.ID, Name: "team"})
+	assert.ErrorIs(t, repo_model.DeleteCustomProperty(db.DefaultContext, org.ID, "team"), util.ErrNotExist)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// DeferredCommitStatus is the status of a deferred commit
+type DeferredCommitStatus int
+
+const (
+	DeferredCommitStatusPending DeferredCommitStatus = iota // waiting for its execution time
+	DeferredCommitStatusRunning                             // being committed
+	DeferredCommitStatusDone                                // committed
+	DeferredCommitStatusFailed                              // the commit failed, see its error
+)
+
+// String returns the name of the status
+func (status DeferredCommitStatus) String() string {
+	switch status {
+	case DeferredCommitStatusRunning:
+		return "running"
+	case DeferredCommitStatusDone:
+		return "done"
+	case DeferredCommitStatusFailed:
+		return "failed"
+	}
+	return "pending"
+}
+
+// DeferredCommit is a change of the files of a repository queued to be committed at a later time
+type DeferredCommit struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	DoerID int64 `xorm:"NOT NULL"`
+	Branch string
+	// Options are the serialized options of the change
+	Options     string               `xorm:"LONGTEXT NOT NULL"`
+	ExecuteUnix timeutil.TimeStamp   `xorm:"INDEX NOT NULL"`
+	Status      DeferredCommitStatus `xorm:"INDEX NOT NULL DEFAULT 0"`
+	CommitID    string               `xorm:"VARCHAR(64)"`
+	Error       string               `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(DeferredCommit))
+}
+
+// CreateDeferredCommit creates a deferred commit
+func CreateDeferredCommit(ctx context.Context, deferredCommit *DeferredCommit) error {
+	return db.Insert(ctx, deferredCommit)
+}
+
+// GetDeferredCommit returns the deferred commit of the repository, nil if there is none
+func GetDeferredCommit(ctx context.Context, repoID, id int64) (*DeferredCommit, error) {
+	deferredCommit := new(DeferredCommit)
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND id = ?", repoID, id).Get(deferredCommit)
+	if err != nil || !has {
+		return nil, err
+	}
+	return deferredCommit, nil
+}
+
+// FindDeferredCommits returns the deferred commits of the repository, the next to be executed first
+func FindDeferredCommits(ctx context.Context, repoID int64) ([]*DeferredCommit, error) {
+	var deferredCommits []*DeferredCommit
+	return deferredCommits, db.GetEngine(ctx).Where("repo_id = ?", repoID).Asc("execute_unix", "id").Find(&deferredCommits)
+}
+
+// GetDueDeferredCommitIDs returns the ids of the pending deferred commits whose execution time has come
+func GetDueDeferredCommitIDs(ctx context.Context, now timeutil.TimeStamp) ([]int64, error) {
+	var ids []int64
+	return ids, db.GetEngine(ctx).Table("deferred_commit").
+		Where("status = ? AND execute_unix <= ?", DeferredCommitStatusPending, now).
+		Asc("execute_unix", "id").Cols("id").Find(&ids)
+}
+
+// GetDeferredCommitByID returns the deferred commit with the id, nil if there is none
+func GetDeferredCommitByID(ctx context.Context, id int64) (*DeferredCommit, error) {
+	deferredCommit, has, err := db.GetByID[DeferredCommit](ctx, id)
+	if err != nil || !has {
+		return nil, err
+	}
+	return deferredCommit, nil
+}
+
+// StartDeferredCommit marks a pending deferred commit as running,
+// it returns false if it isn't pending anymore so it is only executed once
+func StartDeferredCommit(ctx context.Context, deferredCommit *DeferredCommit) (bool, error) {
+	deferredCommit.Status = DeferredCommitStatusRunning
+	n, err := db.GetEngine(ctx).ID(deferredCommit.ID).Where("status = ?", DeferredCommitStatusPending).Cols("status").Update(deferredCommit)
+	return n == 1, err
+}
+
+// UpdateDeferredCommitResult stores the result of the execution of a deferred commit
+func UpdateDeferredCommitResult(ctx context.Context, deferredCommit *DeferredCommit) error {
+	_, err := db.GetEngine(ctx).ID(deferredCommit.ID).Cols("status", "commit_id", "error").Update(deferredCommit)
+	return err
+}
+
+// DeleteDeferredCommit deletes a deferred commit, a pending one isn't executed
+func DeleteDeferredCommit(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(new(DeferredCommit))
+	return err
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RepoManifest describes a manifest file (go.mod, package.json, ...) found on the default branch of a repository.
+// Name is the package name the manifest declares, it is used to link dependencies of other repositories to this one.
+type RepoManifest struct { //revive:disable-line:exported
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	CommitID    string             `xorm:"VARCHAR(64)"`
+	Path        string             `xorm:"VARCHAR(500) NOT NULL"`
+	Ecosystem   string             `xorm:"VARCHAR(20) INDEX(s) NOT NULL"`
+	Name        string             `xorm:"VARCHAR(255) INDEX(s)"`
+	CreatedUnix timeutil.TimeStamp `xorm:"CREATED"`
+}
+
+// RepoDependency describes a dependency declared in a manifest file of a repository
+type RepoDependency struct { //revive:disable-line:exported
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	Manifest    string             `xorm:"VARCHAR(500) NOT NULL"`
+	Ecosystem   string             `xorm:"VARCHAR(20) INDEX(s) NOT NULL"`
+	Name        string             `xorm:"VARCHAR(255) INDEX(s) NOT NULL"`
+	Version     string             `xorm:"VARCHAR(255)"`
+	IsDev       bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"CREATED"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoManifest))
+	db.RegisterModel(new(RepoDependency))
+}
+
+// PackageKey returns the key identifying the package in its ecosystem
+func (d *RepoDependency) PackageKey() string {
+	return d.Ecosystem + ":" + d.Name
+}
+
+// PackageKey returns the key identifying the package in its ecosystem
+func (m *RepoManifest) PackageKey() string {
+	return m.Ecosystem + ":" + m.Name
+}
+
+// ReplaceRepoDependencies replaces all the manifests and dependencies of a repository
+func ReplaceRepoDependencies(ctx context.Context, repoID int64, manifests []*RepoManifest, deps []*RepoDependency) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(RepoManifest)); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(RepoDependency)); err != nil {
+			return err
+		}
+		for _, m := range manifests {
+			m.ID = 0
+			m.RepoID = repoID
+		}
+		for _, d := range deps {
+			d.ID = 0
+			d.RepoID = repoID
+		}
+		if len(manifests) > 0 {
+			if err := db.Insert(ctx, manifests); err != nil {
+				return err
+			}
+		}
+		if len(deps) > 0 {
+			if err := db.Insert(ctx, deps); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetRepoManifests returns the manifests found in a repository
+func GetRepoManifests(ctx context.Context, repoID int64) ([]*RepoManifest, error) {
+	manifests := make([]*RepoManifest, 0, 5)
+	return manifests, db.GetEngine(ctx).Where("repo_id = ?", repoID).Asc("path").Find(&manifests)
+}
+
+// FindRepoDependenciesOptions represents the options to find dependencies
+type FindRepoDependenciesOptions struct {
+	db.ListOptions
+	RepoID    int64
+	Ecosystem string
+	// Packages limits the results to dependencies on the packages declared by these manifests
+	Packages []*RepoManifest
+	// Doer limits the results to repositories whose code the doer can read, nil for public repositories only
+	Doer *user_model.User
+	// ExcludeRepoID excludes the dependencies of a repository, used to skip self references
+	ExcludeRepoID int64
+	// AllRepos disables the access check, only for internal usage
+	AllRepos bool
+}
+
+func (opts FindRepoDependenciesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_dependency.repo_id": opts.RepoID})
+	}
+	if opts.ExcludeRepoID > 0 {
+		cond = cond.And(builder.Neq{"repo_dependency.repo_id": opts.ExcludeRepoID})
+	}
+	if opts.Ecosystem != "" {
+		cond = cond.And(builder.Eq{"repo_dependency.ecosystem": opts.Ecosystem})
+	}
+	if opts.Packages != nil {
+		pkgCond := builder.NewCond()
+		for _, m := range opts.Packages {
+			if m.Name == "" {
+				continue
+			}
+			pkgCond = pkgCond.Or(builder.Eq{"repo_dependency.ecosystem": m.Ecosystem, "repo_dependency.name": m.Name})
+		}
+		if !pkgCond.IsValid() {
+			// no package names means nothing can depend on them
+			pkgCond = builder.Expr("1 = 0")
+		}
+		cond = cond.And(pkgCond)
+	}
+	if !opts.AllRepos {
+		cond = cond.And(builder.In("repo_dependency.repo_id",
+			builder.Select("`repository`.id").From("repository").Where(AccessibleRepositoryCondition(opts.Doer, unit.TypeCode))))
+	}
+	return cond
+}
+
+func (opts FindRepoDependenciesOptions) ToOrders() string {
+	return "repo_dependency.ecosystem ASC, repo_dependency.name ASC, repo_dependency.repo_id ASC, repo_dependency.manifest ASC"
+}
+
+// FindRepoIDsByPackages returns the IDs of the repositories declaring the given packages, keyed by the package key
+func FindRepoIDsByPackages(ctx context.Context, deps []*RepoDependency) (map[string][]int64, error) {
+	res := make(map[string][]int64)
+	if len(deps) == 0 {
+		return res, nil
+	}
+	cond := builder.NewCond()
+	for _, d := range deps {
+		cond = cond.Or(builder.Eq{"ecosystem": d.Ecosystem, "name": d.Name})
+	}
+	manifests := make([]*RepoManifest, 0, len(deps))
+	if err := db.GetEngine(ctx).Where(cond).Find(&manifests); err != nil {
+		return nil, err
+	}
+	for _, m := range manifests {
+		res[m.PackageKey()] = append(res[m.PackageKey()], m.RepoID)
+	}
+	return res, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/setting"
 )
 
@@ -58,3 +59,27 @@ func (repo *Repository) IsDependenciesEnabled(ctx context.Context) bool {
 	}
 	return u.IssuesConfig().EnableDependencies
 }
+
+// GetIssuesConfig returns the config of the issues unit, or an empty config if the unit isn't enabled
+func (repo *Repository) GetIssuesConfig(ctx context.Context) *IssuesConfig {
+	u, err := repo.GetUnit(ctx, unit.TypeIssues)
+	if err != nil {
+		log.Trace("GetIssuesConfig: %v", err)
+		return &IssuesConfig{}
+	}
+	return u.IssuesConfig()
+}
+
+// IssueKeywords returns the keywords which close or reopen the issues referenced from the repository,
+// the keywords of the instance are used if the repository doesn't set its own
+func (repo *Repository) IssueKeywords(ctx context.Context) *references.Keywords {
+	cfg := repo.GetIssuesConfig(ctx)
+	closeKeywords, reopenKeywords := cfg.CloseKeywords, cfg.ReopenKeywords
+	if len(closeKeywords) == 0 {
+		closeKeywords = setting.Repository.PullRequest.CloseKeywords
+	}
+	if len(reopenKeywords) == 0 {
+		reopenKeywords = setting.Repository.PullRequest.ReopenKeywords
+	}
+	return references.NewKeywords(closeKeywords, reopenKeywords, cfg.KeywordLanguages...)
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RepoMaintenanceTrigger is what started the maintenance of a repository
+type RepoMaintenanceTrigger string //revive:disable-line:exported
+
+const (
+	RepoMaintenanceTriggerSchedule RepoMaintenanceTrigger = "schedule" // the maintain_repositories cron task
+	RepoMaintenanceTriggerPush     RepoMaintenanceTrigger = "push"     // a push growing the repository a lot
+	RepoMaintenanceTriggerManual   RepoMaintenanceTrigger = "manual"   // an administrator
+)
+
+// RepoMaintenance holds the status of the last maintenance of a repository, which writes its commit-graph
+// and multi-pack-index to speed up the log and merge-base operations of large repositories.
+type RepoMaintenance struct { //revive:disable-line:exported
+	ID             int64                  `xorm:"pk autoincr"`
+	RepoID         int64                  `xorm:"UNIQUE NOT NULL"`
+	Repo           *Repository            `xorm:"-"`
+	Trigger        RepoMaintenanceTrigger `xorm:"VARCHAR(20) NOT NULL"`
+	DurationMillis int64                  `xorm:"NOT NULL DEFAULT 0"`
+	// Error is empty when the maintenance succeeded
+	Error       string             `xorm:"TEXT"`
+	LastRunUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoMaintenance))
+}
+
+// GetRepoMaintenance returns the status of the last maintenance of a repository, nil if it has never been maintained
+func GetRepoMaintenance(ctx context.Context, repoID int64) (*RepoMaintenance, error) {
+	maintenance := new(RepoMaintenance)
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(maintenance)
+	if err != nil || !has {
+		return nil, err
+	}
+	return maintenance, nil
+}
+
+// SaveRepoMaintenance creates or updates the status of the last maintenance of a repository
+func SaveRepoMaintenance(ctx context.Context, maintenance *RepoMaintenance) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetRepoMaintenance(ctx, maintenance.RepoID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			maintenance.ID = 0
+			return db.Insert(ctx, maintenance)
+		}
+		maintenance.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(maintenance.ID).AllCols().Update(maintenance)
+		return err
+	})
+}
+
+// FindRepoMaintenancesOptions represents the options to list the maintenance statuses of the repositories
+type FindRepoMaintenancesOptions struct {
+	db.ListOptions
+	// OnlyFailed lists only the repositories whose last maintenance failed
+	OnlyFailed bool
+}
+
+func (opts FindRepoMaintenancesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OnlyFailed {
+		cond = cond.And(builder.Neq{"error": ""}.And(builder.NotNull{"error"}))
+	}
+	return cond
+}
+
+func (opts FindRepoMaintenancesOptions) ToOrders() string {
+	return "last_run_unix DESC, id DESC"
+}
+
+type RepoMaintenanceList []*RepoMaintenance //revive:disable-line:exported
+
+// LoadRepos loads the repositories of the maintenance statuses, the deleted ones are left nil
+func (list RepoMaintenanceList) LoadRepos(ctx context.Context) error {
+	ids := container.FilterSlice(list, func(maintenance *RepoMaintenance) (int64, bool) {
+		return maintenance.RepoID, maintenance.Repo == nil
+	})
+
+	reposMap := make(map[int64]*Repository, len(ids))
+	if err := db.GetEngine(ctx).In("id", ids).Find(&reposMap); err != nil {
+		return err
+	}
+	for _, maintenance := range list {
+		if maintenance.Repo == nil {
+			maintenance.Repo = reposMap[maintenance.RepoID]
+		}
+	}
+	return nil
+}
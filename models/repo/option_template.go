@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// OptionTemplateType is the type of a template used to initialize the repositories
+type OptionTemplateType string
+
+const (
+	OptionTemplateGitignore OptionTemplateType = "gitignore"
+	OptionTemplateLicense   OptionTemplateType = "license"
+	OptionTemplateLabel     OptionTemplateType = "label"
+)
+
+// IsValid returns whether the type is a known one
+func (t OptionTemplateType) IsValid() bool {
+	switch t {
+	case OptionTemplateGitignore, OptionTemplateLicense, OptionTemplateLabel:
+		return true
+	}
+	return false
+}
+
+// OptionTemplate is a gitignore, license or label template managed by the instance administrators,
+// it overrides the file of the same type and name provided by bindata or the custom path.
+type OptionTemplate struct {
+	ID   int64              `xorm:"pk autoincr"`
+	Type OptionTemplateType `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+	// Name is the file name of the gitignore and license templates and the display name of the label templates
+	Name        string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	Content     string             `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(OptionTemplate))
+}
+
+// GetOptionTemplate returns the template of the type with the name, nil if there is none
+func GetOptionTemplate(ctx context.Context, tp OptionTemplateType, name string) (*OptionTemplate, error) {
+	tmpl := new(OptionTemplate)
+	has, err := db.GetEngine(ctx).Where("`type` = ? AND `name` = ?", tp, name).Get(tmpl)
+	if err != nil || !has {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// FindOptionTemplates returns the templates of the type ordered by name
+func FindOptionTemplates(ctx context.Context, tp OptionTemplateType) ([]*OptionTemplate, error) {
+	tmpls := make([]*OptionTemplate, 0, 10)
+	return tmpls, db.GetEngine(ctx).Where("`type` = ?", tp).OrderBy("`name`").Find(&tmpls)
+}
+
+// SaveOptionTemplate creates or updates the template of its type with its name
+func SaveOptionTemplate(ctx context.Context, tmpl *OptionTemplate) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetOptionTemplate(ctx, tmpl.Type, tmpl.Name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			tmpl.ID = 0
+			return db.Insert(ctx, tmpl)
+		}
+		tmpl.ID = existing.ID
+		tmpl.CreatedUnix = existing.CreatedUnix
+		_, err = db.GetEngine(ctx).ID(tmpl.ID).Cols("content").Update(tmpl)
+		return err
+	})
+}
+
+// DeleteOptionTemplate deletes the template of the type with the name
+func DeleteOptionTemplate(ctx context.Context, tp OptionTemplateType, name string) error {
+	deleted, err := db.GetEngine(ctx).Where("`type` = ? AND `name` = ?", tp, name).Delete(new(OptionTemplate))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return util.NewNotExistErrorf("%s template %q doesn't exist", tp, name)
+	}
+	return nil
+}
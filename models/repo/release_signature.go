@@ -0,0 +1,59 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ReleaseSignature caches the checksums file, its signature and the provenance statement of a release.
+// Fingerprint identifies the artifacts the record was generated from, a record is outdated as soon as it differs.
+// Signature is an ASCII armored detached signature of Checksums, it is empty if no signing key is configured.
+type ReleaseSignature struct {
+	ID          int64              `xorm:"pk autoincr"`
+	ReleaseID   int64              `xorm:"UNIQUE NOT NULL"`
+	Fingerprint string             `xorm:"VARCHAR(64) NOT NULL"`
+	Checksums   string             `xorm:"LONGTEXT"`
+	Signature   string             `xorm:"LONGTEXT"`
+	KeyID       string             `xorm:"VARCHAR(255)"`
+	Provenance  string             `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ReleaseSignature))
+}
+
+// GetReleaseSignature returns the cached signature of a release, nil if there is none
+func GetReleaseSignature(ctx context.Context, releaseID int64) (*ReleaseSignature, error) {
+	sig := new(ReleaseSignature)
+	has, err := db.GetEngine(ctx).Where("release_id = ?", releaseID).Get(sig)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return sig, nil
+}
+
+// SaveReleaseSignature inserts or replaces the cached signature of a release
+func SaveReleaseSignature(ctx context.Context, sig *ReleaseSignature) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := DeleteReleaseSignature(ctx, sig.ReleaseID); err != nil {
+			return err
+		}
+		sig.ID = 0
+		return db.Insert(ctx, sig)
+	})
+}
+
+// DeleteReleaseSignature deletes the cached signature of a release
+func DeleteReleaseSignature(ctx context.Context, releaseID int64) error {
+	_, err := db.GetEngine(ctx).Where("release_id = ?", releaseID).Delete(new(ReleaseSignature))
+	return err
+}
@@ -20,6 +20,10 @@ const (
 	RepoIndexerTypeCode RepoIndexerType = iota // 0
 	// RepoIndexerTypeStats repository stats indexer
 	RepoIndexerTypeStats // 1
+	// RepoIndexerTypeWiki wiki indexer
+	RepoIndexerTypeWiki // 2
+	// RepoIndexerTypeCommitStats repository commit statistics indexer
+	RepoIndexerTypeCommitStats // 3
 )
 
 // RepoIndexerStatus status of a repo's entry in the repo indexer
@@ -73,6 +77,14 @@ func GetIndexerStatus(ctx context.Context, repo *Repository, indexerType RepoInd
 		if repo.StatsIndexerStatus != nil {
 			return repo.StatsIndexerStatus, nil
 		}
+	case RepoIndexerTypeWiki:
+		if repo.WikiIndexerStatus != nil {
+			return repo.WikiIndexerStatus, nil
+		}
+	case RepoIndexerTypeCommitStats:
+		if repo.CommitStatsIndexerStatus != nil {
+			return repo.CommitStatsIndexerStatus, nil
+		}
 	}
 	status := &RepoIndexerStatus{RepoID: repo.ID}
 	if has, err := db.GetEngine(ctx).Where("`indexer_type` = ?", indexerType).Get(status); err != nil {
@@ -86,6 +98,10 @@ func GetIndexerStatus(ctx context.Context, repo *Repository, indexerType RepoInd
 		repo.CodeIndexerStatus = status
 	case RepoIndexerTypeStats:
 		repo.StatsIndexerStatus = status
+	case RepoIndexerTypeWiki:
+		repo.WikiIndexerStatus = status
+	case RepoIndexerTypeCommitStats:
+		repo.CommitStatsIndexerStatus = status
 	}
 	return status, nil
 }
@@ -112,3 +128,9 @@ func UpdateIndexerStatus(ctx context.Context, repo *Repository, indexerType Repo
 	}
 	return nil
 }
+
+// DeleteIndexerStatuses deletes the statuses of all repositories for the indexer type
+func DeleteIndexerStatuses(ctx context.Context, indexerType RepoIndexerType) error {
+	_, err := db.GetEngine(ctx).Where("`indexer_type` = ?", indexerType).Delete(new(RepoIndexerStatus))
+	return err
+}
@@ -201,6 +201,8 @@ type SearchRepoOptions struct {
 	HasMilestones optional.Option[bool]
 	// LowerNames represents valid lower names to restrict to
 	LowerNames []string
+	// only search repositories whose custom properties have the specified values
+	CustomProperties map[string]string
 	// When specified true, apply some filters over the conditions:
 	// - Don't show forks, when opts.Fork is OptionalBoolNone.
 	// - Do not display repositories that don't have a description, an icon and topics.
@@ -486,6 +488,10 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 			Where(builder.Eq{"language": opts.Language}).And(builder.Eq{"is_primary": true})))
 	}
 
+	if len(opts.CustomProperties) > 0 {
+		cond = cond.And(customPropertiesCond(opts.CustomProperties))
+	}
+
 	if opts.Fork.Has() || opts.OnlyShowRelevant {
 		if opts.OnlyShowRelevant && !opts.Fork.Has() {
 			cond = cond.And(builder.Eq{"is_fork": false})
@@ -753,6 +759,10 @@ func GetUserRepositories(ctx context.Context, opts *SearchRepoOptions) (Reposito
 		cond = cond.And(builder.In("lower_name", opts.LowerNames))
 	}
 
+	if len(opts.CustomProperties) > 0 {
+		cond = cond.And(customPropertiesCond(opts.CustomProperties))
+	}
+
 	sess := db.GetEngine(ctx)
 
 	count, err := sess.Where(cond).Count(new(Repository))
@@ -105,6 +105,11 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	CloseKeywords                    []string // keywords closing the referenced issues, the keywords of the instance are used if empty
+	ReopenKeywords                   []string // keywords reopening the referenced issues, the keywords of the instance are used if empty
+	KeywordLanguages                 []string // languages whose keywords are accepted besides the keywords above
+	CloseOnlyOnDefaultBranch         bool     // merged pull requests only close or reopen issues if their base branch is the default branch
+	CloseOnlyInSameMilestone         bool     // merged pull requests only close or reopen the issues of their milestone
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -131,6 +136,11 @@ type PullRequestsConfig struct {
 	DefaultDeleteBranchAfterMerge bool
 	DefaultMergeStyle             MergeStyle
 	DefaultAllowMaintainerEdit    bool
+	MergeStrategy                 string   // the git merge strategy used to merge pull requests, empty for the git default
+	MergeStrategyOptions          []string // the options passed to the merge strategy
+	EnableMergeQueue              bool     // pull requests are merged through a merge queue which tests them against the pull requests before them
+	RetargetOnBaseDeletion        bool     // pull requests are retargeted to the default branch instead of being closed when their base branch is deleted
+	RebaseOnRetarget              bool     // the head branches of automatically retargeted pull requests are rebased onto their new base branch
 }
 
 // FromDB fills up a PullRequestsConfig from serialized format.
@@ -170,6 +180,17 @@ func (cfg *PullRequestsConfig) GetDefaultMergeStyle() MergeStyle {
 
 type ActionsConfig struct {
 	DisabledWorkflows []string
+	// ArtifactRetentionDays is the number of days the artifacts are kept, 0 to use the retention of the owner or of the instance
+	ArtifactRetentionDays int64
+	// PausedSchedules are the entries of the schedule triggers of the workflows which don't create runs,
+	// they are kept when the workflow files change so an entry is paused until it's resumed or removed
+	PausedSchedules []ActionsPausedSchedule `json:",omitempty"`
+}
+
+// ActionsPausedSchedule is a paused entry of the schedule trigger of a workflow
+type ActionsPausedSchedule struct {
+	WorkflowID string
+	Spec       string
 }
 
 func (cfg *ActionsConfig) EnableWorkflow(file string) {
@@ -194,6 +215,23 @@ func (cfg *ActionsConfig) DisableWorkflow(file string) {
 	cfg.DisabledWorkflows = append(cfg.DisabledWorkflows, file)
 }
 
+// IsSchedulePaused returns whether the entry of the schedule trigger of the workflow is paused
+func (cfg *ActionsConfig) IsSchedulePaused(workflowID, spec string) bool {
+	return slices.Contains(cfg.PausedSchedules, ActionsPausedSchedule{WorkflowID: workflowID, Spec: spec})
+}
+
+// PauseSchedule pauses the entry of the schedule trigger of the workflow
+func (cfg *ActionsConfig) PauseSchedule(workflowID, spec string) {
+	if !cfg.IsSchedulePaused(workflowID, spec) {
+		cfg.PausedSchedules = append(cfg.PausedSchedules, ActionsPausedSchedule{WorkflowID: workflowID, Spec: spec})
+	}
+}
+
+// ResumeSchedule resumes the entry of the schedule trigger of the workflow
+func (cfg *ActionsConfig) ResumeSchedule(workflowID, spec string) {
+	cfg.PausedSchedules = util.SliceRemoveAll(cfg.PausedSchedules, ActionsPausedSchedule{WorkflowID: workflowID, Spec: spec})
+}
+
 // FromDB fills up a ActionsConfig from serialized format.
 func (cfg *ActionsConfig) FromDB(bs []byte) error {
 	return json.UnmarshalHandleDoubleEncode(bs, &cfg)
@@ -28,3 +28,22 @@ func TestActionsConfig(t *testing.T) {
 	cfg.DisableWorkflow("test3.yaml")
 	assert.EqualValues(t, "test1.yaml,test2.yaml,test3.yaml", cfg.ToString())
 }
+
+func TestActionsConfigPausedSchedules(t *testing.T) {
+	cfg := &ActionsConfig{}
+	cfg.PauseSchedule("test1.yaml", "0 * * * *")
+	cfg.PauseSchedule("test1.yaml", "0 * * * *")
+	assert.Len(t, cfg.PausedSchedules, 1)
+	assert.True(t, cfg.IsSchedulePaused("test1.yaml", "0 * * * *"))
+	assert.False(t, cfg.IsSchedulePaused("test1.yaml", "@daily"))
+	assert.False(t, cfg.IsSchedulePaused("test2.yaml", "0 * * * *"))
+
+	bs, err := cfg.ToDB()
+	assert.NoError(t, err)
+	loaded := &ActionsConfig{}
+	assert.NoError(t, loaded.FromDB(bs))
+	assert.True(t, loaded.IsSchedulePaused("test1.yaml", "0 * * * *"))
+
+	cfg.ResumeSchedule("test1.yaml", "0 * * * *")
+	assert.False(t, cfg.IsSchedulePaused("test1.yaml", "0 * * * *"))
+}
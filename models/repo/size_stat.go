@@ -0,0 +1,63 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RepoSizeStat holds the sizes of the data attributable to a repository besides its git and LFS objects,
+// they are refreshed periodically.
+type RepoSizeStat struct { //revive:disable-line:exported
+	ID             int64              `xorm:"pk autoincr"`
+	RepoID         int64              `xorm:"UNIQUE NOT NULL"`
+	AttachmentSize int64              `xorm:"NOT NULL DEFAULT 0"`
+	ArtifactSize   int64              `xorm:"NOT NULL DEFAULT 0"`
+	ActionLogSize  int64              `xorm:"NOT NULL DEFAULT 0"`
+	PackageSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedUnix    timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoSizeStat))
+}
+
+// GetRepoSizeStat returns the size statistics of a repository, nil if they have never been computed
+func GetRepoSizeStat(ctx context.Context, repoID int64) (*RepoSizeStat, error) {
+	stat := new(RepoSizeStat)
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(stat)
+	if err != nil || !has {
+		return nil, err
+	}
+	return stat, nil
+}
+
+// SaveRepoSizeStat creates or updates the size statistics of a repository
+func SaveRepoSizeStat(ctx context.Context, stat *RepoSizeStat) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetRepoSizeStat(ctx, stat.RepoID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			stat.ID = 0
+			return db.Insert(ctx, stat)
+		}
+		stat.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(stat.ID).AllCols().Update(stat)
+		return err
+	})
+}
+
+// GetRepoAttachmentsSize returns the total size of the attachments stored for a repository
+func GetRepoAttachmentsSize(ctx context.Context, repoID int64) (int64, error) {
+	// external attachments are not stored by Gitea
+	return db.GetEngine(ctx).Where(builder.Eq{"repo_id": repoID}.And(builder.Eq{"external_url": ""}.Or(builder.IsNull{"external_url"}))).
+		SumInt(new(Attachment), "size")
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
+)
+
+// RepoStagingSession holds the files uploaded by a user in chunks across multiple requests,
+// until they are committed together to the branch of the session.
+type RepoStagingSession struct { //revive:disable-line:exported
+	ID     int64  `xorm:"pk autoincr"`
+	UUID   string `xorm:"uuid UNIQUE"`
+	RepoID int64  `xorm:"INDEX NOT NULL"`
+	UserID int64  `xorm:"NOT NULL"`
+	Branch string `xorm:"NOT NULL"`
+	// the head of the branch when the session was created, empty for an empty repository
+	BaseCommitID string `xorm:"VARCHAR(64)"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated INDEX"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoStagingSession))
+}
+
+// LocalPath returns the directory where the staged files of the session are stored
+func (session *RepoStagingSession) LocalPath() string {
+	return filepath.Join(setting.Repository.Upload.TempPath, "staging", session.UUID)
+}
+
+// CreateRepoStagingSession creates a staging session
+func CreateRepoStagingSession(ctx context.Context, session *RepoStagingSession) error {
+	session.UUID = gouuid.New().String()
+	return db.Insert(ctx, session)
+}
+
+// GetRepoStagingSession returns the staging session of the user in the repository, nil if there is none
+func GetRepoStagingSession(ctx context.Context, repoID, userID int64, uuid string) (*RepoStagingSession, error) {
+	session := new(RepoStagingSession)
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND user_id = ? AND uuid = ?", repoID, userID, uuid).Get(session)
+	if err != nil || !has {
+		return nil, err
+	}
+	return session, nil
+}
+
+// TouchRepoStagingSession updates the time of the last change of the staging session, so it doesn't expire
+func TouchRepoStagingSession(ctx context.Context, session *RepoStagingSession) error {
+	_, err := db.GetEngine(ctx).ID(session.ID).Cols("updated_unix").Update(session)
+	return err
+}
+
+// DeleteRepoStagingSession deletes the staging session and its staged files
+func DeleteRepoStagingSession(ctx context.Context, session *RepoStagingSession) error {
+	if _, err := db.GetEngine(ctx).ID(session.ID).Delete(new(RepoStagingSession)); err != nil {
+		return err
+	}
+	return util.RemoveAll(session.LocalPath())
+}
+
+// DeleteExpiredRepoStagingSessions deletes the staging sessions which haven't changed for the given duration
+func DeleteExpiredRepoStagingSessions(ctx context.Context, olderThan time.Duration) error {
+	var sessions []*RepoStagingSession
+	if err := db.GetEngine(ctx).Where("updated_unix < ?", time.Now().Add(-olderThan).Unix()).Find(&sessions); err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if err := DeleteRepoStagingSession(ctx, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -27,7 +27,8 @@ type Secret struct {
 	OwnerID     int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
 	RepoID      int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
 	Name        string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
-	Data        string             `xorm:"LONGTEXT"` // encrypted data
+	Environment string             `xorm:"UNIQUE(owner_repo_name) NOT NULL DEFAULT ''"` // the environment of the repository the secret is bound to, if any
+	Data        string             `xorm:"LONGTEXT"`                                    // encrypted data
 	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL"`
 }
 
@@ -45,16 +46,18 @@ func (err ErrSecretNotFound) Unwrap() error {
 }
 
 // InsertEncryptedSecret Creates, encrypts, and validates a new secret with yet unencrypted data and insert into database
-func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, name, data string) (*Secret, error) {
+// The secrets bound to neither an owner nor a repository are the secrets of the instance.
+func InsertEncryptedSecret(ctx context.Context, ownerID, repoID int64, environment, name, data string) (*Secret, error) {
 	encrypted, err := secret_module.EncryptSecret(setting.SecretKey, data)
 	if err != nil {
 		return nil, err
 	}
 	secret := &Secret{
-		OwnerID: ownerID,
-		RepoID:  repoID,
-		Name:    strings.ToUpper(name),
-		Data:    encrypted,
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Name:        strings.ToUpper(name),
+		Environment: environment,
+		Data:        encrypted,
 	}
 	if err := secret.Validate(); err != nil {
 		return secret, err
@@ -67,28 +70,36 @@ func init() {
 }
 
 func (s *Secret) Validate() error {
-	if s.OwnerID == 0 && s.RepoID == 0 {
-		return errors.New("the secret is not bound to any scope")
+	if s.Environment != "" && s.RepoID == 0 {
+		return errors.New("a secret bound to an environment should be bound to a repository")
 	}
 	return nil
 }
 
+// Scope returns the level the secret is defined at
+func (s *Secret) Scope() actions_model.VariableScope {
+	return actions_model.ScopeOf(s.OwnerID, s.RepoID, s.Environment)
+}
+
 type FindSecretsOptions struct {
 	db.ListOptions
-	OwnerID  int64
-	RepoID   int64
-	SecretID int64
-	Name     string
+	OwnerID     int64
+	RepoID      int64
+	SecretID    int64
+	Name        string
+	Environment string
 }
 
 func (opts FindSecretsOptions) ToConds() builder.Cond {
 	cond := builder.NewCond()
-	if opts.OwnerID > 0 {
-		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
-	}
 	if opts.RepoID > 0 {
+		// the secrets of a repository created by the API used to be bound to its owner too
 		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	} else {
+		// without an owner, they are the secrets of the instance
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID}, builder.Eq{"repo_id": 0})
 	}
+	cond = cond.And(builder.Eq{"environment": opts.Environment})
 	if opts.SecretID != 0 {
 		cond = cond.And(builder.Eq{"id": opts.SecretID})
 	}
@@ -129,18 +140,14 @@ func GetSecretsOfTask(ctx context.Context, task *actions_model.ActionTask) (map[
 		return secrets, nil
 	}
 
-	ownerSecrets, err := db.Find[Secret](ctx, FindSecretsOptions{OwnerID: task.Job.Run.Repo.OwnerID})
-	if err != nil {
-		log.Error("find secrets of owner %v: %v", task.Job.Run.Repo.OwnerID, err)
-		return nil, err
-	}
-	repoSecrets, err := db.Find[Secret](ctx, FindSecretsOptions{RepoID: task.Job.Run.RepoID})
+	scopedSecrets, err := FindSecretsOfScopes(ctx, task.Job.Run.Repo.OwnerID, task.Job.Run.RepoID, task.Job.Environment())
 	if err != nil {
 		log.Error("find secrets of repo %v: %v", task.Job.Run.RepoID, err)
 		return nil, err
 	}
 
-	for _, secret := range append(ownerSecrets, repoSecrets...) {
+	// Level precedence: Environment > Repo > Org / User > Global
+	for _, secret := range scopedSecrets {
 		v, err := secret_module.DecryptSecret(setting.SecretKey, secret.Data)
 		if err != nil {
 			log.Error("decrypt secret %v %q: %v", secret.ID, secret.Name, err)
@@ -151,3 +158,22 @@ func GetSecretsOfTask(ctx context.Context, task *actions_model.ActionTask) (map[
 
 	return secrets, nil
 }
+
+// FindSecretsOfScopes returns the secrets of the instance, of the owner, of the repository and of the environment
+// of the repository if it isn't empty, in their order of precedence: a secret overrides the ones of the same name before it
+func FindSecretsOfScopes(ctx context.Context, ownerID, repoID int64, environment string) ([]*Secret, error) {
+	scopes := []FindSecretsOptions{{}, {OwnerID: ownerID}, {RepoID: repoID}}
+	if environment != "" {
+		scopes = append(scopes, FindSecretsOptions{RepoID: repoID, Environment: environment})
+	}
+
+	var secrets []*Secret
+	for _, opts := range scopes {
+		found, err := db.Find[Secret](ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, found...)
+	}
+	return secrets, nil
+}
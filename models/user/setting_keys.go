@@ -10,6 +10,12 @@ const (
 	SettingsKeyDiffWhitespaceBehavior = "diff.whitespace_behaviour"
 	// SettingsKeyShowOutdatedComments is the setting key wether or not to show outdated comments in PRs
 	SettingsKeyShowOutdatedComments = "comment_code.show_outdated"
+	// SettingsKeyActionsArtifactRetentionDays is the setting key for the number of days the artifacts of the repositories of an organization are kept
+	SettingsKeyActionsArtifactRetentionDays = "actions.artifact_retention_days"
+	// SettingsKeyPackagesRequireSignatures is the setting key wether or not uploaded package versions of an owner must be signed
+	SettingsKeyPackagesRequireSignatures = "packages.require_signatures"
+	// SettingsKeyPackagesTotalSizeLimit is the setting key for the maximum total size of the package files of an owner, it overrides the instance setting
+	SettingsKeyPackagesTotalSizeLimit = "packages.total_size_limit"
 	// UserActivityPubPrivPem is user's private key
 	UserActivityPubPrivPem = "activitypub.priv_pem"
 	// UserActivityPubPubPem is user's public key
@@ -0,0 +1,133 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Types of the inputs of the workflow_dispatch event
+// See https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#onworkflow_dispatchinputsinput_idtype
+const (
+	WorkflowDispatchInputTypeString      = "string"
+	WorkflowDispatchInputTypeChoice      = "choice"
+	WorkflowDispatchInputTypeBoolean     = "boolean"
+	WorkflowDispatchInputTypeNumber      = "number"
+	WorkflowDispatchInputTypeEnvironment = "environment"
+)
+
+// WorkflowDispatchInput is an input of a workflow which can be run manually
+type WorkflowDispatchInput struct {
+	Name string
+	model.WorkflowDispatchInput
+}
+
+// GetWorkflowDispatchInputs returns the inputs of the workflow_dispatch trigger of the workflow, in their declaration order.
+// The boolean is false if the workflow can't be run manually.
+func GetWorkflowDispatchInputs(wf *model.Workflow) ([]*WorkflowDispatchInput, bool) {
+	config := wf.WorkflowDispatchConfig()
+	if config == nil {
+		return nil, false
+	}
+
+	inputs := make([]*WorkflowDispatchInput, 0, len(config.Inputs))
+	for _, name := range workflowDispatchInputNames(&wf.RawOn) {
+		input, ok := config.Inputs[name]
+		if !ok {
+			continue
+		}
+		if input.Type == "" {
+			input.Type = WorkflowDispatchInputTypeString
+		}
+		inputs = append(inputs, &WorkflowDispatchInput{Name: name, WorkflowDispatchInput: input})
+	}
+	return inputs, true
+}
+
+// workflowDispatchInputNames returns the names of the inputs of the workflow_dispatch trigger in the order of the document,
+// which is lost when they are decoded to a map
+func workflowDispatchInputNames(rawOn *yaml.Node) []string {
+	mappingValue := func(node *yaml.Node, key string) *yaml.Node {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				return node.Content[i+1]
+			}
+		}
+		return nil
+	}
+
+	inputs := mappingValue(mappingValue(rawOn, GithubEventWorkflowDispatch), "inputs")
+	if inputs == nil || inputs.Kind != yaml.MappingNode {
+		return nil
+	}
+	names := make([]string, 0, len(inputs.Content)/2)
+	for i := 0; i+1 < len(inputs.Content); i += 2 {
+		names = append(names, inputs.Content[i].Value)
+	}
+	return names
+}
+
+// ValidateWorkflowDispatchInputs checks the values given to the inputs of a workflow run manually against their definitions,
+// and returns the values of all the inputs, with the defaults of the ones which haven't been given.
+// The values of the boolean and number inputs are normalized.
+func ValidateWorkflowDispatchInputs(inputs []*WorkflowDispatchInput, values map[string]string) (map[string]string, error) {
+	for name := range values {
+		if !slices.ContainsFunc(inputs, func(input *WorkflowDispatchInput) bool { return input.Name == name }) {
+			return nil, util.NewInvalidArgumentErrorf("unexpected input %q", name)
+		}
+	}
+
+	ret := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		value, ok := values[input.Name]
+		if !ok || value == "" {
+			value = input.Default
+		}
+		if value == "" {
+			if input.Required && input.Type != WorkflowDispatchInputTypeBoolean {
+				return nil, util.NewInvalidArgumentErrorf("input %q is required", input.Name)
+			}
+			if input.Type == WorkflowDispatchInputTypeBoolean {
+				value = "false"
+			}
+			ret[input.Name] = value
+			continue
+		}
+
+		switch input.Type {
+		case WorkflowDispatchInputTypeString, WorkflowDispatchInputTypeEnvironment:
+		case WorkflowDispatchInputTypeChoice:
+			if !slices.Contains(input.Options, value) {
+				return nil, util.NewInvalidArgumentErrorf("input %q must be one of %s", input.Name, strings.Join(input.Options, ", "))
+			}
+		case WorkflowDispatchInputTypeBoolean:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, util.NewInvalidArgumentErrorf("input %q must be a boolean", input.Name)
+			}
+			value = strconv.FormatBool(b)
+		case WorkflowDispatchInputTypeNumber:
+			f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, util.NewInvalidArgumentErrorf("input %q must be a number", input.Name)
+			}
+			value = strconv.FormatFloat(f, 'f', -1, 64)
+		default:
+			return nil, util.NewInvalidArgumentErrorf("input %q has an unsupported type %q", input.Name, input.Type)
+		}
+		ret[input.Name] = value
+	}
+	return ret, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWorkflowDispatchInputs(t *testing.T) {
+	wf, err := model.ReadWorkflow(strings.NewReader(`
+on:
+  push:
+  workflow_dispatch:
+    inputs:
+      version:
+        required: true
+      level:
+        type: choice
+        options: [info, debug]
+        default: info
+      dry_run:
+        type: boolean
+        default: true
+      retries:
+        type: number
+      target:
+        type: environment
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo
+`))
+	require.NoError(t, err)
+	inputs, ok := GetWorkflowDispatchInputs(wf)
+	assert.True(t, ok)
+	if assert.Len(t, inputs, 5) {
+		names := make([]string, 0, len(inputs))
+		for _, input := range inputs {
+			names = append(names, input.Name)
+		}
+		assert.Equal(t, []string{"version", "level", "dry_run", "retries", "target"}, names)
+		assert.Equal(t, WorkflowDispatchInputTypeString, inputs[0].Type)
+		assert.True(t, inputs[0].Required)
+		assert.Equal(t, []string{"info", "debug"}, inputs[1].Options)
+		assert.Equal(t, "true", inputs[2].Default)
+	}
+
+	wf, err = model.ReadWorkflow(strings.NewReader("on: [push, workflow_dispatch]\njobs: {}\n"))
+	require.NoError(t, err)
+	inputs, ok = GetWorkflowDispatchInputs(wf)
+	assert.True(t, ok)
+	assert.Empty(t, inputs)
+
+	wf, err = model.ReadWorkflow(strings.NewReader("on: push\njobs: {}\n"))
+	require.NoError(t, err)
+	_, ok = GetWorkflowDispatchInputs(wf)
+	assert.False(t, ok)
+}
+
+func TestValidateWorkflowDispatchInputs(t *testing.T) {
+	inputs := []*WorkflowDispatchInput{
+		{Name: "version", WorkflowDispatchInput: model.WorkflowDispatchInput{Type: WorkflowDispatchInputTypeString, Required: true}},
+		{Name: "level", WorkflowDispatchInput: model.WorkflowDispatchInput{Type: WorkflowDispatchInputTypeChoice, Options: []string{"info", "debug"}, Default: "info"}},
+		{Name: "dry_run", WorkflowDispatchInput: model.WorkflowDispatchInput{Type: WorkflowDispatchInputTypeBoolean}},
+		{Name: "retries", WorkflowDispatchInput: model.WorkflowDispatchInput{Type: WorkflowDispatchInputTypeNumber}},
+		{Name: "target", WorkflowDispatchInput: model.WorkflowDispatchInput{Type: WorkflowDispatchInputTypeEnvironment}},
+	}
+
+	values, err := ValidateWorkflowDispatchInputs(inputs, map[string]string{"version": "1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"version": "1.0", "level": "info", "dry_run": "false", "retries": "", "target": ""}, values)
+
+	values, err = ValidateWorkflowDispatchInputs(inputs, map[string]string{
+		"version": "1.0",
+		"level":   "debug",
+		"dry_run": "1",
+		"retries": " 3.0 ",
+		"target":  "production",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"version": "1.0", "level": "debug", "dry_run": "true", "retries": "3", "target": "production"}, values)
+
+	for _, tc := range []map[string]string{
+		{},
+		{"version": "1.0", "level": "trace"},
+		{"version": "1.0", "dry_run": "maybe"},
+		{"version": "1.0", "retries": "three"},
+		{"version": "1.0", "retries": "NaN"},
+		{"version": "1.0", "unknown": "value"},
+	} {
+		_, err := ValidateWorkflowDispatchInputs(inputs, tc)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument, "values: %v", tc)
+	}
+
+	_, err = ValidateWorkflowDispatchInputs([]*WorkflowDispatchInput{
+		{Name: "file", WorkflowDispatchInput: model.WorkflowDispatchInput{Type: "file"}},
+	}, map[string]string{"file": "a.txt"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
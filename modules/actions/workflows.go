@@ -95,6 +95,25 @@ func GetEventsFromContent(content []byte) ([]*jobparser.Event, error) {
 	return events, nil
 }
 
+// GetJobMatrix returns the matrix combination of a job parsed by the job parser, which keeps a single value per dimension.
+// It returns nil if the job has no matrix.
+func GetJobMatrix(job *jobparser.Job) (map[string]any, error) {
+	if job.Strategy.RawMatrix.IsZero() {
+		return nil, nil
+	}
+	var rawMatrix map[string][]any
+	if err := job.Strategy.RawMatrix.Decode(&rawMatrix); err != nil {
+		return nil, err
+	}
+	matrix := make(map[string]any, len(rawMatrix))
+	for k, values := range rawMatrix {
+		if len(values) > 0 {
+			matrix[k] = values[0]
+		}
+	}
+	return matrix, nil
+}
+
 func DetectWorkflows(
 	gitRepo *git.Repository,
 	commit *git.Commit,
@@ -10,6 +10,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 
+	"github.com/nektos/act/pkg/jobparser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -136,3 +137,37 @@ func TestDetectMatched(t *testing.T) {
 		})
 	}
 }
+
+func TestGetJobMatrix(t *testing.T) {
+	workflows, err := jobparser.Parse([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, windows]
+        go: ["1.22"]
+    steps:
+      - run: echo build
+  test:
+    needs: build
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`))
+	assert.NoError(t, err)
+
+	matrices := make(map[string]map[string]any, len(workflows))
+	for _, workflow := range workflows {
+		_, job := workflow.Job()
+		matrix, err := GetJobMatrix(job)
+		assert.NoError(t, err)
+		matrices[job.Name] = matrix
+	}
+	assert.Equal(t, map[string]map[string]any{
+		"build (1.22, linux)":   {"os": "linux", "go": "1.22"},
+		"build (1.22, windows)": {"os": "windows", "go": "1.22"},
+		"test":                  nil,
+	}, matrices)
+}
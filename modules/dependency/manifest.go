@@ -0,0 +1,306 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package dependency
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/ini.v1"
+)
+
+// Supported ecosystems
+const (
+	EcosystemGo       = "go"
+	EcosystemNpm      = "npm"
+	EcosystemPyPI     = "pypi"
+	EcosystemComposer = "composer"
+)
+
+// MaxManifestSize is the maximum size of a manifest file which will be parsed
+const MaxManifestSize = 1024 * 1024
+
+// Dependency represents a package required by a manifest
+type Dependency struct {
+	Name    string
+	Version string
+	IsDev   bool
+}
+
+// Manifest represents a parsed manifest file
+type Manifest struct {
+	Path      string
+	Ecosystem string
+	// Name is the package name declared by the manifest, it may be empty
+	Name         string
+	Dependencies []*Dependency
+}
+
+var manifestParsers = map[string]func(content []byte) (*Manifest, error){
+	"go.mod":           parseGoMod,
+	"package.json":     parsePackageJSON,
+	"requirements.txt": parseRequirementsTxt,
+	"pyproject.toml":   parsePyProjectToml,
+	"setup.cfg":        parseSetupCfg,
+	"composer.json":    parseComposerJSON,
+}
+
+// IsManifest returns whether the file at the given path is a supported manifest file
+func IsManifest(filePath string) bool {
+	_, ok := manifestParsers[path.Base(filePath)]
+	return ok
+}
+
+// IsIgnoredDir returns whether manifests inside the directory should be skipped because they belong to vendored or generated code
+func IsIgnoredDir(dirPath string) bool {
+	for _, part := range strings.Split(dirPath, "/") {
+		switch part {
+		case "node_modules", "vendor", "testdata", ".git":
+			return true
+		}
+	}
+	return false
+}
+
+// ParseManifest parses the content of the manifest at the given path
+func ParseManifest(filePath string, content []byte) (*Manifest, error) {
+	parser, ok := manifestParsers[path.Base(filePath)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported manifest file: %s", filePath)
+	}
+	m, err := parser(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filePath, err)
+	}
+	m.Path = filePath
+	sort.SliceStable(m.Dependencies, func(i, j int) bool {
+		return m.Dependencies[i].Name < m.Dependencies[j].Name
+	})
+	return m, nil
+}
+
+func parseGoMod(content []byte) (*Manifest, error) {
+	m := &Manifest{Ecosystem: EcosystemGo}
+	inRequire := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if inRequire {
+			if fields[0] == ")" {
+				inRequire = false
+				continue
+			}
+			if len(fields) >= 2 {
+				m.Dependencies = append(m.Dependencies, &Dependency{Name: strings.Trim(fields[0], `"`), Version: fields[1]})
+			}
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			if len(fields) >= 2 {
+				m.Name = strings.Trim(fields[1], `"`)
+			}
+		case "require":
+			if len(fields) >= 2 && fields[1] == "(" {
+				inRequire = true
+			} else if len(fields) >= 3 {
+				m.Dependencies = append(m.Dependencies, &Dependency{Name: strings.Trim(fields[1], `"`), Version: fields[2]})
+			}
+		}
+	}
+	return m, scanner.Err()
+}
+
+func parsePackageJSON(content []byte) (*Manifest, error) {
+	var pkg struct {
+		Name            string            `json:"name"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+	m := &Manifest{Ecosystem: EcosystemNpm, Name: pkg.Name}
+	for name, version := range pkg.Dependencies {
+		m.Dependencies = append(m.Dependencies, &Dependency{Name: name, Version: version})
+	}
+	for name, version := range pkg.DevDependencies {
+		m.Dependencies = append(m.Dependencies, &Dependency{Name: name, Version: version, IsDev: true})
+	}
+	return m, nil
+}
+
+func parseComposerJSON(content []byte) (*Manifest, error) {
+	var pkg struct {
+		Name       string            `json:"name"`
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+	// platform requirements like "php" or "ext-json" are not packages
+	isPlatform := func(name string) bool {
+		return !strings.Contains(name, "/")
+	}
+	m := &Manifest{Ecosystem: EcosystemComposer, Name: strings.ToLower(pkg.Name)}
+	for name, version := range pkg.Require {
+		if !isPlatform(name) {
+			m.Dependencies = append(m.Dependencies, &Dependency{Name: strings.ToLower(name), Version: version})
+		}
+	}
+	for name, version := range pkg.RequireDev {
+		if !isPlatform(name) {
+			m.Dependencies = append(m.Dependencies, &Dependency{Name: strings.ToLower(name), Version: version, IsDev: true})
+		}
+	}
+	return m, nil
+}
+
+var (
+	requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*([^;]*)`)
+	pypiNameReplacer   = regexp.MustCompile(`[-_.]+`)
+)
+
+// NormalizePyPIName normalizes a python package name as described by PEP 503
+func NormalizePyPIName(name string) string {
+	return strings.ToLower(pypiNameReplacer.ReplaceAllString(name, "-"))
+}
+
+// parseRequirement parses a PEP 508 requirement like "requests[socks]>=2.31; python_version >= '3.8'"
+func parseRequirement(requirement string) *Dependency {
+	match := requirementPattern.FindStringSubmatch(strings.TrimSpace(requirement))
+	if match == nil {
+		return nil
+	}
+	return &Dependency{
+		Name:    NormalizePyPIName(match[1]),
+		Version: strings.ReplaceAll(strings.TrimSpace(match[3]), " ", ""),
+	}
+}
+
+func parseRequirementsTxt(content []byte) (*Manifest, error) {
+	m := &Manifest{Ecosystem: EcosystemPyPI}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		// skip empty lines and options like "-r other.txt" or "--index-url"
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if d := parseRequirement(line); d != nil {
+			m.Dependencies = append(m.Dependencies, d)
+		}
+	}
+	return m, scanner.Err()
+}
+
+// parsePyProjectToml parses the package metadata of PEP 621, or of Poetry which keeps it in its own table
+func parsePyProjectToml(content []byte) (*Manifest, error) {
+	type poetryDependencies map[string]any
+	var pyproject struct {
+		Project struct {
+			Name         string   `toml:"name"`
+			Dependencies []string `toml:"dependencies"`
+		} `toml:"project"`
+		Tool struct {
+			Poetry struct {
+				Name            string             `toml:"name"`
+				Dependencies    poetryDependencies `toml:"dependencies"`
+				DevDependencies poetryDependencies `toml:"dev-dependencies"`
+				Group           map[string]struct {
+					Dependencies poetryDependencies `toml:"dependencies"`
+				} `toml:"group"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
+	}
+	if err := toml.Unmarshal(content, &pyproject); err != nil {
+		return nil, err
+	}
+
+	poetry := pyproject.Tool.Poetry
+	m := &Manifest{Ecosystem: EcosystemPyPI, Name: pyproject.Project.Name}
+	if m.Name == "" {
+		m.Name = poetry.Name
+	}
+	if m.Name != "" {
+		m.Name = NormalizePyPIName(m.Name)
+	}
+	for _, requirement := range pyproject.Project.Dependencies {
+		if d := parseRequirement(requirement); d != nil {
+			m.Dependencies = append(m.Dependencies, d)
+		}
+	}
+
+	addPoetryDependencies := func(deps poetryDependencies, isDev bool) {
+		for name, spec := range deps {
+			// the python version is a requirement of the interpreter, not a package
+			if name == "python" {
+				continue
+			}
+			d := &Dependency{Name: NormalizePyPIName(name), IsDev: isDev}
+			switch v := spec.(type) {
+			case string:
+				d.Version = v
+			case map[string]any:
+				d.Version, _ = v["version"].(string)
+			}
+			m.Dependencies = append(m.Dependencies, d)
+		}
+	}
+	addPoetryDependencies(poetry.Dependencies, false)
+	addPoetryDependencies(poetry.DevDependencies, true)
+	for _, group := range poetry.Group {
+		addPoetryDependencies(group.Dependencies, true)
+	}
+	return m, nil
+}
+
+// parseSetupCfg parses the declarative configuration of setuptools
+func parseSetupCfg(content []byte) (*Manifest, error) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		AllowPythonMultilineValues: true,
+		IgnoreInlineComment:        true,
+	}, content)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{Ecosystem: EcosystemPyPI}
+	if name := cfg.Section("metadata").Key("name").String(); name != "" {
+		m.Name = NormalizePyPIName(name)
+	}
+	installRequires := cfg.Section("options").Key("install_requires").String()
+	// the requirements may be read from another file by "file: requirements.txt", which is parsed on its own
+	if !strings.HasPrefix(installRequires, "file:") {
+		for _, line := range strings.Split(installRequires, "\n") {
+			if idx := strings.Index(line, "#"); idx >= 0 {
+				line = line[:idx]
+			}
+			if d := parseRequirement(line); d != nil {
+				m.Dependencies = append(m.Dependencies, d)
+			}
+		}
+	}
+	return m, nil
+}
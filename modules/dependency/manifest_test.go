@@ -0,0 +1,126 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package dependency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	m, err := ParseManifest("sub/go.mod", []byte(`module example.com/foo // the module
+
+go 1.22
+
+require github.com/a/b v1.0.0
+
+require (
+	github.com/c/d v0.1.0 // indirect
+	"github.com/e/f" v2.0.0+incompatible
+)
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "sub/go.mod", m.Path)
+	assert.Equal(t, EcosystemGo, m.Ecosystem)
+	assert.Equal(t, "example.com/foo", m.Name)
+	assert.Equal(t, []*Dependency{
+		{Name: "github.com/a/b", Version: "v1.0.0"},
+		{Name: "github.com/c/d", Version: "v0.1.0"},
+		{Name: "github.com/e/f", Version: "v2.0.0+incompatible"},
+	}, m.Dependencies)
+
+	m, err = ParseManifest("package.json", []byte(`{"name": "@org/lib", "dependencies": {"left-pad": "^1.0.0"}, "devDependencies": {"eslint": "9.0.0"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, EcosystemNpm, m.Ecosystem)
+	assert.Equal(t, "@org/lib", m.Name)
+	assert.Equal(t, []*Dependency{
+		{Name: "eslint", Version: "9.0.0", IsDev: true},
+		{Name: "left-pad", Version: "^1.0.0"},
+	}, m.Dependencies)
+
+	m, err = ParseManifest("requirements.txt", []byte("# comment\n-r base.txt\nDjango >= 4.2, < 5 # web\nrequests[socks]==2.31.0\nZope.Interface\nfoo; python_version < '3.8'\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, EcosystemPyPI, m.Ecosystem)
+	assert.Equal(t, []*Dependency{
+		{Name: "django", Version: ">=4.2,<5"},
+		{Name: "foo", Version: ""},
+		{Name: "requests", Version: "==2.31.0"},
+		{Name: "zope-interface", Version: ""},
+	}, m.Dependencies)
+
+	m, err = ParseManifest("pyproject.toml", []byte(`[project]
+name = "My_Lib"
+dependencies = ["requests[socks] >= 2.31", "tomli; python_version < '3.11'"]
+
+[project.optional-dependencies]
+docs = ["sphinx"]
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, EcosystemPyPI, m.Ecosystem)
+	assert.Equal(t, "my-lib", m.Name)
+	assert.Equal(t, []*Dependency{
+		{Name: "requests", Version: ">=2.31"},
+		{Name: "tomli", Version: ""},
+	}, m.Dependencies)
+
+	m, err = ParseManifest("pyproject.toml", []byte(`[tool.poetry]
+name = "poetry.lib"
+
+[tool.poetry.dependencies]
+python = "^3.10"
+Flask = "^3.0"
+numpy = { version = ">=1.26", optional = true }
+
+[tool.poetry.group.test.dependencies]
+pytest = "^8"
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "poetry-lib", m.Name)
+	assert.Equal(t, []*Dependency{
+		{Name: "flask", Version: "^3.0"},
+		{Name: "numpy", Version: ">=1.26"},
+		{Name: "pytest", Version: "^8", IsDev: true},
+	}, m.Dependencies)
+
+	m, err = ParseManifest("setup.cfg", []byte(`[metadata]
+name = setup_lib
+version = 1.0
+
+[options]
+install_requires =
+    click>=8 # cli
+    attrs
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, EcosystemPyPI, m.Ecosystem)
+	assert.Equal(t, "setup-lib", m.Name)
+	assert.Equal(t, []*Dependency{
+		{Name: "attrs", Version: ""},
+		{Name: "click", Version: ">=8"},
+	}, m.Dependencies)
+
+	m, err = ParseManifest("composer.json", []byte(`{"name": "Vendor/Pkg", "require": {"php": ">=8.1", "ext-json": "*", "monolog/monolog": "^3.0"}, "require-dev": {"phpunit/phpunit": "^10"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "vendor/pkg", m.Name)
+	assert.Equal(t, []*Dependency{
+		{Name: "monolog/monolog", Version: "^3.0"},
+		{Name: "phpunit/phpunit", Version: "^10", IsDev: true},
+	}, m.Dependencies)
+
+	_, err = ParseManifest("package.json", []byte(`{`))
+	assert.Error(t, err)
+	_, err = ParseManifest("Cargo.toml", nil)
+	assert.Error(t, err)
+}
+
+func TestIsManifest(t *testing.T) {
+	assert.True(t, IsManifest("go.mod"))
+	assert.True(t, IsManifest("web/package.json"))
+	assert.True(t, IsManifest("pyproject.toml"))
+	assert.True(t, IsManifest("setup.cfg"))
+	assert.False(t, IsManifest("package-lock.json"))
+	assert.True(t, IsIgnoredDir("web/node_modules/foo"))
+	assert.False(t, IsIgnoredDir("web/src"))
+}
@@ -0,0 +1,313 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// catFilePoolSyncLine is written to a released process to check that all of its output has been consumed,
+// the all-zero hash never exists in a repository so git answers with "<hash> missing"
+const catFilePoolSyncLine = "0000000000000000000000000000000000000000"
+
+// catFilePoolSyncTimeout is how long a released process has to answer the sync line, it is killed if it doesn't
+var catFilePoolSyncTimeout = 5 * time.Second
+
+type catFileKind int
+
+const (
+	catFileKindBatch catFileKind = iota
+	catFileKindBatchCheck
+)
+
+func (k catFileKind) String() string {
+	if k == catFileKindBatchCheck {
+		return "batch-check"
+	}
+	return "batch"
+}
+
+func (k catFileKind) open(ctx context.Context, repoPath string) (WriteCloserError, *bufio.Reader, func()) {
+	if k == catFileKindBatchCheck {
+		return CatFileBatchCheck(ctx, repoPath)
+	}
+	return CatFileBatch(ctx, repoPath)
+}
+
+type catFilePoolKey struct {
+	repoPath string
+	kind     catFileKind
+}
+
+// catFilePoolEntry holds the processes of one kind for a repository in a given state
+type catFilePoolEntry struct {
+	key   catFilePoolKey
+	info  os.FileInfo
+	idle  []*catFileProcess
+	inUse int
+}
+
+type catFileProcess struct {
+	entry     *catFilePoolEntry
+	writer    WriteCloserError
+	reader    *bufio.Reader
+	cancel    func()
+	idleSince time.Time
+}
+
+// CatFilePoolStats are the statistics of the pool of cat-file processes
+type CatFilePoolStats struct {
+	Idle    int   // Idle is the number of processes waiting to be reused
+	InUse   int   // InUse is the number of pooled processes being used
+	Started int64 // Started is the number of pooled processes started so far
+	Reused  int64 // Reused is the number of times an idle process has been reused
+	Closed  int64 // Closed is the number of pooled processes closed so far
+}
+
+type catFilePool struct {
+	mu             sync.Mutex
+	entries        map[catFilePoolKey]*catFilePoolEntry
+	stats          CatFilePoolStats
+	janitorRunning bool
+}
+
+var globalCatFilePool = &catFilePool{entries: make(map[catFilePoolKey]*catFilePoolEntry)}
+
+// GetCatFilePoolStats returns the statistics of the pool of cat-file processes
+func GetCatFilePoolStats() CatFilePoolStats {
+	globalCatFilePool.mu.Lock()
+	defer globalCatFilePool.mu.Unlock()
+	return globalCatFilePool.stats
+}
+
+// sameRepoState reports whether the repository directory is still the one the processes were started in.
+// A repository recreated at the same path or whose top level has been modified (e.g. by a ref update) gets new processes.
+func sameRepoState(a, b os.FileInfo) bool {
+	return os.SameFile(a, b) && a.ModTime().Equal(b.ModTime())
+}
+
+// isSubPath reports whether target is dir itself or is inside dir
+func isSubPath(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// get returns an idle process of the repository or starts a new one,
+// processes beyond the per-repository limit are not pooled and are closed when released.
+// Only the repositories stored in the repository root are pooled, temporary repositories get their own processes.
+func (p *catFilePool) get(ctx context.Context, repoPath string, kind catFileKind) (WriteCloserError, *bufio.Reader, func()) {
+	if setting.RepoRootPath == "" || !isSubPath(setting.RepoRootPath, repoPath) {
+		return kind.open(ctx, repoPath)
+	}
+	info, err := os.Stat(repoPath)
+	if err != nil {
+		return kind.open(ctx, repoPath)
+	}
+	key := catFilePoolKey{repoPath: repoPath, kind: kind}
+
+	p.mu.Lock()
+	var stale []*catFileProcess
+	entry := p.entries[key]
+	if entry != nil && !sameRepoState(entry.info, info) {
+		stale = p.removeEntryLocked(entry)
+		entry = nil
+	}
+	if entry == nil {
+		entry = &catFilePoolEntry{key: key, info: info}
+		p.entries[key] = entry
+	}
+
+	var proc *catFileProcess
+	if n := len(entry.idle); n > 0 {
+		proc = entry.idle[n-1]
+		entry.idle = entry.idle[:n-1]
+		entry.inUse++
+		p.stats.Idle--
+		p.stats.InUse++
+		p.stats.Reused++
+	} else if entry.inUse < setting.Git.CatFilePool.MaxProcessesPerRepo {
+		proc = &catFileProcess{entry: entry}
+		entry.inUse++
+		p.stats.InUse++
+		p.stats.Started++
+	}
+	if proc == nil && entry.inUse == 0 {
+		delete(p.entries, key)
+	}
+	p.mu.Unlock()
+
+	p.closeProcesses(stale)
+
+	if proc == nil {
+		log.Debug("Opening temporary cat file %s for: %s", kind, repoPath)
+		return kind.open(ctx, repoPath)
+	}
+	if proc.cancel == nil {
+		// pooled processes outlive the request which started them
+		proc.writer, proc.reader, proc.cancel = kind.open(DefaultContext, repoPath)
+	}
+
+	var once sync.Once
+	return proc.writer, proc.reader, func() {
+		once.Do(func() { p.put(proc) })
+	}
+}
+
+// put returns a released process to the pool, or closes it if it can't be reused
+func (p *catFilePool) put(proc *catFileProcess) {
+	reusable := proc.sync()
+
+	p.mu.Lock()
+	entry := proc.entry
+	entry.inUse--
+	p.stats.InUse--
+	if reusable && p.entries[entry.key] == entry && p.stats.Idle < setting.Git.CatFilePool.MaxIdleProcesses {
+		proc.idleSince = time.Now()
+		entry.idle = append(entry.idle, proc)
+		p.stats.Idle++
+		if !p.janitorRunning {
+			p.janitorRunning = true
+			go p.janitor()
+		}
+		proc = nil
+	} else if entry.inUse == 0 && len(entry.idle) == 0 && p.entries[entry.key] == entry {
+		delete(p.entries, entry.key)
+	}
+	p.mu.Unlock()
+
+	if proc != nil {
+		p.closeProcesses([]*catFileProcess{proc})
+	}
+}
+
+// sync checks that the output of the process has been fully consumed by its last user and that it is still alive,
+// a process which doesn't answer within catFilePoolSyncTimeout is killed
+func (proc *catFileProcess) sync() bool {
+	done := make(chan bool, 1)
+	go func() {
+		done <- proc.syncOutput()
+	}()
+
+	timer := time.NewTimer(catFilePoolSyncTimeout)
+	defer timer.Stop()
+	select {
+	case ok := <-done:
+		return ok
+	case <-timer.C:
+		log.Warn("cat-file %s process for %s did not answer within %v, killing it", proc.entry.key.kind, proc.entry.key.repoPath, catFilePoolSyncTimeout)
+		// killing the process closes its pipes, which unblocks the pending write or read
+		proc.cancel()
+		<-done
+		return false
+	}
+}
+
+func (proc *catFileProcess) syncOutput() bool {
+	if proc.reader.Buffered() > 0 {
+		// a trailing line feed after the content of an object is allowed, ReadBatchLine skips it
+		if b, _ := proc.reader.Peek(proc.reader.Buffered()); len(b) != 1 || b[0] != '\n' {
+			return false
+		}
+	}
+	if _, err := proc.writer.Write([]byte(catFilePoolSyncLine + "\n")); err != nil {
+		return false
+	}
+	line, err := proc.reader.ReadSlice('\n')
+	if err == nil && len(line) == 1 {
+		line, err = proc.reader.ReadSlice('\n')
+	}
+	return err == nil && string(line) == catFilePoolSyncLine+" missing\n"
+}
+
+// removeEntryLocked removes the entry from the pool and returns its idle processes to be closed,
+// the processes in use are closed when they are released
+func (p *catFilePool) removeEntryLocked(entry *catFilePoolEntry) []*catFileProcess {
+	delete(p.entries, entry.key)
+	idle := entry.idle
+	entry.idle = nil
+	p.stats.Idle -= len(idle)
+	return idle
+}
+
+// CloseCatFileProcesses closes the pooled cat-file processes of the repositories at or inside dirPath,
+// it must be called before these repositories are removed or moved. The processes in use are closed when they are released.
+func CloseCatFileProcesses(dirPath string) {
+	globalCatFilePool.closeRepoProcesses(dirPath)
+}
+
+func (p *catFilePool) closeRepoProcesses(dirPath string) {
+	var idle []*catFileProcess
+
+	p.mu.Lock()
+	for key, entry := range p.entries {
+		if isSubPath(dirPath, key.repoPath) {
+			idle = append(idle, p.removeEntryLocked(entry)...)
+		}
+	}
+	p.mu.Unlock()
+
+	p.closeProcesses(idle)
+}
+
+func (p *catFilePool) closeProcesses(procs []*catFileProcess) {
+	if len(procs) == 0 {
+		return
+	}
+	for _, proc := range procs {
+		proc.cancel()
+	}
+	p.mu.Lock()
+	p.stats.Closed += int64(len(procs))
+	p.mu.Unlock()
+}
+
+// janitor closes the processes which have been idle for longer than the idle timeout, it stops once the pool has no idle process
+func (p *catFilePool) janitor() {
+	idleTimeout := setting.Git.CatFilePool.IdleTimeout
+	ticker := time.NewTicker(max(idleTimeout/2, time.Millisecond))
+	defer ticker.Stop()
+	for range ticker.C {
+		if !p.closeIdleProcesses(time.Now().Add(-idleTimeout)) {
+			return
+		}
+	}
+}
+
+// closeIdleProcesses closes the processes which have been idle since before the deadline,
+// it returns whether the janitor should keep running
+func (p *catFilePool) closeIdleProcesses(deadline time.Time) bool {
+	var expired []*catFileProcess
+
+	p.mu.Lock()
+	for key, entry := range p.entries {
+		kept := entry.idle[:0]
+		for _, proc := range entry.idle {
+			if proc.idleSince.Before(deadline) {
+				expired = append(expired, proc)
+			} else {
+				kept = append(kept, proc)
+			}
+		}
+		entry.idle = kept
+		if entry.inUse == 0 && len(entry.idle) == 0 {
+			delete(p.entries, key)
+		}
+	}
+	p.stats.Idle -= len(expired)
+	running := p.stats.Idle > 0
+	p.janitorRunning = running
+	p.mu.Unlock()
+
+	p.closeProcesses(expired)
+	return running
+}
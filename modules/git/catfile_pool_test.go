@@ -0,0 +1,151 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !gogit
+
+package git
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatFilePool(t *testing.T) {
+	defer test.MockVariableValue(&setting.Git.CatFilePool.Enabled, true)()
+	defer test.MockVariableValue(&setting.Git.CatFilePool.MaxProcessesPerRepo, 1)()
+
+	repoPath, err := cloneRepo(t, filepath.Join(testReposDir, "repo1_bare"))
+	require.NoError(t, err)
+	defer test.MockVariableValue(&setting.RepoRootPath, filepath.Dir(repoPath))()
+	repo, err := openRepositoryWithDefaultContext(repoPath)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	readCommit := func() {
+		wr, rd, cancel := repo.CatFileBatch(repo.Ctx)
+		defer cancel()
+		_, err := wr.Write([]byte("ce064814f4a0d337b333e646ece456cd39fab612\n"))
+		require.NoError(t, err)
+		_, typ, size, err := ReadBatchLine(rd)
+		require.NoError(t, err)
+		assert.Equal(t, "commit", typ)
+		_, err = rd.Discard(int(size))
+		require.NoError(t, err)
+	}
+
+	before := GetCatFilePoolStats()
+	readCommit()
+	readCommit()
+	stats := GetCatFilePoolStats()
+	assert.EqualValues(t, 1, stats.Started-before.Started)
+	assert.EqualValues(t, 1, stats.Reused-before.Reused)
+	assert.Equal(t, before.Idle+1, stats.Idle)
+
+	t.Run("OverLimit", func(t *testing.T) {
+		before := GetCatFilePoolStats()
+		_, _, cancel1 := repo.CatFileBatch(repo.Ctx)
+		_, _, cancel2 := repo.CatFileBatch(repo.Ctx)
+		cancel2()
+		cancel1()
+		stats := GetCatFilePoolStats()
+		assert.EqualValues(t, 0, stats.Started-before.Started)
+		assert.EqualValues(t, 1, stats.Reused-before.Reused)
+		assert.Equal(t, before.Idle, stats.Idle)
+	})
+
+	t.Run("UnconsumedOutput", func(t *testing.T) {
+		before := GetCatFilePoolStats()
+		wr, rd, cancel := repo.CatFileBatch(repo.Ctx)
+		_, err := wr.Write([]byte("ce064814f4a0d337b333e646ece456cd39fab612\n"))
+		require.NoError(t, err)
+		_, _, _, err = ReadBatchLine(rd)
+		require.NoError(t, err)
+		cancel()
+		stats := GetCatFilePoolStats()
+		assert.EqualValues(t, 1, stats.Closed-before.Closed)
+		assert.Equal(t, before.Idle-1, stats.Idle)
+
+		readCommit()
+		assert.EqualValues(t, 1, GetCatFilePoolStats().Started-before.Started)
+	})
+
+	t.Run("RepositoryChanged", func(t *testing.T) {
+		before := GetCatFilePoolStats()
+		modTime := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(repoPath, modTime, modTime))
+		readCommit()
+		stats := GetCatFilePoolStats()
+		assert.EqualValues(t, 1, stats.Started-before.Started)
+		assert.EqualValues(t, 1, stats.Closed-before.Closed)
+		assert.Equal(t, before.Idle, stats.Idle)
+	})
+
+	t.Run("TemporaryRepository", func(t *testing.T) {
+		defer test.MockVariableValue(&setting.RepoRootPath, t.TempDir())()
+		before := GetCatFilePoolStats()
+		readCommit()
+		stats := GetCatFilePoolStats()
+		assert.EqualValues(t, 0, stats.Started-before.Started)
+		assert.EqualValues(t, 0, stats.Reused-before.Reused)
+	})
+
+	t.Run("CloseRepositoryProcesses", func(t *testing.T) {
+		readCommit()
+		before := GetCatFilePoolStats()
+		require.Positive(t, before.Idle)
+
+		// a process in use is closed once released
+		_, _, cancel := repo.CatFileBatchCheck(repo.Ctx)
+		CloseCatFileProcesses(filepath.Dir(repoPath))
+		stats := GetCatFilePoolStats()
+		assert.Zero(t, stats.Idle)
+		assert.EqualValues(t, before.Idle, stats.Closed-before.Closed)
+		cancel()
+		stats = GetCatFilePoolStats()
+		assert.Zero(t, stats.Idle)
+		assert.EqualValues(t, before.Idle+1, stats.Closed-before.Closed)
+
+		readCommit()
+		assert.Equal(t, 1, GetCatFilePoolStats().Idle)
+	})
+
+	t.Run("IdleTimeout", func(t *testing.T) {
+		before := GetCatFilePoolStats()
+		require.Positive(t, before.Idle)
+		assert.True(t, globalCatFilePool.closeIdleProcesses(time.Now().Add(-time.Minute)))
+		assert.Equal(t, before.Idle, GetCatFilePoolStats().Idle)
+		assert.False(t, globalCatFilePool.closeIdleProcesses(time.Now().Add(time.Second)))
+		assert.Zero(t, GetCatFilePoolStats().Idle)
+	})
+}
+
+func TestCatFilePoolSyncTimeout(t *testing.T) {
+	defer test.MockVariableValue(&catFilePoolSyncTimeout, 10*time.Millisecond)()
+
+	// nothing reads the input of the process nor writes its output, like a hung cat-file
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+	killed := false
+	proc := &catFileProcess{
+		entry:  &catFilePoolEntry{key: catFilePoolKey{repoPath: "hung.git"}},
+		writer: stdinWriter,
+		reader: bufio.NewReader(stdoutReader),
+		cancel: func() {
+			killed = true
+			_ = stdinReader.Close()
+			_ = stdoutWriter.Close()
+		},
+	}
+	assert.False(t, proc.sync())
+	assert.True(t, killed)
+}
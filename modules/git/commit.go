@@ -519,6 +519,33 @@ func GetCommitFileStatus(ctx context.Context, repoPath, commitID string) (*Commi
 	return fileStatus, nil
 }
 
+// CommitFileRename represents a file renamed by a commit
+type CommitFileRename struct {
+	From       string
+	To         string
+	Similarity int
+}
+
+// GetCommitFileRenames returns the files renamed by a commit, detected against its first parent.
+func GetCommitFileRenames(ctx context.Context, repoPath, commitID string) ([]*CommitFileRename, error) {
+	stdout, _, err := NewCommand(ctx, "log", "--name-status", "-m", "--pretty=format:", "--first-parent", "-M", "--diff-filter=R", "-z", "-1").
+		AddDynamicArguments(commitID).RunStdString(&RunOpts{Dir: repoPath})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(strings.TrimLeft(stdout, "\n\x00"), "\x00")
+	renames := make([]*CommitFileRename, 0, len(fields)/3)
+	for i := 0; i+2 < len(fields); i += 3 {
+		if !strings.HasPrefix(fields[i], "R") {
+			break
+		}
+		similarity, _ := strconv.Atoi(fields[i][1:])
+		renames = append(renames, &CommitFileRename{From: fields[i+1], To: fields[i+2], Similarity: similarity})
+	}
+	return renames, nil
+}
+
 // GetFullCommitID returns full length (40) of commit ID by given short SHA in a repository.
 func GetFullCommitID(ctx context.Context, repoPath, shortID string) (string, error) {
 	commitID, _, err := NewCommand(ctx, "rev-parse").AddDynamicArguments(shortID).RunStdString(&RunOpts{Dir: repoPath})
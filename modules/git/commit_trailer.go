@@ -0,0 +1,126 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CommitTrailer represents a trailer of a commit message, e.g. "Co-authored-by: Name <email>"
+type CommitTrailer struct {
+	Key   string
+	Value string
+}
+
+var (
+	commitTrailerKeyPattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+	commitTrailerLinePattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)[ \t]*:[ \t]*(.*)$`)
+)
+
+// String returns the trailer in its canonical "Key: value" form
+func (t CommitTrailer) String() string {
+	return t.Key + ": " + t.Value
+}
+
+// Validate checks that the trailer can be written to a commit message
+func (t CommitTrailer) Validate() error {
+	if !commitTrailerKeyPattern.MatchString(t.Key) {
+		return util.NewInvalidArgumentErrorf("invalid commit trailer key %q", t.Key)
+	}
+	if strings.TrimSpace(t.Value) == "" || strings.ContainsAny(t.Value, "\r\n") {
+		return util.NewInvalidArgumentErrorf("invalid value for commit trailer %q", t.Key)
+	}
+	return nil
+}
+
+// splitCommitTrailers splits a commit message into its body and the trailers of its last paragraph.
+// The trailers are nil if the last paragraph is not a trailer block.
+func splitCommitTrailers(message string) (string, []CommitTrailer) {
+	message = strings.TrimRight(message, " \t\r\n")
+	idx := strings.LastIndex(message, "\n\n")
+	if idx < 0 {
+		// the subject line is never a trailer
+		return message, nil
+	}
+
+	var trailers []CommitTrailer
+	for _, line := range strings.Split(message[idx+2:], "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if len(trailers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			// continuation of a folded value
+			trailers[len(trailers)-1].Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		m := commitTrailerLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			return message, nil
+		}
+		trailers = append(trailers, CommitTrailer{Key: m[1], Value: m[2]})
+	}
+	return strings.TrimRight(message[:idx], " \t\r\n"), trailers
+}
+
+// ParseCommitTrailers returns the trailers of a commit message
+func ParseCommitTrailers(message string) []CommitTrailer {
+	_, trailers := splitCommitTrailers(message)
+	return trailers
+}
+
+func hasCommitTrailer(trailers []CommitTrailer, trailer CommitTrailer) bool {
+	for _, t := range trailers {
+		if strings.EqualFold(t.Key, trailer.Key) && t.Value == trailer.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendCommitTrailers appends the trailers to the trailer block of the message,
+// starting a new block if the message has none. Trailers already present are skipped.
+func AppendCommitTrailers(message string, trailers ...CommitTrailer) string {
+	message = strings.TrimRight(message, " \t\r\n")
+	existing := ParseCommitTrailers(message)
+	hasTrailerBlock := existing != nil
+
+	sb := strings.Builder{}
+	for _, trailer := range trailers {
+		if hasCommitTrailer(existing, trailer) {
+			continue
+		}
+		existing = append(existing, trailer)
+		sb.WriteString(trailer.String())
+		sb.WriteByte('\n')
+	}
+	if sb.Len() == 0 {
+		return message + "\n"
+	}
+
+	switch {
+	case message == "":
+		return sb.String()
+	case hasTrailerBlock:
+		return message + "\n" + sb.String()
+	default:
+		return message + "\n\n" + sb.String()
+	}
+}
+
+// IsCommitSignedOffBy returns whether the commit message has a Signed-off-by trailer for the signature,
+// as required by the Developer Certificate of Origin
+func IsCommitSignedOffBy(message string, sig *Signature) bool {
+	for _, t := range ParseCommitTrailers(message) {
+		if !strings.EqualFold(t.Key, "Signed-off-by") {
+			continue
+		}
+		name, email, ok := strings.Cut(t.Value, "<")
+		if ok && strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(email), ">"), sig.Email) &&
+			(sig.Name == "" || strings.TrimSpace(name) == sig.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitTrailers(t *testing.T) {
+	assert.Nil(t, ParseCommitTrailers("Subject: with a colon"))
+	assert.Nil(t, ParseCommitTrailers("Subject\n\nJust a body."))
+	assert.Nil(t, ParseCommitTrailers("Subject\n\nKey: value\nnot a trailer"))
+	assert.Equal(t, []CommitTrailer{
+		{Key: "Co-authored-by", Value: "A <a@example.com>"},
+		{Key: "Fixes", Value: "#1 and #2"},
+	}, ParseCommitTrailers("Subject\n\nBody\n\nCo-authored-by: A <a@example.com>\nFixes: #1\n  and #2\n\n"))
+}
+
+func TestAppendCommitTrailers(t *testing.T) {
+	signoff := CommitTrailer{Key: "Signed-off-by", Value: "A <a@example.com>"}
+	fixes := CommitTrailer{Key: "Fixes", Value: "#1"}
+
+	assert.Equal(t, "Subject\n", AppendCommitTrailers("Subject\n"))
+	assert.Equal(t, "Subject\n\nSigned-off-by: A <a@example.com>\n", AppendCommitTrailers("Subject", signoff))
+	assert.Equal(t, "Subject\n\nBody\n\nSigned-off-by: A <a@example.com>\nFixes: #1\n", AppendCommitTrailers("Subject\n\nBody\n", signoff, fixes))
+	assert.Equal(t, "Subject\n\nFixes: #1\nSigned-off-by: A <a@example.com>\n", AppendCommitTrailers("Subject\n\nFixes: #1", signoff))
+	assert.Equal(t, "Subject\n\nsigned-off-by: A <a@example.com>\nFixes: #1\n", AppendCommitTrailers("Subject\n\nsigned-off-by: A <a@example.com>", signoff, fixes, fixes))
+	assert.Equal(t, "Fixes: #1\n", AppendCommitTrailers("", fixes))
+}
+
+func TestCommitTrailerValidate(t *testing.T) {
+	assert.NoError(t, CommitTrailer{Key: "Co-authored-by", Value: "A <a@example.com>"}.Validate())
+	assert.Error(t, CommitTrailer{Key: "Bad key", Value: "value"}.Validate())
+	assert.Error(t, CommitTrailer{Key: "-bad", Value: "value"}.Validate())
+	assert.Error(t, CommitTrailer{Key: "Key", Value: " "}.Validate())
+	assert.Error(t, CommitTrailer{Key: "Key", Value: "two\nlines"}.Validate())
+}
+
+func TestIsCommitSignedOffBy(t *testing.T) {
+	sig := &Signature{Name: "A", Email: "a@example.com"}
+	assert.True(t, IsCommitSignedOffBy("Subject\n\nSigned-off-by: A <A@example.com>\n", sig))
+	assert.False(t, IsCommitSignedOffBy("Subject\n\nSigned-off-by: B <b@example.com>\n", sig))
+	assert.False(t, IsCommitSignedOffBy("Signed-off-by: A <a@example.com>\n", sig))
+}
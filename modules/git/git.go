@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -296,7 +298,13 @@ func syncGitConfig() (err error) {
 		if err = configSet("uploadpack.allowfilter", "true"); err != nil {
 			return err
 		}
-		err = configSet("uploadpack.allowAnySHA1InWant", "true")
+		if err = configSet("uploadpack.allowAnySHA1InWant", "true"); err != nil {
+			return err
+		}
+		// the filters can only be restricted from git v2.29
+		if DefaultFeatures().CheckVersionAtLeast("2.29") {
+			err = syncPartialCloneFilters()
+		}
 	} else {
 		if err = configUnsetAll("uploadpack.allowfilter", "true"); err != nil {
 			return err
@@ -307,6 +315,37 @@ func syncGitConfig() (err error) {
 	return err
 }
 
+// PartialCloneFilters are the kinds of filter specs of partial clones which can be restricted
+var PartialCloneFilters = []string{"blob:none", "blob:limit", "tree", "sparse:oid", "object:type", "combine"}
+
+// syncPartialCloneFilters allows only the partial clone filters of the setting, all of them are allowed if it is empty
+func syncPartialCloneFilters() error {
+	if len(setting.Git.PartialCloneFilters) == 0 {
+		if err := configUnsetAll("uploadpackfilter.allow", ""); err != nil {
+			return err
+		}
+		for _, filter := range PartialCloneFilters {
+			if err := configUnsetAll("uploadpackfilter."+filter+".allow", ""); err != nil {
+				return err
+			}
+		}
+	} else {
+		if err := configSet("uploadpackfilter.allow", "false"); err != nil {
+			return err
+		}
+		for _, filter := range PartialCloneFilters {
+			if err := configSet("uploadpackfilter."+filter+".allow", strconv.FormatBool(slices.Contains(setting.Git.PartialCloneFilters, filter))); err != nil {
+				return err
+			}
+		}
+	}
+
+	if setting.Git.PartialCloneTreeMaxDepth > 0 {
+		return configSet("uploadpackfilter.tree.maxDepth", strconv.Itoa(setting.Git.PartialCloneTreeMaxDepth))
+	}
+	return configUnsetAll("uploadpackfilter.tree.maxDepth", "")
+}
+
 func checkGitVersionCompatibility(gitVer *version.Version) error {
 	badVersions := []struct {
 		Version *version.Version
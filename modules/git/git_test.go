@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/modules/util"
 
 	"github.com/hashicorp/go-version"
@@ -95,6 +96,20 @@ func TestSyncConfig(t *testing.T) {
 	assert.True(t, gitConfigContains("cfg-key-a = CfgValA"))
 }
 
+func TestSyncPartialCloneFilters(t *testing.T) {
+	defer test.MockVariableValue(&setting.Git.PartialCloneFilters, []string{"blob:none", "tree"})()
+	assert.NoError(t, syncPartialCloneFilters())
+	assert.True(t, gitConfigContains("[uploadpackfilter]"))
+	assert.True(t, gitConfigContains("allow = false"))
+	assert.True(t, gitConfigContains(`[uploadpackfilter "sparse:oid"]`))
+
+	// all the filters are allowed by default
+	setting.Git.PartialCloneFilters = []string{}
+	assert.NoError(t, syncPartialCloneFilters())
+	assert.False(t, gitConfigContains("allow = false"))
+	assert.False(t, gitConfigContains("allow = true"))
+}
+
 func TestParseGitVersion(t *testing.T) {
 	v, err := parseGitVersionLine("git version 2.29.3")
 	assert.NoError(t, err)
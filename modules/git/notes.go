@@ -3,6 +3,13 @@
 
 package git
 
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+)
+
 // NotesRef is the git ref where Gitea will look for git-notes data.
 // The value ("refs/notes/commits") is the default ref used by git-notes.
 const NotesRef = "refs/notes/commits"
@@ -12,3 +19,43 @@ type Note struct {
 	Message []byte
 	Commit  *Commit
 }
+
+func notesEnv(doer *Signature) []string {
+	commitTimeStr := time.Now().Format(time.RFC3339)
+	return append(os.Environ(),
+		"GIT_AUTHOR_NAME="+doer.Name,
+		"GIT_AUTHOR_EMAIL="+doer.Email,
+		"GIT_AUTHOR_DATE="+commitTimeStr,
+		"GIT_COMMITTER_NAME="+doer.Name,
+		"GIT_COMMITTER_EMAIL="+doer.Email,
+		"GIT_COMMITTER_DATE="+commitTimeStr,
+	)
+}
+
+// SetNote adds or overwrites the git-notes data for a given commit.
+// The change is committed to NotesRef by the given doer.
+func SetNote(ctx context.Context, repo *Repository, commitID, message string, doer *Signature) error {
+	_, _, err := NewCommand(ctx, "notes", "--ref="+NotesRef, "add", "-f", "-F", "-").
+		AddDynamicArguments(commitID).
+		RunStdString(&RunOpts{
+			Dir:   repo.Path,
+			Env:   notesEnv(doer),
+			Stdin: strings.NewReader(message),
+		})
+	return err
+}
+
+// RemoveNote removes the git-notes data for a given commit.
+// It returns ErrNotExist if the commit has no note.
+func RemoveNote(ctx context.Context, repo *Repository, commitID string, doer *Signature) error {
+	_, stderr, err := NewCommand(ctx, "notes", "--ref="+NotesRef, "remove").
+		AddDynamicArguments(commitID).
+		RunStdString(&RunOpts{
+			Dir: repo.Path,
+			Env: notesEnv(doer),
+		})
+	if err != nil && strings.Contains(stderr, "has no note") {
+		return ErrNotExist{ID: commitID}
+	}
+	return err
+}
@@ -50,3 +50,31 @@ func TestGetNonExistentNotes(t *testing.T) {
 	assert.Error(t, err)
 	assert.IsType(t, ErrNotExist{}, err)
 }
+
+func TestSetAndRemoveNote(t *testing.T) {
+	repoPath, err := cloneRepo(t, filepath.Join(testReposDir, "repo1_bare"))
+	assert.NoError(t, err)
+	repo, err := openRepositoryWithDefaultContext(repoPath)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	doer := &Signature{Name: "Gitea", Email: "gitea@fake.local"}
+	commitID := "37991dec2c8e592043f47155ce4808d4580f9123"
+
+	assert.NoError(t, SetNote(context.Background(), repo, commitID, "Build passed", doer))
+	note := Note{}
+	assert.NoError(t, GetNote(context.Background(), repo, commitID, &note))
+	assert.Equal(t, "Build passed\n", string(note.Message))
+	assert.Equal(t, "Gitea", note.Commit.Author.Name)
+
+	assert.NoError(t, SetNote(context.Background(), repo, commitID, "Build failed", doer))
+	assert.NoError(t, GetNote(context.Background(), repo, commitID, &note))
+	assert.Equal(t, "Build failed\n", string(note.Message))
+
+	assert.NoError(t, RemoveNote(context.Background(), repo, commitID, doer))
+	err = GetNote(context.Background(), repo, commitID, &note)
+	assert.True(t, IsErrNotExist(err))
+
+	err = RemoveNote(context.Background(), repo, commitID, doer)
+	assert.True(t, IsErrNotExist(err))
+}
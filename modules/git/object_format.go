@@ -8,6 +8,8 @@ import (
 	"crypto/sha256"
 	"regexp"
 	"strconv"
+
+	"code.gitea.io/gitea/modules/setting"
 )
 
 // sha1Pattern can be used to determine if a string is an valid sha
@@ -132,3 +134,12 @@ func ObjectFormatFromName(name string) ObjectFormat {
 func IsValidObjectFormat(name string) bool {
 	return ObjectFormatFromName(name) != nil
 }
+
+// DefaultObjectFormatName returns the object format of the new repositories,
+// it is SHA-1 if the configured one is not supported by git
+func DefaultObjectFormatName() string {
+	if IsValidObjectFormat(setting.Repository.DefaultObjectFormat) {
+		return setting.Repository.DefaultObjectFormat
+	}
+	return Sha1ObjectFormat.Name()
+}
@@ -15,6 +15,8 @@ const (
 	RemotePrefix = "refs/remotes/"
 	// PullPrefix is the base directory of the pull information of git.
 	PullPrefix = "refs/pull/"
+	// GiteaPrefix is the base directory of the internal references of Gitea, which are not branches.
+	GiteaPrefix = "refs/gitea/"
 )
 
 // refNamePatternInvalid is regular expression with unallowed characters in git reference name
@@ -190,12 +190,13 @@ func CloneWithArgs(ctx context.Context, args TrustedCmdArgs, from, to string, op
 
 // PushOptions options when push to remote
 type PushOptions struct {
-	Remote  string
-	Branch  string
-	Force   bool
-	Mirror  bool
-	Env     []string
-	Timeout time.Duration
+	Remote         string
+	Branch         string
+	Force          bool
+	ForceWithLease string
+	Mirror         bool
+	Env            []string
+	Timeout        time.Duration
 }
 
 // Push pushs local commits to given remote branch.
@@ -204,6 +205,9 @@ func Push(ctx context.Context, repoPath string, opts PushOptions) error {
 	if opts.Force {
 		cmd.AddArguments("-f")
 	}
+	if opts.ForceWithLease != "" {
+		cmd.AddOptionFormat("--force-with-lease=%s", opts.ForceWithLease)
+	}
 	if opts.Mirror {
 		cmd.AddArguments("--mirror")
 	}
@@ -316,3 +320,10 @@ func (repo *Repository) CreateBundle(ctx context.Context, commit string, out io.
 	_, err = io.Copy(out, fi)
 	return err
 }
+
+// CreateRefsBundle creates a bundle of all the branches and tags of the repository at the target path
+func CreateRefsBundle(ctx context.Context, repoPath, bundlePath string) error {
+	_, _, err := NewCommand(ctx, "bundle", "create").AddDynamicArguments(bundlePath).AddArguments("--branches", "--tags").
+		RunStdString(&RunOpts{Dir: repoPath})
+	return err
+}
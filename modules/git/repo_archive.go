@@ -50,8 +50,8 @@ func ToArchiveType(s string) ArchiveType {
 	return 0
 }
 
-// CreateArchive create archive content to the target path
-func (repo *Repository) CreateArchive(ctx context.Context, format ArchiveType, target io.Writer, usePrefix bool, commitID string) error {
+// CreateArchive create archive content to the target path, the archive only contains the given paths if there are any
+func (repo *Repository) CreateArchive(ctx context.Context, format ArchiveType, target io.Writer, usePrefix bool, commitID string, paths ...string) error {
 	if format.String() == "unknown" {
 		return fmt.Errorf("unknown format: %v", format)
 	}
@@ -61,7 +61,7 @@ func (repo *Repository) CreateArchive(ctx context.Context, format ArchiveType, t
 		cmd.AddOptionFormat("--prefix=%s", filepath.Base(strings.TrimSuffix(repo.Path, ".git"))+"/")
 	}
 	cmd.AddOptionFormat("--format=%s", format.String())
-	cmd.AddDynamicArguments(commitID)
+	cmd.AddDynamicArguments(commitID).AddDashesAndList(paths...)
 
 	// Avoid LFS hooks getting installed because of /etc/gitconfig, which can break pull requests.
 	env := append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1")
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -66,14 +67,20 @@ func OpenRepository(ctx context.Context, repoPath string) (*Repository, error) {
 		Ctx:      ctx,
 	}
 
-	repo.batchWriter, repo.batchReader, repo.batchCancel = CatFileBatch(ctx, repoPath)
-	repo.checkWriter, repo.checkReader, repo.checkCancel = CatFileBatchCheck(ctx, repoPath)
+	// with the pool enabled the processes are obtained from it when needed
+	if !setting.Git.CatFilePool.Enabled {
+		repo.batchWriter, repo.batchReader, repo.batchCancel = CatFileBatch(ctx, repoPath)
+		repo.checkWriter, repo.checkReader, repo.checkCancel = CatFileBatchCheck(ctx, repoPath)
+	}
 
 	return repo, nil
 }
 
 // CatFileBatch obtains a CatFileBatch for this repository
 func (repo *Repository) CatFileBatch(ctx context.Context) (WriteCloserError, *bufio.Reader, func()) {
+	if setting.Git.CatFilePool.Enabled {
+		return globalCatFilePool.get(ctx, repo.Path, catFileKindBatch)
+	}
 	if repo.batchCancel == nil || repo.batchInUse {
 		log.Debug("Opening temporary cat file batch for: %s", repo.Path)
 		return CatFileBatch(ctx, repo.Path)
@@ -86,6 +93,9 @@ func (repo *Repository) CatFileBatch(ctx context.Context) (WriteCloserError, *bu
 
 // CatFileBatchCheck obtains a CatFileBatchCheck for this repository
 func (repo *Repository) CatFileBatchCheck(ctx context.Context) (WriteCloserError, *bufio.Reader, func()) {
+	if setting.Git.CatFilePool.Enabled {
+		return globalCatFilePool.get(ctx, repo.Path, catFileKindBatchCheck)
+	}
 	if repo.checkCancel == nil || repo.checkInUse {
 		log.Debug("Opening temporary cat file batch-check for: %s", repo.Path)
 		return CatFileBatchCheck(ctx, repo.Path)
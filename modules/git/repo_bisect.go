@@ -0,0 +1,78 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"bufio"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// BisectState represents the commits marked while bisecting the history of a repository
+type BisectState struct {
+	Bad     string
+	Good    []string
+	Skipped []string
+	// Path restricts the bisection to the commits changing it if not empty
+	Path string
+}
+
+// BisectResult represents the next step of a bisection
+type BisectResult struct {
+	// Candidate is the next commit to test, it is empty once the first bad commit has been found
+	Candidate string
+	// Remaining is the number of commits which are left to test
+	Remaining int
+	// Steps is the estimated number of steps left
+	Steps int
+	// FirstBad is the first bad commit, it is empty until it has been found
+	FirstBad string
+	// OnlySkipped lists the commits which may be the first bad commit as only skipped commits are left to test
+	OnlySkipped []string
+}
+
+// BisectNext computes the next commit to test to find the first bad commit,
+// the candidates are the commits reachable from the bad commit and not from the good ones, closest to the middle first.
+func (repo *Repository) BisectNext(state *BisectState) (*BisectResult, error) {
+	cmd := NewCommand(repo.Ctx, "rev-list", "--bisect-all").AddDynamicArguments(state.Bad)
+	for _, good := range state.Good {
+		cmd.AddDynamicArguments("^" + good)
+	}
+	cmd.AddArguments("--")
+	if state.Path != "" {
+		cmd.AddDynamicArguments(state.Path)
+	}
+	stdout, _, err := cmd.RunStdString(&RunOpts{Dir: repo.Path})
+	if err != nil {
+		return nil, err
+	}
+
+	// each line is "<commit> (dist=<n>)", the best candidates come first
+	var candidates, skipped []string
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		commitID, _, _ := strings.Cut(scanner.Text(), " ")
+		switch {
+		case commitID == "" || commitID == state.Bad:
+		case slices.Contains(state.Skipped, commitID):
+			skipped = append(skipped, commitID)
+		default:
+			candidates = append(candidates, commitID)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &BisectResult{Remaining: len(candidates)}
+	if len(candidates) == 0 {
+		result.FirstBad = state.Bad
+		result.OnlySkipped = skipped
+		return result, nil
+	}
+	result.Candidate = candidates[0]
+	result.Steps = bits.Len(uint(len(candidates)))
+	return result, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_BisectNext(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := openRepositoryWithDefaultContext(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	// the history of master from the newest commit
+	history := []string{
+		"ce064814f4a0d337b333e646ece456cd39fab612",
+		"feaf4ba6bc635fec442f46ddd4512416ec43c2c2",
+		"37991dec2c8e592043f47155ce4808d4580f9123",
+		"6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1",
+		"8006ff9adbf0cb94da7dad9e537e53817f9fa5c0",
+		"8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2",
+		"95bb4d39648ee7e325106df01a621c530863a653",
+	}
+	firstBad := "8006ff9adbf0cb94da7dad9e537e53817f9fa5c0"
+	isBad := func(commitID string) bool {
+		return slices.Index(history, commitID) <= slices.Index(history, firstBad)
+	}
+
+	bisect := func(state *BisectState) (*BisectResult, int) {
+		for step := 1; ; step++ {
+			result, err := bareRepo1.BisectNext(state)
+			assert.NoError(t, err)
+			if result.Candidate == "" {
+				return result, step
+			}
+			assert.Positive(t, result.Steps)
+			if isBad(result.Candidate) {
+				state.Bad = result.Candidate
+			} else {
+				state.Good = append(state.Good, result.Candidate)
+			}
+		}
+	}
+
+	t.Run("All", func(t *testing.T) {
+		result, steps := bisect(&BisectState{Bad: history[0], Good: []string{history[6]}})
+		assert.Equal(t, firstBad, result.FirstBad)
+		assert.Empty(t, result.OnlySkipped)
+		assert.LessOrEqual(t, steps, 4)
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		result, steps := bisect(&BisectState{Bad: history[0], Good: []string{history[6]}, Path: "foo"})
+		assert.Equal(t, firstBad, result.FirstBad)
+		assert.LessOrEqual(t, steps, 3)
+	})
+
+	t.Run("Skipped", func(t *testing.T) {
+		result, err := bareRepo1.BisectNext(&BisectState{Bad: history[3], Good: []string{history[5]}, Skipped: []string{history[4]}})
+		assert.NoError(t, err)
+		assert.Empty(t, result.Candidate)
+		assert.Equal(t, history[3], result.FirstBad)
+		assert.Equal(t, []string{history[4]}, result.OnlySkipped)
+	})
+}
@@ -6,13 +6,30 @@ package git
 import (
 	"context"
 	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
 )
 
 // WriteCommitGraph write commit graph to speed up repo access
-// this requires git v2.18 to be installed
+// this requires git v2.18 to be installed, and v2.27 for the Bloom filters of the changed paths
+// which speed up finding the last commits of the files
 func WriteCommitGraph(ctx context.Context, repoPath string) error {
+	return writeCommitGraph(ctx, repoPath, setting.Git.CommitGraphChangedPaths)
+}
+
+// WriteCommitGraphWithChangedPaths writes the commit graph with the Bloom filters of the changed paths
+// whatever the COMMIT_GRAPH_CHANGED_PATHS setting, the filters are only written by git v2.27 and later
+func WriteCommitGraphWithChangedPaths(ctx context.Context, repoPath string) error {
+	return writeCommitGraph(ctx, repoPath, true)
+}
+
+func writeCommitGraph(ctx context.Context, repoPath string, changedPaths bool) error {
 	if DefaultFeatures().CheckVersionAtLeast("2.18") {
-		if _, _, err := NewCommand(ctx, "commit-graph", "write").RunStdString(&RunOpts{Dir: repoPath}); err != nil {
+		cmd := NewCommand(ctx, "commit-graph", "write")
+		if changedPaths && DefaultFeatures().CheckVersionAtLeast("2.27") {
+			cmd.AddArguments("--changed-paths")
+		}
+		if _, _, err := cmd.RunStdString(&RunOpts{Dir: repoPath}); err != nil {
 			return fmt.Errorf("unable to write commit-graph for '%s' : %w", repoPath, err)
 		}
 	}
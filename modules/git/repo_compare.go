@@ -233,6 +233,118 @@ func parseDiffStat(stdout string) (numFiles, totalAdditions, totalDeletions int,
 	return numFiles, totalAdditions, totalDeletions, err
 }
 
+// CompareFileStat represents the change of a file between two commits
+type CompareFileStat struct {
+	Name string
+	// OldName is the previous name of a renamed or copied file
+	OldName string
+	// Status is the status letter of git diff: A, C, D, M, R, T or U
+	Status    string
+	Additions int
+	Deletions int
+	IsBinary  bool
+}
+
+// CompareFileStatsOptions represents the options of GetCompareFileStats
+type CompareFileStatsOptions struct {
+	// Paths limits the diff to the given paths
+	Paths []string
+	// RenameThreshold is the similarity percentage for detecting renames, 0 uses the default of git, negative disables the detection
+	RenameThreshold int
+	// CopyThreshold is the similarity percentage for detecting copies, 0 disables the detection
+	CopyThreshold int
+}
+
+// GetCompareFileStats returns the files changed between two commits with the numbers of added and deleted lines
+func (repo *Repository) GetCompareFileStats(base, head string, opts CompareFileStatsOptions) ([]*CompareFileStat, error) {
+	cmd := NewCommand(repo.Ctx, "diff", "-z", "--raw", "--numstat")
+	if opts.RenameThreshold < 0 {
+		cmd.AddArguments("--no-renames")
+	} else if opts.RenameThreshold > 0 {
+		cmd.AddArguments(ToTrustedCmdArgs([]string{fmt.Sprintf("--find-renames=%d%%", min(opts.RenameThreshold, 100))})...)
+	} else {
+		cmd.AddArguments("--find-renames")
+	}
+	if opts.CopyThreshold > 0 {
+		cmd.AddArguments(ToTrustedCmdArgs([]string{fmt.Sprintf("--find-copies=%d%%", min(opts.CopyThreshold, 100))})...)
+	}
+	cmd.AddDynamicArguments(base, head).AddDashesAndList(opts.Paths...)
+
+	stdout, _, err := cmd.RunStdString(&RunOpts{Dir: repo.Path})
+	if err != nil {
+		return nil, err
+	}
+	return parseCompareFileStats(stdout)
+}
+
+// parseCompareFileStats parses the output of "git diff -z --raw --numstat",
+// all the raw records are written before the numstat records.
+func parseCompareFileStats(stdout string) ([]*CompareFileStat, error) {
+	fields := strings.Split(strings.TrimSuffix(stdout, "\x00"), "\x00")
+	if len(fields) == 1 && fields[0] == "" {
+		return []*CompareFileStat{}, nil
+	}
+
+	stats := make([]*CompareFileStat, 0, len(fields)/4)
+	statsByName := make(map[string]*CompareFileStat, len(fields)/4)
+	for i := 0; i < len(fields); i++ {
+		if strings.HasPrefix(fields[i], ":") {
+			// :100644 100644 bcd1234 0123456 R086\0old name\0new name\0
+			raw := strings.Fields(fields[i])
+			if len(raw) != 5 || i+1 >= len(fields) {
+				return nil, fmt.Errorf("unable to parse raw diff record: %q", fields[i])
+			}
+			stat := &CompareFileStat{Status: raw[4][:1], Name: fields[i+1]}
+			i++
+			if stat.Status == "R" || stat.Status == "C" {
+				if i+1 >= len(fields) {
+					return nil, fmt.Errorf("unable to parse raw diff record: %q", raw)
+				}
+				stat.OldName, stat.Name = stat.Name, fields[i+1]
+				i++
+			}
+			stats = append(stats, stat)
+			statsByName[stat.Name] = stat
+			continue
+		}
+
+		// 1\t2\tname\0 or 1\t2\t\0old name\0new name\0, binary files have - instead of numbers
+		numstat := strings.SplitN(fields[i], "\t", 3)
+		if len(numstat) != 3 {
+			return nil, fmt.Errorf("unable to parse numstat record: %q", fields[i])
+		}
+		name := numstat[2]
+		if name == "" {
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("unable to parse numstat record: %q", fields[i])
+			}
+			name = fields[i+2]
+			i += 2
+		}
+		stat, ok := statsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("unexpected numstat record for %q", name)
+		}
+		if numstat[0] == "-" && numstat[1] == "-" {
+			stat.IsBinary = true
+			continue
+		}
+		stat.Additions, _ = strconv.Atoi(numstat[0])
+		stat.Deletions, _ = strconv.Atoi(numstat[1])
+	}
+	return stats, nil
+}
+
+// GetCommitIDsByPaths returns the IDs of the commits of the revision range which change one of the paths
+func (repo *Repository) GetCommitIDsByPaths(revisionRange string, paths []string) ([]string, error) {
+	stdout, _, err := NewCommand(repo.Ctx, "rev-list").AddDynamicArguments(revisionRange).AddDashesAndList(paths...).
+		RunStdString(&RunOpts{Dir: repo.Path})
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(stdout), nil
+}
+
 // GetDiffOrPatch generates either diff or formatted patch data between given revisions
 func (repo *Repository) GetDiffOrPatch(base, head string, w io.Writer, patch, binary bool) error {
 	if patch {
@@ -161,3 +161,48 @@ func TestGetCommitFilesChanged(t *testing.T) {
 		assert.ElementsMatch(t, tc.files, changedFiles)
 	}
 }
+
+func TestParseCompareFileStats(t *testing.T) {
+	stdout := ":100644 100644 1111111 2222222 M\x00README.md\x00" +
+		":100644 100644 3333333 3333333 R100\x00old name.txt\x00new name.txt\x00" +
+		":000000 100644 0000000 4444444 A\x00logo.png\x00" +
+		"3\t1\tREADME.md\x00" +
+		"0\t0\t\x00old name.txt\x00new name.txt\x00" +
+		"-\t-\tlogo.png\x00"
+	stats, err := parseCompareFileStats(stdout)
+	assert.NoError(t, err)
+	assert.Equal(t, []*CompareFileStat{
+		{Name: "README.md", Status: "M", Additions: 3, Deletions: 1},
+		{Name: "new name.txt", OldName: "old name.txt", Status: "R"},
+		{Name: "logo.png", Status: "A", IsBinary: true},
+	}, stats)
+
+	stats, err = parseCompareFileStats("")
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	_, err = parseCompareFileStats("3\t1\tunknown.txt\x00")
+	assert.Error(t, err)
+}
+
+func TestGetCompareFileStats(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := openRepositoryWithDefaultContext(bareRepo1Path)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	stats, err := repo.GetCompareFileStats("95bb4d39648ee7e325106df01a621c530863a653", "8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2", CompareFileStatsOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, "file2.txt", stats[0].Name)
+		assert.Equal(t, "A", stats[0].Status)
+	}
+
+	stats, err = repo.GetCompareFileStats("95bb4d39648ee7e325106df01a621c530863a653", "8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2", CompareFileStatsOptions{Paths: []string{"file1.txt"}})
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	commitIDs, err := repo.GetCommitIDsByPaths("95bb4d39648ee7e325106df01a621c530863a653..8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2", []string{"file2.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2"}, commitIDs)
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// WriteMultiPackIndex writes the multi-pack-index of the packs of the repository to speed up the object lookups
+// of repositories with many packs, this requires git v2.21 to be installed
+func WriteMultiPackIndex(ctx context.Context, repoPath string) error {
+	if !DefaultFeatures().CheckVersionAtLeast("2.21") {
+		return nil
+	}
+
+	// git refuses to write the index when there are no packs, e.g. in repositories only having loose objects
+	packs, err := filepath.Glob(filepath.Join(repoPath, "objects", "pack", "*.pack"))
+	if err != nil {
+		return err
+	}
+	if len(packs) == 0 {
+		return nil
+	}
+
+	if _, _, err := NewCommand(ctx, "multi-pack-index", "write").RunStdString(&RunOpts{Dir: repoPath}); err != nil {
+		return fmt.Errorf("unable to write multi-pack-index for '%s' : %w", repoPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package gitgraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// DAGOptions represents the options to list a page of the commit DAG of a repository
+type DAGOptions struct {
+	Page       int
+	PageSize   int
+	HidePRRefs bool
+	// branches to list the commits of, all the refs are listed if empty
+	Branches []string
+}
+
+// DAGEdge represents the edge between a commit and one of its parents
+type DAGEdge struct {
+	Parent string
+	// Lane is the lane taken by the edge to reach the parent
+	Lane int
+}
+
+// DAGCommit represents a commit of the commit DAG placed on a lane
+type DAGCommit struct {
+	Rev         string
+	Lane        int
+	Parents     []DAGEdge
+	Refs        []git.Reference
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	Subject     string
+}
+
+// DAG represents a page of the commit DAG of a repository
+type DAG struct {
+	Commits []*DAGCommit
+	// Lanes is the number of lanes needed to render the page
+	Lanes int
+}
+
+// laneAllocator assigns the commits listed in topological order to lanes,
+// a lane holds the commit which is expected next on it.
+type laneAllocator struct {
+	lanes []string
+}
+
+func (a *laneAllocator) freeLane() int {
+	for i, rev := range a.lanes {
+		if rev == "" {
+			return i
+		}
+	}
+	a.lanes = append(a.lanes, "")
+	return len(a.lanes) - 1
+}
+
+// place assigns the lane of the commit and the lanes of the edges to its parents
+func (a *laneAllocator) place(c *DAGCommit) {
+	c.Lane = -1
+	for i, rev := range a.lanes {
+		if rev != c.Rev {
+			continue
+		}
+		if c.Lane < 0 {
+			c.Lane = i
+		} else {
+			// another child already expects the commit on this lane, they merge here
+			a.lanes[i] = ""
+		}
+	}
+	if c.Lane < 0 {
+		c.Lane = a.freeLane()
+	}
+	a.lanes[c.Lane] = ""
+
+	for i := range c.Parents {
+		parent := c.Parents[i].Parent
+		lane := -1
+		for j, rev := range a.lanes {
+			if rev == parent {
+				lane = j
+				break
+			}
+		}
+		if lane < 0 {
+			if i == 0 {
+				// the first parent continues on the lane of the commit
+				lane = c.Lane
+			} else {
+				lane = a.freeLane()
+			}
+			a.lanes[lane] = parent
+		}
+		c.Parents[i].Lane = lane
+	}
+}
+
+// ensureCommitGraph writes the commit-graph file of the repository if it is missing,
+// it allows git to read the parents of the commits without parsing them.
+func ensureCommitGraph(ctx context.Context, repoPath string) {
+	for _, name := range []string{"objects/info/commit-graph", "objects/info/commit-graphs"} {
+		if _, err := os.Stat(filepath.Join(repoPath, name)); err == nil {
+			return
+		}
+	}
+	if err := git.WriteCommitGraph(ctx, repoPath); err != nil {
+		log.Warn("Unable to write the commit-graph of %s: %v", repoPath, err)
+	}
+}
+
+// GetCommitDAG returns a page of the commit DAG of a repository in topological order,
+// the lanes are computed from the first commit so they are consistent across the pages.
+func GetCommitDAG(ctx context.Context, r *git.Repository, opts DAGOptions) (*DAG, error) {
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	ensureCommitGraph(ctx, r.Path)
+
+	// %x1f separates the fields, the subject is last as it is the only free form one
+	cmd := git.NewCommand(ctx, "log", "--topo-order", "--decorate=full", "--format=%H%x1f%P%x1f%D%x1f%an%x1f%ae%x1f%aI%x1f%s").
+		AddOptionFormat("-n %d", opts.Page*opts.PageSize)
+	if opts.HidePRRefs {
+		cmd.AddArguments("--exclude=" + git.PullPrefix + "*")
+	}
+	if len(opts.Branches) == 0 {
+		cmd.AddArguments("--all")
+	} else {
+		cmd.AddDynamicArguments(opts.Branches...)
+	}
+	cmd.AddArguments("--")
+
+	dag := &DAG{Commits: make([]*DAGCommit, 0, opts.PageSize)}
+	allocator := &laneAllocator{}
+	toSkip := (opts.Page - 1) * opts.PageSize
+
+	stderr := new(strings.Builder)
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Run(&git.RunOpts{
+		Dir:    r.Path,
+		Stdout: stdoutWriter,
+		Stderr: stderr,
+		PipelineFunc: func(ctx context.Context, cancel context.CancelFunc) error {
+			_ = stdoutWriter.Close()
+			defer stdoutReader.Close()
+
+			scanner := bufio.NewScanner(stdoutReader)
+			for scanner.Scan() {
+				c, err := parseDAGCommit(scanner.Bytes())
+				if err != nil {
+					cancel()
+					return err
+				}
+				allocator.place(c)
+				if toSkip > 0 {
+					toSkip--
+					continue
+				}
+				dag.Commits = append(dag.Commits, c)
+				dag.Lanes = max(dag.Lanes, c.Lane+1)
+				for _, edge := range c.Parents {
+					dag.Lanes = max(dag.Lanes, edge.Lane+1)
+				}
+			}
+			return scanner.Err()
+		},
+	})
+	if err != nil {
+		return nil, git.ConcatenateError(err, stderr.String())
+	}
+	return dag, nil
+}
+
+func parseDAGCommit(line []byte) (*DAGCommit, error) {
+	fields := bytes.SplitN(line, []byte{0x1f}, 7)
+	if len(fields) != 7 {
+		return nil, fmt.Errorf("malformed commit line: %q", line)
+	}
+	c := &DAGCommit{
+		Rev:         string(fields[0]),
+		Refs:        newRefsFromRefNames(fields[2]),
+		AuthorName:  string(fields[3]),
+		AuthorEmail: string(fields[4]),
+		Subject:     string(fields[6]),
+	}
+	for _, parent := range strings.Fields(string(fields[1])) {
+		c.Parents = append(c.Parents, DAGEdge{Parent: parent})
+	}
+	var err error
+	if c.AuthorDate, err = time.Parse(time.RFC3339, string(fields[5])); err != nil {
+		return nil, fmt.Errorf("malformed author date of %s: %w", c.Rev, err)
+	}
+	return c, nil
+}
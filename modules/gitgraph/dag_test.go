@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package gitgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaneAllocator(t *testing.T) {
+	newCommit := func(rev string, parents ...string) *DAGCommit {
+		c := &DAGCommit{Rev: rev}
+		for _, parent := range parents {
+			c.Parents = append(c.Parents, DAGEdge{Parent: parent})
+		}
+		return c
+	}
+
+	// f merges the branch d-c into e, which has been forked from a with b
+	commits := []*DAGCommit{
+		newCommit("f", "e", "d"),
+		newCommit("e", "b"),
+		newCommit("d", "c"),
+		newCommit("c", "a"),
+		newCommit("b", "a"),
+		newCommit("a"),
+	}
+	allocator := &laneAllocator{}
+	for _, c := range commits {
+		allocator.place(c)
+	}
+
+	lanes := map[string]int{}
+	for _, c := range commits {
+		lanes[c.Rev] = c.Lane
+	}
+	assert.Equal(t, map[string]int{"f": 0, "e": 0, "d": 1, "c": 1, "b": 0, "a": 1}, lanes)
+	assert.Equal(t, []DAGEdge{{Parent: "e", Lane: 0}, {Parent: "d", Lane: 1}}, commits[0].Parents)
+	assert.Equal(t, []DAGEdge{{Parent: "a", Lane: 1}}, commits[3].Parents)
+	// b joins the lane on which c already expects a
+	assert.Equal(t, []DAGEdge{{Parent: "a", Lane: 1}}, commits[4].Parents)
+	assert.Empty(t, commits[5].Parents)
+	// all the lanes have been freed
+	assert.Equal(t, []string{"", ""}, allocator.lanes)
+}
+
+func TestParseDAGCommit(t *testing.T) {
+	c, err := parseDAGCommit([]byte("2c54faec6c45d31c1abfaecdab471eac6633738a\x1f7c4df115542e05c700f297519e906fd63c9c9804 4e61bacab44e9b4730e44a6615d04098dd3a8eaf\x1fHEAD -> refs/heads/master, tag: refs/tags/v1.0\x1fUser Two\x1fuser2@example.com\x1f2024-01-02T03:04:05+01:00\x1fMerge branch 'a|b'"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2c54faec6c45d31c1abfaecdab471eac6633738a", c.Rev)
+	assert.Equal(t, []DAGEdge{{Parent: "7c4df115542e05c700f297519e906fd63c9c9804"}, {Parent: "4e61bacab44e9b4730e44a6615d04098dd3a8eaf"}}, c.Parents)
+	assert.Len(t, c.Refs, 2)
+	assert.Equal(t, "refs/heads/master", c.Refs[0].Name)
+	assert.Equal(t, "refs/tags/v1.0", c.Refs[1].Name)
+	assert.Equal(t, "User Two", c.AuthorName)
+	assert.Equal(t, "user2@example.com", c.AuthorEmail)
+	assert.EqualValues(t, 1704161045, c.AuthorDate.Unix())
+	assert.Equal(t, "Merge branch 'a|b'", c.Subject)
+
+	_, err = parseDAGCommit([]byte("2c54faec6c45d31c1abfaecdab471eac6633738a"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package gitrepo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/git"
+)
+
+// BlameHunk represents continuous lines of a file which were last changed by the same commit
+type BlameHunk struct {
+	Sha          string
+	PreviousSha  string
+	PreviousPath string
+	// StartLine and EndLine are the 1-based inclusive line range of the hunk in the blamed file
+	StartLine int
+	EndLine   int
+	Lines     []string
+}
+
+// BlameReader streams the blame of a file hunk by hunk
+type BlameReader struct {
+	reader   *git.BlameReader
+	nextLine int
+	previous map[string]*git.BlamePart
+}
+
+// CreateBlameReader creates a BlameReader of the file at the given commit of the repository,
+// the revisions listed in .git-blame-ignore-revs are ignored unless bypassBlameIgnore is set
+func CreateBlameReader(ctx context.Context, repo Repository, objectFormat git.ObjectFormat, commit *git.Commit, file string, bypassBlameIgnore bool) (*BlameReader, error) {
+	reader, err := git.CreateBlameReader(ctx, objectFormat, repoPath(repo), commit, file, bypassBlameIgnore)
+	if err != nil {
+		return nil, err
+	}
+	return &BlameReader{
+		reader:   reader,
+		nextLine: 1,
+		previous: make(map[string]*git.BlamePart),
+	}, nil
+}
+
+// UsesIgnoreRevs returns whether the revisions of .git-blame-ignore-revs are ignored
+func (r *BlameReader) UsesIgnoreRevs() bool {
+	return r.reader.UsesIgnoreRevs()
+}
+
+// NextHunk returns the next hunk of the blame, it returns nil when all the hunks have been read
+func (r *BlameReader) NextHunk() (*BlameHunk, error) {
+	part, err := r.reader.NextPart()
+	if err != nil || part == nil {
+		return nil, err
+	}
+
+	// git only reports the previous commit the first time a commit appears
+	if prev, ok := r.previous[part.Sha]; ok {
+		if part.PreviousSha == "" {
+			part.PreviousSha = prev.PreviousSha
+			part.PreviousPath = prev.PreviousPath
+		}
+	} else {
+		r.previous[part.Sha] = part
+	}
+
+	hunk := &BlameHunk{
+		Sha:          part.Sha,
+		PreviousSha:  part.PreviousSha,
+		PreviousPath: part.PreviousPath,
+		StartLine:    r.nextLine,
+		EndLine:      r.nextLine + len(part.Lines) - 1,
+		Lines:        part.Lines,
+	}
+	r.nextLine += len(part.Lines)
+	return hunk, nil
+}
+
+// Close closes the BlameReader, it returns the error of the git command if it failed
+func (r *BlameReader) Close() error {
+	return r.reader.Close()
+}
@@ -100,7 +100,7 @@ func (r *Request) Param(key, value string) *Request {
 }
 
 // Body adds request raw body.
-// it supports string and []byte.
+// it supports string, []byte and io.Reader, a reader is streamed and its length must be set with ContentLength.
 func (r *Request) Body(data any) *Request {
 	switch t := data.(type) {
 	case string:
@@ -111,10 +111,18 @@ func (r *Request) Body(data any) *Request {
 		bf := bytes.NewBuffer(t)
 		r.req.Body = io.NopCloser(bf)
 		r.req.ContentLength = int64(len(t))
+	case io.Reader:
+		r.req.Body = io.NopCloser(t)
 	}
 	return r
 }
 
+// ContentLength sets the length of the request body.
+func (r *Request) ContentLength(length int64) *Request {
+	r.req.ContentLength = length
+	return r
+}
+
 func (r *Request) getResponse() (*http.Response, error) {
 	if r.resp.StatusCode != 0 {
 		return r.resp, nil
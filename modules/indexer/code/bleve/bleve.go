@@ -113,6 +113,7 @@ var _ internal.Indexer = &Indexer{}
 type Indexer struct {
 	inner                    *inner_bleve.Indexer
 	indexer_internal.Indexer // do not composite inner_bleve.Indexer directly to avoid exposing too much
+	isWiki                   bool
 }
 
 // NewIndexer creates a new bleve local indexer
@@ -124,11 +125,26 @@ func NewIndexer(indexDir string) *Indexer {
 	}
 }
 
+// NewWikiIndexer creates a new bleve local indexer for the pages of the repository wikis
+func NewWikiIndexer(indexDir string) *Indexer {
+	indexer := NewIndexer(indexDir)
+	indexer.isWiki = true
+	return indexer
+}
+
+// repoPath returns the path of the git repository the indexed files are read from
+func (b *Indexer) repoPath(repo *repo_model.Repository) string {
+	if b.isWiki {
+		return repo.WikiPath()
+	}
+	return repo.RepoPath()
+}
+
 func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserError, batchReader *bufio.Reader, commitSha string,
 	update internal.FileUpdate, repo *repo_model.Repository, batch *inner_bleve.FlushingBatch,
 ) error {
 	// Ignore vendored files in code search
-	if setting.Indexer.ExcludeVendored && analyze.IsVendor(update.Filename) {
+	if !b.isWiki && setting.Indexer.ExcludeVendored && analyze.IsVendor(update.Filename) {
 		return nil
 	}
 
@@ -137,7 +153,7 @@ func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserErro
 	var err error
 	if !update.Sized {
 		var stdout string
-		stdout, _, err = git.NewCommand(ctx, "cat-file", "-s").AddDynamicArguments(update.BlobSha).RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+		stdout, _, err = git.NewCommand(ctx, "cat-file", "-s").AddDynamicArguments(update.BlobSha).RunStdString(&git.RunOpts{Dir: b.repoPath(repo)})
 		if err != nil {
 			return err
 		}
@@ -190,12 +206,12 @@ func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha st
 	batch := inner_bleve.NewFlushingBatch(b.inner.Indexer, maxBatchSize)
 	if len(changes.Updates) > 0 {
 		// Now because of some insanity with git cat-file not immediately failing if not run in a valid git directory we need to run git rev-parse first!
-		if err := git.EnsureValidGitRepository(ctx, repo.RepoPath()); err != nil {
-			log.Error("Unable to open git repo: %s for %-v: %v", repo.RepoPath(), repo, err)
+		if err := git.EnsureValidGitRepository(ctx, b.repoPath(repo)); err != nil {
+			log.Error("Unable to open git repo: %s for %-v: %v", b.repoPath(repo), repo, err)
 			return err
 		}
 
-		batchWriter, batchReader, cancel := git.CatFileBatch(ctx, repo.RepoPath())
+		batchWriter, batchReader, cancel := git.CatFileBatch(ctx, b.repoPath(repo))
 		defer cancel()
 
 		for _, update := range changes.Updates {
@@ -42,6 +42,7 @@ var _ internal.Indexer = &Indexer{}
 type Indexer struct {
 	inner                    *inner_elasticsearch.Indexer
 	indexer_internal.Indexer // do not composite inner_elasticsearch.Indexer directly to avoid exposing too much
+	isWiki                   bool
 }
 
 // NewIndexer creates a new elasticsearch indexer
@@ -54,6 +55,21 @@ func NewIndexer(url, indexerName string) *Indexer {
 	return indexer
 }
 
+// NewWikiIndexer creates a new elasticsearch indexer for the pages of the repository wikis
+func NewWikiIndexer(url, indexerName string) *Indexer {
+	indexer := NewIndexer(url, indexerName)
+	indexer.isWiki = true
+	return indexer
+}
+
+// repoPath returns the path of the git repository the indexed files are read from
+func (b *Indexer) repoPath(repo *repo_model.Repository) string {
+	if b.isWiki {
+		return repo.WikiPath()
+	}
+	return repo.RepoPath()
+}
+
 const (
 	defaultMapping = `{
 		"mappings": {
@@ -86,7 +102,7 @@ const (
 
 func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserError, batchReader *bufio.Reader, sha string, update internal.FileUpdate, repo *repo_model.Repository) ([]elastic.BulkableRequest, error) {
 	// Ignore vendored files in code search
-	if setting.Indexer.ExcludeVendored && analyze.IsVendor(update.Filename) {
+	if !b.isWiki && setting.Indexer.ExcludeVendored && analyze.IsVendor(update.Filename) {
 		return nil, nil
 	}
 
@@ -94,7 +110,7 @@ func (b *Indexer) addUpdate(ctx context.Context, batchWriter git.WriteCloserErro
 	var err error
 	if !update.Sized {
 		var stdout string
-		stdout, _, err = git.NewCommand(ctx, "cat-file", "-s").AddDynamicArguments(update.BlobSha).RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+		stdout, _, err = git.NewCommand(ctx, "cat-file", "-s").AddDynamicArguments(update.BlobSha).RunStdString(&git.RunOpts{Dir: b.repoPath(repo)})
 		if err != nil {
 			return nil, err
 		}
@@ -155,12 +171,12 @@ func (b *Indexer) Index(ctx context.Context, repo *repo_model.Repository, sha st
 	reqs := make([]elastic.BulkableRequest, 0)
 	if len(changes.Updates) > 0 {
 		// Now because of some insanity with git cat-file not immediately failing if not run in a valid git directory we need to run git rev-parse first!
-		if err := git.EnsureValidGitRepository(ctx, repo.RepoPath()); err != nil {
-			log.Error("Unable to open git repo: %s for %-v: %v", repo.RepoPath(), repo, err)
+		if err := git.EnsureValidGitRepository(ctx, b.repoPath(repo)); err != nil {
+			log.Error("Unable to open git repo: %s for %-v: %v", b.repoPath(repo), repo, err)
 			return err
 		}
 
-		batchWriter, batchReader, cancel := git.CatFileBatch(ctx, repo.RepoPath())
+		batchWriter, batchReader, cancel := git.CatFileBatch(ctx, b.repoPath(repo))
 		defer cancel()
 
 		for _, update := range changes.Updates {
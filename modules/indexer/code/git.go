@@ -15,8 +15,20 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 )
 
-func getDefaultBranchSha(ctx context.Context, repo *repo_model.Repository) (string, error) {
-	stdout, _, err := git.NewCommand(ctx, "show-ref", "-s").AddDynamicArguments(git.BranchPrefix + repo.DefaultBranch).RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+// indexedRepoPath returns the path of the git repository indexed by the given indexer type
+func indexedRepoPath(repo *repo_model.Repository, indexerType repo_model.RepoIndexerType) string {
+	if indexerType == repo_model.RepoIndexerTypeWiki {
+		return repo.WikiPath()
+	}
+	return repo.RepoPath()
+}
+
+func getDefaultBranchSha(ctx context.Context, repo *repo_model.Repository, indexerType repo_model.RepoIndexerType) (string, error) {
+	branch := repo.DefaultBranch
+	if indexerType == repo_model.RepoIndexerTypeWiki {
+		branch = repo.DefaultWikiBranch
+	}
+	stdout, _, err := git.NewCommand(ctx, "show-ref", "-s").AddDynamicArguments(git.BranchPrefix + branch).RunStdString(&git.RunOpts{Dir: indexedRepoPath(repo, indexerType)})
 	if err != nil {
 		return "", err
 	}
@@ -24,8 +36,8 @@ func getDefaultBranchSha(ctx context.Context, repo *repo_model.Repository) (stri
 }
 
 // getRepoChanges returns changes to repo since last indexer update
-func getRepoChanges(ctx context.Context, repo *repo_model.Repository, revision string) (*internal.RepoChanges, error) {
-	status, err := repo_model.GetIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCode)
+func getRepoChanges(ctx context.Context, indexer internal.Indexer, repo *repo_model.Repository, indexerType repo_model.RepoIndexerType, revision string) (*internal.RepoChanges, error) {
+	status, err := repo_model.GetIndexerStatus(ctx, repo, indexerType)
 	if err != nil {
 		return nil, err
 	}
@@ -33,21 +45,25 @@ func getRepoChanges(ctx context.Context, repo *repo_model.Repository, revision s
 	needGenesis := len(status.CommitSha) == 0
 	if !needGenesis {
 		hasAncestorCmd := git.NewCommand(ctx, "merge-base").AddDynamicArguments(status.CommitSha, revision)
-		stdout, _, _ := hasAncestorCmd.RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+		stdout, _, _ := hasAncestorCmd.RunStdString(&git.RunOpts{Dir: indexedRepoPath(repo, indexerType)})
 		needGenesis = len(stdout) == 0
 	}
 
 	if needGenesis {
-		return genesisChanges(ctx, repo, revision)
+		return genesisChanges(ctx, repo, indexerType, revision)
 	}
-	return nonGenesisChanges(ctx, repo, revision)
+	return nonGenesisChanges(ctx, indexer, repo, indexerType, status.CommitSha, revision)
 }
 
-func isIndexable(entry *git.TreeEntry) bool {
+func isIndexable(entry *git.TreeEntry, indexerType repo_model.RepoIndexerType) bool {
 	if !entry.IsRegular() && !entry.IsExecutable() {
 		return false
 	}
 	name := strings.ToLower(entry.Name())
+	if indexerType == repo_model.RepoIndexerTypeWiki {
+		// only the pages of a wiki are indexed, not its attachments
+		return strings.HasSuffix(name, ".md")
+	}
 	for _, g := range setting.Indexer.ExcludePatterns {
 		if g.Match(name) {
 			return false
@@ -62,7 +78,7 @@ func isIndexable(entry *git.TreeEntry) bool {
 }
 
 // parseGitLsTreeOutput parses the output of a `git ls-tree -r --full-name` command
-func parseGitLsTreeOutput(stdout []byte) ([]internal.FileUpdate, error) {
+func parseGitLsTreeOutput(stdout []byte, indexerType repo_model.RepoIndexerType) ([]internal.FileUpdate, error) {
 	entries, err := git.ParseTreeEntries(stdout)
 	if err != nil {
 		return nil, err
@@ -70,7 +86,7 @@ func parseGitLsTreeOutput(stdout []byte) ([]internal.FileUpdate, error) {
 	idxCount := 0
 	updates := make([]internal.FileUpdate, len(entries))
 	for _, entry := range entries {
-		if isIndexable(entry) {
+		if isIndexable(entry, indexerType) {
 			updates[idxCount] = internal.FileUpdate{
 				Filename: entry.Name(),
 				BlobSha:  entry.ID.String(),
@@ -84,30 +100,30 @@ func parseGitLsTreeOutput(stdout []byte) ([]internal.FileUpdate, error) {
 }
 
 // genesisChanges get changes to add repo to the indexer for the first time
-func genesisChanges(ctx context.Context, repo *repo_model.Repository, revision string) (*internal.RepoChanges, error) {
+func genesisChanges(ctx context.Context, repo *repo_model.Repository, indexerType repo_model.RepoIndexerType, revision string) (*internal.RepoChanges, error) {
 	var changes internal.RepoChanges
-	stdout, _, runErr := git.NewCommand(ctx, "ls-tree", "--full-tree", "-l", "-r").AddDynamicArguments(revision).RunStdBytes(&git.RunOpts{Dir: repo.RepoPath()})
+	stdout, _, runErr := git.NewCommand(ctx, "ls-tree", "--full-tree", "-l", "-r").AddDynamicArguments(revision).RunStdBytes(&git.RunOpts{Dir: indexedRepoPath(repo, indexerType)})
 	if runErr != nil {
 		return nil, runErr
 	}
 
 	var err error
-	changes.Updates, err = parseGitLsTreeOutput(stdout)
+	changes.Updates, err = parseGitLsTreeOutput(stdout, indexerType)
 	return &changes, err
 }
 
 // nonGenesisChanges get changes since the previous indexer update
-func nonGenesisChanges(ctx context.Context, repo *repo_model.Repository, revision string) (*internal.RepoChanges, error) {
-	diffCmd := git.NewCommand(ctx, "diff", "--name-status").AddDynamicArguments(repo.CodeIndexerStatus.CommitSha, revision)
-	stdout, _, runErr := diffCmd.RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+func nonGenesisChanges(ctx context.Context, indexer internal.Indexer, repo *repo_model.Repository, indexerType repo_model.RepoIndexerType, indexedSha, revision string) (*internal.RepoChanges, error) {
+	diffCmd := git.NewCommand(ctx, "diff", "--name-status").AddDynamicArguments(indexedSha, revision)
+	stdout, _, runErr := diffCmd.RunStdString(&git.RunOpts{Dir: indexedRepoPath(repo, indexerType)})
 	if runErr != nil {
 		// previous commit sha may have been removed by a force push, so
 		// try rebuilding from scratch
 		log.Warn("git diff: %v", runErr)
-		if err := (*globalIndexer.Load()).Delete(ctx, repo.ID); err != nil {
+		if err := indexer.Delete(ctx, repo.ID); err != nil {
 			return nil, err
 		}
-		return genesisChanges(ctx, repo, revision)
+		return genesisChanges(ctx, repo, indexerType, revision)
 	}
 
 	var changes internal.RepoChanges
@@ -165,11 +181,11 @@ func nonGenesisChanges(ctx context.Context, repo *repo_model.Repository, revisio
 
 	cmd := git.NewCommand(ctx, "ls-tree", "--full-tree", "-l").AddDynamicArguments(revision).
 		AddDashesAndList(updatedFilenames...)
-	lsTreeStdout, _, err := cmd.RunStdBytes(&git.RunOpts{Dir: repo.RepoPath()})
+	lsTreeStdout, _, err := cmd.RunStdBytes(&git.RunOpts{Dir: indexedRepoPath(repo, indexerType)})
 	if err != nil {
 		return nil, err
 	}
 
-	changes.Updates, err = parseGitLsTreeOutput(lsTreeStdout)
+	changes.Updates, err = parseGitLsTreeOutput(lsTreeStdout, indexerType)
 	return &changes, err
 }
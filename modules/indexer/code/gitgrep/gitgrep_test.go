@@ -1,7 +1,7 @@
 // Copyright 2024 The Gitea Authors. All rights reserved.
 // SPDX-License-Identifier: MIT
 
-package repo
+package gitgrep
 
 import (
 	"testing"
@@ -15,5 +15,5 @@ import (
 func TestIndexSettingToGitGrepPathspecList(t *testing.T) {
 	defer test.MockVariableValue(&setting.Indexer.IncludePatterns, setting.IndexerGlobFromString("a"))()
 	defer test.MockVariableValue(&setting.Indexer.ExcludePatterns, setting.IndexerGlobFromString("b"))()
-	assert.Equal(t, []string{":(glob)a", ":(glob,exclude)b"}, indexSettingToGitGrepPathspecList())
+	assert.Equal(t, []string{":(glob)a", ":(glob,exclude)b"}, IndexSettingToGitGrepPathspecList())
 }
@@ -36,6 +36,7 @@ func init() {
 	i := internal.NewDummyIndexer()
 	dummyIndexer = &i
 	globalIndexer.Store(dummyIndexer)
+	globalWikiIndexer.Store(dummyIndexer)
 }
 
 func index(ctx context.Context, indexer internal.Indexer, repoID int64) error {
@@ -73,11 +74,11 @@ func index(ctx context.Context, indexer internal.Indexer, repoID int64) error {
 		return nil
 	}
 
-	sha, err := getDefaultBranchSha(ctx, repo)
+	sha, err := getDefaultBranchSha(ctx, repo, repo_model.RepoIndexerTypeCode)
 	if err != nil {
 		return err
 	}
-	changes, err := getRepoChanges(ctx, repo, sha)
+	changes, err := getRepoChanges(ctx, indexer, repo, repo_model.RepoIndexerTypeCode, sha)
 	if err != nil {
 		return err
 	} else if changes == nil {
@@ -98,6 +99,10 @@ func Init() {
 		return
 	}
 
+	if setting.Indexer.WikiIndexerEnabled {
+		initWikiIndexer()
+	}
+
 	ctx, cancel, finished := process.GetManager().AddTypedContext(context.Background(), "Service: CodeIndexer", process.SystemProcessType, false)
 
 	graceful.GetManager().RunAtTerminate(func() {
@@ -267,8 +272,14 @@ func populateRepoIndexer(ctx context.Context) {
 		log.Fatal("System error: %v", err)
 	}
 
-	var maxRepoID int64
-	if maxRepoID, err = db.GetMaxID("repository"); err != nil {
+	pushUnindexedRepos(ctx, indexerQueue, repo_model.RepoIndexerTypeCode)
+	log.Info("Done (re)populating the repo indexer with existing repositories")
+}
+
+// pushUnindexedRepos pushes all the repositories without a status for the indexer type to the indexer queue
+func pushUnindexedRepos(ctx context.Context, q *queue.WorkerPoolQueue[*internal.IndexerData], indexerType repo_model.RepoIndexerType) {
+	maxRepoID, err := db.GetMaxID("repository")
+	if err != nil {
 		log.Fatal("System error: %v", err)
 	}
 
@@ -282,7 +293,7 @@ func populateRepoIndexer(ctx context.Context) {
 			return
 		default:
 		}
-		ids, err := repo_model.GetUnindexedRepos(ctx, repo_model.RepoIndexerTypeCode, maxRepoID, 0, 50)
+		ids, err := repo_model.GetUnindexedRepos(ctx, indexerType, maxRepoID, 0, 50)
 		if err != nil {
 			log.Error("populateRepoIndexer: %v", err)
 			return
@@ -296,12 +307,11 @@ func populateRepoIndexer(ctx context.Context) {
 				return
 			default:
 			}
-			if err := indexerQueue.Push(&internal.IndexerData{RepoID: id}); err != nil {
+			if err := q.Push(&internal.IndexerData{RepoID: id}); err != nil {
 				log.Error("indexerQueue.Push: %v", err)
 				return
 			}
 			maxRepoID = id - 1
 		}
 	}
-	log.Info("Done (re)populating the repo indexer with existing repositories")
 }
@@ -136,3 +136,37 @@ func TestESIndexAndSearch(t *testing.T) {
 
 	testIndexer("elastic_search", t, indexer)
 }
+
+func TestBleveWikiIndexAndSearch(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	idx := bleve.NewWikiIndexer(t.TempDir())
+	_, err := idx.Init(context.Background())
+	if err != nil {
+		idx.Close()
+		assert.FailNow(t, "Unable to create bleve wiki indexer Error: %v", err)
+	}
+	defer idx.Close()
+
+	assert.NoError(t, indexWiki(git.DefaultContext, idx, 1))
+
+	total, res, _, err := idx.Search(context.TODO(), &internal.SearchOptions{
+		Keyword:   "spaces",
+		Paginator: &db.ListOptions{Page: 1, PageSize: 10},
+	})
+	assert.NoError(t, err)
+	if assert.EqualValues(t, 1, total) {
+		assert.EqualValues(t, 1, res[0].RepoID)
+		assert.Equal(t, "Page-With-Spaced-Name.md", res[0].Filename)
+	}
+
+	// the code of the repository is not indexed with its wiki
+	total, _, _, err = idx.Search(context.TODO(), &internal.SearchOptions{
+		Keyword:   "Description",
+		Paginator: &db.ListOptions{Page: 1, PageSize: 10},
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, total)
+
+	assert.NoError(t, idx.Delete(context.Background(), 1))
+}
@@ -28,6 +28,7 @@ type Result struct {
 type ResultLine struct {
 	Num              int
 	FormattedContent template.HTML
+	RawContent       string
 }
 
 type SearchResultLanguages = internal.SearchResultLanguages
@@ -74,6 +75,7 @@ func HighlightSearchResultCode(filename, language string, lineNums []int, code s
 	// we should highlight the whole code block first, otherwise it doesn't work well with multiple line highlighting
 	hl, _ := highlight.Code(filename, language, code)
 	highlightedLines := strings.Split(string(hl), "\n")
+	rawLines := strings.Split(code, "\n")
 
 	// The lineNums outputted by highlight.Code might not match the original lineNums, because "highlight" removes the last `\n`
 	lines := make([]*ResultLine, min(len(highlightedLines), len(lineNums)))
@@ -82,6 +84,9 @@ func HighlightSearchResultCode(filename, language string, lineNums []int, code s
 			Num:              lineNums[i],
 			FormattedContent: template.HTML(highlightedLines[i]),
 		}
+		if i < len(rawLines) {
+			lines[i].RawContent = rawLines[i]
+		}
 	}
 	return lines
 }
@@ -131,11 +136,15 @@ func searchResult(result *internal.SearchResult, startIndex, endIndex int) (*Res
 // PerformSearch perform a search on a repository
 // if isFuzzy is true set the Damerau-Levenshtein distance from 0 to 2
 func PerformSearch(ctx context.Context, opts *SearchOptions) (int, []*Result, []*SearchResultLanguages, error) {
+	return performSearch(ctx, *globalIndexer.Load(), opts)
+}
+
+func performSearch(ctx context.Context, indexer internal.Indexer, opts *SearchOptions) (int, []*Result, []*SearchResultLanguages, error) {
 	if opts == nil || len(opts.Keyword) == 0 {
 		return 0, nil, nil, nil
 	}
 
-	total, results, resultLanguages, err := (*globalIndexer.Load()).Search(ctx, opts)
+	total, results, resultLanguages, err := indexer.Search(ctx, opts)
 	if err != nil {
 		return 0, nil, nil, err
 	}
@@ -0,0 +1,158 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package code
+
+import (
+	"context"
+	"os"
+	"runtime/pprof"
+	"sync/atomic"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/indexer/code/bleve"
+	"code.gitea.io/gitea/modules/indexer/code/elasticsearch"
+	"code.gitea.io/gitea/modules/indexer/code/internal"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var (
+	wikiIndexerQueue *queue.WorkerPoolQueue[*internal.IndexerData]
+	// globalWikiIndexer is the global wiki indexer, like globalIndexer it cannot be nil.
+	globalWikiIndexer atomic.Pointer[internal.Indexer]
+)
+
+func indexWiki(ctx context.Context, indexer internal.Indexer, repoID int64) error {
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if repo_model.IsErrRepoNotExist(err) {
+		return indexer.Delete(ctx, repoID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !repo.HasWiki() || !git.IsBranchExist(ctx, repo.WikiPath(), repo.DefaultWikiBranch) {
+		return indexer.Delete(ctx, repoID)
+	}
+
+	sha, err := getDefaultBranchSha(ctx, repo, repo_model.RepoIndexerTypeWiki)
+	if err != nil {
+		return err
+	}
+	changes, err := getRepoChanges(ctx, indexer, repo, repo_model.RepoIndexerTypeWiki, sha)
+	if err != nil {
+		return err
+	} else if changes == nil {
+		return nil
+	}
+
+	if err := indexer.Index(ctx, repo, sha, changes); err != nil {
+		return err
+	}
+
+	return repo_model.UpdateIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeWiki, sha)
+}
+
+// initWikiIndexer initializes the wiki indexer, it is of the same type as the repo indexer
+func initWikiIndexer() {
+	ctx, cancel, finished := process.GetManager().AddTypedContext(context.Background(), "Service: WikiIndexer", process.SystemProcessType, false)
+
+	graceful.GetManager().RunAtTerminate(func() {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		cancel()
+		log.Debug("Closing wiki indexer")
+		(*globalWikiIndexer.Load()).Close()
+		log.Info("PID: %d Wiki Indexer closed", os.Getpid())
+		finished()
+	})
+
+	handler := func(items ...*internal.IndexerData) (unhandled []*internal.IndexerData) {
+		indexer := *globalWikiIndexer.Load()
+		for _, indexerData := range items {
+			log.Trace("IndexerData Process Wiki: %d", indexerData.RepoID)
+			if err := indexWiki(ctx, indexer, indexerData.RepoID); err != nil {
+				unhandled = append(unhandled, indexerData)
+				if !setting.IsInTesting {
+					log.Error("Wikis indexer handler: index error for repo %v: %v", indexerData.RepoID, err)
+				}
+			}
+		}
+		return unhandled
+	}
+
+	wikiIndexerQueue = queue.CreateUniqueQueue(ctx, "wiki_indexer", handler)
+	if wikiIndexerQueue == nil {
+		log.Fatal("Unable to create wikis indexer queue")
+	}
+
+	go func() {
+		pprof.SetGoroutineLabels(ctx)
+		var wIndexer internal.Indexer
+		switch setting.Indexer.RepoType {
+		case "bleve":
+			log.Info("PID: %d Initializing Wiki Indexer at: %s", os.Getpid(), setting.Indexer.WikiPath)
+			wIndexer = bleve.NewWikiIndexer(setting.Indexer.WikiPath)
+		case "elasticsearch":
+			log.Info("PID: %d Initializing Wiki Indexer at: %s", os.Getpid(), setting.Indexer.RepoConnStr)
+			wIndexer = elasticsearch.NewWikiIndexer(setting.Indexer.RepoConnStr, setting.Indexer.WikiIndexerName)
+		default:
+			log.Fatal("PID: %d Unknown Indexer type: %s", os.Getpid(), setting.Indexer.RepoType)
+		}
+
+		existed, err := wIndexer.Init(ctx)
+		if err != nil {
+			cancel()
+			wIndexer.Close()
+			log.Fatal("PID: %d Unable to initialize the Wiki Indexer: %v", os.Getpid(), err)
+		}
+
+		globalWikiIndexer.Store(&wIndexer)
+
+		// Start processing the queue
+		go graceful.GetManager().RunWithCancel(wikiIndexerQueue)
+
+		if !existed { // populate the index because it's created for the first time
+			go graceful.GetManager().RunWithShutdownContext(populateWikiIndexer)
+		}
+	}()
+}
+
+// UpdateWikiIndexer update the entries of a repository wiki in the indexer
+func UpdateWikiIndexer(repo *repo_model.Repository) {
+	indexData := &internal.IndexerData{RepoID: repo.ID}
+	if err := wikiIndexerQueue.Push(indexData); err != nil {
+		log.Error("Update wiki index data %v failed: %v", indexData, err)
+	}
+}
+
+// IsWikiAvailable checks if wiki indexer is available
+func IsWikiAvailable(ctx context.Context) bool {
+	return (*globalWikiIndexer.Load()).Ping(ctx) == nil
+}
+
+// PerformWikiSearch perform a search on the pages of repository wikis
+func PerformWikiSearch(ctx context.Context, opts *SearchOptions) (int, []*Result, []*SearchResultLanguages, error) {
+	return performSearch(ctx, *globalWikiIndexer.Load(), opts)
+}
+
+// populateWikiIndexer populate the wiki indexer with pre-existing data. This
+// should only be run when the indexer is created for the first time.
+func populateWikiIndexer(ctx context.Context) {
+	log.Info("Populating the wiki indexer with existing repositories")
+
+	if err := repo_model.DeleteIndexerStatuses(ctx, repo_model.RepoIndexerTypeWiki); err != nil {
+		log.Fatal("System error: %v", err)
+	}
+
+	pushUnindexedRepos(ctx, wikiIndexerQueue, repo_model.RepoIndexerTypeWiki)
+	log.Info("Done (re)populating the wiki indexer with existing repositories")
+}
@@ -28,6 +28,15 @@ func MatchPhraseQuery(matchPhrase, field, analyzer string, fuzziness int) *query
 	return q
 }
 
+// MatchQuery generates a match query for the given text, field and analyzer, it matches any of the terms of the text
+func MatchQuery(text, field, analyzer string, fuzziness int) *query.MatchQuery {
+	q := bleve.NewMatchQuery(text)
+	q.FieldVal = field
+	q.Analyzer = analyzer
+	q.Fuzziness = fuzziness
+	return q
+}
+
 // BoolFieldQuery generates a bool field query for the given value and field
 func BoolFieldQuery(value bool, field string) *query.BoolFieldQuery {
 	q := bleve.NewBoolFieldQuery(value)
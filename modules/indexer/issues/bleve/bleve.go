@@ -23,7 +23,7 @@ import (
 const (
 	issueIndexerAnalyzer      = "issueIndexer"
 	issueIndexerDocType       = "issueIndexerDocType"
-	issueIndexerLatestVersion = 4
+	issueIndexerLatestVersion = 7
 )
 
 const unicodeNormalizeName = "unicodeNormalize"
@@ -78,6 +78,8 @@ func generateIssueIndexMapping() (mapping.IndexMapping, error) {
 	docMapping.AddFieldMappingsAt("label_ids", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("no_label", boolFieldMapping)
 	docMapping.AddFieldMappingsAt("milestone_id", numberFieldMapping)
+	docMapping.AddFieldMappingsAt("type_id", numberFieldMapping)
+	docMapping.AddFieldMappingsAt("field_value_keys", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("project_id", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("project_board_id", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("poster_id", numberFieldMapping)
@@ -91,6 +93,7 @@ func generateIssueIndexMapping() (mapping.IndexMapping, error) {
 	docMapping.AddFieldMappingsAt("created_unix", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("deadline_unix", numberFieldMapping)
 	docMapping.AddFieldMappingsAt("comment_count", numberFieldMapping)
+	docMapping.AddFieldMappingsAt("vote_count", numberFieldMapping)
 
 	if err := addUnicodeNormalizeTokenFilter(mapping); err != nil {
 		return nil, err
@@ -161,11 +164,19 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 			fuzziness = inner_bleve.GuessFuzzinessByKeyword(options.Keyword)
 		}
 
-		queries = append(queries, bleve.NewDisjunctionQuery([]query.Query{
-			inner_bleve.MatchPhraseQuery(options.Keyword, "title", issueIndexerAnalyzer, fuzziness),
-			inner_bleve.MatchPhraseQuery(options.Keyword, "content", issueIndexerAnalyzer, fuzziness),
-			inner_bleve.MatchPhraseQuery(options.Keyword, "comments", issueIndexerAnalyzer, fuzziness),
-		}...))
+		if options.IsSimilarKeyword {
+			queries = append(queries, bleve.NewDisjunctionQuery([]query.Query{
+				inner_bleve.MatchQuery(options.Keyword, "title", issueIndexerAnalyzer, fuzziness),
+				inner_bleve.MatchQuery(options.Keyword, "content", issueIndexerAnalyzer, fuzziness),
+				inner_bleve.MatchQuery(options.Keyword, "comments", issueIndexerAnalyzer, fuzziness),
+			}...))
+		} else {
+			queries = append(queries, bleve.NewDisjunctionQuery([]query.Query{
+				inner_bleve.MatchPhraseQuery(options.Keyword, "title", issueIndexerAnalyzer, fuzziness),
+				inner_bleve.MatchPhraseQuery(options.Keyword, "content", issueIndexerAnalyzer, fuzziness),
+				inner_bleve.MatchPhraseQuery(options.Keyword, "comments", issueIndexerAnalyzer, fuzziness),
+			}...))
+		}
 	}
 
 	if len(options.RepoIDs) > 0 || options.AllPublic {
@@ -221,6 +232,18 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 		queries = append(queries, bleve.NewDisjunctionQuery(milestoneQueries...))
 	}
 
+	if len(options.TypeIDs) > 0 {
+		var typeQueries []query.Query
+		for _, typeID := range options.TypeIDs {
+			typeQueries = append(typeQueries, inner_bleve.NumericEqualityQuery(typeID, "type_id"))
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(typeQueries...))
+	}
+
+	for fieldID, value := range options.FieldValues {
+		queries = append(queries, inner_bleve.NumericEqualityQuery(internal.FieldValueKey(fieldID, value), "field_value_keys"))
+	}
+
 	if options.ProjectID.Has() {
 		queries = append(queries, inner_bleve.NumericEqualityQuery(options.ProjectID.Value(), "project_id"))
 	}
@@ -266,12 +289,15 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 	skip, limit := indexer_internal.ParsePaginator(options.Paginator)
 	search := bleve.NewSearchRequestOptions(indexerQuery, limit, skip, false)
 
-	if options.SortBy == "" {
-		options.SortBy = internal.SortByCreatedAsc
+	if options.IsSimilarKeyword {
+		search.SortBy([]string{"-_score", "-_id"})
+	} else {
+		if options.SortBy == "" {
+			options.SortBy = internal.SortByCreatedAsc
+		}
+		search.SortBy([]string{string(options.SortBy), "-_id"})
 	}
 
-	search.SortBy([]string{string(options.SortBy), "-_id"})
-
 	result, err := b.inner.Indexer.SearchInContext(ctx, search)
 	if err != nil {
 		return nil, err
@@ -5,6 +5,7 @@ package db
 
 import (
 	"context"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	issue_model "code.gitea.io/gitea/models/issues"
@@ -59,18 +60,26 @@ func (i *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 		}
 		subQuery := builder.Select("id").From("issue").Where(repoCond)
 
-		cond = builder.Or(
-			db.BuildCaseInsensitiveLike("issue.name", options.Keyword),
-			db.BuildCaseInsensitiveLike("issue.content", options.Keyword),
-			builder.In("issue.id", builder.Select("issue_id").
-				From("comment").
-				Where(builder.And(
-					builder.Eq{"type": issue_model.CommentTypeComment},
-					builder.In("issue_id", subQuery),
-					db.BuildCaseInsensitiveLike("content", options.Keyword),
-				)),
-			),
-		)
+		keywords := []string{options.Keyword}
+		if options.IsSimilarKeyword {
+			keywords = strings.Fields(options.Keyword)
+		}
+		keywordCond := builder.NewCond()
+		for _, keyword := range keywords {
+			keywordCond = keywordCond.Or(
+				db.BuildCaseInsensitiveLike("issue.name", keyword),
+				db.BuildCaseInsensitiveLike("issue.content", keyword),
+				builder.In("issue.id", builder.Select("issue_id").
+					From("comment").
+					Where(builder.And(
+						builder.Eq{"type": issue_model.CommentTypeComment},
+						builder.In("issue_id", subQuery),
+						db.BuildCaseInsensitiveLike("content", keyword),
+					)),
+				),
+			)
+		}
+		cond = keywordCond
 	}
 
 	opt, err := ToDBOptions(ctx, options)
@@ -33,9 +33,17 @@ func ToDBOptions(ctx context.Context, options *internal.SearchOptions) (*issue_m
 		sortType = "mostcomment"
 	case internal.SortByDeadlineAsc:
 		sortType = "nearduedate"
+	case internal.SortByVotesDesc:
+		sortType = "mostvotes"
+	case internal.SortByVotesAsc:
+		sortType = "leastvotes"
 	default:
 		sortType = "newest"
 	}
+	if options.IsSimilarKeyword {
+		// the database can't rank the issues by relevance, the recently updated ones are the most likely duplicates
+		sortType = "recentupdate"
+	}
 
 	// See the comment of issues_model.SearchOptions for the reason why we need to convert
 	convertID := func(id optional.Option[int64]) int64 {
@@ -84,6 +92,14 @@ func ToDBOptions(ctx context.Context, options *internal.SearchOptions) (*issue_m
 		opts.MilestoneIDs = options.MilestoneIDs
 	}
 
+	if len(options.TypeIDs) == 1 && options.TypeIDs[0] == 0 {
+		opts.TypeIDs = []int64{db.NoConditionID}
+	} else {
+		opts.TypeIDs = options.TypeIDs
+	}
+
+	opts.FieldValues = options.FieldValues
+
 	if options.NoLabelOnly {
 		opts.LabelIDs = []int64{0} // Be careful, it's zero, not db.NoConditionID
 	} else {
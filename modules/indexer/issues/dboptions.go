@@ -38,6 +38,14 @@ func ToSearchOptions(keyword string, opts *issues_model.IssuesOptions) *SearchOp
 		searchOpt.MilestoneIDs = opts.MilestoneIDs
 	}
 
+	if len(opts.TypeIDs) == 1 && opts.TypeIDs[0] == db.NoConditionID {
+		searchOpt.TypeIDs = []int64{0}
+	} else {
+		searchOpt.TypeIDs = opts.TypeIDs
+	}
+
+	searchOpt.FieldValues = opts.FieldValues
+
 	if opts.ProjectID > 0 {
 		searchOpt.ProjectID = optional.Some(opts.ProjectID)
 	} else if opts.ProjectID == -1 { // FIXME: this is inconsistent from other places
@@ -72,29 +80,38 @@ func ToSearchOptions(keyword string, opts *issues_model.IssuesOptions) *SearchOp
 
 	searchOpt.Paginator = opts.Paginator
 
-	switch opts.SortType {
-	case "", "latest":
-		searchOpt.SortBy = SortByCreatedDesc
+	searchOpt.SortBy = ParseSortBy(opts.SortType)
+
+	return searchOpt
+}
+
+// ParseSortBy converts the sort type of an issue list to the order of the search results
+func ParseSortBy(sortType string) SortBy {
+	switch sortType {
+	case "", "latest", "newest":
+		return SortByCreatedDesc
 	case "oldest":
-		searchOpt.SortBy = SortByCreatedAsc
+		return SortByCreatedAsc
 	case "recentupdate":
-		searchOpt.SortBy = SortByUpdatedDesc
+		return SortByUpdatedDesc
 	case "leastupdate":
-		searchOpt.SortBy = SortByUpdatedAsc
+		return SortByUpdatedAsc
 	case "mostcomment":
-		searchOpt.SortBy = SortByCommentsDesc
+		return SortByCommentsDesc
 	case "leastcomment":
-		searchOpt.SortBy = SortByCommentsAsc
+		return SortByCommentsAsc
+	case "mostvotes":
+		return SortByVotesDesc
+	case "leastvotes":
+		return SortByVotesAsc
 	case "nearduedate":
-		searchOpt.SortBy = SortByDeadlineAsc
+		return SortByDeadlineAsc
 	case "farduedate":
-		searchOpt.SortBy = SortByDeadlineDesc
+		return SortByDeadlineDesc
 	case "priority", "priorityrepo", "project-column-sorting":
 		// Unsupported sort type for search
 		fallthrough
 	default:
-		searchOpt.SortBy = SortByUpdatedDesc
+		return SortByUpdatedDesc
 	}
-
-	return searchOpt
 }
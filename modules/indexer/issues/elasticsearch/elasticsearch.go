@@ -18,7 +18,7 @@ import (
 )
 
 const (
-	issueIndexerLatestVersion = 1
+	issueIndexerLatestVersion = 4
 	// multi-match-types, currently only 2 types are used
 	// Reference: https://www.elastic.co/guide/en/elasticsearch/reference/7.0/query-dsl-multi-match-query.html#multi-match-types
 	esMultiMatchTypeBestFields   = "best_fields"
@@ -61,6 +61,8 @@ const (
 			"label_ids": { "type": "integer", "index": true },
 			"no_label": { "type": "boolean", "index": true },
 			"milestone_id": { "type": "integer", "index": true },
+			"type_id": { "type": "integer", "index": true },
+			"field_value_keys": { "type": "long", "index": true },
 			"project_id": { "type": "integer", "index": true },
 			"project_board_id": { "type": "integer", "index": true },
 			"poster_id": { "type": "integer", "index": true },
@@ -73,7 +75,8 @@ const (
 
 			"created_unix": { "type": "integer", "index": true },
 			"deadline_unix": { "type": "integer", "index": true },
-			"comment_count": { "type": "integer", "index": true }
+			"comment_count": { "type": "integer", "index": true },
+			"vote_count": { "type": "integer", "index": true }
 		}
 	}
 }
@@ -146,7 +149,7 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 
 	if options.Keyword != "" {
 		searchType := esMultiMatchTypePhrasePrefix
-		if options.IsFuzzyKeyword {
+		if options.IsFuzzyKeyword || options.IsSimilarKeyword {
 			searchType = esMultiMatchTypeBestFields
 		}
 
@@ -194,6 +197,14 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 		query.Must(elastic.NewTermsQuery("milestone_id", toAnySlice(options.MilestoneIDs)...))
 	}
 
+	if len(options.TypeIDs) > 0 {
+		query.Must(elastic.NewTermsQuery("type_id", toAnySlice(options.TypeIDs)...))
+	}
+
+	for fieldID, value := range options.FieldValues {
+		query.Must(elastic.NewTermQuery("field_value_keys", internal.FieldValueKey(fieldID, value)))
+	}
+
 	if options.ProjectID.Has() {
 		query.Must(elastic.NewTermQuery("project_id", options.ProjectID.Value()))
 	}
@@ -242,6 +253,9 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 		parseSortBy(options.SortBy),
 		elastic.NewFieldSort("id").Desc(),
 	}
+	if options.IsSimilarKeyword {
+		sortBy[0] = elastic.NewScoreSort()
+	}
 
 	// See https://stackoverflow.com/questions/35206409/elasticsearch-2-1-result-window-is-too-large-index-max-result-window/35221900
 	// TODO: make it configurable since it's configurable in elasticsearch
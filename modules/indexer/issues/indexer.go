@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"runtime/pprof"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -268,15 +269,20 @@ func IsAvailable(ctx context.Context) bool {
 // SearchOptions indicates the options for searching issues
 type SearchOptions = internal.SearchOptions
 
+// SortBy indicates the order of the search results
+type SortBy = internal.SortBy
+
 const (
 	SortByCreatedDesc  = internal.SortByCreatedDesc
 	SortByUpdatedDesc  = internal.SortByUpdatedDesc
 	SortByCommentsDesc = internal.SortByCommentsDesc
 	SortByDeadlineDesc = internal.SortByDeadlineDesc
+	SortByVotesDesc    = internal.SortByVotesDesc
 	SortByCreatedAsc   = internal.SortByCreatedAsc
 	SortByUpdatedAsc   = internal.SortByUpdatedAsc
 	SortByCommentsAsc  = internal.SortByCommentsAsc
 	SortByDeadlineAsc  = internal.SortByDeadlineAsc
+	SortByVotesAsc     = internal.SortByVotesAsc
 )
 
 // SearchIssues search issues by options.
@@ -306,6 +312,28 @@ func SearchIssues(ctx context.Context, opts *SearchOptions) ([]int64, int64, err
 	return ret, result.Total, nil
 }
 
+// DefaultSimilarIssuesLimit is the number of similar issues which are suggested by default
+const DefaultSimilarIssuesLimit = 5
+
+// SearchSimilarIssues returns the ids of the open issues of a repository which are the most similar to the title,
+// the most similar first, it's used to suggest the possible duplicates of a new issue.
+func SearchSimilarIssues(ctx context.Context, repoID int64, title string, limit int) ([]int64, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return []int64{}, nil
+	}
+	ids, _, err := SearchIssues(ctx, &SearchOptions{
+		Keyword:          title,
+		IsFuzzyKeyword:   true,
+		IsSimilarKeyword: true,
+		RepoIDs:          []int64{repoID},
+		IsPull:           optional.Some(false),
+		IsClosed:         optional.Some(false),
+		Paginator:        &db_model.ListOptions{PageSize: limit},
+	})
+	return ids, err
+}
+
 // CountIssues counts issues by options. It is a shortcut of SearchIssues(ctx, opts) but only returns the total count.
 func CountIssues(ctx context.Context, opts *SearchOptions) (int64, error) {
 	opts = opts.Copy(func(options *SearchOptions) { options.Paginator = &db_model.ListOptions{PageSize: 0} })
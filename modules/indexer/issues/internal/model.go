@@ -4,6 +4,9 @@
 package internal
 
 import (
+	"hash/fnv"
+	"strconv"
+
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -26,6 +29,8 @@ type IndexerData struct {
 	LabelIDs           []int64            `json:"label_ids"`
 	NoLabel            bool               `json:"no_label"` // True if LabelIDs is empty
 	MilestoneID        int64              `json:"milestone_id"`
+	TypeID             int64              `json:"type_id"`
+	FieldValueKeys     []int64            `json:"field_value_keys"` // see FieldValueKey
 	ProjectID          int64              `json:"project_id"`
 	ProjectColumnID    int64              `json:"project_board_id"` // the key should be kept as project_board_id to keep compatible
 	PosterID           int64              `json:"poster_id"`
@@ -42,6 +47,7 @@ type IndexerData struct {
 	CreatedUnix  timeutil.TimeStamp `json:"created_unix"`
 	DeadlineUnix timeutil.TimeStamp `json:"deadline_unix"`
 	CommentCount int64              `json:"comment_count"`
+	VoteCount    int64              `json:"vote_count"`
 }
 
 // Match represents on search result
@@ -76,6 +82,8 @@ type SearchOptions struct {
 
 	IsFuzzyKeyword bool // if false the levenshtein distance is 0
 
+	IsSimilarKeyword bool // if true the issues match any word of the keyword and are sorted by relevance, SortBy is ignored
+
 	RepoIDs   []int64 // repository IDs which the issues belong to
 	AllPublic bool    // if include all public repositories
 
@@ -89,6 +97,10 @@ type SearchOptions struct {
 
 	MilestoneIDs []int64 // milestones the issues have
 
+	TypeIDs []int64 // types the issues have, zero means no type
+
+	FieldValues map[int64]string // values of the custom fields the issues have, keyed by the field ids
+
 	ProjectID       optional.Option[int64] // project the issues belong to
 	ProjectColumnID optional.Option[int64] // project column the issues belong to
 
@@ -111,6 +123,17 @@ type SearchOptions struct {
 	SortBy SortBy // sort by field
 }
 
+// FieldValueKey returns the number identifying a value of a custom field in the indexers,
+// so the issues can be filtered by the values like by the other ids without handling strings.
+// It's kept in the range of the integers which a float64 represents exactly.
+func FieldValueKey(fieldID int64, value string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strconv.FormatInt(fieldID, 10)))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(value))
+	return int64(h.Sum64() & (1<<53 - 1))
+}
+
 // Copy returns a copy of the options.
 // Be careful, it's not a deep copy, so `SearchOptions.RepoIDs = {...}` is OK while `SearchOptions.RepoIDs[0] = ...` is not.
 func (o *SearchOptions) Copy(edit ...func(options *SearchOptions)) *SearchOptions {
@@ -131,10 +154,12 @@ const (
 	SortByUpdatedDesc  SortBy = "-updated_unix"
 	SortByCommentsDesc SortBy = "-comment_count"
 	SortByDeadlineDesc SortBy = "-deadline_unix"
+	SortByVotesDesc    SortBy = "-vote_count"
 	SortByCreatedAsc   SortBy = "created_unix"
 	SortByUpdatedAsc   SortBy = "updated_unix"
 	SortByCommentsAsc  SortBy = "comment_count"
 	SortByDeadlineAsc  SortBy = "deadline_unix"
+	SortByVotesAsc     SortBy = "vote_count"
 	// Unsupported sort types which are supported by issues.IssuesOptions.SortType:
 	//
 	//  - "priorityrepo":
@@ -130,6 +130,20 @@ var cases = []*testIndexerCase{
 		ExpectedIDs:   []int64{1002, 1001, 1000},
 		ExpectedTotal: 3,
 	},
+	{
+		Name: "similar keyword",
+		ExtraData: []*internal.IndexerData{
+			{ID: 1000, Title: "avatar upload fails"},
+			{ID: 1001, Title: "crash when uploading an avatar"},
+			{ID: 1002, Title: "typo in the readme"},
+		},
+		SearchOptions: &internal.SearchOptions{
+			Keyword:          "crash uploading avatar",
+			IsSimilarKeyword: true,
+		},
+		ExpectedIDs:   []int64{1001, 1000},
+		ExpectedTotal: 2,
+	},
 	{
 		Name: "RepoIDs",
 		ExtraData: []*internal.IndexerData{
@@ -283,6 +297,61 @@ var cases = []*testIndexerCase{
 			}), result.Total)
 		},
 	},
+	{
+		Name: "TypeIDs",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			TypeIDs: []int64{1, 2},
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Contains(t, []int64{1, 2}, data[v.ID].TypeID)
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return v.TypeID == 1 || v.TypeID == 2
+			}), result.Total)
+		},
+	},
+	{
+		Name: "no TypeIDs",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			TypeIDs: []int64{0},
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Equal(t, int64(0), data[v.ID].TypeID)
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return v.TypeID == 0
+			}), result.Total)
+		},
+	},
+	{
+		Name: "FieldValues",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: 5,
+			},
+			FieldValues: map[int64]string{1: "value1", 2: "value2"},
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			keys := []int64{internal.FieldValueKey(1, "value1"), internal.FieldValueKey(2, "value2")}
+			assert.Equal(t, 5, len(result.Hits))
+			for _, v := range result.Hits {
+				assert.Subset(t, data[v.ID].FieldValueKeys, keys)
+			}
+			assert.Equal(t, countIndexerData(data, func(v *internal.IndexerData) bool {
+				return slices.Contains(v.FieldValueKeys, keys[0]) && slices.Contains(v.FieldValueKeys, keys[1])
+			}), result.Total)
+		},
+	},
 	{
 		Name: "no MilestoneIDs",
 		SearchOptions: &internal.SearchOptions{
@@ -631,6 +700,38 @@ var cases = []*testIndexerCase{
 			}
 		},
 	},
+	{
+		Name: "SortByVotesDesc",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptionsAll,
+			SortBy:    internal.SortByVotesDesc,
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, len(data), len(result.Hits))
+			assert.Equal(t, len(data), int(result.Total))
+			for i, v := range result.Hits {
+				if i < len(result.Hits)-1 {
+					assert.GreaterOrEqual(t, data[v.ID].VoteCount, data[result.Hits[i+1].ID].VoteCount)
+				}
+			}
+		},
+	},
+	{
+		Name: "SortByVotesAsc",
+		SearchOptions: &internal.SearchOptions{
+			Paginator: &db.ListOptionsAll,
+			SortBy:    internal.SortByVotesAsc,
+		},
+		Expected: func(t *testing.T, data map[int64]*internal.IndexerData, result *internal.SearchResult) {
+			assert.Equal(t, len(data), len(result.Hits))
+			assert.Equal(t, len(data), int(result.Total))
+			for i, v := range result.Hits {
+				if i < len(result.Hits)-1 {
+					assert.LessOrEqual(t, data[v.ID].VoteCount, data[result.Hits[i+1].ID].VoteCount)
+				}
+			}
+		},
+	},
 	{
 		Name: "SortByDeadlineAsc",
 		SearchOptions: &internal.SearchOptions{
@@ -692,6 +793,10 @@ func generateDefaultIndexerData() []*internal.IndexerData {
 			for i := range subscriberIDs {
 				subscriberIDs[i] = int64(i) + 1 // SubscriberID should not be 0
 			}
+			fieldValueKeys := []int64{internal.FieldValueKey(1, fmt.Sprintf("value%d", id%3))}
+			if id%2 == 0 {
+				fieldValueKeys = append(fieldValueKeys, internal.FieldValueKey(2, fmt.Sprintf("value%d", id%4)))
+			}
 
 			data = append(data, &internal.IndexerData{
 				ID:                 id,
@@ -705,6 +810,8 @@ func generateDefaultIndexerData() []*internal.IndexerData {
 				LabelIDs:           labelIDs,
 				NoLabel:            len(labelIDs) == 0,
 				MilestoneID:        issueIndex % 4,
+				TypeID:             issueIndex % 3,
+				FieldValueKeys:     fieldValueKeys,
 				ProjectID:          issueIndex % 5,
 				ProjectColumnID:    issueIndex % 6,
 				PosterID:           id%10 + 1, // PosterID should not be 0
@@ -717,6 +824,7 @@ func generateDefaultIndexerData() []*internal.IndexerData {
 				CreatedUnix:        timeutil.TimeStamp(id),
 				DeadlineUnix:       timeutil.TimeStamp(id + issueIndex + repoID),
 				CommentCount:       int64(len(comments)),
+				VoteCount:          (id + issueIndex) % 7,
 			})
 		}
 	}
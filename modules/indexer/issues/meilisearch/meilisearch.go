@@ -18,7 +18,7 @@ import (
 )
 
 const (
-	issueIndexerLatestVersion = 3
+	issueIndexerLatestVersion = 6
 
 	// TODO: make this configurable if necessary
 	maxTotalHits = 10000
@@ -64,6 +64,8 @@ func NewIndexer(url, apiKey, indexerName string) *Indexer {
 			"label_ids",
 			"no_label",
 			"milestone_id",
+			"type_id",
+			"field_value_keys",
 			"project_id",
 			"project_board_id",
 			"poster_id",
@@ -79,6 +81,7 @@ func NewIndexer(url, apiKey, indexerName string) *Indexer {
 			"created_unix",
 			"deadline_unix",
 			"comment_count",
+			"vote_count",
 			"id",
 		},
 		Pagination: &meilisearch.Pagination{
@@ -171,6 +174,14 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 		query.And(inner_meilisearch.NewFilterIn("milestone_id", options.MilestoneIDs...))
 	}
 
+	if len(options.TypeIDs) > 0 {
+		query.And(inner_meilisearch.NewFilterIn("type_id", options.TypeIDs...))
+	}
+
+	for fieldID, value := range options.FieldValues {
+		query.And(inner_meilisearch.NewFilterEq("field_value_keys", internal.FieldValueKey(fieldID, value)))
+	}
+
 	if options.ProjectID.Has() {
 		query.And(inner_meilisearch.NewFilterEq("project_id", options.ProjectID.Value()))
 	}
@@ -215,6 +226,12 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 		parseSortBy(options.SortBy),
 		"id:desc",
 	}
+	matchingStrategy := "all"
+	if options.IsSimilarKeyword {
+		// without a sort the hits are ranked by relevance, and the hits which don't match all words are kept
+		sortBy = nil
+		matchingStrategy = "last"
+	}
 
 	skip, limit := indexer_internal.ParsePaginator(options.Paginator, maxTotalHits)
 
@@ -227,7 +244,7 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 	}
 
 	keyword := options.Keyword
-	if !options.IsFuzzyKeyword {
+	if !options.IsFuzzyKeyword && !options.IsSimilarKeyword {
 		// to make it non fuzzy ("typo tolerance" in meilisearch terms), we have to quote the keyword(s)
 		// https://www.meilisearch.com/docs/reference/api/search#phrase-search
 		keyword = doubleQuoteKeyword(keyword)
@@ -238,7 +255,7 @@ func (b *Indexer) Search(ctx context.Context, options *internal.SearchOptions) (
 		Limit:            int64(limit),
 		Offset:           int64(skip),
 		Sort:             sortBy,
-		MatchingStrategy: "all",
+		MatchingStrategy: matchingStrategy,
 	})
 	if err != nil {
 		return nil, err
@@ -35,8 +35,9 @@ func getIssueIndexerData(ctx context.Context, issueID int64) (*internal.IndexerD
 
 	comments := make([]string, 0, len(issue.Comments))
 	for _, comment := range issue.Comments {
-		if comment.Content != "" {
+		if comment.Content != "" && !comment.IsInternal {
 			// what ever the comment type is, index the content if it is not empty.
+			// The internal comments aren't indexed, they would be found by the users who can't see them.
 			comments = append(comments, comment.Content)
 		}
 	}
@@ -92,6 +93,15 @@ func getIssueIndexerData(ctx context.Context, issueID int64) (*internal.IndexerD
 		projectID = issue.Project.ID
 	}
 
+	fieldValues, err := issue_model.GetIssueFieldValuesByIssueID(ctx, issue.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	fieldValueKeys := make([]int64, 0, len(fieldValues))
+	for _, v := range fieldValues {
+		fieldValueKeys = append(fieldValueKeys, internal.FieldValueKey(v.FieldID, v.Value))
+	}
+
 	return &internal.IndexerData{
 		ID:                 issue.ID,
 		RepoID:             issue.RepoID,
@@ -104,6 +114,8 @@ func getIssueIndexerData(ctx context.Context, issueID int64) (*internal.IndexerD
 		LabelIDs:           labels,
 		NoLabel:            len(labels) == 0,
 		MilestoneID:        issue.MilestoneID,
+		TypeID:             issue.TypeID,
+		FieldValueKeys:     fieldValueKeys,
 		ProjectID:          projectID,
 		ProjectColumnID:    issue.ProjectColumnID(ctx),
 		PosterID:           issue.PosterID,
@@ -116,6 +128,7 @@ func getIssueIndexerData(ctx context.Context, issueID int64) (*internal.IndexerD
 		CreatedUnix:        issue.CreatedUnix,
 		DeadlineUnix:       issue.DeadlineUnix,
 		CommentCount:       int64(len(issue.Comments)),
+		VoteCount:          int64(issue.NumVotes),
 	}, true, nil
 }
 
@@ -0,0 +1,183 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// commitStatsCollector aggregates the statistics of commits by contributor and week, and by weekday and hour
+type commitStatsCollector struct {
+	weeks     map[string]*repo_model.RepoContributorWeek
+	punchCard map[[2]int]*repo_model.RepoPunchCard
+}
+
+func newCommitStatsCollector() *commitStatsCollector {
+	return &commitStatsCollector{
+		weeks:     map[string]*repo_model.RepoContributorWeek{},
+		punchCard: map[[2]int]*repo_model.RepoPunchCard{},
+	}
+}
+
+// startOfWeek returns the unix timestamp of the Sunday at 00:00 UTC starting the week of the time
+func startOfWeek(t time.Time) int64 {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -int(day.Weekday())).Unix()
+}
+
+func (c *commitStatsCollector) add(name, email string, date time.Time, additions, deletions int64) {
+	email = strings.ToLower(email)
+	week := startOfWeek(date)
+	key := fmt.Sprintf("%s\x00%d", email, week)
+	stat, ok := c.weeks[key]
+	if !ok {
+		stat = &repo_model.RepoContributorWeek{Email: email, Name: name, Week: week}
+		c.weeks[key] = stat
+	}
+	stat.Commits++
+	stat.Additions += additions
+	stat.Deletions += deletions
+
+	// the punch card uses the time zone of the author
+	cardKey := [2]int{int(date.Weekday()), date.Hour()}
+	card, ok := c.punchCard[cardKey]
+	if !ok {
+		card = &repo_model.RepoPunchCard{Weekday: cardKey[0], Hour: cardKey[1]}
+		c.punchCard[cardKey] = card
+	}
+	card.Commits++
+}
+
+func (c *commitStatsCollector) results() ([]*repo_model.RepoContributorWeek, []*repo_model.RepoPunchCard) {
+	weeks := make([]*repo_model.RepoContributorWeek, 0, len(c.weeks))
+	for _, week := range c.weeks {
+		weeks = append(weeks, week)
+	}
+	cards := make([]*repo_model.RepoPunchCard, 0, len(c.punchCard))
+	for _, card := range c.punchCard {
+		cards = append(cards, card)
+	}
+	return weeks, cards
+}
+
+// parse parses the output of git log with the commitStatsFormat and --numstat
+func (c *commitStatsCollector) parse(scanner *bufio.Scanner) error {
+	var (
+		name, email          string
+		date                 time.Time
+		additions, deletions int64
+		inCommit             bool
+	)
+	flush := func() {
+		if inCommit {
+			c.add(name, email, date, additions, deletions)
+		}
+	}
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] == 0 {
+			flush()
+			fields := bytes.SplitN(line[1:], []byte{0x1f}, 3)
+			if len(fields) != 3 {
+				return fmt.Errorf("malformed commit line: %q", line)
+			}
+			var err error
+			if date, err = time.Parse(time.RFC3339, string(fields[2])); err != nil {
+				return fmt.Errorf("malformed commit date: %w", err)
+			}
+			name, email = string(fields[0]), string(fields[1])
+			additions, deletions = 0, 0
+			inCommit = true
+			continue
+		}
+
+		// <additions> TAB <deletions> TAB <path>, the numbers are "-" for the binary files
+		fields := bytes.SplitN(line, []byte{'\t'}, 3)
+		if len(fields) != 3 {
+			return fmt.Errorf("malformed numstat line: %q", line)
+		}
+		added, _ := strconv.ParseInt(string(fields[0]), 10, 64)
+		deleted, _ := strconv.ParseInt(string(fields[1]), 10, 64)
+		additions += added
+		deletions += deleted
+	}
+	flush()
+	return scanner.Err()
+}
+
+// the lines of the commits start with a NUL, the fields are separated by %x1f
+const commitStatsFormat = "--format=%x00%aN%x1f%aE%x1f%aI"
+
+// indexCommitStats adds the statistics of the commits of the default branch which have been pushed since the last indexing,
+// the statistics are computed again if the previously indexed commit isn't an ancestor of the new one.
+func indexCommitStats(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, commitID string) error {
+	status, err := repo_model.GetIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCommitStats)
+	if err != nil {
+		return err
+	}
+	if status.CommitSha == commitID {
+		return nil
+	}
+
+	revision := commitID
+	reset := true
+	if status.CommitSha != "" {
+		if err := git.NewCommand(ctx, "merge-base", "--is-ancestor").AddDynamicArguments(status.CommitSha, commitID).
+			Run(&git.RunOpts{Dir: gitRepo.Path}); err == nil {
+			revision = status.CommitSha + ".." + commitID
+			reset = false
+		} else {
+			log.Debug("Commit %s of %s isn't an ancestor of %s, computing its commit stats again", status.CommitSha, repo.FullName(), commitID)
+		}
+	}
+
+	collector := newCommitStatsCollector()
+	stderr := new(strings.Builder)
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	if err := git.NewCommand(ctx, "log", "--no-merges", "--numstat", commitStatsFormat).AddDynamicArguments(revision).AddArguments("--").
+		Run(&git.RunOpts{
+			Dir:    gitRepo.Path,
+			Stdout: stdoutWriter,
+			Stderr: stderr,
+			PipelineFunc: func(ctx context.Context, cancel context.CancelFunc) error {
+				_ = stdoutWriter.Close()
+				defer stdoutReader.Close()
+				if err := collector.parse(bufio.NewScanner(stdoutReader)); err != nil {
+					cancel()
+					return err
+				}
+				return nil
+			},
+		}); err != nil {
+		return fmt.Errorf("unable to list the commits of %s: %w", repo.FullName(), git.ConcatenateError(err, stderr.String()))
+	}
+
+	weeks, punchCard := collector.results()
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := repo_model.AddRepoCommitStats(ctx, repo.ID, weeks, punchCard, reset); err != nil {
+			return err
+		}
+		return repo_model.UpdateIndexerStatus(ctx, repo, repo_model.RepoIndexerTypeCommitStats, commitID)
+	})
+}
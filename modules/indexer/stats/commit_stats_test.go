@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package stats
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartOfWeek(t *testing.T) {
+	sunday := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC).Unix()
+	assert.Equal(t, sunday, startOfWeek(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, sunday, startOfWeek(time.Date(2024, 3, 16, 23, 59, 59, 0, time.UTC)))
+	// the week is computed in UTC
+	assert.Equal(t, sunday, startOfWeek(time.Date(2024, 3, 9, 22, 0, 0, 0, time.FixedZone("", -4*3600))))
+}
+
+func TestCommitStatsCollector(t *testing.T) {
+	output := "\x00User Two\x1fUser2@Example.com\x1f2024-03-11T09:30:00+02:00\n" +
+		"\n" +
+		"3\t1\tREADME.md\n" +
+		"-\t-\timage.png\n" +
+		"\x00User Two\x1fuser2@example.com\x1f2024-03-12T09:10:00+02:00\n" +
+		"\x00User Four\x1fuser4@example.com\x1f2024-03-04T18:00:00Z\n" +
+		"\n" +
+		"0\t5\tdocs/old.md\n"
+
+	collector := newCommitStatsCollector()
+	assert.NoError(t, collector.parse(bufio.NewScanner(strings.NewReader(output))))
+	weeks, punchCard := collector.results()
+
+	assert.ElementsMatch(t, []*repo_model.RepoContributorWeek{
+		{Email: "user2@example.com", Name: "User Two", Week: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC).Unix(), Commits: 2, Additions: 3, Deletions: 1},
+		{Email: "user4@example.com", Name: "User Four", Week: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC).Unix(), Commits: 1, Deletions: 5},
+	}, weeks)
+	assert.ElementsMatch(t, []*repo_model.RepoPunchCard{
+		{Weekday: int(time.Monday), Hour: 9, Commits: 1},
+		{Weekday: int(time.Tuesday), Hour: 9, Commits: 1},
+		{Weekday: int(time.Monday), Hour: 18, Commits: 1},
+	}, punchCard)
+
+	assert.Error(t, newCommitStatsCollector().parse(bufio.NewScanner(strings.NewReader("\x00User Two\n"))))
+}
@@ -56,6 +56,11 @@ func (db *DBIndexer) Index(id int64) error {
 		return err
 	}
 
+	if err := indexCommitStats(ctx, repo, gitRepo, commitID); err != nil {
+		log.Error("Unable to update commit stats for ID %s for default branch %s in %s. Error: %v", commitID, repo.DefaultBranch, repo.RepoPath(), err)
+		return err
+	}
+
 	// Do not recalculate stats if already calculated for this commit
 	if status.CommitSha == commitID {
 		return nil
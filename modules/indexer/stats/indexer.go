@@ -10,6 +10,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
 )
 
 // Indexer defines an interface to index repository stats
@@ -40,8 +41,6 @@ func Init() error {
 func populateRepoIndexer(ctx context.Context) {
 	log.Info("Populating the repo stats indexer with existing repositories")
 
-	isShutdown := graceful.GetManager().IsShutdown()
-
 	exist, err := db.IsTableNotEmpty("repository")
 	if err != nil {
 		log.Fatal("System error: %v", err)
@@ -49,8 +48,23 @@ func populateRepoIndexer(ctx context.Context) {
 		return
 	}
 
-	var maxRepoID int64
-	if maxRepoID, err = db.GetMaxID("repository"); err != nil {
+	// the repositories are indexed for their language and commit statistics at once,
+	// the ones missing either of them are queued
+	for _, indexerType := range []repo_model.RepoIndexerType{repo_model.RepoIndexerTypeStats, repo_model.RepoIndexerTypeCommitStats} {
+		if !pushUnindexedRepos(ctx, indexerType) {
+			return
+		}
+	}
+	log.Info("Done (re)populating the repo stats indexer with existing repositories")
+}
+
+// pushUnindexedRepos pushes the repositories which haven't been indexed for the indexer type to the queue,
+// it returns false if the population has been interrupted by a shutdown.
+func pushUnindexedRepos(ctx context.Context, indexerType repo_model.RepoIndexerType) bool {
+	isShutdown := graceful.GetManager().IsShutdown()
+
+	maxRepoID, err := db.GetMaxID("repository")
+	if err != nil {
 		log.Fatal("System error: %v", err)
 	}
 
@@ -61,13 +75,13 @@ func populateRepoIndexer(ctx context.Context) {
 		select {
 		case <-isShutdown:
 			log.Info("Repository Stats Indexer population shutdown before completion")
-			return
+			return false
 		default:
 		}
-		ids, err := repo_model.GetUnindexedRepos(ctx, repo_model.RepoIndexerTypeStats, maxRepoID, 0, 50)
+		ids, err := repo_model.GetUnindexedRepos(ctx, indexerType, maxRepoID, 0, 50)
 		if err != nil {
 			log.Error("populateRepoIndexer: %v", err)
-			return
+			return false
 		} else if len(ids) == 0 {
 			break
 		}
@@ -75,14 +89,14 @@ func populateRepoIndexer(ctx context.Context) {
 			select {
 			case <-isShutdown:
 				log.Info("Repository Stats Indexer population shutdown before completion")
-				return
+				return false
 			default:
 			}
-			if err := statsQueue.Push(id); err != nil {
+			if err := statsQueue.Push(id); err != nil && err != queue.ErrAlreadyInQueue {
 				log.Error("statsQueue.Push: %v", err)
 			}
 			maxRepoID = id - 1
 		}
 	}
-	log.Info("Done (re)populating the repo stats indexer with existing repositories")
+	return true
 }
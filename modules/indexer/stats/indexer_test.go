@@ -48,4 +48,18 @@ func TestRepoStatsIndex(t *testing.T) {
 	langs, err := repo_model.GetTopLanguageStats(db.DefaultContext, repo, 5)
 	assert.NoError(t, err)
 	assert.Empty(t, langs)
+
+	status, err = repo_model.GetIndexerStatus(db.DefaultContext, repo, repo_model.RepoIndexerTypeCommitStats)
+	assert.NoError(t, err)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", status.CommitSha)
+	frequency, err := repo_model.GetRepoCodeFrequency(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*repo_model.RepoCodeFrequency{{Week: 1489881600, Additions: 3}}, frequency)
+	punchCard, err := repo_model.GetRepoPunchCard(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, punchCard, 1) {
+		assert.Equal(t, 0, punchCard[0].Weekday)
+		assert.Equal(t, 16, punchCard[0].Hour)
+		assert.EqualValues(t, 1, punchCard[0].Commits)
+	}
 }
@@ -0,0 +1,193 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// FieldErrors are the invalid values of the fields of an issue form
+type FieldErrors []*api.IssueFormFieldError
+
+func (errs FieldErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		name := err.Label
+		if name == "" {
+			name = err.ID
+		}
+		msgs = append(msgs, fmt.Sprintf("%s %s", name, err.Message))
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// ValidateValues checks the values of the fields of an issue form, as they are submitted with the web form,
+// against the validations of the fields, the fields which aren't on the form aren't checked
+func ValidateValues(template *api.IssueTemplate, values url.Values) FieldErrors {
+	var errs FieldErrors
+	for _, field := range template.Fields {
+		f := &valuedField{
+			IssueFormField: field,
+			Values:         values,
+		}
+		if f.ID == "" || f.Type == api.IssueFormFieldTypeMarkdown || !f.VisibleOnForm() {
+			continue
+		}
+		if msg := f.validate(); msg != "" {
+			errs = append(errs, &api.IssueFormFieldError{ID: f.ID, Label: f.Label(), Message: msg})
+		}
+	}
+	return errs
+}
+
+func (f *valuedField) isRequired() bool {
+	required, _ := f.Validations["required"].(bool)
+	return required
+}
+
+// validate returns why the value of the field is invalid, or an empty string if it's valid
+func (f *valuedField) validate() string {
+	switch f.Type {
+	case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+		value := f.Value()
+		if value == "" {
+			if f.isRequired() {
+				return "is required"
+			}
+			return ""
+		}
+		if f.Type == api.IssueFormFieldTypeTextarea {
+			return ""
+		}
+		if isNumber, _ := f.Validations["is_number"].(bool); isNumber {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return "should be a number"
+			}
+		}
+		// like the pattern attribute of the input, the regex must match the whole value,
+		// it's only checked if it's also a valid regex in Go
+		if pattern, _ := f.Validations["regex"].(string); pattern != "" {
+			if re, err := regexp.Compile("^(?:" + pattern + ")$"); err == nil && !re.MatchString(value) {
+				return fmt.Sprintf("should match %s", pattern)
+			}
+		}
+	case api.IssueFormFieldTypeDropdown:
+		options := f.Options()
+		var selected []string
+		for _, v := range strings.Split(f.Get("form-field-"+f.ID), ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+			if idx, err := strconv.Atoi(v); err != nil || idx < 0 || idx >= len(options) {
+				return "has an invalid option"
+			}
+			selected = append(selected, v)
+		}
+		if len(selected) == 0 && f.isRequired() {
+			return "is required"
+		}
+		if multiple, _ := f.Attributes["multiple"].(bool); !multiple && len(selected) > 1 {
+			return "can only have one option"
+		}
+	case api.IssueFormFieldTypeCheckboxes:
+		var missing []string
+		for _, option := range f.Options() {
+			if option.isRequired() && !option.IsChecked() {
+				missing = append(missing, option.Label())
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Sprintf("must have %s checked", strings.Join(missing, ", "))
+		}
+	}
+	return ""
+}
+
+func (o *valuedOption) isRequired() bool {
+	if vs, ok := o.data.(map[string]any); ok {
+		required, _ := vs["required"].(bool)
+		return required
+	}
+	return false
+}
+
+// ValuesFromAPI converts the values of the fields of an issue form by their ids, where the values of dropdowns and
+// checkboxes are the labels of the selected options, to the values submitted with the web form
+func ValuesFromAPI(template *api.IssueTemplate, apiValues map[string][]string) (url.Values, FieldErrors) {
+	values := url.Values{}
+	var errs FieldErrors
+	fieldError := func(f *valuedField, format string, a ...any) {
+		errs = append(errs, &api.IssueFormFieldError{ID: f.ID, Label: f.Label(), Message: fmt.Sprintf(format, a...)})
+	}
+
+	ids := make([]string, 0, len(template.Fields))
+	for _, field := range template.Fields {
+		f := &valuedField{
+			IssueFormField: field,
+			Values:         values,
+		}
+		if f.ID == "" || f.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		ids = append(ids, f.ID)
+		fieldValues, ok := apiValues[f.ID]
+		if !ok {
+			// the default option of a dropdown is selected like on the web form
+			if defaultIdx, ok := f.Attributes["default"].(int); ok && f.Type == api.IssueFormFieldTypeDropdown {
+				values.Set("form-field-"+f.ID, strconv.Itoa(defaultIdx))
+			}
+			continue
+		}
+
+		switch f.Type {
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+			if len(fieldValues) > 1 {
+				fieldError(f, "can only have one value")
+				continue
+			}
+			if len(fieldValues) == 1 {
+				values.Set("form-field-"+f.ID, fieldValues[0])
+			}
+		case api.IssueFormFieldTypeDropdown, api.IssueFormFieldTypeCheckboxes:
+			options := f.Options()
+			var selected []string
+			for _, value := range fieldValues {
+				idx := slices.IndexFunc(options, func(option *valuedOption) bool {
+					return option.Label() == value
+				})
+				if idx < 0 {
+					fieldError(f, "doesn't have an option %q", value)
+					break
+				}
+				if f.Type == api.IssueFormFieldTypeCheckboxes {
+					values.Set(fmt.Sprintf("form-field-%s-%d", f.ID, idx), "on")
+				} else {
+					selected = append(selected, strconv.Itoa(idx))
+				}
+			}
+			if len(selected) > 0 {
+				values.Set("form-field-"+f.ID, strings.Join(selected, ","))
+			}
+		}
+	}
+
+	unknownIDs := make([]string, 0, len(apiValues))
+	for id := range apiValues {
+		if !slices.Contains(ids, id) {
+			unknownIDs = append(unknownIDs, id)
+		}
+	}
+	slices.Sort(unknownIDs)
+	for _, id := range unknownIDs {
+		errs = append(errs, &api.IssueFormFieldError{ID: id, Message: "isn't a field of the form"})
+	}
+	return values, errs
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"net/url"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const valuesTestTemplate = `
+name: Bug
+about: Report a bug
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for the report
+  - type: textarea
+    id: description
+    attributes:
+      label: Description
+    validations:
+      required: true
+  - type: input
+    id: version
+    attributes:
+      label: Version
+    validations:
+      is_number: true
+      regex: "[0-9.]+"
+  - type: dropdown
+    id: platform
+    attributes:
+      label: Platform
+      options:
+        - Linux
+        - Windows
+      default: 0
+    validations:
+      required: true
+  - type: dropdown
+    id: browsers
+    attributes:
+      label: Browsers
+      multiple: true
+      options:
+        - Firefox
+        - Chrome
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Terms
+      options:
+        - label: I searched the existing issues
+          required: true
+        - label: I can help to fix it
+`
+
+func TestValidateValues(t *testing.T) {
+	template, err := Unmarshal("bug.yaml", []byte(valuesTestTemplate))
+	require.NoError(t, err)
+	require.NoError(t, Validate(template))
+
+	errs := ValidateValues(template, url.Values{
+		"form-field-description": {"It crashes"},
+		"form-field-version":     {"1.22"},
+		"form-field-platform":    {"1"},
+		"form-field-browsers":    {"0,1"},
+		"form-field-terms-0":     {"on"},
+	})
+	assert.Empty(t, errs)
+
+	errs = ValidateValues(template, url.Values{
+		"form-field-description": {"  "},
+		"form-field-version":     {"latest"},
+		"form-field-platform":    {"0,1"},
+		"form-field-browsers":    {"2"},
+		"form-field-terms-1":     {"on"},
+	})
+	assert.Equal(t, FieldErrors{
+		{ID: "description", Label: "Description", Message: "is required"},
+		{ID: "version", Label: "Version", Message: "should be a number"},
+		{ID: "platform", Label: "Platform", Message: "can only have one option"},
+		{ID: "browsers", Label: "Browsers", Message: "has an invalid option"},
+		{ID: "terms", Label: "Terms", Message: "must have I searched the existing issues checked"},
+	}, errs)
+	assert.Equal(t, "Description is required, Version should be a number, Platform can only have one option, "+
+		"Browsers has an invalid option, Terms must have I searched the existing issues checked", errs.Error())
+
+	errs = ValidateValues(template, url.Values{
+		"form-field-description": {"It crashes"},
+		"form-field-version":     {"-1"},
+		"form-field-terms-0":     {"on"},
+	})
+	assert.Equal(t, FieldErrors{
+		{ID: "version", Label: "Version", Message: "should match [0-9.]+"},
+		{ID: "platform", Label: "Platform", Message: "is required"},
+	}, errs)
+}
+
+func TestValuesFromAPI(t *testing.T) {
+	template, err := Unmarshal("bug.yaml", []byte(valuesTestTemplate))
+	require.NoError(t, err)
+
+	values, errs := ValuesFromAPI(template, map[string][]string{
+		"description": {"It crashes"},
+		"browsers":    {"Chrome", "Firefox"},
+		"terms":       {"I searched the existing issues"},
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, url.Values{
+		"form-field-description": {"It crashes"},
+		"form-field-platform":    {"0"},
+		"form-field-browsers":    {"1,0"},
+		"form-field-terms-0":     {"on"},
+	}, values)
+	assert.Empty(t, ValidateValues(template, values))
+	assert.Contains(t, RenderToMarkdown(template, values), "### Browsers\n\nFirefox, Chrome\n")
+
+	_, errs = ValuesFromAPI(template, map[string][]string{
+		"description": {"It crashes", "twice"},
+		"platform":    {"macOS"},
+		"severity":    {"high"},
+	})
+	assert.Equal(t, FieldErrors{
+		{ID: "description", Label: "Description", Message: "can only have one value"},
+		{ID: "platform", Label: "Platform", Message: `doesn't have an option "macOS"`},
+		{ID: "severity", Message: "isn't a field of the form"},
+	}, errs)
+	assert.IsType(t, []*api.IssueFormFieldError{}, []*api.IssueFormFieldError(errs))
+}
@@ -170,3 +170,6 @@ func UnmarshalHandleDoubleEncode(bs []byte, v any) error {
 	}
 	return err
 }
+
+// RawMessage is a raw encoded JSON value, it's used to delay decoding
+type RawMessage = json.RawMessage
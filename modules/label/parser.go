@@ -39,7 +39,11 @@ func LoadTemplateFile(fileName string) ([]*Label, error) {
 	if err != nil {
 		return nil, ErrTemplateLoad{fileName, fmt.Errorf("LoadTemplateFile: %w", err)}
 	}
+	return ParseTemplate(fileName, data)
+}
 
+// ParseTemplate parses the content of a label template, the format is given by the extension of the file name.
+func ParseTemplate(fileName string, data []byte) ([]*Label, error) {
 	if strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml") {
 		return parseYamlFormat(fileName, data)
 	}
@@ -50,7 +54,7 @@ func parseYamlFormat(fileName string, data []byte) ([]*Label, error) {
 	lf := &labelFile{}
 
 	if err := yaml.Unmarshal(data, lf); err != nil {
-		return nil, err
+		return nil, ErrTemplateLoad{fileName, err}
 	}
 
 	// Validate label data and fix colors
@@ -102,17 +106,21 @@ func parseLegacyFormat(fileName string, data []byte) ([]*Label, error) {
 
 // LoadTemplateDescription loads the labels from a template file, returns a description string by joining each Label.Name with comma
 func LoadTemplateDescription(fileName string) (string, error) {
-	var buf strings.Builder
 	list, err := LoadTemplateFile(fileName)
 	if err != nil {
 		return "", err
 	}
+	return TemplateDescription(list), nil
+}
 
+// TemplateDescription returns the description of a label template by joining each Label.Name with comma
+func TemplateDescription(list []*Label) string {
+	var buf strings.Builder
 	for i := 0; i < len(list); i++ {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
 		buf.WriteString(list[i].Name)
 	}
-	return buf.String(), nil
+	return buf.String()
 }
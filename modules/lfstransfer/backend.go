@@ -0,0 +1,198 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package lfstransfer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/private"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// httpBackend serves a transfer with the LFS server of the local Gitea server,
+// so the permission checks and the storage are the same as for LFS requests over HTTP.
+type httpBackend struct {
+	ctx           context.Context
+	lfsURL        string
+	authorization string
+}
+
+// NewHTTPBackend returns a Backend using the LFS server at the URL, the requests are authorized with the header value
+func NewHTTPBackend(ctx context.Context, lfsURL, authorization string) Backend {
+	return &httpBackend{
+		ctx:           ctx,
+		lfsURL:        lfsURL,
+		authorization: authorization,
+	}
+}
+
+func (b *httpBackend) newRequest(method, path string) *httplib.Request {
+	return private.NewLocalRequest(b.ctx, b.lfsURL+path, method).
+		Header("Authorization", b.authorization).
+		Header("Accept", lfs.MediaType).
+		SetTimeout(10*time.Second, 60*time.Second)
+}
+
+// statusError converts the response of a failed request to a StatusError
+func statusError(resp *http.Response) *StatusError {
+	var errResp api.LFSLockError
+	_ = json.NewDecoder(resp.Body).Decode(&errResp)
+	if errResp.Message == "" {
+		errResp.Message = http.StatusText(resp.StatusCode)
+	}
+	return &StatusError{Status: resp.StatusCode, Message: errResp.Message}
+}
+
+// doJSON sends the request with the JSON body and decodes the response of a successful request into the result
+func (b *httpBackend) doJSON(req *httplib.Request, body, result any) error {
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		req.Header("Content-Type", lfs.MediaType).Body(bs)
+	}
+	resp, err := req.Response()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusError(resp)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (b *httpBackend) Batch(op string, pointers []lfs.Pointer, ref string) ([]BatchItem, error) {
+	batchReq := &lfs.BatchRequest{
+		Operation: op,
+		Transfers: []string{"basic"},
+		Objects:   pointers,
+	}
+	if ref != "" {
+		batchReq.Ref = &lfs.Reference{Name: ref}
+	}
+	var batchResp lfs.BatchResponse
+	if err := b.doJSON(b.newRequest(http.MethodPost, "/objects/batch"), batchReq, &batchResp); err != nil {
+		return nil, err
+	}
+
+	items := make([]BatchItem, 0, len(batchResp.Objects))
+	for _, obj := range batchResp.Objects {
+		item := BatchItem{Pointer: obj.Pointer}
+		if op == OperationUpload {
+			item.Present = obj.Error == nil && obj.Actions[OperationUpload] == nil
+		} else {
+			item.Present = obj.Error == nil && obj.Actions[OperationDownload] != nil
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (b *httpBackend) Upload(pointer lfs.Pointer, r io.Reader) error {
+	req := b.newRequest(http.MethodPut, "/objects/"+pointer.Oid+"/"+strconv.FormatInt(pointer.Size, 10)).
+		Header("Content-Type", "application/octet-stream").
+		Body(r).
+		ContentLength(pointer.Size).
+		SetReadWriteTimeout(0)
+	return b.doJSON(req, nil, nil)
+}
+
+func (b *httpBackend) Verify(pointer lfs.Pointer) error {
+	return b.doJSON(b.newRequest(http.MethodPost, "/verify"), &pointer, nil)
+}
+
+func (b *httpBackend) Download(oid string) (io.ReadCloser, int64, error) {
+	resp, err := b.newRequest(http.MethodGet, "/objects/"+oid).SetReadWriteTimeout(0).Response()
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, 0, statusError(resp)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+func (b *httpBackend) Lock(path, ref string) (*api.LFSLock, error) {
+	lockReq := &api.LFSLockRequest{Path: path}
+	if ref != "" {
+		lockReq.Ref = &api.LFSLockRef{Name: ref}
+	}
+	bs, err := json.Marshal(lockReq)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.newRequest(http.MethodPost, "/locks").Header("Content-Type", lfs.MediaType).Body(bs).Response()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		var lockResp api.LFSLockResponse
+		if err := json.NewDecoder(resp.Body).Decode(&lockResp); err != nil {
+			return nil, err
+		}
+		return lockResp.Lock, nil
+	case http.StatusConflict:
+		// the existing lock is returned with the conflict
+		var lockErr api.LFSLockError
+		if err := json.NewDecoder(resp.Body).Decode(&lockErr); err != nil {
+			return nil, err
+		}
+		return lockErr.Lock, &StatusError{Status: http.StatusConflict, Message: lockErr.Message}
+	}
+	return nil, statusError(resp)
+}
+
+func (b *httpBackend) ListLocks(opts ListLocksOptions) (*api.LFSLockList, error) {
+	req := b.newRequest(http.MethodGet, "/locks")
+	if opts.Path != "" {
+		req.Param("path", opts.Path)
+	}
+	if opts.ID != "" {
+		req.Param("id", opts.ID)
+	}
+	if opts.Cursor != "" {
+		req.Param("cursor", opts.Cursor)
+	}
+	if opts.Limit > 0 {
+		req.Param("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Ref != "" {
+		req.Param("refspec", opts.Ref)
+	}
+	var list api.LFSLockList
+	if err := b.doJSON(req, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (b *httpBackend) Unlock(id string, force bool, ref string) (*api.LFSLock, error) {
+	unlockReq := &api.LFSLockDeleteRequest{Force: force}
+	if ref != "" {
+		unlockReq.Ref = &api.LFSLockRef{Name: ref}
+	}
+	var lockResp api.LFSLockResponse
+	if err := b.doJSON(b.newRequest(http.MethodPost, "/locks/"+url.PathEscape(id)+"/unlock"), unlockReq, &lockResp); err != nil {
+		return nil, err
+	}
+	return lockResp.Lock, nil
+}
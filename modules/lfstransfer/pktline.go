@@ -0,0 +1,157 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package lfstransfer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxPacketDataLen is the maximum length of the data of a packet, the length prefix takes 4 of the 65520 bytes
+const maxPacketDataLen = 65516
+
+type packetType int
+
+const (
+	packetData packetType = iota
+	packetFlush
+	packetDelim
+)
+
+// pktline reads and writes the packets of the pkt-line format used by git
+// https://git-scm.com/docs/protocol-common#_pkt_line_format
+type pktline struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newPktline(r io.Reader, w io.Writer) *pktline {
+	return &pktline{r: bufio.NewReader(r), w: w}
+}
+
+// readPacket reads the next packet, the data is only returned for data packets
+func (p *pktline) readPacket() (packetType, []byte, error) {
+	var lengthHex [4]byte
+	if _, err := io.ReadFull(p.r, lengthHex[:]); err != nil {
+		return packetData, nil, err
+	}
+	length, err := strconv.ParseUint(string(lengthHex[:]), 16, 16)
+	if err != nil {
+		return packetData, nil, fmt.Errorf("invalid packet length %q", lengthHex[:])
+	}
+	switch {
+	case length == 0:
+		return packetFlush, nil, nil
+	case length == 1:
+		return packetDelim, nil, nil
+	case length < 4:
+		return packetData, nil, fmt.Errorf("invalid packet length %d", length)
+	}
+	data := make([]byte, length-4)
+	if _, err := io.ReadFull(p.r, data); err != nil {
+		return packetData, nil, err
+	}
+	return packetData, data, nil
+}
+
+// readText reads the next packet, the line feed ending a text packet is removed
+func (p *pktline) readText() (packetType, string, error) {
+	typ, data, err := p.readPacket()
+	return typ, strings.TrimSuffix(string(data), "\n"), err
+}
+
+// readLines reads text packets until the flush packet
+func (p *pktline) readLines() ([]string, error) {
+	var lines []string
+	for {
+		typ, line, err := p.readText()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case packetFlush:
+			return lines, nil
+		case packetDelim:
+			return nil, errors.New("unexpected delimiter packet")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func (p *pktline) writePacket(data []byte) error {
+	if len(data) > maxPacketDataLen {
+		return fmt.Errorf("packet of %d bytes is too large", len(data))
+	}
+	if _, err := fmt.Fprintf(p.w, "%04x", len(data)+4); err != nil {
+		return err
+	}
+	_, err := p.w.Write(data)
+	return err
+}
+
+func (p *pktline) writeText(line string) error {
+	return p.writePacket([]byte(line + "\n"))
+}
+
+func (p *pktline) writeFlush() error {
+	_, err := io.WriteString(p.w, "0000")
+	return err
+}
+
+func (p *pktline) writeDelim() error {
+	_, err := io.WriteString(p.w, "0001")
+	return err
+}
+
+// dataReader reads the content of the data packets until the flush packet
+type dataReader struct {
+	p   *pktline
+	buf []byte
+	eof bool
+}
+
+func (r *dataReader) Read(b []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		typ, data, err := r.p.readPacket()
+		if err != nil {
+			return 0, err
+		}
+		switch typ {
+		case packetFlush:
+			r.eof = true
+		case packetDelim:
+			return 0, errors.New("unexpected delimiter packet")
+		default:
+			r.buf = data
+		}
+	}
+	n := copy(b, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// dataWriter writes the content as data packets, the flush packet isn't written
+type dataWriter struct {
+	p *pktline
+}
+
+func (w *dataWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		n := min(len(b), maxPacketDataLen)
+		if err := w.p.writePacket(b[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		b = b[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,459 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package lfstransfer implements the server side of the git-lfs SSH transfer protocol,
+// which transfers LFS objects and handles locks within the SSH connection.
+// https://github.com/git-lfs/git-lfs/blob/main/docs/proposals/ssh_adapter.md
+package lfstransfer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+const (
+	OperationUpload   = "upload"
+	OperationDownload = "download"
+)
+
+// StatusError is an error which is sent to the client with the status code
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s", e.Status, e.Message)
+}
+
+// BatchItem is an object of a batch request and whether the server has it
+type BatchItem struct {
+	lfs.Pointer
+	Present bool
+}
+
+// ListLocksOptions are the filters and the paging of a lock listing
+type ListLocksOptions struct {
+	Path   string
+	ID     string
+	Cursor string
+	Limit  int
+	Ref    string
+}
+
+// Backend stores the objects and the locks of the repository of a transfer
+type Backend interface {
+	Batch(op string, pointers []lfs.Pointer, ref string) ([]BatchItem, error)
+	Upload(pointer lfs.Pointer, r io.Reader) error
+	Verify(pointer lfs.Pointer) error
+	Download(oid string) (io.ReadCloser, int64, error)
+	// Lock creates a lock, if the path is locked already the existing lock is returned with a StatusError of http.StatusConflict
+	Lock(path, ref string) (*api.LFSLock, error)
+	ListLocks(opts ListLocksOptions) (*api.LFSLockList, error)
+	Unlock(id string, force bool, ref string) (*api.LFSLock, error)
+}
+
+// request is a command sent by the client, with its arguments and whether data follows
+type request struct {
+	command string
+	arg     string
+	args    map[string]string
+	hasData bool
+}
+
+type processor struct {
+	p        *pktline
+	op       string
+	userName string
+	backend  Backend
+}
+
+// Serve handles the commands of a client until it quits or disconnects,
+// op is the operation requested by the client and userName is the user who is served.
+func Serve(r io.Reader, w io.Writer, op, userName string, backend Backend) error {
+	proc := &processor{
+		p:        newPktline(r, w),
+		op:       op,
+		userName: userName,
+		backend:  backend,
+	}
+
+	// advertise the capabilities
+	if err := proc.p.writeText("version=1"); err != nil {
+		return err
+	}
+	if err := proc.p.writeText("locking"); err != nil {
+		return err
+	}
+	if err := proc.p.writeFlush(); err != nil {
+		return err
+	}
+
+	for {
+		req, err := proc.readRequest()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		switch req.command {
+		case "version":
+			if req.arg != "1" {
+				err = proc.sendError(http.StatusBadRequest, "unsupported version "+req.arg)
+			} else {
+				err = proc.sendStatus(http.StatusOK)
+			}
+		case "batch":
+			err = proc.batch(req)
+		case "put-object":
+			err = proc.putObject(req)
+		case "verify-object":
+			err = proc.verifyObject(req)
+		case "get-object":
+			err = proc.getObject(req)
+		case "lock":
+			err = proc.lock(req)
+		case "list-lock":
+			err = proc.listLocks(req)
+		case "unlock":
+			err = proc.unlock(req)
+		case "quit":
+			return proc.sendStatus(http.StatusOK)
+		default:
+			err = proc.sendError(http.StatusBadRequest, "unknown command "+req.command)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readRequest reads the command and the arguments of a request, the data after the delimiter is left to the command
+func (proc *processor) readRequest() (*request, error) {
+	typ, line, err := proc.p.readText()
+	if err != nil {
+		return nil, err
+	}
+	if typ != packetData {
+		return nil, errors.New("expected a command")
+	}
+
+	req := &request{args: make(map[string]string)}
+	req.command, req.arg, _ = strings.Cut(line, " ")
+	for {
+		typ, line, err := proc.p.readText()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case packetFlush:
+			return req, nil
+		case packetDelim:
+			req.hasData = true
+			return req, nil
+		}
+		key, value, _ := strings.Cut(line, "=")
+		req.args[key] = value
+	}
+}
+
+// discardData skips the data of a request which isn't handled
+func (proc *processor) discardData(req *request) error {
+	if !req.hasData {
+		return nil
+	}
+	_, err := io.Copy(io.Discard, &dataReader{p: proc.p})
+	return err
+}
+
+func (proc *processor) sendStatus(status int, args ...string) error {
+	if err := proc.p.writeText(fmt.Sprintf("status %03d", status)); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if err := proc.p.writeText(arg); err != nil {
+			return err
+		}
+	}
+	return proc.p.writeFlush()
+}
+
+func (proc *processor) sendStatusWithLines(status int, args, lines []string) error {
+	if err := proc.p.writeText(fmt.Sprintf("status %03d", status)); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if err := proc.p.writeText(arg); err != nil {
+			return err
+		}
+	}
+	if err := proc.p.writeDelim(); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if err := proc.p.writeText(line); err != nil {
+			return err
+		}
+	}
+	return proc.p.writeFlush()
+}
+
+func (proc *processor) sendError(status int, message string) error {
+	return proc.sendStatusWithLines(status, nil, []string{message})
+}
+
+// sendBackendError sends the status of a StatusError, other errors are internal errors
+func (proc *processor) sendBackendError(err error) error {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return proc.sendError(statusErr.Status, statusErr.Message)
+	}
+	log.Error("LFS transfer failed: %v", err)
+	return proc.sendError(http.StatusInternalServerError, "internal server error")
+}
+
+func (proc *processor) requireUpload() error {
+	if proc.op != OperationUpload {
+		return &StatusError{Status: http.StatusForbidden, Message: "the command is only allowed for uploads"}
+	}
+	return nil
+}
+
+func parsePointer(oid, size string) (lfs.Pointer, error) {
+	p := lfs.Pointer{Oid: oid}
+	var err error
+	if p.Size, err = strconv.ParseInt(size, 10, 64); err != nil || !p.IsValid() {
+		return p, &StatusError{Status: http.StatusBadRequest, Message: "invalid object " + oid + " " + size}
+	}
+	return p, nil
+}
+
+func (proc *processor) batch(req *request) error {
+	if !req.hasData {
+		return proc.sendError(http.StatusBadRequest, "batch without objects")
+	}
+	lines, err := proc.p.readLines()
+	if err != nil {
+		return err
+	}
+	if algo, ok := req.args["hash-algo"]; ok && algo != "sha256" {
+		return proc.sendError(http.StatusConflict, "unsupported hash algorithm "+algo)
+	}
+	if transfer, ok := req.args["transfer"]; ok && transfer != "basic" {
+		return proc.sendError(http.StatusConflict, "unsupported transfer "+transfer)
+	}
+
+	pointers := make([]lfs.Pointer, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return proc.sendError(http.StatusBadRequest, "invalid object "+line)
+		}
+		p, err := parsePointer(fields[0], fields[1])
+		if err != nil {
+			return proc.sendBackendError(err)
+		}
+		pointers = append(pointers, p)
+	}
+
+	items, err := proc.backend.Batch(proc.op, pointers, req.args["refname"])
+	if err != nil {
+		return proc.sendBackendError(err)
+	}
+
+	results := make([]string, 0, len(items))
+	for _, item := range items {
+		action := "noop"
+		switch {
+		case proc.op == OperationUpload && !item.Present:
+			action = OperationUpload
+		case proc.op == OperationDownload && item.Present:
+			action = OperationDownload
+		}
+		results = append(results, fmt.Sprintf("%s %d %s", item.Oid, item.Size, action))
+	}
+	return proc.sendStatusWithLines(http.StatusOK, nil, results)
+}
+
+func (proc *processor) putObject(req *request) error {
+	err := proc.requireUpload()
+	var p lfs.Pointer
+	if err == nil {
+		p, err = parsePointer(req.arg, req.args["size"])
+	}
+	if err == nil && !req.hasData {
+		err = &StatusError{Status: http.StatusBadRequest, Message: "object without data"}
+	}
+	if req.hasData {
+		data := &dataReader{p: proc.p}
+		if err == nil {
+			err = proc.backend.Upload(p, data)
+		}
+		// the rest of the data must be read even if the upload failed
+		if _, discardErr := io.Copy(io.Discard, data); discardErr != nil {
+			return discardErr
+		}
+	}
+	if err != nil {
+		return proc.sendBackendError(err)
+	}
+	return proc.sendStatus(http.StatusOK)
+}
+
+func (proc *processor) verifyObject(req *request) error {
+	if err := proc.discardData(req); err != nil {
+		return err
+	}
+	if err := proc.requireUpload(); err != nil {
+		return proc.sendBackendError(err)
+	}
+	p, err := parsePointer(req.arg, req.args["size"])
+	if err != nil {
+		return proc.sendBackendError(err)
+	}
+	if err := proc.backend.Verify(p); err != nil {
+		return proc.sendBackendError(err)
+	}
+	return proc.sendStatus(http.StatusOK)
+}
+
+func (proc *processor) getObject(req *request) error {
+	if err := proc.discardData(req); err != nil {
+		return err
+	}
+	if !(lfs.Pointer{Oid: req.arg}).IsValid() {
+		return proc.sendError(http.StatusBadRequest, "invalid object "+req.arg)
+	}
+	rc, size, err := proc.backend.Download(req.arg)
+	if err != nil {
+		return proc.sendBackendError(err)
+	}
+	defer rc.Close()
+
+	if err := proc.p.writeText(fmt.Sprintf("status %03d", http.StatusOK)); err != nil {
+		return err
+	}
+	if err := proc.p.writeText(fmt.Sprintf("size=%d", size)); err != nil {
+		return err
+	}
+	if err := proc.p.writeDelim(); err != nil {
+		return err
+	}
+	// the status has been sent, so a failure can only be reported by closing the connection
+	if _, err := io.Copy(&dataWriter{p: proc.p}, rc); err != nil {
+		return err
+	}
+	return proc.p.writeFlush()
+}
+
+func lockArgs(lock *api.LFSLock) []string {
+	args := []string{
+		"id=" + lock.ID,
+		"path=" + lock.Path,
+		"locked-at=" + lock.LockedAt.UTC().Format(time.RFC3339),
+	}
+	if lock.Owner != nil {
+		args = append(args, "ownername="+lock.Owner.Name)
+	}
+	return args
+}
+
+func (proc *processor) lock(req *request) error {
+	if err := proc.discardData(req); err != nil {
+		return err
+	}
+	if err := proc.requireUpload(); err != nil {
+		return proc.sendBackendError(err)
+	}
+	path := req.args["path"]
+	if path == "" {
+		return proc.sendError(http.StatusBadRequest, "lock without path")
+	}
+
+	lock, err := proc.backend.Lock(path, req.args["refname"])
+	if err != nil {
+		var statusErr *StatusError
+		if lock != nil && errors.As(err, &statusErr) && statusErr.Status == http.StatusConflict {
+			return proc.sendStatusWithLines(http.StatusConflict, lockArgs(lock), []string{statusErr.Message})
+		}
+		return proc.sendBackendError(err)
+	}
+	return proc.sendStatus(http.StatusCreated, lockArgs(lock)...)
+}
+
+func (proc *processor) listLocks(req *request) error {
+	if err := proc.discardData(req); err != nil {
+		return err
+	}
+	opts := ListLocksOptions{
+		Path:   req.args["path"],
+		ID:     req.args["id"],
+		Cursor: req.args["cursor"],
+		Ref:    req.args["refname"],
+	}
+	if limit, ok := req.args["limit"]; ok {
+		var err error
+		if opts.Limit, err = strconv.Atoi(limit); err != nil || opts.Limit < 0 {
+			return proc.sendError(http.StatusBadRequest, "invalid limit "+limit)
+		}
+	}
+
+	list, err := proc.backend.ListLocks(opts)
+	if err != nil {
+		return proc.sendBackendError(err)
+	}
+
+	var args []string
+	if list.Next != "" {
+		args = append(args, "next-cursor="+list.Next)
+	}
+	lines := make([]string, 0, len(list.Locks)*5)
+	for _, lock := range list.Locks {
+		lines = append(lines,
+			"lock "+lock.ID,
+			"path "+lock.ID+" "+lock.Path,
+			"locked-at "+lock.ID+" "+lock.LockedAt.UTC().Format(time.RFC3339),
+		)
+		if lock.Owner != nil {
+			lines = append(lines, "ownername "+lock.ID+" "+lock.Owner.Name)
+		}
+		// uploads verify the locks, so they need to know whose locks they are
+		if proc.op == OperationUpload {
+			owner := "theirs"
+			if lock.Owner != nil && lock.Owner.Name == proc.userName {
+				owner = "ours"
+			}
+			lines = append(lines, "owner "+lock.ID+" "+owner)
+		}
+	}
+	return proc.sendStatusWithLines(http.StatusOK, args, lines)
+}
+
+func (proc *processor) unlock(req *request) error {
+	if err := proc.discardData(req); err != nil {
+		return err
+	}
+	if err := proc.requireUpload(); err != nil {
+		return proc.sendBackendError(err)
+	}
+	if req.arg == "" {
+		return proc.sendError(http.StatusBadRequest, "unlock without id")
+	}
+
+	lock, err := proc.backend.Unlock(req.arg, req.args["force"] == "true", req.args["refname"])
+	if err != nil {
+		return proc.sendBackendError(err)
+	}
+	return proc.sendStatus(http.StatusOK, lockArgs(lock)...)
+}
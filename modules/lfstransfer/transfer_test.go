@@ -0,0 +1,258 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package lfstransfer
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/lfs"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBackend struct {
+	objects map[string][]byte
+	locks   []*api.LFSLock
+}
+
+func (b *testBackend) Batch(op string, pointers []lfs.Pointer, ref string) ([]BatchItem, error) {
+	items := make([]BatchItem, 0, len(pointers))
+	for _, p := range pointers {
+		_, ok := b.objects[p.Oid]
+		items = append(items, BatchItem{Pointer: p, Present: ok})
+	}
+	return items, nil
+}
+
+func (b *testBackend) Upload(pointer lfs.Pointer, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != pointer.Size {
+		return &StatusError{Status: http.StatusUnprocessableEntity, Message: "size mismatch"}
+	}
+	b.objects[pointer.Oid] = data
+	return nil
+}
+
+func (b *testBackend) Verify(pointer lfs.Pointer) error {
+	if _, ok := b.objects[pointer.Oid]; !ok {
+		return &StatusError{Status: http.StatusNotFound, Message: "not found"}
+	}
+	return nil
+}
+
+func (b *testBackend) Download(oid string) (io.ReadCloser, int64, error) {
+	data, ok := b.objects[oid]
+	if !ok {
+		return nil, 0, &StatusError{Status: http.StatusNotFound, Message: "not found"}
+	}
+	return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+}
+
+func (b *testBackend) Lock(path, ref string) (*api.LFSLock, error) {
+	for _, lock := range b.locks {
+		if lock.Path == path {
+			return lock, &StatusError{Status: http.StatusConflict, Message: "already created lock"}
+		}
+	}
+	lock := &api.LFSLock{
+		ID:       "3",
+		Path:     path,
+		LockedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Owner:    &api.LFSLockOwner{Name: "user2"},
+	}
+	b.locks = append(b.locks, lock)
+	return lock, nil
+}
+
+func (b *testBackend) ListLocks(opts ListLocksOptions) (*api.LFSLockList, error) {
+	return &api.LFSLockList{Locks: b.locks}, nil
+}
+
+func (b *testBackend) Unlock(id string, force bool, ref string) (*api.LFSLock, error) {
+	for i, lock := range b.locks {
+		if lock.ID == id {
+			if lock.Owner.Name != "user2" && !force {
+				return nil, &StatusError{Status: http.StatusForbidden, Message: "not owner"}
+			}
+			b.locks = append(b.locks[:i], b.locks[i+1:]...)
+			return lock, nil
+		}
+	}
+	return nil, &StatusError{Status: http.StatusNotFound, Message: "not found"}
+}
+
+// testClient builds the packets sent by a client
+type testClient struct {
+	buf bytes.Buffer
+	p   *pktline
+}
+
+func newTestClient() *testClient {
+	c := &testClient{}
+	c.p = newPktline(nil, &c.buf)
+	return c
+}
+
+func (c *testClient) command(lines ...string) *testClient {
+	for _, line := range lines {
+		_ = c.p.writeText(line)
+	}
+	_ = c.p.writeFlush()
+	return c
+}
+
+func (c *testClient) commandWithData(lines []string, data []byte) *testClient {
+	for _, line := range lines {
+		_ = c.p.writeText(line)
+	}
+	_ = c.p.writeDelim()
+	_, _ = (&dataWriter{p: c.p}).Write(data)
+	_ = c.p.writeFlush()
+	return c
+}
+
+func (c *testClient) commandWithLines(lines, dataLines []string) *testClient {
+	for _, line := range lines {
+		_ = c.p.writeText(line)
+	}
+	_ = c.p.writeDelim()
+	for _, line := range dataLines {
+		_ = c.p.writeText(line)
+	}
+	_ = c.p.writeFlush()
+	return c
+}
+
+// readResponse reads the packets of a response as text, the delimiter is "---"
+func readResponse(t *testing.T, p *pktline) []string {
+	var lines []string
+	for {
+		typ, line, err := p.readText()
+		require.NoError(t, err)
+		switch typ {
+		case packetFlush:
+			return lines
+		case packetDelim:
+			lines = append(lines, "---")
+		default:
+			lines = append(lines, line)
+		}
+	}
+}
+
+func serve(t *testing.T, op string, backend Backend, c *testClient) *pktline {
+	var out bytes.Buffer
+	require.NoError(t, Serve(&c.buf, &out, op, "user2", backend))
+	p := newPktline(&out, nil)
+	assert.Equal(t, []string{"version=1", "locking"}, readResponse(t, p))
+	return p
+}
+
+func TestTransferObjects(t *testing.T) {
+	content := []byte("dummy content")
+	pointer, err := lfs.GeneratePointer(bytes.NewReader(content))
+	require.NoError(t, err)
+	missing := "a0b1c2d3e4f5a0b1c2d3e4f5a0b1c2d3e4f5a0b1c2d3e4f5a0b1c2d3e4f5a0b1"
+	backend := &testBackend{objects: map[string][]byte{}}
+
+	t.Run("Upload", func(t *testing.T) {
+		c := newTestClient().
+			command("version 1").
+			commandWithLines([]string{"batch", "transfer=basic", "refname=refs/heads/main"}, []string{pointer.Oid + " 13"}).
+			commandWithData([]string{"put-object " + pointer.Oid, "size=13"}, content).
+			command("verify-object "+pointer.Oid, "size=13").
+			commandWithData([]string{"put-object " + missing, "size=13"}, []byte("wrong")).
+			command("quit")
+		p := serve(t, OperationUpload, backend, c)
+
+		assert.Equal(t, []string{"status 200"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 200", "---", pointer.Oid + " 13 upload"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 200"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 200"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 422", "---", "size mismatch"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 200"}, readResponse(t, p))
+		assert.Equal(t, content, backend.objects[pointer.Oid])
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		large := bytes.Repeat([]byte("x"), 2*maxPacketDataLen+1)
+		largePointer, err := lfs.GeneratePointer(bytes.NewReader(large))
+		require.NoError(t, err)
+		backend.objects[largePointer.Oid] = large
+
+		c := newTestClient().
+			command("version 1").
+			commandWithLines([]string{"batch"}, []string{pointer.Oid + " 13", missing + " 5"}).
+			command("get-object "+largePointer.Oid).
+			command("get-object "+missing).
+			commandWithData([]string{"put-object " + pointer.Oid, "size=13"}, content).
+			command("unknown")
+		p := serve(t, OperationDownload, backend, c)
+
+		assert.Equal(t, []string{"status 200"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 200", "---", pointer.Oid + " 13 download", missing + " 5 noop"}, readResponse(t, p))
+
+		// the object is sent in several data packets after the delimiter
+		for _, expected := range []string{"status 200", "size=131033"} {
+			_, line, err := p.readText()
+			require.NoError(t, err)
+			assert.Equal(t, expected, line)
+		}
+		typ, _, err := p.readPacket()
+		require.NoError(t, err)
+		assert.Equal(t, packetDelim, typ)
+		data, err := io.ReadAll(&dataReader{p: p})
+		require.NoError(t, err)
+		assert.Equal(t, large, data)
+
+		assert.Equal(t, []string{"status 404", "---", "not found"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 403", "---", "the command is only allowed for uploads"}, readResponse(t, p))
+		assert.Equal(t, []string{"status 400", "---", "unknown command unknown"}, readResponse(t, p))
+	})
+}
+
+func TestTransferLocks(t *testing.T) {
+	backend := &testBackend{locks: []*api.LFSLock{{
+		ID:       "1",
+		Path:     "theirs.bin",
+		LockedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Owner:    &api.LFSLockOwner{Name: "user5"},
+	}}}
+
+	c := newTestClient().
+		command("lock", "path=ours.bin", "refname=refs/heads/main").
+		command("lock", "path=theirs.bin").
+		command("list-lock", "limit=10").
+		command("unlock 1").
+		command("unlock 1", "force=true").
+		command("quit")
+	p := serve(t, OperationUpload, backend, c)
+
+	assert.Equal(t, []string{"status 201", "id=3", "path=ours.bin", "locked-at=2024-01-02T03:04:05Z", "ownername=user2"}, readResponse(t, p))
+	assert.Equal(t, []string{"status 409", "id=1", "path=theirs.bin", "locked-at=2024-01-01T00:00:00Z", "ownername=user5", "---", "already created lock"}, readResponse(t, p))
+	assert.Equal(t, []string{
+		"status 200", "---",
+		"lock 1", "path 1 theirs.bin", "locked-at 1 2024-01-01T00:00:00Z", "ownername 1 user5", "owner 1 theirs",
+		"lock 3", "path 3 ours.bin", "locked-at 3 2024-01-02T03:04:05Z", "ownername 3 user2", "owner 3 ours",
+	}, readResponse(t, p))
+	assert.Equal(t, []string{"status 403", "---", "not owner"}, readResponse(t, p))
+	assert.Equal(t, []string{"status 200", "id=1", "path=theirs.bin", "locked-at=2024-01-01T00:00:00Z", "ownername=user5"}, readResponse(t, p))
+	assert.Equal(t, []string{"status 200"}, readResponse(t, p))
+	assert.Len(t, backend.locks, 1)
+
+	// locks can only be listed by downloads
+	c = newTestClient().command("list-lock").command("lock", "path=ours.bin")
+	p = serve(t, OperationDownload, backend, c)
+	assert.Equal(t, []string{"status 200", "---", "lock 3", "path 3 ours.bin", "locked-at 3 2024-01-02T03:04:05Z", "ownername 3 user2"}, readResponse(t, p))
+	assert.Equal(t, []string{"status 403", "---", "the command is only allowed for uploads"}, readResponse(t, p))
+}
@@ -119,6 +119,7 @@ func SpecializedMarkdown() goldmark.Markdown {
 				),
 				math.NewExtension(
 					math.Enabled(setting.Markdown.EnableMath),
+					math.WithServerRender(setting.Markdown.EnableMathServerRender),
 				),
 				meta.Meta,
 			),
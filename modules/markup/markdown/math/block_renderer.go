@@ -4,17 +4,22 @@
 package math
 
 import (
+	"bytes"
+
 	gast "github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/util"
 )
 
 // BlockRenderer represents a renderer for math Blocks
-type BlockRenderer struct{}
+type BlockRenderer struct {
+	serverRender bool
+}
 
 // NewBlockRenderer creates a new renderer for math Blocks
-func NewBlockRenderer() renderer.NodeRenderer {
-	return &BlockRenderer{}
+// If serverRender is true the math is converted to MathML instead of being left to the frontend
+func NewBlockRenderer(serverRender bool) renderer.NodeRenderer {
+	return &BlockRenderer{serverRender: serverRender}
 }
 
 // RegisterFuncs registers the renderer for math Blocks
@@ -32,11 +37,26 @@ func (r *BlockRenderer) writeLines(w util.BufWriter, source []byte, n gast.Node)
 
 func (r *BlockRenderer) renderBlock(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
 	n := node.(*Block)
-	if entering {
-		_, _ = w.WriteString(`<pre class="code-block is-loading"><code class="chroma language-math display">`)
-		r.writeLines(w, source, n)
-	} else {
-		_, _ = w.WriteString(`</code></pre>` + "\n")
+	if !entering {
+		return gast.WalkContinue, nil
 	}
-	return gast.WalkContinue, nil
+	if r.serverRender {
+		var buf bytes.Buffer
+		l := n.Lines().Len()
+		for i := 0; i < l; i++ {
+			line := n.Lines().At(i)
+			buf.Write(line.Value(source))
+		}
+		if markup, ok := RenderMathMLCached(bytes.TrimSpace(buf.Bytes()), true); ok {
+			_, _ = w.WriteString(markup)
+			_ = w.WriteByte('\n')
+			return gast.WalkSkipChildren, nil
+		}
+	}
+
+	// the math is left to the frontend, also when the server renderer doesn't support it
+	_, _ = w.WriteString(`<pre class="code-block is-loading"><code class="chroma language-math display">`)
+	r.writeLines(w, source, n)
+	_, _ = w.WriteString(`</code></pre>` + "\n")
+	return gast.WalkSkipChildren, nil
 }
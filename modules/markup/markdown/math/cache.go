@@ -0,0 +1,46 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package math
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"code.gitea.io/gitea/modules/log"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+var mathMLCache *lru.Cache[string, string]
+
+func init() {
+	var err error
+	mathMLCache, err = lru.New[string, string](1000)
+	if err != nil {
+		log.Fatal("failed to new LRU cache, err: %v", err)
+	}
+}
+
+func mathMLCacheKey(source []byte, display bool) string {
+	h := sha256.New()
+	if display {
+		_, _ = h.Write([]byte{1})
+	} else {
+		_, _ = h.Write([]byte{0})
+	}
+	_, _ = h.Write(source)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// RenderMathMLCached works like RenderMathML, the result is stored in an LRU cache keyed by the content hash.
+// An unsupported source is cached as an empty string.
+func RenderMathMLCached(source []byte, display bool) (string, bool) {
+	key := mathMLCacheKey(source, display)
+	if v, ok := mathMLCache.Get(key); ok {
+		return v, v != ""
+	}
+	v, ok := RenderMathML(string(source), display)
+	mathMLCache.Add(key, v)
+	return v, ok
+}
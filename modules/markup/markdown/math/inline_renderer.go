@@ -12,36 +12,64 @@ import (
 )
 
 // InlineRenderer is an inline renderer
-type InlineRenderer struct{}
+type InlineRenderer struct {
+	serverRender bool
+}
 
 // NewInlineRenderer returns a new renderer for inline math
-func NewInlineRenderer() renderer.NodeRenderer {
-	return &InlineRenderer{}
+// If serverRender is true the math is converted to MathML instead of being left to the frontend
+func NewInlineRenderer(serverRender bool) renderer.NodeRenderer {
+	return &InlineRenderer{serverRender: serverRender}
 }
 
-func (r *InlineRenderer) renderInline(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
-	if entering {
-		extraClass := ""
-		if _, ok := n.(*InlineBlock); ok {
-			extraClass = "display "
+// renderInlineMathML writes the MathML of the math, it returns false if the math isn't supported by the server renderer
+func (r *InlineRenderer) renderInlineMathML(w util.BufWriter, source []byte, n ast.Node) bool {
+	var buf bytes.Buffer
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		value := c.(*ast.Text).Segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			buf.Write(value[:len(value)-1])
+			buf.WriteByte(' ')
+		} else {
+			buf.Write(value)
 		}
-		_, _ = w.WriteString(`<code class="language-math ` + extraClass + `is-loading">`)
-		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
-			segment := c.(*ast.Text).Segment
-			value := util.EscapeHTML(segment.Value(source))
-			if bytes.HasSuffix(value, []byte("\n")) {
-				_, _ = w.Write(value[:len(value)-1])
-				if c != n.LastChild() {
-					_, _ = w.Write([]byte(" "))
-				}
-			} else {
-				_, _ = w.Write(value)
+	}
+	_, isDisplay := n.(*InlineBlock)
+	markup, ok := RenderMathMLCached(bytes.TrimSpace(buf.Bytes()), isDisplay)
+	if ok {
+		_, _ = w.WriteString(markup)
+	}
+	return ok
+}
+
+func (r *InlineRenderer) renderInline(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if r.serverRender && r.renderInlineMathML(w, source, n) {
+		return ast.WalkSkipChildren, nil
+	}
+
+	// the math is left to the frontend, also when the server renderer doesn't support it
+	extraClass := ""
+	if _, ok := n.(*InlineBlock); ok {
+		extraClass = "display "
+	}
+	_, _ = w.WriteString(`<code class="language-math ` + extraClass + `is-loading">`)
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := util.EscapeHTML(segment.Value(source))
+		if bytes.HasSuffix(value, []byte("\n")) {
+			_, _ = w.Write(value[:len(value)-1])
+			if c != n.LastChild() {
+				_, _ = w.Write([]byte(" "))
 			}
+		} else {
+			_, _ = w.Write(value)
 		}
-		return ast.WalkSkipChildren, nil
 	}
 	_, _ = w.WriteString(`</code>`)
-	return ast.WalkContinue, nil
+	return ast.WalkSkipChildren, nil
 }
 
 // RegisterFuncs registers the renderer for inline math nodes
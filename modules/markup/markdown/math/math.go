@@ -15,6 +15,7 @@ type Extension struct {
 	enabled           bool
 	parseDollarInline bool
 	parseDollarBlock  bool
+	serverRender      bool
 }
 
 // Option is the interface Options should implement
@@ -61,6 +62,17 @@ func WithBlockDollarParser(enable ...bool) Option {
 	})
 }
 
+// WithServerRender enables or disables the server-side rendering of math to MathML
+func WithServerRender(enable ...bool) Option {
+	value := true
+	if len(enable) > 0 {
+		value = enable[0]
+	}
+	return extensionFunc(func(e *Extension) {
+		e.serverRender = value
+	})
+}
+
 // Math represents a math extension with default rendered delimiters
 var Math = &Extension{
 	enabled:           true,
@@ -102,7 +114,7 @@ func (e *Extension) Extend(m goldmark.Markdown) {
 	m.Parser().AddOptions(parser.WithInlineParsers(inlines...))
 
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
-		util.Prioritized(NewBlockRenderer(), 501),
-		util.Prioritized(NewInlineRenderer(), 502),
+		util.Prioritized(NewBlockRenderer(e.serverRender), 501),
+		util.Prioritized(NewInlineRenderer(e.serverRender), 502),
 	))
 }
@@ -0,0 +1,377 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package math
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// mathMLMaxSourceLength is the maximum number of characters of a math source which will be converted,
+// it follows the limit of the frontend KaTeX renderer
+const mathMLMaxSourceLength = 1000
+
+var mathMLIdentifiers = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π", "varpi": "ϖ", "rho": "ρ",
+	"varrho": "ϱ", "sigma": "σ", "varsigma": "ς", "tau": "τ", "upsilon": "υ", "phi": "ϕ",
+	"varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+	"infty": "∞", "partial": "∂", "nabla": "∇", "ell": "ℓ", "hbar": "ℏ", "emptyset": "∅",
+	"Re": "ℜ", "Im": "ℑ", "aleph": "ℵ",
+}
+
+var mathMLOperators = map[string]string{
+	"times": "×", "cdot": "⋅", "div": "÷", "pm": "±", "mp": "∓", "ast": "∗", "star": "⋆",
+	"circ": "∘", "bullet": "∙", "oplus": "⊕", "otimes": "⊗",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈",
+	"equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅", "propto": "∝", "ll": "≪", "gg": "≫",
+	"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "supset": "⊃", "subseteq": "⊆",
+	"supseteq": "⊇", "cup": "∪", "cap": "∩", "setminus": "∖", "forall": "∀", "exists": "∃",
+	"neg": "¬", "lnot": "¬", "land": "∧", "wedge": "∧", "lor": "∨", "vee": "∨",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "leftrightarrow": "↔", "Rightarrow": "⇒",
+	"Leftarrow": "⇐", "Leftrightarrow": "⇔", "implies": "⟹", "iff": "⟺", "mapsto": "↦",
+	"sum": "∑", "prod": "∏", "coprod": "∐", "int": "∫", "iint": "∬", "iiint": "∭", "oint": "∮",
+	"bigcup": "⋃", "bigcap": "⋂", "ldots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱",
+	"langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉",
+	"mid": "∣", "parallel": "∥", "perp": "⊥", "angle": "∠", "prime": "′",
+	"{": "{", "}": "}", "|": "‖", "lbrace": "{", "rbrace": "}", "vert": "|", "Vert": "‖",
+}
+
+var mathMLFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true, "tanh": true,
+	"log": true, "ln": true, "lg": true, "exp": true, "lim": true, "max": true, "min": true,
+	"sup": true, "inf": true, "det": true, "dim": true, "ker": true, "gcd": true, "deg": true,
+	"arg": true, "Pr": true,
+}
+
+// mathMLUnderOverOperators are rendered with under/over scripts in display mode
+var mathMLUnderOverOperators = map[string]bool{
+	"sum": true, "prod": true, "coprod": true, "bigcup": true, "bigcap": true,
+	"lim": true, "max": true, "min": true, "sup": true, "inf": true,
+}
+
+var mathMLSpaces = map[string]string{
+	",": "0.1667em", ":": "0.2222em", ">": "0.2222em", ";": "0.2778em", " ": "0.25em",
+	"quad": "1em", "qquad": "2em", "!": "0",
+}
+
+var mathMLVariants = map[string]string{
+	"mathrm": "normal", "mathbf": "bold", "mathit": "italic", "mathbb": "double-struck",
+	"mathcal": "script", "mathfrak": "fraktur", "mathsf": "sans-serif", "mathtt": "monospace",
+	"operatorname": "normal",
+}
+
+type mathMLConverter struct {
+	src       []rune
+	pos       int
+	display   bool
+	leftDepth int
+	// unsupported is set when the source uses a command or an environment outside the supported subset
+	unsupported bool
+}
+
+// mathMLNode is a converted MathML element with an optional hint for big operators
+type mathMLNode struct {
+	markup    string
+	underOver bool
+}
+
+// RenderMathML converts a (La)TeX math expression to MathML markup.
+// Only a commonly used subset of TeX is supported, false is returned for a source using unknown commands or
+// environments, or which is too long, so it is left to the frontend renderer.
+// The original source is kept as an annotation so it can be copied or re-rendered by the client.
+func RenderMathML(source string, display bool) (string, bool) {
+	src := []rune(source)
+	if len(src) > mathMLMaxSourceLength {
+		return "", false
+	}
+	c := &mathMLConverter{src: src, display: display}
+	inner := c.parseList(0)
+	if c.unsupported {
+		return "", false
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("<math")
+	if display {
+		sb.WriteString(` display="block"`)
+	}
+	sb.WriteString("><semantics><mrow>")
+	sb.WriteString(inner)
+	sb.WriteString("</mrow>")
+	sb.WriteString(`<annotation encoding="application/x-tex">`)
+	sb.WriteString(html.EscapeString(source))
+	sb.WriteString("</annotation></semantics></math>")
+	return sb.String(), true
+}
+
+// convertNested converts a part of the source which has been read as a raw argument
+func (c *mathMLConverter) convertNested(src []rune) string {
+	nested := &mathMLConverter{src: src, display: c.display}
+	markup := nested.parseList(0)
+	c.unsupported = c.unsupported || nested.unsupported
+	return markup
+}
+
+func (c *mathMLConverter) eof() bool {
+	return c.pos >= len(c.src)
+}
+
+func (c *mathMLConverter) skipSpaces() {
+	for !c.eof() && unicode.IsSpace(c.src[c.pos]) {
+		c.pos++
+	}
+}
+
+// parseList parses atoms until the end of the source or an unmatched closing brace
+func (c *mathMLConverter) parseList(depth int) string {
+	sb := strings.Builder{}
+	for {
+		c.skipSpaces()
+		if c.eof() {
+			break
+		}
+		if c.src[c.pos] == '}' {
+			if depth > 0 {
+				break
+			}
+			// stray closing brace, skip it
+			c.pos++
+			continue
+		}
+		if c.leftDepth > 0 && c.hasCommand("right") {
+			break
+		}
+		sb.WriteString(c.parseScripts(c.parseAtom(depth)))
+	}
+	return sb.String()
+}
+
+func (c *mathMLConverter) hasCommand(name string) bool {
+	cmd := "\\" + name
+	if c.pos+len(cmd) > len(c.src) || string(c.src[c.pos:c.pos+len(cmd)]) != cmd {
+		return false
+	}
+	end := c.pos + len(cmd)
+	return end == len(c.src) || !unicode.IsLetter(c.src[end])
+}
+
+// parseScripts handles sub- and superscripts following a base node
+func (c *mathMLConverter) parseScripts(base mathMLNode) string {
+	var sub, sup string
+	var hasSub, hasSup bool
+	for {
+		c.skipSpaces()
+		if c.eof() {
+			break
+		}
+		switch {
+		case c.src[c.pos] == '_' && !hasSub:
+			c.pos++
+			sub, hasSub = c.parseArgument(), true
+			continue
+		case c.src[c.pos] == '^' && !hasSup:
+			c.pos++
+			sup, hasSup = c.parseArgument(), true
+			continue
+		case c.src[c.pos] == '\'':
+			c.pos++
+			sup, hasSup = sup+"<mo>′</mo>", true
+			continue
+		}
+		break
+	}
+	if hasSup {
+		sup = "<mrow>" + sup + "</mrow>"
+	}
+	if hasSub {
+		sub = "<mrow>" + sub + "</mrow>"
+	}
+	under, over, underOver := "msub", "msup", "msubsup"
+	if base.underOver && c.display {
+		under, over, underOver = "munder", "mover", "munderover"
+	}
+	switch {
+	case hasSub && hasSup:
+		return "<" + underOver + ">" + base.markup + sub + sup + "</" + underOver + ">"
+	case hasSub:
+		return "<" + under + ">" + base.markup + sub + "</" + under + ">"
+	case hasSup:
+		return "<" + over + ">" + base.markup + sup + "</" + over + ">"
+	}
+	return base.markup
+}
+
+// parseArgument parses a command argument: either a braced group or a single atom
+func (c *mathMLConverter) parseArgument() string {
+	c.skipSpaces()
+	if c.eof() {
+		return ""
+	}
+	return c.parseAtom(1).markup
+}
+
+// parseRawArgument reads the text of a braced group without converting it
+func (c *mathMLConverter) parseRawArgument() string {
+	c.skipSpaces()
+	if c.eof() {
+		return ""
+	}
+	if c.src[c.pos] != '{' {
+		r := c.src[c.pos]
+		c.pos++
+		return string(r)
+	}
+	c.pos++
+	start, level := c.pos, 1
+	for ; !c.eof(); c.pos++ {
+		switch c.src[c.pos] {
+		case '{':
+			level++
+		case '}':
+			level--
+		}
+		if level == 0 {
+			break
+		}
+	}
+	text := string(c.src[start:c.pos])
+	if !c.eof() {
+		c.pos++
+	}
+	return text
+}
+
+func (c *mathMLConverter) parseAtom(depth int) mathMLNode {
+	r := c.src[c.pos]
+	switch {
+	case r == '{':
+		c.pos++
+		inner := c.parseList(depth + 1)
+		if !c.eof() {
+			c.pos++ // closing brace
+		}
+		return mathMLNode{markup: "<mrow>" + inner + "</mrow>"}
+	case r == '\\':
+		return c.parseCommand(depth)
+	case unicode.IsDigit(r) || (r == '.' && c.pos+1 < len(c.src) && unicode.IsDigit(c.src[c.pos+1])):
+		start := c.pos
+		for !c.eof() && (unicode.IsDigit(c.src[c.pos]) || c.src[c.pos] == '.') {
+			c.pos++
+		}
+		return mathMLNode{markup: "<mn>" + string(c.src[start:c.pos]) + "</mn>"}
+	case unicode.IsLetter(r):
+		c.pos++
+		return mathMLNode{markup: "<mi>" + html.EscapeString(string(r)) + "</mi>"}
+	case r == '&':
+		c.pos++
+		return mathMLNode{markup: `<mspace width="1em"></mspace>`}
+	}
+	c.pos++
+	return mathMLNode{markup: "<mo>" + html.EscapeString(string(r)) + "</mo>"}
+}
+
+func (c *mathMLConverter) parseCommand(depth int) mathMLNode {
+	c.pos++ // backslash
+	if c.eof() {
+		return mathMLNode{markup: "<mo>\\</mo>"}
+	}
+	start := c.pos
+	if unicode.IsLetter(c.src[c.pos]) {
+		for !c.eof() && unicode.IsLetter(c.src[c.pos]) {
+			c.pos++
+		}
+	} else {
+		c.pos++
+	}
+	name := string(c.src[start:c.pos])
+
+	if v, ok := mathMLIdentifiers[name]; ok {
+		return mathMLNode{markup: "<mi>" + v + "</mi>"}
+	}
+	if v, ok := mathMLOperators[name]; ok {
+		return mathMLNode{markup: "<mo>" + html.EscapeString(v) + "</mo>", underOver: mathMLUnderOverOperators[name]}
+	}
+	if mathMLFunctions[name] {
+		return mathMLNode{markup: "<mi>" + name + "</mi>", underOver: mathMLUnderOverOperators[name]}
+	}
+	if v, ok := mathMLSpaces[name]; ok {
+		return mathMLNode{markup: `<mspace width="` + v + `"></mspace>`}
+	}
+	if v, ok := mathMLVariants[name]; ok {
+		arg := c.parseRawArgument()
+		inner := c.convertNested([]rune(arg))
+		return mathMLNode{markup: `<mstyle mathvariant="` + v + `">` + inner + "</mstyle>"}
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac", "binom":
+		num := c.parseArgument()
+		den := c.parseArgument()
+		if name == "binom" {
+			return mathMLNode{markup: `<mrow><mo>(</mo><mfrac linethickness="0"><mrow>` + num + "</mrow><mrow>" + den + "</mrow></mfrac><mo>)</mo></mrow>"}
+		}
+		return mathMLNode{markup: "<mfrac><mrow>" + num + "</mrow><mrow>" + den + "</mrow></mfrac>"}
+	case "sqrt":
+		c.skipSpaces()
+		if !c.eof() && c.src[c.pos] == '[' {
+			c.pos++
+			idxStart := c.pos
+			for !c.eof() && c.src[c.pos] != ']' {
+				c.pos++
+			}
+			index := c.convertNested(c.src[idxStart:c.pos])
+			if !c.eof() {
+				c.pos++
+			}
+			return mathMLNode{markup: "<mroot><mrow>" + c.parseArgument() + "</mrow><mrow>" + index + "</mrow></mroot>"}
+		}
+		return mathMLNode{markup: "<msqrt>" + c.parseArgument() + "</msqrt>"}
+	case "text", "textrm", "textit", "textbf", "mbox":
+		return mathMLNode{markup: "<mtext>" + html.EscapeString(c.parseRawArgument()) + "</mtext>"}
+	case "left", "right", "big", "Big", "bigg", "Bigg", "bigl", "bigr", "Bigl", "Bigr":
+		c.skipSpaces()
+		if c.eof() {
+			return mathMLNode{}
+		}
+		delim := c.parseAtom(depth)
+		if delim.markup == "<mo>.</mo>" {
+			return mathMLNode{}
+		}
+		if name == "left" {
+			c.leftDepth++
+			inner := c.parseList(depth)
+			c.leftDepth--
+			right := mathMLNode{}
+			if c.hasCommand("right") {
+				right = c.parseCommand(depth)
+			}
+			return mathMLNode{markup: "<mrow>" + delim.markup + inner + right.markup + "</mrow>"}
+		}
+		return delim
+	case "overline", "bar":
+		return mathMLNode{markup: `<mover accent="true"><mrow>` + c.parseArgument() + `</mrow><mo>¯</mo></mover>`}
+	case "hat", "widehat":
+		return mathMLNode{markup: `<mover accent="true"><mrow>` + c.parseArgument() + `</mrow><mo>^</mo></mover>`}
+	case "tilde", "widetilde":
+		return mathMLNode{markup: `<mover accent="true"><mrow>` + c.parseArgument() + `</mrow><mo>~</mo></mover>`}
+	case "vec":
+		return mathMLNode{markup: `<mover accent="true"><mrow>` + c.parseArgument() + `</mrow><mo>→</mo></mover>`}
+	case "dot":
+		return mathMLNode{markup: `<mover accent="true"><mrow>` + c.parseArgument() + `</mrow><mo>˙</mo></mover>`}
+	case "underline":
+		return mathMLNode{markup: `<munder><mrow>` + c.parseArgument() + `</mrow><mo>_</mo></munder>`}
+	case "\\":
+		// line breaks are not supported by MathML Core, render them as a wide space
+		return mathMLNode{markup: `<mspace width="1em"></mspace>`}
+	}
+	// environments like \begin{pmatrix} are unsupported too
+	c.unsupported = true
+	return mathMLNode{}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package math
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yuin/goldmark"
+)
+
+func TestRenderMathML(t *testing.T) {
+	wrap := func(inner, source string) string {
+		return `<math><semantics><mrow>` + inner + `</mrow><annotation encoding="application/x-tex">` + source + `</annotation></semantics></math>`
+	}
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{`a`, `<mi>a</mi>`},
+		{`x+1.5`, `<mi>x</mi><mo>+</mo><mn>1.5</mn>`},
+		{`x^2`, `<msup><mi>x</mi><mrow><mn>2</mn></mrow></msup>`},
+		{`a_{ij}^n`, `<msubsup><mi>a</mi><mrow><mrow><mi>i</mi><mi>j</mi></mrow></mrow><mrow><mi>n</mi></mrow></msubsup>`},
+		{`\frac{1}{2}`, `<mfrac><mrow><mrow><mn>1</mn></mrow></mrow><mrow><mrow><mn>2</mn></mrow></mrow></mfrac>`},
+		{`\sqrt{x}`, `<msqrt><mrow><mi>x</mi></mrow></msqrt>`},
+		{`\sqrt[3]{x}`, `<mroot><mrow><mrow><mi>x</mi></mrow></mrow><mrow><mn>3</mn></mrow></mroot>`},
+		{`\alpha \leq \beta`, `<mi>α</mi><mo>≤</mo><mi>β</mi>`},
+		{`\sin x`, `<mi>sin</mi><mi>x</mi>`},
+		{`\text{a < b}`, `<mtext>a &lt; b</mtext>`},
+		{`\left( x \right)`, `<mrow><mo>(</mo><mi>x</mi><mo>)</mo></mrow>`},
+		{`a<b`, `<mi>a</mi><mo>&lt;</mo><mi>b</mi>`},
+		{`}x`, `<mi>x</mi>`},
+	}
+	for _, c := range cases {
+		markup, ok := RenderMathML(c.source, false)
+		assert.True(t, ok, "source: %s", c.source)
+		assert.Equal(t, wrap(c.expected, html.EscapeString(c.source)), markup, "source: %s", c.source)
+	}
+
+	markup, ok := RenderMathML(`\sum_i^n`, true)
+	assert.True(t, ok)
+	assert.Equal(t,
+		`<math display="block"><semantics><mrow><munderover><mo>∑</mo><mrow><mi>i</mi></mrow><mrow><mi>n</mi></mrow></munderover></mrow><annotation encoding="application/x-tex">\sum_i^n</annotation></semantics></math>`,
+		markup)
+
+	for _, source := range []string{
+		`\unknown`,
+		`\begin{pmatrix} a & b \\ c & d \end{pmatrix}`,
+		`\mathbf{\unknown}`,
+		`\sqrt[\unknown]{x}`,
+		strings.Repeat("x", mathMLMaxSourceLength+1),
+	} {
+		_, ok := RenderMathML(source, false)
+		assert.False(t, ok, "source: %s", source)
+	}
+
+	// the length is counted in characters, not in bytes
+	_, ok = RenderMathML(strings.Repeat("α", mathMLMaxSourceLength), false)
+	assert.True(t, ok)
+}
+
+func TestRenderMathMLCached(t *testing.T) {
+	v1, _ := RenderMathMLCached([]byte("x^2"), false)
+	v2, _ := RenderMathMLCached([]byte("x^2"), false)
+	v3, _ := RenderMathMLCached([]byte("x^2"), true)
+	assert.Equal(t, v1, v2)
+	assert.NotEqual(t, v1, v3)
+	assert.NotEqual(t, mathMLCacheKey([]byte("x"), false), mathMLCacheKey([]byte("x"), true))
+
+	for range 2 {
+		_, ok := RenderMathMLCached([]byte(`\unknown`), false)
+		assert.False(t, ok)
+	}
+}
+
+func TestServerRenderFallback(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(NewExtension(Enabled(), WithServerRender())))
+	render := func(input string) string {
+		var buf bytes.Buffer
+		assert.NoError(t, md.Convert([]byte(input), &buf))
+		return buf.String()
+	}
+
+	assert.Contains(t, render("$x^2$"), "<math><semantics>")
+	assert.Equal(t,
+		`<p><code class="language-math is-loading">\begin{pmatrix} a \\ b \end{pmatrix}</code></p>`+"\n",
+		render(`$\begin{pmatrix} a \\ b \end{pmatrix}$`))
+
+	assert.Contains(t, render("$$\nx^2\n$$\n"), `<math display="block">`)
+	assert.Equal(t,
+		`<pre class="code-block is-loading"><code class="chroma language-math display">`+"\n"+`\begin{pmatrix} a \\ b \end{pmatrix}`+"\n"+`</code></pre>`+"\n",
+		render("$$\n\\begin{pmatrix} a \\\\ b \\end{pmatrix}\n$$\n"))
+}
@@ -47,6 +47,19 @@ func (st *Sanitizer) createDefaultPolicy() *bluemonday.Policy {
 	// For Chroma markdown plugin
 	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^(chroma )?language-[\w-]+( display)?( is-loading)?$`)).OnElements("code")
 
+	// For server-side rendered math (MathML)
+	mathMLElements := []string{
+		"math", "semantics", "annotation", "mrow", "mi", "mn", "mo", "mtext", "mspace", "mstyle", "merror",
+		"msub", "msup", "msubsup", "munder", "mover", "munderover", "mfrac", "msqrt", "mroot",
+	}
+	policy.AllowNoAttrs().OnElements(mathMLElements...)
+	policy.AllowAttrs("display").Matching(regexp.MustCompile(`^(block|inline)$`)).OnElements("math")
+	policy.AllowAttrs("encoding").Matching(regexp.MustCompile(`^application/x-tex$`)).OnElements("annotation")
+	policy.AllowAttrs("mathvariant").Matching(regexp.MustCompile(`^[a-z-]+$`)).OnElements("mstyle", "mi")
+	policy.AllowAttrs("width").Matching(regexp.MustCompile(`^[0-9.]+(em)?$`)).OnElements("mspace")
+	policy.AllowAttrs("linethickness").Matching(regexp.MustCompile(`^0$`)).OnElements("mfrac")
+	policy.AllowAttrs("accent").Matching(regexp.MustCompile(`^true$`)).OnElements("mover")
+
 	// Checkboxes
 	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
 	policy.AllowAttrs("checked", "disabled", "data-source-position").OnElements("input")
@@ -64,6 +64,10 @@ func TestSanitizer(t *testing.T) {
 		`<a href="javascript:alert('xss')">bad</a>`, `bad`,
 		`<a href="vbscript:no">bad</a>`, `bad`,
 		`<a href="data:1234">bad</a>`, `bad`,
+
+		// MathML
+		`<math display="block" onclick="x"><semantics><mrow><mi mathvariant="normal">a</mi></mrow><annotation encoding="application/x-tex">a</annotation></semantics></math>`, `<math display="block"><semantics><mrow><mi mathvariant="normal">a</mi></mrow><annotation encoding="application/x-tex">a</annotation></semantics></math>`,
+		`<math display="evil"><mspace width="url(x)"></mspace></math>`, `<math><mspace></mspace></math>`,
 	}
 
 	for i := 0; i < len(testCases); i += 2 {
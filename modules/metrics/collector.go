@@ -5,9 +5,13 @@ package metrics
 
 import (
 	"runtime"
+	"strings"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,10 +23,16 @@ const namespace = "gitea_"
 // exposes gitea metrics for prometheus
 type Collector struct {
 	Accesses           *prometheus.Desc
+	ActionsQueuedJobs  *prometheus.Desc
 	Attachments        *prometheus.Desc
 	BuildInfo          *prometheus.Desc
 	Comments           *prometheus.Desc
 	Follows            *prometheus.Desc
+	GitCatFileIdle     *prometheus.Desc
+	GitCatFileInUse    *prometheus.Desc
+	GitCatFileStarted  *prometheus.Desc
+	GitCatFileReused   *prometheus.Desc
+	GitCatFileClosed   *prometheus.Desc
 	HookTasks          *prometheus.Desc
 	Issues             *prometheus.Desc
 	IssuesOpen         *prometheus.Desc
@@ -56,6 +66,11 @@ func NewCollector() Collector {
 			"Number of Accesses",
 			nil, nil,
 		),
+		ActionsQueuedJobs: prometheus.NewDesc(
+			namespace+"actions_queued_jobs",
+			"Number of Actions jobs waiting for a runner with a set of labels",
+			[]string{"labels"}, nil,
+		),
 		Attachments: prometheus.NewDesc(
 			namespace+"attachments",
 			"Number of Attachments",
@@ -81,6 +96,31 @@ func NewCollector() Collector {
 			"Number of Follows",
 			nil, nil,
 		),
+		GitCatFileIdle: prometheus.NewDesc(
+			namespace+"git_catfile_processes_idle",
+			"Number of idle pooled git cat-file processes",
+			nil, nil,
+		),
+		GitCatFileInUse: prometheus.NewDesc(
+			namespace+"git_catfile_processes_in_use",
+			"Number of pooled git cat-file processes in use",
+			nil, nil,
+		),
+		GitCatFileStarted: prometheus.NewDesc(
+			namespace+"git_catfile_processes_started_total",
+			"Number of pooled git cat-file processes started",
+			nil, nil,
+		),
+		GitCatFileReused: prometheus.NewDesc(
+			namespace+"git_catfile_processes_reused_total",
+			"Number of times an idle git cat-file process has been reused",
+			nil, nil,
+		),
+		GitCatFileClosed: prometheus.NewDesc(
+			namespace+"git_catfile_processes_closed_total",
+			"Number of pooled git cat-file processes closed",
+			nil, nil,
+		),
 		HookTasks: prometheus.NewDesc(
 			namespace+"hooktasks",
 			"Number of HookTasks",
@@ -202,10 +242,16 @@ func NewCollector() Collector {
 // Describe returns all possible prometheus.Desc
 func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Accesses
+	ch <- c.ActionsQueuedJobs
 	ch <- c.Attachments
 	ch <- c.BuildInfo
 	ch <- c.Comments
 	ch <- c.Follows
+	ch <- c.GitCatFileIdle
+	ch <- c.GitCatFileInUse
+	ch <- c.GitCatFileStarted
+	ch <- c.GitCatFileReused
+	ch <- c.GitCatFileClosed
 	ch <- c.HookTasks
 	ch <- c.Issues
 	ch <- c.IssuesByLabel
@@ -240,6 +286,20 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Access),
 	)
+	if setting.Actions.Enabled {
+		queued, err := actions_model.GetQueuedJobs(db.DefaultContext, 0, 0)
+		if err != nil {
+			log.Error("GetQueuedJobs: %v", err)
+		}
+		for _, q := range queued {
+			ch <- prometheus.MustNewConstMetric(
+				c.ActionsQueuedJobs,
+				prometheus.GaugeValue,
+				float64(q.Count),
+				strings.Join(q.Labels, ","),
+			)
+		}
+	}
 	ch <- prometheus.MustNewConstMetric(
 		c.Attachments,
 		prometheus.GaugeValue,
@@ -264,6 +324,33 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Follow),
 	)
+
+	catFileStats := git.GetCatFilePoolStats()
+	ch <- prometheus.MustNewConstMetric(
+		c.GitCatFileIdle,
+		prometheus.GaugeValue,
+		float64(catFileStats.Idle),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.GitCatFileInUse,
+		prometheus.GaugeValue,
+		float64(catFileStats.InUse),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.GitCatFileStarted,
+		prometheus.CounterValue,
+		float64(catFileStats.Started),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.GitCatFileReused,
+		prometheus.CounterValue,
+		float64(catFileStats.Reused),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.GitCatFileClosed,
+		prometheus.CounterValue,
+		float64(catFileStats.Closed),
+	)
 	ch <- prometheus.MustNewConstMetric(
 		c.HookTasks,
 		prometheus.GaugeValue,
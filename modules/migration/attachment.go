@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migration
+
+import (
+	"io"
+	"time"
+)
+
+// Attachment represents a file attached to an issue or a comment
+type Attachment struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+
+	DownloadURL string `yaml:"download_url" json:"download_url"`
+	// the content is only uploaded by the function, the DownloadURL isn't opened by the uploader
+	DownloadFunc func() (io.ReadCloser, error) `yaml:"-" json:"-"` // SECURITY: It is the responsibility of downloader to make sure this is safe
+}
@@ -24,7 +24,11 @@ func Load(filename string, data any, validation bool) error {
 	if err != nil {
 		return err
 	}
+	return Decode(bs, data, isJSON, validation)
+}
 
+// Decode project data from JSON or YAML, with optional validation
+func Decode(bs []byte, data any, isJSON, validation bool) error {
 	if validation {
 		err := validate(bs, data, isJSON)
 		if err != nil {
@@ -66,6 +70,8 @@ func validate(bs []byte, datatype any, isJSON bool) error {
 		schemaFilename = "issue.json"
 	case *[]*Milestone:
 		schemaFilename = "milestone.json"
+	case *IssueBundle:
+		schemaFilename = "issue_bundle.json"
 	default:
 		return fmt.Errorf("file_format:validate: %T has not a validation implemented", datatype)
 	}
@@ -24,8 +24,9 @@ type Issue struct {
 	Labels       []*Label          `json:"labels"`
 	Reactions    []*Reaction       `json:"reactions"`
 	Assignees    []string          `json:"assignees"`
+	Attachments  []*Attachment     `yaml:"attachments,omitempty" json:"attachments,omitempty"`
 	ForeignIndex int64             `json:"foreign_id"`
-	Context      DownloaderContext `yaml:"-"`
+	Context      DownloaderContext `yaml:"-" json:"-"`
 }
 
 // GetExternalName ExternalUserMigrated interface
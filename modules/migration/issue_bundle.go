@@ -0,0 +1,164 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migration
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IssueBundle is the format of the bulk import and export of the issues of a repository,
+// see schemas/issue_bundle.json
+type IssueBundle struct {
+	Labels     []*Label     `json:"labels"`
+	Milestones []*Milestone `json:"milestones"`
+	Issues     []*Issue     `json:"issues"`
+	Comments   []*Comment   `json:"comments"`
+}
+
+// IssueCSVHeader are the columns of the issues in the CSV format, which has a row for each issue
+// without their comments and attachments, the labels and assignees are separated by commas
+var IssueCSVHeader = []string{
+	"number", "title", "state", "poster_id", "poster_name", "labels", "milestone", "assignees",
+	"is_locked", "created", "updated", "closed", "content",
+}
+
+// WriteIssuesCSV writes the issues in the CSV format
+func WriteIssuesCSV(w io.Writer, issues []*Issue) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(IssueCSVHeader); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		labels := make([]string, 0, len(issue.Labels))
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		var closed string
+		if issue.Closed != nil {
+			closed = issue.Closed.Format(time.RFC3339)
+		}
+		if err := writer.Write([]string{
+			strconv.FormatInt(issue.Number, 10),
+			issue.Title,
+			issue.State,
+			strconv.FormatInt(issue.PosterID, 10),
+			issue.PosterName,
+			strings.Join(labels, ", "),
+			issue.Milestone,
+			strings.Join(issue.Assignees, ", "),
+			strconv.FormatBool(issue.IsLocked),
+			issue.Created.Format(time.RFC3339),
+			issue.Updated.Format(time.RFC3339),
+			closed,
+			issue.Content,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadIssuesCSV reads the issues in the CSV format, the columns are found by the header
+// and only the number and the title are required
+func ReadIssuesCSV(r io.Reader) ([]*Issue, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"number", "title"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var issues []*Issue
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		value := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		parseTime := func(name string) (time.Time, error) {
+			if v := value(name); v != "" {
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					return time.Time{}, fmt.Errorf("line %d: invalid %s: %w", line, name, err)
+				}
+				return t, nil
+			}
+			return time.Time{}, nil
+		}
+
+		issue := &Issue{
+			Title:      value("title"),
+			State:      value("state"),
+			PosterName: value("poster_name"),
+			Milestone:  value("milestone"),
+		}
+		if i, ok := columns["content"]; ok {
+			issue.Content = record[i] // the spaces are kept in the markdown
+		}
+		if issue.Number, err = strconv.ParseInt(value("number"), 10, 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid number: %w", line, err)
+		}
+		if v := value("poster_id"); v != "" {
+			if issue.PosterID, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid poster_id: %w", line, err)
+			}
+		}
+		if v := value("is_locked"); v != "" {
+			if issue.IsLocked, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid is_locked: %w", line, err)
+			}
+		}
+		for _, name := range splitCSVList(value("labels")) {
+			issue.Labels = append(issue.Labels, &Label{Name: name})
+		}
+		issue.Assignees = splitCSVList(value("assignees"))
+		if issue.Created, err = parseTime("created"); err != nil {
+			return nil, err
+		}
+		if issue.Updated, err = parseTime("updated"); err != nil {
+			return nil, err
+		}
+		closed, err := parseTime("closed")
+		if err != nil {
+			return nil, err
+		}
+		if !closed.IsZero() {
+			issue.Closed = &closed
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func splitCSVList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migration
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueBundle_Decode(t *testing.T) {
+	bs, err := os.ReadFile("file_format_testdata/issue_bundle.json")
+	require.NoError(t, err)
+
+	var bundle IssueBundle
+	require.NoError(t, Decode(bs, &bundle, true, true))
+	if assert.Len(t, bundle.Issues, 1) {
+		assert.Equal(t, "title_a", bundle.Issues[0].Title)
+		assert.Equal(t, "bug", bundle.Issues[0].Labels[0].Name)
+		assert.Equal(t, "https://example.com/attachments/screenshot.png", bundle.Issues[0].Attachments[0].DownloadURL)
+	}
+	if assert.Len(t, bundle.Comments, 1) {
+		assert.EqualValues(t, 1, bundle.Comments[0].IssueIndex)
+		assert.Equal(t, "+1", bundle.Comments[0].Reactions[0].Content)
+	}
+
+	// the comments must reference their issue
+	bs = []byte(strings.Replace(string(bs), `"issue_index": 1,`, "", 1))
+	assert.Error(t, Decode(bs, &bundle, true, true))
+}
+
+func TestIssuesCSV(t *testing.T) {
+	closed := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	issues := []*Issue{
+		{
+			Number:     1,
+			Title:      "first, with a comma",
+			State:      "open",
+			PosterID:   2,
+			PosterName: "user2",
+			Labels:     []*Label{{Name: "bug"}, {Name: "kind/ui"}},
+			Milestone:  "v1.0",
+			Assignees:  []string{"user1", "user2"},
+			Created:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Updated:    time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+			Content:    "multi\nline \"content\"\n",
+		},
+		{
+			Number:   2,
+			Title:    "second",
+			State:    "closed",
+			IsLocked: true,
+			Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Updated:  closed,
+			Closed:   &closed,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteIssuesCSV(&buf, issues))
+	assert.True(t, strings.HasPrefix(buf.String(), strings.Join(IssueCSVHeader, ",")+"\n"))
+
+	read, err := ReadIssuesCSV(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, issues, read)
+
+	// only the number and the title are required, and the columns can be in any order
+	read, err = ReadIssuesCSV(strings.NewReader("title,number\nhello,3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []*Issue{{Number: 3, Title: "hello"}}, read)
+
+	_, err = ReadIssuesCSV(strings.NewReader("title\nhello\n"))
+	assert.ErrorContains(t, err, `missing column "number"`)
+	_, err = ReadIssuesCSV(strings.NewReader("number,title,created\n1,hello,yesterday\n"))
+	assert.ErrorContains(t, err, "line 2: invalid created")
+}
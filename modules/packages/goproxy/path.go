@@ -0,0 +1,46 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package goproxy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EscapePath escapes the upper case letters of a module path or version the way the proxy protocol expects it
+// https://go.dev/ref/mod#goproxy-protocol
+func EscapePath(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// UnescapePath reverts EscapePath, the letters following an exclamation mark are upper cased
+func UnescapePath(s string) string {
+	if !strings.Contains(s, "!") {
+		return s
+	}
+
+	var sb strings.Builder
+	upper := false
+	for _, r := range s {
+		switch {
+		case r == '!':
+			upper = true
+		case upper:
+			sb.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
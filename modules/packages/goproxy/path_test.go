@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package goproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapePath(t *testing.T) {
+	cases := map[string]string{
+		"gitea.com/go-gitea/gitea":     "gitea.com/go-gitea/gitea",
+		"github.com/BurntSushi/toml":   "github.com/!burnt!sushi/toml",
+		"github.com/Azure/azure-sdk":   "github.com/!azure/azure-sdk",
+		"v1.0.0-RC1":                   "v1.0.0-!r!c1",
+		"github.com/!burnt!sushi/toml": "github.com/!burnt!sushi/toml",
+	}
+	for path, escaped := range cases {
+		assert.Equal(t, escaped, EscapePath(path))
+	}
+}
+
+func TestUnescapePath(t *testing.T) {
+	cases := map[string]string{
+		"gitea.com/go-gitea/gitea":     "gitea.com/go-gitea/gitea",
+		"github.com/!burnt!sushi/toml": "github.com/BurntSushi/toml",
+		"v1.0.0-!r!c1":                 "v1.0.0-RC1",
+	}
+	for escaped, path := range cases {
+		assert.Equal(t, path, UnescapePath(escaped))
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package julia
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+)
+
+var versionPrefixPattern = regexp.MustCompile(`\A(0|[1-9][0-9]*)(?:\.(0|[1-9][0-9]*))?(?:\.(0|[1-9][0-9]*))?\z`)
+
+// parseVersionPrefix parses a version like 1, 1.2 or 1.2.3 and returns its parts
+func parseVersionPrefix(s string) ([]int, bool) {
+	m := versionPrefixPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, false
+	}
+	parts := make([]int, 0, 3)
+	for _, p := range m[1:] {
+		if p == "" {
+			break
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+func joinVersionParts(parts []int) string {
+	s := make([]string, 0, len(parts))
+	for _, p := range parts {
+		s = append(s, strconv.Itoa(p))
+	}
+	return strings.Join(s, ".")
+}
+
+func padVersionParts(parts []int) []int {
+	padded := append([]int{}, parts...)
+	for len(padded) < 3 {
+		padded = append(padded, 0)
+	}
+	return padded
+}
+
+// CompatRanges converts a compat specifier of a Project.toml file to the version ranges used in the registry,
+// like "^1.2, 0.5" to ["1.2-1", "0.5-0.5"].
+// https://pkgdocs.julialang.org/v1/compatibility/
+func CompatRanges(spec string) ([]string, error) {
+	invalid := util.NewInvalidArgumentErrorf("compat specifier %q is invalid", spec)
+
+	ranges := make([]string, 0, 2)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+
+		if lower, upper, ok := strings.Cut(part, " - "); ok {
+			l, ok1 := parseVersionPrefix(lower)
+			u, ok2 := parseVersionPrefix(upper)
+			if !ok1 || !ok2 {
+				return nil, invalid
+			}
+			ranges = append(ranges, joinVersionParts(l)+"-"+joinVersionParts(u))
+			continue
+		}
+
+		var op string
+		for _, prefix := range []string{">=", "≥", "^", "~", "=", "<"} {
+			if strings.HasPrefix(part, prefix) {
+				op = prefix
+				part = strings.TrimPrefix(part, prefix)
+				break
+			}
+		}
+
+		parts, ok := parseVersionPrefix(part)
+		if !ok {
+			return nil, invalid
+		}
+		v := joinVersionParts(parts)
+
+		switch op {
+		case "", "^":
+			// the first non-zero part (or the last given part) must not change
+			upper := len(parts)
+			for i, p := range parts {
+				if p != 0 {
+					upper = i + 1
+					break
+				}
+			}
+			ranges = append(ranges, v+"-"+joinVersionParts(parts[:upper]))
+		case "~":
+			upper := min(len(parts), 2)
+			if len(parts) == 3 && parts[0] == 0 && parts[1] == 0 {
+				upper = 3
+			}
+			ranges = append(ranges, v+"-"+joinVersionParts(parts[:upper]))
+		case "=":
+			ranges = append(ranges, joinVersionParts(padVersionParts(parts)))
+		case ">=", "≥":
+			ranges = append(ranges, v+"-*")
+		case "<":
+			// the highest version below the bound, the prefix notation includes all its patch versions
+			upper := padVersionParts(parts)
+			for i := len(parts) - 1; i >= 0; i-- {
+				if upper[i] > 0 {
+					upper[i]--
+					ranges = append(ranges, "0-"+joinVersionParts(upper[:i+1]))
+					break
+				}
+			}
+		}
+	}
+	return ranges, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package julia
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/google/uuid"
+	"github.com/pelletier/go-toml/v2"
+)
+
+const (
+	PropertyUUID     = "julia.uuid"
+	PropertyTreeHash = "julia.tree_hash"
+
+	maxProjectFileSize = 1 << 20
+)
+
+var (
+	ErrInvalidArchive      = util.NewInvalidArgumentErrorf("package archive is invalid")
+	ErrMissingProjectFile  = util.NewInvalidArgumentErrorf("Project.toml file is missing")
+	ErrInvalidName         = util.NewInvalidArgumentErrorf("package name is invalid")
+	ErrInvalidUUID         = util.NewInvalidArgumentErrorf("package uuid is invalid")
+	ErrInvalidVersion      = util.NewInvalidArgumentErrorf("package version is invalid")
+	ErrInvalidPath         = util.NewInvalidArgumentErrorf("package contains an invalid path")
+	ErrProjectFileTooLarge = util.NewInvalidArgumentErrorf("Project.toml file is too large")
+)
+
+var (
+	namePattern    = regexp.MustCompile(`\A[A-Za-z_][A-Za-z0-9_]*\z`)
+	versionPattern = regexp.MustCompile(`\A(?:0|[1-9][0-9]*)\.(?:0|[1-9][0-9]*)\.(?:0|[1-9][0-9]*)(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?\z`)
+)
+
+// Package represents a Julia package
+type Package struct {
+	Name     string
+	UUID     string
+	Version  string
+	TreeHash string
+	Metadata *Metadata
+}
+
+// Metadata represents the metadata of a Julia package
+type Metadata struct {
+	Authors  []string          `json:"authors,omitempty"`
+	Deps     map[string]string `json:"deps,omitempty"`
+	WeakDeps map[string]string `json:"weak_deps,omitempty"`
+	Compat   map[string]string `json:"compat,omitempty"`
+}
+
+type projectFile struct {
+	Name     string            `toml:"name"`
+	UUID     string            `toml:"uuid"`
+	Version  string            `toml:"version"`
+	Authors  []string          `toml:"authors"`
+	Deps     map[string]string `toml:"deps"`
+	WeakDeps map[string]string `toml:"weakdeps"`
+	Compat   map[string]string `toml:"compat"`
+}
+
+// ParsePackage reads the package metadata from the .tar.gz archive of a Julia package.
+// The Project.toml file must be in the root of the archive. The git tree hash of the content is computed too.
+func ParsePackage(r io.Reader) (*Package, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	defer gzr.Close()
+
+	// JuliaProject.toml takes precedence over Project.toml
+	var project, juliaProject []byte
+
+	t := newTree()
+
+	tr := tar.NewReader(gzr)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidArchive
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+hd.Name), "/")
+		if name == "" {
+			continue
+		}
+		if strings.HasPrefix(hd.Name, "/") || slices.Contains(strings.Split(hd.Name, "/"), "..") {
+			return nil, ErrInvalidPath
+		}
+
+		switch hd.Typeflag {
+		case tar.TypeDir:
+			t.dirOf(name)
+		case tar.TypeSymlink:
+			hash, err := blobHash(strings.NewReader(hd.Linkname), int64(len(hd.Linkname)))
+			if err != nil {
+				return nil, err
+			}
+			t.add(name, modeSymlink, hash)
+		case tar.TypeReg:
+			var content io.Reader = tr
+			var buf *bytes.Buffer
+			if name == "Project.toml" || name == "JuliaProject.toml" {
+				if hd.Size > maxProjectFileSize {
+					return nil, ErrProjectFileTooLarge
+				}
+				buf = &bytes.Buffer{}
+				content = io.TeeReader(tr, buf)
+			}
+
+			hash, err := blobHash(content, hd.Size)
+			if err != nil {
+				return nil, ErrInvalidArchive
+			}
+
+			mode := modeFile
+			if hd.Mode&0o100 != 0 {
+				mode = modeExecutable
+			}
+			t.add(name, mode, hash)
+
+			if buf != nil {
+				if name == "JuliaProject.toml" {
+					juliaProject = buf.Bytes()
+				} else {
+					project = buf.Bytes()
+				}
+			}
+		default:
+			// hardlinks and special files can't be represented in a git tree
+			return nil, ErrInvalidPath
+		}
+	}
+
+	if juliaProject != nil {
+		project = juliaProject
+	}
+	if project == nil {
+		return nil, ErrMissingProjectFile
+	}
+
+	p, err := ParseProjectFile(bytes.NewReader(project))
+	if err != nil {
+		return nil, err
+	}
+	p.TreeHash = t.hexHash()
+
+	return p, nil
+}
+
+// ParseProjectFile parses a Project.toml file to retrieve the metadata of a Julia package
+func ParseProjectFile(r io.Reader) (*Package, error) {
+	var pf projectFile
+	if err := toml.NewDecoder(r).Decode(&pf); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("Project.toml file is invalid: %v", err)
+	}
+
+	if !namePattern.MatchString(pf.Name) {
+		return nil, ErrInvalidName
+	}
+	if _, err := uuid.Parse(pf.UUID); err != nil {
+		return nil, ErrInvalidUUID
+	}
+	if !versionPattern.MatchString(pf.Version) {
+		return nil, ErrInvalidVersion
+	}
+
+	for _, spec := range pf.Compat {
+		if _, err := CompatRanges(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Package{
+		Name:    pf.Name,
+		UUID:    strings.ToLower(pf.UUID),
+		Version: pf.Version,
+		Metadata: &Metadata{
+			Authors:  pf.Authors,
+			Deps:     pf.Deps,
+			WeakDeps: pf.WeakDeps,
+			Compat:   pf.Compat,
+		},
+	}, nil
+}
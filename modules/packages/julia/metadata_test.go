@@ -0,0 +1,265 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package julia
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	packageName    = "Example"
+	packageUUID    = "7876af07-990d-54b4-ab0e-23690620f79a"
+	packageVersion = "0.5.4"
+	packageAuthor  = "Jane Doe <jane@example.com>"
+)
+
+const projectFileContent = `name = "` + packageName + `"
+uuid = "` + packageUUID + `"
+version = "` + packageVersion + `"
+authors = ["` + packageAuthor + `"]
+
+[deps]
+LinearAlgebra = "37e2e46d-f89d-539d-b4ee-838fcccc9c8e"
+
+[compat]
+julia = "1.6"
+`
+
+type archiveEntry struct {
+	Name    string
+	Content string
+	Mode    int64
+	Type    byte
+}
+
+func createArchive(entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Typeflag: e.Type,
+			Name:     e.Name,
+			Mode:     e.Mode,
+		}
+		if e.Type == tar.TypeReg {
+			hdr.Size = int64(len(e.Content))
+		}
+		if e.Type == tar.TypeSymlink || e.Type == tar.TypeLink {
+			hdr.Linkname = e.Content
+		}
+		tw.WriteHeader(hdr)
+		if e.Type == tar.TypeReg {
+			tw.Write([]byte(e.Content))
+		}
+	}
+	tw.Close()
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestParsePackage(t *testing.T) {
+	t.Run("InvalidArchive", func(t *testing.T) {
+		p, err := ParsePackage(strings.NewReader("not an archive"))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidArchive)
+	})
+
+	t.Run("MissingProjectFile", func(t *testing.T) {
+		data := createArchive(archiveEntry{Name: "README.md", Content: "readme", Mode: 0o644, Type: tar.TypeReg})
+
+		p, err := ParsePackage(bytes.NewReader(data))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrMissingProjectFile)
+	})
+
+	t.Run("InvalidPath", func(t *testing.T) {
+		data := createArchive(
+			archiveEntry{Name: "Project.toml", Content: projectFileContent, Mode: 0o644, Type: tar.TypeReg},
+			archiveEntry{Name: "../evil", Content: "evil", Mode: 0o644, Type: tar.TypeReg},
+		)
+
+		p, err := ParsePackage(bytes.NewReader(data))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidPath)
+	})
+
+	t.Run("Hardlink", func(t *testing.T) {
+		data := createArchive(
+			archiveEntry{Name: "Project.toml", Content: projectFileContent, Mode: 0o644, Type: tar.TypeReg},
+			archiveEntry{Name: "link", Content: "Project.toml", Type: tar.TypeLink},
+		)
+
+		p, err := ParsePackage(bytes.NewReader(data))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidPath)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		data := createArchive(
+			archiveEntry{Name: "./", Mode: 0o755, Type: tar.TypeDir},
+			archiveEntry{Name: "./Project.toml", Content: projectFileContent, Mode: 0o644, Type: tar.TypeReg},
+			archiveEntry{Name: "./src/", Mode: 0o755, Type: tar.TypeDir},
+			archiveEntry{Name: "./src/Example.jl", Content: "module Example\nend\n", Mode: 0o644, Type: tar.TypeReg},
+			archiveEntry{Name: "./run.sh", Content: "#!/bin/sh\n", Mode: 0o755, Type: tar.TypeReg},
+			archiveEntry{Name: "./empty/", Mode: 0o755, Type: tar.TypeDir},
+		)
+
+		p, err := ParsePackage(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+
+		assert.Equal(t, packageName, p.Name)
+		assert.Equal(t, packageUUID, p.UUID)
+		assert.Equal(t, packageVersion, p.Version)
+		// same as "git rev-parse HEAD^{tree}" of a repository with these files
+		assert.Equal(t, "e569871287649ac217c386ac9e14d9f009bff3f0", p.TreeHash)
+		assert.NotNil(t, p.Metadata)
+		assert.Equal(t, []string{packageAuthor}, p.Metadata.Authors)
+		assert.Equal(t, map[string]string{"LinearAlgebra": "37e2e46d-f89d-539d-b4ee-838fcccc9c8e"}, p.Metadata.Deps)
+		assert.Equal(t, map[string]string{"julia": "1.6"}, p.Metadata.Compat)
+	})
+}
+
+func TestParseProjectFile(t *testing.T) {
+	createProjectFile := func(name, uuid, version string) io.Reader {
+		content := strings.Replace(projectFileContent, packageName, name, 1)
+		content = strings.Replace(content, packageUUID, uuid, 1)
+		content = strings.Replace(content, packageVersion, version, 1)
+		return strings.NewReader(content)
+	}
+
+	t.Run("InvalidName", func(t *testing.T) {
+		for _, name := range []string{"", "1Example", "Exa-mple", "Example.jl"} {
+			p, err := ParseProjectFile(createProjectFile(name, packageUUID, packageVersion))
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidName)
+		}
+	})
+
+	t.Run("InvalidUUID", func(t *testing.T) {
+		p, err := ParseProjectFile(createProjectFile(packageName, "not-a-uuid", packageVersion))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidUUID)
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		for _, version := range []string{"", "1.0", "v1.0.0", "1.0.0.0"} {
+			p, err := ParseProjectFile(createProjectFile(packageName, packageUUID, version))
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidVersion)
+		}
+	})
+
+	t.Run("InvalidCompat", func(t *testing.T) {
+		p, err := ParseProjectFile(strings.NewReader(projectFileContent + "Dep = \"abc\"\n"))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		p, err := ParseProjectFile(createProjectFile(packageName, strings.ToUpper(packageUUID), packageVersion))
+		assert.NoError(t, err)
+		assert.Equal(t, packageUUID, p.UUID)
+	})
+}
+
+func TestCompatRanges(t *testing.T) {
+	cases := map[string][]string{
+		"1":              {"1-1"},
+		"1.2":            {"1.2-1"},
+		"1.2.3":          {"1.2.3-1"},
+		"0.2":            {"0.2-0.2"},
+		"0.2.3":          {"0.2.3-0.2"},
+		"0.0.3":          {"0.0.3-0.0.3"},
+		"^1.2, 0.5":      {"1.2-1", "0.5-0.5"},
+		"~1.2.3":         {"1.2.3-1.2"},
+		"~1":             {"1-1"},
+		"~0.0.3":         {"0.0.3-0.0.3"},
+		"=1.2":           {"1.2.0"},
+		">= 1.2":         {"1.2-*"},
+		"≥1.2":           {"1.2-*"},
+		"<2":             {"0-1"},
+		"<1.2.0":         {"0-1.1"},
+		"1.2.3 - 4.5":    {"1.2.3-4.5"},
+		"1.6, 1.7 - 1.9": {"1.6-1", "1.7-1.9"},
+	}
+	for spec, expected := range cases {
+		ranges, err := CompatRanges(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expected, ranges, spec)
+	}
+
+	for _, spec := range []string{"", "abc", "1.x", "^", "1.2.3.4"} {
+		_, err := CompatRanges(spec)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument, spec)
+	}
+}
+
+func TestBuildRegistry(t *testing.T) {
+	r := &Registry{
+		Name: "Test",
+		UUID: "23338594-aafe-5451-b93e-139f81909106",
+		Packages: []*RegistryPackage{
+			{
+				Name: packageName,
+				UUID: packageUUID,
+				Versions: []*RegistryVersion{
+					{
+						Version:  packageVersion,
+						TreeHash: "e569871287649ac217c386ac9e14d9f009bff3f0",
+						Metadata: &Metadata{
+							Deps:     map[string]string{"LinearAlgebra": "37e2e46d-f89d-539d-b4ee-838fcccc9c8e"},
+							WeakDeps: map[string]string{"Weak": "a4d3c8fc-a50c-4623-92a3-3e1b6a59b5e1"},
+							Compat:   map[string]string{"julia": "1.6", "Weak": "0.3"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	a, err := BuildRegistry(r)
+	require.NoError(t, err)
+	assert.Len(t, a.TreeHash, 40)
+	assert.Contains(t, a.files, "Registry.toml")
+	assert.Contains(t, a.files, "E/Example/Package.toml")
+	assert.Contains(t, a.files, "E/Example/Versions.toml")
+	assert.Contains(t, a.files, "E/Example/Deps.toml")
+	assert.Contains(t, string(a.files["E/Example/Compat.toml"]), "julia")
+	assert.NotContains(t, string(a.files["E/Example/Compat.toml"]), "Weak")
+	assert.Contains(t, string(a.files["E/Example/WeakCompat.toml"]), "0.3-0.3")
+
+	var buf1, buf2 bytes.Buffer
+	assert.NoError(t, a.Write(&buf1))
+	assert.NoError(t, a.Write(&buf2))
+	assert.Equal(t, buf1.Bytes(), buf2.Bytes())
+
+	// the tree hash of the written archive must match
+	zr, err := gzip.NewReader(&buf1)
+	require.NoError(t, err)
+	tr := tar.NewReader(zr)
+	tree := newTree()
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		hash, err := blobHash(tr, hd.Size)
+		require.NoError(t, err)
+		tree.add(hd.Name, modeFile, hash)
+	}
+	assert.Equal(t, a.TreeHash, tree.hexHash())
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package julia
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Registry represents a registry of Julia packages
+// https://pkgdocs.julialang.org/v1/registries/
+type Registry struct {
+	Name        string
+	UUID        string
+	Repo        string
+	Description string
+	Packages    []*RegistryPackage
+}
+
+// RegistryPackage represents a package of a registry
+type RegistryPackage struct {
+	Name     string
+	UUID     string
+	Repo     string
+	Versions []*RegistryVersion
+}
+
+// RegistryVersion represents a version of a registry package
+type RegistryVersion struct {
+	Version  string
+	TreeHash string
+	Metadata *Metadata
+}
+
+// RegistryArchive contains the files of a registry and their git tree hash
+type RegistryArchive struct {
+	TreeHash string
+	files    map[string][]byte
+}
+
+type registryFile struct {
+	Name        string                         `toml:"name"`
+	UUID        string                         `toml:"uuid"`
+	Repo        string                         `toml:"repo,omitempty"`
+	Description string                         `toml:"description,omitempty"`
+	Packages    map[string]registryFilePackage `toml:"packages"`
+}
+
+type registryFilePackage struct {
+	Name string `toml:"name"`
+	Path string `toml:"path"`
+}
+
+type packageFile struct {
+	Name string `toml:"name"`
+	UUID string `toml:"uuid"`
+	Repo string `toml:"repo,omitempty"`
+}
+
+type versionsFileEntry struct {
+	GitTreeSha1 string `toml:"git-tree-sha1"`
+}
+
+// BuildRegistry creates the files of the registry.
+// The dependencies and compatibility constraints are listed per version instead of compressed version ranges.
+func BuildRegistry(r *Registry) (*RegistryArchive, error) {
+	a := &RegistryArchive{
+		files: make(map[string][]byte),
+	}
+
+	rf := registryFile{
+		Name:        r.Name,
+		UUID:        r.UUID,
+		Repo:        r.Repo,
+		Description: r.Description,
+		Packages:    make(map[string]registryFilePackage, len(r.Packages)),
+	}
+
+	for _, p := range r.Packages {
+		dir := PackagePath(p.Name)
+
+		rf.Packages[p.UUID] = registryFilePackage{
+			Name: p.Name,
+			Path: dir,
+		}
+
+		if err := a.addTomlFile(dir+"/Package.toml", &packageFile{Name: p.Name, UUID: p.UUID, Repo: p.Repo}); err != nil {
+			return nil, err
+		}
+
+		versions := make(map[string]versionsFileEntry, len(p.Versions))
+		deps := make(map[string]map[string]string)
+		compat := make(map[string]map[string][]string)
+		weakDeps := make(map[string]map[string]string)
+		weakCompat := make(map[string]map[string][]string)
+
+		for _, v := range p.Versions {
+			versions[v.Version] = versionsFileEntry{GitTreeSha1: v.TreeHash}
+
+			if v.Metadata == nil {
+				continue
+			}
+
+			if len(v.Metadata.Deps) > 0 {
+				deps[v.Version] = v.Metadata.Deps
+			}
+			if len(v.Metadata.WeakDeps) > 0 {
+				weakDeps[v.Version] = v.Metadata.WeakDeps
+			}
+
+			for name, spec := range v.Metadata.Compat {
+				ranges, err := CompatRanges(spec)
+				if err != nil {
+					return nil, err
+				}
+
+				target := compat
+				if _, ok := v.Metadata.WeakDeps[name]; ok {
+					target = weakCompat
+				}
+				if target[v.Version] == nil {
+					target[v.Version] = make(map[string][]string)
+				}
+				target[v.Version][name] = ranges
+			}
+		}
+
+		if err := a.addTomlFile(dir+"/Versions.toml", versions); err != nil {
+			return nil, err
+		}
+		for _, f := range []struct {
+			Name    string
+			Content any
+			IsEmpty bool
+		}{
+			{"Deps.toml", deps, len(deps) == 0},
+			{"Compat.toml", compat, len(compat) == 0},
+			{"WeakDeps.toml", weakDeps, len(weakDeps) == 0},
+			{"WeakCompat.toml", weakCompat, len(weakCompat) == 0},
+		} {
+			if f.IsEmpty {
+				continue
+			}
+			if err := a.addTomlFile(dir+"/"+f.Name, f.Content); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := a.addTomlFile("Registry.toml", &rf); err != nil {
+		return nil, err
+	}
+
+	t := newTree()
+	for name, content := range a.files {
+		hash, err := blobHash(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return nil, err
+		}
+		t.add(name, modeFile, hash)
+	}
+	a.TreeHash = t.hexHash()
+
+	return a, nil
+}
+
+// PackagePath returns the path of the package in the registry, like "E/Example"
+func PackagePath(name string) string {
+	return strings.ToUpper(name[:1]) + "/" + name
+}
+
+func (a *RegistryArchive) addTomlFile(name string, v any) error {
+	content, err := toml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	a.files[name] = content
+	return nil
+}
+
+// Write writes the registry as .tar.gz archive. The archive is always the same for the same files.
+func (a *RegistryArchive) Write(w io.Writer) error {
+	names := make([]string, 0, len(a.files))
+	for name := range a.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+
+	for _, name := range names {
+		content := a.files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			ModTime:  time.Unix(0, 0),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
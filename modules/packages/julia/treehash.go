@@ -0,0 +1,134 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package julia
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// git modes of the tree entries
+const (
+	modeFile       = "100644"
+	modeExecutable = "100755"
+	modeSymlink    = "120000"
+	modeDirectory  = "40000"
+)
+
+// tree computes the git tree hash of a directory like Pkg does to verify the content of packages and registries.
+// Empty directories and .git directories are skipped.
+type tree struct {
+	entries map[string]*treeEntry
+}
+
+type treeEntry struct {
+	mode string
+	hash []byte
+	tree *tree
+}
+
+func newTree() *tree {
+	return &tree{entries: make(map[string]*treeEntry)}
+}
+
+// blobHash returns the git blob hash of the content
+func blobHash(r io.Reader, size int64) ([]byte, error) {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", size)
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return nil, err
+	}
+	if n != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return h.Sum(nil), nil
+}
+
+// add adds the blob at the slash separated path, the missing parent directories are created
+func (t *tree) add(p, mode string, hash []byte) {
+	parts := strings.Split(p, "/")
+	for _, part := range parts[:len(parts)-1] {
+		t = t.dir(part)
+	}
+	t.entries[parts[len(parts)-1]] = &treeEntry{mode: mode, hash: hash}
+}
+
+// dir returns the sub directory, it's created if it doesn't exist
+func (t *tree) dir(name string) *tree {
+	e, ok := t.entries[name]
+	if !ok || e.tree == nil {
+		e = &treeEntry{mode: modeDirectory, tree: newTree()}
+		t.entries[name] = e
+	}
+	return e.tree
+}
+
+// dirOf returns the sub directory at the slash separated path
+func (t *tree) dirOf(p string) *tree {
+	for _, part := range strings.Split(p, "/") {
+		t = t.dir(part)
+	}
+	return t
+}
+
+// hash returns the git tree hash, nil is returned for a tree without files
+func (t *tree) hash() []byte {
+	type namedEntry struct {
+		name  string
+		entry *treeEntry
+	}
+
+	entries := make([]namedEntry, 0, len(t.entries))
+	for name, e := range t.entries {
+		if name == ".git" {
+			continue
+		}
+		if e.tree != nil {
+			e.hash = e.tree.hash()
+			if e.hash == nil {
+				continue
+			}
+		}
+		entries = append(entries, namedEntry{name, e})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// git compares directories as if their names end with a slash
+	sortName := func(ne namedEntry) string {
+		if ne.entry.tree != nil {
+			return ne.name + "/"
+		}
+		return ne.name
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return sortName(entries[i]) < sortName(entries[j])
+	})
+
+	var content bytes.Buffer
+	for _, ne := range entries {
+		content.WriteString(ne.entry.mode)
+		content.WriteByte(' ')
+		content.WriteString(ne.name)
+		content.WriteByte(0)
+		content.Write(ne.entry.hash)
+	}
+
+	h := sha1.New()
+	fmt.Fprintf(h, "tree %d\x00", content.Len())
+	h.Write(content.Bytes())
+	return h.Sum(nil)
+}
+
+// hexHash returns the tree hash as hex string
+func (t *tree) hexHash() string {
+	return hex.EncodeToString(t.hash())
+}
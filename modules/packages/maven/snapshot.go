@@ -0,0 +1,142 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package maven
+
+import (
+	"encoding/xml"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const snapshotSuffix = "-SNAPSHOT"
+
+var snapshotBuildPattern = regexp.MustCompile(`\A(\d{8}\.\d{6})-(\d+)(?:-([^.]+))?\.(.+)\z`)
+
+// SnapshotFile is a file of a build of a SNAPSHOT version like my-project-1.0-20240102.150405-3-sources.jar
+type SnapshotFile struct {
+	ArtifactID  string
+	Timestamp   string
+	BuildNumber int
+	Classifier  string
+	Extension   string
+}
+
+// IsSnapshotVersion checks if the version is a SNAPSHOT version
+func IsSnapshotVersion(version string) bool {
+	return strings.HasSuffix(version, snapshotSuffix)
+}
+
+// ParseSnapshotFilename parses the name of a file of a build of the SNAPSHOT version.
+// It returns nil if the file doesn't belong to a build, like the maven-metadata.xml or a file without a timestamp.
+func ParseSnapshotFilename(filename, version string) *SnapshotFile {
+	if !IsSnapshotVersion(version) {
+		return nil
+	}
+
+	separator := "-" + strings.TrimSuffix(version, snapshotSuffix) + "-"
+
+	for offset := 0; ; {
+		idx := strings.Index(filename[offset:], separator)
+		if idx == -1 {
+			return nil
+		}
+		idx += offset
+
+		if idx > 0 {
+			if m := snapshotBuildPattern.FindStringSubmatch(filename[idx+len(separator):]); m != nil {
+				buildNumber, err := strconv.Atoi(m[2])
+				if err == nil {
+					return &SnapshotFile{
+						ArtifactID:  filename[:idx],
+						Timestamp:   m[1],
+						BuildNumber: buildNumber,
+						Classifier:  m[3],
+						Extension:   m[4],
+					}
+				}
+			}
+		}
+
+		offset = idx + 1
+	}
+}
+
+// SnapshotMetadata is the maven-metadata.xml of a SNAPSHOT version
+// https://maven.apache.org/ref/3.9.6/maven-repository-metadata/repository-metadata.html
+type SnapshotMetadata struct {
+	XMLName          xml.Name           `xml:"metadata"`
+	ModelVersion     string             `xml:"modelVersion,attr"`
+	GroupID          string             `xml:"groupId"`
+	ArtifactID       string             `xml:"artifactId"`
+	Version          string             `xml:"version"`
+	Timestamp        string             `xml:"versioning>snapshot>timestamp"`
+	BuildNumber      int                `xml:"versioning>snapshot>buildNumber"`
+	LastUpdated      string             `xml:"versioning>lastUpdated"`
+	SnapshotVersions []*SnapshotVersion `xml:"versioning>snapshotVersions>snapshotVersion"`
+}
+
+// SnapshotVersion is the newest build of a classifier and extension of a SNAPSHOT version
+type SnapshotVersion struct {
+	Classifier string `xml:"classifier,omitempty"`
+	Extension  string `xml:"extension"`
+	Value      string `xml:"value"`
+	Updated    string `xml:"updated"`
+}
+
+// CreateSnapshotMetadata creates the metadata of the SNAPSHOT version from the files of its builds.
+// It returns nil if there are no files.
+func CreateSnapshotMetadata(groupID, version string, files []*SnapshotFile) *SnapshotMetadata {
+	if len(files) == 0 {
+		return nil
+	}
+
+	type fileKey struct {
+		Classifier string
+		Extension  string
+	}
+
+	var latest *SnapshotFile
+	newest := make(map[fileKey]*SnapshotFile)
+	for _, f := range files {
+		if latest == nil || f.BuildNumber > latest.BuildNumber {
+			latest = f
+		}
+
+		key := fileKey{f.Classifier, f.Extension}
+		if other, ok := newest[key]; !ok || f.BuildNumber > other.BuildNumber {
+			newest[key] = f
+		}
+	}
+
+	base := strings.TrimSuffix(version, snapshotSuffix)
+
+	snapshotVersions := make([]*SnapshotVersion, 0, len(newest))
+	for _, f := range newest {
+		snapshotVersions = append(snapshotVersions, &SnapshotVersion{
+			Classifier: f.Classifier,
+			Extension:  f.Extension,
+			Value:      base + "-" + f.Timestamp + "-" + strconv.Itoa(f.BuildNumber),
+			Updated:    strings.ReplaceAll(f.Timestamp, ".", ""),
+		})
+	}
+	sort.Slice(snapshotVersions, func(i, j int) bool {
+		if snapshotVersions[i].Extension != snapshotVersions[j].Extension {
+			return snapshotVersions[i].Extension < snapshotVersions[j].Extension
+		}
+		return snapshotVersions[i].Classifier < snapshotVersions[j].Classifier
+	})
+
+	return &SnapshotMetadata{
+		ModelVersion:     "1.1.0",
+		GroupID:          groupID,
+		ArtifactID:       latest.ArtifactID,
+		Version:          version,
+		Timestamp:        latest.Timestamp,
+		BuildNumber:      latest.BuildNumber,
+		LastUpdated:      strings.ReplaceAll(latest.Timestamp, ".", ""),
+		SnapshotVersions: snapshotVersions,
+	}
+}
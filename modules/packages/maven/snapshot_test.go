@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package maven
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSnapshotFilename(t *testing.T) {
+	cases := []struct {
+		Filename string
+		Version  string
+		Expected *SnapshotFile
+	}{
+		{"my-project-1.0-20240102.150405-3.jar", "1.0-SNAPSHOT", &SnapshotFile{ArtifactID: "my-project", Timestamp: "20240102.150405", BuildNumber: 3, Extension: "jar"}},
+		{"my-project-1.0-20240102.150405-3-sources.jar", "1.0-SNAPSHOT", &SnapshotFile{ArtifactID: "my-project", Timestamp: "20240102.150405", BuildNumber: 3, Classifier: "sources", Extension: "jar"}},
+		{"my-project-1.0-20240102.150405-12.pom.asc", "1.0-SNAPSHOT", &SnapshotFile{ArtifactID: "my-project", Timestamp: "20240102.150405", BuildNumber: 12, Extension: "pom.asc"}},
+		{"lib-1.0-x-1.0-20240102.150405-1.jar", "1.0-SNAPSHOT", &SnapshotFile{ArtifactID: "lib-1.0-x", Timestamp: "20240102.150405", BuildNumber: 1, Extension: "jar"}},
+		{"my-project-1.0-SNAPSHOT.jar", "1.0-SNAPSHOT", nil},
+		{"maven-metadata.xml", "1.0-SNAPSHOT", nil},
+		{"my-project-1.0-20240102.150405-3.jar", "1.0", nil},
+		{"my-project-2.0-20240102.150405-3.jar", "1.0-SNAPSHOT", nil},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.Expected, ParseSnapshotFilename(c.Filename, c.Version), "filename: %s", c.Filename)
+	}
+}
+
+func TestCreateSnapshotMetadata(t *testing.T) {
+	assert.Nil(t, CreateSnapshotMetadata(groupID, "1.0-SNAPSHOT", nil))
+
+	files := []*SnapshotFile{
+		{ArtifactID: artifactID, Timestamp: "20240101.100000", BuildNumber: 1, Extension: "jar"},
+		{ArtifactID: artifactID, Timestamp: "20240101.100000", BuildNumber: 1, Extension: "pom"},
+		{ArtifactID: artifactID, Timestamp: "20240101.100000", BuildNumber: 1, Classifier: "sources", Extension: "jar"},
+		{ArtifactID: artifactID, Timestamp: "20240102.110000", BuildNumber: 2, Extension: "jar"},
+		{ArtifactID: artifactID, Timestamp: "20240102.110000", BuildNumber: 2, Extension: "pom"},
+	}
+
+	m := CreateSnapshotMetadata(groupID, "1.0-SNAPSHOT", files)
+	assert.NotNil(t, m)
+	assert.Equal(t, groupID, m.GroupID)
+	assert.Equal(t, artifactID, m.ArtifactID)
+	assert.Equal(t, "1.0-SNAPSHOT", m.Version)
+	assert.Equal(t, "20240102.110000", m.Timestamp)
+	assert.Equal(t, 2, m.BuildNumber)
+	assert.Equal(t, "20240102110000", m.LastUpdated)
+	assert.Equal(t, []*SnapshotVersion{
+		{Extension: "jar", Value: "1.0-20240102.110000-2", Updated: "20240102110000"},
+		{Classifier: "sources", Extension: "jar", Value: "1.0-20240101.100000-1", Updated: "20240101100000"},
+		{Extension: "pom", Value: "1.0-20240102.110000-2", Updated: "20240102110000"},
+	}, m.SnapshotVersions)
+
+	content, err := xml.Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, `<metadata modelVersion="1.1.0"><groupId>org.gitea</groupId><artifactId>my-project</artifactId><version>1.0-SNAPSHOT</version><versioning><snapshot><timestamp>20240102.110000</timestamp><buildNumber>2</buildNumber></snapshot><lastUpdated>20240102110000</lastUpdated><snapshotVersions><snapshotVersion><extension>jar</extension><value>1.0-20240102.110000-2</value><updated>20240102110000</updated></snapshotVersion><snapshotVersion><classifier>sources</classifier><extension>jar</extension><value>1.0-20240101.100000-1</value><updated>20240101100000</updated></snapshotVersion><snapshotVersion><extension>pom</extension><value>1.0-20240102.110000-2</value><updated>20240102110000</updated></snapshotVersion></snapshotVersions></versioning></metadata>`, string(content))
+}
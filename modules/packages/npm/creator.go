@@ -38,12 +38,13 @@ var nameMatch = regexp.MustCompile(`^(@[a-z0-9-][a-z0-9-._]*/)?[a-z0-9-][a-z0-9-
 
 // Package represents a npm package
 type Package struct {
-	Name     string
-	Version  string
-	DistTags []string
-	Metadata Metadata
-	Filename string
-	Data     []byte
+	Name       string
+	Version    string
+	DistTags   []string
+	Metadata   Metadata
+	Filename   string
+	Data       []byte
+	Provenance *Provenance
 }
 
 // PackageMetadata https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#package
@@ -90,12 +91,33 @@ type PackageMetadataVersion struct {
 
 // PackageDistribution https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#version
 type PackageDistribution struct {
-	Integrity    string `json:"integrity"`
-	Shasum       string `json:"shasum"`
-	Tarball      string `json:"tarball"`
-	FileCount    int    `json:"fileCount,omitempty"`
-	UnpackedSize int    `json:"unpackedSize,omitempty"`
-	NpmSignature string `json:"npm-signature,omitempty"`
+	Integrity    string                           `json:"integrity"`
+	Shasum       string                           `json:"shasum"`
+	Tarball      string                           `json:"tarball"`
+	FileCount    int                              `json:"fileCount,omitempty"`
+	UnpackedSize int                              `json:"unpackedSize,omitempty"`
+	NpmSignature string                           `json:"npm-signature,omitempty"`
+	Attestations *PackageDistributionAttestations `json:"attestations,omitempty"`
+}
+
+// PackageDistributionAttestations https://github.com/npm/registry/blob/main/docs/responses/package-metadata.md#dist
+type PackageDistributionAttestations struct {
+	URL        string                                    `json:"url"`
+	Provenance PackageDistributionAttestationsProvenance `json:"provenance"`
+}
+
+type PackageDistributionAttestationsProvenance struct {
+	PredicateType string `json:"predicateType"`
+}
+
+// PackageAttestations is the response of the attestations endpoint of a package version
+type PackageAttestations struct {
+	Attestations []*PackageAttestation `json:"attestations"`
+}
+
+type PackageAttestation struct {
+	PredicateType string          `json:"predicateType"`
+	Bundle        json.RawMessage `json:"bundle"`
 }
 
 type PackageSearch struct {
@@ -235,12 +257,15 @@ func ParsePackage(r io.Reader) (*Package, error) {
 
 		p.Filename = strings.ToLower(fmt.Sprintf("%s-%s.tgz", name, p.Version))
 
-		attachment := func() *PackageAttachment {
-			for _, a := range upload.Attachments {
-				return a
+		// the provenance bundle is published as additional attachment next to the package tarball
+		var attachment, provenance *PackageAttachment
+		for filename, a := range upload.Attachments {
+			if strings.HasSuffix(filename, ProvenanceFileExtension) {
+				provenance = a
+			} else if attachment == nil {
+				attachment = a
 			}
-			return nil
-		}()
+		}
 		if attachment == nil || len(attachment.Data) == 0 {
 			return nil, ErrInvalidAttachment
 		}
@@ -272,6 +297,13 @@ func ParsePackage(r io.Reader) (*Package, error) {
 			return nil, ErrInvalidIntegrity
 		}
 
+		if provenance != nil {
+			p.Provenance, err = ParseProvenance(provenance.Data, provenance.ContentType, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		return p, nil
 	}
 
@@ -0,0 +1,94 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package npm
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrInvalidProvenance indicates an invalid provenance bundle
+var ErrInvalidProvenance = util.NewInvalidArgumentErrorf("package provenance is invalid")
+
+const (
+	// ProvenanceFileExtension is the extension of the attachment holding the provenance bundle
+	ProvenanceFileExtension = ".sigstore"
+	// ProvenanceMediaTypePrefix is the prefix of the media types of sigstore bundles
+	ProvenanceMediaTypePrefix = "application/vnd.dev.sigstore.bundle"
+	// InTotoPayloadType is the payload type of in-toto statements in DSSE envelopes
+	InTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+// Provenance is the sigstore bundle published with a package version
+// https://docs.npmjs.com/generating-provenance-statements
+type Provenance struct {
+	PredicateType string
+	MediaType     string
+	Bundle        string
+}
+
+type sigstoreBundle struct {
+	MediaType    string `json:"mediaType"`
+	DSSEEnvelope *struct {
+		Payload     string `json:"payload"`
+		PayloadType string `json:"payloadType"`
+	} `json:"dsseEnvelope"`
+}
+
+type inTotoStatement struct {
+	Type    string `json:"_type"`
+	Subject []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+	PredicateType string `json:"predicateType"`
+}
+
+// ParseProvenance parses the sigstore bundle and checks that the in-toto statement in it has the package tarball as subject
+func ParseProvenance(bundle, mediaType string, tarball []byte) (*Provenance, error) {
+	var b sigstoreBundle
+	if err := json.Unmarshal([]byte(bundle), &b); err != nil {
+		return nil, ErrInvalidProvenance
+	}
+	if b.MediaType != "" {
+		mediaType = b.MediaType
+	}
+	if !strings.HasPrefix(mediaType, ProvenanceMediaTypePrefix) {
+		return nil, ErrInvalidProvenance
+	}
+	if b.DSSEEnvelope == nil || b.DSSEEnvelope.PayloadType != InTotoPayloadType {
+		return nil, ErrInvalidProvenance
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(b.DSSEEnvelope.Payload)
+	if err != nil {
+		return nil, ErrInvalidProvenance
+	}
+
+	var statement inTotoStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, ErrInvalidProvenance
+	}
+	if statement.PredicateType == "" {
+		return nil, ErrInvalidProvenance
+	}
+
+	hash := sha512.Sum512(tarball)
+	digest := hex.EncodeToString(hash[:])
+	for _, s := range statement.Subject {
+		if strings.EqualFold(s.Digest["sha512"], digest) {
+			return &Provenance{
+				PredicateType: statement.PredicateType,
+				MediaType:     mediaType,
+				Bundle:        bundle,
+			}, nil
+		}
+	}
+	return nil, ErrInvalidProvenance
+}
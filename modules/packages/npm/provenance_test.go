@@ -0,0 +1,55 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package npm
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProvenance(t *testing.T) {
+	tarball := []byte("package tarball")
+	mediaType := "application/vnd.dev.sigstore.bundle+json;version=0.2"
+	predicateType := "https://slsa.dev/provenance/v1"
+
+	createBundle := func(payloadType string, content []byte) string {
+		hash := sha512.Sum512(content)
+		statement := fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"pkg:npm/test-package@1.0.0","digest":{"sha512":"%s"}}],"predicateType":"%s"}`, hex.EncodeToString(hash[:]), predicateType)
+		return fmt.Sprintf(`{"mediaType":"%s","dsseEnvelope":{"payload":"%s","payloadType":"%s","signatures":[]}}`, mediaType, base64.StdEncoding.EncodeToString([]byte(statement)), payloadType)
+	}
+
+	t.Run("InvalidBundle", func(t *testing.T) {
+		p, err := ParseProvenance("not json", mediaType, tarball)
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidProvenance)
+	})
+
+	t.Run("InvalidPayloadType", func(t *testing.T) {
+		p, err := ParseProvenance(createBundle("text/plain", tarball), mediaType, tarball)
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidProvenance)
+	})
+
+	t.Run("SubjectMismatch", func(t *testing.T) {
+		p, err := ParseProvenance(createBundle(InTotoPayloadType, []byte("other tarball")), mediaType, tarball)
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrInvalidProvenance)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		bundle := createBundle(InTotoPayloadType, tarball)
+
+		p, err := ParseProvenance(bundle, "", tarball)
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+		assert.Equal(t, predicateType, p.PredicateType)
+		assert.Equal(t, mediaType, p.MediaType)
+		assert.Equal(t, bundle, p.Bundle)
+	})
+}
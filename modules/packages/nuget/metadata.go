@@ -39,7 +39,8 @@ const (
 	// SymbolsPackage represents a symbol package (*.snupkg)
 	SymbolsPackage
 
-	PropertySymbolID = "nuget.symbol.id"
+	PropertySymbolID       = "nuget.symbol.id"
+	PropertySymbolChecksum = "nuget.symbol.checksum"
 )
 
 var idmatch = regexp.MustCompile(`\A\w+(?:[.-]\w+)*\z`)
@@ -6,7 +6,9 @@ package nuget
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"path"
@@ -24,10 +26,15 @@ var (
 	ErrMissingPdbStream      = util.NewInvalidArgumentErrorf("missing PDB stream")
 )
 
+// pdbIDSize is the size of the PDB id, a GUID followed by a 4 byte stamp
+const pdbIDSize = 20
+
 type PortablePdb struct {
-	Name    string
-	ID      string
-	Content *packages.HashedBuffer
+	Name string
+	ID   string
+	// Checksum is the hex encoded SHA256 checksum of the file with a zeroed PDB id which debuggers use to verify the file
+	Checksum string
+	Content  *packages.HashedBuffer
 }
 
 type PortablePdbList []*PortablePdb
@@ -71,21 +78,36 @@ func ExtractPortablePdb(r io.ReaderAt, size int64) (PortablePdbList, error) {
 					return err
 				}
 
-				id, err := ParseDebugHeaderID(buf)
+				id, idOffset, err := parseDebugHeader(buf)
 				if err != nil {
 					buf.Close()
 					return fmt.Errorf("Invalid PDB file: %w", err)
 				}
 
+				name := path.Base(file.Name)
+
+				// The same file may be included for multiple target frameworks
+				if pdbs.contains(name, id) {
+					buf.Close()
+					continue
+				}
+
+				checksum, err := calculatePdbChecksum(buf, idOffset)
+				if err != nil {
+					buf.Close()
+					return err
+				}
+
 				if _, err := buf.Seek(0, io.SeekStart); err != nil {
 					buf.Close()
 					return err
 				}
 
 				pdbs = append(pdbs, &PortablePdb{
-					Name:    path.Base(file.Name),
-					ID:      id,
-					Content: buf,
+					Name:     name,
+					ID:       id,
+					Checksum: checksum,
+					Content:  buf,
 				})
 			default:
 				return ErrInvalidFiles
@@ -105,34 +127,71 @@ func ExtractPortablePdb(r io.ReaderAt, size int64) (PortablePdbList, error) {
 	return pdbs, nil
 }
 
-// ParseDebugHeaderID TODO
+func (l PortablePdbList) contains(name, id string) bool {
+	for _, pdb := range l {
+		if pdb.ID == id && strings.EqualFold(pdb.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// calculatePdbChecksum calculates the checksum of the whole file with the PDB id set to zero
+// https://github.com/dotnet/runtime/blob/main/docs/design/specs/PE-COFF.md#pdb-checksum-debug-directory-entry-type-19
+func calculatePdbChecksum(r io.ReadSeeker, idOffset int64) (string, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.CopyN(h, r, idOffset); err != nil {
+		return "", err
+	}
+	h.Write(make([]byte, pdbIDSize))
+	if _, err := r.Seek(pdbIDSize, io.SeekCurrent); err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ParseDebugHeaderID parses the id of a Portable PDB file from the #Pdb stream
 func ParseDebugHeaderID(r io.ReadSeeker) (string, error) {
+	id, _, err := parseDebugHeader(r)
+	return id, err
+}
+
+// parseDebugHeader parses the id of a Portable PDB file and returns its offset in the file too
+func parseDebugHeader(r io.ReadSeeker) (string, int64, error) {
 	var magic uint32
 	if err := binary.Read(r, binary.LittleEndian, &magic); err != nil {
-		return "", err
+		return "", 0, err
 	}
 	if magic != 0x424A5342 {
-		return "", ErrInvalidPdbMagicNumber
+		return "", 0, ErrInvalidPdbMagicNumber
 	}
 
 	if _, err := r.Seek(8, io.SeekCurrent); err != nil {
-		return "", err
+		return "", 0, err
 	}
 
 	var versionStringSize int32
 	if err := binary.Read(r, binary.LittleEndian, &versionStringSize); err != nil {
-		return "", err
+		return "", 0, err
 	}
 	if _, err := r.Seek(int64(versionStringSize), io.SeekCurrent); err != nil {
-		return "", err
+		return "", 0, err
 	}
 	if _, err := r.Seek(2, io.SeekCurrent); err != nil {
-		return "", err
+		return "", 0, err
 	}
 
 	var streamCount int16
 	if err := binary.Read(r, binary.LittleEndian, &streamCount); err != nil {
-		return "", err
+		return "", 0, err
 	}
 
 	read4ByteAlignedString := func(r io.Reader) (string, error) {
@@ -153,24 +212,24 @@ func ParseDebugHeaderID(r io.ReadSeeker) (string, error) {
 	for i := 0; i < int(streamCount); i++ {
 		var offset uint32
 		if err := binary.Read(r, binary.LittleEndian, &offset); err != nil {
-			return "", err
+			return "", 0, err
 		}
 		if _, err := r.Seek(4, io.SeekCurrent); err != nil {
-			return "", err
+			return "", 0, err
 		}
 		name, err := read4ByteAlignedString(r)
 		if err != nil {
-			return "", err
+			return "", 0, err
 		}
 
 		if name == "#Pdb" {
 			if _, err := r.Seek(int64(offset), io.SeekStart); err != nil {
-				return "", err
+				return "", 0, err
 			}
 
 			b := make([]byte, 16)
 			if _, err := r.Read(b); err != nil {
-				return "", err
+				return "", 0, err
 			}
 
 			data1 := binary.LittleEndian.Uint32(b[0:4])
@@ -178,9 +237,9 @@ func ParseDebugHeaderID(r io.ReadSeeker) (string, error) {
 			data3 := binary.LittleEndian.Uint16(b[6:8])
 			data4 := b[8:16]
 
-			return fmt.Sprintf("%08x%04x%04x%04x%012x", data1, data2, data3, data4[:2], data4[2:]), nil
+			return fmt.Sprintf("%08x%04x%04x%04x%012x", data1, data2, data3, data4[:2], data4[2:]), int64(offset), nil
 		}
 	}
 
-	return "", ErrMissingPdbStream
+	return "", 0, ErrMissingPdbStream
 }
@@ -52,6 +52,24 @@ func TestExtractPortablePdb(t *testing.T) {
 		assert.Len(t, pdbs, 1)
 		assert.Equal(t, "test.pdb", pdbs[0].Name)
 		assert.Equal(t, "d910bb6948bd4c6cb40155bcf52c3c94", pdbs[0].ID)
+		assert.Equal(t, "d6a673a82db00888c65361a039226e7b34ff689677bf9ee5862d014a63d8a7e2", pdbs[0].Checksum)
+		pdbs.Close()
+	})
+
+	t.Run("DuplicatePdbFiles", func(t *testing.T) {
+		b, _ := base64.StdEncoding.DecodeString(pdbContent)
+
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		for _, name := range []string{"lib/net6.0/test.pdb", "lib/net8.0/test.pdb"} {
+			w, _ := archive.Create(name)
+			w.Write(b)
+		}
+		archive.Close()
+
+		pdbs, err := ExtractPortablePdb(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err)
+		assert.Len(t, pdbs, 1)
 		pdbs.Close()
 	})
 }
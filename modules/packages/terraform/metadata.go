@@ -0,0 +1,214 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/keybase/go-crypto/openpgp"
+)
+
+var (
+	ErrInvalidName          = util.NewInvalidArgumentErrorf("package name is invalid")
+	ErrInvalidModuleArchive = util.NewInvalidArgumentErrorf("module archive is invalid")
+	ErrMissingModuleFiles   = util.NewInvalidArgumentErrorf("module archive doesn't contain any Terraform configuration file")
+	ErrInvalidSHA256SUMS    = util.NewInvalidArgumentErrorf("SHA256SUMS file is invalid")
+	ErrInvalidManifest      = util.NewInvalidArgumentErrorf("manifest file is invalid")
+	ErrInvalidSignature     = util.NewInvalidArgumentErrorf("SHA256SUMS signature can't be verified with a GPG key of the user")
+)
+
+const (
+	// KindModule is the kind of the packages of modules, named `<name>/<system>`
+	KindModule = "module"
+	// KindProvider is the kind of the packages of providers, named `<type>`
+	KindProvider = "provider"
+
+	PropertyOS   = "terraform.os"
+	PropertyArch = "terraform.arch"
+
+	// DefaultProtocol is the plugin protocol of the providers without a manifest
+	DefaultProtocol = "5.0"
+
+	maxReadmeFileSize = 1 * 1024 * 1024
+)
+
+// https://developer.hashicorp.com/terraform/internals/module-registry-protocol#module-addresses
+var namePattern = regexp.MustCompile(`\A[0-9A-Za-z](?:[0-9A-Za-z-_]{0,62}[0-9A-Za-z])?\z`)
+
+// Metadata represents the metadata of a version of a Terraform module or provider
+type Metadata struct {
+	Kind string `json:"kind"`
+	// Readme is the README.md file at the root of a module
+	Readme string `json:"readme,omitempty"`
+	// Protocols are the plugin protocols supported by a provider
+	Protocols []string `json:"protocols,omitempty"`
+	// SigningKey is the GPG key the SHA256SUMS of a provider has been signed with
+	SigningKey *SigningKey `json:"signing_key,omitempty"`
+}
+
+// SigningKey is a GPG public key
+type SigningKey struct {
+	KeyID      string `json:"key_id"`
+	ASCIIArmor string `json:"ascii_armor"`
+}
+
+// IsValidName returns whether the namespace, the name or the system of a module, or the type of a provider, is valid
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// ParseModuleArchive checks the gzipped tar archive of a module and returns its metadata
+func ParseModuleArchive(r io.Reader) (*Metadata, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidModuleArchive
+	}
+	defer gzr.Close()
+
+	metadata := &Metadata{Kind: KindModule}
+	hasConfiguration := false
+
+	tr := tar.NewReader(gzr)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidModuleArchive
+		}
+		if hd.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(hd.Name, "./"))
+		if strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json") {
+			hasConfiguration = true
+		}
+		if strings.EqualFold(name, "README.md") {
+			bs, err := io.ReadAll(io.LimitReader(tr, maxReadmeFileSize))
+			if err != nil {
+				return nil, ErrInvalidModuleArchive
+			}
+			metadata.Readme = string(bs)
+		}
+	}
+	if !hasConfiguration {
+		return nil, ErrMissingModuleFiles
+	}
+	return metadata, nil
+}
+
+// ProviderFileNamePrefix returns the prefix of the names of the files of a version of a provider
+func ProviderFileNamePrefix(providerType, version string) string {
+	return fmt.Sprintf("terraform-provider-%s_%s_", providerType, version)
+}
+
+// SHA256SUMSFileName returns the name of the SHA256SUMS file of a version of a provider
+func SHA256SUMSFileName(providerType, version string) string {
+	return ProviderFileNamePrefix(providerType, version) + "SHA256SUMS"
+}
+
+// SHA256SUMSSignatureFileName returns the name of the detached signature of the SHA256SUMS file of a version of a provider
+func SHA256SUMSSignatureFileName(providerType, version string) string {
+	return SHA256SUMSFileName(providerType, version) + ".sig"
+}
+
+// ManifestFileName returns the name of the manifest of a version of a provider
+func ManifestFileName(providerType, version string) string {
+	return ProviderFileNamePrefix(providerType, version) + "manifest.json"
+}
+
+// ParsePlatformFileName returns the os and the architecture of the zip archive of a version of a provider
+func ParsePlatformFileName(providerType, version, filename string) (os, arch string, ok bool) {
+	platform, found := strings.CutPrefix(filename, ProviderFileNamePrefix(providerType, version))
+	if !found {
+		return "", "", false
+	}
+	platform, found = strings.CutSuffix(platform, ".zip")
+	if !found {
+		return "", "", false
+	}
+	os, arch, ok = strings.Cut(platform, "_")
+	if !ok || !IsValidName(os) || !IsValidName(arch) {
+		return "", "", false
+	}
+	return os, arch, true
+}
+
+// ParseSHA256SUMS returns the checksums of a SHA256SUMS file by file name
+func ParseSHA256SUMS(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		sum, filename, ok := strings.Cut(line, " ")
+		filename = strings.TrimPrefix(strings.TrimSpace(filename), "*")
+		if !ok || filename == "" {
+			return nil, ErrInvalidSHA256SUMS
+		}
+		if bs, err := hex.DecodeString(sum); err != nil || len(bs) != 32 {
+			return nil, ErrInvalidSHA256SUMS
+		}
+		sums[filename] = strings.ToLower(sum)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sums) == 0 {
+		return nil, ErrInvalidSHA256SUMS
+	}
+	return sums, nil
+}
+
+// ParseManifest returns the plugin protocols of the manifest of a provider
+func ParseManifest(r io.Reader) ([]string, error) {
+	var manifest struct {
+		Version  int `json:"version"`
+		Metadata struct {
+			ProtocolVersions []string `json:"protocol_versions"`
+		} `json:"metadata"`
+	}
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, ErrInvalidManifest
+	}
+	if len(manifest.Metadata.ProtocolVersions) == 0 {
+		return []string{DefaultProtocol}, nil
+	}
+	return manifest.Metadata.ProtocolVersions, nil
+}
+
+// VerifySHA256SUMSSignature returns the signing key of the detached signature of the SHA256SUMS file among the armored keys
+func VerifySHA256SUMSSignature(armoredKeys []string, sums, signature []byte) (*SigningKey, error) {
+	for _, armoredKey := range armoredKeys {
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+		if err != nil {
+			continue
+		}
+		signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(sums), bytes.NewReader(signature))
+		if err != nil || signer == nil {
+			continue
+		}
+		return &SigningKey{
+			KeyID:      signer.PrimaryKey.KeyIdString(),
+			ASCIIArmor: armoredKey,
+		}, nil
+	}
+	return nil, ErrInvalidSignature
+}
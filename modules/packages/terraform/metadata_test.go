@@ -0,0 +1,150 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/keybase/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createArchive(files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for name, content := range files {
+		hdr := &tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}
+		tw.WriteHeader(hdr)
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestIsValidName(t *testing.T) {
+	assert.True(t, IsValidName("aws"))
+	assert.True(t, IsValidName("consul-cluster_1"))
+	assert.False(t, IsValidName(""))
+	assert.False(t, IsValidName("-aws"))
+	assert.False(t, IsValidName("aws/vpc"))
+	assert.False(t, IsValidName(strings.Repeat("a", 65)))
+}
+
+func TestParseModuleArchive(t *testing.T) {
+	t.Run("InvalidArchive", func(t *testing.T) {
+		_, err := ParseModuleArchive(strings.NewReader("not an archive"))
+		assert.ErrorIs(t, err, ErrInvalidModuleArchive)
+	})
+
+	t.Run("MissingConfiguration", func(t *testing.T) {
+		_, err := ParseModuleArchive(bytes.NewReader(createArchive(map[string]string{"README.md": "readme"})))
+		assert.ErrorIs(t, err, ErrMissingModuleFiles)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		metadata, err := ParseModuleArchive(bytes.NewReader(createArchive(map[string]string{
+			"./main.tf":                `resource "null_resource" "test" {}`,
+			"./README.md":              "# Module",
+			"modules/nested/README.md": "# Nested",
+		})))
+		require.NoError(t, err)
+		assert.Equal(t, KindModule, metadata.Kind)
+		assert.Equal(t, "# Module", metadata.Readme)
+	})
+}
+
+func TestParsePlatformFileName(t *testing.T) {
+	os, arch, ok := ParsePlatformFileName("test", "1.0.0", "terraform-provider-test_1.0.0_linux_amd64.zip")
+	assert.True(t, ok)
+	assert.Equal(t, "linux", os)
+	assert.Equal(t, "amd64", arch)
+
+	for _, filename := range []string{
+		"terraform-provider-test_1.0.0_linux_amd64.tar.gz",
+		"terraform-provider-test_1.0.1_linux_amd64.zip",
+		"terraform-provider-other_1.0.0_linux_amd64.zip",
+		"terraform-provider-test_1.0.0_linux.zip",
+		"terraform-provider-test_1.0.0_SHA256SUMS",
+	} {
+		_, _, ok := ParsePlatformFileName("test", "1.0.0", filename)
+		assert.False(t, ok, filename)
+	}
+}
+
+func TestParseSHA256SUMS(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	sums, err := ParseSHA256SUMS(strings.NewReader(sum + "  terraform-provider-test_1.0.0_linux_amd64.zip\n" + strings.ToUpper(sum) + " *terraform-provider-test_1.0.0_darwin_arm64.zip\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"terraform-provider-test_1.0.0_linux_amd64.zip":  sum,
+		"terraform-provider-test_1.0.0_darwin_arm64.zip": sum,
+	}, sums)
+
+	for _, content := range []string{"", "abcd  file.zip", sum, sum + "  "} {
+		_, err := ParseSHA256SUMS(strings.NewReader(content))
+		assert.ErrorIs(t, err, ErrInvalidSHA256SUMS, content)
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	protocols, err := ParseManifest(strings.NewReader(`{"version":1,"metadata":{"protocol_versions":["6.0"]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6.0"}, protocols)
+
+	protocols, err = ParseManifest(strings.NewReader(`{"version":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultProtocol}, protocols)
+
+	_, err = ParseManifest(strings.NewReader(`{`))
+	assert.ErrorIs(t, err, ErrInvalidManifest)
+}
+
+func TestVerifySHA256SUMSSignature(t *testing.T) {
+	armoredKey := func(e *openpgp.Entity) string {
+		// serializing the private key signs the identities and the subkeys
+		require.NoError(t, e.SerializePrivate(io.Discard, nil))
+
+		var buf bytes.Buffer
+		w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+		require.NoError(t, err)
+		require.NoError(t, e.Serialize(w))
+		require.NoError(t, w.Close())
+		return buf.String()
+	}
+
+	signer, err := openpgp.NewEntity("Signer", "", "signer@example.com", nil)
+	require.NoError(t, err)
+	other, err := openpgp.NewEntity("Other", "", "other@example.com", nil)
+	require.NoError(t, err)
+
+	signerKey, otherKey := armoredKey(signer), armoredKey(other)
+
+	sums := []byte(strings.Repeat("ab", 32) + "  terraform-provider-test_1.0.0_linux_amd64.zip\n")
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.DetachSign(&signature, signer, bytes.NewReader(sums), nil))
+
+	key, err := VerifySHA256SUMSSignature([]string{"invalid", otherKey, signerKey}, sums, signature.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, signer.PrimaryKey.KeyIdString(), key.KeyID)
+	assert.Equal(t, signerKey, key.ASCIIArmor)
+
+	_, err = VerifySHA256SUMSSignature([]string{otherKey}, sums, signature.Bytes())
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = VerifySHA256SUMSSignature([]string{signerKey}, append(sums, '\n'), signature.Bytes())
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
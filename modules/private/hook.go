@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/git"
@@ -29,13 +30,29 @@ type GitPushOptions map[string]string
 
 // GitPushOptions keys
 const (
-	GitPushOptionRepoPrivate  = "repo.private"
-	GitPushOptionRepoTemplate = "repo.template"
+	GitPushOptionRepoPrivate            = "repo.private"
+	GitPushOptionRepoTemplate           = "repo.template"
+	GitPushOptionSkipCI                 = "skip-ci"
+	GitPushOptionTopic                  = "topic"
+	GitPushOptionTitle                  = "title"
+	GitPushOptionDescription            = "description"
+	GitPushOptionForcePush              = "force-push"
+	GitPushOptionMergeWhenChecksSucceed = "merge_when_checks_succeed"
 )
 
-// Bool checks for a key in the map and parses as a boolean
+// AddFromKeyValue adds a push option given as "key=value", or as "key" for an option without value
+func (g GitPushOptions) AddFromKeyValue(line string) {
+	key, value, _ := strings.Cut(line, "=")
+	g[key] = value
+}
+
+// Bool checks for a key in the map and parses as a boolean,
+// an option given without value (e.g. "-o skip-ci") is true
 func (g GitPushOptions) Bool(key string) optional.Option[bool] {
 	if val, ok := g[key]; ok {
+		if val == "" {
+			return optional.Some(true)
+		}
 		if b, err := strconv.ParseBool(val); err == nil {
 			return optional.Some(b)
 		}
@@ -59,6 +76,8 @@ type HookOptions struct {
 	DeployKeyID                     int64 // if the pusher is a DeployKey, then UserID is the repo's org user.
 	IsWiki                          bool
 	ActionPerm                      int
+	SkipWebhooks                    bool
+	SkipCI                          bool
 }
 
 // SSHLogOption ssh log options
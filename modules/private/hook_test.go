@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPushOptions(t *testing.T) {
+	opts := make(GitPushOptions)
+	opts.AddFromKeyValue("skip-ci")
+	opts.AddFromKeyValue("repo.private=false")
+	opts.AddFromKeyValue("title=a=b")
+	opts.AddFromKeyValue("force-push=invalid")
+
+	assert.Equal(t, GitPushOptions{"skip-ci": "", "repo.private": "false", "title": "a=b", "force-push": "invalid"}, opts)
+	assert.True(t, opts.Bool(GitPushOptionSkipCI).Value())
+	assert.False(t, opts.Bool(GitPushOptionRepoPrivate).Value())
+	assert.True(t, opts.Bool(GitPushOptionRepoPrivate).Has())
+	assert.False(t, opts.Bool(GitPushOptionForcePush).Has())
+	assert.False(t, opts.Bool(GitPushOptionRepoTemplate).Has())
+}
@@ -40,10 +40,27 @@ func newInternalRequest(ctx context.Context, url, method string, body ...any) *h
 Ensure you are running in the correct environment or set the correct configuration file with -c.`, setting.CustomConf)
 	}
 
+	req := NewLocalRequest(ctx, url, method).
+		Header("Authorization", fmt.Sprintf("Bearer %s", setting.InternalToken))
+
+	if len(body) == 1 {
+		req.Header("Content-Type", "application/json")
+		jsonBytes, _ := json.Marshal(body[0])
+		req.Body(jsonBytes)
+	} else if len(body) > 1 {
+		log.Fatal("Too many arguments for newInternalRequest")
+	}
+
+	req.SetTimeout(10*time.Second, 60*time.Second)
+	return req
+}
+
+// NewLocalRequest creates a request to the local Gitea server without the internal token,
+// it is used for the endpoints which authenticate the request by other means, like the LFS server.
+func NewLocalRequest(ctx context.Context, url, method string) *httplib.Request {
 	req := httplib.NewRequest(url, method).
 		SetContext(ctx).
 		Header("X-Real-IP", getClientIP()).
-		Header("Authorization", fmt.Sprintf("Bearer %s", setting.InternalToken)).
 		SetTLSClientConfig(&tls.Config{
 			InsecureSkipVerify: true,
 			ServerName:         setting.Domain,
@@ -82,15 +99,5 @@ Ensure you are running in the correct environment or set the correct configurati
 			},
 		})
 	}
-
-	if len(body) == 1 {
-		req.Header("Content-Type", "application/json")
-		jsonBytes, _ := json.Marshal(body[0])
-		req.Body(jsonBytes)
-	} else if len(body) > 1 {
-		log.Fatal("Too many arguments for newInternalRequest")
-	}
-
-	req.SetTimeout(10*time.Second, 60*time.Second)
 	return req
 }
@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package references
+
+import (
+	"regexp"
+	"sort"
+)
+
+// LanguageKeywords are the keywords closing and reopening issues in other languages than English,
+// which can be accepted by a repository besides its own keywords
+var LanguageKeywords = map[string]struct {
+	Close  []string
+	Reopen []string
+}{
+	"de": {
+		Close:  []string{"schließt", "schliesst", "behebt", "löst", "erledigt"},
+		Reopen: []string{"wiedereröffnet"},
+	},
+	"es": {
+		Close:  []string{"cierra", "cerró", "corrige", "arregla", "resuelve"},
+		Reopen: []string{"reabre"},
+	},
+	"fr": {
+		Close:  []string{"ferme", "corrige", "résout"},
+		Reopen: []string{"rouvre"},
+	},
+	"it": {
+		Close:  []string{"chiude", "corregge", "risolve"},
+		Reopen: []string{"riapre"},
+	},
+	"nl": {
+		Close:  []string{"sluit", "repareert", "verhelpt"},
+		Reopen: []string{"heropent"},
+	},
+	"pt": {
+		Close:  []string{"fecha", "corrige", "resolve"},
+		Reopen: []string{"reabre"},
+	},
+}
+
+// KeywordLanguages returns the sorted languages of LanguageKeywords
+func KeywordLanguages() []string {
+	languages := make([]string, 0, len(LanguageKeywords))
+	for language := range LanguageKeywords {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Keywords are the keywords which close or reopen the issues referenced after them
+type Keywords struct {
+	closePat, reopenPat *regexp.Regexp
+}
+
+// NewKeywords compiles the keywords closing and reopening issues, invalid keywords are ignored.
+// The keywords of the languages are added to them.
+func NewKeywords(close, reopen []string, languages ...string) *Keywords {
+	close = append([]string{}, close...)
+	reopen = append([]string{}, reopen...)
+	for _, language := range languages {
+		if keywords, ok := LanguageKeywords[language]; ok {
+			close = append(close, keywords.Close...)
+			reopen = append(reopen, keywords.Reopen...)
+		}
+	}
+	return &Keywords{
+		closePat:  makeKeywordsPat(close),
+		reopenPat: makeKeywordsPat(reopen),
+	}
+}
+
+// FindAllIssueReferences returns a list of unvalidated references found in a string,
+// their actions are found with the keywords
+func (kw *Keywords) FindAllIssueReferences(content string) []IssueReference {
+	return findAllIssueReferences(kw, content)
+}
+
+// FindAllIssueReferencesMarkdown strips content from markdown markup
+// and returns a list of unvalidated references found in it, their actions are found with the keywords
+func (kw *Keywords) FindAllIssueReferencesMarkdown(content string) []IssueReference {
+	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(kw, content))
+}
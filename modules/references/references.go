@@ -44,8 +44,8 @@ var (
 	// timeLogPattern matches string for time tracking
 	timeLogPattern = regexp.MustCompile(`(?:\s|^|\(|\[)(@([0-9]+([\.,][0-9]+)?(w|d|m|h))+)(?:\s|$|\)|\]|[:;,.?!]\s|[:;,.?!]$)`)
 
-	issueCloseKeywordsPat, issueReopenKeywordsPat *regexp.Regexp
-	issueKeywordsOnce                             sync.Once
+	defaultKeywords   *Keywords
+	issueKeywordsOnce sync.Once
 
 	giteaHostInit         sync.Once
 	giteaHost             string
@@ -165,8 +165,7 @@ func newKeywords() {
 }
 
 func doNewKeywords(close, reopen []string) {
-	issueCloseKeywordsPat = makeKeywordsPat(close)
-	issueReopenKeywordsPat = makeKeywordsPat(reopen)
+	defaultKeywords = NewKeywords(close, reopen)
 }
 
 // getGiteaHostName returns a normalized string with the local host name, with no scheme or port information
@@ -243,12 +242,12 @@ func FindFirstMentionBytes(content []byte) (bool, RefSpan) {
 // FindAllIssueReferencesMarkdown strips content from markdown markup
 // and returns a list of unvalidated references found in it.
 func FindAllIssueReferencesMarkdown(content string) []IssueReference {
-	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content))
+	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(nil, content))
 }
 
-func findAllIssueReferencesMarkdown(content string) []*rawReference {
+func findAllIssueReferencesMarkdown(kw *Keywords, content string) []*rawReference {
 	bcontent, links := mdstripper.StripMarkdownBytes([]byte(content))
-	return findAllIssueReferencesBytes(bcontent, links)
+	return findAllIssueReferencesBytes(kw, bcontent, links)
 }
 
 func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byte) {
@@ -319,6 +318,10 @@ func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byt
 
 // FindAllIssueReferences returns a list of unvalidated references found in a string.
 func FindAllIssueReferences(content string) []IssueReference {
+	return findAllIssueReferences(nil, content)
+}
+
+func findAllIssueReferences(kw *Keywords, content string) []IssueReference {
 	// Need to convert fully qualified html references to local system to #/! short codes
 	contentBytes := []byte(content)
 	if re := getGiteaIssuePullPattern(); re != nil {
@@ -326,7 +329,7 @@ func FindAllIssueReferences(content string) []IssueReference {
 	} else {
 		log.Debug("No GiteaIssuePullPattern pattern")
 	}
-	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}))
+	return rawToIssueReferenceList(findAllIssueReferencesBytes(kw, contentBytes, []string{}))
 }
 
 // FindRenderizableReferenceNumeric returns the first unvalidated reference found in a string.
@@ -340,7 +343,7 @@ func FindRenderizableReferenceNumeric(content string, prOnly, crossLinkOnly bool
 			return false, nil
 		}
 	}
-	r := getCrossReference(util.UnsafeStringToBytes(content), match[2], match[3], false, prOnly)
+	r := getCrossReference(nil, util.UnsafeStringToBytes(content), match[2], match[3], false, prOnly)
 	if r == nil {
 		return false, nil
 	}
@@ -378,7 +381,7 @@ func FindRenderizableReferenceRegexp(content string, pattern *regexp.Regexp) (bo
 		return false, nil
 	}
 
-	action, location := findActionKeywords([]byte(content), match[2])
+	action, location := findActionKeywords(nil, []byte(content), match[2])
 
 	return true, &RenderizableReference{
 		Issue:          content[match[2]:match[3]],
@@ -396,7 +399,7 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 		return false, nil
 	}
 
-	action, location := findActionKeywords([]byte(content), match[2])
+	action, location := findActionKeywords(nil, []byte(content), match[2])
 
 	return true, &RenderizableReference{
 		Issue:          content[match[2]:match[3]],
@@ -408,7 +411,7 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 }
 
 // FindAllIssueReferencesBytes returns a list of unvalidated references found in a byte slice.
-func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference {
+func findAllIssueReferencesBytes(kw *Keywords, content []byte, links []string) []*rawReference {
 	ret := make([]*rawReference, 0, 10)
 	pos := 0
 
@@ -421,7 +424,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(kw, content, match[2]+pos, match[3]+pos, false, false); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -439,7 +442,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(kw, content, match[2]+pos, match[3]+pos, false, false); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -473,7 +476,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 			}
 			// Note: closing/reopening keywords not supported with URLs
 			bytes := []byte(parts[1] + "/" + parts[2] + sep + parts[4])
-			if ref := getCrossReference(bytes, 0, len(bytes), true, false); ref != nil {
+			if ref := getCrossReference(kw, bytes, 0, len(bytes), true, false); ref != nil {
 				ref.refLocation = nil
 				ret = append(ret, ref)
 			}
@@ -515,7 +518,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 	return ret
 }
 
-func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *rawReference {
+func getCrossReference(kw *Keywords, content []byte, start, end int, fromLink, prOnly bool) *rawReference {
 	sep := bytes.IndexAny(content[start:end], "#!")
 	if sep < 0 {
 		return nil
@@ -535,7 +538,7 @@ func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *r
 			// Markdown links must specify owner/repo
 			return nil
 		}
-		action, location := findActionKeywords(content, start)
+		action, location := findActionKeywords(kw, content, start)
 		return &rawReference{
 			index:          index,
 			action:         action,
@@ -553,7 +556,7 @@ func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *r
 	if !validNamePattern.MatchString(owner) || !validNamePattern.MatchString(name) {
 		return nil
 	}
-	action, location := findActionKeywords(content, start)
+	action, location := findActionKeywords(kw, content, start)
 	return &rawReference{
 		index:          index,
 		owner:          owner,
@@ -566,17 +569,21 @@ func getCrossReference(content []byte, start, end int, fromLink, prOnly bool) *r
 	}
 }
 
-func findActionKeywords(content []byte, start int) (XRefAction, *RefSpan) {
-	newKeywords()
+// findActionKeywords finds the keyword preceding a reference, the keywords of the instance are used if kw is nil
+func findActionKeywords(kw *Keywords, content []byte, start int) (XRefAction, *RefSpan) {
+	if kw == nil {
+		newKeywords()
+		kw = defaultKeywords
+	}
 	var m []int
-	if issueCloseKeywordsPat != nil {
-		m = issueCloseKeywordsPat.FindSubmatchIndex(content[:start])
+	if kw.closePat != nil {
+		m = kw.closePat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionCloses, &RefSpan{Start: m[2], End: m[3]}
 		}
 	}
-	if issueReopenKeywordsPat != nil {
-		m = issueReopenKeywordsPat.FindSubmatchIndex(content[:start])
+	if kw.reopenPat != nil {
+		m = kw.reopenPat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionReopens, &RefSpan{Start: m[2], End: m[3]}
 		}
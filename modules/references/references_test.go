@@ -285,7 +285,7 @@ func testFixtures(t *testing.T, fixtures []testFixture, context string) {
 		expref := rawToIssueReferenceList(expraw)
 		refs := FindAllIssueReferencesMarkdown(fixture.input)
 		assert.EqualValues(t, expref, refs, "[%s] Failed to parse: {%s}", context, fixture.input)
-		rawrefs := findAllIssueReferencesMarkdown(fixture.input)
+		rawrefs := findAllIssueReferencesMarkdown(nil, fixture.input)
 		assert.EqualValues(t, expraw, rawrefs, "[%s] Failed to parse: {%s}", context, fixture.input)
 	}
 
@@ -558,3 +558,35 @@ func TestParseCloseKeywords(t *testing.T) {
 		}
 	}
 }
+
+func TestKeywords(t *testing.T) {
+	kw := NewKeywords([]string{"done"}, []string{"again"}, "de", "xx")
+	for _, test := range []struct {
+		content string
+		action  XRefAction
+	}{
+		{"done #1", XRefActionCloses},
+		{"Behebt #1", XRefActionCloses},
+		{"again: #1", XRefActionReopens},
+		{"wiedereröffnet #1", XRefActionReopens},
+		{"fixes #1", XRefActionNone},
+		{"cierra #1", XRefActionNone},
+	} {
+		refs := kw.FindAllIssueReferences(test.content)
+		if assert.Len(t, refs, 1, test.content) {
+			assert.Equal(t, test.action, refs[0].Action, test.content)
+		}
+		refs = kw.FindAllIssueReferencesMarkdown(test.content)
+		if assert.Len(t, refs, 1, test.content) {
+			assert.Equal(t, test.action, refs[0].Action, test.content)
+		}
+	}
+
+	// the keywords of the instance are still used without keywords
+	refs := FindAllIssueReferences("fixes #1")
+	if assert.Len(t, refs, 1) {
+		assert.Equal(t, XRefActionCloses, refs[0].Action)
+	}
+
+	assert.Equal(t, []string{"de", "es", "fr", "it", "nl", "pt"}, KeywordLanguages())
+}
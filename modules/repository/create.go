@@ -205,7 +205,11 @@ func UpdateRepoSize(ctx context.Context, repo *repo_model.Repository) error {
 		return fmt.Errorf("updateSize: GetLFSMetaObjects: %w", err)
 	}
 
-	return repo_model.UpdateRepoSize(ctx, repo.ID, size, lfsSize)
+	if err := repo_model.UpdateRepoSize(ctx, repo.ID, size, lfsSize); err != nil {
+		return err
+	}
+	repo.Size, repo.GitSize, repo.LFSSize = size+lfsSize, size, lfsSize
+	return nil
 }
 
 // CheckDaemonExportOK creates/removes git-daemon-export-ok for git-daemon...
@@ -29,6 +29,8 @@ const (
 	EnvIsInternal   = "GITEA_INTERNAL_PUSH"
 	EnvAppURL       = "GITEA_ROOT_URL"
 	EnvActionPerm   = "GITEA_ACTION_PERM"
+	EnvSkipWebhooks = "GITEA_SKIP_WEBHOOKS"
+	EnvSkipCI       = "GITEA_SKIP_CI"
 )
 
 type PushTrigger string
@@ -36,6 +38,7 @@ type PushTrigger string
 const (
 	PushTriggerPRMergeToBase    PushTrigger = "pr-merge-to-base"
 	PushTriggerPRUpdateWithBase PushTrigger = "pr-update-with-base"
+	PushTriggerPRMergeQueue     PushTrigger = "pr-merge-queue"
 )
 
 // InternalPushingEnvironment returns an os environment to switch off hooks on push
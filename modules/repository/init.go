@@ -104,20 +104,24 @@ func LoadRepoConfig() error {
 		LabelTemplateFiles = append(LabelTemplateFiles, OptionFile{DisplayName: displayName, Description: description})
 	}
 
-	// Filter out invalid names and promote preferred licenses.
-	sortedLicenses := make([]string, 0, len(Licenses))
+	Licenses = sortLicenses(Licenses)
+	return nil
+}
+
+// sortLicenses filters out invalid names and promotes preferred licenses.
+func sortLicenses(licenses []string) []string {
+	sortedLicenses := make([]string, 0, len(licenses))
 	for _, name := range setting.Repository.PreferredLicenses {
-		if util.SliceContainsString(Licenses, name, true) {
+		if util.SliceContainsString(licenses, name, true) {
 			sortedLicenses = append(sortedLicenses, name)
 		}
 	}
-	for _, name := range Licenses {
+	for _, name := range licenses {
 		if !util.SliceContainsString(setting.Repository.PreferredLicenses, name, true) {
 			sortedLicenses = append(sortedLicenses, name)
 		}
 	}
-	Licenses = sortedLicenses
-	return nil
+	return sortedLicenses
 }
 
 func CheckInitRepository(ctx context.Context, owner, name, objectFormatName string) (err error) {
@@ -146,7 +150,7 @@ func CheckInitRepository(ctx context.Context, owner, name, objectFormatName stri
 
 // InitializeLabels adds a label set to a repository using a template
 func InitializeLabels(ctx context.Context, id int64, labelTemplate string, isOrg bool) error {
-	list, err := LoadTemplateLabelsByDisplayName(labelTemplate)
+	list, err := LoadTemplateLabelsByDisplayName(ctx, labelTemplate)
 	if err != nil {
 		return err
 	}
@@ -173,8 +177,15 @@ func InitializeLabels(ctx context.Context, id int64, labelTemplate string, isOrg
 	return nil
 }
 
-// LoadTemplateLabelsByDisplayName loads a label template by its display name
-func LoadTemplateLabelsByDisplayName(displayName string) ([]*label.Label, error) {
+// LoadTemplateLabelsByDisplayName loads a label template by its display name,
+// the templates managed by the administrators override the files.
+func LoadTemplateLabelsByDisplayName(ctx context.Context, displayName string) ([]*label.Label, error) {
+	tmpl, err := repo_model.GetOptionTemplate(ctx, repo_model.OptionTemplateLabel, displayName)
+	if err != nil {
+		return nil, err
+	} else if tmpl != nil {
+		return label.ParseTemplate(displayName+".yaml", []byte(tmpl.Content))
+	}
 	if fileName, ok := labelTemplateFileMap[displayName]; ok {
 		return label.LoadTemplateFile(fileName)
 	}
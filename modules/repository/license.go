@@ -6,11 +6,12 @@ package repository
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 
-	"code.gitea.io/gitea/modules/options"
+	repo_model "code.gitea.io/gitea/models/repo"
 )
 
 type LicenseValues struct {
@@ -20,8 +21,8 @@ type LicenseValues struct {
 	Year  string
 }
 
-func GetLicense(name string, values *LicenseValues) ([]byte, error) {
-	data, err := options.License(name)
+func GetLicense(ctx context.Context, name string, values *LicenseValues) ([]byte, error) {
+	data, err := ReadOptionTemplate(ctx, repo_model.OptionTemplateLicense, name)
 	if err != nil {
 		return nil, fmt.Errorf("GetRepoInitFile[%s]: %w", name, err)
 	}
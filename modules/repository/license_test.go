@@ -7,10 +7,15 @@ import (
 	"fmt"
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_getLicense(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
 	type args struct {
 		name   string
 		values *LicenseValues
@@ -49,7 +54,7 @@ THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLI
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := GetLicense(tt.args.name, tt.args.values)
+			got, err := GetLicense(db.DefaultContext, tt.args.name, tt.args.values)
 			if !tt.wantErr(t, err, fmt.Sprintf("GetLicense(%v, %v)", tt.args.name, tt.args.values)) {
 				return
 			}
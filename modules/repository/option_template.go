@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/label"
+	"code.gitea.io/gitea/modules/options"
+)
+
+// ReadOptionTemplate reads the content of a gitignore or license template,
+// the templates managed by the administrators override the files from static/bindata or custom path.
+func ReadOptionTemplate(ctx context.Context, tp repo_model.OptionTemplateType, name string) ([]byte, error) {
+	tmpl, err := repo_model.GetOptionTemplate(ctx, tp, name)
+	if err != nil {
+		return nil, err
+	} else if tmpl != nil {
+		return []byte(tmpl.Content), nil
+	}
+
+	switch tp {
+	case repo_model.OptionTemplateGitignore:
+		return options.Gitignore(name)
+	case repo_model.OptionTemplateLicense:
+		return options.License(name)
+	}
+	return nil, fmt.Errorf("unable to read the %s template %q", tp, name)
+}
+
+// mergeOptionTemplateNames returns the names of the files followed by the names of the templates managed by the administrators which don't override a file
+func mergeOptionTemplateNames(ctx context.Context, tp repo_model.OptionTemplateType, names []string) ([]string, error) {
+	tmpls, err := repo_model.FindOptionTemplates(ctx, tp)
+	if err != nil {
+		return nil, err
+	}
+	merged := slices.Clone(names)
+	for _, tmpl := range tmpls {
+		if !slices.Contains(names, tmpl.Name) {
+			merged = append(merged, tmpl.Name)
+		}
+	}
+	return merged, nil
+}
+
+// ListGitignores returns the names of the gitignore templates
+func ListGitignores(ctx context.Context) ([]string, error) {
+	names, err := mergeOptionTemplateNames(ctx, repo_model.OptionTemplateGitignore, Gitignores)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// ListLicenses returns the names of the license templates, the preferred licenses come first
+func ListLicenses(ctx context.Context) ([]string, error) {
+	names, err := mergeOptionTemplateNames(ctx, repo_model.OptionTemplateLicense, Licenses)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == len(Licenses) {
+		return names, nil
+	}
+	slices.Sort(names)
+	return sortLicenses(names), nil
+}
+
+// ListLabelTemplates returns the label templates with their description
+func ListLabelTemplates(ctx context.Context) ([]OptionFile, error) {
+	tmpls, err := repo_model.FindOptionTemplates(ctx, repo_model.OptionTemplateLabel)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpls) == 0 {
+		return LabelTemplateFiles, nil
+	}
+
+	files := make([]OptionFile, 0, len(LabelTemplateFiles)+len(tmpls))
+	for _, file := range LabelTemplateFiles {
+		if !slices.ContainsFunc(tmpls, func(tmpl *repo_model.OptionTemplate) bool { return tmpl.Name == file.DisplayName }) {
+			files = append(files, file)
+		}
+	}
+	for _, tmpl := range tmpls {
+		list, err := label.ParseTemplate(tmpl.Name+".yaml", []byte(tmpl.Content))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, OptionFile{DisplayName: tmpl.Name, Description: label.TemplateDescription(list)})
+	}
+	slices.SortFunc(files, func(a, b OptionFile) int {
+		return strings.Compare(a.DisplayName, b.DisplayName)
+	})
+	return files, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionTemplates(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	assert.NoError(t, LoadRepoConfig())
+	ctx := db.DefaultContext
+
+	content, err := ReadOptionTemplate(ctx, repo_model.OptionTemplateGitignore, "Go")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "custom-go", string(content))
+
+	assert.NoError(t, repo_model.SaveOptionTemplate(ctx, &repo_model.OptionTemplate{Type: repo_model.OptionTemplateGitignore, Name: "Go", Content: "custom-go"}))
+	assert.NoError(t, repo_model.SaveOptionTemplate(ctx, &repo_model.OptionTemplate{Type: repo_model.OptionTemplateGitignore, Name: "MyStack", Content: "my-stack"}))
+	assert.NoError(t, repo_model.SaveOptionTemplate(ctx, &repo_model.OptionTemplate{Type: repo_model.OptionTemplateLicense, Name: "In-House", Content: "Copyright <owner>"}))
+	assert.NoError(t, repo_model.SaveOptionTemplate(ctx, &repo_model.OptionTemplate{
+		Type:    repo_model.OptionTemplateLabel,
+		Name:    "Minimal",
+		Content: "labels:\n  - name: bug\n    color: ee0701\n  - name: feature\n    color: 84b6eb\n",
+	}))
+
+	content, err = ReadOptionTemplate(ctx, repo_model.OptionTemplateGitignore, "Go")
+	assert.NoError(t, err)
+	assert.Equal(t, "custom-go", string(content))
+
+	gitignores, err := ListGitignores(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, gitignores, len(Gitignores)+1)
+	assert.Contains(t, gitignores, "MyStack")
+
+	licenses, err := ListLicenses(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, licenses, len(Licenses)+1)
+	assert.Equal(t, Licenses[0], licenses[0])
+	assert.Contains(t, licenses, "In-House")
+
+	license, err := GetLicense(ctx, "In-House", &LicenseValues{Owner: "Gitea"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Copyright Gitea\n", string(license))
+
+	labelTemplates, err := ListLabelTemplates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, labelTemplates, len(LabelTemplateFiles)+1)
+	assert.Contains(t, labelTemplates, OptionFile{DisplayName: "Minimal", Description: "bug, feature"})
+
+	labels, err := LoadTemplateLabelsByDisplayName(ctx, "Minimal")
+	assert.NoError(t, err)
+	if assert.Len(t, labels, 2) {
+		assert.Equal(t, "bug", labels[0].Name)
+		assert.Equal(t, "#ee0701", labels[0].Color)
+	}
+
+	assert.NoError(t, repo_model.DeleteOptionTemplate(ctx, repo_model.OptionTemplateGitignore, "Go"))
+	content, err = ReadOptionTemplate(ctx, repo_model.OptionTemplateGitignore, "Go")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "custom-go", string(content))
+	assert.ErrorIs(t, repo_model.DeleteOptionTemplate(ctx, repo_model.OptionTemplateGitignore, "Go"), util.ErrNotExist)
+}
@@ -16,6 +16,9 @@ type PushUpdateOptions struct {
 	RefFullName  git.RefName // branch, tag or other name to push
 	OldCommitID  string
 	NewCommitID  string
+	SkipWebhooks bool              // don't trigger the webhooks of the push
+	SkipCI       bool              // don't trigger the Actions runs of the push, like a [skip ci] commit message
+	PushOptions  map[string]string // the options given with git push -o
 }
 
 // IsNewRef return true if it's a first-time push to a branch, tag or etc.
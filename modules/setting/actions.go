@@ -5,10 +5,13 @@ package setting
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
+
+	"github.com/dustin/go-humanize"
 )
 
 // Actions settings
@@ -17,12 +20,19 @@ var (
 		LogStorage            *Storage // how the created logs should be stored
 		ArtifactStorage       *Storage // how the created artifacts should be stored
 		ArtifactRetentionDays int64    `ini:"ARTIFACT_RETENTION_DAYS"`
+		CacheStorage          *Storage // how the caches of actions/cache should be stored
+		CacheRepoQuota        int64    `ini:"-"` // the maximum size of the caches of a repository, the least recently used ones are evicted beyond it
+		CacheRetentionDays    int64    `ini:"CACHE_RETENTION_DAYS"`
 		Enabled               bool
 		DefaultActionsURL     defaultActionsURL `ini:"DEFAULT_ACTIONS_URL"`
 		ZombieTaskTimeout     time.Duration     `ini:"ZOMBIE_TASK_TIMEOUT"`
 		EndlessTaskTimeout    time.Duration     `ini:"ENDLESS_TASK_TIMEOUT"`
 		AbandonedJobTimeout   time.Duration     `ini:"ABANDONED_JOB_TIMEOUT"`
 		SkipWorkflowStrings   []string          `ìni:"SKIP_WORKFLOW_STRINGS"`
+		// the URL the queued jobs by labels are posted to for the autoscalers of runners, signed with the secret
+		AutoscalerWebhookURL      string        `ini:"AUTOSCALER_WEBHOOK_URL"`
+		AutoscalerWebhookSecret   string        `ini:"AUTOSCALER_WEBHOOK_SECRET"`
+		AutoscalerWebhookInterval time.Duration `ini:"AUTOSCALER_WEBHOOK_INTERVAL"`
 	}{
 		Enabled:             true,
 		DefaultActionsURL:   defaultActionsURLGitHub,
@@ -82,15 +92,36 @@ func loadActionsFrom(rootCfg ConfigProvider) error {
 	actionsSec, _ := rootCfg.GetSection("actions.artifacts")
 
 	Actions.ArtifactStorage, err = getStorage(rootCfg, "actions_artifacts", "", actionsSec)
+	if err != nil {
+		return err
+	}
 
 	// default to 90 days in Github Actions
 	if Actions.ArtifactRetentionDays <= 0 {
 		Actions.ArtifactRetentionDays = 90
 	}
 
+	cacheSec, _ := rootCfg.GetSection("actions.cache")
+
+	Actions.CacheStorage, err = getStorage(rootCfg, "actions_cache", "", cacheSec)
+
+	// default to 10 GiB and 7 days in Github Actions
+	Actions.CacheRepoQuota = 10 << 30
+	if quota := sec.Key("CACHE_REPO_QUOTA").String(); quota != "" {
+		size, parseErr := humanize.ParseBytes(quota)
+		if parseErr != nil || size > math.MaxInt64 {
+			return fmt.Errorf("invalid [actions] CACHE_REPO_QUOTA: %q", quota)
+		}
+		Actions.CacheRepoQuota = int64(size)
+	}
+	if Actions.CacheRetentionDays <= 0 {
+		Actions.CacheRetentionDays = 7
+	}
+
 	Actions.ZombieTaskTimeout = sec.Key("ZOMBIE_TASK_TIMEOUT").MustDuration(10 * time.Minute)
 	Actions.EndlessTaskTimeout = sec.Key("ENDLESS_TASK_TIMEOUT").MustDuration(3 * time.Hour)
 	Actions.AbandonedJobTimeout = sec.Key("ABANDONED_JOB_TIMEOUT").MustDuration(24 * time.Hour)
+	Actions.AutoscalerWebhookInterval = sec.Key("AUTOSCALER_WEBHOOK_INTERVAL").MustDuration(10 * time.Second)
 
 	return err
 }
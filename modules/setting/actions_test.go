@@ -179,3 +179,42 @@ DEFAULT_ACTIONS_URL = gitea
 		})
 	}
 }
+
+func Test_getCacheSettingsForActions(t *testing.T) {
+	cfg, err := NewConfigProviderFromData(`
+[storage]
+STORAGE_TYPE = minio
+`)
+	assert.NoError(t, err)
+	assert.NoError(t, loadActionsFrom(cfg))
+
+	assert.EqualValues(t, "minio", Actions.CacheStorage.Type)
+	assert.EqualValues(t, "actions_cache/", Actions.CacheStorage.MinioConfig.BasePath)
+	assert.EqualValues(t, 10<<30, Actions.CacheRepoQuota)
+	assert.EqualValues(t, 7, Actions.CacheRetentionDays)
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+CACHE_REPO_QUOTA = 512 MiB
+CACHE_RETENTION_DAYS = 30
+
+[storage.actions_cache]
+STORAGE_TYPE = minio
+MINIO_BASE_PATH = my_cache/
+`)
+	assert.NoError(t, err)
+	assert.NoError(t, loadActionsFrom(cfg))
+
+	assert.EqualValues(t, "minio", Actions.CacheStorage.Type)
+	assert.EqualValues(t, "my_cache/", Actions.CacheStorage.MinioConfig.BasePath)
+	assert.EqualValues(t, "local", Actions.ArtifactStorage.Type)
+	assert.EqualValues(t, 512<<20, Actions.CacheRepoQuota)
+	assert.EqualValues(t, 30, Actions.CacheRetentionDays)
+
+	cfg, err = NewConfigProviderFromData(`
+[actions]
+CACHE_REPO_QUOTA = lots
+`)
+	assert.NoError(t, err)
+	assert.Error(t, loadActionsFrom(cfg))
+}
@@ -30,6 +30,11 @@ var Git = struct {
 	LargeObjectThreshold      int64
 	DisableCoreProtectNTFS    bool
 	DisablePartialClone       bool
+	PartialCloneFilters       []string `ini:"PARTIAL_CLONE_FILTERS" delim:","` // PartialCloneFilters are the allowed kinds of filter specs, all of them are allowed if empty
+	PartialCloneTreeMaxDepth  int      // PartialCloneTreeMaxDepth is the maximum depth of the "tree:<depth>" filter specs, 0 means no limit
+	PartialCloneMaxRepoSize   int64    // PartialCloneMaxRepoSize disables the filters for the repositories larger than it, 0 means no limit
+	CommitGraphChangedPaths   bool     // CommitGraphChangedPaths writes the Bloom filters of the changed paths in the commit-graph
+	MaintenanceAfterPushSize  int64    // MaintenanceAfterPushSize maintains a repository after a push growing it by at least this many bytes, 0 disables it
 	Timeout                   struct {
 		Default int
 		Migrate int
@@ -38,6 +43,12 @@ var Git = struct {
 		Pull    int
 		GC      int `ini:"GC"`
 	} `ini:"git.timeout"`
+	CatFilePool struct {
+		Enabled             bool
+		MaxProcessesPerRepo int           // MaxProcessesPerRepo is the maximum number of pooled processes of each kind for a repository, the others are closed after use
+		MaxIdleProcesses    int           // MaxIdleProcesses is the maximum number of idle processes kept by the pool
+		IdleTimeout         time.Duration // IdleTimeout closes the processes which have not been used for this long
+	} `ini:"git.cat_file_pool"`
 }{
 	DisableDiffHighlight:      false,
 	MaxGitDiffLines:           1000,
@@ -52,6 +63,8 @@ var Git = struct {
 	PullRequestPushMessage:    true,
 	LargeObjectThreshold:      1024 * 1024,
 	DisablePartialClone:       false,
+	PartialCloneFilters:       []string{},
+	MaintenanceAfterPushSize:  50 * 1024 * 1024,
 	Timeout: struct {
 		Default int
 		Migrate int
@@ -67,6 +80,17 @@ var Git = struct {
 		Pull:    300,
 		GC:      60,
 	},
+	CatFilePool: struct {
+		Enabled             bool
+		MaxProcessesPerRepo int
+		MaxIdleProcesses    int
+		IdleTimeout         time.Duration
+	}{
+		Enabled:             false,
+		MaxProcessesPerRepo: 4,
+		MaxIdleProcesses:    128,
+		IdleTimeout:         time.Minute,
+	},
 }
 
 type GitConfigType struct {
@@ -90,6 +114,9 @@ func loadGitFrom(rootCfg ConfigProvider) {
 	if err := sec.MapTo(&Git); err != nil {
 		log.Fatal("Failed to map Git settings: %v", err)
 	}
+	if Git.CatFilePool.IdleTimeout <= 0 {
+		Git.CatFilePool.IdleTimeout = time.Minute
+	}
 
 	secGitConfig := rootCfg.Section("git.config")
 	GitConfig.Options = make(map[string]string)
@@ -5,6 +5,7 @@ package setting
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -63,3 +64,31 @@ EXPIRATION = 123
 	assert.EqualValues(t, "false", GitConfig.GetOption("core.logAllRefUpdates"))
 	assert.EqualValues(t, "123", GitConfig.GetOption("gc.reflogExpire"))
 }
+
+func TestGitCatFilePool(t *testing.T) {
+	oldGit := Git
+	oldGitConfig := GitConfig
+	defer func() {
+		Git = oldGit
+		GitConfig = oldGitConfig
+	}()
+
+	cfg, err := NewConfigProviderFromData(``)
+	assert.NoError(t, err)
+	loadGitFrom(cfg)
+	assert.False(t, Git.CatFilePool.Enabled)
+	assert.EqualValues(t, 4, Git.CatFilePool.MaxProcessesPerRepo)
+	assert.EqualValues(t, time.Minute, Git.CatFilePool.IdleTimeout)
+
+	cfg, err = NewConfigProviderFromData(`
+[git.cat_file_pool]
+ENABLED = true
+MAX_PROCESSES_PER_REPO = 2
+IDLE_TIMEOUT = 10s
+`)
+	assert.NoError(t, err)
+	loadGitFrom(cfg)
+	assert.True(t, Git.CatFilePool.Enabled)
+	assert.EqualValues(t, 2, Git.CatFilePool.MaxProcessesPerRepo)
+	assert.EqualValues(t, 10*time.Second, Git.CatFilePool.IdleTimeout)
+}
@@ -31,6 +31,10 @@ var Indexer = struct {
 	IncludePatterns      []*GlobMatcher
 	ExcludePatterns      []*GlobMatcher
 	ExcludeVendored      bool
+
+	WikiIndexerEnabled bool
+	WikiPath           string
+	WikiIndexerName    string
 }{
 	IssueType:        "bleve",
 	IssuePath:        "indexers/issues.bleve",
@@ -46,6 +50,10 @@ var Indexer = struct {
 	RepoIndexerName:      "gitea_codes",
 	MaxIndexerFileSize:   1024 * 1024,
 	ExcludeVendored:      true,
+
+	WikiIndexerEnabled: false,
+	WikiPath:           "indexers/wikis.bleve",
+	WikiIndexerName:    "gitea_wikis",
 }
 
 func loadIndexerFrom(rootCfg ConfigProvider) {
@@ -87,6 +95,14 @@ func loadIndexerFrom(rootCfg ConfigProvider) {
 	Indexer.ExcludePatterns = IndexerGlobFromString(sec.Key("REPO_INDEXER_EXCLUDE").MustString(""))
 	Indexer.ExcludeVendored = sec.Key("REPO_INDEXER_EXCLUDE_VENDORED").MustBool(true)
 	Indexer.MaxIndexerFileSize = sec.Key("MAX_FILE_SIZE").MustInt64(1024 * 1024)
+
+	// the wiki indexer shares the type and connection of the repo indexer
+	Indexer.WikiIndexerEnabled = Indexer.RepoIndexerEnabled && sec.Key("WIKI_INDEXER_ENABLED").MustBool(false)
+	Indexer.WikiPath = filepath.ToSlash(sec.Key("WIKI_INDEXER_PATH").MustString(filepath.ToSlash(filepath.Join(AppDataPath, "indexers/wikis.bleve"))))
+	if !filepath.IsAbs(Indexer.WikiPath) {
+		Indexer.WikiPath = filepath.ToSlash(filepath.Join(AppWorkPath, Indexer.WikiPath))
+	}
+	Indexer.WikiIndexerName = sec.Key("WIKI_INDEXER_NAME").MustString("gitea_wikis")
 	Indexer.StartupTimeout = sec.Key("STARTUP_TIMEOUT").MustDuration(30 * time.Second)
 }
 
@@ -30,6 +30,7 @@ var Markdown = struct {
 	CustomURLSchemes               []string `ini:"CUSTOM_URL_SCHEMES"`
 	FileExtensions                 []string
 	EnableMath                     bool
+	EnableMathServerRender         bool
 }{
 	EnableHardLineBreakInComments:  true,
 	EnableHardLineBreakInDocuments: false,
@@ -47,6 +47,7 @@ var (
 		DisableMigrations                       bool
 		DisableStars                            bool `ini:"DISABLE_STARS"`
 		DefaultBranch                           string
+		DefaultObjectFormat                     string
 		AllowAdoptionOfUnadoptedRepositories    bool
 		AllowDeleteOfUnadoptedRepositories      bool
 		DisableDownloadSourceArchives           bool
@@ -86,6 +87,7 @@ var (
 			AddCoCommitterTrailers                   bool
 			TestConflictingPatchesWithGitApply       bool
 			RetargetChildrenOnMerge                  bool
+			MergeQueueBatchSize                      int
 		} `ini:"repository.pull-request"`
 
 		// Issue Setting
@@ -95,8 +97,9 @@ var (
 		} `ini:"repository.issue"`
 
 		Release struct {
-			AllowedTypes     string
-			DefaultPagingNum int
+			AllowedTypes                 string
+			DefaultPagingNum             int
+			ExternalAssetAllowedHostList string
 		} `ini:"repository.release"`
 
 		Signing struct {
@@ -108,6 +111,7 @@ var (
 			Merges            []string
 			Wiki              []string
 			DefaultTrustModel string
+			SignWithUserKeys  bool
 		} `ini:"repository.signing"`
 	}{
 		DetectedCharsetsOrder: []string{
@@ -165,6 +169,7 @@ var (
 		DisableMigrations:                       false,
 		DisableStars:                            false,
 		DefaultBranch:                           "main",
+		DefaultObjectFormat:                     "sha1",
 		AllowForkWithoutMaximumLimit:            true,
 
 		// Repository editor settings
@@ -211,6 +216,7 @@ var (
 			AddCoCommitterTrailers                   bool
 			TestConflictingPatchesWithGitApply       bool
 			RetargetChildrenOnMerge                  bool
+			MergeQueueBatchSize                      int
 		}{
 			WorkInProgressPrefixes: []string{"WIP:", "[WIP]"},
 			// Same as GitHub. See
@@ -226,6 +232,7 @@ var (
 			PopulateSquashCommentWithCommitMessages:  false,
 			AddCoCommitterTrailers:                   true,
 			RetargetChildrenOnMerge:                  true,
+			MergeQueueBatchSize:                      5,
 		},
 
 		// Issue settings
@@ -238,11 +245,13 @@ var (
 		},
 
 		Release: struct {
-			AllowedTypes     string
-			DefaultPagingNum int
+			AllowedTypes                 string
+			DefaultPagingNum             int
+			ExternalAssetAllowedHostList string
 		}{
-			AllowedTypes:     "",
-			DefaultPagingNum: 10,
+			AllowedTypes:                 "",
+			DefaultPagingNum:             10,
+			ExternalAssetAllowedHostList: "",
 		},
 
 		// Signing settings
@@ -255,6 +264,7 @@ var (
 			Merges            []string
 			Wiki              []string
 			DefaultTrustModel string
+			SignWithUserKeys  bool
 		}{
 			SigningKey:        "default",
 			SigningName:       "",
@@ -279,6 +289,7 @@ func loadRepositoryFrom(rootCfg ConfigProvider) {
 	Repository.GoGetCloneURLProtocol = sec.Key("GO_GET_CLONE_URL_PROTOCOL").MustString("https")
 	Repository.MaxCreationLimit = sec.Key("MAX_CREATION_LIMIT").MustInt(-1)
 	Repository.DefaultBranch = sec.Key("DEFAULT_BRANCH").MustString(Repository.DefaultBranch)
+	Repository.DefaultObjectFormat = strings.ToLower(sec.Key("DEFAULT_OBJECT_FORMAT").In(Repository.DefaultObjectFormat, []string{"sha1", "sha256"}))
 	RepoRootPath = sec.Key("ROOT").MustString(path.Join(AppDataPath, "gitea-repositories"))
 	if !filepath.IsAbs(RepoRootPath) {
 		RepoRootPath = filepath.Join(AppWorkPath, RepoRootPath)
@@ -129,6 +129,8 @@ var (
 	Actions ObjectStorage = uninitializedStorage
 	// Actions Artifacts represents actions artifacts storage
 	ActionsArtifacts ObjectStorage = uninitializedStorage
+	// ActionsCache represents the storage of the caches of actions/cache
+	ActionsCache ObjectStorage = uninitializedStorage
 )
 
 // Init init the stoarge
@@ -214,6 +216,7 @@ func initActions() (err error) {
 	if !setting.Actions.Enabled {
 		Actions = discardStorage("Actions isn't enabled")
 		ActionsArtifacts = discardStorage("ActionsArtifacts isn't enabled")
+		ActionsCache = discardStorage("ActionsCache isn't enabled")
 		return nil
 	}
 	log.Info("Initialising Actions storage with type: %s", setting.Actions.LogStorage.Type)
@@ -221,6 +224,10 @@ func initActions() (err error) {
 		return err
 	}
 	log.Info("Initialising ActionsArtifacts storage with type: %s", setting.Actions.ArtifactStorage.Type)
-	ActionsArtifacts, err = NewStorage(setting.Actions.ArtifactStorage.Type, setting.Actions.ArtifactStorage)
+	if ActionsArtifacts, err = NewStorage(setting.Actions.ArtifactStorage.Type, setting.Actions.ArtifactStorage); err != nil {
+		return err
+	}
+	log.Info("Initialising ActionsCache storage with type: %s", setting.Actions.CacheStorage.Type)
+	ActionsCache, err = NewStorage(setting.Actions.CacheStorage.Type, setting.Actions.CacheStorage)
 	return err
 }
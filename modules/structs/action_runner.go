@@ -0,0 +1,115 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// ActionRunner represents a runner
+type ActionRunner struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// the labels the jobs are matched with
+	Labels []*ActionRunnerLabel `json:"labels"`
+	// the id of the runner group of the runner, 0 if it isn't in a group
+	GroupID int64 `json:"runner_group_id"`
+	// whether the runner runs a single job and is removed once it's done
+	Ephemeral bool `json:"ephemeral"`
+}
+
+// ActionRunnersResponse returns runners
+type ActionRunnersResponse struct {
+	Entries    []*ActionRunner `json:"runners"`
+	TotalCount int64           `json:"total_count"`
+}
+
+// ActionRunnerLabel represents a label of a runner
+type ActionRunnerLabel struct {
+	Name string `json:"name"`
+	// "read-only" for a label declared by the runner itself, "custom" for a label added with the API
+	// enum: read-only,custom
+	Type string `json:"type"`
+}
+
+// ActionRunnerLabelsResponse returns the labels of a runner
+type ActionRunnerLabelsResponse struct {
+	Labels     []*ActionRunnerLabel `json:"labels"`
+	TotalCount int64                `json:"total_count"`
+}
+
+// ActionRunnerLabelsOption represents the custom labels to add to a runner or to replace its custom labels with
+type ActionRunnerLabelsOption struct {
+	// required: true
+	Labels []string `json:"labels" binding:"Required"`
+}
+
+// ActionRunnerGroup represents a group of runners of an organization or of the instance
+type ActionRunnerGroup struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// "all" if the runners can be used by all the repositories of the owner of the group,
+	// "selected" if they can only be used by the repositories and the organizations the group is granted to
+	// enum: all,selected
+	Visibility string `json:"visibility"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ActionRunnerGroupsResponse returns runner groups
+type ActionRunnerGroupsResponse struct {
+	Entries    []*ActionRunnerGroup `json:"runner_groups"`
+	TotalCount int64                `json:"total_count"`
+}
+
+// CreateActionRunnerGroupOption represents the options to create a runner group
+type CreateActionRunnerGroupOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(255)"`
+	Description string `json:"description"`
+	// enum: all,selected
+	Visibility string `json:"visibility" binding:"In(,all,selected)"`
+}
+
+// EditActionRunnerGroupOption represents the options to edit a runner group
+type EditActionRunnerGroupOption struct {
+	Name        *string `json:"name" binding:"MaxSize(255)"`
+	Description *string `json:"description"`
+	// enum: all,selected
+	Visibility *string `json:"visibility"`
+}
+
+// CreateActionEphemeralRunnerTokenOption represents the options to create a token registering an ephemeral runner
+type CreateActionEphemeralRunnerTokenOption struct {
+	// the number of seconds the token can register a runner, an hour if it's 0, a day at most
+	TTL int64 `json:"ttl"`
+}
+
+// ActionEphemeralRunnerToken represents a token registering a single ephemeral runner, which runs a single job
+type ActionEphemeralRunnerToken struct {
+	Token string `json:"token"`
+	// swagger:strfmt date-time
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ActionQueuedJobs represents the number of the jobs waiting for a runner with a set of labels
+type ActionQueuedJobs struct {
+	// the labels the jobs run on, sorted
+	Labels []string `json:"labels"`
+	Count  int64    `json:"count"`
+	// when the job waiting for the longest time has been queued
+	// swagger:strfmt date-time
+	OldestQueuedAt time.Time `json:"oldest_queued_at"`
+}
+
+// ActionQueuedJobsResponse returns the jobs waiting for a runner by set of labels
+type ActionQueuedJobsResponse struct {
+	Entries []*ActionQueuedJobs `json:"queued_jobs"`
+	// the number of the jobs waiting for a runner
+	TotalCount int64 `json:"total_count"`
+}
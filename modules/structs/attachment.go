@@ -18,6 +18,29 @@ type Attachment struct {
 	Created     time.Time `json:"created_at"`
 	UUID        string    `json:"uuid"`
 	DownloadURL string    `json:"browser_download_url"`
+	// "attachment" for files stored by Gitea, "external" for files hosted elsewhere
+	Type string `json:"type"`
+	// expected SHA-256 checksum of an external attachment
+	SHA256 string `json:"sha256,omitempty"`
+	// result of the last verification of an external attachment: unverified, verified, mismatch or unreachable
+	VerificationStatus string `json:"verification_status,omitempty"`
+	// swagger:strfmt date-time
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+}
+
+// CreateExternalAttachmentOption options for adding a file hosted outside of Gitea as an attachment
+// swagger:model
+type CreateExternalAttachmentOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// required: true
+	URL string `json:"url" binding:"Required;ValidUrl"`
+	// hex encoded SHA-256 checksum of the file
+	// required: true
+	SHA256 string `json:"sha256" binding:"Required"`
+	// size of the file in bytes
+	// required: true
+	Size int64 `json:"size" binding:"Required"`
 }
 
 // EditAttachmentOptions options for editing attachments
@@ -3,6 +3,10 @@
 
 package structs
 
+import (
+	"time"
+)
+
 // GitHook represents a Git repository hook
 type GitHook struct {
 	Name     string `json:"name"`
@@ -17,3 +21,46 @@ type GitHookList []*GitHook
 type EditGitHookOption struct {
 	Content string `json:"content"`
 }
+
+// ManagedGitHook represents the git hooks and push checks managed by the instance administrators
+// for all the repositories of an organization
+type ManagedGitHook struct {
+	// maximum size in bytes of a pushed file, 0 means unlimited
+	MaxFileSize int64 `json:"max_file_size"`
+	// glob patterns of the paths which can't be pushed
+	ForbiddenPaths []string `json:"forbidden_paths"`
+	// regular expression which the messages of the pushed commits must match
+	CommitMessagePattern string `json:"commit_message_pattern"`
+	PreReceiveScript     string `json:"pre_receive_script"`
+	UpdateScript         string `json:"update_script"`
+	// whether the repository administrators can opt out
+	AllowRepoOptOut bool `json:"allow_repo_opt_out"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditManagedGitHookOption options for setting the managed git hook of an organization
+type EditManagedGitHookOption struct {
+	// maximum size in bytes of a pushed file, 0 means unlimited
+	MaxFileSize int64 `json:"max_file_size"`
+	// glob patterns of the paths which can't be pushed
+	ForbiddenPaths []string `json:"forbidden_paths"`
+	// regular expression which the messages of the pushed commits must match
+	CommitMessagePattern string `json:"commit_message_pattern"`
+	// script run as pre-receive hook, it requires git hooks to be enabled
+	PreReceiveScript string `json:"pre_receive_script"`
+	// script run as update hook, it requires git hooks to be enabled
+	UpdateScript string `json:"update_script"`
+	// whether the repository administrators can opt out
+	AllowRepoOptOut bool `json:"allow_repo_opt_out"`
+}
+
+// RepoManagedGitHook represents the managed git hook which applies to a repository
+type RepoManagedGitHook struct {
+	// the managed git hook of the repository owner, null if there is none
+	ManagedGitHook *ManagedGitHook `json:"managed_git_hook"`
+	// whether the repository opted out of the managed git hook
+	OptedOut bool `json:"opted_out"`
+	// whether the managed git hook applies to the pushes to the repository
+	Active bool `json:"active"`
+}
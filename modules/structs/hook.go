@@ -20,6 +20,7 @@ type Hook struct {
 	ID                  int64             `json:"id"`
 	Type                string            `json:"type"`
 	BranchFilter        string            `json:"branch_filter"`
+	PackageTypeFilter   string            `json:"package_type_filter"`
 	URL                 string            `json:"-"`
 	Config              map[string]string `json:"config"`
 	Events              []string          `json:"events"`
@@ -47,6 +48,7 @@ type CreateHookOption struct {
 	Config              CreateHookOptionConfig `json:"config" binding:"Required"`
 	Events              []string               `json:"events"`
 	BranchFilter        string                 `json:"branch_filter" binding:"GlobPattern"`
+	PackageTypeFilter   string                 `json:"package_type_filter" binding:"GlobPattern"`
 	AuthorizationHeader string                 `json:"authorization_header"`
 	// default: false
 	Active bool `json:"active"`
@@ -57,6 +59,7 @@ type EditHookOption struct {
 	Config              map[string]string `json:"config"`
 	Events              []string          `json:"events"`
 	BranchFilter        string            `json:"branch_filter" binding:"GlobPattern"`
+	PackageTypeFilter   string            `json:"package_type_filter" binding:"GlobPattern"`
 	AuthorizationHeader string            `json:"authorization_header"`
 	Active              *bool             `json:"active"`
 }
@@ -114,6 +117,7 @@ var (
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
 	_ Payloader = &PackagePayload{}
+	_ Payloader = &WorkflowDispatchPayload{}
 )
 
 // _________                        __
@@ -280,6 +284,8 @@ type PushPayload struct {
 	Repo         *Repository      `json:"repository"`
 	Pusher       *User            `json:"pusher"`
 	Sender       *User            `json:"sender"`
+	// the options given with `git push -o`, an option without value has an empty value
+	PushOptions map[string]string `json:"push_options,omitempty"`
 }
 
 // JSONPayload FIXME
@@ -475,22 +481,42 @@ func (p *RepositoryPayload) JSONPayload() ([]byte, error) {
 type HookPackageAction string
 
 const (
-	// HookPackageCreated created
+	// HookPackageCreated created, a version of the package was published
 	HookPackageCreated HookPackageAction = "created"
-	// HookPackageDeleted deleted
+	// HookPackageDeleted deleted, a version of the package was deleted
 	HookPackageDeleted HookPackageAction = "deleted"
+	// HookPackageRemoved removed, the package was deleted with all its versions
+	HookPackageRemoved HookPackageAction = "removed"
 )
 
 // PackagePayload represents a package payload
 type PackagePayload struct {
-	Action       HookPackageAction `json:"action"`
-	Repository   *Repository       `json:"repository"`
-	Package      *Package          `json:"package"`
-	Organization *User             `json:"organization"`
-	Sender       *User             `json:"sender"`
+	Action     HookPackageAction `json:"action"`
+	Repository *Repository       `json:"repository"`
+	Package    *Package          `json:"package"`
+	// files of the published version, only set for the created action
+	Files []*PackageFile `json:"files,omitempty"`
+	// deleted versions, only set for the removed action
+	Versions     []string `json:"versions,omitempty"`
+	Organization *User    `json:"organization"`
+	Sender       *User    `json:"sender"`
 }
 
 // JSONPayload implements Payload
 func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
+
+// WorkflowDispatchPayload represents the payload of a workflow run manually
+type WorkflowDispatchPayload struct {
+	Workflow   string            `json:"workflow"`
+	Ref        string            `json:"ref"`
+	Inputs     map[string]string `json:"inputs"`
+	Repository *Repository       `json:"repository"`
+	Sender     *User             `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowDispatchPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
@@ -44,19 +44,21 @@ type RepositoryMeta struct {
 // Issue represents an issue in a repository
 // swagger:model
 type Issue struct {
-	ID               int64         `json:"id"`
-	URL              string        `json:"url"`
-	HTMLURL          string        `json:"html_url"`
-	Index            int64         `json:"number"`
-	Poster           *User         `json:"user"`
-	OriginalAuthor   string        `json:"original_author"`
-	OriginalAuthorID int64         `json:"original_author_id"`
-	Title            string        `json:"title"`
-	Body             string        `json:"body"`
-	Ref              string        `json:"ref"`
-	Attachments      []*Attachment `json:"assets"`
-	Labels           []*Label      `json:"labels"`
-	Milestone        *Milestone    `json:"milestone"`
+	ID               int64             `json:"id"`
+	URL              string            `json:"url"`
+	HTMLURL          string            `json:"html_url"`
+	Index            int64             `json:"number"`
+	Poster           *User             `json:"user"`
+	OriginalAuthor   string            `json:"original_author"`
+	OriginalAuthorID int64             `json:"original_author_id"`
+	Title            string            `json:"title"`
+	Body             string            `json:"body"`
+	Ref              string            `json:"ref"`
+	Attachments      []*Attachment     `json:"assets"`
+	Labels           []*Label          `json:"labels"`
+	Milestone        *Milestone        `json:"milestone"`
+	Type             *IssueType        `json:"type"`
+	SubIssues        *SubIssueProgress `json:"sub_issues,omitempty"`
 	// deprecated
 	Assignee  *User   `json:"assignee"`
 	Assignees []*User `json:"assignees"`
@@ -67,6 +69,8 @@ type Issue struct {
 	State    StateType `json:"state"`
 	IsLocked bool      `json:"is_locked"`
 	Comments int       `json:"comments"`
+	// number of the votes for the issue, which are separate from the reactions
+	Votes int `json:"votes"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -98,6 +102,13 @@ type CreateIssueOption struct {
 	// list of label ids
 	Labels []int64 `json:"labels"`
 	Closed bool    `json:"closed"`
+	// issue type id
+	Type int64 `json:"type"`
+	// file name of the issue form the issue is created from, like .gitea/ISSUE_TEMPLATE/bug.yaml,
+	// the body is rendered from the values of the fields of the form
+	Template string `json:"template"`
+	// values of the fields of the issue form by their ids, the values of dropdowns and checkboxes are the labels of the selected options
+	FormValues map[string][]string `json:"form_values"`
 }
 
 // EditIssueOption options for editing an issue
@@ -109,7 +120,9 @@ type EditIssueOption struct {
 	Assignee  *string  `json:"assignee"`
 	Assignees []string `json:"assignees"`
 	Milestone *int64   `json:"milestone"`
-	State     *string  `json:"state"`
+	// issue type id, 0 to remove the type
+	Type  *int64  `json:"type"`
+	State *string `json:"state"`
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
@@ -174,6 +187,14 @@ const (
 	IssueFormFieldVisibleContent IssueFormFieldVisible = "content"
 )
 
+// IssueFormFieldError represents an invalid value of a field of an issue form
+// swagger:model
+type IssueFormFieldError struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Message string `json:"message"`
+}
+
 // IssueTemplate represents an issue template for a repository
 // swagger:model
 type IssueTemplate struct {
@@ -258,6 +279,13 @@ func (it IssueTemplate) Type() IssueTemplateType {
 	return ""
 }
 
+// SubIssueProgress the progress of the sub-issues of an issue, omitted if the issue doesn't have any
+type SubIssueProgress struct {
+	Total            int64 `json:"total"`
+	Closed           int64 `json:"closed"`
+	PercentCompleted int   `json:"percent_completed"`
+}
+
 // IssueMeta basic issue information
 // swagger:model
 type IssueMeta struct {
@@ -0,0 +1,83 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// IssueAutomationRule a rule periodically applied to the open issues or pull requests of a repository
+// without activity for some days, or with a label added some days ago
+// swagger:model
+type IssueAutomationRule struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// enum: issues,pulls,all
+	Target string `json:"target"`
+	// label the issues must have, the days are then counted since it was added
+	LabelID int64 `json:"label_id"`
+	// number of days without activity, or since the label was added
+	Days int `json:"days"`
+	// labels of the issues which the rule never applies to
+	ExemptLabels []int64 `json:"exempt_labels"`
+	// enum: label,comment,close
+	Action string `json:"action"`
+	// label added by the label action
+	ActionLabelID int64 `json:"action_label_id"`
+	// comment posted by the comment action, and with the other actions if it isn't empty
+	Comment string `json:"comment"`
+	// whether the rule only records the actions it would take in the logs
+	DryRun bool `json:"dry_run"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateIssueAutomationRuleOption options for creating an automation rule of the issues of a repository
+type CreateIssueAutomationRuleOption struct {
+	// required:true
+	Name string `json:"name" binding:"Required;MaxSize(50)"`
+	// enum: issues,pulls,all
+	Target  string `json:"target"`
+	LabelID int64  `json:"label_id"`
+	// required:true
+	Days         int     `json:"days" binding:"Required"`
+	ExemptLabels []int64 `json:"exempt_labels"`
+	// required:true
+	// enum: label,comment,close
+	Action        string `json:"action" binding:"Required"`
+	ActionLabelID int64  `json:"action_label_id"`
+	Comment       string `json:"comment"`
+	DryRun        bool   `json:"dry_run"`
+}
+
+// EditIssueAutomationRuleOption options for editing an automation rule of the issues of a repository
+type EditIssueAutomationRuleOption struct {
+	Name *string `json:"name"`
+	// enum: issues,pulls,all
+	Target       *string `json:"target"`
+	LabelID      *int64  `json:"label_id"`
+	Days         *int    `json:"days"`
+	ExemptLabels []int64 `json:"exempt_labels"`
+	// enum: label,comment,close
+	Action        *string `json:"action"`
+	ActionLabelID *int64  `json:"action_label_id"`
+	Comment       *string `json:"comment"`
+	DryRun        *bool   `json:"dry_run"`
+}
+
+// IssueAutomationLog an action taken by an automation rule on an issue or a pull request
+// swagger:model
+type IssueAutomationLog struct {
+	ID       int64  `json:"id"`
+	RuleID   int64  `json:"rule_id"`
+	RuleName string `json:"rule_name"`
+	// index of the issue or the pull request, 0 if it has been deleted
+	IssueIndex int64 `json:"issue_index"`
+	// enum: label,comment,close
+	Action string `json:"action"`
+	// whether the action was only recorded by a dry-run rule
+	DryRun bool `json:"dry_run"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
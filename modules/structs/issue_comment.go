@@ -18,6 +18,8 @@ type Comment struct {
 	OriginalAuthorID int64         `json:"original_author_id"`
 	Body             string        `json:"body"`
 	Attachments      []*Attachment `json:"assets"`
+	// whether the comment is only visible to the users who can write the issue
+	Internal bool `json:"internal"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -28,6 +30,8 @@ type Comment struct {
 type CreateIssueCommentOption struct {
 	// required:true
 	Body string `json:"body" binding:"Required"`
+	// post the comment only for the users who can write the issue
+	Internal bool `json:"internal"`
 }
 
 // EditIssueCommentOption options for editing a comment
@@ -46,6 +50,8 @@ type TimelineComment struct {
 	IssueURL string `json:"issue_url"`
 	Poster   *User  `json:"user"`
 	Body     string `json:"body"`
+	// whether the comment is only visible to the users who can write the issue
+	Internal bool `json:"internal"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -0,0 +1,58 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// IssueField a custom field of issues and pull requests
+// swagger:model
+type IssueField struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// enum: text,number,date,select,multi_select,user
+	Type string `json:"type"`
+	// options of a select or multi_select field
+	Options []string `json:"options"`
+	// whether the field is defined by the organization owning the repository
+	IsOrgField bool `json:"is_org_field"`
+}
+
+// CreateIssueFieldOption options for creating a custom field
+type CreateIssueFieldOption struct {
+	// required:true
+	Name        string `json:"name" binding:"Required;MaxSize(50)"`
+	Description string `json:"description"`
+	// required:true
+	// enum: text,number,date,select,multi_select,user
+	Type    string   `json:"type" binding:"Required;In(text,number,date,select,multi_select,user)"`
+	Options []string `json:"options"`
+}
+
+// EditIssueFieldOption options for editing a custom field, the type of a field can't be changed
+type EditIssueFieldOption struct {
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Options     []string `json:"options"`
+}
+
+// IssueFieldValue the values of a custom field of an issue
+// swagger:model
+type IssueFieldValue struct {
+	Field *IssueField `json:"field"`
+	// numbers are formatted canonically, dates as 2006-01-02 and users by their names
+	Values []string `json:"values"`
+}
+
+// IssueFieldValueOption the values to set to a custom field of an issue
+type IssueFieldValueOption struct {
+	// required:true
+	FieldID int64 `json:"field_id" binding:"Required"`
+	// empty to remove the values, users can be referenced by their ids or names
+	Values []string `json:"values"`
+}
+
+// EditIssueFieldsOption options for editing the custom field values of an issue
+type EditIssueFieldsOption struct {
+	// required:true
+	Fields []IssueFieldValueOption `json:"fields" binding:"Required"`
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// IssueFilter a saved combination of filters of an issue list
+// swagger:model
+type IssueFilter struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// identifies the filter in the url of the issue list, like issues?filter=my-bugs
+	Slug string `json:"slug"`
+	// labels the issues must have, a negative id excludes the issues with the label and 0 selects the issues without labels
+	Labels []int64 `json:"labels"`
+	// assignee of the issues, -1 selects the issues without assignee
+	AssigneeID int64 `json:"assignee_id"`
+	// milestone of the issues, -1 selects the issues without milestone
+	MilestoneID int64 `json:"milestone_id"`
+	// type of the issues, -1 selects the issues without type
+	TypeID int64 `json:"type_id"`
+	// enum: open,closed,all
+	State   string `json:"state"`
+	Keyword string `json:"keyword"`
+	// enum: newest,oldest,recentupdate,leastupdate,mostcomment,leastcomment,mostvotes,leastvotes,nearduedate,farduedate,priority
+	Sort string `json:"sort"`
+	// whether the filter is applied to the issue list of the repository opened without any filter
+	IsDefault bool `json:"is_default"`
+	// whether the filter is saved by the organization owning the repository
+	IsOrgFilter bool `json:"is_org_filter"`
+	// whether the filter is a personal filter of the user, only visible to them
+	IsPersonal bool `json:"is_personal"`
+	// query string of the issue list with the filters
+	Query string `json:"query"`
+}
+
+// CreateIssueFilterOption options for saving a filter of an issue list
+type CreateIssueFilterOption struct {
+	// required:true
+	Name string `json:"name" binding:"Required;MaxSize(50)"`
+	// derived from the name if it's empty
+	Slug        string  `json:"slug" binding:"MaxSize(50)"`
+	Labels      []int64 `json:"labels"`
+	AssigneeID  int64   `json:"assignee_id"`
+	MilestoneID int64   `json:"milestone_id"`
+	TypeID      int64   `json:"type_id"`
+	// enum: open,closed,all
+	State   string `json:"state"`
+	Keyword string `json:"keyword"`
+	// enum: newest,oldest,recentupdate,leastupdate,mostcomment,leastcomment,mostvotes,leastvotes,nearduedate,farduedate,priority
+	Sort      string `json:"sort"`
+	IsDefault bool   `json:"is_default"`
+	// whether the filter of a repository is shared with all its users instead of being personal,
+	// only the writers of the issues can share a filter. The filters of an organization are always shared.
+	Shared bool `json:"shared"`
+}
+
+// EditIssueFilterOption options for editing a saved filter of an issue list
+type EditIssueFilterOption struct {
+	Name        *string `json:"name"`
+	Slug        *string `json:"slug"`
+	Labels      []int64 `json:"labels"`
+	AssigneeID  *int64  `json:"assignee_id"`
+	MilestoneID *int64  `json:"milestone_id"`
+	TypeID      *int64  `json:"type_id"`
+	// enum: open,closed,all
+	State   *string `json:"state"`
+	Keyword *string `json:"keyword"`
+	// enum: newest,oldest,recentupdate,leastupdate,mostcomment,leastcomment,mostvotes,leastvotes,nearduedate,farduedate,priority
+	Sort      *string `json:"sort"`
+	IsDefault *bool   `json:"is_default"`
+}
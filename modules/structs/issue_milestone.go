@@ -7,7 +7,7 @@ import (
 	"time"
 )
 
-// Milestone milestone is a collection of issues on one repository
+// Milestone milestone is a collection of issues on one repository, or on all the repositories of an organization
 type Milestone struct {
 	ID           int64     `json:"id"`
 	Title        string    `json:"title"`
@@ -15,6 +15,8 @@ type Milestone struct {
 	State        StateType `json:"state"`
 	OpenIssues   int       `json:"open_issues"`
 	ClosedIssues int       `json:"closed_issues"`
+	// whether the milestone belongs to the organization and is shared by its repositories
+	IsOrgMilestone bool `json:"is_org_milestone"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -42,3 +44,25 @@ type EditMilestoneOption struct {
 	State       *string    `json:"state"`
 	Deadline    *time.Time `json:"due_on"`
 }
+
+// MilestoneBurndownDay the issues of a milestone at the end of a day
+type MilestoneBurndownDay struct {
+	// swagger:strfmt date
+	Date         string `json:"date"`
+	OpenIssues   int    `json:"open_issues"`
+	ClosedIssues int    `json:"closed_issues"`
+}
+
+// MilestoneRepoProgress the issues of a milestone in one repository
+type MilestoneRepoProgress struct {
+	Repository   *RepositoryMeta `json:"repository"`
+	OpenIssues   int             `json:"open_issues"`
+	ClosedIssues int             `json:"closed_issues"`
+}
+
+// MilestoneBurndown the progress of a milestone over time, aggregated across the repositories
+type MilestoneBurndown struct {
+	Milestone *Milestone               `json:"milestone"`
+	Days      []*MilestoneBurndownDay  `json:"days"`
+	Repos     []*MilestoneRepoProgress `json:"repos"`
+}
@@ -19,3 +19,9 @@ type Reaction struct {
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 }
+
+// ReactionSummary contain the number of reactions of a type
+type ReactionSummary struct {
+	Reaction string `json:"content"`
+	Count    int    `json:"count"`
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// IssueType a type of issues, like bug, feature, task or epic
+// swagger:model
+type IssueType struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// example: octicon-bug
+	Icon string `json:"icon"`
+	// example: #00aabb
+	Color string `json:"color"`
+	// default content of the new issues of the type
+	Template string `json:"template"`
+	// fields the new issues of the type must have, can be content, labels, milestone, assignees or deadline
+	RequiredFields []string `json:"required_fields"`
+	// whether the type is defined by the organization owning the repository
+	IsOrgType bool `json:"is_org_type"`
+}
+
+// CreateIssueTypeOption options for creating an issue type
+type CreateIssueTypeOption struct {
+	// required:true
+	Name        string `json:"name" binding:"Required;MaxSize(50)"`
+	Description string `json:"description"`
+	// example: octicon-bug
+	Icon string `json:"icon"`
+	// example: #00aabb
+	Color          string   `json:"color"`
+	Template       string   `json:"template"`
+	RequiredFields []string `json:"required_fields"`
+}
+
+// EditIssueTypeOption options for editing an issue type
+type EditIssueTypeOption struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	// example: octicon-bug
+	Icon *string `json:"icon"`
+	// example: #00aabb
+	Color          *string  `json:"color"`
+	Template       *string  `json:"template"`
+	RequiredFields []string `json:"required_fields"`
+}
@@ -22,10 +22,17 @@ type LFSLockOwner struct {
 	Name string `json:"name"`
 }
 
+// LFSLockRef represents the ref a lock request is made for.
+// Locks are not scoped to refs, the ref is accepted for compatibility only.
+type LFSLockRef struct {
+	Name string `json:"name"`
+}
+
 // LFSLockRequest contains the path of the lock to create
 // https://github.com/git-lfs/git-lfs/blob/master/docs/api/locking.md#create-lock
 type LFSLockRequest struct {
-	Path string `json:"path"`
+	Path string      `json:"path"`
+	Ref  *LFSLockRef `json:"ref,omitempty"`
 }
 
 // LFSLockResponse represent a lock created
@@ -41,6 +48,14 @@ type LFSLockList struct {
 	Next  string     `json:"next_cursor,omitempty"`
 }
 
+// LFSLockListVerifyRequest contains the params of a lock verification request
+// https://github.com/git-lfs/git-lfs/blob/master/docs/api/locking.md#list-locks-for-verification
+type LFSLockListVerifyRequest struct {
+	Cursor string      `json:"cursor,omitempty"`
+	Limit  int         `json:"limit,omitempty"`
+	Ref    *LFSLockRef `json:"ref,omitempty"`
+}
+
 // LFSLockListVerify represent a list of lock verification requested
 // https://github.com/git-lfs/git-lfs/blob/master/docs/api/locking.md#list-locks-for-verification
 type LFSLockListVerify struct {
@@ -60,5 +75,6 @@ type LFSLockError struct {
 // LFSLockDeleteRequest contains params of a delete request
 // https://github.com/git-lfs/git-lfs/blob/master/docs/api/locking.md#delete-lock
 type LFSLockDeleteRequest struct {
-	Force bool `json:"force"`
+	Force bool        `json:"force"`
+	Ref   *LFSLockRef `json:"ref,omitempty"`
 }
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// MergeQueueEntry represents a pull request waiting in the merge queue of its base branch
+type MergeQueueEntry struct {
+	// the 1-based position of the pull request in the queue
+	Position int `json:"position"`
+	// the index of the pull request
+	Index int64 `json:"number"`
+	// the branch the pull request is merged into
+	BaseBranch string `json:"base_branch"`
+	// waiting for its speculative merge commit or testing it
+	State string `json:"state"`
+	// the merge style used to merge the pull request
+	MergeStyle string `json:"merge_style"`
+	// the head commit of the pull request which is merged
+	HeadCommitID string `json:"head_sha"`
+	// the commit the speculative merge commit is created on
+	BaseCommitID string `json:"base_sha,omitempty"`
+	// the speculative merge commit whose checks must succeed before the pull request is merged
+	MergeCommitID string `json:"merge_commit_sha,omitempty"`
+	// the user who added the pull request to the queue
+	Enqueuer *User `json:"enqueued_by"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// AddToMergeQueueOption options for adding a pull request to the merge queue of its base branch
+type AddToMergeQueueOption struct {
+	// the merge style, the default merge style of the repository is used if empty
+	// enum: merge,rebase,rebase-merge,squash,fast-forward-only
+	MergeStyle string `json:"merge_style"`
+	// the title of the merge commit, the default one is used if empty
+	Title string `json:"title"`
+	// the message of the merge commit
+	Message string `json:"message"`
+}
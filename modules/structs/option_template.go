@@ -0,0 +1,28 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// OptionTemplate represents a gitignore, license or label template managed by the instance administrators
+type OptionTemplate struct {
+	// enum: gitignore,license,label
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// content of the template, label templates use the YAML format
+	Content string `json:"content"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditOptionTemplateOption options for creating or updating a gitignore, license or label template
+type EditOptionTemplateOption struct {
+	// content of the template, label templates use the YAML format
+	// required: true
+	Content string `json:"content" binding:"Required"`
+}
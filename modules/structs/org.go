@@ -15,6 +15,8 @@ type Organization struct {
 	Location                  string `json:"location"`
 	Visibility                string `json:"visibility"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	// whether only the members of the organization can vote for the issues of its repositories
+	IssueVotingMembersOnly bool `json:"issue_voting_members_only"`
 	// deprecated
 	UserName string `json:"username"`
 }
@@ -41,6 +43,7 @@ type CreateOrgOption struct {
 	// enum: public,limited,private
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	IssueVotingMembersOnly    bool   `json:"issue_voting_members_only"`
 }
 
 // TODO: make EditOrgOption fields optional after https://gitea.com/go-chi/binding/pulls/5 got merged
@@ -56,4 +59,5 @@ type EditOrgOption struct {
 	// enum: public,limited,private
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess *bool  `json:"repo_admin_change_team_access"`
+	IssueVotingMembersOnly    *bool  `json:"issue_voting_members_only"`
 }
@@ -17,6 +17,10 @@ type Package struct {
 	Name       string      `json:"name"`
 	Version    string      `json:"version"`
 	HTMLURL    string      `json:"html_url"`
+	// whether the versions of the package can only be overwritten or deleted by site admins
+	Immutable bool `json:"immutable"`
+	// name of the owner the version was promoted from
+	PromotedFrom string `json:"promoted_from"`
 	// swagger:strfmt date-time
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -31,3 +35,154 @@ type PackageFile struct {
 	HashSHA256 string `json:"sha256"`
 	HashSHA512 string `json:"sha512"`
 }
+
+// PackageCleanupRule represents a rule which removes the versions of the packages of a type of an owner
+type PackageCleanupRule struct {
+	ID            int64  `json:"id"`
+	Enabled       bool   `json:"enabled"`
+	Type          string `json:"type"`
+	KeepCount     int    `json:"keep_count"`
+	KeepPattern   string `json:"keep_pattern"`
+	RemoveDays    int    `json:"remove_days"`
+	RemovePattern string `json:"remove_pattern"`
+	MatchFullName bool   `json:"match_full_name"`
+	// the untagged manifests of the container images older than the days are removed, whatever the other rules
+	RemoveUntaggedDays int `json:"remove_untagged_days"`
+	// glob patterns of the tags of the container images which are never removed
+	ProtectedTags []string `json:"protected_tags"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreatePackageCleanupRuleOption options for creating a cleanup rule of the packages of a type
+type CreatePackageCleanupRuleOption struct {
+	// required: true
+	// enum: alpine,cargo,chef,composer,conan,conda,container,cran,debian,generic,go,helm,julia,maven,npm,nuget,pub,pypi,rpm,rubygems,swift,terraform,vagrant
+	Type          string `json:"type" binding:"Required"`
+	Enabled       bool   `json:"enabled"`
+	KeepCount     int    `json:"keep_count"`
+	KeepPattern   string `json:"keep_pattern"`
+	RemoveDays    int    `json:"remove_days"`
+	RemovePattern string `json:"remove_pattern"`
+	MatchFullName bool   `json:"match_full_name"`
+	// only for container images
+	RemoveUntaggedDays int `json:"remove_untagged_days"`
+	// only for container images
+	ProtectedTags []string `json:"protected_tags"`
+}
+
+// EditPackageCleanupRuleOption options for editing a cleanup rule, the type can't be changed
+type EditPackageCleanupRuleOption struct {
+	Enabled            *bool    `json:"enabled"`
+	KeepCount          *int     `json:"keep_count"`
+	KeepPattern        *string  `json:"keep_pattern"`
+	RemoveDays         *int     `json:"remove_days"`
+	RemovePattern      *string  `json:"remove_pattern"`
+	MatchFullName      *bool    `json:"match_full_name"`
+	RemoveUntaggedDays *int     `json:"remove_untagged_days"`
+	ProtectedTags      []string `json:"protected_tags"`
+}
+
+// PackageUpstream represents an upstream registry, the missing versions of the packages of a type of an owner are fetched from it
+type PackageUpstream struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SetPackageUpstreamOption options for setting the upstream registry of the packages of a type
+type SetPackageUpstreamOption struct {
+	// base url of the registry, like https://registry.npmjs.org or https://proxy.golang.org
+	//
+	// required: true
+	URL string `json:"url" binding:"Required"`
+}
+
+// PackageAttestation represents a signature or an attestation of a package version
+type PackageAttestation struct {
+	ID int64 `json:"id"`
+	// type of the predicate of the attestation, like https://slsa.dev/provenance/v1
+	PredicateType string `json:"predicate_type"`
+	MediaType     string `json:"media_type"`
+	// content of the attestation, like a sigstore bundle or a DSSE envelope
+	Bundle string `json:"bundle"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreatePackageAttestationOption options for adding a signature or an attestation to a package version
+type CreatePackageAttestationOption struct {
+	// type of the predicate of the attestation, like https://slsa.dev/provenance/v1
+	//
+	// required: true
+	PredicateType string `json:"predicate_type" binding:"Required"`
+	// media type of the bundle, like application/vnd.dev.sigstore.bundle.v0.3+json
+	MediaType string `json:"media_type"`
+	// content of the attestation, like a sigstore bundle or a DSSE envelope
+	//
+	// required: true
+	Bundle string `json:"bundle" binding:"Required"`
+}
+
+// PromotePackageOption options for promoting a package version to another owner
+type PromotePackageOption struct {
+	// name of the user or organization the package version is copied to
+	// required: true
+	Owner string `json:"owner" binding:"Required"`
+}
+
+// PackageAudit represents an action on a package recorded in the audit trail of its owner
+type PackageAudit struct {
+	ID int64 `json:"id"`
+	// enum: publish,delete_version,delete_package,set_immutable,unset_immutable
+	Action string `json:"action"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	// empty for the actions on the whole package
+	Version string `json:"version"`
+	// user who did the action, null for the actions of the system like the cleanup rules
+	Doer *User `json:"doer"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// PackageUsage represents the storage used by the packages of an owner
+type PackageUsage struct {
+	// total size of the package files in bytes
+	TotalSize int64 `json:"total_size"`
+	// maximum total size of the package files in bytes, -1 if it is unlimited
+	TotalSizeLimit int64 `json:"total_size_limit"`
+	// whether the limit is set for the owner instead of the instance setting
+	IsOwnerLimit bool                `json:"is_owner_limit"`
+	Types        []*PackageTypeUsage `json:"types"`
+}
+
+// PackageTypeUsage represents the storage used by the packages of a type
+type PackageTypeUsage struct {
+	Type         string `json:"type"`
+	PackageCount int64  `json:"package_count"`
+	VersionCount int64  `json:"version_count"`
+	Size         int64  `json:"size"`
+}
+
+// SetPackageQuotaOption options for setting the package storage quota of an owner
+type SetPackageQuotaOption struct {
+	// maximum total size of the package files in bytes, -1 for unlimited
+	//
+	// required: true
+	TotalSizeLimit *int64 `json:"total_size_limit" binding:"Required"`
+}
+
+// PackageSize represents the storage used by all versions of a package
+type PackageSize struct {
+	Owner        *User  `json:"owner"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	VersionCount int64  `json:"version_count"`
+	Size         int64  `json:"size"`
+}
@@ -116,3 +116,95 @@ type ChangedFile struct {
 	ContentsURL      string `json:"contents_url,omitempty"`
 	RawURL           string `json:"raw_url,omitempty"`
 }
+
+// PullRequestPush represents a push to the head branch of a pull request
+type PullRequestPush struct {
+	ID          int64 `json:"id"`
+	Pusher      *User `json:"pusher"`
+	IsForcePush bool  `json:"is_force_push"`
+	// the pushed commits, oldest first, or the heads before and after a force push
+	CommitIDs []string `json:"commit_ids"`
+	// the head of the pull request after the push
+	HeadCommitID string `json:"head_commit_id"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// PullRequestChanges represents the changes of a pull request between two of its heads
+type PullRequestChanges struct {
+	BeforeCommitID string         `json:"before_commit_id"`
+	AfterCommitID  string         `json:"after_commit_id"`
+	Files          []*ChangedFile `json:"files"`
+}
+
+// RequiredStatusCheck represents the result of a required status check of a protected branch for a commit
+type RequiredStatusCheck struct {
+	// the required status check context, a glob pattern or an any-of group of patterns separated by "|"
+	Rule string `json:"rule"`
+	// the patterns of the rule
+	Patterns []string `json:"patterns"`
+	// the best state of the patterns, the state of a pattern is the worst state of the statuses matching it
+	// or pending if there is none
+	State CommitStatusState `json:"state"`
+	// whether the rule succeeds
+	Satisfied bool `json:"satisfied"`
+	// the contexts of the commit statuses matching the rule
+	Contexts []string `json:"contexts"`
+}
+
+// PullRequestStatusChecks represents the required status checks of a pull request
+type PullRequestStatusChecks struct {
+	// the head commit of the pull request the statuses belong to
+	CommitID string `json:"commit_id"`
+	// the combined state of the required status checks
+	State CommitStatusState `json:"state"`
+	// the required status checks of the base branch, empty if its protection doesn't enable status checks
+	Checks []*RequiredStatusCheck `json:"checks"`
+	// the rules which don't succeed
+	Unsatisfied []string `json:"unsatisfied"`
+}
+
+// BatchPullRequestOption options for performing an action on several pull requests at once
+type BatchPullRequestOption struct {
+	// indexes of the pull requests
+	// required: true
+	Indexes []int64 `json:"indexes" binding:"Required"`
+	// the action to perform on each pull request
+	// required: true
+	// enum: close,retarget,add_labels,request_reviewers,update
+	Action string `json:"action" binding:"Required;In(close,retarget,add_labels,request_reviewers,update)"`
+	// the new base branch for the retarget action
+	Base string `json:"base"`
+	// the ids of the labels to add for the add_labels action
+	Labels []int64 `json:"labels"`
+	// the names of the users to request reviews from for the request_reviewers action
+	Reviewers []string `json:"reviewers"`
+	// the names of the teams to request reviews from for the request_reviewers action
+	TeamReviewers []string `json:"team_reviewers"`
+	// how the pull requests are updated with their base branch for the update action
+	// enum: merge,rebase
+	Style string `json:"style" binding:"In(,merge,rebase)"`
+}
+
+// BatchPullRequestResult represents the result of a batch action for a pull request
+type BatchPullRequestResult struct {
+	Index int64 `json:"index"`
+	// whether the action succeeded for the pull request
+	Success bool `json:"success"`
+	// why the action failed for the pull request
+	Error string `json:"error,omitempty"`
+}
+
+// PullRequestCodeOwnerRule represents a CODEOWNERS rule matching files changed by a pull request
+type PullRequestCodeOwnerRule struct {
+	// the pattern of the rule in the CODEOWNERS file
+	Pattern string `json:"pattern"`
+	// the changed files matched by the rule
+	Files []string `json:"files"`
+	Users []*User  `json:"users"`
+	Teams []*Team  `json:"teams"`
+	// the owners of the rule who approved the pull request
+	Approvers []*User `json:"approvers"`
+	// whether an owner of the rule approved the pull request
+	Satisfied bool `json:"satisfied"`
+}
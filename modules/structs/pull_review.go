@@ -89,12 +89,53 @@ type CreatePullReviewComment struct {
 	NewLineNum int64 `json:"new_position"`
 }
 
+// EditPullReviewCommentOptions are options to edit a comment of a pending pull review
+type EditPullReviewCommentOptions struct {
+	Body string `json:"body" binding:"Required"`
+}
+
 // SubmitPullReviewOptions are options to submit a pending pull review
 type SubmitPullReviewOptions struct {
 	Event ReviewStateType `json:"event"`
 	Body  string          `json:"body"`
 }
 
+// ApplyPullReviewSuggestionsOptions are options to apply the changes suggested by review comments
+type ApplyPullReviewSuggestionsOptions struct {
+	// ids of the review comments whose suggestions are applied
+	CommentIDs []int64 `json:"comment_ids" binding:"Required"`
+	// message of the commit, a default one is used if it is empty
+	Message string `json:"message"`
+}
+
+// PullReviewViewedFile represents the viewed state of a file changed by a pull request
+type PullReviewViewedFile struct {
+	Path string `json:"path"`
+	// whether the file is marked as viewed
+	Viewed bool `json:"viewed"`
+	// whether the file changed since it was marked as viewed
+	ChangedSinceReview bool `json:"changed_since_review"`
+}
+
+// PullReviewViewedFiles represents the files of a pull request the user marked as viewed
+type PullReviewViewedFiles struct {
+	Files          []*PullReviewViewedFile `json:"files"`
+	NumFiles       int                     `json:"num_files"`
+	NumViewedFiles int                     `json:"num_viewed_files"`
+	// percentage of the changed files marked as viewed
+	Progress int `json:"progress"`
+}
+
+// UpdatePullReviewViewedFilesOptions are options to mark files of a pull request as viewed or unviewed
+type UpdatePullReviewViewedFilesOptions struct {
+	// maps paths of changed files to whether they are viewed
+	Files map[string]bool `json:"files"`
+	// maps directories to whether all the changed files under them are viewed
+	Directories map[string]bool `json:"directories"`
+	// the head commit the files were viewed at, defaults to the current head of the pull request
+	HeadCommitSHA string `json:"head_commit_sha"`
+}
+
 // DismissPullReviewOptions are options to dismiss a pull review
 type DismissPullReviewOptions struct {
 	Message string `json:"message"`
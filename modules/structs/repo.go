@@ -24,6 +24,16 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Keywords closing the referenced issues, the keywords of the instance are used if empty
+	CloseKeywords []string `json:"close_keywords"`
+	// Keywords reopening the referenced issues, the keywords of the instance are used if empty
+	ReopenKeywords []string `json:"reopen_keywords"`
+	// Languages whose keywords are accepted besides the keywords of the repository
+	KeywordLanguages []string `json:"keyword_languages"`
+	// Only close issues by pull requests merged into the default branch
+	CloseOnlyOnDefaultBranch bool `json:"close_only_on_default_branch"`
+	// Only close issues by pull requests in the same milestone
+	CloseOnlyInSameMilestone bool `json:"close_only_in_same_milestone"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -104,6 +114,11 @@ type Repository struct {
 	DefaultDeleteBranchAfterMerge bool             `json:"default_delete_branch_after_merge"`
 	DefaultMergeStyle             string           `json:"default_merge_style"`
 	DefaultAllowMaintainerEdit    bool             `json:"default_allow_maintainer_edit"`
+	MergeStrategy                 string           `json:"merge_strategy"`
+	MergeStrategyOptions          []string         `json:"merge_strategy_options"`
+	EnableMergeQueue              bool             `json:"enable_merge_queue"`
+	RetargetOnBaseDeletion        bool             `json:"retarget_on_base_deletion"`
+	RebaseOnRetarget              bool             `json:"rebase_on_retarget"`
 	AvatarURL                     string           `json:"avatar_url"`
 	Internal                      bool             `json:"internal"`
 	MirrorInterval                string           `json:"mirror_interval"`
@@ -214,6 +229,16 @@ type EditRepoOption struct {
 	DefaultMergeStyle *string `json:"default_merge_style,omitempty"`
 	// set to `true` to allow edits from maintainers by default
 	DefaultAllowMaintainerEdit *bool `json:"default_allow_maintainer_edit,omitempty"`
+	// set to the git merge strategy used to merge pull requests: "ort", "recursive", or "" for the git default.
+	MergeStrategy *string `json:"merge_strategy,omitempty"`
+	// set to the options passed to the merge strategy, e.g. `theirs` or `find-renames=50`.
+	MergeStrategyOptions *[]string `json:"merge_strategy_options,omitempty"`
+	// set to `true` to merge pull requests through the merge queue of their base branch.
+	EnableMergeQueue *bool `json:"enable_merge_queue,omitempty"`
+	// set to `true` to retarget pull requests to the default branch instead of closing them when their base branch is deleted.
+	RetargetOnBaseDeletion *bool `json:"retarget_on_base_deletion,omitempty"`
+	// set to `true` to rebase automatically retargeted pull requests onto their new base branch.
+	RebaseOnRetarget *bool `json:"rebase_on_retarget,omitempty"`
 	// set to `true` to archive this repository.
 	Archived *bool `json:"archived,omitempty"`
 	// set to a string like `8h30m0s` to set the mirror interval time
@@ -32,3 +32,178 @@ type ActionTaskResponse struct {
 	Entries    []*ActionTask `json:"workflow_runs"`
 	TotalCount int64         `json:"total_count"`
 }
+
+// ActionRun represents a run of a workflow
+type ActionRun struct {
+	ID           int64  `json:"id"`
+	RunNumber    int64  `json:"run_number"`
+	DisplayTitle string `json:"display_title"`
+	WorkflowID   string `json:"workflow_id"`
+	HeadBranch   string `json:"head_branch"`
+	HeadSHA      string `json:"head_sha"`
+	Event        string `json:"event"`
+	Status       string `json:"status"`
+	// the number of the attempt of the run, starting at 1 and increased each time the run is rerun
+	RunAttempt int64 `json:"run_attempt"`
+	// the evaluated `concurrency` group of the workflow, the runs of the repository sharing it don't run at the same time
+	ConcurrencyGroup string `json:"concurrency_group"`
+	// whether a later run of the concurrency group cancels this one when it's in progress
+	CancelInProgress bool `json:"cancel_in_progress"`
+	// the inputs of the run if it has been triggered manually by workflow_dispatch
+	Inputs map[string]string `json:"inputs,omitempty"`
+	// the reasons the run has failed before being executed, like the actions the actions policies don't allow
+	FailureAnnotations []string `json:"failure_annotations,omitempty"`
+	HTMLURL            string   `json:"html_url"`
+	// the jobs of the run, only returned when getting a single run
+	Jobs []*ActionRunJob `json:"jobs,omitempty"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	UpdatedAt time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	RunStartedAt time.Time `json:"run_started_at"`
+}
+
+// ActionRunJob represents a job of a run
+type ActionRunJob struct {
+	ID int64 `json:"id"`
+	// the id of the job in the workflow, shared by the jobs expanded from its matrix
+	JobID  string `json:"job_id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// the ids in the workflow of the jobs this job depends on
+	Needs []string `json:"needs"`
+	// the matrix combination of the job, empty if the job has no matrix
+	Matrix  map[string]any `json:"matrix,omitempty"`
+	RunsOn  []string       `json:"runs_on"`
+	Attempt int64          `json:"attempt"`
+	// the evaluated `concurrency` group of the job, the jobs of the repository sharing it don't run at the same time
+	ConcurrencyGroup string `json:"concurrency_group"`
+	// whether a later job of the concurrency group cancels this one when it's in progress
+	CancelInProgress bool `json:"cancel_in_progress"`
+	// swagger:strfmt date-time
+	StartedAt *time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	CompletedAt *time.Time `json:"completed_at"`
+	// the duration of the job in seconds, up to now if it's running
+	Duration int64 `json:"duration"`
+}
+
+// ActionJobStep represents a step of the logs of an action job, including the steps setting up and completing the job
+type ActionJobStep struct {
+	// the position of the step in the job, starting at 0
+	Index  int    `json:"index"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// swagger:strfmt date-time
+	StartedAt *time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// ActionJobLogLine represents a line of the logs of an action job
+type ActionJobLogLine struct {
+	// the index of the step the line belongs to
+	Step int `json:"step"`
+	// the position of the line in the logs of the job, starting at 1
+	Line    int64     `json:"line"`
+	Time    time.Time `json:"time"`
+	Content string    `json:"content"`
+}
+
+// CreateActionWorkflowDispatch represents the options to run a workflow manually
+type CreateActionWorkflowDispatch struct {
+	// the branch or the tag to run the workflow on
+	// required: true
+	Ref string `json:"ref" binding:"Required"`
+	// the values of the inputs of the workflow_dispatch trigger of the workflow
+	Inputs map[string]string `json:"inputs"`
+}
+
+// ActionRunsResponse returns the runs of a repository
+type ActionRunsResponse struct {
+	Entries    []*ActionRun `json:"workflow_runs"`
+	TotalCount int64        `json:"total_count"`
+}
+
+// ActionArtifact represents an artifact uploaded by a run
+type ActionArtifact struct {
+	Name string `json:"name"`
+	// the size of the files of the artifact in bytes
+	Size int64 `json:"size_in_bytes"`
+	// whether the artifact has expired, its files can't be downloaded anymore
+	Expired bool `json:"expired"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ActionArtifactsResponse returns the artifacts of a run
+type ActionArtifactsResponse struct {
+	Entries    []*ActionArtifact `json:"artifacts"`
+	TotalCount int64             `json:"total_count"`
+}
+
+// ActionArtifactFile represents a file inside an artifact
+type ActionArtifactFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// ActionArtifactRetention represents the number of days the artifacts are kept
+type ActionArtifactRetention struct {
+	Days int64 `json:"days"`
+	// the retention can't exceed the one of the owner of the repository, or of the instance
+	MaximumAllowedDays int64 `json:"maximum_allowed_days"`
+}
+
+// EditActionArtifactRetentionOption represents the number of days the artifacts are kept
+type EditActionArtifactRetentionOption struct {
+	// 0 to use the retention of the owner of the repository, or of the instance
+	Days int64 `json:"days"`
+}
+
+// ActionsPolicy represents the actions the workflows of the repositories of an organization, or of the instance, can use
+type ActionsPolicy struct {
+	// "all" allows all the actions but the blocked ones, "local_only" only the actions of the repositories of the instance,
+	// "selected" the actions of the repositories of the instance and the ones matching an allowed pattern
+	// enum: all,local_only,selected
+	AllowedActions string `json:"allowed_actions"`
+	// the patterns of the actions allowed by the selected mode, like `owner/*`, `owner/repo` or `owner/repo@ref`,
+	// the actions which aren't on the default actions URL are prefixed by their host
+	PatternsAllowed []string `json:"patterns_allowed"`
+	// the patterns of the actions never allowed, whatever the mode
+	PatternsBlocked []string `json:"patterns_blocked"`
+	// whether the actions which aren't on the instance must be pinned to a full commit SHA
+	RequireSHAPinning bool `json:"require_sha_pinning"`
+}
+
+// EditActionsPolicyOption represents the actions the workflows of the repositories of an organization, or of the instance, can use
+type EditActionsPolicyOption struct {
+	// required: true
+	// enum: all,local_only,selected
+	AllowedActions    string   `json:"allowed_actions" binding:"Required;In(all,local_only,selected)"`
+	PatternsAllowed   []string `json:"patterns_allowed"`
+	PatternsBlocked   []string `json:"patterns_blocked"`
+	RequireSHAPinning bool     `json:"require_sha_pinning"`
+}
+
+// ActionSchedule represents an entry of the `schedule` trigger of a workflow of the default branch
+type ActionSchedule struct {
+	// the id of the entry, it changes when the workflows of the default branch are updated
+	ID         int64  `json:"id"`
+	WorkflowID string `json:"workflow_id"`
+	Cron       string `json:"cron"`
+	// whether the entry is paused, it doesn't create runs until it's resumed
+	Paused bool `json:"paused"`
+	// whether the workflow is disabled, none of its entries creates runs
+	WorkflowDisabled bool `json:"workflow_disabled"`
+	// when the entry creates its next run, null if it's paused or if the workflow is disabled
+	// swagger:strfmt date-time
+	NextRunAt *time.Time `json:"next_run_at"`
+	// swagger:strfmt date-time
+	LastRunAt *time.Time `json:"last_run_at"`
+	// the latest run created by the schedule trigger of the workflow
+	LastRun *ActionRun `json:"last_run"`
+}
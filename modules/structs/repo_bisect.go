@@ -0,0 +1,57 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// BisectSession represents the bisection of the history of a repository by the authenticated user
+type BisectSession struct {
+	// the commit known to be bad
+	Bad string `json:"bad"`
+	// the commits known to be good
+	Good []string `json:"good"`
+	// the commits which can't be tested
+	Skipped []string `json:"skipped"`
+	// the path the bisection is restricted to
+	Path string `json:"path"`
+	// the next commit to test, empty once the first bad commit has been found
+	Candidate string `json:"candidate"`
+	// the number of commits left to test
+	Remaining int `json:"remaining"`
+	// the estimated number of steps left
+	Steps int `json:"steps"`
+	// whether the first bad commit has been found
+	Finished bool `json:"finished"`
+	// the first bad commit, once found
+	FirstBad string `json:"first_bad"`
+	// the skipped commits which may be the first bad commit as only skipped commits were left to test
+	OnlySkipped []string `json:"only_skipped"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// StartBisectOption options for starting a bisect session
+type StartBisectOption struct {
+	// a commit, branch or tag known to be bad
+	// required: true
+	Bad string `json:"bad" binding:"Required"`
+	// commits, branches or tags known to be good
+	// required: true
+	Good []string `json:"good" binding:"Required"`
+	// only test the commits changing this path
+	Path string `json:"path"`
+}
+
+// BisectVerdictOption options for recording the result of testing a commit
+type BisectVerdictOption struct {
+	// the tested commit, the current candidate if empty
+	Commit string `json:"commit"`
+	// required: true
+	// enum: good,bad,skip
+	Verdict string `json:"verdict" binding:"Required;In(good,bad,skip)"`
+}
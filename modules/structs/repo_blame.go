@@ -0,0 +1,36 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// BlameHunk represents continuous lines of a file which were last changed by the same commit
+type BlameHunk struct {
+	// the first line of the hunk, starting at 1
+	StartLine int `json:"start_line"`
+	// the last line of the hunk, inclusive
+	EndLine   int            `json:"end_line"`
+	Commit    *CommitMeta    `json:"commit"`
+	Author    *CommitUser    `json:"author"`
+	Committer *CommitUser    `json:"committer"`
+	Message   string         `json:"message"`
+	Previous  *BlamePrevious `json:"previous,omitempty"`
+	Lines     []string       `json:"lines,omitempty"`
+}
+
+// BlamePrevious represents the commit and path of a file before the change of a blame hunk
+type BlamePrevious struct {
+	SHA  string `json:"sha"`
+	Path string `json:"path"`
+}
+
+// BlameResponse contains the blame of a file
+type BlameResponse struct {
+	Path string `json:"path"`
+	// the commit the file is blamed at
+	SHA string `json:"sha"`
+	// whether the revisions listed in .git-blame-ignore-revs were ignored
+	UsesIgnoreRevs bool `json:"uses_ignore_revs"`
+	// whether .git-blame-ignore-revs could not be used and was bypassed
+	FaultyIgnoreRevsFile bool         `json:"faulty_ignore_revs_file"`
+	Hunks                []*BlameHunk `json:"hunks"`
+}
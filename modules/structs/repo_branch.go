@@ -42,9 +42,11 @@ type BranchProtection struct {
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
 	IgnoreStaleApprovals          bool     `json:"ignore_stale_approvals"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
+	RequireSignoff                bool     `json:"require_signoff"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       string   `json:"unprotected_file_patterns"`
 	// swagger:strfmt date-time
@@ -75,13 +77,31 @@ type CreateBranchProtectionOption struct {
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
 	IgnoreStaleApprovals          bool     `json:"ignore_stale_approvals"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
+	RequireSignoff                bool     `json:"require_signoff"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       string   `json:"unprotected_file_patterns"`
 }
 
+// BranchProtectionStatusChecks represents the required status checks of a branch protection
+type BranchProtectionStatusChecks struct {
+	EnableStatusCheck bool `json:"enable_status_check"`
+	// patterns of the status contexts required to succeed before merging
+	StatusCheckContexts []string `json:"status_check_contexts"`
+	// status contexts reported on the repository during the last week
+	RecentContexts []string `json:"recent_contexts"`
+}
+
+// EditBranchProtectionStatusChecksOption options for editing the required status checks of a branch protection
+type EditBranchProtectionStatusChecksOption struct {
+	// patterns of the status contexts required to succeed before merging,
+	// status checks are disabled when empty
+	StatusCheckContexts []string `json:"status_check_contexts"`
+}
+
 // EditBranchProtectionOption options for editing a branch protection
 type EditBranchProtectionOption struct {
 	EnablePush                    *bool    `json:"enable_push"`
@@ -101,9 +121,45 @@ type EditBranchProtectionOption struct {
 	BlockOnRejectedReviews        *bool    `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests *bool    `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         *bool    `json:"block_on_outdated_branch"`
+	RequireCodeOwnerApproval      *bool    `json:"require_code_owner_approval"`
 	DismissStaleApprovals         *bool    `json:"dismiss_stale_approvals"`
 	IgnoreStaleApprovals          *bool    `json:"ignore_stale_approvals"`
 	RequireSignedCommits          *bool    `json:"require_signed_commits"`
+	RequireSignoff                *bool    `json:"require_signoff"`
 	ProtectedFilePatterns         *string  `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       *string  `json:"unprotected_file_patterns"`
 }
+
+// RebaseBranchOption options for rebasing a branch on to another branch
+type RebaseBranchOption struct {
+	// Name of the branch to rebase on to, defaults to the default branch of the repository
+	Onto string `json:"onto" binding:"GitRefName;MaxSize(100)"`
+}
+
+// RebaseBranchConflict represents the conflict which stopped the rebase of a branch
+type RebaseBranchConflict struct {
+	Message string `json:"message"`
+	// SHA of the commit which could not be applied
+	CommitSHA       string   `json:"commit_sha"`
+	ConflictedFiles []string `json:"conflicted_files"`
+}
+
+// BranchUpdate represents an update of a branch recorded in the journal of its updates
+type BranchUpdate struct {
+	ID int64 `json:"id"`
+	// the position of the branch before the update, empty when the branch was created
+	OldCommitID string `json:"old_commit_id"`
+	// the position of the branch after the update, empty when the branch was deleted
+	NewCommitID string `json:"new_commit_id"`
+	Pusher      *User  `json:"pusher"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// RestoreBranchOption options for restoring a branch to a previous position
+type RestoreBranchOption struct {
+	// SHA of the commit to restore the branch to, it must be a position recorded in the updates of the branch
+	//
+	// required: true
+	CommitID string `json:"commit_id" binding:"Required;MaxSize(64)"`
+}
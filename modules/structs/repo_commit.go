@@ -56,6 +56,8 @@ type Commit struct {
 	Parents    []*CommitMeta          `json:"parents"`
 	Files      []*CommitAffectedFiles `json:"files"`
 	Stats      *CommitStats           `json:"stats"`
+	// number of reactions of each type on the commit
+	Reactions []*ReactionSummary `json:"reactions"`
 }
 
 // CommitDateOptions store dates for GIT_AUTHOR_DATE and GIT_COMMITTER_DATE
@@ -69,5 +71,36 @@ type CommitDateOptions struct {
 // CommitAffectedFiles store information about files affected by the commit
 type CommitAffectedFiles struct {
 	Filename string `json:"filename"`
-	Status   string `json:"status"`
+	// `status` is added, removed, modified or renamed
+	Status string `json:"status"`
+	// `previous_filename` is populated when `status` is renamed
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	// similarity index in percent of a renamed file to its previous name
+	Similarity int `json:"similarity,omitempty"`
+}
+
+// CommitGraphEdge represents the edge between a commit of the commit graph and one of its parents
+type CommitGraphEdge struct {
+	SHA string `json:"sha"`
+	// lane taken by the edge to reach the parent
+	Lane int `json:"lane"`
+}
+
+// CommitGraphNode represents a commit of the commit graph
+type CommitGraphNode struct {
+	SHA string `json:"sha"`
+	// lane of the commit, starting at 0
+	Lane    int                `json:"lane"`
+	Parents []*CommitGraphEdge `json:"parents"`
+	// full names of the refs pointing to the commit
+	Refs    []string    `json:"refs"`
+	Author  *CommitUser `json:"author"`
+	Subject string      `json:"subject"`
+}
+
+// CommitGraph represents a page of the commit graph of a repository, the commits are in topological order
+type CommitGraph struct {
+	Commits []*CommitGraphNode `json:"commits"`
+	// number of lanes needed to render the page
+	Lanes int `json:"lanes"`
 }
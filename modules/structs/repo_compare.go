@@ -7,4 +7,17 @@ package structs
 type Compare struct {
 	TotalCommits int       `json:"total_commits"` // Total number of commits in the comparison.
 	Commits      []*Commit `json:"commits"`       // List of commits in the comparison.
+	// Base commit of the diff, the merge base for a three-dot comparison
+	BaseCommit string `json:"base_commit"`
+	// Head commit of the diff
+	HeadCommit string `json:"head_commit"`
+	// Whether the comparison is a two-dot comparison, which diffs the base against the head directly
+	DirectComparison bool `json:"direct_comparison"`
+	TotalFiles       int  `json:"total_files"` // Total number of changed files in the comparison.
+	Additions        int  `json:"additions"`   // Total number of added lines in the comparison.
+	Deletions        int  `json:"deletions"`   // Total number of deleted lines in the comparison.
+	// List of changed files, omitted in stats-only mode
+	Files []*ChangedFile `json:"files"`
+	// Whether only the statistics are returned, because they were requested or the diff has too many files
+	StatsOnly bool `json:"stats_only"`
 }
@@ -0,0 +1,51 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// CustomProperty a custom property defined by an organization for its repositories
+type CustomProperty struct {
+	Name string `json:"name"`
+	// enum: string,single_select,true_false
+	Type          string   `json:"type"`
+	Description   string   `json:"description"`
+	AllowedValues []string `json:"allowed_values"`
+	DefaultValue  string   `json:"default_value"`
+	Required      bool     `json:"required"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateOrUpdateCustomPropertyOption options for creating or updating a custom property
+type CreateOrUpdateCustomPropertyOption struct {
+	// required: true
+	// enum: string,single_select,true_false
+	Type        string `json:"type" binding:"Required;In(string,single_select,true_false)"`
+	Description string `json:"description"`
+	// values allowed for a single_select property
+	AllowedValues []string `json:"allowed_values"`
+	// value of the repositories which have no value for the property
+	DefaultValue string `json:"default_value"`
+	// whether every repository must have a value, requires a default value
+	Required bool `json:"required"`
+}
+
+// CustomPropertyValue the value of a custom property of a repository
+type CustomPropertyValue struct {
+	// required: true
+	Name string `json:"name" binding:"Required"`
+	// an empty value removes the value of the repository
+	Value string `json:"value"`
+}
+
+// UpdateCustomPropertyValuesOption options for setting the custom property values of a repository
+type UpdateCustomPropertyValuesOption struct {
+	// required: true
+	Properties []*CustomPropertyValue `json:"properties" binding:"Required"`
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// DeferredCommit represents a change of files scheduled to be committed at a later time
+type DeferredCommit struct {
+	ID int64 `json:"id"`
+	// the branch the change is committed to
+	Branch string `json:"branch"`
+	// pending, running, done or failed
+	Status string `json:"status"`
+	// the commit of the change once it is done
+	CommitID string `json:"commit_id,omitempty"`
+	// why the change couldn't be committed
+	Error string `json:"error,omitempty"`
+	// swagger:strfmt date-time
+	ExecuteAt time.Time `json:"execute_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
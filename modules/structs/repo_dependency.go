@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// RepoDependency represents a package required by a manifest file of a repository
+type RepoDependency struct {
+	// path of the manifest file declaring the dependency
+	Manifest string `json:"manifest"`
+	// ecosystem of the package, e.g. go, npm, pypi or composer
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	// version constraint as written in the manifest
+	Version string `json:"version"`
+	IsDev   bool   `json:"is_dev"`
+	// repositories of this instance declaring the package
+	InternalRepos []*Repository `json:"internal_repos"`
+}
+
+// RepoDependent represents a repository depending on a package declared by another repository
+type RepoDependent struct {
+	Repository *Repository `json:"repository"`
+	// path of the manifest file declaring the dependency
+	Manifest  string `json:"manifest"`
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	// version constraint as written in the manifest
+	Version string `json:"version"`
+	IsDev   bool   `json:"is_dev"`
+}
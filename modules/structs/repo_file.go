@@ -4,6 +4,10 @@
 
 package structs
 
+import (
+	"time"
+)
+
 // FileOptions options for all file APIs
 type FileOptions struct {
 	// message (optional) for the commit of this file. if not supplied, a default message will be used
@@ -18,15 +22,43 @@ type FileOptions struct {
 	Dates     CommitDateOptions `json:"dates"`
 	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
 	Signoff bool `json:"signoff"`
+	// trailers (optional) appended to the commit log message, e.g. Co-authored-by or issue references
+	Trailers []CommitTrailer `json:"trailers"`
+	// don't trigger the webhooks of the push, requires repository admin permission
+	SkipWebhooks bool `json:"skip_webhooks"`
+	// don't trigger the Actions runs of the push like a [skip ci] commit message, requires repository admin permission
+	SkipCI bool `json:"skip_ci"`
+}
+
+// CommitTrailer a trailer of a commit log message
+type CommitTrailer struct {
+	// key of the trailer, e.g. Co-authored-by
+	// required: true
+	Key string `json:"key" binding:"Required"`
+	// required: true
+	Value string `json:"value" binding:"Required"`
 }
 
 // CreateFileOptions options for creating files
 // Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
 type CreateFileOptions struct {
 	FileOptions
-	// content must be base64 encoded
+	// content must be base64 encoded unless another encoding is given
 	// required: true
 	ContentBase64 string `json:"content"`
+	// mode of the file, 100644 if empty
+	// enum: 100644,100755
+	Mode string `json:"mode" binding:"In(,100644,100755)"`
+	// encoding of the content, base64 if empty. utf-8 and iso-8859-1 content is given as text and committed in that encoding
+	// enum: base64,utf-8,iso-8859-1
+	Encoding string `json:"encoding" binding:"In(,base64,utf-8,iso-8859-1)"`
+	// line endings the content is committed with, they are kept if empty
+	// enum: keep,lf,crlf
+	LineEndings string `json:"line_endings" binding:"In(,keep,lf,crlf)"`
+	// parent_commit (optional) is the SHA of the commit the new commit is based on instead of the head of `branch`
+	ParentCommit string `json:"parent_commit"`
+	// target_ref (optional) is a reference under refs/gitea/ the new commit is written to instead of a branch, no branch is changed
+	TargetRef string `json:"target_ref" binding:"MaxSize(255)"`
 }
 
 // Branch returns branch name
@@ -52,11 +84,24 @@ func (o *DeleteFileOptions) Branch() string {
 // Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
 type UpdateFileOptions struct {
 	DeleteFileOptions
-	// content must be base64 encoded
+	// content must be base64 encoded unless another encoding is given
 	// required: true
 	ContentBase64 string `json:"content"`
 	// from_path (optional) is the path of the original file which will be moved/renamed to the path in the URL
 	FromPath string `json:"from_path" binding:"MaxSize(500)"`
+	// mode of the file, the current mode is kept if empty
+	// enum: 100644,100755
+	Mode string `json:"mode" binding:"In(,100644,100755)"`
+	// encoding of the content, base64 if empty. utf-8 and iso-8859-1 content is given as text and committed in that encoding
+	// enum: base64,utf-8,iso-8859-1
+	Encoding string `json:"encoding" binding:"In(,base64,utf-8,iso-8859-1)"`
+	// line endings the content is committed with, they are kept if empty
+	// enum: keep,lf,crlf
+	LineEndings string `json:"line_endings" binding:"In(,keep,lf,crlf)"`
+	// parent_commit (optional) is the SHA of the commit the new commit is based on instead of the head of `branch`
+	ParentCommit string `json:"parent_commit"`
+	// target_ref (optional) is a reference under refs/gitea/ the new commit is written to instead of a branch, no branch is changed
+	TargetRef string `json:"target_ref" binding:"MaxSize(255)"`
 }
 
 // Branch returns branch name
@@ -66,28 +111,50 @@ func (o *UpdateFileOptions) Branch() string {
 
 // ChangeFileOperation for creating, updating or deleting a file
 type ChangeFileOperation struct {
-	// indicates what to do with the file
+	// indicates what to do with the file, or with the directory for delete-dir and move-dir
 	// required: true
-	// enum: create,update,delete
+	// enum: create,update,delete,delete-dir,move-dir
 	Operation string `json:"operation" binding:"Required"`
-	// path to the existing or new file
+	// path to the existing or new file, or to the directory to delete or move to
 	// required: true
 	Path string `json:"path" binding:"Required;MaxSize(500)"`
-	// new or updated file content, must be base64 encoded
+	// new or updated file content, must be base64 encoded unless another encoding is given
 	ContentBase64 string `json:"content"`
 	// sha is the SHA for the file that already exists, required for update or delete
 	SHA string `json:"sha"`
-	// old path of the file to move
+	// old path of the file or directory to move
 	FromPath string `json:"from_path"`
+	// create or update a symbolic link, the content is the base64 encoded target of the link
+	IsSymlink bool `json:"is_symlink"`
+	// mode of the created or updated file, the current mode is kept if empty when updating it and 100644 is used when creating it
+	// enum: 100644,100755
+	Mode string `json:"mode" binding:"In(,100644,100755)"`
+	// encoding of the content, base64 if empty. utf-8 and iso-8859-1 content is given as text and committed in that encoding
+	// enum: base64,utf-8,iso-8859-1
+	Encoding string `json:"encoding" binding:"In(,base64,utf-8,iso-8859-1)"`
+	// line endings the content is committed with, they are kept if empty
+	// enum: keep,lf,crlf
+	LineEndings string `json:"line_endings" binding:"In(,keep,lf,crlf)"`
 }
 
 // ChangeFilesOptions options for creating, updating or deleting multiple files
 // Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
 type ChangeFilesOptions struct {
 	FileOptions
-	// list of file operations
-	// required: true
-	Files []*ChangeFileOperation `json:"files" binding:"Required"`
+	// list of file operations, required unless a commit is cherry-picked
+	Files []*ChangeFileOperation `json:"files"`
+	// SHA of a commit of the repository, or a branch or tag name, whose changes are applied to the branch before the file operations
+	CherryPick string `json:"cherry_pick"`
+	// amend the head of the branch instead of adding a commit, its message is kept if none is given.
+	// The branch is force-updated so it must not be protected
+	Amend bool `json:"amend"`
+	// time at which the change is committed, it is committed immediately if not given or in the past
+	// swagger:strfmt date-time
+	ExecuteAt *time.Time `json:"execute_at"`
+	// parent_commit (optional) is the SHA of the commit the new commit is based on instead of the head of `branch`
+	ParentCommit string `json:"parent_commit"`
+	// target_ref (optional) is a reference under refs/gitea/ the new commit is written to instead of a branch, no branch is changed
+	TargetRef string `json:"target_ref" binding:"MaxSize(255)"`
 }
 
 // Branch returns branch name
@@ -104,8 +171,34 @@ type FileOptionInterface interface {
 // Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
 type ApplyDiffPatchFileOptions struct {
 	DeleteFileOptions
+	// unified diff or patch generated by git format-patch, the author, date and message of the patch are used unless they are given
 	// required: true
 	Content string `json:"content"`
+	// fall back to a three-way merge if the patch doesn't apply cleanly, defaults to true
+	ThreeWay *bool `json:"three_way"`
+}
+
+// PatchHunkConflict represents a hunk of a patch which doesn't apply
+type PatchHunkConflict struct {
+	// path of the file changed by the hunk
+	Path string `json:"path"`
+	// header of the hunk, empty if the file change has no hunks
+	Hunk     string `json:"hunk"`
+	OldStart int    `json:"old_start"`
+	OldLines int    `json:"old_lines"`
+	NewStart int    `json:"new_start"`
+	NewLines int    `json:"new_lines"`
+	// error reported by git apply for the hunk
+	Message string `json:"message"`
+}
+
+// ApplyPatchConflicts contains the reasons why a patch doesn't apply
+type ApplyPatchConflicts struct {
+	Message string `json:"message"`
+	// hunks which don't apply to the branch
+	Conflicts []*PatchHunkConflict `json:"conflicts"`
+	// files with conflicts left by the three-way merge
+	ConflictedFiles []string `json:"conflicted_files"`
 }
 
 // FileLinksResponse contains the links for a repo's file
@@ -137,6 +230,10 @@ type ContentsResponse struct {
 	// `submodule_git_url` is populated when `type` is `submodule`, otherwise null
 	SubmoduleGitURL *string            `json:"submodule_git_url"`
 	Links           *FileLinksResponse `json:"_links"`
+	// `previous_path` is populated when the file has been renamed by a change of files
+	PreviousPath string `json:"previous_path,omitempty"`
+	// similarity index in percent of a renamed file to its previous path, as detected by git
+	Similarity int `json:"similarity,omitempty"`
 }
 
 // FileCommitResponse contains information generated from a Git commit for a repo's file.
@@ -170,3 +267,11 @@ type FileDeleteResponse struct {
 	Commit       *FileCommitResponse        `json:"commit"`
 	Verification *PayloadCommitVerification `json:"verification"`
 }
+
+// RepoArchiveStatus represents the status of an archive being generated
+type RepoArchiveStatus struct {
+	// status of the archive, "generating" until it can be downloaded
+	Status string `json:"status"`
+	// percentage of the archive which has been generated, if known
+	Progress int `json:"progress"`
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// MigrateToLFSOption options for migrating files in the history of a repository to LFS
+type MigrateToLFSOption struct {
+	// patterns of the files to migrate, in the syntax of .gitattributes
+	// required: true
+	Patterns []string `json:"patterns" binding:"Required"`
+	// branches whose history is rewritten, defaults to the default branch
+	Branches []string `json:"branches"`
+	// start the migration even if there are open pull requests from or to the branches
+	IgnoreOpenPullRequests bool `json:"ignore_open_pull_requests"`
+}
+
+// LFSMigrateTask represents a task migrating files in the history of a repository to LFS
+type LFSMigrateTask struct {
+	ID int64 `json:"id"`
+	// enum: queued,running,stopped,failed,finished
+	Status string `json:"status"`
+	// progress of a running task, result of a finished task or error of a failed task
+	Message  string   `json:"message"`
+	Patterns []string `json:"patterns"`
+	Branches []string `json:"branches"`
+	// the previous heads of the branches are kept below this prefix
+	BackupRefPrefix string `json:"backup_ref_prefix"`
+	Doer            *User  `json:"doer"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at,omitempty"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
@@ -8,3 +8,11 @@ type Note struct {
 	Message string  `json:"message"`
 	Commit  *Commit `json:"commit"`
 }
+
+// SetNoteOption options for adding or replacing the note of a commit
+type SetNoteOption struct {
+	// message of the note
+	//
+	// required: true
+	Message string `json:"message" binding:"Required"`
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// ContentSearchResult a file or a wiki page matching a content search
+type ContentSearchResult struct {
+	// path of the file in the repository or in the wiki
+	Path string `json:"path"`
+	// title of the wiki page, only set for wiki results
+	Title    string `json:"title,omitempty"`
+	HTMLURL  string `json:"html_url"`
+	CommitID string `json:"commit_id"`
+	Language string `json:"language,omitempty"`
+	// the matching lines with their surrounding lines
+	Lines []*ContentSearchResultLine `json:"lines"`
+}
+
+// ContentSearchResultLine a line of a content search result
+type ContentSearchResultLine struct {
+	Num     int    `json:"num"`
+	Content string `json:"content"`
+}
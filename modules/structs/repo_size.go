@@ -0,0 +1,28 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoSize represents the sizes in bytes of the data attributable to a repository
+type RepoSize struct {
+	// size of the git objects
+	Git int64 `json:"git"`
+	// size of the LFS objects
+	LFS int64 `json:"lfs"`
+	// size of the attachments of the issues, pull requests and releases, external assets are not counted
+	Attachments int64 `json:"attachments"`
+	// size of the Actions artifacts which haven't expired
+	ActionsArtifacts int64 `json:"actions_artifacts"`
+	// size of the Actions logs which haven't expired
+	ActionsLogs int64 `json:"actions_logs"`
+	// size of the files of the packages linked to the repository
+	Packages int64 `json:"packages"`
+	Total    int64 `json:"total"`
+	// time when the sizes were last computed
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// StagingSession represents files uploaded in chunks by the authenticated user to be committed together
+type StagingSession struct {
+	// the identifier of the session used to upload and commit its files
+	ID string `json:"id"`
+	// the branch the files are committed to
+	Branch string `json:"branch"`
+	// the head of the branch when the session was created, empty for an empty repository
+	BaseCommit string `json:"base_commit"`
+	// the files staged so far
+	Files []*StagedFile `json:"files"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// StagedFile represents a file uploaded to a staging session
+type StagedFile struct {
+	Path string `json:"path"`
+	// the number of bytes uploaded so far, the offset of the next chunk
+	Size int64 `json:"size"`
+}
+
+// CreateStagingSessionOption options for creating a staging session
+type CreateStagingSessionOption struct {
+	// branch the files are committed to. if not given, the default branch is used
+	BranchName string `json:"branch" binding:"GitRefName;MaxSize(100)"`
+}
+
+// CommitStagingSessionOption options for committing the files of a staging session
+type CommitStagingSessionOption struct {
+	// message (optional) for the commit. if not supplied, a default message will be used
+	Message string `json:"message"`
+	// new_branch (optional) will make a new branch from the branch of the session before committing the files
+	NewBranchName string `json:"new_branch" binding:"GitRefName;MaxSize(100)"`
+	// `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used)
+	Author    Identity          `json:"author"`
+	Committer Identity          `json:"committer"`
+	Dates     CommitDateOptions `json:"dates"`
+	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
+	Signoff bool `json:"signoff"`
+	// trailers (optional) appended to the commit log message, e.g. Co-authored-by or issue references
+	Trailers []CommitTrailer `json:"trailers"`
+}
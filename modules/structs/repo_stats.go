@@ -0,0 +1,38 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// CodeFrequencyWeek represents the lines added and deleted on the default branch of a repository during a week
+type CodeFrequencyWeek struct {
+	// start of the week, on Sunday at 00:00 UTC
+	// swagger:strfmt date-time
+	Week      time.Time `json:"week"`
+	Additions int64     `json:"additions"`
+	Deletions int64     `json:"deletions"`
+}
+
+// PunchCardEntry represents the number of commits to the default branch of a repository for an hour of a weekday,
+// in the time zones of their authors
+type PunchCardEntry struct {
+	// day of the week, 0 is Sunday
+	Weekday int   `json:"weekday"`
+	Hour    int   `json:"hour"`
+	Commits int64 `json:"commits"`
+}
+
+// ContributorStats represents the statistics of the commits of a contributor to the default branch of a repository
+type ContributorStats struct {
+	Name string `json:"name"`
+	// swagger:strfmt email
+	Email string `json:"email"`
+	// the user with the email of the contributor, null if there is none
+	User      *User `json:"user"`
+	Commits   int64 `json:"commits"`
+	Additions int64 `json:"additions"`
+	Deletions int64 `json:"deletions"`
+}
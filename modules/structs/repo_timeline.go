@@ -0,0 +1,48 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// TimelineEvent an issue, pull request or commit event of the timeline of a repository
+type TimelineEvent struct {
+	// the cursor of the event, the following events are requested with it as `since`
+	ID int64 `json:"id"`
+	// the kind of object the event is about
+	//
+	// enum: issue,pull_request,commit
+	Type string `json:"type"`
+	// what happened, like the op_type of an activity
+	Action string `json:"action"`
+	Actor  *User  `json:"actor"`
+	// the number of the issue or pull request
+	Number  int64    `json:"number,omitempty"`
+	Comment *Comment `json:"comment,omitempty"`
+	// the branch or tag of a commit event
+	RefName string            `json:"ref_name,omitempty"`
+	Commits []*TimelineCommit `json:"commits,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// TimelineCommit a commit pushed in a commit event of a repository timeline
+type TimelineCommit struct {
+	SHA         string `json:"sha"`
+	Message     string `json:"message"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	// swagger:strfmt date-time
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// RepoTimeline a page of the events of the timeline of a repository
+type RepoTimeline struct {
+	Events []*TimelineEvent `json:"events"`
+	// the cursor of the last event, the following events are requested with it as `since`
+	Cursor int64 `json:"cursor"`
+	// whether there are more events following the cursor
+	HasMore bool `json:"has_more"`
+}
@@ -21,4 +21,6 @@ type GitTreeResponse struct {
 	Truncated  bool       `json:"truncated"`
 	Page       int        `json:"page"`
 	TotalCount int        `json:"total_count"`
+	// the cursor of the next entries if there are more, it is the path of the last entry
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -44,3 +44,31 @@ type WikiCommitList struct {
 	WikiCommits []*WikiCommit `json:"commits"`
 	Count       int64         `json:"count"`
 }
+
+// MoveWikiPageOptions form for moving a wiki page
+type MoveWikiPageOptions struct {
+	// new page title
+	// required: true
+	Title string `json:"title" binding:"Required"`
+	// optional commit message summarizing the change
+	Message string `json:"message"`
+	// rewrite the links to the page in the other pages of the wiki
+	UpdateLinks bool `json:"update_links"`
+}
+
+// RestoreWikiPageOptions form for restoring a revision of a wiki page
+type RestoreWikiPageOptions struct {
+	// sha of the commit to restore the page from
+	// required: true
+	Revision string `json:"revision" binding:"Required"`
+	// optional commit message summarizing the change
+	Message string `json:"message"`
+}
+
+// EditWikiSectionOptions form for editing the sidebar or footer of a wiki
+type EditWikiSectionOptions struct {
+	// content must be base64 encoded
+	ContentBase64 string `json:"content_base64"`
+	// optional commit message summarizing the change
+	Message string `json:"message"`
+}
@@ -30,8 +30,32 @@ type ActionVariable struct {
 	OwnerID int64 `json:"owner_id"`
 	// the repository to which the variable belongs
 	RepoID int64 `json:"repo_id"`
+	// the environment of the repository to which the variable belongs, if any
+	Environment string `json:"environment,omitempty"`
 	// the name of the variable
 	Name string `json:"name"`
 	// the value of the variable
 	Data string `json:"data"`
 }
+
+// ActionEffectiveVariable represents a variable or a secret the workflows of a repository get, with the scope it's defined at
+// swagger:model
+type ActionEffectiveVariable struct {
+	// the name of the variable or of the secret
+	Name string `json:"name"`
+	// the value of the variable, never returned for a secret
+	Data string `json:"data,omitempty"`
+	// the scope the variable or the secret is defined at: instance, owner, repository or environment
+	Scope string `json:"scope"`
+	// the scopes of the variables or of the secrets of the same name overridden by this one
+	Overrides []string `json:"overrides"`
+}
+
+// ActionEffectiveVariables represents the variables and the secrets the workflows of a repository get
+// swagger:model
+type ActionEffectiveVariables struct {
+	Variables []*ActionEffectiveVariable `json:"variables"`
+	Secrets   []*ActionEffectiveVariable `json:"secrets"`
+	// a message for each variable or secret overriding another one of the same name
+	Warnings []string `json:"warnings"`
+}
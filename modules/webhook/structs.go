@@ -30,10 +30,11 @@ type HookEvents struct {
 
 // HookEvent represents events that will delivery hook.
 type HookEvent struct {
-	PushOnly       bool   `json:"push_only"`
-	SendEverything bool   `json:"send_everything"`
-	ChooseEvents   bool   `json:"choose_events"`
-	BranchFilter   string `json:"branch_filter"`
+	PushOnly          bool   `json:"push_only"`
+	SendEverything    bool   `json:"send_everything"`
+	ChooseEvents      bool   `json:"choose_events"`
+	BranchFilter      string `json:"branch_filter"`
+	PackageTypeFilter string `json:"package_type_filter"`
 
 	HookEvents `json:"events"`
 }
@@ -245,7 +245,7 @@ func (ar artifactRoutes) uploadArtifact(ctx *ArtifactContext) {
 	fileRealTotalSize, contentLength := getUploadFileSize(ctx)
 
 	// get artifact retention days
-	expiredDays := setting.Actions.ArtifactRetentionDays
+	var expiredDays int64
 	if queryRetentionDays := ctx.Req.URL.Query().Get("retentionDays"); queryRetentionDays != "" {
 		var err error
 		expiredDays, err = strconv.ParseInt(queryRetentionDays, 10, 64)
@@ -255,6 +255,12 @@ func (ar artifactRoutes) uploadArtifact(ctx *ArtifactContext) {
 			return
 		}
 	}
+	expiredDays, err := actions_service.GetUploadArtifactRetentionDays(ctx, task.RepoID, expiredDays)
+	if err != nil {
+		log.Error("Error get artifact retention days: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error get artifact retention days")
+		return
+	}
 	log.Debug("[artifact] upload chunk, name: %s, path: %s, size: %d, retention days: %d",
 		artifactName, artifactPath, fileRealTotalSize, expiredDays)
 
@@ -98,6 +98,7 @@ import (
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 
 	"google.golang.org/protobuf/encoding/protojson"
@@ -259,10 +260,16 @@ func (r *artifactV4Routes) createArtifact(ctx *ArtifactContext) {
 
 	artifactName := req.Name
 
-	rententionDays := setting.Actions.ArtifactRetentionDays
+	var rententionDays int64
 	if req.ExpiresAt != nil {
 		rententionDays = int64(time.Until(req.ExpiresAt.AsTime()).Hours() / 24)
 	}
+	rententionDays, err := actions_service.GetUploadArtifactRetentionDays(ctx, ctx.ActionTask.RepoID, rententionDays)
+	if err != nil {
+		log.Error("Error get artifact retention days: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error get artifact retention days")
+		return
+	}
 	// create or get artifact with name and path
 	artifact, err := actions.CreateArtifact(ctx, ctx.ActionTask, artifactName, artifactName+".zip", rententionDays)
 	if err != nil {
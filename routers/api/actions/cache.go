@@ -0,0 +1,310 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// GitHub Actions Cache API
+//
+// The cache server implements the API used by actions/cache, inspired by https://github.com/nektos/act/blob/master/pkg/artifactcache/handler.go.
+// act_runner uses it when its `cache.external_server` is set to "<ROOT_URL>api/actions_cache/",
+// then the tasks authenticate with their ACTIONS_RUNTIME_TOKEN.
+//
+// The caches are scoped by repository and by ref: a run saves its caches to its ref,
+// and restores them from its ref, the base branch of its pull request or the default branch, in this order.
+//
+// 1. Find a cache matching one of the keys
+// GET: /_apis/artifactcache/cache?keys=key1,key2&version=...
+// Response: 200 {"result":"hit","archiveLocation":"...","cacheKey":"..."}, or 204 if there is no match
+//
+// 2. Reserve a cache before uploading it
+// POST: /_apis/artifactcache/caches
+// Request: {"key":"...","version":"...","cacheSize":1024}
+// Response: {"cacheId":1}, 409 if the key is already reserved
+//
+// 3. Upload a chunk of the content of the cache, only by the run which has reserved it
+// PATCH: /_apis/artifactcache/caches/{cache_id}
+// Headers: Content-Range: bytes 0-1023/*
+//
+// 4. Commit the cache once all its chunks are uploaded
+// POST: /_apis/artifactcache/caches/{cache_id}
+//
+// 5. Download the content of a cache, with the signed archiveLocation returned by 1
+// GET: /_apis/artifactcache/artifacts/{cache_id}?sig=...&expires=...
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+const cacheRouteBase = "/_apis/artifactcache"
+
+type cacheRoutes struct {
+	prefix string
+	fs     storage.ObjectStorage
+}
+
+func CacheRoutes(prefix string) *web.Router {
+	m := web.NewRouter()
+
+	r := cacheRoutes{
+		prefix: prefix,
+		fs:     storage.ActionsCache,
+	}
+
+	m.Group(cacheRouteBase, func() {
+		m.Group("", func() {
+			m.Get("/cache", r.findCache)
+			m.Post("/caches", r.reserveCache)
+			m.Combo("/caches/{cache_id}").Patch(r.uploadCache).Post(r.commitCache)
+		}, ArtifactContexter())
+		// the content is downloaded without the runtime token, the link is signed instead
+		m.Get("/artifacts/{cache_id}", ArtifactV4Contexter(), r.downloadCache)
+	})
+
+	return m
+}
+
+func (r cacheRoutes) buildSignature(cacheID int64, expires string) []byte {
+	mac := hmac.New(sha256.New, setting.GetGeneralTokenSigningSecret())
+	mac.Write([]byte("ActionsCache"))
+	mac.Write([]byte(fmt.Sprint(cacheID)))
+	mac.Write([]byte(expires))
+	return mac.Sum(nil)
+}
+
+func (r cacheRoutes) buildDownloadURL(ctx *ArtifactContext, cacheID int64) string {
+	expires := time.Now().Add(60 * time.Minute).Format(time.RFC3339)
+	return strings.TrimSuffix(httplib.GuessCurrentAppURL(ctx), "/") + strings.TrimSuffix(r.prefix, "/") +
+		cacheRouteBase + "/artifacts/" + strconv.FormatInt(cacheID, 10) +
+		"?sig=" + base64.URLEncoding.EncodeToString(r.buildSignature(cacheID, expires)) + "&expires=" + url.QueryEscape(expires)
+}
+
+// scopesOfTask returns the scope the task saves its caches to and the ones it can restore them from
+func (r cacheRoutes) scopesOfTask(ctx *ArtifactContext) (string, []string, bool) {
+	if err := ctx.ActionTask.Job.LoadRun(ctx); err != nil {
+		log.Error("Error getting run: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error getting run")
+		return "", nil, false
+	}
+	scope, scopes, err := actions_service.CacheScopesOfRun(ctx, ctx.ActionTask.Job.Run)
+	if err != nil {
+		log.Error("Error getting cache scopes: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error getting cache scopes")
+		return "", nil, false
+	}
+	return scope, scopes, true
+}
+
+// getReservedCache returns the cache of the path of the request to upload its content.
+// The cache has to have been reserved by the run of the task in the scope the task saves its caches to,
+// so a task can't write to the caches restored by other refs, like the ones of the default branch.
+func (r cacheRoutes) getReservedCache(ctx *ArtifactContext) (*actions.ActionCache, bool) {
+	scope, _, ok := r.scopesOfTask(ctx)
+	if !ok {
+		return nil, false
+	}
+	cacheID := ctx.PathParamInt64("cache_id")
+	cache, err := actions.GetCacheByID(ctx, cacheID)
+	if errors.Is(err, util.ErrNotExist) || (err == nil && (cache.RepoID != ctx.ActionTask.RepoID || cache.RunID != ctx.ActionTask.Job.RunID || cache.Scope != scope)) {
+		ctx.Error(http.StatusNotFound, "Cache not found")
+		return nil, false
+	} else if err != nil {
+		log.Error("Error getting cache %d: %v", cacheID, err)
+		ctx.Error(http.StatusInternalServerError, "Error getting cache")
+		return nil, false
+	}
+	return cache, true
+}
+
+type findCacheResponse struct {
+	Result          string `json:"result"`
+	ArchiveLocation string `json:"archiveLocation"`
+	CacheKey        string `json:"cacheKey"`
+}
+
+func (r cacheRoutes) findCache(ctx *ArtifactContext) {
+	keys := make([]string, 0, 2)
+	for _, key := range strings.Split(ctx.Req.URL.Query().Get("keys"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	version := ctx.Req.URL.Query().Get("version")
+	if len(keys) == 0 || version == "" {
+		ctx.Error(http.StatusBadRequest, "Missing keys or version")
+		return
+	}
+
+	_, scopes, ok := r.scopesOfTask(ctx)
+	if !ok {
+		return
+	}
+	cache, err := actions.MatchCache(ctx, ctx.ActionTask.RepoID, scopes, keys, version)
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.Status(http.StatusNoContent)
+		return
+	} else if err != nil {
+		log.Error("Error matching cache: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error matching cache")
+		return
+	}
+
+	log.Debug("[cache] hit cache %d of repository %d, key: %s, scope: %s", cache.ID, cache.RepoID, cache.Key, cache.Scope)
+	ctx.JSON(http.StatusOK, findCacheResponse{
+		Result:          "hit",
+		ArchiveLocation: r.buildDownloadURL(ctx, cache.ID),
+		CacheKey:        cache.Key,
+	})
+}
+
+type reserveCacheRequest struct {
+	Key       string `json:"key"`
+	Version   string `json:"version"`
+	CacheSize int64  `json:"cacheSize"`
+}
+
+type reserveCacheResponse struct {
+	CacheID int64 `json:"cacheId"`
+}
+
+func (r cacheRoutes) reserveCache(ctx *ArtifactContext) {
+	var req reserveCacheRequest
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.Error(http.StatusBadRequest, "Error decode request body")
+		return
+	}
+	if req.Key == "" || req.Version == "" || len(req.Key) > 255 || len(req.Version) > 255 {
+		ctx.Error(http.StatusBadRequest, "Invalid key or version")
+		return
+	}
+	if req.CacheSize > setting.Actions.CacheRepoQuota {
+		ctx.Error(http.StatusBadRequest, "Cache size exceeds the quota of the repository")
+		return
+	}
+
+	scope, _, ok := r.scopesOfTask(ctx)
+	if !ok {
+		return
+	}
+	cache, err := actions.ReserveCache(ctx, ctx.ActionTask.RepoID, ctx.ActionTask.Job.RunID, scope, req.Key, req.Version)
+	if errors.Is(err, util.ErrAlreadyExist) {
+		ctx.Error(http.StatusConflict, "Cache already exists")
+		return
+	} else if err != nil {
+		log.Error("Error reserving cache: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error reserving cache")
+		return
+	}
+
+	log.Debug("[cache] reserve cache %d of repository %d, key: %s, scope: %s", cache.ID, cache.RepoID, cache.Key, cache.Scope)
+	ctx.JSON(http.StatusOK, reserveCacheResponse{CacheID: cache.ID})
+}
+
+func (r cacheRoutes) uploadCache(ctx *ArtifactContext) {
+	cache, ok := r.getReservedCache(ctx)
+	if !ok {
+		return
+	}
+	if cache.Complete {
+		ctx.Error(http.StatusBadRequest, "Cache already committed")
+		return
+	}
+
+	// parse content-range header, format: bytes 0-1023/*
+	contentRange := ctx.Req.Header.Get("Content-Range")
+	var start, end int64
+	if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/", &start, &end); err != nil || start < 0 || end < start {
+		log.Warn("parse content range error: %v, content-range: %s", err, contentRange)
+		ctx.Error(http.StatusBadRequest, "Invalid content range")
+		return
+	}
+	if end >= setting.Actions.CacheRepoQuota {
+		ctx.Error(http.StatusBadRequest, "Cache size exceeds the quota of the repository")
+		return
+	}
+
+	size := end - start + 1
+	if _, err := r.fs.Save(cache.ChunkStoragePath(start), ctx.Req.Body, size); err != nil {
+		log.Error("Error saving chunk of cache %d: %v", cache.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Error saving chunk")
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (r cacheRoutes) commitCache(ctx *ArtifactContext) {
+	cache, ok := r.getReservedCache(ctx)
+	if !ok {
+		return
+	}
+	if cache.Complete {
+		ctx.Error(http.StatusBadRequest, "Cache already committed")
+		return
+	}
+
+	if err := actions_service.CommitCacheContent(ctx, cache); err != nil {
+		log.Error("Error committing cache %d: %v", cache.ID, err)
+		if errors.Is(err, actions_service.ErrCacheTooLarge) {
+			ctx.Error(http.StatusBadRequest, "Cache size exceeds the quota of the repository")
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "Error committing cache")
+		return
+	}
+
+	log.Debug("[cache] commit cache %d of repository %d, size: %d", cache.ID, cache.RepoID, cache.Size)
+	ctx.Status(http.StatusNoContent)
+}
+
+func (r cacheRoutes) downloadCache(ctx *ArtifactContext) {
+	cacheID := ctx.PathParamInt64("cache_id")
+	expires := ctx.Req.URL.Query().Get("expires")
+	sig, _ := base64.URLEncoding.DecodeString(ctx.Req.URL.Query().Get("sig"))
+	if !hmac.Equal(sig, r.buildSignature(cacheID, expires)) {
+		ctx.Error(http.StatusUnauthorized, "Error unauthorized")
+		return
+	}
+	if t, err := time.Parse(time.RFC3339, expires); err != nil || t.Before(time.Now()) {
+		ctx.Error(http.StatusUnauthorized, "Error link expired")
+		return
+	}
+
+	cache, err := actions.GetCacheByID(ctx, cacheID)
+	if err != nil || !cache.Complete {
+		ctx.Error(http.StatusNotFound, "Cache not found")
+		return
+	}
+
+	file, err := r.fs.Open(cache.StoragePath())
+	if err != nil {
+		log.Error("Error opening cache %d: %v", cache.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Error opening cache")
+		return
+	}
+	defer file.Close()
+
+	ctx.ServeContent(file, &context.ServeHeaderOptions{
+		Filename:     fmt.Sprintf("cache-%d.tzst", cache.ID),
+		LastModified: cache.CreatedUnix.AsLocalTime(),
+	})
+}
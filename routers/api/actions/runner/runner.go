@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/actions"
@@ -54,6 +55,10 @@ func (s *Service) Register(
 		return nil, errors.New("runner registration token has been invalidated, please use the latest one")
 	}
 
+	if runnerToken.IsExpired() {
+		return nil, errors.New("runner registration token has expired")
+	}
+
 	if runnerToken.OwnerID > 0 {
 		if _, err := user_model.GetUserByID(ctx, runnerToken.OwnerID); err != nil {
 			return nil, errors.New("owner of the token not found")
@@ -77,6 +82,7 @@ func (s *Service) Register(
 		RepoID:      runnerToken.RepoID,
 		Version:     req.Msg.Version,
 		AgentLabels: labels,
+		Ephemeral:   runnerToken.Ephemeral,
 	}
 	if err := runner.GenerateToken(); err != nil {
 		return nil, errors.New("can't generate token")
@@ -87,8 +93,8 @@ func (s *Service) Register(
 		return nil, errors.New("can't create new runner")
 	}
 
-	// update token status
-	runnerToken.IsActive = true
+	// update token status, an ephemeral token registers a single runner
+	runnerToken.IsActive = !runnerToken.Ephemeral
 	if err := actions_model.UpdateRunnerToken(ctx, runnerToken, "is_active"); err != nil {
 		return nil, errors.New("can't update runner token status")
 	}
@@ -152,6 +158,15 @@ func (s *Service) FetchTask(
 		latestVersion++
 	}
 
+	if runner.Ephemeral {
+		// an ephemeral runner runs a single job
+		if has, err := db.Exist[actions_model.ActionTask](ctx, actions_model.FindTaskOptions{RunnerID: runner.ID}.ToConds()); err != nil {
+			return nil, status.Errorf(codes.Internal, "query the tasks of the runner: %v", err)
+		} else if has {
+			return connect.NewResponse(&runnerv1.FetchTaskResponse{TasksVersion: latestVersion}), nil
+		}
+	}
+
 	if tasksVersion != latestVersion {
 		// if the task version in request is not equal to the version in db,
 		// it means there may still be some tasks not be assgined.
@@ -221,6 +236,14 @@ func (s *Service) UpdateTask(
 		if err := actions_service.EmitJobsIfReady(task.Job.RunID); err != nil {
 			log.Error("Emit ready jobs of run %d: %v", task.Job.RunID, err)
 		}
+		if err := actions_service.EmitConcurrencyGroupsOfRun(ctx, task.Job.RunID); err != nil {
+			log.Error("Emit the concurrency groups of run %d: %v", task.Job.RunID, err)
+		}
+		if runner := GetRunner(ctx); runner.Ephemeral {
+			if err := actions_model.DeleteRunner(ctx, runner.ID); err != nil {
+				log.Error("Delete the ephemeral runner %d: %v", runner.ID, err)
+			}
+		}
 	}
 
 	return connect.NewResponse(&runnerv1.UpdateTaskResponse{
@@ -36,9 +36,9 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 		return nil, false, fmt.Errorf("GetSecretsOfTask: %w", err)
 	}
 
-	vars, err := actions_model.GetVariablesOfRun(ctx, t.Job.Run)
+	vars, err := actions_model.GetVariablesOfJob(ctx, t.Job)
 	if err != nil {
-		return nil, false, fmt.Errorf("GetVariablesOfRun: %w", err)
+		return nil, false, fmt.Errorf("GetVariablesOfJob: %w", err)
 	}
 
 	actions.CreateCommitStatus(ctx, t.Job)
@@ -128,7 +128,7 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 		"retention_days":    "",                                                   // string, The number of days that workflow run logs and artifacts are kept.
 		"run_id":            fmt.Sprint(t.Job.RunID),                              // string, A unique number for each workflow run within a repository. This number does not change if you re-run the workflow run.
 		"run_number":        fmt.Sprint(t.Job.Run.Index),                          // string, A unique number for each run of a particular workflow in a repository. This number begins at 1 for the workflow's first run, and increments with each new run. This number does not change if you re-run the workflow run.
-		"run_attempt":       fmt.Sprint(t.Job.Attempt),                            // string, A unique number for each attempt of a particular workflow run in a repository. This number begins at 1 for the workflow run's first attempt, and increments with each re-run.
+		"run_attempt":       fmt.Sprint(t.Job.Run.Attempt),                        // string, A unique number for each attempt of a particular workflow run in a repository. This number begins at 1 for the workflow run's first attempt, and increments with each re-run.
 		"secret_source":     "Actions",                                            // string, The source of a secret used in a workflow. Possible values are None, Actions, Dependabot, or Codespaces.
 		"server_url":        setting.AppURL,                                       // string, The URL of the GitHub server. For example: https://github.com.
 		"sha":               sha,                                                  // string, The commit SHA that triggered the workflow. The value of this commit SHA depends on the event that triggered the workflow. For more information, see "Events that trigger workflows." For example, ffac537e6cbbf934b08745a378932722df287a53.
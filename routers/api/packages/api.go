@@ -25,6 +25,7 @@ import (
 	"code.gitea.io/gitea/routers/api/packages/generic"
 	"code.gitea.io/gitea/routers/api/packages/goproxy"
 	"code.gitea.io/gitea/routers/api/packages/helm"
+	"code.gitea.io/gitea/routers/api/packages/julia"
 	"code.gitea.io/gitea/routers/api/packages/maven"
 	"code.gitea.io/gitea/routers/api/packages/npm"
 	"code.gitea.io/gitea/routers/api/packages/nuget"
@@ -33,6 +34,7 @@ import (
 	"code.gitea.io/gitea/routers/api/packages/rpm"
 	"code.gitea.io/gitea/routers/api/packages/rubygems"
 	"code.gitea.io/gitea/routers/api/packages/swift"
+	"code.gitea.io/gitea/routers/api/packages/terraform"
 	"code.gitea.io/gitea/routers/api/packages/vagrant"
 	"code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/context"
@@ -107,6 +109,14 @@ func CommonRoutes() *web.Router {
 		&chef.Auth{},
 	})
 
+	// The Terraform registry protocols have a single base URL for the instance, the owners are the namespaces
+	r.Group("/-/terraform", func() {
+		r.Get("/modules/v1/{username}/{name}/{system}/versions", terraform.EnumerateModuleVersions)
+		r.Get("/modules/v1/{username}/{name}/{system}/{version}/download", terraform.GetModuleDownloadURL)
+		r.Get("/providers/v1/{username}/{provider}/versions", terraform.EnumerateProviderVersions)
+		r.Get("/providers/v1/{username}/{provider}/{version}/download/{os}/{arch}", terraform.GetProviderPackage)
+	}, context.UserAssignmentWeb(), context.PackageAssignment(), reqPackageAccess(perm.AccessModeRead))
+
 	r.Group("/{username}", func() {
 		r.Group("/alpine", func() {
 			r.Get("/key", alpine.GetRepositoryKey)
@@ -300,6 +310,7 @@ func CommonRoutes() *web.Router {
 					r.Get("/PACKAGES", cran.EnumerateSourcePackages)
 					r.Get("/PACKAGES{format}", cran.EnumerateSourcePackages)
 					r.Get("/{filename}", cran.DownloadSourcePackageFile)
+					r.Get("/Archive/{packagename}/{filename}", cran.DownloadArchivedSourcePackageFile)
 				})
 				r.Put("", reqPackageAccess(perm.AccessModeWrite), cran.UploadSourcePackageFile)
 			})
@@ -405,6 +416,14 @@ func CommonRoutes() *web.Router {
 			r.Get("/{filename}", helm.DownloadPackageFile)
 			r.Post("/api/charts", reqPackageAccess(perm.AccessModeWrite), helm.UploadPackage)
 		}, reqPackageAccess(perm.AccessModeRead))
+		r.Group("/julia", func() {
+			r.Get("/registries", julia.EnumerateRegistries)
+			r.Get("/registries{flavor}", julia.EnumerateRegistries)
+			r.Get("/registry/{uuid}/{hash}", julia.DownloadRegistry)
+			r.Get("/package/{uuid}/{hash}", julia.DownloadPackageFile)
+			r.Put("", reqPackageAccess(perm.AccessModeWrite), julia.UploadPackage)
+			r.Delete("/{name}/{version}", reqPackageAccess(perm.AccessModeWrite), julia.DeletePackage)
+		}, reqPackageAccess(perm.AccessModeRead))
 		r.Group("/maven", func() {
 			r.Put("/*", reqPackageAccess(perm.AccessModeWrite), maven.UploadPackageFile)
 			r.Get("/*", maven.DownloadPackageFile)
@@ -488,6 +507,8 @@ func CommonRoutes() *web.Router {
 					r.Delete("", npm.DeletePackageTag)
 				}, reqPackageAccess(perm.AccessModeWrite))
 			})
+			r.Get("/-/npm/v1/attestations/@{scope}/{id}@{version}", npm.PackageAttestations)
+			r.Get("/-/npm/v1/attestations/{id}@{version}", npm.PackageAttestations)
 			r.Group("/-/v1/search", func() {
 				r.Get("", npm.PackageSearch)
 			})
@@ -630,6 +651,16 @@ func CommonRoutes() *web.Router {
 			})
 			r.Get("/identifiers", swift.CheckAcceptMediaType(swift.AcceptJSON), swift.LookupPackageIdentifiers)
 		}, reqPackageAccess(perm.AccessModeRead))
+		r.Group("/terraform", func() {
+			r.Group("/modules/{name}/{system}/{version}", func() {
+				r.Put("", reqPackageAccess(perm.AccessModeWrite), terraform.UploadModule)
+				r.Get("/{filename}", terraform.DownloadModuleFile)
+			})
+			r.Group("/providers/{provider}/{version}/{filename}", func() {
+				r.Get("", terraform.DownloadProviderFile)
+				r.Put("", reqPackageAccess(perm.AccessModeWrite), terraform.UploadProviderFile)
+			})
+		}, reqPackageAccess(perm.AccessModeRead))
 		r.Group("/vagrant", func() {
 			r.Group("/authenticate", func() {
 				r.Get("", vagrant.CheckAuthenticate)
@@ -416,7 +416,7 @@ func uploadFile(ctx *context.Context, fileFilter container.Set[string], fileKey
 		switch err {
 		case packages_model.ErrDuplicatePackageFile:
 			apiError(ctx, http.StatusConflict, err)
-		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize, packages_service.ErrPackageImmutable:
 			apiError(ctx, http.StatusForbidden, err)
 		default:
 			apiError(ctx, http.StatusInternalServerError, err)
@@ -482,6 +482,8 @@ func DeleteRecipeV1(ctx *context.Context) {
 	if err := deleteRecipeOrPackage(ctx, rref, true, nil, false); err != nil {
 		if err == packages_model.ErrPackageNotExist || err == conan_model.ErrPackageReferenceNotExist {
 			apiError(ctx, http.StatusNotFound, err)
+		} else if err == packages_service.ErrPackageImmutable {
+			apiError(ctx, http.StatusForbidden, err)
 		} else {
 			apiError(ctx, http.StatusInternalServerError, err)
 		}
@@ -497,6 +499,8 @@ func DeleteRecipeV2(ctx *context.Context) {
 	if err := deleteRecipeOrPackage(ctx, rref, rref.Revision == "", nil, false); err != nil {
 		if err == packages_model.ErrPackageNotExist || err == conan_model.ErrPackageReferenceNotExist {
 			apiError(ctx, http.StatusNotFound, err)
+		} else if err == packages_service.ErrPackageImmutable {
+			apiError(ctx, http.StatusForbidden, err)
 		} else {
 			apiError(ctx, http.StatusInternalServerError, err)
 		}
@@ -544,6 +548,8 @@ func DeletePackageV1(ctx *context.Context) {
 			if err := deleteRecipeOrPackage(ctx, currentRref, true, pref, true); err != nil {
 				if err == packages_model.ErrPackageNotExist || err == conan_model.ErrPackageReferenceNotExist {
 					apiError(ctx, http.StatusNotFound, err)
+				} else if err == packages_service.ErrPackageImmutable {
+					apiError(ctx, http.StatusForbidden, err)
 				} else {
 					apiError(ctx, http.StatusInternalServerError, err)
 				}
@@ -608,6 +614,10 @@ func deleteRecipeOrPackage(apictx *context.Context, rref *conan_module.RecipeRef
 			return err
 		}
 
+		if err := packages_service.CheckVersionIsMutable(ctx, apictx.Doer, pv); err != nil {
+			return err
+		}
+
 		pd, err = packages_model.GetPackageDescriptor(ctx, pv)
 		if err != nil {
 			return err
@@ -566,6 +566,15 @@ func UploadManifest(ctx *context.Context) {
 		return
 	}
 
+	if err := checkSignatureRequired(ctx, mci, digestFromHashSummer(buf)); err != nil {
+		if err == packages_service.ErrSignatureRequired {
+			apiErrorDefined(ctx, errDenied.WithMessage(err.Error()))
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
 	digest, err := processManifest(ctx, mci, buf)
 	if err != nil {
 		var namedError *namedError
@@ -575,7 +584,7 @@ func UploadManifest(ctx *context.Context) {
 			apiErrorDefined(ctx, errBlobUnknown)
 		} else {
 			switch err {
-			case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize, packages_service.ErrPackageImmutable:
 				apiError(ctx, http.StatusForbidden, err)
 			default:
 				apiError(ctx, http.StatusInternalServerError, err)
@@ -620,15 +629,38 @@ func getManifestFromContext(ctx *context.Context) (*packages_model.PackageFileDe
 	return workaroundGetContainerBlob(ctx, opts)
 }
 
+// getOrCacheManifest gets the requested manifest, a missing manifest is fetched from the upstream registry of the owner if there is one
+func getOrCacheManifest(ctx *context.Context) (*packages_model.PackageFileDescriptor, error) {
+	manifest, err := getManifestFromContext(ctx)
+	if err == container_model.ErrContainerBlobNotExist {
+		if err = cacheUpstreamManifest(ctx); err == nil {
+			manifest, err = getManifestFromContext(ctx)
+		}
+	}
+	return manifest, err
+}
+
+func apiManifestError(ctx *context.Context, err error) {
+	var namedError *namedError
+	switch {
+	case err == container_model.ErrContainerBlobNotExist:
+		apiErrorDefined(ctx, errManifestUnknown)
+	case errors.As(err, &namedError):
+		apiErrorDefined(ctx, namedError)
+	case err == packages_service.ErrSignatureRequired:
+		apiErrorDefined(ctx, errDenied.WithMessage(err.Error()))
+	case err == packages_service.ErrQuotaTotalCount, err == packages_service.ErrQuotaTypeSize, err == packages_service.ErrQuotaTotalSize:
+		apiError(ctx, http.StatusForbidden, err)
+	default:
+		apiError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
 // https://github.com/opencontainers/distribution-spec/blob/main/spec.md#checking-if-content-exists-in-the-registry
 func HeadManifest(ctx *context.Context) {
-	manifest, err := getManifestFromContext(ctx)
+	manifest, err := getOrCacheManifest(ctx)
 	if err != nil {
-		if err == container_model.ErrContainerBlobNotExist {
-			apiErrorDefined(ctx, errManifestUnknown)
-		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
-		}
+		apiManifestError(ctx, err)
 		return
 	}
 
@@ -642,13 +674,9 @@ func HeadManifest(ctx *context.Context) {
 
 // https://github.com/opencontainers/distribution-spec/blob/main/spec.md#pulling-manifests
 func GetManifest(ctx *context.Context) {
-	manifest, err := getManifestFromContext(ctx)
+	manifest, err := getOrCacheManifest(ctx)
 	if err != nil {
-		if err == container_model.ErrContainerBlobNotExist {
-			apiErrorDefined(ctx, errManifestUnknown)
-		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
-		}
+		apiManifestError(ctx, err)
 		return
 	}
 
@@ -677,7 +705,11 @@ func DeleteManifest(ctx *context.Context) {
 
 	for _, pv := range pvs {
 		if err := packages_service.RemovePackageVersion(ctx, ctx.Doer, pv); err != nil {
-			apiError(ctx, http.StatusInternalServerError, err)
+			if errors.Is(err, util.ErrPermissionDenied) {
+				apiError(ctx, http.StatusForbidden, err)
+			} else {
+				apiError(ctx, http.StatusInternalServerError, err)
+			}
 			return
 		}
 	}
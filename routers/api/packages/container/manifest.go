@@ -176,6 +176,11 @@ func processImageManifest(ctx context.Context, mci *manifestCreationInfo, buf *p
 			return err
 		}
 
+		if err := linkManifestSignatures(ctx, mci, pv, digest, buf); err != nil {
+			removeBlob = created
+			return err
+		}
+
 		if err := committer.Commit(); err != nil {
 			removeBlob = created
 			return err
@@ -280,6 +285,11 @@ func processImageManifestIndex(ctx context.Context, mci *manifestCreationInfo, b
 			return err
 		}
 
+		if err := linkManifestSignatures(ctx, mci, pv, digest, buf); err != nil {
+			removeBlob = created
+			return err
+		}
+
 		if err := committer.Commit(); err != nil {
 			removeBlob = created
 			return err
@@ -353,6 +363,13 @@ func createPackageAndVersion(ctx context.Context, mci *manifestCreationInfo, met
 	var pv *packages_model.PackageVersion
 	if pv, err = packages_model.GetOrInsertVersion(ctx, _pv); err != nil {
 		if err == packages_model.ErrDuplicatePackageVersion {
+			// a manifest referenced by digest has the same content, only the tags of an immutable image can't be moved
+			if mci.IsTagged {
+				if err := packages_service.CheckVersionIsMutable(ctx, mci.Creator, pv); err != nil {
+					return nil, err
+				}
+			}
+
 			if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
 				return nil, err
 			}
@@ -386,6 +403,12 @@ func createPackageAndVersion(ctx context.Context, mci *manifestCreationInfo, met
 			return nil, err
 		}
 	}
+	for name, value := range mci.Properties {
+		if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, name, value); err != nil {
+			log.Error("Error setting package version property: %v", err)
+			return nil, err
+		}
+	}
 
 	return pv, nil
 }
@@ -0,0 +1,173 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// cosignSignaturePredicateType is used for cosign signatures which are no in-toto statements
+	cosignSignaturePredicateType = "https://sigstore.dev/cosign/sign/v1"
+	// cosignAttestationPredicateType is used if the cosign attestation doesn't annotate its predicate type
+	cosignAttestationPredicateType = "https://in-toto.io/Statement/v0.1"
+)
+
+// cosign stores signatures, attestations and SBOMs of a manifest as tags named after its digest
+// https://github.com/sigstore/cosign/blob/main/specs/SIGNATURE_SPEC.md
+var signatureTagPattern = regexp.MustCompile(`\Asha256-([a-f0-9]{64})\.(sig|att|sbom)\z`)
+
+// isSignatureTag returns whether the reference is a tag holding a signature, an attestation or a SBOM
+func isSignatureTag(reference string) bool {
+	return signatureTagPattern.MatchString(strings.ToLower(reference))
+}
+
+// checkSignatureRequired returns ErrSignatureRequired if the owner requires signatures and the manifest is not signed.
+// Signatures are pushed after the manifest, so the manifest must be pushed by digest and signed before it's tagged.
+func checkSignatureRequired(ctx context.Context, mci *manifestCreationInfo, manifestDigest string) error {
+	if !mci.IsTagged || isSignatureTag(mci.Reference) {
+		return nil
+	}
+
+	required, err := packages_service.IsSignatureRequired(ctx, mci.Owner.ID)
+	if err != nil || !required {
+		return err
+	}
+
+	pvs, err := container_model.GetManifestVersions(ctx, &container_model.BlobSearchOptions{
+		OwnerID:    mci.Owner.ID,
+		Image:      mci.Image,
+		Digest:     manifestDigest,
+		IsManifest: true,
+	})
+	if err != nil {
+		return err
+	}
+	for _, pv := range pvs {
+		has, err := packages_model.HasAttestations(ctx, pv.ID)
+		if err != nil {
+			return err
+		}
+		if has {
+			return nil
+		}
+	}
+	return packages_service.ErrSignatureRequired
+}
+
+// signaturePredicateType returns the predicate type of a cosign signature or attestation manifest
+func signaturePredicateType(kind string, content []byte) (string, bool) {
+	switch kind {
+	case "sig":
+		return cosignSignaturePredicateType, true
+	case "att":
+		var manifest oci.Manifest
+		if err := json.Unmarshal(content, &manifest); err == nil && len(manifest.Layers) > 0 {
+			if predicateType := manifest.Layers[0].Annotations["predicateType"]; predicateType != "" {
+				return predicateType, true
+			}
+		}
+		return cosignAttestationPredicateType, true
+	}
+	return "", false
+}
+
+// linkSignatures links the cosign signatures and attestations to the manifests they are made for.
+// If the manifest is a signature, it's linked to all versions of the signed manifest.
+// Otherwise the existing signatures of the manifest are linked to the new version.
+func linkSignatures(ctx context.Context, mci *manifestCreationInfo, pv *packages_model.PackageVersion, manifestDigest string, content []byte) error {
+	if m := signatureTagPattern.FindStringSubmatch(strings.ToLower(mci.Reference)); m != nil {
+		predicateType, ok := signaturePredicateType(m[2], content)
+		if !ok {
+			return nil
+		}
+
+		pvs, err := container_model.GetManifestVersions(ctx, &container_model.BlobSearchOptions{
+			OwnerID:    mci.Owner.ID,
+			Image:      mci.Image,
+			Digest:     "sha256:" + m[1],
+			IsManifest: true,
+		})
+		if err != nil {
+			return err
+		}
+		for _, signed := range pvs {
+			if err := insertSignature(ctx, mci, signed.ID, pv.ID, predicateType, content); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, kind := range []string{"sig", "att"} {
+		tag := "sha256-" + strings.TrimPrefix(manifestDigest, "sha256:") + "." + kind
+
+		pfd, err := container_model.GetContainerBlob(ctx, &container_model.BlobSearchOptions{
+			OwnerID:    mci.Owner.ID,
+			Image:      mci.Image,
+			Tag:        tag,
+			IsManifest: true,
+		})
+		if err != nil {
+			if err == container_model.ErrContainerBlobNotExist {
+				continue
+			}
+			return err
+		}
+
+		signature, err := readManifestContent(pfd)
+		if err != nil {
+			return err
+		}
+
+		predicateType, _ := signaturePredicateType(kind, signature)
+		if err := insertSignature(ctx, mci, pv.ID, pfd.File.VersionID, predicateType, signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func linkManifestSignatures(ctx context.Context, mci *manifestCreationInfo, pv *packages_model.PackageVersion, manifestDigest string, buf *packages_module.HashedBuffer) error {
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	content, err := io.ReadAll(buf)
+	if err != nil {
+		return err
+	}
+	return linkSignatures(ctx, mci, pv, manifestDigest, content)
+}
+
+func insertSignature(ctx context.Context, mci *manifestCreationInfo, signedVersionID, signatureVersionID int64, predicateType string, content []byte) error {
+	return packages_model.InsertAttestation(ctx, &packages_model.PackageAttestation{
+		VersionID:          signedVersionID,
+		ReferenceVersionID: signatureVersionID,
+		PredicateType:      predicateType,
+		MediaType:          mci.MediaType,
+		Content:            string(content),
+		CreatorID:          mci.Creator.ID,
+	})
+}
+
+func readManifestContent(pfd *packages_model.PackageFileDescriptor) ([]byte, error) {
+	r, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pfd.Blob.HashSHA256))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(io.LimitReader(r, maxManifestSize))
+}
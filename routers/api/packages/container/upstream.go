@@ -0,0 +1,184 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"fmt"
+	"io"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	upstream_service "code.gitea.io/gitea/services/packages/upstream"
+
+	digest "github.com/opencontainers/go-digest"
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// upstreamImage stores the manifests and blobs of an image fetched from the upstream registry of the owner
+type upstreamImage struct {
+	registry *upstream_service.ContainerRegistry
+	owner    *user_model.User
+	creator  *user_model.User
+	image    string
+}
+
+// cacheUpstreamManifest fetches the requested manifest with its blobs from the upstream registry of the owner and stores it.
+// It returns container_model.ErrContainerBlobNotExist if there is no upstream registry or it doesn't know the manifest.
+func cacheUpstreamManifest(ctx *context.Context) error {
+	pu, err := upstream_service.GetUpstream(ctx, ctx.Package.Owner.ID, packages_model.TypeContainer)
+	if err != nil {
+		return err
+	}
+	if pu == nil {
+		return container_model.ErrContainerBlobNotExist
+	}
+
+	image := ctx.PathParam("image")
+	reference := ctx.PathParam("reference")
+	ui := &upstreamImage{
+		registry: upstream_service.NewContainerRegistry(pu, image),
+		owner:    ctx.Package.Owner,
+		creator:  upstream_service.Creator(ctx.Doer),
+		image:    image,
+	}
+	if err := ui.cacheManifest(ctx, reference, digest.Digest(reference).Validate() != nil); err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			return container_model.ErrContainerBlobNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+// cacheManifest stores a manifest of the upstream registry, the manifests of an index and the blobs of an image are stored first
+func (ui *upstreamImage) cacheManifest(ctx *context.Context, reference string, isTagged bool) error {
+	defer upstream_service.LockVersion(ui.owner.ID, packages_model.TypeContainer, ui.image, reference)()
+
+	opts := &container_model.BlobSearchOptions{
+		OwnerID:    ui.owner.ID,
+		Image:      ui.image,
+		IsManifest: true,
+	}
+	if isTagged {
+		opts.Tag = reference
+	} else {
+		opts.Digest = reference
+	}
+	if _, err := workaroundGetContainerBlob(ctx, opts); err == nil {
+		return nil
+	}
+
+	rc, mediaType, err := ui.registry.GetManifest(ctx, reference)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	maxSize := maxManifestSize + 1
+	buf, err := packages_module.CreateHashedBufferFromReaderWithSize(&io.LimitedReader{R: rc, N: int64(maxSize)}, maxSize)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	if buf.Size() > maxManifestSize {
+		return errManifestInvalid.WithMessage("Manifest exceeds maximum size")
+	}
+	manifestDigest := digestFromHashSummer(buf)
+	if !isTagged && manifestDigest != reference {
+		return fmt.Errorf("upstream manifest %s has the digest %s", reference, manifestDigest)
+	}
+
+	var manifest struct {
+		oci.Manifest
+		Manifests []oci.Descriptor `json:"manifests"`
+	}
+	if err := json.NewDecoder(buf).Decode(&manifest); err != nil {
+		return err
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if !isValidMediaType(mediaType) {
+		mediaType = manifest.MediaType
+	}
+
+	if isImageIndexMediaType(mediaType) {
+		for _, m := range manifest.Manifests {
+			if err := ui.cacheManifest(ctx, string(m.Digest), false); err != nil {
+				return err
+			}
+		}
+	} else if isImageManifestMediaType(mediaType) {
+		for _, blob := range append([]oci.Descriptor{manifest.Config}, manifest.Layers...) {
+			if err := ui.cacheBlob(ctx, blob); err != nil {
+				return err
+			}
+		}
+	}
+
+	mci := &manifestCreationInfo{
+		MediaType: mediaType,
+		Owner:     ui.owner,
+		Creator:   ui.creator,
+		Image:     ui.image,
+		Reference: reference,
+		IsTagged:  isTagged,
+		Properties: map[string]string{
+			packages_model.PropertyUpstreamURL: ui.registry.ManifestURL(reference),
+		},
+	}
+	if err := checkSignatureRequired(ctx, mci, manifestDigest); err != nil {
+		return err
+	}
+	_, err = processManifest(ctx, mci, buf)
+	return err
+}
+
+// cacheBlob stores a blob of the upstream registry if the image doesn't have it yet
+func (ui *upstreamImage) cacheBlob(ctx *context.Context, blob oci.Descriptor) error {
+	if err := blob.Digest.Validate(); err != nil {
+		return err
+	}
+	if _, err := container_model.GetContainerBlob(ctx, &container_model.BlobSearchOptions{
+		OwnerID: ui.owner.ID,
+		Image:   ui.image,
+		Digest:  string(blob.Digest),
+	}); err == nil {
+		return nil
+	}
+
+	rc, err := ui.registry.GetBlob(ctx, string(blob.Digest))
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	buf, err := packages_module.CreateHashedBufferFromReader(&io.LimitedReader{R: rc, N: blob.Size + 1})
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	if buf.Size() != blob.Size || digestFromHashSummer(buf) != string(blob.Digest) {
+		return fmt.Errorf("upstream blob %s doesn't match its descriptor", blob.Digest)
+	}
+
+	_, err = saveAsPackageBlob(ctx,
+		buf,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner: ui.owner,
+				Name:  ui.image,
+			},
+			Creator: ui.creator,
+		},
+	)
+	return err
+}
@@ -229,6 +229,16 @@ func DownloadSourcePackageFile(ctx *context.Context) {
 	})
 }
 
+// DownloadArchivedSourcePackageFile serves the source package files in the archive layout used to install older versions
+func DownloadArchivedSourcePackageFile(ctx *context.Context) {
+	if !strings.HasPrefix(ctx.PathParam("filename"), ctx.PathParam("packagename")+"_") {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	DownloadSourcePackageFile(ctx)
+}
+
 func DownloadBinaryPackageFile(ctx *context.Context) {
 	downloadPackageFile(ctx, &cran_model.SearchOptions{
 		OwnerID:  ctx.Package.Owner.ID,
@@ -247,6 +247,7 @@ func DeletePackageFile(ctx *context.Context) {
 	architecture := ctx.PathParam("architecture")
 
 	owner := ctx.Package.Owner
+	doer := ctx.Doer
 
 	var pd *packages_model.PackageDescriptor
 
@@ -256,6 +257,10 @@ func DeletePackageFile(ctx *context.Context) {
 			return err
 		}
 
+		if err := packages_service.CheckVersionIsMutable(ctx, doer, pv); err != nil {
+			return err
+		}
+
 		pf, err := packages_model.GetFileForVersionByName(
 			ctx,
 			pv.ID,
@@ -290,6 +295,8 @@ func DeletePackageFile(ctx *context.Context) {
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			apiError(ctx, http.StatusForbidden, err)
 		} else {
 			apiError(ctx, http.StatusInternalServerError, err)
 		}
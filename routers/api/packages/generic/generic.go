@@ -13,6 +13,7 @@ import (
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
@@ -159,6 +160,10 @@ func DeletePackage(ctx *context.Context) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, util.ErrPermissionDenied) {
+			apiError(ctx, http.StatusForbidden, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -190,6 +195,15 @@ func DeletePackageFile(ctx *context.Context) {
 		return
 	}
 
+	if err := packages_service.CheckVersionIsMutable(ctx, ctx.Doer, pv); err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) {
+			apiError(ctx, http.StatusForbidden, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
 	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"sort"
 	"time"
 
 	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	goproxy_module "code.gitea.io/gitea/modules/packages/goproxy"
@@ -19,6 +21,7 @@ import (
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
+	upstream_service "code.gitea.io/gitea/services/packages/upstream"
 )
 
 func apiError(ctx *context.Context, status int, obj any) {
@@ -28,29 +31,57 @@ func apiError(ctx *context.Context, status int, obj any) {
 }
 
 func EnumeratePackageVersions(ctx *context.Context) {
-	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeGo, ctx.PathParam("name"))
+	name, _ := moduleFromParams(ctx)
+
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeGo, name)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	if len(pvs) == 0 {
-		apiError(ctx, http.StatusNotFound, err)
-		return
-	}
 
 	sort.Slice(pvs, func(i, j int) bool {
 		return pvs[i].CreatedUnix < pvs[j].CreatedUnix
 	})
 
+	versions := make([]string, 0, len(pvs))
+	for _, pv := range pvs {
+		versions = append(versions, pv.Version)
+	}
+
+	pu, err := upstream_service.GetUpstream(ctx, ctx.Package.Owner.ID, packages_model.TypeGo)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if pu != nil {
+		// the versions of the upstream proxy are listed too, they are cached once they're requested
+		upstreamVersions, err := upstream_service.GetGoModuleVersions(ctx, pu, name)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			log.Warn("Unable to list the versions of %s from the upstream proxy of %s: %v", name, ctx.Package.Owner.Name, err)
+		}
+		for _, v := range upstreamVersions {
+			if !slices.Contains(versions, v) {
+				versions = append(versions, v)
+			}
+		}
+	}
+
+	if len(versions) == 0 {
+		apiError(ctx, http.StatusNotFound, err)
+		return
+	}
+
 	ctx.Resp.Header().Set("Content-Type", "text/plain;charset=utf-8")
 
-	for _, pv := range pvs {
-		fmt.Fprintln(ctx.Resp, pv.Version)
+	for _, v := range versions {
+		fmt.Fprintln(ctx.Resp, v)
 	}
 }
 
 func PackageVersionMetadata(ctx *context.Context) {
-	pv, err := resolvePackage(ctx, ctx.Package.Owner.ID, ctx.PathParam("name"), ctx.PathParam("version"))
+	name, version := moduleFromParams(ctx)
+
+	pv, err := resolvePackage(ctx, name, version)
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
@@ -70,7 +101,9 @@ func PackageVersionMetadata(ctx *context.Context) {
 }
 
 func PackageVersionGoModContent(ctx *context.Context) {
-	pv, err := resolvePackage(ctx, ctx.Package.Owner.ID, ctx.PathParam("name"), ctx.PathParam("version"))
+	name, version := moduleFromParams(ctx)
+
+	pv, err := resolvePackage(ctx, name, version)
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
@@ -90,7 +123,9 @@ func PackageVersionGoModContent(ctx *context.Context) {
 }
 
 func DownloadPackageFile(ctx *context.Context) {
-	pv, err := resolvePackage(ctx, ctx.Package.Owner.ID, ctx.PathParam("name"), ctx.PathParam("version"))
+	name, version := moduleFromParams(ctx)
+
+	pv, err := resolvePackage(ctx, name, version)
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
@@ -119,7 +154,39 @@ func DownloadPackageFile(ctx *context.Context) {
 	helper.ServePackageFile(ctx, s, u, pfs[0])
 }
 
-func resolvePackage(ctx *context.Context, ownerID int64, name, version string) (*packages_model.PackageVersion, error) {
+// moduleFromParams gets the module path and version from the url parameters, they are escaped by the go command
+func moduleFromParams(ctx *context.Context) (string, string) {
+	return goproxy_module.UnescapePath(ctx.PathParam("name")), goproxy_module.UnescapePath(ctx.PathParam("version"))
+}
+
+// resolvePackage gets the package version, a missing version is fetched from the upstream proxy of the owner if there is one
+func resolvePackage(ctx *context.Context, name, version string) (*packages_model.PackageVersion, error) {
+	pv, err := resolveLocalPackage(ctx, ctx.Package.Owner.ID, name, version)
+	if err == nil || !errors.Is(err, util.ErrNotExist) {
+		return pv, err
+	}
+
+	pu, upstreamErr := upstream_service.GetUpstream(ctx, ctx.Package.Owner.ID, packages_model.TypeGo)
+	if upstreamErr != nil {
+		return nil, upstreamErr
+	}
+	if pu == nil {
+		return nil, err
+	}
+
+	if version == "latest" {
+		version, err = upstream_service.GetGoModuleLatestVersion(ctx, pu, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := upstream_service.CacheGoModuleVersion(ctx, pu, ctx.Package.Owner, ctx.Doer, name, version); err != nil {
+		return nil, err
+	}
+	return packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeGo, name, version)
+}
+
+func resolveLocalPackage(ctx *context.Context, ownerID int64, name, version string) (*packages_model.PackageVersion, error) {
 	var pv *packages_model.PackageVersion
 
 	if version == "latest" {
@@ -0,0 +1,325 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package julia
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/optional"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	julia_module "code.gitea.io/gitea/modules/packages/julia"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/packages/helper"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/google/uuid"
+)
+
+func apiError(ctx *context.Context, status int, obj any) {
+	helper.LogAndProcessError(ctx, status, obj, func(message string) {
+		ctx.PlainText(status, message)
+	})
+}
+
+// RegistryUUID returns the uuid of the registry of the owner
+func RegistryUUID(owner *user_model.User) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(setting.AppURL+"api/packages/julia/"+strconv.FormatInt(owner.ID, 10))).String()
+}
+
+// buildRegistry creates the registry from all Julia packages of the owner
+func buildRegistry(ctx *context.Context) (*julia_module.RegistryArchive, error) {
+	pvs, err := packages_model.GetVersionsByPackageType(ctx, ctx.Package.Owner.ID, packages_model.TypeJulia)
+	if err != nil {
+		return nil, err
+	}
+
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &julia_module.Registry{
+		Name:        ctx.Package.Owner.Name,
+		UUID:        RegistryUUID(ctx.Package.Owner),
+		Description: fmt.Sprintf("Julia packages of %s", ctx.Package.Owner.Name),
+	}
+
+	packages := make(map[int64]*julia_module.RegistryPackage)
+	for _, pd := range pds {
+		rp, ok := packages[pd.Package.ID]
+		if !ok {
+			rp = &julia_module.RegistryPackage{
+				Name: pd.Package.Name,
+				UUID: pd.PackageProperties.GetByName(julia_module.PropertyUUID),
+			}
+			if pd.Repository != nil {
+				rp.Repo = pd.Repository.CloneLink().HTTPS
+			}
+			packages[pd.Package.ID] = rp
+			r.Packages = append(r.Packages, rp)
+		}
+
+		rp.Versions = append(rp.Versions, &julia_module.RegistryVersion{
+			Version:  pd.Version.Version,
+			TreeHash: pd.VersionProperties.GetByName(julia_module.PropertyTreeHash),
+			Metadata: pd.Metadata.(*julia_module.Metadata),
+		})
+	}
+
+	return julia_module.BuildRegistry(r)
+}
+
+// EnumerateRegistries lists the registry served for the owner
+// https://pkgdocs.julialang.org/v1/protocol/
+func EnumerateRegistries(ctx *context.Context) {
+	switch ctx.PathParam("flavor") {
+	case "", ".eager", ".conservative":
+	default:
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	a, err := buildRegistry(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.PlainText(http.StatusOK, fmt.Sprintf("/registry/%s/%s\n", RegistryUUID(ctx.Package.Owner), a.TreeHash))
+}
+
+// DownloadRegistry serves the registry as archive if the tree hash matches the current content
+func DownloadRegistry(ctx *context.Context) {
+	if ctx.PathParam("uuid") != RegistryUUID(ctx.Package.Owner) {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	a, err := buildRegistry(ctx)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	hash := ctx.PathParam("hash")
+	if hash != a.TreeHash {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := a.Write(&buf); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.ServeContent(bytes.NewReader(buf.Bytes()), &context.ServeHeaderOptions{
+		ContentType: "application/gzip",
+		Filename:    hash + ".tar.gz",
+	})
+}
+
+// getPackageByUUID returns the Julia package of the owner with the uuid
+func getPackageByUUID(ctx *context.Context, packageUUID string) (*packages_model.Package, error) {
+	ps, err := packages_model.GetPackagesByType(ctx, ctx.Package.Owner.ID, packages_model.TypeJulia)
+	if err != nil {
+		return nil, err
+	}
+
+	packageUUID = strings.ToLower(packageUUID)
+	for _, p := range ps {
+		pps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypePackage, p.ID, julia_module.PropertyUUID)
+		if err != nil {
+			return nil, err
+		}
+		if len(pps) > 0 && pps[0].Value == packageUUID {
+			return p, nil
+		}
+	}
+	return nil, packages_model.ErrPackageNotExist
+}
+
+// DownloadPackageFile serves the package version with the tree hash
+func DownloadPackageFile(ctx *context.Context) {
+	p, err := getPackageByUUID(ctx, ctx.PathParam("uuid"))
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		PackageID:  p.ID,
+		Properties: map[string]string{julia_module.PropertyTreeHash: ctx.PathParam("hash")},
+		IsInternal: optional.Some(false),
+	})
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pvs) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageNotExist)
+		return
+	}
+
+	pfs, err := packages_model.GetFilesByVersionID(ctx, pvs[0].ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pfs) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageFileNotExist)
+		return
+	}
+
+	s, u, pf, err := packages_service.GetPackageFileStream(ctx, pfs[0])
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+// UploadPackage creates a new package version from the .tar.gz archive of the package source
+func UploadPackage(ctx *context.Context) {
+	upload, needToClose, err := ctx.UploadStream()
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if needToClose {
+		defer upload.Close()
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(upload)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	pck, err := julia_module.ParsePackage(buf)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			apiError(ctx, http.StatusBadRequest, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the registry identifies packages by their uuid, so the name and the uuid must stay a unique pair
+	p, err := getPackageByUUID(ctx, pck.UUID)
+	if err == nil {
+		if p.LowerName != strings.ToLower(pck.Name) {
+			apiError(ctx, http.StatusBadRequest, util.NewInvalidArgumentErrorf("the uuid is already used by package %s", p.Name))
+			return
+		}
+	} else if err != packages_model.ErrPackageNotExist {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	} else {
+		p, err = packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packages_model.TypeJulia, pck.Name)
+		if err == nil {
+			apiError(ctx, http.StatusBadRequest, util.NewInvalidArgumentErrorf("the package %s has a different uuid", p.Name))
+			return
+		} else if err != packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	_, _, err = packages_service.CreatePackageAndAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeJulia,
+				Name:        pck.Name,
+				Version:     pck.Version,
+			},
+			SemverCompatible: true,
+			Creator:          ctx.Doer,
+			Metadata:         pck.Metadata,
+			PackageProperties: map[string]string{
+				julia_module.PropertyUUID: pck.UUID,
+			},
+			VersionProperties: map[string]string{
+				julia_module.PropertyTreeHash: pck.TreeHash,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: fmt.Sprintf("%s-%s.tar.gz", pck.Name, pck.Version),
+			},
+			Creator: ctx.Doer,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+	if err != nil {
+		switch err {
+		case packages_model.ErrDuplicatePackageVersion:
+			apiError(ctx, http.StatusConflict, err)
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			apiError(ctx, http.StatusForbidden, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// DeletePackage deletes the package version
+func DeletePackage(ctx *context.Context) {
+	err := packages_service.RemovePackageVersionByNameAndVersion(
+		ctx,
+		ctx.Doer,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeJulia,
+			Name:        ctx.PathParam("name"),
+			Version:     ctx.PathParam("version"),
+		},
+	)
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, util.ErrPermissionDenied) {
+			apiError(ctx, http.StatusForbidden, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
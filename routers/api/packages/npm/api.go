@@ -9,13 +9,14 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strings"
 
 	packages_model "code.gitea.io/gitea/models/packages"
 	npm_module "code.gitea.io/gitea/modules/packages/npm"
 	"code.gitea.io/gitea/modules/setting"
 )
 
-func createPackageMetadataResponse(registryURL string, pds []*packages_model.PackageDescriptor) *npm_module.PackageMetadata {
+func createPackageMetadataResponse(registryURL string, pds []*packages_model.PackageDescriptor, provenances map[int64]string) *npm_module.PackageMetadata {
 	sort.Slice(pds, func(i, j int) bool {
 		return pds[i].SemVer.LessThan(pds[j].SemVer)
 	})
@@ -25,6 +26,15 @@ func createPackageMetadataResponse(registryURL string, pds []*packages_model.Pac
 	for _, pd := range pds {
 		versions[pd.SemVer.String()] = createPackageMetadataVersion(registryURL, pd)
 
+		if predicateType, ok := provenances[pd.Version.ID]; ok {
+			versions[pd.SemVer.String()].Dist.Attestations = &npm_module.PackageDistributionAttestations{
+				URL: fmt.Sprintf("%s/-/npm/v1/attestations/%s@%s", registryURL, url.QueryEscape(pd.Package.Name), url.PathEscape(pd.Version.Version)),
+				Provenance: npm_module.PackageDistributionAttestationsProvenance{
+					PredicateType: predicateType,
+				},
+			}
+		}
+
 		for _, pvp := range pd.VersionProperties {
 			if pvp.Name == npm_module.TagProperty {
 				distTags[pvp.Value] = pd.Version.Version
@@ -78,6 +88,54 @@ func createPackageMetadataVersion(registryURL string, pd *packages_model.Package
 	}
 }
 
+// mergeUpstreamPackageMetadata adds the versions of the upstream registry which are not cached yet to the metadata.
+// Their tarballs are served by Gitea, which caches the version once it's downloaded.
+func mergeUpstreamPackageMetadata(registryURL string, metadata, upstream *npm_module.PackageMetadata) *npm_module.PackageMetadata {
+	if metadata == nil {
+		metadata = &npm_module.PackageMetadata{
+			ID:          upstream.Name,
+			Name:        upstream.Name,
+			Description: upstream.Description,
+			Readme:      upstream.Readme,
+			Homepage:    upstream.Homepage,
+			Keywords:    upstream.Keywords,
+			Author:      upstream.Author,
+			License:     upstream.License,
+			Repository:  upstream.Repository,
+			DistTags:    make(map[string]string),
+			Versions:    make(map[string]*npm_module.PackageMetadataVersion),
+		}
+	}
+
+	unscopedName := upstream.Name
+	if parts := strings.SplitN(upstream.Name, "/", 2); len(parts) == 2 {
+		unscopedName = parts[1]
+	}
+
+	for v, upstreamVersion := range upstream.Versions {
+		if _, has := metadata.Versions[v]; has {
+			continue
+		}
+
+		pmv := *upstreamVersion
+		filename := strings.ToLower(fmt.Sprintf("%s-%s.tgz", unscopedName, v))
+		pmv.Dist.Tarball = fmt.Sprintf("%s/%s/-/%s/%s", registryURL, url.QueryEscape(upstream.Name), url.PathEscape(v), url.PathEscape(filename))
+		metadata.Versions[v] = &pmv
+	}
+
+	// tags set in Gitea take precedence over the tags of the upstream registry
+	for tag, v := range upstream.DistTags {
+		if _, has := metadata.DistTags[tag]; has {
+			continue
+		}
+		if _, has := metadata.Versions[v]; has {
+			metadata.DistTags[tag] = v
+		}
+	}
+
+	return metadata
+}
+
 func createPackageSearchResponse(pds []*packages_model.PackageDescriptor, total int64) *npm_module.PackageSearch {
 	objects := make([]*npm_module.PackageSearchObject, 0, len(pds))
 	for _, pd := range pds {
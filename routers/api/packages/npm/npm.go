@@ -17,6 +17,8 @@ import (
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	npm_module "code.gitea.io/gitea/modules/packages/npm"
@@ -25,6 +27,7 @@ import (
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
+	upstream_service "code.gitea.io/gitea/services/packages/upstream"
 
 	"github.com/hashicorp/go-version"
 )
@@ -60,7 +63,14 @@ func PackageMetadata(ctx *context.Context) {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	if len(pvs) == 0 {
+
+	upstreamMetadata, err := getUpstreamPackageMetadata(ctx, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(pvs) == 0 && upstreamMetadata == nil {
 		apiError(ctx, http.StatusNotFound, err)
 		return
 	}
@@ -71,34 +81,128 @@ func PackageMetadata(ctx *context.Context) {
 		return
 	}
 
-	resp := createPackageMetadataResponse(
-		setting.AppURL+"api/packages/"+ctx.Package.Owner.Name+"/npm",
-		pds,
-	)
+	provenances, err := getProvenancePredicateTypes(ctx, pvs)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	registryURL := setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/npm"
+
+	var resp *npm_module.PackageMetadata
+	if len(pds) > 0 {
+		resp = createPackageMetadataResponse(registryURL, pds, provenances)
+	}
+	if upstreamMetadata != nil {
+		resp = mergeUpstreamPackageMetadata(registryURL, resp, upstreamMetadata)
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// getProvenancePredicateTypes returns the predicate types of the provenance statements of the package versions
+func getProvenancePredicateTypes(ctx *context.Context, pvs []*packages_model.PackageVersion) (map[int64]string, error) {
+	ids := make([]int64, 0, len(pvs))
+	for _, pv := range pvs {
+		ids = append(ids, pv.ID)
+	}
+
+	pas, err := packages_model.GetAttestationsByVersionIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	provenances := make(map[int64]string)
+	for _, pa := range pas {
+		if strings.HasPrefix(pa.MediaType, npm_module.ProvenanceMediaTypePrefix) {
+			provenances[pa.VersionID] = pa.PredicateType
+		}
+	}
+	return provenances, nil
+}
+
+// PackageAttestations returns the sigstore bundles published with a package version
+func PackageAttestations(ctx *context.Context) {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageNameFromParams(ctx), ctx.PathParam("version"))
+	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pas, err := packages_model.GetAttestationsByVersionID(ctx, pv.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := &npm_module.PackageAttestations{
+		Attestations: make([]*npm_module.PackageAttestation, 0, len(pas)),
+	}
+	for _, pa := range pas {
+		if !strings.HasPrefix(pa.MediaType, npm_module.ProvenanceMediaTypePrefix) {
+			continue
+		}
+		resp.Attestations = append(resp.Attestations, &npm_module.PackageAttestation{
+			PredicateType: pa.PredicateType,
+			Bundle:        json.RawMessage(pa.Content),
+		})
+	}
+	if len(resp.Attestations) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageAttestationNotExist)
+		return
+	}
 
 	ctx.JSON(http.StatusOK, resp)
 }
 
+// getUpstreamPackageMetadata fetches the package metadata from the upstream registry of the owner.
+// It returns nil if there is no upstream registry or it doesn't know the package.
+func getUpstreamPackageMetadata(ctx *context.Context, packageName string) (*npm_module.PackageMetadata, error) {
+	pu, err := upstream_service.GetUpstream(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm)
+	if err != nil || pu == nil {
+		return nil, err
+	}
+
+	metadata, err := upstream_service.GetNpmPackageMetadata(ctx, pu, packageName)
+	if err != nil {
+		if !errors.Is(err, util.ErrNotExist) {
+			// the cached versions are still served if the upstream registry is unavailable
+			log.Warn("Unable to fetch the metadata of %s from the upstream registry of %s: %v", packageName, ctx.Package.Owner.Name, err)
+		}
+		return nil, nil
+	}
+	return metadata, nil
+}
+
 // DownloadPackageFile serves the content of a package
 func DownloadPackageFile(ctx *context.Context) {
 	packageName := packageNameFromParams(ctx)
 	packageVersion := ctx.PathParam("version")
 	filename := ctx.PathParam("filename")
 
-	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
-		ctx,
-		&packages_service.PackageInfo{
-			Owner:       ctx.Package.Owner,
-			PackageType: packages_model.TypeNpm,
-			Name:        packageName,
-			Version:     packageVersion,
-		},
-		&packages_service.PackageFileInfo{
-			Filename: filename,
-		},
-	)
+	pvi := &packages_service.PackageInfo{
+		Owner:       ctx.Package.Owner,
+		PackageType: packages_model.TypeNpm,
+		Name:        packageName,
+		Version:     packageVersion,
+	}
+	pfi := &packages_service.PackageFileInfo{
+		Filename: filename,
+	}
+
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(ctx, pvi, pfi)
+	if err == packages_model.ErrPackageNotExist {
+		// a missing version is fetched from the upstream registry of the owner if there is one
+		if err = cacheUpstreamPackageVersion(ctx, packageName, packageVersion); err == nil {
+			s, u, pf, err = packages_service.GetFileStreamByPackageNameAndVersion(ctx, pvi, pfi)
+		}
+	}
 	if err != nil {
-		if err == packages_model.ErrPackageNotExist || err == packages_model.ErrPackageFileNotExist {
+		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
@@ -109,6 +213,18 @@ func DownloadPackageFile(ctx *context.Context) {
 	helper.ServePackageFile(ctx, s, u, pf)
 }
 
+// cacheUpstreamPackageVersion fetches the version from the upstream registry of the owner and stores it
+func cacheUpstreamPackageVersion(ctx *context.Context, packageName, packageVersion string) error {
+	pu, err := upstream_service.GetUpstream(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm)
+	if err != nil {
+		return err
+	}
+	if pu == nil {
+		return packages_model.ErrPackageNotExist
+	}
+	return upstream_service.CacheNpmPackageVersion(ctx, pu, ctx.Package.Owner, ctx.Doer, packageName, packageVersion)
+}
+
 // DownloadPackageFileByName finds the version and serves the contents of a package
 func DownloadPackageFileByName(ctx *context.Context) {
 	filename := ctx.PathParam("filename")
@@ -163,6 +279,22 @@ func UploadPackage(ctx *context.Context) {
 		return
 	}
 
+	if npmPackage.Provenance != nil && len(npmPackage.Provenance.Bundle) > packages_service.MaxAttestationSize {
+		apiError(ctx, http.StatusBadRequest, npm_module.ErrInvalidProvenance)
+		return
+	}
+	if npmPackage.Provenance == nil {
+		required, err := packages_service.IsSignatureRequired(ctx, ctx.Package.Owner.ID)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		if required {
+			apiError(ctx, http.StatusForbidden, packages_service.ErrSignatureRequired)
+			return
+		}
+	}
+
 	repo, err := repo_model.GetRepositoryByURL(ctx, npmPackage.Metadata.Repository.URL)
 	if err == nil {
 		canWrite := repo.OwnerID == ctx.Doer.ID
@@ -224,6 +356,13 @@ func UploadPackage(ctx *context.Context) {
 		return
 	}
 
+	if npmPackage.Provenance != nil {
+		if _, err := packages_service.AddAttestation(ctx, ctx.Doer, pv, npmPackage.Provenance.PredicateType, npmPackage.Provenance.MediaType, npmPackage.Provenance.Bundle); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
 	for _, tag := range npmPackage.DistTags {
 		if err := setPackageTag(ctx, tag, pv, false); err != nil {
 			if err == errInvalidTagName {
@@ -271,6 +410,10 @@ func DeletePackageVersion(ctx *context.Context) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, util.ErrPermissionDenied) {
+			apiError(ctx, http.StatusForbidden, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -282,22 +425,26 @@ func DeletePackageVersion(ctx *context.Context) {
 func DeletePackage(ctx *context.Context) {
 	packageName := packageNameFromParams(ctx)
 
-	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
+	p, err := packages_model.GetPackageByName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
 	if err != nil {
+		if err == packages_model.ErrPackageNotExist {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	if len(pvs) == 0 {
-		apiError(ctx, http.StatusNotFound, err)
-		return
-	}
-
-	for _, pv := range pvs {
-		if err := packages_service.RemovePackageVersion(ctx, ctx.Doer, pv); err != nil {
+	if err := packages_service.RemovePackage(ctx, ctx.Doer, p); err != nil {
+		switch {
+		case err == packages_model.ErrPackageNotExist:
+			apiError(ctx, http.StatusNotFound, err)
+		case errors.Is(err, util.ErrPermissionDenied):
+			apiError(ctx, http.StatusForbidden, err)
+		default:
 			apiError(ctx, http.StatusInternalServerError, err)
-			return
 		}
+		return
 	}
 
 	ctx.Status(http.StatusOK)
@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	nuget_model "code.gitea.io/gitea/models/packages/nuget"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	packages_module "code.gitea.io/gitea/modules/packages"
@@ -581,7 +582,8 @@ func UploadSymbolPackage(ctx *context.Context) {
 				Data:    pdb.Content,
 				IsLead:  false,
 				Properties: map[string]string{
-					nuget_module.PropertySymbolID: strings.ToLower(pdb.ID),
+					nuget_module.PropertySymbolID:       strings.ToLower(pdb.ID),
+					nuget_module.PropertySymbolChecksum: pdb.Checksum,
 				},
 			},
 		)
@@ -664,12 +666,36 @@ func DownloadSymbolFile(ctx *context.Context) {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	if len(pfs) != 1 {
+
+	checksums := parseSymbolChecksums(ctx.Req.Header.Get("SymbolChecksum"))
+
+	var symbolFile *packages_model.PackageFile
+	for _, pf := range pfs {
+		if pf.LowerName != strings.ToLower(filename) {
+			continue
+		}
+
+		if len(checksums) != 0 {
+			checksum, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeFile, pf.ID, nuget_module.PropertySymbolChecksum)
+			if err != nil {
+				apiError(ctx, http.StatusInternalServerError, err)
+				return
+			}
+			// Files uploaded without a checksum can't be verified
+			if len(checksum) != 0 && !checksums.Contains(checksum[0].Value) {
+				continue
+			}
+		}
+
+		symbolFile = pf
+		break
+	}
+	if symbolFile == nil {
 		apiError(ctx, http.StatusNotFound, nil)
 		return
 	}
 
-	s, u, pf, err := packages_service.GetPackageFileStream(ctx, pfs[0])
+	s, u, pf, err := packages_service.GetPackageFileStream(ctx, symbolFile)
 	if err != nil {
 		if err == packages_model.ErrPackageNotExist || err == packages_model.ErrPackageFileNotExist {
 			apiError(ctx, http.StatusNotFound, err)
@@ -682,6 +708,19 @@ func DownloadSymbolFile(ctx *context.Context) {
 	helper.ServePackageFile(ctx, s, u, pf)
 }
 
+// parseSymbolChecksums parses the SHA256 checksums of the SymbolChecksum header
+// https://github.com/dotnet/symstore/blob/main/docs/specs/SSQP_Checksum_Extension.md
+func parseSymbolChecksums(header string) container.Set[string] {
+	checksums := make(container.Set[string])
+	for _, part := range strings.Split(header, ";") {
+		algorithm, checksum, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if ok && strings.EqualFold(algorithm, "SHA256") {
+			checksums.Add(strings.ToLower(checksum))
+		}
+	}
+	return checksums
+}
+
 // DeletePackage hard deletes the package
 // https://docs.microsoft.com/en-us/nuget/api/package-publish-resource#delete-a-package
 func DeletePackage(ctx *context.Context) {
@@ -703,6 +742,10 @@ func DeletePackage(ctx *context.Context) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, util.ErrPermissionDenied) {
+			apiError(ctx, http.StatusForbidden, err)
+			return
+		}
 		apiError(ctx, http.StatusInternalServerError, err)
 	}
 
@@ -249,6 +249,10 @@ func DeletePackageFile(webctx *context.Context) {
 			return err
 		}
 
+		if err := packages_service.CheckVersionIsMutable(ctx, webctx.Doer, pv); err != nil {
+			return err
+		}
+
 		pf, err := packages_model.GetFileForVersionByName(
 			ctx,
 			pv.ID,
@@ -283,6 +287,8 @@ func DeletePackageFile(webctx *context.Context) {
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) {
 			apiError(webctx, http.StatusNotFound, err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			apiError(webctx, http.StatusForbidden, err)
 		} else {
 			apiError(webctx, http.StatusInternalServerError, err)
 		}
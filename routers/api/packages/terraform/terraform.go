@@ -0,0 +1,588 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	terraform_module "code.gitea.io/gitea/modules/packages/terraform"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/packages/helper"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/hashicorp/go-version"
+)
+
+// RegistryRoute is the route of the registry protocols, they have a single base URL for the instance and the namespaces are the owners
+const RegistryRoute = "/api/packages/-/terraform"
+
+func apiError(ctx *context.Context, status int, obj any) {
+	helper.LogAndProcessError(ctx, status, obj, func(message string) {
+		ctx.JSON(status, struct {
+			Errors []string `json:"errors"`
+		}{
+			Errors: []string{
+				message,
+			},
+		})
+	})
+}
+
+// ServiceDiscovery returns the base URLs of the registry protocols
+// https://developer.hashicorp.com/terraform/internals/remote-service-discovery
+func ServiceDiscovery(ctx *context.Context) {
+	ctx.JSON(http.StatusOK, map[string]string{
+		"modules.v1":   setting.AppSubURL + RegistryRoute + "/modules/v1/",
+		"providers.v1": setting.AppSubURL + RegistryRoute + "/providers/v1/",
+	})
+}
+
+func ownerBaseURL(ctx *context.Context) string {
+	return fmt.Sprintf("%sapi/packages/%s/terraform", setting.AppURL, url.PathEscape(ctx.Package.Owner.Name))
+}
+
+func moduleName(ctx *context.Context) (string, bool) {
+	name, system := ctx.PathParam("name"), ctx.PathParam("system")
+	if !terraform_module.IsValidName(name) || !terraform_module.IsValidName(system) {
+		return "", false
+	}
+	return name + "/" + system, true
+}
+
+// getPackageDescriptors returns the versions of the package sorted by semver, it writes the error response if there's none
+func getPackageDescriptors(ctx *context.Context, name string) []*packages_model.PackageDescriptor {
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, name)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return nil
+	}
+	if len(pvs) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageNotExist)
+		return nil
+	}
+
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return nil
+	}
+	sort.Slice(pds, func(i, j int) bool {
+		return pds[i].SemVer.LessThan(pds[j].SemVer)
+	})
+	return pds
+}
+
+// getPackageDescriptor returns the version of the package, it writes the error response if it fails
+func getPackageDescriptor(ctx *context.Context, name, packageVersion string) *packages_model.PackageDescriptor {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, name, packageVersion)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return nil
+	}
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return nil
+	}
+	return pd
+}
+
+func findFile(pd *packages_model.PackageDescriptor, filename string) *packages_model.PackageFileDescriptor {
+	for _, pfd := range pd.Files {
+		if pfd.File.Name == filename {
+			return pfd
+		}
+	}
+	return nil
+}
+
+// EnumerateModuleVersions lists the versions of a module
+// https://developer.hashicorp.com/terraform/internals/module-registry-protocol#list-available-versions-for-a-specific-module
+func EnumerateModuleVersions(ctx *context.Context) {
+	name, ok := moduleName(ctx)
+	if !ok {
+		apiError(ctx, http.StatusNotFound, terraform_module.ErrInvalidName)
+		return
+	}
+	pds := getPackageDescriptors(ctx, name)
+	if ctx.Written() {
+		return
+	}
+
+	type moduleVersion struct {
+		Version string `json:"version"`
+	}
+	versions := make([]*moduleVersion, 0, len(pds))
+	for _, pd := range pds {
+		versions = append(versions, &moduleVersion{Version: pd.Version.Version})
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{
+		"modules": []any{
+			map[string]any{"versions": versions},
+		},
+	})
+}
+
+// GetModuleDownloadURL returns the URL of the archive of a version of a module
+// https://developer.hashicorp.com/terraform/internals/module-registry-protocol#download-source-code-for-a-specific-module-version
+func GetModuleDownloadURL(ctx *context.Context) {
+	name, ok := moduleName(ctx)
+	if !ok {
+		apiError(ctx, http.StatusNotFound, terraform_module.ErrInvalidName)
+		return
+	}
+	pd := getPackageDescriptor(ctx, name, ctx.PathParam("version"))
+	if ctx.Written() {
+		return
+	}
+	if len(pd.Files) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageFileNotExist)
+		return
+	}
+
+	ctx.Resp.Header().Set("X-Terraform-Get", fmt.Sprintf("%s/modules/%s/%s/%s",
+		ownerBaseURL(ctx), pd.Package.Name, url.PathEscape(pd.Version.Version), url.PathEscape(pd.Files[0].File.Name)))
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadModule uploads the gzipped tar archive of a version of a module
+func UploadModule(ctx *context.Context) {
+	name, ok := moduleName(ctx)
+	if !ok {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidName)
+		return
+	}
+	moduleVersion := ctx.PathParam("version")
+	if _, err := version.NewSemver(moduleVersion); err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	upload, needsClose, err := ctx.UploadStream()
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if needsClose {
+		defer upload.Close()
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(upload)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	metadata, err := terraform_module.ParseModuleArchive(buf)
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, _, err = packages_service.CreatePackageAndAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeTerraform,
+				Name:        name,
+				Version:     moduleVersion,
+			},
+			SemverCompatible: true,
+			Creator:          ctx.Doer,
+			Metadata:         metadata,
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: fmt.Sprintf("%s-%s-%s.tar.gz", ctx.PathParam("name"), ctx.PathParam("system"), moduleVersion),
+			},
+			Creator: ctx.Doer,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+	if err != nil {
+		switch err {
+		case packages_model.ErrDuplicatePackageVersion:
+			apiError(ctx, http.StatusConflict, err)
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			apiError(ctx, http.StatusForbidden, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// DownloadModuleFile downloads the archive of a version of a module
+func DownloadModuleFile(ctx *context.Context) {
+	name, ok := moduleName(ctx)
+	if !ok {
+		apiError(ctx, http.StatusNotFound, terraform_module.ErrInvalidName)
+		return
+	}
+	downloadPackageFile(ctx, name)
+}
+
+func downloadPackageFile(ctx *context.Context, name string) {
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+		ctx,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        name,
+			Version:     ctx.PathParam("version"),
+		},
+		&packages_service.PackageFileInfo{
+			Filename: ctx.PathParam("filename"),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageNotExist) || errors.Is(err, packages_model.ErrPackageFileNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+type providerPlatform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+// EnumerateProviderVersions lists the versions of a provider which can be installed, the ones with a signed SHA256SUMS file
+// https://developer.hashicorp.com/terraform/internals/provider-registry-protocol#list-available-versions
+func EnumerateProviderVersions(ctx *context.Context) {
+	providerType := ctx.PathParam("provider")
+	if !terraform_module.IsValidName(providerType) {
+		apiError(ctx, http.StatusNotFound, terraform_module.ErrInvalidName)
+		return
+	}
+	pds := getPackageDescriptors(ctx, providerType)
+	if ctx.Written() {
+		return
+	}
+
+	type providerVersion struct {
+		Version   string              `json:"version"`
+		Protocols []string            `json:"protocols"`
+		Platforms []*providerPlatform `json:"platforms"`
+	}
+	versions := make([]*providerVersion, 0, len(pds))
+	for _, pd := range pds {
+		metadata := pd.Metadata.(*terraform_module.Metadata)
+		if metadata.SigningKey == nil {
+			continue
+		}
+		platforms := make([]*providerPlatform, 0, len(pd.Files))
+		for _, pfd := range pd.Files {
+			if os := pfd.Properties.GetByName(terraform_module.PropertyOS); os != "" {
+				platforms = append(platforms, &providerPlatform{OS: os, Arch: pfd.Properties.GetByName(terraform_module.PropertyArch)})
+			}
+		}
+		if len(platforms) == 0 {
+			continue
+		}
+		versions = append(versions, &providerVersion{
+			Version:   pd.Version.Version,
+			Protocols: metadata.Protocols,
+			Platforms: platforms,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, map[string]any{"versions": versions})
+}
+
+// GetProviderPackage returns the package of a version of a provider for a platform
+// https://developer.hashicorp.com/terraform/internals/provider-registry-protocol#find-a-provider-package
+func GetProviderPackage(ctx *context.Context) {
+	providerType := ctx.PathParam("provider")
+	if !terraform_module.IsValidName(providerType) {
+		apiError(ctx, http.StatusNotFound, terraform_module.ErrInvalidName)
+		return
+	}
+	pd := getPackageDescriptor(ctx, providerType, ctx.PathParam("version"))
+	if ctx.Written() {
+		return
+	}
+	metadata := pd.Metadata.(*terraform_module.Metadata)
+	if metadata.SigningKey == nil {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageFileNotExist)
+		return
+	}
+
+	var platformFile *packages_model.PackageFileDescriptor
+	for _, pfd := range pd.Files {
+		if pfd.Properties.GetByName(terraform_module.PropertyOS) == ctx.PathParam("os") &&
+			pfd.Properties.GetByName(terraform_module.PropertyArch) == ctx.PathParam("arch") {
+			platformFile = pfd
+			break
+		}
+	}
+	if platformFile == nil {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageFileNotExist)
+		return
+	}
+
+	versionURL := fmt.Sprintf("%s/providers/%s/%s", ownerBaseURL(ctx), url.PathEscape(pd.Package.Name), url.PathEscape(pd.Version.Version))
+	sumsFileName := terraform_module.SHA256SUMSFileName(pd.Package.Name, pd.Version.Version)
+
+	type gpgPublicKey struct {
+		KeyID          string `json:"key_id"`
+		ASCIIArmor     string `json:"ascii_armor"`
+		TrustSignature string `json:"trust_signature"`
+		Source         string `json:"source"`
+		SourceURL      string `json:"source_url,omitempty"`
+	}
+	ctx.JSON(http.StatusOK, map[string]any{
+		"protocols":             metadata.Protocols,
+		"os":                    ctx.PathParam("os"),
+		"arch":                  ctx.PathParam("arch"),
+		"filename":              platformFile.File.Name,
+		"download_url":          versionURL + "/" + url.PathEscape(platformFile.File.Name),
+		"shasums_url":           versionURL + "/" + url.PathEscape(sumsFileName),
+		"shasums_signature_url": versionURL + "/" + url.PathEscape(terraform_module.SHA256SUMSSignatureFileName(pd.Package.Name, pd.Version.Version)),
+		"shasum":                platformFile.Blob.HashSHA256,
+		"signing_keys": map[string]any{
+			"gpg_public_keys": []*gpgPublicKey{{
+				KeyID:      metadata.SigningKey.KeyID,
+				ASCIIArmor: metadata.SigningKey.ASCIIArmor,
+				Source:     ctx.Package.Owner.Name,
+				SourceURL:  ctx.Package.Owner.HTMLURL(),
+			}},
+		},
+	})
+}
+
+// readSHA256SUMS returns the content of the SHA256SUMS file of the version of the provider
+func readSHA256SUMS(ctx *context.Context, pd *packages_model.PackageDescriptor) ([]byte, error) {
+	pfd := findFile(pd, terraform_module.SHA256SUMSFileName(pd.Package.Name, pd.Version.Version))
+	if pfd == nil {
+		return nil, util.NewInvalidArgumentErrorf("the SHA256SUMS file must be uploaded first")
+	}
+	s, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pfd.Blob.HashSHA256))
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	return io.ReadAll(s)
+}
+
+// getSigningKeys returns the armored GPG keys of the doer
+func getSigningKeys(ctx *context.Context) ([]string, error) {
+	keys, err := db.Find[asymkey_model.GPGKey](ctx, asymkey_model.FindGPGKeyOptions{OwnerID: ctx.Doer.ID})
+	if err != nil {
+		return nil, err
+	}
+	armoredKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		imported, err := asymkey_model.GetGPGImportByKeyID(ctx, key.KeyID)
+		if err != nil {
+			if asymkey_model.IsErrGPGKeyImportNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		armoredKeys = append(armoredKeys, imported.Content)
+	}
+	return armoredKeys, nil
+}
+
+// UploadProviderFile uploads a file of a version of a provider, as built by goreleaser.
+// The SHA256SUMS file must be uploaded first, the archives of the platforms must match their checksums,
+// and its signature must be made by a GPG key of the user uploading it.
+func UploadProviderFile(ctx *context.Context) {
+	providerType := ctx.PathParam("provider")
+	if !terraform_module.IsValidName(providerType) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidName)
+		return
+	}
+	providerVersion := ctx.PathParam("version")
+	if _, err := version.NewSemver(providerVersion); err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	filename := ctx.PathParam("filename")
+
+	upload, needsClose, err := ctx.UploadStream()
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if needsClose {
+		defer upload.Close()
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(upload)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	pci := &packages_service.PackageCreationInfo{
+		PackageInfo: packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        providerType,
+			Version:     providerVersion,
+		},
+		SemverCompatible: true,
+		Creator:          ctx.Doer,
+		Metadata: &terraform_module.Metadata{
+			Kind:      terraform_module.KindProvider,
+			Protocols: []string{terraform_module.DefaultProtocol},
+		},
+	}
+	pfci := &packages_service.PackageFileCreationInfo{
+		PackageFileInfo: packages_service.PackageFileInfo{
+			Filename: filename,
+		},
+		Creator: ctx.Doer,
+		Data:    buf,
+	}
+
+	// the SHA256SUMS file creates the version, the other files are added to it
+	if filename == terraform_module.SHA256SUMSFileName(providerType, providerVersion) {
+		if _, err := terraform_module.ParseSHA256SUMS(buf); err != nil {
+			apiError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		if _, err := buf.Seek(0, io.SeekStart); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		if _, _, err := packages_service.CreatePackageOrAddFileToExisting(ctx, pci, pfci); err != nil {
+			processUploadError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusCreated)
+		return
+	}
+
+	pd := getPackageDescriptor(ctx, providerType, providerVersion)
+	if ctx.Written() {
+		return
+	}
+	sums, err := readSHA256SUMS(ctx, pd)
+	if err != nil {
+		processUploadError(ctx, err)
+		return
+	}
+	metadata := pd.Metadata.(*terraform_module.Metadata)
+
+	switch filename {
+	case terraform_module.SHA256SUMSSignatureFileName(providerType, providerVersion):
+		signature, err := io.ReadAll(buf)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		armoredKeys, err := getSigningKeys(ctx)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		if metadata.SigningKey, err = terraform_module.VerifySHA256SUMSSignature(armoredKeys, sums, signature); err != nil {
+			apiError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	case terraform_module.ManifestFileName(providerType, providerVersion):
+		if metadata.Protocols, err = terraform_module.ParseManifest(buf); err != nil {
+			apiError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		os, arch, ok := terraform_module.ParsePlatformFileName(providerType, providerVersion, filename)
+		if !ok {
+			apiError(ctx, http.StatusBadRequest, util.NewInvalidArgumentErrorf("unexpected file name %q", filename))
+			return
+		}
+		checksums, err := terraform_module.ParseSHA256SUMS(bytes.NewReader(sums))
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		_, _, hashSHA256, _ := buf.Sums()
+		if expected, has := checksums[filename]; !has || expected != hex.EncodeToString(hashSHA256) {
+			apiError(ctx, http.StatusBadRequest, util.NewInvalidArgumentErrorf("the checksum of %q doesn't match the SHA256SUMS file", filename))
+			return
+		}
+		pfci.IsLead = true
+		pfci.Properties = map[string]string{
+			terraform_module.PropertyOS:   os,
+			terraform_module.PropertyArch: arch,
+		}
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if _, err := packages_service.AddFileToPackageVersionInternal(ctx, pd.Version, pfci); err != nil {
+		processUploadError(ctx, err)
+		return
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	pd.Version.MetadataJSON = string(metadataJSON)
+	if err := packages_model.UpdateVersion(ctx, pd.Version); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+func processUploadError(ctx *context.Context, err error) {
+	switch {
+	case errors.Is(err, packages_model.ErrDuplicatePackageFile):
+		apiError(ctx, http.StatusConflict, err)
+	case errors.Is(err, util.ErrInvalidArgument):
+		apiError(ctx, http.StatusBadRequest, err)
+	case errors.Is(err, packages_service.ErrQuotaTotalCount), errors.Is(err, packages_service.ErrQuotaTypeSize), errors.Is(err, packages_service.ErrQuotaTotalSize):
+		apiError(ctx, http.StatusForbidden, err)
+	default:
+		apiError(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// DownloadProviderFile downloads a file of a version of a provider
+func DownloadProviderFile(ctx *context.Context) {
+	downloadPackageFile(ctx, ctx.PathParam("provider"))
+}
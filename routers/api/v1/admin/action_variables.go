@@ -0,0 +1,175 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListActionsSecrets lists the secrets of the instance
+func ListActionsSecrets(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/secrets admin adminListActionsSecrets
+	// ---
+	// summary: List the secrets of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListActionsSecrets(ctx, 0, 0, "")
+}
+
+// CreateOrUpdateActionsSecret creates or updates a secret of the instance
+func CreateOrUpdateActionsSecret(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/actions/secrets/{secretname} admin adminCreateOrUpdateActionsSecret
+	// ---
+	// summary: Create or update a secret of the instance, which is overridden by the secrets of the owners and of the repositories
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateSecretOption"
+	// responses:
+	//   "201":
+	//     description: response when creating a secret
+	//   "204":
+	//     description: response when updating a secret
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.CreateOrUpdateActionsSecret(ctx, 0, 0, "")
+}
+
+// DeleteActionsSecret deletes a secret of the instance
+func DeleteActionsSecret(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/secrets/{secretname} admin adminDeleteActionsSecret
+	// ---
+	// summary: Delete a secret of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: the secret has been deleted
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteActionsSecret(ctx, 0, 0, "")
+}
+
+// ListActionsVariables lists the variables of the instance
+func ListActionsVariables(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/variables admin adminListActionsVariables
+	// ---
+	// summary: List the variables of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VariableList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListActionsVariables(ctx, 0, 0, "")
+}
+
+// CreateOrUpdateActionsVariable creates or updates a variable of the instance
+func CreateOrUpdateActionsVariable(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/actions/variables/{variablename} admin adminCreateOrUpdateActionsVariable
+	// ---
+	// summary: Create or update a variable of the instance, which is overridden by the variables of the owners and of the repositories
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateVariableOption"
+	// responses:
+	//   "201":
+	//     description: response when creating a variable
+	//   "204":
+	//     description: response when updating a variable
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.CreateOrUpdateActionsVariable(ctx, 0, 0, "")
+}
+
+// DeleteActionsVariable deletes a variable of the instance
+func DeleteActionsVariable(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/actions/variables/{variablename} admin adminDeleteActionsVariable
+	// ---
+	// summary: Delete a variable of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: the variable has been deleted
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteActionsVariable(ctx, 0, 0, "")
+}
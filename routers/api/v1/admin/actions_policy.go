@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionsPolicy returns the actions the workflows of the instance can use
+func GetActionsPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /admin/actions/policy admin adminGetActionsPolicy
+	// ---
+	// summary: Get the actions the workflows of all the repositories of the instance can use
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.GetActionsPolicy(ctx, 0)
+}
+
+// EditActionsPolicy sets the actions the workflows of the instance can use
+func EditActionsPolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/actions/policy admin adminEditActionsPolicy
+	// ---
+	// summary: Set the actions the workflows of all the repositories of the instance can use, the runs using other actions fail
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionsPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsPolicy"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.EditActionsPolicy(ctx, 0)
+}
@@ -0,0 +1,224 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/label"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// optionTemplateType returns the type of the templates from the path, it responds with 404 if it is unknown
+func optionTemplateType(ctx *context.APIContext) (repo_model.OptionTemplateType, bool) {
+	tp := repo_model.OptionTemplateType(ctx.PathParam(":type"))
+	if !tp.IsValid() {
+		ctx.NotFound()
+		return "", false
+	}
+	return tp, true
+}
+
+// isValidOptionTemplateName checks that the name can be used as the file name of a template
+func isValidOptionTemplateName(name string) bool {
+	return name != "" && len(name) <= 255 &&
+		!strings.HasPrefix(name, ".") && !strings.ContainsAny(name, `/\`) &&
+		util.PathJoinRelX(name) == name
+}
+
+// ListOptionTemplates lists the templates of a type managed by the administrators
+func ListOptionTemplates(ctx *context.APIContext) {
+	// swagger:operation GET /admin/templates/{type} admin adminListOptionTemplates
+	// ---
+	// summary: List the gitignore, license or label templates managed by the administrators
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: path
+	//   description: type of the templates
+	//   type: string
+	//   enum: [gitignore, license, label]
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OptionTemplateList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	tp, ok := optionTemplateType(ctx)
+	if !ok {
+		return
+	}
+	tmpls, err := repo_model.FindOptionTemplates(ctx, tp)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindOptionTemplates", err)
+		return
+	}
+	apiTmpls := make([]*api.OptionTemplate, len(tmpls))
+	for i := range tmpls {
+		apiTmpls[i] = convert.ToOptionTemplate(tmpls[i])
+	}
+	ctx.JSON(http.StatusOK, apiTmpls)
+}
+
+// GetOptionTemplate gets a template managed by the administrators
+func GetOptionTemplate(ctx *context.APIContext) {
+	// swagger:operation GET /admin/templates/{type}/{name} admin adminGetOptionTemplate
+	// ---
+	// summary: Get a gitignore, license or label template managed by the administrators
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: path
+	//   description: type of the template
+	//   type: string
+	//   enum: [gitignore, license, label]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OptionTemplate"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	tp, ok := optionTemplateType(ctx)
+	if !ok {
+		return
+	}
+	tmpl, err := repo_model.GetOptionTemplate(ctx, tp, ctx.PathParam(":name"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOptionTemplate", err)
+		return
+	} else if tmpl == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToOptionTemplate(tmpl))
+}
+
+// SetOptionTemplate creates or updates a template managed by the administrators
+func SetOptionTemplate(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/templates/{type}/{name} admin adminSetOptionTemplate
+	// ---
+	// summary: Create or update a gitignore, license or label template, it overrides the template file with the same name
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: path
+	//   description: type of the template
+	//   type: string
+	//   enum: [gitignore, license, label]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditOptionTemplateOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OptionTemplate"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	tp, ok := optionTemplateType(ctx)
+	if !ok {
+		return
+	}
+	name := ctx.PathParam(":name")
+	if !isValidOptionTemplateName(name) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid template name")
+		return
+	}
+	form := web.GetForm(ctx).(*api.EditOptionTemplateOption)
+	if tp == repo_model.OptionTemplateLabel {
+		if _, err := label.ParseTemplate(name+".yaml", []byte(form.Content)); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+	}
+
+	tmpl := &repo_model.OptionTemplate{Type: tp, Name: name, Content: form.Content}
+	if err := repo_model.SaveOptionTemplate(ctx, tmpl); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SaveOptionTemplate", err)
+		return
+	}
+	// reload it to get the timestamps set by the database
+	tmpl, err := repo_model.GetOptionTemplate(ctx, tp, name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOptionTemplate", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToOptionTemplate(tmpl))
+}
+
+// DeleteOptionTemplate deletes a template managed by the administrators
+func DeleteOptionTemplate(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/templates/{type}/{name} admin adminDeleteOptionTemplate
+	// ---
+	// summary: Delete a gitignore, license or label template managed by the administrators
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: path
+	//   description: type of the template
+	//   type: string
+	//   enum: [gitignore, license, label]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	tp, ok := optionTemplateType(ctx)
+	if !ok {
+		return
+	}
+	if err := repo_model.DeleteOptionTemplate(ctx, tp, ctx.PathParam(":name")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteOptionTemplate", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// GetUserPackageUsage gets the storage used by the packages of a user or an organization
+func GetUserPackageUsage(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users/{username}/packages/usage admin adminGetUserPackageUsage
+	// ---
+	// summary: Get the storage used by the packages of a user or an organization for each package type
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageUsage"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetOwnerPackageUsage(ctx, ctx.ContextUser)
+}
+
+// SetUserPackageQuota sets the package storage quota of a user or an organization
+func SetUserPackageQuota(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/users/{username}/packages/quota admin adminSetUserPackageQuota
+	// ---
+	// summary: Set the package storage quota of a user or an organization, it overrides the instance setting
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPackageQuotaOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageUsage"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetPackageQuotaOption)
+
+	if err := packages_service.SetTotalSizeLimit(ctx, ctx.ContextUser.ID, *form.TotalSizeLimit); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetTotalSizeLimit", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetTotalSizeLimit", err)
+		}
+		return
+	}
+
+	utils.GetOwnerPackageUsage(ctx, ctx.ContextUser)
+}
+
+// ResetUserPackageQuota removes the package storage quota of a user or an organization
+func ResetUserPackageQuota(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/users/{username}/packages/quota admin adminResetUserPackageQuota
+	// ---
+	// summary: Remove the package storage quota of a user or an organization, the instance setting applies again
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := packages_service.ResetTotalSizeLimit(ctx, ctx.ContextUser.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ResetTotalSizeLimit", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListLargestPackages lists the packages using the most storage
+func ListLargestPackages(ctx *context.APIContext) {
+	// swagger:operation GET /admin/packages/largest admin adminListLargestPackages
+	// ---
+	// summary: List the packages of all owners using the most storage, the largest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: package type filter
+	//   type: string
+	//   enum: [alpine, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, julia, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, terraform, vagrant]
+	// - name: limit
+	//   in: query
+	//   description: number of packages to return
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageSizeList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+
+	sizes, err := packages_model.GetLargestPackages(ctx, packages_model.Type(ctx.FormTrim("type")), listOptions.PageSize)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLargestPackages", err)
+		return
+	}
+
+	ownerIDs := make([]int64, 0, len(sizes))
+	for _, s := range sizes {
+		ownerIDs = append(ownerIDs, s.OwnerID)
+	}
+	users, err := user_model.GetUsersByIDs(ctx, ownerIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsersByIDs", err)
+		return
+	}
+	owners := make(map[int64]*user_model.User, len(users))
+	for _, u := range users {
+		owners[u.ID] = u
+	}
+
+	apiSizes := make([]*api.PackageSize, 0, len(sizes))
+	for _, s := range sizes {
+		owner, ok := owners[s.OwnerID]
+		if !ok {
+			owner = user_model.NewGhostUser()
+		}
+		apiSizes = append(apiSizes, &api.PackageSize{
+			Owner:        convert.ToUser(ctx, owner, ctx.Doer),
+			Type:         string(s.Type),
+			Name:         s.Name,
+			VersionCount: s.VersionCount,
+			Size:         s.Size,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, apiSizes)
+}
@@ -24,3 +24,600 @@ func GetRegistrationToken(ctx *context.APIContext) {
 
 	shared.GetRegistrationToken(ctx, 0, 0)
 }
+
+// CreateEphemeralRegistrationToken creates a token registering a single ephemeral global runner
+func CreateEphemeralRegistrationToken(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners/ephemeral-registration-token admin adminCreateEphemeralRunnerRegistrationToken
+	// ---
+	// summary: Create a token registering a single ephemeral global runner, removed once it has run a job
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionEphemeralRunnerTokenOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionEphemeralRunnerToken"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.CreateEphemeralRegistrationToken(ctx, 0, 0)
+}
+
+// ListQueuedJobs lists the jobs of the instance waiting for a runner by set of labels
+func ListQueuedJobs(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runners/queue admin adminListRunnerQueuedJobs
+	// ---
+	// summary: List the number of jobs of the instance waiting for a runner by set of labels
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionQueuedJobsList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListQueuedJobs(ctx, 0)
+}
+
+// ExpireRunner removes a global runner at once
+func ExpireRunner(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners/{runner_id}/expire admin adminExpireRunner
+	// ---
+	// summary: Remove a global runner at once, the tasks it's running fail
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ExpireRunner(ctx, 0)
+}
+
+// ListRunnerGroups lists the runner groups of the instance
+func ListRunnerGroups(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runner-groups admin adminListRunnerGroups
+	// ---
+	// summary: List the runner groups of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroupsList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListRunnerGroups(ctx, 0)
+}
+
+// CreateRunnerGroup creates a runner group for the instance
+func CreateRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runner-groups admin adminCreateRunnerGroup
+	// ---
+	// summary: Create a runner group for the instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionRunnerGroupOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.CreateRunnerGroup(ctx, 0)
+}
+
+// GetRunnerGroup gets a runner group of the instance
+func GetRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runner-groups/{group_id} admin adminGetRunnerGroup
+	// ---
+	// summary: Get a runner group of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.GetRunnerGroup(ctx, 0)
+}
+
+// EditRunnerGroup edits a runner group of the instance
+func EditRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/runner-groups/{group_id} admin adminEditRunnerGroup
+	// ---
+	// summary: Edit a runner group of the instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerGroupOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.EditRunnerGroup(ctx, 0)
+}
+
+// DeleteRunnerGroup deletes a runner group of the instance, its runners aren't in a group anymore
+func DeleteRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/runner-groups/{group_id} admin adminDeleteRunnerGroup
+	// ---
+	// summary: Delete a runner group of the instance, its runners aren't in a group anymore
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.DeleteRunnerGroup(ctx, 0)
+}
+
+// ListRunnerGroupRunners lists the runners of a runner group of the instance
+func ListRunnerGroupRunners(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runner-groups/{group_id}/runners admin adminListRunnerGroupRunners
+	// ---
+	// summary: List the runners of a runner group of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnersList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListRunnerGroupRunners(ctx, 0)
+}
+
+// AddRunnerGroupRunner moves a global runner to a runner group
+func AddRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/runner-groups/{group_id}/runners/{runner_id} admin adminAddRunnerGroupRunner
+	// ---
+	// summary: Move a global runner to a runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.AddRunnerGroupRunner(ctx, 0)
+}
+
+// RemoveRunnerGroupRunner removes a runner from a runner group of the instance
+func RemoveRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/runner-groups/{group_id}/runners/{runner_id} admin adminRemoveRunnerGroupRunner
+	// ---
+	// summary: Remove a runner from a runner group of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.RemoveRunnerGroupRunner(ctx, 0)
+}
+
+// ListRunnerGroupRepos lists the repositories a runner group of the instance is granted to
+func ListRunnerGroupRepos(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runner-groups/{group_id}/repositories admin adminListRunnerGroupRepos
+	// ---
+	// summary: List the repositories a runner group of the instance is granted to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListRunnerGroupRepos(ctx, 0)
+}
+
+// AddRunnerGroupRepo grants a runner group of the instance to a repository
+func AddRunnerGroupRepo(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/runner-groups/{group_id}/repositories/{repo_id} admin adminAddRunnerGroupRepo
+	// ---
+	// summary: Grant a runner group of the instance to a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: repo_id
+	//   in: path
+	//   description: id of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.AddRunnerGroupRepo(ctx, 0)
+}
+
+// RemoveRunnerGroupRepo revokes the access of a repository to a runner group of the instance
+func RemoveRunnerGroupRepo(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/runner-groups/{group_id}/repositories/{repo_id} admin adminRemoveRunnerGroupRepo
+	// ---
+	// summary: Revoke the access of a repository to a runner group of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: repo_id
+	//   in: path
+	//   description: id of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.RemoveRunnerGroupRepo(ctx, 0)
+}
+
+// ListRunnerGroupOrgs lists the organizations a group of global runners is granted to
+func ListRunnerGroupOrgs(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runner-groups/{group_id}/organizations admin adminListRunnerGroupOrgs
+	// ---
+	// summary: List the organizations a group of global runners is granted to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrganizationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListRunnerGroupOrgs(ctx)
+}
+
+// AddRunnerGroupOrg grants a group of global runners to all the repositories of an organization
+func AddRunnerGroupOrg(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/runner-groups/{group_id}/organizations/{org_id} admin adminAddRunnerGroupOrg
+	// ---
+	// summary: Grant a group of global runners to all the repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: org_id
+	//   in: path
+	//   description: id of the organization
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.AddRunnerGroupOrg(ctx)
+}
+
+// RemoveRunnerGroupOrg revokes the access of an organization to a group of global runners
+func RemoveRunnerGroupOrg(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/runner-groups/{group_id}/organizations/{org_id} admin adminRemoveRunnerGroupOrg
+	// ---
+	// summary: Revoke the access of an organization to a group of global runners
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: org_id
+	//   in: path
+	//   description: id of the organization
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.RemoveRunnerGroupOrg(ctx)
+}
+
+// ListRunnerLabels lists the labels of a global runner
+func ListRunnerLabels(ctx *context.APIContext) {
+	// swagger:operation GET /admin/runners/{runner_id}/labels admin adminListRunnerLabels
+	// ---
+	// summary: List the labels of a global runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListRunnerLabels(ctx, 0)
+}
+
+// SetRunnerLabels replaces the custom labels of a global runner
+func SetRunnerLabels(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/runners/{runner_id}/labels admin adminSetRunnerLabels
+	// ---
+	// summary: Replace the custom labels of a global runner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ActionRunnerLabelsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.SetRunnerLabels(ctx, 0)
+}
+
+// AddRunnerLabels adds custom labels to a global runner
+func AddRunnerLabels(ctx *context.APIContext) {
+	// swagger:operation POST /admin/runners/{runner_id}/labels admin adminAddRunnerLabels
+	// ---
+	// summary: Add custom labels to a global runner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ActionRunnerLabelsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.AddRunnerLabels(ctx, 0)
+}
+
+// RemoveRunnerLabel removes a custom label from a global runner
+func RemoveRunnerLabel(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/runners/{runner_id}/labels/{name} admin adminRemoveRunnerLabel
+	// ---
+	// summary: Remove a custom label from a global runner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the label
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.RemoveRunnerLabel(ctx, 0)
+}
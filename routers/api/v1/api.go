@@ -694,6 +694,31 @@ func bind[T any](_ T) any {
 	}
 }
 
+// branchActions runs the handlers of the action posted to a branch, branch names may contain slashes
+// so the action is matched as the suffix of the wildcard instead of by the router
+func branchActions(actions map[string][]any) func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		for action, handlers := range actions {
+			if !strings.HasSuffix(ctx.PathParam("*"), "/"+action) {
+				continue
+			}
+			for _, handler := range handlers {
+				switch h := handler.(type) {
+				case func(*context.APIContext):
+					h(ctx)
+				default:
+					panic(fmt.Sprintf("unsupported branch action handler type: %T", handler))
+				}
+				if ctx.Written() {
+					return
+				}
+			}
+			return
+		}
+		ctx.NotFound()
+	}
+}
+
 func buildAuthGroup() *auth.Group {
 	group := auth.NewGroup(
 		&auth.OAuth2{},
@@ -1049,6 +1074,7 @@ func Routes() *web.Router {
 			m.Get("/times", repo.ListMyTrackedTimes)
 			m.Get("/stopwatches", repo.GetStopwatches)
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
+			m.Get("/packages/usage", user.GetPackageUsage)
 			m.Get("/teams", org.ListUserTeams)
 			m.Group("/hooks", func() {
 				m.Combo("").Get(user.ListHooks).
@@ -1092,6 +1118,7 @@ func Routes() *web.Router {
 
 			m.Group("/{username}/{reponame}", func() {
 				m.Get("/compare/*", reqRepoReader(unit.TypeCode), repo.CompareDiff)
+				m.Get("/search", context.ReferencesGitRepo(true), repo.SearchContent)
 
 				m.Combo("").Get(reqAnyRepoReader(), repo.Get).
 					Delete(reqToken(), reqOwner(), repo.Delete).
@@ -1102,11 +1129,26 @@ func Routes() *web.Router {
 					m.Post("/accept", repo.AcceptTransfer)
 					m.Post("/reject", repo.RejectTransfer)
 				}, reqToken())
+				m.Combo("/lfs/migrate", reqToken(), reqAdmin()).Get(repo.GetLFSMigration).
+					Post(bind(api.MigrateToLFSOption{}), repo.MigrateToLFS)
 				addActionsRoutes(
 					m,
 					reqOwner(),
 					repo.NewAction(),
 				)
+				m.Group("/actions", func() {
+					m.Get("/effective-variables", repo.GetActionEffectiveVariables)
+					m.Group("/environments/{environment}", func() {
+						m.Get("/secrets", repo.ListActionEnvironmentSecrets)
+						m.Combo("/secrets/{secretname}").
+							Put(bind(api.CreateOrUpdateSecretOption{}), repo.CreateOrUpdateActionEnvironmentSecret).
+							Delete(repo.DeleteActionEnvironmentSecret)
+						m.Get("/variables", repo.ListActionEnvironmentVariables)
+						m.Combo("/variables/{variablename}").
+							Put(bind(api.CreateVariableOption{}), repo.CreateOrUpdateActionEnvironmentVariable).
+							Delete(repo.DeleteActionEnvironmentVariable)
+					})
+				}, reqToken(), reqOwner())
 				m.Group("/hooks/git", func() {
 					m.Combo("").Get(repo.ListGitHooks)
 					m.Group("/{id}", func() {
@@ -1144,7 +1186,9 @@ func Routes() *web.Router {
 				}, reqToken())
 				m.Get("/raw/*", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetRawFile)
 				m.Get("/media/*", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetRawFileOrLFS)
+				m.Get("/blame/*", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetBlame)
 				m.Get("/archive/*", reqRepoReader(unit.TypeCode), repo.GetArchive)
+				m.Get("/bundle/*", reqRepoReader(unit.TypeCode), repo.GetBundle)
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), reqRepoReader(unit.TypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
 				m.Group("/branches", func() {
@@ -1152,6 +1196,10 @@ func Routes() *web.Router {
 					m.Get("/*", repo.GetBranch)
 					m.Delete("/*", reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, repo.DeleteBranch)
 					m.Post("", reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, bind(api.CreateBranchRepoOption{}), repo.CreateBranch)
+					m.Post("/*", reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, branchActions(map[string][]any{
+						"rebase":  {bind(api.RebaseBranchOption{}), repo.RebaseBranch},
+						"history": {reqAdmin(), bind(api.RestoreBranchOption{}), repo.RestoreBranch},
+					}))
 				}, context.ReferencesGitRepo(), reqRepoReader(unit.TypeCode))
 				m.Group("/branch_protections", func() {
 					m.Get("", repo.ListBranchProtections)
@@ -1160,6 +1208,9 @@ func Routes() *web.Router {
 						m.Get("", repo.GetBranchProtection)
 						m.Patch("", bind(api.EditBranchProtectionOption{}), mustNotBeArchived, repo.EditBranchProtection)
 						m.Delete("", repo.DeleteBranchProtection)
+						m.Combo("/status_checks").Get(repo.GetBranchProtectionStatusChecks).
+							Put(bind(api.EditBranchProtectionStatusChecksOption{}), mustNotBeArchived, repo.EditBranchProtectionStatusChecks).
+							Delete(mustNotBeArchived, repo.DeleteBranchProtectionStatusChecks)
 					})
 				}, reqToken(), reqAdmin())
 				m.Group("/tags", func() {
@@ -1179,6 +1230,34 @@ func Routes() *web.Router {
 				}, reqToken(), reqAdmin())
 				m.Group("/actions", func() {
 					m.Get("/tasks", repo.ListActionTasks)
+					m.Get("/runs", repo.ListActionRuns)
+					m.Get("/runs/{id}", repo.GetActionRun)
+					m.Get("/runs/{id}/logs", repo.DownloadActionRunLogs)
+					m.Get("/jobs/{job_id}/logs", repo.GetActionJobLogs)
+					m.Group("", func() {
+						m.Post("/runs/{id}/rerun", repo.RerunActionRun)
+						m.Post("/runs/{id}/rerun-failed-jobs", repo.RerunFailedActionRunJobs)
+						m.Post("/jobs/{job_id}/rerun", repo.RerunActionJob)
+					}, reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived)
+					m.Group("/runs/{id}/artifacts", func() {
+						m.Get("", repo.ListActionRunArtifacts)
+						m.Delete("/{artifact_name}", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.DeleteActionArtifact)
+						m.Get("/{artifact_name}/zip", repo.DownloadActionArtifact)
+						m.Get("/{artifact_name}/files", repo.ListActionArtifactFiles)
+						m.Get("/{artifact_name}/files/*", repo.GetActionArtifactFile)
+						m.Delete("/{artifact_name}/files/*", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.DeleteActionArtifactFile)
+					})
+					m.Combo("/artifact-retention").Get(repo.GetActionArtifactRetention).
+						Put(reqToken(), reqAdmin(), bind(api.EditActionArtifactRetentionOption{}), repo.EditActionArtifactRetention)
+					m.Post("/workflows/{workflow_id}/dispatches", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived,
+						bind(api.CreateActionWorkflowDispatch{}), repo.DispatchActionWorkflow)
+					m.Group("/schedules", func() {
+						m.Get("", repo.ListActionSchedules)
+						m.Combo("/{id}/pause", reqToken(), reqAdmin(), mustNotBeArchived).
+							Put(repo.PauseActionSchedule).
+							Delete(repo.ResumeActionSchedule)
+						m.Post("/{id}/runs", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.RunActionSchedule)
+					})
 				}, reqRepoReader(unit.TypeActions), context.ReferencesGitRepo(true))
 				m.Group("/keys", func() {
 					m.Combo("").Get(repo.ListDeployKeys).
@@ -1198,6 +1277,14 @@ func Routes() *web.Router {
 					m.Get("/revisions/{pageName}", repo.ListPageRevisions)
 					m.Post("/new", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypeWiki), bind(api.CreateWikiPageOptions{}), repo.NewWikiPage)
 					m.Get("/pages", repo.ListWikiPages)
+					m.Post("/move/{pageName}", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypeWiki), bind(api.MoveWikiPageOptions{}), repo.MoveWikiPage)
+					m.Post("/restore/{pageName}", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypeWiki), bind(api.RestoreWikiPageOptions{}), repo.RestoreWikiPage)
+					m.Combo("/sidebar", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypeWiki)).
+						Put(bind(api.EditWikiSectionOptions{}), repo.EditWikiSidebar).
+						Delete(repo.DeleteWikiSidebar)
+					m.Combo("/footer", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypeWiki)).
+						Put(bind(api.EditWikiSectionOptions{}), repo.EditWikiFooter).
+						Delete(repo.DeleteWikiFooter)
 				}, mustEnableWiki)
 				m.Post("/markup", reqToken(), bind(api.MarkupOption{}), misc.Markup)
 				m.Post("/markdown", reqToken(), bind(api.MarkdownOption{}), misc.Markdown)
@@ -1217,9 +1304,15 @@ func Routes() *web.Router {
 						m.Combo("").Get(repo.GetRelease).
 							Patch(reqToken(), reqRepoWriter(unit.TypeReleases), context.ReferencesGitRepo(), bind(api.EditReleaseOption{}), repo.EditRelease).
 							Delete(reqToken(), reqRepoWriter(unit.TypeReleases), repo.DeleteRelease)
+						m.Group("", func() {
+							m.Get("/checksums", repo.GetReleaseChecksums)
+							m.Get("/checksums.asc", repo.GetReleaseChecksumsSignature)
+							m.Get("/provenance", repo.GetReleaseProvenance)
+						}, context.ReferencesGitRepo())
 						m.Group("/assets", func() {
 							m.Combo("").Get(repo.ListReleaseAttachments).
 								Post(reqToken(), reqRepoWriter(unit.TypeReleases), repo.CreateReleaseAttachment)
+							m.Post("/external", reqToken(), reqRepoWriter(unit.TypeReleases), bind(api.CreateExternalAttachmentOption{}), repo.CreateExternalReleaseAttachment)
 							m.Combo("/{attachment_id}").Get(repo.GetReleaseAttachment).
 								Patch(reqToken(), reqRepoWriter(unit.TypeReleases), bind(api.EditAttachmentOptions{}), repo.EditReleaseAttachment).
 								Delete(reqToken(), reqRepoWriter(unit.TypeReleases), repo.DeleteReleaseAttachment)
@@ -1246,6 +1339,8 @@ func Routes() *web.Router {
 					m.Combo("").Get(repo.ListPullRequests).
 						Post(reqToken(), mustNotBeArchived, bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
 					m.Get("/pinned", repo.ListPinnedPullRequests)
+					m.Get("/merge_queue", repo.ListMergeQueue)
+					m.Post("/batch", reqToken(), mustNotBeArchived, reqRepoWriter(unit.TypePullRequests), bind(api.BatchPullRequestOption{}), repo.BatchPullRequests)
 					m.Group("/{index}", func() {
 						m.Combo("").Get(repo.GetPullRequest).
 							Patch(reqToken(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
@@ -1253,9 +1348,18 @@ func Routes() *web.Router {
 						m.Post("/update", reqToken(), repo.UpdatePullRequest)
 						m.Get("/commits", repo.GetPullRequestCommits)
 						m.Get("/files", repo.GetPullRequestFiles)
+						m.Get("/pushes", repo.GetPullRequestPushes)
+						m.Get("/changes", repo.GetPullRequestChanges)
+						m.Get("/status_checks", repo.GetPullRequestStatusChecks)
+						m.Get("/code_owners", repo.GetPullRequestCodeOwners)
+						m.Combo("/viewed_files", reqToken()).Get(repo.GetPullRequestViewedFiles).
+							Put(bind(api.UpdatePullReviewViewedFilesOptions{}), repo.UpdatePullRequestViewedFiles)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
 							Post(reqToken(), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest).
 							Delete(reqToken(), mustNotBeArchived, repo.CancelScheduledAutoMerge)
+						m.Combo("/merge_queue").Get(repo.GetPullRequestMergeQueueEntry).
+							Post(reqToken(), mustNotBeArchived, bind(api.AddToMergeQueueOption{}), repo.AddPullRequestToMergeQueue).
+							Delete(reqToken(), mustNotBeArchived, repo.RemovePullRequestFromMergeQueue)
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
@@ -1266,11 +1370,21 @@ func Routes() *web.Router {
 									Delete(reqToken(), repo.DeletePullReview).
 									Post(reqToken(), bind(api.SubmitPullReviewOptions{}), repo.SubmitPullReview)
 								m.Combo("/comments").
-									Get(repo.GetPullReviewComments)
+									Get(repo.GetPullReviewComments).
+									Post(reqToken(), bind(api.CreatePullReviewComment{}), repo.CreatePullReviewComment)
+								m.Combo("/comments/{comment}").
+									Get(repo.GetPullReviewComment).
+									Patch(reqToken(), bind(api.EditPullReviewCommentOptions{}), repo.EditPullReviewComment).
+									Delete(reqToken(), repo.DeletePullReviewComment)
+								m.Combo("/comments/{comment}/resolution/reactions").
+									Get(repo.GetPullReviewResolutionReactions).
+									Post(reqToken(), mustNotBeArchived, bind(api.EditReactionOption{}), repo.PostPullReviewResolutionReaction).
+									Delete(reqToken(), mustNotBeArchived, bind(api.EditReactionOption{}), repo.DeletePullReviewResolutionReaction)
 								m.Post("/dismissals", reqToken(), bind(api.DismissPullReviewOptions{}), repo.DismissPullReview)
 								m.Post("/undismissals", reqToken(), repo.UnDismissPullReview)
 							})
 						})
+						m.Post("/suggestions", reqToken(), mustNotBeArchived, bind(api.ApplyPullReviewSuggestionsOptions{}), repo.ApplyPullReviewSuggestions)
 						m.Combo("/requested_reviewers", reqToken()).
 							Delete(bind(api.PullReviewRequestOptions{}), repo.DeleteReviewRequests).
 							Post(bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
@@ -1283,6 +1397,7 @@ func Routes() *web.Router {
 				}, reqRepoReader(unit.TypeCode))
 				m.Group("/commits", func() {
 					m.Get("", context.ReferencesGitRepo(), repo.GetAllCommits)
+					m.Get("/graph", context.ReferencesGitRepo(), repo.GetCommitGraph)
 					m.Group("/{ref}", func() {
 						m.Get("/status", repo.GetCombinedCommitStatusByRef)
 						m.Get("/statuses", repo.GetCommitStatusesByRef)
@@ -1292,6 +1407,10 @@ func Routes() *web.Router {
 				m.Group("/git", func() {
 					m.Group("/commits", func() {
 						m.Get("/{sha}", repo.GetSingleCommit)
+						m.Combo("/{sha}/reactions").
+							Get(repo.GetCommitReactions).
+							Post(reqToken(), bind(api.EditReactionOption{}), repo.PostCommitReaction).
+							Delete(reqToken(), bind(api.EditReactionOption{}), repo.DeleteCommitReaction)
 						m.Get("/{sha}.{diffType:diff|patch}", repo.DownloadCommitDiffOrPatch)
 					})
 					m.Get("/refs", repo.GetGitAllRefs)
@@ -1299,7 +1418,9 @@ func Routes() *web.Router {
 					m.Get("/trees/{sha}", repo.GetTree)
 					m.Get("/blobs/{sha}", repo.GetBlob)
 					m.Get("/tags/{sha}", repo.GetAnnotatedTag)
-					m.Get("/notes/{sha}", repo.GetNote)
+					m.Combo("/notes/{sha}").Get(repo.GetNote).
+						Put(reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, bind(api.SetNoteOption{}), repo.SetNote).
+						Delete(reqToken(), reqRepoWriter(unit.TypeCode), mustNotBeArchived, repo.DeleteNote)
 				}, context.ReferencesGitRepo(true), reqRepoReader(unit.TypeCode))
 				m.Post("/diffpatch", reqRepoWriter(unit.TypeCode), reqToken(), bind(api.ApplyDiffPatchFileOptions{}), mustNotBeArchived, repo.ApplyDiffPatch)
 				m.Group("/contents", func() {
@@ -1312,6 +1433,21 @@ func Routes() *web.Router {
 						m.Delete("", bind(api.DeleteFileOptions{}), reqRepoBranchWriter, mustNotBeArchived, repo.DeleteFile)
 					}, reqToken())
 				}, reqRepoReader(unit.TypeCode))
+				m.Put("/contents-raw/*", reqToken(), reqRepoReader(unit.TypeCode), mustNotBeArchived, repo.UploadRawFile)
+				m.Group("/staging", func() {
+					m.Post("", bind(api.CreateStagingSessionOption{}), mustNotBeArchived, repo.CreateStagingSession)
+					m.Group("/{id}", func() {
+						m.Combo("").Get(repo.GetStagingSession).
+							Delete(repo.DeleteStagingSession)
+						m.Put("/files/*", mustNotBeArchived, repo.UploadStagedFile)
+						m.Post("/commit", bind(api.CommitStagingSessionOption{}), mustNotBeArchived, repo.CommitStagingSession)
+					})
+				}, reqToken(), reqRepoReader(unit.TypeCode), context.ReferencesGitRepo())
+				m.Group("/deferred_commits", func() {
+					m.Get("", repo.ListDeferredCommits)
+					m.Combo("/{id}").Get(repo.GetDeferredCommit).
+						Delete(repo.DeleteDeferredCommit)
+				}, reqToken(), reqRepoWriter(unit.TypeCode))
 				m.Get("/signing-key.gpg", misc.SigningKey)
 				m.Group("/topics", func() {
 					m.Combo("").Get(repo.ListTopics).
@@ -1321,11 +1457,36 @@ func Routes() *web.Router {
 							Delete(reqToken(), repo.DeleteTopic)
 					}, reqAdmin())
 				}, reqAnyRepoReader())
+				m.Combo("/properties/values", reqAnyRepoReader()).Get(repo.GetCustomPropertyValues).
+					Patch(reqToken(), reqAdmin(), bind(api.UpdateCustomPropertyValuesOption{}), repo.UpdateCustomPropertyValues)
+				m.Group("/managed_git_hook", func() {
+					m.Get("", repo.GetManagedGitHook)
+					m.Combo("/opt_out").Put(repo.OptOutManagedGitHook).
+						Delete(repo.OptInManagedGitHook)
+				}, reqToken(), reqAdmin())
+				m.Get("/size", reqToken(), reqAdmin(), repo.GetRepoSize)
+				m.Group("/stats", func() {
+					m.Get("/code_frequency", repo.GetCodeFrequency)
+					m.Get("/punch_card", repo.GetPunchCard)
+					m.Get("/contributors", repo.GetContributorsStats)
+				}, reqRepoReader(unit.TypeCode))
+				m.Group("/bisect", func() {
+					m.Combo("").Get(repo.GetBisect).
+						Post(bind(api.StartBisectOption{}), repo.StartBisect).
+						Delete(repo.ResetBisect)
+					m.Post("/verdict", bind(api.BisectVerdictOption{}), repo.BisectVerdict)
+				}, reqToken(), reqRepoReader(unit.TypeCode), context.ReferencesGitRepo())
 				m.Get("/issue_templates", context.ReferencesGitRepo(), repo.GetIssueTemplates)
 				m.Get("/issue_config", context.ReferencesGitRepo(), repo.GetIssueConfig)
 				m.Get("/issue_config/validate", context.ReferencesGitRepo(), repo.ValidateIssueConfig)
 				m.Get("/languages", reqRepoReader(unit.TypeCode), repo.GetLanguages)
+				m.Get("/dependencies", reqRepoReader(unit.TypeCode), repo.ListDependencies)
+				m.Get("/dependents", reqRepoReader(unit.TypeCode), repo.ListDependents)
 				m.Get("/activities/feeds", repo.ListRepoActivityFeeds)
+				m.Group("/timeline", func() {
+					m.Get("", repo.GetTimeline)
+					m.Get("/stream", repo.StreamTimeline)
+				})
 				m.Get("/new_pin_allowed", repo.AreNewIssuePinsAllowed)
 				m.Group("/avatar", func() {
 					m.Post("", bind(api.UpdateRepoAvatarOption{}), repo.UpdateAvatar)
@@ -1352,6 +1513,9 @@ func Routes() *web.Router {
 					m.Combo("").Get(repo.ListIssues).
 						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), reqRepoReader(unit.TypeIssues), repo.CreateIssue)
 					m.Get("/pinned", reqRepoReader(unit.TypeIssues), repo.ListPinnedIssues)
+					m.Get("/similar", reqRepoReader(unit.TypeIssues), repo.ListSimilarIssues)
+					m.Get("/export", reqRepoReader(unit.TypeIssues), repo.ExportIssues)
+					m.Post("/import", reqToken(), reqAdmin(), mustNotBeArchived, mustEnableIssues, repo.ImportIssues)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/{id}", func() {
@@ -1392,6 +1556,8 @@ func Routes() *web.Router {
 								Delete(reqToken(), repo.ClearIssueLabels)
 							m.Delete("/{id}", reqToken(), repo.DeleteIssueLabel)
 						})
+						m.Combo("/fields").Get(repo.GetIssueFieldValues).
+							Patch(reqToken(), mustNotBeArchived, bind(api.EditIssueFieldsOption{}), repo.EditIssueFieldValues)
 						m.Group("/times", func() {
 							m.Combo("").
 								Get(repo.ListTrackedTimes).
@@ -1415,6 +1581,10 @@ func Routes() *web.Router {
 							Get(repo.GetIssueReactions).
 							Post(reqToken(), bind(api.EditReactionOption{}), repo.PostIssueReaction).
 							Delete(reqToken(), bind(api.EditReactionOption{}), repo.DeleteIssueReaction)
+						m.Combo("/votes").
+							Get(repo.GetIssueVoters).
+							Post(reqToken(), mustNotBeArchived, repo.AddIssueVote).
+							Delete(reqToken(), mustNotBeArchived, repo.DeleteIssueVote)
 						m.Group("/assets", func() {
 							m.Combo("").
 								Get(repo.ListIssueAttachments).
@@ -1432,6 +1602,11 @@ func Routes() *web.Router {
 							Get(repo.GetIssueBlocks).
 							Post(reqToken(), bind(api.IssueMeta{}), repo.CreateIssueBlocking).
 							Delete(reqToken(), bind(api.IssueMeta{}), repo.RemoveIssueBlocking)
+						m.Combo("/sub_issues").
+							Get(repo.ListSubIssues).
+							Post(reqToken(), mustNotBeArchived, bind(api.IssueMeta{}), repo.AddSubIssue).
+							Delete(reqToken(), mustNotBeArchived, bind(api.IssueMeta{}), repo.RemoveSubIssue)
+						m.Get("/parent", repo.GetParentIssue)
 						m.Group("/pin", func() {
 							m.Combo("").
 								Post(reqToken(), reqAdmin(), repo.PinIssue).
@@ -1447,6 +1622,38 @@ func Routes() *web.Router {
 						Patch(reqToken(), reqRepoWriter(unit.TypeIssues, unit.TypePullRequests), bind(api.EditLabelOption{}), repo.EditLabel).
 						Delete(reqToken(), reqRepoWriter(unit.TypeIssues, unit.TypePullRequests), repo.DeleteLabel)
 				})
+				m.Group("/issue_types", func() {
+					m.Combo("").Get(repo.ListIssueTypes).
+						Post(reqToken(), reqRepoWriter(unit.TypeIssues), bind(api.CreateIssueTypeOption{}), repo.CreateIssueType)
+					m.Combo("/{id}").Get(repo.GetIssueType).
+						Patch(reqToken(), reqRepoWriter(unit.TypeIssues), bind(api.EditIssueTypeOption{}), repo.EditIssueType).
+						Delete(reqToken(), reqRepoWriter(unit.TypeIssues), repo.DeleteIssueType)
+				}, mustEnableIssues)
+				m.Group("/issue_automations", func() {
+					m.Combo("").Get(repo.ListIssueAutomationRules).
+						Post(bind(api.CreateIssueAutomationRuleOption{}), repo.CreateIssueAutomationRule)
+					m.Get("/logs", repo.ListIssueAutomationLogs)
+					m.Group("/{id}", func() {
+						m.Combo("").Get(repo.GetIssueAutomationRule).
+							Patch(bind(api.EditIssueAutomationRuleOption{}), repo.EditIssueAutomationRule).
+							Delete(repo.DeleteIssueAutomationRule)
+						m.Get("/preview", repo.PreviewIssueAutomationRule)
+					})
+				}, reqToken(), reqAdmin())
+				m.Group("/issue_filters", func() {
+					m.Combo("").Get(repo.ListIssueFilters).
+						Post(reqToken(), reqRepoReader(unit.TypeIssues), bind(api.CreateIssueFilterOption{}), repo.CreateIssueFilter)
+					m.Combo("/{slug}").Get(repo.GetIssueFilter).
+						Patch(reqToken(), reqRepoReader(unit.TypeIssues), bind(api.EditIssueFilterOption{}), repo.EditIssueFilter).
+						Delete(reqToken(), reqRepoReader(unit.TypeIssues), repo.DeleteIssueFilter)
+				}, mustEnableIssues)
+				m.Group("/issue_fields", func() {
+					m.Combo("").Get(repo.ListIssueFields).
+						Post(reqToken(), reqAdmin(), bind(api.CreateIssueFieldOption{}), repo.CreateIssueField)
+					m.Combo("/{id}").Get(repo.GetIssueField).
+						Patch(reqToken(), reqAdmin(), bind(api.EditIssueFieldOption{}), repo.EditIssueField).
+						Delete(reqToken(), reqAdmin(), repo.DeleteIssueField)
+				}, mustEnableIssuesOrPulls)
 				m.Group("/milestones", func() {
 					m.Combo("").Get(repo.ListMilestones).
 						Post(reqToken(), reqRepoWriter(unit.TypeIssues, unit.TypePullRequests), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
@@ -1463,7 +1670,36 @@ func Routes() *web.Router {
 				m.Get("", reqToken(), packages.GetPackage)
 				m.Delete("", reqToken(), reqPackageAccess(perm.AccessModeWrite), packages.DeletePackage)
 				m.Get("/files", reqToken(), packages.ListPackageFiles)
+				m.Post("/promote", reqToken(), bind(api.PromotePackageOption{}), packages.PromotePackage)
+				m.Group("/attestations", func() {
+					m.Combo("").Get(packages.ListPackageAttestations).
+						Post(reqPackageAccess(perm.AccessModeWrite), bind(api.CreatePackageAttestationOption{}), packages.CreatePackageAttestation)
+					m.Delete("/{id}", reqPackageAccess(perm.AccessModeWrite), packages.DeletePackageAttestation)
+				}, reqToken())
 			})
+			m.Delete("/{type}/{name}", reqToken(), reqPackageAccess(perm.AccessModeWrite), packages.RemovePackage)
+			m.Group("/{type}/{name}/-", func() {
+				m.Put("/immutable", reqPackageAccess(perm.AccessModeOwner), packages.SetPackageImmutable)
+				m.Delete("/immutable", reqSiteAdmin(), packages.UnsetPackageImmutable)
+				m.Post("/rebuild_metadata", reqPackageAccess(perm.AccessModeWrite), packages.RebuildPackageMetadata)
+			}, reqToken())
+			m.Group("/cleanup_rules", func() {
+				m.Combo("").Get(packages.ListCleanupRules).
+					Post(bind(api.CreatePackageCleanupRuleOption{}), packages.CreateCleanupRule)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(packages.GetCleanupRule).
+						Patch(bind(api.EditPackageCleanupRuleOption{}), packages.EditCleanupRule).
+						Delete(packages.DeleteCleanupRule)
+					m.Get("/preview", packages.PreviewCleanupRule)
+				})
+			}, reqToken(), reqPackageAccess(perm.AccessModeOwner))
+			m.Group("/upstreams", func() {
+				m.Get("", packages.ListUpstreams)
+				m.Combo("/{type}").Get(packages.GetUpstream).
+					Put(bind(api.SetPackageUpstreamOption{}), packages.SetUpstream).
+					Delete(packages.DeleteUpstream)
+			}, reqToken(), reqPackageAccess(perm.AccessModeOwner))
+			m.Get("/audit", reqToken(), reqPackageAccess(perm.AccessModeOwner), packages.ListPackageAudit)
 			m.Get("/", reqToken(), packages.ListPackages)
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryPackage), context.UserAssignmentAPI(), context.PackageAssignmentAPI(), reqPackageAccess(perm.AccessModeRead))
 
@@ -1481,6 +1717,7 @@ func Routes() *web.Router {
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+			m.Get("/packages/usage", reqToken(), reqOrgMembership(), org.GetPackageUsage)
 			m.Group("/members", func() {
 				m.Get("", reqToken(), org.ListMembers)
 				m.Combo("/{username}").Get(reqToken(), org.IsMember).
@@ -1491,6 +1728,34 @@ func Routes() *web.Router {
 				reqOrgOwnership(),
 				org.NewAction(),
 			)
+			m.Group("/actions", func() {
+				m.Combo("/artifact-retention").Get(org.GetActionArtifactRetention).
+					Put(bind(api.EditActionArtifactRetentionOption{}), org.EditActionArtifactRetention)
+				m.Combo("/policy").Get(org.GetActionsPolicy).
+					Put(bind(api.EditActionsPolicyOption{}), org.EditActionsPolicy)
+				m.Post("/runners/ephemeral-registration-token", bind(api.CreateActionEphemeralRunnerTokenOption{}), org.CreateEphemeralRegistrationToken)
+				m.Get("/runners/queue", org.ListQueuedJobs)
+				m.Post("/runners/{runner_id}/expire", org.ExpireRunner)
+				m.Group("/runners/{runner_id}/labels", func() {
+					m.Combo("").Get(org.ListRunnerLabels).
+						Put(bind(api.ActionRunnerLabelsOption{}), org.SetRunnerLabels).
+						Post(bind(api.ActionRunnerLabelsOption{}), org.AddRunnerLabels)
+					m.Delete("/{name}", org.RemoveRunnerLabel)
+				})
+				m.Group("/runner-groups", func() {
+					m.Combo("").Get(org.ListRunnerGroups).
+						Post(bind(api.CreateActionRunnerGroupOption{}), org.CreateRunnerGroup)
+					m.Group("/{group_id}", func() {
+						m.Combo("").Get(org.GetRunnerGroup).
+							Patch(bind(api.EditActionRunnerGroupOption{}), org.EditRunnerGroup).
+							Delete(org.DeleteRunnerGroup)
+						m.Get("/runners", org.ListRunnerGroupRunners)
+						m.Combo("/runners/{runner_id}").Put(org.AddRunnerGroupRunner).Delete(org.RemoveRunnerGroupRunner)
+						m.Get("/repositories", org.ListRunnerGroupRepos)
+						m.Combo("/repositories/{repo_id}").Put(org.AddRunnerGroupRepo).Delete(org.RemoveRunnerGroupRepo)
+					})
+				})
+			}, reqToken(), reqOrgOwnership())
 			m.Group("/public_members", func() {
 				m.Get("", org.ListPublicMembers)
 				m.Combo("/{username}").Get(org.IsPublicMember).
@@ -1509,6 +1774,35 @@ func Routes() *web.Router {
 					Patch(reqToken(), reqOrgOwnership(), bind(api.EditLabelOption{}), org.EditLabel).
 					Delete(reqToken(), reqOrgOwnership(), org.DeleteLabel)
 			})
+			m.Group("/milestones", func() {
+				m.Get("", org.ListMilestones)
+				m.Post("", reqToken(), reqOrgOwnership(), bind(api.CreateMilestoneOption{}), org.CreateMilestone)
+				m.Combo("/{id}").Get(org.GetMilestone).
+					Patch(reqToken(), reqOrgOwnership(), bind(api.EditMilestoneOption{}), org.EditMilestone).
+					Delete(reqToken(), reqOrgOwnership(), org.DeleteMilestone)
+				m.Get("/{id}/burndown", org.GetMilestoneBurndown)
+			})
+			m.Group("/issue_types", func() {
+				m.Get("", org.ListIssueTypes)
+				m.Post("", reqToken(), reqOrgOwnership(), bind(api.CreateIssueTypeOption{}), org.CreateIssueType)
+				m.Combo("/{id}").Get(org.GetIssueType).
+					Patch(reqToken(), reqOrgOwnership(), bind(api.EditIssueTypeOption{}), org.EditIssueType).
+					Delete(reqToken(), reqOrgOwnership(), org.DeleteIssueType)
+			})
+			m.Group("/issue_filters", func() {
+				m.Get("", org.ListIssueFilters)
+				m.Post("", reqToken(), reqOrgMembership(), bind(api.CreateIssueFilterOption{}), org.CreateIssueFilter)
+				m.Combo("/{slug}").Get(org.GetIssueFilter).
+					Patch(reqToken(), reqOrgMembership(), bind(api.EditIssueFilterOption{}), org.EditIssueFilter).
+					Delete(reqToken(), reqOrgMembership(), org.DeleteIssueFilter)
+			})
+			m.Group("/issue_fields", func() {
+				m.Get("", org.ListIssueFields)
+				m.Post("", reqToken(), reqOrgOwnership(), bind(api.CreateIssueFieldOption{}), org.CreateIssueField)
+				m.Combo("/{id}").Get(org.GetIssueField).
+					Patch(reqToken(), reqOrgOwnership(), bind(api.EditIssueFieldOption{}), org.EditIssueField).
+					Delete(reqToken(), reqOrgOwnership(), org.DeleteIssueField)
+			})
 			m.Group("/hooks", func() {
 				m.Combo("").Get(org.ListHooks).
 					Post(bind(api.CreateHookOption{}), org.CreateHook)
@@ -1516,6 +1810,15 @@ func Routes() *web.Router {
 					Patch(bind(api.EditHookOption{}), org.EditHook).
 					Delete(org.DeleteHook)
 			}, reqToken(), reqOrgOwnership(), reqWebhooksEnabled())
+			m.Group("/properties/schema", func() {
+				m.Get("", org.ListCustomProperties)
+				m.Combo("/{name}").Get(org.GetCustomProperty).
+					Put(reqOrgOwnership(), bind(api.CreateOrUpdateCustomPropertyOption{}), org.CreateOrUpdateCustomProperty).
+					Delete(reqOrgOwnership(), org.DeleteCustomProperty)
+			}, reqToken(), reqOrgMembership())
+			m.Combo("/managed_git_hook", reqToken(), reqOrgOwnership()).Get(org.GetManagedGitHook).
+				Put(reqSiteAdmin(), bind(api.EditManagedGitHookOption{}), org.EditManagedGitHook).
+				Delete(reqSiteAdmin(), org.DeleteManagedGitHook)
 			m.Group("/avatar", func() {
 				m.Post("", bind(api.UpdateUserAvatarOption{}), org.UpdateAvatar)
 				m.Delete("", org.DeleteAvatar)
@@ -1575,8 +1878,12 @@ func Routes() *web.Router {
 					m.Get("/badges", admin.ListUserBadges)
 					m.Post("/badges", bind(api.UserBadgeOption{}), admin.AddUserBadges)
 					m.Delete("/badges", bind(api.UserBadgeOption{}), admin.DeleteUserBadges)
+					m.Get("/packages/usage", admin.GetUserPackageUsage)
+					m.Combo("/packages/quota").Put(bind(api.SetPackageQuotaOption{}), admin.SetUserPackageQuota).
+						Delete(admin.ResetUserPackageQuota)
 				}, context.UserAssignmentAPI())
 			})
+			m.Get("/packages/largest", admin.ListLargestPackages)
 			m.Group("/emails", func() {
 				m.Get("", admin.GetAllEmails)
 				m.Get("/search", admin.SearchEmail)
@@ -1595,6 +1902,48 @@ func Routes() *web.Router {
 			})
 			m.Group("/runners", func() {
 				m.Get("/registration-token", admin.GetRegistrationToken)
+				m.Post("/ephemeral-registration-token", bind(api.CreateActionEphemeralRunnerTokenOption{}), admin.CreateEphemeralRegistrationToken)
+				m.Get("/queue", admin.ListQueuedJobs)
+				m.Post("/{runner_id}/expire", admin.ExpireRunner)
+				m.Group("/{runner_id}/labels", func() {
+					m.Combo("").Get(admin.ListRunnerLabels).
+						Put(bind(api.ActionRunnerLabelsOption{}), admin.SetRunnerLabels).
+						Post(bind(api.ActionRunnerLabelsOption{}), admin.AddRunnerLabels)
+					m.Delete("/{name}", admin.RemoveRunnerLabel)
+				})
+			})
+			m.Group("/actions", func() {
+				m.Combo("/policy").Get(admin.GetActionsPolicy).
+					Put(bind(api.EditActionsPolicyOption{}), admin.EditActionsPolicy)
+				m.Get("/secrets", admin.ListActionsSecrets)
+				m.Combo("/secrets/{secretname}").
+					Put(bind(api.CreateOrUpdateSecretOption{}), admin.CreateOrUpdateActionsSecret).
+					Delete(admin.DeleteActionsSecret)
+				m.Get("/variables", admin.ListActionsVariables)
+				m.Combo("/variables/{variablename}").
+					Put(bind(api.CreateVariableOption{}), admin.CreateOrUpdateActionsVariable).
+					Delete(admin.DeleteActionsVariable)
+			})
+			m.Group("/runner-groups", func() {
+				m.Combo("").Get(admin.ListRunnerGroups).
+					Post(bind(api.CreateActionRunnerGroupOption{}), admin.CreateRunnerGroup)
+				m.Group("/{group_id}", func() {
+					m.Combo("").Get(admin.GetRunnerGroup).
+						Patch(bind(api.EditActionRunnerGroupOption{}), admin.EditRunnerGroup).
+						Delete(admin.DeleteRunnerGroup)
+					m.Get("/runners", admin.ListRunnerGroupRunners)
+					m.Combo("/runners/{runner_id}").Put(admin.AddRunnerGroupRunner).Delete(admin.RemoveRunnerGroupRunner)
+					m.Get("/repositories", admin.ListRunnerGroupRepos)
+					m.Combo("/repositories/{repo_id}").Put(admin.AddRunnerGroupRepo).Delete(admin.RemoveRunnerGroupRepo)
+					m.Get("/organizations", admin.ListRunnerGroupOrgs)
+					m.Combo("/organizations/{org_id}").Put(admin.AddRunnerGroupOrg).Delete(admin.RemoveRunnerGroupOrg)
+				})
+			})
+			m.Group("/templates/{type}", func() {
+				m.Get("", admin.ListOptionTemplates)
+				m.Combo("/{name}").Get(admin.GetOptionTemplate).
+					Put(bind(api.EditOptionTemplateOption{}), admin.SetOptionTemplate).
+					Delete(admin.DeleteOptionTemplate)
 			})
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryAdmin), reqToken(), reqSiteAdmin())
 
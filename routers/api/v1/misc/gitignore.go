@@ -6,7 +6,7 @@ package misc
 import (
 	"net/http"
 
-	"code.gitea.io/gitea/modules/options"
+	repo_model "code.gitea.io/gitea/models/repo"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -23,7 +23,12 @@ func ListGitignoresTemplates(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/GitignoreTemplateList"
-	ctx.JSON(http.StatusOK, repo_module.Gitignores)
+	gitignores, err := repo_module.ListGitignores(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListGitignores", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gitignores)
 }
 
 // SHows information about a gitignore template
@@ -46,7 +51,7 @@ func GetGitignoreTemplateInfo(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	name := util.PathJoinRelX(ctx.PathParam("name"))
 
-	text, err := options.Gitignore(name)
+	text, err := repo_module.ReadOptionTemplate(ctx, repo_model.OptionTemplateGitignore, name)
 	if err != nil {
 		ctx.NotFound()
 		return
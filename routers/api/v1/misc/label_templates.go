@@ -22,9 +22,14 @@ func ListLabelTemplates(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/LabelTemplateList"
-	result := make([]string, len(repo_module.LabelTemplateFiles))
-	for i := range repo_module.LabelTemplateFiles {
-		result[i] = repo_module.LabelTemplateFiles[i].DisplayName
+	files, err := repo_module.ListLabelTemplates(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListLabelTemplates", err)
+		return
+	}
+	result := make([]string, len(files))
+	for i := range files {
+		result[i] = files[i].DisplayName
 	}
 
 	ctx.JSON(http.StatusOK, result)
@@ -50,7 +55,7 @@ func GetLabelTemplate(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	name := util.PathJoinRelX(ctx.PathParam("name"))
 
-	labels, err := repo_module.LoadTemplateLabelsByDisplayName(name)
+	labels, err := repo_module.LoadTemplateLabelsByDisplayName(ctx, name)
 	if err != nil {
 		ctx.NotFound()
 		return
@@ -8,7 +8,7 @@ import (
 	"net/http"
 	"net/url"
 
-	"code.gitea.io/gitea/modules/options"
+	repo_model "code.gitea.io/gitea/models/repo"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -26,8 +26,13 @@ func ListLicenseTemplates(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/LicenseTemplateList"
-	response := make([]api.LicensesTemplateListEntry, len(repo_module.Licenses))
-	for i, license := range repo_module.Licenses {
+	licenses, err := repo_module.ListLicenses(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListLicenses", err)
+		return
+	}
+	response := make([]api.LicensesTemplateListEntry, len(licenses))
+	for i, license := range licenses {
 		response[i] = api.LicensesTemplateListEntry{
 			Key:  license,
 			Name: license,
@@ -57,7 +62,7 @@ func GetLicenseTemplateInfo(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	name := util.PathJoinRelX(ctx.PathParam("name"))
 
-	text, err := options.License(name)
+	text, err := repo_module.ReadOptionTemplate(ctx, repo_model.OptionTemplateLicense, name)
 	if err != nil {
 		ctx.NotFound()
 		return
@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionArtifactRetention returns the number of days the artifacts of the repositories of an organization are kept
+func GetActionArtifactRetention(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/artifact-retention organization orgGetActionArtifactRetention
+	// ---
+	// summary: Get the number of days the artifacts of the repositories of an organization are kept
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionArtifactRetention"
+
+	writeActionArtifactRetention(ctx)
+}
+
+// EditActionArtifactRetention sets the number of days the artifacts of the repositories of an organization are kept
+func EditActionArtifactRetention(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/artifact-retention organization orgEditActionArtifactRetention
+	// ---
+	// summary: Set the number of days the artifacts of the repositories of an organization are kept, the artifacts already kept longer expire earlier
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionArtifactRetentionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionArtifactRetention"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditActionArtifactRetentionOption)
+	if err := actions_service.SetOwnerArtifactRetentionDays(ctx, ctx.Org.Organization.ID, form.Days); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetOwnerArtifactRetentionDays", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetOwnerArtifactRetentionDays", err)
+		}
+		return
+	}
+	writeActionArtifactRetention(ctx)
+}
+
+func writeActionArtifactRetention(ctx *context.APIContext) {
+	days, err := actions_service.GetOwnerArtifactRetentionDays(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOwnerArtifactRetentionDays", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionArtifactRetention{Days: days, MaximumAllowedDays: setting.Actions.ArtifactRetentionDays})
+}
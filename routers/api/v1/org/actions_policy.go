@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionsPolicy returns the actions the workflows of the repositories of an organization can use
+func GetActionsPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/policy organization orgGetActionsPolicy
+	// ---
+	// summary: Get the actions the workflows of the repositories of an organization can use
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.GetActionsPolicy(ctx, ctx.Org.Organization.ID)
+}
+
+// EditActionsPolicy sets the actions the workflows of the repositories of an organization can use
+func EditActionsPolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/policy organization orgEditActionsPolicy
+	// ---
+	// summary: Set the actions the workflows of the repositories of an organization can use, in addition to the policy of the instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionsPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionsPolicy"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.EditActionsPolicy(ctx, ctx.Org.Organization.ID)
+}
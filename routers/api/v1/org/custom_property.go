@@ -0,0 +1,182 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListCustomProperties list the custom properties of an organization
+func ListCustomProperties(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/properties/schema organization orgListCustomProperties
+	// ---
+	// summary: List the custom properties defined by an organization for its repositories
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CustomPropertyList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	props, err := repo_model.GetCustomProperties(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCustomProperties", err)
+		return
+	}
+
+	apiProps := make([]*api.CustomProperty, 0, len(props))
+	for _, prop := range props {
+		apiProps = append(apiProps, convert.ToCustomProperty(prop))
+	}
+	ctx.SetTotalCountHeader(int64(len(apiProps)))
+	ctx.JSON(http.StatusOK, apiProps)
+}
+
+// GetCustomProperty get a custom property of an organization
+func GetCustomProperty(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/properties/schema/{name} organization orgGetCustomProperty
+	// ---
+	// summary: Get a custom property defined by an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the custom property
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CustomProperty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	prop, err := repo_model.GetCustomPropertyByName(ctx, ctx.Org.Organization.ID, ctx.PathParam("name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCustomPropertyByName", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToCustomProperty(prop))
+}
+
+// CreateOrUpdateCustomProperty create or update a custom property of an organization
+func CreateOrUpdateCustomProperty(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/properties/schema/{name} organization orgCreateOrUpdateCustomProperty
+	// ---
+	// summary: Create or update a custom property for the repositories of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the custom property
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateCustomPropertyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CustomProperty"
+	//   "201":
+	//     "$ref": "#/responses/CustomProperty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateOrUpdateCustomPropertyOption)
+
+	prop := &repo_model.CustomProperty{
+		OwnerID:       ctx.Org.Organization.ID,
+		Name:          ctx.PathParam("name"),
+		Type:          repo_model.CustomPropertyType(form.Type),
+		Description:   form.Description,
+		AllowedValues: form.AllowedValues,
+		DefaultValue:  form.DefaultValue,
+		Required:      form.Required,
+	}
+	created, err := repo_model.SaveCustomProperty(ctx, prop)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SaveCustomProperty", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SaveCustomProperty", err)
+		}
+		return
+	}
+
+	if created {
+		ctx.JSON(http.StatusCreated, convert.ToCustomProperty(prop))
+	} else {
+		ctx.JSON(http.StatusOK, convert.ToCustomProperty(prop))
+	}
+}
+
+// DeleteCustomProperty delete a custom property of an organization
+func DeleteCustomProperty(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/properties/schema/{name} organization orgDeleteCustomProperty
+	// ---
+	// summary: Delete a custom property and its values on the repositories of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the custom property
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := repo_model.DeleteCustomProperty(ctx, ctx.Org.Organization.ID, ctx.PathParam("name")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteCustomProperty", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListIssueFilters list the saved filters of an organization
+func ListIssueFilters(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_filters organization orgListIssueFilters
+	// ---
+	// summary: List an organization's saved issue filters
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilterList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	filters, err := issues_model.GetIssueFiltersByOrgID(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueFiltersByOrgID", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(int64(len(filters)))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFilterList(filters))
+}
+
+// GetIssueFilter get a saved filter of an organization
+func GetIssueFilter(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_filters/{slug} organization orgGetIssueFilter
+	// ---
+	// summary: Get a single saved issue filter
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: slug
+	//   in: path
+	//   description: slug of the filter to get
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f := utils.GetIssueFilter(ctx, 0, ctx.Org.Organization.ID, 0)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFilter(f))
+}
+
+// CreateIssueFilter save an issue filter for an organization
+func CreateIssueFilter(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/issue_filters organization orgCreateIssueFilter
+	// ---
+	// summary: Save an issue filter for the issue lists of the repositories of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueFilterOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.AddIssueFilter(ctx, web.GetForm(ctx).(*api.CreateIssueFilterOption), 0, ctx.Org.Organization.ID, 0)
+}
+
+// EditIssueFilter modify a saved filter of an organization
+func EditIssueFilter(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/issue_filters/{slug} organization orgEditIssueFilter
+	// ---
+	// summary: Update a saved issue filter
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: slug
+	//   in: path
+	//   description: slug of the filter to edit
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueFilterOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	f := utils.GetIssueFilter(ctx, 0, ctx.Org.Organization.ID, 0)
+	if ctx.Written() {
+		return
+	}
+
+	utils.EditIssueFilter(ctx, web.GetForm(ctx).(*api.EditIssueFilterOption), f)
+}
+
+// DeleteIssueFilter delete a saved filter of an organization
+func DeleteIssueFilter(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/issue_filters/{slug} organization orgDeleteIssueFilter
+	// ---
+	// summary: Delete a saved issue filter
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: slug
+	//   in: path
+	//   description: slug of the filter to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f := utils.GetIssueFilter(ctx, 0, ctx.Org.Organization.ID, 0)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.DeleteIssueFilter(ctx, f); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueFilter", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,249 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListIssueTypes list the issue types of an organization
+func ListIssueTypes(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_types organization orgListIssueTypes
+	// ---
+	// summary: List an organization's issue types
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueTypeList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	types, err := issues_model.GetIssueTypesByOrgID(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueTypesByOrgID", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(int64(len(types)))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueTypeList(types))
+}
+
+// GetIssueType get an issue type of an organization
+func GetIssueType(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/issue_types/{id} organization orgGetIssueType
+	// ---
+	// summary: Get a single issue type
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the issue type to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueType"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getOrgIssueType(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueType(t))
+}
+
+// CreateIssueType create an issue type for an organization
+func CreateIssueType(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/issue_types organization orgCreateIssueType
+	// ---
+	// summary: Create an issue type for an organization, the issues of all its repositories can have it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueTypeOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueType"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueTypeOption)
+	t := &issues_model.IssueType{
+		OrgID:          ctx.Org.Organization.ID,
+		Name:           form.Name,
+		Description:    form.Description,
+		Icon:           form.Icon,
+		Color:          form.Color,
+		Template:       form.Template,
+		RequiredFields: form.RequiredFields,
+	}
+	if err := issues_model.NewIssueType(ctx, t); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "NewIssueType", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIssueType", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueType(t))
+}
+
+// EditIssueType modify an issue type of an organization
+func EditIssueType(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/issue_types/{id} organization orgEditIssueType
+	// ---
+	// summary: Update an issue type of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the issue type to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueTypeOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueType"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	t := getOrgIssueType(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditIssueTypeOption)
+	if form.Name != nil {
+		t.Name = *form.Name
+	}
+	if form.Description != nil {
+		t.Description = *form.Description
+	}
+	if form.Icon != nil {
+		t.Icon = *form.Icon
+	}
+	if form.Color != nil {
+		t.Color = *form.Color
+	}
+	if form.Template != nil {
+		t.Template = *form.Template
+	}
+	if form.RequiredFields != nil {
+		t.RequiredFields = form.RequiredFields
+	}
+	if err := issues_model.UpdateIssueType(ctx, t); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateIssueType", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueType", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueType(t))
+}
+
+// DeleteIssueType delete an issue type of an organization
+func DeleteIssueType(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/issue_types/{id} organization orgDeleteIssueType
+	// ---
+	// summary: Delete an issue type of an organization, its issues don't have a type anymore
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the issue type to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getOrgIssueType(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.DeleteIssueType(ctx, t); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueType", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getOrgIssueType(ctx *context.APIContext) *issues_model.IssueType {
+	t, err := issues_model.GetIssueTypeByID(ctx, ctx.PathParamInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueTypeByID", err)
+		}
+		return nil
+	}
+	if t.OrgID != ctx.Org.Organization.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return t
+}
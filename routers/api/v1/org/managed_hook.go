@@ -0,0 +1,132 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetManagedGitHook get the managed git hook of an organization
+func GetManagedGitHook(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/managed_git_hook organization orgGetManagedGitHook
+	// ---
+	// summary: Get the git hooks and push checks managed by the instance administrators for the repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ManagedGitHook"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, err := git_model.GetManagedGitHook(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetManagedGitHook", err)
+		return
+	} else if hook == nil {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToManagedGitHook(hook))
+}
+
+// EditManagedGitHook set the managed git hook of an organization
+func EditManagedGitHook(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/managed_git_hook organization orgEditManagedGitHook
+	// ---
+	// summary: Set the git hooks and push checks for all the repositories of an organization, instance administrators only
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditManagedGitHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ManagedGitHook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditManagedGitHookOption)
+	if setting.DisableGitHooks && (strings.TrimSpace(form.PreReceiveScript) != "" || strings.TrimSpace(form.UpdateScript) != "") {
+		ctx.Error(http.StatusUnprocessableEntity, "", "git hook scripts are disabled on this instance")
+		return
+	}
+
+	hook := &git_model.ManagedGitHook{
+		OrgID:                ctx.Org.Organization.ID,
+		MaxFileSize:          form.MaxFileSize,
+		ForbiddenPaths:       form.ForbiddenPaths,
+		CommitMessagePattern: form.CommitMessagePattern,
+		PreReceiveScript:     form.PreReceiveScript,
+		UpdateScript:         form.UpdateScript,
+		AllowRepoOptOut:      form.AllowRepoOptOut,
+	}
+	if err := git_model.SaveManagedGitHook(ctx, hook); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SaveManagedGitHook", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SaveManagedGitHook", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToManagedGitHook(hook))
+}
+
+// DeleteManagedGitHook delete the managed git hook of an organization
+func DeleteManagedGitHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/managed_git_hook organization orgDeleteManagedGitHook
+	// ---
+	// summary: Delete the git hooks and push checks of the repositories of an organization, instance administrators only
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := git_model.DeleteManagedGitHook(ctx, ctx.Org.Organization.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteManagedGitHook", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
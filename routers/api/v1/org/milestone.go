@@ -0,0 +1,325 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/optional"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+
+	"xorm.io/builder"
+)
+
+// ListMilestones list the milestones of an organization
+func ListMilestones(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/milestones organization orgListMilestones
+	// ---
+	// summary: List an organization's milestones
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: Milestone state, Recognized values are open, closed and all. Defaults to "open"
+	//   type: string
+	// - name: name
+	//   in: query
+	//   description: filter by milestone name
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MilestoneList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	state := api.StateType(ctx.FormString("state"))
+	var isClosed optional.Option[bool]
+	switch state {
+	case api.StateClosed, api.StateOpen:
+		isClosed = optional.Some(state == api.StateClosed)
+	}
+
+	milestones, total, err := db.FindAndCount[issues_model.Milestone](ctx, issues_model.FindMilestoneOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OrgID:       ctx.Org.Organization.ID,
+		IsClosed:    isClosed,
+		Name:        ctx.FormString("name"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "db.FindAndCount[issues_model.Milestone]", err)
+		return
+	}
+
+	apiMilestones := make([]*api.Milestone, len(milestones))
+	for i := range milestones {
+		apiMilestones[i] = convert.ToAPIMilestone(milestones[i])
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, &apiMilestones)
+}
+
+// GetMilestone get a milestone of an organization
+func GetMilestone(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/milestones/{id} organization orgGetMilestone
+	// ---
+	// summary: Get a milestone of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the milestone to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Milestone"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	milestone := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIMilestone(milestone))
+}
+
+// CreateMilestone create a milestone for an organization
+func CreateMilestone(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/milestones organization orgCreateMilestone
+	// ---
+	// summary: Create a milestone for an organization, the issues of all its repositories can have it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateMilestoneOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Milestone"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.CreateMilestoneOption)
+
+	if form.Deadline == nil {
+		defaultDeadline, _ := time.ParseInLocation("2006-01-02", "9999-12-31", time.Local)
+		form.Deadline = &defaultDeadline
+	}
+
+	milestone := &issues_model.Milestone{
+		OrgID:        ctx.Org.Organization.ID,
+		Name:         form.Title,
+		Content:      form.Description,
+		DeadlineUnix: timeutil.TimeStamp(form.Deadline.Unix()),
+	}
+
+	if form.State == "closed" {
+		milestone.IsClosed = true
+		milestone.ClosedDateUnix = timeutil.TimeStampNow()
+	}
+
+	if err := issues_model.NewMilestone(ctx, milestone); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewMilestone", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAPIMilestone(milestone))
+}
+
+// EditMilestone modify a milestone of an organization
+func EditMilestone(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/milestones/{id} organization orgEditMilestone
+	// ---
+	// summary: Update a milestone of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the milestone to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditMilestoneOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Milestone"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.EditMilestoneOption)
+	milestone := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if len(form.Title) > 0 {
+		milestone.Name = form.Title
+	}
+	if form.Description != nil {
+		milestone.Content = *form.Description
+	}
+	if form.Deadline != nil && !form.Deadline.IsZero() {
+		milestone.DeadlineUnix = timeutil.TimeStamp(form.Deadline.Unix())
+	}
+
+	oldIsClosed := milestone.IsClosed
+	if form.State != nil {
+		milestone.IsClosed = *form.State == string(api.StateClosed)
+	}
+
+	if err := issues_model.UpdateMilestone(ctx, milestone, oldIsClosed); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateMilestone", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIMilestone(milestone))
+}
+
+// DeleteMilestone delete a milestone of an organization
+func DeleteMilestone(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/milestones/{id} organization orgDeleteMilestone
+	// ---
+	// summary: Delete a milestone of an organization, its issues are removed from it
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the milestone to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	milestone := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.DeleteMilestoneByOrgID(ctx, ctx.Org.Organization.ID, milestone.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteMilestoneByOrgID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetMilestoneBurndown get the burndown of a milestone of an organization across its repositories
+func GetMilestoneBurndown(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/milestones/{id}/burndown organization orgGetMilestoneBurndown
+	// ---
+	// summary: Get the daily open and closed issues of a milestone of an organization, with the progress of each repository the user can read
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the milestone
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MilestoneBurndown"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	milestone := getOrgMilestone(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	// only the issues of the repositories the doer can read are counted
+	var repoCond builder.Cond
+	if ctx.Doer == nil || !ctx.Doer.IsAdmin {
+		repoCond = repo_model.AccessibleRepositoryCondition(ctx.Doer, unit.TypeIssues)
+	}
+	burndown, err := issues_model.GetMilestoneBurndown(ctx, milestone, repoCond, timeutil.TimeStampNow().AsTime())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestoneBurndown", err)
+		return
+	}
+
+	apiBurndown, err := convert.ToAPIMilestoneBurndown(ctx, milestone, burndown)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToAPIMilestoneBurndown", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiBurndown)
+}
+
+func getOrgMilestone(ctx *context.APIContext) *issues_model.Milestone {
+	milestone, err := issues_model.GetMilestoneByOrgID(ctx, ctx.Org.Organization.ID, ctx.PathParamInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrMilestoneNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetMilestoneByOrgID", err)
+		}
+		return nil
+	}
+	return milestone
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetPackageUsage gets the storage used by the packages of an organization
+func GetPackageUsage(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/packages/usage organization orgGetPackageUsage
+	// ---
+	// summary: Get the storage used by the packages of an organization for each package type
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageUsage"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetOwnerPackageUsage(ctx, ctx.ContextUser)
+}
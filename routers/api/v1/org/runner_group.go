@@ -0,0 +1,498 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListRunnerGroups lists the runner groups of the organization
+func ListRunnerGroups(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runner-groups organization orgListRunnerGroups
+	// ---
+	// summary: List the runner groups of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroupsList"
+
+	shared.ListRunnerGroups(ctx, ctx.Org.Organization.ID)
+}
+
+// CreateRunnerGroup creates a runner group for the organization
+func CreateRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runner-groups organization orgCreateRunnerGroup
+	// ---
+	// summary: Create a runner group for an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionRunnerGroupOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.CreateRunnerGroup(ctx, ctx.Org.Organization.ID)
+}
+
+// GetRunnerGroup gets a runner group of the organization
+func GetRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runner-groups/{group_id} organization orgGetRunnerGroup
+	// ---
+	// summary: Get a runner group of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRunnerGroup(ctx, ctx.Org.Organization.ID)
+}
+
+// EditRunnerGroup edits a runner group of the organization
+func EditRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/actions/runner-groups/{group_id} organization orgEditRunnerGroup
+	// ---
+	// summary: Edit a runner group of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionRunnerGroupOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerGroup"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditRunnerGroup(ctx, ctx.Org.Organization.ID)
+}
+
+// DeleteRunnerGroup deletes a runner group of the organization, its runners aren't in a group anymore
+func DeleteRunnerGroup(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/runner-groups/{group_id} organization orgDeleteRunnerGroup
+	// ---
+	// summary: Delete a runner group of an organization, its runners aren't in a group anymore
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRunnerGroup(ctx, ctx.Org.Organization.ID)
+}
+
+// ListRunnerGroupRunners lists the runners of a runner group of the organization
+func ListRunnerGroupRunners(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runner-groups/{group_id}/runners organization orgListRunnerGroupRunners
+	// ---
+	// summary: List the runners of a runner group of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnersList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRunnerGroupRunners(ctx, ctx.Org.Organization.ID)
+}
+
+// AddRunnerGroupRunner moves a runner of the organization to a runner group
+func AddRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/runner-groups/{group_id}/runners/{runner_id} organization orgAddRunnerGroupRunner
+	// ---
+	// summary: Move a runner of an organization to a runner group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.AddRunnerGroupRunner(ctx, ctx.Org.Organization.ID)
+}
+
+// RemoveRunnerGroupRunner removes a runner from a runner group of the organization
+func RemoveRunnerGroupRunner(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/runner-groups/{group_id}/runners/{runner_id} organization orgRemoveRunnerGroupRunner
+	// ---
+	// summary: Remove a runner from a runner group of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RemoveRunnerGroupRunner(ctx, ctx.Org.Organization.ID)
+}
+
+// ListRunnerGroupRepos lists the repositories a runner group of the organization is granted to
+func ListRunnerGroupRepos(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runner-groups/{group_id}/repositories organization orgListRunnerGroupRepos
+	// ---
+	// summary: List the repositories a runner group of an organization is granted to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRunnerGroupRepos(ctx, ctx.Org.Organization.ID)
+}
+
+// AddRunnerGroupRepo grants a runner group of the organization to a repository
+func AddRunnerGroupRepo(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/runner-groups/{group_id}/repositories/{repo_id} organization orgAddRunnerGroupRepo
+	// ---
+	// summary: Grant a runner group of an organization to a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: repo_id
+	//   in: path
+	//   description: id of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.AddRunnerGroupRepo(ctx, ctx.Org.Organization.ID)
+}
+
+// RemoveRunnerGroupRepo revokes the access of a repository to a runner group of the organization
+func RemoveRunnerGroupRepo(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/runner-groups/{group_id}/repositories/{repo_id} organization orgRemoveRunnerGroupRepo
+	// ---
+	// summary: Revoke the access of a repository to a runner group of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: group_id
+	//   in: path
+	//   description: id of the runner group
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: repo_id
+	//   in: path
+	//   description: id of the repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.RemoveRunnerGroupRepo(ctx, ctx.Org.Organization.ID)
+}
+
+// ListRunnerLabels lists the labels of a runner of the organization
+func ListRunnerLabels(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runners/{runner_id}/labels organization orgListRunnerLabels
+	// ---
+	// summary: List the labels of a runner of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRunnerLabels(ctx, ctx.Org.Organization.ID)
+}
+
+// SetRunnerLabels replaces the custom labels of a runner of the organization
+func SetRunnerLabels(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/actions/runners/{runner_id}/labels organization orgSetRunnerLabels
+	// ---
+	// summary: Replace the custom labels of a runner of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ActionRunnerLabelsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.SetRunnerLabels(ctx, ctx.Org.Organization.ID)
+}
+
+// AddRunnerLabels adds custom labels to a runner of the organization
+func AddRunnerLabels(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runners/{runner_id}/labels organization orgAddRunnerLabels
+	// ---
+	// summary: Add custom labels to a runner of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ActionRunnerLabelsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.AddRunnerLabels(ctx, ctx.Org.Organization.ID)
+}
+
+// RemoveRunnerLabel removes a custom label from a runner of the organization
+func RemoveRunnerLabel(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/actions/runners/{runner_id}/labels/{name} organization orgRemoveRunnerLabel
+	// ---
+	// summary: Remove a custom label from a runner of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the label
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunnerLabelsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.RemoveRunnerLabel(ctx, ctx.Org.Organization.ID)
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// CreateEphemeralRegistrationToken creates a token registering a single ephemeral runner of the organization
+func CreateEphemeralRegistrationToken(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runners/ephemeral-registration-token organization orgCreateEphemeralRunnerRegistrationToken
+	// ---
+	// summary: Create a token registering a single ephemeral runner of an organization, removed once it has run a job
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionEphemeralRunnerTokenOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionEphemeralRunnerToken"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.CreateEphemeralRegistrationToken(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// ListQueuedJobs lists the jobs of the organization waiting for a runner by set of labels
+func ListQueuedJobs(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/actions/runners/queue organization orgListRunnerQueuedJobs
+	// ---
+	// summary: List the number of jobs of an organization waiting for a runner by set of labels
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionQueuedJobsList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListQueuedJobs(ctx, ctx.Org.Organization.ID)
+}
+
+// ExpireRunner removes a runner of the organization at once
+func ExpireRunner(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/actions/runners/{runner_id}/expire organization orgExpireRunner
+	// ---
+	// summary: Remove a runner of an organization at once, the tasks it's running fail
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: runner_id
+	//   in: path
+	//   description: id of the runner
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ExpireRunner(ctx, ctx.Org.Organization.ID)
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// ListPackageAttestations lists the signatures and attestations of a package version
+func ListPackageAttestations(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/{version}/attestations package listPackageAttestations
+	// ---
+	// summary: List the signatures and attestations of a package version
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAttestationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pas, err := packages_model.GetAttestationsByVersionID(ctx, ctx.Package.Descriptor.Version.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetAttestationsByVersionID", err)
+		return
+	}
+
+	res := make([]*api.PackageAttestation, 0, len(pas))
+	for _, pa := range pas {
+		res = append(res, convert.ToPackageAttestation(pa))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreatePackageAttestation adds a signature or an attestation to a package version
+func CreatePackageAttestation(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/{type}/{name}/{version}/attestations package createPackageAttestation
+	// ---
+	// summary: Add a signature or an attestation to a package version
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePackageAttestationOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PackageAttestation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreatePackageAttestationOption)
+
+	pa, err := packages_service.AddAttestation(ctx, ctx.Doer, ctx.Package.Descriptor.Version, form.PredicateType, form.MediaType, form.Bundle)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddAttestation", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToPackageAttestation(pa))
+}
+
+// DeletePackageAttestation removes a signature or an attestation of a package version
+func DeletePackageAttestation(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/{type}/{name}/{version}/attestations/{id} package deletePackageAttestation
+	// ---
+	// summary: Remove a signature or an attestation of a package version
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the attestation
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if err := packages_service.RemoveAttestation(ctx, ctx.Doer, ctx.Package.Descriptor.Version, ctx.PathParamInt64("id")); err != nil {
+		switch {
+		case err == packages_model.ErrPackageAttestationNotExist:
+			ctx.NotFound()
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "RemoveAttestation", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListPackageAudit lists the audit trail of the packages of an owner
+func ListPackageAudit(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/audit package listPackageAudit
+	// ---
+	// summary: List the actions on the packages of an owner, the newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: query
+	//   description: package type filter
+	//   type: string
+	//   enum: [alpine, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, julia, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, terraform, vagrant]
+	// - name: name
+	//   in: query
+	//   description: package name filter
+	//   type: string
+	// - name: action
+	//   in: query
+	//   description: action filter
+	//   type: string
+	//   enum: [publish, delete_version, delete_package, set_immutable, unset_immutable]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAuditList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listOptions := utils.GetListOptions(ctx)
+
+	pas, count, err := db.FindAndCount[packages_model.PackageAudit](ctx, packages_model.FindAuditOptions{
+		ListOptions: listOptions,
+		OwnerID:     ctx.Package.Owner.ID,
+		Type:        packages_model.Type(ctx.FormTrim("type")),
+		Name:        ctx.FormTrim("name"),
+		Action:      packages_model.AuditAction(ctx.FormTrim("action")),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindAndCount", err)
+		return
+	}
+
+	apiAudits, err := convert.ToPackageAudits(ctx, pas, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToPackageAudits", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiAudits)
+}
@@ -0,0 +1,363 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
+)
+
+// getCleanupRule returns the cleanup rule of the owner from the path, it writes the error response if it fails
+func getCleanupRule(ctx *context.APIContext) *packages_model.PackageCleanupRule {
+	pcr, err := packages_model.GetCleanupRuleByID(ctx, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, packages_model.ErrPackageCleanupRuleNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCleanupRuleByID", err)
+		}
+		return nil
+	}
+	if pcr.OwnerID != ctx.Package.Owner.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return pcr
+}
+
+// validateCleanupRule checks the values of the rule, it writes the error response if they're invalid
+func validateCleanupRule(ctx *context.APIContext, pcr *packages_model.PackageCleanupRule) bool {
+	if pcr.KeepCount < 0 || pcr.RemoveDays < 0 || pcr.RemoveUntaggedDays < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "counts and days must not be negative")
+		return false
+	}
+	protectedTags := make([]string, 0, len(pcr.ProtectedTags))
+	for _, pattern := range pcr.ProtectedTags {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			protectedTags = append(protectedTags, pattern)
+		}
+	}
+	pcr.ProtectedTags = protectedTags
+	pcr.KeepPatternMatcher, pcr.RemovePatternMatcher, pcr.ProtectedTagMatchers = nil, nil, nil
+	if err := pcr.CompiledPattern(); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return false
+	}
+	return true
+}
+
+// ListCleanupRules lists the cleanup rules of an owner
+func ListCleanupRules(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/cleanup_rules package listPackageCleanupRules
+	// ---
+	// summary: List the cleanup rules of the packages of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRuleList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcrs, err := packages_model.GetCleanupRulesByOwner(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCleanupRulesByOwner", err)
+		return
+	}
+
+	res := make([]*api.PackageCleanupRule, 0, len(pcrs))
+	for _, pcr := range pcrs {
+		res = append(res, convert.ToPackageCleanupRule(pcr))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreateCleanupRule creates the cleanup rule of a type of packages of an owner
+func CreateCleanupRule(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/cleanup_rules package createPackageCleanupRule
+	// ---
+	// summary: Create the cleanup rule of a type of packages of an owner, it's executed by the cron task cleaning up the packages
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePackageCleanupRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreatePackageCleanupRuleOption)
+
+	packageType := packages_model.Type(form.Type)
+	if !slices.Contains(packages_model.TypeList, packageType) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid package type")
+		return
+	}
+
+	pcr := &packages_model.PackageCleanupRule{
+		Enabled:            form.Enabled,
+		OwnerID:            ctx.Package.Owner.ID,
+		Type:               packageType,
+		KeepCount:          form.KeepCount,
+		KeepPattern:        form.KeepPattern,
+		RemoveDays:         form.RemoveDays,
+		RemovePattern:      form.RemovePattern,
+		MatchFullName:      form.MatchFullName,
+		RemoveUntaggedDays: form.RemoveUntaggedDays,
+		ProtectedTags:      form.ProtectedTags,
+	}
+	if !validateCleanupRule(ctx, pcr) {
+		return
+	}
+
+	if has, err := packages_model.HasOwnerCleanupRuleForPackageType(ctx, pcr.OwnerID, pcr.Type); err != nil {
+		ctx.Error(http.StatusInternalServerError, "HasOwnerCleanupRuleForPackageType", err)
+		return
+	} else if has {
+		ctx.Error(http.StatusConflict, "", "a cleanup rule already exists for the package type")
+		return
+	}
+
+	pcr, err := packages_model.InsertCleanupRule(ctx, pcr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "InsertCleanupRule", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToPackageCleanupRule(pcr))
+}
+
+// GetCleanupRule gets a cleanup rule of an owner
+func GetCleanupRule(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/cleanup_rules/{id} package getPackageCleanupRule
+	// ---
+	// summary: Get a cleanup rule of the packages of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRule(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPackageCleanupRule(pcr))
+}
+
+// EditCleanupRule edits a cleanup rule of an owner
+func EditCleanupRule(ctx *context.APIContext) {
+	// swagger:operation PATCH /packages/{owner}/cleanup_rules/{id} package editPackageCleanupRule
+	// ---
+	// summary: Edit a cleanup rule of the packages of an owner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPackageCleanupRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pcr := getCleanupRule(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditPackageCleanupRuleOption)
+	if form.Enabled != nil {
+		pcr.Enabled = *form.Enabled
+	}
+	if form.KeepCount != nil {
+		pcr.KeepCount = *form.KeepCount
+	}
+	if form.KeepPattern != nil {
+		pcr.KeepPattern = *form.KeepPattern
+	}
+	if form.RemoveDays != nil {
+		pcr.RemoveDays = *form.RemoveDays
+	}
+	if form.RemovePattern != nil {
+		pcr.RemovePattern = *form.RemovePattern
+	}
+	if form.MatchFullName != nil {
+		pcr.MatchFullName = *form.MatchFullName
+	}
+	if form.RemoveUntaggedDays != nil {
+		pcr.RemoveUntaggedDays = *form.RemoveUntaggedDays
+	}
+	if form.ProtectedTags != nil {
+		pcr.ProtectedTags = form.ProtectedTags
+	}
+	if !validateCleanupRule(ctx, pcr) {
+		return
+	}
+
+	if err := packages_model.UpdateCleanupRule(ctx, pcr); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateCleanupRule", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPackageCleanupRule(pcr))
+}
+
+// DeleteCleanupRule deletes a cleanup rule of an owner
+func DeleteCleanupRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/cleanup_rules/{id} package deletePackageCleanupRule
+	// ---
+	// summary: Delete a cleanup rule of the packages of an owner
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRule(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := packages_model.DeleteCleanupRuleByID(ctx, pcr.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteCleanupRuleByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// PreviewCleanupRule lists the package versions a cleanup rule removes, without removing them
+func PreviewCleanupRule(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/cleanup_rules/{id}/preview package previewPackageCleanupRule
+	// ---
+	// summary: List the package versions a cleanup rule removes when it's executed, without removing them
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRule(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	pvs, err := packages_cleanup_service.GetVersionsToRemove(ctx, pcr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetVersionsToRemove", err)
+		return
+	}
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackageDescriptors", err)
+		return
+	}
+
+	apiPackages := make([]*api.Package, 0, len(pds))
+	for _, pd := range pds {
+		apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+			return
+		}
+		apiPackages = append(apiPackages, apiPackage)
+	}
+	ctx.JSON(http.StatusOK, apiPackages)
+}
@@ -4,15 +4,21 @@
 package packages
 
 import (
+	"errors"
 	"net/http"
 
 	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/perm"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/optional"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 	packages_service "code.gitea.io/gitea/services/packages"
+	maven_service "code.gitea.io/gitea/services/packages/maven"
 )
 
 // ListPackages gets all packages of an owner
@@ -40,7 +46,7 @@ func ListPackages(ctx *context.APIContext) {
 	//   in: query
 	//   description: package type filter
 	//   type: string
-	//   enum: [alpine, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, vagrant]
+	//   enum: [alpine, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, julia, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, terraform, vagrant]
 	// - name: q
 	//   in: query
 	//   description: name filter
@@ -161,12 +167,71 @@ func DeletePackage(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
 	err := packages_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor.Version)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "RemovePackageVersion", err)
+		if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "RemovePackageVersion", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemovePackageVersion", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemovePackage deletes a package with all its versions
+func RemovePackage(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/{type}/{name} package removePackage
+	// ---
+	// summary: Delete a package with all its versions
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p, err := packages.GetPackageByName(ctx, ctx.Package.Owner.ID, packages.Type(ctx.PathParam("type")), ctx.PathParam("name"))
+	if err != nil {
+		if err == packages.ErrPackageNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageByName", err)
+		}
+		return
+	}
+
+	if err := packages_service.RemovePackage(ctx, ctx.Doer, p); err != nil {
+		switch {
+		case err == packages.ErrPackageNotExist:
+			ctx.NotFound()
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden, "RemovePackage", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "RemovePackage", err)
+		}
 		return
 	}
 	ctx.Status(http.StatusNoContent)
@@ -213,3 +278,232 @@ func ListPackageFiles(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, apiPackageFiles)
 }
+
+// SetPackageImmutable makes the versions of a package immutable
+func SetPackageImmutable(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/{type}/{name}/-/immutable package setPackageImmutable
+	// ---
+	// summary: Make the versions of a package immutable, they can only be overwritten or deleted by site admins afterwards
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setPackageImmutable(ctx, true)
+}
+
+// UnsetPackageImmutable makes the versions of a package mutable again
+func UnsetPackageImmutable(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/{type}/{name}/-/immutable package unsetPackageImmutable
+	// ---
+	// summary: Make the versions of a package mutable again, only site admins can do this
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setPackageImmutable(ctx, false)
+}
+
+func setPackageImmutable(ctx *context.APIContext, immutable bool) {
+	p, err := packages.GetPackageByName(ctx, ctx.Package.Owner.ID, packages.Type(ctx.PathParam("type")), ctx.PathParam("name"))
+	if err != nil {
+		if err == packages.ErrPackageNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageByName", err)
+		}
+		return
+	}
+
+	if err := packages_service.SetPackageImmutable(ctx, ctx.Doer, p, immutable); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetPackageImmutable", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RebuildPackageMetadata rebuilds the metadata of a Maven package from its stored files
+func RebuildPackageMetadata(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/{type}/{name}/-/rebuild_metadata package rebuildPackageMetadata
+	// ---
+	// summary: Rebuild the metadata of the versions of a Maven package and the maven-metadata.xml of its SNAPSHOT versions from the stored files
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package, only maven is supported
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p, err := packages.GetPackageByName(ctx, ctx.Package.Owner.ID, packages.Type(ctx.PathParam("type")), ctx.PathParam("name"))
+	if err != nil {
+		if err == packages.ErrPackageNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageByName", err)
+		}
+		return
+	}
+
+	if err := maven_service.RebuildMetadata(ctx, ctx.Doer, p); err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "RebuildMetadata", err)
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden, "RebuildMetadata", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "RebuildMetadata", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// PromotePackage copies a package version to another owner
+func PromotePackage(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/{type}/{name}/{version}/promote package promotePackage
+	// ---
+	// summary: Promote a package version to another owner, the version is copied with its metadata and files
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/PromotePackageOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Package"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.PromotePackageOption)
+
+	target, err := user_model.GetUserByName(ctx, form.Owner)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	accessMode, err := context.DeterminePackageAccessMode(ctx.Base, target, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeterminePackageAccessMode", err)
+		return
+	}
+	if accessMode < perm.AccessModeWrite && !ctx.IsUserSiteAdmin() {
+		ctx.Error(http.StatusForbidden, "", "user should have write permission to the packages of the target owner")
+		return
+	}
+
+	pd, err := packages_service.PromotePackageVersion(ctx, ctx.Doer, target, ctx.Package.Descriptor)
+	if err != nil {
+		switch {
+		case err == packages.ErrDuplicatePackageVersion:
+			ctx.Error(http.StatusConflict, "", err)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case err == packages_service.ErrQuotaTotalCount, err == packages_service.ErrQuotaTypeSize, err == packages_service.ErrQuotaTotalSize:
+			ctx.Error(http.StatusForbidden, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "PromotePackageVersion", err)
+		}
+		return
+	}
+
+	apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, apiPackage)
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	upstream_service "code.gitea.io/gitea/services/packages/upstream"
+)
+
+// ListUpstreams lists the upstream registries of an owner
+func ListUpstreams(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/upstreams package listPackageUpstreams
+	// ---
+	// summary: List the upstream registries of the packages of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageUpstreamList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pus, err := packages_model.GetUpstreamsByOwner(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUpstreamsByOwner", err)
+		return
+	}
+
+	res := make([]*api.PackageUpstream, 0, len(pus))
+	for _, pu := range pus {
+		res = append(res, convert.ToPackageUpstream(pu))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetUpstream gets the upstream registry of a type of packages of an owner
+func GetUpstream(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/upstreams/{type} package getPackageUpstream
+	// ---
+	// summary: Get the upstream registry of a type of packages of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the packages
+	//   type: string
+	//   enum: [container, go, npm]
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageUpstream"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pu, err := packages_model.GetUpstreamByOwnerAndType(ctx, ctx.Package.Owner.ID, packages_model.Type(ctx.PathParam("type")))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUpstreamByOwnerAndType", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPackageUpstream(pu))
+}
+
+// SetUpstream sets the upstream registry of a type of packages of an owner
+func SetUpstream(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/upstreams/{type} package setPackageUpstream
+	// ---
+	// summary: Set the upstream registry of a type of packages of an owner, the missing versions are fetched from it and cached
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the packages
+	//   type: string
+	//   enum: [container, go, npm]
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPackageUpstreamOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageUpstream"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetPackageUpstreamOption)
+
+	pu, err := upstream_service.SetUpstream(ctx, ctx.Package.Owner.ID, packages_model.Type(ctx.PathParam("type")), form.URL)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetUpstream", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPackageUpstream(pu))
+}
+
+// DeleteUpstream deletes the upstream registry of a type of packages of an owner
+func DeleteUpstream(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/upstreams/{type} package deletePackageUpstream
+	// ---
+	// summary: Delete the upstream registry of a type of packages of an owner, the cached versions are kept
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the packages
+	//   type: string
+	//   enum: [container, go, npm]
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := packages_model.DeleteUpstreamByOwnerAndType(ctx, ctx.Package.Owner.ID, packages_model.Type(ctx.PathParam("type"))); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteUpstreamByOwnerAndType", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -117,12 +117,11 @@ func (Action) CreateOrUpdateSecret(ctx *context.APIContext) {
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	owner := ctx.Repo.Owner
 	repo := ctx.Repo.Repository
 
 	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
 
-	_, created, err := secret_service.CreateOrUpdateSecret(ctx, owner.ID, repo.ID, ctx.PathParam("secretname"), opt.Data)
+	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Doer, 0, repo.ID, ctx.PathParam("secretname"), opt.Data)
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "CreateOrUpdateSecret", err)
@@ -174,10 +173,9 @@ func (Action) DeleteSecret(ctx *context.APIContext) {
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	owner := ctx.Repo.Owner
 	repo := ctx.Repo.Repository
 
-	err := secret_service.DeleteSecretByName(ctx, owner.ID, repo.ID, ctx.PathParam("secretname"))
+	err := secret_service.DeleteSecretByName(ctx, ctx.Doer, 0, repo.ID, ctx.PathParam("secretname"))
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "DeleteSecret", err)
@@ -583,3 +581,196 @@ func ListActionTasks(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &res)
 }
+
+// ListActionRuns list the runs of the workflows of a repository
+func ListActionRuns(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs repository ListActionRuns
+	// ---
+	// summary: List a repository's action runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: concurrency_group
+	//   in: query
+	//   description: only the runs of this concurrency group
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results, default maximum page size is 50
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRunsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	runs, total, err := db.FindAndCount[actions_model.ActionRun](ctx, actions_model.FindRunOptions{
+		ListOptions:      utils.GetListOptions(ctx),
+		RepoID:           ctx.Repo.Repository.ID,
+		ConcurrencyGroup: ctx.FormString("concurrency_group"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRuns", err)
+		return
+	}
+
+	res := &api.ActionRunsResponse{
+		Entries:    make([]*api.ActionRun, len(runs)),
+		TotalCount: total,
+	}
+	for i, run := range runs {
+		run.Repo = ctx.Repo.Repository
+		if res.Entries[i], err = convert.ToActionRun(ctx, run); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionRun get a run of a workflow of a repository with its jobs
+func GetActionRun(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{id} repository GetActionRun
+	// ---
+	// summary: Get an action run of a repository with its jobs, their dependencies, matrix combinations and timings
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run, err := actions_model.GetRunByID(ctx, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		}
+		return
+	}
+	if run.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return
+	}
+	run.Repo = ctx.Repo.Repository
+
+	respondActionRunWithJobs(ctx, http.StatusOK, run)
+}
+
+// respondActionRunWithJobs responds with the run and its jobs
+func respondActionRunWithJobs(ctx *context.APIContext, status int, run *actions_model.ActionRun) {
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+
+	res, err := convert.ToActionRun(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionRun", err)
+		return
+	}
+	res.Jobs = make([]*api.ActionRunJob, 0, len(jobs))
+	for _, job := range jobs {
+		apiJob, err := convert.ToActionRunJob(job)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionRunJob", err)
+			return
+		}
+		res.Jobs = append(res.Jobs, apiJob)
+	}
+
+	ctx.JSON(status, res)
+}
+
+// DispatchActionWorkflow runs a workflow manually
+func DispatchActionWorkflow(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/workflows/{workflow_id}/dispatches repository DispatchActionWorkflow
+	// ---
+	// summary: Run a workflow having a workflow_dispatch trigger on a branch or a tag, with its inputs
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: name of the workflow file
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionWorkflowDispatch"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opt := web.GetForm(ctx).(*api.CreateActionWorkflowDispatch)
+	workflowID := ctx.PathParam("workflow_id")
+
+	if _, err := actions_service.DispatchWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflowID, opt.Ref, opt.Inputs); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, "DispatchWorkflow", err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "DispatchWorkflow", err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "DispatchWorkflow", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DispatchWorkflow", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
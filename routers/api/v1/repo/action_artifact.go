@@ -0,0 +1,471 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// getActionRun returns the run of the repository from the path, it writes the error response if it fails
+func getActionRun(ctx *context.APIContext) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByID(ctx, ctx.PathParamInt64("id"))
+	if err == nil && run.RepoID != ctx.Repo.Repository.ID {
+		err = util.ErrNotExist
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		}
+		return nil
+	}
+	return run
+}
+
+// getActionRunArtifact returns the uploaded records of the artifact of the run from the path, it writes the error response if it fails
+func getActionRunArtifact(ctx *context.APIContext) []*actions_model.ActionArtifact {
+	run := getActionRun(ctx)
+	if ctx.Written() {
+		return nil
+	}
+	artifacts, err := actions_service.GetRunArtifact(ctx, run.ID, ctx.PathParam("artifact_name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunArtifact", err)
+		}
+		return nil
+	}
+	return artifacts
+}
+
+// ListActionRunArtifacts lists the artifacts of a run
+func ListActionRunArtifacts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{id}/artifacts repository ListActionRunArtifacts
+	// ---
+	// summary: List the artifacts of an action run, with the expired ones
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionArtifactsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRun(ctx)
+	if ctx.Written() {
+		return
+	}
+	metas, err := actions_model.ListUploadedArtifactsMeta(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListUploadedArtifactsMeta", err)
+		return
+	}
+
+	res := &api.ActionArtifactsResponse{
+		Entries:    make([]*api.ActionArtifact, 0, len(metas)),
+		TotalCount: int64(len(metas)),
+	}
+	for _, meta := range metas {
+		res.Entries = append(res.Entries, convert.ToActionArtifact(meta))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DownloadActionArtifact downloads an artifact of a run as a zip file
+func DownloadActionArtifact(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{id}/artifacts/{artifact_name}/zip repository DownloadActionArtifact
+	// ---
+	// summary: Download an artifact of an action run as a zip file
+	// description: The artifacts uploaded by actions/upload-artifact@v4 support range requests.
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the zip file of the artifact
+	//   "206":
+	//     description: a range of the zip file of the artifact
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	artifacts := getActionRunArtifact(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !actions_service.IsArtifactV4(artifacts) {
+		// the files uploaded by the older backends are zipped on the fly, without support of range requests
+		ctx.SetServeHeaders(&context.ServeHeaderOptions{Filename: artifacts[0].ArtifactName + ".zip", ContentType: "application/zip"})
+		if err := actions_service.WriteArtifactZip(ctx.Resp, artifacts); err != nil {
+			ctx.Error(http.StatusInternalServerError, "WriteArtifactZip", err)
+		}
+		return
+	}
+
+	art := artifacts[0]
+	if setting.Actions.ArtifactStorage.ServeDirect() {
+		u, err := storage.ActionsArtifacts.URL(art.StoragePath, art.ArtifactPath)
+		if u != nil && err == nil {
+			ctx.Redirect(u.String())
+			return
+		}
+	}
+	f, err := storage.ActionsArtifacts.Open(art.StoragePath)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Open", err)
+		return
+	}
+	defer f.Close()
+	modTime := art.UpdatedUnix.AsTime()
+	httplib.ServeContentByReadSeeker(ctx.Req, ctx.Resp, art.ArtifactPath, &modTime, f)
+}
+
+// DeleteActionArtifact deletes an artifact of a run
+func DeleteActionArtifact(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{id}/artifacts/{artifact_name} repository DeleteActionArtifact
+	// ---
+	// summary: Delete an artifact of an action run
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	artifacts := getActionRunArtifact(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.SetArtifactNeedDelete(ctx, artifacts[0].RunID, artifacts[0].ArtifactName); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetArtifactNeedDelete", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListActionArtifactFiles lists the files inside an artifact of a run
+func ListActionArtifactFiles(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{id}/artifacts/{artifact_name}/files repository ListActionArtifactFiles
+	// ---
+	// summary: List the files inside an artifact of an action run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionArtifactFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	artifacts := getActionRunArtifact(ctx)
+	if ctx.Written() {
+		return
+	}
+	files, err := actions_service.ListArtifactFiles(artifacts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListArtifactFiles", err)
+		return
+	}
+
+	res := make([]*api.ActionArtifactFile, 0, len(files))
+	for _, file := range files {
+		res = append(res, &api.ActionArtifactFile{Path: file.Path, Size: file.Size})
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetActionArtifactFile downloads a file inside an artifact of a run
+func GetActionArtifactFile(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{id}/artifacts/{artifact_name}/files/{filepath} repository GetActionArtifactFile
+	// ---
+	// summary: Download a file inside an artifact of an action run, without downloading the whole artifact
+	// description: The files support range requests.
+	// produces:
+	// - application/octet-stream
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file inside the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the content of the file
+	//   "206":
+	//     description: a range of the content of the file
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	artifacts := getActionRunArtifact(ctx)
+	if ctx.Written() {
+		return
+	}
+	r, file, err := actions_service.OpenArtifactFile(artifacts, ctx.PathParam("*"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "OpenArtifactFile", err)
+		}
+		return
+	}
+	defer r.Close()
+	httplib.ServeContentByReader(ctx.Req, ctx.Resp, file.Path, file.Size, r)
+}
+
+// DeleteActionArtifactFile deletes a file inside an artifact of a run
+func DeleteActionArtifactFile(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/runs/{id}/artifacts/{artifact_name}/files/{filepath} repository DeleteActionArtifactFile
+	// ---
+	// summary: Delete a file inside an artifact of an action run, the artifact is deleted with its last file
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: artifact_name
+	//   in: path
+	//   description: name of the artifact
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file inside the artifact
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	artifacts := getActionRunArtifact(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.DeleteArtifactFile(ctx, artifacts, ctx.PathParam("*")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteArtifactFile", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetActionArtifactRetention returns the number of days the artifacts of a repository are kept
+func GetActionArtifactRetention(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/artifact-retention repository GetActionArtifactRetention
+	// ---
+	// summary: Get the number of days the artifacts of a repository are kept
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionArtifactRetention"
+
+	writeActionArtifactRetention(ctx)
+}
+
+// EditActionArtifactRetention sets the number of days the artifacts of a repository are kept
+func EditActionArtifactRetention(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/artifact-retention repository EditActionArtifactRetention
+	// ---
+	// summary: Set the number of days the artifacts of a repository are kept, the artifacts already kept longer expire earlier
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditActionArtifactRetentionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionArtifactRetention"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditActionArtifactRetentionOption)
+	if err := actions_service.SetRepoArtifactRetentionDays(ctx, ctx.Repo.Repository, form.Days); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetRepoArtifactRetentionDays", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetRepoArtifactRetentionDays", err)
+		}
+		return
+	}
+	writeActionArtifactRetention(ctx)
+}
+
+func writeActionArtifactRetention(ctx *context.APIContext) {
+	maxDays, err := actions_service.GetOwnerArtifactRetentionDays(ctx, ctx.Repo.Repository.OwnerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOwnerArtifactRetentionDays", err)
+		return
+	}
+	days, err := actions_service.GetArtifactRetentionDays(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetArtifactRetentionDays", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ActionArtifactRetention{Days: days, MaximumAllowedDays: maxDays})
+}
@@ -0,0 +1,269 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// jobLogsPollInterval is how often the logs of a followed job are checked for new lines
+const jobLogsPollInterval = time.Second
+
+// getActionRunJob returns the job of the repository from the path, it writes the error response if it fails
+func getActionRunJob(ctx *context.APIContext) *actions_model.ActionRunJob {
+	job, err := actions_model.GetRunJobByID(ctx, ctx.PathParamInt64("job_id"))
+	if err == nil && job.RepoID != ctx.Repo.Repository.ID {
+		err = util.ErrNotExist
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunJobByID", err)
+		}
+		return nil
+	}
+	return job
+}
+
+// GetActionJobLogs downloads or follows the logs of a job
+func GetActionJobLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id}/logs repository GetActionJobLogs
+	// ---
+	// summary: Download the logs of an action job, or follow them as server-sent events
+	// description: Without `follow`, the logs written so far are downloaded as text, a line per log with its timestamp.
+	//   With `follow`, the logs are sent as server-sent events until the job is done. A `step` event is sent with an
+	//   ActionJobStep when a step starts or its status changes, a `log` event with an ActionJobLogLine for each line and
+	//   an `end` event with the ActionRunJob when the job is done. The id of a `log` event is the id of the task running
+	//   the job and the line, separated by a dash, a reconnecting client resumes after the `Last-Event-ID` header.
+	// produces:
+	// - text/plain
+	// - text/event-stream
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: follow
+	//   in: query
+	//   description: stream the logs as server-sent events until the job is done
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     description: the logs of the job
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	job := getActionRunJob(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.FormBool("follow") {
+		followActionJobLogs(ctx, job)
+		return
+	}
+
+	if job.TaskID == 0 {
+		ctx.NotFound("the job hasn't started")
+		return
+	}
+	if err := job.LoadRun(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRun", err)
+		return
+	}
+	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTaskByID", err)
+		return
+	}
+	if task.LogExpired {
+		ctx.NotFound("the logs of the job have been cleaned up")
+		return
+	}
+
+	reader, err := actions_module.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenLogs", err)
+		return
+	}
+	defer reader.Close()
+
+	ctx.ServeContent(reader, &context.ServeHeaderOptions{
+		Filename:           fmt.Sprintf("%v-%v-%v.log", workflowName(job.Run), job.Name, task.ID),
+		ContentLength:      &task.LogSize,
+		ContentType:        "text/plain",
+		ContentTypeCharset: "utf-8",
+		Disposition:        "attachment",
+	})
+}
+
+func followActionJobLogs(ctx *context.APIContext, job *actions_model.ActionRunJob) {
+	follower := &actions_service.JobLogFollower{JobID: job.ID}
+	if lastEventID := ctx.Req.Header.Get("Last-Event-ID"); lastEventID != "" {
+		taskID, line, _ := strings.Cut(lastEventID, "-")
+		if id, err := strconv.ParseInt(taskID, 10, 64); err == nil {
+			if cursor, err := strconv.ParseInt(line, 10, 64); err == nil {
+				follower.TaskID, follower.Cursor = id, cursor
+			}
+		}
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	ctx.Resp.Header().Set("Connection", "keep-alive")
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	ctx.Resp.Flush()
+
+	send := func(event *eventsource.Event) bool {
+		if _, err := event.WriteTo(ctx.Resp); err != nil {
+			log.Error("Unable to write to the log stream of the job %d: %v", job.ID, err)
+			return false
+		}
+		return true
+	}
+
+	shutdownCtx := graceful.GetManager().ShutdownContext()
+	poll := time.NewTicker(jobLogsPollInterval)
+	defer poll.Stop()
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+
+	for {
+		job, steps, lines, err := follower.Read(ctx)
+		if err != nil {
+			log.Error("Unable to read the logs of the job %d: %v", follower.JobID, err)
+			return
+		}
+		for _, step := range steps {
+			if !send(&eventsource.Event{Name: "step", Data: convert.ToActionJobStep(step.Index, step.ActionTaskStep)}) {
+				return
+			}
+		}
+		for _, line := range lines {
+			if !send(&eventsource.Event{
+				Name: "log",
+				ID:   fmt.Sprintf("%d-%d", follower.TaskID, line.Index+1),
+				Data: &api.ActionJobLogLine{
+					Step:    line.Step,
+					Line:    line.Index + 1,
+					Time:    line.Row.Time.AsTime(),
+					Content: line.Row.Content,
+				},
+			}) {
+				return
+			}
+		}
+		// the job is done without a task when it's cancelled or skipped before running
+		if job.Status.IsDone() {
+			apiJob, err := convert.ToActionRunJob(job)
+			if err != nil {
+				log.Error("ToActionRunJob: %v", err)
+				return
+			}
+			if send(&eventsource.Event{Name: "end", Data: apiJob}) {
+				ctx.Resp.Flush()
+			}
+			return
+		}
+		ctx.Resp.Flush()
+
+		select {
+		case <-poll.C:
+		case <-ping.C:
+			if !send(&eventsource.Event{Name: "ping"}) {
+				return
+			}
+			ctx.Resp.Flush()
+		case <-ctx.Done():
+			return
+		case <-shutdownCtx.Done():
+			return
+		}
+	}
+}
+
+// DownloadActionRunLogs downloads the logs of the jobs of a run as a zip file
+func DownloadActionRunLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{id}/logs repository DownloadActionRunLogs
+	// ---
+	// summary: Download the logs of the jobs of an action run as a zip file
+	// description: The zip file has a file with the whole logs of each started job, named after its position in the run
+	//   and its name, and a directory of the same name with a file of the logs of each step of the job.
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the zip file of the logs
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getActionRun(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.SetServeHeaders(&context.ServeHeaderOptions{
+		Filename:    fmt.Sprintf("%s-%d-logs.zip", workflowName(run), run.Index),
+		ContentType: "application/zip",
+	})
+	if err := actions_service.WriteRunLogsZip(ctx, ctx.Resp, run); err != nil {
+		ctx.Error(http.StatusInternalServerError, "WriteRunLogsZip", err)
+	}
+}
+
+// workflowName returns the name of the workflow file of the run without its extension
+func workflowName(run *actions_model.ActionRun) string {
+	name := run.WorkflowID
+	if p := strings.Index(name, "."); p > 0 {
+		name = name[0:p]
+	}
+	return name
+}
@@ -0,0 +1,207 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// RerunActionRun reruns all the jobs of a run
+func RerunActionRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{id}/rerun repository RerunActionRun
+	// ---
+	// summary: Rerun all the jobs of a done action run, on the same commit and with the same inputs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRun(ctx)
+	if ctx.Written() {
+		return
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+
+	rerunActionJobs(ctx, run, jobs)
+}
+
+// RerunFailedActionRunJobs reruns the failed jobs of a run and the jobs depending on them
+func RerunFailedActionRunJobs(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{id}/rerun-failed-jobs repository RerunFailedActionRunJobs
+	// ---
+	// summary: Rerun the failed or cancelled jobs of a done action run and the jobs depending on them
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getActionRun(ctx)
+	if ctx.Written() {
+		return
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+
+	rerunJobs := actions_service.GetFailedRerunJobs(jobs)
+	if len(rerunJobs) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the run has no failed jobs")
+		return
+	}
+	rerunActionJobs(ctx, run, rerunJobs)
+}
+
+// RerunActionJob reruns a job and the jobs depending on it
+func RerunActionJob(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/jobs/{job_id}/rerun repository RerunActionJob
+	// ---
+	// summary: Rerun an action job of a done run and the jobs depending on it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	job := getActionRunJob(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := job.LoadRun(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRun", err)
+		return
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, job.RunID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+	for _, j := range jobs {
+		if j.ID == job.ID {
+			job = j
+			break
+		}
+	}
+
+	rerunActionJobs(ctx, job.Run, actions_service.GetAllRerunJobs(job, jobs))
+}
+
+// rerunActionJobs reruns the jobs of the run and responds with the run, only the done runs can be rerun
+func rerunActionJobs(ctx *context.APIContext, run *actions_model.ActionRun, rerunJobs []*actions_model.ActionRunJob) {
+	if !run.Status.IsDone() {
+		ctx.Error(http.StatusConflict, "", "the run isn't done")
+		return
+	}
+	cfg := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions).ActionsConfig()
+	if cfg.IsWorkflowDisabled(run.WorkflowID) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the workflow of the run is disabled")
+		return
+	}
+
+	if err := actions_service.RerunJobs(ctx, run, rerunJobs); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "RerunJobs", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RerunJobs", err)
+		}
+		return
+	}
+
+	run, err := actions_model.GetRunByID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		return
+	}
+	run.Repo = ctx.Repo.Repository
+	respondActionRunWithJobs(ctx, http.StatusOK, run)
+}
@@ -0,0 +1,246 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+func toActionSchedule(ctx *context.APIContext, entry *actions_service.ScheduleEntry) (*api.ActionSchedule, error) {
+	res := &api.ActionSchedule{
+		ID:               entry.ID,
+		WorkflowID:       entry.Schedule.WorkflowID,
+		Cron:             entry.Spec,
+		Paused:           entry.Paused,
+		WorkflowDisabled: entry.WorkflowDisabled,
+	}
+	if next := entry.NextRunTime(); !next.IsZero() {
+		res.NextRunAt = &next
+	}
+	if entry.Prev != 0 {
+		prev := entry.Prev.AsLocalTime()
+		res.LastRunAt = &prev
+	}
+	if entry.LastRun != nil {
+		entry.LastRun.Repo = ctx.Repo.Repository
+		lastRun, err := convert.ToActionRun(ctx, entry.LastRun)
+		if err != nil {
+			return nil, err
+		}
+		res.LastRun = lastRun
+	}
+	return res, nil
+}
+
+// getActionScheduleEntry returns the schedule entry of the repository from the path, it writes the error response if it fails
+func getActionScheduleEntry(ctx *context.APIContext) *actions_service.ScheduleEntry {
+	entry, err := actions_service.GetScheduleEntry(ctx, ctx.Repo.Repository, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetScheduleEntry", err)
+		}
+		return nil
+	}
+	return entry
+}
+
+func respondActionSchedule(ctx *context.APIContext, entry *actions_service.ScheduleEntry) {
+	res, err := toActionSchedule(ctx, entry)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toActionSchedule", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ListActionSchedules lists the entries of the schedule triggers of the workflows of the default branch
+func ListActionSchedules(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/schedules repository ListActionSchedules
+	// ---
+	// summary: List the entries of the schedule triggers of the workflows of the default branch of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionScheduleList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	entries, err := actions_service.ListScheduleEntries(ctx, ctx.Repo.Repository)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ListScheduleEntries", err)
+		}
+		return
+	}
+
+	res := make([]*api.ActionSchedule, 0, len(entries))
+	for _, entry := range entries {
+		schedule, err := toActionSchedule(ctx, entry)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "toActionSchedule", err)
+			return
+		}
+		res = append(res, schedule)
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// PauseActionSchedule pauses an entry of the schedule trigger of a workflow
+func PauseActionSchedule(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/schedules/{id}/pause repository PauseActionSchedule
+	// ---
+	// summary: Pause an entry of the schedule trigger of a workflow, it doesn't create runs until it's resumed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the schedule entry
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionSchedule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setActionSchedulePaused(ctx, true)
+}
+
+// ResumeActionSchedule resumes a paused entry of the schedule trigger of a workflow
+func ResumeActionSchedule(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/schedules/{id}/pause repository ResumeActionSchedule
+	// ---
+	// summary: Resume a paused entry of the schedule trigger of a workflow, it creates its next run at its next slot
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the schedule entry
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionSchedule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setActionSchedulePaused(ctx, false)
+}
+
+func setActionSchedulePaused(ctx *context.APIContext, paused bool) {
+	entry := getActionScheduleEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.SetScheduleEntryPaused(ctx, ctx.Repo.Repository, entry, paused); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetScheduleEntryPaused", err)
+		return
+	}
+	respondActionSchedule(ctx, entry)
+}
+
+// RunActionSchedule runs the workflow of an entry of a schedule trigger at once
+func RunActionSchedule(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/schedules/{id}/runs repository RunActionSchedule
+	// ---
+	// summary: Run the workflow of an entry of a schedule trigger at once, even if the entry is paused
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the schedule entry
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ActionRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	entry := getActionScheduleEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+	run, err := actions_service.RunScheduleEntry(ctx, ctx.Doer, entry)
+	if err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "RunScheduleEntry", err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "RunScheduleEntry", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RunScheduleEntry", err)
+		}
+		return
+	}
+	run.Repo = ctx.Repo.Repository
+
+	respondActionRunWithJobs(ctx, http.StatusCreated, run)
+}
@@ -0,0 +1,339 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetActionEffectiveVariables returns the variables and the secrets the workflows of a repository get
+func GetActionEffectiveVariables(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/effective-variables repository getRepoActionEffectiveVariables
+	// ---
+	// summary: Get the variables and the secrets the workflows of a repository get, with the scope each one is defined at
+	// description: The variables and the secrets of the instance are overridden by the ones of the owner of the repository,
+	//   overridden by the ones of the repository, overridden by the ones of the environment of the job if it has one.
+	//   The values of the secrets are never returned.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment
+	//   in: query
+	//   description: the environment of the jobs to resolve the variables and the secrets for
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionEffectiveVariables"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	environment := ctx.FormTrim("environment")
+	variables, err := actions_service.ResolveEffectiveVariables(ctx, ctx.Repo.Repository, environment)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ResolveEffectiveVariables", err)
+		return
+	}
+	secrets, err := actions_service.ResolveEffectiveSecrets(ctx, ctx.Repo.Repository, environment)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ResolveEffectiveSecrets", err)
+		return
+	}
+
+	effective := &api.ActionEffectiveVariables{Warnings: []string{}}
+	effective.Variables = toActionEffectiveVariables(variables, "variable", &effective.Warnings)
+	effective.Secrets = toActionEffectiveVariables(secrets, "secret", &effective.Warnings)
+	ctx.JSON(http.StatusOK, effective)
+}
+
+func toActionEffectiveVariables(variables []*actions_service.EffectiveVariable, kind string, warnings *[]string) []*api.ActionEffectiveVariable {
+	result := make([]*api.ActionEffectiveVariable, 0, len(variables))
+	for _, v := range variables {
+		overrides := make([]string, 0, len(v.Overrides))
+		for _, scope := range v.Overrides {
+			overrides = append(overrides, scope.String())
+		}
+		if len(overrides) > 0 {
+			*warnings = append(*warnings, fmt.Sprintf("the %s %s of the %s scope overrides the one of the %s scope",
+				kind, v.Name, v.Scope, strings.Join(overrides, " and ")))
+		}
+		result = append(result, &api.ActionEffectiveVariable{
+			Name:      v.Name,
+			Data:      v.Data,
+			Scope:     v.Scope.String(),
+			Overrides: overrides,
+		})
+	}
+	return result
+}
+
+// ListActionEnvironmentSecrets lists the secrets of an environment of a repository
+func ListActionEnvironmentSecrets(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/environments/{environment}/secrets repository repoListActionEnvironmentSecrets
+	// ---
+	// summary: List the secrets of an environment of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListActionsSecrets(ctx, 0, ctx.Repo.Repository.ID, ctx.PathParam("environment"))
+}
+
+// CreateOrUpdateActionEnvironmentSecret creates or updates a secret of an environment of a repository
+func CreateOrUpdateActionEnvironmentSecret(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/environments/{environment}/secrets/{secretname} repository updateRepoActionEnvironmentSecret
+	// ---
+	// summary: Create or update a secret of an environment of a repository, which overrides the secrets of the repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateSecretOption"
+	// responses:
+	//   "201":
+	//     description: response when creating a secret
+	//   "204":
+	//     description: response when updating a secret
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.CreateOrUpdateActionsSecret(ctx, 0, ctx.Repo.Repository.ID, ctx.PathParam("environment"))
+}
+
+// DeleteActionEnvironmentSecret deletes a secret of an environment of a repository
+func DeleteActionEnvironmentSecret(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/environments/{environment}/secrets/{secretname} repository deleteRepoActionEnvironmentSecret
+	// ---
+	// summary: Delete a secret of an environment of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: the secret has been deleted
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteActionsSecret(ctx, 0, ctx.Repo.Repository.ID, ctx.PathParam("environment"))
+}
+
+// ListActionEnvironmentVariables lists the variables of an environment of a repository
+func ListActionEnvironmentVariables(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/environments/{environment}/variables repository repoListActionEnvironmentVariables
+	// ---
+	// summary: List the variables of an environment of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VariableList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListActionsVariables(ctx, 0, ctx.Repo.Repository.ID, ctx.PathParam("environment"))
+}
+
+// CreateOrUpdateActionEnvironmentVariable creates or updates a variable of an environment of a repository
+func CreateOrUpdateActionEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/environments/{environment}/variables/{variablename} repository updateRepoActionEnvironmentVariable
+	// ---
+	// summary: Create or update a variable of an environment of a repository, which overrides the variables of the repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateVariableOption"
+	// responses:
+	//   "201":
+	//     description: response when creating a variable
+	//   "204":
+	//     description: response when updating a variable
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.CreateOrUpdateActionsVariable(ctx, 0, ctx.Repo.Repository.ID, ctx.PathParam("environment"))
+}
+
+// DeleteActionEnvironmentVariable deletes a variable of an environment of a repository
+func DeleteActionEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/environments/{environment}/variables/{variablename} repository deleteRepoActionEnvironmentVariable
+	// ---
+	// summary: Delete a variable of an environment of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repository
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repository
+	//   type: string
+	//   required: true
+	// - name: environment
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: the variable has been deleted
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteActionsVariable(ctx, 0, ctx.Repo.Repository.ID, ctx.PathParam("environment"))
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetBisect gets the bisect session of the authenticated user in a repository
+func GetBisect(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/bisect repository repoGetBisect
+	// ---
+	// summary: Get the bisect session of the authenticated user in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BisectSession"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	session, err := repo_model.GetRepoBisectSession(ctx, ctx.Repo.Repository.ID, ctx.Doer.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoBisectSession", err)
+		return
+	} else if session == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToBisectSession(session))
+}
+
+// StartBisect starts a bisect session of the authenticated user in a repository
+func StartBisect(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/bisect repository repoStartBisect
+	// ---
+	// summary: Start a bisect session to find the commit which introduced a bug, it replaces the previous session of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/StartBisectOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/BisectSession"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.StartBisectOption)
+	session, err := repo_service.StartBisect(ctx, ctx.Repo.GitRepo, ctx.Repo.Repository, ctx.Doer, form.Bad, form.Good, form.Path)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "StartBisect", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToBisectSession(session))
+}
+
+// BisectVerdict records the result of testing a commit in the bisect session of the authenticated user
+func BisectVerdict(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/bisect/verdict repository repoBisectVerdict
+	// ---
+	// summary: Mark a commit as good, bad or skipped and get the next commit to test
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BisectVerdictOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BisectSession"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.BisectVerdictOption)
+	session, err := repo_service.MarkBisectCommit(ctx, ctx.Repo.GitRepo, ctx.Repo.Repository, ctx.Doer, form.Commit, repo_service.BisectVerdict(form.Verdict))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "MarkBisectCommit", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToBisectSession(session))
+}
+
+// ResetBisect ends the bisect session of the authenticated user in a repository
+func ResetBisect(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/bisect repository repoResetBisect
+	// ---
+	// summary: End the bisect session of the authenticated user in a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := repo_service.ResetBisect(ctx, ctx.Repo.Repository, ctx.Doer); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ResetBisect", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
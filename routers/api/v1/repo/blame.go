@@ -0,0 +1,79 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/services/context"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+// GetBlame returns the blame of a file of a repository
+func GetBlame(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/blame/{ref}/{filepath} repository repoGetBlame
+	// ---
+	// summary: Get the blame of a file of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: the name of the commit/branch/tag
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to blame
+	//   type: string
+	//   required: true
+	// - name: bypass_blame_ignore
+	//   in: query
+	//   description: do not ignore the revisions listed in .git-blame-ignore-revs
+	//   type: boolean
+	// - name: lines
+	//   in: query
+	//   description: include the content of the lines in the hunks
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BlameResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.Repository.IsEmpty || ctx.Repo.TreePath == "" {
+		ctx.NotFound()
+		return
+	}
+
+	blame, err := files_service.GetBlame(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.Repo.Commit, ctx.Repo.TreePath, files_service.GetBlameOptions{
+		BypassBlameIgnore: ctx.FormBool("bypass_blame_ignore"),
+		IncludeLines:      ctx.FormBool("lines"),
+	})
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetBlame", err)
+		} else if models.IsErrFilePathInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "GetBlame", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlame", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, blame)
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
@@ -19,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/optional"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
@@ -58,6 +60,12 @@ func GetBranch(ctx *context.APIContext) {
 
 	branchName := ctx.PathParam("*")
 
+	// branch names may contain slashes so the history is matched as a suffix of the wildcard
+	if strings.HasSuffix(branchName, "/history") && !ctx.Repo.GitRepo.IsBranchExist(branchName) {
+		ListBranchHistory(ctx)
+		return
+	}
+
 	branch, err := ctx.Repo.GitRepo.GetBranch(branchName)
 	if err != nil {
 		if git.IsErrBranchNotExist(err) {
@@ -296,6 +304,284 @@ func CreateBranch(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, br)
 }
 
+// RebaseBranch rebases a branch of a repository on to another branch
+func RebaseBranch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/branches/{branch}/rebase repository repoRebaseBranch
+	// ---
+	// summary: Rebase a branch on to another branch
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: branch to rebase
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RebaseBranchOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Branch"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/RebaseBranchConflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	branchName, _ := strings.CutSuffix(ctx.PathParam("*"), "/rebase")
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+
+	if ctx.Repo.Repository.IsMirror {
+		ctx.Error(http.StatusForbidden, "", "Git Repository is a mirror.")
+		return
+	}
+
+	opt := web.GetForm(ctx).(*api.RebaseBranchOption)
+	onto := opt.Onto
+	if onto == "" {
+		onto = ctx.Repo.Repository.DefaultBranch
+	}
+
+	if err := repo_service.RebaseBranch(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, branchName, onto); err != nil {
+		var conflictErr models.ErrRebaseConflicts
+		switch {
+		case errors.As(err, &conflictErr):
+			ctx.JSON(http.StatusConflict, api.RebaseBranchConflict{
+				Message:         fmt.Sprintf("rebase of %s on to %s failed because of conflicts", branchName, onto),
+				CommitSHA:       conflictErr.CommitSHA,
+				ConflictedFiles: conflictErr.ConflictedFiles,
+			})
+		case git.IsErrBranchNotExist(err):
+			ctx.NotFound(err)
+		case errors.Is(err, git_model.ErrBranchIsProtected):
+			ctx.Error(http.StatusForbidden, "IsProtectedBranch", fmt.Errorf("branch protected"))
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "RebaseBranch", err)
+		case git.IsErrPushOutOfDate(err):
+			ctx.Error(http.StatusConflict, "RebaseBranch", "the branch was updated during the rebase")
+		case git.IsErrPushRejected(err):
+			ctx.Error(http.StatusForbidden, "RebaseBranch", err.(*git.ErrPushRejected).Message)
+		default:
+			ctx.Error(http.StatusInternalServerError, "RebaseBranch", err)
+		}
+		return
+	}
+
+	branch, err := ctx.Repo.GitRepo.GetBranch(branchName)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranch", err)
+		return
+	}
+
+	commit, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+
+	branchProtection, err := git_model.GetFirstMatchProtectedBranchRule(ctx, ctx.Repo.Repository.ID, branch.Name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranchProtection", err)
+		return
+	}
+
+	br, err := convert.ToBranch(ctx, ctx.Repo.Repository, branch.Name, commit, branchProtection, ctx.Doer, ctx.Repo.IsAdmin())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "convert.ToBranch", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, br)
+}
+
+// ListBranchHistory lists the updates of a branch recorded in its journal
+func ListBranchHistory(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branches/{branch}/history repository repoListBranchHistory
+	// ---
+	// summary: List the updates of a branch, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: branch to list the updates of, it may have been deleted
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchUpdateList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !ctx.IsUserRepoAdmin() && !ctx.IsUserSiteAdmin() {
+		ctx.Error(http.StatusForbidden, "ListBranchHistory", "user should be an owner or a collaborator with admin write of a repository")
+		return
+	}
+
+	branchName, _ := strings.CutSuffix(ctx.PathParam("*"), "/history")
+	listOptions := utils.GetListOptions(ctx)
+	refLogs, total, err := db.FindAndCount[git_model.RefUpdateLog](ctx, git_model.FindRefUpdateLogOptions{
+		ListOptions: listOptions,
+		RepoID:      ctx.Repo.Repository.ID,
+		RefName:     git.RefNameFromBranch(branchName),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRefUpdateLogs", err)
+		return
+	}
+	if err := git_model.RefUpdateLogList(refLogs).LoadPusher(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadPusher", err)
+		return
+	}
+
+	updates := make([]*api.BranchUpdate, 0, len(refLogs))
+	for _, refLog := range refLogs {
+		updates = append(updates, convert.ToBranchUpdate(ctx, refLog, ctx.Doer))
+	}
+
+	ctx.SetLinkHeader(int(total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, updates)
+}
+
+// RestoreBranch restores a branch to a previous position recorded in its journal
+func RestoreBranch(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/branches/{branch}/history repository repoRestoreBranch
+	// ---
+	// summary: Restore a branch to a previous position, e.g. after a bad force-push
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: branch to restore, it may have been deleted
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RestoreBranchOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Branch"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	branchName, _ := strings.CutSuffix(ctx.PathParam("*"), "/history")
+
+	if ctx.Repo.Repository.IsMirror {
+		ctx.Error(http.StatusForbidden, "", "Git Repository is a mirror.")
+		return
+	}
+
+	opt := web.GetForm(ctx).(*api.RestoreBranchOption)
+	if err := repo_service.RestoreBranch(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, branchName, opt.CommitID); err != nil {
+		switch {
+		case git.IsErrNotExist(err):
+			ctx.NotFound(err)
+		case errors.Is(err, git_model.ErrBranchIsProtected):
+			ctx.Error(http.StatusForbidden, "IsProtectedBranch", fmt.Errorf("branch protected"))
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "RestoreBranch", err)
+		case git.IsErrPushOutOfDate(err):
+			ctx.Error(http.StatusConflict, "RestoreBranch", "the branch was updated during the restoration")
+		case git.IsErrPushRejected(err):
+			ctx.Error(http.StatusForbidden, "RestoreBranch", err.(*git.ErrPushRejected).Message)
+		default:
+			ctx.Error(http.StatusInternalServerError, "RestoreBranch", err)
+		}
+		return
+	}
+
+	branch, err := ctx.Repo.GitRepo.GetBranch(branchName)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranch", err)
+		return
+	}
+
+	commit, err := branch.GetCommit()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+
+	branchProtection, err := git_model.GetFirstMatchProtectedBranchRule(ctx, ctx.Repo.Repository.ID, branch.Name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBranchProtection", err)
+		return
+	}
+
+	br, err := convert.ToBranch(ctx, ctx.Repo.Repository, branch.Name, commit, branchProtection, ctx.Doer, ctx.Repo.IsAdmin())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "convert.ToBranch", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, br)
+}
+
 // ListBranches list all the branches of a repository
 func ListBranches(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/branches repository repoListBranches
@@ -602,6 +888,12 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		}
 	}
 
+	statusCheckContexts, err := git_model.ValidateStatusCheckContexts(form.StatusCheckContexts)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ValidateStatusCheckContexts", err)
+		return
+	}
+
 	protectBranch = &git_model.ProtectedBranch{
 		RepoID:                        ctx.Repo.Repository.ID,
 		RuleName:                      ruleName,
@@ -610,7 +902,7 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		EnableMergeWhitelist:          form.EnableMergeWhitelist,
 		WhitelistDeployKeys:           form.EnablePush && form.EnablePushWhitelist && form.PushWhitelistDeployKeys,
 		EnableStatusCheck:             form.EnableStatusCheck,
-		StatusCheckContexts:           form.StatusCheckContexts,
+		StatusCheckContexts:           statusCheckContexts,
 		EnableApprovalsWhitelist:      form.EnableApprovalsWhitelist,
 		RequiredApprovals:             requiredApprovals,
 		BlockOnRejectedReviews:        form.BlockOnRejectedReviews,
@@ -618,9 +910,11 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		DismissStaleApprovals:         form.DismissStaleApprovals,
 		IgnoreStaleApprovals:          form.IgnoreStaleApprovals,
 		RequireSignedCommits:          form.RequireSignedCommits,
+		RequireSignoff:                form.RequireSignoff,
 		ProtectedFilePatterns:         form.ProtectedFilePatterns,
 		UnprotectedFilePatterns:       form.UnprotectedFilePatterns,
 		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		RequireCodeOwnerApproval:      form.RequireCodeOwnerApproval,
 	}
 
 	err = git_model.UpdateProtectBranch(ctx, ctx.Repo.Repository, protectBranch, git_model.WhitelistOptions{
@@ -765,7 +1059,12 @@ func EditBranchProtection(ctx *context.APIContext) {
 	}
 
 	if form.StatusCheckContexts != nil {
-		protectBranch.StatusCheckContexts = form.StatusCheckContexts
+		statusCheckContexts, err := git_model.ValidateStatusCheckContexts(form.StatusCheckContexts)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "ValidateStatusCheckContexts", err)
+			return
+		}
+		protectBranch.StatusCheckContexts = statusCheckContexts
 	}
 
 	if form.RequiredApprovals != nil && *form.RequiredApprovals >= 0 {
@@ -796,6 +1095,10 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.RequireSignedCommits = *form.RequireSignedCommits
 	}
 
+	if form.RequireSignoff != nil {
+		protectBranch.RequireSignoff = *form.RequireSignoff
+	}
+
 	if form.ProtectedFilePatterns != nil {
 		protectBranch.ProtectedFilePatterns = *form.ProtectedFilePatterns
 	}
@@ -808,6 +1111,10 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
 	}
 
+	if form.RequireCodeOwnerApproval != nil {
+		protectBranch.RequireCodeOwnerApproval = *form.RequireCodeOwnerApproval
+	}
+
 	var whitelistUsers []int64
 	if form.PushWhitelistUsernames != nil {
 		whitelistUsers, err = user_model.GetUserIDsByNames(ctx, form.PushWhitelistUsernames, false)
@@ -0,0 +1,185 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	git_model "code.gitea.io/gitea/models/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+)
+
+// getBranchProtectionFromPath returns the branch protection of the repository named in the path,
+// it writes the error response if there is none.
+func getBranchProtectionFromPath(ctx *context.APIContext) *git_model.ProtectedBranch {
+	bp, err := git_model.GetProtectedBranchRuleByName(ctx, ctx.Repo.Repository.ID, ctx.PathParam(":name"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedBranchRuleByName", err)
+		return nil
+	}
+	if bp == nil || bp.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return bp
+}
+
+func writeBranchProtectionStatusChecks(ctx *context.APIContext, bp *git_model.ProtectedBranch) {
+	recentContexts, err := git_model.FindRepoRecentCommitStatusContexts(ctx, ctx.Repo.Repository.ID, 7*24*time.Hour)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoRecentCommitStatusContexts", err)
+		return
+	}
+
+	statusCheckContexts := bp.StatusCheckContexts
+	if statusCheckContexts == nil {
+		statusCheckContexts = []string{}
+	}
+	if recentContexts == nil {
+		recentContexts = []string{}
+	}
+	ctx.JSON(http.StatusOK, &api.BranchProtectionStatusChecks{
+		EnableStatusCheck:   bp.EnableStatusCheck,
+		StatusCheckContexts: statusCheckContexts,
+		RecentContexts:      recentContexts,
+	})
+}
+
+// GetBranchProtectionStatusChecks gets the required status checks of a branch protection
+func GetBranchProtectionStatusChecks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branch_protections/{name}/status_checks repository repoGetBranchProtectionStatusChecks
+	// ---
+	// summary: Get the required status checks of a branch protection
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of protected branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionStatusChecks"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	bp := getBranchProtectionFromPath(ctx)
+	if ctx.Written() {
+		return
+	}
+	writeBranchProtectionStatusChecks(ctx, bp)
+}
+
+// EditBranchProtectionStatusChecks replaces the required status checks of a branch protection
+func EditBranchProtectionStatusChecks(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/branch_protections/{name}/status_checks repository repoEditBranchProtectionStatusChecks
+	// ---
+	// summary: Replace the required status checks of a branch protection
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of protected branch
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditBranchProtectionStatusChecksOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtectionStatusChecks"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	form := web.GetForm(ctx).(*api.EditBranchProtectionStatusChecksOption)
+	bp := getBranchProtectionFromPath(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := git_model.UpdateProtectBranchStatusChecks(ctx, ctx.Repo.Repository, bp, form.StatusCheckContexts); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateProtectBranchStatusChecks", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateProtectBranchStatusChecks", err)
+		}
+		return
+	}
+	writeBranchProtectionStatusChecks(ctx, bp)
+}
+
+// DeleteBranchProtectionStatusChecks disables the required status checks of a branch protection
+func DeleteBranchProtectionStatusChecks(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/branch_protections/{name}/status_checks repository repoDeleteBranchProtectionStatusChecks
+	// ---
+	// summary: Remove all the required status checks of a branch protection
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of protected branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	bp := getBranchProtectionFromPath(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := git_model.UpdateProtectBranchStatusChecks(ctx, ctx.Repo.Repository, bp, nil); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateProtectBranchStatusChecks", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// GetCommitReactions list reactions of a commit
+func GetCommitReactions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/commits/{sha}/reactions repository repoGetCommitReactions
+	// ---
+	// summary: Get a list of reactions of a commit
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReactionList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	commitID := getReactionCommitID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reactions, count, err := issues_model.FindCommitReactions(ctx, ctx.Repo.Repository.ID, commitID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindCommitReactions", err)
+		return
+	}
+	_, err = reactions.LoadUsers(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReactionList.LoadUsers()", err)
+		return
+	}
+
+	result := make([]api.Reaction, 0, len(reactions))
+	for _, r := range reactions {
+		result = append(result, api.Reaction{
+			User:     convert.ToUser(ctx, r.User, ctx.Doer),
+			Reaction: r.Type,
+			Created:  r.CreatedUnix.AsTime(),
+		})
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// PostCommitReaction add a reaction to a commit
+func PostCommitReaction(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/commits/{sha}/reactions repository repoPostCommitReaction
+	// ---
+	// summary: Add a reaction to a commit
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Reaction"
+	//   "201":
+	//     "$ref": "#/responses/Reaction"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	form := web.GetForm(ctx).(*api.EditReactionOption)
+	commitID := getReactionCommitID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	reaction, err := issue_service.CreateCommitReaction(ctx, ctx.Doer, ctx.Repo.Repository, commitID, form.Reaction)
+	if err != nil {
+		if issues_model.IsErrForbiddenIssueReaction(err) || errors.Is(err, user_model.ErrBlockedUser) {
+			ctx.Error(http.StatusForbidden, err.Error(), err)
+		} else if issues_model.IsErrReactionAlreadyExist(err) {
+			ctx.JSON(http.StatusOK, api.Reaction{
+				User:     convert.ToUser(ctx, ctx.Doer, ctx.Doer),
+				Reaction: reaction.Type,
+				Created:  reaction.CreatedUnix.AsTime(),
+			})
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateCommitReaction", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, api.Reaction{
+		User:     convert.ToUser(ctx, ctx.Doer, ctx.Doer),
+		Reaction: reaction.Type,
+		Created:  reaction.CreatedUnix.AsTime(),
+	})
+}
+
+// DeleteCommitReaction remove a reaction from a commit
+func DeleteCommitReaction(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/git/commits/{sha}/reactions repository repoDeleteCommitReaction
+	// ---
+	// summary: Remove a reaction from a commit
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	form := web.GetForm(ctx).(*api.EditReactionOption)
+	commitID := getReactionCommitID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.DeleteCommitReaction(ctx, ctx.Doer.ID, ctx.Repo.Repository.ID, commitID, form.Reaction); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteCommitReaction", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// getReactionCommitID returns the full id of the commit of the path, the reactions are recorded on it
+func getReactionCommitID(ctx *context.APIContext) string {
+	sha := ctx.PathParam(":sha")
+	if !git.IsValidRefPattern(sha) {
+		ctx.NotFound(sha)
+		return ""
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(sha)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return ""
+	}
+	return commit.ID.String()
+}
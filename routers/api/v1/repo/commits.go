@@ -9,10 +9,12 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	issues_model "code.gitea.io/gitea/models/issues"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitgraph"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -55,6 +57,10 @@ func GetSingleCommit(ctx *context.APIContext) {
 	//   in: query
 	//   description: include a list of affected files for every commit (disable for speedup, default 'true')
 	//   type: boolean
+	// - name: reactions
+	//   in: query
+	//   description: include the number of reactions of each type for every commit (disable for speedup, default 'true')
+	//   type: boolean
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Commit"
@@ -129,6 +135,10 @@ func GetAllCommits(ctx *context.APIContext) {
 	//   in: query
 	//   description: include a list of affected files for every commit (disable for speedup, default 'true')
 	//   type: boolean
+	// - name: reactions
+	//   in: query
+	//   description: include the number of reactions of each type for every commit (disable for speedup, default 'true')
+	//   type: boolean
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -374,3 +384,119 @@ func GetCommitPullRequest(ctx *context.APIContext) {
 	}
 	ctx.JSON(http.StatusOK, convert.ToAPIPullRequest(ctx, pr, ctx.Doer))
 }
+
+// GetCommitGraph get a page of the commit graph of a repository
+func GetCommitGraph(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/commits/graph repository repoGetCommitGraph
+	// ---
+	// summary: Get a page of the commit graph of a repository to render its network of branches
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branches to list the commits of, the commits of all the refs are listed if empty
+	//   type: array
+	//   items:
+	//     type: string
+	// - name: hide_pr_refs
+	//   in: query
+	//   description: exclude the refs of the pull requests
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitGraph"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/EmptyRepository"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.JSON(http.StatusConflict, api.APIError{
+			Message: "Git Repository is empty.",
+			URL:     setting.API.SwaggerURL,
+		})
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.PageSize > setting.Git.CommitsRangeSize {
+		listOptions.PageSize = setting.Git.CommitsRangeSize
+	}
+	hidePRRefs := ctx.FormBool("hide_pr_refs")
+
+	branches := ctx.FormStrings("branch")
+	for i, branch := range branches {
+		if !ctx.Repo.GitRepo.IsBranchExist(branch) {
+			ctx.Error(http.StatusUnprocessableEntity, "IsBranchExist", fmt.Errorf("branch %q doesn't exist", branch))
+			return
+		}
+		branches[i] = git.BranchPrefix + branch
+	}
+
+	commitsCount, err := ctx.Repo.GetCommitGraphsCount(ctx, hidePRRefs, branches, nil)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommitGraphsCount", err)
+		return
+	}
+
+	dag, err := gitgraph.GetCommitDAG(ctx, ctx.Repo.GitRepo, gitgraph.DAGOptions{
+		Page:       listOptions.Page,
+		PageSize:   listOptions.PageSize,
+		HidePRRefs: hidePRRefs,
+		Branches:   branches,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommitDAG", err)
+		return
+	}
+
+	apiGraph := &api.CommitGraph{
+		Commits: make([]*api.CommitGraphNode, 0, len(dag.Commits)),
+		Lanes:   dag.Lanes,
+	}
+	for _, c := range dag.Commits {
+		node := &api.CommitGraphNode{
+			SHA:     c.Rev,
+			Lane:    c.Lane,
+			Parents: make([]*api.CommitGraphEdge, 0, len(c.Parents)),
+			Refs:    make([]string, 0, len(c.Refs)),
+			Author: &api.CommitUser{
+				Identity: api.Identity{Name: c.AuthorName, Email: c.AuthorEmail},
+				Date:     c.AuthorDate.Format(time.RFC3339),
+			},
+			Subject: c.Subject,
+		}
+		for _, edge := range c.Parents {
+			node.Parents = append(node.Parents, &api.CommitGraphEdge{SHA: edge.Parent, Lane: edge.Lane})
+		}
+		for _, ref := range c.Refs {
+			node.Refs = append(node.Refs, ref.Name)
+		}
+		apiGraph.Commits = append(apiGraph.Commits, node)
+	}
+
+	ctx.SetLinkHeader(int(commitsCount), listOptions.PageSize)
+	ctx.SetTotalCountHeader(commitsCount)
+	ctx.JSON(http.StatusOK, apiGraph)
+}
@@ -5,11 +5,17 @@ package repo
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 )
@@ -34,14 +40,68 @@ func CompareDiff(ctx *context.APIContext) {
 	//   required: true
 	// - name: basehead
 	//   in: path
-	//   description: compare two branches or commits
+	//   description: compare two branches, tags or commit SHAs, "base...head" compares the head with the merge base, "base..head" compares the head with the base directly
 	//   type: string
 	//   required: true
+	// - name: path
+	//   in: query
+	//   description: limit the comparison to the commits and files changing the given paths
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	// - name: rename_threshold
+	//   in: query
+	//   description: similarity percentage for detecting renamed files, 0 disables the detection, defaults to 50
+	//   type: integer
+	// - name: copy_threshold
+	//   in: query
+	//   description: similarity percentage for detecting copied files, 0 (default) disables the detection
+	//   type: integer
+	// - name: stats_only
+	//   in: query
+	//   description: only return the statistics of the comparison, without the commits and the files
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of the changed files, the files are paginated when it or the limit is given
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of the changed files
+	//   type: integer
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Compare"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	renameThreshold, copyThreshold := 50, 0
+	if ctx.FormString("rename_threshold") != "" {
+		renameThreshold = ctx.FormInt("rename_threshold")
+	}
+	if ctx.FormString("copy_threshold") != "" {
+		copyThreshold = ctx.FormInt("copy_threshold")
+	}
+	if renameThreshold < 0 || renameThreshold > 100 || copyThreshold < 0 || copyThreshold > 100 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "similarity thresholds must be between 0 and 100")
+		return
+	}
+	if copyThreshold > 0 && renameThreshold == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "copy detection requires rename detection")
+		return
+	}
+	paths := ctx.FormStrings("path")
+	if slices.Contains(paths, "") {
+		ctx.Error(http.StatusUnprocessableEntity, "", "paths can't be empty")
+		return
+	}
+	statsOnly := ctx.FormBool("stats_only")
+	// the files of huge diffs can be listed page by page
+	paginateFiles := ctx.FormString("page") != "" || ctx.FormString("limit") != ""
+	listOptions := utils.GetListOptions(ctx)
 
 	if ctx.Repo.GitRepo == nil {
 		gitRepo, err := gitrepo.OpenRepository(ctx, ctx.Repo.Repository)
@@ -55,11 +115,14 @@ func CompareDiff(ctx *context.APIContext) {
 
 	infoPath := ctx.PathParam("*")
 	infos := []string{ctx.Repo.Repository.DefaultBranch, ctx.Repo.Repository.DefaultBranch}
+	directComparison := false
 	if infoPath != "" {
 		infos = strings.SplitN(infoPath, "...", 2)
 		if len(infos) != 2 {
 			if infos = strings.SplitN(infoPath, "..", 2); len(infos) != 2 {
 				infos = []string{ctx.Repo.Repository.DefaultBranch, infoPath}
+			} else {
+				directComparison = true
 			}
 		}
 	}
@@ -67,19 +130,82 @@ func CompareDiff(ctx *context.APIContext) {
 	_, headGitRepo, ci, _, _ := parseCompareInfo(ctx, api.CreatePullRequestOption{
 		Base: infos[0],
 		Head: infos[1],
-	})
+	}, directComparison, true)
 	if ctx.Written() {
 		return
 	}
 	defer headGitRepo.Close()
 
+	baseCommitID := ci.MergeBase
+	if directComparison {
+		baseCommitID = ci.BaseCommitID
+	}
+
+	stats, err := headGitRepo.GetCompareFileStats(baseCommitID, ci.HeadCommitID, git.CompareFileStatsOptions{
+		Paths:           paths,
+		RenameThreshold: util.Iif(renameThreshold == 0, -1, renameThreshold),
+		CopyThreshold:   copyThreshold,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCompareFileStats", err)
+		return
+	}
+
+	commits := ci.Commits
+	if len(paths) > 0 {
+		commitIDs, err := headGitRepo.GetCommitIDsByPaths(baseCommitID+".."+ci.HeadCommitID, paths)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetCommitIDsByPaths", err)
+			return
+		}
+		commitIDSet := container.SetOf(commitIDs...)
+		commits = make([]*git.Commit, 0, len(commitIDs))
+		for _, commit := range ci.Commits {
+			if commitIDSet.Contains(commit.ID.String()) {
+				commits = append(commits, commit)
+			}
+		}
+	}
+
+	compare := &api.Compare{
+		TotalCommits:     len(commits),
+		Commits:          []*api.Commit{},
+		BaseCommit:       baseCommitID,
+		HeadCommit:       ci.HeadCommitID,
+		DirectComparison: directComparison,
+		TotalFiles:       len(stats),
+		Files:            []*api.ChangedFile{},
+		// very large diffs only return the statistics unless their files are paginated
+		StatsOnly: statsOnly || (!paginateFiles && setting.Git.MaxGitDiffFiles > 0 && len(stats) > setting.Git.MaxGitDiffFiles),
+	}
+	for _, stat := range stats {
+		compare.Additions += stat.Additions
+		compare.Deletions += stat.Deletions
+	}
+	if !compare.StatsOnly {
+		fileStats := stats
+		if paginateFiles {
+			skip, take := listOptions.GetSkipTake()
+			fileStats = stats[min(skip, len(stats)):min(skip+take, len(stats))]
+			ctx.SetLinkHeader(len(stats), listOptions.PageSize)
+			ctx.SetTotalCountHeader(int64(len(stats)))
+		}
+		for _, stat := range fileStats {
+			compare.Files = append(compare.Files, convert.ToChangedFileFromStat(stat, ctx.Repo.Repository, ci.HeadCommitID))
+		}
+	}
+	if statsOnly {
+		ctx.JSON(http.StatusOK, compare)
+		return
+	}
+
 	verification := ctx.FormString("verification") == "" || ctx.FormBool("verification")
 	files := ctx.FormString("files") == "" || ctx.FormBool("files")
 
-	apiCommits := make([]*api.Commit, 0, len(ci.Commits))
+	apiCommits := make([]*api.Commit, 0, len(commits))
 	userCache := make(map[string]*user_model.User)
-	for i := 0; i < len(ci.Commits); i++ {
-		apiCommit, err := convert.ToCommit(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, ci.Commits[i], userCache,
+	for i := 0; i < len(commits); i++ {
+		apiCommit, err := convert.ToCommit(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, commits[i], userCache,
 			convert.ToCommitOptions{
 				Stat:         true,
 				Verification: verification,
@@ -92,8 +218,6 @@ func CompareDiff(ctx *context.APIContext) {
 		apiCommits = append(apiCommits, apiCommit)
 	}
 
-	ctx.JSON(http.StatusOK, &api.Compare{
-		TotalCommits: len(ci.Commits),
-		Commits:      apiCommits,
-	})
+	compare.Commits = apiCommits
+	ctx.JSON(http.StatusOK, compare)
 }
@@ -0,0 +1,108 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+)
+
+func writeCustomPropertyValues(ctx *context.APIContext) {
+	values, err := repo_model.GetRepoCustomPropertyValues(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoCustomPropertyValues", err)
+		return
+	}
+
+	apiValues := make([]*api.CustomPropertyValue, 0, len(values))
+	for _, value := range values {
+		apiValues = append(apiValues, &api.CustomPropertyValue{Name: value.Name, Value: value.Value})
+	}
+	ctx.JSON(http.StatusOK, apiValues)
+}
+
+// GetCustomPropertyValues get the custom property values of a repository
+func GetCustomPropertyValues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/properties/values repository repoGetCustomPropertyValues
+	// ---
+	// summary: Get the values of the custom properties of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CustomPropertyValueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	writeCustomPropertyValues(ctx)
+}
+
+// UpdateCustomPropertyValues set the custom property values of a repository
+func UpdateCustomPropertyValues(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/properties/values repository repoUpdateCustomPropertyValues
+	// ---
+	// summary: Set the values of custom properties of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdateCustomPropertyValuesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CustomPropertyValueList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.UpdateCustomPropertyValuesOption)
+
+	values := make(map[string]string, len(form.Properties))
+	for _, prop := range form.Properties {
+		values[prop.Name] = prop.Value
+	}
+	if err := repo_model.SetRepoCustomPropertyValues(ctx, ctx.Repo.Repository, values); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetRepoCustomPropertyValues", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetRepoCustomPropertyValues", err)
+		}
+		return
+	}
+
+	writeCustomPropertyValues(ctx)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+// deferChangeFiles stores the change of files to be committed at the given time
+func deferChangeFiles(ctx *context.APIContext, opts *files_service.ChangeRepoFilesOptions, executeAt time.Time) {
+	if !canWriteFiles(ctx, opts.OldBranch) {
+		ctx.Error(http.StatusForbidden, "DeferChangeRepoFiles", repo_model.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.Doer.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+
+	deferredCommit, err := files_service.DeferChangeRepoFiles(ctx, ctx.Repo.Repository, ctx.Doer, opts, executeAt)
+	if err != nil {
+		handleCreateOrUpdateFileError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, convert.ToDeferredCommit(deferredCommit))
+}
+
+// getDeferredCommit returns the deferred commit of the repository given in the path, it responds 404 if it doesn't exist
+func getDeferredCommit(ctx *context.APIContext) *repo_model.DeferredCommit {
+	deferredCommit, err := repo_model.GetDeferredCommit(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("id"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDeferredCommit", err)
+		return nil
+	} else if deferredCommit == nil {
+		ctx.NotFound()
+		return nil
+	}
+	return deferredCommit
+}
+
+// ListDeferredCommits lists the changes of files scheduled to be committed in a repository
+func ListDeferredCommits(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/deferred_commits repository repoListDeferredCommits
+	// ---
+	// summary: List the changes of files scheduled to be committed at a later time, the next to be committed first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeferredCommitList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	deferredCommits, err := repo_model.FindDeferredCommits(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindDeferredCommits", err)
+		return
+	}
+	apiDeferredCommits := make([]*api.DeferredCommit, 0, len(deferredCommits))
+	for _, deferredCommit := range deferredCommits {
+		apiDeferredCommits = append(apiDeferredCommits, convert.ToDeferredCommit(deferredCommit))
+	}
+	ctx.JSON(http.StatusOK, apiDeferredCommits)
+}
+
+// GetDeferredCommit gets a change of files scheduled to be committed and its result
+func GetDeferredCommit(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/deferred_commits/{id} repository repoGetDeferredCommit
+	// ---
+	// summary: Get a change of files scheduled to be committed and its result
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the deferred commit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeferredCommit"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	deferredCommit := getDeferredCommit(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToDeferredCommit(deferredCommit))
+}
+
+// DeleteDeferredCommit cancels a change of files scheduled to be committed, or deletes its result
+func DeleteDeferredCommit(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/deferred_commits/{id} repository repoDeleteDeferredCommit
+	// ---
+	// summary: Cancel a change of files scheduled to be committed, or delete its result once it is done
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the deferred commit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	deferredCommit := getDeferredCommit(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := repo_model.DeleteDeferredCommit(ctx, deferredCommit.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteDeferredCommit", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/container"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// accessibleAPIRepos converts the repositories the doer can read the code of
+func accessibleAPIRepos(ctx *context.APIContext, repoIDs []int64) (map[int64]*api.Repository, error) {
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		return nil, err
+	}
+	apiRepos := make(map[int64]*api.Repository, len(repos))
+	for id, repo := range repos {
+		permission, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			return nil, err
+		}
+		if permission.CanRead(unit.TypeCode) {
+			apiRepos[id] = convert.ToRepo(ctx, repo, permission)
+		}
+	}
+	return apiRepos, nil
+}
+
+// ListDependencies lists the dependencies declared by the manifests of a repository
+func ListDependencies(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/dependencies repository repoListDependencies
+	// ---
+	// summary: List the dependencies declared by the manifest files on the default branch of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ecosystem
+	//   in: query
+	//   description: only list dependencies of this ecosystem (go, npm, pypi, composer)
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoDependencyList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	deps, total, err := db.FindAndCount[repo_model.RepoDependency](ctx, repo_model.FindRepoDependenciesOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		Ecosystem:   ctx.FormTrim("ecosystem"),
+		AllRepos:    true,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoDependencies", err)
+		return
+	}
+
+	providers, err := repo_model.FindRepoIDsByPackages(ctx, deps)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoIDsByPackages", err)
+		return
+	}
+	repoIDs := make(container.Set[int64])
+	for _, ids := range providers {
+		repoIDs.AddMultiple(ids...)
+	}
+	repoIDs.Remove(ctx.Repo.Repository.ID)
+	apiRepos, err := accessibleAPIRepos(ctx, repoIDs.Values())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "accessibleAPIRepos", err)
+		return
+	}
+
+	apiDeps := make([]*api.RepoDependency, 0, len(deps))
+	for _, d := range deps {
+		apiDep := &api.RepoDependency{
+			Manifest:      d.Manifest,
+			Ecosystem:     d.Ecosystem,
+			Name:          d.Name,
+			Version:       d.Version,
+			IsDev:         d.IsDev,
+			InternalRepos: []*api.Repository{},
+		}
+		for _, id := range providers[d.PackageKey()] {
+			if apiRepo, ok := apiRepos[id]; ok {
+				apiDep.InternalRepos = append(apiDep.InternalRepos, apiRepo)
+			}
+		}
+		apiDeps = append(apiDeps, apiDep)
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, apiDeps)
+}
+
+// ListDependents lists the repositories depending on the packages declared by a repository
+func ListDependents(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/dependents repository repoListDependents
+	// ---
+	// summary: List the repositories of this instance depending on the packages declared by a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoDependentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	manifests, err := repo_model.GetRepoManifests(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoManifests", err)
+		return
+	}
+
+	deps, total, err := db.FindAndCount[repo_model.RepoDependency](ctx, repo_model.FindRepoDependenciesOptions{
+		ListOptions:   utils.GetListOptions(ctx),
+		Packages:      manifests,
+		Doer:          ctx.Doer,
+		ExcludeRepoID: ctx.Repo.Repository.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoDependencies", err)
+		return
+	}
+
+	repoIDs := make(container.Set[int64], len(deps))
+	for _, d := range deps {
+		repoIDs.Add(d.RepoID)
+	}
+	apiRepos, err := accessibleAPIRepos(ctx, repoIDs.Values())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "accessibleAPIRepos", err)
+		return
+	}
+
+	apiDependents := make([]*api.RepoDependent, 0, len(deps))
+	for _, d := range deps {
+		apiRepo, ok := apiRepos[d.RepoID]
+		if !ok {
+			continue
+		}
+		apiDependents = append(apiDependents, &api.RepoDependent{
+			Repository: apiRepo,
+			Manifest:   d.Manifest,
+			Ecosystem:  d.Ecosystem,
+			Name:       d.Name,
+			Version:    d.Version,
+			IsDev:      d.IsDev,
+		})
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, apiDependents)
+}
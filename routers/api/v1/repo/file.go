@@ -6,11 +6,12 @@ package repo
 
 import (
 	"bytes"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"path"
 	"strings"
 	"time"
@@ -27,9 +28,11 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/context/upload"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
 	files_service "code.gitea.io/gitea/services/repository/files"
 )
@@ -274,11 +277,25 @@ func GetArchive(ctx *context.APIContext) {
 	//   description: the git reference for download with attached archive format (e.g. master.zip)
 	//   type: string
 	//   required: true
+	// - name: include
+	//   in: query
+	//   description: comma separated list of extra contents of a tar.gz archive, "lfs" for the LFS objects instead of their pointers
+	//                and "submodules" for the trees of the public submodules hosted on this instance.
+	//                Such archives are generated asynchronously, the status is returned until the archive can be downloaded.
+	//   type: string
+	// - name: path
+	//   in: query
+	//   description: limit the archive to a directory or a file of the repository, its path is kept in the archive
+	//   type: string
 	// responses:
 	//   200:
 	//     description: success
+	//   "202":
+	//     "$ref": "#/responses/RepoArchiveStatus"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	if ctx.Repo.GitRepo == nil {
 		gitRepo, err := gitrepo.OpenRepository(ctx, ctx.Repo.Repository)
@@ -290,11 +307,52 @@ func GetArchive(ctx *context.APIContext) {
 		defer gitRepo.Close()
 	}
 
-	archiveDownload(ctx)
+	archiveDownload(ctx, ctx.PathParam("*"))
 }
 
-func archiveDownload(ctx *context.APIContext) {
-	uri := ctx.PathParam("*")
+// GetBundle downloads a git bundle of a reference of a repository
+func GetBundle(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/bundle/{ref} repository repoGetBundle
+	// ---
+	// summary: Get a git bundle of a reference of a repository
+	// produces:
+	// - application/octet-stream
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: path
+	//   description: the name of the commit/branch/tag
+	//   type: string
+	//   required: true
+	// responses:
+	//   200:
+	//     description: success
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if ctx.Repo.GitRepo == nil {
+		gitRepo, err := gitrepo.OpenRepository(ctx, ctx.Repo.Repository)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+			return
+		}
+		ctx.Repo.GitRepo = gitRepo
+		defer gitRepo.Close()
+	}
+
+	archiveDownload(ctx, ctx.PathParam("*")+".bundle")
+}
+
+func archiveDownload(ctx *context.APIContext, uri string) {
 	aReq, err := archiver_service.NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri)
 	if err != nil {
 		if errors.Is(err, archiver_service.ErrUnknownArchiveFormat{}) {
@@ -307,6 +365,25 @@ func archiveDownload(ctx *context.APIContext) {
 		return
 	}
 
+	if err := aReq.SetInclude(ctx.FormString("include")); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "SetInclude", err)
+		return
+	}
+	if err := aReq.SetPath(ctx.Repo.GitRepo, ctx.FormString("path")); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetPath", err)
+		} else {
+			ctx.ServerError("SetPath", err)
+		}
+		return
+	}
+	if aReq.Include != "" {
+		archiveDownloadWithInclude(ctx, aReq)
+		return
+	}
+
 	archiver, err := aReq.Await(ctx)
 	if err != nil {
 		ctx.ServerError("archiver.Await", err)
@@ -316,14 +393,38 @@ func archiveDownload(ctx *context.APIContext) {
 	download(ctx, aReq.GetArchiveName(), archiver)
 }
 
+// archiveDownloadWithInclude downloads the archive if it is ready, otherwise it starts generating it and returns its status
+func archiveDownloadWithInclude(ctx *context.APIContext, aReq *archiver_service.ArchiveRequest) {
+	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID, aReq.Include, aReq.Path)
+	if err != nil {
+		ctx.ServerError("GetRepoArchiver", err)
+		return
+	}
+	if archiver != nil && archiver.Status == repo_model.ArchiverReady {
+		download(ctx, aReq.GetArchiveName(), archiver)
+		return
+	}
+
+	if err := archiver_service.StartArchive(aReq); err != nil {
+		ctx.ServerError("StartArchive", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, &api.RepoArchiveStatus{
+		Status:   "generating",
+		Progress: aReq.Progress(),
+	})
+}
+
 func download(ctx *context.APIContext, archiveName string, archiver *repo_model.RepoArchiver) {
 	downloadName := ctx.Repo.Repository.Name + "-" + archiveName
 
 	// Add nix format link header so tarballs lock correctly:
 	// https://github.com/nixos/nix/blob/56763ff918eb308db23080e560ed2ea3e00c80a7/doc/manual/src/protocols/tarball-fetcher.md
-	ctx.Resp.Header().Add("Link", fmt.Sprintf(`<%s/archive/%s.tar.gz?rev=%s>; rel="immutable"`,
-		ctx.Repo.Repository.APIURL(),
-		archiver.CommitID, archiver.CommitID))
+	immutableLink := fmt.Sprintf("%s/archive/%s.tar.gz?rev=%s", ctx.Repo.Repository.APIURL(), archiver.CommitID, archiver.CommitID)
+	if archiver.Path != "" {
+		immutableLink += "&path=" + url.QueryEscape(archiver.Path)
+	}
+	ctx.Resp.Header().Add("Link", fmt.Sprintf(`<%s>; rel="immutable"`, immutableLink))
 
 	rPath := archiver.RelativePath()
 	if setting.RepoArchive.Storage.ServeDirect() {
@@ -409,17 +510,20 @@ func canWriteFiles(ctx *context.APIContext, branch string) bool {
 		!ctx.Repo.Repository.IsArchived
 }
 
+// canSkipNotifications returns whether the doer may skip the webhooks and the Actions runs of its commits,
+// which requires the repository admin permission
+func canSkipNotifications(ctx *context.APIContext, opts *api.FileOptions) bool {
+	return (!opts.SkipWebhooks && !opts.SkipCI) || ctx.Repo.IsAdmin() || ctx.IsUserSiteAdmin()
+}
+
 // canReadFiles returns true if repository is readable and user has proper access level.
 func canReadFiles(r *context.Repository) bool {
 	return r.Permission.CanRead(unit.TypeCode)
 }
 
-func base64Reader(s string) (io.ReadSeeker, error) {
-	b, err := base64.StdEncoding.DecodeString(s)
-	if err != nil {
-		return nil, err
-	}
-	return bytes.NewReader(b), nil
+// contentEncoding returns the encoding of the content given to the API, which is base64 by default
+func contentEncoding(encoding string) string {
+	return util.IfZero(encoding, files_service.ContentEncodingBase64)
 }
 
 // ChangeFiles handles API call for modifying multiple files
@@ -450,16 +554,29 @@ func ChangeFiles(ctx *context.APIContext) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/FilesResponse"
+	//   "202":
+	//     "$ref": "#/responses/DeferredCommit"
 	//   "403":
 	//     "$ref": "#/responses/error"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
 	//   "422":
 	//     "$ref": "#/responses/error"
 	//   "423":
 	//     "$ref": "#/responses/repoArchivedError"
 
 	apiOpts := web.GetForm(ctx).(*api.ChangeFilesOptions)
+	if !canSkipNotifications(ctx, &apiOpts.FileOptions) {
+		ctx.Error(http.StatusForbidden, "SkipNotifications", "skipping webhooks or CI requires repository admin permission")
+		return
+	}
+
+	if len(apiOpts.Files) == 0 && apiOpts.CherryPick == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "Invalid", "files or cherry_pick is required")
+		return
+	}
 
 	if apiOpts.BranchName == "" {
 		apiOpts.BranchName = ctx.Repo.Repository.DefaultBranch
@@ -467,17 +584,16 @@ func ChangeFiles(ctx *context.APIContext) {
 
 	var files []*files_service.ChangeRepoFile
 	for _, file := range apiOpts.Files {
-		contentReader, err := base64Reader(file.ContentBase64)
-		if err != nil {
-			ctx.Error(http.StatusUnprocessableEntity, "Invalid base64 content", err)
-			return
-		}
 		changeRepoFile := &files_service.ChangeRepoFile{
 			Operation:     file.Operation,
 			TreePath:      file.Path,
 			FromTreePath:  file.FromPath,
-			ContentReader: contentReader,
+			ContentReader: strings.NewReader(file.ContentBase64),
 			SHA:           file.SHA,
+			IsSymlink:     file.IsSymlink,
+			Mode:          file.Mode,
+			Encoding:      contentEncoding(file.Encoding),
+			LineEndings:   file.LineEndings,
 		}
 		files = append(files, changeRepoFile)
 	}
@@ -499,8 +615,26 @@ func ChangeFiles(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
+		Signoff:            apiOpts.Signoff,
+		Trailers:           toCommitTrailers(apiOpts.Trailers),
+		SkipWebhooks:       apiOpts.SkipWebhooks,
+		SkipCI:             apiOpts.SkipCI,
+		CherryPickCommitID: apiOpts.CherryPick,
+		Amend:              apiOpts.Amend,
+		ParentCommitID:     apiOpts.ParentCommit,
+		TargetRef:          apiOpts.TargetRef,
+	}
+
+	// the message of the cherry-picked or amended commit is used if there are no other changes
+	if opts.Message == "" && len(files) > 0 && !opts.Amend {
+		opts.Message = changeFilesCommitMessage(ctx, files)
+	}
+
+	if apiOpts.ExecuteAt != nil && apiOpts.ExecuteAt.After(time.Now()) {
+		deferChangeFiles(ctx, opts, *apiOpts.ExecuteAt)
+		return
 	}
+
 	if opts.Dates.Author.IsZero() {
 		opts.Dates.Author = time.Now()
 	}
@@ -508,10 +642,6 @@ func ChangeFiles(ctx *context.APIContext) {
 		opts.Dates.Committer = time.Now()
 	}
 
-	if opts.Message == "" {
-		opts.Message = changeFilesCommitMessage(ctx, files)
-	}
-
 	if filesResponse, err := createOrUpdateFiles(ctx, opts); err != nil {
 		handleCreateOrUpdateFileError(ctx, err)
 	} else {
@@ -562,23 +692,24 @@ func CreateFile(ctx *context.APIContext) {
 	//     "$ref": "#/responses/repoArchivedError"
 
 	apiOpts := web.GetForm(ctx).(*api.CreateFileOptions)
+	if !canSkipNotifications(ctx, &apiOpts.FileOptions) {
+		ctx.Error(http.StatusForbidden, "SkipNotifications", "skipping webhooks or CI requires repository admin permission")
+		return
+	}
 
 	if apiOpts.BranchName == "" {
 		apiOpts.BranchName = ctx.Repo.Repository.DefaultBranch
 	}
 
-	contentReader, err := base64Reader(apiOpts.ContentBase64)
-	if err != nil {
-		ctx.Error(http.StatusUnprocessableEntity, "Invalid base64 content", err)
-		return
-	}
-
 	opts := &files_service.ChangeRepoFilesOptions{
 		Files: []*files_service.ChangeRepoFile{
 			{
 				Operation:     "create",
 				TreePath:      ctx.PathParam("*"),
-				ContentReader: contentReader,
+				ContentReader: strings.NewReader(apiOpts.ContentBase64),
+				Mode:          apiOpts.Mode,
+				Encoding:      contentEncoding(apiOpts.Encoding),
+				LineEndings:   apiOpts.LineEndings,
 			},
 		},
 		Message:   apiOpts.Message,
@@ -596,7 +727,12 @@ func CreateFile(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
+		Signoff:        apiOpts.Signoff,
+		Trailers:       toCommitTrailers(apiOpts.Trailers),
+		SkipWebhooks:   apiOpts.SkipWebhooks,
+		SkipCI:         apiOpts.SkipCI,
+		ParentCommitID: apiOpts.ParentCommit,
+		TargetRef:      apiOpts.TargetRef,
 	}
 	if opts.Dates.Author.IsZero() {
 		opts.Dates.Author = time.Now()
@@ -659,6 +795,10 @@ func UpdateFile(ctx *context.APIContext) {
 	//   "423":
 	//     "$ref": "#/responses/repoArchivedError"
 	apiOpts := web.GetForm(ctx).(*api.UpdateFileOptions)
+	if !canSkipNotifications(ctx, &apiOpts.FileOptions) {
+		ctx.Error(http.StatusForbidden, "SkipNotifications", "skipping webhooks or CI requires repository admin permission")
+		return
+	}
 	if ctx.Repo.Repository.IsEmpty {
 		ctx.Error(http.StatusUnprocessableEntity, "RepoIsEmpty", fmt.Errorf("repo is empty"))
 		return
@@ -668,20 +808,17 @@ func UpdateFile(ctx *context.APIContext) {
 		apiOpts.BranchName = ctx.Repo.Repository.DefaultBranch
 	}
 
-	contentReader, err := base64Reader(apiOpts.ContentBase64)
-	if err != nil {
-		ctx.Error(http.StatusUnprocessableEntity, "Invalid base64 content", err)
-		return
-	}
-
 	opts := &files_service.ChangeRepoFilesOptions{
 		Files: []*files_service.ChangeRepoFile{
 			{
 				Operation:     "update",
-				ContentReader: contentReader,
+				ContentReader: strings.NewReader(apiOpts.ContentBase64),
 				SHA:           apiOpts.SHA,
 				FromTreePath:  apiOpts.FromPath,
 				TreePath:      ctx.PathParam("*"),
+				Mode:          apiOpts.Mode,
+				Encoding:      contentEncoding(apiOpts.Encoding),
+				LineEndings:   apiOpts.LineEndings,
 			},
 		},
 		Message:   apiOpts.Message,
@@ -699,7 +836,12 @@ func UpdateFile(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
+		Signoff:        apiOpts.Signoff,
+		Trailers:       toCommitTrailers(apiOpts.Trailers),
+		SkipWebhooks:   apiOpts.SkipWebhooks,
+		SkipCI:         apiOpts.SkipCI,
+		ParentCommitID: apiOpts.ParentCommit,
+		TargetRef:      apiOpts.TargetRef,
 	}
 	if opts.Dates.Author.IsZero() {
 		opts.Dates.Author = time.Now()
@@ -720,8 +862,237 @@ func UpdateFile(ctx *context.APIContext) {
 	}
 }
 
+// UploadRawFile handles API call for creating or updating a file with the raw content of the request
+func UploadRawFile(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/contents-raw/{filepath} repository repoUploadRawFile
+	// ---
+	// summary: Create or update a file in a repository with the raw content of the request
+	// description: The content isn't base64 encoded, it is either the request body or the `file` of a multipart form.
+	// consumes:
+	// - application/octet-stream
+	// - multipart/form-data
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file to create or update
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branch to base this file from. if not given, the default branch is used
+	//   type: string
+	//   required: false
+	// - name: new_branch
+	//   in: query
+	//   description: new branch to create from `branch` before changing the file
+	//   type: string
+	//   required: false
+	// - name: message
+	//   in: query
+	//   description: message for the commit of this file. if not supplied, a default message will be used
+	//   type: string
+	//   required: false
+	// - name: sha
+	//   in: query
+	//   description: sha of the file which is updated, it isn't checked if empty
+	//   type: string
+	//   required: false
+	// - name: signoff
+	//   in: query
+	//   description: add a Signed-off-by trailer by the committer at the end of the commit log message
+	//   type: boolean
+	//   required: false
+	// - name: file
+	//   in: formData
+	//   description: content of the file
+	//   type: file
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FileResponse"
+	//   "201":
+	//     "$ref": "#/responses/FileResponse"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	branch := ctx.FormString("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+	if !canWriteFiles(ctx, branch) {
+		ctx.Error(http.StatusForbidden, "UploadRawFile", repo_model.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.Doer.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+	treePath := ctx.PathParam("*")
+	maxSize := setting.Repository.Upload.FileMaxSize * 1024 * 1024
+
+	var content io.ReadSeeker
+	if strings.HasPrefix(strings.ToLower(ctx.Req.Header.Get("Content-Type")), "multipart/form-data") {
+		file, header, err := ctx.Req.FormFile("file")
+		if err != nil {
+			ctx.Error(http.StatusBadRequest, "FormFile", err)
+			return
+		}
+		defer file.Close()
+		if header.Size > maxSize {
+			ctx.Error(http.StatusRequestEntityTooLarge, "UploadRawFile", fmt.Errorf("the file is larger than %d MB", setting.Repository.Upload.FileMaxSize))
+			return
+		}
+		content = file
+	} else {
+		if ctx.Req.ContentLength > maxSize {
+			ctx.Error(http.StatusRequestEntityTooLarge, "UploadRawFile", fmt.Errorf("the file is larger than %d MB", setting.Repository.Upload.FileMaxSize))
+			return
+		}
+		// the files service needs to seek in the content, so the body is streamed into a temporary file
+		tmpFile, err := spoolRawContent(ctx.Req.Body, maxSize)
+		if err != nil {
+			if errors.Is(err, errRawContentTooLarge) {
+				ctx.Error(http.StatusRequestEntityTooLarge, "UploadRawFile", fmt.Errorf("the file is larger than %d MB", setting.Repository.Upload.FileMaxSize))
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "spoolRawContent", err)
+			return
+		}
+		defer func() {
+			_ = tmpFile.Close()
+			_ = util.Remove(tmpFile.Name())
+		}()
+		content = tmpFile
+	}
+
+	buf := make([]byte, 1024)
+	n, _ := util.ReadAtMost(content, buf)
+	if err := upload.Verify(buf[:n], path.Base(treePath), setting.Repository.Upload.AllowedTypes); err != nil {
+		ctx.Error(http.StatusBadRequest, "DetectContentType", err)
+		return
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Seek", err)
+		return
+	}
+
+	// the file is updated if it exists in the branch
+	operation := "create"
+	if !ctx.Repo.Repository.IsEmpty {
+		gitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, ctx.Repo.Repository)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+			return
+		}
+		defer closer.Close()
+		commit, err := gitRepo.GetBranchCommit(branch)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound(err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "GetBranchCommit", err)
+			return
+		}
+		if _, err := commit.GetTreeEntryByPath(treePath); err == nil {
+			operation = "update"
+		} else if !git.IsErrNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetTreeEntryByPath", err)
+			return
+		}
+	}
+
+	opts := &files_service.ChangeRepoFilesOptions{
+		Files: []*files_service.ChangeRepoFile{
+			{
+				Operation:     operation,
+				TreePath:      treePath,
+				ContentReader: content,
+				SHA:           ctx.FormString("sha"),
+			},
+		},
+		Message:   ctx.FormString("message"),
+		OldBranch: branch,
+		NewBranch: ctx.FormString("new_branch"),
+		Signoff:   ctx.FormBool("signoff"),
+	}
+	if opts.Message == "" {
+		opts.Message = changeFilesCommitMessage(ctx, opts.Files)
+	}
+
+	filesResponse, err := createOrUpdateFiles(ctx, opts)
+	if err != nil {
+		handleCreateOrUpdateFileError(ctx, err)
+		return
+	}
+	status := http.StatusOK
+	if operation == "create" {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, files_service.GetFileResponseFromFilesResponse(filesResponse, 0))
+}
+
+var errRawContentTooLarge = errors.New("raw content is too large")
+
+// spoolRawContent copies at most maxSize bytes of the content into a temporary file
+func spoolRawContent(r io.Reader, maxSize int64) (*os.File, error) {
+	if err := os.MkdirAll(setting.Repository.Upload.TempPath, os.ModePerm); err != nil {
+		return nil, err
+	}
+	tmpFile, err := os.CreateTemp(setting.Repository.Upload.TempPath, "raw-content-")
+	if err != nil {
+		return nil, err
+	}
+	written, err := io.Copy(tmpFile, io.LimitReader(r, maxSize+1))
+	if err == nil && written > maxSize {
+		err = errRawContentTooLarge
+	}
+	if err == nil {
+		_, err = tmpFile.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = tmpFile.Close()
+		_ = util.Remove(tmpFile.Name())
+		return nil, err
+	}
+	return tmpFile, nil
+}
+
+// toCommitTrailers converts the trailers of the file API options to git commit trailers
+func toCommitTrailers(trailers []api.CommitTrailer) []git.CommitTrailer {
+	if len(trailers) == 0 {
+		return nil
+	}
+	commitTrailers := make([]git.CommitTrailer, 0, len(trailers))
+	for _, trailer := range trailers {
+		commitTrailers = append(commitTrailers, git.CommitTrailer{Key: trailer.Key, Value: trailer.Value})
+	}
+	return commitTrailers
+}
+
 func handleCreateOrUpdateFileError(ctx *context.APIContext, err error) {
-	if models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) {
+	if models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) || errors.Is(err, util.ErrPermissionDenied) {
 		ctx.Error(http.StatusForbidden, "Access", err)
 		return
 	}
@@ -734,6 +1105,22 @@ func handleCreateOrUpdateFileError(ctx *context.APIContext, err error) {
 		ctx.Error(http.StatusNotFound, "BranchDoesNotExist", err)
 		return
 	}
+	if models.IsErrRepoFileDoesNotExist(err) {
+		ctx.Error(http.StatusNotFound, "FileDoesNotExist", err)
+		return
+	}
+	if git.IsErrNotExist(err) {
+		ctx.Error(http.StatusUnprocessableEntity, "CommitDoesNotExist", err)
+		return
+	}
+	if errors.Is(err, util.ErrInvalidArgument) {
+		ctx.Error(http.StatusUnprocessableEntity, "Invalid", err)
+		return
+	}
+	if models.IsErrMergeConflicts(err) || models.IsErrCommitIDDoesNotMatch(err) {
+		ctx.Error(http.StatusConflict, "Conflict", err)
+		return
+	}
 
 	ctx.Error(http.StatusInternalServerError, "UpdateFile", err)
 }
@@ -756,6 +1143,7 @@ func changeFilesCommitMessage(ctx *context.APIContext, files []*files_service.Ch
 		createFiles []string
 		updateFiles []string
 		deleteFiles []string
+		moveDirs    []string
 	)
 	for _, file := range files {
 		switch file.Operation {
@@ -763,8 +1151,10 @@ func changeFilesCommitMessage(ctx *context.APIContext, files []*files_service.Ch
 			createFiles = append(createFiles, file.TreePath)
 		case "update":
 			updateFiles = append(updateFiles, file.TreePath)
-		case "delete":
+		case "delete", "delete-dir":
 			deleteFiles = append(deleteFiles, file.TreePath)
+		case "move-dir":
+			moveDirs = append(moveDirs, ctx.Locale.TrString("repo.editor.move_dir", file.FromTreePath, file.TreePath))
 		}
 	}
 	message := ""
@@ -775,7 +1165,10 @@ func changeFilesCommitMessage(ctx *context.APIContext, files []*files_service.Ch
 		message += ctx.Locale.TrString("repo.editor.update", strings.Join(updateFiles, ", ")+"\n")
 	}
 	if len(deleteFiles) != 0 {
-		message += ctx.Locale.TrString("repo.editor.delete", strings.Join(deleteFiles, ", "))
+		message += ctx.Locale.TrString("repo.editor.delete", strings.Join(deleteFiles, ", ")+"\n")
+	}
+	if len(moveDirs) != 0 {
+		message += strings.Join(moveDirs, "\n")
 	}
 	return strings.Trim(message, "\n")
 }
@@ -823,6 +1216,10 @@ func DeleteFile(ctx *context.APIContext) {
 	//     "$ref": "#/responses/repoArchivedError"
 
 	apiOpts := web.GetForm(ctx).(*api.DeleteFileOptions)
+	if !canSkipNotifications(ctx, &apiOpts.FileOptions) {
+		ctx.Error(http.StatusForbidden, "SkipNotifications", "skipping webhooks or CI requires repository admin permission")
+		return
+	}
 	if !canWriteFiles(ctx, apiOpts.BranchName) {
 		ctx.Error(http.StatusForbidden, "DeleteFile", repo_model.ErrUserDoesNotHaveAccessToRepo{
 			UserID:   ctx.Doer.ID,
@@ -858,7 +1255,10 @@ func DeleteFile(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
+		Signoff:      apiOpts.Signoff,
+		Trailers:     toCommitTrailers(apiOpts.Trailers),
+		SkipWebhooks: apiOpts.SkipWebhooks,
+		SkipCI:       apiOpts.SkipCI,
 	}
 	if opts.Dates.Author.IsZero() {
 		opts.Dates.Author = time.Now()
@@ -921,6 +1321,11 @@ func GetContents(ctx *context.APIContext) {
 	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
 	//   type: string
 	//   required: false
+	// - name: last_commit
+	//   in: query
+	//   description: "include the last commit of the entries, it is slow for large directories. Default true"
+	//   type: boolean
+	//   required: false
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/ContentsResponse"
@@ -937,8 +1342,9 @@ func GetContents(ctx *context.APIContext) {
 
 	treePath := ctx.PathParam("*")
 	ref := ctx.FormTrim("ref")
+	withLastCommit := ctx.FormOptionalBool("last_commit").ValueOrDefault(true)
 
-	if fileList, err := files_service.GetContentsOrList(ctx, ctx.Repo.Repository, treePath, ref); err != nil {
+	if fileList, err := files_service.GetContentsOrList(ctx, ctx.Repo.Repository, treePath, ref, withLastCommit); err != nil {
 		if git.IsErrNotExist(err) {
 			ctx.NotFound("GetContentsOrList", err)
 			return
@@ -972,6 +1378,11 @@ func GetContentsList(ctx *context.APIContext) {
 	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
 	//   type: string
 	//   required: false
+	// - name: last_commit
+	//   in: query
+	//   description: "include the last commit of the entries, it is slow for large directories. Default true"
+	//   type: boolean
+	//   required: false
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/ContentsListResponse"
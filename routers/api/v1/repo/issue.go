@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,11 +20,14 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/gitrepo"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
@@ -352,6 +356,16 @@ func ListIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: comma separated list of milestone names or ids. It uses names and fall back to ids. Fetch only issues that have any of this milestones. Non existent milestones are discarded
 	//   type: string
+	// - name: issue_types
+	//   in: query
+	//   description: comma separated list of issue type names or ids. It uses names and fall back to ids. Fetch only issues that have any of this types, 0 fetches the issues without a type. Non existent types are discarded
+	//   type: string
+	// - name: fields
+	//   in: query
+	//   description: custom field values like `name:value`, the field can be referenced by its name or id. Fetch only issues that have all of this values. Non existent fields are discarded
+	//   type: array
+	//   items:
+	//     type: string
 	// - name: since
 	//   in: query
 	//   description: Only show items updated after the given time. This is a timestamp in RFC 3339 format
@@ -376,6 +390,11 @@ func ListIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: Only show items in which the given user was mentioned
 	//   type: string
+	// - name: sort
+	//   in: query
+	//   description: order of the issues, the newest are listed first by default
+	//   type: string
+	//   enum: [newest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, mostvotes, leastvotes, nearduedate, farduedate]
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -424,20 +443,20 @@ func ListIssues(ctx *context.APIContext) {
 		for i := range part {
 			// uses names and fall back to ids
 			// non existent milestones are discarded
-			mile, err := issues_model.GetMilestoneByRepoIDANDName(ctx, ctx.Repo.Repository.ID, part[i])
+			mile, err := issues_model.GetMilestoneForRepoByName(ctx, ctx.Repo.Repository.ID, part[i])
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
 			}
 			if !issues_model.IsErrMilestoneNotExist(err) {
-				ctx.Error(http.StatusInternalServerError, "GetMilestoneByRepoIDANDName", err)
+				ctx.Error(http.StatusInternalServerError, "GetMilestoneForRepoByName", err)
 				return
 			}
 			id, err := strconv.ParseInt(part[i], 10, 64)
 			if err != nil {
 				continue
 			}
-			mile, err = issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, id)
+			mile, err = issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, id)
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
@@ -445,10 +464,20 @@ func ListIssues(ctx *context.APIContext) {
 			if issues_model.IsErrMilestoneNotExist(err) {
 				continue
 			}
-			ctx.Error(http.StatusInternalServerError, "GetMilestoneByRepoID", err)
+			ctx.Error(http.StatusInternalServerError, "GetMilestoneForRepo", err)
 		}
 	}
 
+	typeIDs := getIssueTypeIDsForFilter(ctx, ctx.FormString("issue_types"))
+	if ctx.Written() {
+		return
+	}
+
+	fieldValues := getIssueFieldValuesForFilter(ctx, ctx.FormStrings("fields"))
+	if ctx.Written() {
+		return
+	}
+
 	listOptions := utils.GetListOptions(ctx)
 
 	isPull := optional.None[bool]()
@@ -499,6 +528,9 @@ func ListIssues(ctx *context.APIContext) {
 		IsClosed:  isClosed,
 		SortBy:    issue_indexer.SortByCreatedDesc,
 	}
+	if sortType := ctx.FormTrim("sort"); sortType != "" {
+		searchOpt.SortBy = issue_indexer.ParseSortBy(sortType)
+	}
 	if since != 0 {
 		searchOpt.UpdatedAfterUnix = optional.Some(since)
 	}
@@ -523,6 +555,9 @@ func ListIssues(ctx *context.APIContext) {
 		searchOpt.MilestoneIDs = mileIDs
 	}
 
+	searchOpt.TypeIDs = typeIDs
+	searchOpt.FieldValues = fieldValues
+
 	if createdByID > 0 {
 		searchOpt.PosterID = optional.Some(createdByID)
 	}
@@ -549,6 +584,135 @@ func ListIssues(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, ctx.Doer, issues))
 }
 
+// ListSimilarIssues lists the open issues which are similar to a title
+func ListSimilarIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/similar issue issueListSimilarIssues
+	// ---
+	// summary: List the open issues which are similar to a title, to find duplicates before creating an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: title of the new issue
+	//   type: string
+	// - name: limit
+	//   in: query
+	//   description: maximum number of issues to return, the most similar first
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	limit := ctx.FormInt("limit")
+	if limit <= 0 {
+		limit = issue_indexer.DefaultSimilarIssuesLimit
+	} else if limit > setting.API.MaxResponseItems {
+		limit = setting.API.MaxResponseItems
+	}
+
+	ids, err := issue_indexer.SearchSimilarIssues(ctx, ctx.Repo.Repository.ID, ctx.FormString("q"), limit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchSimilarIssues", err)
+		return
+	}
+	issues, err := issues_model.GetIssuesByIDs(ctx, ids, true)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssuesByIDs", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, ctx.Doer, issues))
+}
+
+// getIssueTypeIDsForFilter returns the ids of the issue types from a comma separated list of names or ids,
+// it uses names and falls back to ids, and 0 means the issues without a type
+func getIssueTypeIDsForFilter(ctx *context.APIContext, value string) []int64 {
+	var typeIDs []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "0" {
+			typeIDs = append(typeIDs, 0)
+			continue
+		}
+		issueType, err := issues_model.GetIssueTypeForRepoByName(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, part)
+		if errors.Is(err, util.ErrNotExist) {
+			id, parseErr := strconv.ParseInt(part, 10, 64)
+			if parseErr != nil {
+				continue
+			}
+			issueType, err = issues_model.GetIssueTypeForRepoByID(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, id)
+		}
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				continue
+			}
+			ctx.Error(http.StatusInternalServerError, "GetIssueType", err)
+			return nil
+		}
+		typeIDs = append(typeIDs, issueType.ID)
+	}
+	return typeIDs
+}
+
+// getIssueFieldValuesForFilter returns the normalized custom field values keyed by the field ids from a list of
+// `name:value`, the fields are referenced by their names and fall back to ids, non existent fields are discarded
+func getIssueFieldValuesForFilter(ctx *context.APIContext, filters []string) map[int64]string {
+	var fieldValues map[int64]string
+	for _, filter := range filters {
+		name, value, ok := strings.Cut(filter, ":")
+		if !ok {
+			continue
+		}
+		f, err := issues_model.GetIssueFieldForRepoByName(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, name)
+		if errors.Is(err, util.ErrNotExist) {
+			id, parseErr := strconv.ParseInt(strings.TrimSpace(name), 10, 64)
+			if parseErr != nil {
+				continue
+			}
+			f, err = issues_model.GetIssueFieldForRepoByID(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, id)
+		}
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				continue
+			}
+			ctx.Error(http.StatusInternalServerError, "GetIssueField", err)
+			return nil
+		}
+		values, err := f.NormalizeValues(ctx, []string{value})
+		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "NormalizeValues", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "NormalizeValues", err)
+			}
+			return nil
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if fieldValues == nil {
+			fieldValues = make(map[int64]string, len(filters))
+		}
+		fieldValues[f.ID] = values[0]
+	}
+	return fieldValues
+}
+
 func getUserIDForFilter(ctx *context.APIContext, queryName string) int64 {
 	userName := ctx.FormString(queryName)
 	if len(userName) == 0 {
@@ -649,7 +813,7 @@ func CreateIssue(ctx *context.APIContext) {
 	//   "412":
 	//     "$ref": "#/responses/error"
 	//   "422":
-	//     "$ref": "#/responses/validationError"
+	//     "$ref": "#/responses/invalidIssueFormError"
 	//   "423":
 	//     "$ref": "#/responses/repoArchivedError"
 
@@ -707,6 +871,39 @@ func CreateIssue(ctx *context.APIContext) {
 		form.Labels = make([]int64, 0)
 	}
 
+	if form.Template != "" {
+		issue.Content = renderIssueForm(ctx, form.Template, form.FormValues)
+		if ctx.Written() {
+			return
+		}
+	}
+
+	if form.Type > 0 {
+		issueType, err := issues_model.GetIssueTypeForRepoByID(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, form.Type)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				ctx.Error(http.StatusUnprocessableEntity, "GetIssueTypeForRepoByID", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetIssueTypeForRepoByID", err)
+			}
+			return
+		}
+		if issue.Content == "" {
+			issue.Content = issueType.Template
+		}
+		if err := issueType.ValidateIssue(&issues_model.IssueTypeFieldValues{
+			Content:      issue.Content,
+			LabelIDs:     form.Labels,
+			MilestoneID:  issue.MilestoneID,
+			AssigneeIDs:  assigneeIDs,
+			DeadlineUnix: issue.DeadlineUnix,
+		}); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "ValidateIssue", err)
+			return
+		}
+		issue.TypeID, issue.Type = issueType.ID, issueType
+	}
+
 	if err := issue_service.NewIssue(ctx, ctx.Repo.Repository, issue, form.Labels, nil, assigneeIDs, 0); err != nil {
 		if repo_model.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
@@ -738,6 +935,41 @@ func CreateIssue(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(ctx, ctx.Doer, issue))
 }
 
+// renderIssueForm renders the content of a new issue from the values of the fields of an issue form of the repository,
+// it responds with the invalid fields if the values don't pass the validations of the form
+func renderIssueForm(ctx *context.APIContext, filename string, formValues map[string][]string) string {
+	gitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepositoryFromContextOrOpen", err)
+		return ""
+	}
+	defer closer.Close()
+
+	templates := issue_service.ParseTemplatesFromDefaultBranch(ctx.Repo.Repository, gitRepo).IssueTemplates
+	idx := slices.IndexFunc(templates, func(template *api.IssueTemplate) bool {
+		return template.FileName == filename
+	})
+	if idx < 0 || templates[idx].Type() != api.IssueTemplateTypeYaml {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("%q is not an issue form of the repository", filename))
+		return ""
+	}
+	template := templates[idx]
+
+	values, errs := issue_template.ValuesFromAPI(template, formValues)
+	if len(errs) == 0 {
+		errs = issue_template.ValidateValues(template, values)
+	}
+	if len(errs) > 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, &context.APIInvalidIssueFormError{
+			Message:       errs.Error(),
+			URL:           setting.API.SwaggerURL,
+			InvalidFields: errs,
+		})
+		return ""
+	}
+	return issue_template.RenderToMarkdown(template, values)
+}
+
 // EditIssue modify an issue of a repository
 func EditIssue(ctx *context.APIContext) {
 	// swagger:operation PATCH /repos/{owner}/{repo}/issues/{index} issue issueEditIssue
@@ -880,6 +1112,24 @@ func EditIssue(ctx *context.APIContext) {
 			return
 		}
 	}
+	if canWrite && form.Type != nil && issue.TypeID != *form.Type {
+		var issueType *issues_model.IssueType
+		if *form.Type > 0 {
+			issueType, err = issues_model.GetIssueTypeForRepoByID(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, *form.Type)
+			if err != nil {
+				if errors.Is(err, util.ErrNotExist) {
+					ctx.Error(http.StatusUnprocessableEntity, "GetIssueTypeForRepoByID", err)
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetIssueTypeForRepoByID", err)
+				}
+				return
+			}
+		}
+		if err = issue_service.ChangeIssueType(ctx, issue, ctx.Doer, issueType); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ChangeIssueType", err)
+			return
+		}
+	}
 	if form.State != nil {
 		if issue.IsPull {
 			if err := issue.LoadPullRequest(ctx); err != nil {
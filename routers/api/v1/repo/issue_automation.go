@@ -0,0 +1,392 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListIssueAutomationRules list the automation rules of the issues of a repository
+func ListIssueAutomationRules(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_automations issue issueListIssueAutomationRules
+	// ---
+	// summary: Get the automation rules of the issues and pull requests of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueAutomationRuleList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rules, err := issues_model.GetIssueAutomationRules(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueAutomationRules", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(int64(len(rules)))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueAutomationRuleList(rules))
+}
+
+// GetIssueAutomationRule get an automation rule of the issues of a repository
+func GetIssueAutomationRule(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_automations/{id} issue issueGetIssueAutomationRule
+	// ---
+	// summary: Get an automation rule of the issues and pull requests of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueAutomationRule"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rule := getIssueAutomationRule(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueAutomationRule(rule))
+}
+
+// CreateIssueAutomationRule create an automation rule of the issues of a repository
+func CreateIssueAutomationRule(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issue_automations issue issueCreateIssueAutomationRule
+	// ---
+	// summary: Create an automation rule of the issues and pull requests of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueAutomationRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueAutomationRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueAutomationRuleOption)
+	rule := &issues_model.IssueAutomationRule{
+		RepoID:         ctx.Repo.Repository.ID,
+		Name:           form.Name,
+		Target:         form.Target,
+		LabelID:        form.LabelID,
+		Days:           form.Days,
+		ExemptLabelIDs: form.ExemptLabels,
+		Action:         form.Action,
+		ActionLabelID:  form.ActionLabelID,
+		Comment:        form.Comment,
+		IsDryRun:       form.DryRun,
+	}
+	if err := issues_model.NewIssueAutomationRule(ctx, rule); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "NewIssueAutomationRule", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIssueAutomationRule", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueAutomationRule(rule))
+}
+
+// EditIssueAutomationRule modify an automation rule of the issues of a repository
+func EditIssueAutomationRule(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issue_automations/{id} issue issueEditIssueAutomationRule
+	// ---
+	// summary: Update an automation rule of the issues and pull requests of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueAutomationRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueAutomationRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	rule := getIssueAutomationRule(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditIssueAutomationRuleOption)
+	if form.Name != nil {
+		rule.Name = *form.Name
+	}
+	if form.Target != nil {
+		rule.Target = *form.Target
+	}
+	if form.LabelID != nil {
+		rule.LabelID = *form.LabelID
+	}
+	if form.Days != nil {
+		rule.Days = *form.Days
+	}
+	if form.ExemptLabels != nil {
+		rule.ExemptLabelIDs = form.ExemptLabels
+	}
+	if form.Action != nil {
+		rule.Action = *form.Action
+	}
+	if form.ActionLabelID != nil {
+		rule.ActionLabelID = *form.ActionLabelID
+	}
+	if form.Comment != nil {
+		rule.Comment = *form.Comment
+	}
+	if form.DryRun != nil {
+		rule.IsDryRun = *form.DryRun
+	}
+	if err := issues_model.UpdateIssueAutomationRule(ctx, rule); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateIssueAutomationRule", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueAutomationRule", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueAutomationRule(rule))
+}
+
+// DeleteIssueAutomationRule delete an automation rule of the issues of a repository
+func DeleteIssueAutomationRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issue_automations/{id} issue issueDeleteIssueAutomationRule
+	// ---
+	// summary: Delete an automation rule of the issues and pull requests of a repository, its logs are kept
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rule := getIssueAutomationRule(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.DeleteIssueAutomationRule(ctx, rule); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueAutomationRule", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PreviewIssueAutomationRule list the issues an automation rule would take its action on
+func PreviewIssueAutomationRule(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_automations/{id}/preview issue issuePreviewIssueAutomationRule
+	// ---
+	// summary: Get the issues and pull requests which an automation rule would take its action on now, without taking it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rule := getIssueAutomationRule(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issues, err := rule.FindMatchingIssues(ctx, timeutil.TimeStampNow(), setting.API.MaxResponseItems)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindMatchingIssues", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, ctx.Doer, issues))
+}
+
+// ListIssueAutomationLogs list the actions taken by the automation rules of a repository
+func ListIssueAutomationLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_automations/logs issue issueListIssueAutomationLogs
+	// ---
+	// summary: Get the actions taken by the automation rules of the issues and pull requests of a repository, the most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: rule_id
+	//   in: query
+	//   description: only the actions of this rule
+	//   type: integer
+	//   format: int64
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueAutomationLogList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	logs, count, err := db.FindAndCount[issues_model.IssueAutomationLog](ctx, issues_model.IssueAutomationLogOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		RuleID:      ctx.FormInt64("rule_id"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindIssueAutomationLogs", err)
+		return
+	}
+	if err := issues_model.IssueAutomationLogList(logs).LoadIssues(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssues", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueAutomationLogList(logs))
+}
+
+// getIssueAutomationRule returns the rule with the id in the url, it responds with 404 if it doesn't exist
+func getIssueAutomationRule(ctx *context.APIContext) *issues_model.IssueAutomationRule {
+	rule, err := issues_model.GetIssueAutomationRuleByID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueAutomationRuleByID", err)
+		}
+		return nil
+	}
+	return rule
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/migrations"
+)
+
+// ExportIssues exports the issues of a repository
+func ExportIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/export issue issueExportIssues
+	// ---
+	// summary: Export the issues of a repository with their comments, labels, milestones and attachments
+	// description: The JSON format is described by the issue bundle schema of the migrations,
+	//   the CSV format has a row for each issue without the comments and the attachments.
+	// produces:
+	// - application/json
+	// - text/csv
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: format
+	//   in: query
+	//   description: format of the export
+	//   type: string
+	//   enum: [json, csv]
+	// responses:
+	//   "200":
+	//     description: the exported issues
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	format := ctx.FormString("format")
+	if format != "" && format != "json" && format != "csv" {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unsupported format %q", format))
+		return
+	}
+
+	bundle, err := migrations.ExportIssues(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ExportIssues", err)
+		return
+	}
+
+	if format != "csv" {
+		ctx.JSON(http.StatusOK, bundle)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-issues.csv"`, ctx.Repo.Repository.Name))
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if err := base.WriteIssuesCSV(ctx.Resp, bundle.Issues); err != nil {
+		ctx.Error(http.StatusInternalServerError, "WriteIssuesCSV", err)
+	}
+}
+
+// ImportIssues imports issues into a repository
+func ImportIssues(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/import issue issueImportIssues
+	// ---
+	// summary: Import issues with their comments, labels, milestones and attachments into a repository
+	// description: The issues are numbered after the existing issues, the labels and the milestones are
+	//   matched by their names and created if they don't exist. The body has the format of the export,
+	//   it's read as CSV if its content type is text/csv.
+	// consumes:
+	// - application/json
+	// - text/csv
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: the issues in the format of the export
+	//   schema:
+	//     type: object
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	bundle := &base.IssueBundle{}
+	if mediaType, _, _ := mime.ParseMediaType(ctx.Req.Header.Get("Content-Type")); mediaType == "text/csv" {
+		issues, err := base.ReadIssuesCSV(ctx.Req.Body)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		bundle.Issues = issues
+	} else {
+		bs, err := io.ReadAll(ctx.Req.Body)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ReadAll", err)
+			return
+		}
+		if err := base.Decode(bs, bundle, true, true); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+	}
+
+	issues, err := migrations.ImportIssues(ctx, ctx.Doer, ctx.Repo.Repository, bundle)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ImportIssues", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueList(ctx, ctx.Doer, issues))
+}
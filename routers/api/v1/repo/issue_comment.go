@@ -16,6 +16,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/optional"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
@@ -86,6 +87,9 @@ func ListIssueComments(ctx *context.APIContext) {
 		Before:  before,
 		Type:    issues_model.CommentTypeComment,
 	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		opts.IsInternal = optional.Some(false)
+	}
 
 	comments, err := issues_model.FindComments(ctx, opts)
 	if err != nil {
@@ -186,6 +190,9 @@ func ListIssueCommentsAndTimeline(ctx *context.APIContext) {
 		Before:      before,
 		Type:        issues_model.CommentTypeUndefined,
 	}
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		opts.IsInternal = optional.Some(false)
+	}
 
 	comments, err := issues_model.FindComments(ctx, opts)
 	if err != nil {
@@ -300,6 +307,10 @@ func ListRepoIssueComments(ctx *context.APIContext) {
 		Before:      before,
 		IsPull:      isPull,
 	}
+	// the internal comments are listed if they can be seen in all the issues and pull requests which are listed
+	if (canReadIssue && !ctx.Repo.CanWrite(unit.TypeIssues)) || (canReadPull && !ctx.Repo.CanWrite(unit.TypePullRequests)) {
+		opts.IsInternal = optional.Some(false)
+	}
 
 	comments, err := issues_model.FindComments(ctx, opts)
 	if err != nil {
@@ -396,9 +407,14 @@ func CreateIssueComment(ctx *context.APIContext) {
 		return
 	}
 
-	comment, err := issue_service.CreateIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Body, nil)
+	var comment *issues_model.Comment
+	if form.Internal {
+		comment, err = issue_service.CreateInternalIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Body, nil)
+	} else {
+		comment, err = issue_service.CreateIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Body, nil)
+	}
 	if err != nil {
-		if errors.Is(err, user_model.ErrBlockedUser) {
+		if errors.Is(err, user_model.ErrBlockedUser) || errors.Is(err, util.ErrPermissionDenied) {
 			ctx.Error(http.StatusForbidden, "CreateIssueComment", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateIssueComment", err)
@@ -469,6 +485,11 @@ func GetIssueComment(ctx *context.APIContext) {
 		return
 	}
 
+	if comment.IsInternal && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.NotFound()
+		return
+	}
+
 	if comment.Type != issues_model.CommentTypeComment {
 		ctx.Status(http.StatusNoContent)
 		return
@@ -349,6 +349,11 @@ func getIssueCommentSafe(ctx *context.APIContext) *issues_model.Comment {
 		return nil
 	}
 
+	if comment.IsInternal && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+
 	comment.Issue.Repo = ctx.Repo.Repository
 
 	return comment
@@ -0,0 +1,406 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ListIssueFields list the custom fields which the issues and pull requests of a repository can have
+func ListIssueFields(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_fields issue issueListIssueFields
+	// ---
+	// summary: Get the custom fields which the issues and pull requests of a repository can have, including the ones of its organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFieldList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	fields, err := issues_model.GetIssueFieldsForRepo(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueFieldsForRepo", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(int64(len(fields)))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFieldList(fields))
+}
+
+// GetIssueField get a custom field which the issues and pull requests of a repository can have
+func GetIssueField(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_fields/{id} issue issueGetIssueField
+	// ---
+	// summary: Get a single custom field
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the custom field to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueField"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f, err := issues_model.GetIssueFieldForRepoByID(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, ctx.PathParamInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueFieldForRepoByID", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueField(f))
+}
+
+// CreateIssueField create a custom field for a repository
+func CreateIssueField(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issue_fields issue issueCreateIssueField
+	// ---
+	// summary: Create a custom field for a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueFieldOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueField"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueFieldOption)
+	f := &issues_model.IssueField{
+		RepoID:      ctx.Repo.Repository.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		Type:        issues_model.IssueFieldType(form.Type),
+		Options:     form.Options,
+	}
+	if err := issues_model.NewIssueField(ctx, f); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "NewIssueField", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIssueField", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueField(f))
+}
+
+// EditIssueField modify a custom field of a repository
+func EditIssueField(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issue_fields/{id} issue issueEditIssueField
+	// ---
+	// summary: Update a custom field of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the custom field to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueFieldOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueField"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	f := getRepoIssueField(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditIssueFieldOption)
+	if form.Name != nil {
+		f.Name = *form.Name
+	}
+	if form.Description != nil {
+		f.Description = *form.Description
+	}
+	if form.Options != nil {
+		f.Options = form.Options
+	}
+	if err := issues_model.UpdateIssueField(ctx, f); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateIssueField", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueField", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueField(f))
+}
+
+// DeleteIssueField delete a custom field of a repository
+func DeleteIssueField(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issue_fields/{id} issue issueDeleteIssueField
+	// ---
+	// summary: Delete a custom field of a repository, the values of its issues are deleted too
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the custom field to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f := getRepoIssueField(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.DeleteIssueField(ctx, f); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueField", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// getRepoIssueField returns the custom field defined by the repository, the ones of its organization
+// can only be changed through the organization
+func getRepoIssueField(ctx *context.APIContext) *issues_model.IssueField {
+	f, err := issues_model.GetIssueFieldByID(ctx, ctx.PathParamInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueFieldByID", err)
+		}
+		return nil
+	}
+	if f.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return f
+}
+
+// GetIssueFieldValues get the custom field values of an issue
+func GetIssueFieldValues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/fields issue issueGetFieldValues
+	// ---
+	// summary: Get the custom field values of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFieldValueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue, err := issues_model.GetIssueByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if err := issue.LoadFieldValues(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadFieldValues", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFieldValues(ctx, issue.FieldValues))
+}
+
+// EditIssueFieldValues set the custom field values of an issue
+func EditIssueFieldValues(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issues/{index}/fields issue issueEditFieldValues
+	// ---
+	// summary: Set the values of custom fields of an issue, the values of the other fields are kept
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueFieldsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFieldValueList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIssueFieldsOption)
+	issue, err := issues_model.GetIssueByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return
+	}
+
+	values := make(map[*issues_model.IssueField][]string, len(form.Fields))
+	for _, opt := range form.Fields {
+		f, err := issues_model.GetIssueFieldForRepoByID(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, opt.FieldID)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				ctx.Error(http.StatusUnprocessableEntity, "GetIssueFieldForRepoByID", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetIssueFieldForRepoByID", err)
+			}
+			return
+		}
+		values[f] = opt.Values
+	}
+
+	if err := issue_service.SetIssueFieldValues(ctx, issue, ctx.Doer, values); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetIssueFieldValues", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetIssueFieldValues", err)
+		}
+		return
+	}
+
+	if err := issue.LoadFieldValues(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadFieldValues", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFieldValues(ctx, issue.FieldValues))
+}
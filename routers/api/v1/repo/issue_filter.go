@@ -0,0 +1,265 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unit"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// issueFilterUserID returns the user whose personal filters are visible, 0 for an anonymous user
+func issueFilterUserID(ctx *context.APIContext) int64 {
+	if ctx.Doer == nil {
+		return 0
+	}
+	return ctx.Doer.ID
+}
+
+// canShareIssueFilter returns whether the doer can save, edit and delete the shared filters of the repository
+func canShareIssueFilter(ctx *context.APIContext) bool {
+	return ctx.IsUserRepoWriter([]unit.Type{unit.TypeIssues}) || ctx.IsUserRepoAdmin() || ctx.IsUserSiteAdmin()
+}
+
+// getIssueFilterOfDoer returns the personal filter of the doer with the slug in the url, or the shared filter of the
+// repository if the doer doesn't have one. It responds with 404 if neither exists and with 403 if the doer can't change the shared filter.
+func getIssueFilterOfDoer(ctx *context.APIContext) *issues_model.IssueFilter {
+	f, err := issues_model.GetIssueFilterBySlug(ctx, ctx.Repo.Repository.ID, 0, ctx.Doer.ID, ctx.PathParam(":slug"))
+	if err == nil {
+		return f
+	} else if !errors.Is(err, util.ErrNotExist) {
+		ctx.Error(http.StatusInternalServerError, "GetIssueFilterBySlug", err)
+		return nil
+	}
+
+	f = utils.GetIssueFilter(ctx, ctx.Repo.Repository.ID, 0, 0)
+	if ctx.Written() {
+		return nil
+	}
+	if !canShareIssueFilter(ctx) {
+		ctx.Error(http.StatusForbidden, "", "only the writers of the issues can change a shared filter")
+		return nil
+	}
+	return f
+}
+
+// ListIssueFilters list the saved filters of the issue list of a repository
+func ListIssueFilters(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_filters issue issueListIssueFilters
+	// ---
+	// summary: Get the saved filters of the issue list of a repository, the personal ones of the user, the shared ones of the repository and the ones of its organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilterList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	filters, err := issues_model.GetIssueFiltersForRepo(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, issueFilterUserID(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueFiltersForRepo", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(int64(len(filters)))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFilterList(filters))
+}
+
+// GetIssueFilter get a saved filter of the issue list of a repository
+func GetIssueFilter(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_filters/{slug} issue issueGetIssueFilter
+	// ---
+	// summary: Get a saved filter of the issue list of a repository, the personal filter of the user is preferred over the one of the repository, which is preferred over the one of its organization with the same slug
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: slug
+	//   in: path
+	//   description: slug of the filter to get
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f, err := issues_model.GetIssueFilterForRepoBySlug(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID, issueFilterUserID(ctx), ctx.PathParam(":slug"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueFilterForRepoBySlug", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFilter(f))
+}
+
+// CreateIssueFilter save a filter of the issue list of a repository
+func CreateIssueFilter(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issue_filters issue issueCreateIssueFilter
+	// ---
+	// summary: Save a personal filter of the issue list of a repository, or a filter shared with all its users
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueFilterOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueFilterOption)
+	userID := ctx.Doer.ID
+	if form.Shared {
+		if !canShareIssueFilter(ctx) {
+			ctx.Error(http.StatusForbidden, "", "only the writers of the issues can share a filter")
+			return
+		}
+		userID = 0
+	}
+
+	utils.AddIssueFilter(ctx, form, ctx.Repo.Repository.ID, 0, userID)
+}
+
+// EditIssueFilter modify a saved filter of the issue list of a repository
+func EditIssueFilter(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issue_filters/{slug} issue issueEditIssueFilter
+	// ---
+	// summary: Update a personal filter of the issue list of a repository, or a shared one if the user has none with the slug
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: slug
+	//   in: path
+	//   description: slug of the filter to edit
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueFilterOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueFilter"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	f := getIssueFilterOfDoer(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	utils.EditIssueFilter(ctx, web.GetForm(ctx).(*api.EditIssueFilterOption), f)
+}
+
+// DeleteIssueFilter delete a saved filter of the issue list of a repository
+func DeleteIssueFilter(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issue_filters/{slug} issue issueDeleteIssueFilter
+	// ---
+	// summary: Delete a personal filter of the issue list of a repository, or a shared one if the user has none with the slug
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: slug
+	//   in: path
+	//   description: slug of the filter to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f := getIssueFilterOfDoer(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.DeleteIssueFilter(ctx, f); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteIssueFilter", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
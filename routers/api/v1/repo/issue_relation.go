@@ -0,0 +1,286 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListSubIssues list the sub-issues of an issue
+func ListSubIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/sub_issues issue issueListSubIssues
+	// ---
+	// summary: List the sub-issues of an issue, the ones which the user can't read are omitted
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getParamsIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.Permission.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return
+	}
+
+	subIssues, err := issues_model.GetSubIssues(ctx, issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSubIssues", err)
+		return
+	}
+
+	repoPerms := map[int64]access_model.Permission{ctx.Repo.Repository.ID: ctx.Repo.Permission}
+	readable := make(issues_model.IssueList, 0, len(subIssues))
+	for _, subIssue := range subIssues {
+		perm, ok := repoPerms[subIssue.RepoID]
+		if !ok {
+			perm, err = access_model.GetUserRepoPermission(ctx, subIssue.Repo, ctx.Doer)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+				return
+			}
+			repoPerms[subIssue.RepoID] = perm
+		}
+		if perm.CanReadIssuesOrPulls(subIssue.IsPull) {
+			readable = append(readable, subIssue)
+		}
+	}
+
+	ctx.SetTotalCountHeader(int64(len(readable)))
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, ctx.Doer, readable))
+}
+
+// AddSubIssue make an issue a sub-issue of the issue
+func AddSubIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/sub_issues issue issueAddSubIssue
+	// ---
+	// summary: Make the issue in the form a sub-issue of the issue in the url
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the parent issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	parent, child := getSubIssueRelation(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.AddSubIssue(ctx, parent, child); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "AddSubIssue", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddSubIssue", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(ctx, ctx.Doer, parent))
+}
+
+// RemoveSubIssue make an issue not a sub-issue of the issue anymore
+func RemoveSubIssue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/sub_issues issue issueRemoveSubIssue
+	// ---
+	// summary: Make the issue in the form not a sub-issue of the issue in the url anymore
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the parent issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/IssueMeta"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Issue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	parent, child := getSubIssueRelation(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issues_model.RemoveSubIssue(ctx, parent, child); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveSubIssue", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssue(ctx, ctx.Doer, parent))
+}
+
+// GetParentIssue get the parent of an issue
+func GetParentIssue(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/parent issue issueGetParentIssue
+	// ---
+	// summary: Get the issue which the issue is a sub-issue of
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Issue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getParamsIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !ctx.Repo.Permission.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return
+	}
+
+	parent, err := issues_model.GetParentIssue(ctx, issue.ID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetParentIssue", err)
+		}
+		return
+	}
+	if err := parent.LoadRepo(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepo", err)
+		return
+	}
+
+	perm := getPermissionForRepo(ctx, parent.Repo)
+	if ctx.Written() {
+		return
+	}
+	if !perm.CanReadIssuesOrPulls(parent.IsPull) {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssue(ctx, ctx.Doer, parent))
+}
+
+// getSubIssueRelation returns the parent issue in the url and the sub-issue in the form,
+// the user must be able to write the parent and read the sub-issue
+func getSubIssueRelation(ctx *context.APIContext) (parent, child *issues_model.Issue) {
+	parent = getParamsIssue(ctx)
+	if ctx.Written() {
+		return nil, nil
+	}
+
+	form := web.GetForm(ctx).(*api.IssueMeta)
+	child = getFormIssue(ctx, form)
+	if ctx.Written() {
+		return nil, nil
+	}
+
+	childPerm := getPermissionForRepo(ctx, child.Repo)
+	if ctx.Written() {
+		return nil, nil
+	}
+
+	if !ctx.Repo.Permission.CanWriteIssuesOrPulls(parent.IsPull) || !childPerm.CanReadIssuesOrPulls(child.IsPull) {
+		ctx.NotFound()
+		return nil, nil
+	}
+	return parent, child
+}
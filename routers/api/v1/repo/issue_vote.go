@@ -0,0 +1,189 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// GetIssueVoters lists the users who voted for an issue
+func GetIssueVoters(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/votes issue issueGetVoters
+	// ---
+	// summary: Get users who voted for an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForVote(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	users, count, err := issues_model.GetIssueVoters(ctx, issue.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueVoters", err)
+		return
+	}
+
+	apiUsers := make([]*api.User, 0, len(users))
+	for _, u := range users {
+		apiUsers = append(apiUsers, convert.ToUser(ctx, u, ctx.Doer))
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiUsers)
+}
+
+// AddIssueVote casts the vote of the authenticated user for an issue
+func AddIssueVote(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/votes issue issueAddVote
+	// ---
+	// summary: Vote for an issue
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: Already voted
+	//   "201":
+	//     description: Successfully voted
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForVote(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	voted, err := issue_service.VoteForIssue(ctx, ctx.Doer, issue)
+	if err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) || errors.Is(err, user_model.ErrBlockedUser) {
+			ctx.Error(http.StatusForbidden, "VoteForIssue", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "VoteForIssue", err)
+		}
+		return
+	}
+
+	if !voted {
+		ctx.Status(http.StatusOK)
+		return
+	}
+	ctx.Status(http.StatusCreated)
+}
+
+// DeleteIssueVote removes the vote of the authenticated user for an issue
+func DeleteIssueVote(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/votes issue issueDeleteVote
+	// ---
+	// summary: Remove the vote for an issue
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForVote(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issue_service.RemoveIssueVote(ctx, ctx.Doer, issue); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemoveIssueVote", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func getIssueForVote(ctx *context.APIContext) *issues_model.Issue {
+	issue, err := issues_model.GetIssueByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return nil
+	}
+	return issue
+}
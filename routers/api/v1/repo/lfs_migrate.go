@@ -0,0 +1,136 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	admin_model "code.gitea.io/gitea/models/admin"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
+	task_service "code.gitea.io/gitea/services/task"
+)
+
+// GetLFSMigration returns the latest migration of files to LFS of a repository
+func GetLFSMigration(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/lfs/migrate repository repoGetLFSMigration
+	// ---
+	// summary: Get the latest migration of files to LFS of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LFSMigrateTask"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !setting.LFS.StartServer {
+		ctx.NotFound()
+		return
+	}
+
+	task, err := admin_model.GetLatestTask(ctx, ctx.Repo.Repository.ID, api.TaskTypeMigrateLFS)
+	if err != nil {
+		if admin_model.IsErrTaskDoesNotExist(err) {
+			ctx.NotFound()
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "GetLatestTask", err)
+		return
+	}
+
+	apiTask, err := convert.ToLFSMigrateTask(ctx, ctx.Locale, task, task_service.LFSMigrateBackupRefPrefix(task.ID))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToLFSMigrateTask", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiTask)
+}
+
+// MigrateToLFS starts a migration of the files in the history of a repository to LFS
+func MigrateToLFS(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/lfs/migrate repository repoMigrateToLFS
+	// ---
+	// summary: Rewrite the history of branches of a repository to store the files matching patterns in LFS
+	// description: The migration runs in the background. The previous heads of the branches are kept
+	//   below the backup ref prefix of the task.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/MigrateToLFSOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/LFSMigrateTask"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if !setting.LFS.StartServer {
+		ctx.NotFound()
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.MigrateToLFSOption)
+	task, err := task_service.MigrateRepositoryToLFS(ctx, ctx.Doer, ctx.Repo.Repository, repo_service.MigrateToLFSOptions{
+		Patterns:               form.Patterns,
+		Branches:               form.Branches,
+		IgnoreOpenPullRequests: form.IgnoreOpenPullRequests,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrAlreadyExist), repo_service.IsErrLFSMigrateOpenPullRequests(err):
+			ctx.Error(http.StatusConflict, "MigrateRepositoryToLFS", err)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "MigrateRepositoryToLFS", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "MigrateRepositoryToLFS", err)
+		}
+		return
+	}
+
+	apiTask, err := convert.ToLFSMigrateTask(ctx, ctx.Locale, task, task_service.LFSMigrateBackupRefPrefix(task.ID))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToLFSMigrateTask", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, apiTask)
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	git_model "code.gitea.io/gitea/models/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetManagedGitHook get the managed git hook which applies to a repository
+func GetManagedGitHook(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/managed_git_hook repository repoGetManagedGitHook
+	// ---
+	// summary: Get the git hooks and push checks managed by the instance administrators which apply to a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoManagedGitHook"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, err := git_model.GetManagedGitHook(ctx, ctx.Repo.Repository.OwnerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetManagedGitHook", err)
+		return
+	}
+	optOut, err := git_model.GetManagedGitHookOptOut(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetManagedGitHookOptOut", err)
+		return
+	}
+	effectiveHook, err := git_model.GetEffectiveManagedGitHook(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetEffectiveManagedGitHook", err)
+		return
+	}
+
+	result := &api.RepoManagedGitHook{
+		OptedOut: optOut != nil,
+		Active:   effectiveHook != nil,
+	}
+	if hook != nil {
+		result.ManagedGitHook = convert.ToManagedGitHook(hook)
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// OptOutManagedGitHook make a repository opt out of the managed git hook of its owner
+func OptOutManagedGitHook(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/managed_git_hook/opt_out repository repoOptOutManagedGitHook
+	// ---
+	// summary: Stop applying the managed git hook of the owner to a repository
+	// description: Repository administrators can only opt out if the managed git hook allows it, instance administrators always can.
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, err := git_model.GetManagedGitHook(ctx, ctx.Repo.Repository.OwnerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetManagedGitHook", err)
+		return
+	} else if hook == nil {
+		ctx.NotFound()
+		return
+	}
+	if !ctx.Doer.IsAdmin && !hook.AllowRepoOptOut {
+		ctx.Error(http.StatusForbidden, "", "the managed git hook of the owner doesn't allow opting out")
+		return
+	}
+
+	if err := git_model.SetManagedGitHookOptOut(ctx, ctx.Repo.Repository.ID, ctx.Doer.ID, ctx.Doer.IsAdmin, true); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetManagedGitHookOptOut", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// OptInManagedGitHook apply the managed git hook of its owner to a repository again
+func OptInManagedGitHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/managed_git_hook/opt_out repository repoOptInManagedGitHook
+	// ---
+	// summary: Apply the managed git hook of the owner to a repository again
+	// description: Only instance administrators can revert an opt-out made by an instance administrator.
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	optOut, err := git_model.GetManagedGitHookOptOut(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetManagedGitHookOptOut", err)
+		return
+	} else if optOut == nil {
+		ctx.NotFound()
+		return
+	}
+	if optOut.ByAdmin && !ctx.Doer.IsAdmin {
+		ctx.Error(http.StatusForbidden, "", "the opt-out has been made by an instance administrator")
+		return
+	}
+
+	if err := git_model.SetManagedGitHookOptOut(ctx, ctx.Repo.Repository.ID, ctx.Doer.ID, ctx.Doer.IsAdmin, false); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetManagedGitHookOptOut", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
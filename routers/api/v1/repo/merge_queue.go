@@ -0,0 +1,330 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	asymkey_service "code.gitea.io/gitea/services/asymkey"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/mergequeue"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// ListMergeQueue lists the pull requests in the merge queue of a branch
+func ListMergeQueue(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/merge_queue repository repoListMergeQueue
+	// ---
+	// summary: List the pull requests in the merge queue of a branch, in their merge order
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: the base branch of the merge queue, the default branch of the repository if empty
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeQueueEntryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	branch := ctx.FormString("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+
+	queued, err := mergequeue.GetQueuedPullRequests(ctx, ctx.Repo.Repository, branch)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiEntries := make([]*api.MergeQueueEntry, 0, len(queued))
+	for i, q := range queued {
+		apiEntries = append(apiEntries, convert.ToMergeQueueEntry(ctx, q.Entry, q.PullRequest, i+1, ctx.Doer))
+	}
+	ctx.JSON(http.StatusOK, apiEntries)
+}
+
+// GetPullRequestMergeQueueEntry gets the merge queue entry of a pull request
+func GetPullRequestMergeQueueEntry(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/merge_queue repository repoGetPullRequestMergeQueueEntry
+	// ---
+	// summary: Get the position of a pull request in the merge queue of its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeQueueEntry"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, entry := getPullRequestMergeQueueEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	position, err := pull_model.MergeQueuePosition(ctx, entry)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := entry.LoadDoer(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToMergeQueueEntry(ctx, entry, pr, int(position), ctx.Doer))
+}
+
+// AddPullRequestToMergeQueue adds a pull request to the merge queue of its base branch
+func AddPullRequestToMergeQueue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/merge_queue repository repoAddPullRequestToMergeQueue
+	// ---
+	// summary: Add a pull request to the merge queue of its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     $ref: "#/definitions/AddToMergeQueueOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/MergeQueueEntry"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "405":
+	//     "$ref": "#/responses/empty"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	form := web.GetForm(ctx).(*api.AddToMergeQueueOption)
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	pr.Issue.Repo = ctx.Repo.Repository
+
+	if err := pull_service.CheckPullMergeable(ctx, ctx.Doer, &ctx.Repo.Permission, pr, pull_service.MergeCheckTypeGeneral, false); err != nil {
+		if errors.Is(err, pull_service.ErrIsClosed) {
+			ctx.NotFound()
+		} else if errors.Is(err, pull_service.ErrUserNotAllowedToMerge) {
+			ctx.Error(http.StatusMethodNotAllowed, "Merge", "User not allowed to merge PR")
+		} else if errors.Is(err, pull_service.ErrHasMerged) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR already merged", "")
+		} else if errors.Is(err, pull_service.ErrIsWorkInProgress) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR is a work in progress", "Work in progress PRs cannot be merged")
+		} else if errors.Is(err, pull_service.ErrNotMergeableState) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR not in mergeable state", "Please try again later")
+		} else if models.IsErrDisallowedToMerge(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR is not ready to be merged", err)
+		} else if asymkey_service.IsErrWontSign(err) {
+			ctx.Error(http.StatusMethodNotAllowed, fmt.Sprintf("Protected branch %s requires signed commits but this merge would not be signed", pr.BaseBranch), err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	style := repo_model.MergeStyle(form.MergeStyle)
+	if style == "" {
+		style = ctx.Repo.Repository.MustGetUnit(ctx, unit.TypePullRequests).PullRequestsConfig().GetDefaultMergeStyle()
+	}
+
+	message := strings.TrimSpace(form.Title)
+	if len(message) == 0 {
+		var err error
+		message, _, err = pull_service.GetDefaultMergeMessage(ctx, ctx.Repo.GitRepo, pr, style)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetDefaultMergeMessage", err)
+			return
+		}
+	}
+	if body := strings.TrimSpace(form.Message); len(body) > 0 {
+		message += "\n\n" + body
+	}
+
+	if err := mergequeue.AddToMergeQueue(ctx, ctx.Doer, pr, style, message); err != nil {
+		if pull_model.IsErrAlreadyInMergeQueue(err) {
+			ctx.Error(http.StatusConflict, "AddToMergeQueue", err)
+		} else if models.IsErrInvalidMergeStyle(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not allowed an allowed merge style for this repository", style))
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "AddToMergeQueue", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	_, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if entry == nil {
+		// the pull request has already been merged
+		ctx.Status(http.StatusCreated)
+		return
+	}
+	position, err := pull_model.MergeQueuePosition(ctx, entry)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	entry.Doer = ctx.Doer
+	ctx.JSON(http.StatusCreated, convert.ToMergeQueueEntry(ctx, entry, pr, int(position), ctx.Doer))
+}
+
+// RemovePullRequestFromMergeQueue removes a pull request from the merge queue of its base branch
+func RemovePullRequestFromMergeQueue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/merge_queue repository repoRemovePullRequestFromMergeQueue
+	// ---
+	// summary: Remove a pull request from the merge queue of its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	pr, entry := getPullRequestMergeQueueEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.Doer.ID != entry.DoerID {
+		allowed, err := access_model.IsUserRepoAdmin(ctx, ctx.Repo.Repository, ctx.Doer)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		if !allowed {
+			ctx.Error(http.StatusForbidden, "No permission to remove", "user has no permission to remove the pull request from the merge queue")
+			return
+		}
+	}
+
+	if err := mergequeue.RemoveFromMergeQueue(ctx, ctx.Doer, pr); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getPullRequestMergeQueueEntry(ctx *context.APIContext) (*issues_model.PullRequest, *pull_model.MergeQueueEntry) {
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil, nil
+	}
+
+	exist, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return nil, nil
+	}
+	if !exist {
+		ctx.NotFound()
+		return nil, nil
+	}
+	return pr, entry
+}
@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 )
@@ -60,6 +61,131 @@ func GetNote(ctx *context.APIContext) {
 	getNote(ctx, sha)
 }
 
+// SetNote Add or replace the note of a single commit
+func SetNote(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/git/notes/{sha} repository repoSetNote
+	// ---
+	// summary: Add or replace the note of a single commit
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/SetNoteOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Note"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	commitID, ok := resolveNoteCommitID(ctx)
+	if !ok {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.SetNoteOption)
+	if err := git.SetNote(ctx, ctx.Repo.GitRepo, commitID, form.Message, ctx.Doer.NewGitSig()); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetNote", err)
+		return
+	}
+	getNote(ctx, commitID)
+}
+
+// DeleteNote Remove the note of a single commit
+func DeleteNote(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/git/notes/{sha} repository repoDeleteNote
+	// ---
+	// summary: Remove the note of a single commit
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	commitID, ok := resolveNoteCommitID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := git.RemoveNote(ctx, ctx.Repo.GitRepo, commitID, ctx.Doer.NewGitSig()); err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "RemoveNote", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// resolveNoteCommitID resolves the "sha" path parameter to a full commit ID
+func resolveNoteCommitID(ctx *context.APIContext) (string, bool) {
+	sha := ctx.PathParam(":sha")
+	if !git.IsValidRefPattern(sha) {
+		ctx.Error(http.StatusUnprocessableEntity, "no valid ref or sha", fmt.Sprintf("no valid ref or sha: %s", sha))
+		return "", false
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return "", false
+	}
+	return commit.ID.String(), true
+}
+
 func getNote(ctx *context.APIContext, identifier string) {
 	if ctx.Repo.GitRepo == nil {
 		ctx.InternalServerError(fmt.Errorf("no open git repo"))
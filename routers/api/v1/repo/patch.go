@@ -4,14 +4,15 @@
 package repo
 
 import (
+	"errors"
 	"net/http"
-	"time"
 
 	"code.gitea.io/gitea/models"
 	git_model "code.gitea.io/gitea/models/git"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/repository/files"
@@ -41,15 +42,25 @@ func ApplyDiffPatch(ctx *context.APIContext) {
 	//   in: body
 	//   required: true
 	//   schema:
-	//     "$ref": "#/definitions/UpdateFileOptions"
+	//     "$ref": "#/definitions/ApplyDiffPatchFileOptions"
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/FileResponse"
+	//   "403":
+	//     "$ref": "#/responses/error"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/ApplyPatchConflicts"
+	//   "422":
+	//     "$ref": "#/responses/error"
 	//   "423":
 	//     "$ref": "#/responses/repoArchivedError"
 	apiOpts := web.GetForm(ctx).(*api.ApplyDiffPatchFileOptions)
+	if !canSkipNotifications(ctx, &apiOpts.FileOptions) {
+		ctx.Error(http.StatusForbidden, "SkipNotifications", "skipping webhooks or CI requires repository admin permission")
+		return
+	}
 
 	opts := &files.ApplyDiffPatchOptions{
 		Content:   apiOpts.Content,
@@ -69,19 +80,13 @@ func ApplyDiffPatch(ctx *context.APIContext) {
 			Author:    apiOpts.Dates.Author,
 			Committer: apiOpts.Dates.Committer,
 		},
-		Signoff: apiOpts.Signoff,
-	}
-	if opts.Dates.Author.IsZero() {
-		opts.Dates.Author = time.Now()
-	}
-	if opts.Dates.Committer.IsZero() {
-		opts.Dates.Committer = time.Now()
-	}
-
-	if opts.Message == "" {
-		opts.Message = "apply-patch"
+		Signoff:                apiOpts.Signoff,
+		Trailers:               toCommitTrailers(apiOpts.Trailers),
+		SkipWebhooks:           apiOpts.SkipWebhooks,
+		SkipCI:                 apiOpts.SkipCI,
+		ThreeWay:               apiOpts.ThreeWay == nil || *apiOpts.ThreeWay,
+		UseFormatPatchMetadata: true,
 	}
-
 	if !canWriteFiles(ctx, apiOpts.BranchName) {
 		ctx.Error(http.StatusInternalServerError, "ApplyPatch", repo_model.ErrUserDoesNotHaveAccessToRepo{
 			UserID:   ctx.Doer.ID,
@@ -92,12 +97,24 @@ func ApplyDiffPatch(ctx *context.APIContext) {
 
 	fileResponse, err := files.ApplyDiffPatch(ctx, ctx.Repo.Repository, ctx.Doer, opts)
 	if err != nil {
+		if patchErr, ok := err.(files.ErrPatchDoesNotApply); ok {
+			ctx.JSON(http.StatusConflict, &api.ApplyPatchConflicts{
+				Message:         patchErr.Error(),
+				Conflicts:       patchErr.Conflicts,
+				ConflictedFiles: patchErr.ConflictedFiles,
+			})
+			return
+		}
 		if models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) {
 			ctx.Error(http.StatusForbidden, "Access", err)
 			return
 		}
+		if models.IsErrCommitIDDoesNotMatch(err) {
+			ctx.Error(http.StatusConflict, "CommitIDDoesNotMatch", err)
+			return
+		}
 		if git_model.IsErrBranchAlreadyExists(err) || models.IsErrFilenameInvalid(err) || models.IsErrSHADoesNotMatch(err) ||
-			models.IsErrFilePathInvalid(err) || models.IsErrRepoFileAlreadyExists(err) {
+			models.IsErrFilePathInvalid(err) || models.IsErrRepoFileAlreadyExists(err) || errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusUnprocessableEntity, "Invalid", err)
 			return
 		}
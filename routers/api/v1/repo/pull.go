@@ -28,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
@@ -408,7 +409,7 @@ func CreatePullRequest(ctx *context.APIContext) {
 	)
 
 	// Get repo/branch information
-	headRepo, headGitRepo, compareInfo, baseBranch, headBranch := parseCompareInfo(ctx, form)
+	headRepo, headGitRepo, compareInfo, baseBranch, headBranch := parseCompareInfo(ctx, form, false, false)
 	if ctx.Written() {
 		return
 	}
@@ -462,12 +463,12 @@ func CreatePullRequest(ctx *context.APIContext) {
 	}
 
 	if form.Milestone > 0 {
-		milestone, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, form.Milestone)
+		milestone, err := issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, form.Milestone)
 		if err != nil {
 			if issues_model.IsErrMilestoneNotExist(err) {
 				ctx.NotFound()
 			} else {
-				ctx.Error(http.StatusInternalServerError, "GetMilestoneByRepoID", err)
+				ctx.Error(http.StatusInternalServerError, "GetMilestoneForRepo", err)
 			}
 			return
 		}
@@ -857,6 +858,8 @@ func MergePullRequest(ctx *context.APIContext) {
 	//     "$ref": "#/responses/empty"
 	//   "409":
 	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 	//   "423":
 	//     "$ref": "#/responses/repoArchivedError"
 
@@ -917,6 +920,8 @@ func MergePullRequest(ctx *context.APIContext) {
 			ctx.Error(http.StatusMethodNotAllowed, "PR is not ready to be merged", err)
 		} else if asymkey_service.IsErrWontSign(err) {
 			ctx.Error(http.StatusMethodNotAllowed, fmt.Sprintf("Protected branch %s requires signed commits but this merge would not be signed", pr.BaseBranch), err)
+		} else if errors.Is(err, pull_service.ErrDependenciesLeft) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR is blocked by an open dependency", "Dependencies must be closed before merging")
 		} else {
 			ctx.InternalServerError(err)
 		}
@@ -960,7 +965,16 @@ func MergePullRequest(ctx *context.APIContext) {
 	}
 
 	if form.MergeWhenChecksSucceed {
-		scheduled, err := automerge.ScheduleAutoMerge(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message)
+		conditions, err := automerge.ConditionsFromForm(ctx, pr, form)
+		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "ConditionsFromForm", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "ConditionsFromForm", err)
+			return
+		}
+		scheduled, err := automerge.ScheduleAutoMerge(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message, conditions)
 		if err != nil {
 			if pull_model.IsErrAlreadyScheduledToAutoMerge(err) {
 				ctx.Error(http.StatusConflict, "ScheduleAutoMerge", err)
@@ -975,9 +989,11 @@ func MergePullRequest(ctx *context.APIContext) {
 		}
 	}
 
-	if err := pull_service.Merge(ctx, pr, ctx.Doer, ctx.Repo.GitRepo, repo_model.MergeStyle(form.Do), form.HeadCommitID, message, false); err != nil {
+	if err := pull_service.Merge(ctx, pr, ctx.Doer, ctx.Repo.GitRepo, repo_model.MergeStyle(form.Do), form.HeadCommitID, message, form.MergeStrategyOptions, false); err != nil {
 		if models.IsErrInvalidMergeStyle(err) {
 			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not allowed an allowed merge style for this repository", repo_model.MergeStyle(form.Do)))
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "Merge", err)
 		} else if models.IsErrMergeConflicts(err) {
 			conflictError := err.(models.ErrMergeConflicts)
 			ctx.JSON(http.StatusConflict, conflictError)
@@ -1054,7 +1070,22 @@ func MergePullRequest(ctx *context.APIContext) {
 	ctx.Status(http.StatusOK)
 }
 
-func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption) (*repo_model.Repository, *git.Repository, *git.CompareInfo, string, string) {
+// resolveCompareCommit returns the full ID of the commit named by a full or abbreviated SHA,
+// or an empty string if the name isn't the SHA of a commit of the repository
+func resolveCompareCommit(gitRepo *git.Repository, name string) string {
+	objectFormat, err := gitRepo.GetObjectFormat()
+	if err != nil || !objectFormat.IsValid(name) {
+		return ""
+	}
+	commit, err := gitRepo.GetCommit(name)
+	if err != nil {
+		return ""
+	}
+	return commit.ID.String()
+}
+
+// parseCompareInfo parses the base and the head of a comparison, commit SHAs are accepted besides the branches and the tags if allowCommits is set
+func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption, directComparison, allowCommits bool) (*repo_model.Repository, *git.Repository, *git.CompareInfo, string, string) {
 	baseRepo := ctx.Repo.Repository
 
 	// Get compared branches information
@@ -1101,8 +1132,15 @@ func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption)
 	log.Trace("Repo path: %q, base branch: %q, head branch: %q", ctx.Repo.GitRepo.Path, baseBranch, headBranch)
 	// Check if base branch is valid.
 	if !ctx.Repo.GitRepo.IsBranchExist(baseBranch) && !ctx.Repo.GitRepo.IsTagExist(baseBranch) {
-		ctx.NotFound("BaseNotExist")
-		return nil, nil, nil, "", ""
+		var commitID string
+		if allowCommits {
+			commitID = resolveCompareCommit(ctx.Repo.GitRepo, baseBranch)
+		}
+		if commitID == "" {
+			ctx.NotFound("BaseNotExist")
+			return nil, nil, nil, "", ""
+		}
+		baseBranch = commitID
 	}
 
 	// Check if current user has fork of repository or in the same repository.
@@ -1165,12 +1203,19 @@ func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption)
 
 	// Check if head branch is valid.
 	if !headGitRepo.IsBranchExist(headBranch) && !headGitRepo.IsTagExist(headBranch) {
-		headGitRepo.Close()
-		ctx.NotFound()
-		return nil, nil, nil, "", ""
+		var commitID string
+		if allowCommits {
+			commitID = resolveCompareCommit(headGitRepo, headBranch)
+		}
+		if commitID == "" {
+			headGitRepo.Close()
+			ctx.NotFound()
+			return nil, nil, nil, "", ""
+		}
+		headBranch = commitID
 	}
 
-	compareInfo, err := headGitRepo.GetCompareInfo(repo_model.RepoPath(baseRepo.Owner.Name, baseRepo.Name), baseBranch, headBranch, false, false)
+	compareInfo, err := headGitRepo.GetCompareInfo(repo_model.RepoPath(baseRepo.Owner.Name, baseRepo.Name), baseBranch, headBranch, directComparison, false)
 	if err != nil {
 		headGitRepo.Close()
 		ctx.Error(http.StatusInternalServerError, "GetCompareInfo", err)
@@ -1616,3 +1661,466 @@ func GetPullRequestFiles(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &apiFiles)
 }
+
+// GetPullRequestCodeOwners gets the CODEOWNERS rules matching the files changed by a pull request and their approval state
+func GetPullRequestCodeOwners(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/code_owners repository repoGetPullRequestCodeOwners
+	// ---
+	// summary: Get the CODEOWNERS rules matching the files changed by a pull request and whether their owners approved it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestCodeOwnerRuleList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFirstMatchProtectedBranchRule", err)
+		return
+	}
+
+	statuses, err := pull_service.GetCodeOwnerRuleStatuses(ctx, pb, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCodeOwnerRuleStatuses", err)
+		return
+	}
+
+	apiRules := make([]*api.PullRequestCodeOwnerRule, 0, len(statuses))
+	for _, status := range statuses {
+		teams, err := convert.ToTeams(ctx, status.Rule.Teams, true)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToTeams", err)
+			return
+		}
+		apiRules = append(apiRules, &api.PullRequestCodeOwnerRule{
+			Pattern:   status.Rule.Pattern,
+			Files:     status.Files,
+			Users:     convert.ToUsers(ctx, ctx.Doer, status.Rule.Users),
+			Teams:     teams,
+			Approvers: convert.ToUsers(ctx, ctx.Doer, status.Approvers),
+			Satisfied: status.IsSatisfied(),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// GetPullRequestViewedFiles gets the files of a pull request the authenticated user marked as viewed
+func GetPullRequestViewedFiles(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/viewed_files repository repoGetPullRequestViewedFiles
+	// ---
+	// summary: Get which files changed by a pull request the authenticated user marked as viewed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewViewedFiles"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	respondWithViewedFiles(ctx, pr)
+}
+
+// UpdatePullRequestViewedFiles marks files of a pull request as viewed or unviewed for the authenticated user
+func UpdatePullRequestViewedFiles(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/pulls/{index}/viewed_files repository repoUpdatePullRequestViewedFiles
+	// ---
+	// summary: Mark files changed by a pull request as viewed or unviewed for the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UpdatePullReviewViewedFilesOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewViewedFiles"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opts := web.GetForm(ctx).(*api.UpdatePullReviewViewedFilesOptions)
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	if err := pull_service.UpdateViewedFiles(ctx, ctx.Repo.GitRepo, ctx.Doer.ID, pr, opts.HeadCommitSHA, opts.Files, opts.Directories); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateViewedFiles", err)
+		return
+	}
+
+	respondWithViewedFiles(ctx, pr)
+}
+
+func respondWithViewedFiles(ctx *context.APIContext, pr *issues_model.PullRequest) {
+	files, err := pull_service.GetViewedFiles(ctx, ctx.Repo.GitRepo, ctx.Doer.ID, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetViewedFiles", err)
+		return
+	}
+
+	progress := &pull_service.ReviewProgress{NumFiles: len(files)}
+	apiFiles := make([]*api.PullReviewViewedFile, 0, len(files))
+	for _, file := range files {
+		if file.State == pull_model.Viewed {
+			progress.NumViewedFiles++
+		}
+		apiFiles = append(apiFiles, &api.PullReviewViewedFile{
+			Path:               file.Path,
+			Viewed:             file.State == pull_model.Viewed,
+			ChangedSinceReview: file.State == pull_model.HasChanged,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, &api.PullReviewViewedFiles{
+		Files:          apiFiles,
+		NumFiles:       progress.NumFiles,
+		NumViewedFiles: progress.NumViewedFiles,
+		Progress:       progress.Percent(),
+	})
+}
+
+// GetPullRequestPushes lists the pushes to the head branch of a pull request
+func GetPullRequestPushes(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/pushes repository repoGetPullRequestPushes
+	// ---
+	// summary: List the pushes to the head branch of a pull request, oldest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestPushList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	pushes, err := pull_service.GetPushes(ctx, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPushes", err)
+		return
+	}
+	comments := make(issues_model.CommentList, 0, len(pushes))
+	for _, push := range pushes {
+		comments = append(comments, push.Comment)
+	}
+	if err := comments.LoadPosters(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadPosters", err)
+		return
+	}
+
+	apiPushes := make([]*api.PullRequestPush, 0, len(pushes))
+	for _, push := range pushes {
+		apiPushes = append(apiPushes, &api.PullRequestPush{
+			ID:           push.Comment.ID,
+			Pusher:       convert.ToUser(ctx, push.Comment.Poster, ctx.Doer),
+			IsForcePush:  push.IsForcePush,
+			CommitIDs:    push.CommitIDs,
+			HeadCommitID: push.HeadCommitID,
+			Created:      push.Comment.CreatedUnix.AsTime(),
+		})
+	}
+	ctx.JSON(http.StatusOK, apiPushes)
+}
+
+// GetPullRequestChanges gets the changes of a pull request between two of its heads
+func GetPullRequestChanges(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/changes repository repoGetPullRequestChanges
+	// ---
+	// summary: Get the changes of a pull request between two of its heads, e.g. since the last review of the authenticated user
+	// description: The heads are compared directly, so that the changes are shown across force pushes too.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: since_push
+	//   in: query
+	//   description: id of the push whose head the changes are shown since, the head of the last review of the authenticated user is used if it's not set
+	//   type: integer
+	//   format: int64
+	// - name: until_push
+	//   in: query
+	//   description: id of the push whose head the changes are shown until, the current head is used if it's not set
+	//   type: integer
+	//   format: int64
+	// - name: whitespace
+	//   in: query
+	//   description: whitespace behavior
+	//   type: string
+	//   enum: [ignore-all, ignore-change, ignore-eol, show-all]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestChanges"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+	pushes, err := pull_service.GetPushes(ctx, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPushes", err)
+		return
+	}
+	getPushHead := func(id int64) string {
+		for _, push := range pushes {
+			if push.Comment.ID == id {
+				return push.HeadCommitID
+			}
+		}
+		return ""
+	}
+
+	var beforeCommitID string
+	if sincePush := ctx.FormInt64("since_push"); sincePush > 0 {
+		beforeCommitID = getPushHead(sincePush)
+	} else if ctx.Doer != nil {
+		beforeCommitID, err = pull_service.GetLastReviewCommitID(ctx, pr, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetLastReviewCommitID", err)
+			return
+		}
+	}
+	if beforeCommitID == "" {
+		ctx.NotFound("the head to show the changes since doesn't exist")
+		return
+	}
+
+	var afterCommitID string
+	if untilPush := ctx.FormInt64("until_push"); untilPush > 0 {
+		afterCommitID = getPushHead(untilPush)
+	} else {
+		afterCommitID, err = ctx.Repo.GitRepo.GetRefCommitID(pr.GetGitRefName())
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetRefCommitID", err)
+			return
+		}
+	}
+	if afterCommitID == "" {
+		ctx.NotFound("the head to show the changes until doesn't exist")
+		return
+	}
+
+	diff, err := pull_service.GetChangesBetweenHeads(ctx, ctx.Repo.GitRepo, pr, beforeCommitID, afterCommitID, gitdiff.GetWhitespaceFlag(ctx.FormString("whitespace")))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetChangesBetweenHeads", err)
+		}
+		return
+	}
+
+	apiFiles := make([]*api.ChangedFile, 0, len(diff.Files))
+	for _, file := range diff.Files {
+		// the previous heads are only kept in the base repository
+		apiFiles = append(apiFiles, convert.ToChangedFile(file, ctx.Repo.Repository, afterCommitID))
+	}
+	ctx.JSON(http.StatusOK, &api.PullRequestChanges{
+		BeforeCommitID: beforeCommitID,
+		AfterCommitID:  afterCommitID,
+		Files:          apiFiles,
+	})
+}
+
+// GetPullRequestStatusChecks gets the results of the required status checks of a pull request
+func GetPullRequestStatusChecks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/status_checks repository repoGetPullRequestStatusChecks
+	// ---
+	// summary: Get the results of the required status checks of the base branch for the head of a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestStatusChecks"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+	if pr.HasMerged {
+		ctx.NotFound()
+		return
+	}
+
+	sha, results, err := pull_service.GetPullRequestRequiredStatusChecks(ctx, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPullRequestRequiredStatusChecks", err)
+		return
+	}
+
+	statusChecks := &api.PullRequestStatusChecks{
+		CommitID:    sha,
+		State:       api.CommitStatusSuccess,
+		Checks:      make([]*api.RequiredStatusCheck, 0, len(results)),
+		Unsatisfied: []string{},
+	}
+	for _, result := range results {
+		statusChecks.Checks = append(statusChecks.Checks, &api.RequiredStatusCheck{
+			Rule:      result.Check.Rule,
+			Patterns:  result.Check.Alternatives,
+			State:     result.State,
+			Satisfied: result.IsSatisfied(),
+			Contexts:  result.Contexts,
+		})
+		if !result.IsSatisfied() {
+			statusChecks.Unsatisfied = append(statusChecks.Unsatisfied, result.Check.Rule)
+		}
+		if result.State.NoBetterThan(statusChecks.State) {
+			statusChecks.State = result.State
+		}
+	}
+	ctx.JSON(http.StatusOK, statusChecks)
+}
@@ -0,0 +1,350 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	issue_service "code.gitea.io/gitea/services/issue"
+	notify_service "code.gitea.io/gitea/services/notify"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// batchPullRequestAction performs the action of a batch request on a pull request,
+// the returned error is reported as the result of the pull request
+type batchPullRequestAction func(ctx *context.APIContext, pr *issues_model.PullRequest) error
+
+// BatchPullRequests performs an action on several pull requests at once
+func BatchPullRequests(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/batch repository repoBatchPullRequests
+	// ---
+	// summary: Perform an action on several pull requests at once
+	// description: The parameters of the action and the pull requests are validated before any pull request is changed,
+	//   then the action is performed on each pull request and the failures are reported per pull request.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/BatchPullRequestOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BatchPullRequestResultList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	form := web.GetForm(ctx).(*api.BatchPullRequestOption)
+
+	indexes := container.FilterSlice(form.Indexes, func(index int64) (int64, bool) { return index, true })
+	if len(indexes) > setting.API.MaxResponseItems {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("at most %d pull requests can be changed at once", setting.API.MaxResponseItems))
+		return
+	}
+	prs := make([]*issues_model.PullRequest, 0, len(indexes))
+	for _, index := range indexes {
+		pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, index)
+		if err != nil {
+			if issues_model.IsErrPullRequestNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("pull request #%d doesn't exist", index))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+			}
+			return
+		}
+		prs = append(prs, pr)
+	}
+
+	var action batchPullRequestAction
+	switch form.Action {
+	case "close":
+		action = batchClosePullRequest
+	case "retarget":
+		action = prepareBatchRetargetPullRequest(ctx, form.Base)
+	case "add_labels":
+		action = prepareBatchAddPullRequestLabels(ctx, form.Labels)
+	case "request_reviewers":
+		action = prepareBatchRequestPullRequestReviewers(ctx, form.Reviewers, form.TeamReviewers)
+	case "update":
+		action = prepareBatchUpdatePullRequest(form.Style == "rebase")
+	}
+	if ctx.Written() {
+		return
+	}
+
+	results := make([]*api.BatchPullRequestResult, 0, len(prs))
+	for _, pr := range prs {
+		result := &api.BatchPullRequestResult{Index: pr.Index, Success: true}
+		if err := pr.LoadIssue(ctx); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+			return
+		}
+		pr.Issue.Repo = ctx.Repo.Repository
+		if err := action(ctx, pr); err != nil {
+			if ctx.Written() {
+				return
+			}
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	ctx.JSON(http.StatusOK, results)
+}
+
+// errBatchServerError is returned by the batch actions when they have written an internal server error
+var errBatchServerError = errors.New("internal server error")
+
+func batchClosePullRequest(ctx *context.APIContext, pr *issues_model.PullRequest) error {
+	if pr.HasMerged {
+		return errors.New("the pull request has been merged")
+	}
+	if pr.Issue.IsClosed {
+		return nil
+	}
+	if err := issue_service.ChangeStatus(ctx, pr.Issue, ctx.Doer, "", true); err != nil {
+		if issues_model.IsErrDependenciesLeft(err) {
+			return errors.New("the pull request still has open dependencies")
+		}
+		ctx.Error(http.StatusInternalServerError, "ChangeStatus", err)
+		return errBatchServerError
+	}
+	return nil
+}
+
+func prepareBatchRetargetPullRequest(ctx *context.APIContext, base string) batchPullRequestAction {
+	if base == "" || !ctx.Repo.GitRepo.IsBranchExist(base) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("base branch %q doesn't exist", base))
+		return nil
+	}
+
+	return func(ctx *context.APIContext, pr *issues_model.PullRequest) error {
+		if pr.BaseBranch == base {
+			return nil
+		}
+		if err := pull_service.ChangeTargetBranch(ctx, pr, ctx.Doer, base); err != nil {
+			switch {
+			case issues_model.IsErrPullRequestAlreadyExists(err):
+				return errors.New("there is already a pull request from the same branch to the base branch")
+			case issues_model.IsErrIssueIsClosed(err):
+				return errors.New("the pull request is closed")
+			case models.IsErrPullRequestHasMerged(err):
+				return errors.New("the pull request has been merged")
+			case git_model.IsErrBranchesEqual(err):
+				return errors.New("the head branch is the base branch")
+			}
+			ctx.Error(http.StatusInternalServerError, "ChangeTargetBranch", err)
+			return errBatchServerError
+		}
+		notify_service.PullRequestChangeTargetBranch(ctx, ctx.Doer, pr, base)
+		return nil
+	}
+}
+
+func prepareBatchAddPullRequestLabels(ctx *context.APIContext, labelIDs []int64) batchPullRequestAction {
+	labelIDs = container.FilterSlice(labelIDs, func(id int64) (int64, bool) { return id, true })
+	if len(labelIDs) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "no labels are given")
+		return nil
+	}
+	labels, err := issues_model.GetLabelsInRepoByIDs(ctx, ctx.Repo.Repository.ID, labelIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLabelsInRepoByIDs", err)
+		return nil
+	}
+	if ctx.Repo.Owner.IsOrganization() {
+		orgLabels, err := issues_model.GetLabelsInOrgByIDs(ctx, ctx.Repo.Owner.ID, labelIDs)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetLabelsInOrgByIDs", err)
+			return nil
+		}
+		labels = append(labels, orgLabels...)
+	}
+	if len(labels) != len(labelIDs) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "some labels don't exist")
+		return nil
+	}
+
+	return func(ctx *context.APIContext, pr *issues_model.PullRequest) error {
+		if err := issue_service.AddLabels(ctx, pr.Issue, ctx.Doer, labels); err != nil {
+			ctx.Error(http.StatusInternalServerError, "AddLabels", err)
+			return errBatchServerError
+		}
+		return nil
+	}
+}
+
+func prepareBatchRequestPullRequestReviewers(ctx *context.APIContext, reviewerNames, teamReviewerNames []string) batchPullRequestAction {
+	if len(reviewerNames) == 0 && len(teamReviewerNames) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "no reviewers are given")
+		return nil
+	}
+
+	reviewers := make([]*user_model.User, 0, len(reviewerNames))
+	for _, name := range reviewerNames {
+		var reviewer *user_model.User
+		var err error
+		if strings.Contains(name, "@") {
+			reviewer, err = user_model.GetUserByEmail(ctx, name)
+		} else {
+			reviewer, err = user_model.GetUserByName(ctx, name)
+		}
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("user %q doesn't exist", name))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUser", err)
+			}
+			return nil
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	teamReviewers := make([]*organization.Team, 0, len(teamReviewerNames))
+	if len(teamReviewerNames) > 0 && !ctx.Repo.Owner.IsOrganization() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "teams can only be requested for the repositories of organizations")
+		return nil
+	}
+	for _, name := range teamReviewerNames {
+		team, err := organization.GetTeam(ctx, ctx.Repo.Owner.ID, name)
+		if err != nil {
+			if organization.IsErrTeamNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("team %q doesn't exist", name))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTeam", err)
+			}
+			return nil
+		}
+		teamReviewers = append(teamReviewers, team)
+	}
+
+	permDoer, err := access_model.GetUserRepoPermission(ctx, ctx.Repo.Repository, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return nil
+	}
+
+	return func(ctx *context.APIContext, pr *issues_model.PullRequest) error {
+		// all the reviewers are validated before any review is requested
+		for _, reviewer := range reviewers {
+			if err := issue_service.IsValidReviewRequest(ctx, reviewer, ctx.Doer, true, pr.Issue, &permDoer); err != nil {
+				if issues_model.IsErrNotValidReviewRequest(err) {
+					return err
+				}
+				ctx.Error(http.StatusInternalServerError, "IsValidReviewRequest", err)
+				return errBatchServerError
+			}
+		}
+		for _, team := range teamReviewers {
+			if err := issue_service.IsValidTeamReviewRequest(ctx, team, ctx.Doer, true, pr.Issue); err != nil {
+				if issues_model.IsErrNotValidReviewRequest(err) {
+					return err
+				}
+				ctx.Error(http.StatusInternalServerError, "IsValidTeamReviewRequest", err)
+				return errBatchServerError
+			}
+		}
+
+		for _, reviewer := range reviewers {
+			if _, err := issue_service.ReviewRequest(ctx, pr.Issue, ctx.Doer, reviewer, true); err != nil {
+				if issues_model.IsErrReviewRequestOnClosedPR(err) {
+					return errors.New("the pull request is closed")
+				}
+				ctx.Error(http.StatusInternalServerError, "ReviewRequest", err)
+				return errBatchServerError
+			}
+		}
+		for _, team := range teamReviewers {
+			if _, err := issue_service.TeamReviewRequest(ctx, pr.Issue, ctx.Doer, team, true); err != nil {
+				ctx.Error(http.StatusInternalServerError, "TeamReviewRequest", err)
+				return errBatchServerError
+			}
+		}
+		return nil
+	}
+}
+
+func prepareBatchUpdatePullRequest(rebase bool) batchPullRequestAction {
+	return func(ctx *context.APIContext, pr *issues_model.PullRequest) error {
+		if pr.HasMerged {
+			return errors.New("the pull request has been merged")
+		}
+		if pr.Issue.IsClosed {
+			return errors.New("the pull request is closed")
+		}
+		if err := pr.LoadBaseRepo(ctx); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadBaseRepo", err)
+			return errBatchServerError
+		}
+		if err := pr.LoadHeadRepo(ctx); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadHeadRepo", err)
+			return errBatchServerError
+		}
+
+		allowedUpdateByMerge, allowedUpdateByRebase, err := pull_service.IsUserAllowedToUpdate(ctx, pr, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsUserAllowedToUpdate", err)
+			return errBatchServerError
+		}
+		if (!allowedUpdateByMerge && !rebase) || (rebase && !allowedUpdateByRebase) {
+			return errors.New("you aren't allowed to update the pull request")
+		}
+
+		// the pull requests which are up to date are left as they are
+		diverging, err := pull_service.GetDiverging(ctx, pr)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetDiverging", err)
+			return errBatchServerError
+		}
+		if diverging.Behind == 0 {
+			return nil
+		}
+
+		// default merge commit message
+		message := fmt.Sprintf("Merge branch '%s' into %s", pr.BaseBranch, pr.HeadBranch)
+		if err := pull_service.Update(ctx, pr, ctx.Doer, message, rebase); err != nil {
+			switch {
+			case models.IsErrMergeConflicts(err):
+				return errors.New("merge failed because of conflict")
+			case models.IsErrRebaseConflicts(err):
+				return errors.New("rebase failed because of conflict")
+			}
+			ctx.Error(http.StatusInternalServerError, "pull_service.Update", err)
+			return errBatchServerError
+		}
+		return nil
+	}
+}
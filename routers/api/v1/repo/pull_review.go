@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/services/convert"
 	issue_service "code.gitea.io/gitea/services/issue"
 	pull_service "code.gitea.io/gitea/services/pull"
+	files_service "code.gitea.io/gitea/services/repository/files"
 )
 
 // ListPullReviews lists all reviews of a pull request
@@ -209,6 +210,289 @@ func GetPullReviewComments(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, apiComments)
 }
 
+// CreatePullReviewComment adds a comment to a pending review of a pull request
+func CreatePullReviewComment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments repository repoCreatePullReviewComment
+	// ---
+	// summary: Add a comment to a pending review of a pull request
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreatePullReviewComment"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PullReviewComment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.CreatePullReviewComment)
+	review, pr, isWrong := preparePendingReview(ctx)
+	if isWrong {
+		return
+	}
+
+	if strings.TrimSpace(opts.Body) == "" || opts.Path == "" || (opts.NewLineNum == 0) == (opts.OldLineNum == 0) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("a comment requires a body, a path and either a new or an old line number"))
+		return
+	}
+	line := opts.NewLineNum
+	if opts.OldLineNum > 0 {
+		line = opts.OldLineNum * -1
+	}
+
+	headCommitID, err := ctx.Repo.GitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GitRepo: GetRefCommitID", err)
+		return
+	}
+
+	comment, err := pull_service.CreateCodeComment(ctx,
+		ctx.Doer,
+		ctx.Repo.GitRepo,
+		pr.Issue,
+		line,
+		opts.Body,
+		opts.Path,
+		true, // pending review
+		0,    // no reply
+		headCommitID,
+		nil,
+	)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CreateCodeComment", err)
+		return
+	}
+	comment.Poster = ctx.Doer
+
+	ctx.JSON(http.StatusCreated, convert.ToPullReviewComment(ctx, review, comment, ctx.Doer))
+}
+
+// GetPullReviewComment gets a comment of a pull request review
+func GetPullReviewComment(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments/{comment} repository repoGetPullReviewComment
+	// ---
+	// summary: Get a comment of a pull request review
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: comment
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewComment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	review, _, isWrong := prepareSingleReview(ctx)
+	if isWrong {
+		return
+	}
+	comment, isWrong := prepareReviewComment(ctx, review)
+	if isWrong {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPullReviewComment(ctx, review, comment, ctx.Doer))
+}
+
+// EditPullReviewComment edits a comment of a pending review of a pull request
+func EditPullReviewComment(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments/{comment} repository repoEditPullReviewComment
+	// ---
+	// summary: Edit a comment of a pending review of a pull request
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: comment
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditPullReviewCommentOptions"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewComment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := web.GetForm(ctx).(*api.EditPullReviewCommentOptions)
+	review, _, isWrong := preparePendingReview(ctx)
+	if isWrong {
+		return
+	}
+	comment, isWrong := prepareReviewComment(ctx, review)
+	if isWrong {
+		return
+	}
+
+	oldContent := comment.Content
+	comment.Content = opts.Body
+	if err := issue_service.UpdateComment(ctx, comment, comment.ContentVersion, ctx.Doer, oldContent); err != nil {
+		if errors.Is(err, user_model.ErrBlockedUser) {
+			ctx.Error(http.StatusForbidden, "UpdateComment", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateComment", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPullReviewComment(ctx, review, comment, ctx.Doer))
+}
+
+// DeletePullReviewComment deletes a comment of a pending review of a pull request
+func DeletePullReviewComment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments/{comment} repository repoDeletePullReviewComment
+	// ---
+	// summary: Delete a comment of a pending review of a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: comment
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	review, _, isWrong := preparePendingReview(ctx)
+	if isWrong {
+		return
+	}
+	comment, isWrong := prepareReviewComment(ctx, review)
+	if isWrong {
+		return
+	}
+
+	if err := issue_service.DeleteComment(ctx, ctx.Doer, comment); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteComment", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // DeletePullReview delete a specific review from a pull request
 func DeletePullReview(ctx *context.APIContext) {
 	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/reviews/{id} repository repoDeletePullReview
@@ -373,7 +657,7 @@ func CreatePullReview(ctx *context.APIContext) {
 	// create review and associate all pending review comments
 	review, _, err := pull_service.SubmitReview(ctx, ctx.Doer, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, opts.CommitID, nil)
 	if err != nil {
-		if errors.Is(err, pull_service.ErrSubmitReviewOnClosedPR) {
+		if errors.Is(err, pull_service.ErrSubmitReviewOnClosedPR) || issues_model.IsContentEmptyErr(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "SubmitReview", err)
@@ -445,7 +729,7 @@ func SubmitPullReview(ctx *context.APIContext) {
 	}
 
 	// determine review type
-	reviewType, isWrong := preparePullReviewType(ctx, pr, opts.Event, opts.Body, len(review.Comments) > 0)
+	reviewType, isWrong := preparePullReviewType(ctx, pr, opts.Event, opts.Body, len(review.CodeComments) > 0)
 	if isWrong {
 		return
 	}
@@ -521,6 +805,8 @@ func preparePullReviewType(ctx *context.APIContext, pr *issues_model.PullRequest
 		}
 	default:
 		reviewType = issues_model.ReviewTypePending
+		// comments are added to a pending review until it is submitted with a body
+		needsBody = false
 	}
 
 	// reject reviews with empty body if a body is required for this call
@@ -574,6 +860,63 @@ func prepareSingleReview(ctx *context.APIContext) (*issues_model.Review, *issues
 	return review, pr, false
 }
 
+// preparePendingReview returns the review like prepareSingleReview, it must be a pending review of the doer
+func preparePendingReview(ctx *context.APIContext) (*issues_model.Review, *issues_model.PullRequest, bool) {
+	review, pr, isWrong := prepareSingleReview(ctx)
+	if isWrong {
+		return nil, nil, true
+	}
+
+	if review.Type != issues_model.ReviewTypePending {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("only the comments of a pending review can be changed"))
+		return nil, nil, true
+	}
+	if review.ReviewerID != ctx.Doer.ID {
+		ctx.Error(http.StatusForbidden, "", fmt.Errorf("only the reviewer can change a pending review"))
+		return nil, nil, true
+	}
+
+	if err := pr.LoadIssue(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return nil, nil, true
+	}
+	if err := pr.Issue.LoadRepo(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "pr.Issue.LoadRepo", err)
+		return nil, nil, true
+	}
+
+	return review, pr, false
+}
+
+// prepareReviewComment returns the code comment of the review given in the path with its poster and resolver loaded
+func prepareReviewComment(ctx *context.APIContext, review *issues_model.Review) (*issues_model.Comment, bool) {
+	comment, err := issues_model.GetCommentByID(ctx, ctx.PathParamInt64(":comment"))
+	if err != nil {
+		if issues_model.IsErrCommentNotExist(err) {
+			ctx.NotFound("GetCommentByID", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommentByID", err)
+		}
+		return nil, true
+	}
+
+	if comment.ReviewID != review.ID || comment.Type != issues_model.CommentTypeCode {
+		ctx.NotFound("CommentNotInReview")
+		return nil, true
+	}
+
+	if err := comment.LoadPoster(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadPoster", err)
+		return nil, true
+	}
+	if err := comment.LoadResolveDoer(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadResolveDoer", err)
+		return nil, true
+	}
+
+	return comment, false
+}
+
 // CreateReviewRequests create review requests to an pull request
 func CreateReviewRequests(ctx *context.APIContext) {
 	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/requested_reviewers repository repoCreatePullReviewRequests
@@ -921,3 +1264,70 @@ func dismissReview(ctx *context.APIContext, msg string, isDismiss, dismissPriors
 	}
 	ctx.JSON(http.StatusOK, apiReview)
 }
+
+// ApplyPullReviewSuggestions commits the changes suggested by review comments to the head branch of a pull request
+func ApplyPullReviewSuggestions(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/suggestions repository repoApplyPullReviewSuggestions
+	// ---
+	// summary: Commit the changes suggested by review comments to the head branch of a pull request
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApplyPullReviewSuggestionsOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FilesResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+	opts := web.GetForm(ctx).(*api.ApplyPullReviewSuggestionsOptions)
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	filesResponse, err := files_service.ApplySuggestions(ctx, ctx.Doer, pr, &files_service.ApplySuggestionsOptions{
+		CommentIDs: opts.CommentIDs,
+		Message:    opts.Message,
+	})
+	if err != nil {
+		handleCreateOrUpdateFileError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, filesResponse)
+}
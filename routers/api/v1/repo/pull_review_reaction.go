@@ -0,0 +1,265 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// GetPullReviewResolutionReactions list reactions of the resolution of a review comment conversation
+func GetPullReviewResolutionReactions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments/{comment}/resolution/reactions repository repoGetPullReviewResolutionReactions
+	// ---
+	// summary: Get a list of reactions of the resolution of the conversation started by a review comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: comment
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReactionList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	comment, isWrong := prepareResolutionReactionComment(ctx)
+	if isWrong {
+		return
+	}
+
+	reactions, count, err := issues_model.FindResolutionReactions(ctx, comment.IssueID, comment.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindResolutionReactions", err)
+		return
+	}
+	_, err = reactions.LoadUsers(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReactionList.LoadUsers()", err)
+		return
+	}
+
+	result := make([]api.Reaction, 0, len(reactions))
+	for _, r := range reactions {
+		result = append(result, api.Reaction{
+			User:     convert.ToUser(ctx, r.User, ctx.Doer),
+			Reaction: r.Type,
+			Created:  r.CreatedUnix.AsTime(),
+		})
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// PostPullReviewResolutionReaction add a reaction to the resolution of a review comment conversation
+func PostPullReviewResolutionReaction(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments/{comment}/resolution/reactions repository repoPostPullReviewResolutionReaction
+	// ---
+	// summary: Add a reaction to the resolution of the conversation started by a review comment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: comment
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Reaction"
+	//   "201":
+	//     "$ref": "#/responses/Reaction"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.EditReactionOption)
+	comment, isWrong := prepareResolutionReactionComment(ctx)
+	if isWrong {
+		return
+	}
+
+	reaction, err := issue_service.CreateResolutionReaction(ctx, ctx.Doer, comment, form.Reaction)
+	if err != nil {
+		if issues_model.IsErrForbiddenIssueReaction(err) || errors.Is(err, user_model.ErrBlockedUser) {
+			ctx.Error(http.StatusForbidden, err.Error(), err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateResolutionReaction", err)
+		} else if issues_model.IsErrReactionAlreadyExist(err) {
+			ctx.JSON(http.StatusOK, api.Reaction{
+				User:     convert.ToUser(ctx, ctx.Doer, ctx.Doer),
+				Reaction: reaction.Type,
+				Created:  reaction.CreatedUnix.AsTime(),
+			})
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateResolutionReaction", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, api.Reaction{
+		User:     convert.ToUser(ctx, ctx.Doer, ctx.Doer),
+		Reaction: reaction.Type,
+		Created:  reaction.CreatedUnix.AsTime(),
+	})
+}
+
+// DeletePullReviewResolutionReaction remove a reaction from the resolution of a review comment conversation
+func DeletePullReviewResolutionReaction(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/reviews/{id}/comments/{comment}/resolution/reactions repository repoDeletePullReviewResolutionReaction
+	// ---
+	// summary: Remove a reaction from the resolution of the conversation started by a review comment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the review
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: comment
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: content
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReactionOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	form := web.GetForm(ctx).(*api.EditReactionOption)
+	comment, isWrong := prepareResolutionReactionComment(ctx)
+	if isWrong {
+		return
+	}
+
+	if err := issues_model.DeleteResolutionReaction(ctx, ctx.Doer.ID, comment.IssueID, comment.ID, form.Reaction); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteResolutionReaction", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// prepareResolutionReactionComment returns the review comment of the path whose conversation resolution is reacted to
+func prepareResolutionReactionComment(ctx *context.APIContext) (*issues_model.Comment, bool) {
+	review, _, isWrong := prepareSingleReview(ctx)
+	if isWrong {
+		return nil, true
+	}
+	comment, isWrong := prepareReviewComment(ctx, review)
+	if isWrong {
+		return nil, true
+	}
+	if err := comment.LoadIssue(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return nil, true
+	}
+
+	if ctx.Req.Method != http.MethodGet && comment.Issue.IsLocked && !ctx.Repo.CanWriteIssuesOrPulls(true) {
+		ctx.Error(http.StatusForbidden, "ChangeResolutionReaction", errors.New("no permission to change reaction"))
+		return nil, true
+	}
+	return comment, false
+}
@@ -4,6 +4,7 @@
 package repo
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/attachment"
 	"code.gitea.io/gitea/services/context"
@@ -252,6 +254,72 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToAPIAttachment(ctx.Repo.Repository, attach))
 }
 
+// CreateExternalReleaseAttachment adds a file hosted outside of Gitea as a release attachment
+func CreateExternalReleaseAttachment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/releases/{id}/assets/external repository repoCreateExternalReleaseAttachment
+	// ---
+	// summary: Add a file hosted outside of Gitea as a release attachment
+	// description: Downloads of the attachment are redirected to the given url, the checksum and size of the file are verified periodically.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the release
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateExternalAttachmentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Attachment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	releaseID := ctx.PathParamInt64(":id")
+	if !checkReleaseMatchRepo(ctx, releaseID) {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.CreateExternalAttachmentOption)
+	attach, err := attachment.NewExternalAttachment(ctx, &repo_model.Attachment{
+		Name:           form.Name,
+		UploaderID:     ctx.Doer.ID,
+		RepoID:         ctx.Repo.Repository.ID,
+		ReleaseID:      releaseID,
+		ExternalURL:    form.URL,
+		ExternalSHA256: form.SHA256,
+		Size:           form.Size,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "NewExternalAttachment", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "NewExternalAttachment", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIAttachment(ctx.Repo.Repository, attach))
+}
+
 // EditReleaseAttachment updates the given attachment
 func EditReleaseAttachment(ctx *context.APIContext) {
 	// swagger:operation PATCH /repos/{owner}/{repo}/releases/{id}/assets/{attachment_id} repository repoEditReleaseAttachment
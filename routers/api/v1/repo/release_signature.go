@@ -0,0 +1,166 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/services/context"
+	release_service "code.gitea.io/gitea/services/release"
+)
+
+func getReleaseSignature(ctx *context.APIContext) *repo_model.ReleaseSignature {
+	release, err := repo_model.GetReleaseForRepoByID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":id"))
+	if err != nil {
+		if repo_model.IsErrReleaseNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetReleaseForRepoByID", err)
+		}
+		return nil
+	}
+	if release.IsTag || (release.IsDraft && !ctx.Repo.CanWrite(unit.TypeReleases)) {
+		ctx.NotFound()
+		return nil
+	}
+
+	sig, err := release_service.GetReleaseSignature(ctx, ctx.Repo.GitRepo, release)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReleaseSignature", err)
+		return nil
+	}
+	return sig
+}
+
+// GetReleaseChecksums returns the checksums of the artifacts of a release
+func GetReleaseChecksums(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/{id}/checksums repository repoGetReleaseChecksums
+	// ---
+	// summary: Get the SHA-256 checksums of the source archives and attachments of a release
+	// description: The response uses the format of sha256sum, so downloaded files can be checked with "sha256sum -c".
+	// produces:
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the release
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: checksums file
+	//     schema:
+	//       type: string
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	sig := getReleaseSignature(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.PlainText(http.StatusOK, sig.Checksums)
+}
+
+// GetReleaseChecksumsSignature returns the signature of the checksums of a release
+func GetReleaseChecksumsSignature(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/{id}/checksums.asc repository repoGetReleaseChecksumsSignature
+	// ---
+	// summary: Get the detached GPG signature of the checksums file of a release
+	// description: The signature is made with the signing key of the instance, see the signing-key.gpg endpoints.
+	// produces:
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the release
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: ASCII armored signature
+	//     schema:
+	//       type: string
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	sig := getReleaseSignature(ctx)
+	if ctx.Written() {
+		return
+	}
+	if sig.Signature == "" {
+		ctx.NotFound("no signing key is configured")
+		return
+	}
+	ctx.PlainText(http.StatusOK, sig.Signature)
+}
+
+// GetReleaseProvenance returns the provenance statement of a release
+func GetReleaseProvenance(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/{id}/provenance repository repoGetReleaseProvenance
+	// ---
+	// summary: Get the provenance statement of the source archives and attachments of a release
+	// description: The statement is an in-toto statement with a SLSA provenance predicate.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the release
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: provenance statement
+	//     schema:
+	//       type: object
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	sig := getReleaseSignature(ctx)
+	if ctx.Written() {
+		return
+	}
+	var statement map[string]any
+	if err := json.Unmarshal([]byte(sig.Provenance), &statement); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Unmarshal", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, statement)
+}
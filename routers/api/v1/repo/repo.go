@@ -5,6 +5,7 @@
 package repo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"slices"
@@ -26,9 +27,11 @@ import (
 	"code.gitea.io/gitea/modules/label"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/references"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -36,6 +39,7 @@ import (
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 	"code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
 
@@ -123,6 +127,13 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: property
+	//   in: query
+	//   description: search only for repos whose custom property has the value, in the form "name:value"
+	//   type: array
+	//   items:
+	//     type: string
+	//   collectionFormat: multi
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/SearchResults"
@@ -178,6 +189,13 @@ func Search(ctx *context.APIContext) {
 		opts.IsPrivate = optional.Some(ctx.FormBool("is_private"))
 	}
 
+	customProperties, err := repo_model.ParseCustomPropertyFilters(ctx.FormStrings("property"))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	opts.CustomProperties = customProperties
+
 	sortMode := ctx.FormString("sort")
 	if len(sortMode) > 0 {
 		sortOrder := ctx.FormString("order")
@@ -197,7 +215,6 @@ func Search(ctx *context.APIContext) {
 		}
 	}
 
-	var err error
 	repos, count, err := repo_model.SearchRepository(ctx, opts)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, api.SearchError{
@@ -264,7 +281,8 @@ func CreateUserRepo(ctx *context.APIContext, owner *user_model.User, opt api.Cre
 			ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
 		} else if db.IsErrNameReserved(err) ||
 			db.IsErrNamePatternNotAllowed(err) ||
-			label.IsErrTemplateLoad(err) {
+			label.IsErrTemplateLoad(err) ||
+			errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateRepository", err)
@@ -795,10 +813,22 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			var config *repo_model.IssuesConfig
 
 			if opts.InternalTracker != nil {
+				for _, language := range opts.InternalTracker.KeywordLanguages {
+					if _, ok := references.LanguageKeywords[language]; !ok {
+						err := fmt.Errorf("keyword language %q isn't supported", language)
+						ctx.Error(http.StatusUnprocessableEntity, "Invalid keyword language", err)
+						return err
+					}
+				}
 				config = &repo_model.IssuesConfig{
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					CloseKeywords:                    opts.InternalTracker.CloseKeywords,
+					ReopenKeywords:                   opts.InternalTracker.ReopenKeywords,
+					KeywordLanguages:                 opts.InternalTracker.KeywordLanguages,
+					CloseOnlyOnDefaultBranch:         opts.InternalTracker.CloseOnlyOnDefaultBranch,
+					CloseOnlyInSameMilestone:         opts.InternalTracker.CloseOnlyInSameMilestone,
 				}
 			} else if unit, err := repo.GetUnit(ctx, unit_model.TypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -935,6 +965,25 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			if opts.DefaultAllowMaintainerEdit != nil {
 				config.DefaultAllowMaintainerEdit = *opts.DefaultAllowMaintainerEdit
 			}
+			if opts.MergeStrategy != nil {
+				config.MergeStrategy = *opts.MergeStrategy
+			}
+			if opts.MergeStrategyOptions != nil {
+				config.MergeStrategyOptions = *opts.MergeStrategyOptions
+			}
+			if opts.EnableMergeQueue != nil {
+				config.EnableMergeQueue = *opts.EnableMergeQueue
+			}
+			if opts.RetargetOnBaseDeletion != nil {
+				config.RetargetOnBaseDeletion = *opts.RetargetOnBaseDeletion
+			}
+			if opts.RebaseOnRetarget != nil {
+				config.RebaseOnRetarget = *opts.RebaseOnRetarget
+			}
+			if err := pull_service.ValidateMergeStrategy(config.MergeStrategy, config.MergeStrategyOptions); err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "Invalid merge strategy", err)
+				return err
+			}
 
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
@@ -0,0 +1,175 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/git"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/indexer/code/gitgrep"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	wiki_service "code.gitea.io/gitea/services/wiki"
+)
+
+func toContentSearchResultLines(lines []*code_indexer.ResultLine) []*api.ContentSearchResultLine {
+	apiLines := make([]*api.ContentSearchResultLine, 0, len(lines))
+	for _, line := range lines {
+		apiLines = append(apiLines, &api.ContentSearchResultLine{
+			Num:     line.Num,
+			Content: line.RawContent,
+		})
+	}
+	return apiLines
+}
+
+// SearchContent searches the content of the files or of the wiki pages of a repository
+func SearchContent(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/search repository repoSearchContent
+	// ---
+	// summary: Search the content of the files or of the wiki pages of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: keyword to search for
+	//   type: string
+	//   required: true
+	// - name: scope
+	//   in: query
+	//   description: search the files of the default branch or the pages of the wiki
+	//   type: string
+	//   enum: [code, wiki]
+	//   default: code
+	// - name: fuzzy
+	//   in: query
+	//   description: whether the keyword is matched fuzzily
+	//   type: boolean
+	//   default: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ContentSearchResultList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	keyword := ctx.FormTrim("q")
+	if keyword == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", util.NewInvalidArgumentErrorf("search keyword is required"))
+		return
+	}
+	isFuzzy := ctx.FormOptionalBool("fuzzy").ValueOrDefault(true)
+	listOptions := utils.GetListOptions(ctx)
+	listOptions.SetDefaultValues()
+
+	switch scope := ctx.FormString("scope"); scope {
+	case "", "code":
+		searchCode(ctx, keyword, isFuzzy, listOptions)
+	case "wiki":
+		searchWiki(ctx, keyword, isFuzzy, listOptions)
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", util.NewInvalidArgumentErrorf("unknown search scope %q", scope))
+	}
+}
+
+func searchCode(ctx *context.APIContext, keyword string, isFuzzy bool, listOptions db.ListOptions) {
+	if !ctx.Repo.CanRead(unit.TypeCode) {
+		ctx.NotFound()
+		return
+	}
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.SetTotalCountHeader(0)
+		ctx.JSON(http.StatusOK, []*api.ContentSearchResult{})
+		return
+	}
+
+	var (
+		total   int
+		results []*code_indexer.Result
+		err     error
+	)
+	if setting.Indexer.RepoIndexerEnabled {
+		total, results, _, err = code_indexer.PerformSearch(ctx, &code_indexer.SearchOptions{
+			RepoIDs:        []int64{ctx.Repo.Repository.ID},
+			Keyword:        keyword,
+			IsKeywordFuzzy: isFuzzy,
+			Paginator:      &listOptions,
+		})
+	} else {
+		results, total, err = gitgrep.PerformSearch(ctx, listOptions.Page, listOptions.PageSize, ctx.Repo.Repository.ID, ctx.Repo.GitRepo,
+			git.RefNameFromBranch(ctx.Repo.Repository.DefaultBranch), keyword, isFuzzy, gitgrep.IndexSettingToGitGrepPathspecList())
+	}
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchCode", err)
+		return
+	}
+
+	apiResults := make([]*api.ContentSearchResult, 0, len(results))
+	for _, result := range results {
+		apiResults = append(apiResults, &api.ContentSearchResult{
+			Path:     result.Filename,
+			HTMLURL:  ctx.Repo.Repository.HTMLURL() + "/src/commit/" + util.PathEscapeSegments(result.CommitID) + "/" + util.PathEscapeSegments(result.Filename),
+			CommitID: result.CommitID,
+			Language: result.Language,
+			Lines:    toContentSearchResultLines(result.Lines),
+		})
+	}
+
+	ctx.SetTotalCountHeader(int64(total))
+	ctx.JSON(http.StatusOK, apiResults)
+}
+
+func searchWiki(ctx *context.APIContext, keyword string, isFuzzy bool, listOptions db.ListOptions) {
+	if !ctx.Repo.Repository.UnitEnabled(ctx, unit.TypeWiki) || !ctx.Repo.CanRead(unit.TypeWiki) {
+		ctx.NotFound()
+		return
+	}
+
+	total, results, err := wiki_service.SearchWikiPages(ctx, ctx.Repo.Repository, keyword, isFuzzy, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchWikiPages", err)
+		return
+	}
+
+	apiResults := make([]*api.ContentSearchResult, 0, len(results))
+	for _, result := range results {
+		apiResults = append(apiResults, &api.ContentSearchResult{
+			Path:     result.Filename,
+			Title:    result.Title,
+			HTMLURL:  ctx.Repo.Repository.HTMLURL() + "/wiki/" + result.SubURL,
+			CommitID: result.CommitID,
+			Language: result.Language,
+			Lines:    toContentSearchResultLines(result.Lines),
+		})
+	}
+
+	ctx.SetTotalCountHeader(int64(total))
+	ctx.JSON(http.StatusOK, apiResults)
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetRepoSize get the size breakdown of a repository
+func GetRepoSize(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/size repository repoGetSize
+	// ---
+	// summary: Get the sizes of the data attributable to a repository
+	// description: The sizes are refreshed by the update_repository_sizes cron task.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSize"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := ctx.Repo.Repository
+	stat, err := repo_model.GetRepoSizeStat(ctx, repo.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoSizeStat", err)
+		return
+	}
+	if stat == nil {
+		// the cron task hasn't run since the repository was created
+		if stat, err = repo_service.UpdateRepoSizeStat(ctx, repo); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepoSizeStat", err)
+			return
+		}
+	}
+
+	size := &api.RepoSize{
+		Git:              repo.GitSize,
+		LFS:              repo.LFSSize,
+		Attachments:      stat.AttachmentSize,
+		ActionsArtifacts: stat.ArtifactSize,
+		ActionsLogs:      stat.ActionLogSize,
+		Packages:         stat.PackageSize,
+		Updated:          stat.UpdatedUnix.AsTime(),
+	}
+	size.Total = size.Git + size.LFS + size.Attachments + size.ActionsArtifacts + size.ActionsLogs + size.Packages
+	ctx.JSON(http.StatusOK, size)
+}
@@ -0,0 +1,367 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/context/upload"
+	"code.gitea.io/gitea/services/convert"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+// getStagingSession returns the staging session of the authenticated user given in the path, it responds 404 if it doesn't exist
+func getStagingSession(ctx *context.APIContext) *repo_model.RepoStagingSession {
+	session, err := repo_model.GetRepoStagingSession(ctx, ctx.Repo.Repository.ID, ctx.Doer.ID, ctx.PathParam("id"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoStagingSession", err)
+		return nil
+	} else if session == nil {
+		ctx.NotFound()
+		return nil
+	}
+	return session
+}
+
+// CreateStagingSession starts a staging session to upload files in chunks and commit them together
+func CreateStagingSession(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/staging repository repoCreateStagingSession
+	// ---
+	// summary: Start a staging session to upload many or large files across multiple requests and commit them together
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateStagingSessionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/StagingSession"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	form := web.GetForm(ctx).(*api.CreateStagingSessionOption)
+	branch := util.IfZero(form.BranchName, ctx.Repo.Repository.DefaultBranch)
+	if !canWriteFiles(ctx, branch) {
+		ctx.Error(http.StatusForbidden, "CreateStagingSession", repo_model.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.Doer.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+
+	session, err := files_service.CreateStagingSession(ctx, ctx.Repo.GitRepo, ctx.Repo.Repository, ctx.Doer, branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateStagingSession", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToStagingSession(session, nil))
+}
+
+// GetStagingSession gets a staging session of the authenticated user with its staged files
+func GetStagingSession(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/staging/{id} repository repoGetStagingSession
+	// ---
+	// summary: Get a staging session and the size of its staged files, to resume an interrupted upload
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StagingSession"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	session := getStagingSession(ctx)
+	if ctx.Written() {
+		return
+	}
+	stagedFiles, err := files_service.GetStagedFiles(session)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStagedFiles", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStagingSession(session, stagedFiles))
+}
+
+// DeleteStagingSession discards a staging session of the authenticated user and its staged files
+func DeleteStagingSession(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/staging/{id} repository repoDeleteStagingSession
+	// ---
+	// summary: Discard a staging session and its staged files
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	session := getStagingSession(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := repo_model.DeleteRepoStagingSession(ctx, session); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRepoStagingSession", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadStagedFile appends a chunk of the request body to a file of a staging session
+func UploadStagedFile(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/staging/{id}/files/{filepath} repository repoUploadStagedFile
+	// ---
+	// summary: Upload a chunk of a file to a staging session
+	// description: The chunk is the raw request body, appended at `offset` which must be the size of the file staged so far.
+	// consumes:
+	// - application/octet-stream
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the file in the repository
+	//   type: string
+	//   required: true
+	// - name: offset
+	//   in: query
+	//   description: offset of the chunk in the file, 0 for its first chunk
+	//   type: integer
+	//   format: int64
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StagedFile"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	session := getStagingSession(ctx)
+	if ctx.Written() {
+		return
+	}
+	treePath := ctx.PathParam("*")
+	offset := ctx.FormInt64("offset")
+	maxSize := setting.Repository.Upload.FileMaxSize * 1024 * 1024
+	if offset < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "Invalid", "offset must not be negative")
+		return
+	}
+	if offset+ctx.Req.ContentLength > maxSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "UploadStagedFile", fmt.Errorf("the file is larger than %d MB", setting.Repository.Upload.FileMaxSize))
+		return
+	}
+
+	chunk := ctx.Req.Body
+	if offset == 0 {
+		// the type of the file is checked with its first chunk
+		buf := make([]byte, 1024)
+		n, err := util.ReadAtMost(chunk, buf)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ReadAtMost", err)
+			return
+		}
+		if err := upload.Verify(buf[:n], path.Base(treePath), setting.Repository.Upload.AllowedTypes); err != nil {
+			ctx.Error(http.StatusBadRequest, "DetectContentType", err)
+			return
+		}
+		chunk = io.NopCloser(io.MultiReader(bytes.NewReader(buf[:n]), chunk))
+	}
+
+	stagedFile, err := files_service.StageFileChunk(ctx, session, treePath, offset, chunk)
+	if err != nil {
+		switch {
+		case files_service.IsErrStagedFileOffsetMismatch(err):
+			ctx.Error(http.StatusConflict, "Conflict", err)
+		case files_service.IsErrStagedFileTooLarge(err):
+			ctx.Error(http.StatusRequestEntityTooLarge, "UploadStagedFile", fmt.Errorf("the file is larger than %d MB", setting.Repository.Upload.FileMaxSize))
+		default:
+			handleCreateOrUpdateFileError(ctx, err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStagedFile(stagedFile))
+}
+
+// CommitStagingSession commits the staged files of a staging session in a single commit
+func CommitStagingSession(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/staging/{id}/commit repository repoCommitStagingSession
+	// ---
+	// summary: Commit the staged files of a staging session in a single commit and end the session
+	// description: The staged files existing in the branch are updated, the others are created.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the staging session
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CommitStagingSessionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FilesResponse"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
+	//   "423":
+	//     "$ref": "#/responses/repoArchivedError"
+
+	session := getStagingSession(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !canWriteFiles(ctx, session.Branch) {
+		ctx.Error(http.StatusForbidden, "CommitStagingSession", repo_model.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.Doer.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+
+	apiOpts := web.GetForm(ctx).(*api.CommitStagingSessionOption)
+	opts := &files_service.ChangeRepoFilesOptions{
+		Message:   apiOpts.Message,
+		NewBranch: apiOpts.NewBranchName,
+		Committer: &files_service.IdentityOptions{
+			Name:  apiOpts.Committer.Name,
+			Email: apiOpts.Committer.Email,
+		},
+		Author: &files_service.IdentityOptions{
+			Name:  apiOpts.Author.Name,
+			Email: apiOpts.Author.Email,
+		},
+		Dates: &files_service.CommitDateOptions{
+			Author:    apiOpts.Dates.Author,
+			Committer: apiOpts.Dates.Committer,
+		},
+		Signoff:  apiOpts.Signoff,
+		Trailers: toCommitTrailers(apiOpts.Trailers),
+	}
+	if opts.Dates.Author.IsZero() {
+		opts.Dates.Author = time.Now()
+	}
+	if opts.Dates.Committer.IsZero() {
+		opts.Dates.Committer = time.Now()
+	}
+	if opts.Message == "" {
+		opts.Message = ctx.Locale.TrString("repo.editor.upload_files_to_dir", "/")
+	}
+
+	filesResponse, err := files_service.CommitStagingSession(ctx, ctx.Repo.Repository, ctx.Doer, session, opts)
+	if err != nil {
+		handleCreateOrUpdateFileError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, filesResponse)
+}
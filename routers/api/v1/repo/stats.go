@@ -0,0 +1,227 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/indexer/stats"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// checkCommitStatsReady responds with 202 and queues the computation of the commit statistics of the repository
+// if they have never been computed, the statistics are then updated on each push to the default branch.
+func checkCommitStatsReady(ctx *context.APIContext) bool {
+	status, err := repo_model.GetIndexerStatus(ctx, ctx.Repo.Repository, repo_model.RepoIndexerTypeCommitStats)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIndexerStatus", err)
+		return false
+	}
+	if status.CommitSha != "" {
+		return true
+	}
+	if !ctx.Repo.Repository.IsEmpty {
+		if err := stats.UpdateRepoIndexer(ctx.Repo.Repository); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepoIndexer", err)
+			return false
+		}
+	}
+	ctx.Status(http.StatusAccepted)
+	return false
+}
+
+// GetCodeFrequency get the weekly additions and deletions of a repository
+func GetCodeFrequency(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/code_frequency repository repoGetCodeFrequency
+	// ---
+	// summary: Get the lines added and deleted each week on the default branch of a repository
+	// description: The statistics are computed in the background, 202 is returned while they are being computed for the first time.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeFrequency"
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !checkCommitStatsReady(ctx) {
+		return
+	}
+
+	weeks, err := repo_model.GetRepoCodeFrequency(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoCodeFrequency", err)
+		return
+	}
+
+	apiWeeks := make([]*api.CodeFrequencyWeek, 0, len(weeks))
+	for _, week := range weeks {
+		apiWeeks = append(apiWeeks, &api.CodeFrequencyWeek{
+			Week:      time.Unix(week.Week, 0).UTC(),
+			Additions: week.Additions,
+			Deletions: week.Deletions,
+		})
+	}
+	ctx.JSON(http.StatusOK, apiWeeks)
+}
+
+// GetPunchCard get the number of commits of a repository by weekday and hour
+func GetPunchCard(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/punch_card repository repoGetPunchCard
+	// ---
+	// summary: Get the number of commits to the default branch of a repository for each hour of the week
+	// description: The statistics are computed in the background, 202 is returned while they are being computed for the first time.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PunchCard"
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !checkCommitStatsReady(ctx) {
+		return
+	}
+
+	cards, err := repo_model.GetRepoPunchCard(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoPunchCard", err)
+		return
+	}
+
+	apiCards := make([]*api.PunchCardEntry, 0, len(cards))
+	for _, card := range cards {
+		apiCards = append(apiCards, &api.PunchCardEntry{
+			Weekday: card.Weekday,
+			Hour:    card.Hour,
+			Commits: card.Commits,
+		})
+	}
+	ctx.JSON(http.StatusOK, apiCards)
+}
+
+// GetContributorsStats get the commit statistics of the contributors of a repository
+func GetContributorsStats(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/contributors repository repoGetContributorsStats
+	// ---
+	// summary: Get the statistics of the commits of the contributors to the default branch of a repository
+	// description: The statistics are computed in the background, 202 is returned while they are being computed for the first time.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only count the commits of the weeks starting at or after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only count the commits of the weeks starting before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ContributorStatsList"
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	before, since, err := context.GetQueryBeforeSince(ctx.Base)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	if !checkCommitStatsReady(ctx) {
+		return
+	}
+
+	totals, err := repo_model.GetRepoContributorTotals(ctx, ctx.Repo.Repository.ID, since, before)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoContributorTotals", err)
+		return
+	}
+
+	// the emails of a user are counted together
+	apiStats := make([]*api.ContributorStats, 0, len(totals))
+	userStats := make(map[int64]*api.ContributorStats, len(totals))
+	for _, total := range totals {
+		u, err := user_model.GetUserByEmail(ctx, total.Email)
+		if err != nil && !user_model.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetUserByEmail", err)
+			return
+		}
+		if u != nil {
+			if stat, ok := userStats[u.ID]; ok {
+				stat.Commits += total.Commits
+				stat.Additions += total.Additions
+				stat.Deletions += total.Deletions
+				continue
+			}
+		}
+
+		stat := &api.ContributorStats{
+			Name:      total.Name,
+			Email:     total.Email,
+			Commits:   total.Commits,
+			Additions: total.Additions,
+			Deletions: total.Deletions,
+		}
+		if u != nil {
+			stat.User = convert.ToUser(ctx, u, ctx.Doer)
+			userStats[u.ID] = stat
+		}
+		apiStats = append(apiStats, stat)
+	}
+	slices.SortStableFunc(apiStats, func(a, b *api.ContributorStats) int {
+		return cmp.Compare(b.Commits, a.Commits)
+	})
+	ctx.JSON(http.StatusOK, apiStats)
+}
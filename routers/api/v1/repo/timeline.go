@@ -0,0 +1,232 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetTimeline lists the issue, pull request and commit events of a repository following a cursor
+func GetTimeline(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/timeline repository repoGetTimeline
+	// ---
+	// summary: List the issue, pull request and commit events of a repository in the order they happened
+	// description: The events following a cursor are returned, so that an integration can keep its state in sync
+	//   without polling every issue. The events the user can't read are left out.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: the cursor of the last received event, the events following it are returned
+	//   type: integer
+	//   format: int64
+	// - name: types
+	//   in: query
+	//   description: the kinds of events to return, all the kinds if empty
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//     enum: [issue, pull_request, commit]
+	// - name: limit
+	//   in: query
+	//   description: maximum number of events to return
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoTimeline"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := getTimelineOptions(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	// request one more event to tell whether there are more
+	limit := opts.Limit
+	opts.Limit++
+	actions, err := activities_model.GetRepoTimeline(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoTimeline", err)
+		return
+	}
+
+	timeline := &api.RepoTimeline{
+		Events:  make([]*api.TimelineEvent, 0, min(len(actions), limit)),
+		Cursor:  opts.Since,
+		HasMore: len(actions) > limit,
+	}
+	for i, action := range actions {
+		if i == limit {
+			break
+		}
+		timeline.Events = append(timeline.Events, convert.ToTimelineEvent(ctx, action, ctx.Doer))
+		timeline.Cursor = action.ID
+	}
+	ctx.JSON(http.StatusOK, timeline)
+}
+
+// StreamTimeline delivers the events of the timeline of a repository as server-sent events
+func StreamTimeline(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/timeline/stream repository repoStreamTimeline
+	// ---
+	// summary: Stream the issue, pull request and commit events of a repository as server-sent events
+	// description: The events following the cursor are sent, then the new events as they happen. The name of a sent
+	//   event is its type, its id is its cursor and its data is the event as JSON. A reconnecting client resumes
+	//   after the `Last-Event-ID` header.
+	// produces:
+	// - text/event-stream
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: the cursor of the last received event, the events following it are sent
+	//   type: integer
+	//   format: int64
+	// - name: types
+	//   in: query
+	//   description: the kinds of events to send, all the kinds if empty
+	//   type: array
+	//   collectionFormat: multi
+	//   items:
+	//     type: string
+	//     enum: [issue, pull_request, commit]
+	// responses:
+	//   "200":
+	//     description: the stream of the events
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := getTimelineOptions(ctx)
+	if ctx.Written() {
+		return
+	}
+	if lastEventID := ctx.Req.Header.Get("Last-Event-ID"); lastEventID != "" {
+		if since, err := strconv.ParseInt(lastEventID, 10, 64); err == nil {
+			opts.Since = since
+		}
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	ctx.Resp.Header().Set("Connection", "keep-alive")
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	ctx.Resp.Flush()
+
+	shutdownCtx := graceful.GetManager().ShutdownContext()
+	poll := time.NewTicker(setting.UI.Notification.EventSourceUpdateTime)
+	defer poll.Stop()
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+
+	for {
+		for {
+			actions, err := activities_model.GetRepoTimeline(ctx, opts)
+			if err != nil {
+				log.Error("GetRepoTimeline: %v", err)
+				return
+			}
+			for _, action := range actions {
+				event := &eventsource.Event{
+					Name: string(action.TimelineEventType()),
+					ID:   strconv.FormatInt(action.ID, 10),
+					Data: convert.ToTimelineEvent(ctx, action, ctx.Doer),
+				}
+				if _, err := event.WriteTo(ctx.Resp); err != nil {
+					log.Error("Unable to write to the timeline stream of %s: %v", ctx.Repo.Repository.FullName(), err)
+					return
+				}
+				opts.Since = action.ID
+			}
+			ctx.Resp.Flush()
+			if len(actions) < opts.Limit {
+				break
+			}
+		}
+
+		select {
+		case <-poll.C:
+		case <-ping.C:
+			if _, err := (&eventsource.Event{Name: "ping"}).WriteTo(ctx.Resp); err != nil {
+				return
+			}
+			ctx.Resp.Flush()
+		case <-ctx.Done():
+			return
+		case <-shutdownCtx.Done():
+			return
+		}
+	}
+}
+
+func getTimelineOptions(ctx *context.APIContext) activities_model.RepoTimelineOptions {
+	opts := activities_model.RepoTimelineOptions{
+		Repo:  ctx.Repo.Repository,
+		Actor: ctx.Doer,
+		Since: ctx.FormInt64("since"),
+		Limit: utils.GetListOptions(ctx).PageSize,
+	}
+
+	requested := ctx.FormStrings("types")
+	if len(requested) == 0 {
+		for _, t := range activities_model.TimelineEventTypes {
+			requested = append(requested, string(t))
+		}
+	}
+	for _, name := range requested {
+		t := activities_model.TimelineEventType(name)
+		if !t.IsValid() {
+			ctx.Error(http.StatusUnprocessableEntity, "", "unknown event type: "+name)
+			return opts
+		}
+		// leave out the events the user can't read
+		switch {
+		case t == activities_model.TimelineEventIssue && !ctx.Repo.CanRead(unit.TypeIssues),
+			t == activities_model.TimelineEventPullRequest && !ctx.Repo.CanRead(unit.TypePullRequests),
+			t == activities_model.TimelineEventCommit && !ctx.Repo.CanRead(unit.TypeCode):
+			continue
+		}
+		opts.Types = append(opts.Types, t)
+	}
+	return opts
+}
@@ -38,6 +38,21 @@ func GetTree(ctx *context.APIContext) {
 	//   description: show all directories and files
 	//   required: false
 	//   type: boolean
+	// - name: path_prefix
+	//   in: query
+	//   description: directory of the tree to list, the paths of the entries are still relative to the root of the tree
+	//   required: false
+	//   type: string
+	// - name: depth
+	//   in: query
+	//   description: number of levels of directories to list, it implies recursive; all the levels are listed if it is 0
+	//   required: false
+	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: path of the last entry of the previous page, given by 'next_cursor'; the entries after it are listed instead of a page
+	//   required: false
+	//   type: string
 	// - name: page
 	//   in: query
 	//   description: page number; the 'truncated' field in the response will be true if there are still more items after this page, false if the last page
@@ -61,7 +76,18 @@ func GetTree(ctx *context.APIContext) {
 		ctx.Error(http.StatusBadRequest, "", "sha not provided")
 		return
 	}
-	if tree, err := files_service.GetTreeBySHA(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, sha, ctx.FormInt("page"), ctx.FormInt("per_page"), ctx.FormBool("recursive")); err != nil {
+	if ctx.FormInt("depth") < 0 {
+		ctx.Error(http.StatusBadRequest, "", "depth must not be negative")
+		return
+	}
+	if tree, err := files_service.GetTreeBySHA(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, sha, files_service.GetTreeOptions{
+		Page:       ctx.FormInt("page"),
+		PerPage:    ctx.FormInt("per_page"),
+		Recursive:  ctx.FormBool("recursive"),
+		PathPrefix: ctx.FormString("path_prefix"),
+		Depth:      ctx.FormInt("depth"),
+		Cursor:     ctx.FormString("cursor"),
+	}); err != nil {
 		ctx.Error(http.StatusBadRequest, "", err.Error())
 	} else {
 		ctx.SetTotalCountHeader(int64(tree.TotalCount))
@@ -0,0 +1,184 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	secret_model "code.gitea.io/gitea/models/secret"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	secret_service "code.gitea.io/gitea/services/secrets"
+)
+
+// The secrets and the variables of the instance are bound to neither an owner nor a repository,
+// the ones of an environment are bound to a repository and the environment.
+
+func actionVariableError(ctx *context.APIContext, name string, err error) {
+	switch {
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.Error(http.StatusBadRequest, name, err)
+	case errors.Is(err, util.ErrNotExist):
+		ctx.Error(http.StatusNotFound, name, err)
+	default:
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
+
+// ListActionsSecrets lists the secrets of the scope
+func ListActionsSecrets(ctx *context.APIContext, ownerID, repoID int64, environment string) {
+	secrets, count, err := db.FindAndCount[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Environment: environment,
+	})
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiSecrets := make([]*api.Secret, len(secrets))
+	for i, s := range secrets {
+		apiSecrets[i] = &api.Secret{
+			Name:    s.Name,
+			Created: s.CreatedUnix.AsTime(),
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiSecrets)
+}
+
+// CreateOrUpdateActionsSecret creates or updates a secret of the scope
+func CreateOrUpdateActionsSecret(ctx *context.APIContext, ownerID, repoID int64, environment string) {
+	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
+
+	var created bool
+	var err error
+	if environment != "" {
+		_, created, err = secret_service.CreateOrUpdateEnvironmentSecret(ctx, ctx.Doer, repoID, environment, ctx.PathParam("secretname"), opt.Data)
+	} else {
+		_, created, err = secret_service.CreateOrUpdateSecret(ctx, ctx.Doer, ownerID, repoID, ctx.PathParam("secretname"), opt.Data)
+	}
+	if err != nil {
+		actionVariableError(ctx, "CreateOrUpdateSecret", err)
+		return
+	}
+
+	if created {
+		ctx.Status(http.StatusCreated)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// DeleteActionsSecret deletes a secret of the scope
+func DeleteActionsSecret(ctx *context.APIContext, ownerID, repoID int64, environment string) {
+	var err error
+	if environment != "" {
+		err = secret_service.DeleteEnvironmentSecretByName(ctx, ctx.Doer, repoID, environment, ctx.PathParam("secretname"))
+	} else {
+		err = secret_service.DeleteSecretByName(ctx, ctx.Doer, ownerID, repoID, ctx.PathParam("secretname"))
+	}
+	if err != nil {
+		actionVariableError(ctx, "DeleteSecret", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListActionsVariables lists the variables of the scope
+func ListActionsVariables(ctx *context.APIContext, ownerID, repoID int64, environment string) {
+	vars, count, err := db.FindAndCount[actions_model.ActionVariable](ctx, &actions_model.FindVariablesOpts{
+		ListOptions: utils.GetListOptions(ctx),
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Environment: environment,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindVariables", err)
+		return
+	}
+
+	variables := make([]*api.ActionVariable, len(vars))
+	for i, v := range vars {
+		variables[i] = &api.ActionVariable{
+			OwnerID:     v.OwnerID,
+			RepoID:      v.RepoID,
+			Environment: v.Environment,
+			Name:        v.Name,
+			Data:        v.Data,
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, variables)
+}
+
+// CreateOrUpdateActionsVariable creates or updates a variable of the scope
+func CreateOrUpdateActionsVariable(ctx *context.APIContext, ownerID, repoID int64, environment string) {
+	opt := web.GetForm(ctx).(*api.CreateVariableOption)
+	name := ctx.PathParam("variablename")
+
+	v, err := actions_service.GetVariable(ctx, actions_model.FindVariablesOpts{
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Environment: environment,
+		Name:        name,
+	})
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.Error(http.StatusInternalServerError, "GetVariable", err)
+		return
+	}
+
+	if v != nil {
+		if _, err := actions_service.UpdateVariable(ctx, v.ID, v.Name, opt.Value); err != nil {
+			actionVariableError(ctx, "UpdateVariable", err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	if environment != "" {
+		_, err = actions_service.CreateEnvironmentVariable(ctx, repoID, environment, name, opt.Value)
+	} else {
+		_, err = actions_service.CreateVariable(ctx, ownerID, repoID, name, opt.Value)
+	}
+	if err != nil {
+		actionVariableError(ctx, "CreateVariable", err)
+		return
+	}
+	ctx.Status(http.StatusCreated)
+}
+
+// DeleteActionsVariable deletes a variable of the scope
+func DeleteActionsVariable(ctx *context.APIContext, ownerID, repoID int64, environment string) {
+	v, err := actions_service.GetVariable(ctx, actions_model.FindVariablesOpts{
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Environment: environment,
+		Name:        ctx.PathParam("variablename"),
+	})
+	if err != nil {
+		actionVariableError(ctx, "GetVariable", err)
+		return
+	}
+
+	if err := actions_service.DeleteVariableByID(ctx, v.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteVariableByID", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
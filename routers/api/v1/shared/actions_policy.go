@@ -0,0 +1,53 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetActionsPolicy responds with the actions policy of the owner, or of the instance for 0
+func GetActionsPolicy(ctx *context.APIContext, ownerID int64) {
+	policy, err := actions_model.GetActionPolicy(ctx, ownerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetActionPolicy", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionsPolicy(policy))
+}
+
+// EditActionsPolicy replaces the actions policy of the owner, or of the instance for 0
+func EditActionsPolicy(ctx *context.APIContext, ownerID int64) {
+	form := web.GetForm(ctx).(*api.EditActionsPolicyOption)
+	mode, ok := actions_model.ParseActionsPolicyMode(form.AllowedActions)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid allowed_actions")
+		return
+	}
+
+	policy := &actions_model.ActionPolicy{
+		OwnerID:           ownerID,
+		Mode:              mode,
+		AllowedPatterns:   form.PatternsAllowed,
+		BlockedPatterns:   form.PatternsBlocked,
+		RequireSHAPinning: form.RequireSHAPinning,
+	}
+	if err := actions_model.SetActionPolicy(ctx, policy); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetActionPolicy", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetActionPolicy", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionsPolicy(policy))
+}
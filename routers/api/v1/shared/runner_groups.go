@@ -0,0 +1,433 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+func runnerGroupError(ctx *context.APIContext, name string, err error) {
+	switch {
+	case errors.Is(err, util.ErrNotExist):
+		ctx.Error(http.StatusNotFound, name, err)
+	case errors.Is(err, util.ErrAlreadyExist):
+		ctx.Error(http.StatusConflict, name, err)
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.Error(http.StatusUnprocessableEntity, name, err)
+	default:
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
+
+// getRunnerGroup returns the runner group of the owner from the path, it writes the error response if it fails
+func getRunnerGroup(ctx *context.APIContext, ownerID int64) *actions_model.ActionRunnerGroup {
+	group, err := actions_model.GetRunnerGroupByID(ctx, ownerID, ctx.PathParamInt64("group_id"))
+	if err != nil {
+		runnerGroupError(ctx, "GetRunnerGroupByID", err)
+		return nil
+	}
+	return group
+}
+
+// getOwnerRunner returns the runner of the owner from the path, the runners of repositories can't be managed here
+func getOwnerRunner(ctx *context.APIContext, ownerID int64) *actions_model.ActionRunner {
+	runner, err := actions_model.GetRunnerByID(ctx, ctx.PathParamInt64("runner_id"))
+	if err == nil && (runner.OwnerID != ownerID || runner.RepoID != 0) {
+		err = util.NewNotExistErrorf("runner %d doesn't exist", runner.ID)
+	}
+	if err != nil {
+		runnerGroupError(ctx, "GetRunnerByID", err)
+		return nil
+	}
+	return runner
+}
+
+// ListRunnerGroups lists the runner groups of an organization, or the groups of global runners for ownerID 0
+func ListRunnerGroups(ctx *context.APIContext, ownerID int64) {
+	groups, count, err := db.FindAndCount[actions_model.ActionRunnerGroup](ctx, actions_model.FindRunnerGroupOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRunnerGroups", err)
+		return
+	}
+
+	res := &api.ActionRunnerGroupsResponse{
+		Entries:    make([]*api.ActionRunnerGroup, 0, len(groups)),
+		TotalCount: count,
+	}
+	for _, group := range groups {
+		res.Entries = append(res.Entries, convert.ToActionRunnerGroup(group))
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreateRunnerGroup creates a runner group of the owner
+func CreateRunnerGroup(ctx *context.APIContext, ownerID int64) {
+	form := web.GetForm(ctx).(*api.CreateActionRunnerGroupOption)
+	visibility, _ := actions_model.ParseRunnerGroupVisibility(form.Visibility)
+	group := &actions_model.ActionRunnerGroup{
+		OwnerID:     ownerID,
+		Name:        form.Name,
+		Description: form.Description,
+		Visibility:  visibility,
+	}
+	if err := actions_model.CreateRunnerGroup(ctx, group); err != nil {
+		runnerGroupError(ctx, "CreateRunnerGroup", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToActionRunnerGroup(group))
+}
+
+// GetRunnerGroup returns a runner group of the owner
+func GetRunnerGroup(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionRunnerGroup(group))
+}
+
+// EditRunnerGroup edits a runner group of the owner
+func EditRunnerGroup(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditActionRunnerGroupOption)
+	cols := make([]string, 0, 3)
+	if form.Name != nil {
+		if *form.Name == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "EditRunnerGroup", "the name can't be empty")
+			return
+		}
+		group.Name = *form.Name
+		cols = append(cols, "name")
+	}
+	if form.Description != nil {
+		group.Description = *form.Description
+		cols = append(cols, "description")
+	}
+	if form.Visibility != nil {
+		visibility, ok := actions_model.ParseRunnerGroupVisibility(*form.Visibility)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "EditRunnerGroup", "invalid visibility")
+			return
+		}
+		group.Visibility = visibility
+		cols = append(cols, "visibility")
+	}
+	if len(cols) > 0 {
+		if err := actions_model.UpdateRunnerGroup(ctx, group, cols...); err != nil {
+			runnerGroupError(ctx, "UpdateRunnerGroup", err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionRunnerGroup(group))
+}
+
+// DeleteRunnerGroup deletes a runner group of the owner, its runners aren't in a group anymore
+func DeleteRunnerGroup(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.DeleteRunnerGroup(ctx, group); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRunnerGroup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListRunnerGroupRunners lists the runners of a runner group
+func ListRunnerGroupRunners(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+
+	runners, count, err := db.FindAndCount[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		GroupID:     group.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRunners", err)
+		return
+	}
+
+	res := &api.ActionRunnersResponse{
+		Entries:    make([]*api.ActionRunner, 0, len(runners)),
+		TotalCount: count,
+	}
+	for _, runner := range runners {
+		res.Entries = append(res.Entries, convert.ToActionRunner(runner))
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// AddRunnerGroupRunner moves a runner of the owner to the runner group
+func AddRunnerGroupRunner(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	runner := getOwnerRunner(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.AddRunnerToGroup(ctx, group, runner); err != nil {
+		runnerGroupError(ctx, "AddRunnerToGroup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveRunnerGroupRunner removes a runner from the runner group
+func RemoveRunnerGroupRunner(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	runner := getOwnerRunner(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.RemoveRunnerFromGroup(ctx, group, runner); err != nil {
+		runnerGroupError(ctx, "RemoveRunnerFromGroup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListRunnerGroupRepos lists the repositories a runner group is granted to
+func ListRunnerGroupRepos(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+
+	repoIDs, err := actions_model.GetRunnerGroupRepoIDs(ctx, group.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunnerGroupRepoIDs", err)
+		return
+	}
+	reposMap, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	repos := make([]*api.Repository, 0, len(repoIDs))
+	for _, repoID := range repoIDs {
+		repo, ok := reposMap[repoID]
+		if !ok {
+			continue
+		}
+		permission, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		repos = append(repos, convert.ToRepo(ctx, repo, permission))
+	}
+	ctx.SetTotalCountHeader(int64(len(repos)))
+	ctx.JSON(http.StatusOK, repos)
+}
+
+// AddRunnerGroupRepo grants a runner group to a repository
+func AddRunnerGroupRepo(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, ctx.PathParamInt64("repo_id"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		}
+		return
+	}
+	if err := actions_model.AddRepoToRunnerGroup(ctx, group, repo); err != nil {
+		runnerGroupError(ctx, "AddRepoToRunnerGroup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveRunnerGroupRepo revokes the access of a repository to a runner group
+func RemoveRunnerGroupRepo(ctx *context.APIContext, ownerID int64) {
+	group := getRunnerGroup(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.RemoveRepoFromRunnerGroup(ctx, group, ctx.PathParamInt64("repo_id")); err != nil {
+		runnerGroupError(ctx, "RemoveRepoFromRunnerGroup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListRunnerGroupOrgs lists the organizations a group of global runners is granted to
+func ListRunnerGroupOrgs(ctx *context.APIContext) {
+	group := getRunnerGroup(ctx, 0)
+	if ctx.Written() {
+		return
+	}
+
+	orgIDs, err := actions_model.GetRunnerGroupOrgIDs(ctx, group.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunnerGroupOrgIDs", err)
+		return
+	}
+	orgs := make([]*api.Organization, 0, len(orgIDs))
+	for _, orgID := range orgIDs {
+		org, err := organization.GetOrgByID(ctx, orgID)
+		if user_model.IsErrUserNotExist(err) {
+			continue
+		} else if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetOrgByID", err)
+			return
+		}
+		orgs = append(orgs, convert.ToOrganization(ctx, org))
+	}
+	ctx.SetTotalCountHeader(int64(len(orgs)))
+	ctx.JSON(http.StatusOK, orgs)
+}
+
+// AddRunnerGroupOrg grants a group of global runners to all the repositories of an organization
+func AddRunnerGroupOrg(ctx *context.APIContext) {
+	group := getRunnerGroup(ctx, 0)
+	if ctx.Written() {
+		return
+	}
+	org, err := organization.GetOrgByID(ctx, ctx.PathParamInt64("org_id"))
+	if err == nil && !org.AsUser().IsOrganization() {
+		err = user_model.ErrUserNotExist{UID: org.ID}
+	}
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetOrgByID", err)
+		}
+		return
+	}
+	if err := actions_model.AddOrgToRunnerGroup(ctx, group, org.ID); err != nil {
+		runnerGroupError(ctx, "AddOrgToRunnerGroup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveRunnerGroupOrg revokes the access of an organization to a group of global runners
+func RemoveRunnerGroupOrg(ctx *context.APIContext) {
+	group := getRunnerGroup(ctx, 0)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_model.RemoveOrgFromRunnerGroup(ctx, group, ctx.PathParamInt64("org_id")); err != nil {
+		runnerGroupError(ctx, "RemoveOrgFromRunnerGroup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func writeRunnerLabels(ctx *context.APIContext, status int, runner *actions_model.ActionRunner) {
+	labels := convert.ToActionRunnerLabels(runner)
+	ctx.JSON(status, &api.ActionRunnerLabelsResponse{
+		Labels:     labels,
+		TotalCount: int64(len(labels)),
+	})
+}
+
+// ListRunnerLabels lists the labels of a runner of the owner
+func ListRunnerLabels(ctx *context.APIContext, ownerID int64) {
+	runner := getOwnerRunner(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	writeRunnerLabels(ctx, http.StatusOK, runner)
+}
+
+// SetRunnerLabels replaces the custom labels of a runner of the owner
+func SetRunnerLabels(ctx *context.APIContext, ownerID int64) {
+	runner := getOwnerRunner(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*api.ActionRunnerLabelsOption)
+	runner.CustomLabels = make([]string, 0, len(form.Labels))
+	updateRunnerCustomLabels(ctx, runner, form.Labels)
+}
+
+// AddRunnerLabels adds custom labels to a runner of the owner
+func AddRunnerLabels(ctx *context.APIContext, ownerID int64) {
+	runner := getOwnerRunner(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*api.ActionRunnerLabelsOption)
+	updateRunnerCustomLabels(ctx, runner, form.Labels)
+}
+
+func updateRunnerCustomLabels(ctx *context.APIContext, runner *actions_model.ActionRunner, labels []string) {
+	for _, label := range labels {
+		if label == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateRunnerLabels", "a label can't be empty")
+			return
+		}
+		if !slices.Contains(runner.CustomLabels, label) {
+			runner.CustomLabels = append(runner.CustomLabels, label)
+		}
+	}
+	if err := actions_model.UpdateRunner(ctx, runner, "custom_labels"); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRunner", err)
+		return
+	}
+	writeRunnerLabels(ctx, http.StatusOK, runner)
+}
+
+// RemoveRunnerLabel removes a custom label from a runner of the owner, the labels declared by the runner can't be removed
+func RemoveRunnerLabel(ctx *context.APIContext, ownerID int64) {
+	runner := getOwnerRunner(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	name := ctx.PathParam("name")
+	if !slices.Contains(runner.CustomLabels, name) {
+		if slices.Contains(runner.AgentLabels, name) {
+			ctx.Error(http.StatusUnprocessableEntity, "RemoveRunnerLabel", "the labels declared by the runner are read-only")
+		} else {
+			ctx.NotFound()
+		}
+		return
+	}
+	runner.CustomLabels = slices.DeleteFunc(runner.CustomLabels, func(label string) bool { return label == name })
+	if err := actions_model.UpdateRunner(ctx, runner, "custom_labels"); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRunner", err)
+		return
+	}
+	writeRunnerLabels(ctx, http.StatusOK, runner)
+}
@@ -6,10 +6,20 @@ package shared
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+const (
+	defaultEphemeralRunnerTokenTTL = time.Hour
+	maxEphemeralRunnerTokenTTL     = 24 * time.Hour
 )
 
 // RegistrationToken is response related to registration token
@@ -30,3 +40,50 @@ func GetRegistrationToken(ctx *context.APIContext, ownerID, repoID int64) {
 
 	ctx.JSON(http.StatusOK, RegistrationToken{Token: token.Token})
 }
+
+// CreateEphemeralRegistrationToken creates a token registering a single ephemeral runner of the owner and the repository
+func CreateEphemeralRegistrationToken(ctx *context.APIContext, ownerID, repoID int64) {
+	form := web.GetForm(ctx).(*api.CreateActionEphemeralRunnerTokenOption)
+	ttl := time.Duration(form.TTL) * time.Second
+	if form.TTL < 0 || ttl > maxEphemeralRunnerTokenTTL {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the ttl must be between 0 and a day")
+		return
+	}
+	if ttl == 0 {
+		ttl = defaultEphemeralRunnerTokenTTL
+	}
+
+	token, err := actions_model.NewEphemeralRunnerToken(ctx, ownerID, repoID, ttl)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ActionEphemeralRunnerToken{
+		Token:     token.Token,
+		ExpiresAt: token.Expires.AsTime(),
+	})
+}
+
+// ListQueuedJobs lists the jobs of the owner waiting for a runner by set of labels, or the ones of the instance for 0
+func ListQueuedJobs(ctx *context.APIContext, ownerID int64) {
+	queued, err := actions_model.GetQueuedJobs(ctx, ownerID, 0)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetQueuedJobs", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToActionQueuedJobs(queued))
+}
+
+// ExpireRunner removes a runner of the owner at once, the tasks it's running fail
+func ExpireRunner(ctx *context.APIContext, ownerID int64) {
+	runner := getOwnerRunner(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.ExpireRunner(ctx, runner); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ExpireRunner", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
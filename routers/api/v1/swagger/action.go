@@ -32,3 +32,59 @@ type swaggerResponseVariableList struct {
 	// in:body
 	Body []api.ActionVariable `json:"body"`
 }
+
+// ActionRunnerGroup
+// swagger:response ActionRunnerGroup
+type swaggerResponseActionRunnerGroup struct {
+	// in:body
+	Body api.ActionRunnerGroup `json:"body"`
+}
+
+// ActionRunnerGroupsList
+// swagger:response ActionRunnerGroupsList
+type swaggerResponseActionRunnerGroupsList struct {
+	// in:body
+	Body api.ActionRunnerGroupsResponse `json:"body"`
+}
+
+// ActionEphemeralRunnerToken
+// swagger:response ActionEphemeralRunnerToken
+type swaggerResponseActionEphemeralRunnerToken struct {
+	// in:body
+	Body api.ActionEphemeralRunnerToken `json:"body"`
+}
+
+// ActionQueuedJobsList
+// swagger:response ActionQueuedJobsList
+type swaggerResponseActionQueuedJobsList struct {
+	// in:body
+	Body api.ActionQueuedJobsResponse `json:"body"`
+}
+
+// ActionsPolicy
+// swagger:response ActionsPolicy
+type swaggerResponseActionsPolicy struct {
+	// in:body
+	Body api.ActionsPolicy `json:"body"`
+}
+
+// ActionRunnersList
+// swagger:response ActionRunnersList
+type swaggerResponseActionRunnersList struct {
+	// in:body
+	Body api.ActionRunnersResponse `json:"body"`
+}
+
+// ActionRunnerLabelsList
+// swagger:response ActionRunnerLabelsList
+type swaggerResponseActionRunnerLabelsList struct {
+	// in:body
+	Body api.ActionRunnerLabelsResponse `json:"body"`
+}
+
+// ActionEffectiveVariables
+// swagger:response ActionEffectiveVariables
+type swaggerResponseActionEffectiveVariables struct {
+	// in:body
+	Body api.ActionEffectiveVariables `json:"body"`
+}
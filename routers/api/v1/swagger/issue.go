@@ -56,6 +56,76 @@ type swaggerResponseLabelList struct {
 	Body []api.Label `json:"body"`
 }
 
+// IssueType
+// swagger:response IssueType
+type swaggerResponseIssueType struct {
+	// in:body
+	Body api.IssueType `json:"body"`
+}
+
+// IssueTypeList
+// swagger:response IssueTypeList
+type swaggerResponseIssueTypeList struct {
+	// in:body
+	Body []api.IssueType `json:"body"`
+}
+
+// IssueFilter
+// swagger:response IssueFilter
+type swaggerResponseIssueFilter struct {
+	// in:body
+	Body api.IssueFilter `json:"body"`
+}
+
+// IssueFilterList
+// swagger:response IssueFilterList
+type swaggerResponseIssueFilterList struct {
+	// in:body
+	Body []api.IssueFilter `json:"body"`
+}
+
+// IssueAutomationRule
+// swagger:response IssueAutomationRule
+type swaggerResponseIssueAutomationRule struct {
+	// in:body
+	Body api.IssueAutomationRule `json:"body"`
+}
+
+// IssueAutomationRuleList
+// swagger:response IssueAutomationRuleList
+type swaggerResponseIssueAutomationRuleList struct {
+	// in:body
+	Body []api.IssueAutomationRule `json:"body"`
+}
+
+// IssueAutomationLogList
+// swagger:response IssueAutomationLogList
+type swaggerResponseIssueAutomationLogList struct {
+	// in:body
+	Body []api.IssueAutomationLog `json:"body"`
+}
+
+// IssueField
+// swagger:response IssueField
+type swaggerResponseIssueField struct {
+	// in:body
+	Body api.IssueField `json:"body"`
+}
+
+// IssueFieldList
+// swagger:response IssueFieldList
+type swaggerResponseIssueFieldList struct {
+	// in:body
+	Body []api.IssueField `json:"body"`
+}
+
+// IssueFieldValueList
+// swagger:response IssueFieldValueList
+type swaggerResponseIssueFieldValueList struct {
+	// in:body
+	Body []api.IssueFieldValue `json:"body"`
+}
+
 // Milestone
 // swagger:response Milestone
 type swaggerResponseMilestone struct {
@@ -70,6 +140,13 @@ type swaggerResponseMilestoneList struct {
 	Body []api.Milestone `json:"body"`
 }
 
+// MilestoneBurndown
+// swagger:response MilestoneBurndown
+type swaggerResponseMilestoneBurndown struct {
+	// in:body
+	Body api.MilestoneBurndown `json:"body"`
+}
+
 // TrackedTime
 // swagger:response TrackedTime
 type swaggerResponseTrackedTime struct {
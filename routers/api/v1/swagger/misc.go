@@ -62,3 +62,17 @@ type swaggerResponseLabelTemplateInfo struct {
 	// in:body
 	Body []api.LabelTemplate `json:"body"`
 }
+
+// OptionTemplate
+// swagger:response OptionTemplate
+type swaggerResponseOptionTemplate struct {
+	// in:body
+	Body api.OptionTemplate `json:"body"`
+}
+
+// OptionTemplateList
+// swagger:response OptionTemplateList
+type swaggerResponseOptionTemplateList struct {
+	// in:body
+	Body []api.OptionTemplate `json:"body"`
+}
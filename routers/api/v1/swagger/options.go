@@ -113,6 +113,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditUserOption api.EditUserOption
 
+	// in:body
+	CreateExternalAttachmentOption api.CreateExternalAttachmentOption
+
 	// in:body
 	EditAttachmentOptions api.EditAttachmentOptions
 
@@ -155,9 +158,18 @@ type swaggerParameterBodies struct {
 	// in:body
 	CreatePullReviewComment api.CreatePullReviewComment
 
+	// in:body
+	EditPullReviewCommentOptions api.EditPullReviewCommentOptions
+
+	// in:body
+	ApplyPullReviewSuggestionsOptions api.ApplyPullReviewSuggestionsOptions
+
 	// in:body
 	SubmitPullReviewOptions api.SubmitPullReviewOptions
 
+	// in:body
+	UpdatePullReviewViewedFilesOptions api.UpdatePullReviewViewedFilesOptions
+
 	// in:body
 	DismissPullReviewOptions api.DismissPullReviewOptions
 
@@ -170,6 +182,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	CreateTagOption api.CreateTagOption
 
+	// in:body
+	SetNoteOption api.SetNoteOption
+
 	// in:body
 	CreateTagProtectionOption api.CreateTagProtectionOption
 
@@ -185,6 +200,15 @@ type swaggerParameterBodies struct {
 	// in:body
 	CreateWikiPageOptions api.CreateWikiPageOptions
 
+	// in:body
+	MoveWikiPageOptions api.MoveWikiPageOptions
+
+	// in:body
+	RestoreWikiPageOptions api.RestoreWikiPageOptions
+
+	// in:body
+	EditWikiSectionOptions api.EditWikiSectionOptions
+
 	// in:body
 	CreatePushMirrorOption api.CreatePushMirrorOption
 
@@ -205,4 +229,115 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	UpdateVariableOption api.UpdateVariableOption
+
+	// in:body
+	CreateOrUpdateCustomPropertyOption api.CreateOrUpdateCustomPropertyOption
+
+	// in:body
+	UpdateCustomPropertyValuesOption api.UpdateCustomPropertyValuesOption
+
+	// in:body
+	EditBranchProtectionStatusChecksOption api.EditBranchProtectionStatusChecksOption
+
+	// in:body
+	EditManagedGitHookOption api.EditManagedGitHookOption
+
+	// in:body
+	EditOptionTemplateOption api.EditOptionTemplateOption
+
+	// in:body
+	StartBisectOption api.StartBisectOption
+
+	// in:body
+	BisectVerdictOption api.BisectVerdictOption
+
+	// in:body
+	ApplyDiffPatchFileOptions api.ApplyDiffPatchFileOptions
+
+	// in:body
+	CreateStagingSessionOption api.CreateStagingSessionOption
+
+	// in:body
+	CommitStagingSessionOption api.CommitStagingSessionOption
+
+	// in:body
+	RebaseBranchOption api.RebaseBranchOption
+
+	// in:body
+	RestoreBranchOption api.RestoreBranchOption
+
+	// in:body
+	AddToMergeQueueOption api.AddToMergeQueueOption
+
+	// in:body
+	BatchPullRequestOption api.BatchPullRequestOption
+
+	// in:body
+	CreateIssueTypeOption api.CreateIssueTypeOption
+
+	// in:body
+	EditIssueTypeOption api.EditIssueTypeOption
+
+	// in:body
+	CreateIssueFilterOption api.CreateIssueFilterOption
+
+	// in:body
+	EditIssueFilterOption api.EditIssueFilterOption
+
+	// in:body
+	CreateIssueAutomationRuleOption api.CreateIssueAutomationRuleOption
+
+	// in:body
+	EditIssueAutomationRuleOption api.EditIssueAutomationRuleOption
+
+	// in:body
+	CreateIssueFieldOption api.CreateIssueFieldOption
+
+	// in:body
+	EditIssueFieldOption api.EditIssueFieldOption
+
+	// in:body
+	EditIssueFieldsOption api.EditIssueFieldsOption
+
+	// in:body
+	CreateActionWorkflowDispatch api.CreateActionWorkflowDispatch
+
+	// in:body
+	CreateActionRunnerGroupOption api.CreateActionRunnerGroupOption
+
+	// in:body
+	EditActionRunnerGroupOption api.EditActionRunnerGroupOption
+
+	// in:body
+	ActionRunnerLabelsOption api.ActionRunnerLabelsOption
+
+	// in:body
+	CreateActionEphemeralRunnerTokenOption api.CreateActionEphemeralRunnerTokenOption
+
+	// in:body
+	EditActionsPolicyOption api.EditActionsPolicyOption
+
+	// in:body
+	EditActionArtifactRetentionOption api.EditActionArtifactRetentionOption
+
+	// in:body
+	CreatePackageCleanupRuleOption api.CreatePackageCleanupRuleOption
+
+	// in:body
+	EditPackageCleanupRuleOption api.EditPackageCleanupRuleOption
+
+	// in:body
+	SetPackageUpstreamOption api.SetPackageUpstreamOption
+
+	// in:body
+	PromotePackageOption api.PromotePackageOption
+
+	// in:body
+	CreatePackageAttestationOption api.CreatePackageAttestationOption
+
+	// in:body
+	SetPackageQuotaOption api.SetPackageQuotaOption
+
+	// in:body
+	MigrateToLFSOption api.MigrateToLFSOption
 }
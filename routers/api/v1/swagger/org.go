@@ -35,6 +35,27 @@ type swaggerResponseTeamList struct {
 	Body []api.Team `json:"body"`
 }
 
+// CustomProperty
+// swagger:response CustomProperty
+type swaggerResponseCustomProperty struct {
+	// in:body
+	Body api.CustomProperty `json:"body"`
+}
+
+// CustomPropertyList
+// swagger:response CustomPropertyList
+type swaggerResponseCustomPropertyList struct {
+	// in:body
+	Body []api.CustomProperty `json:"body"`
+}
+
+// ManagedGitHook
+// swagger:response ManagedGitHook
+type swaggerResponseManagedGitHook struct {
+	// in:body
+	Body api.ManagedGitHook `json:"body"`
+}
+
 // OrganizationPermissions
 // swagger:response OrganizationPermissions
 type swaggerResponseOrganizationPermissions struct {
@@ -27,3 +27,66 @@ type swaggerResponsePackageFileList struct {
 	// in:body
 	Body []api.PackageFile `json:"body"`
 }
+
+// PackageCleanupRule
+// swagger:response PackageCleanupRule
+type swaggerResponsePackageCleanupRule struct {
+	// in:body
+	Body api.PackageCleanupRule `json:"body"`
+}
+
+// PackageCleanupRuleList
+// swagger:response PackageCleanupRuleList
+type swaggerResponsePackageCleanupRuleList struct {
+	// in:body
+	Body []api.PackageCleanupRule `json:"body"`
+}
+
+// PackageUpstream
+// swagger:response PackageUpstream
+type swaggerResponsePackageUpstream struct {
+	// in:body
+	Body api.PackageUpstream `json:"body"`
+}
+
+// PackageUpstreamList
+// swagger:response PackageUpstreamList
+type swaggerResponsePackageUpstreamList struct {
+	// in:body
+	Body []api.PackageUpstream `json:"body"`
+}
+
+// PackageAttestation
+// swagger:response PackageAttestation
+type swaggerResponsePackageAttestation struct {
+	// in:body
+	Body api.PackageAttestation `json:"body"`
+}
+
+// PackageAttestationList
+// swagger:response PackageAttestationList
+type swaggerResponsePackageAttestationList struct {
+	// in:body
+	Body []api.PackageAttestation `json:"body"`
+}
+
+// PackageAuditList
+// swagger:response PackageAuditList
+type swaggerResponsePackageAuditList struct {
+	// in:body
+	Body []api.PackageAudit `json:"body"`
+}
+
+// PackageUsage
+// swagger:response PackageUsage
+type swaggerResponsePackageUsage struct {
+	// in:body
+	Body api.PackageUsage `json:"body"`
+}
+
+// PackageSizeList
+// swagger:response PackageSizeList
+type swaggerResponsePackageSizeList struct {
+	// in:body
+	Body []api.PackageSize `json:"body"`
+}
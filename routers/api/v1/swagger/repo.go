@@ -35,6 +35,20 @@ type swaggerResponseBranchList struct {
 	Body []api.Branch `json:"body"`
 }
 
+// BranchUpdateList
+// swagger:response BranchUpdateList
+type swaggerResponseBranchUpdateList struct {
+	// in:body
+	Body []api.BranchUpdate `json:"body"`
+}
+
+// RebaseBranchConflict
+// swagger:response RebaseBranchConflict
+type swaggerResponseRebaseBranchConflict struct {
+	// in:body
+	Body api.RebaseBranchConflict `json:"body"`
+}
+
 // BranchProtection
 // swagger:response BranchProtection
 type swaggerResponseBranchProtection struct {
@@ -42,6 +56,13 @@ type swaggerResponseBranchProtection struct {
 	Body api.BranchProtection `json:"body"`
 }
 
+// BranchProtectionStatusChecks
+// swagger:response BranchProtectionStatusChecks
+type swaggerResponseBranchProtectionStatusChecks struct {
+	// in:body
+	Body api.BranchProtectionStatusChecks `json:"body"`
+}
+
 // BranchProtectionList
 // swagger:response BranchProtectionList
 type swaggerResponseBranchProtectionList struct {
@@ -154,6 +175,48 @@ type swaggerResponsePullRequestList struct {
 	Body []api.PullRequest `json:"body"`
 }
 
+// PullRequestPushList
+// swagger:response PullRequestPushList
+type swaggerResponsePullRequestPushList struct {
+	// in:body
+	Body []api.PullRequestPush `json:"body"`
+}
+
+// PullRequestChanges
+// swagger:response PullRequestChanges
+type swaggerResponsePullRequestChanges struct {
+	// in:body
+	Body api.PullRequestChanges `json:"body"`
+}
+
+// PullRequestStatusChecks
+// swagger:response PullRequestStatusChecks
+type swaggerResponsePullRequestStatusChecks struct {
+	// in:body
+	Body api.PullRequestStatusChecks `json:"body"`
+}
+
+// BatchPullRequestResultList
+// swagger:response BatchPullRequestResultList
+type swaggerResponseBatchPullRequestResultList struct {
+	// in:body
+	Body []api.BatchPullRequestResult `json:"body"`
+}
+
+// PullRequestCodeOwnerRuleList
+// swagger:response PullRequestCodeOwnerRuleList
+type swaggerResponsePullRequestCodeOwnerRuleList struct {
+	// in:body
+	Body []api.PullRequestCodeOwnerRule `json:"body"`
+}
+
+// PullReviewViewedFiles
+// swagger:response PullReviewViewedFiles
+type swaggerResponsePullReviewViewedFiles struct {
+	// in:body
+	Body api.PullReviewViewedFiles `json:"body"`
+}
+
 // PullReview
 // swagger:response PullReview
 type swaggerResponsePullReview struct {
@@ -296,6 +359,13 @@ type swaggerNote struct {
 	Body api.Note `json:"body"`
 }
 
+// BlameResponse
+// swagger:response BlameResponse
+type swaggerBlameResponse struct {
+	// in: body
+	Body api.BlameResponse `json:"body"`
+}
+
 // EmptyRepository
 // swagger:response EmptyRepository
 type swaggerEmptyRepository struct {
@@ -317,6 +387,13 @@ type swaggerFilesResponse struct {
 	Body api.FilesResponse `json:"body"`
 }
 
+// ApplyPatchConflicts
+// swagger:response ApplyPatchConflicts
+type swaggerApplyPatchConflicts struct {
+	// in: body
+	Body api.ApplyPatchConflicts `json:"body"`
+}
+
 // ContentsResponse
 // swagger:response ContentsResponse
 type swaggerContentsResponse struct {
@@ -359,6 +436,20 @@ type swaggerLanguageStatistics struct {
 	Body map[string]int64 `json:"body"`
 }
 
+// RepoDependencyList
+// swagger:response RepoDependencyList
+type swaggerRepoDependencyList struct {
+	// in: body
+	Body []api.RepoDependency `json:"body"`
+}
+
+// RepoDependentList
+// swagger:response RepoDependentList
+type swaggerRepoDependentList struct {
+	// in: body
+	Body []api.RepoDependent `json:"body"`
+}
+
 // CombinedStatus
 // swagger:response CombinedStatus
 type swaggerCombinedStatus struct {
@@ -387,6 +478,20 @@ type swaggerWikiCommitList struct {
 	Body api.WikiCommitList `json:"body"`
 }
 
+// ContentSearchResultList
+// swagger:response ContentSearchResultList
+type swaggerContentSearchResultList struct {
+	// in:body
+	Body []api.ContentSearchResult `json:"body"`
+}
+
+// CustomPropertyValueList
+// swagger:response CustomPropertyValueList
+type swaggerCustomPropertyValueList struct {
+	// in:body
+	Body []api.CustomPropertyValue `json:"body"`
+}
+
 // PushMirror
 // swagger:response PushMirror
 type swaggerPushMirror struct {
@@ -436,8 +541,169 @@ type swaggerRepoTasksList struct {
 	Body api.ActionTaskResponse `json:"body"`
 }
 
+// ActionRunsList
+// swagger:response ActionRunsList
+type swaggerRepoActionRunsList struct {
+	// in:body
+	Body api.ActionRunsResponse `json:"body"`
+}
+
+// ActionRun
+// swagger:response ActionRun
+type swaggerRepoActionRun struct {
+	// in:body
+	Body api.ActionRun `json:"body"`
+}
+
+// ActionSchedule
+// swagger:response ActionSchedule
+type swaggerRepoActionSchedule struct {
+	// in:body
+	Body api.ActionSchedule `json:"body"`
+}
+
+// ActionScheduleList
+// swagger:response ActionScheduleList
+type swaggerRepoActionScheduleList struct {
+	// in:body
+	Body []api.ActionSchedule `json:"body"`
+}
+
+// ActionArtifactsList
+// swagger:response ActionArtifactsList
+type swaggerRepoActionArtifactsList struct {
+	// in:body
+	Body api.ActionArtifactsResponse `json:"body"`
+}
+
+// ActionArtifactFileList
+// swagger:response ActionArtifactFileList
+type swaggerRepoActionArtifactFileList struct {
+	// in:body
+	Body []api.ActionArtifactFile `json:"body"`
+}
+
+// ActionArtifactRetention
+// swagger:response ActionArtifactRetention
+type swaggerRepoActionArtifactRetention struct {
+	// in:body
+	Body api.ActionArtifactRetention `json:"body"`
+}
+
 // swagger:response Compare
 type swaggerCompare struct {
 	// in:body
 	Body api.Compare `json:"body"`
 }
+
+// RepoManagedGitHook
+// swagger:response RepoManagedGitHook
+type swaggerRepoManagedGitHook struct {
+	// in:body
+	Body api.RepoManagedGitHook `json:"body"`
+}
+
+// RepoArchiveStatus
+// swagger:response RepoArchiveStatus
+type swaggerRepoArchiveStatus struct {
+	// in:body
+	Body api.RepoArchiveStatus `json:"body"`
+}
+
+// RepoSize
+// swagger:response RepoSize
+type swaggerRepoSize struct {
+	// in:body
+	Body api.RepoSize `json:"body"`
+}
+
+// LFSMigrateTask
+// swagger:response LFSMigrateTask
+type swaggerLFSMigrateTask struct {
+	// in:body
+	Body api.LFSMigrateTask `json:"body"`
+}
+
+// CommitGraph
+// swagger:response CommitGraph
+type swaggerCommitGraph struct {
+	// in:body
+	Body api.CommitGraph `json:"body"`
+}
+
+// CodeFrequency
+// swagger:response CodeFrequency
+type swaggerCodeFrequency struct {
+	// in:body
+	Body []api.CodeFrequencyWeek `json:"body"`
+}
+
+// PunchCard
+// swagger:response PunchCard
+type swaggerPunchCard struct {
+	// in:body
+	Body []api.PunchCardEntry `json:"body"`
+}
+
+// ContributorStatsList
+// swagger:response ContributorStatsList
+type swaggerContributorStatsList struct {
+	// in:body
+	Body []api.ContributorStats `json:"body"`
+}
+
+// BisectSession
+// swagger:response BisectSession
+type swaggerBisectSession struct {
+	// in:body
+	Body api.BisectSession `json:"body"`
+}
+
+// StagingSession
+// swagger:response StagingSession
+type swaggerStagingSession struct {
+	// in:body
+	Body api.StagingSession `json:"body"`
+}
+
+// StagedFile
+// swagger:response StagedFile
+type swaggerStagedFile struct {
+	// in:body
+	Body api.StagedFile `json:"body"`
+}
+
+// DeferredCommit
+// swagger:response DeferredCommit
+type swaggerDeferredCommit struct {
+	// in:body
+	Body api.DeferredCommit `json:"body"`
+}
+
+// DeferredCommitList
+// swagger:response DeferredCommitList
+type swaggerDeferredCommitList struct {
+	// in:body
+	Body []api.DeferredCommit `json:"body"`
+}
+
+// MergeQueueEntry
+// swagger:response MergeQueueEntry
+type swaggerMergeQueueEntry struct {
+	// in:body
+	Body api.MergeQueueEntry `json:"body"`
+}
+
+// MergeQueueEntryList
+// swagger:response MergeQueueEntryList
+type swaggerMergeQueueEntryList struct {
+	// in:body
+	Body []api.MergeQueueEntry `json:"body"`
+}
+
+// RepoTimeline
+// swagger:response RepoTimeline
+type swaggerRepoTimeline struct {
+	// in:body
+	Body api.RepoTimeline `json:"body"`
+}
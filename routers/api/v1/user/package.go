@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetPackageUsage gets the storage used by the packages of the authenticated user
+func GetPackageUsage(ctx *context.APIContext) {
+	// swagger:operation GET /user/packages/usage user userGetPackageUsage
+	// ---
+	// summary: Get the storage used by the packages of the authenticated user for each package type
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageUsage"
+
+	utils.GetOwnerPackageUsage(ctx, ctx.Doer)
+}
@@ -16,14 +16,15 @@ import (
 )
 
 // listUserRepos - List the repositories owned by the given user.
-func listUserRepos(ctx *context.APIContext, u *user_model.User, private bool) {
+func listUserRepos(ctx *context.APIContext, u *user_model.User, private bool, customProperties map[string]string) {
 	opts := utils.GetListOptions(ctx)
 
 	repos, count, err := repo_model.GetUserRepositories(ctx, &repo_model.SearchRepoOptions{
-		Actor:       u,
-		Private:     private,
-		ListOptions: opts,
-		OrderBy:     "id ASC",
+		Actor:            u,
+		Private:          private,
+		ListOptions:      opts,
+		OrderBy:          "id ASC",
+		CustomProperties: customProperties,
 	})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetUserRepositories", err)
@@ -80,7 +81,7 @@ func ListUserRepos(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 
 	private := ctx.IsSigned
-	listUserRepos(ctx, ctx.ContextUser, private)
+	listUserRepos(ctx, ctx.ContextUser, private, nil)
 }
 
 // ListMyRepos - list the repositories you own or have access to.
@@ -157,11 +158,26 @@ func ListOrgRepos(ctx *context.APIContext) {
 	//   in: query
 	//   description: page size of results
 	//   type: integer
+	// - name: property
+	//   in: query
+	//   description: only list the repositories whose custom property has the value, in the form "name:value"
+	//   type: array
+	//   items:
+	//     type: string
+	//   collectionFormat: multi
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	customProperties, err := repo_model.ParseCustomPropertyFilters(ctx.FormStrings("property"))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ParseCustomPropertyFilters", err)
+		return
+	}
 
-	listUserRepos(ctx, ctx.Org.Organization.AsUser(), ctx.IsSigned)
+	listUserRepos(ctx, ctx.Org.Organization.AsUser(), ctx.IsSigned, customProperties)
 }
@@ -205,8 +205,10 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, ownerID, repoI
 				Wiki:                     util.SliceContainsString(form.Events, string(webhook_module.HookEventWiki), true),
 				Repository:               util.SliceContainsString(form.Events, string(webhook_module.HookEventRepository), true),
 				Release:                  util.SliceContainsString(form.Events, string(webhook_module.HookEventRelease), true),
+				Package:                  util.SliceContainsString(form.Events, string(webhook_module.HookEventPackage), true),
 			},
-			BranchFilter: form.BranchFilter,
+			BranchFilter:      form.BranchFilter,
+			PackageTypeFilter: form.PackageTypeFilter,
 		},
 		IsActive: form.Active,
 		Type:     form.Type,
@@ -364,7 +366,9 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.Repository = util.SliceContainsString(form.Events, string(webhook_module.HookEventRepository), true)
 	w.Wiki = util.SliceContainsString(form.Events, string(webhook_module.HookEventWiki), true)
 	w.Release = util.SliceContainsString(form.Events, string(webhook_module.HookEventRelease), true)
+	w.Package = util.SliceContainsString(form.Events, string(webhook_module.HookEventPackage), true)
 	w.BranchFilter = form.BranchFilter
+	w.PackageTypeFilter = form.PackageTypeFilter
 
 	err := w.SetHeaderAuthorization(form.AuthorizationHeader)
 	if err != nil {
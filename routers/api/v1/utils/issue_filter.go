@@ -0,0 +1,103 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetIssueFilter returns the filter with the slug in the url saved by a repository or an organization,
+// or the personal filter of a user if userID isn't 0, it responds with 404 if it doesn't exist
+func GetIssueFilter(ctx *context.APIContext, repoID, orgID, userID int64) *issues_model.IssueFilter {
+	f, err := issues_model.GetIssueFilterBySlug(ctx, repoID, orgID, userID, ctx.PathParam(":slug"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueFilterBySlug", err)
+		}
+		return nil
+	}
+	return f
+}
+
+// AddIssueFilter saves a filter of a repository or an organization, or a personal filter of a user if userID isn't 0
+func AddIssueFilter(ctx *context.APIContext, form *api.CreateIssueFilterOption, repoID, orgID, userID int64) {
+	f := &issues_model.IssueFilter{
+		RepoID:      repoID,
+		OrgID:       orgID,
+		UserID:      userID,
+		Name:        form.Name,
+		Slug:        form.Slug,
+		LabelIDs:    form.Labels,
+		AssigneeID:  form.AssigneeID,
+		MilestoneID: form.MilestoneID,
+		TypeID:      form.TypeID,
+		State:       form.State,
+		Keyword:     form.Keyword,
+		SortType:    form.Sort,
+		IsDefault:   form.IsDefault,
+	}
+	if err := issues_model.NewIssueFilter(ctx, f); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "NewIssueFilter", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "NewIssueFilter", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssueFilter(f))
+}
+
+// EditIssueFilter updates a saved filter with the options which are set
+func EditIssueFilter(ctx *context.APIContext, form *api.EditIssueFilterOption, f *issues_model.IssueFilter) {
+	if form.Name != nil {
+		f.Name = *form.Name
+	}
+	if form.Slug != nil {
+		f.Slug = *form.Slug
+	}
+	if form.Labels != nil {
+		f.LabelIDs = form.Labels
+	}
+	if form.AssigneeID != nil {
+		f.AssigneeID = *form.AssigneeID
+	}
+	if form.MilestoneID != nil {
+		f.MilestoneID = *form.MilestoneID
+	}
+	if form.TypeID != nil {
+		f.TypeID = *form.TypeID
+	}
+	if form.State != nil {
+		f.State = *form.State
+	}
+	if form.Keyword != nil {
+		f.Keyword = *form.Keyword
+	}
+	if form.Sort != nil {
+		f.SortType = *form.Sort
+	}
+	if form.IsDefault != nil {
+		f.IsDefault = *form.IsDefault
+	}
+	if err := issues_model.UpdateIssueFilter(ctx, f); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateIssueFilter", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateIssueFilter", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueFilter(f))
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// GetOwnerPackageUsage responds with the storage used by the packages of the owner and its quota
+func GetOwnerPackageUsage(ctx *context.APIContext, owner *user_model.User) {
+	usages, err := packages_model.GetUsageByType(ctx, owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsageByType", err)
+		return
+	}
+
+	totalSizeLimit, isOwnerLimit, err := packages_service.GetTotalSizeLimit(ctx, owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTotalSizeLimit", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageUsage(usages, totalSizeLimit, isOwnerLimit))
+}
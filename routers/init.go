@@ -40,17 +40,20 @@ import (
 	"code.gitea.io/gitea/services/auth/source/oauth2"
 	"code.gitea.io/gitea/services/automerge"
 	"code.gitea.io/gitea/services/cron"
+	dependency_service "code.gitea.io/gitea/services/dependency"
 	feed_service "code.gitea.io/gitea/services/feed"
 	indexer_service "code.gitea.io/gitea/services/indexer"
 	"code.gitea.io/gitea/services/mailer"
 	mailer_incoming "code.gitea.io/gitea/services/mailer/incoming"
 	markup_service "code.gitea.io/gitea/services/markup"
+	"code.gitea.io/gitea/services/mergequeue"
 	repo_migrations "code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	pull_service "code.gitea.io/gitea/services/pull"
 	release_service "code.gitea.io/gitea/services/release"
 	repo_service "code.gitea.io/gitea/services/repository"
 	"code.gitea.io/gitea/services/repository/archiver"
+	files_service "code.gitea.io/gitea/services/repository/files"
 	"code.gitea.io/gitea/services/task"
 	"code.gitea.io/gitea/services/uinotification"
 	"code.gitea.io/gitea/services/webhook"
@@ -158,6 +161,9 @@ func InitWebInstalled(ctx context.Context) {
 	mustInit(webhook.Init)
 	mustInit(pull_service.Init)
 	mustInit(automerge.Init)
+	mustInit(mergequeue.Init)
+	mustInit(dependency_service.Init)
+	mustInit(files_service.InitDeferredCommits)
 	mustInit(task.Init)
 	mustInit(repo_migrations.Init)
 	eventsource.GetManager().Init()
@@ -207,6 +213,8 @@ func NormalRoutes() *web.Router {
 		r.Mount(prefix, actions_router.ArtifactsRoutes(prefix))
 		prefix = actions_router.ArtifactV4RouteBase
 		r.Mount(prefix, actions_router.ArtifactsV4Routes(prefix))
+		prefix = "/api/actions_cache"
+		r.Mount(prefix, actions_router.CacheRoutes(prefix))
 	}
 
 	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
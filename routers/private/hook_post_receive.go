@@ -27,9 +27,34 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	gitea_context "code.gitea.io/gitea/services/context"
 	pull_service "code.gitea.io/gitea/services/pull"
+	pushoptions_service "code.gitea.io/gitea/services/pushoptions"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
 
+// addRefUpdateLogs writes the updates of the branches to their journal, so they can be restored to a previous position
+func addRefUpdateLogs(ctx context.Context, repo *repo_model.Repository, updates []*repo_module.PushUpdateOptions) error {
+	logs := make([]*git_model.RefUpdateLog, 0, len(updates))
+	for _, update := range updates {
+		if !update.RefFullName.IsBranch() {
+			continue
+		}
+		refLog := &git_model.RefUpdateLog{
+			RepoID:      repo.ID,
+			RefName:     update.RefFullName.String(),
+			OldCommitID: update.OldCommitID,
+			NewCommitID: update.NewCommitID,
+			PusherID:    update.PusherID,
+		}
+		if update.IsNewRef() {
+			refLog.OldCommitID = ""
+		} else if update.IsDelRef() {
+			refLog.NewCommitID = ""
+		}
+		logs = append(logs, refLog)
+	}
+	return git_model.AddRefUpdateLogs(ctx, logs)
+}
+
 // HookPostReceive updates services and users
 func HookPostReceive(ctx *gitea_context.PrivateContext) {
 	opts := web.GetForm(ctx).(*private.HookOptions)
@@ -78,6 +103,9 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 				PusherName:   opts.UserName,
 				RepoUserName: ownerName,
 				RepoName:     repoName,
+				SkipWebhooks: opts.SkipWebhooks,
+				SkipCI:       opts.SkipCI || opts.GitPushOptions.Bool(private.GitPushOptionSkipCI).Value(),
+				PushOptions:  opts.GitPushOptions,
 			}
 			updates = append(updates, option)
 			if repo.IsEmpty && (refFullName.BranchName() == "master" || refFullName.BranchName() == "main") {
@@ -93,6 +121,14 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 	}
 
 	if repo != nil && len(updates) > 0 {
+		if err := addRefUpdateLogs(ctx, repo, updates); err != nil {
+			log.Error("Failed to add ref update logs: %s/%s Error: %v", ownerName, repoName, err)
+			ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
+				Err: fmt.Sprintf("Failed to add ref update logs: %s/%s Error: %v", ownerName, repoName, err),
+			})
+			return
+		}
+
 		branchesToSync := make([]*repo_module.PushUpdateOptions, 0, len(updates))
 		for _, update := range updates {
 			if !update.RefFullName.IsBranch() {
@@ -161,6 +197,11 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 			})
 			return
 		}
+
+		// the branches have been pushed, failing to apply the push options to their pull requests doesn't fail the push
+		if err := pushoptions_service.HandleBranchUpdates(ctx, repo, opts, updates); err != nil {
+			log.Error("Failed to handle the push options: %s/%s Error: %v", ownerName, repoName, err)
+		}
 	}
 
 	// handle pull request merging, a pull request action should push at least 1 commit
@@ -359,6 +400,10 @@ func handlePullRequestMerging(ctx *gitea_context.PrivateContext, opts *private.H
 		if err := pull_model.DeleteScheduledAutoMerge(ctx, pr.ID); err != nil && !db.IsErrNotExist(err) {
 			return fmt.Errorf("DeleteScheduledAutoMerge[%d]: %v", opts.PullRequestID, err)
 		}
+		// Removing the pull from its merge queue and ignore if not exist
+		if err := pull_model.DeleteMergeQueueEntry(ctx, pr.ID); err != nil && !db.IsErrNotExist(err) {
+			return fmt.Errorf("DeleteMergeQueueEntry[%d]: %v", opts.PullRequestID, err)
+		}
 		if _, err := pr.SetMerged(ctx); err != nil {
 			return fmt.Errorf("SetMerged failed: %s/%s Error: %v", ownerName, repoName, err)
 		}
@@ -22,6 +22,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	gitea_context "code.gitea.io/gitea/services/context"
 	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 type preReceiveContext struct {
@@ -132,9 +133,44 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
+	if !preReceiveManagedGitHook(ourCtx) {
+		return
+	}
+
 	ctx.PlainText(http.StatusOK, "ok")
 }
 
+// preReceiveManagedGitHook checks the push with the managed git hook of the repository owner,
+// it returns false if the push is rejected and it writes the response.
+func preReceiveManagedGitHook(ctx *preReceiveContext) bool {
+	repo := ctx.Repo.Repository
+	hook, err := git_model.GetEffectiveManagedGitHook(ctx, repo)
+	if err != nil {
+		log.Error("Unable to get the managed git hook of repo %-v: %v", repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to get the managed git hook of repo %s/%s: %v", repo.OwnerName, repo.Name, err),
+		})
+		return false
+	} else if hook == nil {
+		return true
+	}
+
+	msg, err := repo_service.CheckManagedGitHook(ctx, repo, hook, ctx.opts.OldCommitIDs, ctx.opts.NewCommitIDs, ctx.opts.RefFullNames, ctx.env)
+	if err != nil {
+		log.Error("Unable to check the push to repo %-v with the managed git hook: %v", repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to check the push to repo %s/%s with the managed git hook: %v", repo.OwnerName, repo.Name, err),
+		})
+		return false
+	} else if msg != "" {
+		ctx.JSON(http.StatusForbidden, private.Response{
+			UserMsg: msg,
+		})
+		return false
+	}
+	return true
+}
+
 func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID string, refFullName git.RefName) {
 	branchName := refFullName.BranchName()
 	ctx.branchName = branchName
@@ -221,6 +257,26 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID string, r
 		}
 	}
 
+	// 3a. Enforce the sign-off of the commits
+	if protectBranch.RequireSignoff {
+		err := verifyCommitsSignedOff(oldCommitID, newCommitID, gitRepo, ctx.env)
+		if err != nil {
+			if !isErrUnverifiedCommit(err) {
+				log.Error("Unable to check the sign-off of commits from %s to %s in %-v: %v", oldCommitID, newCommitID, repo, err)
+				ctx.JSON(http.StatusInternalServerError, private.Response{
+					Err: fmt.Sprintf("Unable to check the sign-off of commits from %s to %s: %v", oldCommitID, newCommitID, err),
+				})
+				return
+			}
+			unsignedOffCommit := err.(*errUnverifiedCommit).sha
+			log.Warn("Forbidden: Branch: %s in %-v is protected from commit %s without sign-off", branchName, repo, unsignedOffCommit)
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: fmt.Sprintf("branch %s is protected from commit %s without a Signed-off-by trailer of its author", branchName, unsignedOffCommit),
+			})
+			return
+		}
+	}
+
 	// Now there are several tests which can be overridden:
 	//
 	// 4. Check protected file patterns - this is overridable from the UI
@@ -18,6 +18,20 @@ import (
 // This file contains commit verification functions for refs passed across in hooks
 
 func verifyCommits(oldCommitID, newCommitID string, repo *git.Repository, env []string) error {
+	return checkCommits(oldCommitID, newCommitID, repo, env, func(ctx context.Context, commit *git.Commit) bool {
+		return asymkey_model.ParseCommitWithSignature(ctx, commit).Verified
+	})
+}
+
+// verifyCommitsSignedOff checks that the new commits have a Signed-off-by trailer of their author
+func verifyCommitsSignedOff(oldCommitID, newCommitID string, repo *git.Repository, env []string) error {
+	return checkCommits(oldCommitID, newCommitID, repo, env, func(_ context.Context, commit *git.Commit) bool {
+		return git.IsCommitSignedOffBy(commit.CommitMessage, commit.Author)
+	})
+}
+
+// checkCommits returns an errUnverifiedCommit for the first new commit failing the check
+func checkCommits(oldCommitID, newCommitID string, repo *git.Repository, env []string, check func(context.Context, *git.Commit) bool) error {
 	stdoutReader, stdoutWriter, err := os.Pipe()
 	if err != nil {
 		log.Error("Unable to create os.Pipe for %s", repo.Path)
@@ -45,7 +59,7 @@ func verifyCommits(oldCommitID, newCommitID string, repo *git.Repository, env []
 		Stdout: stdoutWriter,
 		PipelineFunc: func(ctx context.Context, cancel context.CancelFunc) error {
 			_ = stdoutWriter.Close()
-			err := readAndVerifyCommitsFromShaReader(stdoutReader, repo, env)
+			err := readAndVerifyCommitsFromShaReader(stdoutReader, repo, env, check)
 			if err != nil {
 				log.Error("readAndVerifyCommitsFromShaReader failed: %v", err)
 				cancel()
@@ -60,11 +74,11 @@ func verifyCommits(oldCommitID, newCommitID string, repo *git.Repository, env []
 	return err
 }
 
-func readAndVerifyCommitsFromShaReader(input io.ReadCloser, repo *git.Repository, env []string) error {
+func readAndVerifyCommitsFromShaReader(input io.ReadCloser, repo *git.Repository, env []string, check func(context.Context, *git.Commit) bool) error {
 	scanner := bufio.NewScanner(input)
 	for scanner.Scan() {
 		line := scanner.Text()
-		err := readAndVerifyCommit(line, repo, env)
+		err := readAndVerifyCommit(line, repo, env, check)
 		if err != nil {
 			return err
 		}
@@ -72,7 +86,7 @@ func readAndVerifyCommitsFromShaReader(input io.ReadCloser, repo *git.Repository
 	return scanner.Err()
 }
 
-func readAndVerifyCommit(sha string, repo *git.Repository, env []string) error {
+func readAndVerifyCommit(sha string, repo *git.Repository, env []string, check func(context.Context, *git.Commit) bool) error {
 	stdoutReader, stdoutWriter, err := os.Pipe()
 	if err != nil {
 		log.Error("Unable to create pipe for %s: %v", repo.Path, err)
@@ -96,8 +110,7 @@ func readAndVerifyCommit(sha string, repo *git.Repository, env []string) error {
 				if err != nil {
 					return err
 				}
-				verification := asymkey_model.ParseCommitWithSignature(ctx, commit)
-				if !verification.Verified {
+				if !check(ctx, commit) {
 					cancel()
 					return &errUnverifiedCommit{
 						commit.ID.String(),
@@ -0,0 +1,77 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"net/url"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplActionsCaches base.TplName = "admin/actions"
+
+// ActionsCaches shows the usage of the Actions caches per repository
+func ActionsCaches(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.actions")
+	ctx.Data["PageType"] = "caches"
+	ctx.Data["PageIsSharedSettingsCaches"] = true
+
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	usages, total, err := actions_model.GetRepoCacheUsages(ctx, db.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.Admin.RepoPagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("GetRepoCacheUsages", err)
+		return
+	}
+	repoIDs := make([]int64, 0, len(usages))
+	for _, usage := range usages {
+		repoIDs = append(repoIDs, usage.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		ctx.ServerError("GetRepositoriesMapByIDs", err)
+		return
+	}
+	for _, repo := range repos {
+		if err := repo.LoadOwner(ctx); err != nil {
+			ctx.ServerError("LoadOwner", err)
+			return
+		}
+	}
+
+	ctx.Data["CacheUsages"] = usages
+	ctx.Data["CacheRepos"] = repos
+	ctx.Data["CacheRepoQuota"] = setting.Actions.CacheRepoQuota
+	ctx.Data["CacheRetentionDays"] = setting.Actions.CacheRetentionDays
+	ctx.Data["TotalCount"] = total
+
+	pager := context.NewPagination(int(total), setting.UI.Admin.RepoPagingNum, page, 5)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplActionsCaches)
+}
+
+// DeleteActionsCaches deletes all the Actions caches of a repository
+func DeleteActionsCaches(ctx *context.Context) {
+	if err := actions_service.DeleteRepoCaches(ctx, ctx.FormInt64("id")); err != nil {
+		ctx.ServerError("DeleteRepoCaches", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.caches.deletion.success"))
+	ctx.JSONRedirect(setting.AppSubURL + "/admin/actions/caches?page=" + url.QueryEscape(ctx.FormString("page")))
+}
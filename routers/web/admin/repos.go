@@ -22,8 +22,9 @@ import (
 )
 
 const (
-	tplRepos          base.TplName = "admin/repo/list"
-	tplUnadoptedRepos base.TplName = "admin/repo/unadopted"
+	tplRepos           base.TplName = "admin/repo/list"
+	tplUnadoptedRepos  base.TplName = "admin/repo/unadopted"
+	tplRepoMaintenance base.TplName = "admin/repo/maintenance"
 )
 
 // Repos show all the repositories
@@ -162,3 +163,59 @@ func AdoptOrDeleteRepository(ctx *context.Context) {
 	}
 	ctx.Redirect(setting.AppSubURL + "/admin/repos/unadopted?search=true&q=" + url.QueryEscape(q) + "&page=" + url.QueryEscape(page))
 }
+
+// RepoMaintenance lists the statuses of the last maintenance of the repositories
+func RepoMaintenance(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.repos.maintenance")
+	ctx.Data["PageIsAdminRepositories"] = true
+
+	page := ctx.FormInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	onlyFailed := ctx.FormBool("failed")
+	ctx.Data["OnlyFailed"] = onlyFailed
+
+	maintenances, count, err := db.FindAndCount[repo_model.RepoMaintenance](ctx, repo_model.FindRepoMaintenancesOptions{
+		ListOptions: db.ListOptions{
+			PageSize: setting.UI.Admin.RepoPagingNum,
+			Page:     page,
+		},
+		OnlyFailed: onlyFailed,
+	})
+	if err != nil {
+		ctx.ServerError("FindRepoMaintenances", err)
+		return
+	}
+	if err := repo_model.RepoMaintenanceList(maintenances).LoadRepos(ctx); err != nil {
+		ctx.ServerError("LoadRepos", err)
+		return
+	}
+	ctx.Data["Maintenances"] = maintenances
+
+	pager := context.NewPagination(int(count), setting.UI.Admin.RepoPagingNum, page, 5)
+	pager.SetDefaultParams(ctx)
+	pager.AddParamString("failed", fmt.Sprint(onlyFailed))
+	ctx.Data["Page"] = pager
+	ctx.HTML(http.StatusOK, tplRepoMaintenance)
+}
+
+// RunRepoMaintenance runs the maintenance of a repository
+func RunRepoMaintenance(ctx *context.Context) {
+	repo, err := repo_model.GetRepositoryByID(ctx, ctx.FormInt64("id"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByID", err)
+		} else {
+			ctx.ServerError("GetRepositoryByID", err)
+		}
+		return
+	}
+
+	if err := repo_service.MaintainRepository(ctx, repo, repo_model.RepoMaintenanceTriggerManual); err != nil {
+		ctx.Flash.Error(ctx.Tr("admin.repos.maintenance.failed", repo.FullName()))
+	} else {
+		ctx.Flash.Success(ctx.Tr("admin.repos.maintenance.success", repo.FullName()))
+	}
+	ctx.Redirect(setting.AppSubURL + "/admin/repos/maintenance?page=" + url.QueryEscape(ctx.FormString("page")) + "&failed=" + url.QueryEscape(ctx.FormString("failed")))
+}
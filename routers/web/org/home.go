@@ -80,6 +80,13 @@ func Home(ctx *context.Context) {
 	language := ctx.FormTrim("language")
 	ctx.Data["Language"] = language
 
+	propertyFilters := ctx.FormStrings("property")
+	customProperties, err := repo_model.ParseCustomPropertyFilters(propertyFilters)
+	if err != nil {
+		ctx.Flash.Error(err.Error(), true)
+		propertyFilters, customProperties = nil, nil
+	}
+
 	page := ctx.FormInt("page")
 	if page <= 0 {
 		page = 1
@@ -103,7 +110,6 @@ func Home(ctx *context.Context) {
 	var (
 		repos []*repo_model.Repository
 		count int64
-		err   error
 	)
 	repos, count, err = repo_model.SearchRepository(ctx, &repo_model.SearchRepoOptions{
 		ListOptions: db.ListOptions{
@@ -122,6 +128,7 @@ func Home(ctx *context.Context) {
 		Mirror:             mirror,
 		Template:           template,
 		IsPrivate:          private,
+		CustomProperties:   customProperties,
 	})
 	if err != nil {
 		ctx.ServerError("SearchRepository", err)
@@ -155,6 +162,9 @@ func Home(ctx *context.Context) {
 	pager := context.NewPagination(int(count), setting.UI.User.RepoPagingNum, page, 5)
 	pager.SetDefaultParams(ctx)
 	pager.AddParamString("language", language)
+	for _, filter := range propertyFilters {
+		pager.AddParamString("property", filter)
+	}
 	ctx.Data["Page"] = pager
 
 	ctx.Data["ShowMemberAndTeamTab"] = ctx.Org.IsMember || len(members) > 0
@@ -332,7 +332,13 @@ func ViewProject(ctx *context.Context) {
 		return
 	}
 
-	issuesMap, err := issues_model.LoadIssuesFromColumnList(ctx, columns)
+	sortType := ctx.FormTrim("sort")
+	if sortType != "mostvotes" {
+		sortType = ""
+	}
+	ctx.Data["SortType"] = sortType
+
+	issuesMap, err := issues_model.LoadIssuesFromColumnList(ctx, columns, sortType)
 	if err != nil {
 		ctx.ServerError("LoadIssuesOfColumns", err)
 		return
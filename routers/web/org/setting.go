@@ -46,6 +46,7 @@ func Settings(ctx *context.Context) {
 	ctx.Data["PageIsSettingsOptions"] = true
 	ctx.Data["CurrentVisibility"] = ctx.Org.Organization.Visibility
 	ctx.Data["RepoAdminChangeTeamAccess"] = ctx.Org.Organization.RepoAdminChangeTeamAccess
+	ctx.Data["IssueVotingMembersOnly"] = ctx.Org.Organization.IssueVotingMembersOnly
 	ctx.Data["ContextUser"] = ctx.ContextUser
 
 	err := shared_user.LoadHeaderCount(ctx)
@@ -107,6 +108,7 @@ func SettingsPost(ctx *context.Context) {
 		Location:                  optional.Some(form.Location),
 		Visibility:                optional.Some(form.Visibility),
 		RepoAdminChangeTeamAccess: optional.Some(form.RepoAdminChangeTeamAccess),
+		IssueVotingMembersOnly:    optional.Some(form.IssueVotingMembersOnly),
 	}
 	if ctx.Doer.IsAdmin {
 		opts.MaxRepoCreation = optional.Some(form.MaxRepoCreation)
@@ -244,9 +246,14 @@ func Labels(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.labels")
 	ctx.Data["PageIsOrgSettings"] = true
 	ctx.Data["PageIsOrgSettingsLabels"] = true
-	ctx.Data["LabelTemplateFiles"] = repo_module.LabelTemplateFiles
+	labelTemplateFiles, err := repo_module.ListLabelTemplates(ctx)
+	if err != nil {
+		ctx.ServerError("ListLabelTemplates", err)
+		return
+	}
+	ctx.Data["LabelTemplateFiles"] = labelTemplateFiles
 
-	err := shared_user.LoadHeaderCount(ctx)
+	err = shared_user.LoadHeaderCount(ctx)
 	if err != nil {
 		ctx.ServerError("LoadHeaderCount", err)
 		return
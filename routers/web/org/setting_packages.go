@@ -129,3 +129,12 @@ func RebuildCargoIndex(ctx *context.Context) {
 
 	ctx.Redirect(fmt.Sprintf("%s/org/%s/settings/packages", setting.AppSubURL, ctx.ContextUser.Name))
 }
+
+func SetRequireSignatures(ctx *context.Context) {
+	shared.SetRequireSignatures(ctx, ctx.ContextUser)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Redirect(fmt.Sprintf("%s/org/%s/settings/packages", setting.AppSubURL, ctx.ContextUser.Name))
+}
@@ -5,8 +5,10 @@ package actions
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
@@ -18,7 +20,9 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/repo"
+	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 
@@ -87,7 +91,7 @@ func List(ctx *context.Context) {
 		}
 		allRunnerLabels := make(container.Set[string])
 		for _, r := range runners {
-			allRunnerLabels.AddMultiple(r.AgentLabels...)
+			allRunnerLabels.AddMultiple(r.Labels()...)
 		}
 
 		workflows = make([]Workflow, 0, len(entries))
@@ -104,6 +108,11 @@ func List(ctx *context.Context) {
 				workflows = append(workflows, workflow)
 				continue
 			}
+			if entry.Name() == ctx.FormString("workflow") {
+				if inputs, ok := actions.GetWorkflowDispatchInputs(wf); ok {
+					ctx.Data["WorkflowDispatchInputs"] = inputs
+				}
+			}
 			// The workflow must contain at least one job without "needs". Otherwise, a deadlock will occur and no jobs will be able to run.
 			hasJobWithoutNeeds := false
 			// Check whether have matching runner and a job without "needs"
@@ -163,6 +172,35 @@ func List(ctx *context.Context) {
 		ctx.Data["CurWorkflowDisabled"] = actionsConfig.IsWorkflowDisabled(workflow)
 	}
 
+	// the workflow can be run manually if it has a workflow_dispatch trigger
+	if _, ok := ctx.Data["WorkflowDispatchInputs"]; ok && ctx.Repo.CanWrite(unit.TypeActions) &&
+		!ctx.Repo.Repository.IsArchived && !actionsConfig.IsWorkflowDisabled(workflow) {
+		ctx.Data["AllowRunWorkflow"] = true
+		ctx.Data["DefaultBranch"] = ctx.Repo.Repository.DefaultBranch
+	}
+
+	// the entries of the schedule trigger of the workflow, on the default branch
+	if len(workflow) > 0 {
+		entries, err := actions_service.ListScheduleEntries(ctx, ctx.Repo.Repository)
+		if err != nil {
+			ctx.ServerError("ListScheduleEntries", err)
+			return
+		}
+		scheduleEntries := make([]*actions_service.ScheduleEntry, 0, len(entries))
+		for _, entry := range entries {
+			if entry.Schedule.WorkflowID == workflow {
+				if entry.LastRun != nil {
+					entry.LastRun.Repo = ctx.Repo.Repository
+				}
+				scheduleEntries = append(scheduleEntries, entry)
+			}
+		}
+		ctx.Data["ScheduleEntries"] = scheduleEntries
+		ctx.Data["AllowPauseSchedule"] = ctx.Repo.IsAdmin() && !ctx.Repo.Repository.IsArchived
+		ctx.Data["AllowRunSchedule"] = ctx.Repo.CanWrite(unit.TypeActions) && !ctx.Repo.Repository.IsArchived &&
+			!actionsConfig.IsWorkflowDisabled(workflow)
+	}
+
 	// if status or actor query param is not given to frontend href, (href="/<repoLink>/actions")
 	// they will be 0 by default, which indicates get all status or actors
 	ctx.Data["CurActor"] = actorID
@@ -222,3 +260,96 @@ func List(ctx *context.Context) {
 
 	ctx.HTML(http.StatusOK, tplListActions)
 }
+
+// Run runs the workflow manually on a branch or a tag, with the inputs of its workflow_dispatch trigger
+func Run(ctx *context.Context) {
+	workflow := ctx.FormString("workflow")
+	redirectURL := fmt.Sprintf("%s/actions?workflow=%s", ctx.Repo.RepoLink, url.QueryEscape(workflow))
+
+	// the inputs are posted as "inputs.<name>", a checkbox of a boolean input is preceded by a hidden "false" value
+	inputs := make(map[string]string)
+	for key, values := range ctx.Req.PostForm {
+		if name, ok := strings.CutPrefix(key, "inputs."); ok && len(values) > 0 {
+			inputs[name] = values[len(values)-1]
+		}
+	}
+
+	run, err := actions_service.DispatchWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, workflow, ctx.FormString("ref"), inputs)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) || errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Flash.Error(ctx.Tr("actions.workflow.run_failed", err.Error()))
+			ctx.Redirect(redirectURL)
+			return
+		}
+		ctx.ServerError("DispatchWorkflow", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.workflow.run_success", workflow))
+	ctx.Redirect(run.Link())
+}
+
+// getScheduleEntry returns the schedule entry of the repository from the path, it writes the error response if it fails
+func getScheduleEntry(ctx *context.Context) *actions_service.ScheduleEntry {
+	entry, err := actions_service.GetScheduleEntry(ctx, ctx.Repo.Repository, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound("GetScheduleEntry", err)
+		} else {
+			ctx.ServerError("GetScheduleEntry", err)
+		}
+		return nil
+	}
+	return entry
+}
+
+// PauseSchedule pauses an entry of the schedule trigger of a workflow
+func PauseSchedule(ctx *context.Context) {
+	pauseOrResumeSchedule(ctx, true)
+}
+
+// ResumeSchedule resumes a paused entry of the schedule trigger of a workflow
+func ResumeSchedule(ctx *context.Context) {
+	pauseOrResumeSchedule(ctx, false)
+}
+
+func pauseOrResumeSchedule(ctx *context.Context, paused bool) {
+	entry := getScheduleEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := actions_service.SetScheduleEntryPaused(ctx, ctx.Repo.Repository, entry, paused); err != nil {
+		ctx.ServerError("SetScheduleEntryPaused", err)
+		return
+	}
+
+	if paused {
+		ctx.Flash.Success(ctx.Tr("actions.workflow.schedule_pause_success", entry.Spec, entry.Schedule.WorkflowID))
+	} else {
+		ctx.Flash.Success(ctx.Tr("actions.workflow.schedule_resume_success", entry.Spec, entry.Schedule.WorkflowID))
+	}
+	ctx.JSONRedirect(fmt.Sprintf("%s/actions?workflow=%s", ctx.Repo.RepoLink, url.QueryEscape(entry.Schedule.WorkflowID)))
+}
+
+// RunSchedule runs the workflow of an entry of a schedule trigger at once
+func RunSchedule(ctx *context.Context) {
+	entry := getScheduleEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	run, err := actions_service.RunScheduleEntry(ctx, ctx.Doer, entry)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Flash.Error(ctx.Tr("actions.workflow.run_failed", err.Error()))
+			ctx.JSONRedirect(fmt.Sprintf("%s/actions?workflow=%s", ctx.Repo.RepoLink, url.QueryEscape(entry.Schedule.WorkflowID)))
+			return
+		}
+		ctx.ServerError("RunScheduleEntry", err)
+		return
+	}
+	run.Repo = ctx.Repo.Repository
+
+	ctx.Flash.Success(ctx.Tr("actions.workflow.run_success", entry.Schedule.WorkflowID))
+	ctx.JSONRedirect(run.Link())
+}
@@ -4,12 +4,9 @@
 package actions
 
 import (
-	"archive/zip"
-	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -22,6 +19,8 @@ import (
 	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -59,19 +58,24 @@ type ViewRequest struct {
 type ViewResponse struct {
 	State struct {
 		Run struct {
-			Link              string     `json:"link"`
-			Title             string     `json:"title"`
-			Status            string     `json:"status"`
-			CanCancel         bool       `json:"canCancel"`
-			CanApprove        bool       `json:"canApprove"` // the run needs an approval and the doer has permission to approve
-			CanRerun          bool       `json:"canRerun"`
-			CanDeleteArtifact bool       `json:"canDeleteArtifact"`
-			Done              bool       `json:"done"`
-			WorkflowID        string     `json:"workflowID"`
-			WorkflowLink      string     `json:"workflowLink"`
-			IsSchedule        bool       `json:"isSchedule"`
-			Jobs              []*ViewJob `json:"jobs"`
-			Commit            ViewCommit `json:"commit"`
+			Link              string       `json:"link"`
+			Title             string       `json:"title"`
+			Status            string       `json:"status"`
+			Attempt           int64        `json:"attempt"`
+			CanCancel         bool         `json:"canCancel"`
+			CanApprove        bool         `json:"canApprove"` // the run needs an approval and the doer has permission to approve
+			CanRerun          bool         `json:"canRerun"`
+			CanRerunFailed    bool         `json:"canRerunFailed"`
+			CanDeleteArtifact bool         `json:"canDeleteArtifact"`
+			Done              bool         `json:"done"`
+			WorkflowID        string       `json:"workflowID"`
+			WorkflowLink      string       `json:"workflowLink"`
+			IsSchedule        bool         `json:"isSchedule"`
+			Inputs            []*ViewInput `json:"inputs"`
+			Jobs              []*ViewJob   `json:"jobs"`
+			Commit            ViewCommit   `json:"commit"`
+			// the reasons the run has failed before being executed
+			FailureAnnotations []string `json:"failureAnnotations"`
 		} `json:"run"`
 		CurrentJob struct {
 			Title  string         `json:"title"`
@@ -92,6 +96,12 @@ type ViewJob struct {
 	Duration string `json:"duration"`
 }
 
+// ViewInput is an input of a run triggered manually by workflow_dispatch
+type ViewInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 type ViewCommit struct {
 	ShortSha string     `json:"shortSHA"`
 	Link     string     `json:"link"`
@@ -150,11 +160,21 @@ func ViewPost(ctx *context_module.Context) {
 	resp.State.Run.CanCancel = !run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
 	resp.State.Run.CanApprove = run.NeedApproval && ctx.Repo.CanWrite(unit.TypeActions)
 	resp.State.Run.CanRerun = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanRerunFailed = resp.State.Run.CanRerun && len(actions_service.GetFailedRerunJobs(jobs)) > 0
+	resp.State.Run.Attempt = run.Attempt
 	resp.State.Run.CanDeleteArtifact = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
 	resp.State.Run.Done = run.Status.IsDone()
 	resp.State.Run.WorkflowID = run.WorkflowID
 	resp.State.Run.WorkflowLink = run.WorkflowLink()
 	resp.State.Run.IsSchedule = run.IsSchedule()
+	resp.State.Run.FailureAnnotations = run.FailureAnnotations
+	if resp.State.Run.FailureAnnotations == nil {
+		resp.State.Run.FailureAnnotations = []string{}
+	}
+	resp.State.Run.Inputs = make([]*ViewInput, 0, len(run.DispatchInputs))
+	for _, name := range util.Sorted(util.KeysOfMap(run.DispatchInputs)) {
+		resp.State.Run.Inputs = append(resp.State.Run.Inputs, &ViewInput{Name: name, Value: run.DispatchInputs[name]})
+	}
 	resp.State.Run.Jobs = make([]*ViewJob, 0, len(jobs)) // marshal to '[]' instead fo 'null' in json
 	resp.State.Run.Status = run.Status.String()
 	for _, v := range jobs {
@@ -275,86 +295,75 @@ func Rerun(ctx *context_module.Context) {
 		jobIndex, _ = strconv.ParseInt(jobIndexStr, 10, 64)
 	}
 
-	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, err.Error())
+	run := getRerunRun(ctx, runIndex)
+	if ctx.Written() {
 		return
 	}
 
-	// can not rerun job when workflow is disabled
-	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
-	cfg := cfgUnit.ActionsConfig()
-	if cfg.IsWorkflowDisabled(run.WorkflowID) {
-		ctx.JSONError(ctx.Locale.Tr("actions.workflow.disabled"))
+	job, jobs := getRunJobs(ctx, runIndex, jobIndex)
+	if ctx.Written() {
 		return
 	}
 
-	// reset run's start and stop time when it is done
-	if run.Status.IsDone() {
-		run.PreviousDuration = run.Duration()
-		run.Started = 0
-		run.Stopped = 0
-		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration"); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
+	rerunJobs := jobs
+	if jobIndexStr != "" {
+		rerunJobs = actions_service.GetAllRerunJobs(job, jobs)
+	}
+	if err := actions_service.RerunJobs(ctx, run, rerunJobs); err != nil {
+		writeRerunError(ctx, err)
+		return
 	}
 
-	job, jobs := getRunJobs(ctx, runIndex, jobIndex)
+	ctx.JSON(http.StatusOK, struct{}{})
+}
+
+// RerunFailed reruns the failed jobs of the run and the jobs depending on them
+func RerunFailed(ctx *context_module.Context) {
+	runIndex := ctx.PathParamInt64("run")
+
+	run := getRerunRun(ctx, runIndex)
 	if ctx.Written() {
 		return
 	}
 
-	if jobIndexStr == "" { // rerun all jobs
-		for _, j := range jobs {
-			// if the job has needs, it should be set to "blocked" status to wait for other jobs
-			shouldBlock := len(j.Needs) > 0
-			if err := rerunJob(ctx, j, shouldBlock); err != nil {
-				ctx.Error(http.StatusInternalServerError, err.Error())
-				return
-			}
-		}
-		ctx.JSON(http.StatusOK, struct{}{})
+	_, jobs := getRunJobs(ctx, runIndex, -1)
+	if ctx.Written() {
 		return
 	}
 
-	rerunJobs := actions_service.GetAllRerunJobs(job, jobs)
-
-	for _, j := range rerunJobs {
-		// jobs other than the specified one should be set to "blocked" status
-		shouldBlock := j.JobID != job.JobID
-		if err := rerunJob(ctx, j, shouldBlock); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
+	if err := actions_service.RerunJobs(ctx, run, actions_service.GetFailedRerunJobs(jobs)); err != nil {
+		writeRerunError(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
-func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob, shouldBlock bool) error {
-	status := job.Status
-	if !status.IsDone() {
-		return nil
+// writeRerunError writes the response of an error rerunning jobs, the jobs may use actions the policies don't allow anymore
+func writeRerunError(ctx *context_module.Context, err error) {
+	if errors.Is(err, util.ErrInvalidArgument) {
+		ctx.JSONError(ctx.Locale.Tr("actions.runs.actions_not_allowed", err.Error()))
+		return
 	}
+	ctx.Error(http.StatusInternalServerError, err.Error())
+}
 
-	job.TaskID = 0
-	job.Status = actions_model.StatusWaiting
-	if shouldBlock {
-		job.Status = actions_model.StatusBlocked
+// getRerunRun returns the run to rerun, it writes the error response if the workflow of the run is disabled
+func getRerunRun(ctx *context_module.Context, runIndex int64) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByIndex(ctx, ctx.Repo.Repository.ID, runIndex)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return nil
 	}
-	job.Started = 0
-	job.Stopped = 0
 
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
-		return err
-	}); err != nil {
-		return err
+	// can not rerun job when workflow is disabled
+	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
+	cfg := cfgUnit.ActionsConfig()
+	if cfg.IsWorkflowDisabled(run.WorkflowID) {
+		ctx.JSONError(ctx.Locale.Tr("actions.workflow.disabled"))
+		return nil
 	}
-
-	actions_service.CreateCommitStatus(ctx, job)
-	return nil
+	return run
 }
 
 func Logs(ctx *context_module.Context) {
@@ -444,6 +453,10 @@ func Cancel(ctx *context_module.Context) {
 
 	actions_service.CreateCommitStatus(ctx, jobs...)
 
+	if err := actions_service.EmitConcurrencyGroupsOfRun(ctx, jobs[0].RunID); err != nil {
+		log.Error("Emit the concurrency groups of run %d: %v", jobs[0].RunID, err)
+	}
+
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
@@ -465,6 +478,12 @@ func Approve(ctx *context_module.Context) {
 		}
 		for _, job := range jobs {
 			if len(job.Needs) == 0 && job.Status.IsBlocked() {
+				// the job stays queued until the earlier runs or jobs of its concurrency groups are done
+				if busy, err := actions_model.IsConcurrencyGroupBusy(ctx, run, job); err != nil {
+					return err
+				} else if busy {
+					continue
+				}
 				job.Status = actions_model.StatusWaiting
 				_, err := actions_model.UpdateRunJob(ctx, job, nil, "status")
 				if err != nil {
@@ -623,7 +642,7 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 
 	// Artifacts using the v4 backend are stored as a single combined zip file per artifact on the backend
 	// The v4 backend enshures ContentEncoding is set to "application/zip", which is not the case for the old backend
-	if len(artifacts) == 1 && artifacts[0].ArtifactName+".zip" == artifacts[0].ArtifactPath && artifacts[0].ContentEncoding == "application/zip" {
+	if actions_service.IsArtifactV4(artifacts) {
 		art := artifacts[0]
 		if setting.Actions.ArtifactStorage.ServeDirect() {
 			u, err := storage.ActionsArtifacts.URL(art.StoragePath, art.ArtifactPath)
@@ -637,42 +656,17 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 			ctx.Error(http.StatusInternalServerError, err.Error())
 			return
 		}
-		_, _ = io.Copy(ctx.Resp, f)
+		defer f.Close()
+		// serve the zip file with the support of range requests, to resume the downloads of large artifacts
+		modTime := art.UpdatedUnix.AsTime()
+		httplib.ServeContentByReadSeeker(ctx.Req, ctx.Resp, art.ArtifactPath, &modTime, f)
 		return
 	}
 
 	// Artifacts using the v1-v3 backend are stored as multiple individual files per artifact on the backend
 	// Those need to be zipped for download
-	writer := zip.NewWriter(ctx.Resp)
-	defer writer.Close()
-	for _, art := range artifacts {
-		f, err := storage.ActionsArtifacts.Open(art.StoragePath)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
-
-		var r io.ReadCloser
-		if art.ContentEncoding == "gzip" {
-			r, err = gzip.NewReader(f)
-			if err != nil {
-				ctx.Error(http.StatusInternalServerError, err.Error())
-				return
-			}
-		} else {
-			r = f
-		}
-		defer r.Close()
-
-		w, err := writer.Create(art.ArtifactPath)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
-		if _, err := io.Copy(w, r); err != nil {
-			ctx.Error(http.StatusInternalServerError, err.Error())
-			return
-		}
+	if err := actions_service.WriteArtifactZip(ctx.Resp, artifacts); err != nil {
+		ctx.Error(http.StatusInternalServerError, err.Error())
 	}
 }
 
@@ -127,6 +127,11 @@ func ServeAttachment(ctx *context.Context, uuid string) {
 		return
 	}
 
+	if attach.IsExternal() {
+		ctx.Redirect(attach.ExternalURL)
+		return
+	}
+
 	if setting.Attachment.Storage.ServeDirect() {
 		// If we have a signed url (S3, object storage), redirect to this directly.
 		u, err := storage.Attachments.URL(attach.RelativePath(), attach.Name)
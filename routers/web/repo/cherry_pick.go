@@ -120,6 +120,7 @@ func CherryPickPost(ctx *context.Context) {
 		OldBranch:    ctx.Repo.BranchName,
 		NewBranch:    branchName,
 		Message:      message,
+		ThreeWay:     true,
 	}
 
 	// First lets try the simple plain read-tree -m approach
@@ -0,0 +1,88 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/context"
+)
+
+const (
+	tplDependents base.TplName = "repo/activity"
+)
+
+// Dependent is a dependency of another repository on a package declared by the current repository
+type Dependent struct {
+	*repo_model.RepoDependency
+	Repo *repo_model.Repository
+}
+
+// Dependents renders the page listing the repositories depending on the packages declared by the repository
+func Dependents(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.activity.navbar.dependents")
+
+	ctx.Data["PageIsActivity"] = true
+	ctx.Data["PageIsDependents"] = true
+
+	page := ctx.FormInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	manifests, err := repo_model.GetRepoManifests(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetRepoManifests", err)
+		return
+	}
+	ctx.Data["Manifests"] = manifests
+
+	deps, total, err := db.FindAndCount[repo_model.RepoDependency](ctx, repo_model.FindRepoDependenciesOptions{
+		ListOptions: db.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.RepoSearchPagingNum,
+		},
+		Packages:      manifests,
+		Doer:          ctx.Doer,
+		ExcludeRepoID: ctx.Repo.Repository.ID,
+	})
+	if err != nil {
+		ctx.ServerError("FindRepoDependencies", err)
+		return
+	}
+
+	repoIDs := make(container.Set[int64], len(deps))
+	for _, d := range deps {
+		repoIDs.Add(d.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs.Values())
+	if err != nil {
+		ctx.ServerError("GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	dependents := make([]*Dependent, 0, len(deps))
+	for _, d := range deps {
+		repo, ok := repos[d.RepoID]
+		if !ok {
+			continue
+		}
+		if err := repo.LoadOwner(ctx); err != nil {
+			ctx.ServerError("LoadOwner", err)
+			return
+		}
+		dependents = append(dependents, &Dependent{RepoDependency: d, Repo: repo})
+	}
+	ctx.Data["Dependents"] = dependents
+
+	pager := context.NewPagination(int(total), setting.UI.RepoSearchPagingNum, page, 5)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplDependents)
+}
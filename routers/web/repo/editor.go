@@ -14,6 +14,7 @@ import (
 	git_model "code.gitea.io/gitea/models/git"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/git"
@@ -138,6 +139,21 @@ func editFile(ctx *context.Context, isNewFile bool) {
 			return
 		}
 
+		// Warn about editing a file which is locked by another user
+		lfsLock, err := git_model.GetTreePathLock(ctx, ctx.Repo.Repository.ID, ctx.Repo.TreePath)
+		if err != nil {
+			ctx.ServerError("GetTreePathLock", err)
+			return
+		}
+		if lfsLock != nil && lfsLock.OwnerID != ctx.Doer.ID {
+			u, err := user_model.GetPossibleUserByID(ctx, lfsLock.OwnerID)
+			if err != nil {
+				ctx.ServerError("GetPossibleUserByID", err)
+				return
+			}
+			ctx.Data["LFSLockWarning"] = ctx.Tr("repo.editor.file_is_locked_warning", u.Name)
+		}
+
 		blob := entry.Blob()
 		if blob.Size() >= setting.UI.MaxDisplayFileSize {
 			ctx.NotFound("blob.Size", err)
@@ -154,6 +170,7 @@ func editFile(ctx *context.Context, isNewFile bool) {
 
 		ctx.Data["FileSize"] = blob.Size()
 		ctx.Data["FileName"] = blob.Name()
+		ctx.Data["FileIsSymlink"] = entry.IsLink()
 
 		buf := make([]byte, 1024)
 		n, _ := util.ReadAtMost(dataRc, buf)
@@ -240,6 +257,7 @@ func editFilePost(ctx *context.Context, form forms.EditRepoFileForm, isNewFile b
 	ctx.Data["TreePaths"] = treePaths
 	ctx.Data["BranchLink"] = ctx.Repo.RepoLink + "/src/branch/" + util.PathEscapeSegments(ctx.Repo.BranchName)
 	ctx.Data["FileContent"] = form.Content
+	ctx.Data["FileIsSymlink"] = form.IsSymlink
 	ctx.Data["commit_summary"] = form.CommitSummary
 	ctx.Data["commit_message"] = form.CommitMessage
 	ctx.Data["commit_choice"] = form.CommitChoice
@@ -282,6 +300,23 @@ func editFilePost(ctx *context.Context, form forms.EditRepoFileForm, isNewFile b
 		operation = "create"
 	}
 
+	// the browser submits CRLF line endings, an edited file keeps the line endings it already uses
+	lineEndings := files_service.LineEndingsLF
+	if !isNewFile && !form.IsSymlink {
+		if entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath); err == nil && entry.IsRegular() {
+			if lineEndings, err = files_service.BlobLineEndings(entry.Blob()); err != nil {
+				ctx.ServerError("BlobLineEndings", err)
+				return
+			}
+		}
+	}
+
+	content := form.Content
+	if form.IsSymlink {
+		// the editor may add a final newline which isn't part of the target of the link
+		content = strings.TrimRight(content, "\r\n")
+	}
+
 	if _, err := files_service.ChangeRepoFiles(ctx, ctx.Repo.Repository, ctx.Doer, &files_service.ChangeRepoFilesOptions{
 		LastCommitID: form.LastCommit,
 		OldBranch:    ctx.Repo.BranchName,
@@ -292,10 +327,13 @@ func editFilePost(ctx *context.Context, form forms.EditRepoFileForm, isNewFile b
 				Operation:     operation,
 				FromTreePath:  ctx.Repo.TreePath,
 				TreePath:      form.TreePath,
-				ContentReader: strings.NewReader(strings.ReplaceAll(form.Content, "\r", "")),
+				ContentReader: strings.NewReader(content),
+				IsSymlink:     form.IsSymlink,
+				LineEndings:   lineEndings,
 			},
 		},
-		Signoff: form.Signoff,
+		Signoff:           form.Signoff,
+		ApplyTemplateVars: isNewFile && form.ApplyTemplateVars,
 	}); err != nil {
 		// This is where we handle all the errors thrown by files_service.ChangeRepoFiles
 		if git.IsErrNotExist(err) {
@@ -402,12 +402,34 @@ func (h *serviceHandler) sendFile(ctx *context.Context, contentType, file string
 // one or more key=value pairs separated by colons
 var safeGitProtocolHeader = regexp.MustCompile(`^[0-9a-zA-Z]+=[0-9a-zA-Z]+(:[0-9a-zA-Z]+=[0-9a-zA-Z]+)*$`)
 
-func prepareGitCmdWithAllowedService(ctx *context.Context, service string) (*git.Command, error) {
+// allowsPartialClone returns whether the filters of partial clones can be used for the repository
+func (h *serviceHandler) allowsPartialClone() bool {
+	if setting.Git.DisablePartialClone {
+		return false
+	}
+	return setting.Git.PartialCloneMaxRepoSize <= 0 || h.isWiki || h.repo.GitSize <= setting.Git.PartialCloneMaxRepoSize
+}
+
+// hasPregeneratedBundle returns whether a bundle of the repository can be offered to the clients by the bundle-uri capability
+func (h *serviceHandler) hasPregeneratedBundle() bool {
+	return !h.isWiki && git.DefaultFeatures().CheckVersionAtLeast("2.41") && repo_service.HasPregeneratedBundle(h.repo)
+}
+
+func prepareGitCmdWithAllowedService(ctx *context.Context, h *serviceHandler, service string) (*git.Command, error) {
 	if service == "receive-pack" {
 		return git.NewCommand(ctx, "receive-pack"), nil
 	}
 	if service == "upload-pack" {
-		return git.NewCommand(ctx, "upload-pack"), nil
+		cmd := git.NewCommand(ctx)
+		if !h.allowsPartialClone() {
+			// the global config enables the filters, they are neither advertised nor accepted for this repository
+			cmd.AddArguments("-c", "uploadpack.allowFilter=false")
+		}
+		if h.hasPregeneratedBundle() {
+			cmd.AddArguments("-c", "uploadpack.advertiseBundleURIs=true", "-c", "bundle.version=1", "-c", "bundle.mode=all").
+				AddOptionValues("-c", "bundle.gitea.uri="+h.repo.HTMLURL()+".git/"+repo_service.PregeneratedBundleName)
+		}
+		return cmd.AddArguments("upload-pack"), nil
 	}
 
 	return nil, fmt.Errorf("service %q is not allowed", service)
@@ -427,7 +449,7 @@ func serviceRPC(ctx *context.Context, h *serviceHandler, service string) {
 		return
 	}
 
-	cmd, err := prepareGitCmdWithAllowedService(ctx, service)
+	cmd, err := prepareGitCmdWithAllowedService(ctx, h, service)
 	if err != nil {
 		log.Error("Failed to prepareGitCmdWithService: %v", err)
 		ctx.Resp.WriteHeader(http.StatusUnauthorized)
@@ -521,7 +543,7 @@ func GetInfoRefs(ctx *context.Context) {
 	}
 	setHeaderNoCache(ctx)
 	service := getServiceType(ctx)
-	cmd, err := prepareGitCmdWithAllowedService(ctx, service)
+	cmd, err := prepareGitCmdWithAllowedService(ctx, h, service)
 	if err == nil {
 		if protocol := ctx.Req.Header.Get("Git-Protocol"); protocol != "" && safeGitProtocolHeader.MatchString(protocol) {
 			h.environ = append(h.environ, "GIT_PROTOCOL="+protocol)
@@ -560,6 +582,20 @@ func GetTextFile(p string) func(*context.Context) {
 	}
 }
 
+// GetPregeneratedBundle serves the pregenerated bundle of a repository advertised by upload-pack
+func GetPregeneratedBundle(ctx *context.Context) {
+	h := httpBase(ctx)
+	if h != nil {
+		if h.isWiki {
+			ctx.Resp.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// the bundle is regenerated periodically
+		setHeaderNoCache(ctx)
+		h.sendFile(ctx, "application/octet-stream", repo_service.PregeneratedBundleName)
+	}
+}
+
 // GetInfoPacks implements Git dumb HTTP
 func GetInfoPacks(ctx *context.Context) {
 	h := httpBase(ctx)
@@ -201,11 +201,18 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption opt
 		mileIDs = []int64{milestoneID}
 	}
 
+	issueTypeID := ctx.FormInt64("issue_type")
+	var typeIDs []int64
+	if issueTypeID > 0 || issueTypeID == db.NoConditionID { // -1 to get those issues which have no type
+		typeIDs = []int64{issueTypeID}
+	}
+
 	var issueStats *issues_model.IssueStats
 	statsOpts := &issues_model.IssuesOptions{
 		RepoIDs:           []int64{repo.ID},
 		LabelIDs:          labelIDs,
 		MilestoneIDs:      mileIDs,
+		TypeIDs:           typeIDs,
 		ProjectID:         projectID,
 		AssigneeID:        assigneeID,
 		MentionedID:       mentionedID,
@@ -296,6 +303,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption opt
 			ReviewRequestedID: reviewRequestedID,
 			ReviewedID:        reviewedID,
 			MilestoneIDs:      mileIDs,
+			TypeIDs:           typeIDs,
 			ProjectID:         projectID,
 			IsClosed:          isShowClosed,
 			IsPull:            isPullOption,
@@ -349,6 +357,15 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption opt
 	ctx.Data["CommitLastStatus"] = lastStatus
 	ctx.Data["CommitStatuses"] = commitStatuses
 
+	if ctx.IsSigned && isPullOption.Value() {
+		reviewProgresses, err := pull_service.GetReviewProgresses(ctx, ctx.Repo.GitRepo, ctx.Doer.ID, issues)
+		if err != nil {
+			ctx.ServerError("GetReviewProgresses", err)
+			return
+		}
+		ctx.Data["ReviewProgresses"] = reviewProgresses
+	}
+
 	// Get assignees.
 	assigneeUsers, err := repo_model.GetRepoAssignees(ctx, repo)
 	if err != nil {
@@ -401,6 +418,13 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption opt
 	ctx.Data["Labels"] = labels
 	ctx.Data["NumLabels"] = len(labels)
 
+	issueTypes, err := issues_model.GetIssueTypesForRepo(ctx, repo.ID, repo.OwnerID)
+	if err != nil {
+		ctx.ServerError("GetIssueTypesForRepo", err)
+		return
+	}
+	ctx.Data["IssueTypes"] = issueTypes
+
 	if ctx.FormInt64("assignee") == 0 {
 		assigneeID = 0 // Reset ID to prevent unexpected selection of assignee.
 	}
@@ -442,21 +466,22 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption opt
 	ctx.Data["IssueStats"] = issueStats
 	ctx.Data["OpenCount"] = issueStats.OpenCount
 	ctx.Data["ClosedCount"] = issueStats.ClosedCount
-	linkStr := "%s?q=%s&type=%s&sort=%s&state=%s&labels=%s&milestone=%d&project=%d&assignee=%d&poster=%d&archived=%t"
+	linkStr := "%s?q=%s&type=%s&sort=%s&state=%s&labels=%s&milestone=%d&issue_type=%d&project=%d&assignee=%d&poster=%d&archived=%t"
 	ctx.Data["AllStatesLink"] = fmt.Sprintf(linkStr, ctx.Link,
 		url.QueryEscape(keyword), url.QueryEscape(viewType), url.QueryEscape(sortType), "all", url.QueryEscape(selectLabels),
-		milestoneID, projectID, assigneeID, posterID, archived)
+		milestoneID, issueTypeID, projectID, assigneeID, posterID, archived)
 	ctx.Data["OpenLink"] = fmt.Sprintf(linkStr, ctx.Link,
 		url.QueryEscape(keyword), url.QueryEscape(viewType), url.QueryEscape(sortType), "open", url.QueryEscape(selectLabels),
-		milestoneID, projectID, assigneeID, posterID, archived)
+		milestoneID, issueTypeID, projectID, assigneeID, posterID, archived)
 	ctx.Data["ClosedLink"] = fmt.Sprintf(linkStr, ctx.Link,
 		url.QueryEscape(keyword), url.QueryEscape(viewType), url.QueryEscape(sortType), "closed", url.QueryEscape(selectLabels),
-		milestoneID, projectID, assigneeID, posterID, archived)
+		milestoneID, issueTypeID, projectID, assigneeID, posterID, archived)
 	ctx.Data["SelLabelIDs"] = labelIDs
 	ctx.Data["SelectLabels"] = selectLabels
 	ctx.Data["ViewType"] = viewType
 	ctx.Data["SortType"] = sortType
 	ctx.Data["MilestoneID"] = milestoneID
+	ctx.Data["IssueTypeID"] = issueTypeID
 	ctx.Data["ProjectID"] = projectID
 	ctx.Data["AssigneeID"] = assigneeID
 	ctx.Data["PosterID"] = posterID
@@ -477,6 +502,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption opt
 	pager.AddParamString("state", fmt.Sprint(ctx.Data["State"]))
 	pager.AddParamString("labels", fmt.Sprint(selectLabels))
 	pager.AddParamString("milestone", fmt.Sprint(milestoneID))
+	pager.AddParamString("issue_type", fmt.Sprint(issueTypeID))
 	pager.AddParamString("project", fmt.Sprint(projectID))
 	pager.AddParamString("assignee", fmt.Sprint(assigneeID))
 	pager.AddParamString("poster", fmt.Sprint(posterID))
@@ -511,6 +537,10 @@ func Issues(ctx *context.Context) {
 		ctx.Data["Title"] = ctx.Tr("repo.issues")
 		ctx.Data["PageIsIssueList"] = true
 		ctx.Data["NewIssueChooseTemplate"] = issue_service.HasTemplatesOrContactLinks(ctx.Repo.Repository, ctx.Repo.GitRepo)
+
+		if applyIssueFilter(ctx); ctx.Written() {
+			return
+		}
 	}
 
 	issues(ctx, ctx.FormInt64("milestone"), ctx.FormInt64("project"), optional.Some(isPullList))
@@ -528,10 +558,47 @@ func Issues(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplIssues)
 }
 
+// applyIssueFilter redirects to the issue list with the saved filter of the "filter" parameter, or with the default
+// filter of the repository if the list is opened without any filter, and lists the saved filters
+func applyIssueFilter(ctx *context.Context) {
+	repo := ctx.Repo.Repository
+	var (
+		filter *issues_model.IssueFilter
+		userID int64
+		err    error
+	)
+	if ctx.Doer != nil {
+		userID = ctx.Doer.ID
+	}
+	if slug := ctx.FormString("filter"); slug != "" {
+		filter, err = issues_model.GetIssueFilterForRepoBySlug(ctx, repo.ID, repo.OwnerID, userID, slug)
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound("GetIssueFilterForRepoBySlug", err)
+			return
+		}
+	} else if ctx.Req.URL.RawQuery == "" {
+		filter, err = issues_model.GetDefaultIssueFilter(ctx, repo.ID)
+	}
+	if err != nil {
+		ctx.ServerError("GetIssueFilter", err)
+		return
+	}
+	if filter != nil {
+		ctx.Redirect(ctx.Repo.RepoLink + "/issues?" + filter.QueryString())
+		return
+	}
+
+	ctx.Data["IssueFilters"], err = issues_model.GetIssueFiltersForRepo(ctx, repo.ID, repo.OwnerID, userID)
+	if err != nil {
+		ctx.ServerError("GetIssueFiltersForRepo", err)
+	}
+}
+
 func renderMilestones(ctx *context.Context) {
 	// Get milestones
 	milestones, err := db.Find[issues_model.Milestone](ctx, issues_model.FindMilestoneOptions{
 		RepoID: ctx.Repo.Repository.ID,
+		OrgID:  ctx.Repo.Repository.OwnerID,
 	})
 	if err != nil {
 		ctx.ServerError("GetAllRepoMilestones", err)
@@ -555,6 +622,7 @@ func RetrieveRepoMilestonesAndAssignees(ctx *context.Context, repo *repo_model.R
 	var err error
 	ctx.Data["OpenMilestones"], err = db.Find[issues_model.Milestone](ctx, issues_model.FindMilestoneOptions{
 		RepoID:   repo.ID,
+		OrgID:    repo.OwnerID,
 		IsClosed: optional.Some(false),
 	})
 	if err != nil {
@@ -563,6 +631,7 @@ func RetrieveRepoMilestonesAndAssignees(ctx *context.Context, repo *repo_model.R
 	}
 	ctx.Data["ClosedMilestones"], err = db.Find[issues_model.Milestone](ctx, issues_model.FindMilestoneOptions{
 		RepoID:   repo.ID,
+		OrgID:    repo.OwnerID,
 		IsClosed: optional.Some(true),
 	})
 	if err != nil {
@@ -968,7 +1037,7 @@ func NewIssue(ctx *context.Context) {
 
 	milestoneID := ctx.FormInt64("milestone")
 	if milestoneID > 0 {
-		milestone, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, milestoneID)
+		milestone, err := issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, milestoneID)
 		if err != nil {
 			log.Error("GetMilestoneByID: %d: %v", milestoneID, err)
 		} else {
@@ -994,6 +1063,23 @@ func NewIssue(ctx *context.Context) {
 		}
 	}
 
+	issueTypes, err := issues_model.GetIssueTypesForRepo(ctx, ctx.Repo.Repository.ID, ctx.Repo.Repository.OwnerID)
+	if err != nil {
+		ctx.ServerError("GetIssueTypesForRepo", err)
+		return
+	}
+	ctx.Data["IssueTypes"] = issueTypes
+	if issueTypeID := ctx.FormInt64("issue_type"); issueTypeID > 0 {
+		for _, issueType := range issueTypes {
+			if issueType.ID == issueTypeID {
+				ctx.Data["issue_type_id"] = issueTypeID
+				if body == "" {
+					ctx.Data["BodyQuery"] = issueType.Template
+				}
+			}
+		}
+	}
+
 	RetrieveRepoMetas(ctx, ctx.Repo.Repository, false)
 
 	tags, err := repo_model.GetTagNamesByRepoID(ctx, ctx.Repo.Repository.ID)
@@ -1137,12 +1223,12 @@ func ValidateRepoMetas(ctx *context.Context, form forms.CreateIssueForm, isPull
 	// Check milestone.
 	milestoneID := form.MilestoneID
 	if milestoneID > 0 {
-		milestone, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, milestoneID)
+		milestone, err := issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, milestoneID)
 		if err != nil {
 			ctx.ServerError("GetMilestoneByID", err)
 			return nil, nil, 0, 0
 		}
-		if milestone.RepoID != repo.ID {
+		if milestone.RepoID != repo.ID && milestone.OrgID != repo.OwnerID {
 			ctx.ServerError("GetMilestoneByID", err)
 			return nil, nil, 0, 0
 		}
@@ -1247,6 +1333,10 @@ func NewIssuePost(ctx *context.Context) {
 	content := form.Content
 	if filename := ctx.Req.Form.Get("template-file"); filename != "" {
 		if template, err := issue_template.UnmarshalFromRepo(ctx.Repo.GitRepo, ctx.Repo.Repository.DefaultBranch, filename); err == nil {
+			if errs := issue_template.ValidateValues(template, ctx.Req.Form); len(errs) > 0 {
+				ctx.JSONError(ctx.Tr("repo.issues.new.form_invalid_fields", errs.Error()))
+				return
+			}
 			content = issue_template.RenderToMarkdown(template, ctx.Req.Form)
 		}
 	}
@@ -1262,6 +1352,31 @@ func NewIssuePost(ctx *context.Context) {
 		Ref:         form.Ref,
 	}
 
+	if form.IssueTypeID > 0 {
+		issueType, err := issues_model.GetIssueTypeForRepoByID(ctx, repo.ID, repo.OwnerID, form.IssueTypeID)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				ctx.JSONError(ctx.Tr("repo.issues.new.issue_type_not_exist"))
+			} else {
+				ctx.ServerError("GetIssueTypeForRepoByID", err)
+			}
+			return
+		}
+		if util.IsEmptyString(issue.Content) {
+			issue.Content = issueType.Template
+		}
+		if missing := issueType.MissingFields(&issues_model.IssueTypeFieldValues{
+			Content:     issue.Content,
+			LabelIDs:    labelIDs,
+			MilestoneID: milestoneID,
+			AssigneeIDs: assigneeIDs,
+		}); len(missing) > 0 {
+			ctx.JSONError(ctx.Tr("repo.issues.new.issue_type_missing_fields", issueType.Name, strings.Join(missing, ", ")))
+			return
+		}
+		issue.TypeID, issue.Type = issueType.ID, issueType
+	}
+
 	if err := issue_service.NewIssue(ctx, repo, issue, labelIDs, attachments, assigneeIDs, projectID); err != nil {
 		if repo_model.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err.Error())
@@ -1437,6 +1552,16 @@ func ViewIssue(ctx *context.Context) {
 	ctx.Data["IsAttachmentEnabled"] = setting.Attachment.Enabled
 	upload.AddUploadContext(ctx, "comment")
 
+	// the internal comments are only shown to the users who can write the issue
+	canSeeInternalComments := ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)
+	ctx.Data["CanSeeInternalComments"] = canSeeInternalComments
+	if !canSeeInternalComments {
+		if err = issue.LoadPublicComments(ctx); err != nil {
+			ctx.ServerError("LoadPublicComments", err)
+			return
+		}
+	}
+
 	if err = issue.LoadAttributes(ctx); err != nil {
 		ctx.ServerError("LoadAttributes", err)
 		return
@@ -1460,6 +1585,12 @@ func ViewIssue(ctx *context.Context) {
 		}
 	}
 	ctx.Data["IssueWatch"] = iw
+
+	prepareIssueVoting(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+
 	issue.RenderedContent, err = markdown.RenderString(&markup.RenderContext{
 		Links: markup.Links{
 			Base: ctx.Repo.RepoLink,
@@ -1687,7 +1818,7 @@ func ViewIssue(ctx *context.Context) {
 				ctx.ServerError("LoadAssigneeUserAndTeam", err)
 				return
 			}
-		} else if comment.Type == issues_model.CommentTypeRemoveDependency || comment.Type == issues_model.CommentTypeAddDependency {
+		} else if comment.Type == issues_model.CommentTypeRemoveDependency || comment.Type == issues_model.CommentTypeAddDependency || comment.Type == issues_model.CommentTypeDependencyClosed {
 			if err = comment.LoadDepIssueDetails(ctx); err != nil {
 				if !issues_model.IsErrIssueNotExist(err) {
 					ctx.ServerError("LoadDepIssueDetails", err)
@@ -1911,6 +2042,19 @@ func ViewIssue(ctx *context.Context) {
 			ctx.Data["ChangedProtectedFiles"] = pull.ChangedProtectedFiles
 			ctx.Data["IsBlockedByChangedProtectedFiles"] = len(pull.ChangedProtectedFiles) != 0
 			ctx.Data["ChangedProtectedFilesNum"] = len(pull.ChangedProtectedFiles)
+			if pb.RequireCodeOwnerApproval {
+				codeOwnerRuleStatuses, err := pull_service.GetCodeOwnerRuleStatuses(ctx, pb, pull)
+				if err != nil {
+					ctx.ServerError("GetCodeOwnerRuleStatuses", err)
+					return
+				}
+				ctx.Data["CodeOwnerRuleStatuses"] = codeOwnerRuleStatuses
+				for _, status := range codeOwnerRuleStatuses {
+					if !status.IsSatisfied() {
+						ctx.Data["IsBlockedByCodeOwners"] = true
+					}
+				}
+			}
 		}
 		ctx.Data["WillSign"] = false
 		if ctx.Doer != nil {
@@ -1962,11 +2106,44 @@ func ViewIssue(ctx *context.Context) {
 		ctx.Data["StillCanManualMerge"] = stillCanManualMerge()
 
 		// Check if there is a pending pr merge
-		ctx.Data["HasPendingPullRequestMerge"], ctx.Data["PendingPullRequestMerge"], err = pull_model.GetScheduledMergeByPullID(ctx, pull.ID)
+		hasPendingPullRequestMerge, pendingPullRequestMerge, err := pull_model.GetScheduledMergeByPullID(ctx, pull.ID)
 		if err != nil {
 			ctx.ServerError("GetScheduledMergeByPullID", err)
 			return
 		}
+		ctx.Data["HasPendingPullRequestMerge"] = hasPendingPullRequestMerge
+		ctx.Data["PendingPullRequestMerge"] = pendingPullRequestMerge
+		if hasPendingPullRequestMerge && pendingPullRequestMerge.AfterPullID > 0 {
+			afterPull, err := issues_model.GetPullRequestByID(ctx, pendingPullRequestMerge.AfterPullID)
+			if err != nil && !issues_model.IsErrPullRequestNotExist(err) {
+				ctx.ServerError("GetPullRequestByID", err)
+				return
+			}
+			ctx.Data["PendingPullRequestMergeAfterPull"] = afterPull
+		}
+
+		// Check if the pr is in the merge queue of its base branch
+		ctx.Data["MergeQueueEnabled"] = prConfig.EnableMergeQueue
+		isInMergeQueue, mergeQueueEntry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pull.ID)
+		if err != nil {
+			ctx.ServerError("GetMergeQueueEntryByPullID", err)
+			return
+		}
+		ctx.Data["IsInMergeQueue"] = isInMergeQueue
+		if isInMergeQueue {
+			if err := mergeQueueEntry.LoadDoer(ctx); err != nil {
+				ctx.ServerError("LoadDoer", err)
+				return
+			}
+			position, err := pull_model.MergeQueuePosition(ctx, mergeQueueEntry)
+			if err != nil {
+				ctx.ServerError("MergeQueuePosition", err)
+				return
+			}
+			ctx.Data["MergeQueueEntry"] = mergeQueueEntry
+			ctx.Data["MergeQueuePosition"] = position
+			ctx.Data["CanRemoveFromMergeQueue"] = ctx.Doer != nil && (ctx.Doer.ID == mergeQueueEntry.DoerID || ctx.Repo.IsAdmin())
+		}
 	}
 
 	// Get Dependencies
@@ -1991,6 +2168,34 @@ func ViewIssue(ctx *context.Context) {
 		return
 	}
 
+	// Get the parent and the sub-issues which the user can read
+	subIssues, err := issues_model.GetSubIssues(ctx, issue.ID)
+	if err != nil {
+		ctx.ServerError("GetSubIssues", err)
+		return
+	}
+	ctx.Data["SubIssues"] = filterReadableIssues(ctx, subIssues)
+	if ctx.Written() {
+		return
+	}
+
+	parentIssue, err := issues_model.GetParentIssue(ctx, issue.ID)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.ServerError("GetParentIssue", err)
+		return
+	} else if parentIssue != nil {
+		if err := parentIssue.LoadRepo(ctx); err != nil {
+			ctx.ServerError("LoadRepo", err)
+			return
+		}
+		if len(filterReadableIssues(ctx, issues_model.IssueList{parentIssue})) > 0 {
+			ctx.Data["ParentIssue"] = parentIssue
+		}
+		if ctx.Written() {
+			return
+		}
+	}
+
 	var pinAllowed bool
 	if !issue.IsPinned() {
 		pinAllowed, err = issues_model.IsNewPinAllowed(ctx, issue.RepoID, issue.IsPull)
@@ -2081,6 +2286,29 @@ func checkBlockedByIssues(ctx *context.Context, blockers []*issues_model.Depende
 	return canRead, notPermitted
 }
 
+// filterReadableIssues returns the issues which the user can read, the issues must have their repositories loaded
+func filterReadableIssues(ctx *context.Context, issues issues_model.IssueList) issues_model.IssueList {
+	repoPerms := make(map[int64]access_model.Permission)
+	repoPerms[ctx.Repo.Repository.ID] = ctx.Repo.Permission
+	readable := make(issues_model.IssueList, 0, len(issues))
+	for _, issue := range issues {
+		perm, ok := repoPerms[issue.RepoID]
+		if !ok {
+			var err error
+			perm, err = access_model.GetUserRepoPermission(ctx, issue.Repo, ctx.Doer)
+			if err != nil {
+				ctx.ServerError("GetUserRepoPermission", err)
+				return nil
+			}
+			repoPerms[issue.RepoID] = perm
+		}
+		if perm.CanReadIssuesOrPulls(issue.IsPull) {
+			readable = append(readable, issue)
+		}
+	}
+	return readable
+}
+
 func sortDependencyInfo(blockers []*issues_model.DependencyInfo) {
 	sort.Slice(blockers, func(i, j int) bool {
 		if blockers[i].RepoID == blockers[j].RepoID {
@@ -2744,6 +2972,22 @@ func getUserIDForFilter(ctx *context.Context, queryName string) int64 {
 	return user.ID
 }
 
+// SimilarIssues lists the open issues which are similar to the title of a new issue
+func SimilarIssues(ctx *context.Context) {
+	ids, err := issue_indexer.SearchSimilarIssues(ctx, ctx.Repo.Repository.ID, ctx.FormString("q"), issue_indexer.DefaultSimilarIssuesLimit)
+	if err != nil {
+		ctx.ServerError("SearchSimilarIssues", err)
+		return
+	}
+	issues, err := issues_model.GetIssuesByIDs(ctx, ids, true)
+	if err != nil {
+		ctx.ServerError("GetIssuesByIDs", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, ctx.Doer, issues))
+}
+
 // ListIssues list the issues of a repository
 func ListIssues(ctx *context.Context) {
 	before, since, err := context.GetQueryBeforeSince(ctx.Base)
@@ -2781,7 +3025,7 @@ func ListIssues(ctx *context.Context) {
 		for i := range part {
 			// uses names and fall back to ids
 			// non existent milestones are discarded
-			mile, err := issues_model.GetMilestoneByRepoIDANDName(ctx, ctx.Repo.Repository.ID, part[i])
+			mile, err := issues_model.GetMilestoneForRepoByName(ctx, ctx.Repo.Repository.ID, part[i])
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
@@ -2794,7 +3038,7 @@ func ListIssues(ctx *context.Context) {
 			if err != nil {
 				continue
 			}
-			mile, err = issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, id)
+			mile, err = issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, id)
 			if err == nil {
 				mileIDs = append(mileIDs, mile.ID)
 				continue
@@ -3122,10 +3366,17 @@ func NewComment(ctx *context.Context) {
 		return
 	}
 
-	comment, err := issue_service.CreateIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Content, attachments)
+	var err error
+	if form.IsInternal {
+		comment, err = issue_service.CreateInternalIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Content, attachments)
+	} else {
+		comment, err = issue_service.CreateIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Content, attachments)
+	}
 	if err != nil {
 		if errors.Is(err, user_model.ErrBlockedUser) {
 			ctx.JSONError(ctx.Tr("repo.issues.comment.blocked_user"))
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.JSONError(ctx.Tr("repo.issues.comment.internal_not_allowed"))
 		} else {
 			ctx.ServerError("CreateIssueComment", err)
 		}
@@ -3363,7 +3614,8 @@ func ChangeCommentReaction(ctx *context.Context) {
 		return
 	}
 
-	if comment.Issue.RepoID != ctx.Repo.Repository.ID {
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID ||
+		(comment.IsInternal && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull)) {
 		ctx.NotFound("CompareRepoID", issues_model.ErrCommentNotExist{})
 		return
 	}
@@ -3455,6 +3707,79 @@ func ChangeCommentReaction(ctx *context.Context) {
 	})
 }
 
+// ChangeResolutionReaction create or remove a reaction on the resolution of the conversation started by a code comment
+func ChangeResolutionReaction(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ReactionForm)
+	comment, err := issues_model.GetCommentByID(ctx, ctx.PathParamInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetCommentByID", issues_model.IsErrCommentNotExist, err)
+		return
+	}
+
+	if err := comment.LoadIssue(ctx); err != nil {
+		ctx.NotFoundOrServerError("LoadIssue", issues_model.IsErrIssueNotExist, err)
+		return
+	}
+
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID || !comment.Issue.IsPull || comment.Type != issues_model.CommentTypeCode {
+		ctx.NotFound("CompareRepoID", issues_model.ErrCommentNotExist{})
+		return
+	}
+
+	if !ctx.IsSigned || !ctx.Repo.CanReadIssuesOrPulls(true) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	switch ctx.PathParam(":action") {
+	case "react":
+		reaction, err := issue_service.CreateResolutionReaction(ctx, ctx.Doer, comment, form.Content)
+		if err != nil {
+			if issues_model.IsErrForbiddenIssueReaction(err) || errors.Is(err, user_model.ErrBlockedUser) {
+				ctx.ServerError("ChangeResolutionReaction", err)
+				return
+			}
+			log.Info("CreateResolutionReaction: %s", err)
+			break
+		}
+		log.Trace("Reaction for resolution created: %d/%d/%d/%d", ctx.Repo.Repository.ID, comment.Issue.ID, comment.ID, reaction.ID)
+	case "unreact":
+		if err := issues_model.DeleteResolutionReaction(ctx, ctx.Doer.ID, comment.Issue.ID, comment.ID, form.Content); err != nil {
+			ctx.ServerError("DeleteResolutionReaction", err)
+			return
+		}
+		log.Trace("Reaction for resolution removed: %d/%d/%d", ctx.Repo.Repository.ID, comment.Issue.ID, comment.ID)
+	default:
+		ctx.NotFound(fmt.Sprintf("Unknown action %s", ctx.PathParam(":action")), nil)
+		return
+	}
+
+	// Reload new reactions
+	if err = comment.LoadResolutionReactions(ctx, ctx.Repo.Repository); err != nil {
+		log.Info("comment.LoadResolutionReactions: %s", err)
+	}
+
+	if len(comment.ResolutionReactions) == 0 {
+		ctx.JSON(http.StatusOK, map[string]any{
+			"empty": true,
+			"html":  "",
+		})
+		return
+	}
+
+	html, err := ctx.RenderToHTML(tplReactions, map[string]any{
+		"ActionURL": fmt.Sprintf("%s/comments/%d/resolution/reactions", ctx.Repo.RepoLink, comment.ID),
+		"Reactions": comment.ResolutionReactions.GroupByType(),
+	})
+	if err != nil {
+		ctx.ServerError("ChangeResolutionReaction.HTMLString", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, map[string]any{
+		"html": html,
+	})
+}
+
 func addParticipant(poster *user_model.User, participants []*user_model.User) []*user_model.User {
 	for _, part := range participants {
 		if poster.ID == part.ID {
@@ -3520,7 +3845,8 @@ func GetCommentAttachments(ctx *context.Context) {
 		return
 	}
 
-	if !ctx.Repo.Permission.CanReadIssuesOrPulls(comment.Issue.IsPull) {
+	if !ctx.Repo.Permission.CanReadIssuesOrPulls(comment.Issue.IsPull) ||
+		(comment.IsInternal && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull)) {
 		ctx.NotFound("CanReadIssuesOrPulls", issues_model.ErrCommentNotExist{})
 		return
 	}
@@ -138,6 +138,12 @@ func GetContentHistoryDetail(ctx *context.Context) {
 			log.Error("can not get comment for issue content history %v. err=%v", historyID, err)
 			return
 		}
+		if comment.IsInternal && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+			ctx.JSON(http.StatusNotFound, map[string]any{
+				"message": "Can not find the content history",
+			})
+			return
+		}
 	}
 
 	// get the previous history revision (if exists)
@@ -28,7 +28,12 @@ func Labels(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.labels")
 	ctx.Data["PageIsIssueList"] = true
 	ctx.Data["PageIsLabels"] = true
-	ctx.Data["LabelTemplateFiles"] = repo_module.LabelTemplateFiles
+	labelTemplateFiles, err := repo_module.ListLabelTemplates(ctx)
+	if err != nil {
+		ctx.ServerError("ListLabelTemplates", err)
+		return
+	}
+	ctx.Data["LabelTemplateFiles"] = labelTemplateFiles
 	ctx.HTML(http.StatusOK, tplLabels)
 }
 
@@ -0,0 +1,88 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+const (
+	tplVoting base.TplName = "repo/issue/view_content/voting"
+)
+
+// IssueVote casts or removes the vote of the user for an issue
+func IssueVote(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !ctx.IsSigned || !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	vote, err := strconv.ParseBool(ctx.Req.PostForm.Get("vote"))
+	if err != nil {
+		ctx.ServerError("vote is not bool", err)
+		return
+	}
+
+	if vote {
+		_, err = issue_service.VoteForIssue(ctx, ctx.Doer, issue)
+	} else {
+		err = issue_service.RemoveIssueVote(ctx, ctx.Doer, issue)
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) || errors.Is(err, user_model.ErrBlockedUser) {
+			ctx.Error(http.StatusForbidden)
+		} else {
+			ctx.ServerError("VoteForIssue", err)
+		}
+		return
+	}
+
+	// reload the issue to get the updated number of votes
+	issue, err = issues_model.GetIssueByID(ctx, issue.ID)
+	if err != nil {
+		ctx.ServerError("GetIssueByID", err)
+		return
+	}
+
+	prepareIssueVoting(ctx, issue)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["Issue"] = issue
+	ctx.HTML(http.StatusOK, tplVoting)
+}
+
+func prepareIssueVoting(ctx *context.Context, issue *issues_model.Issue) {
+	if ctx.Doer == nil {
+		return
+	}
+
+	hasVoted, err := issues_model.HasVotedForIssue(ctx, ctx.Doer.ID, issue.ID)
+	if err != nil {
+		ctx.ServerError("HasVotedForIssue", err)
+		return
+	}
+	ctx.Data["HasVoted"] = hasVoted
+
+	err = issue_service.CanVoteForIssue(ctx, ctx.Doer, issue)
+	if err != nil && !errors.Is(err, util.ErrPermissionDenied) && !errors.Is(err, user_model.ErrBlockedUser) {
+		ctx.ServerError("CanVoteForIssue", err)
+		return
+	}
+	ctx.Data["CanVote"] = err == nil
+}
@@ -266,7 +266,7 @@ func DeleteMilestone(ctx *context.Context) {
 func MilestoneIssuesAndPulls(ctx *context.Context) {
 	milestoneID := ctx.PathParamInt64(":id")
 	projectID := ctx.FormInt64("project")
-	milestone, err := issues_model.GetMilestoneByRepoID(ctx, ctx.Repo.Repository.ID, milestoneID)
+	milestone, err := issues_model.GetMilestoneForRepo(ctx, ctx.Repo.Repository.ID, milestoneID)
 	if err != nil {
 		if issues_model.IsErrMilestoneNotExist(err) {
 			ctx.NotFound("GetMilestoneByID", err)
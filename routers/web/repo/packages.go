@@ -68,6 +68,11 @@ func Packages(ctx *context.Context) {
 	ctx.Data["PackageDescriptors"] = pds
 	ctx.Data["Total"] = total
 	ctx.Data["RepositoryAccessMap"] = map[int64]bool{ctx.Repo.Repository.ID: true} // There is only the current repository
+	ctx.Data["SignedVersions"], err = packages.GetAttestedVersionIDs(ctx, pvs)
+	if err != nil {
+		ctx.ServerError("GetAttestedVersionIDs", err)
+		return
+	}
 
 	pager := context.NewPagination(int(total), setting.UI.PackagesPagingNum, page, 5)
 	pager.AddParamString("q", query)
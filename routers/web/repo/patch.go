@@ -93,6 +93,7 @@ func NewDiffPatchPost(ctx *context.Context) {
 		NewBranch:    branchName,
 		Message:      message,
 		Content:      strings.ReplaceAll(form.Content, "\r", ""),
+		ThreeWay:     true,
 	})
 	if err != nil {
 		if git_model.IsErrBranchAlreadyExists(err) {
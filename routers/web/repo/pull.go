@@ -40,12 +40,11 @@ import (
 	"code.gitea.io/gitea/services/context/upload"
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/gitdiff"
+	"code.gitea.io/gitea/services/mergequeue"
 	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 	user_service "code.gitea.io/gitea/services/user"
-
-	"github.com/gobwas/glob"
 )
 
 const (
@@ -443,34 +442,18 @@ func PrepareViewPullInfo(ctx *context.Context, issue *issues_model.Issue) *git.C
 	}
 
 	if pb != nil && pb.EnableStatusCheck {
+		requiredChecks := git_model.ParseRequiredStatusChecks(pb.StatusCheckContexts)
 		var missingRequiredChecks []string
-		for _, requiredContext := range pb.StatusCheckContexts {
-			contextFound := false
-			matchesRequiredContext := createRequiredContextMatcher(requiredContext)
-			for _, presentStatus := range commitStatuses {
-				if matchesRequiredContext(presentStatus.Context) {
-					contextFound = true
-					break
-				}
-			}
-
-			if !contextFound {
-				missingRequiredChecks = append(missingRequiredChecks, requiredContext)
+		for _, result := range pull_service.CheckRequiredStatusChecks(commitStatuses, requiredChecks) {
+			if len(result.Contexts) == 0 {
+				missingRequiredChecks = append(missingRequiredChecks, result.Check.Rule)
 			}
 		}
 		ctx.Data["MissingRequiredChecks"] = missingRequiredChecks
 
 		ctx.Data["is_context_required"] = func(context string) bool {
-			for _, c := range pb.StatusCheckContexts {
-				if c == context {
-					return true
-				}
-				if gp, err := glob.Compile(c); err != nil {
-					// All newly created status_check_contexts are checked to ensure they are valid glob expressions before being stored in the database.
-					// But some old status_check_context created before glob was introduced may be invalid glob expressions.
-					// So log the error here for debugging.
-					log.Error("compile glob %q: %v", c, err)
-				} else if gp.Match(context) {
+			for _, check := range requiredChecks {
+				if check.Match(context) {
 					return true
 				}
 			}
@@ -528,18 +511,6 @@ func PrepareViewPullInfo(ctx *context.Context, issue *issues_model.Issue) *git.C
 	return compareInfo
 }
 
-func createRequiredContextMatcher(requiredContext string) func(string) bool {
-	if gp, err := glob.Compile(requiredContext); err == nil {
-		return func(contextToCheck string) bool {
-			return gp.Match(contextToCheck)
-		}
-	}
-
-	return func(contextToCheck string) bool {
-		return requiredContext == contextToCheck
-	}
-}
-
 type pullCommitList struct {
 	Commits             []pull_service.CommitInfo `json:"commits"`
 	LastReviewCommitSha string                    `json:"last_review_commit_sha"`
@@ -631,9 +602,10 @@ func viewPullFiles(ctx *context.Context, specifiedStartCommit, specifiedEndCommi
 	pull := issue.PullRequest
 
 	var (
-		startCommitID string
-		endCommitID   string
-		gitRepo       = ctx.Repo.GitRepo
+		startCommitID             string
+		endCommitID               string
+		gitRepo                   = ctx.Repo.GitRepo
+		isStartCommitPreviousHead bool
 	)
 
 	var prInfo *git.CompareInfo
@@ -663,6 +635,16 @@ func viewPullFiles(ctx *context.Context, specifiedStartCommit, specifiedEndCommi
 			}
 		}
 
+		// the start commit may be a previous head of the PR, e.g. the one of the last review before a force push
+		if !foundStartCommit && willShowSpecifiedCommitRange && gitRepo.IsCommitExist(specifiedStartCommit) {
+			var err error
+			if foundStartCommit, err = pull_service.IsHeadCommit(ctx, gitRepo, pull, specifiedStartCommit); err != nil {
+				ctx.ServerError("IsHeadCommit", err)
+				return
+			}
+			isStartCommitPreviousHead = foundStartCommit
+		}
+
 		if !(foundStartCommit && foundEndCommit) {
 			ctx.NotFound("Given SHA1 not found for this PR", nil)
 			return
@@ -722,6 +704,8 @@ func viewPullFiles(ctx *context.Context, specifiedStartCommit, specifiedEndCommi
 		MaxLineCharacters:  setting.Git.MaxGitDiffLineCharacters,
 		MaxFiles:           maxFiles,
 		WhitespaceBehavior: gitdiff.GetWhitespaceFlag(ctx.Data["WhitespaceBehavior"].(string)),
+		// a previous head may be unrelated to the current one after a force push
+		DirectComparison: isStartCommitPreviousHead,
 	}
 
 	if !willShowSpecifiedCommit {
@@ -877,6 +861,7 @@ func viewPullFiles(ctx *context.Context, specifiedStartCommit, specifiedEndCommi
 					ctx.Data["HeadRepoLink"] = pull.HeadRepo.Link()
 					ctx.Data["HeadBranchName"] = pull.HeadBranch
 					ctx.Data["BackToLink"] = setting.AppSubURL + ctx.Req.URL.RequestURI()
+					ctx.Data["CanApplySuggestions"] = !issue.IsClosed
 				}
 			}
 		}
@@ -1070,11 +1055,38 @@ func MergePullRequest(ctx *context.Context) {
 		message += "\n\n" + form.MergeMessageField
 	}
 
+	if !form.MergeWhenChecksSucceed && mergequeue.IsMergeQueueEnabled(ctx, ctx.Repo.Repository) {
+		// the pull request is merged by the merge queue of its base branch
+		if err := mergequeue.AddToMergeQueue(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message); err != nil {
+			switch {
+			case pull_model.IsErrAlreadyInMergeQueue(err):
+				ctx.JSONError(ctx.Tr("repo.pulls.merge_queue.already_added"))
+			case models.IsErrInvalidMergeStyle(err), errors.Is(err, util.ErrInvalidArgument):
+				ctx.JSONError(ctx.Tr("repo.pulls.invalid_merge_option"))
+			default:
+				ctx.ServerError("AddToMergeQueue", err)
+			}
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.pulls.merge_queue.added"))
+		ctx.JSONRedirect(issue.Link())
+		return
+	}
+
 	if form.MergeWhenChecksSucceed {
+		conditions, err := automerge.ConditionsFromForm(ctx, pr, form)
+		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.JSONError(err.Error())
+			} else {
+				ctx.ServerError("ConditionsFromForm", err)
+			}
+			return
+		}
 		// delete all scheduled auto merges
 		_ = pull_model.DeleteScheduledAutoMerge(ctx, pr.ID)
 		// schedule auto merge
-		scheduled, err := automerge.ScheduleAutoMerge(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message)
+		scheduled, err := automerge.ScheduleAutoMerge(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message, conditions)
 		if err != nil {
 			ctx.ServerError("ScheduleAutoMerge", err)
 			return
@@ -1086,8 +1098,8 @@ func MergePullRequest(ctx *context.Context) {
 		}
 	}
 
-	if err := pull_service.Merge(ctx, pr, ctx.Doer, ctx.Repo.GitRepo, repo_model.MergeStyle(form.Do), form.HeadCommitID, message, false); err != nil {
-		if models.IsErrInvalidMergeStyle(err) {
+	if err := pull_service.Merge(ctx, pr, ctx.Doer, ctx.Repo.GitRepo, repo_model.MergeStyle(form.Do), form.HeadCommitID, message, form.MergeStrategyOptions, false); err != nil {
+		if models.IsErrInvalidMergeStyle(err) || errors.Is(err, util.ErrInvalidArgument) {
 			ctx.JSONError(ctx.Tr("repo.pulls.invalid_merge_option"))
 		} else if models.IsErrMergeConflicts(err) {
 			conflictError := err.(models.ErrMergeConflicts)
@@ -1209,6 +1221,41 @@ func CancelAutoMergePullRequest(ctx *context.Context) {
 	ctx.Redirect(fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index))
 }
 
+// RemoveFromMergeQueuePullRequest removes a pr from the merge queue of its base branch
+func RemoveFromMergeQueuePullRequest(ctx *context.Context) {
+	issue, ok := getPullInfo(ctx)
+	if !ok {
+		return
+	}
+
+	exist, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, issue.PullRequest.ID)
+	if err != nil {
+		ctx.ServerError("GetMergeQueueEntryByPullID", err)
+		return
+	}
+	if !exist {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.merge_queue.not_added"))
+		ctx.Redirect(issue.Link())
+		return
+	}
+	if ctx.Doer.ID != entry.DoerID && !ctx.Repo.IsAdmin() {
+		ctx.NotFound("RemoveFromMergeQueuePullRequest", nil)
+		return
+	}
+
+	if err := mergequeue.RemoveFromMergeQueue(ctx, ctx.Doer, issue.PullRequest); err != nil {
+		if db.IsErrNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.merge_queue.not_added"))
+			ctx.Redirect(issue.Link())
+			return
+		}
+		ctx.ServerError("RemoveFromMergeQueue", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("repo.pulls.merge_queue.removed"))
+	ctx.Redirect(issue.Link())
+}
+
 func stopTimerIfAvailable(ctx *context.Context, user *user_model.User, issue *issues_model.Issue) error {
 	if issues_model.StopwatchExists(ctx, user.ID, issue.ID) {
 		if err := issues_model.CreateOrStopIssueStopwatch(ctx, user, issue); err != nil {
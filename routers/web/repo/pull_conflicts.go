@@ -0,0 +1,135 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+	pull_service "code.gitea.io/gitea/services/pull"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+const tplPullConflicts base.TplName = "repo/pulls/conflicts"
+
+// getPullToResolveConflicts returns the pull request whose conflicts are resolved if the doer is allowed to update its head branch
+func getPullToResolveConflicts(ctx *context.Context) *issues_model.Issue {
+	issue, ok := getPullInfo(ctx)
+	if !ok {
+		return nil
+	}
+	pr := issue.PullRequest
+	if issue.IsClosed || pr.HasMerged || pr.HeadRepo == nil || pr.Flow != issues_model.PullRequestFlowGithub {
+		ctx.NotFound("PullConflicts", nil)
+		return nil
+	}
+
+	allowed, _, err := pull_service.IsUserAllowedToUpdate(ctx, pr, ctx.Doer)
+	if err != nil {
+		ctx.ServerError("IsUserAllowedToUpdate", err)
+		return nil
+	}
+	if !allowed {
+		ctx.Error(http.StatusForbidden)
+		return nil
+	}
+	return issue
+}
+
+func renderPullConflicts(ctx *context.Context, issue *issues_model.Issue, conflicts *files_service.PullRequestConflicts) {
+	ctx.Data["PageIsPullList"] = true
+	ctx.Data["Conflicts"] = conflicts
+	ctx.Data["DefaultMessage"] = fmt.Sprintf("Merge branch '%s' into %s", issue.PullRequest.BaseBranch, issue.PullRequest.HeadBranch)
+	ctx.HTML(http.StatusOK, tplPullConflicts)
+}
+
+// PullConflicts shows the conflicts of a pull request with its base branch to resolve them in the web editor
+func PullConflicts(ctx *context.Context) {
+	issue := getPullToResolveConflicts(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	conflicts, err := files_service.GetPullRequestConflicts(ctx, issue.PullRequest)
+	if err != nil {
+		if git.IsErrBranchNotExist(err) || errors.Is(err, util.ErrInvalidArgument) {
+			ctx.NotFound("GetPullRequestConflicts", err)
+		} else {
+			ctx.ServerError("GetPullRequestConflicts", err)
+		}
+		return
+	}
+	if len(conflicts.Files) == 0 {
+		ctx.Flash.Info(ctx.Tr("repo.pulls.conflicts.none"))
+		ctx.Redirect(issue.Link())
+		return
+	}
+
+	renderPullConflicts(ctx, issue, conflicts)
+}
+
+// ResolvePullConflicts commits the resolved conflicts of a pull request to its head branch
+func ResolvePullConflicts(ctx *context.Context) {
+	issue := getPullToResolveConflicts(ctx)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*forms.ResolvePullRequestConflictsForm)
+	if len(form.Paths) != len(form.Contents) {
+		ctx.Error(http.StatusBadRequest, "the numbers of paths and contents differ")
+		return
+	}
+	files := make(map[string]string, len(form.Paths))
+	for i, path := range form.Paths {
+		files[path] = form.Contents[i]
+	}
+
+	_, err := files_service.ResolvePullRequestConflicts(ctx, ctx.Doer, issue.PullRequest, &files_service.ResolvePullRequestConflictsOptions{
+		HeadCommitID: form.HeadCommitID,
+		BaseCommitID: form.BaseCommitID,
+		Files:        files,
+		Message:      form.Message,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden)
+		case models.IsErrCommitIDDoesNotMatch(err), git.IsErrPushOutOfDate(err):
+			ctx.Flash.Error(ctx.Tr("repo.pulls.conflicts.outdated"))
+			ctx.Redirect(issue.Link() + "/conflicts")
+		case errors.Is(err, util.ErrInvalidArgument):
+			// render the conflicts again with the submitted contents so that the resolution isn't lost
+			conflicts, err2 := files_service.GetPullRequestConflicts(ctx, issue.PullRequest)
+			if err2 != nil {
+				ctx.ServerError("GetPullRequestConflicts", err2)
+				return
+			}
+			for _, file := range conflicts.Files {
+				if content, ok := files[file.Path]; ok {
+					file.Content = content
+				}
+			}
+			ctx.Flash.Error(err.Error(), true)
+			renderPullConflicts(ctx, issue, conflicts)
+		case git.IsErrPushRejected(err):
+			ctx.Flash.Error(ctx.Tr("repo.editor.push_rejected_no_message"))
+			ctx.Redirect(issue.Link() + "/conflicts")
+		default:
+			ctx.ServerError("ResolvePullRequestConflicts", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.pulls.conflicts.resolved"))
+	ctx.Redirect(issue.Link())
+}
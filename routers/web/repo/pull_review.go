@@ -8,18 +8,20 @@ import (
 	"fmt"
 	"net/http"
 
+	"code.gitea.io/gitea/models"
 	issues_model "code.gitea.io/gitea/models/issues"
-	pull_model "code.gitea.io/gitea/models/pull"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/context/upload"
 	"code.gitea.io/gitea/services/forms"
 	pull_service "code.gitea.io/gitea/services/pull"
+	files_service "code.gitea.io/gitea/services/repository/files"
 	user_service "code.gitea.io/gitea/services/user"
 )
 
@@ -290,10 +292,57 @@ func DismissReview(ctx *context.Context) {
 	ctx.Redirect(fmt.Sprintf("%s/pulls/%d#%s", ctx.Repo.RepoLink, comm.Issue.Index, comm.HashTag()))
 }
 
+// ApplySuggestions commits the changes suggested by the selected code comments to the head branch of the pull request
+func ApplySuggestions(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.NotFound("ApplySuggestions", nil)
+		return
+	}
+	if err := issue.LoadPullRequest(ctx); err != nil {
+		ctx.ServerError("LoadPullRequest", err)
+		return
+	}
+
+	redirectURL := fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index)
+	commentIDs, err := base.StringsToInt64s(ctx.FormStrings("comment_ids"))
+	if err != nil || len(commentIDs) == 0 {
+		ctx.Flash.Error(ctx.Tr("repo.issues.review.suggestion.none_selected"))
+		ctx.Redirect(redirectURL)
+		return
+	}
+
+	_, err = files_service.ApplySuggestions(ctx, ctx.Doer, issue.PullRequest, &files_service.ApplySuggestionsOptions{
+		CommentIDs: commentIDs,
+		Message:    ctx.FormString("message"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden)
+		case models.IsErrCommitIDDoesNotMatch(err), models.IsErrSHADoesNotMatch(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.review.suggestion.outdated"))
+			ctx.Redirect(redirectURL)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Flash.Error(err.Error())
+			ctx.Redirect(redirectURL)
+		default:
+			ctx.ServerError("ApplySuggestions", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.issues.review.suggestion.applied"))
+	ctx.Redirect(redirectURL)
+}
+
 // viewedFilesUpdate Struct to parse the body of a request to update the reviewed files of a PR
-// If you want to implement an API to update the review, simply move this struct into modules.
 type viewedFilesUpdate struct {
 	Files         map[string]bool `json:"files"`
+	Directories   map[string]bool `json:"directories"`
 	HeadCommitSHA string          `json:"headCommitSHA"`
 }
 
@@ -314,21 +363,7 @@ func UpdateViewedFiles(ctx *context.Context) {
 	}
 
 	// Expect the review to have been now if no head commit was supplied
-	if data.HeadCommitSHA == "" {
-		data.HeadCommitSHA = pull.HeadCommitID
-	}
-
-	updatedFiles := make(map[string]pull_model.ViewedState, len(data.Files))
-	for file, viewed := range data.Files {
-		// Only unviewed and viewed are possible, has-changed can not be set from the outside
-		state := pull_model.Unviewed
-		if viewed {
-			state = pull_model.Viewed
-		}
-		updatedFiles[file] = state
-	}
-
-	if err := pull_model.UpdateReviewState(ctx, ctx.Doer.ID, pull.ID, data.HeadCommitSHA, updatedFiles); err != nil {
-		ctx.ServerError("UpdateReview", err)
+	if err := pull_service.UpdateViewedFiles(ctx, ctx.Repo.GitRepo, ctx.Doer.ID, pull, data.HeadCommitSHA, data.Files, data.Directories); err != nil {
+		ctx.ServerError("UpdateViewedFiles", err)
 	}
 }
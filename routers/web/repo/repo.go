@@ -148,15 +148,38 @@ func getRepoPrivate(ctx *context.Context) bool {
 	}
 }
 
+// prepareRepoOptionTemplates lists the templates which can be used to initialize a repository
+func prepareRepoOptionTemplates(ctx *context.Context) bool {
+	gitignores, err := repo_module.ListGitignores(ctx)
+	if err != nil {
+		ctx.ServerError("ListGitignores", err)
+		return false
+	}
+	labelTemplateFiles, err := repo_module.ListLabelTemplates(ctx)
+	if err != nil {
+		ctx.ServerError("ListLabelTemplates", err)
+		return false
+	}
+	licenses, err := repo_module.ListLicenses(ctx)
+	if err != nil {
+		ctx.ServerError("ListLicenses", err)
+		return false
+	}
+	ctx.Data["Gitignores"] = gitignores
+	ctx.Data["LabelTemplateFiles"] = labelTemplateFiles
+	ctx.Data["Licenses"] = licenses
+	ctx.Data["Readmes"] = repo_module.Readmes
+	return true
+}
+
 // Create render creating repository page
 func Create(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("new_repo")
 
 	// Give default value for template to render.
-	ctx.Data["Gitignores"] = repo_module.Gitignores
-	ctx.Data["LabelTemplateFiles"] = repo_module.LabelTemplateFiles
-	ctx.Data["Licenses"] = repo_module.Licenses
-	ctx.Data["Readmes"] = repo_module.Readmes
+	if !prepareRepoOptionTemplates(ctx) {
+		return
+	}
 	ctx.Data["readme"] = "Default"
 	ctx.Data["private"] = getRepoPrivate(ctx)
 	ctx.Data["IsForcedPrivate"] = setting.Repository.ForcePrivate
@@ -181,7 +204,7 @@ func Create(ctx *context.Context) {
 	ctx.Data["CanCreateRepo"] = ctx.Doer.CanCreateRepo()
 	ctx.Data["MaxCreationLimit"] = ctx.Doer.MaxCreationLimit()
 	ctx.Data["SupportedObjectFormats"] = git.DefaultFeatures().SupportedObjectFormats
-	ctx.Data["DefaultObjectFormat"] = git.Sha1ObjectFormat
+	ctx.Data["DefaultObjectFormat"] = git.ObjectFormatFromName(git.DefaultObjectFormatName())
 
 	ctx.HTML(http.StatusOK, tplCreate)
 }
@@ -213,6 +236,8 @@ func handleCreateError(ctx *context.Context, owner *user_model.User, err error,
 	case db.IsErrNamePatternNotAllowed(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(db.ErrNamePatternNotAllowed).Pattern), tpl, form)
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.RenderWithErr(err.Error(), tpl, form)
 	default:
 		ctx.ServerError(name, err)
 	}
@@ -223,10 +248,9 @@ func CreatePost(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.CreateRepoForm)
 	ctx.Data["Title"] = ctx.Tr("new_repo")
 
-	ctx.Data["Gitignores"] = repo_module.Gitignores
-	ctx.Data["LabelTemplateFiles"] = repo_module.LabelTemplateFiles
-	ctx.Data["Licenses"] = repo_module.Licenses
-	ctx.Data["Readmes"] = repo_module.Readmes
+	if !prepareRepoOptionTemplates(ctx) {
+		return
+	}
 
 	ctx.Data["CanCreateRepo"] = ctx.Doer.CanCreateRepo()
 	ctx.Data["MaxCreationLimit"] = ctx.Doer.MaxCreationLimit()
@@ -530,7 +554,7 @@ func InitiateDownload(ctx *context.Context) {
 		return
 	}
 
-	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID)
+	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID, aReq.Include, aReq.Path)
 	if err != nil {
 		ctx.ServerError("archiver_service.StartArchive", err)
 		return
@@ -5,40 +5,40 @@ package repo
 
 import (
 	"net/http"
-	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/git"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/indexer/code/gitgrep"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/context"
+	wiki_service "code.gitea.io/gitea/services/wiki"
 )
 
 const tplSearch base.TplName = "repo/search"
 
-func indexSettingToGitGrepPathspecList() (list []string) {
-	for _, expr := range setting.Indexer.IncludePatterns {
-		list = append(list, ":(glob)"+expr.PatternString())
-	}
-	for _, expr := range setting.Indexer.ExcludePatterns {
-		list = append(list, ":(glob,exclude)"+expr.PatternString())
-	}
-	return list
-}
-
 // Search render repository search page
 func Search(ctx *context.Context) {
 	language := ctx.FormTrim("l")
 	keyword := ctx.FormTrim("q")
 
 	isFuzzy := ctx.FormOptionalBool("fuzzy").ValueOrDefault(true)
+	canSearchWiki := ctx.Repo.Repository.UnitEnabled(ctx, unit.TypeWiki) && ctx.Repo.CanRead(unit.TypeWiki)
+	isWikiSearch := canSearchWiki && ctx.FormString("scope") == "wiki"
 
 	ctx.Data["Keyword"] = keyword
 	ctx.Data["Language"] = language
 	ctx.Data["IsFuzzy"] = isFuzzy
 	ctx.Data["PageIsViewCode"] = true
 	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["CanSearchWiki"] = canSearchWiki
+	ctx.Data["IsWikiSearch"] = isWikiSearch
+
+	if isWikiSearch {
+		ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.WikiIndexerEnabled
+	}
 
 	if keyword == "" {
 		ctx.HTML(http.StatusOK, tplSearch)
@@ -50,6 +50,11 @@ func Search(ctx *context.Context) {
 		page = 1
 	}
 
+	if isWikiSearch {
+		searchWiki(ctx, keyword, isFuzzy, page)
+		return
+	}
+
 	var total int
 	var searchResults []*code_indexer.Result
 	var searchResultLanguages []*code_indexer.SearchResultLanguages
@@ -75,32 +80,14 @@ func Search(ctx *context.Context) {
 			ctx.Data["CodeIndexerUnavailable"] = !code_indexer.IsAvailable(ctx)
 		}
 	} else {
-		res, err := git.GrepSearch(ctx, ctx.Repo.GitRepo, keyword, git.GrepOptions{
-			ContextLineNumber: 1,
-			IsFuzzy:           isFuzzy,
-			RefName:           git.RefNameFromBranch(ctx.Repo.BranchName).String(), // BranchName should be default branch or the first existing branch
-			PathspecList:      indexSettingToGitGrepPathspecList(),
-		})
+		var err error
+		// BranchName should be default branch or the first existing branch
+		searchResults, total, err = gitgrep.PerformSearch(ctx, page, setting.UI.RepoSearchPagingNum, ctx.Repo.Repository.ID, ctx.Repo.GitRepo,
+			git.RefNameFromBranch(ctx.Repo.BranchName), keyword, isFuzzy, gitgrep.IndexSettingToGitGrepPathspecList())
 		if err != nil {
-			// TODO: if no branch exists, it reports: exit status 128, fatal: this operation must be run in a work tree.
 			ctx.ServerError("GrepSearch", err)
 			return
 		}
-		total = len(res)
-		pageStart := min((page-1)*setting.UI.RepoSearchPagingNum, len(res))
-		pageEnd := min(page*setting.UI.RepoSearchPagingNum, len(res))
-		res = res[pageStart:pageEnd]
-		for _, r := range res {
-			searchResults = append(searchResults, &code_indexer.Result{
-				RepoID:   ctx.Repo.Repository.ID,
-				Filename: r.Filename,
-				CommitID: ctx.Repo.CommitID,
-				// UpdatedUnix: not supported yet
-				// Language:    not supported yet
-				// Color:       not supported yet
-				Lines: code_indexer.HighlightSearchResultCode(r.Filename, "", r.LineNumbers, strings.Join(r.LineCodes, "\n")),
-			})
-		}
 	}
 
 	ctx.Data["Repo"] = ctx.Repo.Repository
@@ -114,3 +101,29 @@ func Search(ctx *context.Context) {
 
 	ctx.HTML(http.StatusOK, tplSearch)
 }
+
+func searchWiki(ctx *context.Context, keyword string, isFuzzy bool, page int) {
+	total, searchResults, err := wiki_service.SearchWikiPages(ctx, ctx.Repo.Repository, keyword, isFuzzy, db.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.RepoSearchPagingNum,
+	})
+	if err != nil {
+		if !setting.Indexer.WikiIndexerEnabled || code_indexer.IsWikiAvailable(ctx) {
+			ctx.ServerError("SearchWikiPages", err)
+			return
+		}
+		ctx.Data["CodeIndexerUnavailable"] = true
+	} else if setting.Indexer.WikiIndexerEnabled {
+		ctx.Data["CodeIndexerUnavailable"] = !code_indexer.IsWikiAvailable(ctx)
+	}
+
+	ctx.Data["Repo"] = ctx.Repo.Repository
+	ctx.Data["WikiSearchResults"] = searchResults
+
+	pager := context.NewPagination(total, setting.UI.RepoSearchPagingNum, page, 5)
+	pager.SetDefaultParams(ctx)
+	pager.AddParamString("scope", "wiki")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplSearch)
+}
@@ -5,6 +5,7 @@ package setting
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	gotemplate "html/template"
 	"io"
@@ -14,20 +15,26 @@ import (
 	"strconv"
 	"strings"
 
+	admin_model "code.gitea.io/gitea/models/admin"
 	git_model "code.gitea.io/gitea/models/git"
+	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/charset"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/git/pipeline"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/context"
+	repo_service "code.gitea.io/gitea/services/repository"
+	task_service "code.gitea.io/gitea/services/task"
 )
 
 const (
@@ -36,6 +43,7 @@ const (
 	tplSettingsLFSFile     base.TplName = "repo/settings/lfs_file"
 	tplSettingsLFSFileFind base.TplName = "repo/settings/lfs_file_find"
 	tplSettingsLFSPointers base.TplName = "repo/settings/lfs_pointers"
+	tplSettingsLFSMigrate  base.TplName = "repo/settings/lfs_migrate"
 )
 
 // LFSFiles shows a repository's LFS files
@@ -97,6 +105,24 @@ func LFSLocks(ctx *context.Context) {
 	}
 	ctx.Data["LFSLocks"] = lfsLocks
 
+	ownerIDs := make([]int64, 0, len(lfsLocks))
+	for _, lock := range lfsLocks {
+		ownerIDs = append(ownerIDs, lock.OwnerID)
+	}
+	owners, err := user_model.GetUsersByIDs(ctx, ownerIDs)
+	if err != nil {
+		ctx.ServerError("GetUsersByIDs", err)
+		return
+	}
+	lockOwners := make(map[int64]*user_model.User, len(lfsLocks))
+	for _, lock := range lfsLocks {
+		lockOwners[lock.OwnerID] = user_model.NewGhostUser()
+	}
+	for _, owner := range owners {
+		lockOwners[owner.ID] = owner
+	}
+	ctx.Data["LockOwners"] = lockOwners
+
 	if len(lfsLocks) == 0 {
 		ctx.Data["Page"] = pager
 		ctx.HTML(http.StatusOK, tplSettingsLFSLocks)
@@ -238,6 +264,10 @@ func LFSUnlock(ctx *context.Context) {
 	}
 	_, err := git_model.DeleteLFSLockByID(ctx, ctx.PathParamInt64("lid"), ctx.Repo.Repository, ctx.Doer, true)
 	if err != nil {
+		if git_model.IsErrLFSLockNotExist(err) {
+			ctx.NotFound("LFSUnlock", err)
+			return
+		}
 		ctx.ServerError("LFSUnlock", err)
 		return
 	}
@@ -554,3 +584,63 @@ func LFSAutoAssociate(ctx *context.Context) {
 	}
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/lfs")
 }
+
+// LFSMigrate shows the form to migrate files in the history of a repository to LFS and the status of the last migration
+func LFSMigrate(ctx *context.Context) {
+	if !setting.LFS.StartServer {
+		ctx.NotFound("LFSMigrate", nil)
+		return
+	}
+	ctx.Data["Title"] = ctx.Tr("repo.settings.lfs_migrate")
+	ctx.Data["PageIsSettingsLFS"] = true
+	ctx.Data["LFSFilesLink"] = ctx.Repo.RepoLink + "/settings/lfs"
+
+	task, err := admin_model.GetLatestTask(ctx, ctx.Repo.Repository.ID, structs.TaskTypeMigrateLFS)
+	if err != nil && !admin_model.IsErrTaskDoesNotExist(err) {
+		ctx.ServerError("GetLatestTask", err)
+		return
+	}
+	if task != nil {
+		var opts repo_service.MigrateToLFSOptions
+		if err := json.Unmarshal([]byte(task.PayloadContent), &opts); err != nil {
+			ctx.ServerError("Unmarshal", err)
+			return
+		}
+		ctx.Data["LFSMigrateTask"] = task
+		ctx.Data["LFSMigrateOptions"] = opts
+		ctx.Data["LFSMigrateMessage"] = task.LocaleMessage(ctx.Locale)
+		ctx.Data["LFSMigrateBackupRefPrefix"] = task_service.LFSMigrateBackupRefPrefix(task.ID)
+	}
+
+	ctx.HTML(http.StatusOK, tplSettingsLFSMigrate)
+}
+
+// LFSMigratePost starts a migration of files in the history of a repository to LFS
+func LFSMigratePost(ctx *context.Context) {
+	if !setting.LFS.StartServer {
+		ctx.NotFound("LFSMigratePost", nil)
+		return
+	}
+
+	_, err := task_service.MigrateRepositoryToLFS(ctx, ctx.Doer, ctx.Repo.Repository, repo_service.MigrateToLFSOptions{
+		Patterns:               strings.Split(ctx.FormString("patterns"), "\n"),
+		Branches:               strings.Split(ctx.FormString("branches"), ","),
+		IgnoreOpenPullRequests: ctx.FormBool("ignore_open_pull_requests"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrAlreadyExist):
+			ctx.Flash.Error(ctx.Tr("repo.settings.lfs_migrate.in_progress"))
+		case repo_service.IsErrLFSMigrateOpenPullRequests(err):
+			ctx.Flash.Error(ctx.Tr("repo.settings.lfs_migrate.open_pull_requests", err.(repo_service.ErrLFSMigrateOpenPullRequests).Count))
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Flash.Error(err.Error())
+		default:
+			ctx.ServerError("MigrateRepositoryToLFS", err)
+			return
+		}
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.lfs_migrate.started"))
+	}
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/lfs/migrate")
+}
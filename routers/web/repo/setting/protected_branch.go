@@ -21,8 +21,6 @@ import (
 	"code.gitea.io/gitea/services/forms"
 	pull_service "code.gitea.io/gitea/services/pull"
 	"code.gitea.io/gitea/services/repository"
-
-	"github.com/gobwas/glob"
 )
 
 const (
@@ -197,7 +195,7 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 			if trimmed == "" {
 				continue
 			}
-			if _, err := glob.Compile(trimmed); err != nil {
+			if _, err := git_model.ParseRequiredStatusCheck(trimmed); err != nil {
 				ctx.Flash.Error(ctx.Tr("repo.settings.protect_invalid_status_check_pattern", pattern))
 				ctx.Redirect(fmt.Sprintf("%s/settings/branches/edit?rule_name=%s", ctx.Repo.RepoLink, url.QueryEscape(protectBranch.RuleName)))
 				return
@@ -230,9 +228,11 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 	protectBranch.DismissStaleApprovals = f.DismissStaleApprovals
 	protectBranch.IgnoreStaleApprovals = f.IgnoreStaleApprovals
 	protectBranch.RequireSignedCommits = f.RequireSignedCommits
+	protectBranch.RequireSignoff = f.RequireSignoff
 	protectBranch.ProtectedFilePatterns = f.ProtectedFilePatterns
 	protectBranch.UnprotectedFilePatterns = f.UnprotectedFilePatterns
 	protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
+	protectBranch.RequireCodeOwnerApproval = f.RequireCodeOwnerApproval
 
 	err = git_model.UpdateProtectBranch(ctx, ctx.Repo.Repository, protectBranch, git_model.WhitelistOptions{
 		UserIDs:          whitelistUsers,
@@ -26,6 +26,7 @@ import (
 	"code.gitea.io/gitea/modules/indexer/stats"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -37,6 +38,7 @@ import (
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 	wiki_service "code.gitea.io/gitea/services/wiki"
 )
@@ -66,6 +68,9 @@ func SettingsCtxData(ctx *context.Context) {
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
 	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["IssueKeywordLanguages"] = references.KeywordLanguages()
+	ctx.Data["DefaultCloseKeywords"] = strings.Join(setting.Repository.PullRequest.CloseKeywords, ", ")
+	ctx.Data["DefaultReopenKeywords"] = strings.Join(setting.Repository.PullRequest.ReopenKeywords, ", ")
 
 	if ctx.Doer.IsAdmin {
 		if setting.Indexer.RepoIndexerEnabled {
@@ -529,6 +534,11 @@ func SettingsPost(ctx *context.Context) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					CloseKeywords:                    splitKeywords(form.IssueCloseKeywords),
+					ReopenKeywords:                   splitKeywords(form.IssueReopenKeywords),
+					KeywordLanguages:                 form.IssueKeywordLanguages,
+					CloseOnlyOnDefaultBranch:         form.IssueCloseOnlyOnDefaultBranch,
+					CloseOnlyInSameMilestone:         form.IssueCloseOnlyInSameMilestone,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeExternalTracker)
@@ -581,6 +591,17 @@ func SettingsPost(ctx *context.Context) {
 		}
 
 		if form.EnablePulls && !unit_model.TypePullRequests.UnitGlobalDisabled() {
+			var mergeStrategyOptions []string
+			for _, option := range strings.Split(form.PullsMergeStrategyOptions, ",") {
+				if option = strings.TrimSpace(option); option != "" {
+					mergeStrategyOptions = append(mergeStrategyOptions, option)
+				}
+			}
+			if err := pull_service.ValidateMergeStrategy(form.PullsMergeStrategy, mergeStrategyOptions); err != nil {
+				ctx.Flash.Error(ctx.Tr("repo.settings.pulls.merge_strategy_error"))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
 				Type:   unit_model.TypePullRequests,
@@ -597,6 +618,11 @@ func SettingsPost(ctx *context.Context) {
 					DefaultDeleteBranchAfterMerge: form.DefaultDeleteBranchAfterMerge,
 					DefaultMergeStyle:             repo_model.MergeStyle(form.PullsDefaultMergeStyle),
 					DefaultAllowMaintainerEdit:    form.DefaultAllowMaintainerEdit,
+					MergeStrategy:                 form.PullsMergeStrategy,
+					MergeStrategyOptions:          mergeStrategyOptions,
+					EnableMergeQueue:              form.PullsEnableMergeQueue,
+					RetargetOnBaseDeletion:        form.PullsRetargetOnBaseDeletion,
+					RebaseOnRetarget:              form.PullsRebaseOnRetarget,
 				},
 			})
 		} else if !unit_model.TypePullRequests.UnitGlobalDisabled() {
@@ -989,3 +1015,14 @@ func selectPushMirrorByForm(ctx *context.Context, form *forms.RepoSettingForm, r
 
 	return nil, fmt.Errorf("PushMirror[%v] not associated to repository %v", id, repo)
 }
+
+// splitKeywords splits comma-separated keywords, the empty ones are dropped
+func splitKeywords(s string) []string {
+	var keywords []string
+	for _, keyword := range strings.Split(s, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
@@ -185,7 +185,8 @@ func ParseHookEvent(form forms.WebhookForm) *webhook_module.HookEvent {
 			Repository:               form.Repository,
 			Package:                  form.Package,
 		},
-		BranchFilter: form.BranchFilter,
+		BranchFilter:      form.BranchFilter,
+		PackageTypeFilter: form.PackageTypeFilter,
 	}
 }
 
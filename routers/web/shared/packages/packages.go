@@ -6,19 +6,18 @@ package packages
 import (
 	"fmt"
 	"net/http"
-	"time"
+	"strings"
 
-	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
+	packages_service "code.gitea.io/gitea/services/packages"
 	cargo_service "code.gitea.io/gitea/services/packages/cargo"
-	container_service "code.gitea.io/gitea/services/packages/container"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
 )
 
 func SetPackagesContext(ctx *context.Context, owner *user_model.User) {
@@ -29,6 +28,14 @@ func SetPackagesContext(ctx *context.Context, owner *user_model.User) {
 	}
 
 	ctx.Data["CleanupRules"] = pcrs
+
+	requireSignatures, err := packages_service.IsSignatureRequired(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("IsSignatureRequired", err)
+		return
+	}
+
+	ctx.Data["RequireSignatures"] = requireSignatures
 }
 
 func SetRuleAddContext(ctx *context.Context) {
@@ -95,6 +102,13 @@ func performRuleEditPost(ctx *context.Context, owner *user_model.User, pcr *pack
 	pcr.RemoveDays = form.RemoveDays
 	pcr.RemovePattern = form.RemovePattern
 	pcr.MatchFullName = form.MatchFullName
+	pcr.RemoveUntaggedDays = form.RemoveUntaggedDays
+	pcr.ProtectedTags = pcr.ProtectedTags[:0]
+	for _, pattern := range strings.Split(form.ProtectedTags, "\n") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			pcr.ProtectedTags = append(pcr.ProtectedTags, pattern)
+		}
+	}
 
 	ctx.Data["IsEditRule"] = isEditRule
 	ctx.Data["CleanupRule"] = pcr
@@ -105,6 +119,12 @@ func performRuleEditPost(ctx *context.Context, owner *user_model.User, pcr *pack
 		return
 	}
 
+	if err := pcr.CompiledPattern(); err != nil {
+		ctx.Data["Err_ProtectedTags"] = true
+		ctx.RenderWithErr(ctx.Tr("packages.owner.settings.cleanuprules.keep.protected_tags")+ctx.Tr("form.glob_pattern_error", err.Error()), template, form)
+		return
+	}
+
 	if isEditRule {
 		if err := packages_model.UpdateCleanupRule(ctx, pcr); err != nil {
 			ctx.ServerError("UpdateCleanupRule", err)
@@ -139,64 +159,18 @@ func SetRulePreviewContext(ctx *context.Context, owner *user_model.User) {
 		return
 	}
 
-	if err := pcr.CompiledPattern(); err != nil {
-		ctx.ServerError("CompiledPattern", err)
+	pvs, err := packages_cleanup_service.GetVersionsToRemove(ctx, pcr)
+	if err != nil {
+		ctx.ServerError("GetVersionsToRemove", err)
 		return
 	}
 
-	olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
-
-	packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
+	versionsToRemove, err := packages_model.GetPackageDescriptors(ctx, pvs)
 	if err != nil {
-		ctx.ServerError("GetPackagesByType", err)
+		ctx.ServerError("GetPackageDescriptors", err)
 		return
 	}
 
-	versionsToRemove := make([]*packages_model.PackageDescriptor, 0, 10)
-
-	for _, p := range packages {
-		pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
-			PackageID:  p.ID,
-			IsInternal: optional.Some(false),
-			Sort:       packages_model.SortCreatedDesc,
-			Paginator:  db.NewAbsoluteListOptions(pcr.KeepCount, 200),
-		})
-		if err != nil {
-			ctx.ServerError("SearchVersions", err)
-			return
-		}
-		for _, pv := range pvs {
-			if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
-				ctx.ServerError("ShouldBeSkipped", err)
-				return
-			} else if skip {
-				continue
-			}
-
-			toMatch := pv.LowerVersion
-			if pcr.MatchFullName {
-				toMatch = p.LowerName + "/" + pv.LowerVersion
-			}
-
-			if pcr.KeepPatternMatcher != nil && pcr.KeepPatternMatcher.MatchString(toMatch) {
-				continue
-			}
-			if pv.CreatedUnix.AsLocalTime().After(olderThan) {
-				continue
-			}
-			if pcr.RemovePatternMatcher != nil && !pcr.RemovePatternMatcher.MatchString(toMatch) {
-				continue
-			}
-
-			pd, err := packages_model.GetPackageDescriptor(ctx, pv)
-			if err != nil {
-				ctx.ServerError("GetPackageDescriptor", err)
-				return
-			}
-			versionsToRemove = append(versionsToRemove, pd)
-		}
-	}
-
 	ctx.Data["CleanupRule"] = pcr
 	ctx.Data["VersionsToRemove"] = versionsToRemove
 }
@@ -245,3 +219,12 @@ func RebuildCargoIndex(ctx *context.Context, owner *user_model.User) {
 		ctx.Flash.Success(ctx.Tr("packages.owner.settings.cargo.rebuild.success"))
 	}
 }
+
+func SetRequireSignatures(ctx *context.Context, owner *user_model.User) {
+	err := packages_service.SetSignatureRequired(ctx, owner.ID, ctx.FormBool("require_signatures"))
+	if err != nil {
+		ctx.ServerError("SetSignatureRequired", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("packages.owner.settings.signatures.success"))
+}
@@ -4,6 +4,7 @@
 package user
 
 import (
+	"errors"
 	"net/http"
 	"net/url"
 
@@ -21,6 +22,7 @@ import (
 	"code.gitea.io/gitea/modules/optional"
 	alpine_module "code.gitea.io/gitea/modules/packages/alpine"
 	debian_module "code.gitea.io/gitea/modules/packages/debian"
+	nuget_module "code.gitea.io/gitea/modules/packages/nuget"
 	rpm_module "code.gitea.io/gitea/modules/packages/rpm"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -30,6 +32,7 @@ import (
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
 	packages_service "code.gitea.io/gitea/services/packages"
+	maven_service "code.gitea.io/gitea/services/packages/maven"
 )
 
 const (
@@ -104,6 +107,11 @@ func ListPackages(ctx *context.Context) {
 	ctx.Data["PackageDescriptors"] = pds
 	ctx.Data["Total"] = total
 	ctx.Data["RepositoryAccessMap"] = repositoryAccessMap
+	ctx.Data["SignedVersions"], err = packages_model.GetAttestedVersionIDs(ctx, pvs)
+	if err != nil {
+		ctx.ServerError("GetAttestedVersionIDs", err)
+		return
+	}
 
 	err = shared_user.LoadHeaderCount(ctx)
 	if err != nil {
@@ -177,9 +185,11 @@ func ViewPackageVersion(ctx *context.Context) {
 	ctx.Data["Title"] = pd.Package.Name
 	ctx.Data["IsPackagesPage"] = true
 	ctx.Data["PackageDescriptor"] = pd
+	ctx.Data["UpstreamURL"] = pd.VersionProperties.GetByName(packages_model.PropertyUpstreamURL)
+	ctx.Data["PromotedFrom"] = pd.VersionProperties.GetByName(packages_model.PropertyPromotedFrom)
 
 	switch pd.Package.Type {
-	case packages_model.TypeContainer:
+	case packages_model.TypeContainer, packages_model.TypeTerraform:
 		registryAppURL, err := url.Parse(httplib.GuessCurrentAppURL(ctx))
 		if err != nil {
 			registryAppURL, _ = url.Parse(setting.AppURL)
@@ -244,6 +254,15 @@ func ViewPackageVersion(ctx *context.Context) {
 
 		ctx.Data["Groups"] = util.Sorted(groups.Values())
 		ctx.Data["Architectures"] = util.Sorted(architectures.Values())
+	case packages_model.TypeNuGet:
+		symbolFiles := make([]string, 0, 2)
+		for _, f := range pd.Files {
+			if f.Properties.GetByName(nuget_module.PropertySymbolID) != "" {
+				symbolFiles = append(symbolFiles, f.File.Name)
+			}
+		}
+
+		ctx.Data["SymbolFiles"] = symbolFiles
 	}
 
 	var (
@@ -273,6 +292,17 @@ func ViewPackageVersion(ctx *context.Context) {
 	ctx.Data["LatestVersions"] = pvs
 	ctx.Data["TotalVersionCount"] = total
 
+	attestations, err := packages_model.GetAttestationsByVersionID(ctx, pd.Version.ID)
+	if err != nil {
+		ctx.ServerError("GetAttestationsByVersionID", err)
+		return
+	}
+	predicateTypes := make(container.Set[string])
+	for _, pa := range attestations {
+		predicateTypes.Add(pa.PredicateType)
+	}
+	ctx.Data["AttestationPredicateTypes"] = util.Sorted(predicateTypes.Values())
+
 	ctx.Data["CanWritePackages"] = ctx.Package.AccessMode >= perm.AccessModeWrite || ctx.IsUserSiteAdmin()
 
 	hasRepositoryAccess := false
@@ -381,6 +411,12 @@ func ListPackageVersions(ctx *context.Context) {
 		return
 	}
 
+	ctx.Data["SignedVersions"], err = packages_model.GetAttestedVersionIDs(ctx, pvs)
+	if err != nil {
+		ctx.ServerError("GetAttestedVersionIDs", err)
+		return
+	}
+
 	ctx.Data["Total"] = total
 
 	err = shared_user.LoadHeaderCount(ctx)
@@ -414,6 +450,8 @@ func PackageSettings(ctx *context.Context) {
 	})
 	ctx.Data["Repos"] = repos
 	ctx.Data["CanWritePackages"] = ctx.Package.AccessMode >= perm.AccessModeWrite || ctx.IsUserSiteAdmin()
+	ctx.Data["CanMakeImmutable"] = ctx.Package.AccessMode >= perm.AccessModeOwner || ctx.IsUserSiteAdmin()
+	ctx.Data["CanMakeMutable"] = ctx.IsUserSiteAdmin()
 
 	err := shared_user.LoadHeaderCount(ctx)
 	if err != nil {
@@ -461,11 +499,43 @@ func PackageSettingsPost(ctx *context.Context) {
 			ctx.Flash.Error(ctx.Tr("packages.settings.link.error"))
 		}
 
+		ctx.Redirect(ctx.Link)
+		return
+	case "immutable", "mutable":
+		immutable := form.Action == "immutable"
+		if immutable && ctx.Package.AccessMode < perm.AccessModeOwner && !ctx.IsUserSiteAdmin() ||
+			!immutable && !ctx.IsUserSiteAdmin() {
+			ctx.NotFound("PackageSettingsPost", nil)
+			return
+		}
+
+		if err := packages_service.SetPackageImmutable(ctx, ctx.Doer, pd.Package, immutable); err != nil {
+			log.Error("Error updating package immutability: %v", err)
+			ctx.Flash.Error(ctx.Tr("packages.settings.immutable.error"))
+		} else {
+			ctx.Flash.Success(ctx.Tr("packages.settings.immutable.success"))
+		}
+
+		ctx.Redirect(ctx.Link)
+		return
+	case "rebuild_metadata":
+		if err := maven_service.RebuildMetadata(ctx, ctx.Doer, pd.Package); err != nil {
+			log.Error("Error rebuilding package metadata: %v", err)
+			ctx.Flash.Error(ctx.Tr("packages.settings.rebuild_metadata.error"))
+		} else {
+			ctx.Flash.Success(ctx.Tr("packages.settings.rebuild_metadata.success"))
+		}
+
 		ctx.Redirect(ctx.Link)
 		return
 	case "delete":
 		err := packages_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor.Version)
 		if err != nil {
+			if errors.Is(err, util.ErrPermissionDenied) {
+				ctx.Flash.Error(ctx.Tr("packages.settings.delete.immutable"))
+				ctx.Redirect(ctx.Link)
+				return
+			}
 			log.Error("Error deleting package: %v", err)
 			ctx.Flash.Error(ctx.Tr("packages.settings.delete.error"))
 		} else {
@@ -117,3 +117,12 @@ func RegenerateChefKeyPair(ctx *context.Context) {
 		Filename:    ctx.Doer.Name + ".priv",
 	})
 }
+
+func SetRequireSignatures(ctx *context.Context) {
+	shared.SetRequireSignatures(ctx, ctx.Doer)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/user/settings/packages")
+}
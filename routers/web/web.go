@@ -23,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/modules/web/routing"
+	terraform_packages "code.gitea.io/gitea/routers/api/packages/terraform"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/routers/web/admin"
 	"code.gitea.io/gitea/routers/web/auth"
@@ -473,6 +474,7 @@ func registerRoutes(m *web.Router) {
 			ctx.Redirect(setting.AppSubURL + "/user/settings/account")
 		})
 		m.Get("/passkey-endpoints", passkeyEndpoints)
+		m.Get("/terraform.json", packagesEnabled, terraform_packages.ServiceDiscovery)
 		m.Methods("GET, HEAD", "/*", public.FileHandlerFunc())
 	}, optionsCorsHandler())
 
@@ -627,6 +629,7 @@ func registerRoutes(m *web.Router) {
 				m.Post("/rebuild", user_setting.RebuildCargoIndex)
 			})
 			m.Post("/chef/regenerate_keypair", user_setting.RegenerateChefKeyPair)
+			m.Post("/signatures", user_setting.SetRequireSignatures)
 		}, packagesEnabled)
 
 		m.Group("/actions", func() {
@@ -734,6 +737,8 @@ func registerRoutes(m *web.Router) {
 		m.Group("/repos", func() {
 			m.Get("", admin.Repos)
 			m.Combo("/unadopted").Get(admin.UnadoptedRepos).Post(admin.AdoptOrDeleteRepository)
+			m.Get("/maintenance", admin.RepoMaintenance)
+			m.Post("/maintenance/run", admin.RunRepoMaintenance)
 			m.Post("/delete", admin.DeleteRepo)
 		})
 
@@ -790,6 +795,10 @@ func registerRoutes(m *web.Router) {
 			m.Get("", admin.RedirectToDefaultSetting)
 			addSettingsRunnersRoutes()
 			addSettingsVariablesRoutes()
+			m.Group("/caches", func() {
+				m.Get("", admin.ActionsCaches)
+				m.Post("/delete", admin.DeleteActionsCaches)
+			})
 		})
 	}, adminReq, ctxDataSet("EnableOAuth2", setting.OAuth2.Enabled, "EnablePackages", setting.Packages.Enabled))
 	// ***** END: Admin *****
@@ -955,6 +964,7 @@ func registerRoutes(m *web.Router) {
 						m.Post("/initialize", org.InitializeCargoIndex)
 						m.Post("/rebuild", org.RebuildCargoIndex)
 					})
+					m.Post("/signatures", org.SetRequireSignatures)
 				}, packagesEnabled)
 
 				m.Group("/blocked_users", func() {
@@ -1102,6 +1112,7 @@ func registerRoutes(m *web.Router) {
 			m.Get("/pointers", repo_setting.LFSPointerFiles)
 			m.Post("/pointers/associate", repo_setting.LFSAutoAssociate)
 			m.Get("/find", repo_setting.LFSFileFind)
+			m.Combo("/migrate").Get(repo_setting.LFSMigrate).Post(repo_setting.LFSMigratePost)
 			m.Group("/locks", func() {
 				m.Get("/", repo_setting.LFSLocks)
 				m.Post("/", repo_setting.LFSLockFile)
@@ -1185,6 +1196,7 @@ func registerRoutes(m *web.Router) {
 				m.Get("/choose", context.RepoRef(), repo.NewIssueChooseTemplate)
 			})
 			m.Get("/search", repo.ListIssues)
+			m.Get("/similar", repo.SimilarIssues)
 		}, context.RepoMustNotBeArchived(), reqRepoIssueReader)
 
 		// FIXME: should use different URLs but mostly same logic for comments of issue and pull request.
@@ -1195,6 +1207,7 @@ func registerRoutes(m *web.Router) {
 				m.Post("/content", repo.UpdateIssueContent)
 				m.Post("/deadline", web.Bind(structs.EditDeadlineOption{}), repo.UpdateIssueDeadline)
 				m.Post("/watch", repo.IssueWatch)
+				m.Post("/vote", repo.IssueVote)
 				m.Post("/ref", repo.UpdateIssueRef)
 				m.Post("/pin", reqRepoAdmin, repo.IssuePinOrUnpin)
 				m.Post("/viewed-files", repo.UpdateViewedFiles)
@@ -1240,6 +1253,7 @@ func registerRoutes(m *web.Router) {
 			m.Post("", repo.UpdateCommentContent)
 			m.Post("/delete", repo.DeleteComment)
 			m.Post("/reactions/{action}", web.Bind(forms.ReactionForm{}), repo.ChangeCommentReaction)
+			m.Post("/resolution/reactions/{action}", web.Bind(forms.ReactionForm{}), repo.ChangeResolutionReaction)
 		}, context.RepoMustNotBeArchived())
 
 		m.Group("/labels", func() {
@@ -1383,6 +1397,12 @@ func registerRoutes(m *web.Router) {
 		m.Get("", actions.List)
 		m.Post("/disable", reqRepoAdmin, actions.DisableWorkflowFile)
 		m.Post("/enable", reqRepoAdmin, actions.EnableWorkflowFile)
+		m.Post("/run", reqRepoActionsWriter, context.RepoMustNotBeArchived(), actions.Run)
+		m.Group("/schedules/{id}", func() {
+			m.Post("/pause", reqRepoAdmin, actions.PauseSchedule)
+			m.Post("/resume", reqRepoAdmin, actions.ResumeSchedule)
+			m.Post("/run", reqRepoActionsWriter, actions.RunSchedule)
+		}, context.RepoMustNotBeArchived())
 
 		m.Group("/runs/{run}", func() {
 			m.Combo("").
@@ -1401,6 +1421,7 @@ func registerRoutes(m *web.Router) {
 			m.Get("/artifacts/{artifact_name}", actions.ArtifactsDownloadView)
 			m.Delete("/artifacts/{artifact_name}", actions.ArtifactsDeleteView)
 			m.Post("/rerun", reqRepoActionsWriter, actions.Rerun)
+			m.Post("/rerun-failed", reqRepoActionsWriter, actions.RerunFailed)
 		})
 		m.Group("/workflows/{workflow_name}", func() {
 			m.Get("/badge.svg", actions.GetWorkflowBadge)
@@ -1439,6 +1460,7 @@ func registerRoutes(m *web.Router) {
 			m.Get("", repo.RecentCommits)
 			m.Get("/data", repo.RecentCommitsData)
 		})
+		m.Get("/dependents", reqRepoCodeReader, repo.Dependents)
 	},
 		ignSignIn, context.RepoAssignment, context.RequireRepoReaderOr(unit.TypePullRequests, unit.TypeIssues, unit.TypeReleases),
 		context.RepoRef(), repo.MustBeNotEmpty,
@@ -1490,21 +1512,25 @@ func registerRoutes(m *web.Router) {
 			m.Group("/commits", func() {
 				m.Get("", context.RepoRef(), repo.SetWhitespaceBehavior, repo.GetPullDiffStats, repo.ViewPullCommits)
 				m.Get("/list", context.RepoRef(), repo.GetPullCommits)
-				m.Get("/{sha:[a-f0-9]{7,40}}", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.SetShowOutdatedComments, repo.ViewPullFilesForSingleCommit)
+				m.Get("/{sha:[a-f0-9]{7,64}}", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.SetShowOutdatedComments, repo.ViewPullFilesForSingleCommit)
 			})
 			m.Post("/merge", context.RepoMustNotBeArchived(), web.Bind(forms.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/cancel_auto_merge", context.RepoMustNotBeArchived(), repo.CancelAutoMergePullRequest)
+			m.Post("/remove_from_merge_queue", context.RepoMustNotBeArchived(), repo.RemoveFromMergeQueuePullRequest)
 			m.Post("/update", repo.UpdatePullRequest)
+			m.Combo("/conflicts", context.RepoMustNotBeArchived()).Get(repo.PullConflicts).
+				Post(web.Bind(forms.ResolvePullRequestConflictsForm{}), repo.ResolvePullConflicts)
 			m.Post("/set_allow_maintainer_edit", web.Bind(forms.UpdateAllowEditsForm{}), repo.SetAllowEdits)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
 			m.Group("/files", func() {
 				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.SetShowOutdatedComments, repo.ViewPullFilesForAllCommitsOfPr)
-				m.Get("/{sha:[a-f0-9]{7,40}}", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.SetShowOutdatedComments, repo.ViewPullFilesStartingFromCommit)
-				m.Get("/{shaFrom:[a-f0-9]{7,40}}..{shaTo:[a-f0-9]{7,40}}", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.SetShowOutdatedComments, repo.ViewPullFilesForRange)
+				m.Get("/{sha:[a-f0-9]{7,64}}", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.SetShowOutdatedComments, repo.ViewPullFilesStartingFromCommit)
+				m.Get("/{shaFrom:[a-f0-9]{7,64}}..{shaTo:[a-f0-9]{7,64}}", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.SetShowOutdatedComments, repo.ViewPullFilesForRange)
 				m.Group("/reviews", func() {
 					m.Get("/new_comment", repo.RenderNewCodeCommentForm)
 					m.Post("/comments", web.Bind(forms.CodeCommentForm{}), repo.SetShowOutdatedComments, repo.CreateCodeComment)
 					m.Post("/submit", web.Bind(forms.SubmitReviewForm{}), repo.SubmitReview)
+					m.Post("/suggestions", repo.ApplySuggestions)
 				}, context.RepoMustNotBeArchived())
 			})
 		}, repo.MustAllowPulls)
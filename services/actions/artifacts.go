@@ -0,0 +1,362 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// GetOwnerArtifactRetentionDays returns the number of days the artifacts of the repositories of an owner are kept at most,
+// the retention of the owner can't exceed the one of the instance
+func GetOwnerArtifactRetentionDays(ctx context.Context, ownerID int64) (int64, error) {
+	days := setting.Actions.ArtifactRetentionDays
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsArtifactRetentionDays)
+	if err != nil {
+		return 0, err
+	}
+	if ownerDays, _ := strconv.ParseInt(value, 10, 64); ownerDays > 0 && ownerDays < days {
+		days = ownerDays
+	}
+	return days, nil
+}
+
+// GetArtifactRetentionDays returns the number of days the artifacts of a repository are kept,
+// the retention of the repository can't exceed the one of its owner
+func GetArtifactRetentionDays(ctx context.Context, repo *repo_model.Repository) (int64, error) {
+	days, err := GetOwnerArtifactRetentionDays(ctx, repo.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if repo_model.IsErrUnitTypeNotExist(err) {
+		return days, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if repoDays := cfgUnit.ActionsConfig().ArtifactRetentionDays; repoDays > 0 && repoDays < days {
+		days = repoDays
+	}
+	return days, nil
+}
+
+// GetUploadArtifactRetentionDays returns the number of days an artifact being uploaded is kept,
+// the retention requested by the workflow can only be shorter than the one of the repository
+func GetUploadArtifactRetentionDays(ctx context.Context, repoID, requestedDays int64) (int64, error) {
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if err != nil {
+		return 0, err
+	}
+	days, err := GetArtifactRetentionDays(ctx, repo)
+	if err != nil {
+		return 0, err
+	}
+	if requestedDays > 0 && requestedDays < days {
+		days = requestedDays
+	}
+	return days, nil
+}
+
+// SetOwnerArtifactRetentionDays sets the number of days the artifacts of the repositories of an owner are kept, 0 to use the one of the instance.
+// The artifacts which are already kept longer expire earlier.
+func SetOwnerArtifactRetentionDays(ctx context.Context, ownerID, days int64) error {
+	if days < 0 || days > setting.Actions.ArtifactRetentionDays {
+		return util.NewInvalidArgumentErrorf("the retention must be between 1 and %d days", setting.Actions.ArtifactRetentionDays)
+	}
+	if days == 0 {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyActionsArtifactRetentionDays)
+	}
+	if err := user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyActionsArtifactRetentionDays, strconv.FormatInt(days, 10)); err != nil {
+		return err
+	}
+	return actions_model.LimitArtifactsRetention(ctx, 0, ownerID, days)
+}
+
+// SetRepoArtifactRetentionDays sets the number of days the artifacts of a repository are kept, 0 to use the one of its owner.
+// The artifacts which are already kept longer expire earlier.
+func SetRepoArtifactRetentionDays(ctx context.Context, repo *repo_model.Repository, days int64) error {
+	maxDays, err := GetOwnerArtifactRetentionDays(ctx, repo.OwnerID)
+	if err != nil {
+		return err
+	}
+	if days < 0 || days > maxDays {
+		return util.NewInvalidArgumentErrorf("the retention must be between 1 and %d days", maxDays)
+	}
+
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if repo_model.IsErrUnitTypeNotExist(err) {
+		return util.NewNotExistErrorf("actions are disabled in the repository")
+	} else if err != nil {
+		return err
+	}
+	cfg := cfgUnit.ActionsConfig()
+	cfg.ArtifactRetentionDays = days
+	if err := repo_model.UpdateRepoUnit(ctx, cfgUnit); err != nil {
+		return err
+	}
+	if days == 0 {
+		return nil
+	}
+	return actions_model.LimitArtifactsRetention(ctx, repo.ID, 0, days)
+}
+
+// ArtifactFile is a file inside an artifact
+type ArtifactFile struct {
+	Path string
+	Size int64
+}
+
+// GetRunArtifact returns the uploaded records of an artifact of a run, the backend v4 stores a single zip file per artifact
+// while the older ones store a record per file
+func GetRunArtifact(ctx context.Context, runID int64, name string) ([]*actions_model.ActionArtifact, error) {
+	artifacts, err := db.Find[actions_model.ActionArtifact](ctx, actions_model.FindArtifactsOptions{
+		RunID:        runID,
+		ArtifactName: name,
+		Status:       int(actions_model.ArtifactStatusUploadConfirmed),
+	})
+	if err != nil {
+		return nil, err
+	} else if len(artifacts) == 0 {
+		return nil, util.NewNotExistErrorf("artifact %q doesn't exist", name)
+	}
+	return artifacts, nil
+}
+
+// IsArtifactV4 returns whether the artifact has been uploaded by the backend v4, as a single zip file
+func IsArtifactV4(artifacts []*actions_model.ActionArtifact) bool {
+	return len(artifacts) == 1 && artifacts[0].ArtifactName+".zip" == artifacts[0].ArtifactPath && artifacts[0].ContentEncoding == "application/zip"
+}
+
+// objectReaderAt reads a storage object at an offset, for the objects which don't implement io.ReaderAt
+type objectReaderAt struct {
+	mu  sync.Mutex
+	obj storage.Object
+}
+
+func (r *objectReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.obj.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.obj, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// openArtifactZip opens the zip file of an artifact uploaded by the backend v4, the returned object has to be closed
+func openArtifactZip(artifact *actions_model.ActionArtifact) (*zip.Reader, storage.Object, error) {
+	obj, err := storage.ActionsArtifacts.Open(artifact.StoragePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	fi, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, nil, err
+	}
+	readerAt, ok := obj.(io.ReaderAt)
+	if !ok {
+		readerAt = &objectReaderAt{obj: obj}
+	}
+	zipReader, err := zip.NewReader(readerAt, fi.Size())
+	if err != nil {
+		obj.Close()
+		return nil, nil, err
+	}
+	return zipReader, obj, nil
+}
+
+// ListArtifactFiles lists the files inside an artifact
+func ListArtifactFiles(artifacts []*actions_model.ActionArtifact) ([]*ArtifactFile, error) {
+	if !IsArtifactV4(artifacts) {
+		files := make([]*ArtifactFile, 0, len(artifacts))
+		for _, artifact := range artifacts {
+			files = append(files, &ArtifactFile{Path: artifact.ArtifactPath, Size: artifact.FileSize})
+		}
+		return files, nil
+	}
+
+	zipReader, obj, err := openArtifactZip(artifacts[0])
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	files := make([]*ArtifactFile, 0, len(zipReader.File))
+	for _, f := range zipReader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, &ArtifactFile{Path: f.Name, Size: int64(f.UncompressedSize64)})
+	}
+	return files, nil
+}
+
+type artifactFileReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *artifactFileReader) Close() error {
+	var err error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if e := r.closers[i].Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// OpenArtifactFile opens a file inside an artifact, the entries of the zip files are streamed without extracting them
+func OpenArtifactFile(artifacts []*actions_model.ActionArtifact, filePath string) (io.ReadCloser, *ArtifactFile, error) {
+	if IsArtifactV4(artifacts) {
+		zipReader, obj, err := openArtifactZip(artifacts[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range zipReader.File {
+			if f.Name != filePath || f.FileInfo().IsDir() {
+				continue
+			}
+			r, err := f.Open()
+			if err != nil {
+				obj.Close()
+				return nil, nil, err
+			}
+			return &artifactFileReader{Reader: r, closers: []io.Closer{obj, r}}, &ArtifactFile{Path: f.Name, Size: int64(f.UncompressedSize64)}, nil
+		}
+		obj.Close()
+		return nil, nil, util.NewNotExistErrorf("file %q doesn't exist in the artifact", filePath)
+	}
+
+	for _, artifact := range artifacts {
+		if artifact.ArtifactPath != filePath {
+			continue
+		}
+		obj, err := storage.ActionsArtifacts.Open(artifact.StoragePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		file := &ArtifactFile{Path: artifact.ArtifactPath, Size: artifact.FileSize}
+		if artifact.ContentEncoding != "gzip" {
+			return obj, file, nil
+		}
+		r, err := gzip.NewReader(obj)
+		if err != nil {
+			obj.Close()
+			return nil, nil, err
+		}
+		return &artifactFileReader{Reader: r, closers: []io.Closer{obj, r}}, file, nil
+	}
+	return nil, nil, util.NewNotExistErrorf("file %q doesn't exist in the artifact", filePath)
+}
+
+// WriteArtifactZip zips the files of an artifact uploaded by the backends older than v4, which store them individually
+func WriteArtifactZip(w io.Writer, artifacts []*actions_model.ActionArtifact) error {
+	writer := zip.NewWriter(w)
+	for _, artifact := range artifacts {
+		if err := writeArtifactZipFile(writer, artifact); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+func writeArtifactZipFile(writer *zip.Writer, artifact *actions_model.ActionArtifact) error {
+	r, _, err := OpenArtifactFile([]*actions_model.ActionArtifact{artifact}, artifact.ArtifactPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := writer.Create(artifact.ArtifactPath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// DeleteArtifactFile deletes a file inside an artifact, the whole artifact is deleted with its last file.
+// The zip files of the backend v4 are rewritten without the file.
+func DeleteArtifactFile(ctx context.Context, artifacts []*actions_model.ActionArtifact, filePath string) error {
+	if !IsArtifactV4(artifacts) {
+		for _, artifact := range artifacts {
+			if artifact.ArtifactPath == filePath {
+				return actions_model.SetArtifactFileNeedDelete(ctx, artifact.ID)
+			}
+		}
+		return util.NewNotExistErrorf("file %q doesn't exist in the artifact", filePath)
+	}
+
+	artifact := artifacts[0]
+	zipReader, obj, err := openArtifactZip(artifact)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	tmp, err := os.CreateTemp("", "gitea-artifact-*.zip")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	found, remaining := false, 0
+	zipWriter := zip.NewWriter(tmp)
+	for _, f := range zipReader.File {
+		if f.Name == filePath && !f.FileInfo().IsDir() {
+			found = true
+			continue
+		}
+		if !f.FileInfo().IsDir() {
+			remaining++
+		}
+		if err := zipWriter.Copy(f); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return util.NewNotExistErrorf("file %q doesn't exist in the artifact", filePath)
+	}
+	if remaining == 0 {
+		return actions_model.SetArtifactNeedDelete(ctx, artifact.RunID, artifact.ArtifactName)
+	}
+	if err := zipWriter.Close(); err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := storage.ActionsArtifacts.Save(artifact.StoragePath, tmp, size); err != nil {
+		return err
+	}
+	artifact.FileSize = size
+	artifact.FileCompressedSize = size
+	return actions_model.UpdateArtifactByID(ctx, artifact.ID, artifact)
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ExpireRunner removes a runner at once, the tasks it's running fail
+func ExpireRunner(ctx context.Context, runner *actions_model.ActionRunner) error {
+	if err := stopTasks(ctx, actions_model.FindTaskOptions{
+		RunnerID: runner.ID,
+		Status:   actions_model.StatusRunning,
+	}); err != nil {
+		return err
+	}
+	return actions_model.DeleteRunner(ctx, runner.ID)
+}
+
+// runAutoscalerWebhook posts the jobs waiting for a runner of the instance by set of labels to the autoscaler webhook
+// each time they change, until the shutdown
+func runAutoscalerWebhook(ctx context.Context) {
+	client := &http.Client{
+		Timeout:   time.Minute,
+		Transport: &http.Transport{Proxy: proxy.Proxy()},
+	}
+	ticker := time.NewTicker(setting.Actions.AutoscalerWebhookInterval)
+	defer ticker.Stop()
+
+	var posted []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		queued, err := actions_model.GetQueuedJobs(ctx, 0, 0)
+		if err != nil {
+			log.Error("GetQueuedJobs: %v", err)
+			continue
+		}
+		body, err := json.Marshal(convert.ToActionQueuedJobs(queued))
+		if err != nil {
+			log.Error("Marshal the queued jobs: %v", err)
+			continue
+		}
+		if bytes.Equal(body, posted) {
+			continue
+		}
+		// the queued jobs are posted again at the next tick if it fails
+		if err := postAutoscalerWebhook(ctx, client, body); err != nil {
+			log.Warn("Unable to post the queued jobs to the autoscaler webhook: %v", err)
+			continue
+		}
+		posted = body
+	}
+}
+
+func postAutoscalerWebhook(ctx context.Context, client *http.Client, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, setting.Actions.AutoscalerWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gitea-Event", "actions_queued_jobs")
+	req.Header.Set("X-Gitea-Signature", signAutoscalerWebhook(body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// signAutoscalerWebhook returns the hex encoded HMAC SHA256 of the body with the secret of the webhook, empty without secret
+func signAutoscalerWebhook(body []byte) string {
+	if setting.Actions.AutoscalerWebhookSecret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(setting.Actions.AutoscalerWebhookSecret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func initAutoscalerWebhook() {
+	if setting.Actions.AutoscalerWebhookURL == "" {
+		return
+	}
+	go graceful.GetManager().RunWithShutdownContext(runAutoscalerWebhook)
+}
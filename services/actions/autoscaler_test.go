@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostAutoscalerWebhook(t *testing.T) {
+	body := []byte(`{"queued_jobs":[{"labels":["ubuntu-latest"],"count":1}],"total_count":1}`)
+
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	defer test.MockVariableValue(&setting.Actions.AutoscalerWebhookURL, server.URL)()
+	defer test.MockVariableValue(&setting.Actions.AutoscalerWebhookSecret, "secret")()
+
+	require.NoError(t, postAutoscalerWebhook(context.Background(), server.Client(), body))
+	assert.Equal(t, body, receivedBody)
+	assert.Equal(t, "actions_queued_jobs", received.Header.Get("X-Gitea-Event"))
+	assert.Equal(t, "f2766287921d0fed126d790cdd37ec47b47ad4eb7fadaf608fb8ed1280c36990", received.Header.Get("X-Gitea-Signature"))
+
+	setting.Actions.AutoscalerWebhookSecret = ""
+	assert.Empty(t, signAutoscalerWebhook(body))
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	assert.Error(t, postAutoscalerWebhook(context.Background(), server.Client(), body))
+}
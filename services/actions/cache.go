@@ -0,0 +1,216 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// incompleteCacheTimeout is the time after which a cache which content hasn't been committed is dropped
+const incompleteCacheTimeout = 24 * time.Hour
+
+// ErrCacheTooLarge is returned when the content of a cache is larger than the cache quota of a repository
+var ErrCacheTooLarge = errors.New("cache is larger than the quota")
+
+// CacheScopesOfRun returns the scope the caches saved by the run are stored in, which is its ref,
+// and the scopes it can restore caches from: its ref, then the base branch of its pull request and the default branch.
+// So the caches of a branch are shared with the pull requests and the branches based on it, but not the other way round.
+func CacheScopesOfRun(ctx context.Context, run *actions_model.ActionRun) (string, []string, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return "", nil, err
+	}
+
+	scopes := []string{run.Ref}
+	addScope := func(scope string) {
+		for _, s := range scopes {
+			if s == scope {
+				return
+			}
+		}
+		scopes = append(scopes, scope)
+	}
+	if payload, err := run.GetPullRequestEventPayload(); err == nil && payload.PullRequest != nil && payload.PullRequest.Base != nil {
+		addScope(git.BranchPrefix + payload.PullRequest.Base.Ref)
+	}
+	addScope(git.BranchPrefix + run.Repo.DefaultBranch)
+	return run.Ref, scopes, nil
+}
+
+type cacheChunk struct {
+	Start int64
+	Path  string
+}
+
+// CommitCacheContent merges the chunks uploaded for the cache into its content and marks it as complete.
+// The chunks have to cover the content without gap, the cache is dropped if they don't or if it's larger than the quota.
+func CommitCacheContent(ctx context.Context, cache *actions_model.ActionCache) error {
+	var chunks []*cacheChunk
+	if err := storage.ActionsCache.IterateObjects(cache.ChunkStorageDir(), func(p string, _ storage.Object) error {
+		chunk := &cacheChunk{Path: cache.ChunkStorageDir() + "/" + path.Base(p)}
+		if _, err := fmt.Sscanf(path.Base(p), "%d.chunk", &chunk.Start); err != nil {
+			return fmt.Errorf("parse chunk name %s: %w", p, err)
+		}
+		chunks = append(chunks, chunk)
+		return nil
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].Start < chunks[j].Start })
+
+	var size int64
+	readers := make([]io.Reader, 0, len(chunks))
+	for _, chunk := range chunks {
+		if chunk.Start != size {
+			return errors.Join(fmt.Errorf("cache %d: missing content at %d", cache.ID, size), DeleteCache(ctx, cache))
+		}
+		obj, err := storage.ActionsCache.Open(chunk.Path)
+		if err != nil {
+			return err
+		}
+		defer obj.Close()
+		info, err := obj.Stat()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		readers = append(readers, obj)
+	}
+	if size > setting.Actions.CacheRepoQuota {
+		return errors.Join(ErrCacheTooLarge, DeleteCache(ctx, cache))
+	}
+
+	if _, err := storage.ActionsCache.Save(cache.StoragePath(), io.MultiReader(readers...), size); err != nil {
+		return err
+	}
+	if err := actions_model.CommitCache(ctx, cache, size); err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if err := storage.ActionsCache.Delete(chunk.Path); err != nil {
+			log.Error("Cannot delete chunk %s of cache %d: %v", chunk.Path, cache.ID, err)
+		}
+	}
+	return nil
+}
+
+// EvictCaches drops the caches which haven't been used for the retention days and the incomplete ones which upload has been abandoned,
+// then the least recently used caches of the repositories over the cache quota
+func EvictCaches(ctx context.Context) error {
+	now := time.Now()
+
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCacheOptions{
+		UsedBefore: timeutil.TimeStamp(now.Add(-time.Duration(setting.Actions.CacheRetentionDays) * 24 * time.Hour).Unix()),
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Found %d unused caches", len(caches))
+	if err := deleteCaches(ctx, caches); err != nil {
+		return err
+	}
+
+	caches, err = db.Find[actions_model.ActionCache](ctx, actions_model.FindCacheOptions{
+		Complete:      optional.Some(false),
+		CreatedBefore: timeutil.TimeStamp(now.Add(-incompleteCacheTimeout).Unix()),
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Found %d abandoned caches", len(caches))
+	if err := deleteCaches(ctx, caches); err != nil {
+		return err
+	}
+
+	repoIDs, err := actions_model.GetRepoIDsOverCacheQuota(ctx, setting.Actions.CacheRepoQuota)
+	if err != nil {
+		return err
+	}
+	for _, repoID := range repoIDs {
+		if err := EvictRepoCaches(ctx, repoID, setting.Actions.CacheRepoQuota); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EvictRepoCaches drops the least recently used caches of the repository until the size of its caches fits in the quota
+func EvictRepoCaches(ctx context.Context, repoID, quota int64) error {
+	size, err := actions_model.GetRepoCacheSize(ctx, repoID)
+	if err != nil || size <= quota {
+		return err
+	}
+
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCacheOptions{RepoID: repoID})
+	if err != nil {
+		return err
+	}
+	evicted := make([]*actions_model.ActionCache, 0, len(caches))
+	for _, cache := range caches {
+		if size <= quota {
+			break
+		}
+		evicted = append(evicted, cache)
+		size -= cache.Size
+	}
+	log.Info("Evicting %d caches of repository %d over the quota", len(evicted), repoID)
+	return deleteCaches(ctx, evicted)
+}
+
+// DeleteRepoCaches drops all the caches of the repository
+func DeleteRepoCaches(ctx context.Context, repoID int64) error {
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCacheOptions{RepoID: repoID})
+	if err != nil {
+		return err
+	}
+	return deleteCaches(ctx, caches)
+}
+
+func deleteCaches(ctx context.Context, caches []*actions_model.ActionCache) error {
+	for _, cache := range caches {
+		if err := DeleteCache(ctx, cache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteCache drops the cache and its content
+func DeleteCache(ctx context.Context, cache *actions_model.ActionCache) error {
+	if err := actions_model.DeleteCacheByID(ctx, cache.ID); err != nil {
+		return err
+	}
+	RemoveCacheContent(cache)
+	return nil
+}
+
+// RemoveCacheContent removes the content of the cache from the storage, including the chunks of an upload in progress
+func RemoveCacheContent(cache *actions_model.ActionCache) {
+	if cache.Complete {
+		if err := storage.ActionsCache.Delete(cache.StoragePath()); err != nil {
+			log.Error("Cannot delete cache %d: %v", cache.ID, err)
+		}
+		return
+	}
+	if err := storage.ActionsCache.IterateObjects(cache.ChunkStorageDir(), func(p string, _ storage.Object) error {
+		return storage.ActionsCache.Delete(cache.ChunkStorageDir() + "/" + path.Base(p))
+	}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Error("Cannot delete the chunks of cache %d: %v", cache.ID, err)
+	}
+}
@@ -65,7 +65,7 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 			return fmt.Errorf("head of pull request is missing in event payload")
 		}
 		sha = payload.PullRequest.Head.Sha
-	case webhook_module.HookEventRelease:
+	case webhook_module.HookEventRelease, webhook_module.HookEventWorkflowDispatch:
 		event = string(run.Event)
 		sha = run.CommitSHA
 	default:
@@ -112,6 +112,10 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 	case actions_model.StatusBlocked:
 		description = "Blocked by required conditions"
 	}
+	// the reruns update the status of the same context, the attempt tells them apart
+	if run.Attempt > 1 {
+		description = fmt.Sprintf("%s (attempt %d)", description, run.Attempt)
+	}
 
 	index, err := getIndexOfJob(ctx, job)
 	if err != nil {
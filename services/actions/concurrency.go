@@ -0,0 +1,198 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// concurrencyWorkflow is the part of a workflow defining its concurrency and the concurrency of its jobs,
+// which isn't kept by the job parser
+type concurrencyWorkflow struct {
+	Concurrency yaml.Node `yaml:"concurrency"`
+	Jobs        map[string]struct {
+		Concurrency yaml.Node `yaml:"concurrency"`
+	} `yaml:"jobs"`
+}
+
+// evaluateConcurrencies evaluates the `concurrency` of the workflow of the run and of its jobs.
+// The concurrency of the workflow is set to the run, the ones of the jobs are returned by the index of the jobs.
+// The run is expected to have its repository and its trigger user loaded.
+func evaluateConcurrencies(run *actions_model.ActionRun, content []byte, jobs []*jobparser.SingleWorkflow, vars map[string]string) ([]*actions_model.Concurrency, error) {
+	var workflow concurrencyWorkflow
+	if err := yaml.Unmarshal(content, &workflow); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+
+	gitCtx := newGitContextOfRun(run)
+	results := make(map[string]*jobparser.JobResult, len(jobs)+1)
+	for _, v := range jobs {
+		id, job := v.Job()
+		results[id] = &jobparser.JobResult{Needs: job.Needs()}
+	}
+
+	if !workflow.Concurrency.IsZero() {
+		// the workflow is evaluated as a job without needs nor matrix
+		results[""] = &jobparser.JobResult{}
+		evaluator := jobparser.NewExpressionEvaluator(jobparser.NewInterpeter("", &model.Job{}, nil, gitCtx, results, vars))
+		concurrency, err := evaluateConcurrency(workflow.Concurrency, evaluator)
+		if err != nil {
+			return nil, fmt.Errorf("evaluate the concurrency of the workflow: %w", err)
+		}
+		if concurrency != nil {
+			run.ConcurrencyGroup = concurrency.Group
+			run.ConcurrencyCancel = concurrency.CancelInProgress
+		}
+	}
+
+	concurrencies := make([]*actions_model.Concurrency, len(jobs))
+	for i, v := range jobs {
+		id, job := v.Job()
+		node := workflow.Jobs[id].Concurrency
+		if node.IsZero() {
+			continue
+		}
+		matrix, err := actions_module.GetJobMatrix(job)
+		if err != nil {
+			return nil, fmt.Errorf("decode the matrix of job %s: %w", id, err)
+		}
+
+		evaluator := jobparser.NewExpressionEvaluator(jobparser.NewInterpeter(id, &model.Job{}, matrix, gitCtx, results, vars))
+		concurrency, err := evaluateConcurrency(node, evaluator)
+		if err != nil {
+			return nil, fmt.Errorf("evaluate the concurrency of job %s: %w", id, err)
+		}
+		concurrencies[i] = concurrency
+	}
+	return concurrencies, nil
+}
+
+// evaluateConcurrency evaluates a `concurrency`, which is either a group or a mapping of a group and cancel-in-progress.
+// It returns nil if the group is empty.
+func evaluateConcurrency(node yaml.Node, evaluator *jobparser.ExpressionEvaluator) (*actions_model.Concurrency, error) {
+	if err := evaluator.EvaluateYamlNode(&node); err != nil {
+		return nil, err
+	}
+
+	concurrency := &actions_model.Concurrency{}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if err := node.Decode(&concurrency.Group); err != nil {
+			return nil, err
+		}
+	case yaml.MappingNode:
+		var raw struct {
+			Group            string `yaml:"group"`
+			CancelInProgress bool   `yaml:"cancel-in-progress"`
+		}
+		if err := node.Decode(&raw); err != nil {
+			return nil, err
+		}
+		concurrency.Group = raw.Group
+		concurrency.CancelInProgress = raw.CancelInProgress
+	default:
+		return nil, fmt.Errorf("invalid concurrency: %s", node.Value)
+	}
+
+	concurrency.Group, _ = util.SplitStringAtByteN(strings.TrimSpace(concurrency.Group), 255)
+	if concurrency.Group == "" {
+		return nil, nil
+	}
+	return concurrency, nil
+}
+
+// newGitContextOfRun returns the `github` context of the expressions evaluated before a run is inserted,
+// so the run_id and the run_number aren't known yet
+func newGitContextOfRun(run *actions_model.ActionRun) *model.GithubContext {
+	event := map[string]any{}
+	_ = json.Unmarshal([]byte(run.EventPayload), &event)
+
+	baseRef := ""
+	headRef := ""
+	ref := run.Ref
+	sha := run.CommitSHA
+	if pullPayload, err := run.GetPullRequestEventPayload(); err == nil && pullPayload.PullRequest != nil && pullPayload.PullRequest.Base != nil && pullPayload.PullRequest.Head != nil {
+		baseRef = pullPayload.PullRequest.Base.Ref
+		headRef = pullPayload.PullRequest.Head.Ref
+
+		// same as the context of the tasks, the ref and the sha of pull_request_target are the ones of the base
+		if run.TriggerEvent == actions_module.GithubEventPullRequestTarget {
+			ref = git.BranchPrefix + pullPayload.PullRequest.Base.Name
+			sha = pullPayload.PullRequest.Base.Sha
+		}
+	}
+	refName := git.RefName(ref)
+
+	gitCtx := &model.GithubContext{
+		Event:     event,
+		EventName: run.TriggerEvent,
+		Workflow:  run.WorkflowID,
+		Ref:       ref,
+		RefName:   refName.ShortName(),
+		RefType:   refName.RefType(),
+		Sha:       sha,
+		HeadRef:   headRef,
+		BaseRef:   baseRef,
+		ServerURL: setting.AppURL,
+		APIURL:    setting.AppURL + "api/v1",
+	}
+	if run.Repo != nil {
+		gitCtx.Repository = run.Repo.OwnerName + "/" + run.Repo.Name
+		gitCtx.RepositoryOwner = run.Repo.OwnerName
+	}
+	if run.TriggerUser != nil {
+		gitCtx.Actor = run.TriggerUser.Name
+	}
+	return gitCtx
+}
+
+// EmitConcurrencyGroupsOfRun checks the runs queued in the concurrency groups the run or its jobs are part of,
+// once they are done, so the next of them can start
+func EmitConcurrencyGroupsOfRun(ctx context.Context, runID int64) error {
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	var runGroups, jobGroups []string
+	if run.ConcurrencyGroup != "" && run.Status.IsDone() {
+		runGroups = append(runGroups, run.ConcurrencyGroup)
+	}
+	for _, job := range jobs {
+		if job.ConcurrencyGroup != "" && job.Status.IsDone() {
+			jobGroups = append(jobGroups, job.ConcurrencyGroup)
+		}
+	}
+
+	runIDs, err := actions_model.FindQueuedRunIDsOfConcurrencyGroups(ctx, run.RepoID, runGroups, jobGroups)
+	if err != nil {
+		return err
+	}
+	for _, id := range runIDs {
+		if id == run.ID {
+			continue
+		}
+		if err := EmitJobsIfReady(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evaluateConcurrencies(t *testing.T) {
+	content := []byte(`
+name: deploy
+on: push
+concurrency:
+  group: ${{ github.workflow }}-${{ github.ref }}
+  cancel-in-progress: ${{ github.ref != 'refs/heads/main' }}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, windows]
+    concurrency: build-${{ matrix.os }}-${{ vars.SUFFIX }}
+    steps:
+      - run: echo build
+  deploy:
+    needs: build
+    runs-on: ubuntu-latest
+    concurrency:
+      group: deploy-${{ github.repository }}
+    steps:
+      - run: echo deploy
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`)
+	vars := map[string]string{"SUFFIX": "v1"}
+	jobs, err := jobparser.Parse(content, jobparser.WithVars(vars))
+	require.NoError(t, err)
+
+	run := &actions_model.ActionRun{
+		WorkflowID:   "deploy.yaml",
+		Ref:          "refs/heads/feature",
+		TriggerEvent: "push",
+		Repo:         &repo_model.Repository{OwnerName: "user2", Name: "repo1"},
+		TriggerUser:  &user_model.User{Name: "user2"},
+	}
+	concurrencies, err := evaluateConcurrencies(run, content, jobs, vars)
+	require.NoError(t, err)
+	assert.Equal(t, "deploy.yaml-refs/heads/feature", run.ConcurrencyGroup)
+	assert.True(t, run.ConcurrencyCancel)
+
+	groups := make(map[string]*actions_model.Concurrency, len(jobs))
+	for i, v := range jobs {
+		_, job := v.Job()
+		groups[job.Name] = concurrencies[i]
+	}
+	assert.Equal(t, &actions_model.Concurrency{Group: "build-linux-v1"}, groups["build (linux)"])
+	assert.Equal(t, &actions_model.Concurrency{Group: "build-windows-v1"}, groups["build (windows)"])
+	assert.Equal(t, &actions_model.Concurrency{Group: "deploy-user2/repo1"}, groups["deploy"])
+	assert.Nil(t, groups["test"])
+
+	run.Ref = "refs/heads/main"
+	_, err = evaluateConcurrencies(run, content, jobs, vars)
+	require.NoError(t, err)
+	assert.Equal(t, "deploy.yaml-refs/heads/main", run.ConcurrencyGroup)
+	assert.False(t, run.ConcurrencyCancel)
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"sort"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	repo_model "code.gitea.io/gitea/models/repo"
+	secret_model "code.gitea.io/gitea/models/secret"
+)
+
+// EffectiveVariable is a variable or a secret a workflow of a repository gets, with the scope it's defined at
+type EffectiveVariable struct {
+	Name string
+	// Data is the value of a variable, it's always empty for a secret
+	Data  string
+	Scope actions_model.VariableScope
+	// Overrides are the scopes of the variables or the secrets of the same name overridden by this one
+	Overrides []actions_model.VariableScope
+}
+
+// ResolveEffectiveVariables returns the variables the workflows of the repository get, or the jobs of the environment if it isn't empty
+func ResolveEffectiveVariables(ctx context.Context, repo *repo_model.Repository, environment string) ([]*EffectiveVariable, error) {
+	vars, err := actions_model.FindVariablesOfScopes(ctx, repo.OwnerID, repo.ID, environment)
+	if err != nil {
+		return nil, err
+	}
+	scoped := make([]*EffectiveVariable, 0, len(vars))
+	for _, v := range vars {
+		scoped = append(scoped, &EffectiveVariable{Name: v.Name, Data: v.Data, Scope: v.Scope()})
+	}
+	return mergeScopedVariables(scoped), nil
+}
+
+// ResolveEffectiveSecrets returns the secrets the workflows of the repository get, or the jobs of the environment if it isn't empty.
+// The values of the secrets aren't returned.
+func ResolveEffectiveSecrets(ctx context.Context, repo *repo_model.Repository, environment string) ([]*EffectiveVariable, error) {
+	secrets, err := secret_model.FindSecretsOfScopes(ctx, repo.OwnerID, repo.ID, environment)
+	if err != nil {
+		return nil, err
+	}
+	scoped := make([]*EffectiveVariable, 0, len(secrets))
+	for _, s := range secrets {
+		scoped = append(scoped, &EffectiveVariable{Name: s.Name, Scope: s.Scope()})
+	}
+	return mergeScopedVariables(scoped), nil
+}
+
+// mergeScopedVariables keeps the last variable of each name, the variables are expected in their order of precedence
+func mergeScopedVariables(scoped []*EffectiveVariable) []*EffectiveVariable {
+	byName := make(map[string]*EffectiveVariable, len(scoped))
+	for _, v := range scoped {
+		if previous, ok := byName[v.Name]; ok {
+			v.Overrides = append(previous.Overrides, previous.Scope)
+		}
+		byName[v.Name] = v
+	}
+
+	effective := make([]*EffectiveVariable, 0, len(byName))
+	for _, v := range byName {
+		effective = append(effective, v)
+	}
+	sort.Slice(effective, func(i, j int) bool {
+		return effective[i].Name < effective[j].Name
+	})
+	return effective
+}
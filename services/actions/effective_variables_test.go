@@ -0,0 +1,33 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeScopedVariables(t *testing.T) {
+	effective := mergeScopedVariables([]*EffectiveVariable{
+		{Name: "B", Data: "instance", Scope: actions_model.VariableScopeInstance},
+		{Name: "A", Data: "instance", Scope: actions_model.VariableScopeInstance},
+		{Name: "A", Data: "owner", Scope: actions_model.VariableScopeOwner},
+		{Name: "C", Data: "repository", Scope: actions_model.VariableScopeRepository},
+		{Name: "A", Data: "environment", Scope: actions_model.VariableScopeEnvironment},
+	})
+
+	assert.Equal(t, []*EffectiveVariable{
+		{
+			Name:      "A",
+			Data:      "environment",
+			Scope:     actions_model.VariableScopeEnvironment,
+			Overrides: []actions_model.VariableScope{actions_model.VariableScopeInstance, actions_model.VariableScopeOwner},
+		},
+		{Name: "B", Data: "instance", Scope: actions_model.VariableScopeInstance},
+		{Name: "C", Data: "repository", Scope: actions_model.VariableScopeRepository},
+	}, effective)
+}
@@ -23,4 +23,6 @@ func Init() {
 	go graceful.GetManager().RunWithCancel(jobEmitterQueue)
 
 	notify_service.RegisterNotifier(NewNotifier())
+
+	initAutoscalerWebhook()
 }
@@ -49,6 +49,10 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 	if err != nil {
 		return err
 	}
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
 		idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
 		for _, job := range jobs {
@@ -58,6 +62,17 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 		updates := newJobStatusResolver(jobs).Resolve()
 		for _, job := range jobs {
 			if status, ok := updates[job.ID]; ok {
+				if status == actions_model.StatusWaiting {
+					if run.NeedApproval {
+						continue
+					}
+					// the job stays queued until the earlier runs or jobs of its concurrency groups are done
+					if busy, err := actions_model.IsConcurrencyGroupBusy(ctx, run, job); err != nil {
+						return err
+					} else if busy {
+						continue
+					}
+				}
 				job.Status = status
 				if n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": actions_model.StatusBlocked}, "status"); err != nil {
 					return err
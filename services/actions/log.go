@@ -0,0 +1,171 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	actions_module "code.gitea.io/gitea/modules/actions"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+// JobLogStep is a step of the logs of a job, its index counts the steps setting up and completing the job
+type JobLogStep struct {
+	Index int
+	*actions_model.ActionTaskStep
+}
+
+// JobLogLine is a line of the logs of a job
+type JobLogLine struct {
+	Step  int
+	Index int64 // the position of the line in the logs of the task, starting at 0
+	Row   *runnerv1.LogRow
+}
+
+// JobLogFollower reads the logs of a job while its runner writes them
+type JobLogFollower struct {
+	JobID int64
+	// TaskID is the task whose logs are read, the logs of the new task are read from the start when the job is rerun
+	TaskID int64
+	// Cursor is the number of lines of the logs of the task already read
+	Cursor int64
+
+	stepStatus map[int]actions_model.Status
+}
+
+// Read returns the job, the steps whose status changed and the lines written since the last read
+func (f *JobLogFollower) Read(ctx context.Context) (*actions_model.ActionRunJob, []*JobLogStep, []*JobLogLine, error) {
+	job, err := actions_model.GetRunJobByID(ctx, f.JobID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if job.TaskID == 0 {
+		return job, nil, nil, nil
+	}
+	if job.TaskID != f.TaskID {
+		f.TaskID = job.TaskID
+		f.Cursor = 0
+		f.stepStatus = nil
+	}
+	if f.stepStatus == nil {
+		f.stepStatus = make(map[int]actions_model.Status)
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if task.LogExpired {
+		return job, nil, nil, nil
+	}
+	if task.Steps, err = actions_model.GetTaskStepsByTaskID(ctx, task.ID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var steps []*JobLogStep
+	var lines []*JobLogLine
+	for i, step := range actions_module.FullSteps(task) {
+		if status, ok := f.stepStatus[i]; !ok || status != step.Status {
+			f.stepStatus[i] = step.Status
+			steps = append(steps, &JobLogStep{Index: i, ActionTaskStep: step})
+		}
+
+		// the lines of a step can be read before the step data is updated, they are kept in the step they were read in
+		start := max(f.Cursor, step.LogIndex)
+		end := min(step.LogIndex+step.LogLength, int64(len(task.LogIndexes)))
+		if start >= end {
+			continue
+		}
+		rows, err := actions_module.ReadLogs(ctx, task.LogInStorage, task.LogFilename, task.LogIndexes[start], end-start)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for j, row := range rows {
+			lines = append(lines, &JobLogLine{Step: i, Index: start + int64(j), Row: row})
+		}
+		f.Cursor = start + int64(len(rows))
+	}
+	return job, steps, lines, nil
+}
+
+// WriteRunLogsZip zips the logs of the started jobs of a run, with a file of the whole logs of each job
+// and a directory of the logs of its steps
+func WriteRunLogsZip(ctx context.Context, w io.Writer, run *actions_model.ActionRun) error {
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return err
+	}
+
+	writer := zip.NewWriter(w)
+	for i, job := range jobs {
+		if job.TaskID == 0 {
+			continue
+		}
+		task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+		if err != nil {
+			return err
+		}
+		if task.LogExpired {
+			continue
+		}
+		if task.Steps, err = actions_model.GetTaskStepsByTaskID(ctx, task.ID); err != nil {
+			return err
+		}
+		if err := writeJobLogsZipFiles(ctx, writer, fmt.Sprintf("%d_%s", i+1, logFileName(job.Name)), task); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+func writeJobLogsZipFiles(ctx context.Context, writer *zip.Writer, name string, task *actions_model.ActionTask) error {
+	f, err := actions_module.OpenLogs(ctx, task.LogInStorage, task.LogFilename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// the logs may be written after the task has been loaded, only its known size is read
+	w, err := writer.Create(name + ".txt")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, io.LimitReader(f, task.LogSize)); err != nil {
+		return err
+	}
+
+	for i, step := range actions_module.FullSteps(task) {
+		if step.LogLength == 0 || step.LogIndex >= int64(len(task.LogIndexes)) {
+			continue
+		}
+		start := task.LogIndexes[step.LogIndex]
+		end := task.LogSize
+		if next := step.LogIndex + step.LogLength; next < int64(len(task.LogIndexes)) {
+			end = task.LogIndexes[next]
+		}
+		if _, err := f.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		w, err := writer.Create(fmt.Sprintf("%s/%d_%s.txt", name, i+1, logFileName(step.Name)))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, io.LimitReader(f, end-start)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var logFileNameReplacer = strings.NewReplacer("/", "_", "\\", "_")
+
+func logFileName(name string) string {
+	return logFileNameReplacer.Replace(name)
+}
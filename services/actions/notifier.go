@@ -520,6 +520,10 @@ func (n *actionsNotifier) PushCommits(ctx context.Context, pusher *user_model.Us
 		log.Trace("new commitID is empty")
 		return
 	}
+	if opts.SkipCI {
+		log.Debug("repo %s with commit %s: skipped run because the push skips CI", repo.RepoPath(), opts.NewCommitID)
+		return
+	}
 
 	ctx = withMethod(ctx, "PushCommits")
 
@@ -324,6 +324,18 @@ func handleWorkflows(
 			continue
 		}
 
+		// the run fails at once if it uses actions the policies don't allow
+		if run.FailureAnnotations, err = CheckActionsPolicies(ctx, run.OwnerID, jobs); err != nil {
+			log.Error("CheckActionsPolicies: %v", err)
+			continue
+		}
+
+		concurrencies, err := evaluateConcurrencies(run, dwf.Content, jobs, vars)
+		if err != nil {
+			log.Error("evaluateConcurrencies: %v", err)
+			continue
+		}
+
 		// cancel running jobs if the event is push or pull_request_sync
 		if run.Event == webhook_module.HookEventPush ||
 			run.Event == webhook_module.HookEventPullRequestSync {
@@ -338,7 +350,7 @@ func handleWorkflows(
 			}
 		}
 
-		if err := actions_model.InsertRun(ctx, run, jobs); err != nil {
+		if err := actions_model.InsertRun(ctx, run, jobs, concurrencies); err != nil {
 			log.Error("InsertRun: %v", err)
 			continue
 		}
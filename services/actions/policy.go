@@ -0,0 +1,121 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"gopkg.in/yaml.v3"
+)
+
+// parseActionReference returns the action or the reusable workflow of a `uses`, or nil if it's in the repository of the workflow.
+// The actions without a URL are on the default actions URL, their name doesn't include its host.
+func parseActionReference(uses string) *actions_model.ActionReference {
+	if strings.HasPrefix(uses, "./") {
+		return nil
+	}
+	if strings.HasPrefix(uses, "docker://") {
+		name, digest, _ := strings.Cut(uses, "@")
+		return &actions_model.ActionReference{Name: name, Ref: digest}
+	}
+
+	name, ref, _ := strings.Cut(uses, "@")
+	location := name
+	if u, err := url.Parse(name); err != nil || u.Scheme == "" || u.Host == "" {
+		location = setting.Actions.DefaultActionsURL.URL() + "/" + name
+	}
+	location = hostAndPath(location)
+
+	action := &actions_model.ActionReference{Name: location, Ref: ref}
+	if base := hostAndPath(setting.Actions.DefaultActionsURL.URL()) + "/"; strings.HasPrefix(location, base) {
+		action.Name = strings.TrimPrefix(location, base)
+	}
+	action.Local = strings.HasPrefix(location, hostAndPath(setting.AppURL)+"/")
+	return action
+}
+
+// hostAndPath returns the URL without its scheme and its trailing slash
+func hostAndPath(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return strings.TrimSuffix(s, "/")
+	}
+	return u.Host + strings.TrimSuffix(u.Path, "/")
+}
+
+// CheckActionsPolicies returns an annotation for each action used by the jobs which the policy of the instance,
+// or the one of the owner of the repository, doesn't allow
+func CheckActionsPolicies(ctx context.Context, ownerID int64, jobs []*jobparser.SingleWorkflow) ([]string, error) {
+	type scopedPolicy struct {
+		scope  string
+		policy *actions_model.ActionPolicy
+	}
+	instancePolicy, err := actions_model.GetActionPolicy(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	policies := []scopedPolicy{{"the instance", instancePolicy}}
+	if ownerID != 0 {
+		ownerPolicy, err := actions_model.GetActionPolicy(ctx, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, scopedPolicy{"the owner of the repository", ownerPolicy})
+	}
+
+	var annotations []string
+	// the jobs expanded from a matrix share their steps
+	added := make(container.Set[string])
+	check := func(user, uses string) {
+		action := parseActionReference(uses)
+		if action == nil {
+			return
+		}
+		for _, p := range policies {
+			if reason := p.policy.Check(action); reason != "" {
+				annotation := fmt.Sprintf("%s uses %q, which the actions policy of %s doesn't allow: %s", user, uses, p.scope, reason)
+				if added.Add(annotation) {
+					annotations = append(annotations, annotation)
+				}
+				return
+			}
+		}
+	}
+	for _, workflow := range jobs {
+		id, job := workflow.Job()
+		if job == nil {
+			continue
+		}
+		if job.Uses != "" {
+			check(fmt.Sprintf("the job %q", id), job.Uses)
+		}
+		for _, step := range job.Steps {
+			if step.Uses != "" {
+				check(fmt.Sprintf("the step %q of the job %q", step.String(), id), step.Uses)
+			}
+		}
+	}
+	return annotations, nil
+}
+
+// checkRunJobsActionsPolicies checks the actions used by the jobs of a run against the current policies
+func checkRunJobsActionsPolicies(ctx context.Context, ownerID int64, runJobs []*actions_model.ActionRunJob) ([]string, error) {
+	jobs := make([]*jobparser.SingleWorkflow, 0, len(runJobs))
+	for _, runJob := range runJobs {
+		workflow := &jobparser.SingleWorkflow{}
+		if err := yaml.Unmarshal(runJob.WorkflowPayload, workflow); err != nil {
+			return nil, fmt.Errorf("unmarshal the workflow of the job %d: %w", runJob.ID, err)
+		}
+		jobs = append(jobs, workflow)
+	}
+	return CheckActionsPolicies(ctx, ownerID, jobs)
+}
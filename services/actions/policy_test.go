@@ -0,0 +1,31 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseActionReference(t *testing.T) {
+	defer test.MockVariableValue(&setting.AppURL, "https://gitea.example.com/")()
+	defer test.MockVariableValue(&setting.Actions.DefaultActionsURL, "github")()
+
+	assert.Nil(t, parseActionReference("./.gitea/actions/build"))
+	assert.Equal(t, &actions_model.ActionReference{Name: "actions/checkout", Ref: "v4"}, parseActionReference("actions/checkout@v4"))
+	assert.Equal(t, &actions_model.ActionReference{Name: "actions/checkout", Ref: "v4"}, parseActionReference("https://github.com/actions/checkout@v4"))
+	assert.Equal(t, &actions_model.ActionReference{Name: "gitea.com/actions/go-hashfiles", Ref: "v0.0.1"}, parseActionReference("https://gitea.com/actions/go-hashfiles@v0.0.1"))
+	assert.Equal(t, &actions_model.ActionReference{Name: "gitea.example.com/org/action", Ref: "main", Local: true}, parseActionReference("https://gitea.example.com/org/action@main"))
+	assert.Equal(t, &actions_model.ActionReference{Name: "docker://alpine:3", Ref: ""}, parseActionReference("docker://alpine:3"))
+	assert.Equal(t, &actions_model.ActionReference{Name: "org/repo/.gitea/workflows/build.yml", Ref: "v1"}, parseActionReference("org/repo/.gitea/workflows/build.yml@v1"))
+
+	setting.Actions.DefaultActionsURL = "self"
+	assert.Equal(t, &actions_model.ActionReference{Name: "actions/checkout", Ref: "v4", Local: true}, parseActionReference("actions/checkout@v4"))
+	assert.Equal(t, &actions_model.ActionReference{Name: "github.com/actions/checkout", Ref: "v4"}, parseActionReference("https://github.com/actions/checkout@v4"))
+}
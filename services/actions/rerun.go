@@ -4,8 +4,16 @@
 package actions
 
 import (
+	"context"
+	"slices"
+	"strings"
+
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 // GetAllRerunJobs get all jobs that need to be rerun when job should be rerun
@@ -36,3 +44,83 @@ func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.A
 
 	return rerunJobs
 }
+
+// GetFailedRerunJobs gets the failed or cancelled jobs and all the jobs depending on them
+func GetFailedRerunJobs(allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
+	var rerunJobs []*actions_model.ActionRunJob
+	// the jobs expanded from a matrix share their JobID, so they are told apart by their ID
+	rerunJobsIDSet := make(container.Set[int64])
+	for _, job := range allJobs {
+		if job.Status != actions_model.StatusFailure && job.Status != actions_model.StatusCancelled {
+			continue
+		}
+		for _, j := range GetAllRerunJobs(job, allJobs) {
+			if rerunJobsIDSet.Add(j.ID) {
+				rerunJobs = append(rerunJobs, j)
+			}
+		}
+	}
+	return rerunJobs
+}
+
+// RerunJobs reruns the done jobs among the jobs of the run, they keep the commit and the inputs of the run.
+// A job needing another rerun job is blocked until it's done. The run starts a new attempt if it's done.
+// It returns util.ErrInvalidArgument if the jobs use actions the current policies don't allow.
+func RerunJobs(ctx context.Context, run *actions_model.ActionRun, rerunJobs []*actions_model.ActionRunJob) error {
+	annotations, err := checkRunJobsActionsPolicies(ctx, run.OwnerID, rerunJobs)
+	if err != nil {
+		return err
+	} else if len(annotations) > 0 {
+		return util.NewInvalidArgumentErrorf("%s", strings.Join(annotations, "\n"))
+	}
+
+	// reset run's start and stop time when it is done
+	if run.Status.IsDone() {
+		run.PreviousDuration = run.Duration()
+		run.Started = 0
+		run.Stopped = 0
+		run.Attempt++
+		run.FailureAnnotations = nil
+		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration", "attempt", "failure_annotations"); err != nil {
+			return err
+		}
+	}
+
+	rerunJobsIDSet := make(container.Set[string])
+	for _, job := range rerunJobs {
+		rerunJobsIDSet.Add(job.JobID)
+	}
+	for _, job := range rerunJobs {
+		job.Run = run
+		shouldBlock := slices.ContainsFunc(job.Needs, rerunJobsIDSet.Contains)
+		if err := rerunJob(ctx, job, shouldBlock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rerunJob(ctx context.Context, job *actions_model.ActionRunJob, shouldBlock bool) error {
+	status := job.Status
+	if !status.IsDone() {
+		return nil
+	}
+
+	job.TaskID = 0
+	job.Status = actions_model.StatusWaiting
+	if shouldBlock {
+		job.Status = actions_model.StatusBlocked
+	}
+	job.Started = 0
+	job.Stopped = 0
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	CreateCommitStatus(ctx, job)
+	return nil
+}
@@ -46,3 +46,20 @@ func TestGetAllRerunJobs(t *testing.T) {
 		assert.ElementsMatch(t, tc.rerunJobs, rerunJobs)
 	}
 }
+
+func TestGetFailedRerunJobs(t *testing.T) {
+	build := &actions_model.ActionRunJob{ID: 1, JobID: "build", Status: actions_model.StatusSuccess}
+	testLinux := &actions_model.ActionRunJob{ID: 2, JobID: "test", Needs: []string{"build"}, Status: actions_model.StatusFailure}
+	testWindows := &actions_model.ActionRunJob{ID: 3, JobID: "test", Needs: []string{"build"}, Status: actions_model.StatusCancelled}
+	deploy := &actions_model.ActionRunJob{ID: 4, JobID: "deploy", Needs: []string{"test"}, Status: actions_model.StatusSkipped}
+	lint := &actions_model.ActionRunJob{ID: 5, JobID: "lint", Status: actions_model.StatusSuccess}
+
+	jobs := []*actions_model.ActionRunJob{build, testLinux, testWindows, deploy, lint}
+	assert.ElementsMatch(t, []*actions_model.ActionRunJob{testLinux, testWindows, deploy}, GetFailedRerunJobs(jobs))
+
+	testWindows.Status = actions_model.StatusSuccess
+	assert.ElementsMatch(t, []*actions_model.ActionRunJob{testLinux, deploy}, GetFailedRerunJobs(jobs))
+
+	testLinux.Status = actions_model.StatusSuccess
+	assert.Empty(t, GetFailedRerunJobs(jobs))
+}
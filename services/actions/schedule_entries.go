@@ -0,0 +1,146 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ScheduleEntry is an entry of the schedule trigger of a workflow of the default branch of a repository
+type ScheduleEntry struct {
+	*actions_model.ActionScheduleSpec
+	// Paused is whether the entry is paused, it doesn't create runs until it's resumed
+	Paused bool
+	// WorkflowDisabled is whether the workflow is disabled, none of its entries creates runs
+	WorkflowDisabled bool
+	// LastRun is the latest run created by the schedule trigger of the workflow, nil if there's none
+	LastRun *actions_model.ActionRun
+}
+
+// NextRunTime returns when the entry creates its next run, or the zero time if it's paused or its workflow is disabled
+func (e *ScheduleEntry) NextRunTime() time.Time {
+	if e.Paused || e.WorkflowDisabled || e.Next == 0 {
+		return time.Time{}
+	}
+	return e.Next.AsTime()
+}
+
+func getActionsConfig(ctx context.Context, repo *repo_model.Repository) (*repo_model.RepoUnit, error) {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if repo_model.IsErrUnitTypeNotExist(err) {
+		return nil, util.NewNotExistErrorf("actions are disabled in the repository")
+	}
+	return cfgUnit, err
+}
+
+// loadScheduleEntries returns the entries of the specs, with the latest run of each workflow
+func loadScheduleEntries(ctx context.Context, cfg *repo_model.ActionsConfig, specs []*actions_model.ActionScheduleSpec) ([]*ScheduleEntry, error) {
+	lastRuns := make(map[string]*actions_model.ActionRun)
+	entries := make([]*ScheduleEntry, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Schedule == nil {
+			continue
+		}
+		workflowID := spec.Schedule.WorkflowID
+		lastRun, ok := lastRuns[workflowID]
+		if !ok {
+			run, err := actions_model.GetLatestScheduleRun(ctx, spec.RepoID, workflowID)
+			if err != nil && !errors.Is(err, util.ErrNotExist) {
+				return nil, err
+			}
+			lastRun = run
+			lastRuns[workflowID] = run
+		}
+		entries = append(entries, &ScheduleEntry{
+			ActionScheduleSpec: spec,
+			Paused:             cfg.IsSchedulePaused(workflowID, spec.Spec),
+			WorkflowDisabled:   cfg.IsWorkflowDisabled(workflowID),
+			LastRun:            lastRun,
+		})
+	}
+	return entries, nil
+}
+
+// ListScheduleEntries returns the entries of the schedule triggers of the workflows of the default branch of the repository,
+// sorted by workflow and in the order they're written in the workflow
+func ListScheduleEntries(ctx context.Context, repo *repo_model.Repository) ([]*ScheduleEntry, error) {
+	cfgUnit, err := getActionsConfig(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	specs, _, err := actions_model.FindSpecs(ctx, actions_model.FindSpecOptions{
+		ListOptions: db.ListOptionsAll,
+		RepoID:      repo.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := loadScheduleEntries(ctx, cfgUnit.ActionsConfig(), specs)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Schedule.WorkflowID != entries[j].Schedule.WorkflowID {
+			return entries[i].Schedule.WorkflowID < entries[j].Schedule.WorkflowID
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// GetScheduleEntry returns an entry of the schedule trigger of a workflow of the repository by the id of its spec
+func GetScheduleEntry(ctx context.Context, repo *repo_model.Repository, id int64) (*ScheduleEntry, error) {
+	cfgUnit, err := getActionsConfig(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := actions_model.GetScheduleSpecByRepoID(ctx, repo.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := loadScheduleEntries(ctx, cfgUnit.ActionsConfig(), []*actions_model.ActionScheduleSpec{spec})
+	if err != nil {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// SetScheduleEntryPaused pauses or resumes the entry, without changing the workflow.
+// The pause is kept in the settings of the repository, so it outlives the updates of the default branch.
+func SetScheduleEntryPaused(ctx context.Context, repo *repo_model.Repository, entry *ScheduleEntry, paused bool) error {
+	cfgUnit, err := getActionsConfig(ctx, repo)
+	if err != nil {
+		return err
+	}
+	cfg := cfgUnit.ActionsConfig()
+	if paused {
+		cfg.PauseSchedule(entry.Schedule.WorkflowID, entry.Spec)
+	} else {
+		cfg.ResumeSchedule(entry.Schedule.WorkflowID, entry.Spec)
+	}
+	if err := repo_model.UpdateRepoUnit(ctx, cfgUnit); err != nil {
+		return err
+	}
+	entry.Paused = paused
+	return nil
+}
+
+// RunScheduleEntry creates a run of the workflow of the entry at once, triggered by the doer,
+// even if the entry is paused. It doesn't change when the entry creates its next run.
+func RunScheduleEntry(ctx context.Context, doer *user_model.User, entry *ScheduleEntry) (*actions_model.ActionRun, error) {
+	if entry.WorkflowDisabled {
+		return nil, util.NewPermissionDeniedErrorf("workflow %q is disabled", entry.Schedule.WorkflowID)
+	}
+	return createScheduleRun(ctx, entry.Schedule, doer.ID)
+}
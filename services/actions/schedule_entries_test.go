@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleEntryNextRunTime(t *testing.T) {
+	next := timeutil.TimeStamp(1700000000)
+	entry := &ScheduleEntry{ActionScheduleSpec: &actions_model.ActionScheduleSpec{Spec: "@hourly", Next: next}}
+	assert.Equal(t, next.AsTime(), entry.NextRunTime())
+
+	entry.Paused = true
+	assert.True(t, entry.NextRunTime().IsZero())
+
+	entry.Paused, entry.WorkflowDisabled = false, true
+	assert.True(t, entry.NextRunTime().IsZero())
+}
@@ -83,9 +83,13 @@ func startTasks(ctx context.Context) error {
 				continue
 			}
 
-			if err := CreateScheduleTask(ctx, row.Schedule); err != nil {
-				log.Error("CreateScheduleTask: %v", err)
-				return err
+			// A paused entry skips its slots without creating runs, so it doesn't run at once when it's resumed
+			paused := cfg.ActionsConfig().IsSchedulePaused(row.Schedule.WorkflowID, row.Spec)
+			if !paused {
+				if err := CreateScheduleTask(ctx, row.Schedule); err != nil {
+					log.Error("CreateScheduleTask: %v", err)
+					return err
+				}
 			}
 
 			// Parse the spec
@@ -95,9 +99,13 @@ func startTasks(ctx context.Context) error {
 				return err
 			}
 
-			// Update the spec's next run time and previous run time
-			row.Prev = row.Next
-			row.Next = timeutil.TimeStamp(schedule.Next(now.Add(1 * time.Minute)).Unix())
+			// Update the spec's next run time and previous run time.
+			// The next run time is the first slot after now, not after now plus the interval of this task:
+			// the slots missed while the task was late are coalesced into this run and the next one isn't skipped.
+			if !paused {
+				row.Prev = row.Next
+			}
+			row.Next = timeutil.TimeStamp(schedule.Next(now).Unix())
 			if err := actions_model.UpdateScheduleSpec(ctx, row, "prev", "next"); err != nil {
 				log.Error("UpdateScheduleSpec: %v", err)
 				return err
@@ -116,13 +124,19 @@ func startTasks(ctx context.Context) error {
 // CreateScheduleTask creates a scheduled task from a cron action schedule.
 // It creates an action run based on the schedule, inserts it into the database, and creates commit statuses for each job.
 func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule) error {
+	_, err := createScheduleRun(ctx, cron, cron.TriggerUserID)
+	return err
+}
+
+// createScheduleRun creates a run of the schedule triggered by the user
+func createScheduleRun(ctx context.Context, cron *actions_model.ActionSchedule, triggerUserID int64) (*actions_model.ActionRun, error) {
 	// Create a new action run based on the schedule
 	run := &actions_model.ActionRun{
 		Title:         cron.Title,
 		RepoID:        cron.RepoID,
 		OwnerID:       cron.OwnerID,
 		WorkflowID:    cron.WorkflowID,
-		TriggerUserID: cron.TriggerUserID,
+		TriggerUserID: triggerUserID,
 		Ref:           cron.Ref,
 		CommitSHA:     cron.CommitSHA,
 		Event:         cron.Event,
@@ -132,23 +146,38 @@ func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule)
 		Status:        actions_model.StatusWaiting,
 	}
 
+	if err := run.LoadAttributes(ctx); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return nil, err
+	}
+
 	vars, err := actions_model.GetVariablesOfRun(ctx, run)
 	if err != nil {
 		log.Error("GetVariablesOfRun: %v", err)
-		return err
+		return nil, err
 	}
 
 	// Parse the workflow specification from the cron schedule
 	workflows, err := jobparser.Parse(cron.Content, jobparser.WithVars(vars))
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// Fail the run at once if it uses actions the policies don't allow
+	if run.FailureAnnotations, err = CheckActionsPolicies(ctx, run.OwnerID, workflows); err != nil {
+		return nil, err
+	}
+
+	// Evaluate the concurrency of the workflow and its jobs
+	concurrencies, err := evaluateConcurrencies(run, cron.Content, workflows, vars)
+	if err != nil {
+		return nil, err
 	}
 
 	// Insert the action run and its associated jobs into the database
-	if err := actions_model.InsertRun(ctx, run, workflows); err != nil {
-		return err
+	if err := actions_model.InsertRun(ctx, run, workflows, concurrencies); err != nil {
+		return nil, err
 	}
 
-	// Return nil if no errors occurred
-	return nil
+	return run, nil
 }
@@ -15,6 +15,18 @@ import (
 )
 
 func CreateVariable(ctx context.Context, ownerID, repoID int64, name, data string) (*actions_model.ActionVariable, error) {
+	return createVariable(ctx, ownerID, repoID, "", name, data)
+}
+
+// CreateEnvironmentVariable creates a variable bound to an environment of a repository
+func CreateEnvironmentVariable(ctx context.Context, repoID int64, environment, name, data string) (*actions_model.ActionVariable, error) {
+	if err := secret_service.ValidateEnvironmentName(environment); err != nil {
+		return nil, err
+	}
+	return createVariable(ctx, 0, repoID, environment, name, data)
+}
+
+func createVariable(ctx context.Context, ownerID, repoID int64, environment, name, data string) (*actions_model.ActionVariable, error) {
 	if err := secret_service.ValidateName(name); err != nil {
 		return nil, err
 	}
@@ -23,7 +35,7 @@ func CreateVariable(ctx context.Context, ownerID, repoID int64, name, data strin
 		return nil, err
 	}
 
-	v, err := actions_model.InsertVariable(ctx, ownerID, repoID, name, util.ReserveLineBreakForTextarea(data))
+	v, err := actions_model.InsertEnvironmentVariable(ctx, ownerID, repoID, environment, name, util.ReserveLineBreakForTextarea(data))
 	if err != nil {
 		return nil, err
 	}
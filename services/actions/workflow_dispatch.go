@@ -0,0 +1,166 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// resolveDispatchRef returns the full name and the commit of the branch or the tag a workflow is run manually on,
+// the ref can be a short or a full name
+func resolveDispatchRef(gitRepo *git.Repository, ref string) (git.RefName, *git.Commit, error) {
+	refName := git.RefName(ref)
+	if !strings.HasPrefix(ref, "refs/") {
+		if gitRepo.IsBranchExist(ref) {
+			refName = git.RefNameFromBranch(ref)
+		} else if gitRepo.IsTagExist(ref) {
+			refName = git.RefNameFromTag(ref)
+		}
+	}
+
+	var commit *git.Commit
+	var err error
+	switch {
+	case refName.IsBranch():
+		commit, err = gitRepo.GetBranchCommit(refName.BranchName())
+	case refName.IsTag():
+		commit, err = gitRepo.GetTagCommit(refName.TagName())
+	default:
+		return "", nil, util.NewNotExistErrorf("ref %q is not a branch or a tag", ref)
+	}
+	if git.IsErrNotExist(err) {
+		return "", nil, util.NewNotExistErrorf("ref %q doesn't exist", ref)
+	} else if err != nil {
+		return "", nil, err
+	}
+	return refName, commit, nil
+}
+
+// GetWorkflowContent returns the content of the workflow file on the commit
+func GetWorkflowContent(commit *git.Commit, workflowID string) ([]byte, error) {
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Name() == workflowID {
+			return actions_module.GetContentFromEntry(entry)
+		}
+	}
+	return nil, util.NewNotExistErrorf("workflow %q doesn't exist", workflowID)
+}
+
+// DispatchWorkflow runs the workflow manually on the branch or the tag, with the inputs of its workflow_dispatch trigger.
+// The inputs are validated against their definitions in the workflow and recorded in the run.
+func DispatchWorkflow(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, workflowID, ref string, inputs map[string]string) (*actions_model.ActionRun, error) {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if repo_model.IsErrUnitTypeNotExist(err) {
+		return nil, util.NewNotExistErrorf("actions are disabled in the repository")
+	} else if err != nil {
+		return nil, err
+	}
+	if cfgUnit.ActionsConfig().IsWorkflowDisabled(workflowID) {
+		return nil, util.NewPermissionDeniedErrorf("workflow %q is disabled", workflowID)
+	}
+
+	refName, commit, err := resolveDispatchRef(gitRepo, ref)
+	if err != nil {
+		return nil, err
+	}
+	content, err := GetWorkflowContent(commit, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := model.ReadWorkflow(bytes.NewReader(content))
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid workflow %q: %v", workflowID, err)
+	}
+	dispatchInputs, ok := actions_module.GetWorkflowDispatchInputs(wf)
+	if !ok {
+		return nil, util.NewInvalidArgumentErrorf("workflow %q doesn't have a workflow_dispatch trigger", workflowID)
+	}
+	inputs, err = actions_module.ValidateWorkflowDispatchInputs(dispatchInputs, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return nil, err
+	}
+	p, err := json.Marshal(&api.WorkflowDispatchPayload{
+		Workflow:   workflowID,
+		Ref:        refName.String(),
+		Inputs:     inputs,
+		Repository: convert.ToRepo(ctx, repo, permission),
+		Sender:     convert.ToUser(ctx, doer, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	run := &actions_model.ActionRun{
+		Title:          strings.SplitN(commit.CommitMessage, "\n", 2)[0],
+		RepoID:         repo.ID,
+		OwnerID:        repo.OwnerID,
+		WorkflowID:     workflowID,
+		TriggerUserID:  doer.ID,
+		Ref:            refName.String(),
+		CommitSHA:      commit.ID.String(),
+		Event:          webhook_module.HookEventWorkflowDispatch,
+		EventPayload:   string(p),
+		TriggerEvent:   actions_module.GithubEventWorkflowDispatch,
+		DispatchInputs: inputs,
+		Status:         actions_model.StatusWaiting,
+	}
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	vars, err := actions_model.GetVariablesOfRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := jobparser.Parse(content, jobparser.WithVars(vars))
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid workflow %q: %v", workflowID, err)
+	}
+	if run.FailureAnnotations, err = CheckActionsPolicies(ctx, run.OwnerID, jobs); err != nil {
+		return nil, err
+	}
+	concurrencies, err := evaluateConcurrencies(run, content, jobs, vars)
+	if err != nil {
+		return nil, err
+	}
+	if err := actions_model.InsertRun(ctx, run, jobs, concurrencies); err != nil {
+		return nil, err
+	}
+
+	alljobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	if err != nil {
+		return nil, err
+	}
+	CreateCommitStatus(ctx, alljobs...)
+	return run, nil
+}
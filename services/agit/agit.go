@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -19,15 +18,16 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
+	pushoptions_service "code.gitea.io/gitea/services/pushoptions"
 )
 
 // ProcReceive handle proc receive work
 func ProcReceive(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, opts *private.HookOptions) ([]private.HookProcReceiveRefResult, error) {
 	results := make([]private.HookProcReceiveRefResult, 0, len(opts.OldCommitIDs))
-	topicBranch := opts.GitPushOptions["topic"]
-	forcePush, _ := strconv.ParseBool(opts.GitPushOptions["force-push"])
-	title := strings.TrimSpace(opts.GitPushOptions["title"])
-	description := strings.TrimSpace(opts.GitPushOptions["description"]) // TODO: Add more options?
+	topicBranch := opts.GitPushOptions[private.GitPushOptionTopic]
+	forcePush := opts.GitPushOptions.Bool(private.GitPushOptionForcePush).Value()
+	title := strings.TrimSpace(opts.GitPushOptions[private.GitPushOptionTitle])
+	description := strings.TrimSpace(opts.GitPushOptions[private.GitPushOptionDescription])
 	objectFormat := git.ObjectFormatFromName(repo.ObjectFormatName)
 	userName := strings.ToLower(opts.UserName)
 
@@ -145,6 +145,10 @@ func ProcReceive(ctx context.Context, repo *repo_model.Repository, gitRepo *git.
 
 			log.Trace("Pull request created: %d/%d", repo.ID, prIssue.ID)
 
+			if err := pushoptions_service.HandlePullRequestPush(ctx, pusher, pr, opts); err != nil {
+				log.Error("Failed to handle the push options of %-v: %v", pr, err)
+			}
+
 			results = append(results, private.HookProcReceiveRefResult{
 				Ref:               pr.GetGitRefName(),
 				OriginalRef:       opts.RefFullNames[i],
@@ -212,6 +216,10 @@ func ProcReceive(ctx context.Context, repo *repo_model.Repository, gitRepo *git.
 		notify_service.PullRequestSynchronized(ctx, pusher, pr)
 		isForcePush := comment != nil && comment.IsForcePush
 
+		if err := pushoptions_service.HandlePullRequestPush(ctx, pusher, pr, opts); err != nil {
+			log.Error("Failed to handle the push options of %-v: %v", pr, err)
+		}
+
 		results = append(results, private.HookProcReceiveRefResult{
 			OldOID:            oldCommitID,
 			NewOID:            opts.NewCommitIDs[i],
@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 )
 
 type signingMode string
@@ -281,6 +282,74 @@ Loop:
 	return true, signingKey, sig, nil
 }
 
+// UserSigningKey is a key of a user which the server can use to sign commits on their behalf
+type UserSigningKey struct {
+	// Format is the value of gpg.format to sign with the key, "openpgp" or "ssh"
+	Format string
+	// KeyID is the key to pass to git commit -S
+	KeyID string
+}
+
+// GetUserSigningKey returns a key to sign the CRUD commits of the user with if SIGN_WITH_USER_KEYS is enabled:
+// a GPG key of the user whose secret key is in the keyring of the server, or a verified SSH key of the user
+// which is loaded into the ssh-agent of the server. It returns nil if there is no such key.
+func GetUserSigningKey(ctx context.Context, u *user_model.User) (*UserSigningKey, error) {
+	if !setting.Repository.Signing.SignWithUserKeys || u == nil || u.ID <= 0 {
+		return nil, nil
+	}
+
+	gpgKeys, err := db.Find[asymkey_model.GPGKey](ctx, asymkey_model.FindGPGKeyOptions{
+		OwnerID:        u.ID,
+		IncludeSubKeys: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range gpgKeys {
+		if !key.CanSign || (key.ExpiredUnix > 0 && key.ExpiredUnix < timeutil.TimeStampNow()) {
+			continue
+		}
+		// gpg only lists the secret key if it is in the keyring or available through the gpg-agent
+		if _, _, err := process.GetManager().Exec("gpg --list-secret-keys",
+			"gpg", "--batch", "--with-colons", "--list-secret-keys", key.KeyID); err == nil {
+			return &UserSigningKey{Format: "openpgp", KeyID: key.KeyID}, nil
+		}
+	}
+
+	if !git.DefaultFeatures().CheckVersionAtLeast("2.34") {
+		return nil, nil
+	}
+	sshKeys, err := db.Find[asymkey_model.PublicKey](ctx, asymkey_model.FindPublicKeyOptions{
+		OwnerID:  u.ID,
+		KeyTypes: []asymkey_model.KeyType{asymkey_model.KeyTypeUser},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var agentKeys string
+	for _, key := range sshKeys {
+		if !key.Verified {
+			continue
+		}
+		if agentKeys == "" {
+			// the ssh-agent lists its keys as "<type> <base64 key> <comment>"
+			stdout, stderr, err := process.GetManager().Exec("ssh-add -L", "ssh-add", "-L")
+			if err != nil {
+				log.Debug("Unable to list the keys of the ssh-agent: %s, %v", stderr, err)
+				return nil, nil
+			}
+			agentKeys = stdout
+		}
+		publicKey := key.OmitEmail()
+		for _, agentKey := range strings.Split(agentKeys, "\n") {
+			if fields := strings.Fields(agentKey); len(fields) >= 2 && fields[0]+" "+fields[1] == publicKey {
+				return &UserSigningKey{Format: "ssh", KeyID: "key::" + publicKey}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
 // SignMerge determines if we should sign a PR merge commit to the base repository
 func SignMerge(ctx context.Context, pr *issues_model.PullRequest, u *user_model.User, tmpBasePath, baseCommit, headCommit string) (bool, string, *git.Signature, error) {
 	if err := pr.LoadBaseRepo(ctx); err != nil {
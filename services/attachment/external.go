@@ -0,0 +1,129 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attachment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/google/uuid"
+	"xorm.io/builder"
+)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// NewExternalAttachment creates an attachment for a file hosted outside of Gitea.
+// The file is not fetched, its checksum is verified later by VerifyExternalAttachments.
+func NewExternalAttachment(ctx context.Context, attach *repo_model.Attachment) (*repo_model.Attachment, error) {
+	if attach.RepoID == 0 {
+		return nil, fmt.Errorf("attachment %s should belong to a repository", attach.Name)
+	}
+
+	u, err := url.Parse(attach.ExternalURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, util.NewInvalidArgumentErrorf("external url must be an absolute http(s) url")
+	}
+	attach.ExternalSHA256 = strings.ToLower(attach.ExternalSHA256)
+	if !sha256Pattern.MatchString(attach.ExternalSHA256) {
+		return nil, util.NewInvalidArgumentErrorf("sha256 must be a hex encoded SHA-256 checksum")
+	}
+	if attach.Size <= 0 {
+		return nil, util.NewInvalidArgumentErrorf("size of an external attachment must be positive")
+	}
+
+	attach.UUID = uuid.New().String()
+	attach.ExternalStatus = repo_model.ExternalAssetUnverified
+	return attach, db.Insert(ctx, attach)
+}
+
+func newExternalAssetHTTPClient() *http.Client {
+	allowedHostListValue := setting.Repository.Release.ExternalAssetAllowedHostList
+	if allowedHostListValue == "" {
+		allowedHostListValue = hostmatcher.MatchBuiltinExternal
+	}
+	allowedHostMatcher := hostmatcher.ParseHostMatchList("repository.release.EXTERNAL_ASSET_ALLOWED_HOST_LIST", allowedHostListValue)
+
+	return &http.Client{
+		Timeout: 10 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:       proxy.Proxy(),
+			DialContext: hostmatcher.NewDialContext("external asset", allowedHostMatcher, nil),
+		},
+	}
+}
+
+func checkExternalAttachment(ctx context.Context, client *http.Client, attach *repo_model.Attachment) repo_model.ExternalAssetStatus {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attach.ExternalURL, nil)
+	if err != nil {
+		log.Debug("Invalid url of external attachment %s: %v", attach.UUID, err)
+		return repo_model.ExternalAssetUnreachable
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Debug("Unable to fetch external attachment %s: %v", attach.UUID, err)
+		return repo_model.ExternalAssetUnreachable
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Debug("Unable to fetch external attachment %s: unexpected status %d", attach.UUID, resp.StatusCode)
+		return repo_model.ExternalAssetUnreachable
+	}
+
+	// read one byte more than expected to detect files which are too large
+	h := sha256.New()
+	n, err := io.Copy(h, io.LimitReader(resp.Body, attach.Size+1))
+	if err != nil {
+		log.Debug("Unable to read external attachment %s: %v", attach.UUID, err)
+		return repo_model.ExternalAssetUnreachable
+	}
+	if n != attach.Size || hex.EncodeToString(h.Sum(nil)) != attach.ExternalSHA256 {
+		return repo_model.ExternalAssetMismatch
+	}
+	return repo_model.ExternalAssetVerified
+}
+
+// VerifyExternalAttachment downloads an external attachment and checks it against the expected checksum and size
+func VerifyExternalAttachment(ctx context.Context, attach *repo_model.Attachment) error {
+	return verifyExternalAttachment(ctx, newExternalAssetHTTPClient(), attach)
+}
+
+func verifyExternalAttachment(ctx context.Context, client *http.Client, attach *repo_model.Attachment) error {
+	if !attach.IsExternal() {
+		return nil
+	}
+	attach.ExternalStatus = checkExternalAttachment(ctx, client, attach)
+	attach.ExternalCheckedUnix = timeutil.TimeStampNow()
+	return repo_model.UpdateAttachmentByUUID(ctx, attach, "external_status", "external_checked_unix")
+}
+
+// VerifyExternalAttachments verifies all external attachments
+func VerifyExternalAttachments(ctx context.Context) error {
+	client := newExternalAssetHTTPClient()
+	cond := builder.NotNull{"external_url"}.And(builder.Neq{"external_url": ""})
+	return db.Iterate(ctx, cond, func(ctx context.Context, attach *repo_model.Attachment) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before verifying external attachment %s", attach.UUID)
+		default:
+		}
+		return verifyExternalAttachment(ctx, client, attach)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attachment
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalAttachment(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer test.MockVariableValue(&setting.Repository.Release.ExternalAssetAllowedHostList, "loopback")()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gitea.tar.gz" {
+			_, _ = w.Write([]byte("release content"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	newAttach := func(path, sha string, size int64) (*repo_model.Attachment, error) {
+		return NewExternalAttachment(db.DefaultContext, &repo_model.Attachment{
+			RepoID:         1,
+			ReleaseID:      1,
+			UploaderID:     2,
+			Name:           "gitea.tar.gz",
+			ExternalURL:    srv.URL + path,
+			ExternalSHA256: sha,
+			Size:           size,
+		})
+	}
+	const sha = "5A3ED4E5D4D0B6ECBF8EA4E2AE29AE38B4A2A0C2D32F5F9FDA52FDB0F4E7F4E1"
+
+	_, err := newAttach("/gitea.tar.gz", "abc", 15)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = NewExternalAttachment(db.DefaultContext, &repo_model.Attachment{RepoID: 1, ExternalURL: "file:///etc/passwd", ExternalSHA256: sha, Size: 1})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	correct, err := newAttach("/gitea.tar.gz", "FD6DCFD7CD7FA52F54A35E7A1B95C91F826785E5E0FCD1FA53615DC3F1F13949", 15)
+	assert.NoError(t, err)
+	assert.True(t, correct.IsExternal())
+	assert.Equal(t, repo_model.ExternalAssetUnverified, correct.ExternalStatus)
+	wrongSize, err := newAttach("/gitea.tar.gz", sha, 14)
+	assert.NoError(t, err)
+	missing, err := newAttach("/missing", sha, 15)
+	assert.NoError(t, err)
+
+	assert.NoError(t, VerifyExternalAttachments(db.DefaultContext))
+
+	for attach, status := range map[*repo_model.Attachment]repo_model.ExternalAssetStatus{
+		correct:   repo_model.ExternalAssetVerified,
+		wrongSize: repo_model.ExternalAssetMismatch,
+		missing:   repo_model.ExternalAssetUnreachable,
+	} {
+		a := unittest.AssertExistsAndLoadBean(t, &repo_model.Attachment{ID: attach.ID})
+		assert.Equal(t, status, a.ExternalStatus, a.ExternalURL)
+		assert.NotZero(t, a.ExternalCheckedUnix)
+	}
+}
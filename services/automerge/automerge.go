@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -20,8 +21,12 @@ import (
 	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/process"
 	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/forms"
 	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
@@ -63,9 +68,9 @@ func addToQueue(pr *issues_model.PullRequest, sha string) {
 }
 
 // ScheduleAutoMerge if schedule is false and no error, pull can be merged directly
-func ScheduleAutoMerge(ctx context.Context, doer *user_model.User, pull *issues_model.PullRequest, style repo_model.MergeStyle, message string) (scheduled bool, err error) {
+func ScheduleAutoMerge(ctx context.Context, doer *user_model.User, pull *issues_model.PullRequest, style repo_model.MergeStyle, message string, conditions pull_model.AutoMergeConditions) (scheduled bool, err error) {
 	err = db.WithTx(ctx, func(ctx context.Context) error {
-		if err := pull_model.ScheduleAutoMerge(ctx, doer, pull.ID, style, message); err != nil {
+		if err := pull_model.ScheduleAutoMerge(ctx, doer, pull.ID, style, message, conditions); err != nil {
 			return err
 		}
 		scheduled = true
@@ -76,6 +81,40 @@ func ScheduleAutoMerge(ctx context.Context, doer *user_model.User, pull *issues_
 	return scheduled, err
 }
 
+// ConditionsFromForm returns the conditions of the auto merge of the pull request scheduled by the form
+func ConditionsFromForm(ctx context.Context, pull *issues_model.PullRequest, form *forms.MergePullRequestForm) (pull_model.AutoMergeConditions, error) {
+	conditions := pull_model.AutoMergeConditions{
+		RequiredApprovals:  form.AutoMergeRequiredApprovals,
+		NoChangesRequested: form.AutoMergeNoChangesRequested,
+	}
+	if conditions.RequiredApprovals < 0 {
+		return conditions, util.NewInvalidArgumentErrorf("the number of required approvals can't be negative")
+	}
+
+	if form.AutoMergeAfter != "" {
+		mergeAfter, err := time.Parse(time.RFC3339, form.AutoMergeAfter)
+		if err != nil {
+			return conditions, util.NewInvalidArgumentErrorf("invalid merge time %q: %v", form.AutoMergeAfter, err)
+		}
+		conditions.MergeAfterUnix = timeutil.TimeStamp(mergeAfter.Unix())
+	}
+
+	if form.AutoMergeAfterPull > 0 {
+		afterPR, err := issues_model.GetPullRequestByIndex(ctx, pull.BaseRepoID, form.AutoMergeAfterPull)
+		if err != nil {
+			if issues_model.IsErrPullRequestNotExist(err) {
+				return conditions, util.NewInvalidArgumentErrorf("pull request #%d doesn't exist", form.AutoMergeAfterPull)
+			}
+			return conditions, err
+		}
+		if afterPR.ID == pull.ID {
+			return conditions, util.NewInvalidArgumentErrorf("a pull request can't be merged after itself")
+		}
+		conditions.AfterPullID = afterPR.ID
+	}
+	return conditions, nil
+}
+
 // RemoveScheduledAutoMerge cancels a previously scheduled pull request
 func RemoveScheduledAutoMerge(ctx context.Context, doer *user_model.User, pull *issues_model.PullRequest) error {
 	return db.WithTx(ctx, func(ctx context.Context) error {
@@ -88,6 +127,30 @@ func RemoveScheduledAutoMerge(ctx context.Context, doer *user_model.User, pull *
 	})
 }
 
+// QueueDueAutoMerges starts an automerge check for the pull requests scheduled for merging after a time which has passed
+func QueueDueAutoMerges(ctx context.Context) error {
+	pullIDs, err := pull_model.GetDueAutoMergePullIDs(ctx, timeutil.TimeStampNow())
+	if err != nil {
+		return err
+	}
+	return startPRCheckAndAutoMergeByIDs(ctx, pullIDs)
+}
+
+// startPRCheckAndAutoMergeByIDs starts an automerge check for the pull requests with the given IDs
+func startPRCheckAndAutoMergeByIDs(ctx context.Context, pullIDs []int64) error {
+	for _, pullID := range pullIDs {
+		pr, err := issues_model.GetPullRequestByID(ctx, pullID)
+		if err != nil {
+			if issues_model.IsErrPullRequestNotExist(err) {
+				continue
+			}
+			return err
+		}
+		StartPRCheckAndAutoMerge(ctx, pr)
+	}
+	return nil
+}
+
 // StartPRCheckAndAutoMergeBySHA start an automerge check and auto merge task for all pull requests of repository and SHA
 func StartPRCheckAndAutoMergeBySHA(ctx context.Context, sha string, repo *repo_model.Repository) error {
 	pulls, err := getPullRequestsByHeadSHA(ctx, sha, repo, func(pr *issues_model.PullRequest) bool {
@@ -262,6 +325,17 @@ func handlePullRequestAutoMerge(pullID int64, sha string) {
 		return
 	}
 
+	// Check if the additional conditions are met
+	met, err := conditionsMet(ctx, pr, &scheduledPRM.AutoMergeConditions)
+	if err != nil {
+		log.Error("%-v conditionsMet: %v", pr, err)
+		return
+	}
+	if !met {
+		log.Info("Scheduled auto merge %-v has unmet conditions", pr)
+		return
+	}
+
 	// Merge if all checks succeeded
 	doer, err := user_model.GetUserByID(ctx, scheduledPRM.DoerID)
 	if err != nil {
@@ -284,7 +358,7 @@ func handlePullRequestAutoMerge(pullID int64, sha string) {
 		return
 	}
 
-	if err := pull_service.Merge(ctx, pr, doer, baseGitRepo, scheduledPRM.MergeStyle, "", scheduledPRM.Message, true); err != nil {
+	if err := pull_service.Merge(ctx, pr, doer, baseGitRepo, scheduledPRM.MergeStyle, "", scheduledPRM.Message, nil, true); err != nil {
 		log.Error("pull_service.Merge: %v", err)
 		// FIXME: if merge failed, we should display some error message to the pull request page.
 		// The resolution is add a new column on automerge table named `error_message` to store the error message and displayed
@@ -292,3 +366,53 @@ func handlePullRequestAutoMerge(pullID int64, sha string) {
 		return
 	}
 }
+
+// conditionsMet returns whether the pull request meets the conditions of its scheduled auto merge
+func conditionsMet(ctx context.Context, pr *issues_model.PullRequest, conditions *pull_model.AutoMergeConditions) (bool, error) {
+	if conditions.MergeAfterUnix > timeutil.TimeStampNow() {
+		return false, nil
+	}
+
+	if conditions.AfterPullID > 0 {
+		afterPR, err := issues_model.GetPullRequestByID(ctx, conditions.AfterPullID)
+		if err != nil && !issues_model.IsErrPullRequestNotExist(err) {
+			return false, err
+		}
+		// a deleted pull request can't be merged anymore, so it doesn't block the merge
+		if err == nil && !afterPR.HasMerged {
+			return false, nil
+		}
+	}
+
+	if conditions.RequiredApprovals > 0 {
+		approvals, err := issues_model.CountReviews(ctx, issues_model.FindReviewOptions{
+			Type:         issues_model.ReviewTypeApprove,
+			IssueID:      pr.IssueID,
+			OfficialOnly: true,
+			Dismissed:    optional.Some(false),
+		})
+		if err != nil {
+			return false, err
+		}
+		if approvals < conditions.RequiredApprovals {
+			return false, nil
+		}
+	}
+
+	if conditions.NoChangesRequested {
+		rejections, err := issues_model.CountReviews(ctx, issues_model.FindReviewOptions{
+			Type:         issues_model.ReviewTypeReject,
+			IssueID:      pr.IssueID,
+			OfficialOnly: true,
+			Dismissed:    optional.Some(false),
+		})
+		if err != nil {
+			return false, err
+		}
+		if rejections > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	notify_service "code.gitea.io/gitea/services/notify"
@@ -44,3 +45,62 @@ func (n *automergeNotifier) PullReviewDismiss(ctx context.Context, doer *user_mo
 	// as reviews could have blocked a pending automerge let's recheck
 	StartPRCheckAndAutoMerge(ctx, review.Issue.PullRequest)
 }
+
+func (n *automergeNotifier) IssueChangeStatus(ctx context.Context, doer *user_model.User, commitID string, issue *issues_model.Issue, actionComment *issues_model.Comment, closeOrReopen bool) {
+	if closeOrReopen {
+		startBlockedPRsCheckAndAutoMerge(ctx, issue)
+	}
+}
+
+// startBlockedPRsCheckAndAutoMerge rechecks the pull requests blocked by the closed issue,
+// which could be scheduled to merge once their dependencies are closed
+func startBlockedPRsCheckAndAutoMerge(ctx context.Context, issue *issues_model.Issue) {
+	blockedIssues, err := issues_model.GetOpenIssuesBlockedBy(ctx, issue.ID)
+	if err != nil {
+		log.Error("GetOpenIssuesBlockedBy: %v", err)
+		return
+	}
+	for _, blocked := range blockedIssues {
+		if !blocked.IsPull {
+			continue
+		}
+		if err := blocked.LoadPullRequest(ctx); err != nil {
+			log.Error("LoadPullRequest: %v", err)
+			continue
+		}
+		StartPRCheckAndAutoMerge(ctx, blocked.PullRequest)
+	}
+}
+
+func (n *automergeNotifier) MergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	startBlockedPRsCheckAndAutoMerge(ctx, pr.Issue)
+
+	// pull requests could have been scheduled to merge after this one
+	pullIDs, err := pull_model.GetAutoMergePullIDsAfterPull(ctx, pr.ID)
+	if err != nil {
+		log.Error("GetAutoMergePullIDsAfterPull: %v", err)
+		return
+	}
+	if err := startPRCheckAndAutoMergeByIDs(ctx, pullIDs); err != nil {
+		log.Error("startPRCheckAndAutoMergeByIDs: %v", err)
+	}
+}
+
+func (n *automergeNotifier) PullRequestChecked(ctx context.Context, pr *issues_model.PullRequest) {
+	// a scheduled automerge is skipped while the pull request is being checked
+	if !pr.CanAutoMerge() {
+		return
+	}
+	exists, _, err := pull_model.GetScheduledMergeByPullID(ctx, pr.ID)
+	if err != nil {
+		log.Error("GetScheduledMergeByPullID: %v", err)
+		return
+	}
+	if exists {
+		StartPRCheckAndAutoMerge(ctx, pr)
+	}
+}
+
+func (n *automergeNotifier) AutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	n.MergePullRequest(ctx, doer, pr)
+}
@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/httpcache"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	web_types "code.gitea.io/gitea/modules/web/types"
 )
@@ -72,6 +73,14 @@ type APIInvalidTopicsError struct {
 	InvalidTopics []string `json:"invalidTopics"`
 }
 
+// APIInvalidIssueFormError is error format response to invalid values of the fields of an issue form
+// swagger:response invalidIssueFormError
+type APIInvalidIssueFormError struct {
+	Message       string                         `json:"message"`
+	URL           string                         `json:"url"`
+	InvalidFields []*structs.IssueFormFieldError `json:"invalid_fields"`
+}
+
 // APIEmpty is an empty response
 // swagger:response empty
 type APIEmpty struct{}
@@ -92,6 +92,11 @@ func packageAssignment(ctx *packageAssignmentCtx, errCb func(int, string, any))
 	return pkg
 }
 
+// DeterminePackageAccessMode returns the access mode of the doer to the packages of the owner
+func DeterminePackageAccessMode(ctx *Base, owner, doer *user_model.User) (perm.AccessMode, error) {
+	return determineAccessMode(ctx, &Package{Owner: owner}, doer)
+}
+
 func determineAccessMode(ctx *Base, pkg *Package, doer *user_model.User) (perm.AccessMode, error) {
 	if setting.Service.RequireSignInView && (doer == nil || doer.IsGhost()) {
 		return perm.AccessModeNone, nil
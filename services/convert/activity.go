@@ -5,12 +5,15 @@ package convert
 
 import (
 	"context"
+	"strconv"
 
 	activities_model "code.gitea.io/gitea/models/activities"
 	perm_model "code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repository"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -50,3 +53,44 @@ func ToActivities(ctx context.Context, al activities_model.ActionList, doer *use
 	}
 	return result
 }
+
+// ToTimelineEvent converts an action of the timeline of a repository into API Format
+func ToTimelineEvent(ctx context.Context, ac *activities_model.Action, doer *user_model.User) *api.TimelineEvent {
+	eventType := ac.TimelineEventType()
+	result := &api.TimelineEvent{
+		ID:      ac.ID,
+		Type:    string(eventType),
+		Action:  ac.OpType.String(),
+		Actor:   ToUser(ctx, ac.ActUser, doer),
+		Created: ac.CreatedUnix.AsTime(),
+	}
+
+	switch eventType {
+	case activities_model.TimelineEventIssue, activities_model.TimelineEventPullRequest:
+		result.Number, _ = strconv.ParseInt(ac.GetIssueInfos()[0], 10, 64)
+		if ac.Comment != nil {
+			result.Comment = ToAPIComment(ctx, ac.Repo, ac.Comment)
+		}
+	case activities_model.TimelineEventCommit:
+		result.RefName = ac.RefName
+		if ac.Content == "" {
+			break
+		}
+		push := repository.NewPushCommits()
+		if err := json.Unmarshal([]byte(ac.Content), push); err != nil {
+			log.Error("Unable to unmarshal the commits of action %d: %v", ac.ID, err)
+			break
+		}
+		result.Commits = make([]*api.TimelineCommit, 0, len(push.Commits))
+		for _, commit := range push.Commits {
+			result.Commits = append(result.Commits, &api.TimelineCommit{
+				SHA:         commit.Sha1,
+				Message:     commit.Message,
+				AuthorName:  commit.AuthorName,
+				AuthorEmail: commit.AuthorEmail,
+				Timestamp:   commit.Timestamp,
+			})
+		}
+	}
+	return result
+}
@@ -28,7 +28,7 @@ func ToAPIAttachment(repo *repo_model.Repository, a *repo_model.Attachment) *api
 
 // toAttachment converts models.Attachment to api.Attachment for API usage
 func toAttachment(repo *repo_model.Repository, a *repo_model.Attachment, getDownloadURL func(repo *repo_model.Repository, attach *repo_model.Attachment) string) *api.Attachment {
-	return &api.Attachment{
+	apiAttachment := &api.Attachment{
 		ID:            a.ID,
 		Name:          a.Name,
 		Created:       a.CreatedUnix.AsTime(),
@@ -36,7 +36,18 @@ func toAttachment(repo *repo_model.Repository, a *repo_model.Attachment, getDown
 		Size:          a.Size,
 		UUID:          a.UUID,
 		DownloadURL:   getDownloadURL(repo, a), // for web request json and api request json, return different download urls
+		Type:          "attachment",
 	}
+	if a.IsExternal() {
+		apiAttachment.Type = "external"
+		apiAttachment.SHA256 = a.ExternalSHA256
+		apiAttachment.VerificationStatus = a.ExternalStatus.String()
+		if a.ExternalCheckedUnix > 0 {
+			verifiedAt := a.ExternalCheckedUnix.AsTime()
+			apiAttachment.VerifiedAt = &verifiedAt
+		}
+	}
+	return apiAttachment
 }
 
 func ToAPIAttachments(repo *repo_model.Repository, attachments []*repo_model.Attachment) []*api.Attachment {
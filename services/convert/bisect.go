@@ -0,0 +1,27 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToBisectSession converts a RepoBisectSession to API format
+func ToBisectSession(session *repo_model.RepoBisectSession) *api.BisectSession {
+	return &api.BisectSession{
+		Bad:         session.Bad,
+		Good:        session.Good,
+		Skipped:     session.Skipped,
+		Path:        session.Path,
+		Candidate:   session.Candidate,
+		Remaining:   session.Remaining,
+		Steps:       session.Steps,
+		Finished:    session.FirstBad != "",
+		FirstBad:    session.FirstBad,
+		OnlySkipped: session.OnlySkipped,
+		Created:     session.CreatedUnix.AsTime(),
+		Updated:     session.UpdatedUnix.AsTime(),
+	}
+}
@@ -7,6 +7,7 @@ package convert
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +23,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
@@ -29,6 +31,8 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/gitdiff"
+
+	"github.com/nektos/act/pkg/jobparser"
 )
 
 // ToEmail convert models.EmailAddress to api.Email
@@ -173,9 +177,11 @@ func ToBranchProtection(ctx context.Context, bp *git_model.ProtectedBranch, repo
 		BlockOnRejectedReviews:        bp.BlockOnRejectedReviews,
 		BlockOnOfficialReviewRequests: bp.BlockOnOfficialReviewRequests,
 		BlockOnOutdatedBranch:         bp.BlockOnOutdatedBranch,
+		RequireCodeOwnerApproval:      bp.RequireCodeOwnerApproval,
 		DismissStaleApprovals:         bp.DismissStaleApprovals,
 		IgnoreStaleApprovals:          bp.IgnoreStaleApprovals,
 		RequireSignedCommits:          bp.RequireSignedCommits,
+		RequireSignoff:                bp.RequireSignoff,
 		ProtectedFilePatterns:         bp.ProtectedFilePatterns,
 		UnprotectedFilePatterns:       bp.UnprotectedFilePatterns,
 		Created:                       bp.CreatedUnix.AsTime(),
@@ -220,6 +226,169 @@ func ToActionTask(ctx context.Context, t *actions_model.ActionTask) (*api.Action
 	}, nil
 }
 
+// ToActionRun convert a actions_model.ActionRun to an api.ActionRun
+func ToActionRun(ctx context.Context, run *actions_model.ActionRun) (*api.ActionRun, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	return &api.ActionRun{
+		ID:               run.ID,
+		RunNumber:        run.Index,
+		DisplayTitle:     run.Title,
+		WorkflowID:       run.WorkflowID,
+		HeadBranch:       run.PrettyRef(),
+		HeadSHA:          run.CommitSHA,
+		Event:            run.TriggerEvent,
+		Status:           run.Status.String(),
+		RunAttempt:       run.Attempt,
+		ConcurrencyGroup: run.ConcurrencyGroup,
+		CancelInProgress: run.ConcurrencyCancel,
+		Inputs:           run.DispatchInputs,
+		HTMLURL:          run.HTMLURL(),
+		CreatedAt:        run.Created.AsLocalTime(),
+		UpdatedAt:        run.Updated.AsLocalTime(),
+		RunStartedAt:     run.Started.AsLocalTime(),
+
+		FailureAnnotations: run.FailureAnnotations,
+	}, nil
+}
+
+// ToActionsPolicy converts an actions policy to the api format
+func ToActionsPolicy(policy *actions_model.ActionPolicy) *api.ActionsPolicy {
+	return &api.ActionsPolicy{
+		AllowedActions:    policy.Mode.String(),
+		PatternsAllowed:   append([]string{}, policy.AllowedPatterns...),
+		PatternsBlocked:   append([]string{}, policy.BlockedPatterns...),
+		RequireSHAPinning: policy.RequireSHAPinning,
+	}
+}
+
+// ToActionRunJob convert a actions_model.ActionRunJob to an api.ActionRunJob
+func ToActionRunJob(job *actions_model.ActionRunJob) (*api.ActionRunJob, error) {
+	var matrix map[string]any
+	if len(job.WorkflowPayload) > 0 {
+		workflows, err := jobparser.Parse(job.WorkflowPayload)
+		if err != nil {
+			return nil, err
+		}
+		if len(workflows) == 1 {
+			_, workflowJob := workflows[0].Job()
+			if workflowJob != nil {
+				if matrix, err = actions_module.GetJobMatrix(workflowJob); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	apiJob := &api.ActionRunJob{
+		ID:               job.ID,
+		JobID:            job.JobID,
+		Name:             job.Name,
+		Status:           job.Status.String(),
+		Needs:            job.Needs,
+		Matrix:           matrix,
+		RunsOn:           job.RunsOn,
+		Attempt:          job.Attempt,
+		ConcurrencyGroup: job.ConcurrencyGroup,
+		CancelInProgress: job.ConcurrencyCancel,
+		Duration:         int64(job.Duration().Seconds()),
+	}
+	if apiJob.Needs == nil {
+		apiJob.Needs = []string{}
+	}
+	if job.Started > 0 {
+		startedAt := job.Started.AsLocalTime()
+		apiJob.StartedAt = &startedAt
+	}
+	if job.Stopped > 0 && job.Status.IsDone() {
+		completedAt := job.Stopped.AsLocalTime()
+		apiJob.CompletedAt = &completedAt
+	}
+	return apiJob, nil
+}
+
+// ToActionJobStep convert the step at the index of the logs of a job to an api.ActionJobStep
+func ToActionJobStep(index int, step *actions_model.ActionTaskStep) *api.ActionJobStep {
+	apiStep := &api.ActionJobStep{
+		Index:  index,
+		Name:   step.Name,
+		Status: step.Status.String(),
+	}
+	if step.Started > 0 {
+		startedAt := step.Started.AsLocalTime()
+		apiStep.StartedAt = &startedAt
+	}
+	if step.Stopped > 0 && step.Status.IsDone() {
+		completedAt := step.Stopped.AsLocalTime()
+		apiStep.CompletedAt = &completedAt
+	}
+	return apiStep
+}
+
+// ToActionArtifactconvert the meta data of an artifact to an api.ActionArtifact
+func ToActionArtifact(meta *actions_model.ActionArtifactMeta) *api.ActionArtifact {
+	return &api.ActionArtifact{
+		Name:      meta.ArtifactName,
+		Size:      meta.FileSize,
+		Expired:   meta.Status == actions_model.ArtifactStatusExpired,
+		CreatedAt: meta.CreatedUnix.AsTime(),
+		ExpiresAt: meta.ExpiredUnix.AsTime(),
+	}
+}
+
+// ToActionRunner convert a actions_model.ActionRunner to an api.ActionRunner
+func ToActionRunner(runner *actions_model.ActionRunner) *api.ActionRunner {
+	return &api.ActionRunner{
+		ID:        runner.ID,
+		Name:      runner.Name,
+		Status:    runner.StatusName(),
+		Labels:    ToActionRunnerLabels(runner),
+		GroupID:   runner.GroupID,
+		Ephemeral: runner.Ephemeral,
+	}
+}
+
+// ToActionQueuedJobs converts the jobs waiting for a runner by set of labels
+func ToActionQueuedJobs(queued []*actions_model.QueuedJobs) *api.ActionQueuedJobsResponse {
+	res := &api.ActionQueuedJobsResponse{Entries: make([]*api.ActionQueuedJobs, 0, len(queued))}
+	for _, q := range queued {
+		res.Entries = append(res.Entries, &api.ActionQueuedJobs{
+			Labels:         q.Labels,
+			Count:          q.Count,
+			OldestQueuedAt: q.OldestQueued.AsTime(),
+		})
+		res.TotalCount += q.Count
+	}
+	return res
+}
+
+// ToActionRunnerLabels returns the labels of a runner, the ones declared by the runner are read-only
+func ToActionRunnerLabels(runner *actions_model.ActionRunner) []*api.ActionRunnerLabel {
+	labels := make([]*api.ActionRunnerLabel, 0, len(runner.AgentLabels)+len(runner.CustomLabels))
+	for _, label := range runner.Labels() {
+		labelType := "custom"
+		if slices.Contains(runner.AgentLabels, label) {
+			labelType = "read-only"
+		}
+		labels = append(labels, &api.ActionRunnerLabel{Name: label, Type: labelType})
+	}
+	return labels
+}
+
+// ToActionRunnerGroup convert a actions_model.ActionRunnerGroup to an api.ActionRunnerGroup
+func ToActionRunnerGroup(group *actions_model.ActionRunnerGroup) *api.ActionRunnerGroup {
+	return &api.ActionRunnerGroup{
+		ID:          group.ID,
+		Name:        group.Name,
+		Description: group.Description,
+		Visibility:  group.Visibility.String(),
+		Created:     group.Created.AsTime(),
+		Updated:     group.Updated.AsTime(),
+	}
+}
+
 // ToVerification convert a git.Commit.Signature to an api.PayloadCommitVerification
 func ToVerification(ctx context.Context, c *git.Commit) *api.PayloadCommitVerification {
 	verif := asymkey_model.ParseCommitWithSignature(ctx, c)
@@ -336,6 +505,7 @@ func ToOrganization(ctx context.Context, org *organization.Organization) *api.Or
 		Location:                  org.Location,
 		Visibility:                org.Visibility.String(),
 		RepoAdminChangeTeamAccess: org.RepoAdminChangeTeamAccess,
+		IssueVotingMembersOnly:    org.IssueVotingMembersOnly,
 	}
 }
 
@@ -445,6 +615,41 @@ func ToTopicResponse(topic *repo_model.Topic) *api.TopicResponse {
 	}
 }
 
+// ToCustomProperty convert from repo_model.CustomProperty to api.CustomProperty
+func ToCustomProperty(prop *repo_model.CustomProperty) *api.CustomProperty {
+	allowedValues := prop.AllowedValues
+	if allowedValues == nil {
+		allowedValues = []string{}
+	}
+	return &api.CustomProperty{
+		Name:          prop.Name,
+		Type:          string(prop.Type),
+		Description:   prop.Description,
+		AllowedValues: allowedValues,
+		DefaultValue:  prop.DefaultValue,
+		Required:      prop.Required,
+		Created:       prop.CreatedUnix.AsTime(),
+		Updated:       prop.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToManagedGitHook convert from git_model.ManagedGitHook to api.ManagedGitHook
+func ToManagedGitHook(hook *git_model.ManagedGitHook) *api.ManagedGitHook {
+	forbiddenPaths := hook.ForbiddenPaths
+	if forbiddenPaths == nil {
+		forbiddenPaths = []string{}
+	}
+	return &api.ManagedGitHook{
+		MaxFileSize:          hook.MaxFileSize,
+		ForbiddenPaths:       forbiddenPaths,
+		CommitMessagePattern: hook.CommitMessagePattern,
+		PreReceiveScript:     hook.PreReceiveScript,
+		UpdateScript:         hook.UpdateScript,
+		AllowRepoOptOut:      hook.AllowRepoOptOut,
+		Updated:              hook.UpdatedUnix.AsTime(),
+	}
+}
+
 // ToOAuth2Application convert from auth.OAuth2Application to api.OAuth2Application
 func ToOAuth2Application(app *auth.OAuth2Application) *api.OAuth2Application {
 	return &api.OAuth2Application{
@@ -506,3 +711,34 @@ func ToChangedFile(f *gitdiff.DiffFile, repo *repo_model.Repository, commit stri
 
 	return file
 }
+
+// ToChangedFileFromStat convert a git.CompareFileStat to api.ChangedFile
+func ToChangedFileFromStat(stat *git.CompareFileStat, repo *repo_model.Repository, commit string) *api.ChangedFile {
+	status := "changed"
+	switch stat.Status {
+	case "A":
+		status = "added"
+	case "D":
+		status = "deleted"
+	case "R":
+		status = "renamed"
+	case "C":
+		status = "copied"
+	default:
+		if stat.Additions == 0 && stat.Deletions == 0 && !stat.IsBinary {
+			status = "unchanged"
+		}
+	}
+
+	return &api.ChangedFile{
+		Filename:         stat.Name,
+		PreviousFilename: stat.OldName,
+		Status:           status,
+		Additions:        stat.Additions,
+		Deletions:        stat.Deletions,
+		Changes:          stat.Additions + stat.Deletions,
+		HTMLURL:          fmt.Sprint(repo.HTMLURL(), "/src/commit/", commit, "/", util.PathEscapeSegments(stat.Name)),
+		ContentsURL:      fmt.Sprint(repo.APIURL(), "/contents/", util.PathEscapeSegments(stat.Name), "?ref=", commit),
+		RawURL:           fmt.Sprint(repo.HTMLURL(), "/raw/commit/", commit, "/", util.PathEscapeSegments(stat.Name)),
+	}
+}
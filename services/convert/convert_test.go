@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToActionRunJob(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+	apiJob, err := ToActionRunJob(job)
+	assert.NoError(t, err)
+	assert.Equal(t, "job_2", apiJob.JobID)
+	assert.Equal(t, "success", apiJob.Status)
+	assert.NotNil(t, apiJob.Needs)
+	assert.Empty(t, apiJob.Matrix)
+	if assert.NotNil(t, apiJob.StartedAt) && assert.NotNil(t, apiJob.CompletedAt) {
+		assert.EqualValues(t, 1683636528, apiJob.StartedAt.Unix())
+		assert.EqualValues(t, 1683636626, apiJob.CompletedAt.Unix())
+	}
+	assert.EqualValues(t, 98, apiJob.Duration)
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToDeferredCommit converts a DeferredCommit to API format
+func ToDeferredCommit(deferredCommit *repo_model.DeferredCommit) *api.DeferredCommit {
+	return &api.DeferredCommit{
+		ID:        deferredCommit.ID,
+		Branch:    deferredCommit.Branch,
+		Status:    deferredCommit.Status.String(),
+		CommitID:  deferredCommit.CommitID,
+		Error:     deferredCommit.Error,
+		ExecuteAt: deferredCommit.ExecuteUnix.AsTime(),
+		Created:   deferredCommit.CreatedUnix.AsTime(),
+		Updated:   deferredCommit.UpdatedUnix.AsTime(),
+	}
+}
@@ -8,10 +8,14 @@ import (
 	"net/url"
 	"time"
 
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	ctx "code.gitea.io/gitea/services/context"
@@ -77,6 +81,7 @@ type ToCommitOptions struct {
 	Stat         bool
 	Verification bool
 	Files        bool
+	Reactions    bool
 }
 
 func ParseCommitOptions(ctx *ctx.APIContext) ToCommitOptions {
@@ -84,6 +89,7 @@ func ParseCommitOptions(ctx *ctx.APIContext) ToCommitOptions {
 		Stat:         ctx.FormString("stat") == "" || ctx.FormBool("stat"),
 		Files:        ctx.FormString("files") == "" || ctx.FormBool("files"),
 		Verification: ctx.FormString("verification") == "" || ctx.FormBool("verification"),
+		Reactions:    ctx.FormString("reactions") == "" || ctx.FormBool("reactions"),
 	}
 }
 
@@ -195,15 +201,36 @@ func ToCommit(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Rep
 			return nil, err
 		}
 
+		renames, err := git.GetCommitFileRenames(gitRepo.Ctx, repo.RepoPath(), commit.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		// a renamed file replaces the removal of its previous name and the addition of its new name
+		renamedFiles := make(container.Set[string], len(renames)*2)
+		for _, rename := range renames {
+			renamedFiles.AddMultiple("removed:"+rename.From, "added:"+rename.To)
+		}
+
 		affectedFileList := make([]*api.CommitAffectedFiles, 0, len(fileStatus.Added)+len(fileStatus.Removed)+len(fileStatus.Modified))
 		for filestatus, files := range map[string][]string{"added": fileStatus.Added, "removed": fileStatus.Removed, "modified": fileStatus.Modified} {
 			for _, filename := range files {
+				if renamedFiles.Contains(filestatus + ":" + filename) {
+					continue
+				}
 				affectedFileList = append(affectedFileList, &api.CommitAffectedFiles{
 					Filename: filename,
 					Status:   filestatus,
 				})
 			}
 		}
+		for _, rename := range renames {
+			affectedFileList = append(affectedFileList, &api.CommitAffectedFiles{
+				Filename:         rename.To,
+				Status:           "renamed",
+				PreviousFilename: rename.From,
+				Similarity:       rename.Similarity,
+			})
+		}
 
 		res.Files = affectedFileList
 	}
@@ -224,5 +251,25 @@ func ToCommit(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Rep
 		}
 	}
 
+	if opts.Reactions {
+		reactions, _, err := issues_model.FindCommitReactions(ctx, repo.ID, commit.ID.String(), db.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		res.Reactions = toReactionSummaries(reactions)
+	}
+
 	return res, nil
 }
+
+// toReactionSummaries counts the reactions of each type, in the order of the allowed reactions
+func toReactionSummaries(reactions issues_model.ReactionList) []*api.ReactionSummary {
+	grouped := reactions.GroupByType()
+	summaries := make([]*api.ReactionSummary, 0, len(grouped))
+	for _, reaction := range setting.UI.Reactions {
+		if list := grouped[reaction]; len(list) > 0 {
+			summaries = append(summaries, &api.ReactionSummary{Reaction: reaction, Count: len(list)})
+		}
+	}
+	return summaries
+}
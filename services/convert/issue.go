@@ -52,6 +52,7 @@ func toIssue(ctx context.Context, doer *user_model.User, issue *issues_model.Iss
 		State:       issue.State(),
 		IsLocked:    issue.IsLocked,
 		Comments:    issue.NumComments,
+		Votes:       issue.NumVotes,
 		Created:     issue.CreatedUnix.AsTime(),
 		Updated:     issue.UpdatedUnix.AsTime(),
 		PinOrder:    issue.PinOrder,
@@ -86,6 +87,24 @@ func toIssue(ctx context.Context, doer *user_model.User, issue *issues_model.Iss
 		apiIssue.Milestone = ToAPIMilestone(issue.Milestone)
 	}
 
+	if err := issue.LoadType(ctx); err != nil {
+		return &api.Issue{}
+	}
+	if issue.Type != nil {
+		apiIssue.Type = ToAPIIssueType(issue.Type)
+	}
+
+	if err := issue.LoadSubIssueProgress(ctx); err != nil {
+		return &api.Issue{}
+	}
+	if issue.SubIssueProgress.Total > 0 {
+		apiIssue.SubIssues = &api.SubIssueProgress{
+			Total:            issue.SubIssueProgress.Total,
+			Closed:           issue.SubIssueProgress.Closed,
+			PercentCompleted: issue.SubIssueProgress.Percent(),
+		}
+	}
+
 	if err := issue.LoadAssignees(ctx); err != nil {
 		return &api.Issue{}
 	}
@@ -256,6 +275,8 @@ func ToAPIMilestone(m *issues_model.Milestone) *api.Milestone {
 		ClosedIssues: m.NumClosedIssues,
 		Created:      m.CreatedUnix.AsTime(),
 		Updated:      m.UpdatedUnix.AsTimePtr(),
+
+		IsOrgMilestone: m.BelongsToOrg(),
 	}
 	if m.IsClosed {
 		apiMilestone.Closed = m.ClosedDateUnix.AsTimePtr()
@@ -266,6 +287,150 @@ func ToAPIMilestone(m *issues_model.Milestone) *api.Milestone {
 	return apiMilestone
 }
 
+// ToAPIMilestoneBurndown converts the burndown of a milestone into API Format,
+// the numbers of issues of the milestone are the ones of the repositories of the burndown
+func ToAPIMilestoneBurndown(ctx context.Context, m *issues_model.Milestone, burndown *issues_model.MilestoneBurndown) (*api.MilestoneBurndown, error) {
+	repoIDs := make([]int64, 0, len(burndown.Repos))
+	for _, progress := range burndown.Repos {
+		repoIDs = append(repoIDs, progress.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(ctx, repoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.MilestoneBurndown{
+		Milestone: ToAPIMilestone(m),
+		Days:      make([]*api.MilestoneBurndownDay, 0, len(burndown.Days)),
+		Repos:     make([]*api.MilestoneRepoProgress, 0, len(burndown.Repos)),
+	}
+	result.Milestone.OpenIssues = 0
+	result.Milestone.ClosedIssues = 0
+	for _, day := range burndown.Days {
+		result.Days = append(result.Days, &api.MilestoneBurndownDay{
+			Date:         day.Day.Format("2006-01-02"),
+			OpenIssues:   day.OpenIssues,
+			ClosedIssues: day.ClosedIssues,
+		})
+	}
+	for _, progress := range burndown.Repos {
+		repo, ok := repos[progress.RepoID]
+		if !ok {
+			continue
+		}
+		result.Repos = append(result.Repos, &api.MilestoneRepoProgress{
+			Repository: &api.RepositoryMeta{
+				ID:       repo.ID,
+				Name:     repo.Name,
+				Owner:    repo.OwnerName,
+				FullName: repo.FullName(),
+			},
+			OpenIssues:   progress.OpenIssues,
+			ClosedIssues: progress.ClosedIssues,
+		})
+		result.Milestone.OpenIssues += progress.OpenIssues
+		result.Milestone.ClosedIssues += progress.ClosedIssues
+	}
+	return result, nil
+}
+
+// ToAPIIssueType converts IssueType into API Format
+func ToAPIIssueType(t *issues_model.IssueType) *api.IssueType {
+	requiredFields := t.RequiredFields
+	if requiredFields == nil {
+		requiredFields = []string{}
+	}
+	return &api.IssueType{
+		ID:             t.ID,
+		Name:           t.Name,
+		Description:    t.Description,
+		Icon:           t.IconName(),
+		Color:          t.Color,
+		Template:       t.Template,
+		RequiredFields: requiredFields,
+		IsOrgType:      t.BelongsToOrg(),
+	}
+}
+
+// ToAPIIssueTypeList converts a list of IssueType into API Format
+func ToAPIIssueTypeList(types []*issues_model.IssueType) []*api.IssueType {
+	result := make([]*api.IssueType, len(types))
+	for i := range types {
+		result[i] = ToAPIIssueType(types[i])
+	}
+	return result
+}
+
+// ToAPIIssueFilter converts IssueFilter into API Format
+func ToAPIIssueFilter(f *issues_model.IssueFilter) *api.IssueFilter {
+	labels := f.LabelIDs
+	if labels == nil {
+		labels = []int64{}
+	}
+	return &api.IssueFilter{
+		ID:          f.ID,
+		Name:        f.Name,
+		Slug:        f.Slug,
+		Labels:      labels,
+		AssigneeID:  f.AssigneeID,
+		MilestoneID: f.MilestoneID,
+		TypeID:      f.TypeID,
+		State:       f.State,
+		Keyword:     f.Keyword,
+		Sort:        f.SortType,
+		IsDefault:   f.IsDefault,
+		IsOrgFilter: f.BelongsToOrg(),
+		IsPersonal:  f.IsPersonal(),
+		Query:       f.QueryString(),
+	}
+}
+
+// ToAPIIssueFilterList converts a list of IssueFilter into API Format
+func ToAPIIssueFilterList(filters []*issues_model.IssueFilter) []*api.IssueFilter {
+	result := make([]*api.IssueFilter, len(filters))
+	for i := range filters {
+		result[i] = ToAPIIssueFilter(filters[i])
+	}
+	return result
+}
+
+// ToAPIIssueField converts IssueField into API Format
+func ToAPIIssueField(f *issues_model.IssueField) *api.IssueField {
+	options := f.Options
+	if options == nil {
+		options = []string{}
+	}
+	return &api.IssueField{
+		ID:          f.ID,
+		Name:        f.Name,
+		Description: f.Description,
+		Type:        string(f.Type),
+		Options:     options,
+		IsOrgField:  f.BelongsToOrg(),
+	}
+}
+
+// ToAPIIssueFieldList converts a list of IssueField into API Format
+func ToAPIIssueFieldList(fields []*issues_model.IssueField) []*api.IssueField {
+	result := make([]*api.IssueField, len(fields))
+	for i := range fields {
+		result[i] = ToAPIIssueField(fields[i])
+	}
+	return result
+}
+
+// ToAPIIssueFieldValues converts the custom field values of an issue into API Format
+func ToAPIIssueFieldValues(ctx context.Context, values []*issues_model.IssueFieldValues) []*api.IssueFieldValue {
+	result := make([]*api.IssueFieldValue, len(values))
+	for i := range values {
+		result[i] = &api.IssueFieldValue{
+			Field:  ToAPIIssueField(values[i].Field),
+			Values: values[i].DisplayValues(ctx),
+		}
+	}
+	return result
+}
+
 // ToLabelTemplate converts Label to API format
 func ToLabelTemplate(label *label.Label) *api.LabelTemplate {
 	result := &api.LabelTemplate{
@@ -286,3 +451,53 @@ func ToLabelTemplateList(labels []*label.Label) []*api.LabelTemplate {
 	}
 	return result
 }
+
+// ToAPIIssueAutomationRule converts IssueAutomationRule into API Format
+func ToAPIIssueAutomationRule(r *issues_model.IssueAutomationRule) *api.IssueAutomationRule {
+	exemptLabels := r.ExemptLabelIDs
+	if exemptLabels == nil {
+		exemptLabels = []int64{}
+	}
+	return &api.IssueAutomationRule{
+		ID:            r.ID,
+		Name:          r.Name,
+		Target:        r.Target,
+		LabelID:       r.LabelID,
+		Days:          r.Days,
+		ExemptLabels:  exemptLabels,
+		Action:        r.Action,
+		ActionLabelID: r.ActionLabelID,
+		Comment:       r.Comment,
+		DryRun:        r.IsDryRun,
+		Created:       r.CreatedUnix.AsTime(),
+		Updated:       r.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToAPIIssueAutomationRuleList converts a list of IssueAutomationRule into API Format
+func ToAPIIssueAutomationRuleList(rules []*issues_model.IssueAutomationRule) []*api.IssueAutomationRule {
+	result := make([]*api.IssueAutomationRule, len(rules))
+	for i := range rules {
+		result[i] = ToAPIIssueAutomationRule(rules[i])
+	}
+	return result
+}
+
+// ToAPIIssueAutomationLogList converts a list of IssueAutomationLog with their issues into API Format
+func ToAPIIssueAutomationLogList(logs issues_model.IssueAutomationLogList) []*api.IssueAutomationLog {
+	result := make([]*api.IssueAutomationLog, len(logs))
+	for i, l := range logs {
+		result[i] = &api.IssueAutomationLog{
+			ID:       l.ID,
+			RuleID:   l.RuleID,
+			RuleName: l.RuleName,
+			Action:   l.Action,
+			DryRun:   l.IsDryRun,
+			Created:  l.CreatedUnix.AsTime(),
+		}
+		if l.Issue != nil {
+			result[i].IssueIndex = l.Issue.Index
+		}
+	}
+	return result
+}
@@ -24,6 +24,7 @@ func ToAPIComment(ctx context.Context, repo *repo_model.Repository, c *issues_mo
 		PRURL:       c.PRURL(ctx),
 		Body:        c.Content,
 		Attachments: ToAPIAttachments(repo, c.Attachments),
+		Internal:    c.IsInternal,
 		Created:     c.CreatedUnix.AsTime(),
 		Updated:     c.UpdatedUnix.AsTime(),
 	}
@@ -86,6 +87,7 @@ func ToTimelineComment(ctx context.Context, repo *repo_model.Repository, c *issu
 		IssueURL: c.IssueURL(ctx),
 		PRURL:    c.PRURL(ctx),
 		Body:     c.Content,
+		Internal: c.IsInternal,
 		Created:  c.CreatedUnix.AsTime(),
 		Updated:  c.UpdatedUnix.AsTime(),
 
@@ -0,0 +1,33 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToMergeQueueEntry converts an entry of a merge queue at the given position to API format
+func ToMergeQueueEntry(ctx context.Context, entry *pull_model.MergeQueueEntry, pr *issues_model.PullRequest, position int, doer *user_model.User) *api.MergeQueueEntry {
+	state := "waiting"
+	if entry.IsTesting() {
+		state = "testing"
+	}
+	return &api.MergeQueueEntry{
+		Position:      position,
+		Index:         pr.Index,
+		BaseBranch:    pr.BaseBranch,
+		State:         state,
+		MergeStyle:    string(entry.MergeStyle),
+		HeadCommitID:  entry.HeadCommitID,
+		BaseCommitID:  entry.BaseCommitID,
+		MergeCommitID: entry.MergeCommitID,
+		Enqueuer:      ToUser(ctx, entry.Doer, doer),
+		Created:       entry.CreatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToOptionTemplate converts an OptionTemplate to API format
+func ToOptionTemplate(tmpl *repo_model.OptionTemplate) *api.OptionTemplate {
+	return &api.OptionTemplate{
+		Type:    string(tmpl.Type),
+		Name:    tmpl.Name,
+		Content: tmpl.Content,
+		Created: tmpl.CreatedUnix.AsTime(),
+		Updated: tmpl.UpdatedUnix.AsTime(),
+	}
+}
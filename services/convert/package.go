@@ -9,6 +9,7 @@ import (
 	"code.gitea.io/gitea/models/packages"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -27,15 +28,17 @@ func ToPackage(ctx context.Context, pd *packages.PackageDescriptor, doer *user_m
 	}
 
 	return &api.Package{
-		ID:         pd.Version.ID,
-		Owner:      ToUser(ctx, pd.Owner, doer),
-		Repository: repo,
-		Creator:    ToUser(ctx, pd.Creator, doer),
-		Type:       string(pd.Package.Type),
-		Name:       pd.Package.Name,
-		Version:    pd.Version.Version,
-		CreatedAt:  pd.Version.CreatedUnix.AsTime(),
-		HTMLURL:    pd.VersionHTMLURL(),
+		ID:           pd.Version.ID,
+		Owner:        ToUser(ctx, pd.Owner, doer),
+		Repository:   repo,
+		Creator:      ToUser(ctx, pd.Creator, doer),
+		Type:         string(pd.Package.Type),
+		Name:         pd.Package.Name,
+		Version:      pd.Version.Version,
+		Immutable:    pd.Package.IsImmutable,
+		PromotedFrom: pd.VersionProperties.GetByName(packages.PropertyPromotedFrom),
+		CreatedAt:    pd.Version.CreatedUnix.AsTime(),
+		HTMLURL:      pd.VersionHTMLURL(),
 	}, nil
 }
 
@@ -51,3 +54,104 @@ func ToPackageFile(pfd *packages.PackageFileDescriptor) *api.PackageFile {
 		HashSHA512: pfd.Blob.HashSHA512,
 	}
 }
+
+// ToPackageCleanupRule converts packages.PackageCleanupRule to api.PackageCleanupRule
+func ToPackageCleanupRule(pcr *packages.PackageCleanupRule) *api.PackageCleanupRule {
+	protectedTags := pcr.ProtectedTags
+	if protectedTags == nil {
+		protectedTags = []string{}
+	}
+	return &api.PackageCleanupRule{
+		ID:                 pcr.ID,
+		Enabled:            pcr.Enabled,
+		Type:               string(pcr.Type),
+		KeepCount:          pcr.KeepCount,
+		KeepPattern:        pcr.KeepPattern,
+		RemoveDays:         pcr.RemoveDays,
+		RemovePattern:      pcr.RemovePattern,
+		MatchFullName:      pcr.MatchFullName,
+		RemoveUntaggedDays: pcr.RemoveUntaggedDays,
+		ProtectedTags:      protectedTags,
+		Created:            pcr.CreatedUnix.AsTime(),
+		Updated:            pcr.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToPackageUpstream converts packages.PackageUpstream to api.PackageUpstream
+func ToPackageUpstream(pu *packages.PackageUpstream) *api.PackageUpstream {
+	return &api.PackageUpstream{
+		Type:    string(pu.Type),
+		URL:     pu.URL,
+		Created: pu.CreatedUnix.AsTime(),
+		Updated: pu.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToPackageAttestation converts packages.PackageAttestation to api.PackageAttestation
+func ToPackageAttestation(pa *packages.PackageAttestation) *api.PackageAttestation {
+	return &api.PackageAttestation{
+		ID:            pa.ID,
+		PredicateType: pa.PredicateType,
+		MediaType:     pa.MediaType,
+		Bundle:        pa.Content,
+		Created:       pa.CreatedUnix.AsTime(),
+	}
+}
+
+// ToPackageAudits converts the entries of the audit trail to api.PackageAudit
+func ToPackageAudits(ctx context.Context, pas []*packages.PackageAudit, doer *user_model.User) ([]*api.PackageAudit, error) {
+	doerIDs := make(container.Set[int64])
+	for _, pa := range pas {
+		if pa.DoerID != 0 {
+			doerIDs.Add(pa.DoerID)
+		}
+	}
+	users, err := user_model.GetUsersByIDs(ctx, doerIDs.Values())
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[int64]*user_model.User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	apiAudits := make([]*api.PackageAudit, 0, len(pas))
+	for _, pa := range pas {
+		apiAudit := &api.PackageAudit{
+			ID:      pa.ID,
+			Action:  string(pa.Action),
+			Type:    string(pa.Type),
+			Name:    pa.Name,
+			Version: pa.Version,
+			Created: pa.CreatedUnix.AsTime(),
+		}
+		if pa.DoerID != 0 {
+			u, ok := usersByID[pa.DoerID]
+			if !ok {
+				u = user_model.NewGhostUser()
+			}
+			apiAudit.Doer = ToUser(ctx, u, doer)
+		}
+		apiAudits = append(apiAudits, apiAudit)
+	}
+	return apiAudits, nil
+}
+
+// ToPackageUsage converts the storage used by the packages of an owner to api.PackageUsage
+func ToPackageUsage(usages []*packages.TypeUsage, totalSizeLimit int64, isOwnerLimit bool) *api.PackageUsage {
+	apiUsage := &api.PackageUsage{
+		TotalSizeLimit: totalSizeLimit,
+		IsOwnerLimit:   isOwnerLimit,
+		Types:          make([]*api.PackageTypeUsage, 0, len(usages)),
+	}
+	for _, u := range usages {
+		apiUsage.TotalSize += u.Size
+		apiUsage.Types = append(apiUsage.Types, &api.PackageTypeUsage{
+			Type:         string(u.Type),
+			PackageCount: u.PackageCount,
+			VersionCount: u.VersionCount,
+			Size:         u.Size,
+		})
+	}
+	return apiUsage
+}
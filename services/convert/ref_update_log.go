@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	git_model "code.gitea.io/gitea/models/git"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToBranchUpdate converts a RefUpdateLog to API format, its pusher must be loaded
+func ToBranchUpdate(ctx context.Context, refLog *git_model.RefUpdateLog, doer *user_model.User) *api.BranchUpdate {
+	return &api.BranchUpdate{
+		ID:          refLog.ID,
+		OldCommitID: refLog.OldCommitID,
+		NewCommitID: refLog.NewCommitID,
+		Pusher:      ToUser(ctx, refLog.Pusher, doer),
+		Created:     refLog.CreatedUnix.AsTime(),
+	}
+}
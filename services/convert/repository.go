@@ -66,6 +66,11 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, permissionInR
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			CloseKeywords:                    config.CloseKeywords,
+			ReopenKeywords:                   config.ReopenKeywords,
+			KeywordLanguages:                 config.KeywordLanguages,
+			CloseOnlyOnDefaultBranch:         config.CloseOnlyOnDefaultBranch,
+			CloseOnlyInSameMilestone:         config.CloseOnlyInSameMilestone,
 		}
 	} else if unit, err := repo.GetUnit(ctx, unit_model.TypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
@@ -99,6 +104,11 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, permissionInR
 	defaultDeleteBranchAfterMerge := false
 	defaultMergeStyle := repo_model.MergeStyleMerge
 	defaultAllowMaintainerEdit := false
+	var mergeStrategy string
+	var mergeStrategyOptions []string
+	enableMergeQueue := false
+	retargetOnBaseDeletion := false
+	rebaseOnRetarget := false
 	if unit, err := repo.GetUnit(ctx, unit_model.TypePullRequests); err == nil {
 		config := unit.PullRequestsConfig()
 		hasPullRequests = true
@@ -112,6 +122,11 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, permissionInR
 		defaultDeleteBranchAfterMerge = config.DefaultDeleteBranchAfterMerge
 		defaultMergeStyle = config.GetDefaultMergeStyle()
 		defaultAllowMaintainerEdit = config.DefaultAllowMaintainerEdit
+		mergeStrategy = config.MergeStrategy
+		mergeStrategyOptions = config.MergeStrategyOptions
+		enableMergeQueue = config.EnableMergeQueue
+		retargetOnBaseDeletion = config.RetargetOnBaseDeletion
+		rebaseOnRetarget = config.RebaseOnRetarget
 	}
 	hasProjects := false
 	projectsMode := repo_model.ProjectsModeAll
@@ -231,6 +246,11 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, permissionInR
 		DefaultDeleteBranchAfterMerge: defaultDeleteBranchAfterMerge,
 		DefaultMergeStyle:             string(defaultMergeStyle),
 		DefaultAllowMaintainerEdit:    defaultAllowMaintainerEdit,
+		MergeStrategy:                 mergeStrategy,
+		MergeStrategyOptions:          mergeStrategyOptions,
+		EnableMergeQueue:              enableMergeQueue,
+		RetargetOnBaseDeletion:        retargetOnBaseDeletion,
+		RebaseOnRetarget:              rebaseOnRetarget,
 		AvatarURL:                     repo.AvatarLink(ctx),
 		Internal:                      !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
 		MirrorInterval:                mirrorInterval,
@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+// ToStagingSession converts a RepoStagingSession and its staged files to API format
+func ToStagingSession(session *repo_model.RepoStagingSession, stagedFiles []*files_service.StagedFile) *api.StagingSession {
+	files := make([]*api.StagedFile, 0, len(stagedFiles))
+	for _, f := range stagedFiles {
+		files = append(files, ToStagedFile(f))
+	}
+	return &api.StagingSession{
+		ID:         session.UUID,
+		Branch:     session.Branch,
+		BaseCommit: session.BaseCommitID,
+		Files:      files,
+		Created:    session.CreatedUnix.AsTime(),
+		Updated:    session.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToStagedFile converts a StagedFile to API format
+func ToStagedFile(f *files_service.StagedFile) *api.StagedFile {
+	return &api.StagedFile{
+		Path: f.TreePath,
+		Size: f.Size,
+	}
+}
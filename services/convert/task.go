@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"time"
+
+	admin_model "code.gitea.io/gitea/models/admin"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// ToLFSMigrateTask converts a task migrating files to LFS to API format
+func ToLFSMigrateTask(ctx context.Context, lang translation.Locale, task *admin_model.Task, backupRefPrefix string) (*api.LFSMigrateTask, error) {
+	var opts api.MigrateToLFSOption
+	if err := json.Unmarshal([]byte(task.PayloadContent), &opts); err != nil {
+		return nil, err
+	}
+	if err := task.LoadDoer(ctx); err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		task.Doer = user_model.NewGhostUser()
+	}
+
+	var started, finished *time.Time
+	if task.StartTime > 0 {
+		started = task.StartTime.AsTimePtr()
+	}
+	if task.EndTime > 0 {
+		finished = task.EndTime.AsTimePtr()
+	}
+
+	return &api.LFSMigrateTask{
+		ID:              task.ID,
+		Status:          task.Status.Name(),
+		Message:         task.LocaleMessage(lang),
+		Patterns:        opts.Patterns,
+		Branches:        opts.Branches,
+		BackupRefPrefix: backupRefPrefix,
+		Doer:            ToUser(ctx, task.Doer, nil),
+		Created:         task.Created.AsTime(),
+		Started:         started,
+		Finished:        finished,
+	}, nil
+}
@@ -9,17 +9,21 @@ import (
 
 	"code.gitea.io/gitea/models"
 	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/models/webhook"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/auth"
+	"code.gitea.io/gitea/services/automerge"
+	issue_service "code.gitea.io/gitea/services/issue"
 	"code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+	files_service "code.gitea.io/gitea/services/repository/files"
 )
 
 func registerUpdateMirrorTask() {
@@ -117,6 +121,50 @@ func registerDeletedBranchesCleanup() {
 	})
 }
 
+func registerStagingSessionsCleanup() {
+	RegisterTaskFatal("staging_sessions_cleanup", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return repo_model.DeleteExpiredRepoStagingSessions(ctx, realConfig.OlderThan)
+	})
+}
+
+func registerDeferredCommits() {
+	RegisterTaskFatal("deferred_commits", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return files_service.QueueDueDeferredCommits(ctx)
+	})
+}
+
+func registerDueAutoMerges() {
+	RegisterTaskFatal("due_auto_merges", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return automerge.QueueDueAutoMerges(ctx)
+	})
+}
+
+func registerIssueAutomations() {
+	RegisterTaskFatal("issue_automations", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return issue_service.RunIssueAutomations(ctx)
+	})
+}
+
 func registerUpdateMigrationPosterID() {
 	RegisterTaskFatal("update_migration_poster_id", &BaseConfig{
 		Enabled:    true,
@@ -171,6 +219,16 @@ func registerActionsCleanup() {
 	})
 }
 
+func registerActionsCacheEviction() {
+	RegisterTaskFatal("evict_actions_caches", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return actions.EvictCaches(ctx)
+	})
+}
+
 func initBasicTasks() {
 	if setting.Mirror.Enabled {
 		registerUpdateMirrorTask()
@@ -180,6 +238,10 @@ func initBasicTasks() {
 	registerArchiveCleanup()
 	registerSyncExternalUsers()
 	registerDeletedBranchesCleanup()
+	registerStagingSessionsCleanup()
+	registerDeferredCommits()
+	registerDueAutoMerges()
+	registerIssueAutomations()
 	if !setting.Repository.DisableMigrations {
 		registerUpdateMigrationPosterID()
 	}
@@ -189,5 +251,6 @@ func initBasicTasks() {
 	}
 	if setting.Actions.Enabled {
 		registerActionsCleanup()
+		registerActionsCacheEviction()
 	}
 }
@@ -8,13 +8,17 @@ import (
 	"time"
 
 	activities_model "code.gitea.io/gitea/models/activities"
+	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/system"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/updatechecker"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
+	attachment_service "code.gitea.io/gitea/services/attachment"
+	dependency_service "code.gitea.io/gitea/services/dependency"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
 	user_service "code.gitea.io/gitea/services/user"
@@ -223,6 +227,91 @@ func registerRebuildIssueIndexer() {
 	})
 }
 
+func registerUpdateDependencyGraphs() {
+	RegisterTaskFatal("update_dependency_graphs", &BaseConfig{
+		Enabled:    false,
+		RunAtStart: false,
+		Schedule:   "@annually",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return dependency_service.UpdateAllRepoDependencies(ctx)
+	})
+}
+
+func registerDeleteOldCommitStatuses() {
+	RegisterTaskFatal("delete_old_commit_statuses", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 168h",
+		},
+		OlderThan: 180 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		deleted, err := git_model.DeleteOldCommitStatuses(ctx, olderThanConfig.OlderThan)
+		if err != nil {
+			return err
+		}
+		log.Info("Deleted %d old commit statuses", deleted)
+		return nil
+	})
+}
+
+func registerVerifyExternalReleaseAssets() {
+	RegisterTaskFatal("verify_external_release_assets", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return attachment_service.VerifyExternalAttachments(ctx)
+	})
+}
+
+func registerUpdateRepositorySizes() {
+	RegisterTaskFatal("update_repository_sizes", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return repo_service.UpdateRepositorySizes(ctx)
+	})
+}
+
+func registerGenerateRepositoryBundles() {
+	type GenerateBundlesConfig struct {
+		BaseConfig
+		MinRepoSize int64
+	}
+	RegisterTaskFatal("generate_repo_bundles", &GenerateBundlesConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		MinRepoSize: 100 * 1024 * 1024,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		generateBundlesConfig := config.(*GenerateBundlesConfig)
+		return repo_service.GenerateRepositoryBundles(ctx, generateBundlesConfig.MinRepoSize)
+	})
+}
+
+func registerMaintainRepositories() {
+	type MaintainRepositoriesConfig struct {
+		BaseConfig
+		MinRepoSize int64
+	}
+	RegisterTaskFatal("maintain_repositories", &MaintainRepositoriesConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		MinRepoSize: 10 * 1024 * 1024,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		maintainRepositoriesConfig := config.(*MaintainRepositoriesConfig)
+		return repo_service.MaintainRepositories(ctx, maintainRepositoriesConfig.MinRepoSize)
+	})
+}
+
 func initExtendedTasks() {
 	registerDeleteInactiveUsers()
 	registerDeleteRepositoryArchives()
@@ -238,4 +327,10 @@ func initExtendedTasks() {
 	registerDeleteOldSystemNotices()
 	registerGCLFS()
 	registerRebuildIssueIndexer()
+	registerUpdateDependencyGraphs()
+	registerVerifyExternalReleaseAssets()
+	registerDeleteOldCommitStatuses()
+	registerUpdateRepositorySizes()
+	registerGenerateRepositoryBundles()
+	registerMaintainRepositories()
 }
@@ -0,0 +1,152 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/dependency"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	notify_service "code.gitea.io/gitea/services/notify"
+
+	"xorm.io/builder"
+)
+
+// maxManifests is the maximum number of manifest files parsed in a single repository
+const maxManifests = 100
+
+var dependencyUpdateQueue *queue.WorkerPoolQueue[int64]
+
+// Init registers the notifier and starts the queue which updates the dependency graph of repositories
+func Init() error {
+	notify_service.RegisterNotifier(NewNotifier())
+
+	dependencyUpdateQueue = queue.CreateUniqueQueue(graceful.GetManager().ShutdownContext(), "repo_dependency_update", handler)
+	if dependencyUpdateQueue == nil {
+		return fmt.Errorf("unable to create repo_dependency_update queue")
+	}
+	go graceful.GetManager().RunWithCancel(dependencyUpdateQueue)
+	return nil
+}
+
+func handler(items ...int64) []int64 {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, repoID := range items {
+		repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+		if err != nil {
+			if !repo_model.IsErrRepoNotExist(err) {
+				log.Error("GetRepositoryByID[%d]: %v", repoID, err)
+			}
+			continue
+		}
+		if err := UpdateRepoDependencies(ctx, repo); err != nil {
+			log.Error("UpdateRepoDependencies[%-v]: %v", repo, err)
+		}
+	}
+	return nil
+}
+
+// AddRepoToQueue schedules an update of the dependency graph of a repository
+func AddRepoToQueue(repo *repo_model.Repository) {
+	if err := dependencyUpdateQueue.Push(repo.ID); err != nil {
+		log.Error("Unable to push repo %d to the repo_dependency_update queue: %v", repo.ID, err)
+	}
+}
+
+// UpdateAllRepoDependencies schedules an update of the dependency graph of all the non-empty repositories,
+// it fills the graph of the repositories which haven't been pushed to since it exists
+func UpdateAllRepoDependencies(ctx context.Context) error {
+	log.Trace("Doing: UpdateAllRepoDependencies")
+
+	if err := db.Iterate(
+		ctx,
+		builder.Eq{"is_empty": false},
+		func(ctx context.Context, repo *repo_model.Repository) error {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before updating the dependency graph of %s", repo.FullName())
+			default:
+			}
+			AddRepoToQueue(repo)
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: UpdateAllRepoDependencies")
+	return nil
+}
+
+// UpdateRepoDependencies parses the manifests on the default branch of a repository and stores its dependencies
+func UpdateRepoDependencies(ctx context.Context, repo *repo_model.Repository) error {
+	if repo.IsEmpty || repo.IsBroken() || repo.DefaultBranch == "" {
+		return repo_model.ReplaceRepoDependencies(ctx, repo.ID, nil, nil)
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return err
+	}
+	entries, err := commit.Tree.ListEntriesRecursiveWithSize()
+	if err != nil {
+		return err
+	}
+
+	manifests := make([]*repo_model.RepoManifest, 0, 5)
+	deps := make([]*repo_model.RepoDependency, 0, 50)
+	for _, entry := range entries {
+		if len(manifests) >= maxManifests {
+			log.Debug("Repository %-v has more than %d manifests, the remaining ones are ignored", repo, maxManifests)
+			break
+		}
+		filePath := entry.Name()
+		if !entry.IsRegular() || !dependency.IsManifest(filePath) || dependency.IsIgnoredDir(path.Dir(filePath)) {
+			continue
+		}
+		if entry.Size() > dependency.MaxManifestSize {
+			continue
+		}
+		content, err := entry.Blob().GetBlobContent(dependency.MaxManifestSize)
+		if err != nil {
+			return err
+		}
+		m, err := dependency.ParseManifest(filePath, []byte(content))
+		if err != nil {
+			// broken manifests must not prevent the others from being indexed
+			log.Debug("Unable to parse manifest in %-v: %v", repo, err)
+			continue
+		}
+		manifests = append(manifests, &repo_model.RepoManifest{
+			CommitID:  commit.ID.String(),
+			Path:      m.Path,
+			Ecosystem: m.Ecosystem,
+			Name:      m.Name,
+		})
+		for _, d := range m.Dependencies {
+			deps = append(deps, &repo_model.RepoDependency{
+				Manifest:  m.Path,
+				Ecosystem: m.Ecosystem,
+				Name:      d.Name,
+				Version:   d.Version,
+				IsDev:     d.IsDev,
+			})
+		}
+	}
+
+	return repo_model.ReplaceRepoDependencies(ctx, repo.ID, manifests, deps)
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package dependency
+
+import (
+	"context"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/repository"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+type dependencyNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &dependencyNotifier{}
+
+// NewNotifier create a new dependencyNotifier notifier
+func NewNotifier() notify_service.Notifier {
+	return &dependencyNotifier{}
+}
+
+func (n *dependencyNotifier) PushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if opts.RefFullName.IsBranch() && opts.RefFullName.BranchName() == repo.DefaultBranch {
+		AddRepoToQueue(repo)
+	}
+}
+
+func (n *dependencyNotifier) SyncPushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	n.PushCommits(ctx, pusher, repo, opts, commits)
+}
+
+func (n *dependencyNotifier) ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
+	AddRepoToQueue(repo)
+}
+
+func (n *dependencyNotifier) MigrateRepository(ctx context.Context, doer, u *user_model.User, repo *repo_model.Repository) {
+	AddRepoToQueue(repo)
+}
@@ -99,6 +99,11 @@ func (a *actionNotifier) IssueChangeStatus(ctx context.Context, doer *user_model
 func (a *actionNotifier) CreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository,
 	issue *issues_model.Issue, comment *issues_model.Comment, mentions []*user_model.User,
 ) {
+	// the feeds are visible to all the readers of the repository, the internal comments aren't
+	if comment.IsInternal {
+		return
+	}
+
 	act := &activities_model.Action{
 		ActUserID: doer.ID,
 		ActUser:   doer,
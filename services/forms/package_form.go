@@ -13,15 +13,17 @@ import (
 )
 
 type PackageCleanupRuleForm struct {
-	ID            int64
-	Enabled       bool
-	Type          string `binding:"Required;In(alpine,cargo,chef,composer,conan,conda,container,cran,debian,generic,go,helm,maven,npm,nuget,pub,pypi,rpm,rubygems,swift,vagrant)"`
-	KeepCount     int    `binding:"In(0,1,5,10,25,50,100)"`
-	KeepPattern   string `binding:"RegexPattern"`
-	RemoveDays    int    `binding:"In(0,7,14,30,60,90,180)"`
-	RemovePattern string `binding:"RegexPattern"`
-	MatchFullName bool
-	Action        string `binding:"Required;In(save,remove)"`
+	ID                 int64
+	Enabled            bool
+	Type               string `binding:"Required;In(alpine,cargo,chef,composer,conan,conda,container,cran,debian,generic,go,helm,julia,maven,npm,nuget,pub,pypi,rpm,rubygems,swift,terraform,vagrant)"`
+	KeepCount          int    `binding:"In(0,1,5,10,25,50,100)"`
+	KeepPattern        string `binding:"RegexPattern"`
+	RemoveDays         int    `binding:"In(0,7,14,30,60,90,180)"`
+	RemovePattern      string `binding:"RegexPattern"`
+	MatchFullName      bool
+	RemoveUntaggedDays int `binding:"In(0,1,7,14,30,60,90,180)"`
+	ProtectedTags      string
+	Action             string `binding:"Required;In(save,remove)"`
 }
 
 func (f *PackageCleanupRuleForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
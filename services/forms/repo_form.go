@@ -143,6 +143,11 @@ type RepoSettingForm struct {
 	TrackerIssueStyle                     string
 	ExternalTrackerRegexpPattern          string
 	EnableCloseIssuesViaCommitInAnyBranch bool
+	IssueCloseKeywords                    string
+	IssueReopenKeywords                   string
+	IssueKeywordLanguages                 []string
+	IssueCloseOnlyOnDefaultBranch         bool
+	IssueCloseOnlyInSameMilestone         bool
 	EnableProjects                        bool
 	ProjectsMode                          string
 	EnableReleases                        bool
@@ -161,6 +166,11 @@ type RepoSettingForm struct {
 	PullsAllowRebaseUpdate                bool
 	DefaultDeleteBranchAfterMerge         bool
 	DefaultAllowMaintainerEdit            bool
+	PullsMergeStrategy                    string
+	PullsMergeStrategyOptions             string
+	PullsEnableMergeQueue                 bool
+	PullsRetargetOnBaseDeletion           bool
+	PullsRebaseOnRetarget                 bool
 	EnableTimetracker                     bool
 	AllowOnlyContributorsToTrackTime      bool
 	EnableIssueDependencies               bool
@@ -207,9 +217,11 @@ type ProtectBranchForm struct {
 	BlockOnRejectedReviews        bool
 	BlockOnOfficialReviewRequests bool
 	BlockOnOutdatedBranch         bool
+	RequireCodeOwnerApproval      bool
 	DismissStaleApprovals         bool
 	IgnoreStaleApprovals          bool
 	RequireSignedCommits          bool
+	RequireSignoff                bool
 	ProtectedFilePatterns         string
 	UnprotectedFilePatterns       string
 }
@@ -253,6 +265,7 @@ type WebhookForm struct {
 	Package                  bool
 	Active                   bool
 	BranchFilter             string `binding:"GlobPattern"`
+	PackageTypeFilter        string `binding:"GlobPattern"`
 	AuthorizationHeader      string
 }
 
@@ -443,6 +456,7 @@ type CreateIssueForm struct {
 	MilestoneID         int64
 	ProjectID           int64
 	AssigneeID          int64
+	IssueTypeID         int64
 	Content             string
 	Files               []string
 	AllowMaintainerEdit bool
@@ -456,9 +470,10 @@ func (f *CreateIssueForm) Validate(req *http.Request, errs binding.Errors) bindi
 
 // CreateCommentForm form for creating comment
 type CreateCommentForm struct {
-	Content string
-	Status  string `binding:"OmitEmpty;In(reopen,close)"`
-	Files   []string
+	Content    string
+	Status     string `binding:"OmitEmpty;In(reopen,close)"`
+	Files      []string
+	IsInternal bool
 }
 
 // Validate validates the fields
@@ -573,6 +588,16 @@ type MergePullRequestForm struct {
 	ForceMerge             bool   `json:"force_merge,omitempty"`
 	MergeWhenChecksSucceed bool   `json:"merge_when_checks_succeed,omitempty"`
 	DeleteBranchAfterMerge bool   `json:"delete_branch_after_merge,omitempty"`
+	// minimum number of official approvals required by the scheduled auto merge
+	AutoMergeRequiredApprovals int64 `json:"auto_merge_required_approvals,omitempty"`
+	// whether the scheduled auto merge requires that no official review requests changes
+	AutoMergeNoChangesRequested bool `json:"auto_merge_no_changes_requested,omitempty"`
+	// time in RFC 3339 format before which the scheduled auto merge doesn't happen
+	AutoMergeAfter string `json:"auto_merge_after,omitempty"`
+	// index of a pull request of the repository which has to be merged before the scheduled auto merge
+	AutoMergeAfterPull int64 `json:"auto_merge_after_pull,omitempty"`
+	// options passed to the merge strategy with `--strategy-option`, they replace the options configured for the repository
+	MergeStrategyOptions []string `json:"merge_strategy_options,omitempty"`
 }
 
 // Validate validates the fields
@@ -581,6 +606,22 @@ func (f *MergePullRequestForm) Validate(req *http.Request, errs binding.Errors)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ResolvePullRequestConflictsForm form for resolving the conflicts of a pull request,
+// Contents holds the resolved content of the file at the same position in Paths
+type ResolvePullRequestConflictsForm struct {
+	HeadCommitID string
+	BaseCommitID string
+	Paths        []string
+	Contents     []string
+	Message      string
+}
+
+// Validate validates the fields
+func (f *ResolvePullRequestConflictsForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // CodeCommentForm form for adding code comments for PRs
 type CodeCommentForm struct {
 	Origin         string `binding:"Required;In(timeline,diff)"`
@@ -720,14 +761,16 @@ func (f *NewWikiForm) Validate(req *http.Request, errs binding.Errors) binding.E
 
 // EditRepoFileForm form for changing repository file
 type EditRepoFileForm struct {
-	TreePath      string `binding:"Required;MaxSize(500)"`
-	Content       string
-	CommitSummary string `binding:"MaxSize(100)"`
-	CommitMessage string
-	CommitChoice  string `binding:"Required;MaxSize(50)"`
-	NewBranchName string `binding:"GitRefName;MaxSize(100)"`
-	LastCommit    string
-	Signoff       bool
+	TreePath          string `binding:"Required;MaxSize(500)"`
+	Content           string
+	CommitSummary     string `binding:"MaxSize(100)"`
+	CommitMessage     string
+	CommitChoice      string `binding:"Required;MaxSize(50)"`
+	NewBranchName     string `binding:"GitRefName;MaxSize(100)"`
+	LastCommit        string
+	Signoff           bool
+	IsSymlink         bool
+	ApplyTemplateVars bool
 }
 
 // Validate validates the fields
@@ -52,6 +52,7 @@ var hiddenCommentTypeGroups = hiddenCommentTypeGroupsType{
 	"dependency": {
 		/*19*/ issues_model.CommentTypeAddDependency,
 		/*20*/ issues_model.CommentTypeRemoveDependency,
+		/*40*/ issues_model.CommentTypeDependencyClosed,
 	},
 	"lock": {
 		/*23*/ issues_model.CommentTypeLock,
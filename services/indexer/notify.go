@@ -72,6 +72,9 @@ func (r *indexerNotifier) DeleteRepository(ctx context.Context, doer *user_model
 	if setting.Indexer.RepoIndexerEnabled {
 		code_indexer.UpdateRepoIndexer(repo)
 	}
+	if setting.Indexer.WikiIndexerEnabled {
+		code_indexer.UpdateWikiIndexer(repo)
+	}
 }
 
 func (r *indexerNotifier) MigrateRepository(ctx context.Context, doer, u *user_model.User, repo *repo_model.Repository) {
@@ -79,6 +82,9 @@ func (r *indexerNotifier) MigrateRepository(ctx context.Context, doer, u *user_m
 	if setting.Indexer.RepoIndexerEnabled && !repo.IsEmpty {
 		code_indexer.UpdateRepoIndexer(repo)
 	}
+	if setting.Indexer.WikiIndexerEnabled {
+		code_indexer.UpdateWikiIndexer(repo)
+	}
 	if err := stats_indexer.UpdateRepoIndexer(repo); err != nil {
 		log.Error("stats_indexer.UpdateRepoIndexer(%d) failed: %v", repo.ID, err)
 	}
@@ -143,6 +149,18 @@ func (r *indexerNotifier) IssueChangeMilestone(ctx context.Context, doer *user_m
 	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
 }
 
+func (r *indexerNotifier) IssueChangeType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTypeID int64) {
+	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+}
+
+func (r *indexerNotifier) IssueChangeFields(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) {
+	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+}
+
+func (r *indexerNotifier) IssueChangeVote(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, voted bool) {
+	issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+}
+
 func (r *indexerNotifier) IssueChangeLabels(ctx context.Context, doer *user_model.User, issue *issues_model.Issue,
 	addedLabels, removedLabels []*issues_model.Label,
 ) {
@@ -168,3 +186,21 @@ func (r *indexerNotifier) AutoMergePullRequest(ctx context.Context, doer *user_m
 	}
 	issue_indexer.UpdateIssueIndexer(ctx, pr.Issue.ID)
 }
+
+func (r *indexerNotifier) NewWikiPage(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, page, comment string) {
+	if setting.Indexer.WikiIndexerEnabled {
+		code_indexer.UpdateWikiIndexer(repo)
+	}
+}
+
+func (r *indexerNotifier) EditWikiPage(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, page, comment string) {
+	if setting.Indexer.WikiIndexerEnabled {
+		code_indexer.UpdateWikiIndexer(repo)
+	}
+}
+
+func (r *indexerNotifier) DeleteWikiPage(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, page string) {
+	if setting.Indexer.WikiIndexerEnabled {
+		code_indexer.UpdateWikiIndexer(repo)
+	}
+}
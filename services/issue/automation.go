@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// issueAutomationBatchSize is the maximum number of issues a rule takes an action on in a run,
+// the other matching issues are left to the next runs
+const issueAutomationBatchSize = 50
+
+// RunIssueAutomations applies the rules of all the repositories to their issues, it's run by a cron task
+func RunIssueAutomations(ctx context.Context) error {
+	return db.Iterate(ctx, nil, func(ctx context.Context, rule *issues_model.IssueAutomationRule) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before applying the issue automation rule %d", rule.ID)
+		default:
+		}
+		if _, err := RunIssueAutomationRule(ctx, rule); err != nil {
+			log.Error("RunIssueAutomationRule %d: %v", rule.ID, err)
+		}
+		return nil
+	})
+}
+
+// RunIssueAutomationRule takes the action of a rule on the issues it matches and records it in the logs,
+// a dry-run rule only records the actions. It returns the logs of the run.
+func RunIssueAutomationRule(ctx context.Context, rule *issues_model.IssueAutomationRule) (issues_model.IssueAutomationLogList, error) {
+	repo, err := repo_model.GetRepositoryByID(ctx, rule.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	if repo.IsArchived || (!repo.UnitEnabled(ctx, unit.TypeIssues) && !repo.UnitEnabled(ctx, unit.TypePullRequests)) {
+		return nil, nil
+	}
+
+	issues, err := rule.FindMatchingIssues(ctx, timeutil.TimeStampNow(), issueAutomationBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var label *issues_model.Label
+	if rule.Action == issues_model.IssueAutomationActionLabel && !rule.IsDryRun && len(issues) > 0 {
+		if label, err = issues_model.GetLabelByID(ctx, rule.ActionLabelID); err != nil {
+			return nil, fmt.Errorf("GetLabelByID: %w", err)
+		}
+	}
+
+	doer := user_model.NewActionsUser()
+	logs := make(issues_model.IssueAutomationLogList, 0, len(issues))
+	for _, issue := range issues {
+		issue.Repo = repo
+		if !rule.IsDryRun {
+			if err := applyIssueAutomationRule(ctx, doer, rule, issue, label); err != nil {
+				log.Error("Unable to apply the issue automation rule %d to issue[%d]#%d: %v", rule.ID, issue.ID, issue.Index, err)
+				continue
+			}
+		}
+		l := &issues_model.IssueAutomationLog{
+			RepoID:   rule.RepoID,
+			RuleID:   rule.ID,
+			IssueID:  issue.ID,
+			RuleName: rule.Name,
+			Action:   rule.Action,
+			IsDryRun: rule.IsDryRun,
+			Issue:    issue,
+		}
+		if err := db.Insert(ctx, l); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+func applyIssueAutomationRule(ctx context.Context, doer *user_model.User, rule *issues_model.IssueAutomationRule, issue *issues_model.Issue, label *issues_model.Label) error {
+	if rule.Comment != "" {
+		if _, err := CreateIssueComment(ctx, doer, issue.Repo, issue, rule.Comment, nil); err != nil {
+			return err
+		}
+	}
+	switch rule.Action {
+	case issues_model.IssueAutomationActionLabel:
+		return AddLabel(ctx, issue, doer, label)
+	case issues_model.IssueAutomationActionClose:
+		return ChangeStatus(ctx, issue, doer, "", true)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunIssueAutomationRule(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	rule := &issues_model.IssueAutomationRule{RepoID: 1, Name: "Stale", Days: 30, Action: "close", Comment: "Closed for inactivity", IsDryRun: true}
+	assert.NoError(t, issues_model.NewIssueAutomationRule(db.DefaultContext, rule))
+
+	// a dry run only records the actions
+	logs, err := RunIssueAutomationRule(db.DefaultContext, rule)
+	assert.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.EqualValues(t, 1, logs[0].IssueID)
+		assert.True(t, logs[0].IsDryRun)
+	}
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1, IsClosed: false})
+	unittest.AssertNotExistsBean(t, &issues_model.Comment{IssueID: 1, Content: "Closed for inactivity"})
+
+	// the issue isn't matched again until it's updated
+	logs, err = RunIssueAutomationRule(db.DefaultContext, rule)
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+
+	rule = &issues_model.IssueAutomationRule{RepoID: 1, Name: "Stale pulls", Target: "pulls", Days: 30, Action: "close", Comment: "Closed for inactivity"}
+	assert.NoError(t, issues_model.NewIssueAutomationRule(db.DefaultContext, rule))
+	logs, err = RunIssueAutomationRule(db.DefaultContext, rule)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 3)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2, IsClosed: true})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: 2, Content: "Closed for inactivity"})
+	unittest.AssertCount(t, &issues_model.IssueAutomationLog{RuleID: rule.ID, IsDryRun: false}, 3)
+}
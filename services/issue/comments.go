@@ -13,6 +13,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 	notify_service "code.gitea.io/gitea/services/notify"
 )
 
@@ -53,6 +54,23 @@ func CreateRefComment(ctx context.Context, doer *user_model.User, repo *repo_mod
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content string, attachments []string) (*issues_model.Comment, error) {
+	return createIssueComment(ctx, doer, repo, issue, content, attachments, false)
+}
+
+// CreateInternalIssueComment creates a plain issue comment only visible to the users who can write
+// the issues or the pull requests of the repository.
+func CreateInternalIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content string, attachments []string) (*issues_model.Comment, error) {
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanWriteIssuesOrPulls(issue.IsPull) {
+		return nil, util.NewPermissionDeniedErrorf("only the users who can write the issue can post internal comments")
+	}
+	return createIssueComment(ctx, doer, repo, issue, content, attachments, true)
+}
+
+func createIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content string, attachments []string, isInternal bool) (*issues_model.Comment, error) {
 	if user_model.IsUserBlockedBy(ctx, doer, issue.PosterID, repo.OwnerID) {
 		if isAdmin, _ := access_model.IsUserRepoAdmin(ctx, repo, doer); !isAdmin {
 			return nil, user_model.ErrBlockedUser
@@ -66,6 +84,7 @@ func CreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_m
 		Issue:       issue,
 		Content:     content,
 		Attachments: attachments,
+		IsInternal:  isInternal,
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateInternalIssueComment(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	writer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	reader := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+	_, err := CreateInternalIssueComment(db.DefaultContext, reader, repo, issue, "triage notes", nil)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+
+	// the internal comments don't reference other issues
+	comment, err := CreateInternalIssueComment(db.DefaultContext, writer, repo, issue, "triage notes, like #2", nil)
+	assert.NoError(t, err)
+	assert.True(t, comment.IsInternal)
+	unittest.AssertNotExistsBean(t, &issues_model.Comment{Type: issues_model.CommentTypeCommentRef, RefCommentID: comment.ID})
+
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	assert.NoError(t, issue.LoadPublicComments(db.DefaultContext))
+	assert.NotEmpty(t, issue.Comments)
+	for _, c := range issue.Comments {
+		assert.NotEqual(t, comment.ID, c.ID)
+	}
+}
@@ -104,6 +104,7 @@ func getIssueFromRef(ctx context.Context, repo *repo_model.Repository, index int
 
 // UpdateIssuesCommit checks if issues are manipulated by commit message.
 func UpdateIssuesCommit(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, commits []*repository.PushCommit, branchName string) error {
+	keywords := repo.IssueKeywords(ctx)
 	// Commits are appended in the reverse order.
 	for i := len(commits) - 1; i >= 0; i-- {
 		c := commits[i]
@@ -117,7 +118,7 @@ func UpdateIssuesCommit(ctx context.Context, doer *user_model.User, repo *repo_m
 		var refRepo *repo_model.Repository
 		var refIssue *issues_model.Issue
 		var err error
-		for _, ref := range references.FindAllIssueReferences(c.Message) {
+		for _, ref := range keywords.FindAllIssueReferences(c.Message) {
 			// issue is from another repo
 			if len(ref.Owner) > 0 && len(ref.Name) > 0 {
 				refRepo, err = repo_model.GetRepositoryByOwnerAndName(ctx, ref.Owner, ref.Name)
@@ -188,6 +188,15 @@ func DeleteIssue(ctx context.Context, doer *user_model.User, gitRepo *git.Reposi
 		if err := gitRepo.RemoveReference(fmt.Sprintf("%s%d/head", git.PullPrefix, issue.PullRequest.Index)); err != nil {
 			return err
 		}
+		pushRefs, err := gitRepo.GetRefsFiltered(fmt.Sprintf("%s%d/pushes/", git.PullPrefix, issue.PullRequest.Index))
+		if err != nil {
+			return err
+		}
+		for _, ref := range pushRefs {
+			if err := gitRepo.RemoveReference(ref.Name); err != nil {
+				return err
+			}
+		}
 	}
 
 	// If the Issue is pinned, we should unpin it before deletion to avoid problems with other pinned Issues
@@ -279,6 +288,10 @@ func deleteIssue(ctx context.Context, issue *issues_model.Issue) error {
 			issue.MilestoneID, err)
 	}
 
+	if err := issues_model.DeleteIssueRelations(ctx, issue.ID); err != nil {
+		return err
+	}
+
 	if err := activities_model.DeleteIssueActions(ctx, issue.RepoID, issue.ID, issue.Index); err != nil {
 		return err
 	}
@@ -298,11 +311,13 @@ func deleteIssue(ctx context.Context, issue *issues_model.Issue) error {
 		&issues_model.Comment{IssueID: issue.ID},
 		&issues_model.IssueLabel{IssueID: issue.ID},
 		&issues_model.IssueDependency{IssueID: issue.ID},
+		&issues_model.IssueFieldValue{IssueID: issue.ID},
 		&issues_model.IssueAssignees{IssueID: issue.ID},
 		&issues_model.IssueUser{IssueID: issue.ID},
 		&activities_model.Notification{IssueID: issue.ID},
 		&issues_model.Reaction{IssueID: issue.ID},
 		&issues_model.IssueWatch{IssueID: issue.ID},
+		&issues_model.IssueVote{IssueID: issue.ID},
 		&issues_model.Stopwatch{IssueID: issue.ID},
 		&issues_model.TrackedTime{IssueID: issue.ID},
 		&project_model.ProjectIssue{IssueID: issue.ID},
@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// SetIssueFieldValues sets the values of custom fields of an issue, the values are keyed by the fields.
+// The issue doesn't have a value of a field anymore if its values are empty.
+// The fields must be ones which the issues of the repository can have.
+func SetIssueFieldValues(ctx context.Context, issue *issues_model.Issue, doer *user_model.User, values map[*issues_model.IssueField][]string) error {
+	if err := issue.LoadFieldValues(ctx); err != nil {
+		return err
+	}
+
+	normalized := make(map[*issues_model.IssueField][]string, len(values))
+	for f, vals := range values {
+		vals, err := f.NormalizeValues(ctx, vals)
+		if err != nil {
+			return err
+		}
+		normalized[f] = vals
+	}
+
+	oldValues := make(map[int64][]string, len(issue.FieldValues))
+	for _, fv := range issue.FieldValues {
+		oldValues[fv.Field.ID] = fv.Values
+	}
+
+	changed := false
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		for f, vals := range normalized {
+			if slices.Equal(oldValues[f.ID], vals) {
+				continue
+			}
+			if err := issues_model.SetIssueFieldValues(ctx, issue, f, vals); err != nil {
+				return err
+			}
+			changed = true
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := issue.LoadFieldValues(ctx); err != nil {
+		return err
+	}
+	notify_service.IssueChangeFields(ctx, doer, issue)
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// ChangeIssueType changes the type of an issue, the issue doesn't have a type anymore if the type is nil.
+// The type must be one of the types which the issues of the repository can have.
+func ChangeIssueType(ctx context.Context, issue *issues_model.Issue, doer *user_model.User, t *issues_model.IssueType) error {
+	oldTypeID := issue.TypeID
+	if t != nil && t.ID == oldTypeID || t == nil && oldTypeID == 0 {
+		return nil
+	}
+
+	if err := issues_model.ChangeIssueType(ctx, issue, t); err != nil {
+		return err
+	}
+
+	notify_service.IssueChangeType(ctx, doer, issue, oldTypeID)
+	return nil
+}
@@ -16,12 +16,8 @@ import (
 func changeMilestoneAssign(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64) error {
 	// Only check if milestone exists if we don't remove it.
 	if issue.MilestoneID > 0 {
-		has, err := issues_model.HasMilestoneByRepoID(ctx, issue.RepoID, issue.MilestoneID)
-		if err != nil {
-			return fmt.Errorf("HasMilestoneByRepoID: %w", err)
-		}
-		if !has {
-			return fmt.Errorf("HasMilestoneByRepoID: issue doesn't exist")
+		if _, err := issues_model.GetMilestoneForRepo(ctx, issue.RepoID, issue.MilestoneID); err != nil {
+			return fmt.Errorf("GetMilestoneForRepo: %w", err)
 		}
 	}
 
@@ -40,34 +40,24 @@ type ReviewRequestNotifier struct {
 	ReviewTeam *org_model.Team
 }
 
-func PullRequestCodeOwnersReview(ctx context.Context, issue *issues_model.Issue, pr *issues_model.PullRequest) ([]*ReviewRequestNotifier, error) {
+// GetPullRequestCodeOwnersRules returns the rules of the CODEOWNERS file of the default branch of the base repository
+// and the files changed by the pull request
+func GetPullRequestCodeOwnersRules(ctx context.Context, pr *issues_model.PullRequest) ([]*issues_model.CodeOwnerRule, []string, error) {
 	files := []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitea/CODEOWNERS"}
 
-	if pr.IsWorkInProgress(ctx) {
-		return nil, nil
-	}
-
-	if err := pr.LoadHeadRepo(ctx); err != nil {
-		return nil, err
-	}
-
 	if err := pr.LoadBaseRepo(ctx); err != nil {
-		return nil, err
-	}
-
-	if pr.BaseRepo.IsFork {
-		return nil, nil
+		return nil, nil, err
 	}
 
 	repo, err := gitrepo.OpenRepository(ctx, pr.BaseRepo)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer repo.Close()
 
 	commit, err := repo.GetBranchCommit(pr.BaseRepo.DefaultBranch)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var data string
@@ -81,16 +71,44 @@ func PullRequestCodeOwnersReview(ctx context.Context, issue *issues_model.Issue,
 	}
 
 	rules, _ := issues_model.GetCodeOwnersFromContent(ctx, data)
+	if len(rules) == 0 {
+		return nil, nil, nil
+	}
 
 	// get the mergebase
 	mergeBase, err := getMergeBase(repo, pr, git.BranchPrefix+pr.BaseBranch, pr.GetGitRefName())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// https://github.com/go-gitea/gitea/issues/29763, we need to get the files changed
 	// between the merge base and the head commit but not the base branch and the head commit
 	changedFiles, err := repo.GetFilesChangedBetween(mergeBase, pr.GetGitRefName())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rules, changedFiles, nil
+}
+
+func PullRequestCodeOwnersReview(ctx context.Context, issue *issues_model.Issue, pr *issues_model.PullRequest) ([]*ReviewRequestNotifier, error) {
+	if pr.IsWorkInProgress(ctx) {
+		return nil, nil
+	}
+
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	if pr.BaseRepo.IsFork {
+		return nil, nil
+	}
+
+	rules, changedFiles, err := GetPullRequestCodeOwnersRules(ctx, pr)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +117,7 @@ func PullRequestCodeOwnersReview(ctx context.Context, issue *issues_model.Issue,
 	uniqTeams := make(map[string]*org_model.Team)
 	for _, rule := range rules {
 		for _, f := range changedFiles {
-			if (rule.Rule.MatchString(f) && !rule.Negative) || (!rule.Rule.MatchString(f) && rule.Negative) {
+			if rule.MatchFile(f) {
 				for _, u := range rule.Users {
 					uniqUsers[u.ID] = u
 				}
@@ -7,7 +7,9 @@ import (
 	"context"
 
 	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // CreateIssueReaction creates a reaction on an issue.
@@ -48,3 +50,44 @@ func CreateCommentReaction(ctx context.Context, doer *user_model.User, comment *
 		CommentID: comment.ID,
 	})
 }
+
+// CreateResolutionReaction creates a reaction on the resolution of the conversation started by a code comment.
+func CreateResolutionReaction(ctx context.Context, doer *user_model.User, comment *issues_model.Comment, content string) (*issues_model.Reaction, error) {
+	if !comment.IsResolved() {
+		return nil, util.NewInvalidArgumentErrorf("the conversation of comment %d isn't resolved", comment.ID)
+	}
+
+	if err := comment.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := comment.Issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	if user_model.IsUserBlockedBy(ctx, doer, comment.Issue.PosterID, comment.Issue.Repo.OwnerID, comment.ResolveDoerID) {
+		return nil, user_model.ErrBlockedUser
+	}
+
+	return issues_model.CreateReaction(ctx, &issues_model.ReactionOptions{
+		Type:         content,
+		DoerID:       doer.ID,
+		IssueID:      comment.Issue.ID,
+		CommentID:    comment.ID,
+		IsResolution: true,
+	})
+}
+
+// CreateCommitReaction creates a reaction on a commit of a repository.
+func CreateCommitReaction(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, commitSHA, content string) (*issues_model.Reaction, error) {
+	if user_model.IsUserBlockedBy(ctx, doer, repo.OwnerID) {
+		return nil, user_model.ErrBlockedUser
+	}
+
+	return issues_model.CreateReaction(ctx, &issues_model.ReactionOptions{
+		Type:      content,
+		DoerID:    doer.ID,
+		RepoID:    repo.ID,
+		CommitSHA: commitSHA,
+	})
+}
@@ -12,8 +12,10 @@ import (
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
 )
 
 func addReaction(t *testing.T, doer *user_model.User, issue *issues_model.Issue, comment *issues_model.Comment, content string) {
@@ -160,3 +162,84 @@ func TestIssueCommentReactionCount(t *testing.T) {
 
 	unittest.AssertNotExistsBean(t, &issues_model.Reaction{Type: "heart", UserID: user1.ID, IssueID: comment.IssueID, CommentID: comment.ID})
 }
+
+func TestCommitReaction(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user1 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	repo2 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+	sha := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	for _, doer := range []*user_model.User{user1, user2} {
+		_, err := CreateCommitReaction(db.DefaultContext, doer, repo1, sha, "heart")
+		assert.NoError(t, err)
+	}
+	_, err := CreateCommitReaction(db.DefaultContext, user1, repo1, sha, "heart")
+	assert.True(t, issues_model.IsErrReactionAlreadyExist(err))
+	// the same commit in another repository has its own reactions
+	_, err = CreateCommitReaction(db.DefaultContext, user1, repo2, sha, "heart")
+	assert.NoError(t, err)
+	_, err = CreateCommitReaction(db.DefaultContext, user1, repo1, sha, "+1")
+	assert.NoError(t, err)
+
+	reactions, count, err := issues_model.FindCommitReactions(db.DefaultContext, repo1.ID, sha, db.ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	assert.Len(t, reactions.GroupByType()["heart"], 2)
+
+	assert.NoError(t, issues_model.DeleteCommitReaction(db.DefaultContext, user1.ID, repo1.ID, sha, "heart"))
+	unittest.AssertNotExistsBean(t, &issues_model.Reaction{Type: "heart", UserID: user1.ID, RepoID: repo1.ID, CommitSHA: sha})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Reaction{Type: "heart", UserID: user1.ID, RepoID: repo2.ID, CommitSHA: sha})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Reaction{Type: "heart", UserID: user2.ID, RepoID: repo1.ID, CommitSHA: sha})
+
+	// the reactions on issues are still found by their issue
+	addReaction(t, user1, unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1}), nil, "heart")
+	reactions, _, err = issues_model.FindIssueReactions(db.DefaultContext, 1, db.ListOptions{})
+	assert.NoError(t, err)
+	for _, reaction := range reactions {
+		assert.EqualValues(t, 1, reaction.IssueID)
+		assert.Empty(t, reaction.CommitSHA)
+	}
+}
+
+func TestResolutionReaction(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user1 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 4})
+
+	// only a resolved conversation has a resolution to react to
+	_, err := CreateResolutionReaction(db.DefaultContext, user1, comment, "heart")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	assert.NoError(t, issues_model.MarkConversation(db.DefaultContext, comment, user2, true))
+	comment = unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 4})
+
+	addReaction(t, user1, nil, comment, "heart")
+	for _, doer := range []*user_model.User{user1, user2} {
+		_, err = CreateResolutionReaction(db.DefaultContext, doer, comment, "heart")
+		assert.NoError(t, err)
+	}
+	_, err = CreateResolutionReaction(db.DefaultContext, user1, comment, "heart")
+	assert.True(t, issues_model.IsErrReactionAlreadyExist(err))
+
+	// the reactions on the resolution are kept apart from the ones on the comment
+	reactions, _, err := issues_model.FindCommentReactions(db.DefaultContext, comment.IssueID, comment.ID)
+	assert.NoError(t, err)
+	assert.Len(t, reactions, 1)
+	reactions, _, err = issues_model.FindResolutionReactions(db.DefaultContext, comment.IssueID, comment.ID, db.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, reactions, 2)
+
+	assert.NoError(t, issues_model.DeleteResolutionReaction(db.DefaultContext, user1.ID, comment.IssueID, comment.ID, "heart"))
+	unittest.AssertNotExistsBean(t, &issues_model.Reaction{Type: "heart", UserID: user1.ID, CommentID: comment.ID}, builder.Eq{"is_resolution": true})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Reaction{Type: "heart", UserID: user1.ID, CommentID: comment.ID})
+
+	// withdrawing the resolution removes its reactions
+	assert.NoError(t, issues_model.MarkConversation(db.DefaultContext, comment, user2, false))
+	unittest.AssertNotExistsBean(t, &issues_model.Reaction{Type: "heart", UserID: user2.ID, CommentID: comment.ID}, builder.Eq{"is_resolution": true})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Reaction{Type: "heart", UserID: user1.ID, CommentID: comment.ID})
+}
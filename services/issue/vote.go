@@ -0,0 +1,77 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// ErrVotingMembersOnly is returned when somebody who isn't a member of an organization votes for one of its issues
+// while the organization restricts the voting to its members
+var ErrVotingMembersOnly = util.NewPermissionDeniedErrorf("only the members of the organization can vote")
+
+// CanVoteForIssue returns nil if the user can vote for the issue
+func CanVoteForIssue(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) error {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if err := issue.Repo.LoadOwner(ctx); err != nil {
+		return err
+	}
+
+	if user_model.IsUserBlockedBy(ctx, doer, issue.PosterID, issue.Repo.OwnerID) {
+		return user_model.ErrBlockedUser
+	}
+
+	if issue.Repo.Owner.IsOrganization() && issue.Repo.Owner.IssueVotingMembersOnly {
+		isMember, err := organization.IsOrganizationMember(ctx, issue.Repo.OwnerID, doer.ID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return ErrVotingMembersOnly
+		}
+	}
+	return nil
+}
+
+// VoteForIssue casts the vote of the user for the issue, it returns false if the user had already voted
+func VoteForIssue(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) (bool, error) {
+	if err := CanVoteForIssue(ctx, doer, issue); err != nil {
+		return false, err
+	}
+
+	voted, err := issues_model.HasVotedForIssue(ctx, doer.ID, issue.ID)
+	if err != nil || voted {
+		return false, err
+	}
+
+	if err := issues_model.CreateIssueVote(ctx, doer.ID, issue.ID); err != nil {
+		return false, err
+	}
+
+	notify_service.IssueChangeVote(ctx, doer, issue, true)
+	return true, nil
+}
+
+// RemoveIssueVote removes the vote of the user for the issue if there is one
+func RemoveIssueVote(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) error {
+	voted, err := issues_model.HasVotedForIssue(ctx, doer.ID, issue.ID)
+	if err != nil || !voted {
+		return err
+	}
+
+	if err := issues_model.DeleteIssueVote(ctx, doer.ID, issue.ID); err != nil {
+		return err
+	}
+
+	notify_service.IssueChangeVote(ctx, doer, issue, false)
+	return nil
+}
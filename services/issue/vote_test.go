@@ -0,0 +1,50 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoteForIssue(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	member := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	nonMember := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 6, RepoID: 3})
+
+	voted, err := VoteForIssue(db.DefaultContext, nonMember, issue)
+	assert.NoError(t, err)
+	assert.True(t, voted)
+
+	voted, err = VoteForIssue(db.DefaultContext, nonMember, issue)
+	assert.NoError(t, err)
+	assert.False(t, voted)
+
+	t.Run("MembersOnly", func(t *testing.T) {
+		org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+		org.IssueVotingMembersOnly = true
+		_, err := db.GetEngine(db.DefaultContext).ID(org.ID).Cols("issue_voting_members_only").Update(org)
+		assert.NoError(t, err)
+
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 12, RepoID: 3})
+		_, err = VoteForIssue(db.DefaultContext, nonMember, issue)
+		assert.ErrorIs(t, err, ErrVotingMembersOnly)
+
+		voted, err := VoteForIssue(db.DefaultContext, member, issue)
+		assert.NoError(t, err)
+		assert.True(t, voted)
+	})
+
+	// the vote can be removed even if the user can't vote anymore
+	assert.NoError(t, RemoveIssueVote(db.DefaultContext, nonMember, issue))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueVote{UserID: nonMember.ID, IssueID: issue.ID})
+}
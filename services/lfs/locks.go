@@ -4,6 +4,7 @@
 package lfs
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -44,6 +45,20 @@ func handleLockListOut(ctx *context.Context, repo *repo_model.Repository, lock *
 	})
 }
 
+// parseLockListPaging returns the zero based page index and the page size of a lock list request
+func parseLockListPaging(cursor string, limit int) (int, int) {
+	page, _ := strconv.Atoi(cursor)
+	if page < 0 {
+		page = 0
+	}
+	if limit > setting.LFS.LocksPagingNum && setting.LFS.LocksPagingNum > 0 {
+		limit = setting.LFS.LocksPagingNum
+	} else if limit < 0 {
+		limit = 0
+	}
+	return page, limit
+}
+
 // GetListLockHandler list locks
 func GetListLockHandler(ctx *context.Context) {
 	rv := getRequestContext(ctx)
@@ -74,16 +89,7 @@ func GetListLockHandler(ctx *context.Context) {
 	}
 	ctx.Resp.Header().Set("Content-Type", lfs_module.MediaType)
 
-	cursor := ctx.FormInt("cursor")
-	if cursor < 0 {
-		cursor = 0
-	}
-	limit := ctx.FormInt("limit")
-	if limit > setting.LFS.LocksPagingNum && setting.LFS.LocksPagingNum > 0 {
-		limit = setting.LFS.LocksPagingNum
-	} else if limit < 0 {
-		limit = 0
-	}
+	cursor, limit := parseLockListPaging(ctx.FormString("cursor"), ctx.FormInt("limit"))
 	id := ctx.FormString("id")
 	if id != "" { // Case where we request a specific id
 		v, err := strconv.ParseInt(id, 10, 64)
@@ -112,7 +118,7 @@ func GetListLockHandler(ctx *context.Context) {
 	}
 
 	// If no query params path or id
-	lockList, err := git_model.GetLFSLockByRepoID(ctx, repository.ID, cursor, limit)
+	lockList, err := git_model.GetLFSLockByRepoID(ctx, repository.ID, cursor+1, limit)
 	if err != nil {
 		log.Error("Unable to list locks for repository ID[%d]: Error: %v", repository.ID, err)
 		ctx.JSON(http.StatusInternalServerError, api.LFSLockError{
@@ -239,17 +245,19 @@ func VerifyLockHandler(ctx *context.Context) {
 
 	ctx.Resp.Header().Set("Content-Type", lfs_module.MediaType)
 
-	cursor := ctx.FormInt("cursor")
-	if cursor < 0 {
-		cursor = 0
-	}
-	limit := ctx.FormInt("limit")
-	if limit > setting.LFS.LocksPagingNum && setting.LFS.LocksPagingNum > 0 {
-		limit = setting.LFS.LocksPagingNum
-	} else if limit < 0 {
-		limit = 0
+	var req api.LFSLockListVerifyRequest
+	bodyReader := ctx.Req.Body
+	defer bodyReader.Close()
+
+	dec := json.NewDecoder(bodyReader)
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		log.Warn("Failed to decode lock verify request as json. Error: %v", err)
+		writeStatus(ctx, http.StatusBadRequest)
+		return
 	}
-	lockList, err := git_model.GetLFSLockByRepoID(ctx, repository.ID, cursor, limit)
+
+	cursor, limit := parseLockListPaging(req.Cursor, req.Limit)
+	lockList, err := git_model.GetLFSLockByRepoID(ctx, repository.ID, cursor+1, limit)
 	if err != nil {
 		log.Error("Unable to list locks for repository ID[%d]: Error: %v", repository.ID, err)
 		ctx.JSON(http.StatusInternalServerError, api.LFSLockError{
@@ -323,6 +331,18 @@ func UnLockHandler(ctx *context.Context) {
 
 	lock, err := git_model.DeleteLFSLockByID(ctx, ctx.PathParamInt64("lid"), repository, ctx.Doer, req.Force)
 	if err != nil {
+		if git_model.IsErrLFSLockNotExist(err) {
+			ctx.JSON(http.StatusNotFound, api.LFSLockError{
+				Message: "lock not found",
+			})
+			return
+		}
+		if git_model.IsErrLFSLockNotOwner(err) {
+			ctx.JSON(http.StatusForbidden, api.LFSLockError{
+				Message: "You must set the force flag to delete a lock of another user",
+			})
+			return
+		}
 		if git_model.IsErrLFSUnauthorizedAction(err) {
 			ctx.Resp.Header().Set("WWW-Authenticate", "Basic realm=gitea-lfs")
 			ctx.JSON(http.StatusUnauthorized, api.LFSLockError{
@@ -150,6 +150,17 @@ func mailIssueCommentBatch(ctx *mailCommentContext, users []*user_model.User, vi
 			continue
 		}
 
+		// the internal comments are only sent to the users who can write the issue/pull
+		if ctx.Comment != nil && ctx.Comment.IsInternal {
+			perm, err := access_model.GetUserRepoPermission(ctx, ctx.Issue.Repo, user)
+			if err != nil {
+				return err
+			}
+			if !perm.CanWriteIssuesOrPulls(ctx.Issue.IsPull) {
+				continue
+			}
+		}
+
 		langMap[user.Language] = append(langMap[user.Language], user)
 	}
 
@@ -0,0 +1,473 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package mergequeue merges the pull requests of a merge queue in order once the checks of their speculative merge commits succeed.
+//
+// The first entries of a queue, up to the batch size, get a speculative merge commit on the branch returned by
+// pull_service.MergeQueueBranchName: the first one is merged on top of the base branch and every other one on top of
+// the speculative merge commit of the entry before it. The checks of these commits test the pull requests against
+// the ones which will be merged before them. The base branch is fast-forwarded to the speculative merge commit of the
+// last entry of the batch whose checks succeed, which merges it along with the entries before it.
+package mergequeue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// mergeQueueQueue holds the ids of the merge queues to process
+var mergeQueueQueue *queue.WorkerPoolQueue[int64]
+
+// mergeQueueWorkingPool prevents a merge queue from being processed by several workers at once
+var mergeQueueWorkingPool = sync.NewExclusivePool()
+
+// Init runs the task queue that processes the merge queues
+func Init() error {
+	notify_service.RegisterNotifier(NewNotifier())
+
+	mergeQueueQueue = queue.CreateUniqueQueue(graceful.GetManager().ShutdownContext(), "pr_merge_queue", handler)
+	if mergeQueueQueue == nil {
+		return fmt.Errorf("unable to create pr_merge_queue queue")
+	}
+	go graceful.GetManager().RunWithCancel(mergeQueueQueue)
+	return nil
+}
+
+func handler(items ...int64) []int64 {
+	for _, queueID := range items {
+		handleMergeQueue(queueID)
+	}
+	return nil
+}
+
+func addToQueue(queueID int64) {
+	log.Trace("Adding merge queue %d to the merge queue processing queue", queueID)
+	if err := mergeQueueQueue.Push(queueID); err != nil {
+		log.Error("Error adding merge queue %d to the merge queue processing queue: %v", queueID, err)
+	}
+}
+
+// IsMergeQueueEnabled returns whether the pull requests of the repository are merged through merge queues
+func IsMergeQueueEnabled(ctx context.Context, repo *repo_model.Repository) bool {
+	prUnit, err := repo.GetUnit(ctx, unit.TypePullRequests)
+	if err != nil {
+		return false
+	}
+	return prUnit.PullRequestsConfig().EnableMergeQueue
+}
+
+// AddToMergeQueue appends the pull request to the merge queue of its base branch,
+// the caller should check that the pull request can be merged by the doer
+func AddToMergeQueue(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, style repo_model.MergeStyle, message string) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return err
+	}
+	prUnit, err := pr.BaseRepo.GetUnit(ctx, unit.TypePullRequests)
+	if err != nil {
+		return err
+	}
+	prConfig := prUnit.PullRequestsConfig()
+	if !prConfig.EnableMergeQueue {
+		return util.NewInvalidArgumentErrorf("the merge queue is not enabled for the pull requests of %s", pr.BaseRepo.FullName())
+	}
+	if style == repo_model.MergeStyleManuallyMerged || !prConfig.IsMergeStyleAllowed(style) {
+		return models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: style}
+	}
+	if pr.HasMerged {
+		return util.NewInvalidArgumentErrorf("the pull request has already been merged")
+	}
+	if pr.Flow == issues_model.PullRequestFlowGithub && pr.HeadRepoID == pr.BaseRepoID && pull_service.IsMergeQueueBranch(pr.HeadBranch) {
+		return util.NewInvalidArgumentErrorf("the head branch holds a speculative merge commit")
+	}
+
+	headCommitID, err := getHeadCommitID(ctx, pr)
+	if err != nil {
+		return err
+	}
+
+	entry := &pull_model.MergeQueueEntry{
+		RepoID:       pr.BaseRepoID,
+		PullID:       pr.ID,
+		DoerID:       doer.ID,
+		MergeStyle:   style,
+		Message:      message,
+		HeadCommitID: headCommitID,
+	}
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if err := pull_model.AddToMergeQueue(ctx, entry, pr.BaseBranch); err != nil {
+			return err
+		}
+		_, err := createMergeQueueComment(ctx, issues_model.CommentTypePRAddedToMergeQueue, pr, doer, "")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	addToQueue(entry.QueueID)
+	return nil
+}
+
+// RemoveFromMergeQueue removes the pull request from its merge queue
+func RemoveFromMergeQueue(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) error {
+	exist, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil {
+		return err
+	} else if !exist {
+		return db.ErrNotExist{Resource: "merge_queue_entry", ID: pr.ID}
+	}
+	if err := removeEntry(ctx, doer, pr, entry, ""); err != nil {
+		return err
+	}
+	// the entries after the removed one have to be merged again without it
+	addToQueue(entry.QueueID)
+	return nil
+}
+
+// removeEntry removes the entry of the pull request from its merge queue, the reason is recorded if it isn't removed by a user.
+// The merge queue has to be processed again afterwards.
+func removeEntry(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, entry *pull_model.MergeQueueEntry, reason string) error {
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if err := pull_model.DeleteMergeQueueEntry(ctx, pr.ID); err != nil {
+			return err
+		}
+		if pr.HasMerged || pr.Issue != nil && pr.Issue.IsClosed {
+			return nil
+		}
+		_, err := createMergeQueueComment(ctx, issues_model.CommentTypePRRemovedFromMergeQueue, pr, doer, reason)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	deleteMergeQueueBranch(ctx, doer, pr)
+	return nil
+}
+
+func createMergeQueueComment(ctx context.Context, typ issues_model.CommentType, pr *issues_model.PullRequest, doer *user_model.User, content string) (*issues_model.Comment, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	return issues_model.CreateComment(ctx, &issues_model.CreateCommentOptions{
+		Type:    typ,
+		Doer:    doer,
+		Repo:    pr.BaseRepo,
+		Issue:   pr.Issue,
+		Content: content,
+	})
+}
+
+// deleteMergeQueueBranch deletes the branch holding the speculative merge commit of the pull request, if any
+func deleteMergeQueueBranch(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	if err := pull_service.DeleteMergeQueueBranch(ctx, pr, doer); err != nil {
+		log.Error("Unable to delete the merge queue branch of %-v: %v", pr, err)
+	}
+}
+
+// getHeadCommitID returns the current head commit of the pull request
+func getHeadCommitID(ctx context.Context, pr *issues_model.PullRequest) (string, error) {
+	gitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, pr.BaseRepo)
+	if err != nil {
+		return "", fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer closer.Close()
+
+	commitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return "", fmt.Errorf("GetRefCommitID: %w", err)
+	}
+	return commitID, nil
+}
+
+// StartMergeQueueCheckBySHA processes the merge queues with a speculative merge commit whose status has changed
+func StartMergeQueueCheckBySHA(ctx context.Context, sha string, repo *repo_model.Repository) error {
+	entries, err := pull_model.GetMergeQueueEntriesByMergeCommitID(ctx, repo.ID, sha)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		addToQueue(entry.QueueID)
+	}
+	return nil
+}
+
+// startMergeQueueCheckByPull processes the merge queue of the pull request, if it is in one
+func startMergeQueueCheckByPull(ctx context.Context, pr *issues_model.PullRequest) {
+	exist, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil {
+		log.Error("GetMergeQueueEntryByPullID: %v", err)
+		return
+	}
+	if exist {
+		addToQueue(entry.QueueID)
+	}
+}
+
+// startMergeQueueCheckByBranch processes the merge queue of the branch, if it has one
+func startMergeQueueCheckByBranch(ctx context.Context, repoID int64, branch string) {
+	mergeQueue, err := pull_model.GetMergeQueue(ctx, repoID, branch)
+	if err != nil {
+		log.Error("GetMergeQueue: %v", err)
+		return
+	}
+	if mergeQueue != nil {
+		addToQueue(mergeQueue.ID)
+	}
+}
+
+// handleMergeQueue processes the merge queue until it has to wait for the checks of its speculative merge commits
+func handleMergeQueue(queueID int64) {
+	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().HammerContext(),
+		fmt.Sprintf("Handle merge queue[%d]", queueID))
+	defer finished()
+
+	mergeQueueWorkingPool.CheckIn(strconv.FormatInt(queueID, 10))
+	defer mergeQueueWorkingPool.CheckOut(strconv.FormatInt(queueID, 10))
+
+	mergeQueue, err := pull_model.GetMergeQueueByID(ctx, queueID)
+	if err != nil {
+		if !db.IsErrNotExist(err) {
+			log.Error("GetMergeQueueByID[%d]: %v", queueID, err)
+		}
+		return
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, mergeQueue.RepoID)
+	if err != nil {
+		log.Error("GetRepositoryByID[%d]: %v", mergeQueue.RepoID, err)
+		return
+	}
+
+	for {
+		again, err := processMergeQueue(ctx, repo, mergeQueue)
+		if err != nil {
+			log.Error("Unable to process the merge queue of %s in %-v: %v", mergeQueue.BaseBranch, repo, err)
+			return
+		}
+		if !again {
+			return
+		}
+	}
+}
+
+// QueuedPullRequest is an entry of a merge queue with its pull request
+type QueuedPullRequest struct {
+	Entry       *pull_model.MergeQueueEntry
+	PullRequest *issues_model.PullRequest
+}
+
+// GetQueuedPullRequests returns the pull requests of the merge queue of the branch in their merge order
+func GetQueuedPullRequests(ctx context.Context, repo *repo_model.Repository, branch string) ([]*QueuedPullRequest, error) {
+	mergeQueue, err := pull_model.GetMergeQueue(ctx, repo.ID, branch)
+	if err != nil || mergeQueue == nil {
+		return nil, err
+	}
+	entries, err := pull_model.GetMergeQueueEntries(ctx, mergeQueue.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	queued := make([]*QueuedPullRequest, 0, len(entries))
+	for _, entry := range entries {
+		pr, err := issues_model.GetPullRequestByID(ctx, entry.PullID)
+		if err != nil {
+			if issues_model.IsErrPullRequestNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if err := entry.LoadDoer(ctx); err != nil {
+			return nil, err
+		}
+		queued = append(queued, &QueuedPullRequest{Entry: entry, PullRequest: pr})
+	}
+	return queued, nil
+}
+
+// processMergeQueue does one step of the processing of the merge queue, it returns whether the queue has changed and must be processed again
+func processMergeQueue(ctx context.Context, repo *repo_model.Repository, mergeQueue *pull_model.MergeQueue) (bool, error) {
+	entries, err := pull_model.GetMergeQueueEntries(ctx, mergeQueue.ID)
+	if err != nil {
+		return false, err
+	}
+
+	batchSize := max(setting.Repository.PullRequest.MergeQueueBatchSize, 1)
+	batch := make([]*QueuedPullRequest, 0, min(len(entries), batchSize))
+	for _, entry := range entries {
+		if len(batch) == batchSize {
+			break
+		}
+		queued, removed, err := loadQueuedPullRequest(ctx, repo, mergeQueue, entry)
+		if err != nil {
+			return false, err
+		} else if removed {
+			return true, nil
+		}
+		batch = append(batch, queued)
+	}
+	if len(batch) == 0 {
+		return false, nil
+	}
+
+	baseCommitID, err := gitrepo.GetBranchCommitID(ctx, repo, mergeQueue.BaseBranch)
+	if err != nil {
+		return false, fmt.Errorf("GetBranchCommitID: %w", err)
+	}
+
+	// create the speculative merge commits which aren't based on the current base of their entry
+	onCommitID, onBranch := baseCommitID, mergeQueue.BaseBranch
+	for _, queued := range batch {
+		if queued.Entry.BaseCommitID != onCommitID || !queued.Entry.IsTesting() {
+			commitID, err := pull_service.CreateSpeculativeMerge(ctx, queued.PullRequest, queued.Entry.Doer, queued.Entry.MergeStyle, queued.Entry.HeadCommitID, queued.Entry.Message, onBranch)
+			if err != nil {
+				log.Debug("Unable to create the speculative merge commit of %-v: %v", queued.PullRequest, err)
+				return true, removeEntry(ctx, queued.Entry.Doer, queued.PullRequest, queued.Entry, speculativeMergeFailureReason(err))
+			}
+			queued.Entry.BaseCommitID = onCommitID
+			queued.Entry.MergeCommitID = commitID
+			if err := pull_model.UpdateMergeQueueEntrySpeculativeMerge(ctx, queued.Entry); err != nil {
+				return false, err
+			}
+		}
+		onCommitID, onBranch = queued.Entry.MergeCommitID, pull_service.MergeQueueBranchName(queued.PullRequest)
+	}
+
+	// the checks of a speculative merge commit also test the entries before it
+	lastSuccess := -1
+	for i, queued := range batch {
+		state, err := getSpeculativeMergeCommitStatusState(ctx, repo, mergeQueue.BaseBranch, queued.Entry.MergeCommitID)
+		if err != nil {
+			return false, err
+		}
+		if state.IsSuccess() {
+			lastSuccess = i
+		} else if state.IsFailure() || state.IsError() {
+			if i == 0 {
+				return true, removeEntry(ctx, queued.Entry.Doer, queued.PullRequest, queued.Entry, "The checks of its speculative merge commit failed.")
+			}
+			// the failure may be caused by an entry before this one, it is removed once they are merged
+			break
+		}
+	}
+	if lastSuccess < 0 {
+		return false, nil
+	}
+
+	for _, queued := range batch[:lastSuccess+1] {
+		if err := pull_service.MergeSpeculativeCommit(ctx, queued.PullRequest, queued.Entry.Doer, queued.Entry.MergeCommitID); err != nil {
+			if git.IsErrPushOutOfDate(err) {
+				// the base branch has been updated, the speculative merge commits have to be created again
+				return true, nil
+			}
+			var pushErr *git.ErrPushRejected
+			if errors.As(err, &pushErr) {
+				return true, removeEntry(ctx, queued.Entry.Doer, queued.PullRequest, queued.Entry, "The merge was rejected: "+pushErr.Message)
+			}
+			return false, err
+		}
+		// the entry has been deleted when the pull request has been marked as merged
+		deleteMergeQueueBranch(ctx, queued.Entry.Doer, queued.PullRequest)
+	}
+	return true, nil
+}
+
+// loadQueuedPullRequest loads the pull request of the entry, the entry is removed if its pull request can't be merged through the queue anymore
+func loadQueuedPullRequest(ctx context.Context, repo *repo_model.Repository, mergeQueue *pull_model.MergeQueue, entry *pull_model.MergeQueueEntry) (queued *QueuedPullRequest, removed bool, err error) {
+	pr, err := issues_model.GetPullRequestByID(ctx, entry.PullID)
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			return nil, true, pull_model.DeleteMergeQueueEntry(ctx, entry.PullID)
+		}
+		return nil, false, err
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, false, err
+	}
+	pr.BaseRepo = repo
+	if err := entry.LoadDoer(ctx); err != nil {
+		return nil, false, err
+	}
+	queued = &QueuedPullRequest{Entry: entry, PullRequest: pr}
+
+	var reason string
+	switch {
+	case pr.HasMerged, pr.Issue.IsClosed:
+		// nothing to explain
+	case pr.BaseBranch != mergeQueue.BaseBranch:
+		reason = "The base branch of the pull request has been changed."
+	default:
+		headCommitID, err := getHeadCommitID(ctx, pr)
+		if err != nil {
+			return nil, false, err
+		}
+		if headCommitID == entry.HeadCommitID {
+			return queued, false, nil
+		}
+		reason = "The head branch of the pull request has been updated."
+	}
+	return nil, true, removeEntry(ctx, entry.Doer, pr, entry, reason)
+}
+
+// getSpeculativeMergeCommitStatusState returns the state of the checks of a speculative merge commit,
+// the required status checks of the base branch are used when its protection enables them.
+// Without required status checks, a commit without any status succeeds.
+func getSpeculativeMergeCommitStatusState(ctx context.Context, repo *repo_model.Repository, baseBranch, commitID string) (structs.CommitStatusState, error) {
+	commitStatuses, _, err := git_model.GetLatestCommitStatus(ctx, repo.ID, commitID, db.ListOptionsAll)
+	if err != nil {
+		return "", fmt.Errorf("GetLatestCommitStatus: %w", err)
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, repo.ID, baseBranch)
+	if err != nil {
+		return "", fmt.Errorf("GetFirstMatchProtectedBranchRule: %w", err)
+	}
+	var requiredContexts []string
+	if pb != nil && pb.EnableStatusCheck {
+		requiredContexts = pb.StatusCheckContexts
+	}
+	if len(requiredContexts) == 0 && len(commitStatuses) == 0 {
+		return structs.CommitStatusSuccess, nil
+	}
+	return pull_service.MergeRequiredContextsCommitStatus(commitStatuses, requiredContexts), nil
+}
+
+// speculativeMergeFailureReason explains why the speculative merge commit of a pull request couldn't be created
+func speculativeMergeFailureReason(err error) string {
+	switch {
+	case models.IsErrMergeConflicts(err), models.IsErrRebaseConflicts(err):
+		return "The pull request conflicts with the base branch or the pull requests before it in the merge queue."
+	case models.IsErrMergeUnrelatedHistories(err):
+		return "The pull request and the base branch have unrelated histories."
+	case models.IsErrMergeDivergingFastForwardOnly(err):
+		return "The pull request can't be fast-forwarded onto the base branch and the pull requests before it in the merge queue."
+	case models.IsErrSHADoesNotMatch(err):
+		return "The head branch of the pull request has been updated."
+	case models.IsErrInvalidMergeStyle(err):
+		return "The merge style is not allowed anymore."
+	}
+	return "The speculative merge commit couldn't be created."
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mergequeue
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repository"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+type mergeQueueNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &mergeQueueNotifier{}
+
+// NewNotifier create a new mergeQueueNotifier notifier
+func NewNotifier() notify_service.Notifier {
+	return &mergeQueueNotifier{}
+}
+
+func (n *mergeQueueNotifier) IssueChangeStatus(ctx context.Context, doer *user_model.User, commitID string, issue *issues_model.Issue, actionComment *issues_model.Comment, isClosed bool) {
+	if !issue.IsPull || !isClosed {
+		return
+	}
+	if err := issue.LoadPullRequest(ctx); err != nil {
+		log.Error("LoadPullRequest: %v", err)
+		return
+	}
+	// a closed pull request leaves its merge queue
+	startMergeQueueCheckByPull(ctx, issue.PullRequest)
+}
+
+func (n *mergeQueueNotifier) PullRequestSynchronized(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	// a pull request whose head branch has been updated leaves its merge queue
+	startMergeQueueCheckByPull(ctx, pr)
+}
+
+func (n *mergeQueueNotifier) PullRequestChangeTargetBranch(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, oldBranch string) {
+	startMergeQueueCheckByPull(ctx, pr)
+}
+
+func (n *mergeQueueNotifier) PushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	// the speculative merge commits of the queue have to be created again on top of the updated base branch,
+	// the push may come from the merge queue itself so the check must not wait for it to be processed
+	if opts.RefFullName.IsBranch() && !opts.IsDelRef() {
+		go startMergeQueueCheckByBranch(graceful.GetManager().ShutdownContext(), repo.ID, opts.RefFullName.BranchName())
+	}
+}
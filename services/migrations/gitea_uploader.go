@@ -446,14 +446,53 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 			return err
 		}
 
-		for _, is := range iss {
+		for i, is := range iss {
 			g.issues[is.Index] = is
+			if err := g.insertAttachments(is.ID, 0, issues[i].Attachments); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// insertAttachments stores the files attached to an issue or a comment
+func (g *GiteaLocalUploader) insertAttachments(issueID, commentID int64, attachments []*base.Attachment) error {
+	for _, attachment := range attachments {
+		// SECURITY: unlike the assets of the releases, the DownloadURL isn't opened,
+		// only the content provided by the downloader is uploaded
+		if attachment.DownloadFunc == nil {
+			continue
+		}
+		if attachment.Created.IsZero() {
+			attachment.Created = time.Now()
+		}
+		attach := &repo_model.Attachment{
+			UUID:        uuid.New().String(),
+			RepoID:      g.repo.ID,
+			IssueID:     issueID,
+			CommentID:   commentID,
+			UploaderID:  g.doer.ID,
+			Name:        attachment.Name,
+			CreatedUnix: timeutil.TimeStamp(attachment.Created.Unix()),
+		}
+		rc, err := attachment.DownloadFunc()
+		if err != nil {
+			return err
+		}
+		attach.Size, err = storage.Attachments.Save(attach.RelativePath(), rc, -1)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if err := db.Insert(g.ctx, attach); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateComments creates comments of issues
 func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 	cms := make([]*issues_model.Comment, 0, len(comments))
@@ -536,7 +575,15 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 	if len(cms) == 0 {
 		return nil
 	}
-	return issues_model.InsertIssueComments(g.ctx, cms)
+	if err := issues_model.InsertIssueComments(g.ctx, cms); err != nil {
+		return err
+	}
+	for i, cm := range cms {
+		if err := g.insertAttachments(cm.IssueID, cm.ID, comments[i].Attachments); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // CreatePullRequests creates pull requests
@@ -0,0 +1,412 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// the types of the comments which are exported and imported with the issues,
+// the other types describe changes of the issues which aren't part of the bundle
+var issueBundleCommentTypes = []issues_model.CommentType{
+	issues_model.CommentTypeComment,
+	issues_model.CommentTypeReopen,
+	issues_model.CommentTypeClose,
+	issues_model.CommentTypeChangeTitle,
+}
+
+// ExportIssues exports the issues of a repository with their labels, milestones and comments
+func ExportIssues(ctx context.Context, repo *repo_model.Repository) (*base.IssueBundle, error) {
+	bundle := &base.IssueBundle{
+		Labels:     []*base.Label{},
+		Milestones: []*base.Milestone{},
+		Issues:     []*base.Issue{},
+		Comments:   []*base.Comment{},
+	}
+
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		bundle.Labels = append(bundle.Labels, toMigrationLabel(label))
+	}
+
+	milestones, err := db.Find[issues_model.Milestone](ctx, issues_model.FindMilestoneOptions{RepoID: repo.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, milestone := range milestones {
+		deadline := milestone.DeadlineUnix.AsTime()
+		updated := milestone.UpdatedUnix.AsTime()
+		ms := &base.Milestone{
+			Title:       milestone.Name,
+			Description: milestone.Content,
+			Deadline:    &deadline,
+			Created:     milestone.CreatedUnix.AsTime(),
+			Updated:     &updated,
+			State:       "open",
+		}
+		if milestone.IsClosed {
+			closed := milestone.ClosedDateUnix.AsTime()
+			ms.Closed = &closed
+			ms.State = "closed"
+		}
+		bundle.Milestones = append(bundle.Milestones, ms)
+	}
+
+	issues, err := issues_model.Issues(ctx, &issues_model.IssuesOptions{
+		RepoIDs:  []int64{repo.ID},
+		IsPull:   optional.Some(false),
+		SortType: "oldest",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := issues.LoadAttachments(ctx); err != nil {
+		return nil, err
+	}
+	indexes := make(map[int64]int64, len(issues))
+	commentReactions := make(map[int64]issues_model.ReactionList)
+	for _, issue := range issues {
+		indexes[issue.ID] = issue.Index
+
+		reactions, _, err := issues_model.FindReactions(ctx, issues_model.FindReactionsOptions{IssueID: issue.ID})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := reactions.LoadUsers(ctx, repo); err != nil {
+			return nil, err
+		}
+		var issueReactions issues_model.ReactionList
+		for _, reaction := range reactions {
+			if reaction.CommentID == 0 {
+				issueReactions = append(issueReactions, reaction)
+			} else {
+				commentReactions[reaction.CommentID] = append(commentReactions[reaction.CommentID], reaction)
+			}
+		}
+
+		is := &base.Issue{
+			Number:      issue.Index,
+			Title:       issue.Title,
+			Content:     issue.Content,
+			Ref:         issue.Ref,
+			State:       "open",
+			IsLocked:    issue.IsLocked,
+			Created:     issue.CreatedUnix.AsTime(),
+			Updated:     issue.UpdatedUnix.AsTime(),
+			Labels:      make([]*base.Label, 0, len(issue.Labels)),
+			Reactions:   toMigrationReactions(issueReactions),
+			Assignees:   make([]string, 0, len(issue.Assignees)),
+			Attachments: toMigrationAttachments(issue.Attachments),
+		}
+		is.PosterID, is.PosterName = migrationPoster(issue.Poster, issue.OriginalAuthorID, issue.OriginalAuthor)
+		if issue.IsClosed {
+			closed := issue.ClosedUnix.AsTime()
+			is.Closed = &closed
+			is.State = "closed"
+		}
+		if issue.Milestone != nil {
+			is.Milestone = issue.Milestone.Name
+		}
+		for _, label := range issue.Labels {
+			is.Labels = append(is.Labels, toMigrationLabel(label))
+		}
+		for _, assignee := range issue.Assignees {
+			is.Assignees = append(is.Assignees, assignee.Name)
+		}
+		bundle.Issues = append(bundle.Issues, is)
+	}
+
+	// the exports are available to all the readers, the internal comments aren't exported
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		RepoID:     repo.ID,
+		IsPull:     optional.Some(false),
+		IsInternal: optional.Some(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := comments.LoadPosters(ctx); err != nil {
+		return nil, err
+	}
+	if err := comments.LoadAttachments(ctx); err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		if !slices.Contains(issueBundleCommentTypes, comment.Type) {
+			continue
+		}
+		cm := &base.Comment{
+			IssueIndex:  indexes[comment.IssueID],
+			Index:       comment.ID,
+			CommentType: comment.Type.String(),
+			Created:     comment.CreatedUnix.AsTime(),
+			Updated:     comment.UpdatedUnix.AsTime(),
+			Content:     comment.Content,
+			Reactions:   toMigrationReactions(commentReactions[comment.ID]),
+			Attachments: toMigrationAttachments(comment.Attachments),
+		}
+		cm.PosterID, cm.PosterName = migrationPoster(comment.Poster, comment.OriginalAuthorID, comment.OriginalAuthor)
+		if comment.Type == issues_model.CommentTypeChangeTitle {
+			cm.Meta = map[string]any{"OldTitle": comment.OldTitle, "NewTitle": comment.NewTitle}
+		}
+		bundle.Comments = append(bundle.Comments, cm)
+	}
+
+	return bundle, nil
+}
+
+// migrationPoster returns the id and the name of a poster, or of the original author if it was migrated
+func migrationPoster(poster *user_model.User, originalAuthorID int64, originalAuthor string) (int64, string) {
+	if originalAuthor != "" {
+		return originalAuthorID, originalAuthor
+	}
+	if poster == nil {
+		poster = user_model.NewGhostUser()
+	}
+	return poster.ID, poster.Name
+}
+
+func toMigrationLabel(label *issues_model.Label) *base.Label {
+	return &base.Label{
+		Name:        label.Name,
+		Color:       label.Color,
+		Description: label.Description,
+		Exclusive:   label.Exclusive,
+	}
+}
+
+func toMigrationReactions(reactions issues_model.ReactionList) []*base.Reaction {
+	result := make([]*base.Reaction, 0, len(reactions))
+	for _, reaction := range reactions {
+		r := &base.Reaction{Content: reaction.Type}
+		r.UserID, r.UserName = migrationPoster(reaction.User, reaction.OriginalAuthorID, reaction.OriginalAuthor)
+		result = append(result, r)
+	}
+	return result
+}
+
+func toMigrationAttachments(attachments []*repo_model.Attachment) []*base.Attachment {
+	result := make([]*base.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		result = append(result, &base.Attachment{
+			Name:        attachment.Name,
+			Size:        attachment.Size,
+			Created:     attachment.CreatedUnix.AsTime(),
+			DownloadURL: attachment.DownloadURL(),
+		})
+	}
+	return result
+}
+
+// ImportIssues imports the issues of a bundle into an existing repository with the uploader of the migrations.
+// The issues are numbered after the issues and pull requests of the repository, the labels and milestones are
+// matched by their names and created if they don't exist, and the posters are matched by their ids and names.
+func ImportIssues(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, bundle *base.IssueBundle) (issues_model.IssueList, error) {
+	if err := prepareIssueBundle(ctx, doer, bundle); err != nil {
+		return nil, err
+	}
+
+	issues := make(issues_model.IssueList, 0, len(bundle.Issues))
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		uploader := NewGiteaLocalUploader(ctx, doer, repo.OwnerName, repo.Name)
+		uploader.repo = repo
+		uploader.sameApp = true
+
+		labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, label := range labels {
+			uploader.labels[label.Name] = label
+		}
+		var newLabels []*base.Label
+		addLabel := func(label *base.Label) {
+			if _, ok := uploader.labels[label.Name]; ok {
+				return
+			}
+			for _, l := range newLabels {
+				if l.Name == label.Name {
+					return
+				}
+			}
+			newLabels = append(newLabels, label)
+		}
+		for _, label := range bundle.Labels {
+			addLabel(label)
+		}
+		for _, issue := range bundle.Issues {
+			for _, label := range issue.Labels {
+				addLabel(label)
+			}
+		}
+		if len(newLabels) > 0 {
+			if err := uploader.CreateLabels(newLabels...); err != nil {
+				return err
+			}
+		}
+
+		milestones, err := db.Find[issues_model.Milestone](ctx, issues_model.FindMilestoneOptions{RepoID: repo.ID})
+		if err != nil {
+			return err
+		}
+		for _, milestone := range milestones {
+			uploader.milestones[milestone.Name] = milestone.ID
+		}
+		var newMilestones []*base.Milestone
+		addMilestone := func(milestone *base.Milestone) {
+			if _, ok := uploader.milestones[milestone.Title]; ok {
+				return
+			}
+			for _, m := range newMilestones {
+				if m.Title == milestone.Title {
+					return
+				}
+			}
+			newMilestones = append(newMilestones, milestone)
+		}
+		for _, milestone := range bundle.Milestones {
+			addMilestone(milestone)
+		}
+		for _, issue := range bundle.Issues {
+			if issue.Milestone != "" {
+				addMilestone(&base.Milestone{Title: issue.Milestone, State: "open"})
+			}
+		}
+		if len(newMilestones) > 0 {
+			if err := uploader.CreateMilestones(newMilestones...); err != nil {
+				return err
+			}
+		}
+
+		// the numbers of the bundle are replaced by the next indexes of the repository
+		indexes := make(map[int64]int64, len(bundle.Issues))
+		for _, issue := range bundle.Issues {
+			index, err := db.GetNextResourceIndex(ctx, "issue_index", repo.ID)
+			if err != nil {
+				return err
+			}
+			indexes[issue.Number] = index
+			issue.Number = index
+		}
+		for _, comment := range bundle.Comments {
+			comment.IssueIndex = indexes[comment.IssueIndex]
+		}
+
+		if err := uploader.CreateIssues(bundle.Issues...); err != nil {
+			return err
+		}
+		if err := uploader.CreateComments(bundle.Comments...); err != nil {
+			return err
+		}
+		for _, issue := range bundle.Issues {
+			issues = append(issues, uploader.issues[issue.Number])
+		}
+		return models.UpdateRepoStats(ctx, repo.ID)
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		issue_indexer.UpdateIssueIndexer(ctx, issue.ID)
+	}
+	return issues, nil
+}
+
+// prepareIssueBundle checks the references of a bundle, which aren't checked by its schema,
+// and prepares the download of the attachments
+func prepareIssueBundle(ctx context.Context, doer *user_model.User, bundle *base.IssueBundle) error {
+	if len(bundle.Issues) == 0 {
+		return util.NewInvalidArgumentErrorf("there are no issues to import")
+	}
+
+	client := NewMigrationHTTPClient()
+	prepareAttachments := func(attachments []*base.Attachment) error {
+		if len(attachments) > 0 && !setting.Attachment.Enabled {
+			return util.NewInvalidArgumentErrorf("attachments are disabled")
+		}
+		for _, attachment := range attachments {
+			u, err := url.Parse(attachment.DownloadURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				return util.NewInvalidArgumentErrorf("attachment %q has an invalid download url", attachment.Name)
+			}
+			if err := IsMigrateURLAllowed(attachment.DownloadURL, doer); err != nil {
+				return util.NewInvalidArgumentErrorf("attachment %q can't be downloaded: %v", attachment.Name, err)
+			}
+			downloadURL := attachment.DownloadURL
+			attachment.DownloadFunc = func() (io.ReadCloser, error) {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+				if err != nil {
+					return nil, err
+				}
+				resp, err := client.Do(req)
+				if err != nil {
+					return nil, err
+				}
+				if resp.StatusCode != http.StatusOK {
+					resp.Body.Close()
+					return nil, util.NewInvalidArgumentErrorf("download of attachment %q failed: %s", attachment.Name, resp.Status)
+				}
+				return resp.Body, nil
+			}
+		}
+		return nil
+	}
+
+	numbers := make(map[int64]bool, len(bundle.Issues))
+	for _, issue := range bundle.Issues {
+		if issue.Number <= 0 || numbers[issue.Number] {
+			return util.NewInvalidArgumentErrorf("issue number %d is invalid or isn't unique", issue.Number)
+		}
+		numbers[issue.Number] = true
+		if strings.TrimSpace(issue.Title) == "" {
+			return util.NewInvalidArgumentErrorf("issue %d has no title", issue.Number)
+		}
+		switch issue.State {
+		case "":
+			issue.State = "open"
+		case "open", "closed":
+		default:
+			return util.NewInvalidArgumentErrorf("issue %d has an invalid state %q", issue.Number, issue.State)
+		}
+		if issue.State == "closed" && issue.Closed == nil {
+			now := time.Now()
+			issue.Closed = &now
+		}
+		if err := prepareAttachments(issue.Attachments); err != nil {
+			return err
+		}
+	}
+	for _, comment := range bundle.Comments {
+		if !numbers[comment.IssueIndex] {
+			return util.NewInvalidArgumentErrorf("comment references non existent issue %d", comment.IssueIndex)
+		}
+		if comment.CommentType != "" && !slices.Contains(issueBundleCommentTypes, issues_model.AsCommentType(comment.CommentType)) {
+			return util.NewInvalidArgumentErrorf("comment of issue %d has an unsupported type %q", comment.IssueIndex, comment.CommentType)
+		}
+		if err := prepareAttachments(comment.Attachments); err != nil {
+			return err
+		}
+	}
+	return nil
+}
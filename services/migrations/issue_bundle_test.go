@@ -0,0 +1,98 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	base "code.gitea.io/gitea/modules/migration"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"xorm.io/builder"
+)
+
+func TestExportImportIssues(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	source := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	target := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+
+	bundle, err := ExportIssues(db.DefaultContext, source)
+	require.NoError(t, err)
+	assert.Len(t, bundle.Labels, 2)
+	assert.Len(t, bundle.Milestones, 3)
+	require.Len(t, bundle.Issues, unittest.GetCount(t, &issues_model.Issue{}, builder.Eq{"repo_id": source.ID, "is_pull": false}))
+	issue1 := bundle.Issues[0]
+	assert.EqualValues(t, 1, issue1.Number)
+	assert.Equal(t, "issue1", issue1.Title)
+	assert.Equal(t, "user1", issue1.PosterName)
+	if assert.Len(t, issue1.Labels, 1) {
+		assert.Equal(t, "label1", issue1.Labels[0].Name)
+	}
+	if assert.Len(t, issue1.Reactions, 1) {
+		assert.Equal(t, "eyes", issue1.Reactions[0].Content)
+	}
+	var comment2 *base.Comment
+	for _, comment := range bundle.Comments {
+		if comment.Index == 2 {
+			comment2 = comment
+		}
+	}
+	if assert.NotNil(t, comment2) {
+		assert.EqualValues(t, 1, comment2.IssueIndex)
+		assert.Len(t, comment2.Reactions, 2)
+	}
+
+	// the attachments would be downloaded from the source, which isn't allowed for a local network
+	for _, issue := range bundle.Issues {
+		issue.Attachments = nil
+	}
+	for _, comment := range bundle.Comments {
+		comment.Attachments = nil
+	}
+	numIssues := unittest.GetCount(t, &issues_model.Issue{RepoID: target.ID})
+	issues, err := ImportIssues(db.DefaultContext, doer, target, bundle)
+	require.NoError(t, err)
+	require.Len(t, issues, len(bundle.Issues))
+	assert.Equal(t, numIssues+len(issues), unittest.GetCount(t, &issues_model.Issue{RepoID: target.ID}))
+
+	// the issues are numbered after the existing issues and keep their poster
+	imported := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issues[0].ID})
+	assert.Equal(t, bundle.Issues[0].Number, imported.Index)
+	assert.Greater(t, imported.Index, int64(numIssues))
+	assert.EqualValues(t, 1, imported.PosterID)
+	assert.Equal(t, "issue1", imported.Title)
+	label1 := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{RepoID: target.ID, Name: "label1"})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: imported.ID, LabelID: label1.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{RepoID: target.ID, Name: "milestone1"})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: imported.ID, Content: comment2.Content})
+	target = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: target.ID})
+	assert.Equal(t, numIssues+len(issues), target.NumIssues)
+
+	// importing twice doesn't duplicate the labels and the milestones
+	_, err = ImportIssues(db.DefaultContext, doer, target, &base.IssueBundle{
+		Issues: []*base.Issue{{Number: 1, Title: "again", Labels: []*base.Label{{Name: "label1"}}, Milestone: "milestone1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, unittest.GetCount(t, &issues_model.Label{RepoID: target.ID, Name: "label1"}))
+	assert.Equal(t, 1, unittest.GetCount(t, &issues_model.Milestone{RepoID: target.ID, Name: "milestone1"}))
+
+	_, err = ImportIssues(db.DefaultContext, doer, target, &base.IssueBundle{
+		Issues:   []*base.Issue{{Number: 1, Title: "issue"}},
+		Comments: []*base.Comment{{IssueIndex: 2, Content: "comment"}},
+	})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = ImportIssues(db.DefaultContext, doer, target, &base.IssueBundle{
+		Issues: []*base.Issue{{Number: 1, Title: "issue", Attachments: []*base.Attachment{{Name: "file", DownloadURL: "file:///etc/passwd"}}}},
+	})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
@@ -88,6 +88,13 @@ func AutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues
 	}
 }
 
+// PullRequestChecked notifies that the mergeability check of a pull request has finished to notifiers
+func PullRequestChecked(ctx context.Context, pr *issues_model.PullRequest) {
+	for _, notifier := range notifiers {
+		notifier.PullRequestChecked(ctx, pr)
+	}
+}
+
 // NewPullRequest notifies new pull request to notifiers
 func NewPullRequest(ctx context.Context, pr *issues_model.PullRequest, mentions []*user_model.User) {
 	if err := pr.LoadIssue(ctx); err != nil {
@@ -198,6 +205,27 @@ func IssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *iss
 	}
 }
 
+// IssueChangeType notifies change type to notifiers
+func IssueChangeType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTypeID int64) {
+	for _, notifier := range notifiers {
+		notifier.IssueChangeType(ctx, doer, issue, oldTypeID)
+	}
+}
+
+// IssueChangeFields notifies change of the custom field values to notifiers
+func IssueChangeFields(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) {
+	for _, notifier := range notifiers {
+		notifier.IssueChangeFields(ctx, doer, issue)
+	}
+}
+
+// IssueChangeVote notifies a vote cast or removed to notifiers
+func IssueChangeVote(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, voted bool) {
+	for _, notifier := range notifiers {
+		notifier.IssueChangeVote(ctx, doer, issue, voted)
+	}
+}
+
 // IssueChangeContent notifies change content to notifiers
 func IssueChangeContent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldContent string) {
 	for _, notifier := range notifiers {
@@ -361,6 +389,13 @@ func PackageDelete(ctx context.Context, doer *user_model.User, pd *packages_mode
 	}
 }
 
+// PackageRemove notifies deletion of a package with all its versions to notifiers
+func PackageRemove(ctx context.Context, doer *user_model.User, pds []*packages_model.PackageDescriptor) {
+	for _, notifier := range notifiers {
+		notifier.PackageRemove(ctx, doer, pds)
+	}
+}
+
 // ChangeDefaultBranch notifies change default branch to notifiers
 func ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 	for _, notifier := range notifiers {
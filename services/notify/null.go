@@ -60,6 +60,10 @@ func (*NullNotifier) MergePullRequest(ctx context.Context, doer *user_model.User
 func (*NullNotifier) AutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
 }
 
+// PullRequestChecked places a place holder function
+func (*NullNotifier) PullRequestChecked(ctx context.Context, pr *issues_model.PullRequest) {
+}
+
 // PullRequestSynchronized places a place holder function
 func (*NullNotifier) PullRequestSynchronized(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
 }
@@ -112,6 +116,18 @@ func (*NullNotifier) DeleteRelease(ctx context.Context, doer *user_model.User, r
 func (*NullNotifier) IssueChangeMilestone(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldMilestoneID int64) {
 }
 
+// IssueChangeType places a place holder function
+func (*NullNotifier) IssueChangeType(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTypeID int64) {
+}
+
+// IssueChangeFields places a place holder function
+func (*NullNotifier) IssueChangeFields(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) {
+}
+
+// IssueChangeVote places a place holder function
+func (*NullNotifier) IssueChangeVote(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, voted bool) {
+}
+
 // IssueChangeContent places a place holder function
 func (*NullNotifier) IssueChangeContent(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldContent string) {
 }
@@ -205,6 +221,10 @@ func (*NullNotifier) PackageCreate(ctx context.Context, doer *user_model.User, p
 func (*NullNotifier) PackageDelete(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
 }
 
+// PackageRemove places a place holder function
+func (*NullNotifier) PackageRemove(ctx context.Context, doer *user_model.User, pds []*packages_model.PackageDescriptor) {
+}
+
 // ChangeDefaultBranch places a place holder function
 func (*NullNotifier) ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 }
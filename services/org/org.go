@@ -9,6 +9,8 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
 	org_model "code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -52,6 +54,15 @@ func DeleteOrganization(ctx context.Context, org *org_model.Organization, purge
 		return fmt.Errorf("DeleteOrganization: %w", err)
 	}
 
+	if err := git_model.DeleteManagedGitHook(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteManagedGitHook: %w", err)
+	}
+
+	// the organization has no repositories anymore, so no issue can have its milestones
+	if _, err := db.DeleteByBean(ctx, &issues_model.Milestone{OrgID: org.ID}); err != nil {
+		return fmt.Errorf("DeleteMilestones: %w", err)
+	}
+
 	if err := committer.Commit(); err != nil {
 		return err
 	}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"strconv"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MaxAttestationSize is the maximum size of the content of a signature or an attestation
+const MaxAttestationSize = 1 << 20
+
+var ErrSignatureRequired = util.NewPermissionDeniedErrorf("the package versions of the owner must be signed")
+
+// IsSignatureRequired returns whether uploaded package versions of the owner must be signed
+func IsSignatureRequired(ctx context.Context, ownerID int64) (bool, error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyPackagesRequireSignatures)
+	if err != nil {
+		return false, err
+	}
+	required, _ := strconv.ParseBool(value)
+	return required, nil
+}
+
+// SetSignatureRequired sets whether uploaded package versions of the owner must be signed
+func SetSignatureRequired(ctx context.Context, ownerID int64, required bool) error {
+	if !required {
+		return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyPackagesRequireSignatures)
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyPackagesRequireSignatures, strconv.FormatBool(required))
+}
+
+// AddAttestation stores a signature or an attestation of the package version
+func AddAttestation(ctx context.Context, doer *user_model.User, pv *packages_model.PackageVersion, predicateType, mediaType, content string) (*packages_model.PackageAttestation, error) {
+	if predicateType == "" {
+		return nil, util.NewInvalidArgumentErrorf("the predicate type must not be empty")
+	}
+	if content == "" {
+		return nil, util.NewInvalidArgumentErrorf("the attestation must not be empty")
+	}
+	if len(content) > MaxAttestationSize {
+		return nil, util.NewInvalidArgumentErrorf("the attestation must not exceed %d bytes", MaxAttestationSize)
+	}
+
+	pa := &packages_model.PackageAttestation{
+		VersionID:     pv.ID,
+		PredicateType: predicateType,
+		MediaType:     mediaType,
+		Content:       content,
+	}
+	if doer != nil {
+		pa.CreatorID = doer.ID
+	}
+	if err := packages_model.InsertAttestation(ctx, pa); err != nil {
+		return nil, err
+	}
+	return pa, nil
+}
+
+// RemoveAttestation deletes a signature or an attestation of the package version if the version is mutable
+func RemoveAttestation(ctx context.Context, doer *user_model.User, pv *packages_model.PackageVersion, attestationID int64) error {
+	pa, err := packages_model.GetAttestationByID(ctx, pv.ID, attestationID)
+	if err != nil {
+		return err
+	}
+	if pa.ReferenceVersionID != 0 {
+		return util.NewInvalidArgumentErrorf("the attestation is stored in another package version and is removed together with it")
+	}
+	if err := CheckVersionIsMutable(ctx, doer, pv); err != nil {
+		return err
+	}
+	return packages_model.DeleteAttestationByID(ctx, pa.ID)
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+func init() {
+	notify_service.RegisterNotifier(&auditNotifier{})
+}
+
+// auditNotifier records the published and deleted packages in the audit trail of their owners
+type auditNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &auditNotifier{}
+
+func (*auditNotifier) PackageCreate(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
+	recordAudit(ctx, doer, packages_model.AuditActionPublish, pd.Package, pd.Version.Version)
+}
+
+func (*auditNotifier) PackageDelete(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
+	recordAudit(ctx, doer, packages_model.AuditActionDeleteVersion, pd.Package, pd.Version.Version)
+}
+
+func (*auditNotifier) PackageRemove(ctx context.Context, doer *user_model.User, pds []*packages_model.PackageDescriptor) {
+	if len(pds) == 0 {
+		return
+	}
+	recordAudit(ctx, doer, packages_model.AuditActionDeletePackage, pds[0].Package, "")
+}
+
+func recordAudit(ctx context.Context, doer *user_model.User, action packages_model.AuditAction, p *packages_model.Package, version string) {
+	if err := packages_model.InsertAudit(ctx, newAudit(doer, action, p, version)); err != nil {
+		log.Error("Error recording the %s action of package %d: %v", action, p.ID, err)
+	}
+}
+
+func newAudit(doer *user_model.User, action packages_model.AuditAction, p *packages_model.Package, version string) *packages_model.PackageAudit {
+	pa := &packages_model.PackageAudit{
+		OwnerID: p.OwnerID,
+		Action:  action,
+		Type:    p.Type,
+		Name:    p.Name,
+		Version: version,
+	}
+	if doer != nil {
+		pa.DoerID = doer.ID
+	}
+	return pa
+}
+
+// SetPackageImmutable sets if the versions of the package are immutable and records the change in the audit trail
+func SetPackageImmutable(ctx context.Context, doer *user_model.User, p *packages_model.Package, immutable bool) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := packages_model.SetImmutable(ctx, p.ID, immutable); err != nil {
+			return err
+		}
+
+		action := packages_model.AuditActionSetImmutable
+		if !immutable {
+			action = packages_model.AuditActionUnsetImmutable
+		}
+		return packages_model.InsertAudit(ctx, newAudit(doer, action, p, ""))
+	})
+}
+
+// RecordCleanupAudit records the removal of a package version by a cleanup rule in the audit trail
+func RecordCleanupAudit(ctx context.Context, p *packages_model.Package, pv *packages_model.PackageVersion) error {
+	return packages_model.InsertAudit(ctx, newAudit(nil, packages_model.AuditActionDeleteVersion, p, pv.Version))
+}
@@ -19,6 +19,7 @@ import (
 	cargo_service "code.gitea.io/gitea/services/packages/cargo"
 	container_service "code.gitea.io/gitea/services/packages/container"
 	debian_service "code.gitea.io/gitea/services/packages/debian"
+	maven_service "code.gitea.io/gitea/services/packages/maven"
 	rpm_service "code.gitea.io/gitea/services/packages/rpm"
 )
 
@@ -28,9 +29,111 @@ func CleanupTask(ctx context.Context, olderThan time.Duration) error {
 		return err
 	}
 
+	if err := maven_service.CleanupSnapshotVersions(ctx); err != nil {
+		return err
+	}
+
 	return CleanupExpiredData(ctx, olderThan)
 }
 
+// maxVersionsToRemovePerPackage bounds the versions of a package removed by a rule at once, the next runs remove the others
+const maxVersionsToRemovePerPackage = 200
+
+// GetVersionsToRemove returns the versions the rule removes when it's executed, the newest first for each package.
+// For container images, the kept count applies to the tags, the "latest" tag, the protected tags and the manifests
+// referenced by an index are always kept, and the untagged manifests older than RemoveUntaggedDays are removed whatever the other criteria.
+// The versions of immutable packages are never removed.
+func GetVersionsToRemove(ctx context.Context, pcr *packages_model.PackageCleanupRule) ([]*packages_model.PackageVersion, error) {
+	if err := pcr.CompiledPattern(); err != nil {
+		return nil, fmt.Errorf("CleanupRule [%d]: CompilePattern failed: %w", pcr.ID, err)
+	}
+
+	olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
+	untaggedOlderThan := time.Now().AddDate(0, 0, -pcr.RemoveUntaggedDays)
+
+	packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
+	if err != nil {
+		return nil, fmt.Errorf("CleanupRule [%d]: GetPackagesByType failed: %w", pcr.ID, err)
+	}
+
+	versionsToRemove := make([]*packages_model.PackageVersion, 0, 10)
+	for _, p := range packages {
+		if p.IsImmutable {
+			log.Debug("Rule[%d]: keep '%s' (immutable)", pcr.ID, p.Name)
+			continue
+		}
+
+		pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+			PackageID:  p.ID,
+			IsInternal: optional.Some(false),
+			Sort:       packages_model.SortCreatedDesc,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("CleanupRule [%d]: SearchVersions failed: %w", pcr.ID, err)
+		}
+
+		kept, removed := 0, 0
+		for _, pv := range pvs {
+			if removed >= maxVersionsToRemovePerPackage {
+				break
+			}
+
+			if pcr.Type == packages_model.TypeContainer {
+				if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
+					return nil, fmt.Errorf("CleanupRule [%d]: container.ShouldBeSkipped failed: %w", pcr.ID, err)
+				} else if skip {
+					log.Debug("Rule[%d]: keep '%s/%s' (container)", pcr.ID, p.Name, pv.Version)
+					continue
+				}
+
+				if container_service.IsUntagged(pv) {
+					if pcr.RemoveUntaggedDays > 0 {
+						if pv.CreatedUnix.AsLocalTime().After(untaggedOlderThan) {
+							log.Debug("Rule[%d]: keep '%s/%s' (remove untagged days)", pcr.ID, p.Name, pv.Version)
+						} else {
+							log.Debug("Rule[%d]: remove '%s/%s' (untagged)", pcr.ID, p.Name, pv.Version)
+							versionsToRemove = append(versionsToRemove, pv)
+							removed++
+						}
+						continue
+					}
+				} else if kept < pcr.KeepCount {
+					kept++
+					log.Debug("Rule[%d]: keep '%s/%s' (keep count)", pcr.ID, p.Name, pv.Version)
+					continue
+				}
+			} else if kept < pcr.KeepCount {
+				kept++
+				log.Debug("Rule[%d]: keep '%s/%s' (keep count)", pcr.ID, p.Name, pv.Version)
+				continue
+			}
+
+			toMatch := pv.LowerVersion
+			if pcr.MatchFullName {
+				toMatch = p.LowerName + "/" + pv.LowerVersion
+			}
+
+			if pcr.KeepPatternMatcher != nil && pcr.KeepPatternMatcher.MatchString(toMatch) {
+				log.Debug("Rule[%d]: keep '%s/%s' (keep pattern)", pcr.ID, p.Name, pv.Version)
+				continue
+			}
+			if pv.CreatedUnix.AsLocalTime().After(olderThan) {
+				log.Debug("Rule[%d]: keep '%s/%s' (remove days)", pcr.ID, p.Name, pv.Version)
+				continue
+			}
+			if pcr.RemovePatternMatcher != nil && !pcr.RemovePatternMatcher.MatchString(toMatch) {
+				log.Debug("Rule[%d]: keep '%s/%s' (remove pattern)", pcr.ID, p.Name, pv.Version)
+				continue
+			}
+
+			log.Debug("Rule[%d]: remove '%s/%s'", pcr.ID, p.Name, pv.Version)
+			versionsToRemove = append(versionsToRemove, pv)
+			removed++
+		}
+	}
+	return versionsToRemove, nil
+}
+
 func ExecuteCleanupRules(outerCtx context.Context) error {
 	ctx, committer, err := db.TxContext(outerCtx)
 	if err != nil {
@@ -45,81 +148,43 @@ func ExecuteCleanupRules(outerCtx context.Context) error {
 		default:
 		}
 
-		if err := pcr.CompiledPattern(); err != nil {
-			return fmt.Errorf("CleanupRule [%d]: CompilePattern failed: %w", pcr.ID, err)
-		}
-
-		olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
-
-		packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
+		pvs, err := GetVersionsToRemove(ctx, pcr)
 		if err != nil {
-			return fmt.Errorf("CleanupRule [%d]: GetPackagesByType failed: %w", pcr.ID, err)
+			return err
 		}
 
-		anyVersionDeleted := false
-		for _, p := range packages {
-			pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
-				PackageID:  p.ID,
-				IsInternal: optional.Some(false),
-				Sort:       packages_model.SortCreatedDesc,
-				Paginator:  db.NewAbsoluteListOptions(pcr.KeepCount, 200),
-			})
-			if err != nil {
-				return fmt.Errorf("CleanupRule [%d]: SearchVersions failed: %w", pcr.ID, err)
-			}
-			versionDeleted := false
-			for _, pv := range pvs {
-				if pcr.Type == packages_model.TypeContainer {
-					if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
-						return fmt.Errorf("CleanupRule [%d]: container.ShouldBeSkipped failed: %w", pcr.ID, err)
-					} else if skip {
-						log.Debug("Rule[%d]: keep '%s/%s' (container)", pcr.ID, p.Name, pv.Version)
-						continue
-					}
-				}
-
-				toMatch := pv.LowerVersion
-				if pcr.MatchFullName {
-					toMatch = p.LowerName + "/" + pv.LowerVersion
-				}
-
-				if pcr.KeepPatternMatcher != nil && pcr.KeepPatternMatcher.MatchString(toMatch) {
-					log.Debug("Rule[%d]: keep '%s/%s' (keep pattern)", pcr.ID, p.Name, pv.Version)
-					continue
-				}
-				if pv.CreatedUnix.AsLocalTime().After(olderThan) {
-					log.Debug("Rule[%d]: keep '%s/%s' (remove days)", pcr.ID, p.Name, pv.Version)
-					continue
-				}
-				if pcr.RemovePatternMatcher != nil && !pcr.RemovePatternMatcher.MatchString(toMatch) {
-					log.Debug("Rule[%d]: keep '%s/%s' (remove pattern)", pcr.ID, p.Name, pv.Version)
-					continue
-				}
-
-				log.Debug("Rule[%d]: remove '%s/%s'", pcr.ID, p.Name, pv.Version)
-
-				if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
-					return fmt.Errorf("CleanupRule [%d]: DeletePackageVersionAndReferences failed: %w", pcr.ID, err)
+		packagesByID := make(map[int64]*packages_model.Package)
+		for _, pv := range pvs {
+			p, ok := packagesByID[pv.PackageID]
+			if !ok {
+				p, err = packages_model.GetPackageByID(ctx, pv.PackageID)
+				if err != nil {
+					return fmt.Errorf("CleanupRule [%d]: GetPackageByID failed: %w", pcr.ID, err)
 				}
+				packagesByID[pv.PackageID] = p
+			}
 
-				versionDeleted = true
-				anyVersionDeleted = true
+			if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
+				return fmt.Errorf("CleanupRule [%d]: DeletePackageVersionAndReferences failed: %w", pcr.ID, err)
+			}
+			if err := packages_service.RecordCleanupAudit(ctx, p, pv); err != nil {
+				return fmt.Errorf("CleanupRule [%d]: RecordCleanupAudit failed: %w", pcr.ID, err)
 			}
+		}
 
-			if versionDeleted {
-				if pcr.Type == packages_model.TypeCargo {
-					owner, err := user_model.GetUserByID(ctx, pcr.OwnerID)
-					if err != nil {
-						return fmt.Errorf("GetUserByID failed: %w", err)
-					}
-					if err := cargo_service.UpdatePackageIndexIfExists(ctx, owner, owner, p.ID); err != nil {
-						return fmt.Errorf("CleanupRule [%d]: cargo.UpdatePackageIndexIfExists failed: %w", pcr.ID, err)
-					}
+		if pcr.Type == packages_model.TypeCargo && len(packagesByID) > 0 {
+			owner, err := user_model.GetUserByID(ctx, pcr.OwnerID)
+			if err != nil {
+				return fmt.Errorf("GetUserByID failed: %w", err)
+			}
+			for packageID := range packagesByID {
+				if err := cargo_service.UpdatePackageIndexIfExists(ctx, owner, owner, packageID); err != nil {
+					return fmt.Errorf("CleanupRule [%d]: cargo.UpdatePackageIndexIfExists failed: %w", pcr.ID, err)
 				}
 			}
 		}
 
-		if anyVersionDeleted {
+		if len(pvs) > 0 {
 			if pcr.Type == packages_model.TypeDebian {
 				if err := debian_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
 					return fmt.Errorf("CleanupRule [%d]: debian.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
@@ -79,6 +79,12 @@ func cleanupExpiredUploadedBlobs(ctx context.Context, olderThan time.Duration) e
 	return nil
 }
 
+// IsUntagged returns whether the version is a manifest without a tag, its version is its digest
+func IsUntagged(pv *packages_model.PackageVersion) bool {
+	return digest.Digest(pv.LowerVersion).Validate() == nil
+}
+
+// ShouldBeSkipped returns whether the version is always kept by the rule, the patterns of the rule must be compiled
 func ShouldBeSkipped(ctx context.Context, pcr *packages_model.PackageCleanupRule, p *packages_model.Package, pv *packages_model.PackageVersion) (bool, error) {
 	// Always skip the "latest" tag
 	if pv.LowerVersion == "latest" {
@@ -86,7 +92,7 @@ func ShouldBeSkipped(ctx context.Context, pcr *packages_model.PackageCleanupRule
 	}
 
 	// Check if the version is a digest (or untagged)
-	if digest.Digest(pv.LowerVersion).Validate() == nil {
+	if IsUntagged(pv) {
 		// Check if there is another manifest referencing this version
 		has, err := packages_model.ExistVersion(ctx, &packages_model.PackageSearchOptions{
 			PackageID: p.ID,
@@ -102,6 +108,8 @@ func ShouldBeSkipped(ctx context.Context, pcr *packages_model.PackageCleanupRule
 		if has {
 			return true, nil
 		}
+	} else if pcr.IsProtectedTag(pv.Version) {
+		return true, nil
 	}
 
 	return false, nil
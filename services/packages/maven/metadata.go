@@ -0,0 +1,152 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package maven
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	maven_module "code.gitea.io/gitea/modules/packages/maven"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+const (
+	metadataFilename = "maven-metadata.xml"
+	extensionPom     = ".pom"
+)
+
+// RebuildMetadata rebuilds the metadata of all versions of the Maven package from their stored files.
+// The metadata of each version is parsed again from its newest pom file
+// and the maven-metadata.xml of the SNAPSHOT versions is recreated from the files of their builds.
+func RebuildMetadata(ctx context.Context, doer *user_model.User, p *packages_model.Package) error {
+	if p.Type != packages_model.TypeMaven {
+		return util.NewInvalidArgumentErrorf("package is not a Maven package")
+	}
+
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, p.OwnerID, p.Type, p.Name)
+	if err != nil {
+		return err
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		for _, pv := range pvs {
+			pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
+			if err != nil {
+				return err
+			}
+
+			if err := updateVersionMetadata(ctx, pv, pfs); err != nil {
+				return fmt.Errorf("updating the metadata of version %s failed: %w", pv.Version, err)
+			}
+			if err := rebuildSnapshotMetadata(ctx, doer, p, pv, pfs); err != nil {
+				return fmt.Errorf("rebuilding the %s of version %s failed: %w", metadataFilename, pv.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// updateVersionMetadata parses the metadata of the version from its newest pom file
+func updateVersionMetadata(ctx context.Context, pv *packages_model.PackageVersion, pfs []*packages_model.PackageFile) error {
+	var pom *packages_model.PackageFile
+	pomBuildNumber := -1
+	for _, pf := range pfs {
+		if !strings.HasSuffix(pf.LowerName, extensionPom) {
+			continue
+		}
+
+		buildNumber := 0
+		if sf := maven_module.ParseSnapshotFilename(pf.Name, pv.Version); sf != nil {
+			buildNumber = sf.BuildNumber
+		}
+		if buildNumber > pomBuildNumber || buildNumber == pomBuildNumber && pf.ID > pom.ID {
+			pom, pomBuildNumber = pf, buildNumber
+		}
+	}
+	if pom == nil {
+		return nil
+	}
+
+	pb, err := packages_model.GetBlobByID(ctx, pom.BlobID)
+	if err != nil {
+		return err
+	}
+
+	r, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pb.HashSHA256))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	metadata, err := maven_module.ParsePackageMetaData(r)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	if pv.MetadataJSON == string(raw) {
+		return nil
+	}
+
+	pv.MetadataJSON = string(raw)
+	return packages_model.UpdateVersion(ctx, pv)
+}
+
+// rebuildSnapshotMetadata recreates the maven-metadata.xml of a SNAPSHOT version from the files of its builds
+func rebuildSnapshotMetadata(ctx context.Context, doer *user_model.User, p *packages_model.Package, pv *packages_model.PackageVersion, pfs []*packages_model.PackageFile) error {
+	if !maven_module.IsSnapshotVersion(pv.Version) {
+		return nil
+	}
+
+	files := make([]*maven_module.SnapshotFile, 0, len(pfs))
+	for _, pf := range pfs {
+		if sf := maven_module.ParseSnapshotFilename(pf.Name, pv.Version); sf != nil {
+			files = append(files, sf)
+		}
+	}
+	if len(files) == 0 {
+		// The version was published without unique build names, there is nothing to list
+		return nil
+	}
+
+	// The package name consists of the group and the artifact id
+	groupID := strings.TrimSuffix(p.Name, "-"+files[0].ArtifactID)
+
+	content, err := xml.Marshal(maven_module.CreateSnapshotMetadata(groupID, pv.Version, files))
+	if err != nil {
+		return err
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(bytes.NewReader(append([]byte(xml.Header), content...)))
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	_, err = packages_service.AddFileToPackageVersionInternal(
+		ctx,
+		pv,
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: metadataFilename,
+			},
+			Creator:           doer,
+			Data:              buf,
+			OverwriteExisting: true,
+		},
+	)
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package maven
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	maven_module "code.gitea.io/gitea/modules/packages/maven"
+	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// CleanupSnapshotVersions removes the files of the older builds of the SNAPSHOT versions of all Maven packages.
+// The newest RetainMavenSnapshotBuilds builds of each version are kept and the versions of immutable packages are skipped.
+func CleanupSnapshotVersions(outerCtx context.Context) error {
+	retainBuilds := setting.Packages.RetainMavenSnapshotBuilds
+	if retainBuilds < 1 {
+		return nil
+	}
+
+	return db.WithTx(outerCtx, func(ctx context.Context) error {
+		pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+			Type: packages_model.TypeMaven,
+			Version: packages_model.SearchValue{
+				Value: "-snapshot",
+			},
+			IsInternal: optional.Some(false),
+		})
+		if err != nil {
+			return err
+		}
+
+		packagesByID := make(map[int64]*packages_model.Package)
+		for _, pv := range pvs {
+			select {
+			case <-outerCtx.Done():
+				return db.ErrCancelledf("While cleaning up the Maven SNAPSHOT versions")
+			default:
+			}
+
+			if !maven_module.IsSnapshotVersion(pv.Version) {
+				continue
+			}
+
+			p, ok := packagesByID[pv.PackageID]
+			if !ok {
+				p, err = packages_model.GetPackageByID(ctx, pv.PackageID)
+				if err != nil {
+					return err
+				}
+				packagesByID[pv.PackageID] = p
+			}
+			if p.IsImmutable {
+				continue
+			}
+
+			if err := removeSnapshotBuilds(ctx, p, pv, retainBuilds); err != nil {
+				return fmt.Errorf("removing the builds of %s/%s failed: %w", p.Name, pv.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// removeSnapshotBuilds removes the files of all but the newest builds of the SNAPSHOT version
+func removeSnapshotBuilds(ctx context.Context, p *packages_model.Package, pv *packages_model.PackageVersion, retainBuilds int) error {
+	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
+	if err != nil {
+		return err
+	}
+
+	buildNumbers := make(map[int]struct{})
+	for _, pf := range pfs {
+		if sf := maven_module.ParseSnapshotFilename(pf.Name, pv.Version); sf != nil {
+			buildNumbers[sf.BuildNumber] = struct{}{}
+		}
+	}
+	if len(buildNumbers) <= retainBuilds {
+		return nil
+	}
+
+	sorted := make([]int, 0, len(buildNumbers))
+	for buildNumber := range buildNumbers {
+		sorted = append(sorted, buildNumber)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	oldestRetained := sorted[retainBuilds-1]
+
+	kept := make([]*packages_model.PackageFile, 0, len(pfs))
+	for _, pf := range pfs {
+		if sf := maven_module.ParseSnapshotFilename(pf.Name, pv.Version); sf != nil && sf.BuildNumber < oldestRetained {
+			log.Debug("Maven cleanup: remove '%s/%s/%s'", p.Name, pv.Version, pf.Name)
+			if err := packages_service.DeletePackageFile(ctx, pf); err != nil {
+				return err
+			}
+			continue
+		}
+		kept = append(kept, pf)
+	}
+
+	return rebuildSnapshotMetadata(ctx, user_model.NewGhostUser(), p, pv, kept)
+}
@@ -22,13 +22,15 @@ import (
 	packages_module "code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
 	notify_service "code.gitea.io/gitea/services/notify"
 )
 
 var (
-	ErrQuotaTypeSize   = errors.New("maximum allowed package type size exceeded")
-	ErrQuotaTotalSize  = errors.New("maximum allowed package storage quota exceeded")
-	ErrQuotaTotalCount = errors.New("maximum allowed package count exceeded")
+	ErrQuotaTypeSize    = errors.New("maximum allowed package type size exceeded")
+	ErrQuotaTotalSize   = errors.New("maximum allowed package storage quota exceeded")
+	ErrQuotaTotalCount  = errors.New("maximum allowed package count exceeded")
+	ErrPackageImmutable = util.NewPermissionDeniedErrorf("the versions of the package are immutable")
 )
 
 // PackageInfo describes a package
@@ -286,6 +288,10 @@ func addFileToPackageVersionUnchecked(ctx context.Context, pv *packages_model.Pa
 				return pf, pb, !exists, nil
 			}
 
+			if err := CheckVersionIsMutable(ctx, pfci.Creator, pv); err != nil {
+				return nil, pb, !exists, err
+			}
+
 			if err := packages_model.DeleteAllProperties(ctx, packages_model.PropertyTypeFile, pf.ID); err != nil {
 				return nil, pb, !exists, err
 			}
@@ -377,6 +383,8 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		typeSpecificSize = setting.Packages.LimitSizeGo
 	case packages_model.TypeHelm:
 		typeSpecificSize = setting.Packages.LimitSizeHelm
+	case packages_model.TypeJulia:
+		typeSpecificSize = setting.Packages.LimitSizeJulia
 	case packages_model.TypeMaven:
 		typeSpecificSize = setting.Packages.LimitSizeMaven
 	case packages_model.TypeNpm:
@@ -393,6 +401,8 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		typeSpecificSize = setting.Packages.LimitSizeRubyGems
 	case packages_model.TypeSwift:
 		typeSpecificSize = setting.Packages.LimitSizeSwift
+	case packages_model.TypeTerraform:
+		typeSpecificSize = setting.Packages.LimitSizeTerraform
 	case packages_model.TypeVagrant:
 		typeSpecificSize = setting.Packages.LimitSizeVagrant
 	}
@@ -400,7 +410,12 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		return ErrQuotaTypeSize
 	}
 
-	if setting.Packages.LimitTotalOwnerSize > -1 {
+	totalSizeLimit, _, err := GetTotalSizeLimit(ctx, owner.ID)
+	if err != nil {
+		log.Error("GetTotalSizeLimit failed: %v", err)
+		return err
+	}
+	if totalSizeLimit > -1 {
 		totalSize, err := packages_model.CalculateFileSize(ctx, &packages_model.PackageFileSearchOptions{
 			OwnerID: owner.ID,
 		})
@@ -408,7 +423,7 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 			log.Error("CalculateFileSize failed: %v", err)
 			return err
 		}
-		if totalSize+uploadSize > setting.Packages.LimitTotalOwnerSize {
+		if totalSize+uploadSize > totalSizeLimit {
 			return ErrQuotaTotalSize
 		}
 	}
@@ -474,6 +489,10 @@ func RemovePackageVersion(ctx context.Context, doer *user_model.User, pv *packag
 	}
 	defer committer.Close()
 
+	if err := CheckVersionIsMutable(dbCtx, doer, pv); err != nil {
+		return err
+	}
+
 	pd, err := packages_model.GetPackageDescriptor(dbCtx, pv)
 	if err != nil {
 		return err
@@ -494,11 +513,73 @@ func RemovePackageVersion(ctx context.Context, doer *user_model.User, pv *packag
 	return nil
 }
 
+// RemovePackage deletes all versions of the package, the package itself is deleted if it has no internal versions
+func RemovePackage(ctx context.Context, doer *user_model.User, p *packages_model.Package) error {
+	var pds []*packages_model.PackageDescriptor
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+			PackageID:  p.ID,
+			IsInternal: optional.Some(false),
+		})
+		if err != nil {
+			return err
+		}
+		if len(pvs) == 0 {
+			return packages_model.ErrPackageNotExist
+		}
+
+		if err := CheckVersionIsMutable(ctx, doer, pvs[0]); err != nil {
+			return err
+		}
+
+		pds, err = packages_model.GetPackageDescriptors(ctx, pvs)
+		if err != nil {
+			return err
+		}
+
+		log.Trace("Deleting package: %v", p.ID)
+
+		for _, pv := range pvs {
+			if err := DeletePackageVersionAndReferences(ctx, pv); err != nil {
+				return err
+			}
+		}
+
+		has, err := packages_model.ExistVersion(ctx, &packages_model.PackageSearchOptions{PackageID: p.ID})
+		if err != nil {
+			return err
+		}
+		if has {
+			return nil
+		}
+		if err := packages_model.DeleteAllProperties(ctx, packages_model.PropertyTypePackage, p.ID); err != nil {
+			return err
+		}
+		return packages_model.DeletePackageByID(ctx, p.ID)
+	}); err != nil {
+		return err
+	}
+
+	notify_service.PackageRemove(ctx, doer, pds)
+
+	return nil
+}
+
 // RemovePackageFileAndVersionIfUnreferenced deletes the package file and the version if there are no referenced files afterwards
 func RemovePackageFileAndVersionIfUnreferenced(ctx context.Context, doer *user_model.User, pf *packages_model.PackageFile) error {
 	var pd *packages_model.PackageDescriptor
 
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		pv, err := packages_model.GetVersionByID(ctx, pf.VersionID)
+		if err != nil {
+			return err
+		}
+
+		if err := CheckVersionIsMutable(ctx, doer, pv); err != nil {
+			return err
+		}
+
 		if err := DeletePackageFile(ctx, pf); err != nil {
 			return err
 		}
@@ -508,11 +589,6 @@ func RemovePackageFileAndVersionIfUnreferenced(ctx context.Context, doer *user_m
 			return err
 		}
 		if !has {
-			pv, err := packages_model.GetVersionByID(ctx, pf.VersionID)
-			if err != nil {
-				return err
-			}
-
 			pd, err = packages_model.GetPackageDescriptor(ctx, pv)
 			if err != nil {
 				return err
@@ -535,12 +611,33 @@ func RemovePackageFileAndVersionIfUnreferenced(ctx context.Context, doer *user_m
 	return nil
 }
 
-// DeletePackageVersionAndReferences deletes the package version and its properties and files
+// CheckVersionIsMutable checks if the files of the package version may be overwritten or deleted by the doer.
+// The versions of an immutable package can only be changed by admins.
+func CheckVersionIsMutable(ctx context.Context, doer *user_model.User, pv *packages_model.PackageVersion) error {
+	if doer != nil && doer.IsAdmin {
+		return nil
+	}
+
+	p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
+	if err != nil {
+		return err
+	}
+	if p.IsImmutable {
+		return ErrPackageImmutable
+	}
+	return nil
+}
+
+// DeletePackageVersionAndReferences deletes the package version and its properties, attestations and files
 func DeletePackageVersionAndReferences(ctx context.Context, pv *packages_model.PackageVersion) error {
 	if err := packages_model.DeleteAllProperties(ctx, packages_model.PropertyTypeVersion, pv.ID); err != nil {
 		return err
 	}
 
+	if err := packages_model.DeleteAttestationsByVersionID(ctx, pv.ID); err != nil {
+		return err
+	}
+
 	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
 	if err != nil {
 		return err
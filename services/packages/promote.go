@@ -0,0 +1,244 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/util"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// unpromotableTypes are the types of packages which are served from repository files built for the whole owner
+var unpromotableTypes = []packages_model.Type{
+	packages_model.TypeAlpine,
+	packages_model.TypeCargo,
+	packages_model.TypeDebian,
+	packages_model.TypeRpm,
+}
+
+// IsPromotableType returns whether package versions of the type can be promoted to another owner
+func IsPromotableType(packageType packages_model.Type) bool {
+	return !slices.Contains(unpromotableTypes, packageType)
+}
+
+// PromotePackageVersion copies the package version with its metadata, properties and files to the target owner.
+// The copied files share the blobs of the source files. The owner the version was promoted from is stored in a version property.
+func PromotePackageVersion(ctx context.Context, doer, target *user_model.User, pd *packages_model.PackageDescriptor) (*packages_model.PackageDescriptor, error) {
+	if !IsPromotableType(pd.Package.Type) {
+		return nil, util.NewInvalidArgumentErrorf("packages of type %s can't be promoted", pd.Package.Type)
+	}
+	if pd.Version.IsInternal {
+		return nil, util.NewInvalidArgumentErrorf("internal package versions can't be promoted")
+	}
+	if target.ID == pd.Owner.ID {
+		return nil, util.NewInvalidArgumentErrorf("the package version is already owned by %s", target.Name)
+	}
+
+	var pv *packages_model.PackageVersion
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		p, err := getOrCreatePromotedPackage(ctx, target, pd)
+		if err != nil {
+			return err
+		}
+
+		pv, err = copyPackageVersion(ctx, doer, target, p, pd)
+		if err != nil {
+			return err
+		}
+
+		// the manifests referenced by a multi-platform image are stored as untagged versions of the same package
+		if p.Type == packages_model.TypeContainer {
+			for _, pvp := range pd.VersionProperties {
+				if pvp.Name != container_module.PropertyManifestReference {
+					continue
+				}
+
+				if _, err := packages_model.GetVersionByNameAndVersion(ctx, target.ID, p.Type, p.Name, pvp.Value); err == nil {
+					continue
+				} else if err != packages_model.ErrPackageNotExist {
+					return err
+				}
+
+				refPv, err := packages_model.GetVersionByNameAndVersion(ctx, pd.Owner.ID, p.Type, p.Name, pvp.Value)
+				if err != nil {
+					return err
+				}
+				refPd, err := packages_model.GetPackageDescriptor(ctx, refPv)
+				if err != nil {
+					return err
+				}
+				if _, err := copyPackageVersion(ctx, doer, target, p, refPd); err != nil {
+					return err
+				}
+			}
+		}
+
+		return CheckCountQuotaExceeded(ctx, doer, target)
+	}); err != nil {
+		return nil, err
+	}
+
+	promoted, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		return nil, err
+	}
+
+	notify_service.PackageCreate(ctx, doer, promoted)
+
+	return promoted, nil
+}
+
+func getOrCreatePromotedPackage(ctx context.Context, target *user_model.User, pd *packages_model.PackageDescriptor) (*packages_model.Package, error) {
+	p := &packages_model.Package{
+		OwnerID:          target.ID,
+		Type:             pd.Package.Type,
+		Name:             pd.Package.Name,
+		LowerName:        pd.Package.LowerName,
+		SemverCompatible: pd.Package.SemverCompatible,
+	}
+	p, err := packages_model.TryInsertPackage(ctx, p)
+	if err != nil {
+		if err == packages_model.ErrDuplicatePackage {
+			return p, nil
+		}
+		log.Error("Error inserting package: %v", err)
+		return nil, err
+	}
+
+	for _, pp := range pd.PackageProperties {
+		value := pp.Value
+		if pp.Name == container_module.PropertyRepository {
+			value = strings.ToLower(target.LowerName + "/" + p.Name)
+		}
+		if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypePackage, p.ID, pp.Name, value); err != nil {
+			log.Error("Error setting package property: %v", err)
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func copyPackageVersion(ctx context.Context, doer, target *user_model.User, p *packages_model.Package, pd *packages_model.PackageDescriptor) (*packages_model.PackageVersion, error) {
+	pv := &packages_model.PackageVersion{
+		PackageID:    p.ID,
+		CreatorID:    doer.ID,
+		Version:      pd.Version.Version,
+		LowerVersion: pd.Version.LowerVersion,
+		MetadataJSON: pd.Version.MetadataJSON,
+	}
+	pv, err := packages_model.GetOrInsertVersion(ctx, pv)
+	if err != nil {
+		if err != packages_model.ErrDuplicatePackageVersion {
+			log.Error("Error inserting package version: %v", err)
+		}
+		return nil, err
+	}
+
+	for _, pvp := range pd.VersionProperties {
+		if pvp.Name == packages_model.PropertyPromotedFrom {
+			continue
+		}
+		// a npm dist tag points to a single version of the package
+		if p.Type == packages_model.TypeNpm && pvp.Name == npm_module.TagProperty {
+			if err := removeVersionPropertyFromPackage(ctx, p.ID, pvp.Name, pvp.Value); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, pvp.Name, pvp.Value); err != nil {
+			log.Error("Error setting package version property: %v", err)
+			return nil, err
+		}
+	}
+	if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, packages_model.PropertyPromotedFrom, pd.Owner.Name); err != nil {
+		log.Error("Error setting package version property: %v", err)
+		return nil, err
+	}
+
+	for _, pfd := range pd.Files {
+		if err := CheckSizeQuotaExceeded(ctx, doer, target, p.Type, pfd.Blob.Size); err != nil {
+			return nil, err
+		}
+
+		pf := &packages_model.PackageFile{
+			VersionID:    pv.ID,
+			BlobID:       pfd.Blob.ID,
+			Name:         pfd.File.Name,
+			LowerName:    pfd.File.LowerName,
+			CompositeKey: pfd.File.CompositeKey,
+			IsLead:       pfd.File.IsLead,
+		}
+		if pf, err = packages_model.TryInsertFile(ctx, pf); err != nil {
+			log.Error("Error inserting package file: %v", err)
+			return nil, err
+		}
+
+		for _, pfp := range pfd.Properties {
+			if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeFile, pf.ID, pfp.Name, pfp.Value); err != nil {
+				log.Error("Error setting package file property: %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	pas, err := packages_model.GetAttestationsByVersionID(ctx, pd.Version.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pa := range pas {
+		// attestations stored in other package versions are linked again when these versions are promoted
+		if pa.ReferenceVersionID != 0 {
+			continue
+		}
+		if err := packages_model.InsertAttestation(ctx, &packages_model.PackageAttestation{
+			VersionID:     pv.ID,
+			PredicateType: pa.PredicateType,
+			MediaType:     pa.MediaType,
+			Content:       pa.Content,
+			CreatorID:     pa.CreatorID,
+		}); err != nil {
+			log.Error("Error inserting package attestation: %v", err)
+			return nil, err
+		}
+	}
+
+	return pv, nil
+}
+
+// removeVersionPropertyFromPackage removes the version property with the value from all versions of the package
+func removeVersionPropertyFromPackage(ctx context.Context, packageID int64, name, value string) error {
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		PackageID:  packageID,
+		Properties: map[string]string{name: value},
+		IsInternal: optional.Some(false),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pv := range pvs {
+		pvps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, name)
+		if err != nil {
+			return err
+		}
+		for _, pvp := range pvps {
+			if pvp.Value == value {
+				if err := packages_model.DeletePropertyByID(ctx, pvp.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"strconv"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// GetTotalSizeLimit returns the maximum total size of the package files of the owner, -1 if it is unlimited.
+// The limit set for the owner overrides the instance setting.
+func GetTotalSizeLimit(ctx context.Context, ownerID int64) (limit int64, isOwnerLimit bool, err error) {
+	value, err := user_model.GetUserSetting(ctx, ownerID, user_model.SettingsKeyPackagesTotalSizeLimit)
+	if err != nil {
+		return 0, false, err
+	}
+	if value == "" {
+		return setting.Packages.LimitTotalOwnerSize, false, nil
+	}
+	limit, err = strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return limit, true, nil
+}
+
+// SetTotalSizeLimit sets the maximum total size of the package files of the owner, -1 for unlimited
+func SetTotalSizeLimit(ctx context.Context, ownerID, limit int64) error {
+	if limit < -1 {
+		return util.NewInvalidArgumentErrorf("the limit must be -1 or a size in bytes")
+	}
+	return user_model.SetUserSetting(ctx, ownerID, user_model.SettingsKeyPackagesTotalSizeLimit, strconv.FormatInt(limit, 10))
+}
+
+// ResetTotalSizeLimit removes the limit set for the owner, the instance setting applies again
+func ResetTotalSizeLimit(ctx context.Context, ownerID int64) error {
+	return user_model.DeleteUserSetting(ctx, ownerID, user_model.SettingsKeyPackagesTotalSizeLimit)
+}
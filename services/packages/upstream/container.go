@@ -0,0 +1,192 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// dockerHubHosts are the registry hosts of Docker Hub, its official images are in the library namespace
+var dockerHubHosts = []string{"registry-1.docker.io", "index.docker.io", "registry.hub.docker.com"}
+
+// containerManifestMediaTypes are the media types of the manifests requested from an upstream registry
+var containerManifestMediaTypes = []string{
+	oci.MediaTypeImageIndex,
+	oci.MediaTypeImageManifest,
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ContainerRegistry fetches the manifests and blobs of an image from an upstream container registry.
+// Registries which require authentication, like Docker Hub, are accessed with an anonymous bearer token.
+type ContainerRegistry struct {
+	client   *http.Client
+	imageURL string
+	token    string
+}
+
+// NewContainerRegistry returns the upstream registry of the image
+func NewContainerRegistry(pu *packages_model.PackageUpstream, image string) *ContainerRegistry {
+	if u, err := url.Parse(pu.URL); err == nil && slices.Contains(dockerHubHosts, u.Hostname()) && !strings.Contains(image, "/") {
+		image = "library/" + image
+	}
+	return &ContainerRegistry{
+		client:   newHTTPClient(),
+		imageURL: pu.URL + "/v2/" + image,
+	}
+}
+
+// ManifestURL returns the url of the manifest of a tag or digest
+func (r *ContainerRegistry) ManifestURL(reference string) string {
+	return r.imageURL + "/manifests/" + url.PathEscape(reference)
+}
+
+// BlobURL returns the url of a blob
+func (r *ContainerRegistry) BlobURL(digest string) string {
+	return r.imageURL + "/blobs/" + url.PathEscape(digest)
+}
+
+// GetManifest fetches the manifest of a tag or digest and returns its media type, the caller must verify its digest
+func (r *ContainerRegistry) GetManifest(ctx context.Context, reference string) (io.ReadCloser, string, error) {
+	resp, err := r.fetch(ctx, r.ManifestURL(reference), strings.Join(containerManifestMediaTypes, ", "))
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return resp.Body, mediaType, nil
+}
+
+// GetBlob fetches a blob, the caller must verify its digest
+func (r *ContainerRegistry) GetBlob(ctx context.Context, digest string) (io.ReadCloser, error) {
+	resp, err := r.fetch(ctx, r.BlobURL(digest), "")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// fetch requests the url with the token of the registry, the token is requested if the registry asks for authentication
+func (r *ContainerRegistry) fetch(ctx context.Context, rawURL, accept string) (*http.Response, error) {
+	resp, err := r.do(ctx, rawURL, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if r.token, err = r.requestToken(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = r.do(ctx, rawURL, accept); err != nil {
+			return nil, err
+		}
+	}
+	body, err := checkResponse(resp, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+func (r *ContainerRegistry) do(ctx context.Context, rawURL, accept string) (*http.Response, error) {
+	req, err := newRequest(ctx, rawURL, accept)
+	if err != nil {
+		return nil, err
+	}
+	if r.token != "" {
+		// the client doesn't send the token to the other hosts blobs are redirected to
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	return r.client.Do(req)
+}
+
+// requestToken requests an anonymous token from the authorization service named by the bearer challenge of the registry
+func (r *ContainerRegistry) requestToken(ctx context.Context, challenge string) (string, error) {
+	params, ok := parseBearerChallenge(challenge)
+	if !ok {
+		return "", fmt.Errorf("upstream %s requires an unsupported authentication: %q", r.imageURL, challenge)
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || (realm.Scheme != "http" && realm.Scheme != "https") || realm.Host == "" {
+		return "", fmt.Errorf("upstream %s has an invalid authentication realm: %q", r.imageURL, params["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := newRequest(ctx, realm.String(), "application/json")
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	body, err := checkResponse(resp, realm.String())
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("upstream %s returned no token", realm.String())
+}
+
+// parseBearerChallenge parses the parameters of a WWW-Authenticate header like
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/alpine:pull"
+func parseBearerChallenge(challenge string) (map[string]string, bool) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ",")) {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return nil, false
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			end := strings.IndexByte(value[1:], '"')
+			if end < 0 {
+				return nil, false
+			}
+			value, rest = value[1:end+1], value[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return params, params["realm"] != ""
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package upstream
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	goproxy_module "code.gitea.io/gitea/modules/packages/goproxy"
+	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+func goModuleURL(pu *packages_model.PackageUpstream, name string) string {
+	return pu.URL + "/" + goproxy_module.EscapePath(name)
+}
+
+// GetGoModuleVersions fetches the list of the versions of a Go module from the upstream proxy
+func GetGoModuleVersions(ctx context.Context, pu *packages_model.PackageUpstream, name string) ([]string, error) {
+	body, err := fetch(ctx, goModuleURL(pu, name)+"/@v/list", "")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	versions := make([]string, 0, 10)
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		if v := strings.TrimSpace(scanner.Text()); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions, scanner.Err()
+}
+
+// GetGoModuleLatestVersion fetches the latest version of a Go module from the upstream proxy
+func GetGoModuleLatestVersion(ctx context.Context, pu *packages_model.PackageUpstream, name string) (string, error) {
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := fetchJSON(ctx, goModuleURL(pu, name)+"/@latest", &info); err != nil {
+		return "", err
+	}
+	if info.Version == "" {
+		return "", packages_model.ErrPackageNotExist
+	}
+	return info.Version, nil
+}
+
+// CacheGoModuleVersion fetches a version of a Go module from the upstream proxy and stores it as a package of the owner
+func CacheGoModuleVersion(ctx context.Context, pu *packages_model.PackageUpstream, owner, doer *user_model.User, name, version string) error {
+	defer LockVersion(owner.ID, packages_model.TypeGo, name, version)()
+
+	if _, err := packages_model.GetVersionByNameAndVersion(ctx, owner.ID, packages_model.TypeGo, name, version); err == nil {
+		return nil
+	} else if err != packages_model.ErrPackageNotExist {
+		return err
+	}
+
+	zipURL := goModuleURL(pu, name) + "/@v/" + goproxy_module.EscapePath(version) + ".zip"
+	body, err := fetch(ctx, zipURL, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	buf, err := readFile(body, setting.Packages.LimitSizeGo)
+	if err != nil {
+		return err
+	}
+	defer buf.Close()
+
+	pck, err := goproxy_module.ParsePackage(buf, buf.Size())
+	if err != nil {
+		return err
+	}
+	if pck.Name != name || pck.Version != version {
+		return fmt.Errorf("upstream returned %s@%s instead of %s@%s", pck.Name, pck.Version, name, version)
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, _, err = packages_service.CreatePackageAndAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       owner,
+				PackageType: packages_model.TypeGo,
+				Name:        pck.Name,
+				Version:     pck.Version,
+			},
+			Creator: Creator(doer),
+			VersionProperties: map[string]string{
+				goproxy_module.PropertyGoMod:       pck.GoMod,
+				packages_model.PropertyUpstreamURL: zipURL,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: fmt.Sprintf("%v.zip", pck.Version),
+			},
+			Creator: Creator(doer),
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+	if err == packages_model.ErrDuplicatePackageVersion {
+		return nil
+	}
+	return err
+}
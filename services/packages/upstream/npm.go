@@ -0,0 +1,204 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package upstream
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/url"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/hashicorp/go-version"
+)
+
+// upstreamNpmMetadata is the package metadata returned by the upstream registry, the versions are decoded one by one
+// because packages published long ago may contain fields of unexpected types
+type upstreamNpmMetadata struct {
+	Name     string                     `json:"name"`
+	DistTags map[string]string          `json:"dist-tags"`
+	Versions map[string]json.RawMessage `json:"versions"`
+}
+
+// unmarshalLenient decodes the json object into v, fields of unexpected types are skipped
+func unmarshalLenient(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err == nil {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		field, err := json.Marshal(map[string]json.RawMessage{key: value})
+		if err != nil {
+			continue
+		}
+		_ = json.Unmarshal(field, v)
+	}
+	return nil
+}
+
+// GetNpmPackageMetadata fetches the metadata of a npm package from the upstream registry.
+// The tarball urls of the versions point to the upstream registry.
+func GetNpmPackageMetadata(ctx context.Context, pu *packages_model.PackageUpstream, packageName string) (*npm_module.PackageMetadata, error) {
+	body, err := fetch(ctx, pu.URL+"/"+url.PathEscape(packageName), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var upstream upstreamNpmMetadata
+	if err := json.Unmarshal(data, &upstream); err != nil {
+		return nil, err
+	}
+	if upstream.Name != packageName {
+		return nil, packages_model.ErrPackageNotExist
+	}
+
+	metadata := &npm_module.PackageMetadata{}
+	if err := unmarshalLenient(data, metadata); err != nil {
+		return nil, err
+	}
+	metadata.ID = packageName
+	metadata.Name = packageName
+	metadata.DistTags = upstream.DistTags
+	metadata.Versions = make(map[string]*npm_module.PackageMetadataVersion, len(upstream.Versions))
+
+	for v, raw := range upstream.Versions {
+		pmv := &npm_module.PackageMetadataVersion{}
+		if err := unmarshalLenient(raw, pmv); err != nil {
+			log.Debug("Skipping invalid version %s of upstream npm package %s: %v", v, packageName, err)
+			continue
+		}
+		if pmv.Dist.Integrity == "" {
+			// packages published before the integrity field existed only have a sha1 checksum
+			if shasum, err := hex.DecodeString(pmv.Dist.Shasum); err == nil && len(shasum) == sha1.Size {
+				pmv.Dist.Integrity = "sha1-" + base64.StdEncoding.EncodeToString(shasum)
+			}
+		}
+		if _, err := version.NewSemver(pmv.Version); err != nil || pmv.Version != v || pmv.Name != packageName || pmv.Dist.Tarball == "" || pmv.Dist.Integrity == "" {
+			log.Debug("Skipping invalid version %s of upstream npm package %s", v, packageName)
+			continue
+		}
+		metadata.Versions[v] = pmv
+	}
+
+	return metadata, nil
+}
+
+// CacheNpmPackageVersion fetches a version of a npm package from the upstream registry and stores it as a package of the owner
+func CacheNpmPackageVersion(ctx context.Context, pu *packages_model.PackageUpstream, owner, doer *user_model.User, packageName, packageVersion string) error {
+	defer LockVersion(owner.ID, packages_model.TypeNpm, packageName, packageVersion)()
+
+	if _, err := packages_model.GetVersionByNameAndVersion(ctx, owner.ID, packages_model.TypeNpm, packageName, packageVersion); err == nil {
+		return nil
+	} else if err != packages_model.ErrPackageNotExist {
+		return err
+	}
+
+	metadata, err := GetNpmPackageMetadata(ctx, pu, packageName)
+	if err != nil {
+		return err
+	}
+	pmv, ok := metadata.Versions[packageVersion]
+	if !ok {
+		return packages_model.ErrPackageNotExist
+	}
+	if pmv.Readme == "" && metadata.DistTags["latest"] == packageVersion {
+		pmv.Readme = metadata.Readme
+	}
+
+	body, err := fetch(ctx, pmv.Dist.Tarball, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tarball, err := readFile(body, setting.Packages.LimitSizeNpm)
+	if err != nil {
+		return err
+	}
+	defer tarball.Close()
+
+	data, err := io.ReadAll(tarball)
+	if err != nil {
+		return err
+	}
+
+	// the package is parsed like a published one, which verifies the integrity of the tarball
+	upload, err := json.Marshal(struct {
+		*npm_module.PackageMetadata
+		Attachments map[string]*npm_module.PackageAttachment `json:"_attachments"`
+	}{
+		PackageMetadata: &npm_module.PackageMetadata{
+			ID:       packageName,
+			Name:     packageName,
+			Versions: map[string]*npm_module.PackageMetadataVersion{packageVersion: pmv},
+		},
+		Attachments: map[string]*npm_module.PackageAttachment{
+			packageName: {
+				Data:   base64.StdEncoding.EncodeToString(data),
+				Length: len(data),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	npmPackage, err := npm_module.ParsePackage(bytes.NewReader(upload))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tarball.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, _, err = packages_service.CreatePackageAndAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       owner,
+				PackageType: packages_model.TypeNpm,
+				Name:        npmPackage.Name,
+				Version:     npmPackage.Version,
+			},
+			SemverCompatible: true,
+			Creator:          Creator(doer),
+			Metadata:         npmPackage.Metadata,
+			VersionProperties: map[string]string{
+				packages_model.PropertyUpstreamURL: pmv.Dist.Tarball,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: npmPackage.Filename,
+			},
+			Creator: Creator(doer),
+			Data:    tarball,
+			IsLead:  true,
+		},
+	)
+	if err == packages_model.ErrDuplicatePackageVersion {
+		return nil
+	}
+	return err
+}
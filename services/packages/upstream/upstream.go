@@ -0,0 +1,177 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// SupportedTypes are the types of packages which can be fetched from an upstream registry
+var SupportedTypes = []packages_model.Type{
+	packages_model.TypeContainer,
+	packages_model.TypeGo,
+	packages_model.TypeNpm,
+}
+
+// cachePool prevents fetching the same package version concurrently
+var cachePool = sync.NewExclusivePool()
+
+// IsSupportedType returns whether packages of the type can be fetched from an upstream registry
+func IsSupportedType(packageType packages_model.Type) bool {
+	return slices.Contains(SupportedTypes, packageType)
+}
+
+// SetUpstream validates the url and sets the upstream registry of a type of packages of an owner
+func SetUpstream(ctx context.Context, ownerID int64, packageType packages_model.Type, upstreamURL string) (*packages_model.PackageUpstream, error) {
+	if !IsSupportedType(packageType) {
+		return nil, util.NewInvalidArgumentErrorf("packages of type %s can't be fetched from an upstream registry", packageType)
+	}
+
+	u, err := url.Parse(strings.TrimSpace(upstreamURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, util.NewInvalidArgumentErrorf("upstream url must be an absolute http(s) url")
+	}
+	if u.User != nil {
+		return nil, util.NewInvalidArgumentErrorf("upstream url must not contain credentials")
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return packages_model.SetUpstream(ctx, ownerID, packageType, strings.TrimSuffix(u.String(), "/"))
+}
+
+// GetUpstream gets the upstream registry of a type of packages of an owner, it returns nil if there is none
+func GetUpstream(ctx context.Context, ownerID int64, packageType packages_model.Type) (*packages_model.PackageUpstream, error) {
+	if !IsSupportedType(packageType) {
+		return nil, nil
+	}
+
+	pu, err := packages_model.GetUpstreamByOwnerAndType(ctx, ownerID, packageType)
+	if err == packages_model.ErrPackageUpstreamNotExist {
+		return nil, nil
+	}
+	return pu, err
+}
+
+func newHTTPClient() *http.Client {
+	allowedHostListValue := setting.Packages.UpstreamAllowedHostList
+	if allowedHostListValue == "" {
+		allowedHostListValue = hostmatcher.MatchBuiltinExternal
+	}
+	allowedHostMatcher := hostmatcher.ParseHostMatchList("packages.UPSTREAM_ALLOWED_HOST_LIST", allowedHostListValue)
+
+	return &http.Client{
+		Timeout: 10 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:       proxy.Proxy(),
+			DialContext: hostmatcher.NewDialContext("package upstream", allowedHostMatcher, nil),
+		},
+	}
+}
+
+// fetch requests the url from the upstream registry, a missing resource results in packages_model.ErrPackageNotExist
+func fetch(ctx context.Context, rawURL, accept string) (io.ReadCloser, error) {
+	req, err := newRequest(ctx, rawURL, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := newHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return checkResponse(resp, rawURL)
+}
+
+func newRequest(ctx context.Context, rawURL, accept string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+	return req, nil
+}
+
+// checkResponse returns the body of a successful response, the body of other responses is closed
+func checkResponse(resp *http.Response, rawURL string) (io.ReadCloser, error) {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound, http.StatusGone:
+		resp.Body.Close()
+		return nil, packages_model.ErrPackageNotExist
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("upstream %s responded with status %d", rawURL, resp.StatusCode)
+	}
+}
+
+// fetchJSON requests the url from the upstream registry and decodes the response
+func fetchJSON(ctx context.Context, rawURL string, v any) error {
+	body, err := fetch(ctx, rawURL, "application/json")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return json.NewDecoder(body).Decode(v)
+}
+
+// readFile reads the file into a buffer, files larger than the limit of the package type are rejected
+func readFile(r io.Reader, limit int64) (*packages_module.HashedBuffer, error) {
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	buf, err := packages_module.CreateHashedBufferFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && buf.Size() > limit {
+		buf.Close()
+		return nil, packages_service.ErrQuotaTypeSize
+	}
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		buf.Close()
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Creator returns the user the cached package versions are attributed to
+func Creator(doer *user_model.User) *user_model.User {
+	if doer == nil {
+		return user_model.NewGhostUser()
+	}
+	return doer
+}
+
+// LockVersion prevents the same package version from being cached concurrently, the returned function releases the lock
+func LockVersion(ownerID int64, packageType packages_model.Type, name, version string) func() {
+	key := fmt.Sprintf("%d/%s/%s/%s", ownerID, packageType, name, version)
+	cachePool.CheckIn(key)
+	return func() {
+		cachePool.CheckOut(key)
+	}
+}
@@ -164,8 +164,8 @@ func isSignedIfRequired(ctx context.Context, pr *issues_model.PullRequest, doer
 }
 
 // checkAndUpdateStatus checks if pull request is possible to leaving checking status,
-// and set to be either conflict or mergeable.
-func checkAndUpdateStatus(ctx context.Context, pr *issues_model.PullRequest) {
+// and set to be either conflict or mergeable. It returns whether the status has been updated.
+func checkAndUpdateStatus(ctx context.Context, pr *issues_model.PullRequest) bool {
 	// If status has not been changed to conflict by testPatch then we are mergeable
 	if pr.Status == issues_model.PullRequestStatusChecking {
 		pr.Status = issues_model.PullRequestStatusMergeable
@@ -179,12 +179,14 @@ func checkAndUpdateStatus(ctx context.Context, pr *issues_model.PullRequest) {
 
 	if has {
 		log.Trace("Not updating status for %-v as it is due to be rechecked", pr)
-		return
+		return false
 	}
 
 	if err := pr.UpdateColsIfNotMerged(ctx, "merge_base", "status", "conflicted_files", "changed_protected_files"); err != nil {
 		log.Error("Update[%-v]: %v", pr, err)
+		return false
 	}
+	return true
 }
 
 // getMergeCommit checks if a pull request has been merged
@@ -328,12 +330,16 @@ func InitializePullRequests(ctx context.Context) {
 func handler(items ...string) []string {
 	for _, s := range items {
 		id, _ := strconv.ParseInt(s, 10, 64)
-		testPR(id)
+		// notify once the pull request is no longer locked, the notifiers could try to merge it
+		if pr := testPR(id); pr != nil {
+			notify_service.PullRequestChecked(graceful.GetManager().ShutdownContext(), pr)
+		}
 	}
 	return nil
 }
 
-func testPR(id int64) {
+// testPR tests the pull request and returns it if its status has been updated
+func testPR(id int64) *issues_model.PullRequest {
 	pullWorkingPool.CheckIn(fmt.Sprint(id))
 	defer pullWorkingPool.CheckOut(fmt.Sprint(id))
 	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().HammerContext(), fmt.Sprintf("Test PR[%d] from patch checking queue", id))
@@ -342,7 +348,7 @@ func testPR(id int64) {
 	pr, err := issues_model.GetPullRequestByID(ctx, id)
 	if err != nil {
 		log.Error("Unable to GetPullRequestByID[%d] for testPR: %v", id, err)
-		return
+		return nil
 	}
 
 	log.Trace("Testing %-v", pr)
@@ -352,12 +358,12 @@ func testPR(id int64) {
 
 	if pr.HasMerged {
 		log.Trace("%-v is already merged (status: %s, merge commit: %s)", pr, pr.Status, pr.MergedCommitID)
-		return
+		return nil
 	}
 
 	if manuallyMerged(ctx, pr) {
 		log.Trace("%-v is manually merged (status: %s, merge commit: %s)", pr, pr.Status, pr.MergedCommitID)
-		return
+		return nil
 	}
 
 	if err := TestPatch(pr); err != nil {
@@ -366,9 +372,12 @@ func testPR(id int64) {
 		if err := pr.UpdateCols(ctx, "status"); err != nil {
 			log.Error("update pr [%-v] status to PullRequestStatusError failed: %v", pr, err)
 		}
-		return
+		return nil
+	}
+	if !checkAndUpdateStatus(ctx, pr) {
+		return nil
 	}
-	checkAndUpdateStatus(ctx, pr)
+	return pr
 }
 
 // CheckPRsForBaseBranch check all pulls with baseBrannch
@@ -0,0 +1,127 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"slices"
+
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	org_model "code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/optional"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// CodeOwnerRuleStatus represents the approval state of a CODEOWNERS rule matching files changed by a pull request
+type CodeOwnerRuleStatus struct {
+	Rule *issues_model.CodeOwnerRule
+	// Files are the changed files matched by the rule
+	Files []string
+	// Approvers are the owners of the rule who approved the pull request
+	Approvers []*user_model.User
+}
+
+// IsSatisfied returns whether an owner of the rule approved the pull request
+func (status *CodeOwnerRuleStatus) IsSatisfied() bool {
+	return len(status.Approvers) > 0
+}
+
+// GetCodeOwnerRuleStatuses returns the state of the CODEOWNERS rules matching files changed by the pull request.
+// A rule is satisfied by an official approval of one of its users or of a member of one of its teams,
+// stale approvals aren't counted if the protected branch ignores them.
+func GetCodeOwnerRuleStatuses(ctx context.Context, pb *git_model.ProtectedBranch, pr *issues_model.PullRequest) ([]*CodeOwnerRuleStatus, error) {
+	rules, changedFiles, err := issue_service.GetPullRequestCodeOwnersRules(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []*CodeOwnerRuleStatus
+	for _, rule := range rules {
+		status := &CodeOwnerRuleStatus{Rule: rule}
+		for _, f := range changedFiles {
+			if rule.MatchFile(f) {
+				status.Files = append(status.Files, f)
+			}
+		}
+		if len(status.Files) > 0 {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+
+	approvals, err := issues_model.FindReviews(ctx, issues_model.FindReviewOptions{
+		Type:         issues_model.ReviewTypeApprove,
+		IssueID:      pr.IssueID,
+		OfficialOnly: true,
+		Dismissed:    optional.Some(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pb != nil && pb.IgnoreStaleApprovals {
+		approvals = slices.DeleteFunc(approvals, func(review *issues_model.Review) bool {
+			return review.Stale
+		})
+	}
+	if err := approvals.LoadReviewers(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, status := range statuses {
+		for _, approval := range approvals {
+			if approval.Reviewer == nil {
+				continue
+			}
+			isOwner, err := isCodeOwner(ctx, status.Rule, approval.Reviewer)
+			if err != nil {
+				return nil, err
+			}
+			if isOwner {
+				status.Approvers = append(status.Approvers, approval.Reviewer)
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// isCodeOwner returns whether the user is an owner of the rule, directly or through a team
+func isCodeOwner(ctx context.Context, rule *issues_model.CodeOwnerRule, user *user_model.User) (bool, error) {
+	for _, u := range rule.Users {
+		if u.ID == user.ID {
+			return true, nil
+		}
+	}
+	for _, t := range rule.Teams {
+		isMember, err := org_model.IsTeamMember(ctx, t.OrgID, t.ID, user.ID)
+		if err != nil {
+			return false, err
+		}
+		if isMember {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MergeBlockedByCodeOwners returns whether the protected branch requires the approval of code owners
+// and a CODEOWNERS rule matching changed files isn't satisfied
+func MergeBlockedByCodeOwners(ctx context.Context, pb *git_model.ProtectedBranch, pr *issues_model.PullRequest) (bool, error) {
+	if !pb.RequireCodeOwnerApproval {
+		return false, nil
+	}
+	statuses, err := GetCodeOwnerRuleStatuses(ctx, pb, pr)
+	if err != nil {
+		return false, err
+	}
+	for _, status := range statuses {
+		if !status.IsSatisfied() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -9,8 +9,10 @@ import (
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
 )
 
 // getCommitIDsFromRepo get commit IDs from repo in between oldCommitID and newCommitID
@@ -89,6 +91,17 @@ func CreatePushPullComment(ctx context.Context, pusher *user_model.User, pr *iss
 	ops.Content = string(dataJSON)
 
 	comment, err = issues_model.CreateComment(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.IsForcePush {
+		// keep the previous head so that the changes since then can still be shown once it's unreachable
+		if _, _, err := git.NewCommand(ctx, "update-ref").AddDynamicArguments(pr.GetGitPushRefName(comment.ID), oldCommitID).
+			RunStdString(&git.RunOpts{Dir: pr.BaseRepo.RepoPath()}); err != nil {
+			log.Error("Unable to keep the head %s of %-v before the force push: %v", oldCommitID, pr, err)
+		}
+	}
 
-	return comment, err
+	return comment, nil
 }
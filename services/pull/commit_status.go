@@ -12,54 +12,60 @@ import (
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/gitrepo"
-	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/structs"
 
-	"github.com/gobwas/glob"
 	"github.com/pkg/errors"
 )
 
-// MergeRequiredContextsCommitStatus returns a commit status state for given required contexts
-func MergeRequiredContextsCommitStatus(commitStatuses []*git_model.CommitStatus, requiredContexts []string) structs.CommitStatusState {
-	// matchedCount is the number of `CommitStatus.Context` that match any context of `requiredContexts`
-	matchedCount := 0
-	returnedStatus := structs.CommitStatusSuccess
+// RequiredStatusCheckResult is the result of a required status check for the statuses of a commit
+type RequiredStatusCheckResult struct {
+	Check *git_model.RequiredStatusCheck
+	// State is the best state of the patterns of the check, the state of a pattern is the worst state of the statuses
+	// matching it, or pending if there is none
+	State structs.CommitStatusState
+	// Contexts are the contexts of the statuses matching the check
+	Contexts []string
+}
 
-	if len(requiredContexts) > 0 {
-		requiredContextsGlob := make(map[string]glob.Glob, len(requiredContexts))
-		for _, ctx := range requiredContexts {
-			if gp, err := glob.Compile(ctx); err != nil {
-				log.Error("glob.Compile %s failed. Error: %v", ctx, err)
-			} else {
-				requiredContextsGlob[ctx] = gp
-			}
-		}
+// IsSatisfied returns whether the required status check succeeds
+func (result *RequiredStatusCheckResult) IsSatisfied() bool {
+	return result.State.IsSuccess()
+}
 
-		for _, gp := range requiredContextsGlob {
-			var targetStatus structs.CommitStatusState
-			for _, commitStatus := range commitStatuses {
-				if gp.Match(commitStatus.Context) {
-					targetStatus = commitStatus.State
-					matchedCount++
-					break
-				}
+// CheckRequiredStatusChecks returns the results of the required status checks for the statuses of a commit
+func CheckRequiredStatusChecks(commitStatuses []*git_model.CommitStatus, checks []*git_model.RequiredStatusCheck) []*RequiredStatusCheckResult {
+	results := make([]*RequiredStatusCheckResult, 0, len(checks))
+	for _, check := range checks {
+		result := &RequiredStatusCheckResult{Check: check, Contexts: []string{}}
+		states := make([]structs.CommitStatusState, len(check.Alternatives))
+		for _, commitStatus := range commitStatuses {
+			i := check.MatchAlternative(commitStatus.Context)
+			if i < 0 {
+				continue
 			}
-
-			// If required rule not match any action, then it is pending
-			if targetStatus == "" {
-				if structs.CommitStatusPending.NoBetterThan(returnedStatus) {
-					returnedStatus = structs.CommitStatusPending
-				}
-				break
+			result.Contexts = append(result.Contexts, commitStatus.Context)
+			if states[i] == "" || commitStatus.State.NoBetterThan(states[i]) {
+				states[i] = commitStatus.State
 			}
+		}
 
-			if targetStatus.NoBetterThan(returnedStatus) {
-				returnedStatus = targetStatus
+		for _, state := range states {
+			// a pattern which doesn't match any status is pending
+			if state == "" {
+				state = structs.CommitStatusPending
+			}
+			if result.State == "" || !state.NoBetterThan(result.State) {
+				result.State = state
 			}
 		}
+		results = append(results, result)
 	}
+	return results
+}
 
-	if matchedCount == 0 && returnedStatus == structs.CommitStatusSuccess {
+// MergeRequiredContextsCommitStatus returns a commit status state for given required contexts
+func MergeRequiredContextsCommitStatus(commitStatuses []*git_model.CommitStatus, requiredContexts []string) structs.CommitStatusState {
+	if len(requiredContexts) == 0 {
 		status := git_model.CalcCommitStatus(commitStatuses)
 		if status != nil {
 			return status.State
@@ -67,6 +73,12 @@ func MergeRequiredContextsCommitStatus(commitStatuses []*git_model.CommitStatus,
 		return structs.CommitStatusSuccess
 	}
 
+	returnedStatus := structs.CommitStatusSuccess
+	for _, result := range CheckRequiredStatusChecks(commitStatuses, git_model.ParseRequiredStatusChecks(requiredContexts)) {
+		if result.State.NoBetterThan(returnedStatus) {
+			returnedStatus = result.State
+		}
+	}
 	return returnedStatus
 }
 
@@ -81,19 +93,8 @@ func IsCommitStatusContextSuccess(commitStatuses []*git_model.CommitStatus, requ
 		return true
 	}
 
-	for _, ctx := range requiredContexts {
-		var found bool
-		for _, commitStatus := range commitStatuses {
-			if commitStatus.Context == ctx {
-				if commitStatus.State != structs.CommitStatusSuccess {
-					return false
-				}
-
-				found = true
-				break
-			}
-		}
-		if !found {
+	for _, result := range CheckRequiredStatusChecks(commitStatuses, git_model.ParseRequiredStatusChecks(requiredContexts)) {
+		if !result.IsSatisfied() {
 			return false
 		}
 	}
@@ -119,23 +120,60 @@ func IsPullCommitStatusPass(ctx context.Context, pr *issues_model.PullRequest) (
 
 // GetPullRequestCommitStatusState returns pull request merged commit status state
 func GetPullRequestCommitStatusState(ctx context.Context, pr *issues_model.PullRequest) (structs.CommitStatusState, error) {
+	_, commitStatuses, err := getPullRequestHeadCommitStatuses(ctx, pr)
+	if err != nil {
+		return "", err
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return "", errors.Wrap(err, "LoadProtectedBranch")
+	}
+	var requiredContexts []string
+	if pb != nil {
+		requiredContexts = pb.StatusCheckContexts
+	}
+
+	return MergeRequiredContextsCommitStatus(commitStatuses, requiredContexts), nil
+}
+
+// GetPullRequestRequiredStatusChecks returns the head commit of the pull request and the results of the required status checks
+// of its base branch for it, there are no results if the protection of the base branch doesn't enable status checks
+func GetPullRequestRequiredStatusChecks(ctx context.Context, pr *issues_model.PullRequest) (string, []*RequiredStatusCheckResult, error) {
+	sha, commitStatuses, err := getPullRequestHeadCommitStatuses(ctx, pr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "LoadProtectedBranch")
+	}
+	if pb == nil || !pb.EnableStatusCheck {
+		return sha, []*RequiredStatusCheckResult{}, nil
+	}
+	return sha, CheckRequiredStatusChecks(commitStatuses, git_model.ParseRequiredStatusChecks(pb.StatusCheckContexts)), nil
+}
+
+// getPullRequestHeadCommitStatuses returns the head commit of the pull request and its latest commit statuses
+func getPullRequestHeadCommitStatuses(ctx context.Context, pr *issues_model.PullRequest) (string, []*git_model.CommitStatus, error) {
 	// Ensure HeadRepo is loaded
 	if err := pr.LoadHeadRepo(ctx); err != nil {
-		return "", errors.Wrap(err, "LoadHeadRepo")
+		return "", nil, errors.Wrap(err, "LoadHeadRepo")
 	}
 
 	// check if all required status checks are successful
 	headGitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, pr.HeadRepo)
 	if err != nil {
-		return "", errors.Wrap(err, "OpenRepository")
+		return "", nil, errors.Wrap(err, "OpenRepository")
 	}
 	defer closer.Close()
 
 	if pr.Flow == issues_model.PullRequestFlowGithub && !headGitRepo.IsBranchExist(pr.HeadBranch) {
-		return "", errors.New("Head branch does not exist, can not merge")
+		return "", nil, errors.New("Head branch does not exist, can not merge")
 	}
 	if pr.Flow == issues_model.PullRequestFlowAGit && !git.IsReferenceExist(ctx, headGitRepo.Path, pr.GetGitRefName()) {
-		return "", errors.New("Head branch does not exist, can not merge")
+		return "", nil, errors.New("Head branch does not exist, can not merge")
 	}
 
 	var sha string
@@ -145,26 +183,16 @@ func GetPullRequestCommitStatusState(ctx context.Context, pr *issues_model.PullR
 		sha, err = headGitRepo.GetRefCommitID(pr.GetGitRefName())
 	}
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	if err := pr.LoadBaseRepo(ctx); err != nil {
-		return "", errors.Wrap(err, "LoadBaseRepo")
+		return "", nil, errors.Wrap(err, "LoadBaseRepo")
 	}
 
 	commitStatuses, _, err := git_model.GetLatestCommitStatus(ctx, pr.BaseRepo.ID, sha, db.ListOptionsAll)
 	if err != nil {
-		return "", errors.Wrap(err, "GetLatestCommitStatus")
+		return "", nil, errors.Wrap(err, "GetLatestCommitStatus")
 	}
-
-	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
-	if err != nil {
-		return "", errors.Wrap(err, "LoadProtectedBranch")
-	}
-	var requiredContexts []string
-	if pb != nil {
-		requiredContexts = pb.StatusCheckContexts
-	}
-
-	return MergeRequiredContextsCommitStatus(commitStatuses, requiredContexts), nil
+	return sha, commitStatuses, nil
 }
@@ -63,3 +63,34 @@ func TestMergeRequiredContextsCommitStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckRequiredStatusChecks(t *testing.T) {
+	commitStatuses := []*git_model.CommitStatus{
+		{Context: "ci/build-linux", State: structs.CommitStatusSuccess},
+		{Context: "ci/build-windows", State: structs.CommitStatusFailure},
+		{Context: "ci/test-v2", State: structs.CommitStatusSuccess},
+		{Context: "ci/lint", State: structs.CommitStatusPending},
+	}
+	checks := git_model.ParseRequiredStatusChecks([]string{"ci/build-*", "ci/test | ci/test-v2", "ci/lint | ci/vet", "ci/docs"})
+	results := CheckRequiredStatusChecks(commitStatuses, checks)
+	assert.Len(t, results, 4)
+
+	// all the statuses matching a pattern must succeed
+	assert.Equal(t, structs.CommitStatusFailure, results[0].State)
+	assert.Equal(t, []string{"ci/build-linux", "ci/build-windows"}, results[0].Contexts)
+	assert.False(t, results[0].IsSatisfied())
+
+	// any pattern of a group may succeed
+	assert.Equal(t, structs.CommitStatusSuccess, results[1].State)
+	assert.Equal(t, []string{"ci/test-v2"}, results[1].Contexts)
+	assert.True(t, results[1].IsSatisfied())
+
+	assert.Equal(t, structs.CommitStatusPending, results[2].State)
+	assert.Equal(t, structs.CommitStatusPending, results[3].State)
+	assert.Empty(t, results[3].Contexts)
+
+	assert.Equal(t, structs.CommitStatusFailure, MergeRequiredContextsCommitStatus(commitStatuses, []string{"ci/build-*", "ci/test | ci/test-v2"}))
+	assert.Equal(t, structs.CommitStatusSuccess, MergeRequiredContextsCommitStatus(commitStatuses, []string{"ci/build-linux", "ci/test | ci/test-v2"}))
+	assert.True(t, IsCommitStatusContextSuccess(commitStatuses, []string{"ci/build-linux", "ci/test|ci/test-v2"}))
+	assert.False(t, IsCommitStatusContextSuccess(commitStatuses, []string{"ci/lint | ci/vet"}))
+}
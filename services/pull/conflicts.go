@@ -0,0 +1,141 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ConflictedFile is a file which couldn't be merged automatically
+type ConflictedFile struct {
+	Path string
+	Mode string
+	// Resolvable is whether the conflict can be resolved by editing the file, which is only possible
+	// for the regular files changed on both sides. Content holds the conflict markers in that case.
+	Resolvable bool
+	Content    string
+}
+
+// GetConflictedFiles returns the files left unmerged by AttemptThreeWayMerge in the index of the repository at gitPath,
+// the conflict markers of the contents are labeled with oursLabel and theirsLabel
+func GetConflictedFiles(ctx context.Context, gitPath, oursLabel, theirsLabel string) ([]*ConflictedFile, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	unmerged := make(chan *unmergedFile)
+	go unmergedFiles(ctx, gitPath, unmerged)
+
+	defer func() {
+		cancel()
+		for range unmerged {
+			// empty the unmerged channel
+		}
+	}()
+
+	files := make([]*ConflictedFile, 0, 5)
+	for file := range unmerged {
+		if file == nil {
+			break
+		}
+		if file.err != nil {
+			return nil, file.err
+		}
+
+		conflicted, err := getConflictedFile(ctx, file, gitPath, oursLabel, theirsLabel)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, conflicted)
+	}
+	return files, nil
+}
+
+func getConflictedFile(ctx context.Context, file *unmergedFile, gitPath, oursLabel, theirsLabel string) (*ConflictedFile, error) {
+	ours, theirs := file.stage2, file.stage3
+	conflicted := &ConflictedFile{}
+	switch {
+	case ours != nil:
+		conflicted.Path, conflicted.Mode = ours.path, ours.mode
+	case theirs != nil:
+		conflicted.Path, conflicted.Mode = theirs.path, theirs.mode
+	default:
+		conflicted.Path, conflicted.Mode = file.stage1.path, file.stage1.mode
+	}
+
+	if ours == nil || theirs == nil || ours.mode != theirs.mode || (ours.mode != "100644" && ours.mode != "100755") {
+		return conflicted, nil
+	}
+
+	unpack := func(sha string) (string, error) {
+		name, _, err := git.NewCommand(ctx, "unpack-file").AddDynamicArguments(sha).RunStdString(&git.RunOpts{Dir: gitPath})
+		if err != nil {
+			return "", fmt.Errorf("unable to unpack object: %s at path: %s. Error: %w", sha, conflicted.Path, err)
+		}
+		return filepath.Join(gitPath, strings.TrimSpace(name)), nil
+	}
+
+	oursFile, err := unpack(ours.sha)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = util.Remove(oursFile)
+	}()
+	theirsFile, err := unpack(theirs.sha)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = util.Remove(theirsFile)
+	}()
+
+	// a file added on both sides is merged as if it was empty before
+	var rootFile string
+	if file.stage1 != nil {
+		rootFile, err = unpack(file.stage1.sha)
+	} else {
+		var empty *os.File
+		if empty, err = os.CreateTemp(gitPath, "empty-"); err == nil {
+			rootFile = empty.Name()
+			err = empty.Close()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = util.Remove(rootFile)
+	}()
+
+	stdout := &bytes.Buffer{}
+	stderr := &strings.Builder{}
+	err = git.NewCommand(ctx, "merge-file", "-p", "-q").
+		AddOptionValues("-L", oursLabel).AddOptionValues("-L", "merge base").AddOptionValues("-L", theirsLabel).
+		AddDynamicArguments(oursFile, rootFile, theirsFile).
+		Run(&git.RunOpts{Dir: gitPath, Stdout: stdout, Stderr: stderr})
+	if err != nil {
+		// merge-file exits with the number of conflicts, and with a negative status if it can't merge the file, e.g. a binary one
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("unable to merge file at path: %s. Error: %w\nStderr: %s", conflicted.Path, err, stderr)
+		}
+		if code := exitErr.ExitCode(); code <= 0 || code >= 128 {
+			return conflicted, nil
+		}
+	}
+
+	conflicted.Resolvable = true
+	conflicted.Content = stdout.String()
+	return conflicted, nil
+}
@@ -98,7 +98,9 @@ func getMergeMessage(ctx context.Context, baseGitRepo *git.Repository, pr *issue
 			if err == nil {
 				closeIssueIndexes := make([]string, 0, len(refs))
 				closeWord := "close"
-				if len(setting.Repository.PullRequest.CloseKeywords) > 0 {
+				if closeKeywords := pr.BaseRepo.GetIssuesConfig(ctx).CloseKeywords; len(closeKeywords) > 0 {
+					closeWord = closeKeywords[0]
+				} else if len(setting.Repository.PullRequest.CloseKeywords) > 0 {
 					closeWord = setting.Repository.PullRequest.CloseKeywords[0]
 				}
 				for _, ref := range refs {
@@ -160,7 +162,8 @@ func GetDefaultMergeMessage(ctx context.Context, baseGitRepo *git.Repository, pr
 
 // Merge merges pull request to base repository.
 // Caller should check PR is ready to be merged (review and status checks)
-func Merge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, baseGitRepo *git.Repository, mergeStyle repo_model.MergeStyle, expectedHeadCommitID, message string, wasAutoMerged bool) error {
+// The merge strategy options of the repository are used if strategyOptions is nil
+func Merge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, baseGitRepo *git.Repository, mergeStyle repo_model.MergeStyle, expectedHeadCommitID, message string, strategyOptions []string, wasAutoMerged bool) error {
 	if err := pr.LoadBaseRepo(ctx); err != nil {
 		log.Error("Unable to load base repo: %v", err)
 		return fmt.Errorf("unable to load base repo: %w", err)
@@ -188,13 +191,18 @@ func Merge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.U
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false, "", "")
 	}()
 
-	_, err = doMergeAndPush(ctx, pr, doer, mergeStyle, expectedHeadCommitID, message, repo_module.PushTriggerPRMergeToBase)
+	_, err = doMergeAndPush(ctx, pr, doer, mergeStyle, expectedHeadCommitID, message, strategyOptions, repo_module.PushTriggerPRMergeToBase)
 	if err != nil {
 		return err
 	}
 
+	return afterMerge(ctx, pr, doer, wasAutoMerged)
+}
+
+// afterMerge notifies the merge of the pull request and resolves its cross references once it has been pushed to the base branch
+func afterMerge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, wasAutoMerged bool) error {
 	// reload pull request because it has been updated by post receive hook
-	pr, err = issues_model.GetPullRequestByID(ctx, pr.ID)
+	pr, err := issues_model.GetPullRequestByID(ctx, pr.ID)
 	if err != nil {
 		return err
 	}
@@ -247,13 +255,25 @@ func Merge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.U
 }
 
 // doMergeAndPush performs the merge operation without changing any pull information in database and pushes it up to the base repository
-func doMergeAndPush(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, mergeStyle repo_model.MergeStyle, expectedHeadCommitID, message string, pushTrigger repo_module.PushTrigger) (string, error) { //nolint:unparam
+func doMergeAndPush(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, mergeStyle repo_model.MergeStyle, expectedHeadCommitID, message string, strategyOptions []string, pushTrigger repo_module.PushTrigger) (string, error) { //nolint:unparam
+	return doMergeAndPushToBranch(ctx, pr, doer, mergeStyle, expectedHeadCommitID, message, strategyOptions, pushTrigger, pr.BaseBranch, false)
+}
+
+// doMergeAndPushToBranch performs the merge operation and pushes the result to the given branch of the base repository,
+// the branch is overwritten if forcePush is true
+func doMergeAndPushToBranch(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, mergeStyle repo_model.MergeStyle, expectedHeadCommitID, message string, strategyOptions []string, pushTrigger repo_module.PushTrigger, pushBranch string, forcePush bool) (string, error) {
+	strategyArgs, err := getMergeStrategyArgs(ctx, pr, strategyOptions)
+	if err != nil {
+		return "", err
+	}
+
 	// Clone base repo.
 	mergeCtx, cancel, err := createTemporaryRepoForMerge(ctx, pr, doer, expectedHeadCommitID)
 	if err != nil {
 		return "", err
 	}
 	defer cancel()
+	mergeCtx.strategyArgs = strategyArgs
 
 	// Merge commits.
 	switch mergeStyle {
@@ -300,29 +320,15 @@ func doMergeAndPush(ctx context.Context, pr *issues_model.PullRequest, doer *use
 		}
 	}
 
-	var headUser *user_model.User
-	err = pr.HeadRepo.LoadOwner(ctx)
+	mergeCtx.env, err = mergePushingEnvironment(ctx, pr, doer, pushTrigger)
 	if err != nil {
-		if !user_model.IsErrUserNotExist(err) {
-			log.Error("Can't find user: %d for head repository in %-v: %v", pr.HeadRepo.OwnerID, pr, err)
-			return "", err
-		}
-		log.Warn("Can't find user: %d for head repository in %-v - defaulting to doer: %s - %v", pr.HeadRepo.OwnerID, pr, doer.Name, err)
-		headUser = doer
-	} else {
-		headUser = pr.HeadRepo.Owner
+		return "", err
 	}
-
-	mergeCtx.env = repo_module.FullPushingEnvironment(
-		headUser,
-		doer,
-		pr.BaseRepo,
-		pr.BaseRepo.Name,
-		pr.ID,
-	)
-
-	mergeCtx.env = append(mergeCtx.env, repo_module.EnvPushTrigger+"="+string(pushTrigger))
-	pushCmd := git.NewCommand(ctx, "push", "origin").AddDynamicArguments(baseBranch + ":" + git.BranchPrefix + pr.BaseBranch)
+	refSpec := baseBranch + ":" + git.BranchPrefix + pushBranch
+	if forcePush {
+		refSpec = "+" + refSpec
+	}
+	pushCmd := git.NewCommand(ctx, "push", "origin").AddDynamicArguments(refSpec)
 
 	// Push back to upstream.
 	// This cause an api call to "/api/internal/hook/post-receive/...",
@@ -351,6 +357,31 @@ func doMergeAndPush(ctx context.Context, pr *issues_model.PullRequest, doer *use
 	return mergeCommitID, nil
 }
 
+// mergePushingEnvironment returns the environment of the push of a merge of the pull request to its base repository
+func mergePushingEnvironment(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, pushTrigger repo_module.PushTrigger) ([]string, error) {
+	var headUser *user_model.User
+	err := pr.HeadRepo.LoadOwner(ctx)
+	if err != nil {
+		if !user_model.IsErrUserNotExist(err) {
+			log.Error("Can't find user: %d for head repository in %-v: %v", pr.HeadRepo.OwnerID, pr, err)
+			return nil, err
+		}
+		log.Warn("Can't find user: %d for head repository in %-v - defaulting to doer: %s - %v", pr.HeadRepo.OwnerID, pr, doer.Name, err)
+		headUser = doer
+	} else {
+		headUser = pr.HeadRepo.Owner
+	}
+
+	env := repo_module.FullPushingEnvironment(
+		headUser,
+		doer,
+		pr.BaseRepo,
+		pr.BaseRepo.Name,
+		pr.ID,
+	)
+	return append(env, repo_module.EnvPushTrigger+"="+string(pushTrigger)), nil
+}
+
 func commitAndSignNoAuthor(ctx *mergeContext, message string) error {
 	cmdCommit := git.NewCommand(ctx, "commit").AddOptionFormat("--message=%s", message)
 	if ctx.signKeyID == "" {
@@ -461,6 +492,13 @@ func CheckPullBranchProtections(ctx context.Context, pr *issues_model.PullReques
 			Reason: "There are official review requests",
 		}
 	}
+	if blocked, err := MergeBlockedByCodeOwners(ctx, pb, pr); err != nil {
+		return err
+	} else if blocked {
+		return models.ErrDisallowedToMerge{
+			Reason: "Not all code owners have approved",
+		}
+	}
 
 	if issues_model.MergeBlockedByOutdatedBranch(pb, pr) {
 		return models.ErrDisallowedToMerge{
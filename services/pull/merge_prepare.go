@@ -30,6 +30,8 @@ type mergeContext struct {
 	committer *git.Signature
 	signKeyID string // empty for no-sign, non-empty to sign
 	env       []string
+
+	strategyArgs git.TrustedCmdArgs // the arguments selecting the merge strategy and its options
 }
 
 func (ctx *mergeContext) RunOpts() *git.RunOpts {
@@ -245,7 +247,7 @@ func rebaseTrackingOnToBase(ctx *mergeContext, mergeStyle repo_model.MergeStyle)
 	ctx.errbuf.Reset()
 
 	// Rebase before merging
-	if err := git.NewCommand(ctx, "rebase").AddDynamicArguments(baseBranch).
+	if err := git.NewCommand(ctx, "rebase").AddArguments(ctx.strategyArgs...).AddDynamicArguments(baseBranch).
 		Run(ctx.RunOpts()); err != nil {
 		// Rebase will leave a REBASE_HEAD file in .git if there is a conflict
 		if _, statErr := os.Stat(filepath.Join(ctx.tmpBasePath, ".git", "REBASE_HEAD")); statErr == nil {
@@ -272,12 +274,14 @@ func rebaseTrackingOnToBase(ctx *mergeContext, mergeStyle repo_model.MergeStyle)
 				return fmt.Errorf("unable to git rebase staging on to base in temp repo for %v: %w\n%s\n%s", ctx.pr, err, ctx.outbuf.String(), ctx.errbuf.String())
 			}
 			log.Debug("Conflict when rebasing staging on to base in %-v at %s: %v\n%s\n%s", ctx.pr, commitSha, err, ctx.outbuf.String(), ctx.errbuf.String())
+			stdout, stderr := ctx.outbuf.String(), ctx.errbuf.String()
 			return models.ErrRebaseConflicts{
-				CommitSHA: commitSha,
-				Style:     mergeStyle,
-				StdOut:    ctx.outbuf.String(),
-				StdErr:    ctx.errbuf.String(),
-				Err:       err,
+				CommitSHA:       commitSha,
+				ConflictedFiles: getUnmergedFiles(ctx),
+				Style:           mergeStyle,
+				StdOut:          stdout,
+				StdErr:          stderr,
+				Err:             err,
 			}
 		}
 		return fmt.Errorf("unable to git rebase staging on to base in temp repo for %v: %w\n%s\n%s", ctx.pr, err, ctx.outbuf.String(), ctx.errbuf.String())
@@ -286,3 +290,19 @@ func rebaseTrackingOnToBase(ctx *mergeContext, mergeStyle repo_model.MergeStyle)
 	ctx.errbuf.Reset()
 	return nil
 }
+
+// getUnmergedFiles returns the files left unmerged by a conflicting merge or rebase in the temporary repository
+func getUnmergedFiles(ctx *mergeContext) []string {
+	stdout, _, err := git.NewCommand(ctx, "diff", "--name-only", "--diff-filter=U", "-z").RunStdString(&git.RunOpts{Dir: ctx.tmpBasePath})
+	if err != nil {
+		log.Error("Unable to list the unmerged files in temp repo for %-v: %v", ctx.pr, err)
+		return nil
+	}
+	files := make([]string, 0, 5)
+	for _, file := range strings.Split(stdout, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
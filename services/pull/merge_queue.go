@@ -0,0 +1,109 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+)
+
+// MergeQueueBranchPrefix is the prefix of the branches holding the speculative merge commits of the merge queues
+const MergeQueueBranchPrefix = "gitea-merge-queue/"
+
+// MergeQueueBranchName returns the branch holding the speculative merge commit of a pull request in the merge queue of its base branch
+func MergeQueueBranchName(pr *issues_model.PullRequest) string {
+	return fmt.Sprintf("%s%s/pr-%d", MergeQueueBranchPrefix, pr.BaseBranch, pr.Index)
+}
+
+// IsMergeQueueBranch returns whether the branch holds a speculative merge commit of a merge queue
+func IsMergeQueueBranch(branch string) bool {
+	return strings.HasPrefix(branch, MergeQueueBranchPrefix)
+}
+
+// CreateSpeculativeMerge merges the pull request on top of onBranch and pushes the result to the merge queue branch of the pull request,
+// the pull request itself is left untouched. onBranch is the base branch or the merge queue branch of the previous pull request of the queue.
+// The merge fails with models.ErrSHADoesNotMatch if the head of the pull request isn't expectedHeadCommitID anymore.
+func CreateSpeculativeMerge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, mergeStyle repo_model.MergeStyle, expectedHeadCommitID, message, onBranch string) (string, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return "", fmt.Errorf("unable to load base repo: %w", err)
+	} else if err := pr.LoadHeadRepo(ctx); err != nil {
+		return "", fmt.Errorf("unable to load head repo: %w", err)
+	} else if err := pr.LoadIssue(ctx); err != nil {
+		return "", fmt.Errorf("unable to load issue: %w", err)
+	}
+
+	pullWorkingPool.CheckIn(fmt.Sprint(pr.ID))
+	defer pullWorkingPool.CheckOut(fmt.Sprint(pr.ID))
+
+	prUnit, err := pr.BaseRepo.GetUnit(ctx, unit.TypePullRequests)
+	if err != nil {
+		return "", err
+	}
+	if !prUnit.PullRequestsConfig().IsMergeStyleAllowed(mergeStyle) {
+		return "", models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
+	}
+
+	// use the merge functions but merge into the given branch
+	speculativePR := *pr
+	speculativePR.BaseBranch = onBranch
+
+	return doMergeAndPushToBranch(ctx, &speculativePR, doer, mergeStyle, expectedHeadCommitID, message, nil, repo_module.PushTriggerPRMergeQueue, MergeQueueBranchName(pr), true)
+}
+
+// MergeSpeculativeCommit merges the pull request by fast-forwarding its base branch to its speculative merge commit
+func MergeSpeculativeCommit(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, commitID string) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return fmt.Errorf("unable to load base repo: %w", err)
+	} else if err := pr.LoadHeadRepo(ctx); err != nil {
+		return fmt.Errorf("unable to load head repo: %w", err)
+	}
+
+	pullWorkingPool.CheckIn(fmt.Sprint(pr.ID))
+	defer pullWorkingPool.CheckOut(fmt.Sprint(pr.ID))
+
+	defer func() {
+		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false, "", "")
+	}()
+
+	env, err := mergePushingEnvironment(ctx, pr, doer, repo_module.PushTriggerPRMergeToBase)
+	if err != nil {
+		return err
+	}
+
+	// the post receive hook marks the pull request as merged
+	if err := git.Push(ctx, pr.BaseRepo.RepoPath(), git.PushOptions{
+		Remote: pr.BaseRepo.RepoPath(),
+		Branch: commitID + ":" + git.BranchPrefix + pr.BaseBranch,
+		Env:    env,
+	}); err != nil {
+		return err
+	}
+
+	return afterMerge(ctx, pr, doer, true)
+}
+
+// DeleteMergeQueueBranch deletes the branch holding the speculative merge commit of the pull request, if it exists
+func DeleteMergeQueueBranch(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return fmt.Errorf("unable to load base repo: %w", err)
+	}
+	branchName := MergeQueueBranchName(pr)
+	if !git.IsBranchExist(ctx, pr.BaseRepo.RepoPath(), branchName) {
+		return nil
+	}
+	return git.Push(ctx, pr.BaseRepo.RepoPath(), git.PushOptions{
+		Remote: pr.BaseRepo.RepoPath(),
+		Branch: ":" + git.BranchPrefix + branchName,
+		Env:    repo_module.PushingEnvironment(doer, pr.BaseRepo),
+	})
+}
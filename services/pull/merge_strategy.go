@@ -0,0 +1,93 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MergeStrategies are the git merge strategies which can be used to merge pull requests, empty is the git default
+var MergeStrategies = []string{"", "ort", "recursive"}
+
+// mergeStrategyOptions are the merge strategy options which can be given without a value
+var mergeStrategyOptions = []string{
+	"ours",
+	"theirs",
+	"patience",
+	"ignore-space-change",
+	"ignore-all-space",
+	"ignore-space-at-eol",
+	"ignore-cr-at-eol",
+	"renormalize",
+	"no-renormalize",
+	"no-renames",
+	"find-renames",
+}
+
+var mergeStrategyDiffAlgorithms = []string{"patience", "minimal", "histogram", "myers"}
+
+// ValidateMergeStrategy checks that the merge strategy and its options can be passed to git merge
+func ValidateMergeStrategy(strategy string, options []string) error {
+	if !util.SliceContainsString(MergeStrategies, strategy) {
+		return util.NewInvalidArgumentErrorf("invalid merge strategy %q", strategy)
+	}
+	if strategy == "ort" && !git.DefaultFeatures().CheckVersionAtLeast("2.33") {
+		return util.NewInvalidArgumentErrorf("merge strategy ort requires git 2.33 or later")
+	}
+	for _, option := range options {
+		if !isValidMergeStrategyOption(option) {
+			return util.NewInvalidArgumentErrorf("invalid merge strategy option %q", option)
+		}
+	}
+	return nil
+}
+
+func isValidMergeStrategyOption(option string) bool {
+	name, value, hasValue := strings.Cut(option, "=")
+	if !hasValue {
+		return util.SliceContainsString(mergeStrategyOptions, name)
+	}
+	switch name {
+	case "diff-algorithm":
+		return util.SliceContainsString(mergeStrategyDiffAlgorithms, value)
+	case "find-renames", "rename-threshold":
+		// the similarity threshold is a percentage, the "%" sign is optional
+		n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		return err == nil && n >= 0 && n <= 100
+	}
+	return false
+}
+
+// mergeStrategyArgs returns the arguments selecting the merge strategy and its options,
+// they must have been checked by ValidateMergeStrategy
+func mergeStrategyArgs(strategy string, options []string) git.TrustedCmdArgs {
+	args := make([]string, 0, len(options)+1)
+	if strategy != "" {
+		args = append(args, "--strategy="+strategy)
+	}
+	for _, option := range options {
+		args = append(args, "--strategy-option="+option)
+	}
+	return git.ToTrustedCmdArgs(args)
+}
+
+// getMergeStrategyArgs returns the arguments of the merge strategy configured for the base repository of the pull request,
+// the given options replace the configured ones when they are not nil
+func getMergeStrategyArgs(ctx context.Context, pr *issues_model.PullRequest, options []string) (git.TrustedCmdArgs, error) {
+	prConfig := pr.BaseRepo.MustGetUnit(ctx, unit.TypePullRequests).PullRequestsConfig()
+	if options == nil {
+		options = prConfig.MergeStrategyOptions
+	}
+	if err := ValidateMergeStrategy(prConfig.MergeStrategy, options); err != nil {
+		return nil, err
+	}
+	return mergeStrategyArgs(prConfig.MergeStrategy, options), nil
+}
@@ -6,6 +6,9 @@ package pull
 import (
 	"testing"
 
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,3 +68,20 @@ func Test_expandDefaultMergeMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMergeStrategy(t *testing.T) {
+	assert.NoError(t, ValidateMergeStrategy("", nil))
+	assert.NoError(t, ValidateMergeStrategy("recursive", []string{"theirs", "diff-algorithm=histogram", "find-renames=50%", "rename-threshold=30"}))
+	assert.NoError(t, ValidateMergeStrategy("", []string{"ours", "ignore-space-change", "no-renames", "find-renames"}))
+
+	assert.ErrorIs(t, ValidateMergeStrategy("octopus", nil), util.ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateMergeStrategy("", []string{"subtree=dir"}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateMergeStrategy("", []string{"diff-algorithm=unknown"}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateMergeStrategy("", []string{"find-renames=101"}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateMergeStrategy("", []string{"ours=1"}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateMergeStrategy("", []string{"--upload-pack=evil"}), util.ErrInvalidArgument)
+
+	assert.Equal(t, git.ToTrustedCmdArgs([]string{"--strategy=ort", "--strategy-option=theirs", "--strategy-option=find-renames=50"}),
+		mergeStrategyArgs("ort", []string{"theirs", "find-renames=50"}))
+	assert.Empty(t, mergeStrategyArgs("", nil))
+}
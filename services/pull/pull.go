@@ -18,6 +18,7 @@ import (
 	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/container"
@@ -601,7 +602,7 @@ func RetargetBranchPulls(ctx context.Context, doer *user_model.User, repoID int6
 	for _, pr := range prs {
 		if err = pr.Issue.LoadRepo(ctx); err != nil {
 			errs = append(errs, err)
-		} else if err = ChangeTargetBranch(ctx, pr, doer, targetBranch); err != nil &&
+		} else if err = retargetPull(ctx, doer, pr, targetBranch); err != nil &&
 			!issues_model.IsErrIssueIsClosed(err) && !models.IsErrPullRequestHasMerged(err) &&
 			!issues_model.IsErrPullRequestAlreadyExists(err) {
 			errs = append(errs, err)
@@ -614,7 +615,48 @@ func RetargetBranchPulls(ctx context.Context, doer *user_model.User, repoID int6
 	return nil
 }
 
-// CloseBranchPulls close all the pull requests who's head branch is the branch
+// retargetPull changes the target branch of a pull request automatically,
+// its head branch is rebased onto the new target branch if it's configured for the base repository
+func retargetPull(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, targetBranch string) error {
+	if err := ChangeTargetBranch(ctx, pr, doer, targetBranch); err != nil {
+		return err
+	}
+
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return err
+	}
+	prUnit, err := pr.BaseRepo.GetUnit(ctx, unit.TypePullRequests)
+	if repo_model.IsErrUnitTypeNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !prUnit.PullRequestsConfig().RebaseOnRetarget || pr.CommitsBehind == 0 {
+		return nil
+	}
+
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return err
+	} else if pr.HeadRepo == nil {
+		return nil
+	}
+	_, rebaseAllowed, err := IsUserAllowedToUpdate(ctx, pr, doer)
+	if err != nil {
+		return err
+	} else if !rebaseAllowed {
+		log.Debug("%-v can't be rebased onto %s by %-v after being retargeted", pr, targetBranch, doer)
+		return nil
+	}
+
+	// the pull request stays retargeted if it can't be rebased, e.g. because of conflicts
+	if err := Update(ctx, pr, doer, "", true); err != nil {
+		log.Warn("Unable to rebase %-v onto %s after retargeting it: %v", pr, targetBranch, err)
+	}
+	return nil
+}
+
+// CloseBranchPulls close all the pull requests who's head or base branch is the branch,
+// the pull requests whose base branch is the branch are retargeted to the default branch instead if it's configured for the repository
 func CloseBranchPulls(ctx context.Context, doer *user_model.User, repoID int64, branch string) error {
 	prs, err := issues_model.GetUnmergedPullRequestsByHeadInfo(ctx, repoID, branch)
 	if err != nil {
@@ -626,12 +668,39 @@ func CloseBranchPulls(ctx context.Context, doer *user_model.User, repoID int64,
 		return err
 	}
 
+	var errs errlist
+	if len(prs2) > 0 {
+		repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+		if err != nil {
+			return err
+		}
+		if prUnit, err := repo.GetUnit(ctx, unit.TypePullRequests); err == nil && prUnit.PullRequestsConfig().RetargetOnBaseDeletion && branch != repo.DefaultBranch {
+			if err := issues_model.PullRequestList(prs2).LoadAttributes(ctx); err != nil {
+				return err
+			}
+			unretargeted := make([]*issues_model.PullRequest, 0, len(prs2))
+			for _, pr := range prs2 {
+				pr.Issue.Repo = repo
+				if err := retargetPull(ctx, doer, pr, repo.DefaultBranch); err != nil {
+					if issues_model.IsErrPullRequestAlreadyExists(err) || git_model.IsErrBranchesEqual(err) {
+						// the pull request can't be retargeted, it's closed
+						unretargeted = append(unretargeted, pr)
+					} else if !issues_model.IsErrIssueIsClosed(err) && !models.IsErrPullRequestHasMerged(err) {
+						errs = append(errs, err)
+					}
+				}
+			}
+			prs2 = unretargeted
+		} else if err != nil && !repo_model.IsErrUnitTypeNotExist(err) {
+			return err
+		}
+	}
+
 	prs = append(prs, prs2...)
 	if err := issues_model.PullRequestList(prs).LoadAttributes(ctx); err != nil {
 		return err
 	}
 
-	var errs errlist
 	for _, pr := range prs {
 		if err = issue_service.ChangeStatus(ctx, pr.Issue, doer, "", true); err != nil && !issues_model.IsErrPullWasClosed(err) && !issues_model.IsErrDependenciesLeft(err) {
 			errs = append(errs, err)
@@ -1008,17 +1077,13 @@ func GetPullCommits(ctx *gitea_context.Context, issue *issues_model.Issue) ([]Co
 	var lastReviewCommitID string
 	if ctx.IsSigned {
 		// get last review of current user and store information in context (if available)
-		lastreview, err := issues_model.FindLatestReviews(ctx, issues_model.FindReviewOptions{
-			IssueID:    issue.ID,
-			ReviewerID: ctx.Doer.ID,
-			Type:       issues_model.ReviewTypeUnknown,
-		})
-
-		if err != nil && !issues_model.IsErrReviewNotExist(err) {
+		lastReviewCommitID, err = GetLastReviewCommitID(ctx, pull, ctx.Doer)
+		if err != nil {
 			return nil, "", err
 		}
-		if len(lastreview) > 0 {
-			lastReviewCommitID = lastreview[0].CommitID
+		// the commit may have been garbage collected after a force push if it predates the push refs
+		if lastReviewCommitID != "" && !baseGitRepo.IsCommitExist(lastReviewCommitID) {
+			lastReviewCommitID = ""
 		}
 	}
 
@@ -0,0 +1,138 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"slices"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/gitdiff"
+)
+
+// Push is a push to the head branch of a pull request, it's recorded by a push comment
+type Push struct {
+	Comment     *issues_model.Comment
+	IsForcePush bool
+	// CommitIDs are the pushed commits, oldest first, or the heads before and after a force push
+	CommitIDs []string
+	// HeadCommitID is the head of the pull request after the push
+	HeadCommitID string
+}
+
+// BeforeCommitID returns the head of the pull request before a force push
+func (push *Push) BeforeCommitID() string {
+	if push.IsForcePush && len(push.CommitIDs) == 2 {
+		return push.CommitIDs[0]
+	}
+	return ""
+}
+
+// GetPushes returns the pushes to the head branch of the pull request, oldest first
+func GetPushes(ctx context.Context, pr *issues_model.PullRequest) ([]*Push, error) {
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		IssueID: pr.IssueID,
+		Type:    issues_model.CommentTypePullRequestPush,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pushes := make([]*Push, 0, len(comments))
+	for _, comment := range comments {
+		var data issues_model.PushActionContent
+		if err := json.Unmarshal([]byte(comment.Content), &data); err != nil {
+			return nil, err
+		}
+		push := &Push{Comment: comment, IsForcePush: data.IsForcePush, CommitIDs: data.CommitIDs}
+		if len(data.CommitIDs) > 0 {
+			push.HeadCommitID = data.CommitIDs[len(data.CommitIDs)-1]
+		}
+		pushes = append(pushes, push)
+	}
+	return pushes, nil
+}
+
+// GetLastReviewCommitID returns the head of the pull request when the user submitted their last review,
+// it's empty if the user hasn't reviewed the pull request
+func GetLastReviewCommitID(ctx context.Context, pr *issues_model.PullRequest, user *user_model.User) (string, error) {
+	reviews, err := issues_model.FindReviews(ctx, issues_model.FindReviewOptions{
+		IssueID:    pr.IssueID,
+		ReviewerID: user.ID,
+		Type:       issues_model.ReviewTypeUnknown,
+	})
+	if err != nil {
+		return "", err
+	}
+	for i := len(reviews) - 1; i >= 0; i-- {
+		switch reviews[i].Type {
+		case issues_model.ReviewTypeApprove, issues_model.ReviewTypeComment, issues_model.ReviewTypeReject:
+			if reviews[i].CommitID != "" {
+				return reviews[i].CommitID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// IsHeadCommit returns whether the commit has been a head of the pull request, i.e. it's its current head,
+// the head after or before one of its pushes, or the head one of its reviews was submitted for
+func IsHeadCommit(ctx context.Context, gitRepo *git.Repository, pr *issues_model.PullRequest, commitID string) (bool, error) {
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return false, err
+	}
+	if commitID == headCommitID {
+		return true, nil
+	}
+
+	pushes, err := GetPushes(ctx, pr)
+	if err != nil {
+		return false, err
+	}
+	for _, push := range pushes {
+		if commitID == push.HeadCommitID || commitID == push.BeforeCommitID() {
+			return true, nil
+		}
+	}
+
+	reviews, err := issues_model.FindReviews(ctx, issues_model.FindReviewOptions{
+		IssueID: pr.IssueID,
+		Type:    issues_model.ReviewTypeUnknown,
+	})
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(reviews, func(review *issues_model.Review) bool { return review.CommitID == commitID }), nil
+}
+
+// GetChangesBetweenHeads returns the diff between two heads of the pull request, e.g. the one of the last review of a user and the current one.
+// The heads don't need to be related, so that the changes are shown across force pushes too.
+func GetChangesBetweenHeads(ctx context.Context, gitRepo *git.Repository, pr *issues_model.PullRequest, beforeCommitID, afterCommitID string, whitespaceBehavior git.TrustedCmdArgs) (*gitdiff.Diff, error) {
+	for _, commitID := range []string{beforeCommitID, afterCommitID} {
+		if ok, err := IsHeadCommit(ctx, gitRepo, pr, commitID); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, util.NewNotExistErrorf("%s has never been the head of the pull request", commitID)
+		}
+		if !gitRepo.IsCommitExist(commitID) {
+			return nil, util.NewNotExistErrorf("%s doesn't exist anymore", commitID)
+		}
+	}
+
+	return gitdiff.GetDiff(ctx, gitRepo, &gitdiff.DiffOptions{
+		BeforeCommitID:     beforeCommitID,
+		AfterCommitID:      afterCommitID,
+		MaxLines:           setting.Git.MaxGitDiffLines,
+		MaxLineCharacters:  setting.Git.MaxGitDiffLineCharacters,
+		MaxFiles:           -1, // all the files are returned
+		WhitespaceBehavior: whitespaceBehavior,
+		DirectComparison:   true,
+	})
+}
@@ -105,3 +105,19 @@ func updateHeadByRebaseOnToBase(ctx context.Context, pr *issues_model.PullReques
 
 	return nil
 }
+
+// RebaseBranch rebases the head branch on to the base branch of the same repository in a temporary repository
+// and force pushes the result back to the head branch
+func RebaseBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, headBranch, baseBranch string) error {
+	// this pull request is never stored, it only describes the branches to the temporary repository machinery
+	pr := &issues_model.PullRequest{
+		HeadRepoID: repo.ID,
+		HeadRepo:   repo,
+		HeadBranch: headBranch,
+		BaseRepoID: repo.ID,
+		BaseRepo:   repo,
+		BaseBranch: baseBranch,
+		Flow:       issues_model.PullRequestFlowGithub,
+	}
+	return updateHeadByRebaseOnToBase(ctx, pr, doer)
+}
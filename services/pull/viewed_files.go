@@ -0,0 +1,174 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// ViewedFile is the viewed state of a file changed by a pull request for a reviewer
+type ViewedFile struct {
+	Path  string
+	State pull_model.ViewedState
+}
+
+// ReviewProgress represents how many of the files changed by a pull request a reviewer viewed
+type ReviewProgress struct {
+	NumFiles       int
+	NumViewedFiles int
+}
+
+// Percent returns the percentage of the changed files which have been viewed
+func (progress *ReviewProgress) Percent() int {
+	if progress.NumFiles == 0 {
+		return 0
+	}
+	return progress.NumViewedFiles * 100 / progress.NumFiles
+}
+
+// getChangedFiles returns the files changed by the pull request, renamed files are only listed under their new name
+// like in the diff of the pull request
+func getChangedFiles(ctx context.Context, gitRepo *git.Repository, pr *issues_model.PullRequest) ([]string, error) {
+	stdout, _, err := git.NewCommand(ctx, "diff", "--name-only", "-z", "-M").
+		AddDynamicArguments(pr.MergeBase, pr.GetGitRefName()).
+		RunStdString(&git.RunOpts{Dir: gitRepo.Path})
+	if err != nil {
+		return nil, err
+	}
+	files := strings.Split(stdout, "\x00")
+	// git emits a terminal NUL, so the last entry is always empty
+	return files[:len(files)-1], nil
+}
+
+// getViewedFiles returns the viewed state of the changed files according to the review state
+func getViewedFiles(gitRepo *git.Repository, pr *issues_model.PullRequest, review *pull_model.ReviewState, changedFiles []string) []*ViewedFile {
+	var changedSinceReview []string
+	if review != nil && review.UpdatedFiles != nil {
+		var err error
+		// Like in the diff of the pull request, the files are assumed not to have changed if the comparison fails
+		changedSinceReview, err = gitRepo.GetFilesChangedBetween(review.CommitSHA, pr.GetGitRefName())
+		if err != nil {
+			log.Error("Could not get changed files between %s and %s for pull request %d in repo with path %s. Assuming no changes. Error: %v", review.CommitSHA, pr.GetGitRefName(), pr.Index, gitRepo.Path, err)
+		}
+	}
+
+	files := make([]*ViewedFile, 0, len(changedFiles))
+	for _, path := range changedFiles {
+		file := &ViewedFile{Path: path, State: pull_model.Unviewed}
+		if review != nil {
+			file.State = review.UpdatedFiles[path]
+			if file.State == pull_model.Viewed && slices.Contains(changedSinceReview, path) {
+				file.State = pull_model.HasChanged
+			}
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// GetViewedFiles returns the viewed state of the files changed by the pull request for the user
+func GetViewedFiles(ctx context.Context, gitRepo *git.Repository, userID int64, pr *issues_model.PullRequest) ([]*ViewedFile, error) {
+	changedFiles, err := getChangedFiles(ctx, gitRepo, pr)
+	if err != nil {
+		return nil, err
+	}
+	review, err := pull_model.GetNewestReviewState(ctx, userID, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+	return getViewedFiles(gitRepo, pr, review, changedFiles), nil
+}
+
+// UpdateViewedFiles marks files changed by the pull request as viewed or unviewed for the user at the given head commit,
+// the current head of the pull request is used if it is empty.
+// Marking a directory applies to all the changed files under it, the state of the files given explicitly takes precedence.
+func UpdateViewedFiles(ctx context.Context, gitRepo *git.Repository, userID int64, pr *issues_model.PullRequest, headCommitSHA string, files, directories map[string]bool) error {
+	if headCommitSHA == "" {
+		var err error
+		headCommitSHA, err = gitRepo.GetRefCommitID(pr.GetGitRefName())
+		if err != nil {
+			return err
+		}
+	}
+
+	updatedFiles := make(map[string]pull_model.ViewedState, len(files))
+	setState := func(file string, viewed bool) {
+		// Only unviewed and viewed are possible, has-changed can not be set from the outside
+		state := pull_model.Unviewed
+		if viewed {
+			state = pull_model.Viewed
+		}
+		updatedFiles[file] = state
+	}
+
+	if len(directories) > 0 {
+		changedFiles, err := getChangedFiles(ctx, gitRepo, pr)
+		if err != nil {
+			return err
+		}
+		for dir, viewed := range directories {
+			dir = strings.Trim(dir, "/")
+			for _, file := range changedFiles {
+				if dir == "" || strings.HasPrefix(file, dir+"/") {
+					setState(file, viewed)
+				}
+			}
+		}
+	}
+	for file, viewed := range files {
+		setState(file, viewed)
+	}
+
+	if len(updatedFiles) == 0 {
+		return nil
+	}
+	return pull_model.UpdateReviewState(ctx, userID, pr.ID, headCommitSHA, updatedFiles)
+}
+
+// GetReviewProgresses returns the review progress of the user for the pull requests of the issues, which must belong to the repository.
+// Pull requests the user hasn't started to review are skipped.
+func GetReviewProgresses(ctx context.Context, gitRepo *git.Repository, userID int64, issues issues_model.IssueList) (map[int64]*ReviewProgress, error) {
+	pullIDs := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		if issue.IsPull && issue.PullRequest != nil {
+			pullIDs = append(pullIDs, issue.PullRequest.ID)
+		}
+	}
+	reviews, err := pull_model.GetNewestReviewStatesByPullIDs(ctx, userID, pullIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	progresses := make(map[int64]*ReviewProgress, len(reviews))
+	for _, issue := range issues {
+		if !issue.IsPull || issue.PullRequest == nil || issue.PullRequest.MergeBase == "" {
+			continue
+		}
+		pr := issue.PullRequest
+		review := reviews[pr.ID]
+		if review == nil {
+			continue
+		}
+		changedFiles, err := getChangedFiles(ctx, gitRepo, pr)
+		if err != nil {
+			log.Error("Could not get the changed files of pull request %d in repo with path %s: %v", pr.Index, gitRepo.Path, err)
+			continue
+		}
+		progress := &ReviewProgress{NumFiles: len(changedFiles)}
+		for _, file := range getViewedFiles(gitRepo, pr, review, changedFiles) {
+			if file.State == pull_model.Viewed {
+				progress.NumViewedFiles++
+			}
+		}
+		progresses[pr.ID] = progress
+	}
+	return progresses, nil
+}
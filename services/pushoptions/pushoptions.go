@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package pushoptions handles the options given with `git push -o <option>` which act on the pull requests of a push
+package pushoptions
+
+import (
+	"context"
+	"fmt"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/services/automerge"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// scheduleAutoMergeRequested reports whether the push asks to merge its pull requests once their checks succeed
+func scheduleAutoMergeRequested(opts *private.HookOptions) bool {
+	// the pusher of a deploy key is the owner of the repository, the key must not be able to merge on its behalf
+	return opts.GitPushOptions.Bool(private.GitPushOptionMergeWhenChecksSucceed).Value() && opts.DeployKeyID == 0
+}
+
+// HandlePullRequestPush applies the push options to a pull request created or updated by an AGit push
+func HandlePullRequestPush(ctx context.Context, pusher *user_model.User, pr *issues_model.PullRequest, opts *private.HookOptions) error {
+	if !scheduleAutoMergeRequested(opts) {
+		return nil
+	}
+	return scheduleAutoMerge(ctx, pusher, pr)
+}
+
+// HandleBranchUpdates applies the push options to the open pull requests from the branches updated by a push
+func HandleBranchUpdates(ctx context.Context, repo *repo_model.Repository, opts *private.HookOptions, updates []*repo_module.PushUpdateOptions) error {
+	if !scheduleAutoMergeRequested(opts) {
+		return nil
+	}
+
+	pusher, err := user_model.GetUserByID(ctx, opts.UserID)
+	if err != nil {
+		return fmt.Errorf("GetUserByID: %w", err)
+	}
+
+	for _, update := range updates {
+		if !update.RefFullName.IsBranch() || update.IsDelRef() {
+			continue
+		}
+		prs, err := issues_model.GetUnmergedPullRequestsByHeadInfo(ctx, repo.ID, update.RefFullName.BranchName())
+		if err != nil {
+			return fmt.Errorf("GetUnmergedPullRequestsByHeadInfo: %w", err)
+		}
+		for _, pr := range prs {
+			if err := scheduleAutoMerge(ctx, pusher, pr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scheduleAutoMerge schedules the pull request to be merged with the default merge style of its base repository once its checks succeed,
+// nothing is done if the pusher isn't allowed to merge it
+func scheduleAutoMerge(ctx context.Context, pusher *user_model.User, pr *issues_model.PullRequest) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return fmt.Errorf("LoadBaseRepo: %w", err)
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, pr.BaseRepo, pusher)
+	if err != nil {
+		return fmt.Errorf("GetUserRepoPermission: %w", err)
+	}
+	if allowed, err := pull_service.IsUserAllowedToMerge(ctx, pr, perm, pusher); err != nil {
+		return fmt.Errorf("IsUserAllowedToMerge: %w", err)
+	} else if !allowed {
+		log.Debug("%-v: %s is not allowed to schedule the merge with a push option", pr, pusher.Name)
+		return nil
+	}
+
+	prUnit, err := pr.BaseRepo.GetUnit(ctx, unit.TypePullRequests)
+	if err != nil {
+		return fmt.Errorf("GetUnit: %w", err)
+	}
+	prConfig := prUnit.PullRequestsConfig()
+	style := prConfig.GetDefaultMergeStyle()
+	if !prConfig.IsMergeStyleAllowed(style) {
+		log.Debug("%-v: the default merge style %s is not allowed", pr, style)
+		return nil
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, pr.BaseRepo)
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	message, _, err := pull_service.GetDefaultMergeMessage(ctx, gitRepo, pr, style)
+	if err != nil {
+		return fmt.Errorf("GetDefaultMergeMessage: %w", err)
+	}
+
+	if _, err := automerge.ScheduleAutoMerge(ctx, pusher, pr, style, message, pull_model.AutoMergeConditions{}); err != nil && !pull_model.IsErrAlreadyScheduledToAutoMerge(err) {
+		return fmt.Errorf("ScheduleAutoMerge: %w", err)
+	}
+	return nil
+}
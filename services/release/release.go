@@ -361,9 +361,15 @@ func DeleteReleaseByID(ctx context.Context, repo *repo_model.Repository, rel *re
 	if err := repo_model.DeleteAttachmentsByRelease(ctx, rel.ID); err != nil {
 		return fmt.Errorf("DeleteAttachments: %w", err)
 	}
+	if err := repo_model.DeleteReleaseSignature(ctx, rel.ID); err != nil {
+		return fmt.Errorf("DeleteReleaseSignature: %w", err)
+	}
 
 	for i := range rel.Attachments {
 		attachment := rel.Attachments[i]
+		if attachment.IsExternal() {
+			continue
+		}
 		if err := storage.Attachments.Delete(attachment.RelativePath()); err != nil {
 			log.Error("Delete attachment %s of release %s failed: %v", attachment.UUID, rel.ID, err)
 		}
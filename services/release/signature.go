@@ -0,0 +1,226 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	asymkey_service "code.gitea.io/gitea/services/asymkey"
+	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+)
+
+const provenanceBuildType = "https://gitea.com/gitea/gitea/release@v1"
+
+type artifactChecksum struct {
+	Name   string
+	SHA256 string
+}
+
+type provenanceDigest struct {
+	SHA256    string `json:"sha256,omitempty"`
+	GitCommit string `json:"gitCommit,omitempty"`
+}
+
+type provenanceResource struct {
+	Name   string           `json:"name,omitempty"`
+	URI    string           `json:"uri,omitempty"`
+	Digest provenanceDigest `json:"digest"`
+}
+
+// provenanceStatement is an in-toto statement with a SLSA provenance predicate
+type provenanceStatement struct {
+	Type          string                `json:"_type"`
+	Subject       []*provenanceResource `json:"subject"`
+	PredicateType string                `json:"predicateType"`
+	Predicate     struct {
+		BuildDefinition struct {
+			BuildType            string                `json:"buildType"`
+			ExternalParameters   map[string]string     `json:"externalParameters"`
+			ResolvedDependencies []*provenanceResource `json:"resolvedDependencies"`
+		} `json:"buildDefinition"`
+		RunDetails struct {
+			Builder struct {
+				ID string `json:"id"`
+			} `json:"builder"`
+			Metadata struct {
+				InvocationID string `json:"invocationId"`
+				FinishedOn   string `json:"finishedOn"`
+			} `json:"metadata"`
+		} `json:"runDetails"`
+	} `json:"predicate"`
+}
+
+func hashReader(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func releaseFingerprint(rel *repo_model.Release, keyID string) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n", rel.TagName, rel.Sha1, keyID)
+	for _, a := range rel.Attachments {
+		_, _ = fmt.Fprintf(h, "%s %s %d %s\n", a.UUID, a.Name, a.Size, a.ExternalSHA256)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sourceArchiveChecksums(ctx context.Context, gitRepo *git.Repository, rel *repo_model.Release) ([]*artifactChecksum, error) {
+	checksums := make([]*artifactChecksum, 0, 2)
+	for _, ext := range []string{".zip", ".tar.gz"} {
+		aReq, err := archiver_service.NewRequest(rel.RepoID, gitRepo, rel.TagName+ext)
+		if err != nil {
+			return nil, err
+		}
+		archiver, err := aReq.Await(ctx)
+		if err != nil {
+			return nil, err
+		}
+		fr, err := storage.RepoArchives.Open(archiver.RelativePath())
+		if err != nil {
+			return nil, err
+		}
+		sum, err := hashReader(fr)
+		fr.Close()
+		if err != nil {
+			return nil, err
+		}
+		checksums = append(checksums, &artifactChecksum{Name: rel.Repo.Name + "-" + aReq.GetArchiveName(), SHA256: sum})
+	}
+	return checksums, nil
+}
+
+func attachmentChecksums(rel *repo_model.Release) ([]*artifactChecksum, error) {
+	checksums := make([]*artifactChecksum, 0, len(rel.Attachments))
+	for _, a := range rel.Attachments {
+		if a.IsExternal() {
+			checksums = append(checksums, &artifactChecksum{Name: a.Name, SHA256: a.ExternalSHA256})
+			continue
+		}
+		fr, err := storage.Attachments.Open(a.RelativePath())
+		if err != nil {
+			return nil, err
+		}
+		sum, err := hashReader(fr)
+		fr.Close()
+		if err != nil {
+			return nil, err
+		}
+		checksums = append(checksums, &artifactChecksum{Name: a.Name, SHA256: sum})
+	}
+	sort.SliceStable(checksums, func(i, j int) bool {
+		return checksums[i].Name < checksums[j].Name
+	})
+	return checksums, nil
+}
+
+func buildProvenance(rel *repo_model.Release, checksums []*artifactChecksum) (string, error) {
+	statement := &provenanceStatement{
+		Type:          "https://in-toto.io/Statement/v1",
+		PredicateType: "https://slsa.dev/provenance/v1",
+	}
+	for _, c := range checksums {
+		statement.Subject = append(statement.Subject, &provenanceResource{Name: c.Name, Digest: provenanceDigest{SHA256: c.SHA256}})
+	}
+	def := &statement.Predicate.BuildDefinition
+	def.BuildType = provenanceBuildType
+	def.ExternalParameters = map[string]string{
+		"repository": rel.Repo.HTMLURL(),
+		"tag":        rel.TagName,
+	}
+	def.ResolvedDependencies = []*provenanceResource{{
+		URI:    "git+" + rel.Repo.CloneLink().HTTPS + "@" + git.TagPrefix + rel.TagName,
+		Digest: provenanceDigest{GitCommit: rel.Sha1},
+	}}
+	run := &statement.Predicate.RunDetails
+	run.Builder.ID = setting.AppURL
+	run.Metadata.InvocationID = fmt.Sprintf("%s/releases/%d", rel.Repo.APIURL(), rel.ID)
+	run.Metadata.FinishedOn = time.Now().UTC().Format(time.RFC3339)
+
+	content, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func signChecksums(ctx context.Context, rel *repo_model.Release, keyID, checksums string) string {
+	stdout, stderr, err := process.GetManager().ExecDirEnvStdIn(ctx, -1, rel.Repo.RepoPath(),
+		fmt.Sprintf("SignReleaseChecksums: %d", rel.ID), nil, strings.NewReader(checksums),
+		"gpg", "--batch", "--yes", "--armor", "--detach-sign", "--local-user", keyID)
+	if err != nil {
+		log.Error("Unable to sign the checksums of release %d with key %s: %s, %v", rel.ID, keyID, stderr, err)
+		return ""
+	}
+	return stdout
+}
+
+// GetReleaseSignature returns the checksums file of the source archives and attachments of a release together
+// with its signature and a provenance statement. They are regenerated when the artifacts of the release changed.
+func GetReleaseSignature(ctx context.Context, gitRepo *git.Repository, rel *repo_model.Release) (*repo_model.ReleaseSignature, error) {
+	if err := rel.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	keyID, _ := asymkey_service.SigningKey(ctx, rel.Repo.RepoPath())
+	fingerprint := releaseFingerprint(rel, keyID)
+	sig, err := repo_model.GetReleaseSignature(ctx, rel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if sig != nil && sig.Fingerprint == fingerprint {
+		return sig, nil
+	}
+
+	checksums, err := sourceArchiveChecksums(ctx, gitRepo, rel)
+	if err != nil {
+		return nil, fmt.Errorf("sourceArchiveChecksums: %w", err)
+	}
+	assetChecksums, err := attachmentChecksums(rel)
+	if err != nil {
+		return nil, fmt.Errorf("attachmentChecksums: %w", err)
+	}
+	checksums = append(checksums, assetChecksums...)
+
+	var sb strings.Builder
+	for _, c := range checksums {
+		// same format as sha256sum, so the file can be checked with "sha256sum -c"
+		_, _ = fmt.Fprintf(&sb, "%s  %s\n", c.SHA256, c.Name)
+	}
+
+	provenance, err := buildProvenance(rel, checksums)
+	if err != nil {
+		return nil, err
+	}
+
+	sig = &repo_model.ReleaseSignature{
+		ReleaseID:   rel.ID,
+		Fingerprint: fingerprint,
+		Checksums:   sb.String(),
+		Provenance:  provenance,
+	}
+	if keyID != "" {
+		sig.Signature = signChecksums(ctx, rel, keyID, sig.Checksums)
+		if sig.Signature != "" {
+			sig.KeyID = keyID
+		}
+	}
+	return sig, repo_model.SaveReleaseSignature(ctx, sig)
+}
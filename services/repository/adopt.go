@@ -229,6 +229,7 @@ func DeleteUnadoptedRepository(ctx context.Context, doer, u *user_model.User, re
 		}
 	}
 
+	git.CloseCatFileProcesses(repoPath)
 	return util.RemoveAll(repoPath)
 }
 
@@ -22,8 +22,12 @@ import (
 	"code.gitea.io/gitea/modules/queue"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
 )
 
+// maxArchivePathLength limits the paths of the archives, their storage file names contain them
+const maxArchivePathLength = 128
+
 // ArchiveRequest defines the parameters of an archive request, which notably
 // includes the specific repository being archived as well as the commit, the
 // name by which it was requested, and the kind of archive being requested.
@@ -34,6 +38,8 @@ type ArchiveRequest struct {
 	refName  string
 	Type     git.ArchiveType
 	CommitID string
+	Include  string
+	Path     string
 }
 
 // ErrUnknownArchiveFormat request archive format is not supported
@@ -105,7 +111,39 @@ func NewRequest(repoID int64, repo *git.Repository, uri string) (*ArchiveRequest
 // GetArchiveName returns the name of the caller, based on the ref used by the
 // caller to create this request.
 func (aReq *ArchiveRequest) GetArchiveName() string {
-	return strings.ReplaceAll(aReq.refName, "/", "-") + "." + aReq.Type.String()
+	name := aReq.refName
+	if aReq.Path != "" {
+		name += "-" + aReq.Path
+	}
+	return strings.ReplaceAll(name, "/", "-") + "." + aReq.Type.String()
+}
+
+// SetPath limits the archive to a directory or a file of the tree, bundles can't be limited
+func (aReq *ArchiveRequest) SetPath(repo *git.Repository, treePath string) error {
+	treePath = util.PathJoinRelX(treePath)
+	if treePath == "" || treePath == "." {
+		aReq.Path = ""
+		return nil
+	}
+	if aReq.Type == git.BUNDLE {
+		return util.NewInvalidArgumentErrorf("bundles can't be limited to a path")
+	}
+	if len(treePath) > maxArchivePathLength {
+		return util.NewInvalidArgumentErrorf("the path is longer than %d bytes", maxArchivePathLength)
+	}
+
+	commit, err := repo.GetCommit(aReq.CommitID)
+	if err != nil {
+		return err
+	}
+	if _, err := commit.GetTreeEntryByPath(treePath); err != nil {
+		if git.IsErrNotExist(err) {
+			return util.NewNotExistErrorf("path %q doesn't exist in %s", treePath, aReq.refName)
+		}
+		return err
+	}
+	aReq.Path = treePath
+	return nil
 }
 
 // Await awaits the completion of an ArchiveRequest. If the archive has
@@ -115,7 +153,7 @@ func (aReq *ArchiveRequest) GetArchiveName() string {
 // context is cancelled/times out a started archiver will still continue to run
 // in the background.
 func (aReq *ArchiveRequest) Await(ctx context.Context) (*repo_model.RepoArchiver, error) {
-	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID)
+	archiver, err := repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID, aReq.Include, aReq.Path)
 	if err != nil {
 		return nil, fmt.Errorf("models.GetRepoArchiver: %w", err)
 	}
@@ -140,7 +178,7 @@ func (aReq *ArchiveRequest) Await(ctx context.Context) (*repo_model.RepoArchiver
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-poll.C:
-			archiver, err = repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID)
+			archiver, err = repo_model.GetRepoArchiver(ctx, aReq.RepoID, aReq.Type, aReq.CommitID, aReq.Include, aReq.Path)
 			if err != nil {
 				return nil, fmt.Errorf("repo_model.GetRepoArchiver: %w", err)
 			}
@@ -160,7 +198,7 @@ func doArchive(ctx context.Context, r *ArchiveRequest) (*repo_model.RepoArchiver
 	ctx, _, finished := process.GetManager().AddContext(txCtx, fmt.Sprintf("ArchiveRequest[%d]: %s", r.RepoID, r.GetArchiveName()))
 	defer finished()
 
-	archiver, err := repo_model.GetRepoArchiver(ctx, r.RepoID, r.Type, r.CommitID)
+	archiver, err := repo_model.GetRepoArchiver(ctx, r.RepoID, r.Type, r.CommitID, r.Include, r.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -176,6 +214,8 @@ func doArchive(ctx context.Context, r *ArchiveRequest) (*repo_model.RepoArchiver
 			RepoID:   r.RepoID,
 			Type:     r.Type,
 			CommitID: r.CommitID,
+			Include:  r.Include,
+			Path:     r.Path,
 			Status:   repo_model.ArchiverGenerating,
 		}
 		if err := db.Insert(ctx, archiver); err != nil {
@@ -223,28 +263,32 @@ func doArchive(ctx context.Context, r *ArchiveRequest) (*repo_model.RepoArchiver
 			}
 		}()
 
-		if archiver.Type == git.BUNDLE {
+		if archiver.Include != "" {
+			err = createArchiveWithInclude(ctx, repo, gitRepo, r, w)
+		} else if archiver.Type == git.BUNDLE {
 			err = gitRepo.CreateBundle(
 				ctx,
 				archiver.CommitID,
 				w,
 			)
 		} else {
+			var paths []string
+			if archiver.Path != "" {
+				paths = []string{archiver.Path}
+			}
 			err = gitRepo.CreateArchive(
 				ctx,
 				archiver.Type,
 				w,
 				setting.Repository.PrefixArchiveFiles,
 				archiver.CommitID,
+				paths...,
 			)
 		}
 		_ = w.CloseWithError(err)
 		done <- err
 	}(done, w, archiver, gitRepo)
 
-	// TODO: add lfs data to zip
-	// TODO: add submodule data to zip
-
 	if _, err := storage.RepoArchives.Save(rPath, rd, -1); err != nil {
 		return nil, fmt.Errorf("unable to write archive: %w", err)
 	}
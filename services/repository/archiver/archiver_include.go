@@ -0,0 +1,338 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package archiver
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	// lfsPointerMaxSize is the maximum size of the LFS pointer files
+	lfsPointerMaxSize = 1024
+	// maxSubmoduleDepth limits the nesting of the resolved submodules
+	maxSubmoduleDepth = 5
+)
+
+// archiveProgress tracks the files written to an archive being generated
+type archiveProgress struct {
+	total atomic.Int64
+	done  atomic.Int64
+}
+
+func (p *archiveProgress) percent() int {
+	total := p.total.Load()
+	if total <= 0 {
+		return 0
+	}
+	return int(min(p.done.Load()*100/total, 99))
+}
+
+// archiveProgresses holds the progress of the archives with extra contents being generated by this instance
+var archiveProgresses sync.Map
+
+// ParseArchiveInclude parses a comma separated list of extra contents and returns it normalized
+func ParseArchiveInclude(include string) (string, error) {
+	includes := make([]string, 0, 2)
+	for _, s := range strings.Split(include, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" || slices.Contains(includes, s) {
+			continue
+		}
+		switch repo_model.ArchiveInclude(s) {
+		case repo_model.ArchiveIncludeLFS, repo_model.ArchiveIncludeSubmodules:
+			includes = append(includes, s)
+		default:
+			return "", util.NewInvalidArgumentErrorf("unknown archive content %q", s)
+		}
+	}
+	slices.Sort(includes)
+	return strings.Join(includes, ","), nil
+}
+
+// SetInclude sets the extra contents of the archive, they are only supported by tar.gz archives
+func (aReq *ArchiveRequest) SetInclude(include string) error {
+	include, err := ParseArchiveInclude(include)
+	if err != nil {
+		return err
+	}
+	if include != "" && aReq.Type != git.TARGZ {
+		return util.NewInvalidArgumentErrorf("only tar.gz archives can include %s", include)
+	}
+	aReq.Include = include
+	return nil
+}
+
+func (aReq *ArchiveRequest) progressKey() string {
+	return fmt.Sprintf("%d/%s/%s/%s/%s", aReq.RepoID, aReq.CommitID, aReq.Type, aReq.Include, aReq.Path)
+}
+
+// Progress returns the percentage of the archive which has been generated,
+// it is only known for the archives with extra contents being generated by this instance.
+func (aReq *ArchiveRequest) Progress() int {
+	if p, ok := archiveProgresses.Load(aReq.progressKey()); ok {
+		return p.(*archiveProgress).percent()
+	}
+	return 0
+}
+
+// archiveWriter writes a tar archive of a git tree with the LFS objects and the submodules
+type archiveWriter struct {
+	tw                *tar.Writer
+	includeLFS        bool
+	includeSubmodules bool
+	progress          *archiveProgress
+	contentStore      *lfs.ContentStore
+}
+
+// createArchiveWithInclude writes the tar.gz archive of the request with its extra contents
+func createArchiveWithInclude(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, r *ArchiveRequest, w io.Writer) error {
+	progress := &archiveProgress{}
+	key := r.progressKey()
+	archiveProgresses.Store(key, progress)
+	defer archiveProgresses.Delete(key)
+
+	archiver := &repo_model.RepoArchiver{Include: r.Include}
+	gzw := gzip.NewWriter(w)
+	aw := &archiveWriter{
+		tw:                tar.NewWriter(gzw),
+		includeLFS:        archiver.HasInclude(repo_model.ArchiveIncludeLFS) && setting.LFS.StartServer,
+		includeSubmodules: archiver.HasInclude(repo_model.ArchiveIncludeSubmodules),
+		progress:          progress,
+	}
+	if aw.includeLFS {
+		aw.contentStore = lfs.NewContentStore()
+	}
+
+	prefix := ""
+	if setting.Repository.PrefixArchiveFiles {
+		prefix = filepath.Base(strings.TrimSuffix(gitRepo.Path, ".git")) + "/"
+	}
+	if err := aw.writeTree(ctx, repo, gitRepo, r.CommitID, r.Path, prefix, 0); err != nil {
+		return err
+	}
+	if err := aw.tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+// writeTree writes the files of the commit of the repository under the prefix, then the trees of its submodules,
+// only the files and the submodules under the tree path are written if it isn't empty
+func (aw *archiveWriter) writeTree(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, commitID, treePath, prefix string, depth int) error {
+	var paths []string
+	if treePath != "" {
+		paths = []string{treePath}
+	}
+	numEntries, gitlinks, err := listTreeEntries(ctx, gitRepo, commitID, paths...)
+	if err != nil {
+		return err
+	}
+	if depth == 0 {
+		aw.progress.total.Store(int64(numEntries))
+	}
+
+	rd, wr := io.Pipe()
+	defer rd.Close()
+	go func() {
+		// Avoid LFS hooks getting installed because of /etc/gitconfig, like CreateArchive.
+		err := git.NewCommand(ctx, "archive", "--format=tar").AddOptionFormat("--prefix=%s", prefix).AddDynamicArguments(commitID).AddDashesAndList(paths...).
+			Run(&git.RunOpts{Dir: gitRepo.Path, Stdout: wr, Env: append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1")})
+		_ = wr.CloseWithError(err)
+	}()
+
+	tr := tar.NewReader(rd)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("unable to read the archive of %s: %w", repo.FullName(), err)
+		}
+
+		// git archive stores the commit id in a global header, only keep the one of the repository
+		if hdr.Typeflag == tar.TypeXGlobalHeader && depth > 0 {
+			continue
+		}
+
+		if aw.includeLFS && hdr.Typeflag == tar.TypeReg && hdr.Size <= lfsPointerMaxSize {
+			if err := aw.writeFileOrLFSObject(ctx, repo, hdr, tr); err != nil {
+				return err
+			}
+		} else {
+			if err := aw.tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := io.Copy(aw.tw, tr); err != nil {
+				return err
+			}
+		}
+
+		if depth == 0 && hdr.Typeflag != tar.TypeDir && hdr.Typeflag != tar.TypeXGlobalHeader {
+			aw.progress.done.Add(1)
+		}
+	}
+
+	if !aw.includeSubmodules || len(gitlinks) == 0 {
+		return nil
+	}
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return err
+	}
+	for _, gitlink := range gitlinks {
+		if depth < maxSubmoduleDepth {
+			if err := aw.writeSubmodule(ctx, repo, commit, gitlink, prefix, depth); err != nil {
+				return err
+			}
+		}
+		if depth == 0 {
+			aw.progress.done.Add(1)
+		}
+	}
+	return nil
+}
+
+// writeFileOrLFSObject writes the LFS object if the file is the pointer of an LFS object of the repository, or the file itself
+func (aw *archiveWriter) writeFileOrLFSObject(ctx context.Context, repo *repo_model.Repository, hdr *tar.Header, rd io.Reader) error {
+	buf, err := io.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+
+	pointer, _ := lfs.ReadPointerFromBuffer(buf)
+	if pointer.IsValid() {
+		if _, err := git_model.GetLFSMetaObjectByOid(ctx, repo.ID, pointer.Oid); err == nil {
+			obj, err := aw.contentStore.Get(pointer)
+			if err == nil {
+				defer obj.Close()
+				var stat os.FileInfo
+				if stat, err = obj.Stat(); err == nil && stat.Size() != pointer.Size {
+					err = fmt.Errorf("size %d doesn't match the pointer", stat.Size())
+				}
+			}
+			if err == nil {
+				lfsHdr := *hdr
+				lfsHdr.Size = pointer.Size
+				if err := aw.tw.WriteHeader(&lfsHdr); err != nil {
+					return err
+				}
+				_, err = io.CopyN(aw.tw, obj, pointer.Size)
+				return err
+			}
+			log.Warn("Unable to read the LFS object %s of %s for its archive: %v", pointer.Oid, repo.FullName(), err)
+		} else if !errors.Is(err, git_model.ErrLFSObjectNotExist) {
+			return err
+		}
+	}
+
+	if err := aw.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = aw.tw.Write(buf)
+	return err
+}
+
+// writeSubmodule writes the tree of a submodule if it is a public repository of this instance.
+// The archive can be downloaded by anyone who can read the repository, so private submodules are never included.
+func (aw *archiveWriter) writeSubmodule(ctx context.Context, repo *repo_model.Repository, commit *git.Commit, gitlink treeGitlink, prefix string, depth int) error {
+	module, err := commit.GetSubModule(gitlink.Path)
+	if err != nil || module == nil {
+		return err
+	}
+
+	refURL := git.NewSubModuleFile(commit, module.URL, gitlink.CommitID).RefURL(setting.AppURL, repo.FullName(), setting.SSH.Domain)
+	fullName, ok := strings.CutPrefix(refURL, strings.TrimSuffix(setting.AppURL, "/")+"/")
+	if !ok {
+		// the submodule isn't hosted on this instance
+		return nil
+	}
+	ownerName, repoName, ok := strings.Cut(fullName, "/")
+	if !ok || strings.Contains(repoName, "/") {
+		return nil
+	}
+
+	subRepo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := subRepo.LoadOwner(ctx); err != nil {
+		return err
+	}
+	if subRepo.IsPrivate || subRepo.Owner.Visibility != structs.VisibleTypePublic || subRepo.IsEmpty {
+		return nil
+	}
+
+	subGitRepo, err := gitrepo.OpenRepository(ctx, subRepo)
+	if err != nil {
+		return err
+	}
+	defer subGitRepo.Close()
+	if _, err := subGitRepo.GetCommit(gitlink.CommitID); err != nil {
+		if git.IsErrNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return aw.writeTree(ctx, subRepo, subGitRepo, gitlink.CommitID, "", prefix+gitlink.Path+"/", depth+1)
+}
+
+// treeGitlink represents a submodule entry of a git tree
+type treeGitlink struct {
+	Path     string
+	CommitID string
+}
+
+// listTreeEntries returns the number of entries of the tree of the commit, including the submodules, and its submodule entries,
+// only the entries under the given paths are listed if there are any
+func listTreeEntries(ctx context.Context, gitRepo *git.Repository, commitID string, paths ...string) (int, []treeGitlink, error) {
+	stdout, _, err := git.NewCommand(ctx, "ls-tree", "-r", "-z").AddDynamicArguments(commitID).AddDashesAndList(paths...).RunStdBytes(&git.RunOpts{Dir: gitRepo.Path})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	numEntries := 0
+	var gitlinks []treeGitlink
+	for _, entry := range bytes.Split(stdout, []byte{0}) {
+		if len(entry) == 0 {
+			continue
+		}
+		numEntries++
+		// <mode> SP <type> SP <object> TAB <file>
+		info, path, ok := bytes.Cut(entry, []byte{'\t'})
+		fields := bytes.Fields(info)
+		if !ok || len(fields) != 3 {
+			return 0, nil, fmt.Errorf("unexpected ls-tree entry: %q", entry)
+		}
+		if string(fields[1]) == "commit" {
+			gitlinks = append(gitlinks, treeGitlink{Path: string(path), CommitID: string(fields[2])})
+		}
+	}
+	return numEntries, gitlinks, nil
+}
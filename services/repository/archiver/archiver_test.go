@@ -10,6 +10,8 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/contexttest"
 
 	_ "code.gitea.io/gitea/models/actions"
@@ -132,3 +134,43 @@ func TestErrUnknownArchiveFormat(t *testing.T) {
 	err := ErrUnknownArchiveFormat{RequestFormat: "master"}
 	assert.True(t, errors.Is(err, ErrUnknownArchiveFormat{}))
 }
+
+func TestArchiveRequestInclude(t *testing.T) {
+	include, err := ParseArchiveInclude(" submodules,lfs,submodules,")
+	assert.NoError(t, err)
+	assert.Equal(t, "lfs,submodules", include)
+
+	_, err = ParseArchiveInclude("lfs,wiki")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	req := &ArchiveRequest{Type: git.TARGZ}
+	assert.NoError(t, req.SetInclude("lfs"))
+	assert.Equal(t, "lfs", req.Include)
+
+	req = &ArchiveRequest{Type: git.ZIP}
+	assert.ErrorIs(t, req.SetInclude("lfs"), util.ErrInvalidArgument)
+	assert.NoError(t, req.SetInclude(""))
+}
+
+func TestArchiveRequestPath(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	ctx, _ := contexttest.MockContext(t, "user27/repo49")
+	contexttest.LoadRepo(t, ctx, 49)
+	contexttest.LoadGitRepo(t, ctx)
+	defer ctx.Repo.GitRepo.Close()
+
+	req, err := NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, "aacbdfe9e1c4.tar.gz")
+	assert.NoError(t, err)
+	assert.NoError(t, req.SetPath(ctx.Repo.GitRepo, "/test/../test/"))
+	assert.Equal(t, "test", req.Path)
+	assert.Equal(t, "aacbdfe9e1c4-test.tar.gz", req.GetArchiveName())
+
+	assert.ErrorIs(t, req.SetPath(ctx.Repo.GitRepo, "missing"), util.ErrNotExist)
+	assert.NoError(t, req.SetPath(ctx.Repo.GitRepo, "/"))
+	assert.Empty(t, req.Path)
+
+	req, err = NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, "aacbdfe9e1c4.bundle")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, req.SetPath(ctx.Repo.GitRepo, "test"), util.ErrInvalidArgument)
+}
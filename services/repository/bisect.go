@@ -0,0 +1,152 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// BisectVerdict is the result of testing a commit while bisecting
+type BisectVerdict string
+
+const (
+	BisectVerdictGood BisectVerdict = "good"
+	BisectVerdictBad  BisectVerdict = "bad"
+	BisectVerdictSkip BisectVerdict = "skip"
+)
+
+// resolveBisectCommit returns the ID of the commit referenced by the ref
+func resolveBisectCommit(gitRepo *git.Repository, ref string) (string, error) {
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", util.NewInvalidArgumentErrorf("commit %q doesn't exist", ref)
+		}
+		return "", err
+	}
+	return commit.ID.String(), nil
+}
+
+// updateBisectNext computes the next step of the bisect session from the commits marked so far
+func updateBisectNext(gitRepo *git.Repository, session *repo_model.RepoBisectSession) error {
+	result, err := gitRepo.BisectNext(&git.BisectState{
+		Bad:     session.Bad,
+		Good:    session.Good,
+		Skipped: session.Skipped,
+		Path:    session.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to compute the next bisect step: %w", err)
+	}
+	session.Candidate = result.Candidate
+	session.Remaining = result.Remaining
+	session.Steps = result.Steps
+	session.FirstBad = result.FirstBad
+	session.OnlySkipped = result.OnlySkipped
+	return nil
+}
+
+// StartBisect starts a bisect session of the doer in the repository, replacing the previous one if any
+func StartBisect(ctx context.Context, gitRepo *git.Repository, repo *repo_model.Repository, doer *user_model.User, bad string, good []string, path string) (*repo_model.RepoBisectSession, error) {
+	if len(good) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("at least one good commit is required")
+	}
+
+	session := &repo_model.RepoBisectSession{RepoID: repo.ID, UserID: doer.ID, Path: util.PathJoinRelX(path)}
+	if session.Path == "." {
+		session.Path = ""
+	}
+	var err error
+	if session.Bad, err = resolveBisectCommit(gitRepo, bad); err != nil {
+		return nil, err
+	}
+	for _, ref := range good {
+		commitID, err := resolveBisectCommit(gitRepo, ref)
+		if err != nil {
+			return nil, err
+		}
+		if commitID == session.Bad {
+			return nil, util.NewInvalidArgumentErrorf("commit %q can't be both good and bad", ref)
+		}
+		if !slices.Contains(session.Good, commitID) {
+			session.Good = append(session.Good, commitID)
+		}
+	}
+	if err := updateBisectNext(gitRepo, session); err != nil {
+		return nil, err
+	}
+
+	return session, db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := repo_model.DeleteRepoBisectSession(ctx, repo.ID, doer.ID); err != nil {
+			return err
+		}
+		return repo_model.SaveRepoBisectSession(ctx, session)
+	})
+}
+
+// MarkBisectCommit records the verdict of the doer on a commit of their bisect session in the repository
+// and computes the next step, the current candidate is marked if ref is empty.
+func MarkBisectCommit(ctx context.Context, gitRepo *git.Repository, repo *repo_model.Repository, doer *user_model.User, ref string, verdict BisectVerdict) (*repo_model.RepoBisectSession, error) {
+	session, err := repo_model.GetRepoBisectSession(ctx, repo.ID, doer.ID)
+	if err != nil {
+		return nil, err
+	} else if session == nil {
+		return nil, util.NewNotExistErrorf("there is no bisect session")
+	}
+
+	commitID := session.Candidate
+	if ref != "" {
+		if commitID, err = resolveBisectCommit(gitRepo, ref); err != nil {
+			return nil, err
+		}
+	} else if commitID == "" {
+		return nil, util.NewInvalidArgumentErrorf("the first bad commit has been found, a commit is required")
+	}
+
+	session.Good = slices.DeleteFunc(session.Good, func(id string) bool { return id == commitID })
+	session.Skipped = slices.DeleteFunc(session.Skipped, func(id string) bool { return id == commitID })
+	switch verdict {
+	case BisectVerdictGood:
+		if commitID == session.Bad {
+			return nil, util.NewInvalidArgumentErrorf("the bad commit can't be marked as good")
+		}
+		session.Good = append(session.Good, commitID)
+	case BisectVerdictBad:
+		session.Bad = commitID
+	case BisectVerdictSkip:
+		if commitID == session.Bad {
+			return nil, util.NewInvalidArgumentErrorf("the bad commit can't be skipped")
+		}
+		session.Skipped = append(session.Skipped, commitID)
+	default:
+		return nil, util.NewInvalidArgumentErrorf("unknown verdict %q", verdict)
+	}
+	if len(session.Good) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("at least one good commit is required")
+	}
+
+	if err := updateBisectNext(gitRepo, session); err != nil {
+		return nil, err
+	}
+	return session, repo_model.SaveRepoBisectSession(ctx, session)
+}
+
+// ResetBisect ends the bisect session of the doer in the repository
+func ResetBisect(ctx context.Context, repo *repo_model.Repository, doer *user_model.User) error {
+	deleted, err := repo_model.DeleteRepoBisectSession(ctx, repo.ID, doer.ID)
+	if err != nil {
+		return err
+	} else if !deleted {
+		return util.NewNotExistErrorf("there is no bisect session")
+	}
+	return nil
+}
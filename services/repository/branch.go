@@ -29,6 +29,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 	notify_service "code.gitea.io/gitea/services/notify"
+	pull_service "code.gitea.io/gitea/services/pull"
 	files_service "code.gitea.io/gitea/services/repository/files"
 
 	"xorm.io/builder"
@@ -510,6 +511,76 @@ func DeleteBranch(ctx context.Context, doer *user_model.User, repo *repo_model.R
 	return nil
 }
 
+// RebaseBranch rebases a branch on to another branch of the same repository entirely server-side,
+// it returns a models.ErrRebaseConflicts listing the conflicted files if the rebase cannot be completed
+func RebaseBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, branchName, ontoBranchName string) error {
+	if err := repo.MustNotBeArchived(); err != nil {
+		return err
+	}
+
+	if branchName == ontoBranchName {
+		return util.NewInvalidArgumentErrorf("can not rebase branch %s on to itself", branchName)
+	}
+	for _, name := range []string{branchName, ontoBranchName} {
+		if !gitRepo.IsBranchExist(name) {
+			return git.ErrBranchNotExist{Name: name}
+		}
+	}
+
+	// the rebased branch is force pushed which protected branches do not allow
+	isProtected, err := git_model.IsBranchProtected(ctx, repo.ID, branchName)
+	if err != nil {
+		return err
+	}
+	if isProtected {
+		return git_model.ErrBranchIsProtected
+	}
+
+	return pull_service.RebaseBranch(ctx, doer, repo, branchName, ontoBranchName)
+}
+
+// RestoreBranch restores a branch, even a deleted one, to a previous position recorded in the journal of its updates
+func RestoreBranch(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, branchName, commitID string) error {
+	if err := repo.MustNotBeArchived(); err != nil {
+		return err
+	}
+
+	ok, err := git_model.IsRefUpdateLogCommit(ctx, repo.ID, git.RefNameFromBranch(branchName), commitID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return util.NewInvalidArgumentErrorf("commit %s is not a previous position of branch %s", commitID, branchName)
+	}
+
+	// restoring a branch is likely not a fast-forward which protected branches do not allow
+	isProtected, err := git_model.IsBranchProtected(ctx, repo.ID, branchName)
+	if err != nil {
+		return err
+	}
+	if isProtected {
+		return git_model.ErrBranchIsProtected
+	}
+
+	// the commit may have been garbage collected since
+	if _, err := gitRepo.GetCommit(commitID); err != nil {
+		return err
+	}
+
+	if err := git.Push(ctx, repo.RepoPath(), git.PushOptions{
+		Remote: repo.RepoPath(),
+		Branch: fmt.Sprintf("%s:%s%s", commitID, git.BranchPrefix, branchName),
+		Force:  true,
+		Env:    repo_module.PushingEnvironment(doer, repo),
+	}); err != nil {
+		if git.IsErrPushOutOfDate(err) || git.IsErrPushRejected(err) {
+			return err
+		}
+		return fmt.Errorf("push: %w", err)
+	}
+	return nil
+}
+
 type BranchSyncOptions struct {
 	RepoID int64
 }
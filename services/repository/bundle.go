@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// PregeneratedBundleName is the name of the pregenerated bundle in the directory of a repository,
+// it is offered to the clients of the repository by the bundle-uri capability of upload-pack
+const PregeneratedBundleName = "gitea-clone.bundle"
+
+// PregeneratedBundlePath returns the path of the pregenerated bundle of the repository
+func PregeneratedBundlePath(repo *repo_model.Repository) string {
+	return filepath.Join(repo.RepoPath(), PregeneratedBundleName)
+}
+
+// HasPregeneratedBundle returns whether a bundle has been pregenerated for the repository
+func HasPregeneratedBundle(repo *repo_model.Repository) bool {
+	exist, err := util.IsExist(PregeneratedBundlePath(repo))
+	if err != nil {
+		log.Error("Unable to check the pregenerated bundle of %-v: %v", repo, err)
+	}
+	return exist
+}
+
+// GenerateRepoBundle (re)generates the bundle of the branches and tags of the repository
+func GenerateRepoBundle(ctx context.Context, repo *repo_model.Repository) error {
+	bundlePath := PregeneratedBundlePath(repo)
+	tmpPath := bundlePath + ".tmp"
+	if err := git.CreateRefsBundle(ctx, repo.RepoPath(), tmpPath); err != nil {
+		_ = util.Remove(tmpPath)
+		return fmt.Errorf("CreateRefsBundle: %w", err)
+	}
+	// replace the bundle at once so that it is never read while it is written
+	return os.Rename(tmpPath, bundlePath)
+}
+
+// GenerateRepositoryBundles generates the bundles of the repositories whose git size is at least minSize
+// and removes the bundles of the other repositories
+func GenerateRepositoryBundles(ctx context.Context, minSize int64) error {
+	log.Trace("Doing: GenerateRepositoryBundles")
+
+	if err := db.Iterate(
+		ctx,
+		builder.Gt{"id": 0},
+		func(ctx context.Context, repo *repo_model.Repository) error {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before generating the bundle of %s", repo.FullName())
+			default:
+			}
+			if repo.IsEmpty || repo.GitSize < minSize {
+				if err := util.Remove(PregeneratedBundlePath(repo)); err != nil && !os.IsNotExist(err) {
+					log.Error("Unable to remove the bundle of %-v: %v", repo, err)
+				}
+				return nil
+			}
+			if err := GenerateRepoBundle(ctx, repo); err != nil {
+				log.Error("Unable to generate the bundle of %-v: %v", repo, err)
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: GenerateRepositoryBundles")
+	return nil
+}
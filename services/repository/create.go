@@ -98,7 +98,7 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 		var buf bytes.Buffer
 		names := strings.Split(opts.Gitignores, ",")
 		for _, name := range names {
-			data, err = options.Gitignore(name)
+			data, err = repo_module.ReadOptionTemplate(ctx, repo_model.OptionTemplateGitignore, name)
 			if err != nil {
 				return fmt.Errorf("GetRepoInitFile[%s]: %w", name, err)
 			}
@@ -116,7 +116,7 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 
 	// LICENSE
 	if len(opts.License) > 0 {
-		data, err = repo_module.GetLicense(opts.License, &repo_module.LicenseValues{
+		data, err = repo_module.GetLicense(ctx, opts.License, &repo_module.LicenseValues{
 			Owner: repo.OwnerName,
 			Email: authorSig.Email,
 			Repo:  repo.Name,
@@ -209,13 +209,16 @@ func CreateRepositoryDirectly(ctx context.Context, doer, u *user_model.User, opt
 
 	// Check if label template exist
 	if len(opts.IssueLabels) > 0 {
-		if _, err := repo_module.LoadTemplateLabelsByDisplayName(opts.IssueLabels); err != nil {
+		if _, err := repo_module.LoadTemplateLabelsByDisplayName(ctx, opts.IssueLabels); err != nil {
 			return nil, err
 		}
 	}
 
 	if opts.ObjectFormatName == "" {
-		opts.ObjectFormatName = git.Sha1ObjectFormat.Name()
+		opts.ObjectFormatName = git.DefaultObjectFormatName()
+	}
+	if !git.IsValidObjectFormat(opts.ObjectFormatName) {
+		return nil, util.NewInvalidArgumentErrorf("unsupported object format: %s", opts.ObjectFormatName)
 	}
 
 	repo := &repo_model.Repository{
@@ -13,7 +13,10 @@ import (
 	"code.gitea.io/gitea/models/perm"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -146,3 +149,33 @@ func TestIncludesAllRepositoriesTeams(t *testing.T) {
 	}
 	assert.NoError(t, organization.DeleteOrganization(db.DefaultContext, org), "DeleteOrganization")
 }
+
+func TestCreateRepositoryObjectFormat(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	repo, err := CreateRepositoryDirectly(db.DefaultContext, user, user, CreateRepoOptions{Name: "repo-default-object-format"})
+	assert.NoError(t, err)
+	assert.Equal(t, git.DefaultObjectFormatName(), repo.ObjectFormatName)
+	assert.NoError(t, DeleteRepositoryDirectly(db.DefaultContext, user, repo.ID))
+
+	for _, objectFormat := range git.DefaultFeatures().SupportedObjectFormats {
+		repo, err := CreateRepositoryDirectly(db.DefaultContext, user, user, CreateRepoOptions{
+			Name:             "repo-" + objectFormat.Name(),
+			ObjectFormatName: objectFormat.Name(),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, objectFormat.Name(), repo.ObjectFormatName)
+
+		gitRepo, err := gitrepo.OpenRepository(db.DefaultContext, repo)
+		assert.NoError(t, err)
+		repoObjectFormat, err := gitRepo.GetObjectFormat()
+		assert.NoError(t, err)
+		assert.Equal(t, objectFormat, repoObjectFormat)
+		gitRepo.Close()
+		assert.NoError(t, DeleteRepositoryDirectly(db.DefaultContext, user, repo.ID))
+	}
+
+	_, err = CreateRepositoryDirectly(db.DefaultContext, user, user, CreateRepoOptions{Name: "repo-md5", ObjectFormatName: "md5"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
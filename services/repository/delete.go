@@ -18,15 +18,18 @@ import (
 	"code.gitea.io/gitea/models/organization"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	project_model "code.gitea.io/gitea/models/project"
+	pull_model "code.gitea.io/gitea/models/pull"
 	repo_model "code.gitea.io/gitea/models/repo"
 	secret_model "code.gitea.io/gitea/models/secret"
 	system_model "code.gitea.io/gitea/models/system"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/models/webhook"
 	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/storage"
+	actions_service "code.gitea.io/gitea/services/actions"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 
 	"xorm.io/builder"
@@ -66,6 +69,12 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		return fmt.Errorf("list actions artifacts of repo %v: %w", repoID, err)
 	}
 
+	// Query the caches of this repo, they will be needed after they have been deleted to remove their content in ObjectStorage
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCacheOptions{RepoID: repoID})
+	if err != nil {
+		return fmt.Errorf("list actions caches of repo %v: %w", repoID, err)
+	}
+
 	// In case owner is a organization, we have to change repo specific teams
 	// if ignoreOrgTeams is not true
 	var org *user_model.User
@@ -119,6 +128,9 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 	}
 	releaseAttachments := make([]string, 0, len(attachments))
 	for i := 0; i < len(attachments); i++ {
+		if attachments[i].IsExternal() {
+			continue
+		}
 		releaseAttachments = append(releaseAttachments, attachments[i].RelativePath())
 	}
 
@@ -131,6 +143,11 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		return err
 	}
 
+	if _, err := db.GetEngine(ctx).In("release_id", builder.Select("id").From("`release`").Where(builder.Eq{"`release`.repo_id": repo.ID})).
+		Delete(&repo_model.ReleaseSignature{}); err != nil {
+		return err
+	}
+
 	if err := db.DeleteBeans(ctx,
 		&access_model.Access{RepoID: repo.ID},
 		&activities_model.Action{RepoID: repo.ID},
@@ -140,7 +157,26 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		&git_model.Branch{RepoID: repoID},
 		&git_model.LFSLock{RepoID: repoID},
 		&repo_model.LanguageStat{RepoID: repoID},
+		&repo_model.RepoSizeStat{RepoID: repoID},
+		&repo_model.RepoMaintenance{RepoID: repoID},
+		&repo_model.RepoContributorWeek{RepoID: repoID},
+		&repo_model.RepoPunchCard{RepoID: repoID},
+		&repo_model.RepoBisectSession{RepoID: repoID},
+		&repo_model.DeferredCommit{RepoID: repoID},
+		&repo_model.RepoManifest{RepoID: repoID},
+		&repo_model.RepoDependency{RepoID: repoID},
+		&repo_model.RepoCustomPropertyValue{RepoID: repoID},
+		&git_model.ManagedGitHookOptOut{RepoID: repoID},
+		&git_model.RefUpdateLog{RepoID: repoID},
 		&issues_model.Milestone{RepoID: repoID},
+		&issues_model.IssueType{RepoID: repoID},
+		&issues_model.IssueField{RepoID: repoID},
+		&issues_model.IssueFilter{RepoID: repoID},
+		&issues_model.IssueAutomationRule{RepoID: repoID},
+		&issues_model.IssueAutomationLog{RepoID: repoID},
+		&issues_model.Reaction{RepoID: repoID},
+		&pull_model.MergeQueue{RepoID: repoID},
+		&pull_model.MergeQueueEntry{RepoID: repoID},
 		&repo_model.Mirror{RepoID: repoID},
 		&activities_model.Notification{RepoID: repoID},
 		&git_model.ProtectedBranch{RepoID: repoID},
@@ -163,7 +199,9 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		&actions_model.ActionScheduleSpec{RepoID: repoID},
 		&actions_model.ActionSchedule{RepoID: repoID},
 		&actions_model.ActionArtifact{RepoID: repoID},
+		&actions_model.ActionCache{RepoID: repoID},
 		&actions_model.ActionRunnerToken{RepoID: repoID},
+		&actions_model.ActionRunnerGroupAccess{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %w", err)
 	}
@@ -178,12 +216,26 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		return err
 	}
 
+	// The milestones of the organization keep existing without the issues of the repository
+	var orgMilestoneIDs []int64
+	if err := db.GetEngine(ctx).Table("milestone").Where("org_id > 0").
+		And(builder.In("id", builder.Select("milestone_id").From("issue").Where(builder.Eq{"repo_id": repoID}))).
+		Cols("id").Find(&orgMilestoneIDs); err != nil {
+		return err
+	}
+
 	// Delete Issues and related objects
 	var attachmentPaths []string
 	if attachmentPaths, err = issues_model.DeleteIssuesByRepoID(ctx, repoID); err != nil {
 		return err
 	}
 
+	for _, id := range orgMilestoneIDs {
+		if err := issues_model.UpdateMilestoneCounters(ctx, id); err != nil {
+			return err
+		}
+	}
+
 	// Delete issue index
 	if err := db.DeleteResourceIndex(ctx, "issue_index", repoID); err != nil {
 		return err
@@ -289,10 +341,12 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 
 	// Remove repository files.
 	repoPath := repo.RepoPath()
+	git.CloseCatFileProcesses(repoPath)
 	system_model.RemoveAllWithNotice(ctx, "Delete repository files", repoPath)
 
 	// Remove wiki files
 	if repo.HasWiki() {
+		git.CloseCatFileProcesses(repo.WikiPath())
 		system_model.RemoveAllWithNotice(ctx, "Delete repository wiki", repo.WikiPath())
 	}
 
@@ -344,6 +398,11 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		}
 	}
 
+	// delete actions caches in ObjectStorage after the repo have already been deleted
+	for _, cache := range caches {
+		actions_service.RemoveCacheContent(cache)
+	}
+
 	return nil
 }
 
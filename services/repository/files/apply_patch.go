@@ -0,0 +1,294 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrPatchDoesNotApply represents an error if a patch doesn't apply to a branch
+type ErrPatchDoesNotApply struct {
+	Branch string
+	// Conflicts are the hunks which don't apply
+	Conflicts []*structs.PatchHunkConflict
+	// ConflictedFiles are the files left with conflicts by the three-way merge
+	ConflictedFiles []string
+}
+
+// IsErrPatchDoesNotApply checks if an error is a ErrPatchDoesNotApply.
+func IsErrPatchDoesNotApply(err error) bool {
+	_, ok := err.(ErrPatchDoesNotApply)
+	return ok
+}
+
+func (err ErrPatchDoesNotApply) Error() string {
+	return fmt.Sprintf("patch does not apply to %s: %d conflicting hunks, %d conflicted files", err.Branch, len(err.Conflicts), len(err.ConflictedFiles))
+}
+
+// patchInfo holds the information of a patch generated by git format-patch
+type patchInfo struct {
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	Message     string
+	Diff        string
+}
+
+var formatPatchFromLine = regexp.MustCompile(`(?m)^From [0-9a-f]{40,64} `)
+
+// parseFormatPatch extracts the author, message and diff of a patch generated by git format-patch with git mailinfo,
+// it returns nil if the content is a plain diff.
+func parseFormatPatch(ctx context.Context, tmpDir, content string) (*patchInfo, error) {
+	switch len(formatPatchFromLine.FindAllStringIndex(content, 2)) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, util.NewInvalidArgumentErrorf("the patch contains several commits, they must be applied one by one")
+	}
+
+	msgFile, patchFile := filepath.Join(tmpDir, "patch-message"), filepath.Join(tmpDir, "patch-diff")
+	stdout, _, runErr := git.NewCommand(ctx, "mailinfo").AddDynamicArguments(msgFile, patchFile).
+		RunStdString(&git.RunOpts{Dir: tmpDir, Stdin: strings.NewReader(content)})
+	if runErr != nil {
+		return nil, util.NewInvalidArgumentErrorf("unable to read the patch: %v", runErr)
+	}
+	defer func() {
+		_ = util.Remove(msgFile)
+		_ = util.Remove(patchFile)
+	}()
+
+	info := &patchInfo{}
+	var subject string
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), ": ")
+		switch key {
+		case "Author":
+			info.AuthorName = value
+		case "Email":
+			info.AuthorEmail = value
+		case "Subject":
+			subject = value
+		case "Date":
+			if date, err := mail.ParseDate(value); err == nil {
+				info.AuthorDate = date
+			}
+		}
+	}
+
+	body, err := os.ReadFile(msgFile)
+	if err != nil {
+		return nil, err
+	}
+	info.Message = strings.TrimSpace(subject + "\n\n" + strings.TrimSpace(string(body)))
+	diff, err := os.ReadFile(patchFile)
+	if err != nil {
+		return nil, err
+	}
+	info.Diff = string(diff)
+	return info, nil
+}
+
+// patchHunk is a hunk of a diff with the header of its file
+type patchHunk struct {
+	path       string
+	fileHeader string
+	header     string
+	lines      string
+	oldStart   int
+	oldLines   int
+	newStart   int
+	newLines   int
+}
+
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+func atoiOr(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+// splitPatchHunks splits a diff into its hunks, a file without hunks, e.g. a binary or a mode change, is a single hunk without header
+func splitPatchHunks(diff string) []*patchHunk {
+	var (
+		hunks                []*patchHunk
+		fileHeader           strings.Builder
+		path                 string
+		hunk                 *patchHunk
+		hunkLines            strings.Builder
+		oldLeft, newLeft     int
+		fileHasHunks, inFile bool
+	)
+	flushHunk := func() {
+		if hunk != nil {
+			hunk.lines = hunkLines.String()
+			hunks = append(hunks, hunk)
+			hunk = nil
+		}
+	}
+	flushFile := func() {
+		flushHunk()
+		if inFile && !fileHasHunks {
+			hunks = append(hunks, &patchHunk{path: path, fileHeader: fileHeader.String()})
+		}
+		fileHeader.Reset()
+		path, fileHasHunks, inFile = "", false, false
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			hunkLines.WriteString(line + "\n")
+			switch {
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, "+"):
+				newLeft--
+			case strings.HasPrefix(line, "\\"):
+			default:
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+		if hunk != nil && strings.HasPrefix(line, "\\") {
+			// "\ No newline at end of file" after the last line of the hunk
+			hunkLines.WriteString(line + "\n")
+			continue
+		}
+
+		if m := hunkHeaderRegexp.FindStringSubmatch(line); m != nil && inFile {
+			flushHunk()
+			fileHasHunks = true
+			hunk = &patchHunk{
+				path:       path,
+				fileHeader: fileHeader.String(),
+				header:     line,
+				oldStart:   atoiOr(m[1], 0),
+				oldLines:   atoiOr(m[2], 1),
+				newStart:   atoiOr(m[3], 0),
+				newLines:   atoiOr(m[4], 1),
+			}
+			oldLeft, newLeft = hunk.oldLines, hunk.newLines
+			hunkLines.Reset()
+			hunkLines.WriteString(line + "\n")
+			continue
+		}
+
+		if line == "-- " {
+			// the signature of git format-patch
+			flushFile()
+			continue
+		}
+		if strings.HasPrefix(line, "diff --git ") || (strings.HasPrefix(line, "--- ") && (hunk != nil || !inFile)) {
+			flushFile()
+			inFile = true
+		} else if !inFile {
+			continue
+		} else if hunk != nil {
+			// the content following the last hunk of the file, e.g. the signature of git format-patch
+			continue
+		}
+		fileHeader.WriteString(line + "\n")
+		switch {
+		case strings.HasPrefix(line, "+++ ") && !strings.HasSuffix(line, "/dev/null"):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "--- ") && path == "" && !strings.HasSuffix(line, "/dev/null"):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "diff --git ") && path == "":
+			if _, b, ok := strings.Cut(line, " b/"); ok {
+				path = b
+			}
+		}
+	}
+	flushFile()
+	return hunks
+}
+
+func newGitApplyCommand(ctx context.Context) *git.Command {
+	return git.NewCommand(ctx, "apply", "--cached", "--recount", "--ignore-whitespace", "--whitespace=fix", "--binary")
+}
+
+// findPatchConflicts checks which hunks of the diff don't apply to the index
+func findPatchConflicts(ctx context.Context, t *TemporaryUploadRepository, diff string) []*structs.PatchHunkConflict {
+	conflicts := make([]*structs.PatchHunkConflict, 0, 5)
+	for _, hunk := range splitPatchHunks(diff) {
+		stderr := new(strings.Builder)
+		if err := newGitApplyCommand(ctx).AddArguments("--check").Run(&git.RunOpts{
+			Dir:    t.basePath,
+			Stderr: stderr,
+			Stdin:  strings.NewReader(hunk.fileHeader + hunk.lines),
+		}); err == nil {
+			continue
+		}
+		conflicts = append(conflicts, &structs.PatchHunkConflict{
+			Path:     hunk.path,
+			Hunk:     hunk.header,
+			OldStart: hunk.oldStart,
+			OldLines: hunk.oldLines,
+			NewStart: hunk.newStart,
+			NewLines: hunk.newLines,
+			Message:  strings.TrimSpace(stderr.String()),
+		})
+	}
+	return conflicts
+}
+
+// applyPatchToIndex applies the diff to the index of the temporary repository, with a three-way merge if it doesn't apply cleanly
+func applyPatchToIndex(ctx context.Context, t *TemporaryUploadRepository, branch, diff string, threeWay bool) error {
+	stderr := new(strings.Builder)
+	err := newGitApplyCommand(ctx).Run(&git.RunOpts{Dir: t.basePath, Stderr: stderr, Stdin: strings.NewReader(diff)})
+	if err == nil {
+		return nil
+	}
+	log.Debug("Patch does not apply cleanly to %s in %s: %s", branch, t.repo.FullName(), stderr.String())
+
+	var conflictedFiles []string
+	if threeWay && git.DefaultFeatures().CheckVersionAtLeast("2.32") {
+		if err = newGitApplyCommand(ctx).AddArguments("--3way").Run(&git.RunOpts{Dir: t.basePath, Stdin: strings.NewReader(diff)}); err == nil {
+			return nil
+		}
+		stdout, _, lsErr := git.NewCommand(ctx, "ls-files", "--unmerged", "-z").RunStdString(&git.RunOpts{Dir: t.basePath})
+		if lsErr != nil {
+			return lsErr
+		}
+		// each entry is "<mode> <object> <stage>\t<path>", with an entry per stage of a path
+		for _, entry := range strings.Split(stdout, "\x00") {
+			_, path, ok := strings.Cut(entry, "\t")
+			if ok && (len(conflictedFiles) == 0 || conflictedFiles[len(conflictedFiles)-1] != path) {
+				conflictedFiles = append(conflictedFiles, path)
+			}
+		}
+		// the hunks are checked against the index of the branch
+		if err := t.SetDefaultIndex(); err != nil {
+			return err
+		}
+	}
+
+	return ErrPatchDoesNotApply{
+		Branch:          branch,
+		Conflicts:       findPatchConflicts(ctx, t, diff),
+		ConflictedFiles: conflictedFiles,
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitPatchHunks(t *testing.T) {
+	diff := `diff --git a/README.md b/README.md
+index 4b4851a..f3dc1b5 100644
+--- a/README.md
++++ b/README.md
+@@ -1,2 +1,2 @@
+ # repo1
+-Description for repo1
++Updated description
+@@ -10,3 +10,4 @@ section
+ a
++b
+ c
+-d
+\ No newline at end of file
++d
+diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..ce01362
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+diff --git a/script.sh b/script.sh
+old mode 100644
+new mode 100755
+` + "-- \n2.39.5\n"
+	hunks := splitPatchHunks(diff)
+	if assert.Len(t, hunks, 4) {
+		assert.Equal(t, "README.md", hunks[0].path)
+		assert.Equal(t, "@@ -1,2 +1,2 @@", hunks[0].header)
+		assert.Equal(t, 1, hunks[0].oldStart)
+		assert.Equal(t, 2, hunks[0].oldLines)
+		assert.Equal(t, "diff --git a/README.md b/README.md\nindex 4b4851a..f3dc1b5 100644\n--- a/README.md\n+++ b/README.md\n", hunks[0].fileHeader)
+		assert.Equal(t, "@@ -1,2 +1,2 @@\n # repo1\n-Description for repo1\n+Updated description\n", hunks[0].lines)
+
+		assert.Equal(t, "README.md", hunks[1].path)
+		assert.Equal(t, 10, hunks[1].newStart)
+		assert.Equal(t, 4, hunks[1].newLines)
+		assert.Equal(t, hunks[0].fileHeader, hunks[1].fileHeader)
+		assert.Contains(t, hunks[1].lines, "\\ No newline at end of file\n+d\n")
+
+		assert.Equal(t, "new.txt", hunks[2].path)
+		assert.Equal(t, 0, hunks[2].oldLines)
+		assert.Equal(t, 1, hunks[2].newLines)
+
+		assert.Equal(t, "script.sh", hunks[3].path)
+		assert.Empty(t, hunks[3].header)
+		assert.Equal(t, "diff --git a/script.sh b/script.sh\nold mode 100644\nnew mode 100755\n", hunks[3].fileHeader)
+	}
+
+	// a diff without git headers
+	hunks = splitPatchHunks("--- a/file\n+++ b/file\n@@ -1 +1 @@\n-a\n+b\n")
+	if assert.Len(t, hunks, 1) {
+		assert.Equal(t, "file", hunks[0].path)
+		assert.Equal(t, "--- a/file\n+++ b/file\n", hunks[0].fileHeader)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetBlameOptions are the options of the blame of a file
+type GetBlameOptions struct {
+	// BypassBlameIgnore disables the use of .git-blame-ignore-revs
+	BypassBlameIgnore bool
+	// IncludeLines adds the content of the lines to the hunks
+	IncludeLines bool
+}
+
+// GetBlame returns the blame of the file at treePath of the commit
+func GetBlame(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, commit *git.Commit, treePath string, opts GetBlameOptions) (*api.BlameResponse, error) {
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsDir() || entry.IsSubModule() {
+		return nil, models.ErrFilePathInvalid{
+			Message: fmt.Sprintf("%s is not a file", treePath),
+			Path:    treePath,
+			Name:    treePath,
+			Type:    entry.Mode(),
+		}
+	}
+
+	blame := &api.BlameResponse{
+		Path: treePath,
+		SHA:  commit.ID.String(),
+	}
+	hunks, usesIgnoreRevs, err := readBlameHunks(ctx, repo, gitRepo, commit, treePath, opts.BypassBlameIgnore)
+	if err != nil {
+		if len(hunks) > 0 || !usesIgnoreRevs {
+			return nil, err
+		}
+		// the .git-blame-ignore-revs file is probably faulty, try again without it
+		blame.FaultyIgnoreRevsFile = true
+		if hunks, usesIgnoreRevs, err = readBlameHunks(ctx, repo, gitRepo, commit, treePath, true); err != nil {
+			return nil, err
+		}
+	}
+	blame.UsesIgnoreRevs = usesIgnoreRevs
+
+	commits := make(map[string]*git.Commit)
+	commits[commit.ID.String()] = commit
+	blame.Hunks = make([]*api.BlameHunk, 0, len(hunks))
+	for _, hunk := range hunks {
+		hunkCommit, ok := commits[hunk.Sha]
+		if !ok {
+			if hunkCommit, err = gitRepo.GetCommit(hunk.Sha); err != nil {
+				return nil, err
+			}
+			commits[hunk.Sha] = hunkCommit
+		}
+		blame.Hunks = append(blame.Hunks, toAPIBlameHunk(repo, hunkCommit, hunk, opts.IncludeLines))
+	}
+	return blame, nil
+}
+
+func readBlameHunks(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, commit *git.Commit, treePath string, bypassBlameIgnore bool) ([]*gitrepo.BlameHunk, bool, error) {
+	reader, err := gitrepo.CreateBlameReader(ctx, repo, git.ObjectFormatFromName(repo.ObjectFormatName), commit, treePath, bypassBlameIgnore)
+	if err != nil {
+		return nil, false, err
+	}
+	usesIgnoreRevs := reader.UsesIgnoreRevs()
+
+	hunks := make([]*gitrepo.BlameHunk, 0, 5)
+	for {
+		hunk, err := reader.NextHunk()
+		if err != nil {
+			_ = reader.Close()
+			return nil, usesIgnoreRevs, fmt.Errorf("BlameReader.NextHunk failed: %w", err)
+		}
+		if hunk == nil {
+			break
+		}
+		hunks = append(hunks, hunk)
+	}
+	return hunks, usesIgnoreRevs, reader.Close()
+}
+
+func toAPIBlameHunk(repo *repo_model.Repository, commit *git.Commit, hunk *gitrepo.BlameHunk, includeLines bool) *api.BlameHunk {
+	apiHunk := &api.BlameHunk{
+		StartLine: hunk.StartLine,
+		EndLine:   hunk.EndLine,
+		Commit: &api.CommitMeta{
+			URL:     repo.APIURL() + "/git/commits/" + url.PathEscape(hunk.Sha),
+			SHA:     hunk.Sha,
+			Created: commit.Committer.When,
+		},
+		Author: &api.CommitUser{
+			Identity: api.Identity{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+			},
+			Date: commit.Author.When.UTC().Format(time.RFC3339),
+		},
+		Committer: &api.CommitUser{
+			Identity: api.Identity{
+				Name:  commit.Committer.Name,
+				Email: commit.Committer.Email,
+			},
+			Date: commit.Committer.When.UTC().Format(time.RFC3339),
+		},
+		Message: commit.Message(),
+	}
+	if hunk.PreviousSha != "" {
+		apiHunk.Previous = &api.BlamePrevious{
+			SHA:  hunk.PreviousSha,
+			Path: hunk.PreviousPath,
+		}
+	}
+	if includeLines {
+		apiHunk.Lines = hunk.Lines
+	}
+	return apiHunk
+}
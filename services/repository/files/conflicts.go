@@ -0,0 +1,237 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/pull"
+)
+
+// ErrUserCannotResolveConflicts represents an error that the user isn't allowed to update the head branch of the pull request
+var ErrUserCannotResolveConflicts = util.NewPermissionDeniedErrorf("user is not allowed to update the head branch of the pull request")
+
+// PullRequestConflicts are the files conflicting when merging the base branch of a pull request into its head branch
+type PullRequestConflicts struct {
+	HeadCommitID string
+	BaseCommitID string
+	Files        []*pull.ConflictedFile
+}
+
+// Resolvable returns whether all the conflicts can be resolved by editing the conflicted files
+func (conflicts *PullRequestConflicts) Resolvable() bool {
+	for _, file := range conflicts.Files {
+		if !file.Resolvable {
+			return false
+		}
+	}
+	return len(conflicts.Files) > 0
+}
+
+// ResolvePullRequestConflictsOptions holds the options to resolve the conflicts of a pull request
+type ResolvePullRequestConflictsOptions struct {
+	// HeadCommitID and BaseCommitID are the commits the conflicts have been resolved for,
+	// the current ones are used if they are empty
+	HeadCommitID string
+	BaseCommitID string
+	// Files are the resolved contents of the conflicted files by path
+	Files map[string]string
+	// Message is the message of the merge commit, a default one is used if it is empty
+	Message string
+}
+
+// mergeBaseIntoHead merges the base branch of the pull request into its head branch in the index of a temporary repository
+// of the head repository, the conflicted files are left unmerged
+func mergeBaseIntoHead(ctx context.Context, pr *issues_model.PullRequest) (*TemporaryUploadRepository, *PullRequestConflicts, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, nil, err
+	}
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return nil, nil, err
+	} else if pr.HeadRepo == nil {
+		return nil, nil, util.NewInvalidArgumentErrorf("the head repository of the pull request has been deleted")
+	}
+
+	t, err := NewTemporaryUploadRepository(ctx, pr.HeadRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+	conflicts, err := func() (*PullRequestConflicts, error) {
+		if err := t.Clone(pr.HeadBranch, false); err != nil {
+			return nil, err
+		}
+		if err := t.SetDefaultIndex(); err != nil {
+			return nil, err
+		}
+		if err := t.RefreshIndex(); err != nil {
+			return nil, err
+		}
+		headCommit, err := t.GetBranchCommit(pr.HeadBranch)
+		if err != nil {
+			return nil, err
+		}
+
+		// the base branch is fetched, it's in another repository if the pull request comes from a fork
+		if _, _, err := git.NewCommand(ctx, "fetch", "--no-tags").AddDashesAndList(pr.BaseRepo.RepoPath(), git.BranchPrefix+pr.BaseBranch).
+			RunStdString(&git.RunOpts{Dir: t.basePath}); err != nil {
+			return nil, fmt.Errorf("unable to fetch the base branch %s: %w", pr.BaseBranch, err)
+		}
+		baseCommitID, err := t.GetLastCommitByRef("FETCH_HEAD")
+		if err != nil {
+			return nil, err
+		}
+		mergeBase, _, err := git.NewCommand(ctx, "merge-base").AddDashesAndList(headCommit.ID.String(), baseCommitID).
+			RunStdString(&git.RunOpts{Dir: t.basePath})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the merge base of %s and %s: %w", pr.HeadBranch, pr.BaseBranch, err)
+		}
+
+		description := fmt.Sprintf("Merge %s into %s", pr.BaseBranch, pr.HeadBranch)
+		conflict, _, err := pull.AttemptThreeWayMerge(ctx,
+			t.basePath, t.gitRepo, strings.TrimSpace(mergeBase), headCommit.ID.String(), baseCommitID, description)
+		if err != nil {
+			return nil, fmt.Errorf("failed to three-way merge %s into %s: %w", pr.BaseBranch, pr.HeadBranch, err)
+		}
+
+		conflicts := &PullRequestConflicts{HeadCommitID: headCommit.ID.String(), BaseCommitID: baseCommitID}
+		if conflict {
+			conflicts.Files, err = pull.GetConflictedFiles(ctx, t.basePath, pr.HeadBranch, pr.BaseBranch)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return conflicts, nil
+	}()
+	if err != nil {
+		t.Close()
+		return nil, nil, err
+	}
+	return t, conflicts, nil
+}
+
+// GetPullRequestConflicts returns the files conflicting when merging the base branch of the pull request into its head branch,
+// with conflict markers in their contents
+func GetPullRequestConflicts(ctx context.Context, pr *issues_model.PullRequest) (*PullRequestConflicts, error) {
+	t, conflicts, err := mergeBaseIntoHead(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+	t.Close()
+	return conflicts, nil
+}
+
+// ResolvePullRequestConflicts merges the base branch of the pull request into its head branch with the given contents
+// for the conflicted files, all the conflicts must be resolved
+func ResolvePullRequestConflicts(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, opts *ResolvePullRequestConflictsOptions) (*structs.FileResponse, error) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return nil, util.NewInvalidArgumentErrorf("the pull request is closed")
+	}
+	if pr.Flow != issues_model.PullRequestFlowGithub {
+		return nil, util.NewInvalidArgumentErrorf("the conflicts of an AGit pull request can't be resolved")
+	}
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return nil, err
+	} else if pr.HeadRepo == nil {
+		return nil, util.NewInvalidArgumentErrorf("the head repository of the pull request has been deleted")
+	}
+	if allowed, _, err := pull.IsUserAllowedToUpdate(ctx, pr, doer); err != nil {
+		return nil, err
+	} else if !allowed {
+		return nil, ErrUserCannotResolveConflicts
+	}
+
+	t, conflicts, err := mergeBaseIntoHead(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+
+	if opts.HeadCommitID != "" && opts.HeadCommitID != conflicts.HeadCommitID {
+		return nil, models.ErrCommitIDDoesNotMatch{GivenCommitID: opts.HeadCommitID, CurrentCommitID: conflicts.HeadCommitID}
+	}
+	if opts.BaseCommitID != "" && opts.BaseCommitID != conflicts.BaseCommitID {
+		return nil, models.ErrCommitIDDoesNotMatch{GivenCommitID: opts.BaseCommitID, CurrentCommitID: conflicts.BaseCommitID}
+	}
+	if len(conflicts.Files) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("the pull request has no conflicts")
+	}
+
+	for path := range opts.Files {
+		if !slices.ContainsFunc(conflicts.Files, func(file *pull.ConflictedFile) bool { return file.Path == path }) {
+			return nil, util.NewInvalidArgumentErrorf("%s is not conflicted", path)
+		}
+	}
+	for _, file := range conflicts.Files {
+		if !file.Resolvable {
+			return nil, util.NewInvalidArgumentErrorf("the conflict of %s can't be resolved by editing the file", file.Path)
+		}
+		content, ok := opts.Files[file.Path]
+		if !ok {
+			return nil, util.NewInvalidArgumentErrorf("the conflict of %s isn't resolved", file.Path)
+		}
+		if hasConflictMarkers(content) {
+			return nil, util.NewInvalidArgumentErrorf("%s still has conflict markers", file.Path)
+		}
+
+		objectHash, err := t.HashObject(strings.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		if err := t.RemoveFilesFromIndex(file.Path); err != nil {
+			return nil, err
+		}
+		if err := t.AddObjectToIndex(file.Mode, objectHash, file.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return nil, err
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	if message == "" {
+		message = fmt.Sprintf("Merge branch '%s' into %s", pr.BaseBranch, pr.HeadBranch)
+	}
+	commitHash, err := t.CommitMergeTree([]string{conflicts.HeadCommitID, conflicts.BaseCommitID}, doer, doer, treeHash, message)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Push(doer, commitHash, pr.HeadBranch); err != nil {
+		return nil, err
+	}
+
+	commit, err := t.GetCommit(commitHash)
+	if err != nil {
+		return nil, err
+	}
+	fileCommitResponse, _ := GetFileCommitResponse(pr.HeadRepo, commit) // ok if fails, then will be nil
+	return &structs.FileResponse{
+		Commit:       fileCommitResponse,
+		Verification: GetPayloadCommitVerification(ctx, commit),
+	}, nil
+}
+
+// hasConflictMarkers returns whether the content still has the markers of a conflict
+func hasConflictMarkers(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
@@ -40,15 +40,12 @@ func (ct *ContentType) String() string {
 }
 
 // GetContentsOrList gets the meta data of a file's contents (*ContentsResponse) if treePath not a tree
-// directory, otherwise a listing of file contents ([]*ContentsResponse). Ref can be a branch, commit or tag
-func GetContentsOrList(ctx context.Context, repo *repo_model.Repository, treePath, ref string) (any, error) {
+// directory, otherwise a listing of file contents ([]*ContentsResponse). Ref can be a branch, commit or tag.
+// The last commits of the listed entries are looked up together, or not at all if withLastCommit is false.
+func GetContentsOrList(ctx context.Context, repo *repo_model.Repository, treePath, ref string, withLastCommit bool) (any, error) {
 	if repo.IsEmpty {
 		return make([]any, 0), nil
 	}
-	if ref == "" {
-		ref = repo.DefaultBranch
-	}
-	origRef := ref
 
 	// Check that the path given in opts.treePath is valid (not a git path)
 	cleanTreePath := CleanUploadFileName(treePath)
@@ -65,25 +62,30 @@ func GetContentsOrList(ctx context.Context, repo *repo_model.Repository, treePat
 	}
 	defer closer.Close()
 
-	// Get the commit object for the ref
-	commit, err := gitRepo.GetCommit(ref)
+	r, err := newContentsRef(repo, gitRepo, ref)
 	if err != nil {
 		return nil, err
 	}
 
-	entry, err := commit.GetTreeEntryByPath(treePath)
+	entry, err := r.commit.GetTreeEntryByPath(treePath)
 	if err != nil {
 		return nil, err
 	}
 
 	if entry.Type() != "tree" {
-		return GetContents(ctx, repo, treePath, origRef, false)
+		var lastCommit *git.Commit
+		if withLastCommit {
+			if lastCommit, err = r.getLastCommit(treePath); err != nil {
+				return nil, err
+			}
+		}
+		return r.toContentsResponse(ctx, treePath, entry, false, lastCommit)
 	}
 
 	// We are in a directory, so we return a list of FileContentResponse objects
 	var fileList []*api.ContentsResponse
 
-	gitTree, err := commit.SubTree(treePath)
+	gitTree, err := r.commit.SubTree(treePath)
 	if err != nil {
 		return nil, err
 	}
@@ -91,9 +93,25 @@ func GetContentsOrList(ctx context.Context, repo *repo_model.Repository, treePat
 	if err != nil {
 		return nil, err
 	}
+
+	// the last commits of all the entries are found by a single walk of the history
+	lastCommits := make(map[string]*git.Commit, len(entries))
+	if withLastCommit {
+		if err := r.addLastCommitCache(); err != nil {
+			return nil, err
+		}
+		commitsInfo, _, err := entries.GetCommitsInfo(ctx, r.commit, treePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range commitsInfo {
+			lastCommits[info.Entry.Name()] = info.Commit
+		}
+	}
+
 	for _, e := range entries {
 		subTreePath := path.Join(treePath, e.Name())
-		fileContentResponse, err := GetContents(ctx, repo, subTreePath, origRef, true)
+		fileContentResponse, err := r.toContentsResponse(ctx, subTreePath, e, true, lastCommits[e.Name()])
 		if err != nil {
 			return nil, err
 		}
@@ -120,11 +138,6 @@ func GetObjectTypeFromTreeEntry(entry *git.TreeEntry) ContentType {
 
 // GetContents gets the meta data on a file's contents. Ref can be a branch, commit or tag
 func GetContents(ctx context.Context, repo *repo_model.Repository, treePath, ref string, forList bool) (*api.ContentsResponse, error) {
-	if ref == "" {
-		ref = repo.DefaultBranch
-	}
-	origRef := ref
-
 	// Check that the path given in opts.treePath is valid (not a git path)
 	cleanTreePath := CleanUploadFileName(treePath)
 	if cleanTreePath == "" && treePath != "" {
@@ -140,6 +153,40 @@ func GetContents(ctx context.Context, repo *repo_model.Repository, treePath, ref
 	}
 	defer closer.Close()
 
+	r, err := newContentsRef(repo, gitRepo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := r.commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lastCommit, err := r.getLastCommit(treePath)
+	if err != nil {
+		return nil, err
+	}
+	return r.toContentsResponse(ctx, treePath, entry, forList, lastCommit)
+}
+
+// contentsRef is the commit of a ref whose contents are returned
+type contentsRef struct {
+	repo    *repo_model.Repository
+	gitRepo *git.Repository
+	commit  *git.Commit
+	// ref is the ref of the links, an abbreviated commit ID is expanded
+	ref     string
+	origRef string
+	refType git.ObjectType
+}
+
+func newContentsRef(repo *repo_model.Repository, gitRepo *git.Repository, ref string) (*contentsRef, error) {
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	origRef := ref
+
 	// Get the commit object for the ref
 	commit, err := gitRepo.GetCommit(ref)
 	if err != nil {
@@ -150,52 +197,60 @@ func GetContents(ctx context.Context, repo *repo_model.Repository, treePath, ref
 		ref = commit.ID.String()
 	}
 
-	entry, err := commit.GetTreeEntryByPath(treePath)
-	if err != nil {
-		return nil, err
-	}
-
 	refType := gitRepo.GetRefType(ref)
 	if refType == "invalid" {
 		return nil, fmt.Errorf("no commit found for the ref [ref: %s]", ref)
 	}
+	return &contentsRef{repo: repo, gitRepo: gitRepo, commit: commit, ref: ref, origRef: origRef, refType: refType}, nil
+}
 
-	selfURL, err := url.Parse(repo.APIURL() + "/contents/" + util.PathEscapeSegments(treePath) + "?ref=" + url.QueryEscape(origRef))
-	if err != nil {
-		return nil, err
-	}
-	selfURLString := selfURL.String()
+// addLastCommitCache enables the cache of the last commits of the paths in the commit
+func (r *contentsRef) addLastCommitCache() error {
+	return r.gitRepo.AddLastCommitCache(r.repo.GetCommitsCountCacheKey(r.ref, r.refType != git.ObjectCommit), r.repo.FullName(), r.commit.ID.String())
+}
 
-	err = gitRepo.AddLastCommitCache(repo.GetCommitsCountCacheKey(ref, refType != git.ObjectCommit), repo.FullName(), commitID)
-	if err != nil {
+// getLastCommit returns the last commit which changed the path
+func (r *contentsRef) getLastCommit(treePath string) (*git.Commit, error) {
+	if err := r.addLastCommitCache(); err != nil {
 		return nil, err
 	}
+	return r.commit.GetCommitByPath(treePath)
+}
+
+// toContentsResponse returns the contents of the entry, its last commit is omitted if it is nil
+func (r *contentsRef) toContentsResponse(ctx context.Context, treePath string, entry *git.TreeEntry, forList bool, lastCommit *git.Commit) (*api.ContentsResponse, error) {
+	repo, ref, refType := r.repo, r.ref, r.refType
 
-	lastCommit, err := commit.GetCommitByPath(treePath)
+	selfURL, err := url.Parse(repo.APIURL() + "/contents/" + util.PathEscapeSegments(treePath) + "?ref=" + url.QueryEscape(r.origRef))
 	if err != nil {
 		return nil, err
 	}
+	selfURLString := selfURL.String()
 
 	// All content types have these fields in populated
 	contentsResponse := &api.ContentsResponse{
-		Name:          entry.Name(),
-		Path:          treePath,
-		SHA:           entry.ID.String(),
-		LastCommitSHA: lastCommit.ID.String(),
-		Size:          entry.Size(),
-		URL:           &selfURLString,
+		Name: entry.Name(),
+		Path: treePath,
+		SHA:  entry.ID.String(),
+		Size: entry.Size(),
+		URL:  &selfURLString,
 		Links: &api.FileLinksResponse{
 			Self: &selfURLString,
 		},
 	}
+	if lastCommit != nil {
+		contentsResponse.LastCommitSHA = lastCommit.ID.String()
+	}
 
 	// Now populate the rest of the ContentsResponse based on entry type
 	if entry.IsRegular() || entry.IsExecutable() {
 		contentsResponse.Type = string(ContentTypeRegular)
-		if blobResponse, err := GetBlobBySHA(ctx, repo, gitRepo, entry.ID.String()); err != nil {
-			return nil, err
-		} else if !forList {
-			// We don't show the content if we are getting a list of FileContentResponses
+		// We don't show the content if we are getting a list of FileContentResponses
+		if !forList {
+			blobResponse, err := GetBlobBySHA(ctx, repo, r.gitRepo, entry.ID.String())
+			if err != nil {
+				return nil, err
+			}
 			contentsResponse.Encoding = &blobResponse.Encoding
 			contentsResponse.Content = &blobResponse.Content
 		}
@@ -211,7 +266,7 @@ func GetContents(ctx context.Context, repo *repo_model.Repository, treePath, ref
 		contentsResponse.Target = &targetFromContent
 	} else if entry.IsSubModule() {
 		contentsResponse.Type = string(ContentTypeSubmodule)
-		submodule, err := commit.GetSubModule(treePath)
+		submodule, err := r.commit.GetSubModule(treePath)
 		if err != nil {
 			return nil, err
 		}
@@ -101,16 +101,24 @@ func TestGetContentsOrListForDir(t *testing.T) {
 	}
 
 	t.Run("Get root dir contents with GetContentsOrList(ctx, )", func(t *testing.T) {
-		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, ref)
+		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, ref, true)
 		assert.EqualValues(t, expectedContentsListResponse, fileContentResponse)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Get root dir contents with ref as empty string (should then use the repo's default branch) with GetContentsOrList(ctx, )", func(t *testing.T) {
-		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, "")
+		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, "", true)
 		assert.EqualValues(t, expectedContentsListResponse, fileContentResponse)
 		assert.NoError(t, err)
 	})
+
+	t.Run("Get root dir contents without the last commits with GetContentsOrList(ctx, )", func(t *testing.T) {
+		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, ref, false)
+		assert.NoError(t, err)
+		withoutLastCommit := *readmeContentsResponse
+		withoutLastCommit.LastCommitSHA = ""
+		assert.EqualValues(t, []*api.ContentsResponse{&withoutLastCommit}, fileContentResponse)
+	})
 }
 
 func TestGetContentsOrListForFile(t *testing.T) {
@@ -129,13 +137,13 @@ func TestGetContentsOrListForFile(t *testing.T) {
 	expectedContentsResponse := getExpectedReadmeContentsResponse()
 
 	t.Run("Get README.md contents with GetContentsOrList(ctx, )", func(t *testing.T) {
-		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, ref)
+		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, ref, true)
 		assert.EqualValues(t, expectedContentsResponse, fileContentResponse)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Get README.md contents with ref as empty string (should then use the repo's default branch) with GetContentsOrList(ctx, )", func(t *testing.T) {
-		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, "")
+		fileContentResponse, err := GetContentsOrList(ctx, ctx.Repo.Repository, treePath, "", true)
 		assert.EqualValues(t, expectedContentsResponse, fileContentResponse)
 		assert.NoError(t, err)
 	})
@@ -188,7 +196,7 @@ func TestGetContentsOrListErrors(t *testing.T) {
 
 	t.Run("bad treePath", func(t *testing.T) {
 		badTreePath := "bad/tree.md"
-		fileContentResponse, err := GetContentsOrList(ctx, repo, badTreePath, ref)
+		fileContentResponse, err := GetContentsOrList(ctx, repo, badTreePath, ref, true)
 		assert.Error(t, err)
 		assert.EqualError(t, err, "object does not exist [id: , rel_path: bad]")
 		assert.Nil(t, fileContentResponse)
@@ -196,7 +204,7 @@ func TestGetContentsOrListErrors(t *testing.T) {
 
 	t.Run("bad ref", func(t *testing.T) {
 		badRef := "bad_ref"
-		fileContentResponse, err := GetContentsOrList(ctx, repo, treePath, badRef)
+		fileContentResponse, err := GetContentsOrList(ctx, repo, treePath, badRef, true)
 		assert.Error(t, err)
 		assert.EqualError(t, err, "object does not exist [id: "+badRef+", rel_path: ]")
 		assert.Nil(t, fileContentResponse)
@@ -215,7 +223,7 @@ func TestGetContentsOrListOfEmptyRepos(t *testing.T) {
 	repo := ctx.Repo.Repository
 
 	t.Run("empty repo", func(t *testing.T) {
-		contents, err := GetContentsOrList(ctx, repo, "", "")
+		contents, err := GetContentsOrList(ctx, repo, "", "", true)
 		assert.NoError(t, err)
 		assert.Empty(t, contents)
 	})
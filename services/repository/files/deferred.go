@@ -0,0 +1,271 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+var deferredCommitQueue *queue.WorkerPoolQueue[int64]
+
+// InitDeferredCommits starts the queue which executes the deferred commits
+func InitDeferredCommits() error {
+	deferredCommitQueue = queue.CreateUniqueQueue(graceful.GetManager().ShutdownContext(), "deferred_commit", handleDeferredCommits)
+	if deferredCommitQueue == nil {
+		return fmt.Errorf("unable to create deferred_commit queue")
+	}
+	go graceful.GetManager().RunWithCancel(deferredCommitQueue)
+	return nil
+}
+
+func handleDeferredCommits(items ...int64) []int64 {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, id := range items {
+		if err := executeDeferredCommit(ctx, id); err != nil {
+			log.Error("executeDeferredCommit[%d]: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// deferredChangeRepoFile is a ChangeRepoFile stored with its content until the deferred commit is executed
+type deferredChangeRepoFile struct {
+	Operation    string
+	TreePath     string
+	FromTreePath string
+	Content      []byte
+	SHA          string
+	IsSymlink    bool
+	Mode         string
+	Encoding     string
+	LineEndings  string
+}
+
+// deferredChangeRepoFilesOptions are the ChangeRepoFilesOptions stored until the deferred commit is executed
+type deferredChangeRepoFilesOptions struct {
+	LastCommitID       string
+	OldBranch          string
+	NewBranch          string
+	Message            string
+	Files              []*deferredChangeRepoFile
+	Author             *IdentityOptions
+	Committer          *IdentityOptions
+	Dates              *CommitDateOptions
+	Signoff            bool
+	Trailers           []git.CommitTrailer
+	ParentCommitID     string
+	TargetRef          string
+	CherryPickCommitID string
+	ApplyTemplateVars  bool
+	SkipWebhooks       bool
+	SkipCI             bool
+	Amend              bool
+}
+
+// DeferChangeRepoFiles stores the change of the files to be committed by ChangeRepoFiles at the given time.
+// The dates of the commit are the time it is executed unless they are given.
+func DeferChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, opts *ChangeRepoFilesOptions, executeAt time.Time) (*repo_model.DeferredCommit, error) {
+	if err := repo.MustNotBeArchived(); err != nil {
+		return nil, err
+	}
+	for _, trailer := range opts.Trailers {
+		if err := trailer.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	deferredOpts := &deferredChangeRepoFilesOptions{
+		LastCommitID:       opts.LastCommitID,
+		OldBranch:          opts.OldBranch,
+		NewBranch:          opts.NewBranch,
+		Message:            opts.Message,
+		Author:             opts.Author,
+		Committer:          opts.Committer,
+		Dates:              opts.Dates,
+		Signoff:            opts.Signoff,
+		Trailers:           opts.Trailers,
+		ParentCommitID:     opts.ParentCommitID,
+		TargetRef:          opts.TargetRef,
+		CherryPickCommitID: opts.CherryPickCommitID,
+		ApplyTemplateVars:  opts.ApplyTemplateVars,
+		SkipWebhooks:       opts.SkipWebhooks,
+		SkipCI:             opts.SkipCI,
+		Amend:              opts.Amend,
+	}
+	if deferredOpts.OldBranch == "" {
+		deferredOpts.OldBranch = repo.DefaultBranch
+	}
+	for _, file := range opts.Files {
+		if err := validateContentEncoding(file); err != nil {
+			return nil, err
+		}
+		deferredFile := &deferredChangeRepoFile{
+			Operation:    file.Operation,
+			TreePath:     file.TreePath,
+			FromTreePath: file.FromTreePath,
+			SHA:          file.SHA,
+			IsSymlink:    file.IsSymlink,
+			Mode:         file.Mode,
+			Encoding:     file.Encoding,
+			LineEndings:  file.LineEndings,
+		}
+		if file.ContentReader != nil {
+			content, err := io.ReadAll(file.ContentReader)
+			if err != nil {
+				return nil, err
+			}
+			deferredFile.Content = content
+		}
+		deferredOpts.Files = append(deferredOpts.Files, deferredFile)
+	}
+	options, err := json.Marshal(deferredOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	deferredCommit := &repo_model.DeferredCommit{
+		RepoID:      repo.ID,
+		DoerID:      doer.ID,
+		Branch:      deferredOpts.OldBranch,
+		Options:     string(options),
+		ExecuteUnix: timeutil.TimeStamp(executeAt.Unix()),
+	}
+	if err := repo_model.CreateDeferredCommit(ctx, deferredCommit); err != nil {
+		return nil, err
+	}
+	if !executeAt.After(time.Now()) {
+		if err := deferredCommitQueue.Push(deferredCommit.ID); err != nil {
+			return nil, err
+		}
+	}
+	return deferredCommit, nil
+}
+
+// QueueDueDeferredCommits queues the pending deferred commits whose execution time has come
+func QueueDueDeferredCommits(ctx context.Context) error {
+	ids, err := repo_model.GetDueDeferredCommitIDs(ctx, timeutil.TimeStampNow())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := deferredCommitQueue.Push(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeDeferredCommit commits the change of a pending deferred commit and stores its result
+func executeDeferredCommit(ctx context.Context, id int64) error {
+	deferredCommit, err := repo_model.GetDeferredCommitByID(ctx, id)
+	if err != nil || deferredCommit == nil {
+		return err
+	}
+	if started, err := repo_model.StartDeferredCommit(ctx, deferredCommit); err != nil || !started {
+		return err
+	}
+
+	commitID, err := commitDeferredChange(ctx, deferredCommit)
+	if err != nil {
+		log.Debug("deferred commit %d failed: %v", deferredCommit.ID, err)
+		deferredCommit.Status = repo_model.DeferredCommitStatusFailed
+		deferredCommit.Error = err.Error()
+	} else {
+		deferredCommit.Status = repo_model.DeferredCommitStatusDone
+		deferredCommit.CommitID = commitID
+	}
+	return repo_model.UpdateDeferredCommitResult(ctx, deferredCommit)
+}
+
+func commitDeferredChange(ctx context.Context, deferredCommit *repo_model.DeferredCommit) (string, error) {
+	var deferredOpts deferredChangeRepoFilesOptions
+	if err := json.Unmarshal([]byte(deferredCommit.Options), &deferredOpts); err != nil {
+		return "", err
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, deferredCommit.RepoID)
+	if err != nil {
+		return "", err
+	}
+	doer, err := user_model.GetUserByID(ctx, deferredCommit.DoerID)
+	if err != nil {
+		return "", err
+	}
+
+	// the permissions of the doer may have changed since the commit was deferred
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return "", err
+	}
+	if !doer.IsActive || doer.ProhibitLogin || !issues_model.CanMaintainerWriteToBranch(ctx, perm, deferredOpts.OldBranch, doer) {
+		return "", fmt.Errorf("%s can't write to the branch %s anymore", doer.Name, deferredOpts.OldBranch)
+	}
+	if (deferredOpts.SkipWebhooks || deferredOpts.SkipCI) && !perm.IsAdmin() && !doer.IsAdmin {
+		return "", fmt.Errorf("%s can't skip the webhooks or the Actions runs anymore", doer.Name)
+	}
+
+	opts := &ChangeRepoFilesOptions{
+		LastCommitID:       deferredOpts.LastCommitID,
+		OldBranch:          deferredOpts.OldBranch,
+		NewBranch:          deferredOpts.NewBranch,
+		Message:            deferredOpts.Message,
+		Author:             deferredOpts.Author,
+		Committer:          deferredOpts.Committer,
+		Dates:              deferredOpts.Dates,
+		Signoff:            deferredOpts.Signoff,
+		Trailers:           deferredOpts.Trailers,
+		ParentCommitID:     deferredOpts.ParentCommitID,
+		TargetRef:          deferredOpts.TargetRef,
+		CherryPickCommitID: deferredOpts.CherryPickCommitID,
+		ApplyTemplateVars:  deferredOpts.ApplyTemplateVars,
+		SkipWebhooks:       deferredOpts.SkipWebhooks,
+		SkipCI:             deferredOpts.SkipCI,
+		Amend:              deferredOpts.Amend,
+	}
+	if opts.Dates == nil {
+		opts.Dates = &CommitDateOptions{}
+	}
+	if opts.Dates.Author.IsZero() {
+		opts.Dates.Author = time.Now()
+	}
+	if opts.Dates.Committer.IsZero() {
+		opts.Dates.Committer = time.Now()
+	}
+	for _, file := range deferredOpts.Files {
+		changeRepoFile := &ChangeRepoFile{
+			Operation:    file.Operation,
+			TreePath:     file.TreePath,
+			FromTreePath: file.FromTreePath,
+			SHA:          file.SHA,
+			IsSymlink:    file.IsSymlink,
+			Mode:         file.Mode,
+			Encoding:     file.Encoding,
+			LineEndings:  file.LineEndings,
+		}
+		if file.Content != nil {
+			changeRepoFile.ContentReader = bytes.NewReader(file.Content)
+		}
+		opts.Files = append(opts.Files, changeRepoFile)
+	}
+
+	filesResponse, err := ChangeRepoFiles(ctx, repo, doer, opts)
+	if err != nil {
+		return "", err
+	}
+	return filesResponse.Commit.SHA, nil
+}
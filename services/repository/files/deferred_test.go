@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDeferredOptionsFields checks that a deferred commit stores all the options of the change
+func TestDeferredOptionsFields(t *testing.T) {
+	assertStored := func(t *testing.T, options, stored any, skipped ...string) {
+		optionsType, storedType := reflect.TypeOf(options), reflect.TypeOf(stored)
+		for i := 0; i < optionsType.NumField(); i++ {
+			field := optionsType.Field(i)
+			if !field.IsExported() || slices.Contains(skipped, field.Name) {
+				continue
+			}
+			storedField, ok := storedType.FieldByName(field.Name)
+			if assert.True(t, ok, "%s isn't stored", field.Name) {
+				assert.Equal(t, field.Type, storedField.Type, "%s is stored with another type", field.Name)
+			}
+		}
+	}
+
+	// the files are stored with their content
+	assertStored(t, ChangeRepoFilesOptions{}, deferredChangeRepoFilesOptions{}, "Files")
+	// the content is read from ContentReader and Options is computed from the other fields
+	assertStored(t, ChangeRepoFile{}, deferredChangeRepoFile{}, "ContentReader", "Options")
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Content encodings of a ChangeRepoFile
+const (
+	ContentEncodingUTF8   = "utf-8"
+	ContentEncodingBase64 = "base64"
+	ContentEncodingLatin1 = "iso-8859-1"
+)
+
+// Line endings a ChangeRepoFile is committed with
+const (
+	LineEndingsKeep = "keep"
+	LineEndingsLF   = "lf"
+	LineEndingsCRLF = "crlf"
+)
+
+func validateContentEncoding(file *ChangeRepoFile) error {
+	switch file.Encoding {
+	case "", ContentEncodingUTF8, ContentEncodingBase64, ContentEncodingLatin1:
+	default:
+		return util.NewInvalidArgumentErrorf("unsupported content encoding %q for %s", file.Encoding, file.TreePath)
+	}
+	switch file.LineEndings {
+	case "", LineEndingsKeep, LineEndingsLF, LineEndingsCRLF:
+	default:
+		return util.NewInvalidArgumentErrorf("unsupported line endings %q for %s", file.LineEndings, file.TreePath)
+	}
+	return nil
+}
+
+// convertLineEndings converts all the line endings of the content to LF or CRLF
+func convertLineEndings(content []byte, lineEndings string) []byte {
+	switch lineEndings {
+	case LineEndingsLF:
+		return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	case LineEndingsCRLF:
+		return bytes.ReplaceAll(convertLineEndings(content, LineEndingsLF), []byte("\n"), []byte("\r\n"))
+	}
+	return content
+}
+
+// transcodeContent decodes the content of the file from its declared encoding, converts its line endings
+// and encodes it as it is committed. Latin-1 content is given as UTF-8 text.
+func transcodeContent(file *ChangeRepoFile) error {
+	if file.ContentReader == nil || (file.Encoding == "" && file.LineEndings == "") {
+		return nil
+	}
+	content, err := io.ReadAll(file.ContentReader)
+	if err != nil {
+		return err
+	}
+
+	if file.Encoding == ContentEncodingBase64 {
+		if content, err = base64.StdEncoding.DecodeString(string(content)); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid base64 content for %s: %v", file.TreePath, err)
+		}
+	}
+	if !file.IsSymlink {
+		content = convertLineEndings(content, file.LineEndings)
+	}
+	if file.Encoding == ContentEncodingLatin1 {
+		if content, err = charmap.ISO8859_1.NewEncoder().Bytes(content); err != nil {
+			return util.NewInvalidArgumentErrorf("content of %s cannot be encoded as %s", file.TreePath, ContentEncodingLatin1)
+		}
+	}
+
+	file.ContentReader = bytes.NewReader(content)
+	return nil
+}
+
+// BlobLineEndings returns the line endings used by the blob, CRLF if its first line ends with CRLF and LF otherwise
+func BlobLineEndings(blob *git.Blob) (string, error) {
+	r, err := blob.DataAsync()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	buf := make([]byte, 8*1024)
+	n, err := util.ReadAtMost(r, buf)
+	if err != nil {
+		return "", err
+	}
+	if line, _, ok := strings.Cut(string(buf[:n]), "\n"); ok && strings.HasSuffix(line, "\r") {
+		return LineEndingsCRLF, nil
+	}
+	return LineEndingsLF, nil
+}
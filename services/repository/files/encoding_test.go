@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertLineEndings(t *testing.T) {
+	assert.Equal(t, "a\nb\n", string(convertLineEndings([]byte("a\r\nb\n"), LineEndingsLF)))
+	assert.Equal(t, "a\r\nb\r\n", string(convertLineEndings([]byte("a\r\nb\n"), LineEndingsCRLF)))
+	assert.Equal(t, "a\r\nb\n", string(convertLineEndings([]byte("a\r\nb\n"), LineEndingsKeep)))
+}
+
+func TestTranscodeContent(t *testing.T) {
+	transcode := func(content, encoding, lineEndings string) (string, error) {
+		file := &ChangeRepoFile{TreePath: "file", ContentReader: strings.NewReader(content), Encoding: encoding, LineEndings: lineEndings}
+		if err := transcodeContent(file); err != nil {
+			return "", err
+		}
+		b, err := io.ReadAll(file.ContentReader)
+		require.NoError(t, err)
+		return string(b), nil
+	}
+
+	content, err := transcode("YQ0KYgo=", ContentEncodingBase64, LineEndingsLF)
+	require.NoError(t, err)
+	assert.Equal(t, "a\nb\n", content)
+
+	content, err = transcode("café\n", ContentEncodingLatin1, LineEndingsCRLF)
+	require.NoError(t, err)
+	assert.Equal(t, "caf\xe9\r\n", content)
+
+	_, err = transcode("not base64!", ContentEncodingBase64, "")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = transcode("日本", ContentEncodingLatin1, "")
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	assert.ErrorIs(t, validateContentEncoding(&ChangeRepoFile{Encoding: "utf-16"}), util.ErrInvalidArgument)
+	assert.ErrorIs(t, validateContentEncoding(&ChangeRepoFile{LineEndings: "cr"}), util.ErrInvalidArgument)
+}
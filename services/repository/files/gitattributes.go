@@ -0,0 +1,83 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"bytes"
+	"regexp"
+
+	"code.gitea.io/gitea/modules/git"
+)
+
+// cleanFilterAttributes are the attributes of the filters git applies to the content of a file added to its index.
+// export-ignore isn't one of them, it only excludes files from the archives.
+var cleanFilterAttributes = []string{"filter", "text", "eol", "ident"}
+
+var identPattern = regexp.MustCompile(`\$Id:[^$\n]*\$`)
+
+// isBinaryContent reports whether the text=auto detection of git considers the content as binary
+func isBinaryContent(content []byte) bool {
+	// a trailing ^Z (DOS end of file) is ignored
+	content = bytes.TrimSuffix(content, []byte{'\032'})
+	printable, nonPrintable := 0, 0
+	for i, c := range content {
+		switch {
+		case c == 0:
+			return true
+		case c == '\r':
+			if i+1 >= len(content) || content[i+1] != '\n' {
+				// lone carriage return
+				return true
+			}
+		case c == '\n', c == '\b', c == '\t', c == '\033', c == '\014':
+			printable++
+		case c < 32 || c == 127:
+			nonPrintable++
+		default:
+			printable++
+		}
+	}
+	return printable>>7 < nonPrintable
+}
+
+// needsCleanFilters reports whether the attributes of a file change its content when it is added to the index
+func needsCleanFilters(attrs map[string]string) bool {
+	return git.AttributeToBool(attrs, "ident").Value() ||
+		attrs["text"] == "auto" || git.AttributeToBool(attrs, "text").Value() ||
+		(hasEOLAttribute(attrs) && !git.AttributeToBool(attrs, "text").Has())
+}
+
+// hasEOLAttribute reports whether the eol attribute sets the line endings of the checked out file
+func hasEOLAttribute(attrs map[string]string) bool {
+	return attrs["eol"] == "lf" || attrs["eol"] == "crlf"
+}
+
+// applyCleanFilters normalizes the line endings of text files and collapses the ident keywords like git does
+// when a file is added to its index, so committed files are the same as the ones committed by a git client.
+// hasCRLFInIndex reports whether the file in the index has CRLF line endings, which text=auto keeps.
+func applyCleanFilters(content []byte, attrs map[string]string, hasCRLFInIndex func() (bool, error)) ([]byte, error) {
+	text := git.AttributeToBool(attrs, "text")
+	auto := attrs["text"] == "auto"
+	// eol sets the text attribute unless it is unset or auto
+	normalize := text.Value() || (!text.Has() && !auto && hasEOLAttribute(attrs))
+
+	if (normalize || auto) && bytes.Contains(content, []byte("\r\n")) {
+		convert := normalize
+		if auto && !isBinaryContent(content) {
+			hasCRLF, err := hasCRLFInIndex()
+			if err != nil {
+				return nil, err
+			}
+			convert = !hasCRLF
+		}
+		if convert {
+			content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+		}
+	}
+
+	if git.AttributeToBool(attrs, "ident").Value() {
+		content = identPattern.ReplaceAllLiteral(content, []byte("$Id$"))
+	}
+	return content, nil
+}
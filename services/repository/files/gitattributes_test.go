@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBinaryContent(t *testing.T) {
+	assert.False(t, isBinaryContent([]byte("a\r\nb\n\tc\032")))
+	assert.True(t, isBinaryContent([]byte("a\x00b")))
+	assert.True(t, isBinaryContent([]byte("a\rb\r\n")))
+	assert.True(t, isBinaryContent([]byte("\x01\x02\x03")))
+}
+
+func TestNeedsCleanFilters(t *testing.T) {
+	assert.True(t, needsCleanFilters(map[string]string{"text": "set"}))
+	assert.True(t, needsCleanFilters(map[string]string{"text": "auto"}))
+	assert.True(t, needsCleanFilters(map[string]string{"eol": "lf"}))
+	assert.True(t, needsCleanFilters(map[string]string{"ident": "set"}))
+	assert.False(t, needsCleanFilters(map[string]string{"text": "unset", "eol": "lf"}))
+	assert.False(t, needsCleanFilters(map[string]string{"text": "unspecified", "filter": "lfs"}))
+	assert.False(t, needsCleanFilters(nil))
+}
+
+func TestApplyCleanFilters(t *testing.T) {
+	noCRLFInIndex := func() (bool, error) { return false, nil }
+	crlfInIndex := func() (bool, error) { return true, nil }
+	clean := func(content string, attrs map[string]string, hasCRLFInIndex func() (bool, error)) string {
+		b, err := applyCleanFilters([]byte(content), attrs, hasCRLFInIndex)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	assert.Equal(t, "a\nb\n", clean("a\r\nb\n", map[string]string{"text": "set"}, crlfInIndex))
+	assert.Equal(t, "a\nb\n", clean("a\r\nb\n", map[string]string{"eol": "crlf"}, crlfInIndex))
+	assert.Equal(t, "a\r\nb\n", clean("a\r\nb\n", map[string]string{"text": "unset", "eol": "crlf"}, noCRLFInIndex))
+	assert.Equal(t, "a\nb\n", clean("a\r\nb\n", map[string]string{"text": "auto"}, noCRLFInIndex))
+	assert.Equal(t, "a\r\nb\n", clean("a\r\nb\n", map[string]string{"text": "auto"}, crlfInIndex))
+	assert.Equal(t, "a\x00\r\n", clean("a\x00\r\n", map[string]string{"text": "auto"}, noCRLFInIndex))
+	assert.Equal(t, "a\r\nb\n", clean("a\r\nb\n", map[string]string{}, noCRLFInIndex))
+	assert.Equal(t, "$Id$ $Id: x\n$", clean("$Id: 1234 $ $Id: x\n$", map[string]string{"ident": "set"}, noCRLFInIndex))
+}
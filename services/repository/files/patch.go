@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	git_model "code.gitea.io/gitea/models/git"
@@ -16,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 )
 
@@ -24,17 +26,29 @@ type ApplyDiffPatchOptions struct {
 	LastCommitID string
 	OldBranch    string
 	NewBranch    string
-	Message      string
-	Content      string
-	SHA          string
-	Author       *IdentityOptions
-	Committer    *IdentityOptions
-	Dates        *CommitDateOptions
-	Signoff      bool
+	// Message is the commit message, the message of the patch is used if it is empty and UseFormatPatchMetadata is set
+	Message string
+	// Content is a unified diff or a patch generated by git format-patch
+	Content   string
+	SHA       string
+	Author    *IdentityOptions
+	Committer *IdentityOptions
+	Dates     *CommitDateOptions
+	Signoff   bool
+	Trailers  []git.CommitTrailer
+	// ThreeWay falls back to a three-way merge if the patch doesn't apply cleanly
+	ThreeWay bool
+	// UseFormatPatchMetadata uses the author, date and message of a patch generated by git format-patch,
+	// the author is only used if neither the author nor the committer are given
+	UseFormatPatchMetadata bool
+	// SkipWebhooks and SkipCI don't trigger the webhooks and the Actions runs of the push,
+	// the caller must check the doer is an administrator of the repository
+	SkipWebhooks bool
+	SkipCI       bool
 }
 
-// Validate validates the provided options
-func (opts *ApplyDiffPatchOptions) Validate(ctx context.Context, repo *repo_model.Repository, doer *user_model.User) error {
+// validateBranches checks the old branch exists and the new branch doesn't if it's another one
+func (opts *ApplyDiffPatchOptions) validateBranches(ctx context.Context, repo *repo_model.Repository) error {
 	// If no branch name is set, assume master
 	if opts.OldBranch == "" {
 		opts.OldBranch = repo.DefaultBranch
@@ -55,7 +69,6 @@ func (opts *ApplyDiffPatchOptions) Validate(ctx context.Context, repo *repo_mode
 	}
 	// A NewBranch can be specified for the patch to be applied to.
 	// Check to make sure the branch does not already exist, otherwise we can't proceed.
-	// If we aren't branching to a new branch, make sure user can commit to the given branch
 	if opts.NewBranch != opts.OldBranch {
 		existingBranch, err := gitRepo.GetBranch(opts.NewBranch)
 		if existingBranch != nil {
@@ -66,7 +79,17 @@ func (opts *ApplyDiffPatchOptions) Validate(ctx context.Context, repo *repo_mode
 		if err != nil && !git.IsErrBranchNotExist(err) {
 			return err
 		}
-	} else {
+	}
+	return nil
+}
+
+// Validate validates the provided options
+func (opts *ApplyDiffPatchOptions) Validate(ctx context.Context, repo *repo_model.Repository, doer *user_model.User) error {
+	if err := opts.validateBranches(ctx, repo); err != nil {
+		return err
+	}
+	// If we aren't branching to a new branch, make sure user can commit to the given branch
+	if opts.NewBranch == opts.OldBranch {
 		protectedBranch, err := git_model.GetFirstMatchProtectedBranchRule(ctx, repo.ID, opts.OldBranch)
 		if err != nil {
 			return err
@@ -94,26 +117,30 @@ func (opts *ApplyDiffPatchOptions) Validate(ctx context.Context, repo *repo_mode
 	return nil
 }
 
-// ApplyDiffPatch applies a patch to the given repository
+// ApplyDiffPatch applies a unified diff or a patch generated by git format-patch to the given repository,
+// the changed files are checked against the branch protection like ChangeRepoFiles does.
+// It returns an ErrPatchDoesNotApply with the conflicting hunks if the patch doesn't apply.
 func ApplyDiffPatch(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, opts *ApplyDiffPatchOptions) (*structs.FileResponse, error) {
 	err := repo.MustNotBeArchived()
 	if err != nil {
 		return nil, err
 	}
 
-	if err := opts.Validate(ctx, repo, doer); err != nil {
+	if err := opts.validateBranches(ctx, repo); err != nil {
 		return nil, err
 	}
-
-	message := strings.TrimSpace(opts.Message)
-
-	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
+	for _, trailer := range opts.Trailers {
+		if err := trailer.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	t, err := NewTemporaryUploadRepository(ctx, repo)
 	if err != nil {
 		log.Error("NewTemporaryUploadRepository failed: %v", err)
 	}
 	defer t.Close()
+	t.SkipPushNotifications(opts.SkipWebhooks, opts.SkipCI)
 	if err := t.Clone(opts.OldBranch, true); err != nil {
 		return nil, err
 	}
@@ -121,6 +148,40 @@ func ApplyDiffPatch(ctx context.Context, repo *repo_model.Repository, doer *user
 		return nil, err
 	}
 
+	diff := opts.Content
+	message := strings.TrimSpace(opts.Message)
+	authorIdentity, committerIdentity := opts.Author, opts.Committer
+	dates := opts.Dates
+	if opts.UseFormatPatchMetadata {
+		info, err := parseFormatPatch(ctx, t.basePath, opts.Content)
+		if err != nil {
+			return nil, err
+		}
+		if info != nil {
+			diff = info.Diff
+			if message == "" {
+				message = info.Message
+			}
+			if (authorIdentity == nil || authorIdentity.Email == "") && (committerIdentity == nil || committerIdentity.Email == "") && info.AuthorEmail != "" {
+				authorIdentity = &IdentityOptions{Name: info.AuthorName, Email: info.AuthorEmail}
+				committerIdentity = &IdentityOptions{Name: doer.DisplayName(), Email: doer.Email}
+			}
+			if (dates == nil || dates.Author.IsZero()) && !info.AuthorDate.IsZero() {
+				dates = &CommitDateOptions{Author: info.AuthorDate}
+				if opts.Dates != nil {
+					dates.Committer = opts.Dates.Committer
+				}
+			}
+		}
+	}
+	if message == "" {
+		message = "apply-patch"
+	}
+	if len(opts.Trailers) > 0 {
+		message = strings.TrimSpace(git.AppendCommitTrailers(message, opts.Trailers...))
+	}
+	author, committer := GetAuthorAndCommitterUsers(authorIdentity, committerIdentity, doer)
+
 	// Get the commit of the original branch
 	commit, err := t.GetBranchCommit(opts.OldBranch)
 	if err != nil {
@@ -139,26 +200,13 @@ func ApplyDiffPatch(ctx context.Context, repo *repo_model.Repository, doer *user
 		if commit.ID.String() != opts.LastCommitID {
 			return nil, models.ErrCommitIDDoesNotMatch{
 				GivenCommitID:   opts.LastCommitID,
-				CurrentCommitID: opts.LastCommitID,
+				CurrentCommitID: commit.ID.String(),
 			}
 		}
 	}
 
-	stdout := &strings.Builder{}
-	stderr := &strings.Builder{}
-
-	cmdApply := git.NewCommand(ctx, "apply", "--index", "--recount", "--cached", "--ignore-whitespace", "--whitespace=fix", "--binary")
-	if git.DefaultFeatures().CheckVersionAtLeast("2.32") {
-		cmdApply.AddArguments("-3")
-	}
-
-	if err := cmdApply.Run(&git.RunOpts{
-		Dir:    t.basePath,
-		Stdout: stdout,
-		Stderr: stderr,
-		Stdin:  strings.NewReader(opts.Content),
-	}); err != nil {
-		return nil, fmt.Errorf("Error: Stdout: %s\nStderr: %s\nErr: %w", stdout.String(), stderr.String(), err)
+	if err := applyPatchToIndex(ctx, t, opts.OldBranch, diff, opts.ThreeWay); err != nil {
+		return nil, err
 	}
 
 	// Now write the tree
@@ -166,13 +214,31 @@ func ApplyDiffPatch(ctx context.Context, repo *repo_model.Repository, doer *user
 	if err != nil {
 		return nil, err
 	}
+	treePaths, err := t.gitRepo.GetFilesChangedBetween(commit.ID.String(), treeHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(treePaths) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("the patch doesn't change any file")
+	}
+	if opts.NewBranch == opts.OldBranch {
+		if err := VerifyBranchProtection(ctx, repo, doer, opts.OldBranch, treePaths); err != nil {
+			return nil, err
+		}
+	}
 
 	// Now commit the tree
 	var commitHash string
-	if opts.Dates != nil {
-		commitHash, err = t.CommitTreeWithDate("HEAD", author, committer, treeHash, message, opts.Signoff, opts.Dates.Author, opts.Dates.Committer)
+	if dates != nil {
+		if dates.Author.IsZero() {
+			dates.Author = time.Now()
+		}
+		if dates.Committer.IsZero() {
+			dates.Committer = time.Now()
+		}
+		commitHash, err = t.CommitTreeWithDate(commit.ID.String(), author, committer, treeHash, message, opts.Signoff, dates.Author, dates.Committer)
 	} else {
-		commitHash, err = t.CommitTree("HEAD", author, committer, treeHash, message, opts.Signoff)
+		commitHash, err = t.CommitTree(commit.ID.String(), author, committer, treeHash, message, opts.Signoff)
 	}
 	if err != nil {
 		return nil, err
@@ -0,0 +1,238 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// StagedFile is a file uploaded to a staging session
+type StagedFile struct {
+	TreePath string
+	Size     int64
+}
+
+// ErrStagedFileOffsetMismatch represents an error if a chunk isn't uploaded at the end of the staged file
+type ErrStagedFileOffsetMismatch struct {
+	TreePath string
+	Offset   int64
+	Size     int64
+}
+
+// IsErrStagedFileOffsetMismatch checks if an error is a ErrStagedFileOffsetMismatch.
+func IsErrStagedFileOffsetMismatch(err error) bool {
+	_, ok := err.(ErrStagedFileOffsetMismatch)
+	return ok
+}
+
+func (err ErrStagedFileOffsetMismatch) Error() string {
+	return fmt.Sprintf("chunk of %s uploaded at offset %d but %d bytes are staged", err.TreePath, err.Offset, err.Size)
+}
+
+// ErrStagedFileTooLarge represents an error if a staged file exceeds the maximum size of uploaded files
+type ErrStagedFileTooLarge struct {
+	TreePath string
+	MaxSize  int64
+}
+
+// IsErrStagedFileTooLarge checks if an error is a ErrStagedFileTooLarge.
+func IsErrStagedFileTooLarge(err error) bool {
+	_, ok := err.(ErrStagedFileTooLarge)
+	return ok
+}
+
+func (err ErrStagedFileTooLarge) Error() string {
+	return fmt.Sprintf("staged file %s is larger than %d bytes", err.TreePath, err.MaxSize)
+}
+
+// CreateStagingSession starts a staging session of the doer to commit files to the branch
+func CreateStagingSession(ctx context.Context, gitRepo *git.Repository, repo *repo_model.Repository, doer *user_model.User, branch string) (*repo_model.RepoStagingSession, error) {
+	if err := repo.MustNotBeArchived(); err != nil {
+		return nil, err
+	}
+	session := &repo_model.RepoStagingSession{
+		RepoID: repo.ID,
+		UserID: doer.ID,
+		Branch: branch,
+	}
+	if !repo.IsEmpty {
+		commit, err := gitRepo.GetBranchCommit(branch)
+		if err != nil {
+			return nil, err
+		}
+		session.BaseCommitID = commit.ID.String()
+	}
+	if err := repo_model.CreateRepoStagingSession(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(session.LocalPath(), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("MkdirAll: %w", err)
+	}
+	return session, nil
+}
+
+// stagedFileLocalPath returns where the staged file is stored, the path must not conflict with another staged file
+func stagedFileLocalPath(session *repo_model.RepoStagingSession, treePath string) (string, error) {
+	localPath := session.LocalPath()
+	parts := strings.Split(treePath, "/")
+	for i, part := range parts {
+		localPath = filepath.Join(localPath, part)
+		fi, err := os.Stat(localPath)
+		if os.IsNotExist(err) {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		if isLast := i == len(parts)-1; isLast == fi.IsDir() {
+			return "", util.NewInvalidArgumentErrorf("staged file %s conflicts with another staged file", treePath)
+		}
+	}
+	return filepath.Join(session.LocalPath(), filepath.FromSlash(treePath)), nil
+}
+
+// StageFileChunk appends a chunk to a staged file, it must be uploaded at the end of the content staged so far
+// so an interrupted upload can be resumed.
+func StageFileChunk(ctx context.Context, session *repo_model.RepoStagingSession, treePath string, offset int64, chunk io.Reader) (*StagedFile, error) {
+	cleanPath := CleanUploadFileName(treePath)
+	if cleanPath == "" {
+		return nil, models.ErrFilenameInvalid{Path: treePath}
+	}
+	localPath, err := stagedFileLocalPath(session, cleanPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(localPath), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("MkdirAll: %w", err)
+	}
+
+	f, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if offset != size {
+		return nil, ErrStagedFileOffsetMismatch{TreePath: cleanPath, Offset: offset, Size: size}
+	}
+
+	maxSize := setting.Repository.Upload.FileMaxSize * 1024 * 1024
+	n, err := io.Copy(f, io.LimitReader(chunk, maxSize-size+1))
+	if err == nil && size+n > maxSize {
+		err = ErrStagedFileTooLarge{TreePath: cleanPath, MaxSize: maxSize}
+	}
+	if err != nil {
+		// drop the partial chunk, the upload is resumed from the previous size
+		if truncErr := f.Truncate(size); truncErr != nil {
+			return nil, truncErr
+		}
+		return nil, err
+	}
+
+	if err := repo_model.TouchRepoStagingSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return &StagedFile{TreePath: cleanPath, Size: size + n}, nil
+}
+
+// GetStagedFiles returns the files staged in the session
+func GetStagedFiles(session *repo_model.RepoStagingSession) ([]*StagedFile, error) {
+	var files []*StagedFile
+	root := session.LocalPath()
+	err := filepath.WalkDir(root, func(localPath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, localPath)
+		if err != nil {
+			return err
+		}
+		files = append(files, &StagedFile{TreePath: filepath.ToSlash(relPath), Size: fi.Size()})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return files, err
+}
+
+// CommitStagingSession commits the files staged in the session to its branch and deletes the session.
+// The files existing in the branch are updated, the others are created.
+func CommitStagingSession(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, session *repo_model.RepoStagingSession, opts *ChangeRepoFilesOptions) (*structs.FilesResponse, error) {
+	stagedFiles, err := GetStagedFiles(session)
+	if err != nil {
+		return nil, err
+	}
+	if len(stagedFiles) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("no files are staged")
+	}
+
+	var baseCommit *git.Commit
+	if session.BaseCommitID != "" {
+		gitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		defer closer.Close()
+		if baseCommit, err = gitRepo.GetCommit(session.BaseCommitID); err != nil {
+			return nil, err
+		}
+	}
+
+	opts.OldBranch = session.Branch
+	opts.LastCommitID = session.BaseCommitID
+	opts.Files = make([]*ChangeRepoFile, 0, len(stagedFiles))
+	for _, stagedFile := range stagedFiles {
+		operation := "create"
+		if baseCommit != nil {
+			if _, err := baseCommit.GetTreeEntryByPath(stagedFile.TreePath); err == nil {
+				operation = "update"
+			} else if !git.IsErrNotExist(err) {
+				return nil, err
+			}
+		}
+
+		f, err := os.Open(filepath.Join(session.LocalPath(), filepath.FromSlash(stagedFile.TreePath)))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		opts.Files = append(opts.Files, &ChangeRepoFile{
+			Operation:     operation,
+			TreePath:      stagedFile.TreePath,
+			ContentReader: f,
+		})
+	}
+
+	filesResponse, err := ChangeRepoFiles(ctx, repo, doer, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo_model.DeleteRepoStagingSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return filesResponse, nil
+}
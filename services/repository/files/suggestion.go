@@ -0,0 +1,249 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrUserCannotApplySuggestions represents an error that the user isn't allowed to push to the head branch of the pull request
+var ErrUserCannotApplySuggestions = util.NewPermissionDeniedErrorf("user is not allowed to push to the head branch of the pull request")
+
+// ApplySuggestionsOptions holds the options to apply the changes suggested by code comments
+type ApplySuggestionsOptions struct {
+	CommentIDs []int64
+	// Message is the message of the commit, a default one is used if it is empty
+	Message string
+}
+
+type appliedSuggestion struct {
+	comment    *issues_model.Comment
+	suggestion *issues_model.CodeSuggestion
+}
+
+// ApplySuggestions commits the changes suggested by the code comments of a pull request to its head branch in a single commit,
+// the suggesters are credited as co-authors and the conversations of the comments are resolved
+func ApplySuggestions(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, opts *ApplySuggestionsOptions) (*structs.FilesResponse, error) {
+	if len(opts.CommentIDs) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("no suggestion to apply")
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return nil, util.NewInvalidArgumentErrorf("the pull request is closed")
+	}
+	if pr.Flow != issues_model.PullRequestFlowGithub {
+		return nil, util.NewInvalidArgumentErrorf("suggestions can't be applied to an AGit pull request")
+	}
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return nil, err
+	} else if pr.HeadRepo == nil {
+		return nil, util.NewInvalidArgumentErrorf("the head repository of the pull request has been deleted")
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, pr.HeadRepo, doer)
+	if err != nil {
+		return nil, err
+	}
+	if !issues_model.CanMaintainerWriteToBranch(ctx, perm, pr.HeadBranch, doer) {
+		return nil, ErrUserCannotApplySuggestions
+	}
+
+	// load the suggestions grouped by file
+	suggestionsByPath := make(map[string][]*appliedSuggestion)
+	var paths []string
+	for _, id := range opts.CommentIDs {
+		comment, err := issues_model.GetCommentByID(ctx, id)
+		if err != nil {
+			if issues_model.IsErrCommentNotExist(err) {
+				return nil, util.NewInvalidArgumentErrorf("comment %d doesn't exist", id)
+			}
+			return nil, err
+		}
+		if comment.IssueID != pr.IssueID || comment.Type != issues_model.CommentTypeCode {
+			return nil, util.NewInvalidArgumentErrorf("comment %d is not a code comment of the pull request", id)
+		}
+		if err := comment.LoadReview(ctx); err != nil {
+			return nil, err
+		}
+		if comment.Review != nil && comment.Review.Type == issues_model.ReviewTypePending {
+			return nil, util.NewInvalidArgumentErrorf("comment %d belongs to a pending review", id)
+		}
+		if comment.Invalidated {
+			return nil, util.NewInvalidArgumentErrorf("comment %d is outdated", id)
+		}
+		suggestion := comment.Suggestion()
+		if suggestion == nil {
+			return nil, util.NewInvalidArgumentErrorf("comment %d doesn't suggest a change", id)
+		}
+		for _, other := range suggestionsByPath[comment.TreePath] {
+			if other.comment.ID == comment.ID {
+				return nil, util.NewInvalidArgumentErrorf("comment %d is given several times", id)
+			}
+			if other.comment.Line == comment.Line {
+				return nil, util.NewInvalidArgumentErrorf("comments %d and %d change the same line", other.comment.ID, id)
+			}
+		}
+		if _, ok := suggestionsByPath[comment.TreePath]; !ok {
+			paths = append(paths, comment.TreePath)
+		}
+		suggestionsByPath[comment.TreePath] = append(suggestionsByPath[comment.TreePath], &appliedSuggestion{comment: comment, suggestion: suggestion})
+	}
+
+	gitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, pr.HeadRepo)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	headCommit, err := gitRepo.GetBranchCommit(pr.HeadBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*ChangeRepoFile, 0, len(paths))
+	for _, treePath := range paths {
+		file, err := applySuggestionsToFile(gitRepo, headCommit, treePath, suggestionsByPath[treePath])
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	// credit the suggesters
+	var trailers []git.CommitTrailer
+	applied := make([]*appliedSuggestion, 0, len(opts.CommentIDs))
+	for _, treePath := range paths {
+		applied = append(applied, suggestionsByPath[treePath]...)
+	}
+	for _, a := range applied {
+		if err := a.comment.LoadPoster(ctx); err != nil {
+			return nil, err
+		}
+		if a.comment.PosterID == doer.ID || a.comment.Poster.IsGhost() {
+			continue
+		}
+		sig := a.comment.Poster.NewGitSig()
+		trailer := git.CommitTrailer{Key: "Co-authored-by", Value: fmt.Sprintf("%s <%s>", sig.Name, sig.Email)}
+		if !slices.Contains(trailers, trailer) {
+			trailers = append(trailers, trailer)
+		}
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	if message == "" {
+		if len(applied) == 1 {
+			message = "Apply suggestion from code review"
+		} else {
+			message = "Apply suggestions from code review"
+		}
+	}
+
+	filesResponse, err := ChangeRepoFiles(ctx, pr.HeadRepo, doer, &ChangeRepoFilesOptions{
+		LastCommitID: headCommit.ID.String(),
+		OldBranch:    pr.HeadBranch,
+		NewBranch:    pr.HeadBranch,
+		Message:      message,
+		Files:        files,
+		Trailers:     trailers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range applied {
+		if err := issues_model.MarkConversation(ctx, a.comment, doer, true); err != nil {
+			return nil, err
+		}
+	}
+	return filesResponse, nil
+}
+
+// applySuggestionsToFile replaces the commented lines of the file at the head of the pull request by the suggested ones
+func applySuggestionsToFile(gitRepo *git.Repository, headCommit *git.Commit, treePath string, suggestions []*appliedSuggestion) (*ChangeRepoFile, error) {
+	entry, err := headCommit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, util.NewInvalidArgumentErrorf("%s doesn't exist in the head branch of the pull request", treePath)
+		}
+		return nil, err
+	}
+	if !entry.IsRegular() && !entry.IsExecutable() {
+		return nil, util.NewInvalidArgumentErrorf("%s is not a regular file", treePath)
+	}
+	content, err := entry.Blob().GetBlobContent(entry.Blob().Size())
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(content, "\n")
+
+	// replace the lines from the bottom so that the line numbers of the other suggestions stay valid
+	slices.SortFunc(suggestions, func(a, b *appliedSuggestion) int {
+		return int(b.comment.Line - a.comment.Line)
+	})
+	for _, s := range suggestions {
+		// the line must not have been changed since it has been commented
+		oldLine, err := getCommentedLine(gitRepo, s.comment)
+		if err != nil {
+			return nil, err
+		}
+		idx := int(s.comment.Line) - 1
+		if !hasLine(lines, idx) || lines[idx] != oldLine {
+			return nil, util.NewInvalidArgumentErrorf("the line commented by comment %d has been changed", s.comment.ID)
+		}
+
+		newLines := s.suggestion.NewLines
+		if strings.HasSuffix(oldLine, "\r") {
+			newLines = make([]string, 0, len(s.suggestion.NewLines))
+			for _, line := range s.suggestion.NewLines {
+				newLines = append(newLines, line+"\r")
+			}
+		}
+		lines = slices.Replace(lines, idx, idx+1, newLines...)
+	}
+
+	return &ChangeRepoFile{
+		Operation:     "update",
+		TreePath:      treePath,
+		FromTreePath:  treePath,
+		ContentReader: strings.NewReader(strings.Join(lines, "\n")),
+		SHA:           entry.ID.String(),
+	}, nil
+}
+
+// getCommentedLine returns the line of the file commented by the code comment, as it was when the comment was made
+func getCommentedLine(gitRepo *git.Repository, comment *issues_model.Comment) (string, error) {
+	commit, err := gitRepo.GetCommit(comment.CommitSHA)
+	if err != nil {
+		return "", err
+	}
+	content, err := commit.GetFileContent(comment.TreePath, 0)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(content, "\n")
+	idx := int(comment.Line) - 1
+	if !hasLine(lines, idx) {
+		return "", util.NewInvalidArgumentErrorf("the line commented by comment %d doesn't exist", comment.ID)
+	}
+	return lines[idx], nil
+}
+
+// hasLine returns whether the index is a line of the split content, the end of the last line doesn't start a line
+func hasLine(lines []string, idx int) bool {
+	return idx >= 0 && idx < len(lines) && !(idx == len(lines)-1 && lines[idx] == "")
+}
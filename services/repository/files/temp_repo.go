@@ -30,6 +30,9 @@ type TemporaryUploadRepository struct {
 	repo     *repo_model.Repository
 	gitRepo  *git.Repository
 	basePath string
+
+	skipWebhooks bool
+	skipCI       bool
 }
 
 // NewTemporaryUploadRepository creates a new temporary upload repository
@@ -94,6 +97,18 @@ func (t *TemporaryUploadRepository) Init(objectFormatName string) error {
 	return nil
 }
 
+// DetachHead points the HEAD at the given commit, the working tree is checked out if the repository isn't bare
+func (t *TemporaryUploadRepository) DetachHead(commitID string, bare bool) error {
+	cmd := git.NewCommand(t.ctx, "checkout", "--detach", "-q").AddDynamicArguments(commitID)
+	if bare {
+		cmd = git.NewCommand(t.ctx, "update-ref", "--no-deref", "HEAD").AddDynamicArguments(commitID)
+	}
+	if _, _, err := cmd.RunStdString(&git.RunOpts{Dir: t.basePath}); err != nil {
+		return fmt.Errorf("DetachHead: %w", err)
+	}
+	return nil
+}
+
 // SetDefaultIndex sets the git index to our HEAD
 func (t *TemporaryUploadRepository) SetDefaultIndex() error {
 	if _, _, err := git.NewCommand(t.ctx, "read-tree", "HEAD").RunStdString(&git.RunOpts{Dir: t.basePath}); err != nil {
@@ -233,6 +248,19 @@ func (t *TemporaryUploadRepository) CommitTree(parent string, author, committer
 
 // CommitTreeWithDate creates a commit from a given tree for the user with provided message
 func (t *TemporaryUploadRepository) CommitTreeWithDate(parent string, author, committer *user_model.User, treeHash, message string, signoff bool, authorDate, committerDate time.Time) (string, error) {
+	var parents []string
+	if parent != "" {
+		parents = []string{parent}
+	}
+	return t.commitTree(parents, author, committer, treeHash, message, signoff, authorDate, committerDate)
+}
+
+// CommitMergeTree creates a merge commit of the parents from a given tree for the user with provided message
+func (t *TemporaryUploadRepository) CommitMergeTree(parents []string, author, committer *user_model.User, treeHash, message string) (string, error) {
+	return t.commitTree(parents, author, committer, treeHash, message, false, time.Now(), time.Now())
+}
+
+func (t *TemporaryUploadRepository) commitTree(parents []string, author, committer *user_model.User, treeHash, message string, signoff bool, authorDate, committerDate time.Time) (string, error) {
 	authorSig := author.NewGitSig()
 	committerSig := committer.NewGitSig()
 
@@ -244,35 +272,41 @@ func (t *TemporaryUploadRepository) CommitTreeWithDate(parent string, author, co
 		"GIT_COMMITTER_DATE="+committerDate.Format(time.RFC3339),
 	)
 
-	messageBytes := new(bytes.Buffer)
-	_, _ = messageBytes.WriteString(message)
-	_, _ = messageBytes.WriteString("\n")
+	// A key of the committer is preferred to the signing key of the instance
+	userKey, err := asymkey_service.GetUserSigningKey(t.ctx, committer)
+	if err != nil {
+		log.Error("Unable to get the signing key of %s: %v", committer.Name, err)
+	}
 
-	cmdCommitTree := git.NewCommand(t.ctx, "commit-tree").AddDynamicArguments(treeHash)
-	if parent != "" {
+	cmdCommitTree := git.NewCommand(t.ctx)
+	if userKey != nil {
+		cmdCommitTree.AddOptionValues("-c", "gpg.format="+userKey.Format)
+	}
+	cmdCommitTree.AddArguments("commit-tree").AddDynamicArguments(treeHash)
+	for _, parent := range parents {
 		cmdCommitTree.AddOptionValues("-p", parent)
 	}
 
 	var sign bool
 	var keyID string
 	var signer *git.Signature
-	if parent != "" {
-		sign, keyID, signer, _ = asymkey_service.SignCRUDAction(t.ctx, t.repo.RepoPath(), author, t.basePath, parent)
-	} else {
+	var trailers []git.CommitTrailer
+	if userKey == nil && len(parents) > 0 {
+		sign, keyID, signer, _ = asymkey_service.SignCRUDAction(t.ctx, t.repo.RepoPath(), author, t.basePath, parents[0])
+	} else if userKey == nil {
 		sign, keyID, signer, _ = asymkey_service.SignInitialCommit(t.ctx, t.repo.RepoPath(), author)
 	}
-	if sign {
+	if userKey != nil {
+		cmdCommitTree.AddOptionFormat("-S%s", userKey.KeyID)
+	} else if sign {
 		cmdCommitTree.AddOptionFormat("-S%s", keyID)
 		if t.repo.GetTrustModel() == repo_model.CommitterTrustModel || t.repo.GetTrustModel() == repo_model.CollaboratorCommitterTrustModel {
 			if committerSig.Name != authorSig.Name || committerSig.Email != authorSig.Email {
 				// Add trailers
-				_, _ = messageBytes.WriteString("\n")
-				_, _ = messageBytes.WriteString("Co-authored-by: ")
-				_, _ = messageBytes.WriteString(committerSig.String())
-				_, _ = messageBytes.WriteString("\n")
-				_, _ = messageBytes.WriteString("Co-committed-by: ")
-				_, _ = messageBytes.WriteString(committerSig.String())
-				_, _ = messageBytes.WriteString("\n")
+				trailers = append(trailers,
+					git.CommitTrailer{Key: "Co-authored-by", Value: committerSig.String()},
+					git.CommitTrailer{Key: "Co-committed-by", Value: committerSig.String()},
+				)
 			}
 			committerSig = signer
 		}
@@ -281,10 +315,15 @@ func (t *TemporaryUploadRepository) CommitTreeWithDate(parent string, author, co
 	}
 
 	if signoff {
-		// Signed-off-by
+		trailers = append(trailers, git.CommitTrailer{Key: "Signed-off-by", Value: committerSig.String()})
+	}
+
+	messageBytes := new(bytes.Buffer)
+	if len(trailers) > 0 {
+		_, _ = messageBytes.WriteString(git.AppendCommitTrailers(message, trailers...))
+	} else {
+		_, _ = messageBytes.WriteString(message)
 		_, _ = messageBytes.WriteString("\n")
-		_, _ = messageBytes.WriteString("Signed-off-by: ")
-		_, _ = messageBytes.WriteString(committerSig.String())
 	}
 
 	env = append(env,
@@ -312,11 +351,29 @@ func (t *TemporaryUploadRepository) CommitTreeWithDate(parent string, author, co
 
 // Push the provided commitHash to the repository branch by the provided user
 func (t *TemporaryUploadRepository) Push(doer *user_model.User, commitHash, branch string) error {
+	return t.PushRef(doer, commitHash, git.BranchPrefix+strings.TrimSpace(branch), false)
+}
+
+// SkipPushNotifications makes the pushes skip the webhooks and/or the Actions runs they would trigger
+func (t *TemporaryUploadRepository) SkipPushNotifications(skipWebhooks, skipCI bool) {
+	t.skipWebhooks = skipWebhooks
+	t.skipCI = skipCI
+}
+
+// PushRef pushes the provided commitHash to the reference of the repository by the provided user
+func (t *TemporaryUploadRepository) PushRef(doer *user_model.User, commitHash, refName string, force bool) error {
 	// Because calls hooks we need to pass in the environment
 	env := repo_module.PushingEnvironment(doer, t.repo)
+	if t.skipWebhooks {
+		env = append(env, repo_module.EnvSkipWebhooks+"=true")
+	}
+	if t.skipCI {
+		env = append(env, repo_module.EnvSkipCI+"=true")
+	}
 	if err := git.Push(t.ctx, t.basePath, git.PushOptions{
 		Remote: t.repo.RepoPath(),
-		Branch: strings.TrimSpace(commitHash) + ":" + git.BranchPrefix + strings.TrimSpace(branch),
+		Branch: strings.TrimSpace(commitHash) + ":" + refName,
+		Force:  force,
 		Env:    env,
 	}); err != nil {
 		if git.IsErrPushOutOfDate(err) {
@@ -0,0 +1,125 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/huandu/xstrings"
+)
+
+type transformer struct {
+	Name      string
+	Transform func(string) string
+}
+
+type expansion struct {
+	Name         string
+	Value        string
+	Transformers []transformer
+}
+
+var defaultTransformers = []transformer{
+	{Name: "SNAKE", Transform: xstrings.ToSnakeCase},
+	{Name: "KEBAB", Transform: xstrings.ToKebabCase},
+	{Name: "CAMEL", Transform: func(str string) string {
+		return xstrings.FirstRuneToLower(xstrings.ToCamelCase(str))
+	}},
+	{Name: "PASCAL", Transform: xstrings.ToCamelCase},
+	{Name: "LOWER", Transform: strings.ToLower},
+	{Name: "UPPER", Transform: strings.ToUpper},
+	{Name: "TITLE", Transform: util.ToTitleCase},
+}
+
+func templateExpansions(templateRepo, repo *repo_model.Repository) []expansion {
+	return []expansion{
+		{Name: "REPO_NAME", Value: repo.Name, Transformers: defaultTransformers},
+		{Name: "TEMPLATE_NAME", Value: templateRepo.Name, Transformers: defaultTransformers},
+		{Name: "REPO_DESCRIPTION", Value: repo.Description, Transformers: nil},
+		{Name: "TEMPLATE_DESCRIPTION", Value: templateRepo.Description, Transformers: nil},
+		{Name: "REPO_OWNER", Value: repo.OwnerName, Transformers: defaultTransformers},
+		{Name: "TEMPLATE_OWNER", Value: templateRepo.OwnerName, Transformers: defaultTransformers},
+		{Name: "REPO_LINK", Value: repo.Link(), Transformers: nil},
+		{Name: "TEMPLATE_LINK", Value: templateRepo.Link(), Transformers: nil},
+		{Name: "REPO_HTTPS_URL", Value: repo.CloneLink().HTTPS, Transformers: nil},
+		{Name: "TEMPLATE_HTTPS_URL", Value: templateRepo.CloneLink().HTTPS, Transformers: nil},
+		{Name: "REPO_SSH_URL", Value: repo.CloneLink().SSH, Transformers: nil},
+		{Name: "TEMPLATE_SSH_URL", Value: templateRepo.CloneLink().SSH, Transformers: nil},
+	}
+}
+
+// templateVarRegexp matches the escaped "$$", "${VAR}" and "$VAR"
+var templateVarRegexp = regexp.MustCompile(`\$(?:\$|\{\w+\}|\w+)`)
+
+func expandTemplateVars(src string, expansions []expansion, sanitizeFileName bool) string {
+	expansionMap := make(map[string]string)
+	for _, e := range expansions {
+		expansionMap[e.Name] = e.Value
+		for _, tr := range e.Transformers {
+			expansionMap[fmt.Sprintf("%s_%s", e.Name, tr.Name)] = tr.Transform(e.Value)
+		}
+	}
+
+	return templateVarRegexp.ReplaceAllStringFunc(src, func(variable string) string {
+		if variable == "$$" {
+			return "$"
+		}
+		if expansion, ok := expansionMap[strings.Trim(variable[1:], "{}")]; ok {
+			if sanitizeFileName {
+				return fileNameSanitize(expansion)
+			}
+			return expansion
+		}
+		// unknown variables, e.g. the ones of a shell script, are kept as they are
+		return variable
+	})
+}
+
+// ExpandTemplateVars expands the template variables of a repository generated from templateRepo, e.g. ${REPO_NAME}, in src.
+// The TEMPLATE_ variables refer to the template the repository is generated from.
+func ExpandTemplateVars(src string, templateRepo, repo *repo_model.Repository, sanitizeFileName bool) string {
+	return expandTemplateVars(src, templateExpansions(templateRepo, repo), sanitizeFileName)
+}
+
+// expandFileTemplateVars expands the template variables in a file created in the repository,
+// besides the ones of the generated repositories it supports the OWNER and YEAR variables
+func expandFileTemplateVars(src string, templateRepo, repo *repo_model.Repository, sanitizeFileName bool) string {
+	expansions := append(templateExpansions(templateRepo, repo),
+		expansion{Name: "OWNER", Value: repo.OwnerName, Transformers: defaultTransformers},
+		expansion{Name: "YEAR", Value: strconv.Itoa(time.Now().Year()), Transformers: nil},
+	)
+	return expandTemplateVars(src, expansions, sanitizeFileName)
+}
+
+// applyTemplateVars expands the template variables in the path and the content of a file
+func applyTemplateVars(file *ChangeRepoFile, templateRepo, repo *repo_model.Repository) error {
+	file.TreePath = expandFileTemplateVars(file.TreePath, templateRepo, repo, true)
+	if file.ContentReader == nil || file.IsSymlink {
+		return nil
+	}
+	content, err := io.ReadAll(file.ContentReader)
+	if err != nil {
+		return err
+	}
+	file.ContentReader = strings.NewReader(expandFileTemplateVars(string(content), templateRepo, repo, false))
+	return nil
+}
+
+var fileNameSanitizeRegexp = regexp.MustCompile(`(?i)\.\.|[<>:\"/\\|?*\x{0000}-\x{001F}]|^(con|prn|aux|nul|com\d|lpt\d)$`)
+
+// Sanitize user input to valid OS filenames
+//
+//		Based on https://github.com/sindresorhus/filename-reserved-regex
+//	 Adds ".." to prevent directory traversal
+func fileNameSanitize(s string) string {
+	return strings.TrimSpace(fileNameSanitizeRegexp.ReplaceAllString(s, "_"))
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTemplateVars(t *testing.T) {
+	templateRepo := &repo_model.Repository{OwnerName: "user2", Name: "template"}
+	repo := &repo_model.Repository{OwnerName: "user30", Name: "go-sdk"}
+
+	assert.Equal(t, "go-sdk GO-SDK template user30", ExpandTemplateVars("$REPO_NAME ${REPO_NAME_UPPER} $TEMPLATE_NAME ${REPO_OWNER}", templateRepo, repo, false))
+	assert.Equal(t, "go-sdk_template.go", ExpandTemplateVars("${REPO_NAME}_${TEMPLATE_NAME}.go", templateRepo, repo, true))
+
+	// unknown variables are kept as they are and "$$" escapes a variable
+	assert.Equal(t, `echo "$HOME $1 ${PATH} $" $REPO_NAME`, ExpandTemplateVars(`echo "$HOME $1 ${PATH} $" $$REPO_NAME`, templateRepo, repo, false))
+
+	// the variables only supported in created files are not expanded in generated repositories
+	assert.Equal(t, "${OWNER} $YEAR", ExpandTemplateVars("${OWNER} $YEAR", templateRepo, repo, false))
+	year := strconv.Itoa(time.Now().Year())
+	assert.Equal(t, "user30 USER30 "+year, expandFileTemplateVars("${OWNER} ${OWNER_UPPER} $YEAR", templateRepo, repo, false))
+}
+
+func TestFileNameSanitize(t *testing.T) {
+	assert.Equal(t, "test_CON", fileNameSanitize("test_CON"))
+	assert.Equal(t, "test CON", fileNameSanitize("test CON "))
+	assert.Equal(t, "__traverse__", fileNameSanitize("../traverse/.."))
+	assert.Equal(t, "http___localhost_3003_user_test.git", fileNameSanitize("http://localhost:3003/user/test.git"))
+	assert.Equal(t, "_", fileNameSanitize("CON"))
+	assert.Equal(t, "_", fileNameSanitize("con"))
+	assert.Equal(t, "_", fileNameSanitize("\u0000"))
+	assert.Equal(t, "目标", fileNameSanitize("目标"))
+}
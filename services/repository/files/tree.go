@@ -7,16 +7,32 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 )
 
+// GetTreeOptions are the options of the listing of a tree
+type GetTreeOptions struct {
+	Page      int
+	PerPage   int
+	Recursive bool
+	// PathPrefix is the directory of the tree whose entries are listed
+	PathPrefix string
+	// Depth is how many levels of directories are listed, it implies Recursive. 0 lists all the levels if Recursive.
+	Depth int
+	// Cursor is the path of the last entry of the previous page, the entries after it are listed instead of Page
+	Cursor string
+}
+
 // GetTreeBySHA get the GitTreeResponse of a repository using a sha hash.
-func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, sha string, page, perPage int, recursive bool) (*api.GitTreeResponse, error) {
+func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, sha string, opts GetTreeOptions) (*api.GitTreeResponse, error) {
 	gitTree, err := gitRepo.GetTree(sha)
 	if err != nil || gitTree == nil {
 		return nil, models.ErrSHANotFound{
@@ -26,8 +42,21 @@ func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git
 	tree := new(api.GitTreeResponse)
 	tree.SHA = gitTree.ResolvedID.String()
 	tree.URL = repo.APIURL() + "/git/trees/" + url.PathEscape(tree.SHA)
+
+	pathPrefix := strings.Trim(opts.PathPrefix, "/")
+	if pathPrefix != "" {
+		entry, err := gitTree.GetTreeEntryByPath(pathPrefix)
+		if git.IsErrNotExist(err) || (err == nil && !entry.IsDir()) {
+			return nil, util.NewInvalidArgumentErrorf("path prefix %s is not a directory of the tree", pathPrefix)
+		} else if err != nil {
+			return nil, err
+		}
+		if gitTree, err = gitTree.SubTree(pathPrefix); err != nil {
+			return nil, err
+		}
+	}
 	var entries git.Entries
-	if recursive {
+	if opts.Recursive || opts.Depth > 0 {
 		entries, err = gitTree.ListEntriesRecursiveWithSize()
 	} else {
 		entries, err = gitTree.ListEntries()
@@ -35,6 +64,18 @@ func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git
 	if err != nil {
 		return nil, err
 	}
+	if opts.Depth > 0 {
+		entries = slices.DeleteFunc(entries, func(entry *git.TreeEntry) bool {
+			return strings.Count(entry.Name(), "/") >= opts.Depth
+		})
+	}
+	entryPath := func(entry *git.TreeEntry) string {
+		if pathPrefix == "" {
+			return entry.Name()
+		}
+		return pathPrefix + "/" + entry.Name()
+	}
+
 	apiURL := repo.APIURL()
 	apiURLLen := len(apiURL)
 	objectFormat := git.ObjectFormatFromName(repo.ObjectFormatName)
@@ -53,6 +94,7 @@ func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git
 	// copyPos is at the start of the hash
 	copyPos := len(treeURL) - hashLen
 
+	perPage, page := opts.PerPage, opts.Page
 	if perPage <= 0 || perPage > setting.API.DefaultGitTreesPerPage {
 		perPage = setting.API.DefaultGitTreesPerPage
 	}
@@ -62,6 +104,17 @@ func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git
 	tree.Page = page
 	tree.TotalCount = len(entries)
 	rangeStart := perPage * (page - 1)
+	if opts.Cursor != "" {
+		// the entries of a tree don't change, so the cursor is always one of them
+		cursor := slices.IndexFunc(entries, func(entry *git.TreeEntry) bool {
+			return entryPath(entry) == opts.Cursor
+		})
+		if cursor < 0 {
+			return nil, util.NewInvalidArgumentErrorf("cursor %s is not an entry of the tree", opts.Cursor)
+		}
+		rangeStart = cursor + 1
+		tree.Page = 0
+	}
 	if rangeStart >= len(entries) {
 		return tree, nil
 	}
@@ -71,14 +124,18 @@ func GetTreeBySHA(ctx context.Context, repo *repo_model.Repository, gitRepo *git
 	}
 	if rangeStart+perPage < len(entries) {
 		rangeEnd = rangeStart + perPage
+		tree.NextCursor = entryPath(entries[rangeEnd-1])
 	} else {
 		rangeEnd = len(entries)
 	}
+	if opts.Cursor != "" {
+		tree.Truncated = tree.NextCursor != ""
+	}
 	tree.Entries = make([]api.GitEntry, rangeEnd-rangeStart)
 	for e := rangeStart; e < rangeEnd; e++ {
 		i := e - rangeStart
 
-		tree.Entries[i].Path = entries[e].Name()
+		tree.Entries[i].Path = entryPath(entries[e])
 		tree.Entries[i].Mode = fmt.Sprintf("%06o", entries[e].Mode())
 		tree.Entries[i].Type = entries[e].Type()
 		tree.Entries[i].Size = entries[e].Size()
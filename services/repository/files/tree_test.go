@@ -28,7 +28,7 @@ func TestGetTreeBySHA(t *testing.T) {
 	ctx.SetPathParam(":id", "1")
 	ctx.SetPathParam(":sha", sha)
 
-	tree, err := GetTreeBySHA(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.PathParam(":sha"), page, perPage, true)
+	tree, err := GetTreeBySHA(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.PathParam(":sha"), GetTreeOptions{Page: page, PerPage: perPage, Recursive: true})
 	assert.NoError(t, err)
 	expectedTree := &api.GitTreeResponse{
 		SHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d",
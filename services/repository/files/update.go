@@ -4,6 +4,7 @@
 package files
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -21,7 +22,9 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
+	"code.gitea.io/gitea/services/pull"
 )
 
 // IdentityOptions for a person's identity like an author or committer
@@ -37,12 +40,21 @@ type CommitDateOptions struct {
 }
 
 type ChangeRepoFile struct {
+	// Operation is create, update or delete for a file, delete-dir or move-dir for a directory
 	Operation     string
 	TreePath      string
 	FromTreePath  string
 	ContentReader io.ReadSeeker
 	SHA           string
-	Options       *RepoFileOptions
+	// IsSymlink creates or updates a symbolic link, the content is its target
+	IsSymlink bool
+	// Mode is the mode of the created or updated file, 100644 or 100755, the mode of the updated file is kept if empty
+	Mode string
+	// Encoding is the encoding the content is given in or committed with: utf-8 (default), base64 or iso-8859-1
+	Encoding string
+	// LineEndings converts the line endings of the content to lf or crlf, they are kept if empty
+	LineEndings string
+	Options     *RepoFileOptions
 }
 
 // ChangeRepoFilesOptions holds the repository files update options
@@ -56,12 +68,37 @@ type ChangeRepoFilesOptions struct {
 	Committer    *IdentityOptions
 	Dates        *CommitDateOptions
 	Signoff      bool
+	// Trailers are appended to the trailer block of the commit message
+	Trailers []git.CommitTrailer
+	// ParentCommitID is the parent of the new commit instead of the head of OldBranch
+	ParentCommitID string
+	// TargetRef is a reference under refs/gitea/ the new commit is written to instead of NewBranch, no branch is changed
+	TargetRef string
+	// CherryPickCommitID is a commit whose changes are applied to the branch before the files
+	CherryPickCommitID string
+	// ApplyTemplateVars expands the template variables of the repository in the paths and contents of created files
+	ApplyTemplateVars bool
+	// SkipWebhooks and SkipCI don't trigger the webhooks and the Actions runs of the push,
+	// the caller must check the doer is an administrator of the repository
+	SkipWebhooks bool
+	SkipCI       bool
+	// Amend replaces the head of the branch with a commit of its changes and the files instead of adding a commit,
+	// the branch is force-updated so it must not be protected
+	Amend bool
 }
 
 type RepoFileOptions struct {
 	treePath     string
 	fromTreePath string
 	executable   bool
+	// symlink is set for a symbolic link, which is also kept when a link is updated without IsSymlink
+	symlink bool
+	// dirFiles are the paths of the files in the directory of a delete-dir or move-dir operation
+	dirFiles []string
+}
+
+func isDirOperation(operation string) bool {
+	return operation == "delete-dir" || operation == "move-dir"
 }
 
 // ChangeRepoFiles adds, updates or removes multiple files in the given repository
@@ -78,6 +115,22 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 	if opts.NewBranch == "" {
 		opts.NewBranch = opts.OldBranch
 	}
+	for _, trailer := range opts.Trailers {
+		if err := trailer.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, file := range opts.Files {
+		if err := validateContentEncoding(file); err != nil {
+			return nil, err
+		}
+	}
+	if opts.TargetRef != "" && (!strings.HasPrefix(opts.TargetRef, git.GiteaPrefix) || !git.IsValidRefPattern(opts.TargetRef)) {
+		return nil, util.NewInvalidArgumentErrorf("invalid target reference %q, it must be under %s", opts.TargetRef, git.GiteaPrefix)
+	}
+	if opts.Amend && (repo.IsEmpty || opts.NewBranch != opts.OldBranch || opts.TargetRef != "" || opts.ParentCommitID != "" || opts.CherryPickCommitID != "") {
+		return nil, util.NewInvalidArgumentErrorf("only the head of an existing branch can be amended")
+	}
 
 	gitRepo, closer, err := gitrepo.RepositoryFromContextOrOpen(ctx, repo)
 	if err != nil {
@@ -90,8 +143,47 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 		return nil, err
 	}
 
-	var treePaths []string
+	// the files are changed on top of the parent commit if one is given
+	baseRef := opts.OldBranch
+	if opts.ParentCommitID != "" {
+		parent, err := gitRepo.GetCommit(opts.ParentCommitID)
+		if err != nil {
+			return nil, err
+		}
+		opts.ParentCommitID = parent.ID.String()
+		baseRef = opts.ParentCommitID
+	}
+
+	var treePaths, dirFiles []string
+	var cherryPick *git.Commit
+	if opts.CherryPickCommitID != "" {
+		if cherryPick, err = gitRepo.GetCommit(opts.CherryPickCommitID); err != nil {
+			return nil, err
+		}
+		// the files changed by the cherry-picked commit are checked against the branch protection and returned too
+		if treePaths, err = gitRepo.GetFilesChangedBetween(cherryPickBase(repo, cherryPick).String(), cherryPick.ID.String()); err != nil {
+			return nil, err
+		}
+	}
+	// the variables of a generated repository refer to its template
+	templateRepo := repo
+	if opts.ApplyTemplateVars {
+		if generatedFrom, err := repo_model.GetTemplateRepo(ctx, repo); err != nil && !repo_model.IsErrRepoNotExist(err) {
+			return nil, err
+		} else if generatedFrom != nil {
+			templateRepo = generatedFrom
+		}
+	}
 	for _, file := range opts.Files {
+		if opts.ApplyTemplateVars && file.Operation == "create" {
+			if err := applyTemplateVars(file, templateRepo, repo); err != nil {
+				return nil, err
+			}
+		}
+		if err := transcodeContent(file); err != nil {
+			return nil, err
+		}
+
 		// If FromTreePath is not set, set it to the opts.TreePath
 		if file.TreePath != "" && file.FromTreePath == "" {
 			file.FromTreePath = file.TreePath
@@ -112,18 +204,46 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 			}
 		}
 
+		switch file.Mode {
+		case "", "100644", "100755":
+		default:
+			return nil, util.NewInvalidArgumentErrorf("invalid mode %q of %s, supported modes are 100644 and 100755", file.Mode, file.TreePath)
+		}
+		if file.IsSymlink && file.Mode != "" {
+			return nil, util.NewInvalidArgumentErrorf("the mode of the symbolic link %s can't be set", file.TreePath)
+		}
+
 		file.Options = &RepoFileOptions{
 			treePath:     treePath,
 			fromTreePath: fromTreePath,
-			executable:   false,
+			executable:   file.Mode == "100755",
+			symlink:      file.IsSymlink,
 		}
 		treePaths = append(treePaths, treePath)
+
+		if isDirOperation(file.Operation) {
+			if file.Options.dirFiles, err = getDirFiles(gitRepo, repo, baseRef, fromTreePath); err != nil {
+				return nil, err
+			}
+			dirFiles = append(dirFiles, file.Options.dirFiles...)
+			if file.Operation == "move-dir" {
+				if fromTreePath == treePath || strings.HasPrefix(treePath, fromTreePath+"/") {
+					return nil, util.NewInvalidArgumentErrorf("can't move the directory %s to %s", fromTreePath, treePath)
+				}
+				for _, dirFile := range file.Options.dirFiles {
+					dirFiles = append(dirFiles, path.Join(treePath, strings.TrimPrefix(dirFile, fromTreePath+"/")))
+				}
+			}
+		}
 	}
 
 	// A NewBranch can be specified for the file to be created/updated in a new branch.
 	// Check to make sure the branch does not already exist, otherwise we can't proceed.
 	// If we aren't branching to a new branch, make sure user can commit to the given branch
-	if opts.NewBranch != opts.OldBranch {
+	// No branch is changed by a commit which is written to a target reference.
+	switch {
+	case opts.TargetRef != "":
+	case opts.NewBranch != opts.OldBranch:
 		existingBranch, err := gitRepo.GetBranch(opts.NewBranch)
 		if existingBranch != nil {
 			return nil, git_model.ErrBranchAlreadyExists{
@@ -133,11 +253,27 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 		if err != nil && !git.IsErrBranchNotExist(err) {
 			return nil, err
 		}
-	} else if err := VerifyBranchProtection(ctx, repo, doer, opts.OldBranch, treePaths); err != nil {
-		return nil, err
+	default:
+		if err := VerifyBranchProtection(ctx, repo, doer, opts.OldBranch, append(treePaths, dirFiles...)); err != nil {
+			return nil, err
+		}
+		if opts.Amend {
+			// amending rewrites the head of the branch, which is a force push
+			if protected, err := git_model.IsBranchProtected(ctx, repo.ID, opts.OldBranch); err != nil {
+				return nil, err
+			} else if protected {
+				return nil, util.NewPermissionDeniedErrorf("branch %s is protected from force push", opts.OldBranch)
+			}
+		}
 	}
 
 	message := strings.TrimSpace(opts.Message)
+	if message == "" && cherryPick != nil {
+		message = fmt.Sprintf("%s\n\n(cherry picked from commit %s)", strings.TrimSpace(cherryPick.Message()), cherryPick.ID.String())
+	}
+	if len(opts.Trailers) > 0 {
+		message = strings.TrimSpace(git.AppendCommitTrailers(message, opts.Trailers...))
+	}
 
 	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
 
@@ -146,14 +282,16 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 		log.Error("NewTemporaryUploadRepository failed: %v", err)
 	}
 	defer t.Close()
+	t.SkipPushNotifications(opts.SkipWebhooks, opts.SkipCI)
 	hasOldBranch := true
-	if err := t.Clone(opts.OldBranch, true); err != nil {
+	// the three-way merge of a cherry-pick needs a working tree
+	if err := t.Clone(opts.OldBranch, cherryPick == nil); err != nil {
 		for _, file := range opts.Files {
-			if file.Operation == "delete" {
+			if file.Operation == "delete" || isDirOperation(file.Operation) {
 				return nil, err
 			}
 		}
-		if !git.IsErrBranchNotExist(err) || !repo.IsEmpty {
+		if cherryPick != nil || opts.ParentCommitID != "" || !git.IsErrBranchNotExist(err) || !repo.IsEmpty {
 			return nil, err
 		}
 		if err := t.Init(repo.ObjectFormatName); err != nil {
@@ -162,6 +300,11 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 		hasOldBranch = false
 		opts.LastCommitID = ""
 	}
+	if opts.ParentCommitID != "" {
+		if err := t.DetachHead(opts.ParentCommitID, cherryPick == nil); err != nil {
+			return nil, err
+		}
+	}
 	if hasOldBranch {
 		if err := t.SetDefaultIndex(); err != nil {
 			return nil, err
@@ -192,9 +335,14 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 		}
 	}
 
+	// the parent of the new commit, which is the parent of the head of the branch if it is amended
+	parentCommitID := ""
 	if hasOldBranch {
 		// Get the commit of the original branch
 		commit, err := t.GetBranchCommit(opts.OldBranch)
+		if opts.ParentCommitID != "" {
+			commit, err = t.GetCommit(opts.ParentCommitID)
+		}
 		if err != nil {
 			return nil, err // Couldn't get a commit for the branch
 		}
@@ -209,12 +357,49 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 			}
 			opts.LastCommitID = lastCommitID.String()
 		}
+		if opts.Amend {
+			if opts.LastCommitID != commit.ID.String() {
+				// only the commit known by the doer may be amended
+				return nil, models.ErrCommitIDDoesNotMatch{
+					GivenCommitID:   opts.LastCommitID,
+					CurrentCommitID: commit.ID.String(),
+				}
+			}
+			if commit.ParentCount() > 1 {
+				return nil, util.NewInvalidArgumentErrorf("the merge commit %s can't be amended", commit.ID.String())
+			}
+			if commit.ParentCount() == 1 {
+				parentCommitID = commit.Parents[0].String()
+			}
+			// the message of the amended commit is kept if none is given
+			if strings.TrimSpace(opts.Message) == "" {
+				message = strings.TrimSpace(git.AppendCommitTrailers(strings.TrimSpace(commit.Message()), opts.Trailers...))
+			}
+		}
 
 		for _, file := range opts.Files {
 			if err := handleCheckErrors(file, commit, opts); err != nil {
 				return nil, err
 			}
 		}
+
+		// The index is read from the head of the branch, so if the branch has moved since the last commit
+		// the changes are rebased onto the head, unless the commits in between touch any of the changed paths.
+		if opts.LastCommitID != commit.ID.String() {
+			if err := checkUnrelatedChanges(t, commit, opts.LastCommitID, changedPaths(opts.Files, treePaths)); err != nil {
+				return nil, err
+			}
+			opts.LastCommitID = commit.ID.String()
+		}
+		if !opts.Amend {
+			parentCommitID = opts.LastCommitID
+		}
+
+		if cherryPick != nil {
+			if err := applyCherryPick(ctx, t, repo, commit, cherryPick, opts); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	contentStore := lfs.NewContentStore()
@@ -229,8 +414,16 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 			if err := t.RemoveFilesFromIndex(file.TreePath); err != nil {
 				return nil, err
 			}
+		case "delete-dir":
+			if err := t.RemoveFilesFromIndex(file.Options.dirFiles...); err != nil {
+				return nil, err
+			}
+		case "move-dir":
+			if err := moveDirInIndex(t, file.Options.fromTreePath, file.Options.treePath); err != nil {
+				return nil, err
+			}
 		default:
-			return nil, fmt.Errorf("invalid file operation: %s %s, supported operations are create, update, delete", file.Operation, file.Options.treePath)
+			return nil, fmt.Errorf("invalid file operation: %s %s, supported operations are create, update, delete, delete-dir, move-dir", file.Operation, file.Options.treePath)
 		}
 	}
 
@@ -243,16 +436,23 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 	// Now commit the tree
 	var commitHash string
 	if opts.Dates != nil {
-		commitHash, err = t.CommitTreeWithDate(opts.LastCommitID, author, committer, treeHash, message, opts.Signoff, opts.Dates.Author, opts.Dates.Committer)
+		commitHash, err = t.CommitTreeWithDate(parentCommitID, author, committer, treeHash, message, opts.Signoff, opts.Dates.Author, opts.Dates.Committer)
 	} else {
-		commitHash, err = t.CommitTree(opts.LastCommitID, author, committer, treeHash, message, opts.Signoff)
+		commitHash, err = t.CommitTree(parentCommitID, author, committer, treeHash, message, opts.Signoff)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Then push this tree to NewBranch
-	if err := t.Push(doer, commitHash, opts.NewBranch); err != nil {
+	// Then push this tree to NewBranch, a speculative commit replaces the target reference
+	responseRef := opts.NewBranch
+	if opts.TargetRef != "" {
+		err = t.PushRef(doer, commitHash, opts.TargetRef, true)
+		responseRef = commitHash
+	} else {
+		err = t.PushRef(doer, commitHash, git.BranchPrefix+opts.NewBranch, opts.Amend)
+	}
+	if err != nil {
 		log.Error("%T %v", err, err)
 		return nil, err
 	}
@@ -262,12 +462,15 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 		return nil, err
 	}
 
-	filesResponse, err := GetFilesResponseFromCommit(ctx, repo, commit, opts.NewBranch, treePaths)
+	filesResponse, err := GetFilesResponseFromCommit(ctx, repo, commit, responseRef, treePaths)
 	if err != nil {
 		return nil, err
 	}
+	if err := setRenamedFiles(t, commitHash, opts.Files, filesResponse); err != nil {
+		return nil, err
+	}
 
-	if repo.IsEmpty {
+	if repo.IsEmpty && opts.TargetRef == "" {
 		if isEmpty, err := gitRepo.IsEmpty(); err == nil && !isEmpty {
 			_ = repo_model.UpdateRepositoryCols(ctx, &repo_model.Repository{ID: repo.ID, IsEmpty: false, DefaultBranch: opts.NewBranch}, "is_empty", "default_branch")
 		}
@@ -276,6 +479,179 @@ func ChangeRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 	return filesResponse, nil
 }
 
+// setRenamedFiles adds the previous paths of the moved files, and their similarity as detected by git, to the response
+func setRenamedFiles(t *TemporaryUploadRepository, commitHash string, files []*ChangeRepoFile, filesResponse *structs.FilesResponse) error {
+	previousPaths := make(map[string]string)
+	for _, file := range files {
+		if file.Operation == "update" && file.Options.fromTreePath != file.Options.treePath {
+			previousPaths[file.Options.treePath] = file.Options.fromTreePath
+		}
+	}
+	if len(previousPaths) == 0 {
+		return nil
+	}
+
+	renames, err := git.GetCommitFileRenames(t.ctx, t.basePath, commitHash)
+	if err != nil {
+		return err
+	}
+	similarities := make(map[string]int, len(renames))
+	for _, rename := range renames {
+		if previousPaths[rename.To] == rename.From {
+			similarities[rename.To] = rename.Similarity
+		}
+	}
+	for _, contents := range filesResponse.Files {
+		if contents == nil {
+			continue
+		}
+		if previousPath, ok := previousPaths[contents.Path]; ok {
+			contents.PreviousPath = previousPath
+			contents.Similarity = similarities[contents.Path]
+		}
+	}
+	return nil
+}
+
+// getDirFiles returns the paths of the files in a directory of the ref
+func getDirFiles(gitRepo *git.Repository, repo *repo_model.Repository, ref, dir string) ([]string, error) {
+	if repo.IsEmpty {
+		return nil, models.ErrRepoFileDoesNotExist{Path: dir}
+	}
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(dir)
+	if git.IsErrNotExist(err) || (err == nil && !entry.IsDir()) {
+		return nil, models.ErrRepoFileDoesNotExist{Path: dir}
+	} else if err != nil {
+		return nil, err
+	}
+	tree, err := commit.SubTree(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := tree.ListEntriesRecursiveFast()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, path.Join(dir, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// moveDirInIndex moves the entries of a directory of the index to another directory
+func moveDirInIndex(t *TemporaryUploadRepository, fromDir, toDir string) error {
+	existing, err := t.LsFiles(toDir)
+	if err != nil {
+		return err
+	}
+	for _, existingPath := range existing {
+		if existingPath != "" {
+			return models.ErrRepoFileAlreadyExists{Path: toDir}
+		}
+	}
+
+	objFmt, err := t.gitRepo.GetObjectFormat()
+	if err != nil {
+		return err
+	}
+	stdout, _, err := git.NewCommand(t.ctx, "ls-files", "--stage", "-z").AddDashesAndList(fromDir + "/").RunStdString(&git.RunOpts{Dir: t.basePath})
+	if err != nil {
+		return fmt.Errorf("unable to list the files of %s: %w", fromDir, err)
+	}
+	// each entry is "<mode> <object> <stage>\t<path>", the index info input is "<mode> <object>\t<path>"
+	stdin := new(bytes.Buffer)
+	for _, entry := range strings.Split(stdout, "\x00") {
+		info, oldPath, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(info)
+		if len(fields) != 3 {
+			return fmt.Errorf("unexpected index entry: %q", entry)
+		}
+		_, _ = fmt.Fprintf(stdin, "0 %s\t%s\x00", objFmt.EmptyObjectID(), oldPath)
+		_, _ = fmt.Fprintf(stdin, "%s %s\t%s\x00", fields[0], fields[1], path.Join(toDir, strings.TrimPrefix(oldPath, fromDir+"/")))
+	}
+	if stdin.Len() == 0 {
+		return models.ErrRepoFileDoesNotExist{Path: fromDir}
+	}
+
+	stderr := new(bytes.Buffer)
+	if err := git.NewCommand(t.ctx, "update-index", "--add", "--remove", "-z", "--index-info").
+		Run(&git.RunOpts{Dir: t.basePath, Stdin: stdin, Stderr: stderr}); err != nil {
+		return fmt.Errorf("unable to move %s to %s in the index: %w\nstderr: %s", fromDir, toDir, err, stderr.String())
+	}
+	return nil
+}
+
+// cherryPickBase returns the first parent of the commit, or the empty tree if it is a root commit
+func cherryPickBase(repo *repo_model.Repository, commit *git.Commit) git.ObjectID {
+	parent, err := commit.ParentID(0)
+	if err != nil {
+		return git.ObjectFormatFromName(repo.ObjectFormatName).EmptyTree()
+	}
+	return parent
+}
+
+// applyCherryPick merges the changes of the cherry-picked commit into the index of the branch head
+func applyCherryPick(ctx context.Context, t *TemporaryUploadRepository, repo *repo_model.Repository, head, cherryPick *git.Commit, opts *ChangeRepoFilesOptions) error {
+	if err := t.RefreshIndex(); err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("CherryPick %s onto %s", cherryPick.ID.String(), opts.OldBranch)
+	conflict, conflictedFiles, err := pull.AttemptThreeWayMerge(ctx,
+		t.basePath, t.gitRepo, cherryPickBase(repo, cherryPick).String(), head.ID.String(), cherryPick.ID.String(), description)
+	if err != nil {
+		return fmt.Errorf("failed to three-way merge %s onto %s: %w", cherryPick.ID.String(), opts.OldBranch, err)
+	}
+	if conflict {
+		return models.ErrMergeConflicts{
+			Err: fmt.Errorf("cherry-picking %s onto %s conflicts in %s", cherryPick.ID.String(), opts.OldBranch, strings.Join(conflictedFiles, ", ")),
+		}
+	}
+	return nil
+}
+
+// changedPaths returns all paths changed by the files, including the source paths and the files of directories
+func changedPaths(files []*ChangeRepoFile, treePaths []string) []string {
+	paths := append([]string{}, treePaths...)
+	for _, file := range files {
+		if file.Options.fromTreePath != "" {
+			paths = append(paths, file.Options.fromTreePath)
+		}
+		paths = append(paths, file.Options.dirFiles...)
+	}
+	return paths
+}
+
+// checkUnrelatedChanges returns ErrCommitIDDoesNotMatch if any of the paths has been changed between the last commit and the head
+func checkUnrelatedChanges(t *TemporaryUploadRepository, head *git.Commit, lastCommitID string, paths []string) error {
+	changed, err := t.gitRepo.GetFilesChangedBetween(lastCommitID, head.ID.String())
+	if err != nil {
+		return err
+	}
+	for _, changedPath := range changed {
+		for _, p := range paths {
+			// a changed file conflicts with the path itself, a directory above it or the files below it
+			if changedPath == p || strings.HasPrefix(changedPath, p+"/") || strings.HasPrefix(p, changedPath+"/") {
+				return models.ErrCommitIDDoesNotMatch{
+					GivenCommitID:   lastCommitID,
+					CurrentCommitID: head.ID.String(),
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // handles the check for various issues for ChangeRepoFiles
 func handleCheckErrors(file *ChangeRepoFile, commit *git.Commit, opts *ChangeRepoFilesOptions) error {
 	if file.Operation == "update" || file.Operation == "delete" {
@@ -311,7 +687,15 @@ func handleCheckErrors(file *ChangeRepoFile, commit *git.Commit, opts *ChangeRep
 			// haven't been made. We throw an error if one wasn't provided.
 			return models.ErrSHAOrCommitIDNotProvided{}
 		}
-		file.Options.executable = fromEntry.IsExecutable()
+		if file.Operation == "update" && fromEntry.IsLink() {
+			// an updated symbolic link stays a link, its mode can't be changed
+			if file.Mode != "" {
+				return util.NewInvalidArgumentErrorf("the mode of the symbolic link %s can't be set", file.Options.fromTreePath)
+			}
+			file.Options.symlink = true
+		} else if file.Mode == "" {
+			file.Options.executable = fromEntry.IsExecutable()
+		}
 	}
 	if file.Operation == "create" || file.Operation == "update" {
 		// For the path where this file will be created/updated, we need to make
@@ -339,7 +723,8 @@ func handleCheckErrors(file *ChangeRepoFile, commit *git.Commit, opts *ChangeRep
 						Type:    git.EntryModeBlob,
 					}
 				}
-			} else if entry.IsLink() {
+			} else if entry.IsLink() && (file.Operation == "create" || file.Options.fromTreePath != file.Options.treePath) {
+				// a symbolic link can only be updated in place
 				return models.ErrFilePathInvalid{
 					Message: fmt.Sprintf("a symbolic link exists where you’re trying to create a subdirectory [path: %s]", subTreePath),
 					Path:    subTreePath,
@@ -396,18 +781,19 @@ func CreateOrUpdateFile(ctx context.Context, t *TemporaryUploadRepository, file
 
 	treeObjectContentReader := file.ContentReader
 	var lfsMetaObject *git_model.LFSMetaObject
-	if setting.LFS.StartServer && hasOldBranch {
+	if hasOldBranch && !file.Options.symlink {
 		// Check there is no way this can return multiple infos
 		filename2attribute2info, err := t.gitRepo.CheckAttribute(git.CheckAttributeOpts{
-			Attributes: []string{"filter"},
+			Attributes: cleanFilterAttributes,
 			Filenames:  []string{file.Options.treePath},
 			CachedOnly: true,
 		})
 		if err != nil {
 			return err
 		}
+		attrs := filename2attribute2info[file.Options.treePath]
 
-		if filename2attribute2info[file.Options.treePath] != nil && filename2attribute2info[file.Options.treePath]["filter"] == "lfs" {
+		if setting.LFS.StartServer && attrs["filter"] == "lfs" {
 			// OK so we are supposed to LFS this data!
 			pointer, err := lfs.GeneratePointer(treeObjectContentReader)
 			if err != nil {
@@ -415,6 +801,25 @@ func CreateOrUpdateFile(ctx context.Context, t *TemporaryUploadRepository, file
 			}
 			lfsMetaObject = &git_model.LFSMetaObject{Pointer: pointer, RepositoryID: repoID}
 			treeObjectContentReader = strings.NewReader(pointer.StringContent())
+		} else if needsCleanFilters(attrs) {
+			content, err := io.ReadAll(treeObjectContentReader)
+			if err != nil {
+				return err
+			}
+			content, err = applyCleanFilters(content, attrs, func() (bool, error) {
+				if !util.SliceContainsString(filesInIndex, file.Options.treePath) {
+					return false, nil
+				}
+				stdout, _, err := git.NewCommand(ctx, "cat-file", "blob").AddDynamicArguments(":" + file.Options.treePath).RunStdBytes(&git.RunOpts{Dir: t.basePath})
+				if err != nil {
+					return false, err
+				}
+				return bytes.Contains(stdout, []byte("\r\n")), nil
+			})
+			if err != nil {
+				return err
+			}
+			treeObjectContentReader = bytes.NewReader(content)
 		}
 	}
 
@@ -425,14 +830,14 @@ func CreateOrUpdateFile(ctx context.Context, t *TemporaryUploadRepository, file
 	}
 
 	// Add the object to the index
-	if file.Options.executable {
-		if err := t.AddObjectToIndex("100755", objectHash, file.Options.treePath); err != nil {
-			return err
-		}
-	} else {
-		if err := t.AddObjectToIndex("100644", objectHash, file.Options.treePath); err != nil {
-			return err
-		}
+	mode := "100644"
+	if file.Options.symlink {
+		mode = "120000"
+	} else if file.Options.executable {
+		mode = "100755"
+	}
+	if err := t.AddObjectToIndex(mode, objectHash, file.Options.treePath); err != nil {
+		return err
 	}
 
 	if lfsMetaObject != nil {
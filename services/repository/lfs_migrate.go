@@ -0,0 +1,524 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+)
+
+const (
+	lfsAttributesFilename = ".gitattributes"
+	lfsAttributes         = "filter=lfs diff=lfs merge=lfs -text"
+
+	// lfsMigrateProgressInterval is the number of rewritten commits between two progress reports
+	lfsMigrateProgressInterval = 100
+)
+
+// MigrateToLFSOptions represents the options of a migration of the files in the history of a repository to LFS
+type MigrateToLFSOptions struct {
+	Patterns               []string `json:"patterns"`
+	Branches               []string `json:"branches"`
+	IgnoreOpenPullRequests bool     `json:"ignore_open_pull_requests"`
+	// BackupRefPrefix is the prefix of the refs keeping the previous heads of the rewritten branches
+	BackupRefPrefix string `json:"-"`
+}
+
+// MigrateToLFSResult represents the result of a migration of files to LFS
+type MigrateToLFSResult struct {
+	Files   int
+	Commits int
+}
+
+// ErrLFSMigrateOpenPullRequests represents an error that there are open pull requests from or to the branches to rewrite
+type ErrLFSMigrateOpenPullRequests struct {
+	Count int
+}
+
+// IsErrLFSMigrateOpenPullRequests checks if an error is a ErrLFSMigrateOpenPullRequests.
+func IsErrLFSMigrateOpenPullRequests(err error) bool {
+	_, ok := err.(ErrLFSMigrateOpenPullRequests)
+	return ok
+}
+
+func (err ErrLFSMigrateOpenPullRequests) Error() string {
+	return fmt.Sprintf("there are %d open pull requests from or to the branches, their history will be rewritten", err.Count)
+}
+
+// lfsPattern matches the paths of files like a pattern of .gitattributes
+type lfsPattern struct {
+	glob glob.Glob
+	// matchPath is set if the pattern contains a slash and is matched against the whole path instead of the file name
+	matchPath bool
+}
+
+func (p *lfsPattern) Match(treePath string) bool {
+	if p.matchPath {
+		return p.glob.Match(treePath) || p.glob.Match("/"+treePath)
+	}
+	return p.glob.Match(path.Base(treePath))
+}
+
+func compileLFSPatterns(patterns []string) ([]*lfsPattern, error) {
+	compiled := make([]*lfsPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(strings.TrimPrefix(pattern, "/"), '/')
+		if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid pattern %q: %v", pattern, err)
+		}
+		compiled = append(compiled, &lfsPattern{glob: g, matchPath: strings.Contains(pattern, "/")})
+	}
+	return compiled, nil
+}
+
+// ValidateMigrateToLFS checks and normalizes the options of a migration of files to LFS
+func ValidateMigrateToLFS(ctx context.Context, repo *repo_model.Repository, opts *MigrateToLFSOptions) error {
+	if !setting.LFS.StartServer {
+		return util.NewInvalidArgumentErrorf("LFS is disabled")
+	}
+	if repo.IsArchived || repo.IsMirror || repo.IsEmpty {
+		return util.NewInvalidArgumentErrorf("the history of archived, mirrored or empty repositories can't be rewritten")
+	}
+
+	seen := make(container.Set[string])
+	patterns := make([]string, 0, len(opts.Patterns))
+	for _, pattern := range opts.Patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.ContainsAny(pattern, " \t\r\n") {
+			return util.NewInvalidArgumentErrorf("pattern %q must not contain whitespace", pattern)
+		}
+		if path.Base(pattern) == lfsAttributesFilename || strings.HasSuffix(pattern, "/") {
+			return util.NewInvalidArgumentErrorf("pattern %q doesn't match files which can be migrated", pattern)
+		}
+		if seen.Add(pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return util.NewInvalidArgumentErrorf("no pattern is given")
+	}
+	opts.Patterns = patterns
+	if _, err := compileLFSPatterns(opts.Patterns); err != nil {
+		return err
+	}
+
+	seen = make(container.Set[string])
+	branches := make([]string, 0, len(opts.Branches))
+	for _, branch := range opts.Branches {
+		if branch = strings.TrimSpace(branch); branch != "" && seen.Add(branch) {
+			branches = append(branches, branch)
+		}
+	}
+	if len(branches) == 0 {
+		branches = append(branches, repo.DefaultBranch)
+	}
+	opts.Branches = branches
+
+	openPullRequests := 0
+	for _, branch := range opts.Branches {
+		b, err := git_model.GetBranch(ctx, repo.ID, branch)
+		if err != nil {
+			if git_model.IsErrBranchNotExist(err) {
+				return util.NewInvalidArgumentErrorf("branch %q doesn't exist", branch)
+			}
+			return err
+		}
+		if b.IsDeleted {
+			return util.NewInvalidArgumentErrorf("branch %q doesn't exist", branch)
+		}
+
+		headPRs, err := issues_model.GetUnmergedPullRequestsByHeadInfo(ctx, repo.ID, branch)
+		if err != nil {
+			return err
+		}
+		basePRs, err := issues_model.GetUnmergedPullRequestsByBaseInfo(ctx, repo.ID, branch)
+		if err != nil {
+			return err
+		}
+		openPullRequests += len(headPRs) + len(basePRs)
+	}
+	if openPullRequests > 0 && !opts.IgnoreOpenPullRequests {
+		return ErrLFSMigrateOpenPullRequests{Count: openPullRequests}
+	}
+	return nil
+}
+
+// MigrateToLFS rewrites the history of the branches, like "git lfs migrate import", so that the files matching the patterns
+// are stored in LFS and the patterns are tracked in the .gitattributes of every commit.
+// The previous heads of the branches are kept below the backup ref prefix and the rewritten branches are force pushed
+// with the hooks, so the branch protections apply. A branch which was changed in the meantime isn't overwritten.
+func MigrateToLFS(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, opts *MigrateToLFSOptions, progress func(format string, args ...any)) (*MigrateToLFSResult, error) {
+	patterns, err := compileLFSPatterns(opts.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpBasePath, err := repo_module.CreateTemporaryPath("lfs-migrate")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := repo_module.RemoveTemporaryPath(tmpBasePath); err != nil {
+			log.Error("MigrateToLFS: RemoveTemporaryPath: %v", err)
+		}
+	}()
+
+	if err := git.Clone(ctx, repo.RepoPath(), tmpBasePath, git.CloneRepoOptions{
+		Bare:   true,
+		Shared: true,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to clone repository %s: %w", repo.FullName(), err)
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, tmpBasePath)
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	heads := make([]string, 0, len(opts.Branches))
+	for _, branch := range opts.Branches {
+		commitID, err := gitRepo.GetBranchCommitID(branch)
+		if err != nil {
+			return nil, err
+		}
+		heads = append(heads, commitID)
+	}
+
+	stdout, _, err := git.NewCommand(ctx, "rev-list", "--reverse", "--topo-order").AddDynamicArguments(heads...).RunStdString(&git.RunOpts{Dir: tmpBasePath})
+	if err != nil {
+		return nil, err
+	}
+	commitIDs := strings.Fields(stdout)
+
+	m := &lfsMigrator{
+		ctx:          ctx,
+		repo:         repo,
+		gitRepo:      gitRepo,
+		patterns:     patterns,
+		attributes:   opts.Patterns,
+		contentStore: lfs.NewContentStore(),
+		blobs:        make(map[string]string),
+		trees:        make(map[string]string),
+		commits:      make(map[string]string, len(commitIDs)),
+		attributeIDs: make(map[string]string),
+	}
+	result := &MigrateToLFSResult{}
+	for i, commitID := range commitIDs {
+		if i%lfsMigrateProgressInterval == 0 {
+			progress("repo.settings.lfs_migrate.progress_rewriting", i+1, len(commitIDs))
+		}
+		newCommitID, err := m.rewriteCommit(commitID)
+		if err != nil {
+			return nil, fmt.Errorf("rewriting commit %s failed: %w", commitID, err)
+		}
+		if newCommitID != commitID {
+			result.Commits++
+		}
+	}
+	result.Files = m.files
+
+	for i, branch := range opts.Branches {
+		newCommitID := m.commits[heads[i]]
+		if newCommitID == heads[i] {
+			continue
+		}
+
+		progress("repo.settings.lfs_migrate.progress_pushing", branch)
+		if err := git.NewCommand(ctx, "update-ref").AddDynamicArguments(opts.BackupRefPrefix+branch, heads[i]).Run(&git.RunOpts{Dir: repo.RepoPath()}); err != nil {
+			return nil, fmt.Errorf("creating the backup ref of branch %s failed: %w", branch, err)
+		}
+		if err := git.Push(ctx, tmpBasePath, git.PushOptions{
+			Remote:         repo.RepoPath(),
+			Branch:         newCommitID + ":" + git.BranchPrefix + branch,
+			Force:          true,
+			ForceWithLease: git.BranchPrefix + branch + ":" + heads[i],
+			Env:            repo_module.PushingEnvironment(doer, repo),
+		}); err != nil {
+			return nil, fmt.Errorf("pushing branch %s failed: %w", branch, err)
+		}
+	}
+	return result, nil
+}
+
+type lfsMigrator struct {
+	ctx          context.Context
+	repo         *repo_model.Repository
+	gitRepo      *git.Repository
+	patterns     []*lfsPattern
+	attributes   []string
+	contentStore *lfs.ContentStore
+
+	// the ids of the rewritten objects by the ids of the original objects
+	blobs        map[string]string
+	trees        map[string]string
+	commits      map[string]string
+	attributeIDs map[string]string
+
+	files int
+}
+
+func (m *lfsMigrator) match(treePath string) bool {
+	for _, pattern := range m.patterns {
+		if pattern.Match(treePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteCommit writes the commit again with the rewritten tree and parents, the signature of a changed commit is dropped
+func (m *lfsMigrator) rewriteCommit(commitID string) (string, error) {
+	raw, _, err := git.NewCommand(m.ctx, "cat-file", "commit").AddDynamicArguments(commitID).RunStdBytes(&git.RunOpts{Dir: m.gitRepo.Path})
+	if err != nil {
+		return "", err
+	}
+
+	header, message, _ := bytes.Cut(raw, []byte("\n\n"))
+	var buf bytes.Buffer
+	changed := false
+	inSignature := false
+	for _, line := range strings.Split(string(header), "\n") {
+		if inSignature && strings.HasPrefix(line, " ") {
+			continue
+		}
+		inSignature = false
+
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			newTreeID, err := m.rewriteTree(value, "", true)
+			if err != nil {
+				return "", err
+			}
+			changed = changed || newTreeID != value
+			value = newTreeID
+		case "parent":
+			if newParentID, ok := m.commits[value]; ok {
+				changed = changed || newParentID != value
+				value = newParentID
+			}
+		case "gpgsig", "gpgsig-sha256":
+			// the signature isn't valid for a changed commit
+			inSignature = true
+			continue
+		}
+		buf.WriteString(key + " " + value + "\n")
+	}
+
+	newCommitID := commitID
+	if changed {
+		buf.WriteString("\n")
+		buf.Write(message)
+		stdout, _, err := git.NewCommand(m.ctx, "hash-object", "-t", "commit", "-w", "--stdin").RunStdString(&git.RunOpts{
+			Dir:   m.gitRepo.Path,
+			Stdin: &buf,
+		})
+		if err != nil {
+			return "", err
+		}
+		newCommitID = strings.TrimSpace(stdout)
+	}
+	m.commits[commitID] = newCommitID
+	return newCommitID, nil
+}
+
+// rewriteTree writes the tree again with the migrated files, the .gitattributes of the root tree tracks the patterns
+func (m *lfsMigrator) rewriteTree(treeID, treePath string, isRoot bool) (string, error) {
+	key := treeID + ":" + treePath
+	if newTreeID, ok := m.trees[key]; ok {
+		return newTreeID, nil
+	}
+
+	tree, err := m.gitRepo.GetTree(treeID)
+	if err != nil {
+		return "", err
+	}
+	entries, err := tree.ListEntries()
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	changed := false
+	hasAttributes := false
+	for _, entry := range entries {
+		entryID := entry.ID.String()
+		entryPath := path.Join(treePath, entry.Name())
+
+		newEntryID := entryID
+		switch {
+		case entry.IsDir():
+			newEntryID, err = m.rewriteTree(entryID, entryPath, false)
+		case isRoot && entry.Name() == lfsAttributesFilename && (entry.IsRegular() || entry.IsExecutable()):
+			hasAttributes = true
+			newEntryID, err = m.rewriteAttributes(entryID)
+		case (entry.IsRegular() || entry.IsExecutable()) && m.match(entryPath):
+			newEntryID, err = m.migrateBlob(entryID)
+		}
+		if err != nil {
+			return "", err
+		}
+		if newEntryID != entryID {
+			changed = true
+		}
+		fmt.Fprintf(&buf, "%s %s %s\t%s\x00", entry.Mode(), entry.Type(), newEntryID, entry.Name())
+	}
+	if isRoot && !hasAttributes {
+		attributesID, err := m.rewriteAttributes("")
+		if err != nil {
+			return "", err
+		}
+		changed = true
+		fmt.Fprintf(&buf, "%s blob %s\t%s\x00", git.EntryModeBlob, attributesID, lfsAttributesFilename)
+	}
+
+	newTreeID := treeID
+	if changed {
+		stdout, _, err := git.NewCommand(m.ctx, "mktree", "-z").RunStdString(&git.RunOpts{
+			Dir:   m.gitRepo.Path,
+			Stdin: &buf,
+		})
+		if err != nil {
+			return "", err
+		}
+		newTreeID = strings.TrimSpace(stdout)
+	}
+	m.trees[key] = newTreeID
+	return newTreeID, nil
+}
+
+// rewriteAttributes adds the patterns which aren't tracked yet to the content of the .gitattributes blob
+func (m *lfsMigrator) rewriteAttributes(blobID string) (string, error) {
+	if newBlobID, ok := m.attributeIDs[blobID]; ok {
+		return newBlobID, nil
+	}
+
+	var content []byte
+	if blobID != "" {
+		blob, err := m.gitRepo.GetBlob(blobID)
+		if err != nil {
+			return "", err
+		}
+		rc, err := blob.DataAsync()
+		if err != nil {
+			return "", err
+		}
+		content, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	existing := make(container.Set[string])
+	for _, line := range strings.Split(string(content), "\n") {
+		existing.Add(strings.Join(strings.Fields(line), " "))
+	}
+
+	newContent := content
+	for _, pattern := range m.attributes {
+		line := pattern + " " + lfsAttributes
+		if existing.Contains(line) {
+			continue
+		}
+		if len(newContent) > 0 && newContent[len(newContent)-1] != '\n' {
+			newContent = append(newContent, '\n')
+		}
+		newContent = append(newContent, line+"\n"...)
+	}
+
+	newBlobID := blobID
+	if blobID == "" || len(newContent) != len(content) {
+		objectID, err := m.gitRepo.HashObject(bytes.NewReader(newContent))
+		if err != nil {
+			return "", err
+		}
+		newBlobID = objectID.String()
+	}
+	m.attributeIDs[blobID] = newBlobID
+	return newBlobID, nil
+}
+
+// migrateBlob stores the content of the blob in LFS and writes a blob with the pointer to it
+func (m *lfsMigrator) migrateBlob(blobID string) (string, error) {
+	if newBlobID, ok := m.blobs[blobID]; ok {
+		return newBlobID, nil
+	}
+
+	blob, err := m.gitRepo.GetBlob(blobID)
+	if err != nil {
+		return "", err
+	}
+
+	if blob.Size() <= lfs.MetaFileMaxSize {
+		content, err := blob.GetBlobContent(lfs.MetaFileMaxSize)
+		if err != nil {
+			return "", err
+		}
+		if _, err := lfs.ReadPointerFromBuffer([]byte(content)); err == nil {
+			// the file is stored in LFS already
+			m.blobs[blobID] = blobID
+			return blobID, nil
+		}
+	}
+
+	rc, err := blob.DataAsync()
+	if err != nil {
+		return "", err
+	}
+	pointer, err := lfs.GeneratePointer(rc)
+	rc.Close()
+	if err != nil {
+		return "", err
+	}
+
+	exist, err := m.contentStore.Exists(pointer)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		rc, err := blob.DataAsync()
+		if err != nil {
+			return "", err
+		}
+		err = m.contentStore.Put(pointer, rc)
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	if _, err := git_model.NewLFSMetaObject(m.ctx, m.repo.ID, pointer); err != nil {
+		return "", err
+	}
+
+	objectID, err := m.gitRepo.HashObject(strings.NewReader(pointer.StringContent()))
+	if err != nil {
+		return "", err
+	}
+	m.files++
+	m.blobs[blobID] = objectID.String()
+	return m.blobs[blobID], nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MaintainRepository writes the commit-graph, with the Bloom filters of the changed paths, and the multi-pack-index
+// of the repository and records the status of the maintenance
+func MaintainRepository(ctx context.Context, repo *repo_model.Repository, trigger repo_model.RepoMaintenanceTrigger) error {
+	log.Trace("Running the maintenance of %-v", repo)
+	start := time.Now()
+
+	repoPath := repo.RepoPath()
+	err := git.WriteCommitGraphWithChangedPaths(ctx, repoPath)
+	if err == nil {
+		err = git.WriteMultiPackIndex(ctx, repoPath)
+	}
+
+	maintenance := &repo_model.RepoMaintenance{
+		RepoID:         repo.ID,
+		Trigger:        trigger,
+		DurationMillis: time.Since(start).Milliseconds(),
+		LastRunUnix:    timeutil.TimeStampNow(),
+	}
+	if err != nil {
+		log.Error("Repository maintenance failed for %-v: %v", repo, err)
+		maintenance.Error = err.Error()
+		if err := system_model.CreateRepositoryNotice("Repository maintenance failed for %s: %v", repo.FullName(), err); err != nil {
+			log.Error("CreateRepositoryNotice: %v", err)
+		}
+	}
+	if err := repo_model.SaveRepoMaintenance(ctx, maintenance); err != nil {
+		return fmt.Errorf("SaveRepoMaintenance: %w", err)
+	}
+	return err
+}
+
+// MaintainRepositories maintains the non-empty repositories whose git size is at least minSize
+func MaintainRepositories(ctx context.Context, minSize int64) error {
+	log.Trace("Doing: MaintainRepositories")
+
+	if err := db.Iterate(
+		ctx,
+		builder.Eq{"is_empty": false}.And(builder.Gte{"git_size": minSize}),
+		func(ctx context.Context, repo *repo_model.Repository) error {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before the maintenance of %s", repo.FullName())
+			default:
+			}
+			// the error is logged and recorded in the status of the maintenance
+			_ = MaintainRepository(ctx, repo, repo_model.RepoMaintenanceTriggerSchedule)
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: MaintainRepositories")
+	return nil
+}
+
+// repoMaintenanceQueue represents a queue to maintain the repositories after large pushes
+var repoMaintenanceQueue *queue.WorkerPoolQueue[int64]
+
+func handlerRepoMaintenance(items ...int64) []int64 {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, repoID := range items {
+		repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+		if err != nil {
+			if !repo_model.IsErrRepoNotExist(err) {
+				log.Error("GetRepositoryByID [%d] failed: %v", repoID, err)
+			}
+			continue
+		}
+		// the error is logged and recorded in the status of the maintenance
+		_ = MaintainRepository(ctx, repo, repo_model.RepoMaintenanceTriggerPush)
+	}
+	return nil
+}
+
+func addRepoToMaintenanceQueue(repoID int64) error {
+	return repoMaintenanceQueue.Push(repoID)
+}
+
+func initRepoMaintenanceQueue(ctx context.Context) error {
+	repoMaintenanceQueue = queue.CreateUniqueQueue(ctx, "repo_maintenance", handlerRepoMaintenance)
+	if repoMaintenanceQueue == nil {
+		return errors.New("unable to create repo_maintenance queue")
+	}
+	go graceful.GetManager().RunWithCancel(repoMaintenanceQueue)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintainRepository(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	require.NoError(t, git.NewCommand(db.DefaultContext, "repack", "-d").Run(&git.RunOpts{Dir: repo.RepoPath()}))
+
+	require.NoError(t, MaintainRepository(db.DefaultContext, repo, repo_model.RepoMaintenanceTriggerManual))
+	assert.FileExists(t, filepath.Join(repo.RepoPath(), "objects", "info", "commit-graph"))
+	assert.FileExists(t, filepath.Join(repo.RepoPath(), "objects", "pack", "multi-pack-index"))
+
+	maintenance, err := repo_model.GetRepoMaintenance(db.DefaultContext, repo.ID)
+	require.NoError(t, err)
+	require.NotNil(t, maintenance)
+	assert.Equal(t, repo_model.RepoMaintenanceTriggerManual, maintenance.Trigger)
+	assert.Empty(t, maintenance.Error)
+	assert.NotZero(t, maintenance.LastRunUnix)
+
+	// the status is updated in place
+	require.NoError(t, MaintainRepositories(db.DefaultContext, 0))
+	unittest.AssertCount(t, &repo_model.RepoMaintenance{RepoID: repo.ID}, 1)
+	maintenance, err = repo_model.GetRepoMaintenance(db.DefaultContext, repo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repo_model.RepoMaintenanceTriggerSchedule, maintenance.Trigger)
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/process"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// CheckManagedGitHook checks the ref updates of a push with the built-in checks and the hook scripts of the managed git hook,
+// it returns the message for the pusher if the push is rejected.
+// The env must give access to the quarantined objects of the push.
+func CheckManagedGitHook(ctx context.Context, repo *repo_model.Repository, hook *git_model.ManagedGitHook, oldCommitIDs, newCommitIDs []string, refFullNames []git.RefName, env []string) (string, error) {
+	repoPath := repo.RepoPath()
+
+	if hook.HasChecks() {
+		for i, refFullName := range refFullNames {
+			if git.IsEmptyCommitID(newCommitIDs[i]) {
+				continue
+			}
+			msg, err := checkManagedGitHookCommits(ctx, repoPath, hook, refFullName, newCommitIDs[i], env)
+			if err != nil || msg != "" {
+				return msg, err
+			}
+		}
+	}
+
+	// the scripts are git hooks, they are disabled with the git hooks of the repositories
+	if setting.DisableGitHooks {
+		return "", nil
+	}
+
+	if strings.TrimSpace(hook.PreReceiveScript) != "" {
+		var stdin strings.Builder
+		for i, refFullName := range refFullNames {
+			fmt.Fprintf(&stdin, "%s %s %s\n", oldCommitIDs[i], newCommitIDs[i], refFullName)
+		}
+		msg, err := runManagedGitHookScript(ctx, repoPath, "pre-receive", hook.PreReceiveScript, nil, stdin.String(), env)
+		if err != nil || msg != "" {
+			return msg, err
+		}
+	}
+
+	if strings.TrimSpace(hook.UpdateScript) != "" {
+		for i, refFullName := range refFullNames {
+			msg, err := runManagedGitHookScript(ctx, repoPath, "update", hook.UpdateScript, []string{refFullName.String(), oldCommitIDs[i], newCommitIDs[i]}, "", env)
+			if err != nil || msg != "" {
+				return msg, err
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// checkManagedGitHookCommits checks the commits which are new in the repository and reachable from the new commit of the ref
+func checkManagedGitHookCommits(ctx context.Context, repoPath string, hook *git_model.ManagedGitHook, refFullName git.RefName, newCommitID string, env []string) (string, error) {
+	if hook.CommitMessagePattern != "" {
+		stdout, _, err := git.NewCommand(ctx, "log", "-z", "--format=%H%n%B").AddDynamicArguments(newCommitID).AddArguments("--not", "--all").
+			RunStdBytes(&git.RunOpts{Dir: repoPath, Env: env})
+		if err != nil {
+			return "", fmt.Errorf("unable to list the new commits of %s: %w", refFullName, err)
+		}
+		for _, record := range bytes.Split(stdout, []byte{0}) {
+			commitID, message, _ := strings.Cut(string(record), "\n")
+			if commitID == "" {
+				continue
+			}
+			if !hook.IsValidCommitMessage(strings.TrimSpace(message)) {
+				return fmt.Sprintf("The message of commit %s pushed to %s doesn't match the pattern %q required by the organization.", commitID, refFullName.ShortName(), hook.CommitMessagePattern), nil
+			}
+		}
+	}
+
+	if hook.MaxFileSize > 0 || len(hook.ForbiddenPaths) > 0 {
+		objects, _, err := git.NewCommand(ctx, "rev-list", "--objects").AddDynamicArguments(newCommitID).AddArguments("--not", "--all").
+			RunStdBytes(&git.RunOpts{Dir: repoPath, Env: env})
+		if err != nil {
+			return "", fmt.Errorf("unable to list the new objects of %s: %w", refFullName, err)
+		}
+		if len(objects) == 0 {
+			return "", nil
+		}
+
+		// %(rest) outputs the path given after the object id by rev-list
+		stdout, _, err := git.NewCommand(ctx, "cat-file", "--batch-check=%(objecttype) %(objectsize) %(rest)").
+			RunStdBytes(&git.RunOpts{Dir: repoPath, Env: env, Stdin: bytes.NewReader(objects)})
+		if err != nil {
+			return "", fmt.Errorf("unable to check the new objects of %s: %w", refFullName, err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(stdout))
+		for scanner.Scan() {
+			fields := strings.SplitN(scanner.Text(), " ", 3)
+			if len(fields) != 3 || fields[0] != "blob" {
+				continue
+			}
+			size, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("unexpected object size %q: %w", fields[1], err)
+			}
+			path := fields[2]
+			if hook.IsForbiddenPath(path) {
+				return fmt.Sprintf("The file %s pushed to %s is in a path forbidden by the organization.", path, refFullName.ShortName()), nil
+			}
+			if hook.MaxFileSize > 0 && size > hook.MaxFileSize {
+				return fmt.Sprintf("The file %s pushed to %s is larger than the maximum size of %d bytes allowed by the organization.", path, refFullName.ShortName(), hook.MaxFileSize), nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// runManagedGitHookScript runs a hook script the way git would in the repository,
+// it returns the output of the script if it rejects the push.
+func runManagedGitHookScript(ctx context.Context, repoPath, hookName, script string, args []string, stdin string, env []string) (string, error) {
+	tmpDir, err := repo_module.CreateTemporaryPath("managed-hook")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = repo_module.RemoveTemporaryPath(tmpDir)
+	}()
+
+	scriptPath := filepath.Join(tmpDir, hookName)
+	if err := os.WriteFile(scriptPath, []byte(strings.ReplaceAll(script, "\r", "")), 0o700); err != nil {
+		return "", err
+	}
+
+	stdout, stderr, err := process.GetManager().ExecDirEnvStdIn(ctx, time.Duration(setting.Git.Timeout.Default)*time.Second, repoPath,
+		fmt.Sprintf("Managed %s hook: %s", hookName, repoPath), append(slices.Clip(env), "GIT_DIR="+repoPath), strings.NewReader(stdin), scriptPath, args...)
+	if err != nil {
+		var processErr *process.Error
+		if !errors.As(err, &processErr) {
+			return "", err
+		}
+		msg := strings.TrimSpace(stdout + stderr)
+		if msg == "" {
+			msg = fmt.Sprintf("The push has been rejected by the %s hook of the organization.", hookName)
+		}
+		return msg, nil
+	}
+	return "", nil
+}
@@ -92,9 +92,15 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 	}
 	defer gitRepo.Close()
 
+	oldGitSize := repo.GitSize
 	if err = repo_module.UpdateRepoSize(ctx, repo); err != nil {
 		return fmt.Errorf("Failed to update size for repository: %v", err)
 	}
+	if setting.Git.MaintenanceAfterPushSize > 0 && repo.GitSize-oldGitSize >= setting.Git.MaintenanceAfterPushSize {
+		if err := addRepoToMaintenanceQueue(repo.ID); err != nil {
+			log.Error("Unable to queue the maintenance of %-v: %v", repo, err)
+		}
+	}
 
 	addTags := make([]string, 0, len(optsList))
 	delTags := make([]string, 0, len(optsList))
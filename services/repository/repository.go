@@ -104,6 +104,9 @@ func Init(ctx context.Context) error {
 	if err := initPushQueue(); err != nil {
 		return err
 	}
+	if err := initRepoMaintenanceQueue(graceful.GetManager().ShutdownContext()); err != nil {
+		return err
+	}
 	return initBranchSyncQueue(graceful.GetManager().ShutdownContext())
 }
 
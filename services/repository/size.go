@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+
+	"xorm.io/builder"
+)
+
+// UpdateRepoSizeStat computes and saves the sizes of the data attributable to a repository besides its git and LFS objects
+func UpdateRepoSizeStat(ctx context.Context, repo *repo_model.Repository) (*repo_model.RepoSizeStat, error) {
+	stat := &repo_model.RepoSizeStat{RepoID: repo.ID}
+	var err error
+	if stat.AttachmentSize, err = repo_model.GetRepoAttachmentsSize(ctx, repo.ID); err != nil {
+		return nil, err
+	}
+	if stat.ArtifactSize, err = actions_model.GetRepoArtifactsSize(ctx, repo.ID); err != nil {
+		return nil, err
+	}
+	if stat.ActionLogSize, err = actions_model.GetRepoTaskLogsSize(ctx, repo.ID); err != nil {
+		return nil, err
+	}
+	if stat.PackageSize, err = packages_model.CalculateFileSize(ctx, &packages_model.PackageFileSearchOptions{RepoID: repo.ID}); err != nil {
+		return nil, err
+	}
+	return stat, repo_model.SaveRepoSizeStat(ctx, stat)
+}
+
+// UpdateRepositorySizes refreshes the git and LFS sizes and the size statistics of all the repositories
+func UpdateRepositorySizes(ctx context.Context) error {
+	log.Trace("Doing: UpdateRepositorySizes")
+
+	if err := db.Iterate(
+		ctx,
+		builder.Gt{"id": 0},
+		func(ctx context.Context, repo *repo_model.Repository) error {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before updating the size of %s", repo.FullName())
+			default:
+			}
+			if !repo.IsEmpty {
+				if err := repo_module.UpdateRepoSize(ctx, repo); err != nil {
+					log.Error("Unable to update the size of %-v: %v", repo, err)
+				}
+			}
+			if _, err := UpdateRepoSizeStat(ctx, repo); err != nil {
+				log.Error("Unable to update the size statistics of %-v: %v", repo, err)
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: UpdateRepositorySizes")
+	return nil
+}
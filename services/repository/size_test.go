@@ -0,0 +1,38 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateRepoSizeStat(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 4})
+	stat, err := repo_model.GetRepoSizeStat(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, stat)
+
+	stat, err = UpdateRepoSizeStat(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2*90179, stat.ActionLogSize)
+
+	// the statistics are updated in place
+	_, err = UpdateRepoSizeStat(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	unittest.AssertCount(t, &repo_model.RepoSizeStat{RepoID: repo.ID}, 1)
+
+	saved, err := repo_model.GetRepoSizeStat(db.DefaultContext, repo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, stat.ID, saved.ID)
+	assert.EqualValues(t, 2*90179, saved.ActionLogSize)
+	assert.NotZero(t, saved.UpdatedUnix)
+}
@@ -11,17 +11,21 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/sync"
 	"code.gitea.io/gitea/modules/util"
 	notify_service "code.gitea.io/gitea/services/notify"
+
+	"xorm.io/builder"
 )
 
 // repoWorkingPool represents a working pool to order the parallel changes to the same repository
@@ -170,6 +174,14 @@ func transferOwnership(ctx context.Context, doer *user_model.User, newOwnerName
 		collaboration.UserID = 0
 	}
 
+	// Custom properties and managed git hooks are defined by the owner, they don't apply to the new owner.
+	if _, err := sess.Delete(&repo_model.RepoCustomPropertyValue{RepoID: repo.ID}); err != nil {
+		return fmt.Errorf("remove custom property values: %w", err)
+	}
+	if _, err := sess.Delete(&git_model.ManagedGitHookOptOut{RepoID: repo.ID}); err != nil {
+		return fmt.Errorf("remove managed git hook opt-out: %w", err)
+	}
+
 	// Remove old team-repository relations.
 	if oldOwner.IsOrganization() {
 		if err := organization.RemoveOrgRepo(ctx, oldOwner.ID, repo.ID); err != nil {
@@ -237,6 +249,25 @@ func transferOwnership(ctx context.Context, doer *user_model.User, newOwnerName
 		) AS il_too)`, issues_model.CommentTypeLabel, repo.ID, newOwner.ID); err != nil {
 			return fmt.Errorf("Unable to remove old org label comments: %w", err)
 		}
+
+		// Remove the issues from the milestones of the old organization
+		var orgMilestoneIDs []int64
+		if err := sess.Table("milestone").Where("org_id = ?", oldOwner.ID).
+			And(builder.In("id", builder.Select("milestone_id").From("issue").Where(builder.Eq{"repo_id": repo.ID}))).
+			Cols("id").Find(&orgMilestoneIDs); err != nil {
+			return fmt.Errorf("find old org milestones: %w", err)
+		}
+		if len(orgMilestoneIDs) > 0 {
+			if _, err := sess.Table("issue").Where("repo_id = ?", repo.ID).In("milestone_id", orgMilestoneIDs).
+				Update(map[string]any{"milestone_id": 0}); err != nil {
+				return fmt.Errorf("Unable to remove old org milestones: %w", err)
+			}
+			for _, id := range orgMilestoneIDs {
+				if err := issues_model.UpdateMilestoneCounters(ctx, id); err != nil {
+					return fmt.Errorf("UpdateMilestoneCounters: %w", err)
+				}
+			}
+		}
 	}
 
 	// Rename remote repository to new path and delete local copy.
@@ -246,6 +277,7 @@ func transferOwnership(ctx context.Context, doer *user_model.User, newOwnerName
 		return fmt.Errorf("Failed to create dir %s: %w", dir, err)
 	}
 
+	git.CloseCatFileProcesses(repo_model.RepoPath(oldOwner.Name, repo.Name))
 	if err := util.Rename(repo_model.RepoPath(oldOwner.Name, repo.Name), repo_model.RepoPath(newOwner.Name, repo.Name)); err != nil {
 		return fmt.Errorf("rename repository directory: %w", err)
 	}
@@ -258,6 +290,7 @@ func transferOwnership(ctx context.Context, doer *user_model.User, newOwnerName
 		log.Error("Unable to check if %s exists. Error: %v", wikiPath, err)
 		return err
 	} else if isExist {
+		git.CloseCatFileProcesses(wikiPath)
 		if err := util.Rename(wikiPath, repo_model.WikiPath(newOwner.Name, repo.Name)); err != nil {
 			return fmt.Errorf("rename repository wiki: %w", err)
 		}
@@ -307,6 +340,7 @@ func changeRepositoryName(ctx context.Context, repo *repo_model.Repository, newR
 	}
 
 	newRepoPath := repo_model.RepoPath(repo.Owner.Name, newRepoName)
+	git.CloseCatFileProcesses(repo.RepoPath())
 	if err = util.Rename(repo.RepoPath(), newRepoPath); err != nil {
 		return fmt.Errorf("rename repository directory: %w", err)
 	}
@@ -318,6 +352,7 @@ func changeRepositoryName(ctx context.Context, repo *repo_model.Repository, newR
 		return err
 	}
 	if isExist {
+		git.CloseCatFileProcesses(wikiPath)
 		if err = util.Rename(wikiPath, repo_model.WikiPath(repo.Owner.Name, newRepoName)); err != nil {
 			return fmt.Errorf("rename repository wiki: %w", err)
 		}
@@ -5,41 +5,60 @@ package secrets
 
 import (
 	"context"
+	"fmt"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	secret_model "code.gitea.io/gitea/models/secret"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
 )
 
-func CreateOrUpdateSecret(ctx context.Context, ownerID, repoID int64, name, data string) (*secret_model.Secret, bool, error) {
+func CreateOrUpdateSecret(ctx context.Context, doer *user_model.User, ownerID, repoID int64, name, data string) (*secret_model.Secret, bool, error) {
+	return createOrUpdateSecret(ctx, doer, ownerID, repoID, "", name, data)
+}
+
+// CreateOrUpdateEnvironmentSecret creates or updates a secret bound to an environment of a repository
+func CreateOrUpdateEnvironmentSecret(ctx context.Context, doer *user_model.User, repoID int64, environment, name, data string) (*secret_model.Secret, bool, error) {
+	if err := ValidateEnvironmentName(environment); err != nil {
+		return nil, false, err
+	}
+	return createOrUpdateSecret(ctx, doer, 0, repoID, environment, name, data)
+}
+
+func createOrUpdateSecret(ctx context.Context, doer *user_model.User, ownerID, repoID int64, environment, name, data string) (*secret_model.Secret, bool, error) {
 	if err := ValidateName(name); err != nil {
 		return nil, false, err
 	}
 
 	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
-		OwnerID: ownerID,
-		RepoID:  repoID,
-		Name:    name,
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Name:        name,
+		Environment: environment,
 	})
 	if err != nil {
 		return nil, false, err
 	}
 
 	if len(s) == 0 {
-		s, err := secret_model.InsertEncryptedSecret(ctx, ownerID, repoID, name, data)
+		s, err := secret_model.InsertEncryptedSecret(ctx, ownerID, repoID, environment, name, data)
 		if err != nil {
 			return nil, false, err
 		}
+		auditSecretChange(doer, s, "created")
 		return s, true, nil
 	}
 
 	if err := secret_model.UpdateSecret(ctx, s[0].ID, data); err != nil {
 		return nil, false, err
 	}
+	auditSecretChange(doer, s[0], "updated")
 
 	return s[0], false, nil
 }
 
-func DeleteSecretByID(ctx context.Context, ownerID, repoID, secretID int64) error {
+func DeleteSecretByID(ctx context.Context, doer *user_model.User, ownerID, repoID, secretID int64) error {
 	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
 		OwnerID:  ownerID,
 		RepoID:   repoID,
@@ -52,18 +71,31 @@ func DeleteSecretByID(ctx context.Context, ownerID, repoID, secretID int64) erro
 		return secret_model.ErrSecretNotFound{}
 	}
 
-	return deleteSecret(ctx, s[0])
+	return deleteSecret(ctx, doer, s[0])
+}
+
+func DeleteSecretByName(ctx context.Context, doer *user_model.User, ownerID, repoID int64, name string) error {
+	return deleteSecretByName(ctx, doer, ownerID, repoID, "", name)
 }
 
-func DeleteSecretByName(ctx context.Context, ownerID, repoID int64, name string) error {
+// DeleteEnvironmentSecretByName deletes a secret bound to an environment of a repository
+func DeleteEnvironmentSecretByName(ctx context.Context, doer *user_model.User, repoID int64, environment, name string) error {
+	if err := ValidateEnvironmentName(environment); err != nil {
+		return err
+	}
+	return deleteSecretByName(ctx, doer, 0, repoID, environment, name)
+}
+
+func deleteSecretByName(ctx context.Context, doer *user_model.User, ownerID, repoID int64, environment, name string) error {
 	if err := ValidateName(name); err != nil {
 		return err
 	}
 
 	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
-		OwnerID: ownerID,
-		RepoID:  repoID,
-		Name:    name,
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		Name:        name,
+		Environment: environment,
 	})
 	if err != nil {
 		return err
@@ -72,12 +104,29 @@ func DeleteSecretByName(ctx context.Context, ownerID, repoID int64, name string)
 		return secret_model.ErrSecretNotFound{}
 	}
 
-	return deleteSecret(ctx, s[0])
+	return deleteSecret(ctx, doer, s[0])
 }
 
-func deleteSecret(ctx context.Context, s *secret_model.Secret) error {
+func deleteSecret(ctx context.Context, doer *user_model.User, s *secret_model.Secret) error {
 	if _, err := db.DeleteByID[secret_model.Secret](ctx, s.ID); err != nil {
 		return err
 	}
+	auditSecretChange(doer, s, "deleted")
 	return nil
 }
+
+// auditSecretChange logs who changed a secret and where it's defined, the value of the secret is never logged
+func auditSecretChange(doer *user_model.User, s *secret_model.Secret, action string) {
+	var scope string
+	switch s.Scope() {
+	case actions_model.VariableScopeInstance:
+		scope = "the instance"
+	case actions_model.VariableScopeOwner:
+		scope = fmt.Sprintf("the owner %d", s.OwnerID)
+	case actions_model.VariableScopeRepository:
+		scope = fmt.Sprintf("the repository %d", s.RepoID)
+	case actions_model.VariableScopeEnvironment:
+		scope = fmt.Sprintf("the environment %q of the repository %d", s.Environment, s.RepoID)
+	}
+	log.Info("Actions secret %s of %s %s by %s (user %d)", s.Name, scope, action, doer.Name, doer.ID)
+}
@@ -5,6 +5,7 @@ package secrets
 
 import (
 	"regexp"
+	"strings"
 
 	"code.gitea.io/gitea/modules/util"
 )
@@ -23,3 +24,13 @@ func ValidateName(name string) error {
 	}
 	return nil
 }
+
+// ErrInvalidEnvironmentName is returned for the names of environments which are empty, too long or contain a slash or an expression
+var ErrInvalidEnvironmentName = util.NewInvalidArgumentErrorf("invalid environment name")
+
+func ValidateEnvironmentName(name string) error {
+	if name == "" || len(name) > 255 || strings.TrimSpace(name) != name || strings.ContainsAny(name, "/\\") || strings.Contains(name, "${{") {
+		return ErrInvalidEnvironmentName
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package task
+
+import (
+	"context"
+	"fmt"
+
+	admin_model "code.gitea.io/gitea/models/admin"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// LFSMigrateBackupRefPrefix returns the prefix of the refs keeping the previous heads of the branches rewritten by the task
+func LFSMigrateBackupRefPrefix(taskID int64) string {
+	return fmt.Sprintf("refs/lfs-migrate-backup/%d/", taskID)
+}
+
+// MigrateRepositoryToLFS adds a task migrating the files in the history of the repository to LFS
+func MigrateRepositoryToLFS(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, opts repo_service.MigrateToLFSOptions) (*admin_model.Task, error) {
+	task, err := admin_model.GetLatestTask(ctx, repo.ID, structs.TaskTypeMigrateLFS)
+	if err != nil && !admin_model.IsErrTaskDoesNotExist(err) {
+		return nil, err
+	}
+	if task != nil && (task.Status == structs.TaskStatusQueued || task.Status == structs.TaskStatusRunning) {
+		return nil, util.NewAlreadyExistErrorf("a migration of files to LFS is in progress")
+	}
+
+	if err := repo_service.ValidateMigrateToLFS(ctx, repo, &opts); err != nil {
+		return nil, err
+	}
+
+	bs, err := json.Marshal(&opts)
+	if err != nil {
+		return nil, err
+	}
+	task = &admin_model.Task{
+		DoerID:         doer.ID,
+		OwnerID:        repo.OwnerID,
+		RepoID:         repo.ID,
+		Type:           structs.TaskTypeMigrateLFS,
+		Status:         structs.TaskStatusQueued,
+		PayloadContent: string(bs),
+	}
+	if err := admin_model.CreateTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task, taskQueue.Push(task)
+}
+
+func runLFSMigrateTask(ctx context.Context, t *admin_model.Task) (err error) {
+	defer func(ctx context.Context) {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("PANIC whilst trying to do LFS migrate task: %v", e)
+			log.Critical("PANIC during runLFSMigrateTask[%d] by DoerID[%d] to RepoID[%d]: %v\nStacktrace: %v", t.ID, t.DoerID, t.RepoID, e, log.Stack(2))
+		}
+		t.EndTime = timeutil.TimeStampNow()
+		if err == nil {
+			t.Status = structs.TaskStatusFinished
+		} else {
+			log.Error("runLFSMigrateTask[%d] by DoerID[%d] to RepoID[%d] failed: %v", t.ID, t.DoerID, t.RepoID, err)
+			t.Status = structs.TaskStatusFailed
+			t.Message = err.Error()
+		}
+		if err := t.UpdateCols(ctx, "status", "message", "end_time"); err != nil {
+			log.Error("Task UpdateCols failed: %v", err)
+		}
+	}(graceful.GetManager().ShutdownContext()) // even if the parent ctx is canceled, this defer-function still needs to update the task record in database
+
+	if err = t.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if err = t.LoadDoer(ctx); err != nil {
+		return err
+	}
+
+	var opts repo_service.MigrateToLFSOptions
+	if err = json.Unmarshal([]byte(t.PayloadContent), &opts); err != nil {
+		return err
+	}
+	opts.BackupRefPrefix = LFSMigrateBackupRefPrefix(t.ID)
+
+	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().ShutdownContext(), fmt.Sprintf("LFSMigrateTask: %s", t.Repo.FullName()))
+	defer finished()
+
+	t.StartTime = timeutil.TimeStampNow()
+	t.Status = structs.TaskStatusRunning
+	if err = t.UpdateCols(ctx, "start_time", "status"); err != nil {
+		return err
+	}
+
+	setMessage := func(format string, args ...any) {
+		bs, _ := json.Marshal(admin_model.TranslatableMessage{
+			Format: format,
+			Args:   args,
+		})
+		t.Message = string(bs)
+		_ = t.UpdateCols(ctx, "message")
+	}
+
+	result, err := repo_service.MigrateToLFS(ctx, t.Doer, t.Repo, &opts, setMessage)
+	if err != nil {
+		return err
+	}
+
+	log.Trace("Files of repository [%d] migrated to LFS: %d files in %d commits", t.RepoID, result.Files, result.Commits)
+	setMessage("repo.settings.lfs_migrate.finished", result.Files, result.Commits)
+	return nil
+}
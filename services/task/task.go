@@ -32,6 +32,8 @@ func Run(ctx context.Context, t *admin_model.Task) error {
 	switch t.Type {
 	case structs.TaskTypeMigrateRepo:
 		return runMigrateTask(ctx, t)
+	case structs.TaskTypeMigrateLFS:
+		return runLFSMigrateTask(ctx, t)
 	default:
 		return fmt.Errorf("Unknown task type: %d", t.Type)
 	}
@@ -107,6 +107,27 @@ func (ns *notificationService) IssueChangeStatus(ctx context.Context, doer *user
 		NotificationAuthorID: doer.ID,
 		CommentID:            actionComment.ID,
 	})
+	if isClosed {
+		ns.notifyBlockedIssues(ctx, doer, issue)
+	}
+}
+
+// notifyBlockedIssues notifies the watchers of the open issues blocked by the closed issue, in any repository
+func (ns *notificationService) notifyBlockedIssues(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) {
+	blockedIssues, err := issues_model.GetOpenIssuesBlockedBy(ctx, issue.ID)
+	if err != nil {
+		log.Error("GetOpenIssuesBlockedBy: %v", err)
+		return
+	}
+	for _, blocked := range blockedIssues {
+		if !blocked.Repo.IsDependenciesEnabled(ctx) {
+			continue
+		}
+		_ = ns.issueQueue.Push(issueNotificationOpts{
+			IssueID:              blocked.ID,
+			NotificationAuthorID: doer.ID,
+		})
+	}
 }
 
 func (ns *notificationService) IssueChangeTitle(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldTitle string) {
@@ -127,6 +148,7 @@ func (ns *notificationService) MergePullRequest(ctx context.Context, doer *user_
 		IssueID:              pr.Issue.ID,
 		NotificationAuthorID: doer.ID,
 	})
+	ns.notifyBlockedIssues(ctx, doer, pr.Issue)
 }
 
 func (ns *notificationService) AutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
@@ -53,6 +53,16 @@ func deleteUser(ctx context.Context, u *user_model.User, purge bool) (err error)
 	}
 	// ***** END: Star *****
 
+	// ***** START: IssueVote *****
+	votedIssueIDs, err := db.FindIDs(ctx, "issue_vote", "issue_vote.issue_id",
+		builder.Eq{"issue_vote.user_id": u.ID})
+	if err != nil {
+		return fmt.Errorf("get all issue votes: %w", err)
+	} else if err = db.DecrByIDs(ctx, votedIssueIDs, "num_votes", new(issues_model.Issue)); err != nil {
+		return fmt.Errorf("decrease issue num_votes: %w", err)
+	}
+	// ***** END: IssueVote *****
+
 	// ***** START: Follow *****
 	followeeIDs, err := db.FindIDs(ctx, "follow", "follow.follow_id",
 		builder.Eq{"follow.user_id": u.ID})
@@ -77,6 +87,7 @@ func deleteUser(ctx context.Context, u *user_model.User, purge bool) (err error)
 		&access_model.Access{UserID: u.ID},
 		&repo_model.Watch{UserID: u.ID},
 		&repo_model.Star{UID: u.ID},
+		&repo_model.RepoBisectSession{UserID: u.ID},
 		&user_model.Follow{UserID: u.ID},
 		&user_model.Follow{FollowID: u.ID},
 		&activities_model.Action{UserID: u.ID},
@@ -84,6 +95,8 @@ func deleteUser(ctx context.Context, u *user_model.User, purge bool) (err error)
 		&user_model.EmailAddress{UID: u.ID},
 		&user_model.UserOpenID{UID: u.ID},
 		&issues_model.Reaction{UserID: u.ID},
+		&issues_model.IssueVote{UserID: u.ID},
+		&issues_model.IssueFilter{UserID: u.ID},
 		&organization.TeamUser{UID: u.ID},
 		&issues_model.Stopwatch{UserID: u.ID},
 		&user_model.Setting{UserID: u.ID},
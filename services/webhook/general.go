@@ -303,6 +303,9 @@ func getPackagePayloadInfo(p *api.PackagePayload, linkFormatter linkFormatter, w
 	case api.HookPackageDeleted:
 		text = fmt.Sprintf("Package deleted: %s", refLink)
 		color = redColor
+	case api.HookPackageRemoved:
+		text = fmt.Sprintf("Package removed with %d versions: %s", len(p.Versions), linkFormatter(p.Package.HTMLURL, p.Package.Name))
+		color = redColor
 	}
 	if withSender {
 		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
@@ -342,5 +345,6 @@ func ToHook(repoLink string, w *webhook_model.Webhook) (*api.Hook, error) {
 		Updated:             w.UpdatedUnix.AsTime(),
 		Created:             w.CreatedUnix.AsTime(),
 		BranchFilter:        w.BranchFilter,
+		PackageTypeFilter:   w.PackageTypeFilter,
 	}, nil
 }
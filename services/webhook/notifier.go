@@ -586,6 +586,11 @@ func (m *webhookNotifier) IssueChangeMilestone(ctx context.Context, doer *user_m
 }
 
 func (m *webhookNotifier) PushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if opts.SkipWebhooks {
+		log.Trace("repo %s: skipped the webhooks of the push to %s", repo.FullName(), opts.RefFullName)
+		return
+	}
+
 	apiPusher := convert.ToUser(ctx, pusher, nil)
 	apiCommits, apiHeadCommit, err := commits.ToAPIPayloadCommits(ctx, repo.RepoPath(), repo.HTMLURL())
 	if err != nil {
@@ -604,6 +609,7 @@ func (m *webhookNotifier) PushCommits(ctx context.Context, pusher *user_model.Us
 		Repo:         convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeOwner}),
 		Pusher:       apiPusher,
 		Sender:       apiPusher,
+		PushOptions:  opts.PushOptions,
 	}); err != nil {
 		log.Error("PrepareWebhooks: %v", err)
 	}
@@ -860,14 +866,34 @@ func (m *webhookNotifier) SyncDeleteRef(ctx context.Context, pusher *user_model.
 }
 
 func (m *webhookNotifier) PackageCreate(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
-	notifyPackage(ctx, doer, pd, api.HookPackageCreated)
+	notifyPackage(ctx, doer, pd, api.HookPackageCreated, func(p *api.PackagePayload) {
+		p.Files = make([]*api.PackageFile, 0, len(pd.Files))
+		for _, pfd := range pd.Files {
+			p.Files = append(p.Files, convert.ToPackageFile(pfd))
+		}
+	})
 }
 
 func (m *webhookNotifier) PackageDelete(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
-	notifyPackage(ctx, doer, pd, api.HookPackageDeleted)
+	notifyPackage(ctx, doer, pd, api.HookPackageDeleted, nil)
+}
+
+func (m *webhookNotifier) PackageRemove(ctx context.Context, doer *user_model.User, pds []*packages_model.PackageDescriptor) {
+	if len(pds) == 0 {
+		return
+	}
+
+	notifyPackage(ctx, doer, pds[0], api.HookPackageRemoved, func(p *api.PackagePayload) {
+		p.Package.Version = ""
+		p.Package.HTMLURL = pds[0].PackageHTMLURL()
+		p.Versions = make([]string, 0, len(pds))
+		for _, pd := range pds {
+			p.Versions = append(p.Versions, pd.Version.Version)
+		}
+	})
 }
 
-func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_model.PackageDescriptor, action api.HookPackageAction) {
+func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_model.PackageDescriptor, action api.HookPackageAction, fill func(*api.PackagePayload)) {
 	source := EventSource{
 		Repository: pd.Repository,
 		Owner:      pd.Owner,
@@ -879,11 +905,20 @@ func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_mo
 		return
 	}
 
-	if err := PrepareWebhooks(ctx, source, webhook_module.HookEventPackage, &api.PackagePayload{
-		Action:  action,
-		Package: apiPackage,
-		Sender:  convert.ToUser(ctx, sender, nil),
-	}); err != nil {
+	payload := &api.PackagePayload{
+		Action:     action,
+		Repository: apiPackage.Repository,
+		Package:    apiPackage,
+		Sender:     convert.ToUser(ctx, sender, nil),
+	}
+	if pd.Owner.IsOrganization() {
+		payload.Organization = convert.ToUser(ctx, pd.Owner, nil)
+	}
+	if fill != nil {
+		fill(payload)
+	}
+
+	if err := PrepareWebhooks(ctx, source, webhook_module.HookEventPackage, payload); err != nil {
 		log.Error("PrepareWebhooks: %v", err)
 	}
 }
@@ -114,18 +114,26 @@ func enqueueHookTask(taskID int64) error {
 }
 
 func checkBranch(w *webhook_model.Webhook, branch string) bool {
-	if w.BranchFilter == "" || w.BranchFilter == "*" {
+	return matchFilter(w.BranchFilter, branch)
+}
+
+func checkPackageType(w *webhook_model.Webhook, packageType string) bool {
+	return matchFilter(w.PackageTypeFilter, packageType)
+}
+
+func matchFilter(filter, value string) bool {
+	if filter == "" || filter == "*" {
 		return true
 	}
 
-	g, err := glob.Compile(w.BranchFilter)
+	g, err := glob.Compile(filter)
 	if err != nil {
-		// should not really happen as BranchFilter is validated
-		log.Error("CheckBranch failed: %s", err)
+		// should not really happen as the filters are validated
+		log.Error("glob.Compile(%q) failed: %s", filter, err)
 		return false
 	}
 
-	return g.Match(branch)
+	return g.Match(value)
 }
 
 // PrepareWebhook creates a hook task and enqueues it for processing.
@@ -164,6 +172,13 @@ func PrepareWebhook(ctx context.Context, w *webhook_model.Webhook, event webhook
 		}
 	}
 
+	if packagePayload, ok := p.(*api.PackagePayload); ok && packagePayload.Package != nil {
+		if !checkPackageType(w, packagePayload.Package.Type) {
+			log.Info("Package type %q doesn't match package type filter %q, skipping", packagePayload.Package.Type, w.PackageTypeFilter)
+			return nil
+		}
+	}
+
 	payload, err := p.JSONPayload()
 	if err != nil {
 		return fmt.Errorf("JSONPayload for %s: %w", event, err)
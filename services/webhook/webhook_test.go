@@ -77,3 +77,28 @@ func TestPrepareWebhooksBranchFilterNoMatch(t *testing.T) {
 		unittest.AssertNotExistsBean(t, hookTask)
 	}
 }
+
+func TestPrepareWebhookPackageTypeFilter(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	w := unittest.AssertExistsAndLoadBean(t, &webhook_model.Webhook{ID: 1})
+	w.HookEvent = &webhook_module.HookEvent{
+		SendEverything:    true,
+		PackageTypeFilter: "{npm,container}",
+	}
+
+	hookTask := &webhook_model.HookTask{HookID: 1, EventType: webhook_module.HookEventPackage}
+	unittest.AssertNotExistsBean(t, hookTask)
+
+	assert.NoError(t, PrepareWebhook(db.DefaultContext, w, webhook_module.HookEventPackage, &api.PackagePayload{
+		Action:  api.HookPackageCreated,
+		Package: &api.Package{Type: "maven", Name: "test"},
+	}))
+	unittest.AssertNotExistsBean(t, hookTask)
+
+	assert.NoError(t, PrepareWebhook(db.DefaultContext, w, webhook_module.HookEventPackage, &api.PackagePayload{
+		Action:  api.HookPackageCreated,
+		Package: &api.Package{Type: "npm", Name: "test"},
+	}))
+	unittest.AssertExistsAndLoadBean(t, hookTask)
+}
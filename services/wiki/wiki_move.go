@@ -0,0 +1,242 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package wiki
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/util"
+	asymkey_service "code.gitea.io/gitea/services/asymkey"
+)
+
+// commitWikiChanges applies the changes made by the callback to the index of a temporary clone of an existing wiki
+// and pushes them as a single commit to the default wiki branch.
+func commitWikiChanges(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, message string, change func(gitRepo *git.Repository, head *git.Commit) error) error {
+	if err := repo.MustNotBeArchived(); err != nil {
+		return err
+	}
+
+	wikiWorkingPool.CheckIn(fmt.Sprint(repo.ID))
+	defer wikiWorkingPool.CheckOut(fmt.Sprint(repo.ID))
+
+	if !repo.HasWiki() || !git.IsBranchExist(ctx, repo.WikiPath(), repo.DefaultWikiBranch) {
+		return os.ErrNotExist
+	}
+
+	basePath, err := repo_module.CreateTemporaryPath("update-wiki")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := repo_module.RemoveTemporaryPath(basePath); err != nil {
+			log.Error("Merge: RemoveTemporaryPath: %s", err)
+		}
+	}()
+
+	if err := git.Clone(ctx, repo.WikiPath(), basePath, git.CloneRepoOptions{
+		Bare:   true,
+		Shared: true,
+		Branch: repo.DefaultWikiBranch,
+	}); err != nil {
+		log.Error("Failed to clone repository: %s (%v)", repo.FullName(), err)
+		return fmt.Errorf("failed to clone repository: %s (%w)", repo.FullName(), err)
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, basePath)
+	if err != nil {
+		log.Error("Unable to open temporary repository: %s (%v)", basePath, err)
+		return fmt.Errorf("failed to open new temporary repository in: %s %w", basePath, err)
+	}
+	defer gitRepo.Close()
+
+	if err := gitRepo.ReadTreeToIndex("HEAD"); err != nil {
+		log.Error("Unable to read HEAD tree to index in: %s %v", basePath, err)
+		return fmt.Errorf("unable to read HEAD tree to index in: %s %w", basePath, err)
+	}
+
+	head, err := gitRepo.GetBranchCommit(repo.DefaultWikiBranch)
+	if err != nil {
+		return err
+	}
+
+	if err := change(gitRepo, head); err != nil {
+		return err
+	}
+
+	// FIXME: The wiki doesn't have lfs support at present - if this changes need to check attributes here
+
+	tree, err := gitRepo.WriteTree()
+	if err != nil {
+		return err
+	}
+	commitTreeOpts := git.CommitTreeOpts{
+		Message: message,
+		Parents: []string{"HEAD"},
+	}
+
+	committer := doer.NewGitSig()
+
+	sign, signingKey, signer, _ := asymkey_service.SignWikiCommit(ctx, repo, doer)
+	if sign {
+		commitTreeOpts.KeyID = signingKey
+		if repo.GetTrustModel() == repo_model.CommitterTrustModel || repo.GetTrustModel() == repo_model.CollaboratorCommitterTrustModel {
+			committer = signer
+		}
+	} else {
+		commitTreeOpts.NoGPGSign = true
+	}
+
+	commitHash, err := gitRepo.CommitTree(doer.NewGitSig(), committer, tree, commitTreeOpts)
+	if err != nil {
+		return err
+	}
+
+	if err := git.Push(gitRepo.Ctx, basePath, git.PushOptions{
+		Remote: DefaultRemote,
+		Branch: fmt.Sprintf("%s:%s%s", commitHash.String(), git.BranchPrefix, repo.DefaultWikiBranch),
+		Env: repo_module.FullPushingEnvironment(
+			doer,
+			doer,
+			repo,
+			repo.Name+".wiki",
+			0,
+		),
+	}); err != nil {
+		if git.IsErrPushOutOfDate(err) || git.IsErrPushRejected(err) {
+			return err
+		}
+		return fmt.Errorf("Push: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceWikiLinks rewrites the links to the old page in the content of another page so they point to the new page.
+// Both markdown links to the web path and [[wiki links]] to the title or web path of the page are handled.
+func ReplaceWikiLinks(content string, oldWikiName, newWikiName WebPath) string {
+	oldURL := regexp.QuoteMeta(WebPathToURLPath(oldWikiName))
+	newURL := WebPathToURLPath(newWikiName)
+	_, oldTitle := WebPathToUserTitle(oldWikiName)
+	_, newTitle := WebPathToUserTitle(newWikiName)
+
+	// [text](Old-Page), [text](./Old-Page#anchor), [text](/owner/repo/wiki/Old-Page)
+	markdownLink := regexp.MustCompile(`(\]\((?:[^()\s]*/wiki/|\./)?)` + oldURL + `((?:#[^()\s]*)?\))`)
+	content = markdownLink.ReplaceAllStringFunc(content, func(s string) string {
+		m := markdownLink.FindStringSubmatch(s)
+		return m[1] + newURL + m[2]
+	})
+
+	// [[Old Page]], [[text|Old-Page]]
+	wikiLink := regexp.MustCompile(`(\[\[(?:[^\[\]|]*\|)?\s*)(?:` + regexp.QuoteMeta(oldTitle) + `|` + oldURL + `)(\s*\]\])`)
+	return wikiLink.ReplaceAllStringFunc(content, func(s string) string {
+		m := wikiLink.FindStringSubmatch(s)
+		return m[1] + newTitle + m[2]
+	})
+}
+
+// RenameWikiPage moves a wiki page to a new name, optionally updating the links to it in the other pages.
+func RenameWikiPage(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, oldWikiName, newWikiName WebPath, message string, updateLinks bool) error {
+	if err := validateWebPath(newWikiName); err != nil {
+		return err
+	}
+
+	return commitWikiChanges(ctx, doer, repo, message, func(gitRepo *git.Repository, head *git.Commit) error {
+		found, oldPath, err := prepareGitPath(gitRepo, repo.DefaultWikiBranch, oldWikiName)
+		if err != nil {
+			return err
+		} else if !found {
+			return os.ErrNotExist
+		}
+		found, newPath, err := prepareGitPath(gitRepo, repo.DefaultWikiBranch, newWikiName)
+		if err != nil {
+			return err
+		} else if found {
+			return repo_model.ErrWikiAlreadyExist{Title: newPath}
+		}
+
+		entry, err := head.GetTreeEntryByPath(oldPath)
+		if err != nil {
+			return err
+		}
+		if err := gitRepo.RemoveFilesFromIndex(oldPath); err != nil {
+			return err
+		}
+		if err := gitRepo.AddObjectToIndex("100644", entry.ID, newPath); err != nil {
+			return err
+		}
+		if !updateLinks {
+			return nil
+		}
+
+		entries, err := head.Tree.ListEntriesRecursiveFast()
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !e.IsRegular() || !strings.HasSuffix(e.Name(), ".md") {
+				continue
+			}
+			filePath := e.Name()
+			if filePath == oldPath {
+				filePath = newPath
+			}
+			content, err := e.Blob().GetBlobContent(e.Blob().Size())
+			if err != nil {
+				return err
+			}
+			replaced := ReplaceWikiLinks(content, oldWikiName, newWikiName)
+			if replaced == content {
+				continue
+			}
+			objectHash, err := gitRepo.HashObject(strings.NewReader(replaced))
+			if err != nil {
+				return err
+			}
+			if err := gitRepo.AddObjectToIndex("100644", objectHash, filePath); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreWikiPage replaces the content of a wiki page with its content at the given revision.
+func RestoreWikiPage(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, wikiName WebPath, revision, message string) error {
+	wikiRepo, err := gitrepo.OpenWikiRepository(ctx, repo)
+	if err != nil {
+		return err
+	}
+	defer wikiRepo.Close()
+
+	commit, err := wikiRepo.GetCommit(revision)
+	if err != nil {
+		return err
+	}
+	entry, err := commit.GetTreeEntryByPath(WebPathToGitPath(wikiName))
+	if git.IsErrNotExist(err) {
+		entry, err = commit.GetTreeEntryByPath(string(wikiName) + ".md")
+	}
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return util.NewNotExistErrorf("wiki page %q doesn't exist at revision %s", wikiName, revision)
+		}
+		return err
+	}
+	content, err := entry.Blob().GetBlobContent(entry.Blob().Size())
+	if err != nil {
+		return err
+	}
+
+	return EditWikiPage(ctx, doer, repo, wikiName, wikiName, content, message)
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package wiki
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	"code.gitea.io/gitea/modules/indexer/code/gitgrep"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// SearchResult a wiki page matching a search
+type SearchResult struct {
+	*code_indexer.Result
+	WebPath WebPath
+	SubURL  string
+	Title   string
+}
+
+// SearchWikiPages searches the pages of a repository wiki, with the wiki indexer if it is enabled or with "git grep" otherwise
+func SearchWikiPages(ctx context.Context, repo *repo_model.Repository, keyword string, isFuzzy bool, listOptions db.ListOptions) (int, []*SearchResult, error) {
+	if keyword == "" || !repo.HasWiki() {
+		return 0, nil, nil
+	}
+	listOptions.SetDefaultValues()
+
+	var (
+		total   int
+		results []*code_indexer.Result
+		err     error
+	)
+	if setting.Indexer.WikiIndexerEnabled {
+		total, results, _, err = code_indexer.PerformWikiSearch(ctx, &code_indexer.SearchOptions{
+			RepoIDs:        []int64{repo.ID},
+			Keyword:        keyword,
+			IsKeywordFuzzy: isFuzzy,
+			Paginator:      &listOptions,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+	} else {
+		if !git.IsBranchExist(ctx, repo.WikiPath(), repo.DefaultWikiBranch) {
+			return 0, nil, nil
+		}
+		wikiRepo, err := gitrepo.OpenWikiRepository(ctx, repo)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer wikiRepo.Close()
+
+		results, total, err = gitgrep.PerformSearch(ctx, listOptions.Page, listOptions.PageSize, repo.ID, wikiRepo, git.RefNameFromBranch(repo.DefaultWikiBranch), keyword, isFuzzy, []string{"*.md"})
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	pages := make([]*SearchResult, 0, len(results))
+	for _, result := range results {
+		wikiName, err := GitPathToWebPath(result.Filename)
+		if err != nil {
+			log.Warn("Unable to convert the wiki file %q of %-v to a page name: %v", result.Filename, repo, err)
+			continue
+		}
+		_, title := WebPathToUserTitle(wikiName)
+		pages = append(pages, &SearchResult{
+			Result:  result,
+			WebPath: wikiName,
+			SubURL:  WebPathToURLPath(wikiName),
+			Title:   title,
+		})
+	}
+	return total, pages, nil
+}
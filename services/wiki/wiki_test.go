@@ -5,6 +5,7 @@ package wiki
 
 import (
 	"math/rand"
+	"os"
 	"strings"
 	"testing"
 
@@ -249,6 +250,52 @@ func TestRepository_DeleteWikiPage(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestReplaceWikiLinks(t *testing.T) {
+	kases := []struct {
+		content  string
+		expected string
+	}{
+		{"[home](Home)", "[home](New-Home)"},
+		{"[home](./Home#usage)", "[home](./New-Home#usage)"},
+		{"[home](/user2/repo1/wiki/Home)", "[home](/user2/repo1/wiki/New-Home)"},
+		{"[[Home]] and [[the start|Home]]", "[[New Home]] and [[the start|New Home]]"},
+		{"[other](Home-Page) [[Homepage]]", "[other](Home-Page) [[Homepage]]"},
+	}
+	for _, kase := range kases {
+		assert.Equal(t, kase.expected, ReplaceWikiLinks(kase.content, "Home", "New-Home"))
+	}
+}
+
+func TestRepository_RenameWikiPage(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, AddWikiPage(git.DefaultContext, doer, repo, "Links", "See [[Home]]", "Add links"))
+	assert.NoError(t, RenameWikiPage(git.DefaultContext, doer, repo, "Home", "Start", "Rename home", true))
+
+	err := RenameWikiPage(git.DefaultContext, doer, repo, "Home", "Start", "Rename home", true)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	err = RenameWikiPage(git.DefaultContext, doer, repo, "Links", "Start", "Rename links", true)
+	assert.True(t, repo_model.IsErrWikiAlreadyExist(err))
+
+	gitRepo, err := gitrepo.OpenWikiRepository(git.DefaultContext, repo)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer gitRepo.Close()
+	masterTree, err := gitRepo.GetTree(repo.DefaultWikiBranch)
+	assert.NoError(t, err)
+	_, err = masterTree.GetTreeEntryByPath("Home.md")
+	assert.Error(t, err)
+	_, err = masterTree.GetTreeEntryByPath("Start.md")
+	assert.NoError(t, err)
+	entry, err := masterTree.GetTreeEntryByPath("Links.md")
+	assert.NoError(t, err)
+	content, err := entry.Blob().GetBlobContent(entry.Blob().Size())
+	assert.NoError(t, err)
+	assert.Equal(t, "See [[Start]]", content)
+}
+
 func TestPrepareWikiFileName(t *testing.T) {
 	unittest.PrepareTestEnv(t)
 	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
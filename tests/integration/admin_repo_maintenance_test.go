@@ -0,0 +1,41 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminRepoMaintenance(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	req := NewRequest(t, "GET", "/admin/repos/maintenance")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.True(t, test.IsNormalPageCompleted(resp.Body.String()))
+
+	req = NewRequestWithValues(t, "POST", "/admin/repos/maintenance/run", map[string]string{
+		"_csrf": GetCSRF(t, session, "/admin/repos/maintenance"),
+		"id":    "1",
+	})
+	session.MakeRequest(t, req, http.StatusSeeOther)
+	unittest.AssertExistsAndLoadBean(t, &repo_model.RepoMaintenance{RepoID: 1, Trigger: repo_model.RepoMaintenanceTriggerManual})
+
+	req = NewRequest(t, "GET", "/admin/repos/maintenance")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.Equal(t, "user2/repo1", htmlDoc.doc.Find("table tbody tr td a").First().Text())
+
+	// only administrators can see the statuses
+	req = NewRequest(t, "GET", "/admin/repos/maintenance")
+	loginUser(t, "user2").MakeRequest(t, req, http.StatusForbidden)
+}
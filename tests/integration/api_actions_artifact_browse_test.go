@@ -0,0 +1,161 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/actions"
+	actions_service "code.gitea.io/gitea/services/actions"
+	repo_service "code.gitea.io/gitea/services/repository"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func uploadActionsArtifactV4(t *testing.T, name string, body []byte) {
+	token, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	require.NoError(t, err)
+
+	req := NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/CreateArtifact", toProtoJSON(&actions.CreateArtifactRequest{
+		Version:                 4,
+		Name:                    name,
+		WorkflowRunBackendId:    "792",
+		WorkflowJobRunBackendId: "193",
+	})).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var uploadResp actions.CreateArtifactResponse
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &uploadResp))
+
+	idx := strings.Index(uploadResp.SignedUploadUrl, "/twirp/")
+	req = NewRequestWithBody(t, "PUT", uploadResp.SignedUploadUrl[idx:]+"&comp=block", bytes.NewReader(body))
+	MakeRequest(t, req, http.StatusCreated)
+
+	sha := sha256.Sum256(body)
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.ArtifactService/FinalizeArtifact", toProtoJSON(&actions.FinalizeArtifactRequest{
+		Name:                    name,
+		Size:                    int64(len(body)),
+		Hash:                    wrapperspb.String("sha256:" + hex.EncodeToString(sha[:])),
+		WorkflowRunBackendId:    "792",
+		WorkflowJobRunBackendId: "193",
+	})).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusOK)
+}
+
+// enableRepo4Actions enables the actions of the repository of the run 792
+func enableRepo4Actions(t *testing.T) {
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 4})
+	require.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+		RepoID: repo.ID,
+		Type:   unit_model.TypeActions,
+	}}, nil))
+}
+
+func TestAPIActionsArtifactBrowse(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	enableRepo4Actions(t)
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"report.txt": "0123456789", "logs/build.log": "build ok"} {
+		w, err := zipWriter.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	uploadActionsArtifactV4(t, "browsed", buf.Bytes())
+
+	token := getUserToken(t, "user5", auth_model.AccessTokenScopeWriteRepository)
+	link := "/api/v1/repos/user5/repo4/actions/runs/792/artifacts"
+
+	req := NewRequest(t, "GET", link).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var artifacts api.ActionArtifactsResponse
+	DecodeJSON(t, resp, &artifacts)
+	names := make([]string, 0, len(artifacts.Entries))
+	for _, artifact := range artifacts.Entries {
+		names = append(names, artifact.Name)
+	}
+	assert.Contains(t, names, "browsed")
+
+	req = NewRequest(t, "GET", link+"/browsed/files").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var files []*api.ActionArtifactFile
+	DecodeJSON(t, resp, &files)
+	assert.ElementsMatch(t, []*api.ActionArtifactFile{{Path: "report.txt", Size: 10}, {Path: "logs/build.log", Size: 8}}, files)
+
+	req = NewRequest(t, "GET", link+"/browsed/files/logs/build.log").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "build ok", resp.Body.String())
+	req = NewRequest(t, "GET", link+"/browsed/files/report.txt").AddTokenAuth(token).SetHeader("Range", "bytes=2-5")
+	resp = MakeRequest(t, req, http.StatusPartialContent)
+	assert.Equal(t, "2345", resp.Body.String())
+	req = NewRequest(t, "GET", link+"/browsed/files/unknown.txt").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", link+"/browsed/zip").AddTokenAuth(token).SetHeader("Range", "bytes=0-3")
+	resp = MakeRequest(t, req, http.StatusPartialContent)
+	assert.Equal(t, buf.Bytes()[:4], resp.Body.Bytes())
+
+	// the zip file is rewritten without the deleted file
+	req = NewRequest(t, "DELETE", link+"/browsed/files/report.txt").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", link+"/browsed/files").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &files)
+	assert.Equal(t, []*api.ActionArtifactFile{{Path: "logs/build.log", Size: 8}}, files)
+
+	// the artifact is deleted with its last file
+	req = NewRequest(t, "DELETE", link+"/browsed/files/logs/build.log").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", link+"/browsed/files").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{RunID: 792, ArtifactName: "browsed", Status: int64(actions_model.ArtifactStatusPendingDeletion)})
+}
+
+func TestAPIActionsArtifactRetention(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	enableRepo4Actions(t)
+
+	uploadActionsArtifactV4(t, "retained", []byte("content"))
+	artifact := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{RunID: 792, ArtifactName: "retained"})
+	assert.InDelta(t, 90*24*3600, int64(artifact.ExpiredUnix-artifact.CreatedUnix), 2)
+
+	token := getUserToken(t, "user5", auth_model.AccessTokenScopeWriteRepository)
+	link := "/api/v1/repos/user5/repo4/actions/artifact-retention"
+
+	req := NewRequestWithJSON(t, "PUT", link, &api.EditActionArtifactRetentionOption{Days: 100}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PUT", link, &api.EditActionArtifactRetentionOption{Days: 10}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var retention api.ActionArtifactRetention
+	DecodeJSON(t, resp, &retention)
+	assert.EqualValues(t, 10, retention.Days)
+	assert.EqualValues(t, 90, retention.MaximumAllowedDays)
+
+	// the artifacts already uploaded expire earlier, the new ones are kept for the retention of the repository
+	artifact = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{ID: artifact.ID})
+	assert.InDelta(t, 10*24*3600, int64(artifact.ExpiredUnix-artifact.CreatedUnix), 2)
+	uploadActionsArtifactV4(t, "retained-after", []byte("content"))
+	artifact = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{RunID: 792, ArtifactName: "retained-after"})
+	assert.InDelta(t, 10*24*3600, int64(artifact.ExpiredUnix-artifact.CreatedUnix), 2)
+}
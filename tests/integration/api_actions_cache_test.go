@@ -0,0 +1,112 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsCache(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	const token = "8061e833a55f6fc0157c98b883e91fcfeeb1a71a"
+	const base = "/api/actions_cache/_apis/artifactcache"
+
+	// no cache yet
+	req := NewRequest(t, "GET", base+"/cache?keys=npm-linux-abc&version=v1").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// reserve and upload the cache in two chunks
+	req = NewRequestWithJSON(t, "POST", base+"/caches", map[string]any{
+		"key":       "npm-linux-abc",
+		"version":   "v1",
+		"cacheSize": 2048,
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var reserved struct {
+		CacheID int64 `json:"cacheId"`
+	}
+	DecodeJSON(t, resp, &reserved)
+	assert.NotZero(t, reserved.CacheID)
+	cacheURL := fmt.Sprintf("%s/caches/%d", base, reserved.CacheID)
+
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader(strings.Repeat("B", 1024))).
+		AddTokenAuth(token).
+		SetHeader("Content-Range", "bytes 1024-2047/*")
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader(strings.Repeat("A", 1024))).
+		AddTokenAuth(token).
+		SetHeader("Content-Range", "bytes 0-1023/*")
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// the caches reserved by other runs or in other scopes, like the default branch, can't be written
+	cache, err := actions_model.GetCacheByID(db.DefaultContext, reserved.CacheID)
+	require.NoError(t, err)
+	otherRun, err := actions_model.ReserveCache(db.DefaultContext, cache.RepoID, cache.RunID+1, cache.Scope, "npm-linux-other-run", "v1")
+	require.NoError(t, err)
+	otherScope, err := actions_model.ReserveCache(db.DefaultContext, cache.RepoID, cache.RunID, cache.Scope+"-other", "npm-linux-abc", "v1")
+	require.NoError(t, err)
+	for _, id := range []int64{otherRun.ID, otherScope.ID} {
+		req = NewRequestWithBody(t, "PATCH", fmt.Sprintf("%s/caches/%d", base, id), strings.NewReader("poisoned")).
+			AddTokenAuth(token).
+			SetHeader("Content-Range", "bytes 0-7/*")
+		MakeRequest(t, req, http.StatusNotFound)
+		req = NewRequest(t, "POST", fmt.Sprintf("%s/caches/%d", base, id)).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	}
+
+	// the cache isn't restored before it's committed
+	req = NewRequest(t, "GET", base+"/cache?keys=npm-linux-abc&version=v1").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequestWithJSON(t, "POST", cacheURL, map[string]any{"size": 2048}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// the key can't be reserved again
+	req = NewRequestWithJSON(t, "POST", base+"/caches", map[string]any{
+		"key":     "npm-linux-abc",
+		"version": "v1",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusConflict)
+
+	// restore the cache by a prefix of its key
+	req = NewRequest(t, "GET", base+"/cache?keys=npm-windows-,npm-linux-&version=v1").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var found struct {
+		Result          string `json:"result"`
+		ArchiveLocation string `json:"archiveLocation"`
+		CacheKey        string `json:"cacheKey"`
+	}
+	DecodeJSON(t, resp, &found)
+	assert.Equal(t, "hit", found.Result)
+	assert.Equal(t, "npm-linux-abc", found.CacheKey)
+
+	// another version doesn't match
+	req = NewRequest(t, "GET", base+"/cache?keys=npm-linux-abc&version=v2").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// the content is downloaded with the signed link, without the token
+	idx := strings.Index(found.ArchiveLocation, base)
+	req = NewRequest(t, "GET", found.ArchiveLocation[idx:])
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, strings.Repeat("A", 1024)+strings.Repeat("B", 1024), resp.Body.String())
+
+	// a link which isn't signed is rejected
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/artifacts/%d", base, reserved.CacheID))
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// the runtime token is required
+	req = NewRequest(t, "GET", base+"/cache?keys=npm-linux-abc&version=v1")
+	MakeRequest(t, req, http.StatusUnauthorized)
+}
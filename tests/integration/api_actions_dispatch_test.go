@@ -0,0 +1,136 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIDispatchActionWorkflow(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+		repo, err := repo_service.CreateRepository(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:          "workflow-dispatch",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "master",
+		})
+		assert.NoError(t, err)
+		err = repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+			RepoID: repo.ID,
+			Type:   unit_model.TypeActions,
+		}}, nil)
+		assert.NoError(t, err)
+
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/deploy.yml",
+					ContentReader: strings.NewReader(`on:
+  workflow_dispatch:
+    inputs:
+      level:
+        type: choice
+        options: [info, debug]
+        default: info
+      dry_run:
+        type: boolean
+      replicas:
+        type: number
+        required: true
+      target:
+        type: environment
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`),
+				},
+			},
+			Message:   "add workflow",
+			OldBranch: "master",
+			NewBranch: "master",
+			Author:    &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Committer: &files_service.IdentityOptions{Name: user2.Name, Email: user2.Email},
+			Dates:     &files_service.CommitDateOptions{Author: time.Now(), Committer: time.Now()},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID}))
+
+		link := fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/deploy.yml/dispatches", user2.Name, repo.Name)
+
+		// invalid inputs
+		for _, inputs := range []map[string]string{
+			{},
+			{"replicas": "two"},
+			{"replicas": "2", "level": "trace"},
+			{"replicas": "2", "dry_run": "maybe"},
+			{"replicas": "2", "unknown": "value"},
+		} {
+			req := NewRequestWithJSON(t, "POST", link, &api.CreateActionWorkflowDispatch{Ref: "master", Inputs: inputs}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		}
+
+		// unknown ref or workflow
+		req := NewRequestWithJSON(t, "POST", link, &api.CreateActionWorkflowDispatch{Ref: "unknown", Inputs: map[string]string{"replicas": "2"}}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+		req = NewRequestWithJSON(t, "POST", strings.Replace(link, "deploy.yml", "unknown.yml", 1), &api.CreateActionWorkflowDispatch{Ref: "master"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+		assert.Equal(t, 0, unittest.GetCount(t, &actions_model.ActionRun{RepoID: repo.ID}))
+
+		req = NewRequestWithJSON(t, "POST", link, &api.CreateActionWorkflowDispatch{
+			Ref:    "master",
+			Inputs: map[string]string{"replicas": "2.0", "dry_run": "true", "target": "production"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID})
+		assert.Equal(t, webhook_module.HookEventWorkflowDispatch, run.Event)
+		assert.Equal(t, "workflow_dispatch", run.TriggerEvent)
+		assert.Equal(t, "refs/heads/master", run.Ref)
+		expected := map[string]string{"level": "info", "dry_run": "true", "replicas": "2", "target": "production"}
+		assert.Equal(t, expected, run.DispatchInputs)
+		payload := &api.WorkflowDispatchPayload{}
+		assert.NoError(t, json.Unmarshal([]byte(run.EventPayload), payload))
+		assert.Equal(t, expected, payload.Inputs)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", user2.Name, repo.Name, run.ID)).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var apiRun api.ActionRun
+		DecodeJSON(t, resp, &apiRun)
+		assert.Equal(t, "workflow_dispatch", apiRun.Event)
+		assert.Equal(t, expected, apiRun.Inputs)
+
+		// a reader can't run the workflow
+		readerToken := getTokenForLoggedInUser(t, loginUser(t, "user4"), auth_model.AccessTokenScopeWriteRepository)
+		req = NewRequestWithJSON(t, "POST", link, &api.CreateActionWorkflowDispatch{Ref: "master", Inputs: map[string]string{"replicas": "2"}}).AddTokenAuth(readerToken)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIActionsEffectiveVariables(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+
+	req := NewRequestWithJSON(t, "PUT", "/api/v1/admin/actions/variables/TARGET", api.CreateVariableOption{Value: "instance"}).AddTokenAuth(adminToken)
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/admin/actions/secrets/DEPLOY_KEY", api.CreateOrUpdateSecretOption{Data: "instance"}).AddTokenAuth(adminToken)
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/admin/actions/variables/TARGET", api.CreateVariableOption{Value: "instance"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/actions/environments/production/variables/TARGET", api.CreateVariableOption{Value: "production"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/actions/environments/production/variables/TARGET", api.CreateVariableOption{Value: "prod"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/actions/environments/production/secrets/DEPLOY_KEY", api.CreateOrUpdateSecretOption{Data: "production"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/actions/environments/production/variables").AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var variables []*api.ActionVariable
+	DecodeJSON(t, resp, &variables)
+	if assert.Len(t, variables, 1) {
+		assert.Equal(t, "production", variables[0].Environment)
+		assert.Equal(t, "prod", variables[0].Data)
+	}
+
+	t.Run("WithoutEnvironment", func(t *testing.T) {
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/actions/effective-variables").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var effective api.ActionEffectiveVariables
+		DecodeJSON(t, resp, &effective)
+		assert.Equal(t, []*api.ActionEffectiveVariable{{Name: "TARGET", Data: "instance", Scope: "instance", Overrides: []string{}}}, effective.Variables)
+		assert.Equal(t, []*api.ActionEffectiveVariable{{Name: "DEPLOY_KEY", Scope: "instance", Overrides: []string{}}}, effective.Secrets)
+		assert.Empty(t, effective.Warnings)
+	})
+
+	t.Run("WithEnvironment", func(t *testing.T) {
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/actions/effective-variables?environment=production").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var effective api.ActionEffectiveVariables
+		DecodeJSON(t, resp, &effective)
+		assert.Equal(t, []*api.ActionEffectiveVariable{{Name: "TARGET", Data: "prod", Scope: "environment", Overrides: []string{"instance"}}}, effective.Variables)
+		assert.Equal(t, []*api.ActionEffectiveVariable{{Name: "DEPLOY_KEY", Scope: "environment", Overrides: []string{"instance"}}}, effective.Secrets)
+		assert.Len(t, effective.Warnings, 2)
+	})
+
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/actions/environments/production/secrets/DEPLOY_KEY").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "DELETE", "/api/v1/repos/user2/repo1/actions/environments/production/secrets/DEPLOY_KEY").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "DELETE", "/api/v1/admin/actions/variables/TARGET").AddTokenAuth(adminToken)
+	MakeRequest(t, req, http.StatusNoContent)
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/tests"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// prepareActionTaskLogs writes the logs of the task 48 of the job 193 with a step and completes them
+func prepareActionTaskLogs(t *testing.T, contents []string) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	rows := make([]*runnerv1.LogRow, 0, len(contents))
+	for i, content := range contents {
+		rows = append(rows, &runnerv1.LogRow{Time: timestamppb.New(started.Add(time.Duration(i) * time.Second)), Content: content})
+	}
+
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 48})
+	task.LogFilename = "api-actions-log-test/48.log"
+	task.LogInStorage = false
+	ns, err := actions_module.WriteLogs(db.DefaultContext, task.LogFilename, 0, rows)
+	require.NoError(t, err)
+	task.LogIndexes, task.LogSize = nil, 0
+	for _, n := range ns {
+		task.LogIndexes = append(task.LogIndexes, task.LogSize)
+		task.LogSize += int64(n)
+	}
+	task.LogLength = int64(len(rows))
+	task.Status = actions_model.StatusSuccess
+	_, err = db.GetEngine(db.DefaultContext).ID(task.ID).Cols("log_filename", "log_in_storage", "log_indexes", "log_size", "log_length", "status").Update(task)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Insert(db.DefaultContext, &actions_model.ActionTaskStep{
+		TaskID:    task.ID,
+		RepoID:    task.RepoID,
+		Name:      "build",
+		Status:    actions_model.StatusSuccess,
+		LogIndex:  1,
+		LogLength: int64(len(rows)) - 2,
+		Started:   timeutil.TimeStamp(started.Unix() + 1),
+		Stopped:   timeutil.TimeStamp(started.Unix() + int64(len(rows)) - 1),
+	}))
+	_, err = db.GetEngine(db.DefaultContext).ID(193).Cols("status").Update(&actions_model.ActionRunJob{Status: actions_model.StatusSuccess})
+	require.NoError(t, err)
+}
+
+func TestAPIActionsJobLogs(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	enableRepo4Actions(t)
+	prepareActionTaskLogs(t, []string{"set up", "building", "built", "cleaned up"})
+
+	token := getUserToken(t, "user5", auth_model.AccessTokenScopeReadRepository)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user5/repo4/actions/jobs/193/logs").AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	lines := strings.Split(strings.TrimSuffix(resp.Body.String(), "\n"), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.Equal(t, "2024-05-01T10:00:01.0000000Z building", lines[1])
+	}
+
+	// the job is done, the stream ends after its logs
+	req = NewRequest(t, "GET", "/api/v1/repos/user5/repo4/actions/jobs/193/logs?follow=true").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var names, ids []string
+	var steps []*api.ActionJobStep
+	var logLines []*api.ActionJobLogLine
+	for _, event := range strings.Split(strings.TrimSpace(resp.Body.String()), "\n\n") {
+		var name, id, data string
+		for _, field := range strings.Split(event, "\n") {
+			key, value, _ := strings.Cut(field, ": ")
+			switch key {
+			case "event":
+				name = value
+			case "id":
+				id = value
+			case "data":
+				data = value
+			}
+		}
+		names = append(names, name)
+		switch name {
+		case "step":
+			var step api.ActionJobStep
+			require.NoError(t, json.Unmarshal([]byte(data), &step))
+			steps = append(steps, &step)
+		case "log":
+			var line api.ActionJobLogLine
+			require.NoError(t, json.Unmarshal([]byte(data), &line))
+			logLines = append(logLines, &line)
+			ids = append(ids, id)
+		}
+	}
+	assert.Equal(t, []string{"step", "log", "step", "log", "log", "step", "log", "end"}, names)
+	assert.Equal(t, []string{"48-1", "48-2", "48-3", "48-4"}, ids)
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, "Set up job", steps[0].Name)
+		assert.Equal(t, "build", steps[1].Name)
+		assert.Equal(t, "success", steps[1].Status)
+		assert.Equal(t, "Complete job", steps[2].Name)
+	}
+	if assert.Len(t, logLines, 4) {
+		assert.Equal(t, &api.ActionJobLogLine{Step: 1, Line: 3, Time: time.Date(2024, 5, 1, 10, 0, 2, 0, time.UTC), Content: "built"}, logLines[2])
+	}
+
+	// a reconnecting client only receives the following lines
+	req = NewRequest(t, "GET", "/api/v1/repos/user5/repo4/actions/jobs/193/logs?follow=true").AddTokenAuth(token).SetHeader("Last-Event-ID", "48-3")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, 1, strings.Count(resp.Body.String(), "event: log\n"))
+	assert.Contains(t, resp.Body.String(), `"content":"cleaned up"`)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user5/repo4/actions/runs/792/logs").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	zipReader, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string, len(zipReader.File))
+	for _, file := range zipReader.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+	assert.Len(t, files, 4)
+	assert.Equal(t, strings.Join(lines, "\n")+"\n", files["1_job_2.txt"])
+	assert.Equal(t, "2024-05-01T10:00:01.0000000Z building\n2024-05-01T10:00:02.0000000Z built\n", files["1_job_2/2_build.txt"])
+	assert.Equal(t, "2024-05-01T10:00:03.0000000Z cleaned up\n", files["1_job_2/3_Complete job.txt"])
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/actions/jobs/193/logs").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIActionsPolicy(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+	orgToken := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteOrganization)
+
+	req := NewRequest(t, "GET", "/api/v1/admin/actions/policy").AddTokenAuth(adminToken)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var policy api.ActionsPolicy
+	DecodeJSON(t, resp, &policy)
+	assert.Equal(t, api.ActionsPolicy{AllowedActions: "all", PatternsAllowed: []string{}, PatternsBlocked: []string{}}, policy)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/admin/actions/policy", api.EditActionsPolicyOption{
+		AllowedActions:  "all",
+		PatternsBlocked: []string{"evil/*"},
+	}).AddTokenAuth(adminToken)
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/admin/actions/policy", api.EditActionsPolicyOption{AllowedActions: "local_only"}).AddTokenAuth(orgToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/actions/policy", api.EditActionsPolicyOption{
+		AllowedActions:    "selected",
+		PatternsAllowed:   []string{"actions/*", "gitea.com/actions/go-hashfiles@v0.0.1"},
+		RequireSHAPinning: true,
+	}).AddTokenAuth(orgToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &policy)
+	assert.Equal(t, api.ActionsPolicy{
+		AllowedActions:    "selected",
+		PatternsAllowed:   []string{"actions/*", "gitea.com/actions/go-hashfiles@v0.0.1"},
+		PatternsBlocked:   []string{},
+		RequireSHAPinning: true,
+	}, policy)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/org3/actions/policy").AddTokenAuth(orgToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &policy)
+	assert.Equal(t, "selected", policy.AllowedActions)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/actions/policy", api.EditActionsPolicyOption{AllowedActions: "none"}).AddTokenAuth(orgToken)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/actions/policy", api.EditActionsPolicyOption{
+		AllowedActions:  "all",
+		PatternsBlocked: []string{"actions/[checkout"},
+	}).AddTokenAuth(orgToken)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
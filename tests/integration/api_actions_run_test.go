@@ -0,0 +1,232 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIActionRunConcurrency(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+
+	jobs, err := jobparser.Parse([]byte(`
+on: push
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`))
+	assert.NoError(t, err)
+	run := &actions_model.ActionRun{
+		Title:             "deploy",
+		RepoID:            repo.ID,
+		OwnerID:           repo.OwnerID,
+		WorkflowID:        "deploy.yaml",
+		TriggerUserID:     owner.ID,
+		Ref:               "refs/heads/master",
+		TriggerEvent:      "push",
+		Status:            actions_model.StatusWaiting,
+		ConcurrencyGroup:  "deploy-master",
+		ConcurrencyCancel: true,
+	}
+	assert.NoError(t, actions_model.InsertRun(db.DefaultContext, run, jobs, []*actions_model.Concurrency{{Group: "deploy-job"}}))
+
+	req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?concurrency_group=deploy-master", owner.Name, repo.Name)).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var runs api.ActionRunsResponse
+	DecodeJSON(t, resp, &runs)
+	assert.EqualValues(t, 1, runs.TotalCount)
+	if assert.Len(t, runs.Entries, 1) {
+		assert.Equal(t, run.ID, runs.Entries[0].ID)
+		assert.Equal(t, "deploy-master", runs.Entries[0].ConcurrencyGroup)
+		assert.True(t, runs.Entries[0].CancelInProgress)
+		assert.Empty(t, runs.Entries[0].Jobs)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs?concurrency_group=other", owner.Name, repo.Name)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	runs = api.ActionRunsResponse{}
+	DecodeJSON(t, resp, &runs)
+	assert.Empty(t, runs.Entries)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", owner.Name, repo.Name, run.ID)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var apiRun api.ActionRun
+	DecodeJSON(t, resp, &apiRun)
+	assert.Equal(t, run.Index, apiRun.RunNumber)
+	if assert.Len(t, apiRun.Jobs, 1) {
+		assert.Equal(t, "deploy", apiRun.Jobs[0].JobID)
+		assert.Equal(t, "deploy-job", apiRun.Jobs[0].ConcurrencyGroup)
+		assert.Equal(t, "waiting", apiRun.Jobs[0].Status)
+	}
+
+	// a run of another repository isn't found
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", owner.Name, repo.Name, 791)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIActionRunJobGraph(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+
+	jobs, err := jobparser.Parse([]byte(`
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, windows]
+    steps:
+      - run: echo build
+  deploy:
+    needs: build
+    runs-on: [self-hosted, linux]
+    steps:
+      - run: echo deploy
+`))
+	assert.NoError(t, err)
+	run := &actions_model.ActionRun{
+		Title:         "build",
+		RepoID:        repo.ID,
+		OwnerID:       repo.OwnerID,
+		WorkflowID:    "build.yaml",
+		TriggerUserID: owner.ID,
+		Ref:           "refs/heads/master",
+		TriggerEvent:  "push",
+		Status:        actions_model.StatusWaiting,
+	}
+	assert.NoError(t, actions_model.InsertRun(db.DefaultContext, run, jobs, nil))
+
+	req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", owner.Name, repo.Name, run.ID)).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var apiRun api.ActionRun
+	DecodeJSON(t, resp, &apiRun)
+
+	apiJobs := make(map[string]*api.ActionRunJob, len(apiRun.Jobs))
+	for _, job := range apiRun.Jobs {
+		apiJobs[job.Name] = job
+	}
+	if assert.Len(t, apiJobs, 3) {
+		assert.Equal(t, map[string]any{"os": "linux"}, apiJobs["build (linux)"].Matrix)
+		assert.Equal(t, map[string]any{"os": "windows"}, apiJobs["build (windows)"].Matrix)
+		assert.Empty(t, apiJobs["build (linux)"].Needs)
+		assert.Equal(t, "build", apiJobs["build (windows)"].JobID)
+
+		deploy := apiJobs["deploy"]
+		assert.Nil(t, deploy.Matrix)
+		assert.Equal(t, []string{"build"}, deploy.Needs)
+		assert.Equal(t, []string{"self-hosted", "linux"}, deploy.RunsOn)
+		assert.Equal(t, "blocked", deploy.Status)
+		assert.Nil(t, deploy.StartedAt)
+		assert.Nil(t, deploy.CompletedAt)
+		assert.Zero(t, deploy.Duration)
+	}
+}
+
+func TestAPIActionRunRerun(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	jobs, err := jobparser.Parse([]byte(`
+on: workflow_dispatch
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+  test:
+    needs: build
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`))
+	assert.NoError(t, err)
+	run := &actions_model.ActionRun{
+		Title:          "test",
+		RepoID:         repo.ID,
+		OwnerID:        repo.OwnerID,
+		WorkflowID:     "test.yaml",
+		TriggerUserID:  owner.ID,
+		Ref:            "refs/heads/master",
+		TriggerEvent:   "workflow_dispatch",
+		DispatchInputs: map[string]string{"target": "staging"},
+		Status:         actions_model.StatusWaiting,
+	}
+	assert.NoError(t, actions_model.InsertRun(db.DefaultContext, run, jobs, nil))
+	runJobs, err := actions_model.GetRunJobsByRunID(db.DefaultContext, run.ID)
+	assert.NoError(t, err)
+	setJobStatus := func(job *actions_model.ActionRunJob, status actions_model.Status) {
+		job.Status = status
+		_, err := actions_model.UpdateRunJob(db.DefaultContext, job, nil, "status")
+		assert.NoError(t, err)
+	}
+	setJobStatus(runJobs[0], actions_model.StatusSuccess)
+	setJobStatus(runJobs[1], actions_model.StatusFailure)
+
+	runLink := fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d", owner.Name, repo.Name, run.ID)
+	rerun := func(link string, expectedStatus int) *api.ActionRun {
+		resp := MakeRequest(t, NewRequest(t, "POST", link).AddTokenAuth(token), expectedStatus)
+		if expectedStatus != http.StatusOK {
+			return nil
+		}
+		var apiRun api.ActionRun
+		DecodeJSON(t, resp, &apiRun)
+		return &apiRun
+	}
+
+	// only the failed job is rerun, the run keeps its inputs
+	apiRun := rerun(runLink+"/rerun-failed-jobs", http.StatusOK)
+	assert.EqualValues(t, 2, apiRun.RunAttempt)
+	assert.Equal(t, map[string]string{"target": "staging"}, apiRun.Inputs)
+	if assert.Len(t, apiRun.Jobs, 2) {
+		assert.Equal(t, "success", apiRun.Jobs[0].Status)
+		assert.Equal(t, "waiting", apiRun.Jobs[1].Status)
+	}
+
+	// the run isn't done
+	rerun(runLink+"/rerun", http.StatusConflict)
+
+	setJobStatus(runJobs[1], actions_model.StatusSuccess)
+	rerun(runLink+"/rerun-failed-jobs", http.StatusUnprocessableEntity)
+
+	// the jobs depending on the rerun job wait for it
+	apiRun = rerun(fmt.Sprintf("/api/v1/repos/%s/%s/actions/jobs/%d/rerun", owner.Name, repo.Name, runJobs[0].ID), http.StatusOK)
+	assert.EqualValues(t, 3, apiRun.RunAttempt)
+	if assert.Len(t, apiRun.Jobs, 2) {
+		assert.Equal(t, "waiting", apiRun.Jobs[0].Status)
+		assert.Equal(t, "blocked", apiRun.Jobs[1].Status)
+	}
+
+	req := NewRequest(t, "POST", runLink+"/rerun").AddTokenAuth(getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository))
+	MakeRequest(t, req, http.StatusForbidden)
+}
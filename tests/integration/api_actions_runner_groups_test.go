@@ -0,0 +1,138 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIOrgRunnerGroups(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	runner := &actions_model.ActionRunner{
+		UUID:        "d7b7e7a4-6a3d-4e4a-9d3c-6b5e3e3c4d5e",
+		Name:        "org3-runner",
+		OwnerID:     3,
+		TokenHash:   "api-runner-groups-org3",
+		AgentLabels: []string{"ubuntu-latest"},
+	}
+	require.NoError(t, actions_model.CreateRunner(db.DefaultContext, runner))
+
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteOrganization)
+	link := "/api/v1/orgs/org3/actions/runner-groups"
+
+	req := NewRequestWithJSON(t, "POST", link, &api.CreateActionRunnerGroupOption{Name: "gpu", Visibility: "selected"}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var group api.ActionRunnerGroup
+	DecodeJSON(t, resp, &group)
+	assert.Equal(t, "gpu", group.Name)
+	assert.Equal(t, "selected", group.Visibility)
+
+	req = NewRequestWithJSON(t, "POST", link, &api.CreateActionRunnerGroupOption{Name: "gpu"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusConflict)
+
+	groupLink := fmt.Sprintf("%s/%d", link, group.ID)
+	req = NewRequest(t, "PUT", fmt.Sprintf("%s/runners/%d", groupLink, runner.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", groupLink+"/runners").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var runners api.ActionRunnersResponse
+	DecodeJSON(t, resp, &runners)
+	if assert.Len(t, runners.Entries, 1) {
+		assert.Equal(t, runner.ID, runners.Entries[0].ID)
+		assert.Equal(t, group.ID, runners.Entries[0].GroupID)
+	}
+
+	// repo1 belongs to user2, not to the organization
+	req = NewRequest(t, "PUT", groupLink+"/repositories/1").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequest(t, "PUT", groupLink+"/repositories/3").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", groupLink+"/repositories").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var repos []*api.Repository
+	DecodeJSON(t, resp, &repos)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 3, repos[0].ID)
+	}
+
+	labelsLink := fmt.Sprintf("/api/v1/orgs/org3/actions/runners/%d/labels", runner.ID)
+	req = NewRequestWithJSON(t, "POST", labelsLink, &api.ActionRunnerLabelsOption{Labels: []string{"gpu", "cuda"}}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var labels api.ActionRunnerLabelsResponse
+	DecodeJSON(t, resp, &labels)
+	assert.EqualValues(t, 3, labels.TotalCount)
+	assert.Equal(t, &api.ActionRunnerLabel{Name: "ubuntu-latest", Type: "read-only"}, labels.Labels[0])
+	assert.Equal(t, &api.ActionRunnerLabel{Name: "gpu", Type: "custom"}, labels.Labels[1])
+
+	req = NewRequest(t, "DELETE", labelsLink+"/ubuntu-latest").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequest(t, "DELETE", labelsLink+"/cuda").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequestWithJSON(t, "PUT", labelsLink, &api.ActionRunnerLabelsOption{Labels: []string{"arm64"}}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusOK)
+	runner = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{ID: runner.ID})
+	assert.Equal(t, []string{"arm64"}, runner.CustomLabels)
+
+	// a global runner isn't a runner of the organization
+	globalRunner := &actions_model.ActionRunner{UUID: "e8c8f8b5-7b4e-4f5b-8e4d-7c6f4f4d5e6f", Name: "global-runner", TokenHash: "api-runner-groups-global"}
+	require.NoError(t, actions_model.CreateRunner(db.DefaultContext, globalRunner))
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/org3/actions/runners/%d/labels", globalRunner.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "DELETE", groupLink).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	runner = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunner{ID: runner.ID})
+	assert.Zero(t, runner.GroupID)
+
+	// only the owners of the organization can manage the runner groups
+	req = NewRequest(t, "GET", link).AddTokenAuth(getUserToken(t, "user4", auth_model.AccessTokenScopeWriteOrganization))
+	MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIAdminRunnerGroups(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/admin/runner-groups", &api.CreateActionRunnerGroupOption{Name: "shared"}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var group api.ActionRunnerGroup
+	DecodeJSON(t, resp, &group)
+	assert.Equal(t, "all", group.Visibility)
+
+	groupLink := fmt.Sprintf("/api/v1/admin/runner-groups/%d", group.ID)
+	req = NewRequestWithJSON(t, "PATCH", groupLink, &api.EditActionRunnerGroupOption{Visibility: &[]string{"selected"}[0]}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &group)
+	assert.Equal(t, "selected", group.Visibility)
+
+	req = NewRequest(t, "PUT", groupLink+"/organizations/3").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	// user2 isn't an organization
+	req = NewRequest(t, "PUT", groupLink+"/organizations/2").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", groupLink+"/organizations").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var orgs []*api.Organization
+	DecodeJSON(t, resp, &orgs)
+	if assert.Len(t, orgs, 1) {
+		assert.Equal(t, "org3", orgs[0].Name)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/admin/runner-groups").AddTokenAuth(getUserToken(t, "user2", auth_model.AccessTokenScopeWriteAdmin))
+	MakeRequest(t, req, http.StatusForbidden)
+}
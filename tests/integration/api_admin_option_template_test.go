@@ -0,0 +1,110 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminOptionTemplates(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+	userToken := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteAdmin)
+
+	MakeRequest(t, NewRequestWithJSON(t, "PUT", "/api/v1/admin/templates/gitignore/MyStack", &api.EditOptionTemplateOption{Content: "*.out"}).
+		AddTokenAuth(userToken), http.StatusForbidden)
+	MakeRequest(t, NewRequestWithJSON(t, "PUT", "/api/v1/admin/templates/readme/MyStack", &api.EditOptionTemplateOption{Content: "*.out"}).
+		AddTokenAuth(adminToken), http.StatusNotFound)
+	MakeRequest(t, NewRequestWithJSON(t, "PUT", "/api/v1/admin/templates/gitignore/.hidden", &api.EditOptionTemplateOption{Content: "*.out"}).
+		AddTokenAuth(adminToken), http.StatusUnprocessableEntity)
+	MakeRequest(t, NewRequestWithJSON(t, "PUT", "/api/v1/admin/templates/label/Broken", &api.EditOptionTemplateOption{Content: "labels:\n  - name: bug\n"}).
+		AddTokenAuth(adminToken), http.StatusUnprocessableEntity)
+
+	resp := MakeRequest(t, NewRequestWithJSON(t, "PUT", "/api/v1/admin/templates/gitignore/MyStack", &api.EditOptionTemplateOption{Content: "*.out\n"}).
+		AddTokenAuth(adminToken), http.StatusOK)
+	var tmpl api.OptionTemplate
+	DecodeJSON(t, resp, &tmpl)
+	assert.Equal(t, "gitignore", tmpl.Type)
+	assert.Equal(t, "MyStack", tmpl.Name)
+	assert.Equal(t, "*.out\n", tmpl.Content)
+	assert.False(t, tmpl.Created.IsZero())
+
+	MakeRequest(t, NewRequestWithJSON(t, "PUT", "/api/v1/admin/templates/license/In-House", &api.EditOptionTemplateOption{Content: "Copyright <owner>\n"}).
+		AddTokenAuth(adminToken), http.StatusOK)
+	MakeRequest(t, NewRequestWithJSON(t, "PUT", "/api/v1/admin/templates/label/Minimal", &api.EditOptionTemplateOption{
+		Content: "labels:\n  - name: bug\n    color: ee0701\n  - name: feature\n    color: 84b6eb\n",
+	}).AddTokenAuth(adminToken), http.StatusOK)
+
+	resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/admin/templates/license").AddTokenAuth(adminToken), http.StatusOK)
+	var tmpls []*api.OptionTemplate
+	DecodeJSON(t, resp, &tmpls)
+	if assert.Len(t, tmpls, 1) {
+		assert.Equal(t, "In-House", tmpls[0].Name)
+	}
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/admin/templates/label/Minimal").AddTokenAuth(adminToken), http.StatusOK)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/admin/templates/label/Unknown").AddTokenAuth(adminToken), http.StatusNotFound)
+
+	t.Run("List", func(t *testing.T) {
+		resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/gitignore/templates"), http.StatusOK)
+		var gitignores []string
+		DecodeJSON(t, resp, &gitignores)
+		assert.Contains(t, gitignores, "MyStack")
+
+		resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/gitignore/templates/MyStack"), http.StatusOK)
+		var gitignore api.GitignoreTemplateInfo
+		DecodeJSON(t, resp, &gitignore)
+		assert.Equal(t, "*.out\n", gitignore.Source)
+
+		resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/licenses"), http.StatusOK)
+		var licenses []*api.LicensesTemplateListEntry
+		DecodeJSON(t, resp, &licenses)
+		assert.Condition(t, func() bool {
+			for _, license := range licenses {
+				if license.Key == "In-House" {
+					return true
+				}
+			}
+			return false
+		})
+
+		resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/label/templates"), http.StatusOK)
+		var labelTemplates []string
+		DecodeJSON(t, resp, &labelTemplates)
+		assert.Contains(t, labelTemplates, "Minimal")
+	})
+
+	t.Run("CreateRepo", func(t *testing.T) {
+		resp := MakeRequest(t, NewRequestWithJSON(t, "POST", "/api/v1/user/repos", &api.CreateRepoOption{
+			Name:        "custom-templates",
+			AutoInit:    true,
+			Readme:      "Default",
+			Gitignores:  "MyStack",
+			License:     "In-House",
+			IssueLabels: "Minimal",
+		}).AddTokenAuth(adminToken), http.StatusCreated)
+		var repo api.Repository
+		DecodeJSON(t, resp, &repo)
+
+		resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user1/custom-templates/raw/.gitignore").AddTokenAuth(adminToken), http.StatusOK)
+		assert.Equal(t, "# ---> MyStack\n*.out\n\n", resp.Body.String())
+		resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user1/custom-templates/raw/LICENSE").AddTokenAuth(adminToken), http.StatusOK)
+		assert.Equal(t, "Copyright user1\n", resp.Body.String())
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{RepoID: repo.ID, Name: "bug", Color: "#ee0701"})
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Label{RepoID: repo.ID, Name: "feature"})
+	})
+
+	MakeRequest(t, NewRequest(t, "DELETE", "/api/v1/admin/templates/gitignore/MyStack").AddTokenAuth(adminToken), http.StatusNoContent)
+	MakeRequest(t, NewRequest(t, "DELETE", "/api/v1/admin/templates/gitignore/MyStack").AddTokenAuth(adminToken), http.StatusNotFound)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/gitignore/templates/MyStack"), http.StatusNotFound)
+}
@@ -11,10 +11,16 @@ import (
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/tests"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func testAPIGetBranch(t *testing.T, branchName string, exists bool) {
@@ -230,6 +236,49 @@ func TestAPIBranchProtection(t *testing.T) {
 	testAPIDeleteBranch(t, "branch2", http.StatusNoContent)
 }
 
+func TestAPIBranchProtectionStatusChecks(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+	statusChecksURL := "/api/v1/repos/user2/repo1/branch_protections/master/status_checks"
+
+	req := NewRequest(t, "GET", statusChecksURL).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	testAPICreateBranchProtection(t, "master", http.StatusCreated)
+
+	req = NewRequest(t, "GET", statusChecksURL).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var statusChecks api.BranchProtectionStatusChecks
+	DecodeJSON(t, resp, &statusChecks)
+	assert.False(t, statusChecks.EnableStatusCheck)
+	assert.Empty(t, statusChecks.StatusCheckContexts)
+
+	req = NewRequestWithJSON(t, "PUT", statusChecksURL, &api.EditBranchProtectionStatusChecksOption{
+		StatusCheckContexts: []string{"ci/[build"},
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PUT", statusChecksURL, &api.EditBranchProtectionStatusChecksOption{
+		StatusCheckContexts: []string{"ci/build", " ci/test* ", "", "ci/build"},
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &statusChecks)
+	assert.True(t, statusChecks.EnableStatusCheck)
+	assert.Equal(t, []string{"ci/build", "ci/test*"}, statusChecks.StatusCheckContexts)
+
+	bp := testAPIGetBranchProtection(t, "master", http.StatusOK)
+	assert.True(t, bp.EnableStatusCheck)
+	assert.Equal(t, []string{"ci/build", "ci/test*"}, bp.StatusCheckContexts)
+
+	req = NewRequest(t, "DELETE", statusChecksURL).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	bp = testAPIGetBranchProtection(t, "master", http.StatusOK)
+	assert.False(t, bp.EnableStatusCheck)
+	assert.Empty(t, bp.StatusCheckContexts)
+}
+
 func TestAPICreateBranchWithSyncBranches(t *testing.T) {
 	defer tests.PrepareTestEnv(t)()
 
@@ -263,3 +312,133 @@ func TestAPICreateBranchWithSyncBranches(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, branches, 1)
 }
+
+func TestAPIRebaseBranch(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+		testAPICreateBranch(t, session, "user2", "repo1", "master", "feature/rebase", http.StatusCreated)
+		_, err := createFileInBranch(user2, repo1, "feature.txt", "feature/rebase", "feature")
+		require.NoError(t, err)
+		_, err = createFileInBranch(user2, repo1, "master.txt", "master", "master")
+		require.NoError(t, err)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches/feature/rebase/rebase", &api.RebaseBranchOption{}).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var branch api.Branch
+		DecodeJSON(t, resp, &branch)
+		assert.Equal(t, "feature/rebase", branch.Name)
+
+		gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo1)
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		masterCommitID, err := gitRepo.GetBranchCommitID("master")
+		require.NoError(t, err)
+		commit, err := gitRepo.GetCommit(branch.Commit.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, commit.ParentCount())
+		parentID, err := commit.ParentID(0)
+		require.NoError(t, err)
+		assert.Equal(t, masterCommitID, parentID.String())
+
+		t.Run("Conflict", func(t *testing.T) {
+			testAPICreateBranch(t, session, "user2", "repo1", "master", "conflict", http.StatusCreated)
+			_, err := createFileInBranch(user2, repo1, "conflict.txt", "conflict", "theirs")
+			require.NoError(t, err)
+			_, err = createFileInBranch(user2, repo1, "conflict.txt", "master", "ours")
+			require.NoError(t, err)
+			conflictCommitID, err := gitRepo.GetBranchCommitID("conflict")
+			require.NoError(t, err)
+
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches/conflict/rebase", &api.RebaseBranchOption{Onto: "master"}).
+				AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusConflict)
+			var conflict api.RebaseBranchConflict
+			DecodeJSON(t, resp, &conflict)
+			assert.Equal(t, conflictCommitID, conflict.CommitSHA)
+			assert.Equal(t, []string{"conflict.txt"}, conflict.ConflictedFiles)
+
+			// the branch is left untouched
+			commitID, err := gitRepo.GetBranchCommitID("conflict")
+			require.NoError(t, err)
+			assert.Equal(t, conflictCommitID, commitID)
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches/master/rebase", &api.RebaseBranchOption{}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+			req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches/no-such-branch/rebase", &api.RebaseBranchOption{}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+
+			req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches/master", &api.RebaseBranchOption{}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
+
+func TestAPIBranchHistory(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+		gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo1)
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		masterCommitID, err := gitRepo.GetBranchCommitID("master")
+		require.NoError(t, err)
+
+		testAPICreateBranch(t, session, "user2", "repo1", "master", "feature/history", http.StatusCreated)
+		resp, err := createFileInBranch(user2, repo1, "history.txt", "feature/history", "history")
+		require.NoError(t, err)
+		featureCommitID := resp.Commit.SHA
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/feature/history/history").
+			AddTokenAuth(token)
+		var updates []*api.BranchUpdate
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &updates)
+		require.Len(t, updates, 2)
+		assert.Equal(t, masterCommitID, updates[0].OldCommitID)
+		assert.Equal(t, featureCommitID, updates[0].NewCommitID)
+		assert.Equal(t, "user2", updates[0].Pusher.UserName)
+		assert.Empty(t, updates[1].OldCommitID)
+		assert.Equal(t, masterCommitID, updates[1].NewCommitID)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches/feature/history/history", &api.RestoreBranchOption{CommitID: masterCommitID}).
+			AddTokenAuth(token)
+		var branch api.Branch
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &branch)
+		assert.Equal(t, "feature/history", branch.Name)
+		assert.Equal(t, masterCommitID, branch.Commit.ID)
+
+		// the restoration is an update of the branch too
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/feature/history/history").
+			AddTokenAuth(token)
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &updates)
+		require.Len(t, updates, 3)
+		assert.Equal(t, featureCommitID, updates[0].OldCommitID)
+		assert.Equal(t, masterCommitID, updates[0].NewCommitID)
+
+		t.Run("UnknownPosition", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branches/master/history", &api.RestoreBranchOption{CommitID: featureCommitID}).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		})
+
+		t.Run("NotAdmin", func(t *testing.T) {
+			token := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/feature/history/history").
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusForbidden)
+		})
+	})
+}
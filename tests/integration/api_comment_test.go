@@ -136,6 +136,55 @@ func TestAPICreateComment(t *testing.T) {
 	})
 }
 
+func TestAPIInternalComment(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/comments", repo.OwnerName, repo.Name, issue.Index)
+
+	// a reader can not post internal comments
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueCommentOption{
+		Body:     "internal note",
+		Internal: true,
+	}).AddTokenAuth(getUserToken(t, "user4", auth_model.AccessTokenScopeWriteIssue))
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueCommentOption{
+		Body:     "internal note",
+		Internal: true,
+	}).AddTokenAuth(getUserToken(t, repo.OwnerName, auth_model.AccessTokenScopeWriteIssue))
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var comment api.Comment
+	DecodeJSON(t, resp, &comment)
+	assert.True(t, comment.Internal)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: comment.ID, IsInternal: true})
+
+	// anonymous users do not see the comment
+	req = NewRequest(t, "GET", urlStr)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var comments []*api.Comment
+	DecodeJSON(t, resp, &comments)
+	for _, c := range comments {
+		assert.NotEqual(t, comment.ID, c.ID)
+	}
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/issues/comments/%d", repo.OwnerName, repo.Name, comment.ID)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// the owner does
+	req = NewRequest(t, "GET", urlStr).AddTokenAuth(getUserToken(t, repo.OwnerName, auth_model.AccessTokenScopeReadIssue))
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &comments)
+	found := false
+	for _, c := range comments {
+		if c.ID == comment.ID {
+			found = true
+			assert.True(t, c.Internal)
+		}
+	}
+	assert.True(t, found)
+}
+
 func TestAPIGetComment(t *testing.T) {
 	defer tests.PrepareTestEnv(t)()
 
@@ -0,0 +1,89 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueAutomations(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	repoURL := fmt.Sprintf("/api/v1/repos/%s/%s/issue_automations", owner.Name, repo.Name)
+
+	// only the administrators of the repository manage its rules
+	token := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue)
+	MakeRequest(t, NewRequest(t, "GET", repoURL).AddTokenAuth(token), http.StatusForbidden)
+
+	token = getUserToken(t, owner.Name, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue)
+	req := NewRequestWithJSON(t, "POST", repoURL, &api.CreateIssueAutomationRuleOption{
+		Name:   "Stale",
+		Days:   30,
+		Action: "label",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", repoURL, &api.CreateIssueAutomationRuleOption{
+		Name:          "Stale",
+		Days:          30,
+		Action:        "label",
+		ActionLabelID: 2,
+		Comment:       "This issue is stale.",
+		DryRun:        true,
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var rule api.IssueAutomationRule
+	DecodeJSON(t, resp, &rule)
+	assert.Equal(t, "issues", rule.Target)
+	assert.True(t, rule.DryRun)
+
+	// the preview lists the issues the rule matches
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/%d/preview", repoURL, rule.ID)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var issues []*api.Issue
+	DecodeJSON(t, resp, &issues)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, 1, issues[0].Index)
+	}
+
+	// a dry run is recorded in the logs without changing the issue
+	assert.NoError(t, issue_service.RunIssueAutomations(db.DefaultContext))
+	unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{IssueID: 1, LabelID: 2})
+	req = NewRequest(t, "GET", repoURL+"/logs").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var logs []*api.IssueAutomationLog
+	DecodeJSON(t, resp, &logs)
+	if assert.Len(t, logs, 1) {
+		assert.EqualValues(t, 1, logs[0].IssueIndex)
+		assert.True(t, logs[0].DryRun)
+	}
+
+	dryRun := false
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/%d", repoURL, rule.ID), &api.EditIssueAutomationRuleOption{
+		DryRun: &dryRun,
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &rule)
+	assert.False(t, rule.DryRun)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d", repoURL, rule.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertNotExistsBean(t, &issues_model.IssueAutomationRule{ID: rule.ID})
+}
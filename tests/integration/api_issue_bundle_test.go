@@ -0,0 +1,86 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	base "code.gitea.io/gitea/modules/migration"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIExportImportIssues(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	source := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	target := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: source.OwnerID})
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteIssue)
+
+	req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/export", owner.Name, source.Name)).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var bundle base.IssueBundle
+	DecodeJSON(t, resp, &bundle)
+	assert.Len(t, bundle.Issues, 2)
+	assert.NotEmpty(t, bundle.Comments)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/export?format=csv", owner.Name, source.Name)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	csvIssues, err := base.ReadIssuesCSV(resp.Body)
+	assert.NoError(t, err)
+	assert.Len(t, csvIssues, 2)
+
+	// the attachments of the source can't be downloaded from the local network
+	for _, issue := range bundle.Issues {
+		issue.Attachments = nil
+	}
+	for _, comment := range bundle.Comments {
+		comment.Attachments = nil
+	}
+	numIssues := unittest.GetCount(t, &issues_model.Issue{RepoID: target.ID})
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues/import", owner.Name, target.Name), &bundle).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var apiIssues []*api.Issue
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 2) {
+		assert.Equal(t, "issue1", apiIssues[0].Title)
+		assert.Greater(t, apiIssues[0].Index, int64(numIssues))
+	}
+
+	req = NewRequestWithBody(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues/import", owner.Name, target.Name),
+		strings.NewReader("number,title,labels\n1,from a spreadsheet,label1\n")).AddTokenAuth(token)
+	req.Header.Set("Content-Type", "text/csv")
+	resp = MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiIssues)
+	if assert.Len(t, apiIssues, 1) {
+		assert.Equal(t, "from a spreadsheet", apiIssues[0].Title)
+		assert.Len(t, apiIssues[0].Labels, 1)
+	}
+	assert.Equal(t, numIssues+3, unittest.GetCount(t, &issues_model.Issue{RepoID: target.ID}))
+
+	// the issues must be valid against the schema
+	req = NewRequestWithBody(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues/import", owner.Name, target.Name),
+		strings.NewReader(`{"issues": [{"title": "no number"}]}`)).AddTokenAuth(token)
+	req.Header.Set("Content-Type", "application/json")
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// only the admins of the repository can import issues
+	reader := "user4"
+	readerToken := getUserToken(t, reader, auth_model.AccessTokenScopeWriteIssue)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues/import", owner.Name, source.Name), &bundle).AddTokenAuth(readerToken)
+	MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueFields(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: 1})
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue, auth_model.AccessTokenScopeWriteOrganization)
+	repoURL := fmt.Sprintf("/api/v1/repos/%s/%s", owner.Name, repo.Name)
+
+	// create a field of the organization and one of the repository
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs/%s/issue_fields", owner.Name), &api.CreateIssueFieldOption{
+		Name:    "Priority",
+		Type:    "select",
+		Options: []string{"High", "Low"},
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var priority api.IssueField
+	DecodeJSON(t, resp, &priority)
+	assert.True(t, priority.IsOrgField)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_fields", &api.CreateIssueFieldOption{
+		Name: "Reviewer",
+		Type: "user",
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var reviewer api.IssueField
+	DecodeJSON(t, resp, &reviewer)
+	assert.False(t, reviewer.IsOrgField)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_fields", &api.CreateIssueFieldOption{
+		Name: "Platforms",
+		Type: "multi_select",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", repoURL+"/issue_fields").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var fields []*api.IssueField
+	DecodeJSON(t, resp, &fields)
+	assert.Len(t, fields, 2)
+
+	// set the values of the issue
+	issueFieldsURL := fmt.Sprintf("%s/issues/%d/fields", repoURL, issue.Index)
+	req = NewRequestWithJSON(t, "PATCH", issueFieldsURL, &api.EditIssueFieldsOption{
+		Fields: []api.IssueFieldValueOption{{FieldID: priority.ID, Values: []string{"Medium"}}},
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PATCH", issueFieldsURL, &api.EditIssueFieldsOption{
+		Fields: []api.IssueFieldValueOption{
+			{FieldID: priority.ID, Values: []string{"High"}},
+			{FieldID: reviewer.ID, Values: []string{"user2"}},
+		},
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var values []*api.IssueFieldValue
+	DecodeJSON(t, resp, &values)
+	if assert.Len(t, values, 2) {
+		assert.Equal(t, "Priority", values[0].Field.Name)
+		assert.Equal(t, []string{"High"}, values[0].Values)
+		assert.Equal(t, "Reviewer", values[1].Field.Name)
+		assert.Equal(t, []string{"user2"}, values[1].Values)
+	}
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueFieldValue{IssueID: issue.ID, FieldID: reviewer.ID, Value: "2"})
+
+	// filter the issues by the values
+	req = NewRequest(t, "GET", repoURL+"/issues?state=all&fields="+url.QueryEscape("priority:High")).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var issues []*api.Issue
+	DecodeJSON(t, resp, &issues)
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, issue.Index, issues[0].Index)
+	}
+
+	req = NewRequest(t, "GET", repoURL+"/issues?state=all&fields="+url.QueryEscape("priority:Low")).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &issues)
+	assert.Empty(t, issues)
+
+	// the values are deleted with the field
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/%s/issue_fields/%d", owner.Name, priority.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequest(t, "GET", issueFieldsURL).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &values)
+	if assert.Len(t, values, 1) {
+		assert.Equal(t, "Reviewer", values[0].Field.Name)
+	}
+}
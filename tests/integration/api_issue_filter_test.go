@@ -0,0 +1,172 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueFilters(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue, auth_model.AccessTokenScopeWriteOrganization)
+	repoURL := fmt.Sprintf("/api/v1/repos/%s/%s", owner.Name, repo.Name)
+
+	// save a filter of the organization and one of the repository
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs/%s/issue_filters", owner.Name), &api.CreateIssueFilterOption{
+		Name:   "Org bugs",
+		Labels: []int64{3},
+		State:  "all",
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var orgFilter api.IssueFilter
+	DecodeJSON(t, resp, &orgFilter)
+	assert.Equal(t, "org-bugs", orgFilter.Slug)
+	assert.True(t, orgFilter.IsOrgFilter)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_filters", &api.CreateIssueFilterOption{
+		Name:      "Oldest first",
+		Slug:      "oldest",
+		Sort:      "oldest",
+		IsDefault: true,
+		Shared:    true,
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var repoFilter api.IssueFilter
+	DecodeJSON(t, resp, &repoFilter)
+	assert.Equal(t, "sort=oldest", repoFilter.Query)
+	assert.False(t, repoFilter.IsPersonal)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_filters", &api.CreateIssueFilterOption{
+		Name: "Shuffled",
+		Sort: "random",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", repoURL+"/issue_filters").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var filters []*api.IssueFilter
+	DecodeJSON(t, resp, &filters)
+	assert.Len(t, filters, 2)
+
+	req = NewRequestWithJSON(t, "PATCH", repoURL+"/issue_filters/oldest", &api.EditIssueFilterOption{
+		State: util.ToPointer("closed"),
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &repoFilter)
+	assert.Equal(t, "sort=oldest&state=closed", repoFilter.Query)
+
+	// the filters are applied to the issue list by their slugs, and the default one without any filter
+	issuesURL := fmt.Sprintf("/%s/%s/issues", owner.Name, repo.Name)
+	req = NewRequest(t, "GET", issuesURL+"?filter=org-bugs")
+	resp = session.MakeRequest(t, req, http.StatusSeeOther)
+	assert.Equal(t, issuesURL+"?labels=3&state=all", test.RedirectURL(resp))
+
+	req = NewRequest(t, "GET", issuesURL)
+	resp = session.MakeRequest(t, req, http.StatusSeeOther)
+	assert.Equal(t, issuesURL+"?sort=oldest&state=closed", test.RedirectURL(resp))
+
+	req = NewRequest(t, "GET", issuesURL+"?filter=missing")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "DELETE", repoURL+"/issue_filters/oldest").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequest(t, "GET", issuesURL)
+	session.MakeRequest(t, req, http.StatusOK)
+}
+
+func TestAPIIssueFiltersPersonal(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	ownerToken := getUserToken(t, owner.Name, auth_model.AccessTokenScopeWriteIssue)
+	readerSession := loginUser(t, "user5")
+	readerToken := getTokenForLoggedInUser(t, readerSession, auth_model.AccessTokenScopeWriteIssue)
+	repoURL := fmt.Sprintf("/api/v1/repos/%s/%s", owner.Name, repo.Name)
+
+	// a reader saves personal filters, but can't share them
+	req := NewRequestWithJSON(t, "POST", repoURL+"/issue_filters", &api.CreateIssueFilterOption{
+		Name:  "Mine",
+		State: "closed",
+	}).AddTokenAuth(readerToken)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var personalFilter api.IssueFilter
+	DecodeJSON(t, resp, &personalFilter)
+	assert.True(t, personalFilter.IsPersonal)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_filters", &api.CreateIssueFilterOption{
+		Name:   "Shared",
+		State:  "all",
+		Shared: true,
+	}).AddTokenAuth(readerToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_filters", &api.CreateIssueFilterOption{
+		Name:      "Default",
+		State:     "all",
+		IsDefault: true,
+	}).AddTokenAuth(readerToken)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// the writer shares a filter with the same slug
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_filters", &api.CreateIssueFilterOption{
+		Name:   "Mine",
+		State:  "all",
+		Shared: true,
+	}).AddTokenAuth(ownerToken)
+	MakeRequest(t, req, http.StatusCreated)
+
+	listFilters := func(token string) []*api.IssueFilter {
+		req := NewRequest(t, "GET", repoURL+"/issue_filters").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var filters []*api.IssueFilter
+		DecodeJSON(t, resp, &filters)
+		return filters
+	}
+	assert.Len(t, listFilters(readerToken), 2)
+	if filters := listFilters(ownerToken); assert.Len(t, filters, 1) {
+		assert.False(t, filters[0].IsPersonal)
+	}
+
+	// the personal filter is preferred over the shared one with the same slug
+	req = NewRequest(t, "GET", repoURL+"/issue_filters/mine").AddTokenAuth(readerToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var f api.IssueFilter
+	DecodeJSON(t, resp, &f)
+	assert.Equal(t, personalFilter.ID, f.ID)
+
+	issuesURL := fmt.Sprintf("/%s/%s/issues", owner.Name, repo.Name)
+	req = NewRequest(t, "GET", issuesURL+"?filter=mine")
+	resp = readerSession.MakeRequest(t, req, http.StatusSeeOther)
+	assert.Equal(t, issuesURL+"?state=closed", test.RedirectURL(resp))
+	req = NewRequest(t, "GET", issuesURL+"?filter=mine")
+	resp = MakeRequest(t, req, http.StatusSeeOther)
+	assert.Equal(t, issuesURL+"?state=all", test.RedirectURL(resp))
+
+	// the reader changes their personal filter, but not the shared one
+	req = NewRequest(t, "DELETE", repoURL+"/issue_filters/mine").AddTokenAuth(readerToken)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "DELETE", repoURL+"/issue_filters/mine").AddTokenAuth(readerToken)
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "DELETE", repoURL+"/issue_filters/mine").AddTokenAuth(ownerToken)
+	MakeRequest(t, req, http.StatusNoContent)
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPISubIssues(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	parent := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: 1})
+	openChild := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: 2})
+	closedChild := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID, Index: 4})
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteIssue)
+	issueURL := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d", owner.Name, repo.Name, parent.Index)
+
+	for _, child := range []*issues_model.Issue{openChild, closedChild} {
+		req := NewRequestWithJSON(t, "POST", issueURL+"/sub_issues", &api.IssueMeta{
+			Owner: owner.Name,
+			Name:  repo.Name,
+			Index: child.Index,
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+	}
+
+	// an issue can't be a sub-issue of its sub-issue
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/sub_issues", owner.Name, repo.Name, openChild.Index), &api.IssueMeta{
+		Owner: owner.Name,
+		Name:  repo.Name,
+		Index: parent.Index,
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", issueURL+"/sub_issues").AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var subIssues []*api.Issue
+	DecodeJSON(t, resp, &subIssues)
+	if assert.Len(t, subIssues, 2) {
+		assert.Equal(t, openChild.Index, subIssues[0].Index)
+		assert.Equal(t, closedChild.Index, subIssues[1].Index)
+	}
+
+	req = NewRequest(t, "GET", issueURL).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	if assert.NotNil(t, apiIssue.SubIssues) {
+		assert.EqualValues(t, 2, apiIssue.SubIssues.Total)
+		assert.EqualValues(t, 1, apiIssue.SubIssues.Closed)
+		assert.Equal(t, 50, apiIssue.SubIssues.PercentCompleted)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/parent", owner.Name, repo.Name, closedChild.Index)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiIssue)
+	assert.Equal(t, parent.Index, apiIssue.Index)
+
+	req = NewRequestWithJSON(t, "DELETE", issueURL+"/sub_issues", &api.IssueMeta{
+		Owner: owner.Name,
+		Name:  repo.Name,
+		Index: closedChild.Index,
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusOK)
+	unittest.AssertNotExistsBean(t, &issues_model.IssueRelation{ChildID: closedChild.ID})
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/parent", owner.Name, repo.Name, closedChild.Index)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueTypes(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue, auth_model.AccessTokenScopeWriteOrganization)
+	repoURL := fmt.Sprintf("/api/v1/repos/%s/%s", owner.Name, repo.Name)
+
+	// create a type of the organization and one of the repository
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs/%s/issue_types", owner.Name), &api.CreateIssueTypeOption{
+		Name: "Epic",
+		Icon: "octicon-project",
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var epic api.IssueType
+	DecodeJSON(t, resp, &epic)
+	assert.True(t, epic.IsOrgType)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_types", &api.CreateIssueTypeOption{
+		Name:           "Bug",
+		Icon:           "octicon-bug",
+		Color:          "#ee0701",
+		Template:       "## Steps to reproduce",
+		RequiredFields: []string{"labels"},
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var bug api.IssueType
+	DecodeJSON(t, resp, &bug)
+	assert.False(t, bug.IsOrgType)
+	assert.Equal(t, []string{"labels"}, bug.RequiredFields)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issue_types", &api.CreateIssueTypeOption{
+		Name:           "Feature",
+		RequiredFields: []string{"title"},
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", repoURL+"/issue_types").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var types []*api.IssueType
+	DecodeJSON(t, resp, &types)
+	if assert.Len(t, types, 2) {
+		assert.Equal(t, "Bug", types[0].Name)
+		assert.Equal(t, "Epic", types[1].Name)
+	}
+
+	// the types of the organization can't be changed through the repository
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/issue_types/%d", repoURL, epic.ID), &api.EditIssueTypeOption{}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// the required fields are checked when the issue is created
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issues", &api.CreateIssueOption{
+		Title: "a bug",
+		Type:  bug.ID,
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	label := &issues_model.Label{RepoID: repo.ID, Name: "bug", Color: "#ee0701"}
+	assert.NoError(t, issues_model.NewLabel(db.DefaultContext, label))
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issues", &api.CreateIssueOption{
+		Title:  "a bug",
+		Type:   bug.ID,
+		Labels: []int64{label.ID},
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	if assert.NotNil(t, apiIssue.Type) {
+		assert.Equal(t, bug.ID, apiIssue.Type.ID)
+	}
+	assert.Equal(t, "## Steps to reproduce", apiIssue.Body)
+
+	// filter the issues by type
+	req = NewRequest(t, "GET", repoURL+"/issues?state=all&issue_types=bug").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var issues []*api.Issue
+	DecodeJSON(t, resp, &issues)
+	if assert.Len(t, issues, 1) {
+		assert.Equal(t, apiIssue.ID, issues[0].ID)
+	}
+
+	// change the type of the issue
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/issues/%d", repoURL, apiIssue.Index), &api.EditIssueOption{
+		Type: &epic.ID,
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiIssue)
+	if assert.NotNil(t, apiIssue.Type) {
+		assert.Equal(t, epic.ID, apiIssue.Type.ID)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/issues?state=all&issue_types=%d", repoURL, epic.ID)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &issues)
+	assert.Len(t, issues, 1)
+
+	// the issues of a deleted type don't have a type anymore
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/%s/issue_types/%d", owner.Name, epic.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: apiIssue.ID, TypeID: 0})
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueVotes(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/votes", repo.OwnerName, repo.Name, issue.Index)
+	token := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteIssue)
+
+	MakeRequest(t, NewRequest(t, "POST", urlStr), http.StatusUnauthorized)
+	MakeRequest(t, NewRequest(t, "POST", urlStr).AddTokenAuth(token), http.StatusCreated)
+	MakeRequest(t, NewRequest(t, "POST", urlStr).AddTokenAuth(token), http.StatusOK)
+
+	resp := MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusOK)
+	var voters []*api.User
+	DecodeJSON(t, resp, &voters)
+	if assert.Len(t, voters, 1) {
+		assert.Equal(t, "user4", voters[0].UserName)
+	}
+	assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+
+	resp = MakeRequest(t, NewRequestf(t, "GET", "/api/v1/repos/%s/%s/issues?state=all&sort=mostvotes", repo.OwnerName, repo.Name), http.StatusOK)
+	var issues []*api.Issue
+	DecodeJSON(t, resp, &issues)
+	if assert.NotEmpty(t, issues) {
+		assert.Equal(t, issue.ID, issues[0].ID)
+		assert.Equal(t, 1, issues[0].Votes)
+	}
+
+	MakeRequest(t, NewRequest(t, "DELETE", urlStr).AddTokenAuth(token), http.StatusNoContent)
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issue.ID})
+	assert.Equal(t, 0, issue.NumVotes)
+}
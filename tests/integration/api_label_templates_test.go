@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/models/db"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/tests"
@@ -49,7 +50,7 @@ func TestAPIGetLabelTemplateInfo(t *testing.T) {
 	var templateInfo []api.LabelTemplate
 	DecodeJSON(t, resp, &templateInfo)
 
-	labels, err := repo_module.LoadTemplateLabelsByDisplayName(templateName)
+	labels, err := repo_module.LoadTemplateLabelsByDisplayName(db.DefaultContext, templateName)
 	assert.NoError(t, err)
 
 	for i := range labels {
@@ -0,0 +1,115 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgMilestones(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue, auth_model.AccessTokenScopeWriteOrganization)
+	orgURL := fmt.Sprintf("/api/v1/orgs/%s/milestones", owner.Name)
+	repoURL := fmt.Sprintf("/api/v1/repos/%s/%s", owner.Name, repo.Name)
+
+	req := NewRequestWithJSON(t, "POST", orgURL, &api.CreateMilestoneOption{
+		Title:       "v2.0",
+		Description: "the next major release",
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var milestone api.Milestone
+	DecodeJSON(t, resp, &milestone)
+	assert.True(t, milestone.IsOrgMilestone)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Milestone{ID: milestone.ID, OrgID: owner.ID, RepoID: 0})
+
+	req = NewRequest(t, "GET", orgURL).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var milestones []*api.Milestone
+	DecodeJSON(t, resp, &milestones)
+	if assert.Len(t, milestones, 1) {
+		assert.Equal(t, "v2.0", milestones[0].Title)
+	}
+
+	// the milestone of the organization can't be changed through the repository
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/milestones/%d", repoURL, milestone.ID), &api.EditMilestoneOption{}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// the issues of the repositories of the organization can have the milestone
+	req = NewRequestWithJSON(t, "POST", repoURL+"/issues", &api.CreateIssueOption{
+		Title:     "shared milestone",
+		Milestone: milestone.ID,
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	if assert.NotNil(t, apiIssue.Milestone) {
+		assert.Equal(t, milestone.ID, apiIssue.Milestone.ID)
+	}
+
+	publicRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 32, OwnerID: owner.ID, IsPrivate: false})
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues", owner.Name, publicRepo.Name), &api.CreateIssueOption{
+		Title:     "shared milestone in a public repository",
+		Milestone: milestone.ID,
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+
+	burndownURL := fmt.Sprintf("%s/%d/burndown", orgURL, milestone.ID)
+	req = NewRequest(t, "GET", burndownURL).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var burndown api.MilestoneBurndown
+	DecodeJSON(t, resp, &burndown)
+	assert.Equal(t, 2, burndown.Milestone.OpenIssues)
+	if assert.NotEmpty(t, burndown.Days) {
+		assert.Equal(t, 2, burndown.Days[len(burndown.Days)-1].OpenIssues)
+	}
+	if assert.Len(t, burndown.Repos, 2) {
+		assert.Equal(t, repo.FullName(), burndown.Repos[0].Repository.FullName)
+		assert.Equal(t, 1, burndown.Repos[0].OpenIssues)
+		assert.Equal(t, publicRepo.FullName(), burndown.Repos[1].Repository.FullName)
+	}
+
+	// the issues of the private repository aren't counted for the users who can't read it
+	outsiderToken := getUserToken(t, "user5", auth_model.AccessTokenScopeReadOrganization)
+	for _, req := range []*RequestWrapper{NewRequest(t, "GET", burndownURL), NewRequest(t, "GET", burndownURL).AddTokenAuth(outsiderToken)} {
+		resp = MakeRequest(t, req, http.StatusOK)
+		var outsiderBurndown api.MilestoneBurndown
+		DecodeJSON(t, resp, &outsiderBurndown)
+		assert.Equal(t, 1, outsiderBurndown.Milestone.OpenIssues)
+		if assert.NotEmpty(t, outsiderBurndown.Days) {
+			assert.Equal(t, 1, outsiderBurndown.Days[len(outsiderBurndown.Days)-1].OpenIssues)
+		}
+		if assert.Len(t, outsiderBurndown.Repos, 1) {
+			assert.Equal(t, publicRepo.FullName(), outsiderBurndown.Repos[0].Repository.FullName)
+		}
+	}
+
+	state := "closed"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/%d", orgURL, milestone.ID), &api.EditMilestoneOption{
+		State: &state,
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &milestone)
+	assert.Equal(t, api.StateClosed, milestone.State)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d", orgURL, milestone.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	unittest.AssertNotExistsBean(t, &issues_model.Milestone{ID: milestone.ID})
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: apiIssue.ID, MilestoneID: 0})
+}
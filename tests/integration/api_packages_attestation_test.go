@@ -0,0 +1,265 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	packages_service "code.gitea.io/gitea/services/packages"
+	"code.gitea.io/gitea/tests"
+
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAttestation(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+
+	t.Run("API", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		packageName := "attested-package"
+		packageVersion := "1.0.0"
+
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/%s/%s/file.bin", user.Name, packageName, packageVersion), strings.NewReader("content")).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		apiURL := fmt.Sprintf("/api/v1/packages/%s/generic/%s/%s/attestations", user.Name, packageName, packageVersion)
+
+		req = NewRequest(t, "GET", apiURL).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var attestations []*api.PackageAttestation
+		DecodeJSON(t, resp, &attestations)
+		assert.Empty(t, attestations)
+
+		req = NewRequestWithJSON(t, "POST", apiURL, &api.CreatePackageAttestationOption{
+			Bundle: "{}",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", apiURL, &api.CreatePackageAttestationOption{
+			PredicateType: "https://slsa.dev/provenance/v1",
+			MediaType:     "application/vnd.dev.sigstore.bundle.v0.3+json",
+			Bundle:        "{}",
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+
+		var attestation *api.PackageAttestation
+		DecodeJSON(t, resp, &attestation)
+		assert.Equal(t, "https://slsa.dev/provenance/v1", attestation.PredicateType)
+		assert.Equal(t, "{}", attestation.Bundle)
+
+		req = NewRequest(t, "GET", apiURL).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		DecodeJSON(t, resp, &attestations)
+		assert.Len(t, attestations, 1)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/generic/%s/%s", user.Name, packageName, packageVersion)).
+			AddBasicAuth(user.Name)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "https://slsa.dev/provenance/v1")
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d", apiURL, attestation.ID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d", apiURL, attestation.ID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Npm", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		packageName := "@scope/provenance-package"
+		packageVersion := "1.0.0"
+		predicateType := "https://slsa.dev/provenance/v1"
+		bundleMediaType := "application/vnd.dev.sigstore.bundle.v0.3+json"
+
+		data := "H4sIAAAAAAAA/ytITM5OTE/VL4DQelnF+XkMVAYGBgZmJiYK2MRBwNDcSIHB2NTMwNDQzMwAqA7IMDUxA9LUdgg2UFpcklgEdAql5kD8ogCnhwio5lJQUMpLzE1VslJQcihOzi9I1S9JLS7RhSYIJR2QgrLUouLM/DyQGkM9Az1D3YIiqExKanFyUWZBCVQ2BKhVwQVJDKwosbQkI78IJO/tZ+LsbRykxFXLNdA+HwWjYBSMgpENACgAbtAACAAA"
+		tarball, _ := base64.StdEncoding.DecodeString(data)
+		hash := sha512.Sum512(tarball)
+
+		statement := fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"pkg:npm/%s@%s","digest":{"sha512":"%s"}}],"predicateType":"%s","predicate":{}}`, url.PathEscape(packageName), packageVersion, hex.EncodeToString(hash[:]), predicateType)
+		bundle := fmt.Sprintf(`{"mediaType":"%s","dsseEnvelope":{"payload":"%s","payloadType":"%s","signatures":[]}}`, bundleMediaType, base64.StdEncoding.EncodeToString([]byte(statement)), npm_module.InTotoPayloadType)
+
+		buildUpload := func(version string, withProvenance bool) string {
+			attachments := `"` + packageName + `-` + version + `.tgz": {
+				"data": "` + data + `"
+			}`
+			if withProvenance {
+				attachments += `, "` + packageName + `-` + version + `.sigstore": {
+					"content_type": "` + bundleMediaType + `",
+					"data": ` + fmt.Sprintf("%q", bundle) + `
+				}`
+			}
+			return `{
+				"_id": "` + packageName + `",
+				"name": "` + packageName + `",
+				"versions": {
+					"` + version + `": {
+						"name": "` + packageName + `",
+						"version": "` + version + `",
+						"dist": {
+							"integrity": "sha512-yA4FJsVhetynGfOC1jFf79BuS+jrHbm0fhh+aHzCQkOaOBXKf9oBnC4a6DnLLnEsHQDRLYd00cwj8sCXpC+wIg==",
+							"shasum": "aaa7eaf852a948b0aa05afeda35b1badca155d90"
+						}
+					}
+				},
+				"_attachments": {` + attachments + `}
+			}`
+		}
+
+		root := fmt.Sprintf("/api/packages/%s/npm/%s", user.Name, url.QueryEscape(packageName))
+
+		assert.NoError(t, packages_service.SetSignatureRequired(db.DefaultContext, user.ID, true))
+
+		req := NewRequestWithBody(t, "PUT", root, strings.NewReader(buildUpload(packageVersion, false))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithBody(t, "PUT", root, strings.NewReader(buildUpload(packageVersion, true))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		assert.NoError(t, packages_service.SetSignatureRequired(db.DefaultContext, user.ID, false))
+
+		req = NewRequestWithBody(t, "PUT", root, strings.NewReader(buildUpload("1.0.1", false))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequest(t, "GET", root).
+			AddBasicAuth(user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result npm_module.PackageMetadata
+		DecodeJSON(t, resp, &result)
+
+		assert.Nil(t, result.Versions["1.0.1"].Dist.Attestations)
+		attestations := result.Versions[packageVersion].Dist.Attestations
+		assert.NotNil(t, attestations)
+		assert.Equal(t, predicateType, attestations.Provenance.PredicateType)
+
+		req = NewRequest(t, "GET", strings.TrimPrefix(attestations.URL, setting.AppURL[:len(setting.AppURL)-1])).
+			AddBasicAuth(user.Name)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		var bundles npm_module.PackageAttestations
+		DecodeJSON(t, resp, &bundles)
+		assert.Len(t, bundles.Attestations, 1)
+		assert.Equal(t, predicateType, bundles.Attestations[0].PredicateType)
+		assert.JSONEq(t, bundle, string(bundles.Attestations[0].Bundle))
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/npm/-/npm/v1/attestations/%s@1.0.1", user.Name, url.QueryEscape(packageName))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Container", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		image := "signed-image"
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%sv2/token", setting.AppURL)).
+			AddBasicAuth(user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		tokenResponse := &struct {
+			Token string `json:"token"`
+		}{}
+		DecodeJSON(t, resp, &tokenResponse)
+		userToken := "Bearer " + tokenResponse.Token
+
+		blobDigest := "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+		blobContent, _ := base64.StdEncoding.DecodeString(`H4sIAAAJbogA/2IYBaNgFIxYAAgAAP//Lq+17wAEAAA=`)
+		configContent := `{"architecture":"amd64","os":"linux","config":{}}`
+		configHash := sha256.Sum256([]byte(configContent))
+		configDigest := "sha256:" + hex.EncodeToString(configHash[:])
+
+		createManifest := func(layerMediaType string) (string, string) {
+			content := `{"schemaVersion":2,"mediaType":"` + oci.MediaTypeImageManifest + `","config":{"mediaType":"` + oci.MediaTypeImageConfig + `","digest":"` + configDigest + `","size":` + fmt.Sprint(len(configContent)) + `},"layers":[{"mediaType":"` + layerMediaType + `","digest":"` + blobDigest + `","size":32}]}`
+			hash := sha256.Sum256([]byte(content))
+			return content, hex.EncodeToString(hash[:])
+		}
+
+		url := fmt.Sprintf("%sv2/%s/%s", setting.AppURL, user.Name, image)
+
+		for digest, content := range map[string][]byte{blobDigest: blobContent, configDigest: []byte(configContent)} {
+			req = NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", url, digest), bytes.NewReader(content)).
+				AddTokenAuth(userToken)
+			MakeRequest(t, req, http.StatusCreated)
+		}
+
+		manifestContent, manifestHash := createManifest(oci.MediaTypeImageLayerGzip)
+		signatureContent, _ := createManifest("application/vnd.dev.cosign.simplesigning.v1+json")
+
+		uploadManifest := func(reference, content string, expectedStatus int) {
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, reference), strings.NewReader(content)).
+				AddTokenAuth(userToken).
+				SetHeader("Content-Type", oci.MediaTypeImageManifest)
+			MakeRequest(t, req, expectedStatus)
+		}
+
+		assert.NoError(t, packages_service.SetSignatureRequired(db.DefaultContext, user.ID, true))
+		defer func() {
+			assert.NoError(t, packages_service.SetSignatureRequired(db.DefaultContext, user.ID, false))
+		}()
+
+		// an unsigned manifest can only be pushed by digest
+		uploadManifest("latest", manifestContent, http.StatusForbidden)
+		uploadManifest("sha256:"+manifestHash, manifestContent, http.StatusCreated)
+
+		// the signature tag of the manifest
+		uploadManifest("sha256-"+manifestHash+".sig", signatureContent, http.StatusCreated)
+
+		uploadManifest("latest", manifestContent, http.StatusCreated)
+
+		for _, reference := range []string{"sha256:" + manifestHash, "latest"} {
+			pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, image, reference)
+			assert.NoError(t, err)
+
+			pas, err := packages_model.GetAttestationsByVersionID(db.DefaultContext, pv.ID)
+			assert.NoError(t, err)
+			assert.Len(t, pas, 1)
+			assert.Equal(t, signatureContent, pas[0].Content)
+		}
+
+		// the signature is removed together with its tag
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/manifests/sha256-%s.sig", url, manifestHash)).
+			AddTokenAuth(userToken)
+		MakeRequest(t, req, http.StatusAccepted)
+
+		pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, image, "latest")
+		assert.NoError(t, err)
+
+		has, err := packages_model.HasAttestations(db.DefaultContext, pv.ID)
+		assert.NoError(t, err)
+		assert.False(t, has)
+	})
+}
@@ -0,0 +1,208 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAudit(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage, auth_model.AccessTokenScopeWriteUser)
+
+	packageName := "audited-package"
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/user/hooks", &api.CreateHookOption{
+		Type: "gitea",
+		Config: api.CreateHookOptionConfig{
+			"content_type": "json",
+			"url":          "http://example.com/",
+		},
+		Events:            []string{string(webhook_module.HookEventPackage)},
+		PackageTypeFilter: "generic",
+		Active:            true,
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+
+	var hook *api.Hook
+	DecodeJSON(t, resp, &hook)
+	assert.Equal(t, "generic", hook.PackageTypeFilter)
+	assert.Contains(t, hook.Events, string(webhook_module.HookEventPackage))
+
+	getPackagePayloads := func(t *testing.T) []*api.PackagePayload {
+		t.Helper()
+
+		tasks, err := webhook_model.HookTasks(db.DefaultContext, hook.ID, 1)
+		assert.NoError(t, err)
+
+		payloads := make([]*api.PackagePayload, 0, len(tasks))
+		for _, task := range tasks {
+			assert.Equal(t, webhook_module.HookEventPackage, task.EventType)
+
+			var payload *api.PackagePayload
+			assert.NoError(t, json.Unmarshal([]byte(task.PayloadContent), &payload))
+			payloads = append(payloads, payload)
+		}
+		return payloads
+	}
+
+	uploadPackage := func(t *testing.T, version string) {
+		t.Helper()
+
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/%s/%s/file.bin", user.Name, packageName, version), strings.NewReader("content")).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+	}
+
+	listAudit := func(t *testing.T, query string) []*api.PackageAudit {
+		t.Helper()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/audit?%s", user.Name, query)).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var audits []*api.PackageAudit
+		DecodeJSON(t, resp, &audits)
+		return audits
+	}
+
+	t.Run("Publish", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		uploadPackage(t, "1.0.0")
+		uploadPackage(t, "1.1.0")
+
+		payloads := getPackagePayloads(t)
+		assert.Len(t, payloads, 2)
+		assert.Equal(t, api.HookPackageCreated, payloads[0].Action)
+		assert.Equal(t, "1.1.0", payloads[0].Package.Version)
+		assert.Len(t, payloads[0].Files, 1)
+		assert.Equal(t, "file.bin", payloads[0].Files[0].Name)
+
+		audits := listAudit(t, "")
+		assert.Len(t, audits, 2)
+		assert.Equal(t, "publish", audits[0].Action)
+		assert.Equal(t, "generic", audits[0].Type)
+		assert.Equal(t, packageName, audits[0].Name)
+		assert.Equal(t, "1.1.0", audits[0].Version)
+		assert.Equal(t, user.Name, audits[0].Doer.UserName)
+	})
+
+	t.Run("Immutable", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "PUT", fmt.Sprintf("/api/v1/packages/%s/generic/%s/-/immutable", user.Name, packageName)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		audits := listAudit(t, "action=set_immutable")
+		assert.Len(t, audits, 1)
+		assert.Empty(t, audits[0].Version)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/packages/%s/generic/%s", user.Name, packageName)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+		adminToken := getUserToken(t, admin.Name, auth_model.AccessTokenScopeWritePackage)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/packages/%s/generic/%s/-/immutable", user.Name, packageName)).
+			AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		audits = listAudit(t, "action=unset_immutable")
+		assert.Len(t, audits, 1)
+		assert.Equal(t, admin.Name, audits[0].Doer.UserName)
+	})
+
+	t.Run("DeleteVersion", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/packages/%s/generic/%s/1.0.0", user.Name, packageName)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		payloads := getPackagePayloads(t)
+		assert.Len(t, payloads, 3)
+		assert.Equal(t, api.HookPackageDeleted, payloads[0].Action)
+		assert.Equal(t, "1.0.0", payloads[0].Package.Version)
+
+		audits := listAudit(t, "action=delete_version")
+		assert.Len(t, audits, 1)
+		assert.Equal(t, "1.0.0", audits[0].Version)
+	})
+
+	t.Run("DeletePackage", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		uploadPackage(t, "1.2.0")
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/packages/%s/generic/%s", user.Name, packageName)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/packages/%s/generic/%s", user.Name, packageName)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		payloads := getPackagePayloads(t)
+		assert.Len(t, payloads, 5)
+		assert.Equal(t, api.HookPackageRemoved, payloads[0].Action)
+		assert.Equal(t, packageName, payloads[0].Package.Name)
+		assert.Empty(t, payloads[0].Package.Version)
+		assert.ElementsMatch(t, []string{"1.1.0", "1.2.0"}, payloads[0].Versions)
+
+		audits := listAudit(t, "action=delete_package")
+		assert.Len(t, audits, 1)
+		assert.Empty(t, audits[0].Version)
+
+		audits = listAudit(t, "name="+packageName)
+		assert.Len(t, audits, 7)
+		assert.Empty(t, listAudit(t, "type=npm"))
+	})
+
+	t.Run("TypeFilter", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		uploadPackage(t, "2.0.0")
+		assert.Len(t, getPackagePayloads(t), 6)
+
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/user/hooks/%d", hook.ID), &api.EditHookOption{
+			Events:            []string{string(webhook_module.HookEventPackage)},
+			PackageTypeFilter: "{npm,container}",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		uploadPackage(t, "2.1.0")
+		assert.Len(t, getPackagePayloads(t), 6)
+	})
+
+	t.Run("Permission", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+		otherToken := getUserToken(t, other.Name, auth_model.AccessTokenScopeReadPackage)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/audit", user.Name)).
+			AddTokenAuth(otherToken)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
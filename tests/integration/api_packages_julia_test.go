@@ -0,0 +1,211 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	julia_module "code.gitea.io/gitea/modules/packages/julia"
+	julia_router "code.gitea.io/gitea/routers/api/packages/julia"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageJulia(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageName := "Example"
+	packageUUID := "7876af07-990d-54b4-ab0e-23690620f79a"
+	packageVersion := "0.5.4"
+	packageAuthor := "KN4CK3R"
+
+	createArchive := func(name, uuid, version string) []byte {
+		files := map[string]string{
+			"Project.toml": fmt.Sprintf(`name = "%s"
+uuid = "%s"
+version = "%s"
+authors = ["%s"]
+
+[deps]
+LinearAlgebra = "37e2e46d-f89d-539d-b4ee-838fcccc9c8e"
+
+[compat]
+julia = "1.6"
+`, name, uuid, version, packageAuthor),
+			"src/" + name + ".jl": "module " + name + "\nend\n",
+		}
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(zw)
+		for filename, content := range files {
+			hdr := &tar.Header{
+				Name: filename,
+				Mode: 0o644,
+				Size: int64(len(content)),
+			}
+			tw.WriteHeader(hdr)
+			tw.Write([]byte(content))
+		}
+		tw.Close()
+		zw.Close()
+		return buf.Bytes()
+	}
+
+	content := createArchive(packageName, packageUUID, packageVersion)
+
+	pck, err := julia_module.ParsePackage(bytes.NewReader(content))
+	assert.NoError(t, err)
+
+	url := fmt.Sprintf("/api/packages/%s/julia", user.Name)
+
+	t.Run("Upload", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "PUT", url, bytes.NewReader(content))
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithBody(t, "PUT", url, bytes.NewReader([]byte{1, 2, 3})).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "PUT", url, bytes.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeJulia)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 1)
+
+		pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+		assert.NoError(t, err)
+		assert.NotNil(t, pd.SemVer)
+		assert.IsType(t, &julia_module.Metadata{}, pd.Metadata)
+		assert.Equal(t, packageName, pd.Package.Name)
+		assert.Equal(t, packageVersion, pd.Version.Version)
+		assert.Equal(t, packageUUID, pd.PackageProperties.GetByName(julia_module.PropertyUUID))
+		assert.Equal(t, pck.TreeHash, pd.VersionProperties.GetByName(julia_module.PropertyTreeHash))
+
+		pfs, err := packages.GetFilesByVersionID(db.DefaultContext, pvs[0].ID)
+		assert.NoError(t, err)
+		assert.Len(t, pfs, 1)
+		assert.Equal(t, fmt.Sprintf("%s-%s.tar.gz", packageName, packageVersion), pfs[0].Name)
+		assert.True(t, pfs[0].IsLead)
+
+		req = NewRequestWithBody(t, "PUT", url, bytes.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusConflict)
+
+		// the uuid of the package must not change
+		req = NewRequestWithBody(t, "PUT", url, bytes.NewReader(createArchive(packageName, "a4d3c8fc-a50c-4623-92a3-3e1b6a59b5e1", "1.0.0"))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		// the uuid must not be used by another package
+		req = NewRequestWithBody(t, "PUT", url, bytes.NewReader(createArchive("Other", packageUUID, "1.0.0"))).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		registryUUID := julia_router.RegistryUUID(user)
+
+		var registryPath string
+		for _, suffix := range []string{"", ".eager", ".conservative"} {
+			req := NewRequest(t, "GET", url+"/registries"+suffix).
+				AddBasicAuth(user.Name)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			registryPath = strings.TrimSpace(resp.Body.String())
+			assert.True(t, strings.HasPrefix(registryPath, "/registry/"+registryUUID+"/"))
+		}
+
+		req := NewRequest(t, "GET", url+"/registries.other").
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", url+"/registry/"+registryUUID+"/0000000000000000000000000000000000000000").
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", url+registryPath).
+			AddBasicAuth(user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		files := make(map[string]string)
+		zr, err := gzip.NewReader(resp.Body)
+		assert.NoError(t, err)
+		tr := tar.NewReader(zr)
+		for {
+			hd, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+
+			data, err := io.ReadAll(tr)
+			assert.NoError(t, err)
+			files[hd.Name] = string(data)
+		}
+
+		assert.Contains(t, files["Registry.toml"], registryUUID)
+		assert.Contains(t, files["Registry.toml"], packageUUID)
+		assert.Contains(t, files["E/Example/Package.toml"], packageName)
+		assert.Contains(t, files["E/Example/Versions.toml"], pck.TreeHash)
+		assert.Contains(t, files["E/Example/Deps.toml"], "LinearAlgebra")
+		assert.Contains(t, files["E/Example/Compat.toml"], "1.6-1")
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/package/%s/%s", url, packageUUID, pck.TreeHash)).
+			AddBasicAuth(user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, content, resp.Body.Bytes())
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/package/%s/0000000000000000000000000000000000000000", url, packageUUID)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/package/a4d3c8fc-a50c-4623-92a3-3e1b6a59b5e1/%s", url, pck.TreeHash)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("%s/%s/%s", url, packageName, packageVersion))
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%s/%s", url, packageName, packageVersion)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeJulia)
+		assert.NoError(t, err)
+		assert.Empty(t, pvs)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%s/%s", url, packageName, packageVersion)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
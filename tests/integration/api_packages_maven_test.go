@@ -10,12 +10,15 @@ import (
 	"strings"
 	"testing"
 
+	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/packages/maven"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/test"
+	maven_service "code.gitea.io/gitea/services/packages/maven"
 	"code.gitea.io/gitea/tests"
 
 	"github.com/stretchr/testify/assert"
@@ -252,3 +255,93 @@ func TestPackageMaven(t *testing.T) {
 		assert.True(t, test.IsNormalPageCompleted(resp.Body.String()))
 	})
 }
+
+func TestPackageMavenSnapshot(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+
+	groupID := "com.gitea"
+	artifactID := "snapshot-project"
+	packageName := groupID + "-" + artifactID
+	snapshotVersion := "2.0-SNAPSHOT"
+
+	root := fmt.Sprintf("/api/packages/%s/maven/%s/%s/%s", user.Name, strings.ReplaceAll(groupID, ".", "/"), artifactID, snapshotVersion)
+
+	putFile := func(t *testing.T, filename, content string, expectedStatus int) {
+		t.Helper()
+
+		req := NewRequestWithBody(t, "PUT", root+"/"+filename, strings.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, expectedStatus)
+	}
+
+	getMetadata := func(t *testing.T) string {
+		t.Helper()
+
+		req := NewRequest(t, "GET", root+"/maven-metadata.xml").
+			AddBasicAuth(user.Name)
+		return MakeRequest(t, req, http.StatusOK).Body.String()
+	}
+
+	getVersion := func(t *testing.T) *packages.PackageDescriptor {
+		t.Helper()
+
+		pv, err := packages.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages.TypeMaven, packageName, snapshotVersion)
+		assert.NoError(t, err)
+		pd, err := packages.GetPackageDescriptor(db.DefaultContext, pv)
+		assert.NoError(t, err)
+		return pd
+	}
+
+	for build := 1; build <= 3; build++ {
+		prefix := fmt.Sprintf("%s-2.0-20240101.10000%d-%d", artifactID, build, build)
+		putFile(t, prefix+".jar", "jar", http.StatusCreated)
+		putFile(t, prefix+"-sources.jar", "sources", http.StatusCreated)
+		putFile(t, prefix+".pom", fmt.Sprintf(`<?xml version="1.0"?><project><groupId>%s</groupId><artifactId>%s</artifactId><version>%s</version><description>build %d</description></project>`, groupID, artifactID, snapshotVersion, build), http.StatusCreated)
+	}
+	putFile(t, "maven-metadata.xml", "corrupted", http.StatusCreated)
+
+	t.Run("RebuildMetadata", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		pd := getVersion(t)
+		pd.Version.MetadataJSON = ""
+		assert.NoError(t, packages.UpdateVersion(db.DefaultContext, pd.Version))
+
+		req := NewRequest(t, "POST", fmt.Sprintf("/api/v1/packages/%s/maven/%s/-/rebuild_metadata", user.Name, packageName)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		pd = getVersion(t)
+		assert.Equal(t, "build 3", pd.Metadata.(*maven.Metadata).Description)
+
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<metadata modelVersion="1.1.0"><groupId>com.gitea</groupId><artifactId>snapshot-project</artifactId><version>2.0-SNAPSHOT</version><versioning><snapshot><timestamp>20240101.100003</timestamp><buildNumber>3</buildNumber></snapshot><lastUpdated>20240101100003</lastUpdated><snapshotVersions><snapshotVersion><extension>jar</extension><value>2.0-20240101.100003-3</value><updated>20240101100003</updated></snapshotVersion><snapshotVersion><classifier>sources</classifier><extension>jar</extension><value>2.0-20240101.100003-3</value><updated>20240101100003</updated></snapshotVersion><snapshotVersion><extension>pom</extension><value>2.0-20240101.100003-3</value><updated>20240101100003</updated></snapshotVersion></snapshotVersions></versioning></metadata>`, getMetadata(t))
+
+		req = NewRequest(t, "POST", fmt.Sprintf("/api/v1/packages/%s/maven/%s/-/rebuild_metadata", user.Name, "unknown")).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("CleanupBuilds", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		assert.NoError(t, maven_service.CleanupSnapshotVersions(db.DefaultContext))
+		assert.Len(t, getVersion(t).Files, 10)
+
+		defer test.MockVariableValue(&setting.Packages.RetainMavenSnapshotBuilds, 2)()
+
+		putFile(t, "maven-metadata.xml", "corrupted", http.StatusCreated)
+
+		assert.NoError(t, maven_service.CleanupSnapshotVersions(db.DefaultContext))
+
+		pd := getVersion(t)
+		assert.Len(t, pd.Files, 7)
+		for _, pfd := range pd.Files {
+			assert.NotContains(t, pfd.File.Name, "-1.")
+			assert.NotContains(t, pfd.File.Name, "-1-")
+		}
+		assert.Contains(t, getMetadata(t), "<buildNumber>3</buildNumber>")
+	})
+}
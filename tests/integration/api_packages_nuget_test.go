@@ -14,6 +14,7 @@ import (
 	"net/http/httptest"
 	neturl "net/url"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -89,6 +90,7 @@ func TestPackageNuGet(t *testing.T) {
 	packageDescription := "Gitea Test Package"
 	symbolFilename := "test.pdb"
 	symbolID := "d910bb6948bd4c6cb40155bcf52c3c94"
+	symbolChecksum := "d6a673a82db00888c65361a039226e7b34ff689677bf9ee5862d014a63d8a7e2"
 
 	createNuspec := func(id, version string) string {
 		return `<?xml version="1.0" encoding="utf-8"?>
@@ -344,9 +346,9 @@ AAAjQmxvYgAAAGm7ENm9SGxMtAFVvPUsPJTF6PbtAAAAAFcVogEJAAAAAQAAAA==`)
 
 					pps, err := packages.GetProperties(db.DefaultContext, packages.PropertyTypeFile, pf.ID)
 					assert.NoError(t, err)
-					assert.Len(t, pps, 1)
-					assert.Equal(t, nuget_module.PropertySymbolID, pps[0].Name)
-					assert.Equal(t, symbolID, pps[0].Value)
+					assert.Len(t, pps, 2)
+					assert.Equal(t, symbolID, packages.PackagePropertyList(pps).GetByName(nuget_module.PropertySymbolID))
+					assert.Equal(t, symbolChecksum, packages.PackagePropertyList(pps).GetByName(nuget_module.PropertySymbolChecksum))
 				default:
 					assert.FailNow(t, "unexpected file: %v", pf.Name)
 				}
@@ -404,6 +406,25 @@ AAAjQmxvYgAAAGm7ENm9SGxMtAFVvPUsPJTF6PbtAAAAAFcVogEJAAAAAQAAAA==`)
 				AddBasicAuth(user.Name)
 			MakeRequest(t, req, http.StatusOK)
 
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/symbols/%s/%sFFFFFFFF/%s", url, "st.pdb", symbolID, "st.pdb")).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNotFound)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/symbols/%s/%sFFFFFFFF/%s", url, symbolFilename, symbolID, symbolFilename)).
+				SetHeader("SymbolChecksum", "SHA256:"+strings.ToUpper(symbolChecksum)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusOK)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/symbols/%s/%sFFFFFFFF/%s", url, symbolFilename, symbolID, symbolFilename)).
+				SetHeader("SymbolChecksum", "SHA256:0000000000000000000000000000000000000000000000000000000000000000;SHA256:"+symbolChecksum).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusOK)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/symbols/%s/%sFFFFFFFF/%s", url, symbolFilename, symbolID, symbolFilename)).
+				SetHeader("SymbolChecksum", "SHA256:0000000000000000000000000000000000000000000000000000000000000000").
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNotFound)
+
 			checkDownloadCount(1)
 		})
 	})
@@ -0,0 +1,149 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageImmutableAndPromote(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+	other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+
+	token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+	adminToken := getUserToken(t, admin.Name, auth_model.AccessTokenScopeWritePackage)
+
+	packageName := "test-package"
+	packageVersion := "1.0.0"
+	filename := "file.bin"
+	content := []byte{1, 2, 3}
+
+	url := fmt.Sprintf("/api/packages/%s/generic/%s/%s", user.Name, packageName, packageVersion)
+	req := NewRequestWithBody(t, "PUT", url+"/"+filename, bytes.NewReader(content)).
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithBody(t, "PUT", url+"/other.bin", bytes.NewReader(content)).
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+
+	packageURL := fmt.Sprintf("/api/v1/packages/%s/generic/%s", user.Name, packageName)
+	immutableURL := packageURL + "/-/immutable"
+
+	t.Run("Immutable", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "PUT", immutableURL)
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequest(t, "PUT", fmt.Sprintf("/api/v1/packages/%s/generic/unknown/-/immutable", user.Name)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "PUT", immutableURL).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", packageURL+"/"+packageVersion).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var ap *api.Package
+		DecodeJSON(t, resp, &ap)
+		assert.True(t, ap.Immutable)
+
+		req = NewRequest(t, "DELETE", url+"/"+filename).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "DELETE", packageURL+"/"+packageVersion).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		// only site admins can make the package mutable again
+		req = NewRequest(t, "DELETE", immutableURL).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("Promote", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		promoteURL := packageURL + "/" + packageVersion + "/promote"
+
+		req := NewRequestWithJSON(t, "POST", promoteURL, &api.PromotePackageOption{Owner: org.Name})
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithJSON(t, "POST", promoteURL, &api.PromotePackageOption{Owner: "unknown-owner"}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", promoteURL, &api.PromotePackageOption{Owner: user.Name}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", promoteURL, &api.PromotePackageOption{Owner: other.Name}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "POST", promoteURL, &api.PromotePackageOption{Owner: org.Name}).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+
+		var ap *api.Package
+		DecodeJSON(t, resp, &ap)
+		assert.Equal(t, org.Name, ap.Owner.UserName)
+		assert.Equal(t, packageName, ap.Name)
+		assert.Equal(t, packageVersion, ap.Version)
+		assert.Equal(t, user.Name, ap.PromotedFrom)
+		assert.False(t, ap.Immutable)
+
+		pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, org.ID, packages_model.TypeGeneric, packageName, packageVersion)
+		assert.NoError(t, err)
+		pfs, err := packages_model.GetFilesByVersionID(db.DefaultContext, pv.ID)
+		assert.NoError(t, err)
+		assert.Len(t, pfs, 2)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/generic/%s/%s/%s", org.Name, packageName, packageVersion, filename)).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, content, resp.Body.Bytes())
+
+		req = NewRequestWithJSON(t, "POST", promoteURL, &api.PromotePackageOption{Owner: org.Name}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusConflict)
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", url+"/"+filename).
+			AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", immutableURL).
+			AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", packageURL+"/"+packageVersion).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+	})
+}
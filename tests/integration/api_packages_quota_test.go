@@ -0,0 +1,157 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageOwnerQuota(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	adminToken := getUserToken(t, admin.Name, auth_model.AccessTokenScopeWriteAdmin)
+	token := getUserToken(t, user.Name, auth_model.AccessTokenScopeReadUser)
+
+	content := "package-content"
+
+	uploadPackage := func(t *testing.T, name string, expectedStatus int) {
+		t.Helper()
+
+		req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/generic/%s/1.0.0/file.bin", user.Name, name), strings.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, expectedStatus)
+	}
+
+	getUsage := func(t *testing.T) *api.PackageUsage {
+		t.Helper()
+
+		req := NewRequest(t, "GET", "/api/v1/user/packages/usage").
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var usage *api.PackageUsage
+		DecodeJSON(t, resp, &usage)
+		return usage
+	}
+
+	uploadPackage(t, "quota-package", http.StatusCreated)
+
+	t.Run("Usage", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		usage := getUsage(t)
+		assert.EqualValues(t, len(content), usage.TotalSize)
+		assert.EqualValues(t, -1, usage.TotalSizeLimit)
+		assert.False(t, usage.IsOwnerLimit)
+		assert.Len(t, usage.Types, 1)
+		assert.Equal(t, "generic", usage.Types[0].Type)
+		assert.EqualValues(t, 1, usage.Types[0].PackageCount)
+		assert.EqualValues(t, 1, usage.Types[0].VersionCount)
+		assert.EqualValues(t, len(content), usage.Types[0].Size)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/users/%s/packages/usage", user.Name)).
+			AddTokenAuth(adminToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var adminUsage *api.PackageUsage
+		DecodeJSON(t, resp, &adminUsage)
+		assert.Equal(t, usage, adminUsage)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/users/%s/packages/usage", user.Name)).
+			AddTokenAuth(getUserToken(t, user.Name, auth_model.AccessTokenScopeWriteAdmin))
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("SetQuota", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		limit := int64(-2)
+		req := NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/admin/users/%s/packages/quota", user.Name), &api.SetPackageQuotaOption{
+			TotalSizeLimit: &limit,
+		}).AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		limit = int64(len(content) + 1)
+		req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/admin/users/%s/packages/quota", user.Name), &api.SetPackageQuotaOption{
+			TotalSizeLimit: &limit,
+		}).AddTokenAuth(adminToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var usage *api.PackageUsage
+		DecodeJSON(t, resp, &usage)
+		assert.Equal(t, limit, usage.TotalSizeLimit)
+		assert.True(t, usage.IsOwnerLimit)
+
+		uploadPackage(t, "quota-package-2", http.StatusForbidden)
+	})
+
+	t.Run("ResetQuota", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/users/%s/packages/quota", user.Name)).
+			AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		usage := getUsage(t)
+		assert.EqualValues(t, -1, usage.TotalSizeLimit)
+		assert.False(t, usage.IsOwnerLimit)
+
+		uploadPackage(t, "quota-package-2", http.StatusCreated)
+	})
+
+	t.Run("LargestPackages", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/api/v1/admin/packages/largest?type=generic&limit=1").
+			AddTokenAuth(adminToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var sizes []*api.PackageSize
+		DecodeJSON(t, resp, &sizes)
+		assert.Len(t, sizes, 1)
+		assert.Equal(t, user.Name, sizes[0].Owner.UserName)
+		assert.Equal(t, "generic", sizes[0].Type)
+		assert.EqualValues(t, 1, sizes[0].VersionCount)
+		assert.EqualValues(t, len(content), sizes[0].Size)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/packages/largest?type=npm").
+			AddTokenAuth(adminToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		DecodeJSON(t, resp, &sizes)
+		assert.Empty(t, sizes)
+	})
+
+	t.Run("Organization", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		org := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/%s/packages/usage", org.Name)).
+			AddTokenAuth(getUserToken(t, user.Name, auth_model.AccessTokenScopeReadOrganization))
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var usage *api.PackageUsage
+		DecodeJSON(t, resp, &usage)
+		assert.Empty(t, usage.Types)
+
+		other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/%s/packages/usage", org.Name)).
+			AddTokenAuth(getUserToken(t, other.Name, auth_model.AccessTokenScopeReadOrganization))
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	terraform_module "code.gitea.io/gitea/modules/packages/terraform"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/keybase/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageTerraform(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	token := "Bearer " + getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+
+	root := fmt.Sprintf("/api/packages/%s/terraform", user.Name)
+	registryRoot := "/api/packages/-/terraform"
+
+	t.Run("ServiceDiscovery", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/.well-known/terraform.json")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result map[string]string
+		DecodeJSON(t, resp, &result)
+		assert.Equal(t, setting.AppSubURL+registryRoot+"/modules/v1/", result["modules.v1"])
+		assert.Equal(t, setting.AppSubURL+registryRoot+"/providers/v1/", result["providers.v1"])
+	})
+
+	t.Run("Module", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		moduleName, moduleSystem, moduleVersion := "vpc", "aws", "1.0.0"
+		readme := "# VPC"
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		archive := tar.NewWriter(zw)
+		for name, content := range map[string]string{"main.tf": `variable "cidr" {}`, "README.md": readme} {
+			archive.WriteHeader(&tar.Header{
+				Name: name,
+				Mode: 0o600,
+				Size: int64(len(content)),
+			})
+			archive.Write([]byte(content))
+		}
+		archive.Close()
+		zw.Close()
+		content := buf.Bytes()
+
+		uploadURL := fmt.Sprintf("%s/modules/%s/%s/%s", root, moduleName, moduleSystem, moduleVersion)
+
+		t.Run("Upload", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(content))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader([]byte("invalid"))).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/modules/%s/%s/invalid", root, moduleName, moduleSystem), bytes.NewReader(content)).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(content)).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			require.NoError(t, err)
+			require.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			require.NoError(t, err)
+			assert.Equal(t, moduleName+"/"+moduleSystem, pd.Package.Name)
+			assert.IsType(t, &terraform_module.Metadata{}, pd.Metadata)
+			assert.Equal(t, terraform_module.KindModule, pd.Metadata.(*terraform_module.Metadata).Kind)
+			assert.Equal(t, readme, pd.Metadata.(*terraform_module.Metadata).Readme)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(content)).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusConflict)
+		})
+
+		t.Run("EnumerateVersions", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/modules/v1/%s/%s/%s/versions", registryRoot, user.Name, moduleName, moduleSystem))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result struct {
+				Modules []struct {
+					Versions []struct {
+						Version string `json:"version"`
+					} `json:"versions"`
+				} `json:"modules"`
+			}
+			DecodeJSON(t, resp, &result)
+			require.Len(t, result.Modules, 1)
+			require.Len(t, result.Modules[0].Versions, 1)
+			assert.Equal(t, moduleVersion, result.Modules[0].Versions[0].Version)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/modules/v1/%s/unknown/%s/versions", registryRoot, user.Name, moduleSystem))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/modules/v1/%s/%s/%s/%s/download", registryRoot, user.Name, moduleName, moduleSystem, moduleVersion))
+			resp := MakeRequest(t, req, http.StatusNoContent)
+
+			downloadURL := resp.Header().Get("X-Terraform-Get")
+			assert.Equal(t, fmt.Sprintf("%s%s/modules/%s/%s/%s/%s-%s-%s.tar.gz", setting.AppURL, root[1:], moduleName, moduleSystem, moduleVersion, moduleName, moduleSystem, moduleVersion), downloadURL)
+
+			req = NewRequest(t, "GET", downloadURL[len(setting.AppURL)-1:])
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, content, resp.Body.Bytes())
+		})
+	})
+
+	t.Run("Provider", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		providerType, providerVersion := "test", "1.0.0"
+
+		entity, err := openpgp.NewEntity("Test", "", user.Email, nil)
+		require.NoError(t, err)
+		require.NoError(t, entity.SerializePrivate(io.Discard, nil))
+		var armored bytes.Buffer
+		w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+		require.NoError(t, err)
+		require.NoError(t, entity.Serialize(w))
+		require.NoError(t, w.Close())
+
+		keyID := entity.PrimaryKey.KeyIdString()
+		require.NoError(t, db.Insert(db.DefaultContext, &asymkey_model.GPGKey{OwnerID: user.ID, KeyID: keyID, Content: "content", CanSign: true, Verified: true}))
+		require.NoError(t, db.Insert(db.DefaultContext, &asymkey_model.GPGKeyImport{KeyID: keyID, Content: armored.String()}))
+
+		platformFileName := fmt.Sprintf("terraform-provider-%s_%s_linux_amd64.zip", providerType, providerVersion)
+		platformContent := []byte("provider archive")
+		platformSum := sha256.Sum256(platformContent)
+		sums := []byte(hex.EncodeToString(platformSum[:]) + "  " + platformFileName + "\n")
+		var signature bytes.Buffer
+		require.NoError(t, openpgp.DetachSign(&signature, entity, bytes.NewReader(sums), nil))
+
+		versionURL := fmt.Sprintf("%s/providers/%s/%s", root, providerType, providerVersion)
+		sumsFileName := terraform_module.SHA256SUMSFileName(providerType, providerVersion)
+		signatureFileName := terraform_module.SHA256SUMSSignatureFileName(providerType, providerVersion)
+
+		uploadFile := func(t *testing.T, filename string, content []byte, expectedStatus int) {
+			req := NewRequestWithBody(t, "PUT", versionURL+"/"+filename, bytes.NewReader(content)).
+				AddTokenAuth(token)
+			MakeRequest(t, req, expectedStatus)
+		}
+
+		t.Run("Upload", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			uploadFile(t, platformFileName, platformContent, http.StatusNotFound)
+			uploadFile(t, sumsFileName, []byte("invalid"), http.StatusBadRequest)
+			uploadFile(t, sumsFileName, sums, http.StatusCreated)
+			uploadFile(t, sumsFileName, sums, http.StatusConflict)
+			uploadFile(t, signatureFileName, []byte("invalid"), http.StatusBadRequest)
+			uploadFile(t, signatureFileName, signature.Bytes(), http.StatusCreated)
+			uploadFile(t, terraform_module.ManifestFileName(providerType, providerVersion), []byte(`{"version":1,"metadata":{"protocol_versions":["6.0"]}}`), http.StatusCreated)
+			uploadFile(t, platformFileName, []byte("other archive"), http.StatusBadRequest)
+			uploadFile(t, "unexpected.zip", platformContent, http.StatusBadRequest)
+			uploadFile(t, platformFileName, platformContent, http.StatusCreated)
+
+			pv, err := packages.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages.TypeTerraform, providerType, providerVersion)
+			require.NoError(t, err)
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pv)
+			require.NoError(t, err)
+			metadata := pd.Metadata.(*terraform_module.Metadata)
+			assert.Equal(t, terraform_module.KindProvider, metadata.Kind)
+			assert.Equal(t, []string{"6.0"}, metadata.Protocols)
+			require.NotNil(t, metadata.SigningKey)
+			assert.Equal(t, keyID, metadata.SigningKey.KeyID)
+			assert.Len(t, pd.Files, 4)
+		})
+
+		t.Run("EnumerateVersions", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/providers/v1/%s/%s/versions", registryRoot, user.Name, providerType))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result struct {
+				Versions []struct {
+					Version   string   `json:"version"`
+					Protocols []string `json:"protocols"`
+					Platforms []struct {
+						OS   string `json:"os"`
+						Arch string `json:"arch"`
+					} `json:"platforms"`
+				} `json:"versions"`
+			}
+			DecodeJSON(t, resp, &result)
+			require.Len(t, result.Versions, 1)
+			assert.Equal(t, providerVersion, result.Versions[0].Version)
+			assert.Equal(t, []string{"6.0"}, result.Versions[0].Protocols)
+			require.Len(t, result.Versions[0].Platforms, 1)
+			assert.Equal(t, "linux", result.Versions[0].Platforms[0].OS)
+			assert.Equal(t, "amd64", result.Versions[0].Platforms[0].Arch)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/providers/v1/%s/%s/%s/download/darwin/arm64", registryRoot, user.Name, providerType, providerVersion))
+			MakeRequest(t, req, http.StatusNotFound)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/providers/v1/%s/%s/%s/download/linux/amd64", registryRoot, user.Name, providerType, providerVersion))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result struct {
+				Filename            string `json:"filename"`
+				DownloadURL         string `json:"download_url"`
+				SHASumsURL          string `json:"shasums_url"`
+				SHASumsSignatureURL string `json:"shasums_signature_url"`
+				SHASum              string `json:"shasum"`
+				SigningKeys         struct {
+					GPGPublicKeys []struct {
+						KeyID      string `json:"key_id"`
+						ASCIIArmor string `json:"ascii_armor"`
+					} `json:"gpg_public_keys"`
+				} `json:"signing_keys"`
+			}
+			DecodeJSON(t, resp, &result)
+			assert.Equal(t, platformFileName, result.Filename)
+			assert.Equal(t, hex.EncodeToString(platformSum[:]), result.SHASum)
+			require.Len(t, result.SigningKeys.GPGPublicKeys, 1)
+			assert.Equal(t, keyID, result.SigningKeys.GPGPublicKeys[0].KeyID)
+			assert.Equal(t, armored.String(), result.SigningKeys.GPGPublicKeys[0].ASCIIArmor)
+
+			for url, expected := range map[string][]byte{
+				result.DownloadURL:         platformContent,
+				result.SHASumsURL:          sums,
+				result.SHASumsSignatureURL: signature.Bytes(),
+			} {
+				req = NewRequest(t, "GET", url[len(setting.AppURL)-1:])
+				resp = MakeRequest(t, req, http.StatusOK)
+				assert.Equal(t, expected, resp.Body.Bytes())
+			}
+		})
+	})
+}
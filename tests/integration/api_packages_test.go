@@ -18,6 +18,7 @@ import (
 	container_model "code.gitea.io/gitea/models/packages/container"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	container_module "code.gitea.io/gitea/modules/packages/container"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -639,4 +640,122 @@ func TestPackageCleanup(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("ContainerRetention", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+
+		p, err := packages_model.TryInsertPackage(db.DefaultContext, &packages_model.Package{
+			OwnerID:   user.ID,
+			Type:      packages_model.TypeContainer,
+			Name:      "retention-test",
+			LowerName: "retention-test",
+		})
+		assert.NoError(t, err)
+
+		digest := func(c string) string {
+			return "sha256:" + strings.Repeat(c, 64)
+		}
+
+		type version struct {
+			Version     string
+			ShouldExist bool
+			Created     int64
+			References  string
+		}
+		versions := []version{
+			{Version: "latest", ShouldExist: true, Created: 1},
+			{Version: "v2.0", ShouldExist: true, References: digest("c")},
+			{Version: "v1.0", ShouldExist: true, Created: 1},
+			{Version: "dev-1", ShouldExist: false, Created: 1},
+			{Version: digest("a"), ShouldExist: false, Created: 1},
+			{Version: digest("b"), ShouldExist: true},
+			{Version: digest("c"), ShouldExist: true, Created: 1},
+		}
+		for _, v := range versions {
+			pv, err := packages_model.GetOrInsertVersion(db.DefaultContext, &packages_model.PackageVersion{
+				PackageID:    p.ID,
+				CreatorID:    user.ID,
+				Version:      v.Version,
+				LowerVersion: strings.ToLower(v.Version),
+				MetadataJSON: "{}",
+			})
+			assert.NoError(t, err)
+			if v.Created != 0 {
+				_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE package_version SET created_unix = ? WHERE id = ?", v.Created, pv.ID)
+				assert.NoError(t, err)
+			}
+			if v.References != "" {
+				_, err = packages_model.InsertProperty(db.DefaultContext, packages_model.PropertyTypeVersion, pv.ID, container_module.PropertyManifestReference, v.References)
+				assert.NoError(t, err)
+			}
+		}
+
+		rulesURL := fmt.Sprintf("/api/v1/packages/%s/cleanup_rules", user.Name)
+
+		req := NewRequestWithJSON(t, "POST", rulesURL, &api.CreatePackageCleanupRuleOption{
+			Type:          "container",
+			ProtectedTags: []string{"[invalid"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", rulesURL, &api.CreatePackageCleanupRuleOption{
+			Type:               "container",
+			KeepCount:          1,
+			RemoveDays:         7,
+			RemoveUntaggedDays: 1,
+			ProtectedTags:      []string{"v1.*"},
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var rule api.PackageCleanupRule
+		DecodeJSON(t, resp, &rule)
+		assert.False(t, rule.Enabled)
+		assert.Equal(t, []string{"v1.*"}, rule.ProtectedTags)
+
+		req = NewRequestWithJSON(t, "POST", rulesURL, &api.CreatePackageCleanupRuleOption{Type: "container"}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d/preview", rulesURL, rule.ID)).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var preview []*api.Package
+		DecodeJSON(t, resp, &preview)
+		previewed := make([]string, 0, len(preview))
+		for _, pkg := range preview {
+			previewed = append(previewed, pkg.Version)
+		}
+		assert.ElementsMatch(t, []string{"dev-1", digest("a")}, previewed)
+
+		// the rule is disabled, the preview doesn't remove anything
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, duration))
+		for _, v := range versions {
+			_, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, p.Name, v.Version)
+			assert.NoError(t, err)
+		}
+
+		enabled := true
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("%s/%d", rulesURL, rule.ID), &api.EditPackageCleanupRuleOption{Enabled: &enabled}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		assert.NoError(t, packages_cleanup_service.CleanupTask(db.DefaultContext, duration))
+		for _, v := range versions {
+			_, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, p.Name, v.Version)
+			if v.ShouldExist {
+				assert.NoError(t, err, v.Version)
+			} else {
+				assert.ErrorIs(t, err, packages_model.ErrPackageNotExist, v.Version)
+			}
+		}
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%d", rulesURL, rule.ID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%d", rulesURL, rule.ID)).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
 }
@@ -0,0 +1,341 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/tests"
+
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageUpstream(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	defer test.MockVariableValue(&setting.Packages.UpstreamAllowedHostList, "loopback")()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+
+	npmPackageName := "@scope/upstream-package"
+	npmPackageVersion := "1.0.0"
+	npmTarball := []byte("npm-tarball-content")
+	npmIntegrity := sha512.Sum512(npmTarball)
+
+	goModuleName := "gitea.com/upstream/Module"
+	goModuleVersion := "v1.0.0"
+	goModContent := "module gitea.com/upstream/Module"
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create(goModuleName + "@" + goModuleVersion + "/go.mod")
+	w.Write([]byte(goModContent))
+	zw.Close()
+	goModuleZip := buf.Bytes()
+
+	containerDigest := func(content string) string {
+		return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(content)))
+	}
+	containerImage := "upstream-image"
+	containerToken := "upstream-token"
+	containerConfig := `{"architecture":"amd64","os":"linux"}`
+	containerLayer := "container-layer-content"
+	containerManifest := fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":%q,"digest":%q,"size":%d},"layers":[{"mediaType":%q,"digest":%q,"size":%d}]}`,
+		oci.MediaTypeImageManifest, oci.MediaTypeImageConfig, containerDigest(containerConfig), len(containerConfig),
+		oci.MediaTypeImageLayerGzip, containerDigest(containerLayer), len(containerLayer))
+	containerIndex := fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"manifests":[{"mediaType":%q,"digest":%q,"size":%d,"platform":{"os":"linux","architecture":"amd64"}}]}`,
+		oci.MediaTypeImageIndex, oci.MediaTypeImageManifest, containerDigest(containerManifest), len(containerManifest))
+	containerContents := map[string]string{
+		"/manifests/latest": containerIndex,
+		"/manifests/" + containerDigest(containerManifest): containerManifest,
+		"/blobs/" + containerDigest(containerConfig):       containerConfig,
+		"/blobs/" + containerDigest(containerLayer):        containerLayer,
+	}
+
+	var fileRequests, tokenRequests atomic.Int32
+	var upstreamURL string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path, ok := strings.CutPrefix(r.URL.Path, "/v2/"+containerImage); ok {
+			// the registry requires an anonymous token like Docker Hub
+			if r.Header.Get("Authorization") != "Bearer "+containerToken {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="upstream-registry",scope="repository:%s:pull"`, upstreamURL, containerImage))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			content, ok := containerContents[path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fileRequests.Add(1)
+			if strings.HasPrefix(path, "/manifests/") {
+				var manifest oci.Manifest
+				json.Unmarshal([]byte(content), &manifest)
+				w.Header().Set("Content-Type", manifest.MediaType)
+			}
+			w.Write([]byte(content))
+			return
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/token":
+			tokenRequests.Add(1)
+			if r.URL.Query().Get("service") != "upstream-registry" || r.URL.Query().Get("scope") != "repository:"+containerImage+":pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"token": %q}`, containerToken)
+		case "/@scope%2Fupstream-package":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{
+				"name": %q,
+				"dist-tags": {"latest": %q},
+				"license": {"type": "MIT"},
+				"versions": {
+					%q: {
+						"name": %q,
+						"version": %q,
+						"description": "upstream",
+						"repository": "github.com/upstream/package",
+						"dist": {"integrity": %q, "tarball": %q}
+					},
+					"invalid": {"name": %q, "version": "invalid"}
+				}
+			}`, npmPackageName, npmPackageVersion, npmPackageVersion, npmPackageName, npmPackageVersion,
+				"sha512-"+base64.StdEncoding.EncodeToString(npmIntegrity[:]), upstreamURL+"/tarballs/upstream-package-1.0.0.tgz", npmPackageName)
+		case "/tarballs/upstream-package-1.0.0.tgz":
+			fileRequests.Add(1)
+			w.Write(npmTarball)
+		case "/gitea.com/upstream/!module/@v/list":
+			fmt.Fprintln(w, goModuleVersion)
+		case "/gitea.com/upstream/!module/@latest":
+			fmt.Fprintf(w, `{"Version": %q}`, goModuleVersion)
+		case "/gitea.com/upstream/!module/@v/v1.0.0.zip":
+			fileRequests.Add(1)
+			w.Write(goModuleZip)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+	upstreamURL = upstream.URL
+
+	upstreamsURL := fmt.Sprintf("/api/v1/packages/%s/upstreams", user.Name)
+
+	t.Run("API", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "PUT", upstreamsURL+"/npm", &api.SetPackageUpstreamOption{URL: upstreamURL})
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithJSON(t, "PUT", upstreamsURL+"/nuget", &api.SetPackageUpstreamOption{URL: upstreamURL}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PUT", upstreamsURL+"/npm", &api.SetPackageUpstreamOption{URL: "ftp://example.com"}).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		for _, packageType := range []string{"npm", "go", "container"} {
+			req = NewRequestWithJSON(t, "PUT", upstreamsURL+"/"+packageType, &api.SetPackageUpstreamOption{URL: upstreamURL + "/"}).
+				AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var pu *api.PackageUpstream
+			DecodeJSON(t, resp, &pu)
+			assert.Equal(t, packageType, pu.Type)
+			assert.Equal(t, upstreamURL, pu.URL)
+		}
+
+		req = NewRequest(t, "GET", upstreamsURL).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var pus []*api.PackageUpstream
+		DecodeJSON(t, resp, &pus)
+		assert.Len(t, pus, 3)
+	})
+
+	t.Run("Npm", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		root := fmt.Sprintf("/api/packages/%s/npm/%s", user.Name, "@scope%2Fupstream-package")
+
+		req := NewRequest(t, "GET", root).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result npm_module.PackageMetadata
+		DecodeJSON(t, resp, &result)
+		assert.Equal(t, npmPackageName, result.Name)
+		assert.Equal(t, npmPackageVersion, result.DistTags["latest"])
+		assert.Len(t, result.Versions, 1)
+		pmv := result.Versions[npmPackageVersion]
+		assert.NotNil(t, pmv)
+		assert.Equal(t, "upstream", pmv.Description)
+		assert.Equal(t, fmt.Sprintf("%sapi/packages/%s/npm/%s/-/%s/upstream-package-1.0.0.tgz", setting.AppURL, user.Name, "%40scope%2Fupstream-package", npmPackageVersion), pmv.Dist.Tarball)
+
+		pvs, err := packages_model.GetVersionsByPackageType(db.DefaultContext, user.ID, packages_model.TypeNpm)
+		assert.NoError(t, err)
+		assert.Empty(t, pvs)
+
+		for i := 0; i < 2; i++ {
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/-/%s/upstream-package-1.0.0.tgz", root, npmPackageVersion)).
+				AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, npmTarball, resp.Body.Bytes())
+		}
+		assert.EqualValues(t, 1, fileRequests.Load())
+
+		pvs, err = packages_model.GetVersionsByPackageType(db.DefaultContext, user.ID, packages_model.TypeNpm)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 1)
+
+		pd, err := packages_model.GetPackageDescriptor(db.DefaultContext, pvs[0])
+		assert.NoError(t, err)
+		assert.Equal(t, npmPackageName, pd.Package.Name)
+		assert.Equal(t, upstreamURL+"/tarballs/upstream-package-1.0.0.tgz", pd.VersionProperties.GetByName(packages_model.PropertyUpstreamURL))
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/-/%s/upstream-package-2.0.0.tgz", root, "2.0.0")).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Go", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		fileRequests.Store(0)
+
+		root := fmt.Sprintf("/api/packages/%s/go/gitea.com/upstream/!module", user.Name)
+
+		req := NewRequest(t, "GET", root+"/@v/list").
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, goModuleVersion+"\n", resp.Body.String())
+
+		req = NewRequest(t, "GET", root+"/@latest").
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		var info struct {
+			Version string `json:"Version"`
+		}
+		DecodeJSON(t, resp, &info)
+		assert.Equal(t, goModuleVersion, info.Version)
+
+		req = NewRequest(t, "GET", root+"/@v/v1.0.0.mod").
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, goModContent, resp.Body.String())
+
+		req = NewRequest(t, "GET", root+"/@v/v1.0.0.zip").
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, goModuleZip, resp.Body.Bytes())
+		assert.EqualValues(t, 1, fileRequests.Load())
+
+		pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeGo, goModuleName, goModuleVersion)
+		assert.NoError(t, err)
+		pd, err := packages_model.GetPackageDescriptor(db.DefaultContext, pv)
+		assert.NoError(t, err)
+		assert.Equal(t, upstreamURL+"/gitea.com/upstream/!module/@v/v1.0.0.zip", pd.VersionProperties.GetByName(packages_model.PropertyUpstreamURL))
+
+		req = NewRequest(t, "GET", root+"/@v/v2.0.0.info").
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Container", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		fileRequests.Store(0)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%sv2/token", setting.AppURL)).
+			AddBasicAuth(user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var tokenResponse struct {
+			Token string `json:"token"`
+		}
+		DecodeJSON(t, resp, &tokenResponse)
+		containerUserToken := "Bearer " + tokenResponse.Token
+
+		root := fmt.Sprintf("/v2/%s/%s", user.Name, containerImage)
+
+		for i := 0; i < 2; i++ {
+			req = NewRequest(t, "GET", root+"/manifests/latest").
+				AddTokenAuth(containerUserToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, containerIndex, resp.Body.String())
+			assert.Equal(t, oci.MediaTypeImageIndex, resp.Header().Get("Content-Type"))
+			assert.Equal(t, containerDigest(containerIndex), resp.Header().Get("Docker-Content-Digest"))
+		}
+		// the index, the manifest and its blobs are fetched once with a single token
+		assert.EqualValues(t, 4, fileRequests.Load())
+		assert.EqualValues(t, 1, tokenRequests.Load())
+
+		req = NewRequest(t, "GET", root+"/manifests/"+containerDigest(containerManifest)).
+			AddTokenAuth(containerUserToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, containerManifest, resp.Body.String())
+
+		req = NewRequest(t, "GET", root+"/blobs/"+containerDigest(containerLayer)).
+			AddTokenAuth(containerUserToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, containerLayer, resp.Body.String())
+		assert.EqualValues(t, 4, fileRequests.Load())
+
+		pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, containerImage, "latest")
+		assert.NoError(t, err)
+		pd, err := packages_model.GetPackageDescriptor(db.DefaultContext, pv)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%s/v2/%s/manifests/latest", upstreamURL, containerImage), pd.VersionProperties.GetByName(packages_model.PropertyUpstreamURL))
+
+		req = NewRequest(t, "HEAD", root+"/manifests/unknown").
+			AddTokenAuth(containerUserToken)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", upstreamsURL+"/npm").
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", upstreamsURL+"/npm").
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// the cached version is still served without the upstream registry
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/npm/%s", user.Name, "@scope%2Fupstream-package")).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result npm_module.PackageMetadata
+		DecodeJSON(t, resp, &result)
+		assert.Len(t, result.Versions, 1)
+	})
+}
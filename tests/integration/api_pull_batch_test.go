@@ -0,0 +1,141 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIBatchPullRequests(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_batch_pulls",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue)
+		indexes := make([]int64, 0, 2)
+		for _, branch := range []string{"feature1", "feature2", "release"} {
+			_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				OldBranch: "master",
+				NewBranch: branch,
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      branch + ".txt",
+						ContentReader: strings.NewReader(branch + "\n"),
+					},
+				},
+			})
+			require.NoError(t, err)
+			if branch == "release" {
+				continue
+			}
+
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_batch_pulls/pulls", &api.CreatePullRequestOption{
+				Head:  branch,
+				Base:  "master",
+				Title: "Add " + branch,
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusCreated)
+			pull := new(api.PullRequest)
+			DecodeJSON(t, resp, pull)
+			indexes = append(indexes, pull.Index)
+		}
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_batch_pulls/labels", &api.CreateLabelOption{
+			Name:  "backport",
+			Color: "#00aabb",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		label := new(api.Label)
+		DecodeJSON(t, resp, label)
+
+		batch := func(token string, opts *api.BatchPullRequestOption, expectedStatus int) []*api.BatchPullRequestResult {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_batch_pulls/pulls/batch", opts).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusOK {
+				return nil
+			}
+			var results []*api.BatchPullRequestResult
+			DecodeJSON(t, resp, &results)
+			return results
+		}
+		getPull := func(index int64) *issues_model.PullRequest {
+			pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{BaseRepoID: repo.ID, Index: index})
+			require.NoError(t, pr.LoadIssue(db.DefaultContext))
+			return pr
+		}
+
+		// only the writers of the pull requests can use it
+		token8 := getUserToken(t, "user8", auth_model.AccessTokenScopeWriteRepository)
+		batch(token8, &api.BatchPullRequestOption{Indexes: indexes, Action: "close"}, http.StatusForbidden)
+
+		// nothing is changed if the pull requests or the parameters are invalid
+		batch(token, &api.BatchPullRequestOption{Indexes: append(indexes, 99), Action: "add_labels", Labels: []int64{label.ID}}, http.StatusUnprocessableEntity)
+		batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "add_labels", Labels: []int64{label.ID, 9999}}, http.StatusUnprocessableEntity)
+		batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "retarget", Base: "unknown"}, http.StatusUnprocessableEntity)
+		batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "request_reviewers", Reviewers: []string{"unknown"}}, http.StatusUnprocessableEntity)
+		batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "merge"}, http.StatusUnprocessableEntity)
+		unittest.AssertNotExistsBean(t, &issues_model.IssueLabel{LabelID: label.ID})
+
+		results := batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "add_labels", Labels: []int64{label.ID}}, http.StatusOK)
+		require.Len(t, results, 2)
+		for i, result := range results {
+			assert.Equal(t, indexes[i], result.Index)
+			assert.True(t, result.Success)
+			unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: getPull(result.Index).IssueID, LabelID: label.ID})
+		}
+
+		results = batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "request_reviewers", Reviewers: []string{"user4"}}, http.StatusOK)
+		for _, result := range results {
+			assert.True(t, result.Success, result.Error)
+			unittest.AssertExistsAndLoadBean(t, &issues_model.Review{IssueID: getPull(result.Index).IssueID, ReviewerID: 4, Type: issues_model.ReviewTypeRequest})
+		}
+
+		results = batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "retarget", Base: "release"}, http.StatusOK)
+		for _, result := range results {
+			assert.True(t, result.Success, result.Error)
+			assert.Equal(t, "release", getPull(result.Index).BaseBranch)
+		}
+
+		results = batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "update"}, http.StatusOK)
+		for _, result := range results {
+			assert.True(t, result.Success, result.Error)
+		}
+
+		results = batch(token, &api.BatchPullRequestOption{Indexes: indexes[:1], Action: "close"}, http.StatusOK)
+		assert.True(t, results[0].Success, results[0].Error)
+		assert.True(t, getPull(indexes[0]).Issue.IsClosed)
+		assert.False(t, getPull(indexes[1]).Issue.IsClosed)
+
+		// the failures are reported per pull request
+		results = batch(token, &api.BatchPullRequestOption{Indexes: indexes, Action: "update", Style: "rebase"}, http.StatusOK)
+		require.Len(t, results, 2)
+		assert.False(t, results[0].Success)
+		assert.Equal(t, "the pull request is closed", results[0].Error)
+		assert.True(t, results[1].Success, results[1].Error)
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIPullChangesSinceReview(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_changes_since_review",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		changeFeature := func(path string, amend bool) {
+			opts := &files_service.ChangeRepoFilesOptions{
+				OldBranch: "feature",
+				NewBranch: "feature",
+				Amend:     amend,
+				Message:   "Add the feature",
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      path,
+						ContentReader: strings.NewReader(path + "\n"),
+					},
+				},
+			}
+			if path == "a.txt" {
+				opts.OldBranch = "master"
+			}
+			_, err := files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, opts)
+			require.NoError(t, err)
+		}
+		changeFeature("a.txt", false)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_changes_since_review/pulls", &api.CreatePullRequestOption{
+			Head:  "feature",
+			Base:  "master",
+			Title: "Add the feature",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		pull := new(api.PullRequest)
+		DecodeJSON(t, resp, pull)
+		reviewedCommitID := pull.Head.Sha
+
+		token8 := getUserToken(t, "user8", auth_model.AccessTokenScopeWriteRepository)
+		pullURL := fmt.Sprintf("/api/v1/repos/user2/test_changes_since_review/pulls/%d", pull.Index)
+		req = NewRequestWithJSON(t, "POST", pullURL+"/reviews", &api.CreatePullReviewOptions{
+			Event: api.ReviewStateComment,
+			Body:  "Please add b.txt",
+		}).AddTokenAuth(token8)
+		MakeRequest(t, req, http.StatusOK)
+
+		// the reviewed commit is replaced by a force push
+		changeFeature("b.txt", true)
+
+		// the first push is recorded when the pull request is created, the force push is synchronized in the background
+		var pushes []*api.PullRequestPush
+		assert.Eventually(t, func() bool {
+			req := NewRequest(t, "GET", pullURL+"/pushes").AddTokenAuth(token8)
+			resp := MakeRequest(t, req, http.StatusOK)
+			pushes = nil
+			DecodeJSON(t, resp, &pushes)
+			return len(pushes) == 2
+		}, 10*time.Second, 100*time.Millisecond)
+		require.Len(t, pushes, 2)
+		assert.False(t, pushes[0].IsForcePush)
+		assert.Equal(t, reviewedCommitID, pushes[0].HeadCommitID)
+		push := pushes[1]
+		assert.True(t, push.IsForcePush)
+		assert.Equal(t, "user2", push.Pusher.UserName)
+		require.Len(t, push.CommitIDs, 2)
+		assert.Equal(t, reviewedCommitID, push.CommitIDs[0])
+		headCommitID := push.HeadCommitID
+		assert.NotEqual(t, reviewedCommitID, headCommitID)
+
+		// the previous head is kept
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pull.ID})
+		gitRepo, err := gitrepo.OpenRepository(db.DefaultContext, repo)
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		keptCommitID, err := gitRepo.GetRefCommitID(pr.GetGitPushRefName(push.ID))
+		require.NoError(t, err)
+		assert.Equal(t, reviewedCommitID, keptCommitID)
+
+		// only the file added since the review is changed
+		req = NewRequest(t, "GET", pullURL+"/changes").AddTokenAuth(token8)
+		resp = MakeRequest(t, req, http.StatusOK)
+		changes := new(api.PullRequestChanges)
+		DecodeJSON(t, resp, changes)
+		assert.Equal(t, reviewedCommitID, changes.BeforeCommitID)
+		assert.Equal(t, headCommitID, changes.AfterCommitID)
+		require.Len(t, changes.Files, 1)
+		assert.Equal(t, "b.txt", changes.Files[0].Filename)
+		assert.Equal(t, "added", changes.Files[0].Status)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/changes?since_push=%d", pullURL, push.ID)).AddTokenAuth(token8)
+		resp = MakeRequest(t, req, http.StatusOK)
+		changes = new(api.PullRequestChanges)
+		DecodeJSON(t, resp, changes)
+		assert.Empty(t, changes.Files)
+
+		// the author hasn't reviewed the pull request
+		req = NewRequest(t, "GET", pullURL+"/changes").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// the changes since the review are shown in the UI too
+		session := loginUser(t, "user8")
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/test_changes_since_review/pulls/%d/commits/list", pull.Index))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var commits struct {
+			LastReviewCommitSha string `json:"last_review_commit_sha"`
+		}
+		DecodeJSON(t, resp, &commits)
+		assert.Equal(t, reviewedCommitID, commits.LastReviewCommitSha)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/test_changes_since_review/pulls/%d/files/%s..%s", pull.Index, reviewedCommitID, headCommitID))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, `.diff-file-box[data-new-filename="b.txt"]`, true)
+		htmlDoc.AssertElement(t, `.diff-file-box[data-new-filename="a.txt"]`, false)
+	})
+}
@@ -361,6 +361,107 @@ func TestAPIPullReviewRequest(t *testing.T) {
 	MakeRequest(t, req, http.StatusNoContent)
 }
 
+func TestAPIPullReviewPendingComments(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	pullIssue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 3})
+	assert.NoError(t, pullIssue.LoadAttributes(db.DefaultContext))
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: pullIssue.RepoID})
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+	reviewsURL := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/reviews", repo.OwnerName, repo.Name, pullIssue.Index)
+
+	// create a pending review without a body
+	req := NewRequestWithJSON(t, http.MethodPost, reviewsURL, &api.CreatePullReviewOptions{
+		Comments: []api.CreatePullReviewComment{
+			{
+				Path:       "README.md",
+				Body:       "first new line",
+				NewLineNum: 1,
+			},
+		},
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var review api.PullReview
+	DecodeJSON(t, resp, &review)
+	assert.EqualValues(t, "PENDING", review.State)
+	assert.EqualValues(t, 1, review.CodeCommentsCount)
+	commentsURL := fmt.Sprintf("%s/%d/comments", reviewsURL, review.ID)
+
+	// add comments to the pending review
+	req = NewRequestWithJSON(t, http.MethodPost, commentsURL, &api.CreatePullReviewComment{
+		Path:       "README.md",
+		Body:       "first old line",
+		OldLineNum: 1,
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var comment api.PullReviewComment
+	DecodeJSON(t, resp, &comment)
+	assert.EqualValues(t, review.ID, comment.ReviewID)
+	assert.EqualValues(t, "first old line", comment.Body)
+	assert.EqualValues(t, 1, comment.OldLineNum)
+	assert.EqualValues(t, "user2", comment.Poster.UserName)
+
+	req = NewRequestWithJSON(t, http.MethodPost, commentsURL, &api.CreatePullReviewComment{
+		Path:       "README.md",
+		Body:       "to be deleted",
+		NewLineNum: 1,
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var deleted api.PullReviewComment
+	DecodeJSON(t, resp, &deleted)
+
+	req = NewRequestWithJSON(t, http.MethodPost, commentsURL, &api.CreatePullReviewComment{
+		Path: "README.md",
+		Body: "no line",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// edit and delete pending comments
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("%s/%d", commentsURL, comment.ID), &api.EditPullReviewCommentOptions{
+		Body: "first old line (edited)",
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &comment)
+	assert.EqualValues(t, "first old line (edited)", comment.Body)
+
+	req = NewRequest(t, http.MethodDelete, fmt.Sprintf("%s/%d", commentsURL, deleted.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, http.MethodGet, fmt.Sprintf("%s/%d", commentsURL, deleted.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, http.MethodGet, fmt.Sprintf("%s/%d", commentsURL, comment.ID)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &comment)
+	assert.EqualValues(t, "first old line (edited)", comment.Body)
+
+	// the pending review of another user isn't visible
+	session8 := loginUser(t, "user8")
+	token8 := getTokenForLoggedInUser(t, session8, auth_model.AccessTokenScopeWriteRepository)
+	req = NewRequestWithJSON(t, http.MethodPost, commentsURL, &api.CreatePullReviewComment{
+		Path:       "README.md",
+		Body:       "not my review",
+		NewLineNum: 1,
+	}).AddTokenAuth(token8)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// submit the pending review with its comments
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("%s/%d", reviewsURL, review.ID), &api.SubmitPullReviewOptions{
+		Event: "COMMENT",
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &review)
+	assert.EqualValues(t, "COMMENT", review.State)
+	assert.EqualValues(t, 2, review.CodeCommentsCount)
+
+	// the comments of a submitted review can't be changed anymore
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("%s/%d", commentsURL, comment.ID), &api.EditPullReviewCommentOptions{
+		Body: "too late",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequest(t, http.MethodDelete, fmt.Sprintf("%s/%d", commentsURL, comment.ID)).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
 func TestAPIPullReviewStayDismissed(t *testing.T) {
 	// This test against issue https://github.com/go-gitea/gitea/issues/28542
 	// where old reviews surface after a review request got dismissed.
@@ -483,3 +584,96 @@ func reviewsCountCheck(t *testing.T, name string, issueID, reviewerID int64, exp
 		}, approvalCount)
 	})
 }
+
+func TestAPIPullReviewResolutionReactions(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	pullIssue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 3})
+	assert.NoError(t, pullIssue.LoadAttributes(db.DefaultContext))
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: pullIssue.RepoID})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user2.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+	reviewsURL := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/reviews", repo.OwnerName, repo.Name, pullIssue.Index)
+
+	req := NewRequestWithJSON(t, http.MethodPost, reviewsURL, &api.CreatePullReviewOptions{
+		Event: "COMMENT",
+		Comments: []api.CreatePullReviewComment{
+			{
+				Path:       "README.md",
+				Body:       "a conversation",
+				NewLineNum: 1,
+			},
+		},
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var review api.PullReview
+	DecodeJSON(t, resp, &review)
+
+	req = NewRequest(t, http.MethodGet, fmt.Sprintf("%s/%d/comments", reviewsURL, review.ID)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var comments []*api.PullReviewComment
+	DecodeJSON(t, resp, &comments)
+	assert.Len(t, comments, 1)
+	reactionsURL := fmt.Sprintf("%s/%d/comments/%d/resolution/reactions", reviewsURL, review.ID, comments[0].ID)
+
+	// the conversation isn't resolved yet
+	req = NewRequestWithJSON(t, http.MethodPost, reactionsURL, &api.EditReactionOption{Reaction: "heart"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: comments[0].ID})
+	assert.NoError(t, issues_model.MarkConversation(db.DefaultContext, comment, user2, true))
+
+	req = NewRequestWithJSON(t, http.MethodPost, reactionsURL, &api.EditReactionOption{Reaction: "heart"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithJSON(t, http.MethodPost, reactionsURL, &api.EditReactionOption{Reaction: "heart"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusOK)
+
+	// the reaction is given in the web UI too, it doesn't belong to the comment itself
+	issueURL := fmt.Sprintf("/%s/%s/pulls/%d", repo.OwnerName, repo.Name, pullIssue.Index)
+	req = NewRequestWithValues(t, http.MethodPost, fmt.Sprintf("/%s/%s/comments/%d/resolution/reactions/react", repo.OwnerName, repo.Name, comment.ID), map[string]string{
+		"_csrf":   GetCSRF(t, session, issueURL),
+		"content": "+1",
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, http.MethodGet, reactionsURL)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var reactions []*api.Reaction
+	DecodeJSON(t, resp, &reactions)
+	if assert.Len(t, reactions, 2) {
+		assert.EqualValues(t, "heart", reactions[0].Reaction)
+		assert.EqualValues(t, "+1", reactions[1].Reaction)
+		assert.EqualValues(t, user2.ID, reactions[0].User.ID)
+	}
+	req = NewRequest(t, http.MethodGet, fmt.Sprintf("/api/v1/repos/%s/%s/issues/comments/%d/reactions", repo.OwnerName, repo.Name, comment.ID))
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &reactions)
+	assert.Empty(t, reactions)
+
+	htmlDoc := NewHTMLParser(t, session.MakeRequest(t, NewRequest(t, http.MethodGet, issueURL), http.StatusOK).Body)
+	assert.Equal(t, 2, htmlDoc.Find(".resolution-reactions .bottom-reactions > .ui.label").Length())
+	// the conversation of the diff view is rendered again when it is resolved
+	req = NewRequestWithValues(t, http.MethodPost, fmt.Sprintf("/%s/%s/issues/resolve_conversation", repo.OwnerName, repo.Name), map[string]string{
+		"_csrf":      GetCSRF(t, session, issueURL),
+		"origin":     "diff",
+		"action":     "Resolve",
+		"comment_id": fmt.Sprint(comment.ID),
+	})
+	htmlDoc = NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+	assert.Equal(t, 2, htmlDoc.Find(".resolution-reactions .bottom-reactions > .ui.label").Length())
+
+	req = NewRequestWithJSON(t, http.MethodDelete, reactionsURL, &api.EditReactionOption{Reaction: "heart"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, http.MethodGet, reactionsURL)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &reactions)
+	assert.Len(t, reactions, 1)
+
+	// the reactions are removed with the resolution
+	comment = unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: comment.ID})
+	assert.NoError(t, issues_model.MarkConversation(db.DefaultContext, comment, user2, false))
+	req = NewRequest(t, http.MethodGet, reactionsURL)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &reactions)
+	assert.Empty(t, reactions)
+}
@@ -344,3 +344,104 @@ func TestAPIUploadAssetRelease(t *testing.T) {
 		assert.EqualValues(t, 104, attachment.Size)
 	})
 }
+
+func TestAPIExternalAssetRelease(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, owner.LowerName)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	r := createNewReleaseUsingAPI(t, token, owner, repo, "release-tag", "", "Release Tag", "test")
+	assetURL := fmt.Sprintf("/api/v1/repos/%s/%s/releases/%d/assets/external", owner.Name, repo.Name, r.ID)
+
+	req := NewRequestWithJSON(t, http.MethodPost, assetURL, &api.CreateExternalAttachmentOption{
+		Name:   "gitea.tar.gz",
+		URL:    "https://cdn.example.com/gitea.tar.gz",
+		SHA256: "not-a-checksum",
+		Size:   15,
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, http.MethodPost, assetURL, &api.CreateExternalAttachmentOption{
+		Name:   "gitea.tar.gz",
+		URL:    "https://cdn.example.com/gitea.tar.gz",
+		SHA256: "fd6dcfd7cd7fa52f54a35e7a1b95c91f826785e5e0fcd1fa53615dc3f1f13949",
+		Size:   15,
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var attachment *api.Attachment
+	DecodeJSON(t, resp, &attachment)
+	assert.Equal(t, "external", attachment.Type)
+	assert.Equal(t, "unverified", attachment.VerificationStatus)
+	assert.EqualValues(t, 15, attachment.Size)
+	assert.Nil(t, attachment.VerifiedAt)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/releases/%d", owner.Name, repo.Name, r.ID))
+	resp = MakeRequest(t, req, http.StatusOK)
+	var release *api.Release
+	DecodeJSON(t, resp, &release)
+	if assert.Len(t, release.Attachments, 1) {
+		assert.Equal(t, attachment.UUID, release.Attachments[0].UUID)
+		assert.Equal(t, "fd6dcfd7cd7fa52f54a35e7a1b95c91f826785e5e0fcd1fa53615dc3f1f13949", release.Attachments[0].SHA256)
+	}
+
+	// downloads are redirected to the external url
+	req = NewRequest(t, "GET", fmt.Sprintf("/%s/%s/releases/download/release-tag/gitea.tar.gz", owner.Name, repo.Name))
+	resp = MakeRequest(t, req, http.StatusSeeOther)
+	assert.Equal(t, "https://cdn.example.com/gitea.tar.gz", resp.Header().Get("Location"))
+}
+
+func TestAPIReleaseChecksums(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, owner.LowerName)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	r := createNewReleaseUsingAPI(t, token, owner, repo, "release-tag", "", "Release Tag", "test")
+	releaseURL := fmt.Sprintf("/api/v1/repos/%s/%s/releases/%d", owner.Name, repo.Name, r.ID)
+
+	req := NewRequestWithBody(t, http.MethodPost, releaseURL+"/assets?name=asset.txt", strings.NewReader("release content")).
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", releaseURL+"/checksums")
+	resp := MakeRequest(t, req, http.StatusOK)
+	lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasSuffix(lines[0], "  repo1-release-tag.zip"))
+		assert.True(t, strings.HasSuffix(lines[1], "  repo1-release-tag.tar.gz"))
+		assert.Equal(t, "fd6dcfd7cd7fa52f54a35e7a1b95c91f826785e5e0fcd1fa53615dc3f1f13949  asset.txt", lines[2])
+	}
+
+	// no signing key is configured
+	req = NewRequest(t, "GET", releaseURL+"/checksums.asc")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", releaseURL+"/provenance")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var statement struct {
+		Type    string `json:"_type"`
+		Subject []struct {
+			Name   string            `json:"name"`
+			Digest map[string]string `json:"digest"`
+		} `json:"subject"`
+	}
+	DecodeJSON(t, resp, &statement)
+	assert.Equal(t, "https://in-toto.io/Statement/v1", statement.Type)
+	if assert.Len(t, statement.Subject, 3) {
+		assert.Equal(t, "asset.txt", statement.Subject[2].Name)
+		assert.Equal(t, "fd6dcfd7cd7fa52f54a35e7a1b95c91f826785e5e0fcd1fa53615dc3f1f13949", statement.Subject[2].Digest["sha256"])
+	}
+
+	// the checksums are regenerated when the attachments change
+	req = NewRequestWithBody(t, http.MethodPost, releaseURL+"/assets?name=other.txt", strings.NewReader("other")).
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequest(t, "GET", releaseURL+"/checksums")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Len(t, strings.Split(strings.TrimSpace(resp.Body.String()), "\n"), 4)
+}
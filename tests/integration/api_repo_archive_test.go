@@ -4,17 +4,26 @@
 package integration
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	auth_model "code.gitea.io/gitea/models/auth"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/tests"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +68,100 @@ func TestAPIDownloadArchive(t *testing.T) {
 	link, _ = url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/archive/master", user2.Name, repo.Name))
 	MakeRequest(t, NewRequest(t, "GET", link.String()).AddTokenAuth(token), http.StatusBadRequest)
 }
+
+func TestAPIDownloadArchiveWithInclude(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadRepository)
+
+		MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/archive/master.zip?include=lfs").AddTokenAuth(token), http.StatusUnprocessableEntity)
+		MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/archive/master.tar.gz?include=wiki").AddTokenAuth(token), http.StatusUnprocessableEntity)
+
+		t.Run("LFS", func(t *testing.T) {
+			files := downloadArchiveWithInclude(t, "/api/v1/repos/user2/lfs/archive/master.tar.gz?include=lfs", token)
+			assert.Equal(t, "# Testing documents in LFS\n", files["lfs/CONTRIBUTING.md"])
+			assert.Equal(t, "# An LFS-enabled repo\n", files["lfs/README.md"])
+		})
+
+		t.Run("Submodules", func(t *testing.T) {
+			dstPath := t.TempDir()
+			cloneURL, _ := url.Parse(u.String() + "user2/repo1.git")
+			cloneURL.User = url.UserPassword("user2", userPassword)
+			doGitClone(dstPath, cloneURL)(t)
+			doGitCreateBranch(dstPath, "with-submodules")(t)
+
+			repo1Commit, _, err := git.NewCommand(git.DefaultContext, "rev-parse", "HEAD").RunStdString(&git.RunOpts{Dir: dstPath})
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(filepath.Join(dstPath, ".gitmodules"), []byte(
+				"[submodule \"public\"]\n\tpath = public\n\turl = ../repo1.git\n"+
+					"[submodule \"private\"]\n\tpath = private\n\turl = ../lfs.git\n"), 0o644))
+			_, _, err = git.NewCommand(git.DefaultContext, "update-index", "--add", "--cacheinfo").AddDynamicArguments("160000", strings.TrimSpace(repo1Commit), "public").RunStdString(&git.RunOpts{Dir: dstPath})
+			assert.NoError(t, err)
+			_, _, err = git.NewCommand(git.DefaultContext, "update-index", "--add", "--cacheinfo").AddDynamicArguments("160000", "73cf03db6ece34e12bf91e8853dc58f678f2f82d", "private").RunStdString(&git.RunOpts{Dir: dstPath})
+			assert.NoError(t, err)
+			assert.NoError(t, git.AddChanges(dstPath, false, ".gitmodules"))
+			signature := git.Signature{Email: "user2@example.com", Name: "User Two", When: time.Now()}
+			assert.NoError(t, git.CommitChanges(dstPath, git.CommitChangesOptions{
+				Committer: &signature,
+				Author:    &signature,
+				Message:   "Add submodules",
+			}))
+			doGitPushTestRepository(dstPath, "origin", "with-submodules")(t)
+
+			files := downloadArchiveWithInclude(t, "/api/v1/repos/user2/repo1/archive/with-submodules.tar.gz?include=submodules", token)
+			assert.Contains(t, files, "repo1/README.md")
+			assert.Equal(t, files["repo1/README.md"], files["repo1/public/README.md"])
+			// private submodules are never included
+			assert.NotContains(t, files, "repo1/private/README.md")
+
+			// the archives can be limited to a path of the repository
+			files = downloadArchiveWithInclude(t, "/api/v1/repos/user2/repo1/archive/with-submodules.tar.gz?path=.gitmodules", token)
+			assert.Contains(t, files, "repo1/.gitmodules")
+			assert.NotContains(t, files, "repo1/README.md")
+			files = downloadArchiveWithInclude(t, "/api/v1/repos/user2/repo1/archive/with-submodules.tar.gz?include=submodules&path=public", token)
+			assert.Contains(t, files, "repo1/public/README.md")
+			assert.NotContains(t, files, "repo1/README.md")
+			MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/archive/with-submodules.tar.gz?path=missing").AddTokenAuth(token), http.StatusNotFound)
+			MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/archive/with-submodules.bundle?path=public").AddTokenAuth(token), http.StatusUnprocessableEntity)
+		})
+	})
+}
+
+// downloadArchiveWithInclude waits for the archive to be generated and returns the content of its files
+func downloadArchiveWithInclude(t *testing.T, link, token string) map[string]string {
+	var resp *httptest.ResponseRecorder
+	for i := 0; i < 50; i++ {
+		resp = MakeRequest(t, NewRequest(t, "GET", link).AddTokenAuth(token), NoExpectedStatus)
+		if resp.Code != http.StatusAccepted {
+			break
+		}
+		var status api.RepoArchiveStatus
+		DecodeJSON(t, resp, &status)
+		assert.Equal(t, "generating", status.Status)
+		time.Sleep(200 * time.Millisecond)
+	}
+	if !assert.Equal(t, http.StatusOK, resp.Code) {
+		return nil
+	}
+
+	gzr, err := gzip.NewReader(resp.Body)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			content, err := io.ReadAll(tr)
+			assert.NoError(t, err)
+			files[hdr.Name] = string(content)
+		}
+	}
+	return files
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoBisect(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	// the history of user2/repo-release from the newest commit
+	history := []string{
+		"7197b56fdc75b453f47c9110938cb46a303579fd",
+		"79f9d88f1b054d650f88da0bd658e21f7b0cf6ec",
+		"4380f99290b2b3922733ff82c57afad915ace907",
+		"a8a700e8c644c783ba2c6e742bb81bf91e244bff",
+		"f3f1c90ac949aa1b0f129d30f338d408663c8a83",
+		"184288e5acffbcb17160b990e8efe83b12dfaaba",
+	}
+	firstBad := history[2]
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+	token4 := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+	urlStr := "/api/v1/repos/user2/repo-release/bisect"
+
+	MakeRequest(t, NewRequest(t, "GET", urlStr), http.StatusUnauthorized)
+	MakeRequest(t, NewRequest(t, "GET", urlStr).AddTokenAuth(token), http.StatusNotFound)
+	MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr+"/verdict", &api.BisectVerdictOption{Verdict: "good"}).AddTokenAuth(token), http.StatusNotFound)
+	MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.StartBisectOption{Bad: history[0], Good: []string{"does-not-exist"}}).AddTokenAuth(token), http.StatusUnprocessableEntity)
+	MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.StartBisectOption{Bad: history[0], Good: []string{history[0]}}).AddTokenAuth(token), http.StatusUnprocessableEntity)
+
+	t.Run("Bisect", func(t *testing.T) {
+		resp := MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.StartBisectOption{Bad: history[0], Good: []string{history[5]}}).AddTokenAuth(token), http.StatusCreated)
+		var session api.BisectSession
+		DecodeJSON(t, resp, &session)
+		assert.Equal(t, history[0], session.Bad)
+		assert.Equal(t, []string{history[5]}, session.Good)
+		assert.Equal(t, 4, session.Remaining)
+
+		// the session belongs to user2
+		MakeRequest(t, NewRequest(t, "GET", urlStr).AddTokenAuth(token4), http.StatusNotFound)
+
+		for steps := 0; !session.Finished; steps++ {
+			assert.Less(t, steps, 4)
+			verdict := "good"
+			if slices.Index(history, session.Candidate) <= slices.Index(history, firstBad) {
+				verdict = "bad"
+			}
+			resp = MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr+"/verdict", &api.BisectVerdictOption{Verdict: verdict}).AddTokenAuth(token), http.StatusOK)
+			session = api.BisectSession{}
+			DecodeJSON(t, resp, &session)
+		}
+		assert.Equal(t, firstBad, session.FirstBad)
+		assert.Empty(t, session.Candidate)
+
+		resp = MakeRequest(t, NewRequest(t, "GET", urlStr).AddTokenAuth(token), http.StatusOK)
+		session = api.BisectSession{}
+		DecodeJSON(t, resp, &session)
+		assert.Equal(t, firstBad, session.FirstBad)
+		MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr+"/verdict", &api.BisectVerdictOption{Verdict: "good"}).AddTokenAuth(token), http.StatusUnprocessableEntity)
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		resp := MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.StartBisectOption{Bad: history[0], Good: []string{history[5]}, Path: "feature"}).AddTokenAuth(token), http.StatusCreated)
+		var session api.BisectSession
+		DecodeJSON(t, resp, &session)
+		assert.Equal(t, firstBad, session.Candidate)
+		assert.Equal(t, 1, session.Remaining)
+
+		resp = MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr+"/verdict", &api.BisectVerdictOption{Commit: firstBad, Verdict: "bad"}).AddTokenAuth(token), http.StatusOK)
+		session = api.BisectSession{}
+		DecodeJSON(t, resp, &session)
+		assert.True(t, session.Finished)
+		assert.Equal(t, firstBad, session.FirstBad)
+	})
+
+	MakeRequest(t, NewRequest(t, "DELETE", urlStr).AddTokenAuth(token), http.StatusNoContent)
+	MakeRequest(t, NewRequest(t, "DELETE", urlStr).AddTokenAuth(token), http.StatusNotFound)
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIReposBlame(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+
+	for _, ref := range [...]string{
+		"master", // Branch
+		"v1.1",   // Tag
+		"65f1bf27bc3bf70f64657658635e66094edbcb4d", // Commit
+	} {
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/blame/%s/README.md?lines=true", user.Name, ref).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var blame api.BlameResponse
+		DecodeJSON(t, resp, &blame)
+		assert.Equal(t, "README.md", blame.Path)
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", blame.SHA)
+		if assert.Len(t, blame.Hunks, 1) {
+			hunk := blame.Hunks[0]
+			assert.Equal(t, 1, hunk.StartLine)
+			assert.Equal(t, 3, hunk.EndLine)
+			assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", hunk.Commit.SHA)
+			assert.Equal(t, "user1", hunk.Author.Name)
+			assert.Nil(t, hunk.Previous)
+			assert.Equal(t, []string{"# repo1", "", "Description for repo1"}, hunk.Lines)
+		}
+	}
+
+	// lines are only returned on request
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/blame/master/README.md", user.Name).
+		AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var blame api.BlameResponse
+	DecodeJSON(t, resp, &blame)
+	if assert.Len(t, blame.Hunks, 1) {
+		assert.Empty(t, blame.Hunks[0].Lines)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/blame/master/not-exist.md", user.Name).
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	repo_service "code.gitea.io/gitea/services/repository"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIReposBundle(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+
+	for _, ref := range [...]string{
+		"master", // Branch
+		"v1.1",   // Tag
+		"65f1bf27bc3bf70f64657658635e66094edbcb4d", // Commit
+	} {
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/bundle/%s", user.Name, ref).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.True(t, strings.HasPrefix(resp.Body.String(), "# v2 git bundle\n"))
+	}
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/bundle/not-exist", user.Name).
+		AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestPregeneratedBundle(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1.git/"+repo_service.PregeneratedBundleName)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	assert.NoError(t, repo_service.GenerateRepoBundle(db.DefaultContext, repo))
+	assert.True(t, repo_service.HasPregeneratedBundle(repo))
+
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.True(t, strings.HasPrefix(resp.Body.String(), "# v2 git bundle\n"))
+
+	// the bundles of the repositories smaller than the minimum size are removed
+	assert.NoError(t, repo_service.GenerateRepositoryBundles(db.DefaultContext, 1024*1024*1024))
+	assert.False(t, repo_service.HasPregeneratedBundle(repo))
+}
@@ -36,3 +36,90 @@ func TestAPICompareBranches(t *testing.T) {
 	assert.Equal(t, 2, apiResp.TotalCommits)
 	assert.Len(t, apiResp.Commits, 2)
 }
+
+func TestAPICompareBranchesOptions(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadRepository)
+
+	// three-dot comparisons diff the head with the merge base
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo20/compare/remove-files-a...remove-files-b").AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var apiResp *api.Compare
+	DecodeJSON(t, resp, &apiResp)
+	assert.False(t, apiResp.DirectComparison)
+	assert.Equal(t, 2, apiResp.TotalCommits)
+	assert.Equal(t, 3, apiResp.TotalFiles)
+	assert.Equal(t, 1, apiResp.Additions)
+	assert.Equal(t, 2, apiResp.Deletions)
+	assert.Len(t, apiResp.Files, 3)
+
+	// two-dot comparisons diff the head with the base directly
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo20/compare/remove-files-a..remove-files-b").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiResp)
+	assert.True(t, apiResp.DirectComparison)
+	assert.Equal(t, 2, apiResp.TotalCommits)
+	assert.Equal(t, 1, apiResp.TotalFiles)
+	if assert.Len(t, apiResp.Files, 1) {
+		assert.Equal(t, "test.txt", apiResp.Files[0].Filename)
+		assert.Equal(t, "added", apiResp.Files[0].Status)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo20/compare/remove-files-a...remove-files-b?path=test.csv&path=link_hi").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiResp)
+	assert.Equal(t, 1, apiResp.TotalCommits)
+	assert.Len(t, apiResp.Commits, 1)
+	assert.Equal(t, 2, apiResp.TotalFiles)
+	assert.Equal(t, 0, apiResp.Additions)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo20/compare/remove-files-a...remove-files-b?stats_only=true").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiResp)
+	assert.True(t, apiResp.StatsOnly)
+	assert.Equal(t, 2, apiResp.TotalCommits)
+	assert.Empty(t, apiResp.Commits)
+	assert.Equal(t, 3, apiResp.TotalFiles)
+	assert.Empty(t, apiResp.Files)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo20/compare/remove-files-a...remove-files-b?rename_threshold=101").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo20/compare/remove-files-a...remove-files-b?rename_threshold=0&copy_threshold=50").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
+func TestAPICompareCommits(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadRepository)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo20/compare/remove-files-a...remove-files-b").AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var branchesResp *api.Compare
+	DecodeJSON(t, resp, &branchesResp)
+
+	// full and abbreviated commit SHAs can be compared like the branches
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo20/compare/%s...%s", branchesResp.BaseCommit, branchesResp.HeadCommit[:10]).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var apiResp *api.Compare
+	DecodeJSON(t, resp, &apiResp)
+	assert.Equal(t, branchesResp.BaseCommit, apiResp.BaseCommit)
+	assert.Equal(t, branchesResp.HeadCommit, apiResp.HeadCommit)
+	assert.Equal(t, 2, apiResp.TotalCommits)
+	assert.Equal(t, 3, apiResp.TotalFiles)
+
+	// the changed files can be paginated
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo20/compare/%s...%s?limit=2&page=2", branchesResp.BaseCommit, branchesResp.HeadCommit).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiResp)
+	assert.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+	assert.Equal(t, 3, apiResp.TotalFiles)
+	if assert.Len(t, apiResp.Files, 1) {
+		assert.Equal(t, branchesResp.Files[2].Filename, apiResp.Files[0].Filename)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo20/compare/%s...0123456789abcdef", branchesResp.BaseCommit).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
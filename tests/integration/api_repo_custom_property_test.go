@@ -0,0 +1,164 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgCustomProperties(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	ownerToken := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteOrganization)
+	memberToken := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteOrganization)
+	outsiderToken := getUserToken(t, "user5", auth_model.AccessTokenScopeReadOrganization)
+
+	req := NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/properties/schema/tier", &api.CreateOrUpdateCustomPropertyOption{
+		Type:          "single_select",
+		AllowedValues: []string{"low", "critical"},
+		DefaultValue:  "low",
+	}).AddTokenAuth(memberToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/properties/schema/tier", &api.CreateOrUpdateCustomPropertyOption{
+		Type:          "single_select",
+		AllowedValues: []string{"low", "critical"},
+		DefaultValue:  "medium",
+	}).AddTokenAuth(ownerToken)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/properties/schema/tier", &api.CreateOrUpdateCustomPropertyOption{
+		Type:          "single_select",
+		AllowedValues: []string{"low", "critical"},
+		DefaultValue:  "low",
+	}).AddTokenAuth(ownerToken)
+	resp := MakeRequest(t, req, http.StatusCreated)
+	var prop api.CustomProperty
+	DecodeJSON(t, resp, &prop)
+	assert.Equal(t, "tier", prop.Name)
+	assert.Equal(t, []string{"low", "critical"}, prop.AllowedValues)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/properties/schema/tier", &api.CreateOrUpdateCustomPropertyOption{
+		Type:          "single_select",
+		Description:   "Service tier",
+		AllowedValues: []string{"low", "critical"},
+		DefaultValue:  "low",
+	}).AddTokenAuth(ownerToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &prop)
+	assert.Equal(t, "Service tier", prop.Description)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/properties/schema/team", &api.CreateOrUpdateCustomPropertyOption{
+		Type: "string",
+	}).AddTokenAuth(ownerToken)
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/org3/properties/schema").AddTokenAuth(memberToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var props []*api.CustomProperty
+	DecodeJSON(t, resp, &props)
+	if assert.Len(t, props, 2) {
+		assert.Equal(t, "team", props[0].Name)
+		assert.Equal(t, "tier", props[1].Name)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/org3/properties/schema").AddTokenAuth(outsiderToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/org3/properties/schema/unknown").AddTokenAuth(ownerToken)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "DELETE", "/api/v1/orgs/org3/properties/schema/team").AddTokenAuth(memberToken)
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequest(t, "DELETE", "/api/v1/orgs/org3/properties/schema/team").AddTokenAuth(ownerToken)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "DELETE", "/api/v1/orgs/org3/properties/schema/team").AddTokenAuth(ownerToken)
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
+func TestAPIRepoCustomPropertyValues(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	orgToken := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteOrganization, auth_model.AccessTokenScopeWriteRepository)
+
+	for name, opt := range map[string]*api.CreateOrUpdateCustomPropertyOption{
+		"team": {Type: "string"},
+		"tier": {Type: "single_select", AllowedValues: []string{"low", "critical"}, DefaultValue: "low"},
+	} {
+		req := NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/properties/schema/"+name, opt).AddTokenAuth(orgToken)
+		MakeRequest(t, req, http.StatusCreated)
+	}
+
+	req := NewRequestWithJSON(t, "PATCH", "/api/v1/repos/org3/repo3/properties/values", &api.UpdateCustomPropertyValuesOption{
+		Properties: []*api.CustomPropertyValue{{Name: "tier", Value: "medium"}},
+	}).AddTokenAuth(orgToken)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/org3/repo3/properties/values", &api.UpdateCustomPropertyValuesOption{
+		Properties: []*api.CustomPropertyValue{{Name: "unknown", Value: "value"}},
+	}).AddTokenAuth(orgToken)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	memberToken := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/org3/repo3/properties/values", &api.UpdateCustomPropertyValuesOption{
+		Properties: []*api.CustomPropertyValue{{Name: "team", Value: "payments"}},
+	}).AddTokenAuth(memberToken)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/org3/repo3/properties/values", &api.UpdateCustomPropertyValuesOption{
+		Properties: []*api.CustomPropertyValue{{Name: "team", Value: "payments"}, {Name: "tier", Value: "critical"}},
+	}).AddTokenAuth(orgToken)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var values []*api.CustomPropertyValue
+	DecodeJSON(t, resp, &values)
+	assert.Equal(t, []*api.CustomPropertyValue{{Name: "team", Value: "payments"}, {Name: "tier", Value: "critical"}}, values)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/org3/repo5/properties/values").AddTokenAuth(orgToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &values)
+	assert.Equal(t, []*api.CustomPropertyValue{{Name: "tier", Value: "low"}}, values)
+
+	listRepoNames := func(url string) []string {
+		req := NewRequest(t, "GET", url).AddTokenAuth(orgToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var repos []*api.Repository
+		DecodeJSON(t, resp, &repos)
+		names := make([]string, 0, len(repos))
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"repo3"}, listRepoNames("/api/v1/orgs/org3/repos?property=team:payments"))
+	assert.Equal(t, []string{"repo3"}, listRepoNames("/api/v1/orgs/org3/repos?property=team:payments&property=tier:critical"))
+	assert.Empty(t, listRepoNames("/api/v1/orgs/org3/repos?property=team:payments&property=tier:low"))
+	assert.NotContains(t, listRepoNames("/api/v1/orgs/org3/repos?property=tier:low"), "repo3")
+	assert.Contains(t, listRepoNames("/api/v1/orgs/org3/repos?property=tier:low"), "repo5")
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/org3/repos?property=team").AddTokenAuth(orgToken)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/search?property=team:payments").AddTokenAuth(orgToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var result api.SearchResults
+	DecodeJSON(t, resp, &result)
+	if assert.Len(t, result.Data, 1) {
+		assert.Equal(t, "repo3", result.Data[0].Name)
+	}
+
+	// an empty value removes the value of the repository
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/org3/repo3/properties/values", &api.UpdateCustomPropertyValuesOption{
+		Properties: []*api.CustomPropertyValue{{Name: "tier", Value: ""}},
+	}).AddTokenAuth(orgToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &values)
+	assert.Equal(t, []*api.CustomPropertyValue{{Name: "team", Value: "payments"}, {Name: "tier", Value: "low"}}, values)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIDeferredCommit(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		executeAt := time.Now().Add(time.Hour)
+		deferChange := func(t *testing.T, path string) *api.DeferredCommit {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master", Message: "Bump version"},
+				Files: []*api.ChangeFileOperation{
+					{Operation: "create", Path: path, ContentBase64: base64.StdEncoding.EncodeToString([]byte("1.2.3\n"))},
+				},
+				ExecuteAt: &executeAt,
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusAccepted)
+			var deferredCommit api.DeferredCommit
+			DecodeJSON(t, resp, &deferredCommit)
+			return &deferredCommit
+		}
+		getDeferredCommit := func(t *testing.T, id int64) *api.DeferredCommit {
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/deferred_commits/%d", id)).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var deferredCommit api.DeferredCommit
+			DecodeJSON(t, resp, &deferredCommit)
+			return &deferredCommit
+		}
+
+		deferred := deferChange(t, "VERSION")
+		assert.Equal(t, "pending", deferred.Status)
+		assert.Equal(t, "master", deferred.Branch)
+		assert.Equal(t, executeAt.Unix(), deferred.ExecuteAt.Unix())
+		canceled := deferChange(t, "FREEZE")
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/deferred_commits").AddTokenAuth(token)
+		var deferredCommits []*api.DeferredCommit
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &deferredCommits)
+		assert.Len(t, deferredCommits, 2)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/user2/repo1/deferred_commits/%d", canceled.ID)).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/user2/repo1/deferred_commits/%d", canceled.ID)).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// the change isn't committed before its time
+		require.NoError(t, files_service.QueueDueDeferredCommits(db.DefaultContext))
+		MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/VERSION").AddTokenAuth(token), http.StatusNotFound)
+
+		_, err := db.GetEngine(db.DefaultContext).ID(deferred.ID).Cols("execute_unix").Update(&repo_model.DeferredCommit{ExecuteUnix: 1})
+		require.NoError(t, err)
+		require.NoError(t, files_service.QueueDueDeferredCommits(db.DefaultContext))
+		assert.Eventually(t, func() bool {
+			return getDeferredCommit(t, deferred.ID).Status == "done"
+		}, 10*time.Second, 100*time.Millisecond)
+
+		deferred = getDeferredCommit(t, deferred.ID)
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/"+deferred.CommitID).AddTokenAuth(token)
+		var commit api.Commit
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &commit)
+		assert.Equal(t, "Bump version\n", commit.RepoCommit.Message)
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/VERSION").AddTokenAuth(token)
+		assert.Equal(t, "1.2.3\n", MakeRequest(t, req, http.StatusOK).Body.String())
+	})
+}
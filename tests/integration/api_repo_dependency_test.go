@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	dependency_service "code.gitea.io/gitea/services/dependency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoDependencies(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		lib := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		app := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 16})
+
+		assert.NoError(t, createOrReplaceFileInBranch(user2, lib, "go.mod", lib.DefaultBranch, "module example.com/lib\n\ngo 1.22\n"))
+		assert.NoError(t, createOrReplaceFileInBranch(user2, app, "go.mod", app.DefaultBranch, "module example.com/app\n\nrequire (\n\texample.com/lib v1.2.0\n\tgolang.org/x/text v0.14.0 // indirect\n)\n"))
+		assert.NoError(t, createOrReplaceFileInBranch(user2, app, "web/package.json", app.DefaultBranch, `{"devDependencies": {"eslint": "9.0.0"}}`))
+		assert.NoError(t, dependency_service.UpdateRepoDependencies(db.DefaultContext, lib))
+		assert.NoError(t, dependency_service.UpdateRepoDependencies(db.DefaultContext, app))
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeReadRepository)
+
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo16/dependencies").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var deps []*api.RepoDependency
+		DecodeJSON(t, resp, &deps)
+		assert.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+		if assert.Len(t, deps, 3) {
+			assert.Equal(t, "example.com/lib", deps[0].Name)
+			assert.Equal(t, "v1.2.0", deps[0].Version)
+			if assert.Len(t, deps[0].InternalRepos, 1) {
+				assert.Equal(t, "user2/repo1", deps[0].InternalRepos[0].FullName)
+			}
+			assert.Equal(t, "golang.org/x/text", deps[1].Name)
+			assert.Empty(t, deps[1].InternalRepos)
+			assert.Equal(t, "npm", deps[2].Ecosystem)
+			assert.Equal(t, "web/package.json", deps[2].Manifest)
+			assert.True(t, deps[2].IsDev)
+		}
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo16/dependencies?ecosystem=npm").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &deps)
+		assert.Len(t, deps, 1)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/dependents").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var dependents []*api.RepoDependent
+		DecodeJSON(t, resp, &dependents)
+		if assert.Len(t, dependents, 1) {
+			assert.Equal(t, "user2/repo16", dependents[0].Repository.FullName)
+			assert.Equal(t, "go.mod", dependents[0].Manifest)
+		}
+
+		// the private dependent must not be disclosed to other users
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/dependents")
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &dependents)
+		assert.Empty(t, dependents)
+
+		session := loginUser(t, user2.Name)
+		req = NewRequest(t, "GET", "/user2/repo1/activity/dependents")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		items := htmlDoc.Find("#repo-dependents .flex-item")
+		if assert.Equal(t, 1, items.Length()) {
+			assert.Equal(t, "user2/repo16", strings.TrimSpace(items.Find(".flex-item-title a").Text()))
+			assert.Equal(t, "go.mod", strings.TrimSpace(items.Find(".flex-item-body").Text()))
+		}
+
+		req = NewRequest(t, "GET", "/user2/repo1/activity/dependents")
+		resp = MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.Equal(t, 0, htmlDoc.Find("#repo-dependents .flex-item").Length())
+	})
+}
@@ -273,3 +273,45 @@ func TestAPIUpdateFile(t *testing.T) {
 		MakeRequest(t, req, http.StatusForbidden)
 	})
 }
+
+func TestAPIUpdateFileRename(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		content := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/rename/from.txt", &api.CreateFileOptions{
+			FileOptions:   api.FileOptions{BranchName: "master"},
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte(content)),
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var created api.FileResponse
+		DecodeJSON(t, resp, &created)
+		assert.Empty(t, created.Content.PreviousPath)
+
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/rename/to.txt", &api.UpdateFileOptions{
+			DeleteFileOptions: api.DeleteFileOptions{
+				FileOptions: api.FileOptions{BranchName: "master"},
+				SHA:         created.Content.SHA,
+			},
+			FromPath:      "rename/from.txt",
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte(content + "eleven\n")),
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var renamed api.FileResponse
+		DecodeJSON(t, resp, &renamed)
+		assert.Equal(t, "rename/to.txt", renamed.Content.Path)
+		assert.Equal(t, "rename/from.txt", renamed.Content.PreviousPath)
+		assert.Greater(t, renamed.Content.Similarity, 50)
+		assert.Less(t, renamed.Content.Similarity, 100)
+
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/"+renamed.Commit.SHA).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var commit api.Commit
+		DecodeJSON(t, resp, &commit)
+		if assert.Len(t, commit.Files, 1) {
+			assert.Equal(t, "rename/to.txt", commit.Files[0].Filename)
+			assert.Equal(t, "renamed", commit.Files[0].Status)
+			assert.Equal(t, "rename/from.txt", commit.Files[0].PreviousFilename)
+			assert.Equal(t, renamed.Content.Similarity, commit.Files[0].Similarity)
+		}
+	})
+}
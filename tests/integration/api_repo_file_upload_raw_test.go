@@ -0,0 +1,76 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUploadRawFile(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		content := []byte{0x00, 0x01, 0x02, 0xff, 'r', 'a', 'w'}
+		readFile := func(t *testing.T, path string) []byte {
+			resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/"+path).AddTokenAuth(token), http.StatusOK)
+			return resp.Body.Bytes()
+		}
+
+		t.Run("Create", func(t *testing.T) {
+			req := NewRequestWithBody(t, "PUT", "/api/v1/repos/user2/repo1/contents-raw/raw/file.bin?message=Upload+raw", bytes.NewReader(content)).AddTokenAuth(token)
+			req.Header.Set("Content-Type", "application/octet-stream")
+			resp := MakeRequest(t, req, http.StatusCreated)
+			var fileResponse api.FileResponse
+			DecodeJSON(t, resp, &fileResponse)
+			assert.Equal(t, "raw/file.bin", fileResponse.Content.Path)
+			assert.EqualValues(t, len(content), fileResponse.Content.Size)
+			assert.Equal(t, "Upload raw\n", fileResponse.Commit.Message)
+			assert.Equal(t, content, readFile(t, "raw/file.bin"))
+		})
+
+		t.Run("UpdateMultipart", func(t *testing.T) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("file", "file.bin")
+			assert.NoError(t, err)
+			_, _ = part.Write([]byte("updated"))
+			assert.NoError(t, writer.Close())
+
+			req := NewRequestWithBody(t, "PUT", "/api/v1/repos/user2/repo1/contents-raw/raw/file.bin", body).AddTokenAuth(token)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, []byte("updated"), readFile(t, "raw/file.bin"))
+		})
+
+		t.Run("TooLarge", func(t *testing.T) {
+			defer test.MockVariableValue(&setting.Repository.Upload.FileMaxSize, 1)()
+			req := NewRequestWithBody(t, "PUT", "/api/v1/repos/user2/repo1/contents-raw/raw/large.bin", bytes.NewReader(make([]byte, 1024*1024+1))).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusRequestEntityTooLarge)
+		})
+
+		t.Run("ForbiddenType", func(t *testing.T) {
+			defer test.MockVariableValue(&setting.Repository.Upload.AllowedTypes, ".txt")()
+			req := NewRequestWithBody(t, "PUT", "/api/v1/repos/user2/repo1/contents-raw/raw/other.bin", bytes.NewReader(content)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusBadRequest)
+			req = NewRequestWithBody(t, "PUT", "/api/v1/repos/user2/repo1/contents-raw/raw/other.txt", bytes.NewReader(content)).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+		})
+
+		t.Run("NoPermission", func(t *testing.T) {
+			readToken := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+			req := NewRequestWithBody(t, "PUT", "/api/v1/repos/user2/repo1/contents-raw/raw/denied.bin", bytes.NewReader(content)).AddTokenAuth(readToken)
+			MakeRequest(t, req, http.StatusForbidden)
+		})
+	})
+}
@@ -9,18 +9,24 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"testing"
+	"time"
 
 	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/models/webhook"
 	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/services/context"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func getChangeFilesOptions() *api.ChangeFilesOptions {
@@ -309,3 +315,575 @@ func TestAPIChangeFiles(t *testing.T) {
 		MakeRequest(t, req, http.StatusForbidden)
 	})
 }
+
+func TestAPIChangeFilesCherryPick(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/contents", user2.Name, repo1.Name)
+
+		changeFiles := func(t *testing.T, opts *api.ChangeFilesOptions, expectedStatus int) *api.FilesResponse {
+			resp := MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, opts).AddTokenAuth(token), expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return nil
+			}
+			var filesResponse api.FilesResponse
+			DecodeJSON(t, resp, &filesResponse)
+			return &filesResponse
+		}
+		readmeSHA := func(t *testing.T, branch string) string {
+			resp := MakeRequest(t, NewRequest(t, "GET", urlStr+"/README.md?ref="+branch).AddTokenAuth(token), http.StatusOK)
+			var contents api.ContentsResponse
+			DecodeJSON(t, resp, &contents)
+			return contents.SHA
+		}
+
+		changeFiles(t, &api.ChangeFilesOptions{FileOptions: api.FileOptions{BranchName: "master"}}, http.StatusUnprocessableEntity)
+		changeFiles(t, &api.ChangeFilesOptions{FileOptions: api.FileOptions{BranchName: "master"}, CherryPick: "does-not-exist"}, http.StatusUnprocessableEntity)
+
+		// a commit on another branch and a diverging one on master
+		picked := changeFiles(t, &api.ChangeFilesOptions{
+			FileOptions: api.FileOptions{BranchName: "master", NewBranchName: "cherry", Message: "Add the picked file"},
+			Files: []*api.ChangeFileOperation{
+				{Operation: "create", Path: "cherry/picked.txt", ContentBase64: base64.StdEncoding.EncodeToString([]byte("picked"))},
+			},
+		}, http.StatusCreated)
+		_, err := createFile(user2, repo1, "master-only.txt")
+		assert.NoError(t, err)
+
+		t.Run("CherryPick", func(t *testing.T) {
+			filesResponse := changeFiles(t, &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master"},
+				CherryPick:  picked.Commit.SHA,
+			}, http.StatusCreated)
+			if assert.Len(t, filesResponse.Files, 1) {
+				assert.Equal(t, "cherry/picked.txt", filesResponse.Files[0].Path)
+			}
+			assert.Equal(t, "Add the picked file\n\n(cherry picked from commit "+picked.Commit.SHA+")\n", filesResponse.Commit.Message)
+			assert.NotEqual(t, picked.Commit.SHA, filesResponse.Commit.SHA)
+			MakeRequest(t, NewRequest(t, "GET", urlStr+"/master-only.txt?ref=master").AddTokenAuth(token), http.StatusOK)
+		})
+
+		t.Run("CherryPickWithFiles", func(t *testing.T) {
+			filesResponse := changeFiles(t, &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master", NewBranchName: "cherry-with-files", Message: "Pick and add"},
+				CherryPick:  picked.Commit.SHA,
+				Files: []*api.ChangeFileOperation{
+					{Operation: "create", Path: "cherry/added.txt", ContentBase64: base64.StdEncoding.EncodeToString([]byte("added"))},
+				},
+			}, http.StatusCreated)
+			if assert.Len(t, filesResponse.Files, 2) {
+				assert.Equal(t, "cherry/picked.txt", filesResponse.Files[0].Path)
+				assert.Equal(t, "cherry/added.txt", filesResponse.Files[1].Path)
+			}
+			assert.Equal(t, "Pick and add\n", filesResponse.Commit.Message)
+		})
+
+		t.Run("Conflict", func(t *testing.T) {
+			conflicting := changeFiles(t, &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "cherry", Message: "Update README on cherry"},
+				Files: []*api.ChangeFileOperation{
+					{Operation: "update", Path: "README.md", SHA: readmeSHA(t, "cherry"), ContentBase64: base64.StdEncoding.EncodeToString([]byte("cherry"))},
+				},
+			}, http.StatusCreated)
+			changeFiles(t, &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master", Message: "Update README on master"},
+				Files: []*api.ChangeFileOperation{
+					{Operation: "update", Path: "README.md", SHA: readmeSHA(t, "master"), ContentBase64: base64.StdEncoding.EncodeToString([]byte("master"))},
+				},
+			}, http.StatusCreated)
+
+			changeFiles(t, &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master"},
+				CherryPick:  conflicting.Commit.SHA,
+			}, http.StatusConflict)
+		})
+	})
+}
+
+func TestAPIChangeFilesSymlink(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		changeFiles := func(t *testing.T, file *api.ChangeFileOperation) *api.ContentsResponse {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master"},
+				Files:       []*api.ChangeFileOperation{file},
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusCreated)
+			var filesResponse api.FilesResponse
+			DecodeJSON(t, resp, &filesResponse)
+			return filesResponse.Files[0]
+		}
+
+		link := changeFiles(t, &api.ChangeFileOperation{
+			Operation:     "create",
+			Path:          "links/readme",
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte("../README.md")),
+			IsSymlink:     true,
+		})
+		assert.Equal(t, "symlink", link.Type)
+		assert.Equal(t, "../README.md", *link.Target)
+
+		link = changeFiles(t, &api.ChangeFileOperation{
+			Operation:     "update",
+			Path:          "links/readme",
+			SHA:           link.SHA,
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte("../LICENSE")),
+			IsSymlink:     true,
+		})
+		assert.Equal(t, "symlink", link.Type)
+		assert.Equal(t, "../LICENSE", *link.Target)
+
+		// a link updated without the flag stays a link
+		link = changeFiles(t, &api.ChangeFileOperation{
+			Operation:     "update",
+			Path:          "links/readme",
+			SHA:           link.SHA,
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte("../README.md")),
+		})
+		assert.Equal(t, "symlink", link.Type)
+		assert.Equal(t, "../README.md", *link.Target)
+
+		updateFile := func(t *testing.T, opts *api.UpdateFileOptions, expectedStatus int) *api.ContentsResponse {
+			req := NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/links/readme", opts).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusOK {
+				return nil
+			}
+			var fileResponse api.FileResponse
+			DecodeJSON(t, resp, &fileResponse)
+			return fileResponse.Content
+		}
+		link = updateFile(t, &api.UpdateFileOptions{
+			DeleteFileOptions: api.DeleteFileOptions{FileOptions: api.FileOptions{BranchName: "master"}, SHA: link.SHA},
+			ContentBase64:     base64.StdEncoding.EncodeToString([]byte("../LICENSE")),
+		}, http.StatusOK)
+		assert.Equal(t, "symlink", link.Type)
+		assert.Equal(t, "../LICENSE", *link.Target)
+
+		// the mode of a link can't be changed
+		updateFile(t, &api.UpdateFileOptions{
+			DeleteFileOptions: api.DeleteFileOptions{FileOptions: api.FileOptions{BranchName: "master"}, SHA: link.SHA},
+			ContentBase64:     base64.StdEncoding.EncodeToString([]byte("file")),
+			Mode:              "100644",
+		}, http.StatusUnprocessableEntity)
+
+		// a link can't be replaced by a new file
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+			FileOptions: api.FileOptions{BranchName: "master"},
+			Files: []*api.ChangeFileOperation{
+				{Operation: "create", Path: "links/readme", ContentBase64: base64.StdEncoding.EncodeToString([]byte("file"))},
+			},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
+
+func TestAPIChangeFilesMode(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		changeFile := func(t *testing.T, file *api.ChangeFileOperation, expectedStatus int) *api.ContentsResponse {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master"},
+				Files:       []*api.ChangeFileOperation{file},
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return nil
+			}
+			var filesResponse api.FilesResponse
+			DecodeJSON(t, resp, &filesResponse)
+			return filesResponse.Files[0]
+		}
+		isExecutable := func(t *testing.T, treePath string) bool {
+			gitRepo, err := gitrepo.OpenRepository(stdCtx.Background(), repo1)
+			assert.NoError(t, err)
+			defer gitRepo.Close()
+			commit, err := gitRepo.GetBranchCommit("master")
+			assert.NoError(t, err)
+			entry, err := commit.GetTreeEntryByPath(treePath)
+			assert.NoError(t, err)
+			return entry.IsExecutable()
+		}
+		content := func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		}
+
+		changeFile(t, &api.ChangeFileOperation{Operation: "create", Path: "scripts/run.sh", ContentBase64: content("#!/bin/sh"), Mode: "100777"}, http.StatusUnprocessableEntity)
+		changeFile(t, &api.ChangeFileOperation{Operation: "create", Path: "scripts/link", ContentBase64: content("run.sh"), IsSymlink: true, Mode: "100755"}, http.StatusUnprocessableEntity)
+
+		script := changeFile(t, &api.ChangeFileOperation{Operation: "create", Path: "scripts/run.sh", ContentBase64: content("#!/bin/sh"), Mode: "100755"}, http.StatusCreated)
+		assert.True(t, isExecutable(t, "scripts/run.sh"))
+
+		// the mode is kept when it isn't given
+		script = changeFile(t, &api.ChangeFileOperation{Operation: "update", Path: "scripts/run.sh", SHA: script.SHA, ContentBase64: content("#!/bin/sh\nexit 0")}, http.StatusCreated)
+		assert.True(t, isExecutable(t, "scripts/run.sh"))
+
+		changeFile(t, &api.ChangeFileOperation{Operation: "update", Path: "scripts/run.sh", SHA: script.SHA, ContentBase64: content("#!/bin/sh\nexit 0"), Mode: "100644"}, http.StatusCreated)
+		assert.False(t, isExecutable(t, "scripts/run.sh"))
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/scripts/other.sh", &api.CreateFileOptions{
+			ContentBase64: content("#!/bin/sh"),
+			Mode:          "100755",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+		assert.True(t, isExecutable(t, "scripts/other.sh"))
+	})
+}
+
+func TestAPIChangeFilesDirectories(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		changeFiles := func(t *testing.T, message string, files []*api.ChangeFileOperation, expectedStatus int) *api.FilesResponse {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master", Message: message},
+				Files:       files,
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return nil
+			}
+			var filesResponse api.FilesResponse
+			DecodeJSON(t, resp, &filesResponse)
+			return &filesResponse
+		}
+		fileStatus := func(t *testing.T, path string, expectedStatus int) {
+			MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/"+path).AddTokenAuth(token), expectedStatus)
+		}
+
+		content := base64.StdEncoding.EncodeToString([]byte("content"))
+		changeFiles(t, "Add a directory", []*api.ChangeFileOperation{
+			{Operation: "create", Path: "dir/a.txt", ContentBase64: content},
+			{Operation: "create", Path: "dir/sub/b.sh", ContentBase64: content, Mode: "100755"},
+			{Operation: "create", Path: "other/c.txt", ContentBase64: content},
+		}, http.StatusCreated)
+
+		t.Run("MoveDir", func(t *testing.T) {
+			filesResponse := changeFiles(t, "", []*api.ChangeFileOperation{
+				{Operation: "move-dir", FromPath: "dir", Path: "moved/dir"},
+			}, http.StatusCreated)
+			assert.Equal(t, "Move dir to moved/dir\n", filesResponse.Commit.Message)
+			if assert.Len(t, filesResponse.Files, 1) && assert.NotNil(t, filesResponse.Files[0]) {
+				assert.Equal(t, "dir", filesResponse.Files[0].Type)
+				assert.Equal(t, "moved/dir", filesResponse.Files[0].Path)
+			}
+			fileStatus(t, "dir/a.txt", http.StatusNotFound)
+			fileStatus(t, "moved/dir/a.txt", http.StatusOK)
+
+			resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/trees/master?recursive=true").AddTokenAuth(token), http.StatusOK)
+			var tree api.GitTreeResponse
+			DecodeJSON(t, resp, &tree)
+			modes := map[string]string{}
+			for _, entry := range tree.Entries {
+				modes[entry.Path] = entry.Mode
+			}
+			assert.Equal(t, "100755", modes["moved/dir/sub/b.sh"])
+			assert.Equal(t, "100644", modes["moved/dir/a.txt"])
+		})
+
+		t.Run("MoveDirInvalid", func(t *testing.T) {
+			changeFiles(t, "", []*api.ChangeFileOperation{{Operation: "move-dir", FromPath: "moved", Path: "other"}}, http.StatusUnprocessableEntity)
+			changeFiles(t, "", []*api.ChangeFileOperation{{Operation: "move-dir", FromPath: "moved", Path: "moved/inside"}}, http.StatusUnprocessableEntity)
+			changeFiles(t, "", []*api.ChangeFileOperation{{Operation: "move-dir", FromPath: "missing", Path: "somewhere"}}, http.StatusNotFound)
+			changeFiles(t, "", []*api.ChangeFileOperation{{Operation: "move-dir", FromPath: "other/c.txt", Path: "somewhere"}}, http.StatusNotFound)
+		})
+
+		t.Run("DeleteDir", func(t *testing.T) {
+			filesResponse := changeFiles(t, "", []*api.ChangeFileOperation{
+				{Operation: "delete-dir", Path: "moved"},
+				{Operation: "create", Path: "other/d.txt", ContentBase64: content},
+			}, http.StatusCreated)
+			assert.Equal(t, "Add other/d.txt\nDelete moved\n", filesResponse.Commit.Message)
+			fileStatus(t, "moved/dir/a.txt", http.StatusNotFound)
+			fileStatus(t, "moved/dir/sub/b.sh", http.StatusNotFound)
+			fileStatus(t, "other/c.txt", http.StatusOK)
+			fileStatus(t, "other/d.txt", http.StatusOK)
+
+			changeFiles(t, "", []*api.ChangeFileOperation{{Operation: "delete-dir", Path: "moved"}}, http.StatusNotFound)
+		})
+	})
+}
+
+func TestAPIChangeFilesTrailers(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		createFile := func(t *testing.T, path string, opts api.FileOptions, expectedStatus int) *api.FileResponse {
+			opts.BranchName = "master"
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/"+path, &api.CreateFileOptions{
+				FileOptions:   opts,
+				ContentBase64: base64.StdEncoding.EncodeToString([]byte("content")),
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return nil
+			}
+			var fileResponse api.FileResponse
+			DecodeJSON(t, resp, &fileResponse)
+			return &fileResponse
+		}
+
+		t.Run("Trailers", func(t *testing.T) {
+			fileResponse := createFile(t, "trailers/a.txt", api.FileOptions{
+				Message: "Add a file\n\nWith a body.",
+				Signoff: true,
+				Trailers: []api.CommitTrailer{
+					{Key: "Co-authored-by", Value: "Jane Doe <jane@example.com>"},
+					{Key: "Fixes", Value: "#1"},
+				},
+			}, http.StatusCreated)
+			assert.Equal(t, "Add a file\n\nWith a body.\n\nCo-authored-by: Jane Doe <jane@example.com>\nFixes: #1\nSigned-off-by: "+user2.NewGitSig().String()+"\n",
+				fileResponse.Commit.Message)
+		})
+
+		t.Run("ExistingTrailerBlock", func(t *testing.T) {
+			fileResponse := createFile(t, "trailers/b.txt", api.FileOptions{
+				Message:  "Add another file\n\nFixes: #1",
+				Trailers: []api.CommitTrailer{{Key: "Fixes", Value: "#1"}, {Key: "Refs", Value: "#2"}},
+			}, http.StatusCreated)
+			assert.Equal(t, "Add another file\n\nFixes: #1\nRefs: #2\n", fileResponse.Commit.Message)
+		})
+
+		t.Run("InvalidTrailer", func(t *testing.T) {
+			createFile(t, "trailers/c.txt", api.FileOptions{
+				Trailers: []api.CommitTrailer{{Key: "Not a key", Value: "value"}},
+			}, http.StatusUnprocessableEntity)
+			createFile(t, "trailers/c.txt", api.FileOptions{
+				Trailers: []api.CommitTrailer{{Key: "Refs", Value: "#1\nInjected: value"}},
+			}, http.StatusUnprocessableEntity)
+		})
+	})
+}
+
+func TestAPIChangeFilesEncoding(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		changeFiles := func(t *testing.T, files []*api.ChangeFileOperation, expectedStatus int) {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master"},
+				Files:       files,
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, expectedStatus)
+		}
+		rawContent := func(t *testing.T, path string) string {
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/"+path).AddTokenAuth(token)
+			return MakeRequest(t, req, http.StatusOK).Body.String()
+		}
+
+		changeFiles(t, []*api.ChangeFileOperation{
+			{Operation: "create", Path: "encoding/latin1.txt", ContentBase64: "café\nolé\n", Encoding: "iso-8859-1", LineEndings: "crlf"},
+			{Operation: "create", Path: "encoding/text.txt", ContentBase64: "a\r\nb\r\n", Encoding: "utf-8", LineEndings: "lf"},
+			{Operation: "create", Path: "encoding/base64.txt", ContentBase64: base64.StdEncoding.EncodeToString([]byte("a\r\nb\n"))},
+		}, http.StatusCreated)
+		assert.Equal(t, "caf\xe9\r\nol\xe9\r\n", rawContent(t, "encoding/latin1.txt"))
+		assert.Equal(t, "a\nb\n", rawContent(t, "encoding/text.txt"))
+		assert.Equal(t, "a\r\nb\n", rawContent(t, "encoding/base64.txt"))
+
+		changeFiles(t, []*api.ChangeFileOperation{
+			{Operation: "create", Path: "encoding/invalid.txt", ContentBase64: "日本", Encoding: "iso-8859-1"},
+		}, http.StatusUnprocessableEntity)
+		changeFiles(t, []*api.ChangeFileOperation{
+			{Operation: "create", Path: "encoding/invalid.txt", ContentBase64: "not base64!"},
+		}, http.StatusUnprocessableEntity)
+		changeFiles(t, []*api.ChangeFileOperation{
+			{Operation: "create", Path: "encoding/invalid.txt", ContentBase64: "text", Encoding: "utf-16"},
+		}, http.StatusUnprocessableEntity)
+	})
+}
+
+func TestAPIChangeFilesGitAttributes(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		changeFiles := func(t *testing.T, files []*api.ChangeFileOperation) {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "master"},
+				Files:       files,
+			}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+		}
+		rawContent := func(t *testing.T, path string) string {
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/"+path).AddTokenAuth(token)
+			return MakeRequest(t, req, http.StatusOK).Body.String()
+		}
+
+		changeFiles(t, []*api.ChangeFileOperation{
+			{Operation: "create", Path: ".gitattributes", ContentBase64: "*.txt text eol=crlf\n*.auto text=auto\n*.bin -text\n*.c ident\n", Encoding: "utf-8"},
+			{Operation: "create", Path: "attrs/text.txt", ContentBase64: "a\r\nb\r\n", Encoding: "utf-8"},
+			{Operation: "create", Path: "attrs/file.auto", ContentBase64: "a\r\nb\r\n", Encoding: "utf-8"},
+			{Operation: "create", Path: "attrs/binary.auto", ContentBase64: "a\x00\r\n", Encoding: "utf-8"},
+			{Operation: "create", Path: "attrs/file.bin", ContentBase64: "a\r\nb\r\n", Encoding: "utf-8"},
+			{Operation: "create", Path: "attrs/file.c", ContentBase64: "/* $Id: 1234 $ */\r\n", Encoding: "utf-8"},
+		})
+		assert.Equal(t, "a\nb\n", rawContent(t, "attrs/text.txt"))
+		assert.Equal(t, "a\nb\n", rawContent(t, "attrs/file.auto"))
+		assert.Equal(t, "a\x00\r\n", rawContent(t, "attrs/binary.auto"))
+		assert.Equal(t, "a\r\nb\r\n", rawContent(t, "attrs/file.bin"))
+		assert.Equal(t, "/* $Id$ */\r\n", rawContent(t, "attrs/file.c"))
+	})
+}
+
+func TestAPIChangeFilesSkipWebhooks(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token2 := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		token4 := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+		createFile := func(t *testing.T, token, repoPath, path string, opts api.FileOptions, expectedStatus int) string {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/"+repoPath+"/contents/"+path, &api.CreateFileOptions{
+				FileOptions:   opts,
+				ContentBase64: base64.StdEncoding.EncodeToString([]byte("content")),
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return ""
+			}
+			var fileResponse api.FileResponse
+			DecodeJSON(t, resp, &fileResponse)
+			return fileResponse.Commit.SHA
+		}
+		pushedCommits := func(t *testing.T) []string {
+			hookTasks, err := webhook.HookTasks(db.DefaultContext, 1, 1)
+			assert.NoError(t, err)
+			var commits []string
+			for _, hookTask := range hookTasks {
+				if hookTask.PayloadContent == "" {
+					continue // fixtures
+				}
+				var payload api.PushPayload
+				assert.NoError(t, json.Unmarshal([]byte(hookTask.PayloadContent), &payload))
+				commits = append(commits, payload.After)
+			}
+			return commits
+		}
+
+		// user4 can write to user5/repo4 but isn't an administrator of it
+		createFile(t, token4, "user5/repo4", "skipped.txt", api.FileOptions{SkipCI: true}, http.StatusForbidden)
+		createFile(t, token4, "user5/repo4", "skipped.txt", api.FileOptions{SkipWebhooks: true}, http.StatusForbidden)
+
+		skipped := createFile(t, token2, "user2/repo1", "skipped.txt", api.FileOptions{SkipWebhooks: true, SkipCI: true}, http.StatusCreated)
+		notified := createFile(t, token2, "user2/repo1", "notified.txt", api.FileOptions{}, http.StatusCreated)
+		assert.Eventually(t, func() bool {
+			return slices.Contains(pushedCommits(t), notified)
+		}, 5*time.Second, 100*time.Millisecond)
+		assert.NotContains(t, pushedCommits(t), skipped)
+	})
+}
+
+func TestAPIChangeFilesAmend(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		changeFiles := func(t *testing.T, opts *api.ChangeFilesOptions, expectedStatus int) *api.FilesResponse {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", opts).AddTokenAuth(token)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return nil
+			}
+			var filesResponse api.FilesResponse
+			DecodeJSON(t, resp, &filesResponse)
+			return &filesResponse
+		}
+		updateConfig := func(content string, amend bool) *api.ChangeFilesOptions {
+			return &api.ChangeFilesOptions{
+				FileOptions: api.FileOptions{BranchName: "bot/config"},
+				Files:       []*api.ChangeFileOperation{{Operation: "create", Path: "config-" + content + ".txt", ContentBase64: base64.StdEncoding.EncodeToString([]byte(content))}},
+				Amend:       amend,
+			}
+		}
+
+		first := updateConfig("1", false)
+		first.BranchName = "master"
+		first.NewBranchName = "bot/config"
+		first.Message = "Update config"
+		created := changeFiles(t, first, http.StatusCreated)
+		master := created.Commit.Parents[0].SHA
+
+		// the head of the branch is replaced, keeping its message and its parent
+		amended := changeFiles(t, updateConfig("2", true), http.StatusCreated)
+		assert.Equal(t, "Update config\n", amended.Commit.Message)
+		require.Len(t, amended.Commit.Parents, 1)
+		assert.Equal(t, master, amended.Commit.Parents[0].SHA)
+		req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/config-1.txt?ref=bot/config").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/bot%2Fconfig").AddTokenAuth(token)
+		var branch api.Branch
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &branch)
+		assert.Equal(t, amended.Commit.SHA, branch.Commit.ID)
+
+		// only an existing branch can be amended
+		newBranch := updateConfig("3", true)
+		newBranch.NewBranchName = "bot/other"
+		changeFiles(t, newBranch, http.StatusUnprocessableEntity)
+
+		// amending force-updates the branch, which a protected branch doesn't allow
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+			RuleName:   "bot/config",
+			EnablePush: true,
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+		changeFiles(t, updateConfig("3", true), http.StatusForbidden)
+		changeFiles(t, updateConfig("3", false), http.StatusCreated)
+	})
+}
+
+func TestAPIChangeFilesSpeculativeCommit(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		getBranchCommitID := func(t *testing.T, branch string) string {
+			req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branches/"+branch).AddTokenAuth(token)
+			var apiBranch api.Branch
+			DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &apiBranch)
+			return apiBranch.Commit.ID
+		}
+		master := getBranchCommitID(t, "master")
+		parent := getBranchCommitID(t, "branch2")
+
+		// the commit is written to the target reference on top of the parent commit
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents", &api.ChangeFilesOptions{
+			FileOptions:  api.FileOptions{BranchName: "master"},
+			Files:        []*api.ChangeFileOperation{{Operation: "create", Path: "speculative.txt", ContentBase64: base64.StdEncoding.EncodeToString([]byte("speculative"))}},
+			ParentCommit: parent,
+			TargetRef:    "refs/gitea/bot/speculative",
+		}).AddTokenAuth(token)
+		var filesResponse api.FilesResponse
+		DecodeJSON(t, MakeRequest(t, req, http.StatusCreated), &filesResponse)
+		require.Len(t, filesResponse.Commit.Parents, 1)
+		assert.Equal(t, parent, filesResponse.Commit.Parents[0].SHA)
+
+		gitRepo, err := gitrepo.OpenRepository(stdCtx.Background(), unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1}))
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		refCommitID, err := gitRepo.GetRefCommitID("refs/gitea/bot/speculative")
+		require.NoError(t, err)
+		assert.Equal(t, filesResponse.Commit.SHA, refCommitID)
+
+		// a file can be updated in a speculative commit too
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/contents/README.md", &api.UpdateFileOptions{
+			DeleteFileOptions: api.DeleteFileOptions{SHA: "4b4851ad51df6a7d9f25c979345979eaeb5b349f"},
+			ContentBase64:     base64.StdEncoding.EncodeToString([]byte("speculative")),
+			TargetRef:         "refs/gitea/bot/readme",
+		}).AddTokenAuth(token)
+		var fileResponse api.FileResponse
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &fileResponse)
+		require.Len(t, fileResponse.Commit.Parents, 1)
+		assert.Equal(t, master, fileResponse.Commit.Parents[0].SHA)
+		refCommitID, err = gitRepo.GetRefCommitID("refs/gitea/bot/readme")
+		require.NoError(t, err)
+		assert.Equal(t, fileResponse.Commit.SHA, refCommitID)
+
+		// no branch has been changed
+		assert.Equal(t, master, getBranchCommitID(t, "master"))
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/other.txt", &api.CreateFileOptions{
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte("other")),
+			TargetRef:     "refs/heads/master",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/contents/other.txt", &api.CreateFileOptions{
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte("other")),
+			ParentCommit:  "0000000000000000000000000000000000000001",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
@@ -231,3 +231,101 @@ func TestGetFileHistoryNotOnMaster(t *testing.T) {
 
 	assert.EqualValues(t, resp.Header().Get("X-Total"), "1")
 }
+
+func TestAPIReposCommitGraph(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadRepository)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits/graph?limit=50").AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var graph api.CommitGraph
+	DecodeJSON(t, resp, &graph)
+	assert.Len(t, graph.Commits, 9)
+	assert.Equal(t, "9", resp.Header().Get("X-Total-Count"))
+	assert.Equal(t, "4649299398e4d39a5c09eb4f534df6f1e1eb87cc", graph.Commits[0].SHA)
+	assert.Equal(t, []string{"refs/heads/sub-home-md-img-check"}, graph.Commits[0].Refs)
+
+	// the parents are listed after their children
+	rows := map[string]int{}
+	for i, c := range graph.Commits {
+		rows[c.SHA] = i
+		assert.Less(t, c.Lane, graph.Lanes)
+	}
+	for i, c := range graph.Commits {
+		for _, parent := range c.Parents {
+			assert.Greater(t, rows[parent.SHA], i)
+			assert.Less(t, parent.Lane, graph.Lanes)
+		}
+	}
+
+	// the lanes are the same when paginating
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits/graph?limit=3&page=2").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var page api.CommitGraph
+	DecodeJSON(t, resp, &page)
+	assert.Equal(t, graph.Commits[3:6], page.Commits)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits/graph?branch=master").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &graph)
+	if assert.Len(t, graph.Commits, 1) {
+		assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", graph.Commits[0].SHA)
+		assert.Equal(t, 0, graph.Commits[0].Lane)
+		assert.Empty(t, graph.Commits[0].Parents)
+		assert.Equal(t, "user1", graph.Commits[0].Author.Name)
+	}
+	assert.Equal(t, 1, graph.Lanes)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/commits/graph?branch=unknown").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
+func TestAPIReposGitCommitReactions(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	// the reactions on a ref are recorded on its commit
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/commits/master/reactions", &api.EditReactionOption{
+		Reaction: "rocket",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/commits/65f1bf27bc3bf70f64657658635e66094edbcb4d/reactions", &api.EditReactionOption{
+		Reaction: "rocket",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/commits/65f1/reactions", &api.EditReactionOption{
+		Reaction: "wrong",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/commits/12345/reactions", &api.EditReactionOption{
+		Reaction: "rocket",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/65f1/reactions").AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var reactions []*api.Reaction
+	DecodeJSON(t, resp, &reactions)
+	if assert.Len(t, reactions, 1) {
+		assert.Equal(t, "rocket", reactions[0].Reaction)
+		assert.Equal(t, user.Name, reactions[0].User.UserName)
+	}
+
+	// the commit API has the summary of the reactions
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/65f1?stat=false&files=false").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var commit api.Commit
+	DecodeJSON(t, resp, &commit)
+	assert.Equal(t, []*api.ReactionSummary{{Reaction: "rocket", Count: 1}}, commit.Reactions)
+
+	req = NewRequestWithJSON(t, "DELETE", "/api/v1/repos/user2/repo1/git/commits/65f1/reactions", &api.EditReactionOption{
+		Reaction: "rocket",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/git/commits/65f1?stat=false&files=false").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &commit)
+	assert.Empty(t, commit.Reactions)
+}
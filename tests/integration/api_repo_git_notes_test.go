@@ -44,3 +44,38 @@ func TestAPIReposGitNotes(t *testing.T) {
 		assert.NotNil(t, apiData.Commit.RepoCommit.Verification)
 	})
 }
+
+func TestAPIReposGitNotesWrite(t *testing.T) {
+	onGiteaRun(t, func(*testing.T, *url.URL) {
+		user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		readToken := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+		urlStr := "/api/v1/repos/" + user.Name + "/repo1/git/notes/"
+
+		// a read-only token is not allowed to write notes
+		req := NewRequestWithJSON(t, "PUT", urlStr+"master", &api.SetNoteOption{Message: "Deployed"}).
+			AddTokenAuth(readToken)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "PUT", urlStr+"master", &api.SetNoteOption{Message: "Deployed"}).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var apiData api.Note
+		DecodeJSON(t, resp, &apiData)
+		assert.Equal(t, "Deployed\n", apiData.Message)
+
+		req = NewRequest(t, "GET", urlStr+"master").AddTokenAuth(readToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &apiData)
+		assert.Equal(t, "Deployed\n", apiData.Message)
+
+		req = NewRequest(t, "DELETE", urlStr+"master").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+		req = NewRequest(t, "DELETE", urlStr+"master").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", urlStr+"master").AddTokenAuth(readToken)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
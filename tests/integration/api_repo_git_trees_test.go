@@ -5,13 +5,18 @@ package integration
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 
 	auth_model "code.gitea.io/gitea/models/auth"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAPIReposGitTrees(t *testing.T) {
@@ -75,3 +80,65 @@ func TestAPIReposGitTrees(t *testing.T) {
 	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/trees/d56a3073c1dbb7b15963110a049d50cdb5db99fc?access=%s", org3.Name, repo3.Name, token4)
 	MakeRequest(t, req, http.StatusNotFound)
 }
+
+func TestAPIReposGitTreesPathPrefixAndCursor(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		for _, treePath := range []string{"docs/a.md", "docs/sub/b.md", "docs/sub/deep/c.md"} {
+			_, err := createFile(user2, repo1, treePath)
+			require.NoError(t, err)
+		}
+
+		entryPaths := func(tree *api.GitTreeResponse) []string {
+			paths := make([]string, 0, len(tree.Entries))
+			for _, entry := range tree.Entries {
+				paths = append(paths, entry.Path)
+			}
+			return paths
+		}
+		getTree := func(t *testing.T, query string) *api.GitTreeResponse {
+			req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/trees/master?%s", user2.Name, repo1.Name, query)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var tree api.GitTreeResponse
+			DecodeJSON(t, resp, &tree)
+			return &tree
+		}
+
+		t.Run("PathPrefix", func(t *testing.T) {
+			tree := getTree(t, "path_prefix=docs")
+			assert.Equal(t, []string{"docs/a.md", "docs/sub"}, entryPaths(tree))
+		})
+
+		t.Run("Depth", func(t *testing.T) {
+			tree := getTree(t, "path_prefix=docs&depth=2")
+			assert.Equal(t, []string{"docs/a.md", "docs/sub", "docs/sub/b.md", "docs/sub/deep"}, entryPaths(tree))
+		})
+
+		t.Run("Cursor", func(t *testing.T) {
+			tree := getTree(t, "path_prefix=docs&recursive=true&per_page=2")
+			assert.Equal(t, []string{"docs/a.md", "docs/sub"}, entryPaths(tree))
+			assert.True(t, tree.Truncated)
+			assert.Equal(t, "docs/sub", tree.NextCursor)
+
+			tree = getTree(t, "path_prefix=docs&recursive=true&per_page=2&cursor="+url.QueryEscape(tree.NextCursor))
+			assert.Equal(t, []string{"docs/sub/b.md", "docs/sub/deep"}, entryPaths(tree))
+			assert.True(t, tree.Truncated)
+			assert.Equal(t, "docs/sub/deep", tree.NextCursor)
+
+			tree = getTree(t, "path_prefix=docs&recursive=true&per_page=2&cursor="+url.QueryEscape(tree.NextCursor))
+			assert.Equal(t, []string{"docs/sub/deep/c.md"}, entryPaths(tree))
+			assert.False(t, tree.Truncated)
+			assert.Empty(t, tree.NextCursor)
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/trees/master?path_prefix=README.md", user2.Name, repo1.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+			req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/trees/master?cursor=unknown", user2.Name, repo1.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+			req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git/trees/master?depth=-1", user2.Name, repo1.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+		})
+	})
+}
@@ -179,3 +179,123 @@ func TestAPILFSLocksLogged(t *testing.T) {
 		assert.Len(t, lfsLocks.Locks, 0)
 	}
 }
+
+func TestAPILFSLocksPagingAndForce(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	setting.LFS.StartServer = true
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2}) // in org 3
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4}) // in org 3
+
+	repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	repo3 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3}) // own by org 3
+
+	session2 := loginUser(t, user2.Name)
+	session4 := loginUser(t, user4.Name)
+
+	createLock := func(t *testing.T, session *TestSession, path string) *api.LFSLock {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/%s.git/info/lfs/locks", repo3.FullName()), map[string]any{"path": path, "ref": map[string]string{"name": "refs/heads/master"}})
+		req.Header.Set("Accept", lfs.AcceptHeader)
+		req.Header.Set("Content-Type", lfs.MediaType)
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var lfsLock api.LFSLockResponse
+		DecodeJSON(t, resp, &lfsLock)
+		return lfsLock.Lock
+	}
+
+	lock1 := createLock(t, session2, "doc/doc.md")
+	lock2 := createLock(t, session4, "README.md")
+	lock3 := createLock(t, session4, "test/foo/bar.bin")
+
+	t.Run("List", func(t *testing.T) {
+		listLocks := func(t *testing.T, query string) *api.LFSLockList {
+			req := NewRequestf(t, "GET", "/%s.git/info/lfs/locks?%s", repo3.FullName(), query)
+			req.Header.Set("Accept", lfs.AcceptHeader)
+			resp := session2.MakeRequest(t, req, http.StatusOK)
+			var lfsLocks api.LFSLockList
+			DecodeJSON(t, resp, &lfsLocks)
+			return &lfsLocks
+		}
+
+		page := listLocks(t, "limit=2")
+		assert.Len(t, page.Locks, 2)
+		assert.Equal(t, lock1.ID, page.Locks[0].ID)
+		assert.Equal(t, lock2.ID, page.Locks[1].ID)
+		assert.Equal(t, "1", page.Next)
+
+		page = listLocks(t, "limit=2&cursor="+page.Next)
+		assert.Len(t, page.Locks, 1)
+		assert.Equal(t, lock3.ID, page.Locks[0].ID)
+		assert.Empty(t, page.Next)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/%s.git/info/lfs/locks/verify", repo3.FullName()), map[string]any{"limit": 2, "ref": map[string]string{"name": "refs/heads/master"}})
+		req.Header.Set("Accept", lfs.AcceptHeader)
+		req.Header.Set("Content-Type", lfs.MediaType)
+		resp := session2.MakeRequest(t, req, http.StatusOK)
+		var lfsLocksVerify api.LFSLockListVerify
+		DecodeJSON(t, resp, &lfsLocksVerify)
+		assert.Len(t, lfsLocksVerify.Ours, 1)
+		assert.Len(t, lfsLocksVerify.Theirs, 1)
+		assert.Equal(t, "1", lfsLocksVerify.Next)
+
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/%s.git/info/lfs/locks/verify", repo3.FullName()), map[string]any{"cursor": lfsLocksVerify.Next, "limit": 2})
+		req.Header.Set("Accept", lfs.AcceptHeader)
+		req.Header.Set("Content-Type", lfs.MediaType)
+		resp = session2.MakeRequest(t, req, http.StatusOK)
+		lfsLocksVerify = api.LFSLockListVerify{}
+		DecodeJSON(t, resp, &lfsLocksVerify)
+		assert.Empty(t, lfsLocksVerify.Ours)
+		assert.Len(t, lfsLocksVerify.Theirs, 1)
+		assert.Equal(t, lock3.ID, lfsLocksVerify.Theirs[0].ID)
+		assert.Empty(t, lfsLocksVerify.Next)
+	})
+
+	t.Run("EditorWarning", func(t *testing.T) {
+		req := NewRequestf(t, "GET", "/%s/_edit/master/README.md", repo3.FullName())
+		resp := session2.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		assert.Contains(t, htmlDoc.doc.Find(".ui.warning.message").Text(), user4.Name)
+
+		req = NewRequestf(t, "GET", "/%s/_edit/master/doc/doc.md", repo3.FullName())
+		resp = session2.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, ".ui.warning.message", false)
+	})
+
+	t.Run("Settings", func(t *testing.T) {
+		req := NewRequestf(t, "GET", "/%s/settings/lfs/locks", repo3.FullName())
+		resp := session2.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		rows := htmlDoc.doc.Find("#lfs-files-locks-table tr")
+		assert.Equal(t, 3, rows.Length())
+		assert.Contains(t, rows.Eq(0).Text(), user2.DisplayName())
+		assert.Contains(t, rows.Eq(1).Text(), user4.DisplayName())
+
+		req = NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/settings/lfs/locks/%s/unlock", repo1.FullName(), lock3.ID), map[string]string{
+			"_csrf": GetCSRF(t, session2, "/"+repo3.FullName()+"/settings/lfs/locks"),
+		})
+		session2.MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/settings/lfs/locks/%s/unlock", repo3.FullName(), lock3.ID), map[string]string{
+			"_csrf": GetCSRF(t, session2, "/"+repo3.FullName()+"/settings/lfs/locks"),
+		})
+		session2.MakeRequest(t, req, http.StatusSeeOther)
+	})
+
+	t.Run("Unlock", func(t *testing.T) {
+		unlock := func(t *testing.T, repo *repo_model.Repository, lockID string, force bool, expectedStatus int) {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/%s.git/info/lfs/locks/%s/unlock", repo.FullName(), lockID), map[string]bool{"force": force})
+			req.Header.Set("Accept", lfs.AcceptHeader)
+			req.Header.Set("Content-Type", lfs.MediaType)
+			session2.MakeRequest(t, req, expectedStatus)
+		}
+
+		unlock(t, repo3, lock2.ID, false, http.StatusForbidden)
+		unlock(t, repo1, lock2.ID, true, http.StatusNotFound)
+		unlock(t, repo3, lock2.ID, true, http.StatusOK)
+		unlock(t, repo3, lock2.ID, true, http.StatusNotFound)
+		unlock(t, repo3, lock1.ID, false, http.StatusOK)
+		unlock(t, repo3, lock3.ID, true, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIRepoMigrateToLFS(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		setting.LFS.StartServer = true
+
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		link := "/api/v1/repos/user2/repo1/lfs/migrate"
+
+		gitRepo, err := git.OpenRepository(git.DefaultContext, repo.RepoPath())
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		oldHead, err := gitRepo.GetBranchCommitID("master")
+		require.NoError(t, err)
+
+		t.Run("Invalid", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", link, &api.MigrateToLFSOption{Patterns: []string{".gitattributes"}}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+			req = NewRequestWithJSON(t, "POST", link, &api.MigrateToLFSOption{Patterns: []string{"*.md"}, Branches: []string{"not-exist"}}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		})
+
+		t.Run("OpenPullRequests", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", link, &api.MigrateToLFSOption{Patterns: []string{"*.md"}}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusConflict)
+		})
+
+		t.Run("Migrate", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", link, &api.MigrateToLFSOption{
+				Patterns:               []string{"*.md"},
+				IgnoreOpenPullRequests: true,
+			}).AddTokenAuth(token)
+			var task api.LFSMigrateTask
+			DecodeJSON(t, MakeRequest(t, req, http.StatusAccepted), &task)
+			assert.Equal(t, []string{"*.md"}, task.Patterns)
+			assert.Equal(t, []string{"master"}, task.Branches)
+			assert.Equal(t, fmt.Sprintf("refs/lfs-migrate-backup/%d/", task.ID), task.BackupRefPrefix)
+
+			assert.Eventually(t, func() bool {
+				DecodeJSON(t, MakeRequest(t, NewRequest(t, "GET", link).AddTokenAuth(token), http.StatusOK), &task)
+				return task.Status != "queued" && task.Status != "running"
+			}, 30*time.Second, 100*time.Millisecond)
+			assert.Equal(t, "finished", task.Status, task.Message)
+			assert.NotNil(t, task.Finished)
+
+			// the previous head is kept
+			backup, _, runErr := git.NewCommand(git.DefaultContext, "rev-parse").AddDynamicArguments(task.BackupRefPrefix + "master").RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+			require.NoError(t, runErr)
+			assert.Equal(t, oldHead, strings.TrimSpace(backup))
+
+			commit, err := gitRepo.GetBranchCommit("master")
+			require.NoError(t, err)
+			assert.NotEqual(t, oldHead, commit.ID.String())
+
+			attributes, err := commit.GetFileContent(".gitattributes", 1024)
+			require.NoError(t, err)
+			assert.Equal(t, "*.md filter=lfs diff=lfs merge=lfs -text\n", attributes)
+
+			readme, err := commit.GetFileContent("README.md", 1024)
+			require.NoError(t, err)
+			pointer, err := lfs.ReadPointerFromBuffer([]byte(readme))
+			require.NoError(t, err)
+			_, err = git_model.GetLFSMetaObjectByOid(db.DefaultContext, repo.ID, pointer.Oid)
+			assert.NoError(t, err)
+			exist, err := lfs.NewContentStore().Exists(pointer)
+			assert.NoError(t, err)
+			assert.True(t, exist)
+		})
+
+		t.Run("Settings", func(t *testing.T) {
+			resp := session.MakeRequest(t, NewRequest(t, "GET", "/user2/repo1/settings/lfs/migrate"), http.StatusOK)
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			assert.Equal(t, 1, htmlDoc.doc.Find("#lfs-migrate-task").Length())
+
+			// nothing is left to migrate
+			head, err := gitRepo.GetBranchCommitID("master")
+			require.NoError(t, err)
+			req := NewRequestWithValues(t, "POST", "/user2/repo1/settings/lfs/migrate", map[string]string{
+				"_csrf":                     GetCSRF(t, session, "/user2/repo1/settings/lfs/migrate"),
+				"patterns":                  "*.md",
+				"ignore_open_pull_requests": "on",
+			})
+			session.MakeRequest(t, req, http.StatusSeeOther)
+
+			var task api.LFSMigrateTask
+			assert.Eventually(t, func() bool {
+				DecodeJSON(t, MakeRequest(t, NewRequest(t, "GET", link).AddTokenAuth(token), http.StatusOK), &task)
+				return task.Status == "finished"
+			}, 30*time.Second, 100*time.Millisecond)
+			newHead, err := gitRepo.GetBranchCommitID("master")
+			require.NoError(t, err)
+			assert.Equal(t, head, newHead)
+		})
+	})
+}
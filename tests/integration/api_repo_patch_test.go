@@ -0,0 +1,180 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gitBlobSHA(content string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("blob %d\x00%s", len(content), content))))
+}
+
+func TestAPIApplyDiffPatch(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		repoURL := fmt.Sprintf("/api/v1/repos/%s/%s", user2.Name, repo1.Name)
+
+		applyPatch := func(t *testing.T, opts *api.ApplyDiffPatchFileOptions, expectedStatus int) *httptest.ResponseRecorder {
+			if opts.BranchName == "" {
+				opts.BranchName = "master"
+			}
+			opts.SHA = "unused"
+			return MakeRequest(t, NewRequestWithJSON(t, "POST", repoURL+"/diffpatch", opts).AddTokenAuth(token), expectedStatus)
+		}
+		readFile := func(t *testing.T) string {
+			resp := MakeRequest(t, NewRequest(t, "GET", repoURL+"/raw/patch/base.txt?ref=master").AddTokenAuth(token), http.StatusOK)
+			return resp.Body.String()
+		}
+
+		original := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+		MakeRequest(t, NewRequestWithJSON(t, "POST", repoURL+"/contents/patch/base.txt", &api.CreateFileOptions{
+			FileOptions:   api.FileOptions{BranchName: "master", Message: "Add the base file"},
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte(original)),
+		}).AddTokenAuth(token), http.StatusCreated)
+
+		// a patch against the original content of the file, with the blob IDs needed for a three-way merge
+		diffAgainstOriginal := func(modified, hunk string) string {
+			return fmt.Sprintf("diff --git a/patch/base.txt b/patch/base.txt\nindex %s..%s 100644\n--- a/patch/base.txt\n+++ b/patch/base.txt\n%s",
+				gitBlobSHA(original), gitBlobSHA(modified), hunk)
+		}
+
+		t.Run("UnifiedDiff", func(t *testing.T) {
+			resp := applyPatch(t, &api.ApplyDiffPatchFileOptions{
+				Content: "--- a/patch/base.txt\n+++ b/patch/base.txt\n@@ -1,5 +1,5 @@\n one\n-two\n+TWO\n three\n four\n five\n",
+			}, http.StatusCreated)
+			var fileResponse api.FileResponse
+			DecodeJSON(t, resp, &fileResponse)
+			assert.Nil(t, fileResponse.Content)
+			assert.NotEmpty(t, fileResponse.Commit.SHA)
+			assert.Equal(t, "apply-patch", strings.TrimSpace(fileResponse.Commit.Message))
+			assert.Equal(t, user2.GetEmail(), fileResponse.Commit.Author.Email)
+			assert.Equal(t, "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n", readFile(t))
+		})
+
+		t.Run("FormatPatch", func(t *testing.T) {
+			resp := applyPatch(t, &api.ApplyDiffPatchFileOptions{
+				Content: `From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001
+From: Jane Patch <jane@example.com>
+Date: Tue, 2 Jan 2024 15:04:05 +0100
+Subject: [PATCH] Shout nine
+
+Because it is loud.
+---
+ patch/base.txt | 2 +-
+ 1 file changed, 1 insertion(+), 1 deletion(-)
+
+diff --git a/patch/base.txt b/patch/base.txt
+--- a/patch/base.txt
++++ b/patch/base.txt
+@@ -6,5 +6,5 @@
+ six
+ seven
+ eight
+-nine
++NINE
+ ten
+` + "-- \n2.39.5\n",
+			}, http.StatusCreated)
+			var fileResponse api.FileResponse
+			DecodeJSON(t, resp, &fileResponse)
+			assert.Equal(t, "Shout nine\n\nBecause it is loud.", strings.TrimSpace(fileResponse.Commit.Message))
+			assert.Equal(t, "Jane Patch", fileResponse.Commit.Author.Name)
+			assert.Equal(t, "jane@example.com", fileResponse.Commit.Author.Email)
+			assert.Equal(t, "2024-01-02T14:04:05Z", fileResponse.Commit.Author.Date)
+			assert.Equal(t, user2.GetEmail(), fileResponse.Commit.Committer.Email)
+			assert.Equal(t, "one\nTWO\nthree\nfour\nfive\nsix\nseven\neight\nNINE\nten\n", readFile(t))
+		})
+
+		t.Run("MultipleCommits", func(t *testing.T) {
+			applyPatch(t, &api.ApplyDiffPatchFileOptions{
+				Content: "From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001\nSubject: [PATCH 1/2] a\n\n" +
+					"From 2222222222222222222222222222222222222222 Mon Sep 17 00:00:00 2001\nSubject: [PATCH 2/2] b\n",
+			}, http.StatusUnprocessableEntity)
+		})
+
+		shoutFive := diffAgainstOriginal(strings.Replace(original, "five", "FIVE", 1),
+			"@@ -2,7 +2,7 @@\n two\n three\n four\n-five\n+FIVE\n six\n seven\n eight\n")
+
+		t.Run("ConflictWithoutThreeWay", func(t *testing.T) {
+			resp := applyPatch(t, &api.ApplyDiffPatchFileOptions{
+				Content:  shoutFive,
+				ThreeWay: util.ToPointer(false),
+			}, http.StatusConflict)
+			var conflicts api.ApplyPatchConflicts
+			DecodeJSON(t, resp, &conflicts)
+			if assert.Len(t, conflicts.Conflicts, 1) {
+				assert.Equal(t, "patch/base.txt", conflicts.Conflicts[0].Path)
+				assert.Equal(t, "@@ -2,7 +2,7 @@", conflicts.Conflicts[0].Hunk)
+				assert.Equal(t, 2, conflicts.Conflicts[0].OldStart)
+				assert.Equal(t, 7, conflicts.Conflicts[0].OldLines)
+				assert.NotEmpty(t, conflicts.Conflicts[0].Message)
+			}
+			assert.Empty(t, conflicts.ConflictedFiles)
+		})
+
+		t.Run("ThreeWay", func(t *testing.T) {
+			applyPatch(t, &api.ApplyDiffPatchFileOptions{Content: shoutFive}, http.StatusCreated)
+			assert.Equal(t, "one\nTWO\nthree\nfour\nFIVE\nsix\nseven\neight\nNINE\nten\n", readFile(t))
+		})
+
+		t.Run("ThreeWayConflict", func(t *testing.T) {
+			resp := applyPatch(t, &api.ApplyDiffPatchFileOptions{
+				Content: diffAgainstOriginal(strings.Replace(original, "two", "Two", 1),
+					"@@ -1,5 +1,5 @@\n one\n-two\n+Two\n three\n four\n five\n"),
+			}, http.StatusConflict)
+			var conflicts api.ApplyPatchConflicts
+			DecodeJSON(t, resp, &conflicts)
+			assert.Equal(t, []string{"patch/base.txt"}, conflicts.ConflictedFiles)
+			if assert.Len(t, conflicts.Conflicts, 1) {
+				assert.Equal(t, "@@ -1,5 +1,5 @@", conflicts.Conflicts[0].Hunk)
+			}
+			assert.Equal(t, "one\nTWO\nthree\nfour\nFIVE\nsix\nseven\neight\nNINE\nten\n", readFile(t))
+		})
+
+		t.Run("WebEditor", func(t *testing.T) {
+			// the patches of the web editor are applied the same way, but they are committed by the doer
+			session := loginUser(t, user2.Name)
+			req := NewRequest(t, "GET", "/user2/repo1/_diffpatch/master")
+			htmlDoc := NewHTMLParser(t, session.MakeRequest(t, req, http.StatusOK).Body)
+			req = NewRequestWithValues(t, "POST", "/user2/repo1/_diffpatch/master", map[string]string{
+				"_csrf":          htmlDoc.GetCSRF(),
+				"last_commit":    htmlDoc.GetInputValueByName("last_commit"),
+				"tree_path":      "patch",
+				"commit_choice":  "direct",
+				"commit_summary": "Shout ten",
+				"content": "From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001\nFrom: Jane Patch <jane@example.com>\nSubject: [PATCH] Shout ten\n\n---\n" +
+					"diff --git a/patch/base.txt b/patch/base.txt\n--- a/patch/base.txt\n+++ b/patch/base.txt\n@@ -8,3 +8,3 @@\n eight\n NINE\n-ten\n+TEN\n",
+			})
+			session.MakeRequest(t, req, http.StatusSeeOther)
+			assert.Equal(t, "one\nTWO\nthree\nfour\nFIVE\nsix\nseven\neight\nNINE\nTEN\n", readFile(t))
+
+			resp := MakeRequest(t, NewRequest(t, "GET", repoURL+"/commits?sha=master&limit=1&stat=false").AddTokenAuth(token), http.StatusOK)
+			var commits []*api.Commit
+			DecodeJSON(t, resp, &commits)
+			if assert.Len(t, commits, 1) {
+				assert.Equal(t, "Shout ten", strings.TrimSpace(commits[0].RepoCommit.Message))
+				assert.Equal(t, user2.GetEmail(), commits[0].RepoCommit.Author.Email)
+			}
+		})
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoSize(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/size"), http.StatusUnauthorized)
+	token4 := getUserToken(t, "user4", auth_model.AccessTokenScopeReadRepository)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/size").AddTokenAuth(token4), http.StatusForbidden)
+
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadRepository)
+	assert.NoError(t, repo_service.UpdateRepositorySizes(db.DefaultContext))
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 54})
+	assert.Positive(t, repo.GitSize)
+
+	resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/lfs/size").AddTokenAuth(token), http.StatusOK)
+	var size api.RepoSize
+	DecodeJSON(t, resp, &size)
+	assert.Equal(t, repo.GitSize, size.Git)
+	assert.Equal(t, repo.LFSSize, size.LFS)
+	assert.Equal(t, size.Git+size.LFS+size.Attachments+size.ActionsArtifacts+size.ActionsLogs+size.Packages, size.Total)
+	assert.False(t, size.Updated.IsZero())
+}
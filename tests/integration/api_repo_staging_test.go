@@ -0,0 +1,84 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIRepoStaging(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		token4 := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+		urlStr := "/api/v1/repos/user2/repo1/staging"
+
+		createSession := func(t *testing.T) *api.StagingSession {
+			resp := MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.CreateStagingSessionOption{}).AddTokenAuth(token), http.StatusCreated)
+			var session api.StagingSession
+			DecodeJSON(t, resp, &session)
+			return &session
+		}
+		uploadChunk := func(t *testing.T, session *api.StagingSession, path string, offset int, chunk string, expectedStatus int) {
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/%s/files/%s?offset=%d", urlStr, session.ID, path, offset), strings.NewReader(chunk)).AddTokenAuth(token)
+			MakeRequest(t, req, expectedStatus)
+		}
+
+		MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.CreateStagingSessionOption{BranchName: "does-not-exist"}).AddTokenAuth(token), http.StatusNotFound)
+		MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, &api.CreateStagingSessionOption{}).AddTokenAuth(token4), http.StatusForbidden)
+
+		t.Run("Commit", func(t *testing.T) {
+			session := createSession(t)
+			assert.Equal(t, "master", session.Branch)
+			assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", session.BaseCommit)
+			assert.Empty(t, session.Files)
+
+			uploadChunk(t, session, "staged/a.txt", 0, "hello ", http.StatusOK)
+			uploadChunk(t, session, "staged/a.txt", 0, "hello ", http.StatusConflict)
+			uploadChunk(t, session, "staged/a.txt", 6, "world", http.StatusOK)
+			uploadChunk(t, session, "staged/a.txt/b.txt", 0, "conflict", http.StatusUnprocessableEntity)
+			uploadChunk(t, session, "README.md", 0, "staged readme", http.StatusOK)
+
+			// the session belongs to user2
+			MakeRequest(t, NewRequest(t, "GET", urlStr+"/"+session.ID).AddTokenAuth(token4), http.StatusNotFound)
+
+			resp := MakeRequest(t, NewRequest(t, "GET", urlStr+"/"+session.ID).AddTokenAuth(token), http.StatusOK)
+			DecodeJSON(t, resp, session)
+			assert.Equal(t, []*api.StagedFile{{Path: "README.md", Size: 13}, {Path: "staged/a.txt", Size: 11}}, session.Files)
+
+			resp = MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr+"/"+session.ID+"/commit", &api.CommitStagingSessionOption{Message: "Upload staged files"}).AddTokenAuth(token), http.StatusCreated)
+			var filesResponse api.FilesResponse
+			DecodeJSON(t, resp, &filesResponse)
+			require.Len(t, filesResponse.Files, 2)
+			assert.Equal(t, "Upload staged files\n", filesResponse.Commit.Message)
+			require.Len(t, filesResponse.Commit.Parents, 1)
+			assert.Equal(t, session.BaseCommit, filesResponse.Commit.Parents[0].SHA)
+
+			resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/staged/a.txt").AddTokenAuth(token), http.StatusOK)
+			assert.Equal(t, "hello world", resp.Body.String())
+			resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/raw/README.md").AddTokenAuth(token), http.StatusOK)
+			assert.Equal(t, "staged readme", resp.Body.String())
+
+			// the session ends once committed
+			MakeRequest(t, NewRequest(t, "GET", urlStr+"/"+session.ID).AddTokenAuth(token), http.StatusNotFound)
+		})
+
+		t.Run("Discard", func(t *testing.T) {
+			session := createSession(t)
+			MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr+"/"+session.ID+"/commit", &api.CommitStagingSessionOption{}).AddTokenAuth(token), http.StatusUnprocessableEntity)
+			uploadChunk(t, session, "discarded.txt", 0, "content", http.StatusOK)
+			MakeRequest(t, NewRequest(t, "DELETE", urlStr+"/"+session.ID).AddTokenAuth(token), http.StatusNoContent)
+			MakeRequest(t, NewRequest(t, "GET", urlStr+"/"+session.ID).AddTokenAuth(token), http.StatusNotFound)
+		})
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoCommitStats(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadRepository)
+
+	// the statistics are computed in the background the first time they are requested
+	var resp *httptest.ResponseRecorder
+	for i := 0; i < 50; i++ {
+		resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/code_frequency").AddTokenAuth(token), NoExpectedStatus)
+		if resp.Code != http.StatusAccepted {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var weeks []*api.CodeFrequencyWeek
+	DecodeJSON(t, resp, &weeks)
+	assert.Equal(t, []*api.CodeFrequencyWeek{{Week: time.Date(2017, 3, 19, 0, 0, 0, 0, time.UTC), Additions: 3}}, weeks)
+
+	resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/punch_card").AddTokenAuth(token), http.StatusOK)
+	var punchCard []*api.PunchCardEntry
+	DecodeJSON(t, resp, &punchCard)
+	assert.Equal(t, []*api.PunchCardEntry{{Weekday: 0, Hour: 16, Commits: 1}}, punchCard)
+
+	resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/contributors").AddTokenAuth(token), http.StatusOK)
+	var contributors []*api.ContributorStats
+	DecodeJSON(t, resp, &contributors)
+	if assert.Len(t, contributors, 1) {
+		assert.Equal(t, "address1@example.com", contributors[0].Email)
+		assert.EqualValues(t, 1, contributors[0].Commits)
+		assert.EqualValues(t, 3, contributors[0].Additions)
+	}
+
+	resp = MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/contributors?since=2017-03-20T00:00:00Z").AddTokenAuth(token), http.StatusOK)
+	DecodeJSON(t, resp, &contributors)
+	assert.Empty(t, contributors)
+
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/stats/contributors?since=yesterday").AddTokenAuth(token), http.StatusUnprocessableEntity)
+}
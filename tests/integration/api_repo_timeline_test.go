@@ -0,0 +1,85 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoTimeline(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	owner := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: repo.OwnerID})
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue)
+	timelineURL := fmt.Sprintf("/api/v1/repos/%s/%s/timeline", owner.Name, repo.Name)
+
+	req := NewRequest(t, "GET", timelineURL).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var timeline api.RepoTimeline
+	DecodeJSON(t, resp, &timeline)
+	assert.False(t, timeline.HasMore)
+	cursor := timeline.Cursor
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues", owner.Name, repo.Name), &api.CreateIssueOption{
+		Title: "timeline issue",
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var issue api.Issue
+	DecodeJSON(t, resp, &issue)
+
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/comments", owner.Name, repo.Name, issue.Index), &api.CreateIssueCommentOption{
+		Body: "timeline comment",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+
+	// only the events following the cursor are returned
+	req = NewRequest(t, "GET", fmt.Sprintf("%s?since=%d&limit=1", timelineURL, cursor)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	timeline = api.RepoTimeline{}
+	DecodeJSON(t, resp, &timeline)
+	assert.True(t, timeline.HasMore)
+	if assert.Len(t, timeline.Events, 1) {
+		event := timeline.Events[0]
+		assert.Equal(t, "issue", event.Type)
+		assert.Equal(t, "create_issue", event.Action)
+		assert.Equal(t, issue.Index, event.Number)
+		assert.Equal(t, owner.ID, event.Actor.ID)
+		assert.Equal(t, event.ID, timeline.Cursor)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s?since=%d", timelineURL, timeline.Cursor)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	timeline = api.RepoTimeline{}
+	DecodeJSON(t, resp, &timeline)
+	assert.False(t, timeline.HasMore)
+	if assert.Len(t, timeline.Events, 1) {
+		assert.Equal(t, "comment_issue", timeline.Events[0].Action)
+		if assert.NotNil(t, timeline.Events[0].Comment) {
+			assert.Equal(t, "timeline comment", timeline.Events[0].Comment.Body)
+		}
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s?since=%d&types=commit", timelineURL, cursor)).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	timeline = api.RepoTimeline{}
+	DecodeJSON(t, resp, &timeline)
+	assert.Empty(t, timeline.Events)
+	assert.Equal(t, cursor, timeline.Cursor)
+
+	req = NewRequest(t, "GET", timelineURL+"?types=release").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
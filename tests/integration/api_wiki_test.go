@@ -247,3 +247,117 @@ func TestAPIListPageRevisions(t *testing.T) {
 
 	assert.Equal(t, dummyrevisions, revisions)
 }
+
+func TestAPIMoveWikiPage(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	username := "user2"
+	session := loginUser(t, username)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/wiki/new", username, "repo1"), &api.CreateWikiPageOptions{
+		Title:         "Links",
+		ContentBase64: base64.StdEncoding.EncodeToString([]byte("[home](Home) and [[Home]]")),
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusCreated)
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/wiki/move/Home", username, "repo1")
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.MoveWikiPageOptions{
+		Title:       "Start page",
+		UpdateLinks: true,
+	}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+
+	var page *api.WikiPage
+	DecodeJSON(t, resp, &page)
+	assert.Equal(t, "Start page", page.Title)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/wiki/page/Links", username, "repo1"))
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &page)
+	content, _ := base64.StdEncoding.DecodeString(page.ContentBase64)
+	assert.Equal(t, "[home](Start-page) and [[Start page]]", string(content))
+
+	MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/wiki/page/Home", username, "repo1")), http.StatusNotFound)
+
+	// the old page is gone and the new one can't be overwritten
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.MoveWikiPageOptions{Title: "Other"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/wiki/move/Links", username, "repo1"), &api.MoveWikiPageOptions{
+		Title: "Start page",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusBadRequest)
+}
+
+func TestAPIEditWikiSidebarAndFooter(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	username := "user2"
+	session := loginUser(t, username)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	for _, section := range []string{"sidebar", "footer"} {
+		urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/wiki/%s", username, "repo1", section)
+
+		req := NewRequestWithJSON(t, "PUT", urlStr, &api.EditWikiSectionOptions{
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte("Wiki " + section)),
+		})
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithJSON(t, "PUT", urlStr, &api.EditWikiSectionOptions{
+			ContentBase64: base64.StdEncoding.EncodeToString([]byte("Wiki " + section)),
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+	}
+
+	req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/wiki/page/Home", username, "repo1"))
+	resp := MakeRequest(t, req, http.StatusOK)
+	var page *api.WikiPage
+	DecodeJSON(t, resp, &page)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("Wiki sidebar")), page.Sidebar)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("Wiki footer")), page.Footer)
+
+	for _, section := range []string{"sidebar", "footer"} {
+		urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/wiki/%s", username, "repo1", section)
+		MakeRequest(t, NewRequest(t, "DELETE", urlStr).AddTokenAuth(token), http.StatusNoContent)
+		MakeRequest(t, NewRequest(t, "DELETE", urlStr).AddTokenAuth(token), http.StatusNotFound)
+	}
+
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &page)
+	assert.Empty(t, page.Sidebar)
+	assert.Empty(t, page.Footer)
+}
+
+func TestAPIRestoreWikiPage(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	username := "user2"
+	session := loginUser(t, username)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	pageURL := fmt.Sprintf("/api/v1/repos/%s/%s/wiki/page/Home", username, "repo1")
+	resp := MakeRequest(t, NewRequest(t, "GET", pageURL), http.StatusOK)
+	var original *api.WikiPage
+	DecodeJSON(t, resp, &original)
+
+	req := NewRequestWithJSON(t, "PATCH", pageURL, &api.CreateWikiPageOptions{
+		ContentBase64: base64.StdEncoding.EncodeToString([]byte("Edited home")),
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusOK)
+
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/wiki/restore/Home", username, "repo1")
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.RestoreWikiPageOptions{
+		Revision: "2c54faec6c45d31c1abfaecdab471eac6633738a",
+	}).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+
+	var page *api.WikiPage
+	DecodeJSON(t, resp, &page)
+	assert.Equal(t, original.ContentBase64, page.ContentBase64)
+	assert.EqualValues(t, 3, page.CommitCount)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.RestoreWikiPageOptions{
+		Revision: "0000000000000000000000000000000000000000",
+	}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNotFound)
+}
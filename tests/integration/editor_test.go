@@ -10,8 +10,10 @@ import (
 	"net/url"
 	"path"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
 	gitea_context "code.gitea.io/gitea/services/context"
 
 	"github.com/stretchr/testify/assert"
@@ -24,6 +26,30 @@ func TestCreateFile(t *testing.T) {
 	})
 }
 
+func TestCreateFileWithTemplateVars(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		req := NewRequest(t, "GET", "/user2/repo1/_new/master/")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		assert.Equal(t, 1, doc.Find(`input[name="apply_template_vars"]`).Length())
+
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/_new/master/", map[string]string{
+			"_csrf":               doc.GetCSRF(),
+			"last_commit":         doc.GetInputValueByName("last_commit"),
+			"tree_path":           "${REPO_NAME_UPPER}.txt",
+			"content":             "Copyright ${YEAR} ${OWNER}, $REPO_NAME $HOME",
+			"commit_choice":       "direct",
+			"apply_template_vars": "on",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+
+		req = NewRequest(t, "GET", "/user2/repo1/raw/branch/master/REPO1.txt")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, fmt.Sprintf("Copyright %d user2, repo1 $HOME", time.Now().Year()), resp.Body.String())
+	})
+}
+
 func testCreateFile(t *testing.T, session *TestSession, user, repo, branch, filePath, content string) *httptest.ResponseRecorder {
 	// Request editor page
 	newURL := fmt.Sprintf("/%s/%s/_new/%s/", user, repo, branch)
@@ -176,3 +202,61 @@ func TestEditFileToNewBranch(t *testing.T) {
 		testEditFileToNewBranch(t, session, "user2", "repo1", "master", "feature/test", "README.md", "Hello, World (Edited)\n")
 	})
 }
+
+func TestEditSymlink(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		getLink := func(t *testing.T) *api.ContentsResponse {
+			resp := MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/contents/docs/readme-link"), http.StatusOK)
+			var contents api.ContentsResponse
+			DecodeJSON(t, resp, &contents)
+			return &contents
+		}
+
+		// Create the link, the final newline added by the editor is dropped
+		newURL := "/user2/repo1/_new/master/"
+		resp := session.MakeRequest(t, NewRequest(t, "GET", newURL), http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		session.MakeRequest(t, NewRequestWithValues(t, "POST", newURL, map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"tree_path":     "docs/readme-link",
+			"content":       "../README.md\n",
+			"is_symlink":    "on",
+			"commit_choice": "direct",
+		}), http.StatusSeeOther)
+		link := getLink(t)
+		assert.Equal(t, "symlink", link.Type)
+		assert.Equal(t, "../README.md", *link.Target)
+
+		// Update its target in place
+		editURL := "/user2/repo1/_edit/master/docs/readme-link"
+		resp = session.MakeRequest(t, NewRequest(t, "GET", editURL), http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		_, checked := doc.Find(`input[name="is_symlink"]`).Attr("checked")
+		assert.True(t, checked)
+		session.MakeRequest(t, NewRequestWithValues(t, "POST", editURL, map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"tree_path":     "docs/readme-link",
+			"content":       "../LICENSE",
+			"is_symlink":    "on",
+			"commit_choice": "direct",
+		}), http.StatusSeeOther)
+		link = getLink(t)
+		assert.Equal(t, "symlink", link.Type)
+		assert.Equal(t, "../LICENSE", *link.Target)
+
+		// Turn it into a regular file
+		resp = session.MakeRequest(t, NewRequest(t, "GET", editURL), http.StatusOK)
+		doc = NewHTMLParser(t, resp.Body)
+		session.MakeRequest(t, NewRequestWithValues(t, "POST", editURL, map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"tree_path":     "docs/readme-link",
+			"content":       "not a link",
+			"commit_choice": "direct",
+		}), http.StatusSeeOther)
+		assert.Equal(t, "file", getLink(t).Type)
+	})
+}
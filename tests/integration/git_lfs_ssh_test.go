@@ -0,0 +1,186 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lfsTransferRequest builds the pkt-lines of git-lfs-transfer commands
+type lfsTransferRequest struct {
+	bytes.Buffer
+}
+
+func (r *lfsTransferRequest) line(s string) *lfsTransferRequest {
+	fmt.Fprintf(r, "%04x%s\n", len(s)+5, s)
+	return r
+}
+
+func (r *lfsTransferRequest) data(b []byte) *lfsTransferRequest {
+	fmt.Fprintf(r, "%04x", len(b)+4)
+	r.Write(b)
+	return r
+}
+
+func (r *lfsTransferRequest) delim() *lfsTransferRequest {
+	r.WriteString("0001")
+	return r
+}
+
+func (r *lfsTransferRequest) flush() *lfsTransferRequest {
+	r.WriteString("0000")
+	return r
+}
+
+// runLFSTransfer runs git-lfs-transfer over SSH and returns the pkt-lines of the output, "0000" and "0001" are the flush and delimiter packets
+func runLFSTransfer(t *testing.T, keyFile, repoPath, op string, req *lfsTransferRequest) []string {
+	cmd := exec.Command("ssh",
+		"-o", "UserKnownHostsFile=/dev/null", "-o", "StrictHostKeyChecking=no", "-o", "IdentitiesOnly=yes",
+		"-i", keyFile, "-p", strconv.Itoa(setting.SSH.ListenPort), "git@"+setting.SSH.ListenHost,
+		"git-lfs-transfer", repoPath, op)
+	cmd.Stdin = &req.Buffer
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	require.NoError(t, err, stderr.String())
+
+	var lines []string
+	for len(out) >= 4 {
+		length, err := strconv.ParseUint(string(out[:4]), 16, 16)
+		require.NoError(t, err)
+		if length < 4 {
+			lines = append(lines, string(out[:4]))
+			out = out[4:]
+			continue
+		}
+		lines = append(lines, strings.TrimSuffix(string(out[4:length]), "\n"))
+		out = out[length:]
+	}
+	assert.Empty(t, out)
+	return lines
+}
+
+// withoutLockTimes removes the times of the locks from the lines, the time of a created lock isn't rounded like the stored one
+func withoutLockTimes(lines []string) []string {
+	for i, line := range lines {
+		if strings.HasPrefix(line, "locked-at") {
+			lines[i] = "locked-at"
+		}
+	}
+	return lines
+}
+
+func TestGitLFSSSHTransfer(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user2", Name: "repo1"})
+		ctx := NewAPITestContext(t, "user2", "repo1", auth_model.AccessTokenScopeWriteUser)
+
+		content := []byte("stored in LFS over SSH")
+		p, err := lfs.GeneratePointer(bytes.NewReader(content))
+		require.NoError(t, err)
+		object := fmt.Sprintf("%s %d", p.Oid, p.Size)
+
+		withKeyFile(t, "lfs-transfer-key", func(keyFile string) {
+			t.Run("CreateUserKey", doAPICreateUserKey(ctx, "lfs-transfer-key", keyFile))
+
+			t.Run("Upload", func(t *testing.T) {
+				req := &lfsTransferRequest{}
+				req.line("version 1").flush()
+				req.line("batch").line("transfer=basic").line("refname=refs/heads/master").delim().line(object).flush()
+				req.line("put-object " + p.Oid).line(fmt.Sprintf("size=%d", p.Size)).delim().data(content).flush()
+				req.line("verify-object " + p.Oid).line(fmt.Sprintf("size=%d", p.Size)).flush()
+				req.line("quit").flush()
+
+				assert.Equal(t, []string{
+					"version=1", "locking", "0000",
+					"status 200", "0000",
+					"status 200", "0001", object + " upload", "0000",
+					"status 200", "0000",
+					"status 200", "0000",
+					"status 200", "0000",
+				}, runLFSTransfer(t, keyFile, "user2/repo1.git", "upload", req))
+
+				meta, err := git_model.GetLFSMetaObjectByOid(db.DefaultContext, repo.ID, p.Oid)
+				require.NoError(t, err)
+				assert.Equal(t, p.Size, meta.Size)
+			})
+
+			t.Run("Download", func(t *testing.T) {
+				missing := strings.Repeat("0", 64) + " 5"
+				req := &lfsTransferRequest{}
+				req.line("version 1").flush()
+				req.line("batch").delim().line(object).line(missing).flush()
+				req.line("get-object " + p.Oid).flush()
+				req.line("lock").line("path=file.bin").flush()
+				req.line("quit").flush()
+
+				assert.Equal(t, []string{
+					"version=1", "locking", "0000",
+					"status 200", "0000",
+					"status 200", "0001", object + " download", missing + " noop", "0000",
+					"status 200", fmt.Sprintf("size=%d", p.Size), "0001", string(content), "0000",
+					"status 403", "0001", "the command is only allowed for uploads", "0000",
+					"status 200", "0000",
+				}, runLFSTransfer(t, keyFile, "user2/repo1.git", "download", req))
+			})
+
+			t.Run("Locks", func(t *testing.T) {
+				req := &lfsTransferRequest{}
+				req.line("lock").line("path=file.bin").line("refname=refs/heads/master").flush()
+				req.line("lock").line("path=file.bin").flush()
+				req.line("list-lock").line("path=file.bin").flush()
+				req.line("quit").flush()
+				lines := withoutLockTimes(runLFSTransfer(t, keyFile, "user2/repo1.git", "upload", req))
+
+				lock, err := git_model.GetLFSLock(db.DefaultContext, repo, "file.bin")
+				require.NoError(t, err)
+				id := strconv.FormatInt(lock.ID, 10)
+				assert.Equal(t, []string{
+					"version=1", "locking", "0000",
+					"status 201", "id=" + id, "path=file.bin", "locked-at", "ownername=user2", "0000",
+					"status 409", "id=" + id, "path=file.bin", "locked-at", "ownername=user2", "0001", "already created lock", "0000",
+					"status 200", "0001", "lock " + id, "path " + id + " file.bin", "locked-at", "ownername " + id + " user2", "owner " + id + " ours", "0000",
+					"status 200", "0000",
+				}, lines)
+
+				req = &lfsTransferRequest{}
+				req.line("unlock " + id).flush()
+				req.line("quit").flush()
+				assert.Equal(t, []string{
+					"version=1", "locking", "0000",
+					"status 200", "id=" + id, "path=file.bin", "locked-at", "ownername=user2", "0000",
+					"status 200", "0000",
+				}, withoutLockTimes(runLFSTransfer(t, keyFile, "user2/repo1.git", "upload", req)))
+				unittest.AssertNotExistsBean(t, &git_model.LFSLock{ID: lock.ID})
+			})
+
+			t.Run("NoPermission", func(t *testing.T) {
+				// user2 can read but not write the repository of user5
+				cmd := exec.Command("ssh",
+					"-o", "UserKnownHostsFile=/dev/null", "-o", "StrictHostKeyChecking=no", "-o", "IdentitiesOnly=yes",
+					"-i", keyFile, "-p", strconv.Itoa(setting.SSH.ListenPort), "git@"+setting.SSH.ListenHost,
+					"git-lfs-transfer", "user5/repo4.git", "upload")
+				out, err := cmd.CombinedOutput()
+				assert.Error(t, err, string(out))
+			})
+		})
+	})
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgManagedGitHook(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteOrganization, auth_model.AccessTokenScopeWriteRepository)
+		ownerToken := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteOrganization, auth_model.AccessTokenScopeWriteRepository)
+
+		req := NewRequest(t, "GET", "/api/v1/orgs/org3/managed_git_hook").AddTokenAuth(ownerToken)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// only instance administrators can manage the hooks
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/managed_git_hook", &api.EditManagedGitHookOption{MaxFileSize: 1024}).AddTokenAuth(ownerToken)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/managed_git_hook", &api.EditManagedGitHookOption{CommitMessagePattern: "("}).AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/managed_git_hook", &api.EditManagedGitHookOption{
+			MaxFileSize:          1024,
+			ForbiddenPaths:       []string{"*.exe"},
+			CommitMessagePattern: `^[A-Z]+-[0-9]+ `,
+		}).AddTokenAuth(adminToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var hook api.ManagedGitHook
+		DecodeJSON(t, resp, &hook)
+		assert.EqualValues(t, 1024, hook.MaxFileSize)
+		assert.Equal(t, []string{"*.exe"}, hook.ForbiddenPaths)
+
+		req = NewRequest(t, "GET", "/api/v1/orgs/org3/managed_git_hook").AddTokenAuth(ownerToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &hook)
+		assert.False(t, hook.AllowRepoOptOut)
+
+		dstPath := t.TempDir()
+		cloneURL, _ := url.Parse(u.String() + "org3/repo3.git")
+		cloneURL.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, cloneURL))
+
+		t.Run("BadCommitMessage", func(t *testing.T) {
+			doGitCreateBranch(dstPath, "bad-message")(t)
+			commitManagedGitHookFile(t, dstPath, "notes.txt", "notes", "add notes")
+			doGitPushTestRepositoryFail(dstPath, "origin", "bad-message")(t)
+		})
+
+		t.Run("ForbiddenPath", func(t *testing.T) {
+			doGitCheckoutBranch(dstPath, "-b", "forbidden-path", "master")(t)
+			commitManagedGitHookFile(t, dstPath, "setup.exe", "binary", "GITEA-1 add installer")
+			doGitPushTestRepositoryFail(dstPath, "origin", "forbidden-path")(t)
+		})
+
+		t.Run("TooLargeFile", func(t *testing.T) {
+			doGitCheckoutBranch(dstPath, "-b", "large-file", "master")(t)
+			commitManagedGitHookFile(t, dstPath, "large.txt", strings.Repeat("a", 2048), "GITEA-2 add large file")
+			doGitPushTestRepositoryFail(dstPath, "origin", "large-file")(t)
+		})
+
+		t.Run("ValidPush", func(t *testing.T) {
+			doGitCheckoutBranch(dstPath, "-b", "valid", "master")(t)
+			commitManagedGitHookFile(t, dstPath, "small.txt", "small", "GITEA-3 add small file")
+			doGitPushTestRepository(dstPath, "origin", "valid")(t)
+		})
+
+		t.Run("PreReceiveScript", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/managed_git_hook", &api.EditManagedGitHookOption{
+				PreReceiveScript: "#!/bin/sh\necho rejected by the organization policy >&2\nexit 1\n",
+			}).AddTokenAuth(adminToken)
+			MakeRequest(t, req, http.StatusOK)
+
+			doGitCheckoutBranch(dstPath, "-b", "script", "master")(t)
+			commitManagedGitHookFile(t, dstPath, "script.txt", "script", "add script file")
+			doGitPushTestRepositoryFail(dstPath, "origin", "script")(t)
+		})
+
+		t.Run("OptOut", func(t *testing.T) {
+			req := NewRequest(t, "PUT", "/api/v1/repos/org3/repo3/managed_git_hook/opt_out").AddTokenAuth(ownerToken)
+			MakeRequest(t, req, http.StatusForbidden)
+
+			req = NewRequestWithJSON(t, "PUT", "/api/v1/orgs/org3/managed_git_hook", &api.EditManagedGitHookOption{
+				ForbiddenPaths:  []string{"*.exe"},
+				AllowRepoOptOut: true,
+			}).AddTokenAuth(adminToken)
+			MakeRequest(t, req, http.StatusOK)
+
+			req = NewRequest(t, "PUT", "/api/v1/repos/org3/repo3/managed_git_hook/opt_out").AddTokenAuth(ownerToken)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "GET", "/api/v1/repos/org3/repo3/managed_git_hook").AddTokenAuth(ownerToken)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var repoHook api.RepoManagedGitHook
+			DecodeJSON(t, resp, &repoHook)
+			assert.True(t, repoHook.OptedOut)
+			assert.False(t, repoHook.Active)
+			assert.NotNil(t, repoHook.ManagedGitHook)
+
+			doGitPushTestRepository(dstPath, "origin", "forbidden-path")(t)
+
+			req = NewRequest(t, "DELETE", "/api/v1/repos/org3/repo3/managed_git_hook/opt_out").AddTokenAuth(ownerToken)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "GET", "/api/v1/repos/org3/repo3/managed_git_hook").AddTokenAuth(ownerToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &repoHook)
+			assert.False(t, repoHook.OptedOut)
+			assert.True(t, repoHook.Active)
+		})
+
+		req = NewRequest(t, "DELETE", "/api/v1/orgs/org3/managed_git_hook").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", "/api/v1/orgs/org3/managed_git_hook").AddTokenAuth(ownerToken)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		doGitPushTestRepository(dstPath, "origin", "bad-message")(t)
+	})
+}
+
+func commitManagedGitHookFile(t *testing.T, repoPath, name, content, message string) {
+	assert.NoError(t, os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0o644))
+	assert.NoError(t, git.AddChanges(repoPath, false, name))
+	signature := git.Signature{
+		Email: "user2@example.com",
+		Name:  "User Two",
+		When:  time.Now(),
+	}
+	assert.NoError(t, git.CommitChanges(repoPath, git.CommitChangesOptions{
+		Committer: &signature,
+		Author:    &signature,
+		Message:   message,
+	}))
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPartialClone(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+
+		// the blobs which are not checked out are missing from a filtered clone
+		hasMissingObjects := func(t *testing.T, dstPath string) bool {
+			stdout, _, err := git.NewCommand(git.DefaultContext, "rev-list", "--objects", "--all", "--missing=print").RunStdString(&git.RunOpts{Dir: dstPath})
+			assert.NoError(t, err)
+			for _, line := range strings.Split(stdout, "\n") {
+				if strings.HasPrefix(line, "?") {
+					return true
+				}
+			}
+			return false
+		}
+
+		t.Run("Filtered", func(t *testing.T) {
+			dstPath := t.TempDir()
+			t.Run("Clone", doPartialGitClone(dstPath, u))
+			assert.True(t, hasMissingObjects(t, dstPath))
+		})
+
+		t.Run("CombinedFilter", func(t *testing.T) {
+			// all the kinds of filters are allowed by default
+			dstPath := t.TempDir()
+			assert.NoError(t, git.CloneWithArgs(git.DefaultContext, git.AllowLFSFiltersArgs(), u.String(), dstPath, git.CloneRepoOptions{
+				Filter: "combine:blob:none+tree:2",
+			}))
+			assert.True(t, hasMissingObjects(t, dstPath))
+		})
+
+		t.Run("RepoTooLarge", func(t *testing.T) {
+			defer test.MockVariableValue(&setting.Git.PartialCloneMaxRepoSize, 1024)()
+			assert.NoError(t, repo_model.UpdateRepoSize(db.DefaultContext, 1, 2048, 0))
+
+			// the server ignores the filter and sends a full clone
+			dstPath := t.TempDir()
+			t.Run("Clone", doPartialGitClone(dstPath, u))
+			assert.False(t, hasMissingObjects(t, dstPath))
+		})
+	})
+}
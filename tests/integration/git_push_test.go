@@ -5,14 +5,19 @@ package integration
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
 
+	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
 	repo_service "code.gitea.io/gitea/services/repository"
 
 	"github.com/stretchr/testify/assert"
@@ -134,6 +139,48 @@ func testGitPush(t *testing.T, u *url.URL) {
 	})
 }
 
+func TestGitPushOptionMergeWhenChecksSucceed(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword(user2.Name, userPassword)
+		gitPath := t.TempDir()
+		doGitClone(gitPath, u)(t)
+
+		branchName := "merge-when-checks-succeed"
+		doGitCreateBranch(gitPath, branchName)(t)
+		doGitAddSomeCommits(gitPath, branchName)(t)
+		doGitPushTestRepository(gitPath, "origin", branchName)(t)
+
+		req := NewRequestWithJSON(t, http.MethodPost, "/api/v1/repos/user2/repo1/pulls", &api.CreatePullRequestOption{
+			Head:  branchName,
+			Base:  "master",
+			Title: "merge when checks succeed",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var apiPull api.PullRequest
+		DecodeJSON(t, resp, &apiPull)
+
+		scheduled, _, err := pull_model.GetScheduledMergeByPullID(db.DefaultContext, apiPull.ID)
+		require.NoError(t, err)
+		assert.False(t, scheduled)
+
+		// the push options without value are true
+		_, _, err = git.NewCommand(git.DefaultContext, "commit", "--allow-empty", "-m", "empty commit").RunStdString(&git.RunOpts{Dir: gitPath})
+		require.NoError(t, err)
+		doGitPushTestRepository(gitPath, "origin", branchName, "-o", "merge_when_checks_succeed", "-o", "skip-ci")(t)
+
+		scheduled, autoMerge, err := pull_model.GetScheduledMergeByPullID(db.DefaultContext, apiPull.ID)
+		require.NoError(t, err)
+		assert.True(t, scheduled)
+		assert.Equal(t, user2.ID, autoMerge.DoerID)
+		assert.Equal(t, repo_model.MergeStyleMerge, autoMerge.MergeStyle)
+	})
+}
+
 func runTestGitPush(t *testing.T, u *url.URL, gitOperation func(t *testing.T, gitPath string) (pushed, deleted []string)) {
 	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
 	repo, err := repo_service.CreateRepository(db.DefaultContext, user, user, repo_service.CreateRepoOptions{
@@ -0,0 +1,65 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPushRequireSignoff(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections", &api.CreateBranchProtectionOption{
+			RuleName:       "master",
+			EnablePush:     true,
+			RequireSignoff: true,
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var protection api.BranchProtection
+		DecodeJSON(t, resp, &protection)
+		assert.True(t, protection.RequireSignoff)
+
+		dstPath := t.TempDir()
+		cloneURL, _ := url.Parse(u.String() + "user2/repo1.git")
+		cloneURL.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, cloneURL))
+
+		author := &git.Signature{Name: "User Two", Email: "user2@example.com"}
+		commit := func(t *testing.T, message string) {
+			assert.NoError(t, os.WriteFile(filepath.Join(dstPath, "dco.txt"), []byte(message), 0o644))
+			assert.NoError(t, git.AddChanges(dstPath, true))
+			assert.NoError(t, git.CommitChanges(dstPath, git.CommitChangesOptions{Committer: author, Author: author, Message: message}))
+		}
+
+		t.Run("WithoutSignoff", func(t *testing.T) {
+			commit(t, "add dco.txt")
+			doGitPushTestRepositoryFail(dstPath, "origin", "master")(t)
+			_, _, err := git.NewCommand(git.DefaultContext, "reset", "--hard", "origin/master").RunStdString(&git.RunOpts{Dir: dstPath})
+			assert.NoError(t, err)
+		})
+
+		t.Run("SignoffOfAnotherUser", func(t *testing.T) {
+			commit(t, "add dco.txt\n\nSigned-off-by: User Five <user5@example.com>")
+			doGitPushTestRepositoryFail(dstPath, "origin", "master")(t)
+			_, _, err := git.NewCommand(git.DefaultContext, "reset", "--hard", "origin/master").RunStdString(&git.RunOpts{Dir: dstPath})
+			assert.NoError(t, err)
+		})
+
+		t.Run("SignoffOfAuthor", func(t *testing.T) {
+			commit(t, "add dco.txt\n\nSigned-off-by: User Two <user2@example.com>")
+			doGitPushTestRepository(dstPath, "origin", "master")(t)
+		})
+	})
+}
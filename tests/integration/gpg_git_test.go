@@ -4,13 +4,17 @@
 package integration
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/process"
@@ -259,6 +263,41 @@ func TestGPGGit(t *testing.T) {
 				assert.True(t, branch.Commit.Verification.Verified)
 			}))
 		})
+
+		setting.Repository.Signing.CRUDActions = []string{"never"}
+		setting.Repository.Signing.SignWithUserKeys = true
+		defer func() { setting.Repository.Signing.SignWithUserKeys = false }()
+		t.Run("UserKey-CRUD", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+			testCtx := NewAPITestContext(t, username, "initial-unsigned", auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+
+			// the secret key of the testing key is in the keyring, so it can be used once the user owns the key
+			t.Run("CreateCRUDFile-NoUserKey", crudActionCreateFile(
+				t, testCtx, user, "master", "user-key-none", "unsigned-user-key.txt", func(t *testing.T, response api.FileResponse) {
+					assert.False(t, response.Verification.Verified)
+					assert.Empty(t, response.Verification.Signature)
+				}))
+
+			publicKey := new(bytes.Buffer)
+			armored, err := armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+			assert.NoError(t, err)
+			assert.NoError(t, rootKeyPair.Serialize(armored))
+			assert.NoError(t, armored.Close())
+			token := "user-key-token"
+			signature := new(bytes.Buffer)
+			assert.NoError(t, openpgp.ArmoredDetachSign(signature, rootKeyPair, strings.NewReader(token), nil))
+			_, err = asymkey_model.AddGPGKey(db.DefaultContext, user.ID, publicKey.String(), token, signature.String())
+			assert.NoError(t, err)
+
+			t.Run("CreateCRUDFile-UserKey", crudActionCreateFile(
+				t, testCtx, user, "master", "user-key", "signed-user-key.txt", func(t *testing.T, response api.FileResponse) {
+					assert.NotEmpty(t, response.Verification.Signature)
+					assert.True(t, response.Verification.Verified)
+					if assert.NotNil(t, response.Verification.Signer) {
+						assert.Equal(t, user.Email, response.Verification.Signer.Email)
+					}
+				}))
+		})
 	})
 }
 
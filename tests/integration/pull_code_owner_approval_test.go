@@ -0,0 +1,135 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/forms"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullCodeOwnerApproval(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_codeowner_approval",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			OldBranch: repo.DefaultBranch,
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      "CODEOWNERS",
+					ContentReader: strings.NewReader("README.md @user8\ndocs/.* @user5\n"),
+				},
+			},
+		})
+		require.NoError(t, err)
+		_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			NewBranch: "codeowner-approval",
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "update",
+					TreePath:      "README.md",
+					ContentReader: strings.NewReader("# This is a new project\n"),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		repoURL := fmt.Sprintf("/api/v1/repos/user2/%s", repo.Name)
+
+		req := NewRequestWithJSON(t, "POST", repoURL+"/branch_protections", &api.CreateBranchProtectionOption{
+			RuleName:                 "master",
+			EnablePush:               true,
+			RequireCodeOwnerApproval: true,
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		protection := new(api.BranchProtection)
+		DecodeJSON(t, resp, protection)
+		assert.True(t, protection.RequireCodeOwnerApproval)
+
+		req = NewRequestWithJSON(t, "POST", repoURL+"/pulls", &api.CreatePullRequestOption{
+			Head:  "codeowner-approval",
+			Base:  "master",
+			Title: "Change the readme",
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		pull := new(api.PullRequest)
+		DecodeJSON(t, resp, pull)
+
+		codeOwnersURL := fmt.Sprintf("%s/pulls/%d/code_owners", repoURL, pull.Index)
+		req = NewRequest(t, "GET", codeOwnersURL).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var rules []*api.PullRequestCodeOwnerRule
+		DecodeJSON(t, resp, &rules)
+		require.Len(t, rules, 1)
+		assert.Equal(t, "README.md", rules[0].Pattern)
+		assert.Equal(t, []string{"README.md"}, rules[0].Files)
+		require.Len(t, rules[0].Users, 1)
+		assert.Equal(t, "user8", rules[0].Users[0].UserName)
+		assert.Empty(t, rules[0].Approvers)
+		assert.False(t, rules[0].Satisfied)
+
+		// the merge is blocked until the code owner approves
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/%s/pulls/%d", repo.Name, pull.Index))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, ".pull-code-owners", true)
+
+		mergeURL := fmt.Sprintf("%s/pulls/%d/merge", repoURL, pull.Index)
+		req = NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{Do: "merge"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusMethodNotAllowed)
+
+		// approvals must be official
+		permission := "write"
+		req = NewRequestWithJSON(t, "PUT", repoURL+"/collaborators/user8", &api.AddCollaboratorOption{Permission: &permission}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		token8 := getTokenForLoggedInUser(t, loginUser(t, "user8"), auth_model.AccessTokenScopeWriteRepository)
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/pulls/%d/reviews", repoURL, pull.Index), &api.CreatePullReviewOptions{
+			Event: api.ReviewStateApproved,
+		}).AddTokenAuth(token8)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "GET", codeOwnersURL).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &rules)
+		require.Len(t, rules, 1)
+		require.Len(t, rules[0].Approvers, 1)
+		assert.Equal(t, "user8", rules[0].Approvers[0].UserName)
+		assert.True(t, rules[0].Satisfied)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/%s/pulls/%d", repo.Name, pull.Index))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, ".pull-code-owners", false)
+
+		req = NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{Do: "merge"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+	})
+}
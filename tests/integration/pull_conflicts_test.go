@@ -0,0 +1,128 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullResolveConflicts(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_resolve_conflicts",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		// the same file is added with different contents on both branches
+		for _, branch := range []string{"feature", "master"} {
+			_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				OldBranch: "master",
+				NewBranch: branch,
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      "conflict.txt",
+						ContentReader: strings.NewReader("a\n" + branch + "\nc\n"),
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_resolve_conflicts/pulls", &api.CreatePullRequestOption{
+			Head:  "feature",
+			Base:  "master",
+			Title: "Add a conflicting file",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		pull := new(api.PullRequest)
+		DecodeJSON(t, resp, pull)
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pull.ID})
+		assert.Equal(t, issues_model.PullRequestStatusConflict, pr.Status)
+
+		session := loginUser(t, "user2")
+		pullLink := fmt.Sprintf("/user2/test_resolve_conflicts/pulls/%d", pull.Index)
+		req = NewRequest(t, "GET", pullLink)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, fmt.Sprintf(`a[href="%s/conflicts"]`, pullLink), true)
+
+		req = NewRequest(t, "GET", pullLink+"/conflicts")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.Equal(t, "conflict.txt", htmlDoc.GetInputValueByName("paths"))
+		content := htmlDoc.doc.Find("textarea[name=contents]").Text()
+		assert.Contains(t, content, "<<<<<<< feature\nfeature\n=======\nmaster\n>>>>>>> master\n")
+
+		values := url.Values{
+			"_csrf":          {htmlDoc.GetCSRF()},
+			"head_commit_id": {htmlDoc.GetInputValueByName("head_commit_id")},
+			"base_commit_id": {htmlDoc.GetInputValueByName("base_commit_id")},
+			"paths":          {"conflict.txt"},
+			"contents":       {content},
+			"message":        {"Resolve the conflicts"},
+		}
+
+		// the conflict markers must be removed
+		req = NewRequestWithURLValues(t, "POST", pullLink+"/conflicts", values)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.Contains(t, htmlDoc.doc.Find(".flash-error").Text(), "conflict.txt still has conflict markers")
+
+		values.Set("contents", "a\nfeature\nmaster\nc\n")
+		req = NewRequestWithURLValues(t, "POST", pullLink+"/conflicts", values)
+		session.MakeRequest(t, req, http.StatusSeeOther)
+
+		gitRepo, err := gitrepo.OpenRepository(db.DefaultContext, repo)
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		commit, err := gitRepo.GetBranchCommit("feature")
+		require.NoError(t, err)
+		assert.Equal(t, 2, commit.ParentCount())
+		assert.Equal(t, "Resolve the conflicts\n", commit.CommitMessage)
+		resolved, err := commit.GetFileContent("conflict.txt", 1024)
+		require.NoError(t, err)
+		assert.Equal(t, "a\nfeature\nmaster\nc\n", resolved)
+
+		assert.Eventually(t, func() bool {
+			pr = unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pull.ID})
+			return pr.Status == issues_model.PullRequestStatusMergeable
+		}, 10*time.Second, 100*time.Millisecond)
+
+		// the commits of the form are outdated now
+		req = NewRequestWithURLValues(t, "POST", pullLink+"/conflicts", values)
+		resp = session.MakeRequest(t, req, http.StatusSeeOther)
+		assert.Equal(t, pullLink+"/conflicts", resp.Header().Get("Location"))
+
+		// there is nothing left to resolve
+		req = NewRequest(t, "GET", pullLink+"/conflicts")
+		resp = session.MakeRequest(t, req, http.StatusSeeOther)
+		assert.Equal(t, pullLink, resp.Header().Get("Location"))
+	})
+}
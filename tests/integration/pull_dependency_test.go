@@ -0,0 +1,120 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/forms"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullCrossRepositoryDependency(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		user8 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 8})
+		for _, name := range []string{"test_dependency_app", "test_dependency_lib"} {
+			_, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+				Name:             name,
+				Readme:           "Default",
+				AutoInit:         true,
+				ObjectFormatName: git.Sha1ObjectFormat.Name(),
+				DefaultBranch:    "master",
+			})
+			require.NoError(t, err)
+		}
+		app := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerID: user2.ID, LowerName: "test_dependency_app"})
+
+		_, err := files_service.ChangeRepoFiles(db.DefaultContext, app, user2, &files_service.ChangeRepoFilesOptions{
+			NewBranch: "use-lib",
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      "lib.md",
+					ContentReader: strings.NewReader("uses the new lib\n"),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteIssue)
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_dependency_app/pulls", &api.CreatePullRequestOption{
+			Head:  "use-lib",
+			Base:  "master",
+			Title: "Use the new lib",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		pull := new(api.PullRequest)
+		DecodeJSON(t, resp, pull)
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pull.ID})
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_dependency_lib/issues", &api.CreateIssueOption{
+			Title: "Release the new lib",
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		blocker := new(api.Issue)
+		DecodeJSON(t, resp, blocker)
+
+		// the pull request is blocked by the issue of the other repository
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/test_dependency_app/issues/%d/dependencies", pull.Index), &api.IssueMeta{
+			Owner: "user2",
+			Name:  "test_dependency_lib",
+			Index: blocker.Index,
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		mergeURL := fmt.Sprintf("/api/v1/repos/user2/test_dependency_app/pulls/%d/merge", pull.Index)
+		req = NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{
+			Do: string(repo_model.MergeStyleMerge),
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusMethodNotAllowed)
+
+		// the blocker is closed by another user
+		libCtx := NewAPITestContext(t, "user2", "test_dependency_lib", auth_model.AccessTokenScopeWriteRepository)
+		t.Run("AddCollaborator", doAPIAddCollaborator(libCtx, user8.Name, perm.AccessModeWrite))
+		token8 := getUserToken(t, user8.Name, auth_model.AccessTokenScopeWriteIssue)
+		closed := string(api.StateClosed)
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/user2/test_dependency_lib/issues/%d", blocker.Index), &api.EditIssueOption{
+			State: &closed,
+		}).AddTokenAuth(token8)
+		MakeRequest(t, req, http.StatusCreated)
+
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{
+			Type:             issues_model.CommentTypeDependencyClosed,
+			IssueID:          pr.IssueID,
+			PosterID:         user8.ID,
+			DependentIssueID: blocker.ID,
+		})
+		unittest.AssertExistsAndLoadBean(t, &activities_model.Notification{UserID: user2.ID, IssueID: pr.IssueID})
+
+		session := loginUser(t, "user2")
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/test_dependency_app/pulls/%d", pull.Index))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		assert.Contains(t, htmlDoc.doc.Find(".timeline-item.event").Text(), "user2/test_dependency_lib#1 - Release the new lib")
+
+		req = NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{
+			Do: string(repo_model.MergeStyleMerge),
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+	})
+}
@@ -0,0 +1,234 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullMergeQueue(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		// create a pull request
+		session := loginUser(t, "user1")
+		forkedName := "repo1-merge-queue"
+		testRepoFork(t, session, "user2", "repo1", "user1", forkedName, "")
+		defer func() {
+			testDeleteRepository(t, session, "user1", forkedName)
+		}()
+		testEditFile(t, session, "user1", forkedName, "master", "README.md", "Hello, World (Edited)\n")
+		testPullCreate(t, session, "user1", forkedName, false, "master", "master", "Merge queue test pull")
+
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		baseRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user2", Name: "repo1"})
+		forkedRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user1", Name: forkedName})
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{
+			BaseRepoID: baseRepo.ID,
+			BaseBranch: "master",
+			HeadRepoID: forkedRepo.ID,
+			HeadBranch: "master",
+		})
+
+		ownerSession := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, ownerSession, auth_model.AccessTokenScopeWriteRepository)
+		queueURL := fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/merge_queue", pr.Index)
+
+		// the merge queue is disabled by default
+		req := NewRequestWithJSON(t, "POST", queueURL, &api.AddToMergeQueueOption{MergeStyle: "merge"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		enable := true
+		req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1", &api.EditRepoOption{EnableMergeQueue: &enable}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		apiRepo := new(api.Repository)
+		DecodeJSON(t, resp, apiRepo)
+		assert.True(t, apiRepo.EnableMergeQueue)
+
+		// require a commit status so that the speculative merge commit waits for it
+		csrf := GetCSRF(t, ownerSession, "/user2/repo1/settings/branches")
+		req = NewRequestWithValues(t, "POST", "/user2/repo1/settings/branches/edit", map[string]string{
+			"_csrf":                 csrf,
+			"rule_name":             "master",
+			"enable_push":           "true",
+			"enable_status_check":   "true",
+			"status_check_contexts": "gitea/actions",
+		})
+		ownerSession.MakeRequest(t, req, http.StatusSeeOther)
+
+		baseGitRepo, err := gitrepo.OpenRepository(db.DefaultContext, baseRepo)
+		require.NoError(t, err)
+		masterCommitID, err := baseGitRepo.GetBranchCommitID("master")
+		require.NoError(t, err)
+		headCommitID, err := baseGitRepo.GetRefCommitID(pr.GetGitRefName())
+		require.NoError(t, err)
+		baseGitRepo.Close()
+		defer func() {
+			testResetRepo(t, baseRepo.RepoPath(), "master", masterCommitID)
+		}()
+
+		// the pull request must pass its checks to be added to the queue
+		req = NewRequestWithJSON(t, "POST", queueURL, &api.AddToMergeQueueOption{MergeStyle: "merge"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusMethodNotAllowed)
+		err = commitstatus_service.CreateCommitStatus(db.DefaultContext, baseRepo, user2, headCommitID, &git_model.CommitStatus{
+			State:     api.CommitStatusSuccess,
+			TargetURL: "https://gitea.com",
+			Context:   "gitea/actions",
+		})
+		require.NoError(t, err)
+
+		t.Run("AddAndRemove", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", queueURL, &api.AddToMergeQueueOption{MergeStyle: "merge"}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusCreated)
+			entry := new(api.MergeQueueEntry)
+			DecodeJSON(t, resp, entry)
+			assert.EqualValues(t, 1, entry.Position)
+			assert.EqualValues(t, pr.Index, entry.Index)
+			assert.EqualValues(t, "master", entry.BaseBranch)
+			assert.EqualValues(t, "user2", entry.Enqueuer.UserName)
+
+			req = NewRequestWithJSON(t, "POST", queueURL, &api.AddToMergeQueueOption{MergeStyle: "merge"}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusConflict)
+
+			req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/pulls/merge_queue").AddTokenAuth(token)
+			resp = MakeRequest(t, req, http.StatusOK)
+			var entries []*api.MergeQueueEntry
+			DecodeJSON(t, resp, &entries)
+			if assert.Len(t, entries, 1) {
+				assert.EqualValues(t, pr.Index, entries[0].Index)
+			}
+
+			req = NewRequest(t, "DELETE", queueURL).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "GET", queueURL).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusNotFound)
+			unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: pr.IssueID, Type: issues_model.CommentTypePRRemovedFromMergeQueue})
+		})
+
+		t.Run("MergeAfterChecksSucceed", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "POST", queueURL, &api.AddToMergeQueueOption{MergeStyle: "merge"}).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+
+			// wait for the speculative merge commit
+			var entry *api.MergeQueueEntry
+			assert.Eventually(t, func() bool {
+				req := NewRequest(t, "GET", queueURL).AddTokenAuth(token)
+				resp := MakeRequest(t, req, http.StatusOK)
+				entry = new(api.MergeQueueEntry)
+				DecodeJSON(t, resp, entry)
+				return entry.State == "testing"
+			}, 10*time.Second, 100*time.Millisecond)
+			require.NotEmpty(t, entry.MergeCommitID)
+			assert.EqualValues(t, masterCommitID, entry.BaseCommitID)
+
+			baseGitRepo, err := gitrepo.OpenRepository(db.DefaultContext, baseRepo)
+			require.NoError(t, err)
+			defer baseGitRepo.Close()
+			branchCommitID, err := baseGitRepo.GetBranchCommitID(pull_service.MergeQueueBranchName(pr))
+			require.NoError(t, err)
+			assert.EqualValues(t, entry.MergeCommitID, branchCommitID)
+
+			pr = unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pr.ID})
+			assert.False(t, pr.HasMerged)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/user2/repo1/pulls/%d", pr.Index))
+			resp := ownerSession.MakeRequest(t, req, http.StatusOK)
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			htmlDoc.AssertElement(t, fmt.Sprintf("form[action='/user2/repo1/pulls/%d/remove_from_merge_queue']", pr.Index), true)
+
+			err = commitstatus_service.CreateCommitStatus(db.DefaultContext, baseRepo, user2, entry.MergeCommitID, &git_model.CommitStatus{
+				State:     api.CommitStatusSuccess,
+				TargetURL: "https://gitea.com",
+				Context:   "gitea/actions",
+			})
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool {
+				pr = unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pr.ID})
+				return pr.HasMerged
+			}, 10*time.Second, 100*time.Millisecond)
+			assert.EqualValues(t, entry.MergeCommitID, pr.MergedCommitID)
+			unittest.AssertNotExistsBean(t, &pull_model.MergeQueueEntry{PullID: pr.ID})
+
+			masterHead, err := baseGitRepo.GetBranchCommitID("master")
+			require.NoError(t, err)
+			assert.EqualValues(t, entry.MergeCommitID, masterHead)
+			assert.Eventually(t, func() bool {
+				return !baseGitRepo.IsBranchExist(pull_service.MergeQueueBranchName(pr))
+			}, 10*time.Second, 100*time.Millisecond)
+		})
+	})
+}
+
+func TestPullMergeQueueWithoutStatuses(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		session := loginUser(t, "user1")
+		forkedName := "repo1-merge-queue-no-status"
+		testRepoFork(t, session, "user2", "repo1", "user1", forkedName, "")
+		defer func() {
+			testDeleteRepository(t, session, "user1", forkedName)
+		}()
+		testEditFile(t, session, "user1", forkedName, "master", "README.md", "Hello, World (Edited without status)\n")
+		testPullCreate(t, session, "user1", forkedName, false, "master", "master", "Merge queue without status test pull")
+
+		baseRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user2", Name: "repo1"})
+		forkedRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user1", Name: forkedName})
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{
+			BaseRepoID: baseRepo.ID,
+			BaseBranch: "master",
+			HeadRepoID: forkedRepo.ID,
+			HeadBranch: "master",
+		})
+
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteRepository)
+		enable := true
+		req := NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user2/repo1", &api.EditRepoOption{EnableMergeQueue: &enable}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		baseGitRepo, err := gitrepo.OpenRepository(db.DefaultContext, baseRepo)
+		require.NoError(t, err)
+		defer baseGitRepo.Close()
+		masterCommitID, err := baseGitRepo.GetBranchCommitID("master")
+		require.NoError(t, err)
+		defer func() {
+			testResetRepo(t, baseRepo.RepoPath(), "master", masterCommitID)
+		}()
+
+		// without required status checks, the speculative merge commit without any status is merged
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/merge_queue", pr.Index), &api.AddToMergeQueueOption{MergeStyle: "merge"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		assert.Eventually(t, func() bool {
+			pr = unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pr.ID})
+			return pr.HasMerged
+		}, 10*time.Second, 100*time.Millisecond)
+		unittest.AssertNotExistsBean(t, &pull_model.MergeQueueEntry{PullID: pr.ID})
+
+		commitStatuses, _, err := git_model.GetLatestCommitStatus(db.DefaultContext, baseRepo.ID, pr.MergedCommitID, db.ListOptionsAll)
+		require.NoError(t, err)
+		assert.Empty(t, commitStatuses)
+		masterHead, err := baseGitRepo.GetBranchCommitID("master")
+		require.NoError(t, err)
+		assert.EqualValues(t, pr.MergedCommitID, masterHead)
+	})
+}
@@ -31,16 +31,20 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/modules/translation"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/automerge"
+	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
 	files_service "code.gitea.io/gitea/services/repository/files"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func testPullMerge(t *testing.T, session *TestSession, user, repo, pullnum string, mergeStyle repo_model.MergeStyle, deleteBranch bool) *httptest.ResponseRecorder {
@@ -266,17 +270,80 @@ func TestCantMergeConflict(t *testing.T) {
 		gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo1)
 		assert.NoError(t, err)
 
-		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleMerge, "", "CONFLICT", false)
+		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleMerge, "", "CONFLICT", nil, false)
 		assert.Error(t, err, "Merge should return an error due to conflict")
 		assert.True(t, models.IsErrMergeConflicts(err), "Merge error is not a conflict error")
 
-		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleRebase, "", "CONFLICT", false)
+		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleRebase, "", "CONFLICT", nil, false)
 		assert.Error(t, err, "Merge should return an error due to conflict")
 		assert.True(t, models.IsErrRebaseConflicts(err), "Merge error is not a conflict error")
 		gitRepo.Close()
 	})
 }
 
+func TestMergeStrategyOptions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		session := loginUser(t, "user1")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1", "")
+		testEditFileToNewBranch(t, session, "user1", "repo1", "master", "conflict", "README.md", "Hello, World (Edited Once)\n")
+		testEditFileToNewBranch(t, session, "user1", "repo1", "master", "base", "README.md", "Hello, World (Edited Twice)\n")
+
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequestWithJSON(t, http.MethodPost, "/api/v1/repos/user1/repo1/pulls", &api.CreatePullRequestOption{
+			Head:  "conflict",
+			Base:  "base",
+			Title: "create a conflicting pr",
+		}).AddTokenAuth(token)
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		// invalid strategies and options are rejected
+		req = NewRequestWithJSON(t, http.MethodPatch, "/api/v1/repos/user1/repo1", &api.EditRepoOption{
+			MergeStrategyOptions: &[]string{"--upload-pack=evil"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequestWithJSON(t, http.MethodPatch, "/api/v1/repos/user1/repo1", &api.EditRepoOption{
+			MergeStrategy: util.ToPointer("octopus"),
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, http.MethodPatch, "/api/v1/repos/user1/repo1", &api.EditRepoOption{
+			MergeStrategy:        util.ToPointer("recursive"),
+			MergeStrategyOptions: &[]string{"ours"},
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var apiRepo api.Repository
+		DecodeJSON(t, resp, &apiRepo)
+		assert.Equal(t, "recursive", apiRepo.MergeStrategy)
+		assert.Equal(t, []string{"ours"}, apiRepo.MergeStrategyOptions)
+
+		user1 := unittest.AssertExistsAndLoadBean(t, &user_model.User{Name: "user1"})
+		repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerID: user1.ID, Name: "repo1"})
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{
+			HeadRepoID: repo1.ID,
+			BaseRepoID: repo1.ID,
+			HeadBranch: "conflict",
+			BaseBranch: "base",
+		})
+
+		gitRepo, err := gitrepo.OpenRepository(git.DefaultContext, repo1)
+		require.NoError(t, err)
+		defer gitRepo.Close()
+
+		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleMerge, "", "INVALID", []string{"subtree=docs"}, false)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+		// the options given to the merge replace the ones of the repository
+		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleMerge, "", "THEIRS", []string{"theirs"}, false)
+		require.NoError(t, err)
+
+		commit, err := gitRepo.GetBranchCommit("base")
+		require.NoError(t, err)
+		content, err := commit.GetFileContent("README.md", 1024)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello, World (Edited Once)\n", content)
+	})
+}
+
 func TestCantMergeUnrelated(t *testing.T) {
 	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
 		session := loginUser(t, "user1")
@@ -365,7 +432,7 @@ func TestCantMergeUnrelated(t *testing.T) {
 			BaseBranch: "base",
 		})
 
-		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleMerge, "", "UNRELATED", false)
+		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleMerge, "", "UNRELATED", nil, false)
 		assert.Error(t, err, "Merge should return an error due to unrelated")
 		assert.True(t, models.IsErrMergeUnrelatedHistories(err), "Merge error is not a unrelated histories error")
 		gitRepo.Close()
@@ -405,7 +472,7 @@ func TestFastForwardOnlyMerge(t *testing.T) {
 		gitRepo, err := git.OpenRepository(git.DefaultContext, repo_model.RepoPath(user1.Name, repo1.Name))
 		assert.NoError(t, err)
 
-		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleFastForwardOnly, "", "FAST-FORWARD-ONLY", false)
+		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleFastForwardOnly, "", "FAST-FORWARD-ONLY", nil, false)
 
 		assert.NoError(t, err)
 
@@ -447,7 +514,7 @@ func TestCantFastForwardOnlyMergeDiverging(t *testing.T) {
 		gitRepo, err := git.OpenRepository(git.DefaultContext, repo_model.RepoPath(user1.Name, repo1.Name))
 		assert.NoError(t, err)
 
-		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleFastForwardOnly, "", "DIVERGING", false)
+		err = pull.Merge(context.Background(), pr, user1, gitRepo, repo_model.MergeStyleFastForwardOnly, "", "DIVERGING", nil, false)
 
 		assert.Error(t, err, "Merge should return an error due to being for a diverging branch")
 		assert.True(t, models.IsErrMergeDivergingFastForwardOnly(err), "Merge error is not a diverging fast-forward-only error")
@@ -595,6 +662,63 @@ func TestPullDontRetargetChildOnWrongRepo(t *testing.T) {
 	})
 }
 
+func TestPullRetargetChildOnBaseDeletion(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		defer test.MockVariableValue(&setting.Repository.PullRequest.RetargetChildrenOnMerge, false)()
+
+		session := loginUser(t, "user1")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1", "")
+		testEditFileToNewBranch(t, session, "user1", "repo1", "master", "base-pr", "README.md", "Hello, World\n(Edited - TestPullRetargetChildOnBaseDeletion - base PR)\n")
+		testEditFileToNewBranch(t, session, "user1", "repo1", "base-pr", "child-pr", "README.md", "Hello, World\n(Edited - TestPullRetargetChildOnBaseDeletion - base PR)\n(Edited - TestPullRetargetChildOnBaseDeletion - child PR)\n")
+
+		token := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteRepository)
+		enabled := true
+		req := NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user1/repo1", &api.EditRepoOption{
+			RetargetOnBaseDeletion: &enabled,
+			RebaseOnRetarget:       &enabled,
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		apiRepo := new(api.Repository)
+		DecodeJSON(t, resp, apiRepo)
+		assert.True(t, apiRepo.RetargetOnBaseDeletion)
+		assert.True(t, apiRepo.RebaseOnRetarget)
+
+		respBasePR := testPullCreate(t, session, "user1", "repo1", true, "master", "base-pr", "Base Pull Request")
+		elemBasePR := strings.Split(test.RedirectURL(respBasePR), "/")
+		assert.EqualValues(t, "pulls", elemBasePR[3])
+
+		respChildPR := testPullCreate(t, session, "user1", "repo1", true, "base-pr", "child-pr", "Child Pull Request")
+		elemChildPR := strings.Split(test.RedirectURL(respChildPR), "/")
+		assert.EqualValues(t, "pulls", elemChildPR[3])
+
+		testPullMerge(t, session, elemBasePR[1], elemBasePR[2], elemBasePR[4], repo_model.MergeStyleMerge, true)
+
+		// the child PR is retargeted to the default branch instead of being closed when the base branch is deleted
+		req = NewRequest(t, "GET", test.RedirectURL(respChildPR))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		targetBranch := htmlDoc.doc.Find("#branch_target>a").Text()
+		prStatus := strings.TrimSpace(htmlDoc.doc.Find(".issue-title-meta>.issue-state-label").Text())
+
+		assert.EqualValues(t, "master", targetBranch)
+		assert.EqualValues(t, "Open", prStatus)
+
+		// and it's rebased onto the merge commit of the base PR
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user1", Name: "repo1"})
+		gitRepo, err := gitrepo.OpenRepository(db.DefaultContext, repo)
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		masterCommitID, err := gitRepo.GetBranchCommitID("master")
+		require.NoError(t, err)
+		childCommit, err := gitRepo.GetBranchCommit("child-pr")
+		require.NoError(t, err)
+		parentID, err := childCommit.ParentID(0)
+		require.NoError(t, err)
+		assert.Equal(t, masterCommitID, parentID.String())
+	})
+}
+
 func TestPullMergeIndexerNotifier(t *testing.T) {
 	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
 		// create a pull request
@@ -705,12 +829,12 @@ func TestPullAutoMergeAfterCommitStatusSucceed(t *testing.T) {
 		session.MakeRequest(t, req, http.StatusSeeOther)
 
 		// first time insert automerge record, return true
-		scheduled, err := automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test")
+		scheduled, err := automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test", pull_model.AutoMergeConditions{})
 		assert.NoError(t, err)
 		assert.True(t, scheduled)
 
 		// second time insert automerge record, return false because it does exist
-		scheduled, err = automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test")
+		scheduled, err = automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test", pull_model.AutoMergeConditions{})
 		assert.Error(t, err)
 		assert.False(t, scheduled)
 
@@ -789,12 +913,12 @@ func TestPullAutoMergeAfterCommitStatusSucceedAndApproval(t *testing.T) {
 		session.MakeRequest(t, req, http.StatusSeeOther)
 
 		// first time insert automerge record, return true
-		scheduled, err := automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test")
+		scheduled, err := automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test", pull_model.AutoMergeConditions{})
 		assert.NoError(t, err)
 		assert.True(t, scheduled)
 
 		// second time insert automerge record, return false because it does exist
-		scheduled, err = automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test")
+		scheduled, err = automerge.ScheduleAutoMerge(db.DefaultContext, user1, pr, repo_model.MergeStyleMerge, "auto merge test", pull_model.AutoMergeConditions{})
 		assert.Error(t, err)
 		assert.False(t, scheduled)
 
@@ -846,3 +970,101 @@ func TestPullAutoMergeAfterCommitStatusSucceedAndApproval(t *testing.T) {
 		unittest.AssertNotExistsBean(t, &pull_model.AutoMerge{PullID: pr.ID})
 	})
 }
+
+func TestPullAutoMergeWithConditions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_auto_merge_conditions",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		for branch, treePath := range map[string]string{"feature-a": "a.md", "feature-b": "b.md"} {
+			_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				NewBranch: branch,
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      treePath,
+						ContentReader: strings.NewReader(branch + "\n"),
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		repoURL := fmt.Sprintf("/api/v1/repos/user2/%s", repo.Name)
+		createPull := func(head string) *api.PullRequest {
+			req := NewRequestWithJSON(t, "POST", repoURL+"/pulls", &api.CreatePullRequestOption{
+				Head:  head,
+				Base:  "master",
+				Title: "Add " + head,
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusCreated)
+			pull := new(api.PullRequest)
+			DecodeJSON(t, resp, pull)
+			return pull
+		}
+		pullA := createPull("feature-a")
+		pullB := createPull("feature-b")
+
+		mergeURL := fmt.Sprintf("%s/pulls/%d/merge", repoURL, pullA.Index)
+		req := NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{
+			Do:                     "merge",
+			MergeWhenChecksSucceed: true,
+			AutoMergeAfterPull:     pullA.Index,
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{
+			Do:                          "merge",
+			MergeWhenChecksSucceed:      true,
+			AutoMergeRequiredApprovals:  1,
+			AutoMergeNoChangesRequested: true,
+			AutoMergeAfter:              "2024-01-02T15:04:05Z",
+			AutoMergeAfterPull:          pullB.Index,
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		scheduled := unittest.AssertExistsAndLoadBean(t, &pull_model.AutoMerge{PullID: pullA.ID})
+		assert.EqualValues(t, 1, scheduled.RequiredApprovals)
+		assert.True(t, scheduled.NoChangesRequested)
+		assert.EqualValues(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC).Unix(), scheduled.MergeAfterUnix)
+		assert.Equal(t, pullB.ID, scheduled.AfterPullID)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/%s/pulls/%d", repo.Name, pullA.Index))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, ".auto-merge-conditions", true)
+
+		// the approval alone isn't enough
+		permission := "write"
+		req = NewRequestWithJSON(t, "PUT", repoURL+"/collaborators/user8", &api.AddCollaboratorOption{Permission: &permission}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+		token8 := getTokenForLoggedInUser(t, loginUser(t, "user8"), auth_model.AccessTokenScopeWriteRepository)
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/pulls/%d/reviews", repoURL, pullA.Index), &api.CreatePullReviewOptions{
+			Event: api.ReviewStateApproved,
+		}).AddTokenAuth(token8)
+		MakeRequest(t, req, http.StatusOK)
+
+		time.Sleep(2 * time.Second)
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pullA.ID})
+		assert.False(t, pr.HasMerged)
+
+		// merging the other pull request merges this one
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("%s/pulls/%d/merge", repoURL, pullB.Index), &forms.MergePullRequestForm{Do: "merge"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		assert.Eventually(t, func() bool {
+			pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pullA.ID})
+			return pr.HasMerged
+		}, 10*time.Second, 200*time.Millisecond)
+		unittest.AssertNotExistsBean(t, &pull_model.AutoMerge{PullID: pullA.ID})
+	})
+}
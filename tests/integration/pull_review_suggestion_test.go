@@ -0,0 +1,116 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullReviewSuggestions(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		// create a pull request
+		session := loginUser(t, "user1")
+		forkedName := "repo1-suggestions"
+		testRepoFork(t, session, "user2", "repo1", "user1", forkedName, "")
+		defer func() {
+			testDeleteRepository(t, session, "user1", forkedName)
+		}()
+		testEditFile(t, session, "user1", forkedName, "master", "README.md", "Hello, World (Edited)\nSecond line\n")
+		testPullCreate(t, session, "user1", forkedName, false, "master", "master", "Suggestions test pull")
+
+		baseRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user2", Name: "repo1"})
+		forkedRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{OwnerName: "user1", Name: forkedName})
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{
+			BaseRepoID: baseRepo.ID,
+			BaseBranch: "master",
+			HeadRepoID: forkedRepo.ID,
+			HeadBranch: "master",
+		})
+
+		// suggest changes as a reviewer
+		reviewerSession := loginUser(t, "user2")
+		reviewerToken := getTokenForLoggedInUser(t, reviewerSession, auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/reviews", pr.Index), &api.CreatePullReviewOptions{
+			Event: "COMMENT",
+			Comments: []api.CreatePullReviewComment{
+				{
+					Path:       "README.md",
+					Body:       "Please use:\n```suggestion\nHello, World (Suggested)\n```",
+					NewLineNum: 1,
+				},
+				{
+					Path:       "README.md",
+					Body:       "```suggestion\n```",
+					NewLineNum: 2,
+				},
+				{
+					Path:       "README.md",
+					Body:       "Not a suggestion",
+					NewLineNum: 2,
+				},
+			},
+		}).AddTokenAuth(reviewerToken)
+		MakeRequest(t, req, http.StatusOK)
+
+		first := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: pr.IssueID, Type: issues_model.CommentTypeCode, Line: 1})
+		second := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: pr.IssueID, Type: issues_model.CommentTypeCode, Content: "```suggestion\n```"})
+		other := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: pr.IssueID, Type: issues_model.CommentTypeCode, Content: "Not a suggestion"})
+
+		// the suggestions are displayed as a diff
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/repo1/pulls/%d/files", pr.Index))
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		assert.Equal(t, 2, htmlDoc.Find(".code-suggestion").Length())
+		htmlDoc.AssertElement(t, fmt.Sprintf("input[name='comment_ids'][value='%d']", first.ID), true)
+		htmlDoc.AssertElement(t, "#apply-suggestions-form", true)
+
+		suggestionsURL := fmt.Sprintf("/api/v1/repos/user2/repo1/pulls/%d/suggestions", pr.Index)
+
+		// the reviewer can't push to the head branch
+		req = NewRequestWithJSON(t, "POST", suggestionsURL, &api.ApplyPullReviewSuggestionsOptions{CommentIDs: []int64{first.ID}}).AddTokenAuth(reviewerToken)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		req = NewRequestWithJSON(t, "POST", suggestionsURL, &api.ApplyPullReviewSuggestionsOptions{CommentIDs: []int64{other.ID}}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", suggestionsURL, &api.ApplyPullReviewSuggestionsOptions{CommentIDs: []int64{first.ID, second.ID}}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		filesResponse := new(api.FilesResponse)
+		DecodeJSON(t, resp, filesResponse)
+		require.NotNil(t, filesResponse.Commit)
+		assert.Equal(t, "Apply suggestions from code review\n\nCo-authored-by: User Two <user2@noreply.example.org>\n", filesResponse.Commit.Message)
+
+		gitRepo, err := gitrepo.OpenRepository(db.DefaultContext, forkedRepo)
+		require.NoError(t, err)
+		defer gitRepo.Close()
+		commit, err := gitRepo.GetBranchCommit("master")
+		require.NoError(t, err)
+		assert.Equal(t, filesResponse.Commit.SHA, commit.ID.String())
+		content, err := commit.GetFileContent("README.md", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello, World (Suggested)\n", content)
+
+		first = unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: first.ID})
+		assert.NotZero(t, first.ResolveDoerID)
+
+		// the commented lines have changed
+		req = NewRequestWithJSON(t, "POST", suggestionsURL, &api.ApplyPullReviewSuggestionsOptions{CommentIDs: []int64{first.ID}}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
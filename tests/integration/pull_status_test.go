@@ -12,10 +12,15 @@ import (
 	"testing"
 
 	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -99,6 +104,102 @@ func TestPullCreate_CommitStatus(t *testing.T) {
 	})
 }
 
+func TestAPIPullRequiredStatusChecks(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_required_status_checks",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		assert.NoError(t, err)
+		_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			OldBranch: "master",
+			NewBranch: "feature",
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      "feature.txt",
+					ContentReader: strings.NewReader("feature\n"),
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		testCtx := NewAPITestContext(t, "user2", repo.Name, auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_required_status_checks/pulls", &api.CreatePullRequestOption{
+			Head:  "feature",
+			Base:  "master",
+			Title: "Add the feature",
+		}).AddTokenAuth(testCtx.Token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		pull := new(api.PullRequest)
+		DecodeJSON(t, resp, pull)
+		statusChecksURL := fmt.Sprintf("/api/v1/repos/user2/test_required_status_checks/pulls/%d/status_checks", pull.Index)
+
+		getStatusChecks := func() *api.PullRequestStatusChecks {
+			req := NewRequest(t, "GET", statusChecksURL).AddTokenAuth(testCtx.Token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			statusChecks := new(api.PullRequestStatusChecks)
+			DecodeJSON(t, resp, statusChecks)
+			return statusChecks
+		}
+
+		// nothing is required without a branch protection
+		statusChecks := getStatusChecks()
+		assert.Equal(t, pull.Head.Sha, statusChecks.CommitID)
+		assert.Equal(t, api.CommitStatusSuccess, statusChecks.State)
+		assert.Empty(t, statusChecks.Checks)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_required_status_checks/branch_protections", &api.CreateBranchProtectionOption{
+			RuleName:            "master",
+			EnableStatusCheck:   true,
+			StatusCheckContexts: []string{"ci/build |"},
+		}).AddTokenAuth(testCtx.Token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/test_required_status_checks/branch_protections", &api.CreateBranchProtectionOption{
+			RuleName:            "master",
+			EnableStatusCheck:   true,
+			StatusCheckContexts: []string{"ci/build-*", "ci/test | ci/test-v2"},
+		}).AddTokenAuth(testCtx.Token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		for context, state := range map[string]api.CommitStatusState{
+			"ci/build-linux":   api.CommitStatusSuccess,
+			"ci/build-windows": api.CommitStatusFailure,
+			"ci/test-v2":       api.CommitStatusSuccess,
+		} {
+			t.Run("CreateStatus", doAPICreateCommitStatus(testCtx, pull.Head.Sha, api.CreateStatusOption{
+				State:   state,
+				Context: context,
+			}))
+		}
+
+		statusChecks = getStatusChecks()
+		assert.Equal(t, api.CommitStatusFailure, statusChecks.State)
+		assert.Equal(t, []string{"ci/build-*"}, statusChecks.Unsatisfied)
+		if assert.Len(t, statusChecks.Checks, 2) {
+			assert.False(t, statusChecks.Checks[0].Satisfied)
+			assert.ElementsMatch(t, []string{"ci/build-linux", "ci/build-windows"}, statusChecks.Checks[0].Contexts)
+			assert.Equal(t, "ci/test | ci/test-v2", statusChecks.Checks[1].Rule)
+			assert.Equal(t, []string{"ci/test", "ci/test-v2"}, statusChecks.Checks[1].Patterns)
+			assert.True(t, statusChecks.Checks[1].Satisfied)
+		}
+
+		// the failed job of the matrix is fixed
+		t.Run("CreateStatus", doAPICreateCommitStatus(testCtx, pull.Head.Sha, api.CreateStatusOption{
+			State:   api.CommitStatusSuccess,
+			Context: "ci/build-windows",
+		}))
+		statusChecks = getStatusChecks()
+		assert.Equal(t, api.CommitStatusSuccess, statusChecks.State)
+		assert.Empty(t, statusChecks.Unsatisfied)
+	})
+}
+
 func doAPICreateCommitStatus(ctx APITestContext, commitID string, data api.CreateStatusOption) func(*testing.T) {
 	return func(t *testing.T) {
 		req := NewRequestWithJSON(
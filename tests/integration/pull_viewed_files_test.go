@@ -0,0 +1,122 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullViewedFiles(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_viewed_files",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			NewBranch: "viewed-files",
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "update",
+					TreePath:      "README.md",
+					ContentReader: strings.NewReader("# This is a new project\n"),
+				},
+				{
+					Operation:     "create",
+					TreePath:      "docs/a.md",
+					ContentReader: strings.NewReader("a\n"),
+				},
+				{
+					Operation:     "create",
+					TreePath:      "docs/b.md",
+					ContentReader: strings.NewReader("b\n"),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		repoURL := fmt.Sprintf("/api/v1/repos/user2/%s", repo.Name)
+
+		req := NewRequestWithJSON(t, "POST", repoURL+"/pulls", &api.CreatePullRequestOption{
+			Head:  "viewed-files",
+			Base:  "master",
+			Title: "Add the docs",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		pull := new(api.PullRequest)
+		DecodeJSON(t, resp, pull)
+
+		viewedFilesURL := fmt.Sprintf("%s/pulls/%d/viewed_files", repoURL, pull.Index)
+		req = NewRequest(t, "GET", viewedFilesURL).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		viewedFiles := new(api.PullReviewViewedFiles)
+		DecodeJSON(t, resp, viewedFiles)
+		assert.Equal(t, 3, viewedFiles.NumFiles)
+		assert.Zero(t, viewedFiles.NumViewedFiles)
+		assert.Zero(t, viewedFiles.Progress)
+
+		// the progress isn't displayed before the review started
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/%s/pulls", repo.Name))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		htmlDoc.AssertElement(t, ".review-progress", false)
+
+		// mark all the files under a directory
+		req = NewRequestWithJSON(t, "PUT", viewedFilesURL, &api.UpdatePullReviewViewedFilesOptions{
+			Directories: map[string]bool{"docs": true},
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, viewedFiles)
+		assert.Equal(t, 2, viewedFiles.NumViewedFiles)
+		assert.Equal(t, 66, viewedFiles.Progress)
+		viewed := make(map[string]bool)
+		for _, file := range viewedFiles.Files {
+			viewed[file.Path] = file.Viewed
+		}
+		assert.Equal(t, map[string]bool{"README.md": false, "docs/a.md": true, "docs/b.md": true}, viewed)
+
+		req = NewRequestWithJSON(t, "PUT", viewedFilesURL, &api.UpdatePullReviewViewedFilesOptions{
+			Files: map[string]bool{"docs/a.md": false},
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, viewedFiles)
+		assert.Equal(t, 1, viewedFiles.NumViewedFiles)
+		assert.Equal(t, 33, viewedFiles.Progress)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/user2/%s/pulls", repo.Name))
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		htmlDoc = NewHTMLParser(t, resp.Body)
+		assert.Contains(t, htmlDoc.doc.Find(".review-progress").Text(), "33% reviewed")
+
+		// the viewed files are per user
+		token8 := getTokenForLoggedInUser(t, loginUser(t, "user8"), auth_model.AccessTokenScopeReadRepository)
+		req = NewRequest(t, "GET", viewedFilesURL).AddTokenAuth(token8)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, viewedFiles)
+		assert.Zero(t, viewedFiles.NumViewedFiles)
+	})
+}
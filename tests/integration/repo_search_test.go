@@ -11,6 +11,8 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/tests"
 
 	"github.com/PuerkitoBio/goquery"
@@ -59,3 +61,54 @@ func testSearch(t *testing.T, url string, expected []string) {
 	filenames := resultFilenames(NewHTMLParser(t, resp.Body))
 	assert.EqualValues(t, expected, filenames)
 }
+
+func TestSearchRepoWiki(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo, err := repo_model.GetRepositoryByOwnerAndName(db.DefaultContext, "user2", "repo1")
+	assert.NoError(t, err)
+
+	code_indexer.UpdateWikiIndexer(repo)
+
+	testSearch(t, "/user2/repo1/search?q=spaces&scope=wiki", []string{"Page With Spaced Name"})
+	testSearch(t, "/user2/repo1/search?q=Description&scope=wiki", []string{})
+
+	// without the wiki indexer the pages are searched with git grep
+	defer test.MockVariableValue(&setting.Indexer.WikiIndexerEnabled, false)()
+	testSearch(t, "/user2/repo1/search?q=spaces&scope=wiki", []string{"Page With Spaced Name"})
+}
+
+func TestAPISearchRepoContent(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo, err := repo_model.GetRepositoryByOwnerAndName(db.DefaultContext, "user2", "repo1")
+	assert.NoError(t, err)
+
+	code_indexer.UpdateRepoIndexer(repo)
+	code_indexer.UpdateWikiIndexer(repo)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search?q=Description")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var results []*api.ContentSearchResult
+	DecodeJSON(t, resp, &results)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "README.md", results[0].Path)
+		assert.Empty(t, results[0].Title)
+	}
+	assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search?q=spaces&scope=wiki")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &results)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Page-With-Spaced-Name.md", results[0].Path)
+		assert.Equal(t, "Page With Spaced Name", results[0].Title)
+		assert.Equal(t, setting.AppURL+"user2/repo1/wiki/Page-With-Spaced-Name", results[0].HTMLURL)
+		assert.NotEmpty(t, results[0].Lines)
+	}
+
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search?q=spaces&scope=issues"), http.StatusUnprocessableEntity)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo1/search"), http.StatusUnprocessableEntity)
+	// private repository
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/repos/user2/repo2/search?q=spaces&scope=wiki"), http.StatusNotFound)
+}
@@ -10,12 +10,14 @@ import (
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/models"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/contexttest"
 	files_service "code.gitea.io/gitea/services/repository/files"
 
@@ -397,6 +399,127 @@ func TestChangeRepoFilesWithoutBranchNames(t *testing.T) {
 	})
 }
 
+func TestChangeRepoFilesOnParentCommit(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		ctx, _ := contexttest.MockContext(t, "user2/repo1")
+		ctx.SetPathParam(":id", "1")
+		contexttest.LoadRepo(t, ctx, 1)
+		contexttest.LoadRepoCommit(t, ctx)
+		contexttest.LoadUser(t, ctx, 2)
+		contexttest.LoadGitRepo(t, ctx)
+		defer ctx.Repo.GitRepo.Close()
+
+		repo := ctx.Repo.Repository
+		doer := ctx.Doer
+		gitRepo, _ := gitrepo.OpenRepository(git.DefaultContext, repo)
+		defer gitRepo.Close()
+
+		head, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+		assert.NoError(t, err)
+		// a commit which isn't the head of the branch the files are changed on
+		parent, err := gitRepo.GetBranchCommit("branch2")
+		assert.NoError(t, err)
+
+		for _, content := range []string{"first", "second"} {
+			opts := getCreateRepoFilesOptions(repo)
+			opts.Files[0].ContentReader = strings.NewReader(content)
+			opts.ParentCommitID = parent.ID.String()
+			opts.TargetRef = "refs/gitea/bot/speculative"
+
+			filesResponse, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, opts)
+			assert.NoError(t, err)
+
+			// the commit is written to the target reference on top of the parent commit
+			refCommit, err := gitRepo.GetCommit(opts.TargetRef)
+			assert.NoError(t, err)
+			assert.Equal(t, filesResponse.Commit.SHA, refCommit.ID.String())
+			refParent, err := refCommit.ParentID(0)
+			assert.NoError(t, err)
+			assert.Equal(t, parent.ID.String(), refParent.String())
+			fileContent, err := refCommit.GetFileContent("new/file.txt", 0)
+			assert.NoError(t, err)
+			assert.Equal(t, content, fileContent)
+			if assert.Len(t, filesResponse.Files, 1) && assert.NotNil(t, filesResponse.Files[0]) {
+				assert.Equal(t, "new/file.txt", filesResponse.Files[0].Path)
+			}
+		}
+
+		// no branch has been changed
+		branchCommit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+		assert.NoError(t, err)
+		assert.Equal(t, head.ID.String(), branchCommit.ID.String())
+
+		opts := getCreateRepoFilesOptions(repo)
+		opts.TargetRef = "refs/heads/" + repo.DefaultBranch
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, opts)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+		opts = getCreateRepoFilesOptions(repo)
+		opts.ParentCommitID = "0000000000000000000000000000000000000001"
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, opts)
+		assert.True(t, git.IsErrNotExist(err))
+	})
+}
+
+func TestChangeRepoFilesRebaseOntoMovedBranch(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		ctx, _ := contexttest.MockContext(t, "user2/repo1")
+		ctx.SetPathParam(":id", "1")
+		contexttest.LoadRepo(t, ctx, 1)
+		contexttest.LoadRepoCommit(t, ctx)
+		contexttest.LoadUser(t, ctx, 2)
+		contexttest.LoadGitRepo(t, ctx)
+		defer ctx.Repo.GitRepo.Close()
+
+		repo := ctx.Repo.Repository
+		doer := ctx.Doer
+		gitRepo, _ := gitrepo.OpenRepository(git.DefaultContext, repo)
+		defer gitRepo.Close()
+
+		staleCommit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+		assert.NoError(t, err)
+
+		// the branch moves by a change of README.md
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, getUpdateRepoFilesOptions(repo))
+		assert.NoError(t, err)
+
+		t.Run("UnrelatedChange", func(t *testing.T) {
+			head, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+			assert.NoError(t, err)
+
+			opts := getCreateRepoFilesOptions(repo)
+			opts.LastCommitID = staleCommit.ID.String()
+			filesResponse, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, opts)
+			assert.NoError(t, err)
+
+			commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+			assert.NoError(t, err)
+			assert.Equal(t, filesResponse.Commit.SHA, commit.ID.String())
+			parent, err := commit.ParentID(0)
+			assert.NoError(t, err)
+			assert.Equal(t, head.ID.String(), parent.String())
+			readme, err := commit.GetFileContent("README.md", 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "This is UPDATED content for the README file", readme)
+		})
+
+		t.Run("ConflictingChange", func(t *testing.T) {
+			opts := getUpdateRepoFilesOptions(repo)
+			opts.Files[0].SHA = ""
+			opts.LastCommitID = staleCommit.ID.String()
+			_, err := files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, opts)
+			assert.True(t, models.IsErrCommitIDDoesNotMatch(err))
+
+			// the directory contains a file which was created after the last commit
+			opts = getDeleteRepoFilesOptions(repo)
+			opts.Files = []*files_service.ChangeRepoFile{{Operation: "delete-dir", TreePath: "new"}}
+			opts.LastCommitID = staleCommit.ID.String()
+			_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, doer, opts)
+			assert.True(t, models.IsErrCommitIDDoesNotMatch(err))
+		})
+	})
+}
+
 func TestChangeRepoFilesForDelete(t *testing.T) {
 	onGiteaRun(t, testDeleteRepoFiles)
 }
@@ -28,6 +28,36 @@ export function countAndUpdateViewedFiles() {
   refreshViewedFilesSummary();
 }
 
+// Updates the page after the given "viewed" checkbox form of a file has been (un)checked:
+// the viewed-files summary, the file tree and the folding of the file
+function updateViewedState(form, checkbox, viewed) {
+  // Mark the file as viewed visually - will especially change the background
+  if (viewed) {
+    form.classList.add(viewedStyleClass);
+    checkbox.setAttribute('checked', '');
+    prReview.numberOfViewedFiles++;
+  } else {
+    form.classList.remove(viewedStyleClass);
+    checkbox.removeAttribute('checked');
+    prReview.numberOfViewedFiles--;
+  }
+
+  // Update viewed-files summary and remove "has changed" label if present
+  refreshViewedFilesSummary();
+  const hasChangedLabel = form.parentNode.querySelector('.changed-since-last-review');
+  hasChangedLabel?.remove();
+
+  // check if the file is in our difftreestore and if we find it -> change the IsViewed status
+  const fileInPageData = diffTreeStore().files.find((x) => x.Name === checkbox.getAttribute('name'));
+  if (fileInPageData) {
+    fileInPageData.IsViewed = viewed;
+  }
+
+  // Fold the file accordingly
+  const parentBox = form.closest('.diff-file-header');
+  setFileFolding(parentBox.closest('.file-content'), parentBox.querySelector('.fold-file'), viewed);
+}
+
 // Initializes a listener for all children of the given html element
 // (for example 'document' in the most basic case)
 // to watch for changes of viewed-file checkboxes
@@ -40,45 +70,46 @@ export function initViewedCheckboxListenerFor() {
     // hence the actual checkbox first has to be found
     const checkbox = form.querySelector('input[type=checkbox]');
     checkbox.addEventListener('input', function() {
-      // Mark the file as viewed visually - will especially change the background
-      if (this.checked) {
-        form.classList.add(viewedStyleClass);
-        checkbox.setAttribute('checked', '');
-        prReview.numberOfViewedFiles++;
-      } else {
-        form.classList.remove(viewedStyleClass);
-        checkbox.removeAttribute('checked');
-        prReview.numberOfViewedFiles--;
-      }
-
-      // Update viewed-files summary and remove "has changed" label if present
-      refreshViewedFilesSummary();
-      const hasChangedLabel = form.parentNode.querySelector('.changed-since-last-review');
-      hasChangedLabel?.remove();
-
-      const fileName = checkbox.getAttribute('name');
-
-      // check if the file is in our difftreestore and if we find it -> change the IsViewed status
-      const fileInPageData = diffTreeStore().files.find((x) => x.Name === fileName);
-      if (fileInPageData) {
-        fileInPageData.IsViewed = this.checked;
-      }
+      updateViewedState(form, checkbox, this.checked);
 
       // Unfortunately, actual forms cause too many problems, hence another approach is needed
       const files = {};
-      files[fileName] = this.checked;
+      files[checkbox.getAttribute('name')] = this.checked;
       const data = {files};
       const headCommitSHA = form.getAttribute('data-headcommit');
       if (headCommitSHA) data.headCommitSHA = headCommitSHA;
       POST(form.getAttribute('data-link'), {data});
-
-      // Fold the file accordingly
-      const parentBox = form.closest('.diff-file-header');
-      setFileFolding(parentBox.closest('.file-content'), parentBox.querySelector('.fold-file'), this.checked);
     });
   }
 }
 
+// Marks all the files under the given directory as viewed,
+// the files which haven't been loaded yet are marked by the server
+export function markDirectoryAsViewed(directory) {
+  const tree = document.querySelector('#diff-file-tree');
+  const link = tree?.getAttribute('data-viewed-files-link');
+  if (!link) return;
+
+  const prefix = `${directory}/`;
+  for (const form of document.querySelectorAll(viewedCheckboxSelector)) {
+    const checkbox = form.querySelector('input[type=checkbox]');
+    if (checkbox.checked || !checkbox.getAttribute('name').startsWith(prefix)) continue;
+    checkbox.checked = true;
+    updateViewedState(form, checkbox, true);
+  }
+  for (const file of diffTreeStore().files) {
+    if (file.IsViewed || !file.Name.startsWith(prefix)) continue;
+    file.IsViewed = true;
+    prReview.numberOfViewedFiles++;
+  }
+  refreshViewedFilesSummary();
+
+  const data = {directories: {[directory]: true}};
+  const headCommitSHA = tree.getAttribute('data-headcommit');
+  if (headCommitSHA) data.headCommitSHA = headCommitSHA;
+  POST(link, {data});
+}
+
 export function initExpandAndCollapseFilesButton() {
   // expand btn
   document.querySelector(expandFilesBtnSelector)?.addEventListener('click', () => {
@@ -1,6 +1,6 @@
 import $ from 'jquery';
 import {htmlEscape} from 'escape-goat';
-import {createCodeEditor} from './codeeditor.js';
+import {createCodeEditor, createMonaco} from './codeeditor.js';
 import {hideElem, queryElems, showElem} from '../utils/dom.js';
 import {initMarkupContent} from '../markup/content.js';
 import {attachRefIssueContextPopup} from './contextpopup.js';
@@ -158,6 +158,12 @@ export function initRepoEditor() {
   })();
 }
 
+export function initRepoPullConflictsEditor() {
+  for (const textarea of document.querySelectorAll('.page-content.repository.pull-conflicts textarea.conflict-editor')) {
+    createMonaco(textarea, textarea.getAttribute('data-filename'), {});
+  }
+}
+
 export function renderPreviewPanelContent($previewPanel, data) {
   $previewPanel.html(data);
   initMarkupContent();
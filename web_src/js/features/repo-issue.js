@@ -1,7 +1,7 @@
 import $ from 'jquery';
 import {htmlEscape} from 'escape-goat';
 import {createTippy, showTemporaryTooltip} from '../modules/tippy.js';
-import {hideElem, showElem, toggleElem} from '../utils/dom.js';
+import {hideElem, onInputDebounce, showElem, toggleElem} from '../utils/dom.js';
 import {setFileFolding} from './file-fold.js';
 import {getComboMarkdownEditor, initComboMarkdownEditor} from './comp/ComboMarkdownEditor.js';
 import {toAbsoluteUrl} from '../utils.js';
@@ -366,6 +366,32 @@ export function initRepoIssueWipTitle() {
   });
 }
 
+export function initRepoIssueSimilarIssues() {
+  const container = document.querySelector('#similar-issues');
+  if (!container) return;
+  const titleInput = document.querySelector('#issue_title');
+  const list = container.querySelector('.similar-issues-list');
+
+  const updateSimilarIssues = async () => {
+    const title = titleInput.value.trim();
+    if (!title) {
+      hideElem(container);
+      return;
+    }
+    try {
+      const response = await GET(`${container.getAttribute('data-url')}?${new URLSearchParams({q: title})}`);
+      const issues = await response.json();
+      if (titleInput.value.trim() !== title) return; // the title has changed while loading
+      list.innerHTML = issues.map((issue) => `<div class="item"><a href="${htmlEscape(issue.html_url)}" target="_blank">${htmlEscape(issue.title)} #${issue.number}</a></div>`).join('');
+      toggleElem(container, issues.length > 0);
+    } catch (error) {
+      console.error(error);
+    }
+  };
+  titleInput.addEventListener('input', onInputDebounce(updateSimilarIssues));
+  updateSimilarIssues();
+}
+
 export async function updateIssuesMeta(url, action, issue_ids, id) {
   try {
     const response = await POST(url, {data: new URLSearchParams({action, issue_ids, id})});
@@ -99,7 +99,8 @@ export function initRepoSettingBranches() {
   // show the `Matched` mark for the status checks that match the pattern
   const markMatchedStatusChecks = () => {
     const patterns = (document.querySelector('#status_check_contexts').value || '').split(/[\r\n]+/);
-    const validPatterns = patterns.map((item) => item.trim()).filter(Boolean);
+    // a line can be an any-of group of patterns separated by "|"
+    const validPatterns = patterns.flatMap((item) => item.split('|')).map((item) => item.trim()).filter(Boolean);
     const marks = document.querySelectorAll('.status-check-matched-mark');
 
     for (const el of marks) {
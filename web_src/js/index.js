@@ -31,6 +31,7 @@ import {
   initRepoIssueReferenceRepositorySearch,
   initRepoIssueTimeTracking,
   initRepoIssueWipTitle,
+  initRepoIssueSimilarIssues,
   initRepoPullRequestMergeInstruction,
   initRepoPullRequestAllowMaintainerEdit,
   initRepoPullRequestReview, initRepoIssueSidebarList, initArchivedLabelHandler,
@@ -54,7 +55,7 @@ import {initRepoDiffView} from './features/repo-diff.js';
 import {initOrgTeamSearchRepoBox, initOrgTeamSettings} from './features/org-team.js';
 import {initUserAuthWebAuthn, initUserAuthWebAuthnRegister} from './features/user-auth-webauthn.js';
 import {initRepoRelease, initRepoReleaseNew} from './features/repo-release.js';
-import {initRepoEditor} from './features/repo-editor.js';
+import {initRepoEditor, initRepoPullConflictsEditor} from './features/repo-editor.js';
 import {initCompSearchUserBox} from './features/comp/SearchUserBox.js';
 import {initInstall} from './features/install.js';
 import {initCompWebHookEditor} from './features/comp/WebHookEditor.js';
@@ -184,6 +185,7 @@ onDomReady(() => {
     initRepoEllipsisButton,
     initRepoDiffCommitBranchesAndTags,
     initRepoEditor,
+    initRepoPullConflictsEditor,
     initRepoGraphGit,
     initRepoIssueContentHistory,
     initRepoIssueDue,
@@ -193,6 +195,7 @@ onDomReady(() => {
     initRepoIssueReferenceRepositorySearch,
     initRepoIssueTimeTracking,
     initRepoIssueWipTitle,
+    initRepoIssueSimilarIssues,
     initRepoMigration,
     initRepoMigrationStatusChecker,
     initRepoProject,